@@ -0,0 +1,10 @@
+// Package agentlayer embeds repository-level files the al binary ships with.
+package agentlayer
+
+import _ "embed"
+
+// Changelog is the release notes for every published version, newest first.
+// `al upgrade` condenses the sections between the source and target versions.
+//
+//go:embed CHANGELOG.md
+var Changelog []byte
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/conn-castle/agent-layer/internal/i18n"
+	"github.com/conn-castle/agent-layer/internal/messages"
+	"github.com/conn-castle/agent-layer/internal/output"
+	"github.com/conn-castle/agent-layer/internal/skillfetch"
+)
+
+var (
+	addSkill     = skillfetch.Add
+	updateSkills = skillfetch.Update
+)
+
+func newAddCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   messages.AddUse,
+		Short: messages.AddShort,
+	}
+	cmd.AddCommand(newAddSkillCmd())
+	return cmd
+}
+
+func newAddSkillCmd() *cobra.Command {
+	var force, wait bool
+	cmd := &cobra.Command{
+		Use:   messages.AddSkillUse,
+		Short: messages.AddSkillShort,
+		Long:  messages.AddSkillLong,
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			root, err := resolveRepoRoot()
+			if err != nil {
+				return err
+			}
+			lock, err := lockRepoState(cmd.ErrOrStderr(), root, wait)
+			if err != nil {
+				return err
+			}
+			defer func() { _ = lock.Release() }()
+			result, err := addSkill(root, args[0], force)
+			if err != nil {
+				return err
+			}
+			out := cmd.OutOrStdout()
+			writeSkillFetchWarnings(commandOutput(cmd, noiseModeFromConfig(root)), result)
+			if _, err := fmt.Fprintf(out, i18n.T(messages.AddSkillResultFmt), result.Name, result.Status, result.Source); err != nil {
+				return err
+			}
+			_, err = fmt.Fprintln(out, i18n.T(messages.AddSkillSyncHint))
+			return err
+		},
+	}
+	cmd.Flags().BoolVar(&force, "force", false, messages.AddSkillFlagForce)
+	cmd.Flags().BoolVar(&wait, "wait", false, messages.StateLockFlagWait)
+	return cmd
+}
+
+func newUpdateCmd() *cobra.Command {
+	var force, wait bool
+	cmd := &cobra.Command{
+		Use:               messages.UpdateUse,
+		Short:             messages.UpdateShort,
+		Long:              messages.UpdateLong,
+		ValidArgsFunction: completeLockedSkills,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			root, err := resolveRepoRoot()
+			if err != nil {
+				return err
+			}
+			lock, err := lockRepoState(cmd.ErrOrStderr(), root, wait)
+			if err != nil {
+				return err
+			}
+			defer func() { _ = lock.Release() }()
+			results, err := updateSkills(root, args, force)
+			if err != nil {
+				return err
+			}
+			out := cmd.OutOrStdout()
+			if len(results) == 0 {
+				_, err := fmt.Fprintln(out, i18n.T(messages.UpdateNothingLocked))
+				return err
+			}
+			changed := false
+			skipped := false
+			for _, result := range results {
+				writeSkillFetchWarnings(commandOutput(cmd, noiseModeFromConfig(root)), result)
+				if _, err := fmt.Fprintf(out, i18n.T(messages.UpdateResultFmt), result.Name, result.Status, result.Source); err != nil {
+					return err
+				}
+				changed = changed || result.Status == skillfetch.StatusUpdated || result.Status == skillfetch.StatusInstalled
+				skipped = skipped || result.Status == skillfetch.StatusSkipped
+			}
+			if skipped {
+				if _, err := fmt.Fprintln(out, i18n.T(messages.UpdateSkippedHint)); err != nil {
+					return err
+				}
+			}
+			if changed {
+				_, err = fmt.Fprintln(out, i18n.T(messages.AddSkillSyncHint))
+			}
+			return err
+		},
+	}
+	cmd.Flags().BoolVar(&force, "force", false, messages.UpdateFlagForce)
+	cmd.Flags().BoolVar(&wait, "wait", false, messages.StateLockFlagWait)
+	return cmd
+}
+
+func writeSkillFetchWarnings(out *output.Writer, result skillfetch.Result) {
+	for _, finding := range result.Warnings {
+		out.Infof(messages.SkillFetchWarningFmt, result.Name, finding.Message)
+	}
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/conn-castle/agent-layer/internal/messages"
+	"github.com/conn-castle/agent-layer/internal/skillfetch"
+	"github.com/conn-castle/agent-layer/internal/skillvalidator"
+)
+
+func stubRepoRoot(t *testing.T) string {
+	t.Helper()
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, ".agent-layer"), 0o700); err != nil {
+		t.Fatalf("mkdir .agent-layer: %v", err)
+	}
+	originalGetwd := getwd
+	getwd = func() (string, error) { return root, nil }
+	t.Cleanup(func() { getwd = originalGetwd })
+	return root
+}
+
+func TestAddSkillCmd(t *testing.T) {
+	root := stubRepoRoot(t)
+	original := addSkill
+	addSkill = func(gotRoot string, source string, force bool) (skillfetch.Result, error) {
+		if canonicalPath(gotRoot) != canonicalPath(root) || source != "github.com/org/review@v1" || !force {
+			t.Fatalf("addSkill(%q, %q, %v)", gotRoot, source, force)
+		}
+		return skillfetch.Result{
+			Name:     "review",
+			Source:   source,
+			Status:   skillfetch.StatusInstalled,
+			Warnings: []skillvalidator.Finding{{Message: "too long"}},
+		}, nil
+	}
+	t.Cleanup(func() { addSkill = original })
+
+	cmd := newAddCmd()
+	var out, errOut bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(&errOut)
+	cmd.SetArgs([]string{"skill", "--force", "github.com/org/review@v1"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("add skill: %v", err)
+	}
+	if !strings.Contains(out.String(), "Skill review installed from github.com/org/review@v1") ||
+		!strings.Contains(out.String(), messages.AddSkillSyncHint) {
+		t.Fatalf("unexpected output: %q", out.String())
+	}
+	if !strings.Contains(errOut.String(), "Warning: skill review: too long") {
+		t.Fatalf("expected warning on stderr, got %q", errOut.String())
+	}
+}
+
+func TestAddSkillCmd_PropagatesErrors(t *testing.T) {
+	stubRepoRoot(t)
+	original := addSkill
+	addSkill = func(string, string, bool) (skillfetch.Result, error) { return skillfetch.Result{}, errors.New("boom") }
+	t.Cleanup(func() { addSkill = original })
+
+	cmd := newAddCmd()
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetArgs([]string{"skill", "x"})
+	if err := cmd.Execute(); err == nil || err.Error() != "boom" {
+		t.Fatalf("expected boom, got %v", err)
+	}
+}
+
+func TestUpdateCmd(t *testing.T) {
+	stubRepoRoot(t)
+	original := updateSkills
+	t.Cleanup(func() { updateSkills = original })
+
+	updateSkills = func(_ string, names []string, force bool) ([]skillfetch.Result, error) {
+		if len(names) != 0 || force {
+			t.Fatalf("updateSkills(%v, %v)", names, force)
+		}
+		return nil, nil
+	}
+	cmd := newUpdateCmd()
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	if !strings.Contains(out.String(), messages.UpdateNothingLocked) {
+		t.Fatalf("unexpected output: %q", out.String())
+	}
+
+	updateSkills = func(_ string, names []string, _ bool) ([]skillfetch.Result, error) {
+		return []skillfetch.Result{
+			{Name: "review", Source: "github.com/org/review@main", Status: skillfetch.StatusUpdated},
+			{Name: "deploy", Source: "github.com/org/deploy@main", Status: skillfetch.StatusSkipped},
+		}, nil
+	}
+	cmd = newUpdateCmd()
+	out.Reset()
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"review", "deploy"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	for _, want := range []string{"review: updated", "deploy: skipped", messages.UpdateSkippedHint, messages.AddSkillSyncHint} {
+		if !strings.Contains(out.String(), want) {
+			t.Fatalf("expected %q in output: %q", want, out.String())
+		}
+	}
+}
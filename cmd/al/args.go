@@ -5,6 +5,7 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/conn-castle/agent-layer/internal/i18n"
 	"github.com/conn-castle/agent-layer/internal/messages"
 )
 
@@ -12,10 +13,109 @@ const (
 	flagQuiet       = "--quiet"
 	flagQuietShort  = "-q"
 	flagQuietPrefix = "--quiet="
+
+	flagErrorFormat       = "--error-format"
+	flagErrorFormatPrefix = "--error-format="
+
+	flagOffline       = "--offline"
+	flagOfflinePrefix = "--offline="
+
+	flagCI       = "--ci"
+	flagCIPrefix = "--ci="
+
+	flagColor       = "--color"
+	flagColorPrefix = "--color="
 )
 
+// splitOfflineArgs removes --offline from args, which start with the program
+// name, and reports whether it turned offline mode on. Arguments after "--"
+// are left alone.
+func splitOfflineArgs(args []string) (bool, []string, error) {
+	return splitRootBoolFlag(args, flagOffline, flagOfflinePrefix, messages.OfflineInvalidFmt)
+}
+
+// splitCIArgs removes --ci from args like splitOfflineArgs and reports whether
+// it turned CI mode on.
+func splitCIArgs(args []string) (bool, []string, error) {
+	return splitRootBoolFlag(args, flagCI, flagCIPrefix, messages.CIInvalidFmt)
+}
+
+// splitColorArgs removes --color from args, which start with the program
+// name, and returns its last value, or "" when it was not passed. Arguments
+// after "--" are left alone.
+func splitColorArgs(args []string) (string, []string) {
+	value := ""
+	kept := make([]string, 0, len(args))
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		trimmed := strings.TrimSpace(arg)
+		switch {
+		case i == 0:
+			kept = append(kept, arg)
+		case trimmed == "--":
+			return value, append(kept, args[i:]...)
+		case trimmed == flagColor && i+1 < len(args):
+			i++
+			value = strings.TrimSpace(args[i])
+		case strings.HasPrefix(trimmed, flagColorPrefix):
+			value = strings.TrimPrefix(trimmed, flagColorPrefix)
+		default:
+			kept = append(kept, arg)
+		}
+	}
+	return value, kept
+}
+
+// splitRootBoolFlag removes every occurrence of a boolean root flag, given as
+// flag or prefix+value, from args up to "--". The last occurrence wins.
+func splitRootBoolFlag(args []string, flag string, prefix string, invalidFmt string) (bool, []string, error) {
+	enabled := false
+	kept := make([]string, 0, len(args))
+	for i, arg := range args {
+		if i == 0 {
+			kept = append(kept, arg)
+			continue
+		}
+		trimmed := strings.TrimSpace(arg)
+		if trimmed == "--" {
+			kept = append(kept, args[i:]...)
+			break
+		}
+		if trimmed == flag {
+			enabled = true
+			continue
+		}
+		if strings.HasPrefix(trimmed, prefix) {
+			value := strings.TrimPrefix(trimmed, prefix)
+			parsed, err := strconv.ParseBool(value)
+			if err != nil {
+				return false, nil, fmt.Errorf(invalidFmt, value)
+			}
+			enabled = parsed
+			continue
+		}
+		kept = append(kept, arg)
+	}
+	return enabled, kept, nil
+}
+
+// errorFormatArgWidth reports how many leading args form an --error-format
+// flag, so pass-through parsers can drop it; main reads the value itself.
+func errorFormatArgWidth(args []string) int {
+	switch {
+	case len(args) == 0:
+		return 0
+	case args[0] == flagErrorFormat && len(args) > 1:
+		return 2
+	case args[0] == flagErrorFormat, strings.HasPrefix(args[0], flagErrorFormatPrefix):
+		return 1
+	}
+	return 0
+}
+
 // splitQuietArgs parses --quiet/-q from pass-through args and returns quiet along
-// with the args that should be forwarded to the underlying client.
+// with the args that should be forwarded to the underlying client. The root
+// --error-format flag is dropped rather than forwarded.
 func splitQuietArgs(args []string) (bool, []string, error) {
 	quiet := false
 	passArgs := []string{}
@@ -25,6 +125,10 @@ func splitQuietArgs(args []string) (bool, []string, error) {
 			passArgs = append(passArgs, args[i+1:]...)
 			break
 		}
+		if width := errorFormatArgWidth(args[i:]); width > 0 {
+			i += width - 1
+			continue
+		}
 		if arg == flagQuiet || arg == flagQuietShort {
 			quiet = true
 			continue
@@ -33,7 +137,7 @@ func splitQuietArgs(args []string) (bool, []string, error) {
 			value := strings.TrimPrefix(arg, flagQuietPrefix)
 			parsed, err := strconv.ParseBool(value)
 			if err != nil {
-				return false, nil, fmt.Errorf(messages.QuietInvalidFmt, value)
+				return false, nil, i18n.Errorf(messages.QuietInvalidFmt, value)
 			}
 			quiet = parsed
 			continue
@@ -147,6 +147,11 @@ func TestSplitQuietArgs(t *testing.T) {
 			args:    []string{"--quiet=maybe"},
 			wantErr: true,
 		},
+		{
+			name:     "error format dropped",
+			args:     []string{"--error-format", "json", "--foo", "--error-format=text"},
+			wantArgs: []string{"--foo"},
+		},
 		{
 			name:      "quiet after separator",
 			args:      []string{"--", "--quiet"},
@@ -185,3 +190,22 @@ func writeArgsStub(t *testing.T, dir string, name string, outputPath string) {
 		t.Fatalf("write stub: %v", err)
 	}
 }
+
+func TestSplitColorArgs(t *testing.T) {
+	tests := []struct {
+		args      []string
+		wantValue string
+		wantArgs  []string
+	}{
+		{args: []string{"al", "sync"}, wantValue: "", wantArgs: []string{"al", "sync"}},
+		{args: []string{"al", "--color", "never", "doctor"}, wantValue: "never", wantArgs: []string{"al", "doctor"}},
+		{args: []string{"al", "doctor", "--color=always"}, wantValue: "always", wantArgs: []string{"al", "doctor"}},
+		{args: []string{"al", "claude", "--", "--color", "never"}, wantValue: "", wantArgs: []string{"al", "claude", "--", "--color", "never"}},
+	}
+	for _, tt := range tests {
+		value, args := splitColorArgs(tt.args)
+		if value != tt.wantValue || strings.Join(args, " ") != strings.Join(tt.wantArgs, " ") {
+			t.Fatalf("splitColorArgs(%v) = %q, %v; want %q, %v", tt.args, value, args, tt.wantValue, tt.wantArgs)
+		}
+	}
+}
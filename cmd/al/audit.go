@@ -0,0 +1,89 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/conn-castle/agent-layer/internal/audit"
+	"github.com/conn-castle/agent-layer/internal/errcode"
+	"github.com/conn-castle/agent-layer/internal/i18n"
+	"github.com/conn-castle/agent-layer/internal/messages"
+)
+
+var loadAudit = audit.Load
+
+func newAuditCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   messages.AuditUse,
+		Short: messages.AuditShort,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Help()
+		},
+	}
+	cmd.AddCommand(newAuditShowCmd())
+	return cmd
+}
+
+func newAuditShowCmd() *cobra.Command {
+	var since string
+	var asJSON bool
+
+	cmd := &cobra.Command{
+		Use:   messages.AuditShowUse,
+		Short: messages.AuditShowShort,
+		Long:  messages.AuditShowLong,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			root, err := resolveRepoRoot()
+			if err != nil {
+				return err
+			}
+			entries, err := loadAudit(root)
+			if err != nil {
+				return errcode.Wrap(errcode.Config, err)
+			}
+			if strings.TrimSpace(since) != "" {
+				cutoff, err := audit.ParseSince(since, auditNow())
+				if err != nil {
+					return err
+				}
+				entries = audit.Since(entries, cutoff)
+			}
+			out := cmd.OutOrStdout()
+			if asJSON {
+				encoder := json.NewEncoder(out)
+				for _, entry := range entries {
+					if err := encoder.Encode(entry); err != nil {
+						return err
+					}
+				}
+				return nil
+			}
+			if len(entries) == 0 {
+				_, err := fmt.Fprintln(out, i18n.T(messages.AuditShowNone))
+				return err
+			}
+			for _, entry := range entries {
+				client := entry.Client
+				if client == "" {
+					client = "-"
+				}
+				rule := ""
+				if entry.Rule != "" {
+					rule = i18n.Sprintf(messages.AuditShowRuleFmt, entry.Rule)
+				}
+				if _, err := fmt.Fprintf(out, i18n.T(messages.AuditShowEntryFmt), entry.Time, entry.Source, entry.Decision, client, entry.Command, rule); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&since, "since", "", messages.AuditShowSinceFlag)
+	cmd.Flags().BoolVar(&asJSON, "json", false, messages.AuditShowJSONFlag)
+	return cmd
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/conn-castle/agent-layer/internal/audit"
+)
+
+func runAuditShow(t *testing.T, args ...string) (string, error) {
+	t.Helper()
+	cmd := newAuditCmd()
+	cmd.SilenceUsage = true
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs(append([]string{"show"}, args...))
+	err := cmd.Execute()
+	return out.String(), err
+}
+
+func TestAuditShowCmd(t *testing.T) {
+	root := stubRepoRoot(t)
+	base := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	for _, entry := range []audit.Entry{
+		{Source: audit.SourceExec, Client: "claude", Command: "go test ./...", Decision: audit.DecisionAllowed, Rule: "go test"},
+		{Source: audit.SourceGateway, Command: "github.delete_repo", Decision: audit.DecisionDenied, Rule: "tools_deny"},
+	} {
+		if err := audit.Append(root, entry, base); err != nil {
+			t.Fatal(err)
+		}
+		base = base.Add(48 * time.Hour)
+	}
+	original := auditNow
+	auditNow = func() time.Time { return base }
+	t.Cleanup(func() { auditNow = original })
+
+	out, err := runAuditShow(t)
+	if err != nil {
+		t.Fatalf("show: %v", err)
+	}
+	want := "2026-03-01T12:00:00Z  exec        allowed  claude   go test ./... (go test)\n" +
+		"2026-03-03T12:00:00Z  mcp_gateway denied   -        github.delete_repo (tools_deny)\n"
+	if out != want {
+		t.Fatalf("unexpected output:\n%s\nwant:\n%s", out, want)
+	}
+
+	out, err = runAuditShow(t, "--since", "3d", "--json")
+	if err != nil {
+		t.Fatalf("show --since: %v", err)
+	}
+	if strings.Count(out, "\n") != 1 || !strings.Contains(out, `"command":"github.delete_repo"`) {
+		t.Fatalf("expected only the recent entry as JSON, got %q", out)
+	}
+
+	if _, err := runAuditShow(t, "--since", "last week"); err == nil || !strings.Contains(err.Error(), "invalid --since") {
+		t.Fatalf("expected since error, got %v", err)
+	}
+}
+
+func TestAuditShowCmd_Empty(t *testing.T) {
+	stubRepoRoot(t)
+	out, err := runAuditShow(t)
+	if err != nil {
+		t.Fatalf("show: %v", err)
+	}
+	if out != "No audit entries recorded.\n" {
+		t.Fatalf("unexpected output %q", out)
+	}
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+
+	"github.com/conn-castle/agent-layer/internal/i18n"
+	"github.com/conn-castle/agent-layer/internal/install"
+	"github.com/conn-castle/agent-layer/internal/messages"
+)
+
+var installRebuildBaseline = install.RebuildBaseline
+
+func newBaselineCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   messages.BaselineUse,
+		Short: messages.BaselineShort,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Help()
+		},
+	}
+	cmd.AddCommand(newBaselineRebuildCmd())
+	return cmd
+}
+
+func newBaselineRebuildCmd() *cobra.Command {
+	var assumeVersion string
+	var wait bool
+	cmd := &cobra.Command{
+		Use:   messages.BaselineRebuildUse,
+		Short: messages.BaselineRebuildShort,
+		Long:  messages.BaselineRebuildLong,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			root, err := resolveRepoRoot()
+			if err != nil {
+				return err
+			}
+			lock, err := lockRepoState(cmd.ErrOrStderr(), root, wait)
+			if err != nil {
+				return err
+			}
+			defer func() { _ = lock.Release() }()
+			out := cmd.OutOrStdout()
+			opts := install.BaselineRebuildOptions{
+				System:        install.RealSystem{},
+				AssumeVersion: assumeVersion,
+			}
+			if isTerminal() {
+				in := bufferedReader(cmd.InOrStdin())
+				opts.ChooseVersion = func(candidates []install.BaselineCandidate) (string, error) {
+					return promptBaselineCandidate(in, out, candidates)
+				}
+			}
+			result, err := installRebuildBaseline(root, opts)
+			if err != nil {
+				return err
+			}
+			return writeBaselineRebuildResult(out, result)
+		},
+	}
+	cmd.Flags().StringVar(&assumeVersion, "assume-version", "", messages.BaselineRebuildAssumeVersionFlag)
+	cmd.Flags().BoolVar(&wait, "wait", false, messages.StateLockFlagWait)
+	return cmd
+}
+
+// promptBaselineCandidate asks which of several equally matching releases the
+// repo was installed from. The newest candidate is the default.
+func promptBaselineCandidate(in io.Reader, out io.Writer, candidates []install.BaselineCandidate) (string, error) {
+	if _, err := fmt.Fprintln(out, i18n.T(messages.BaselineRebuildAmbiguousHeader)); err != nil {
+		return "", err
+	}
+	options := make([]string, 0, len(candidates))
+	for _, candidate := range candidates {
+		options = append(options, i18n.Sprintf(messages.BaselineRebuildCandidateFmt, candidate.Version, candidate.Matched, candidate.Present))
+	}
+	idx, err := promptNumberedChoice(bufferedReader(in), out, options, 0)
+	if err != nil {
+		return "", err
+	}
+	return candidates[idx].Version, nil
+}
+
+func writeBaselineRebuildResult(out io.Writer, result install.BaselineRebuildResult) error {
+	if result.Origin == install.BaselineRebuildOriginAssumed {
+		_, err := fmt.Fprintf(out, i18n.T(messages.BaselineRebuildAssumedFmt), result.Version, result.Files, result.PreviousState)
+		return err
+	}
+	for _, candidate := range result.Candidates {
+		if candidate.Version != result.Version {
+			continue
+		}
+		_, err := fmt.Fprintf(out, i18n.T(messages.BaselineRebuildMatchedFmt), result.Version, candidate.Matched, candidate.Present, result.Files, result.PreviousState)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/conn-castle/agent-layer/internal/install"
+)
+
+func TestBaselineRebuildCmd(t *testing.T) {
+	stubRepoRoot(t)
+	originalRebuild, originalTerminal := installRebuildBaseline, isTerminal
+	t.Cleanup(func() { installRebuildBaseline, isTerminal = originalRebuild, originalTerminal })
+
+	candidates := []install.BaselineCandidate{
+		{Version: "1.1.0", Matched: 3, Present: 4, Total: 5},
+		{Version: "1.0.0", Matched: 3, Present: 4, Total: 5},
+	}
+	var gotOpts install.BaselineRebuildOptions
+	installRebuildBaseline = func(_ string, opts install.BaselineRebuildOptions) (install.BaselineRebuildResult, error) {
+		gotOpts = opts
+		if opts.AssumeVersion != "" {
+			return install.BaselineRebuildResult{Version: opts.AssumeVersion, Origin: install.BaselineRebuildOriginAssumed, PreviousState: "missing", Files: 5}, nil
+		}
+		chosen := candidates[0].Version
+		if opts.ChooseVersion != nil {
+			var err error
+			if chosen, err = opts.ChooseVersion(candidates); err != nil {
+				return install.BaselineRebuildResult{}, err
+			}
+		}
+		return install.BaselineRebuildResult{Version: chosen, Origin: install.BaselineRebuildOriginManifestMatch, PreviousState: "invalid", Files: 5, Candidates: candidates}, nil
+	}
+
+	cases := []struct {
+		name       string
+		args       []string
+		terminal   bool
+		input      string
+		wantChoose bool
+		wantOut    []string
+	}{
+		{name: "assumed", args: []string{"--assume-version", "1.0.0"}, wantOut: []string{"Rebuilt managed baseline from v1.0.0 (assumed version); 5 files recorded (previous baseline: missing)."}},
+		{name: "non-interactive", wantOut: []string{"Rebuilt managed baseline from v1.1.0: 3 of 4 present managed files match; 5 files recorded (previous baseline: invalid)."}},
+		{name: "interactive choice", terminal: true, input: "2\n", wantChoose: true, wantOut: []string{"Which release", "1) v1.1.0 (3 of 4 present managed files match)", "from v1.0.0:"}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			isTerminal = func() bool { return tc.terminal }
+			cmd := newBaselineCmd()
+			var out bytes.Buffer
+			cmd.SetOut(&out)
+			cmd.SetIn(strings.NewReader(tc.input))
+			cmd.SetArgs(append([]string{"rebuild"}, tc.args...))
+			if err := cmd.Execute(); err != nil {
+				t.Fatalf("execute: %v", err)
+			}
+			if (gotOpts.ChooseVersion != nil) != tc.wantChoose {
+				t.Fatalf("ChooseVersion set = %v, want %v", gotOpts.ChooseVersion != nil, tc.wantChoose)
+			}
+			for _, want := range tc.wantOut {
+				if !strings.Contains(out.String(), want) {
+					t.Fatalf("output missing %q:\n%s", want, out.String())
+				}
+			}
+		})
+	}
+}
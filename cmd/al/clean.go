@@ -0,0 +1,121 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+
+	"github.com/conn-castle/agent-layer/internal/clean"
+	"github.com/conn-castle/agent-layer/internal/errcode"
+	"github.com/conn-castle/agent-layer/internal/i18n"
+	"github.com/conn-castle/agent-layer/internal/messages"
+)
+
+var (
+	planClean  = clean.Plan
+	applyClean = clean.Apply
+)
+
+func newCleanCmd() *cobra.Command {
+	var generated, state, all, dryRun, yes, force, wait bool
+	cmd := &cobra.Command{
+		Use:   messages.CleanUse,
+		Short: messages.CleanShort,
+		Long:  messages.CleanLong,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			root, err := resolveRepoRoot()
+			if err != nil {
+				return err
+			}
+			if !dryRun {
+				lock, err := lockRepoState(cmd.ErrOrStderr(), root, wait)
+				if err != nil {
+					return err
+				}
+				defer func() { _ = lock.Release() }()
+			}
+			opts := clean.Options{Generated: generated || all || !state, State: state || all, Force: force}
+			entries, err := planClean(root, opts)
+			if err != nil {
+				return err
+			}
+			out := cmd.OutOrStdout()
+			removals, err := writeCleanPlan(out, entries)
+			if err != nil {
+				return err
+			}
+			if removals == 0 || dryRun {
+				return nil
+			}
+			if !yes {
+				if !isTerminal() {
+					return errcode.Wrap(errcode.InputRequired, errors.New(i18n.T(messages.CleanNeedsYes)))
+				}
+				confirmed, err := promptYesNo(cmd.InOrStdin(), out, messages.CleanConfirmPrompt, false)
+				if err != nil {
+					return err
+				}
+				if !confirmed {
+					_, err := fmt.Fprintln(out, i18n.T(messages.CleanCancelled))
+					return err
+				}
+			}
+			removed, err := applyClean(root, entries)
+			if err != nil {
+				return err
+			}
+			if _, err := fmt.Fprintf(out, i18n.T(messages.CleanResultFmt), removed); err != nil {
+				return err
+			}
+			if opts.Generated {
+				_, err = fmt.Fprintln(out, i18n.T(messages.CleanSyncHint))
+			}
+			return err
+		},
+	}
+	cmd.Flags().BoolVar(&generated, "generated", false, messages.CleanFlagGenerated)
+	cmd.Flags().BoolVar(&state, "state", false, messages.CleanFlagState)
+	cmd.Flags().BoolVar(&all, "all", false, messages.CleanFlagAll)
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, messages.CleanFlagDryRun)
+	cmd.Flags().BoolVar(&yes, "yes", false, messages.CleanFlagYes)
+	cmd.Flags().BoolVar(&force, "force", false, messages.CleanFlagForce)
+	cmd.Flags().BoolVar(&wait, "wait", false, messages.StateLockFlagWait)
+	return cmd
+}
+
+// writeCleanPlan lists the entries to remove, then the entries kept, and
+// returns how many will be removed.
+func writeCleanPlan(out io.Writer, entries []clean.Entry) (int, error) {
+	if len(entries) == 0 {
+		_, err := fmt.Fprintln(out, i18n.T(messages.CleanNothing))
+		return 0, err
+	}
+	removals := 0
+	for _, section := range []struct {
+		header string
+		remove bool
+	}{{messages.CleanRemoveHeader, true}, {messages.CleanKeepHeader, false}} {
+		printed := false
+		for _, entry := range entries {
+			if entry.Remove != section.remove {
+				continue
+			}
+			if !printed {
+				if _, err := fmt.Fprintln(out, section.header); err != nil {
+					return 0, err
+				}
+				printed = true
+			}
+			if entry.Remove {
+				removals++
+			}
+			if _, err := fmt.Fprintf(out, i18n.T(messages.CleanEntryFmt), entry.Path, entry.Reason); err != nil {
+				return 0, err
+			}
+		}
+	}
+	return removals, nil
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/conn-castle/agent-layer/internal/clean"
+)
+
+func stubClean(t *testing.T, entries []clean.Entry) (*clean.Options, *bool) {
+	t.Helper()
+	originalPlan, originalApply := planClean, applyClean
+	var gotOpts clean.Options
+	applied := false
+	planClean = func(_ string, opts clean.Options) ([]clean.Entry, error) {
+		gotOpts = opts
+		return entries, nil
+	}
+	applyClean = func(_ string, got []clean.Entry) (int, error) {
+		applied = true
+		return 1, nil
+	}
+	t.Cleanup(func() { planClean, applyClean = originalPlan, originalApply })
+	return &gotOpts, &applied
+}
+
+func TestCleanCmd(t *testing.T) {
+	stubRepoRoot(t)
+	entries := []clean.Entry{
+		{Path: "AGENTS.md", Category: clean.CategoryGenerated, Remove: true, Reason: "generated by al sync"},
+		{Path: ".claude/settings.json", Category: clean.CategoryGenerated, Reason: "patched in place and holds your own settings"},
+	}
+	opts, applied := stubClean(t, entries)
+
+	cases := []struct {
+		name        string
+		args        []string
+		terminal    bool
+		input       string
+		wantOpts    clean.Options
+		wantApplied bool
+		wantErr     string
+		wantOut     string
+	}{
+		{name: "default yes", args: []string{"--yes"}, wantOpts: clean.Options{Generated: true}, wantApplied: true, wantOut: "Removed 1 paths."},
+		{name: "all force", args: []string{"--all", "--force", "--yes"}, wantOpts: clean.Options{Generated: true, State: true, Force: true}, wantApplied: true},
+		{name: "state only", args: []string{"--state", "--dry-run"}, wantOpts: clean.Options{State: true}},
+		{name: "non-interactive", args: nil, wantOpts: clean.Options{Generated: true}, wantErr: "--yes"},
+		{name: "declined", args: nil, terminal: true, input: "n\n", wantOpts: clean.Options{Generated: true}, wantOut: "Nothing removed."},
+		{name: "confirmed", args: nil, terminal: true, input: "y\n", wantOpts: clean.Options{Generated: true}, wantApplied: true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			*applied = false
+			originalTerminal := isTerminal
+			isTerminal = func() bool { return tc.terminal }
+			t.Cleanup(func() { isTerminal = originalTerminal })
+
+			cmd := newCleanCmd()
+			var out bytes.Buffer
+			cmd.SetOut(&out)
+			cmd.SetErr(&bytes.Buffer{})
+			cmd.SetIn(strings.NewReader(tc.input))
+			cmd.SetArgs(tc.args)
+			err := cmd.Execute()
+			if tc.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tc.wantErr, err)
+				}
+			} else if err != nil {
+				t.Fatalf("clean: %v", err)
+			}
+			if *opts != tc.wantOpts {
+				t.Fatalf("options = %+v, want %+v", *opts, tc.wantOpts)
+			}
+			if *applied != tc.wantApplied {
+				t.Fatalf("applied = %v, want %v", *applied, tc.wantApplied)
+			}
+			text := out.String()
+			if !strings.Contains(text, "Will remove:\n  AGENTS.md (generated by al sync)\nKeeping:\n  .claude/settings.json") {
+				t.Fatalf("unexpected plan output:\n%s", text)
+			}
+			if tc.wantOut != "" && !strings.Contains(text, tc.wantOut) {
+				t.Fatalf("expected %q in output:\n%s", tc.wantOut, text)
+			}
+		})
+	}
+}
+
+func TestCleanCmd_Nothing(t *testing.T) {
+	stubRepoRoot(t)
+	_, applied := stubClean(t, nil)
+	cmd := newCleanCmd()
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetArgs(nil)
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("clean: %v", err)
+	}
+	if *applied || !strings.Contains(out.String(), "Nothing to clean.") {
+		t.Fatalf("applied=%v output=%q", *applied, out.String())
+	}
+}
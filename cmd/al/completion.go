@@ -12,13 +12,15 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/conn-castle/agent-layer/internal/fsutil"
+	"github.com/conn-castle/agent-layer/internal/i18n"
 	"github.com/conn-castle/agent-layer/internal/messages"
 )
 
 const (
-	shellBash = "bash"
-	shellZsh  = "zsh"
-	shellFish = "fish"
+	shellBash       = "bash"
+	shellZsh        = "zsh"
+	shellFish       = "fish"
+	shellPowerShell = "powershell"
 )
 
 var (
@@ -34,6 +36,9 @@ var (
 	genFishCompletion = func(cmd *cobra.Command, out io.Writer) error {
 		return cmd.GenFishCompletion(out, true)
 	}
+	genPowerShellCompletion = func(cmd *cobra.Command, out io.Writer) error {
+		return cmd.GenPowerShellCompletionWithDesc(out)
+	}
 )
 
 // newCompletionCmd builds the completion subcommand with optional install behavior.
@@ -43,7 +48,7 @@ func newCompletionCmd() *cobra.Command {
 		Use:       messages.CompletionUse,
 		Short:     messages.CompletionShort,
 		Args:      cobra.ExactArgs(1),
-		ValidArgs: []string{shellBash, shellZsh, shellFish},
+		ValidArgs: []string{shellBash, shellZsh, shellFish, shellPowerShell},
 		RunE: func(cmd *cobra.Command, args []string) error {
 			shell := args[0]
 			script, err := generateCompletion(cmd.Root(), shell)
@@ -77,8 +82,12 @@ func generateCompletion(root *cobra.Command, shell string) (string, error) {
 		if err := genFishCompletion(root, &buf); err != nil {
 			return "", err
 		}
+	case shellPowerShell:
+		if err := genPowerShellCompletion(root, &buf); err != nil {
+			return "", err
+		}
 	default:
-		return "", fmt.Errorf(messages.CompletionUnsupportedShellFmt, shell)
+		return "", i18n.Errorf(messages.CompletionUnsupportedShellFmt, shell)
 	}
 	return buf.String(), nil
 }
@@ -90,13 +99,13 @@ func installCompletion(shell string, script string, out io.Writer) error {
 		return err
 	}
 	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil { // #nosec G301 -- shell completion dirs (e.g. ~/.zfunc, ~/.bash_completion.d) must be world-traversable so the user's interactive shell can source the script.
-		return fmt.Errorf(messages.CompletionCreateDirErrFmt, err)
+		return i18n.Errorf(messages.CompletionCreateDirErrFmt, err)
 	}
 	if err := fsutil.WriteFileAtomic(path, []byte(script), 0o644); err != nil {
-		return fmt.Errorf(messages.CompletionWriteFileErrFmt, err)
+		return i18n.Errorf(messages.CompletionWriteFileErrFmt, err)
 	}
 
-	if _, err := fmt.Fprintf(out, messages.CompletionInstalledFmt, shell, path); err != nil {
+	if _, err := fmt.Fprintf(out, i18n.T(messages.CompletionInstalledFmt), shell, path); err != nil {
 		return err
 	}
 	if note != "" {
@@ -136,10 +145,13 @@ func completionInstallPath(shell string) (string, string, error) {
 			return "", "", err
 		}
 		fallbackDir := filepath.Join(xdgData, "zsh", "site-functions")
-		note := fmt.Sprintf(messages.CompletionZshNoteFmt, fallbackDir)
+		note := i18n.Sprintf(messages.CompletionZshNoteFmt, fallbackDir)
 		return filepath.Join(fallbackDir, "_al"), note, nil
+	case shellPowerShell:
+		// PowerShell has no completion directory; scripts load from $PROFILE.
+		return "", "", i18n.Errorf(messages.CompletionPowerShellInstallUnsupported)
 	default:
-		return "", "", fmt.Errorf(messages.CompletionUnsupportedShellFmt, shell)
+		return "", "", i18n.Errorf(messages.CompletionUnsupportedShellFmt, shell)
 	}
 }
 
@@ -150,7 +162,7 @@ func xdgDataHome() (string, error) {
 	}
 	home, err := userHomeDir()
 	if err != nil {
-		return "", fmt.Errorf(messages.CompletionResolveHomeErrFmt, err)
+		return "", i18n.Errorf(messages.CompletionResolveHomeErrFmt, err)
 	}
 	return filepath.Join(home, ".local", "share"), nil
 }
@@ -162,7 +174,7 @@ func xdgConfigHome() (string, error) {
 	}
 	home, err := userHomeDir()
 	if err != nil {
-		return "", fmt.Errorf(messages.CompletionResolveHomeErrFmt, err)
+		return "", i18n.Errorf(messages.CompletionResolveHomeErrFmt, err)
 	}
 	return filepath.Join(home, ".config"), nil
 }
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/spf13/cobra"
+
+	"github.com/conn-castle/agent-layer/internal/agentdispatch"
+	"github.com/conn-castle/agent-layer/internal/config"
+	"github.com/conn-castle/agent-layer/internal/install"
+	"github.com/conn-castle/agent-layer/internal/lockfile"
+	"github.com/conn-castle/agent-layer/internal/wizard"
+)
+
+// Dynamic completions read repo state at tab time. Every failure yields no
+// suggestions rather than an error, because completion runs inside the
+// user's shell and must never print diagnostics there.
+
+var listUpgradeSnapshots = install.ListUpgradeSnapshots
+
+var listUpgradeHistory = install.ListUpgradeHistory
+
+// completeLockedSkills suggests skills recorded in al.lock that are not
+// already on the command line.
+func completeLockedSkills(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	root, err := resolveRepoRoot()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	lock, err := lockfile.Load(root)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	var names []string
+	for _, skill := range lock.Skills {
+		if !slices.Contains(args, skill.Name) {
+			names = append(names, fmt.Sprintf("%s\t%s", skill.Name, skill.Source))
+		}
+	}
+	return names, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeProjectSkills suggests skills under .agent-layer/skills/.
+func completeProjectSkills(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	root, err := resolveRepoRoot()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	skills, err := config.LoadSkills(config.DefaultPaths(root).SkillsDir)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	names := make([]string, 0, len(skills))
+	for _, skill := range skills {
+		names = append(names, fmt.Sprintf("%s\t%s", skill.Name, skill.Description))
+	}
+	return names, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeSnapshotIDs suggests upgrade snapshot IDs, newest first.
+func completeSnapshotIDs(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	root, err := resolveRepoRoot()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	snapshots, err := listUpgradeSnapshots(root, install.RealSystem{})
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	ids := make([]string, 0, len(snapshots))
+	for _, snapshot := range snapshots {
+		ids = append(ids, fmt.Sprintf("%s\t%s, %s", snapshot.ID, snapshot.CreatedAtUTC, snapshot.Status))
+	}
+	return ids, cobra.ShellCompDirectiveKeepOrder | cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeUpgradeHistoryIDs suggests recorded upgrade IDs, newest first.
+func completeUpgradeHistoryIDs(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	root, err := resolveRepoRoot()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	records, err := listUpgradeHistory(root, install.RealSystem{})
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	ids := make([]string, 0, len(records))
+	for _, record := range records {
+		ids = append(ids, fmt.Sprintf("%s\t%s, %s", record.ID, record.CreatedAtUTC, upgradeHistoryVersions(record.MigrationReport)))
+	}
+	return ids, cobra.ShellCompDirectiveKeepOrder | cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeMCPClients suggests the client names accepted by mcp.servers[].clients.
+func completeMCPClients(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return config.MCPClients(), cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeMCPRegistryIDs suggests the servers al mcp add can insert, with
+// their descriptions.
+func completeMCPRegistryIDs(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	entries, err := wizard.LoadMCPRegistry()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	ids := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		ids = append(ids, fmt.Sprintf("%s\t%s", entry.ID, entry.Description))
+	}
+	return ids, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeDispatchAgents suggests the agents al dispatch can start.
+func completeDispatchAgents(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return []string{agentdispatch.AgentAntigravity, agentdispatch.AgentClaude, agentdispatch.AgentCodex}, cobra.ShellCompDirectiveNoFileComp
+}
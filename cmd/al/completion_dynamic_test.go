@@ -0,0 +1,120 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/conn-castle/agent-layer/internal/install"
+	"github.com/conn-castle/agent-layer/internal/lockfile"
+)
+
+// runCompletion asks the root command for completions of args, the way shell
+// scripts do, and returns the suggestion lines without the directive.
+func runCompletion(t *testing.T, args ...string) []string {
+	t.Helper()
+	cmd := newRootCmd()
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs(append([]string{"__complete"}, args...))
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("complete %v: %v", args, err)
+	}
+	var lines []string
+	for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+		if line != "" && !strings.HasPrefix(line, ":") {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+func TestCompleteLockedSkills(t *testing.T) {
+	root := stubRepoRoot(t)
+	lock := lockfile.File{Version: lockfile.SchemaVersion}
+	lock.SetSkill(lockfile.Skill{Name: "alpha", Source: "github.com/o/r/alpha@v1", Checksum: "sha256:a"})
+	lock.SetSkill(lockfile.Skill{Name: "beta", Source: "github.com/o/r/beta@v1", Checksum: "sha256:b"})
+	if err := lockfile.Save(root, lock); err != nil {
+		t.Fatalf("save lock: %v", err)
+	}
+
+	got := runCompletion(t, "update", "alpha", "")
+	if len(got) != 1 || got[0] != "beta\tgithub.com/o/r/beta@v1" {
+		t.Fatalf("unexpected completions: %q", got)
+	}
+}
+
+func TestCompleteLockedSkills_NoLock(t *testing.T) {
+	stubRepoRoot(t)
+	if got := runCompletion(t, "update", ""); len(got) != 0 {
+		t.Fatalf("expected no completions, got %q", got)
+	}
+}
+
+func TestCompleteProjectSkills(t *testing.T) {
+	root := stubRepoRoot(t)
+	skillDir := filepath.Join(root, ".agent-layer", "skills", "review")
+	if err := os.MkdirAll(skillDir, 0o700); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	skill := "---\nname: review\ndescription: Review a change\n---\n\nBody\n"
+	if err := os.WriteFile(filepath.Join(skillDir, "SKILL.md"), []byte(skill), 0o600); err != nil {
+		t.Fatalf("write skill: %v", err)
+	}
+
+	got := runCompletion(t, "dispatch", "start", "--skill", "")
+	if len(got) != 1 || got[0] != "review\tReview a change" {
+		t.Fatalf("unexpected completions: %q", got)
+	}
+}
+
+func TestCompleteSnapshotIDs(t *testing.T) {
+	stubRepoRoot(t)
+	original := listUpgradeSnapshots
+	listUpgradeSnapshots = func(root string, sys install.System) ([]install.UpgradeSnapshotMetadata, error) {
+		return []install.UpgradeSnapshotMetadata{
+			{ID: "20260102T000000Z", CreatedAtUTC: "2026-01-02T00:00:00Z", Status: "applied"},
+			{ID: "20260101T000000Z", CreatedAtUTC: "2026-01-01T00:00:00Z", Status: "rolled_back"},
+		}, nil
+	}
+	t.Cleanup(func() { listUpgradeSnapshots = original })
+
+	got := runCompletion(t, "upgrade", "rollback", "")
+	want := []string{
+		"20260102T000000Z\t2026-01-02T00:00:00Z, applied",
+		"20260101T000000Z\t2026-01-01T00:00:00Z, rolled_back",
+	}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Fatalf("unexpected completions: %q", got)
+	}
+	if got := runCompletion(t, "upgrade", "rollback", "20260102T000000Z", ""); len(got) != 0 {
+		t.Fatalf("expected no completions after snapshot id, got %q", got)
+	}
+
+	listUpgradeSnapshots = func(string, install.System) ([]install.UpgradeSnapshotMetadata, error) {
+		return nil, errors.New("boom")
+	}
+	if got := runCompletion(t, "upgrade", "rollback", ""); len(got) != 0 {
+		t.Fatalf("expected no completions on error, got %q", got)
+	}
+}
+
+func TestCompleteFixedFlagValues(t *testing.T) {
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{args: []string{"mcp", "gateway", "--client", ""}, want: "antigravity|claude|codex|copilot|vscode"},
+		{args: []string{"dispatch", "start", "--agent", ""}, want: "antigravity|claude|codex"},
+		{args: []string{"--error-format", ""}, want: "text|json"},
+	}
+	for _, tt := range tests {
+		if got := strings.Join(runCompletion(t, tt.args...), "|"); got != tt.want {
+			t.Fatalf("%v: got %q, want %q", tt.args, got, tt.want)
+		}
+	}
+}
@@ -88,7 +88,7 @@ func TestCompletionInstallPathZshFallback(t *testing.T) {
 
 func TestNewCompletionCmd(t *testing.T) {
 	cmd := newCompletionCmd()
-	if cmd.Use != "completion [bash|zsh|fish]" {
+	if cmd.Use != "completion [bash|zsh|fish|powershell]" {
 		t.Errorf("unexpected usage: %s", cmd.Use)
 	}
 
@@ -214,6 +214,7 @@ func TestGenerateCompletion(t *testing.T) {
 		{"bash", false},
 		{"zsh", false},
 		{"fish", false},
+		{"powershell", false},
 		{"unknown", true},
 	}
 
@@ -235,10 +236,12 @@ func TestGenerateCompletion_Error(t *testing.T) {
 	origBash := genBashCompletion
 	origZsh := genZshCompletion
 	origFish := genFishCompletion
+	origPowerShell := genPowerShellCompletion
 	t.Cleanup(func() {
 		genBashCompletion = origBash
 		genZshCompletion = origZsh
 		genFishCompletion = origFish
+		genPowerShellCompletion = origPowerShell
 	})
 
 	errBoom := errors.New("boom")
@@ -268,6 +271,13 @@ func TestGenerateCompletion_Error(t *testing.T) {
 				genFishCompletion = func(_ *cobra.Command, _ io.Writer) error { return errBoom }
 			},
 		},
+		{
+			name:  "powershell",
+			shell: shellPowerShell,
+			setup: func() {
+				genPowerShellCompletion = func(_ *cobra.Command, _ io.Writer) error { return errBoom }
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -275,6 +285,7 @@ func TestGenerateCompletion_Error(t *testing.T) {
 			genBashCompletion = origBash
 			genZshCompletion = origZsh
 			genFishCompletion = origFish
+			genPowerShellCompletion = origPowerShell
 			tt.setup()
 
 			cmd := newCompletionCmd()
@@ -357,6 +368,13 @@ func TestInstallCompletion_NoteWriteError(t *testing.T) {
 	}
 }
 
+func TestCompletionInstallPathPowerShellUnsupported(t *testing.T) {
+	_, _, err := completionInstallPath(shellPowerShell)
+	if err == nil || !strings.Contains(err.Error(), "$PROFILE") {
+		t.Fatalf("expected PowerShell install error, got %v", err)
+	}
+}
+
 func TestXdgDataHomeFallback(t *testing.T) {
 	t.Setenv("XDG_DATA_HOME", "")
 	// We can't easily unset HOME/USERPROFILE in a cross-platform way safely for other tests,
@@ -0,0 +1,265 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/conn-castle/agent-layer/internal/config"
+	"github.com/conn-castle/agent-layer/internal/envcrypt"
+	"github.com/conn-castle/agent-layer/internal/errcode"
+	"github.com/conn-castle/agent-layer/internal/fsutil"
+	"github.com/conn-castle/agent-layer/internal/i18n"
+	"github.com/conn-castle/agent-layer/internal/messages"
+	"github.com/conn-castle/agent-layer/internal/wizard"
+)
+
+var (
+	lintConfig      = config.LintConfig
+	encryptEnvFile  = envcrypt.EncryptFile
+	decryptEnvFile  = envcrypt.DecryptFile
+	reconcileConfig = wizard.Reconcile
+)
+
+func newConfigCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   messages.ConfigUse,
+		Short: messages.ConfigShort,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Help()
+		},
+	}
+	cmd.AddCommand(newConfigLintCmd(), newConfigReconcileCmd(), newConfigEncryptCmd(), newConfigDecryptCmd())
+	return cmd
+}
+
+func newConfigLintCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   messages.ConfigLintUse,
+		Short: messages.ConfigLintShort,
+		Long:  messages.ConfigLintLong,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			root, err := resolveRepoRoot()
+			if err != nil {
+				return err
+			}
+			issues, err := lintConfig(root)
+			if err != nil {
+				return errcode.Wrap(errcode.Config, err)
+			}
+			out := cmd.OutOrStdout()
+			configPath := config.DefaultPaths(root).ConfigPath
+			if len(issues) == 0 {
+				_, err := fmt.Fprintf(out, i18n.T(messages.ConfigLintCleanFmt), configPath)
+				return err
+			}
+			for _, issue := range issues {
+				if _, err := fmt.Fprintf(out, i18n.T(messages.ConfigLintIssueFmt), issue.Kind, issue.Message); err != nil {
+					return err
+				}
+			}
+			return errcode.Wrap(errcode.Config, i18n.Errorf(messages.ConfigLintFailedFmt, len(issues), configPath))
+		},
+	}
+}
+
+func newConfigReconcileCmd() *cobra.Command {
+	var dryRun, yes bool
+	cmd := &cobra.Command{
+		Use:   messages.ConfigReconcileUse,
+		Short: messages.ConfigReconcileShort,
+		Long:  messages.ConfigReconcileLong,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			root, err := resolveRepoRoot()
+			if err != nil {
+				return err
+			}
+			configPath := config.DefaultPaths(root).ConfigPath
+			info, err := os.Stat(configPath)
+			if err != nil {
+				return errcode.Wrap(errcode.Config, i18n.Errorf(messages.ConfigMissingFileFmt, configPath, err))
+			}
+			data, err := os.ReadFile(configPath)
+			if err != nil {
+				return err
+			}
+			all := wizard.ReconcileOptions{AddKeys: true, RestoreComments: true, Reorder: true}
+			_, changes, err := reconcileConfig(string(data), all)
+			if err != nil {
+				return errcode.Wrap(errcode.Config, err)
+			}
+			out := cmd.OutOrStdout()
+			if len(changes) == 0 {
+				_, err := fmt.Fprintf(out, i18n.T(messages.ConfigReconcileNothingFmt), configPath)
+				return err
+			}
+
+			in := bufferedReader(cmd.InOrStdin())
+			var accepted []string
+			for _, step := range wizard.ReconcileSteps {
+				stepChanges := reconcileChangesFor(changes, step)
+				if len(stepChanges) == 0 {
+					continue
+				}
+				if err := writeReconcileStep(out, step, stepChanges); err != nil {
+					return err
+				}
+				if dryRun {
+					continue
+				}
+				if !yes {
+					if !isTerminal() {
+						return errcode.Wrap(errcode.InputRequired, errors.New(i18n.T(messages.ConfigReconcileNeedsYes)))
+					}
+					confirmed, err := promptYesNo(in, out, reconcileStepPrompts[step], true)
+					if err != nil {
+						return err
+					}
+					if !confirmed {
+						continue
+					}
+				}
+				accepted = append(accepted, step)
+			}
+			if dryRun {
+				return nil
+			}
+			if len(accepted) == 0 {
+				_, err := fmt.Fprintln(out, i18n.T(messages.ConfigReconcileCancelled))
+				return err
+			}
+
+			opts := wizard.ReconcileOptions{
+				AddKeys:         slices.Contains(accepted, wizard.ReconcileAddKeys),
+				RestoreComments: slices.Contains(accepted, wizard.ReconcileRestoreComments),
+				Reorder:         slices.Contains(accepted, wizard.ReconcileReorder),
+			}
+			updated, _, err := reconcileConfig(string(data), opts)
+			if err != nil {
+				return errcode.Wrap(errcode.Config, err)
+			}
+			if err := fsutil.WriteFileAtomic(configPath, []byte(updated), info.Mode().Perm()); err != nil {
+				return err
+			}
+			if _, err := fmt.Fprintf(out, i18n.T(messages.ConfigReconcileResultFmt), configPath, strings.Join(accepted, ", ")); err != nil {
+				return err
+			}
+			_, err = fmt.Fprintln(out, i18n.T(messages.ConfigReconcileSyncHint))
+			return err
+		},
+	}
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, messages.ConfigReconcileFlagDryRun)
+	cmd.Flags().BoolVar(&yes, "yes", false, messages.ConfigReconcileFlagYes)
+	return cmd
+}
+
+var (
+	reconcileStepHeaders = map[string]string{
+		wizard.ReconcileAddKeys:         messages.ConfigReconcileAddKeysHeader,
+		wizard.ReconcileRestoreComments: messages.ConfigReconcileCommentsHeader,
+		wizard.ReconcileReorder:         messages.ConfigReconcileReorderHeader,
+	}
+	reconcileStepPrompts = map[string]string{
+		wizard.ReconcileAddKeys:         messages.ConfigReconcileAddKeysPrompt,
+		wizard.ReconcileRestoreComments: messages.ConfigReconcileCommentsPrompt,
+		wizard.ReconcileReorder:         messages.ConfigReconcileReorderPrompt,
+	}
+)
+
+func reconcileChangesFor(changes []wizard.ReconcileChange, step string) []wizard.ReconcileChange {
+	var matched []wizard.ReconcileChange
+	for _, change := range changes {
+		if change.Step == step {
+			matched = append(matched, change)
+		}
+	}
+	return matched
+}
+
+// writeReconcileStep lists what one reconcile step would change: the keys,
+// sections, or comments it adds, or the canonical section order.
+func writeReconcileStep(out io.Writer, step string, changes []wizard.ReconcileChange) error {
+	if _, err := fmt.Fprintln(out, reconcileStepHeaders[step]); err != nil {
+		return err
+	}
+	for _, change := range changes {
+		label := reconcileChangeLabel(change)
+		if _, err := fmt.Fprintf(out, i18n.T(messages.ConfigReconcileEntryFmt), label); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func reconcileChangeLabel(change wizard.ReconcileChange) string {
+	switch {
+	case change.Step == wizard.ReconcileReorder:
+		return strings.Join(change.Lines, ", ")
+	case change.Section == "":
+		return messages.ConfigReconcilePreamble
+	case change.Key == "":
+		return "[" + change.Section + "]"
+	case strings.HasPrefix(change.Key, "["):
+		return change.Key
+	default:
+		return change.Section + "." + change.Key
+	}
+}
+
+func newConfigEncryptCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   messages.ConfigEncryptUse,
+		Short: messages.ConfigEncryptShort,
+		Long:  messages.ConfigEncryptLong,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			root, err := resolveRepoRoot()
+			if err != nil {
+				return err
+			}
+			envPath := config.DefaultPaths(root).EnvPath
+			changed, err := encryptEnvFile(envPath, envcrypt.RecipientsPath(root), args)
+			if err != nil {
+				return errcode.Wrap(errcode.Config, err)
+			}
+			return writeConfigCryptResult(cmd, messages.ConfigCryptEncrypted, messages.ConfigCryptEncrypt, envPath, changed)
+		},
+	}
+}
+
+func newConfigDecryptCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   messages.ConfigDecryptUse,
+		Short: messages.ConfigDecryptShort,
+		Long:  messages.ConfigDecryptLong,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			root, err := resolveRepoRoot()
+			if err != nil {
+				return err
+			}
+			envPath := config.DefaultPaths(root).EnvPath
+			changed, err := decryptEnvFile(envPath, args)
+			if err != nil {
+				return errcode.Wrap(errcode.Config, err)
+			}
+			return writeConfigCryptResult(cmd, messages.ConfigCryptDecrypted, messages.ConfigCryptDecrypt, envPath, changed)
+		},
+	}
+}
+
+func writeConfigCryptResult(cmd *cobra.Command, done string, verb string, envPath string, changed []string) error {
+	out := cmd.OutOrStdout()
+	if len(changed) == 0 {
+		_, err := fmt.Fprintf(out, i18n.T(messages.ConfigCryptNoneFmt), verb, envPath)
+		return err
+	}
+	_, err := fmt.Fprintf(out, i18n.T(messages.ConfigCryptChangedFmt), done, len(changed), envPath, strings.Join(changed, ", "))
+	return err
+}
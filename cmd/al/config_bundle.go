@@ -0,0 +1,111 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/conn-castle/agent-layer/internal/configbundle"
+	"github.com/conn-castle/agent-layer/internal/i18n"
+	"github.com/conn-castle/agent-layer/internal/messages"
+)
+
+var (
+	exportConfigBundle = configbundle.Export
+	importConfigBundle = configbundle.Import
+	exportEnvironment  = configbundle.ExportEnvironment
+)
+
+func newExportCmd() *cobra.Command {
+	var output string
+	var includeSecrets bool
+	cmd := &cobra.Command{
+		Use:   messages.ExportUse,
+		Short: messages.ExportShort,
+		Long:  messages.ExportLong,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			root, err := resolveRepoRoot()
+			if err != nil {
+				return err
+			}
+			manifest, err := exportEnvironment(root, output, configbundle.EnvironmentOptions{Version: Version, IncludeSecrets: includeSecrets})
+			if err != nil {
+				return err
+			}
+			if _, err := fmt.Fprintf(cmd.OutOrStdout(), i18n.T(messages.ExportResultFmt), len(manifest.Files), output, manifest.ALVersion); err != nil {
+				return err
+			}
+			if manifest.Secrets {
+				commandOutput(cmd, noiseModeFromConfig(root)).Infof(messages.ExportSecretsWarningFmt, output)
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&output, "output", "", messages.ExportFlagOutput)
+	cmd.Flags().BoolVar(&includeSecrets, "include-secrets", false, messages.ExportFlagIncludeSecrets)
+	_ = cmd.MarkFlagRequired("output")
+	return cmd
+}
+
+func newExportConfigCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   messages.ExportConfigUse,
+		Short: messages.ExportConfigShort,
+		Long:  messages.ExportConfigLong,
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			root, err := resolveRepoRoot()
+			if err != nil {
+				return err
+			}
+			manifest, err := exportConfigBundle(root, args[0], configbundle.ExportOptions{Version: Version})
+			if err != nil {
+				return err
+			}
+			_, err = fmt.Fprintf(cmd.OutOrStdout(), i18n.T(messages.ExportConfigResultFmt), len(manifest.Files), args[0], manifest.ALVersion)
+			return err
+		},
+	}
+}
+
+func newImportConfigCmd() *cobra.Command {
+	var force, wait bool
+	cmd := &cobra.Command{
+		Use:   messages.ImportConfigUse,
+		Short: messages.ImportConfigShort,
+		Long:  messages.ImportConfigLong,
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// Importing is how a setup reaches a repo that has no .agent-layer
+			// yet, so resolve the root the way init does.
+			root, _, err := resolveInitRoot(false)
+			if err != nil {
+				return err
+			}
+			lock, err := lockRepoState(cmd.ErrOrStderr(), root, wait)
+			if err != nil {
+				return err
+			}
+			defer func() { _ = lock.Release() }()
+			manifest, err := importConfigBundle(root, args[0], force)
+			if err != nil {
+				return err
+			}
+			out := cmd.OutOrStdout()
+			if _, err := fmt.Fprintf(out, i18n.T(messages.ImportConfigResultFmt), len(manifest.Files), args[0], manifest.ALVersion, manifest.CreatedAt); err != nil {
+				return err
+			}
+			if manifest.ALVersion != Version {
+				if _, err := fmt.Fprintf(out, i18n.T(messages.ImportConfigVersionNote), manifest.ALVersion, Version); err != nil {
+					return err
+				}
+			}
+			_, err = fmt.Fprintln(out, i18n.T(messages.ImportConfigSyncHint))
+			return err
+		},
+	}
+	cmd.Flags().BoolVar(&force, "force", false, messages.ImportConfigFlagForce)
+	cmd.Flags().BoolVar(&wait, "wait", false, messages.StateLockFlagWait)
+	return cmd
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/conn-castle/agent-layer/internal/configbundle"
+	"github.com/conn-castle/agent-layer/internal/messages"
+)
+
+func TestExportCmd(t *testing.T) {
+	root := stubRepoRoot(t)
+	original := exportEnvironment
+	exportEnvironment = func(gotRoot string, dest string, opts configbundle.EnvironmentOptions) (configbundle.Manifest, error) {
+		if canonicalPath(gotRoot) != canonicalPath(root) || dest != "env.tar.gz" || opts.Version != Version {
+			t.Fatalf("ExportEnvironment(%q, %q, %+v)", gotRoot, dest, opts)
+		}
+		return configbundle.Manifest{ALVersion: opts.Version, Secrets: opts.IncludeSecrets, Files: make([]configbundle.File, 5)}, nil
+	}
+	t.Cleanup(func() { exportEnvironment = original })
+
+	for _, secrets := range []bool{false, true} {
+		cmd := newExportCmd()
+		var out, errOut bytes.Buffer
+		cmd.SetOut(&out)
+		cmd.SetErr(&errOut)
+		args := []string{"--output", "env.tar.gz"}
+		if secrets {
+			args = append(args, "--include-secrets")
+		}
+		cmd.SetArgs(args)
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("export: %v", err)
+		}
+		if !strings.Contains(out.String(), "Exported 5 files to env.tar.gz") {
+			t.Fatalf("unexpected output: %q", out.String())
+		}
+		if got := strings.Contains(errOut.String(), "contains secrets"); got != secrets {
+			t.Fatalf("secrets warning = %v with --include-secrets=%v: %q", got, secrets, errOut.String())
+		}
+	}
+
+	rootCmd := newRootCmd()
+	var quietErr bytes.Buffer
+	rootCmd.SetOut(&bytes.Buffer{})
+	rootCmd.SetErr(&quietErr)
+	rootCmd.SetArgs([]string{"--quiet", "export", "--output", "env.tar.gz", "--include-secrets"})
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("quiet export: %v", err)
+	}
+	if quietErr.Len() != 0 {
+		t.Fatalf("expected --quiet to hide the secrets warning, got %q", quietErr.String())
+	}
+
+	cmd := newExportCmd()
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs(nil)
+	if err := cmd.Execute(); err == nil || !strings.Contains(err.Error(), "output") {
+		t.Fatalf("expected missing --output error, got %v", err)
+	}
+}
+
+func TestExportConfigCmd(t *testing.T) {
+	root := stubRepoRoot(t)
+	original := exportConfigBundle
+	exportConfigBundle = func(gotRoot string, dest string, opts configbundle.ExportOptions) (configbundle.Manifest, error) {
+		if canonicalPath(gotRoot) != canonicalPath(root) || dest != "out.tar.gz" || opts.Version != Version {
+			t.Fatalf("Export(%q, %q, %+v)", gotRoot, dest, opts)
+		}
+		return configbundle.Manifest{ALVersion: opts.Version, Files: make([]configbundle.File, 3)}, nil
+	}
+	t.Cleanup(func() { exportConfigBundle = original })
+
+	cmd := newExportConfigCmd()
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"out.tar.gz"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("export-config: %v", err)
+	}
+	if !strings.Contains(out.String(), "Exported 3 files to out.tar.gz") {
+		t.Fatalf("unexpected output: %q", out.String())
+	}
+}
+
+func TestImportConfigCmd(t *testing.T) {
+	root := stubRepoRoot(t)
+	original := importConfigBundle
+	var gotForce bool
+	importConfigBundle = func(gotRoot string, src string, force bool) (configbundle.Manifest, error) {
+		if canonicalPath(gotRoot) != canonicalPath(root) || src != "in.tar.gz" {
+			t.Fatalf("Import(%q, %q)", gotRoot, src)
+		}
+		gotForce = force
+		return configbundle.Manifest{ALVersion: "0.0.1", CreatedAt: "2026-01-01T00:00:00Z", Files: make([]configbundle.File, 2)}, nil
+	}
+	t.Cleanup(func() { importConfigBundle = original })
+
+	cmd := newImportConfigCmd()
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"--force", "in.tar.gz"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("import-config: %v", err)
+	}
+	if !gotForce {
+		t.Fatal("expected --force to be passed through")
+	}
+	for _, want := range []string{"Imported 2 files from in.tar.gz (exported by al 0.0.1", "Note: the bundle was exported by al 0.0.1", messages.ImportConfigSyncHint} {
+		if !strings.Contains(out.String(), want) {
+			t.Fatalf("expected %q in output:\n%s", want, out.String())
+		}
+	}
+}
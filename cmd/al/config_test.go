@@ -0,0 +1,238 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/conn-castle/agent-layer/internal/config"
+	"github.com/conn-castle/agent-layer/internal/errcode"
+	"github.com/conn-castle/agent-layer/internal/templates"
+)
+
+func TestConfigLintCmd_ReportsIssues(t *testing.T) {
+	root := stubRepoRoot(t)
+	original := lintConfig
+	var gotRoot string
+	lintConfig = func(r string) ([]config.LintIssue, error) {
+		gotRoot = r
+		return []config.LintIssue{
+			{Kind: config.LintUnknownKey, Key: "agents.claude.modle", Message: "config.toml: agents.claude.modle is not a recognized config key (did you mean agents.claude.model?)"},
+			{Kind: config.LintInvalidValue, Message: "config.toml: approvals.mode must be one of all, mcp, commands, none, yolo"},
+		}, nil
+	}
+	t.Cleanup(func() { lintConfig = original })
+
+	cmd := newConfigCmd()
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs([]string{"lint"})
+	err := cmd.Execute()
+	if err == nil || !strings.Contains(err.Error(), "config lint found 2 problem(s)") {
+		t.Fatalf("expected lint failure, got %v", err)
+	}
+	if errcode.Of(err) != errcode.Config {
+		t.Fatalf("expected config error code, got %q", errcode.Of(err))
+	}
+	if gotRoot != root {
+		t.Fatalf("root = %q, want %q", gotRoot, root)
+	}
+	for _, want := range []string{
+		"unknown_key     config.toml: agents.claude.modle",
+		"invalid_value   config.toml: approvals.mode",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Fatalf("expected %q in output:\n%s", want, out.String())
+		}
+	}
+}
+
+func TestConfigLintCmd_Clean(t *testing.T) {
+	stubRepoRoot(t)
+	original := lintConfig
+	lintConfig = func(string) ([]config.LintIssue, error) { return nil, nil }
+	t.Cleanup(func() { lintConfig = original })
+
+	cmd := newConfigCmd()
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"lint"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("lint: %v", err)
+	}
+	if !strings.Contains(out.String(), "no problems found") {
+		t.Fatalf("unexpected output: %s", out.String())
+	}
+}
+
+func TestConfigLintCmd_ReadError(t *testing.T) {
+	stubRepoRoot(t)
+	original := lintConfig
+	lintConfig = func(string) ([]config.LintIssue, error) { return nil, errors.New("boom") }
+	t.Cleanup(func() { lintConfig = original })
+
+	cmd := newConfigCmd()
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs([]string{"lint"})
+	if err := cmd.Execute(); err == nil || err.Error() != "boom" || errcode.Of(err) != errcode.Config {
+		t.Fatalf("expected tagged read error, got %v", err)
+	}
+}
+
+func TestConfigEncryptCmd(t *testing.T) {
+	root := stubRepoRoot(t)
+	original := encryptEnvFile
+	var gotEnv, gotRecipients string
+	var gotKeys []string
+	encryptEnvFile = func(envPath string, recipientsPath string, keys []string) ([]string, error) {
+		gotEnv, gotRecipients, gotKeys = envPath, recipientsPath, keys
+		return []string{"AL_TOKEN"}, nil
+	}
+	t.Cleanup(func() { encryptEnvFile = original })
+
+	cmd := newConfigCmd()
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"encrypt", "AL_TOKEN"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("config encrypt: %v", err)
+	}
+	if gotEnv != filepath.Join(root, ".agent-layer", ".env") || gotRecipients != filepath.Join(root, ".agent-layer", "age-recipients.txt") {
+		t.Fatalf("unexpected paths: %q, %q", gotEnv, gotRecipients)
+	}
+	if len(gotKeys) != 1 || gotKeys[0] != "AL_TOKEN" {
+		t.Fatalf("keys = %v", gotKeys)
+	}
+	if !strings.Contains(out.String(), "Encrypted 1 value(s)") || !strings.Contains(out.String(), "AL_TOKEN") {
+		t.Fatalf("unexpected output: %s", out.String())
+	}
+}
+
+func TestConfigDecryptCmd(t *testing.T) {
+	stubRepoRoot(t)
+	original := decryptEnvFile
+	t.Cleanup(func() { decryptEnvFile = original })
+
+	decryptEnvFile = func(string, []string) ([]string, error) { return nil, nil }
+	cmd := newConfigCmd()
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"decrypt"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("config decrypt: %v", err)
+	}
+	if !strings.Contains(out.String(), "No values to decrypt") {
+		t.Fatalf("unexpected output: %s", out.String())
+	}
+
+	decryptEnvFile = func(string, []string) ([]string, error) { return nil, errors.New("no age identity found") }
+	cmd = newConfigCmd()
+	cmd.SilenceUsage = true
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs([]string{"decrypt"})
+	err := cmd.Execute()
+	if err == nil || errcode.Of(err) != errcode.Config {
+		t.Fatalf("expected config error, got %v", err)
+	}
+}
+
+func TestConfigReconcileCmd(t *testing.T) {
+	const drifted = "[warnings]\nnoise_mode = \"quiet\"\n\n[approvals]\nmode = \"none\"\n"
+	cases := []struct {
+		name      string
+		args      []string
+		terminal  bool
+		input     string
+		wantErr   string
+		written   bool
+		wantIn    []string
+		wantNotIn []string
+	}{
+		{name: "dry run", args: []string{"--dry-run"}, wantIn: []string{"[dispatch]", "approvals.mode", "[approvals], [dispatch]"}},
+		{name: "non-interactive", wantErr: "--yes"},
+		{name: "yes", args: []string{"--yes"}, written: true, wantIn: []string{"[dispatch]\n", "noise_mode = \"quiet\"", "# one of: "}},
+		{name: "declined add keys", terminal: true, input: "n\ny\ny\n", written: true, wantIn: []string{"# one of: "}, wantNotIn: []string{"[dispatch]", "[agents.claude]"}},
+		{name: "declined all", terminal: true, input: "n\nn\nn\n"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			root := stubRepoRoot(t)
+			configPath := config.DefaultPaths(root).ConfigPath
+			if err := os.WriteFile(configPath, []byte(drifted), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			originalTerminal := isTerminal
+			isTerminal = func() bool { return tc.terminal }
+			t.Cleanup(func() { isTerminal = originalTerminal })
+
+			cmd := newConfigCmd()
+			var out bytes.Buffer
+			cmd.SetOut(&out)
+			cmd.SetErr(&bytes.Buffer{})
+			cmd.SetIn(strings.NewReader(tc.input))
+			cmd.SetArgs(append([]string{"reconcile"}, tc.args...))
+			err := cmd.Execute()
+			if tc.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tc.wantErr, err)
+				}
+			} else if err != nil {
+				t.Fatalf("reconcile: %v", err)
+			}
+
+			data, err := os.ReadFile(configPath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if written := string(data) != drifted; written != tc.written {
+				t.Fatalf("written = %v, want %v:\n%s", written, tc.written, data)
+			}
+			text := out.String()
+			if tc.written {
+				text = string(data)
+				if !strings.Contains(text, "mode = \"none\"") {
+					t.Fatalf("reconcile changed a value:\n%s", text)
+				}
+			}
+			for _, want := range tc.wantIn {
+				if !strings.Contains(text, want) {
+					t.Fatalf("expected %q in:\n%s", want, text)
+				}
+			}
+			for _, unwanted := range tc.wantNotIn {
+				if strings.Contains(text, unwanted) {
+					t.Fatalf("unexpected %q in:\n%s", unwanted, text)
+				}
+			}
+		})
+	}
+}
+
+func TestConfigReconcileCmd_NothingToDo(t *testing.T) {
+	root := stubRepoRoot(t)
+	template, err := templates.Read("config.toml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	configPath := config.DefaultPaths(root).ConfigPath
+	if err := os.WriteFile(configPath, template, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := newConfigCmd()
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"reconcile"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("reconcile: %v", err)
+	}
+	if !strings.Contains(out.String(), "nothing to reconcile") {
+		t.Fatalf("unexpected output %q", out.String())
+	}
+}
@@ -5,6 +5,7 @@ const (
 	commandUpgrade = "upgrade"
 	unknownVersion = "unknown"
 	noSyncFlag     = "--no-sync"
+	workspaceFlag  = "--workspace"
 
 	issueUnrecognizedConfigKeys          = "unrecognized_config_keys"
 	issueUnresolvedConfigPlaceholders    = "unresolved_config_placeholders"
@@ -0,0 +1,164 @@
+//go:build tools
+// +build tools
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/conn-castle/agent-layer/internal/i18n"
+	"github.com/conn-castle/agent-layer/internal/install"
+	"github.com/conn-castle/agent-layer/internal/messages"
+	"github.com/conn-castle/agent-layer/internal/outputdiff"
+	"github.com/conn-castle/agent-layer/internal/templates"
+	"github.com/conn-castle/agent-layer/internal/version"
+)
+
+var (
+	scaffoldUpgradeMigration = install.ScaffoldUpgradeMigration
+	renderTemplateMatrix     = outputdiff.RenderMatrix
+)
+
+// addDevCommands registers maintainer commands. They exist only in tools
+// builds (go run -tags tools ./cmd/al dev ...) and never ship in releases.
+func addDevCommands(root *cobra.Command) {
+	root.AddCommand(newDevCmd())
+}
+
+func newDevCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   messages.DevUse,
+		Short: messages.DevShort,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Help()
+		},
+	}
+	cmd.AddCommand(newDevGenMigrationCmd(), newDevRenderCmd())
+	return cmd
+}
+
+func newDevGenMigrationCmd() *cobra.Command {
+	var from, to, minPrior, fromConfig, output string
+	var force bool
+	cmd := &cobra.Command{
+		Use:   messages.DevGenMigrationUse,
+		Short: messages.DevGenMigrationShort,
+		Long:  messages.DevGenMigrationLong,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if strings.TrimSpace(from) == "" || strings.TrimSpace(to) == "" {
+				return errors.New(i18n.T(messages.DevGenMigrationVersionsRequired))
+			}
+			opts := install.MigrationScaffoldOptions{FromVersion: from, ToVersion: to, MinPriorVersion: minPrior}
+			if fromConfig != "" {
+				data, err := os.ReadFile(fromConfig)
+				if err != nil {
+					return i18n.Errorf(messages.DevGenMigrationReadConfigFmt, fromConfig, err)
+				}
+				current, err := templates.Read("config.toml")
+				if err != nil {
+					return err
+				}
+				opts.FromConfig, opts.ToConfig = data, current
+			}
+			data, err := scaffoldUpgradeMigration(opts)
+			if err != nil {
+				return err
+			}
+			if output == "-" {
+				_, err := cmd.OutOrStdout().Write(data)
+				return err
+			}
+			if output == "" {
+				target, err := version.Normalize(to)
+				if err != nil {
+					return err
+				}
+				output = filepath.Join("internal", "templates", "migrations", target+".json")
+			}
+			if !force {
+				if _, err := os.Stat(output); err == nil {
+					return i18n.Errorf(messages.DevGenMigrationExistsFmt, output)
+				} else if !errors.Is(err, fs.ErrNotExist) {
+					return err
+				}
+			}
+			if err := os.WriteFile(output, data, 0o644); err != nil {
+				return err
+			}
+			_, err = fmt.Fprintf(cmd.OutOrStdout(), i18n.T(messages.DevGenMigrationWroteFmt), output)
+			return err
+		},
+	}
+	cmd.Flags().StringVar(&from, "from", "", messages.DevGenMigrationFromFlag)
+	cmd.Flags().StringVar(&to, "to", "", messages.DevGenMigrationToFlag)
+	cmd.Flags().StringVar(&minPrior, "min-prior-version", "", messages.DevGenMigrationMinPriorFlag)
+	cmd.Flags().StringVar(&fromConfig, "from-config", "", messages.DevGenMigrationFromConfigFlag)
+	cmd.Flags().StringVar(&output, "output", "", messages.DevGenMigrationOutputFlag)
+	cmd.Flags().BoolVar(&force, "force", false, messages.DevGenMigrationFlagForce)
+	return cmd
+}
+
+func newDevRenderCmd() *cobra.Command {
+	var output string
+	var only []string
+	cmd := &cobra.Command{
+		Use:   messages.DevRenderUse,
+		Short: messages.DevRenderShort,
+		Long:  messages.DevRenderLong,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if strings.TrimSpace(output) == "" {
+				return errors.New(i18n.T(messages.DevRenderOutputRequired))
+			}
+			cases, err := selectRenderCases(outputdiff.DefaultMatrix(), only)
+			if err != nil {
+				return err
+			}
+			if err := renderTemplateMatrix(output, cases); err != nil {
+				return err
+			}
+			_, err = fmt.Fprintf(cmd.OutOrStdout(), i18n.T(messages.DevRenderWroteFmt), len(cases), output)
+			return err
+		},
+	}
+	cmd.Flags().StringVar(&output, "output", "", messages.DevRenderOutputFlag)
+	cmd.Flags().StringArrayVar(&only, "case", nil, messages.DevRenderCaseFlag)
+	return cmd
+}
+
+// selectRenderCases returns the cases named in only, in matrix order, or the
+// whole matrix when only is empty.
+func selectRenderCases(matrix []outputdiff.MatrixCase, only []string) ([]outputdiff.MatrixCase, error) {
+	if len(only) == 0 {
+		return matrix, nil
+	}
+	names := make([]string, 0, len(matrix))
+	byName := make(map[string]bool, len(matrix))
+	for _, c := range matrix {
+		names = append(names, c.Name)
+		byName[c.Name] = true
+	}
+	wanted := make(map[string]bool, len(only))
+	for _, name := range only {
+		if !byName[name] {
+			return nil, i18n.Errorf(messages.DevRenderUnknownCaseFmt, name, strings.Join(names, ", "))
+		}
+		wanted[name] = true
+	}
+	var selected []outputdiff.MatrixCase
+	for _, c := range matrix {
+		if wanted[c.Name] {
+			selected = append(selected, c)
+		}
+	}
+	return selected, nil
+}
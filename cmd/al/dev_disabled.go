@@ -0,0 +1,10 @@
+//go:build !tools
+// +build !tools
+
+package main
+
+import "github.com/spf13/cobra"
+
+// addDevCommands is a no-op in release builds; maintainer commands are
+// compiled in only with the tools build tag.
+func addDevCommands(*cobra.Command) {}
@@ -0,0 +1,136 @@
+//go:build tools
+// +build tools
+
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/conn-castle/agent-layer/internal/install"
+	"github.com/conn-castle/agent-layer/internal/outputdiff"
+)
+
+func stubScaffoldUpgradeMigration(t *testing.T) *install.MigrationScaffoldOptions {
+	t.Helper()
+	original := scaffoldUpgradeMigration
+	var got install.MigrationScaffoldOptions
+	scaffoldUpgradeMigration = func(opts install.MigrationScaffoldOptions) ([]byte, error) {
+		got = opts
+		return []byte("{}\n"), nil
+	}
+	t.Cleanup(func() { scaffoldUpgradeMigration = original })
+	return &got
+}
+
+func runDevCmd(args ...string) (string, error) {
+	cmd := newDevCmd()
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs(args)
+	err := cmd.Execute()
+	return out.String(), err
+}
+
+func TestDevGenMigrationCmd_WritesOutput(t *testing.T) {
+	got := stubScaffoldUpgradeMigration(t)
+	output := filepath.Join(t.TempDir(), "0.15.0.json")
+
+	out, err := runDevCmd("gen-migration", "--from", "v0.14.0", "--to", "v0.15.0", "--min-prior-version", "0.13.0", "--output", output)
+	if err != nil {
+		t.Fatalf("gen-migration: %v", err)
+	}
+	if got.FromVersion != "v0.14.0" || got.ToVersion != "v0.15.0" || got.MinPriorVersion != "0.13.0" || got.FromConfig != nil {
+		t.Fatalf("unexpected options: %#v", *got)
+	}
+	data, err := os.ReadFile(output)
+	if err != nil || string(data) != "{}\n" {
+		t.Fatalf("expected scaffold written to %s, got %q (%v)", output, data, err)
+	}
+	if !strings.Contains(out, "replace every TODO rationale") {
+		t.Fatalf("expected TODO reminder, got %q", out)
+	}
+
+	if _, err := runDevCmd("gen-migration", "--from", "0.14.0", "--to", "0.15.0", "--output", output); err == nil || !strings.Contains(err.Error(), "--force") {
+		t.Fatalf("expected existing manifest to be refused, got %v", err)
+	}
+	if _, err := runDevCmd("gen-migration", "--from", "0.14.0", "--to", "0.15.0", "--output", output, "--force"); err != nil {
+		t.Fatalf("expected --force to overwrite: %v", err)
+	}
+}
+
+func TestDevGenMigrationCmd_Stdout(t *testing.T) {
+	stubScaffoldUpgradeMigration(t)
+
+	out, err := runDevCmd("gen-migration", "--from", "0.14.0", "--to", "0.15.0", "--output", "-")
+	if err != nil {
+		t.Fatalf("gen-migration: %v", err)
+	}
+	if out != "{}\n" {
+		t.Fatalf("expected scaffold on stdout, got %q", out)
+	}
+}
+
+func TestDevGenMigrationCmd_FromConfig(t *testing.T) {
+	got := stubScaffoldUpgradeMigration(t)
+	fromConfig := filepath.Join(t.TempDir(), "config.toml")
+	if err := os.WriteFile(fromConfig, []byte("[approvals]\nmode = \"all\"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := runDevCmd("gen-migration", "--from", "0.14.0", "--to", "0.15.0", "--from-config", fromConfig, "--output", "-"); err != nil {
+		t.Fatalf("gen-migration: %v", err)
+	}
+	if string(got.FromConfig) != "[approvals]\nmode = \"all\"\n" || len(got.ToConfig) == 0 {
+		t.Fatalf("expected both config templates, got from=%q to=%d bytes", got.FromConfig, len(got.ToConfig))
+	}
+
+	if _, err := runDevCmd("gen-migration", "--from", "0.14.0", "--to", "0.15.0", "--from-config", fromConfig+".missing"); err == nil {
+		t.Fatalf("expected error for a missing config template")
+	}
+}
+
+func TestDevGenMigrationCmd_RequiresVersions(t *testing.T) {
+	stubScaffoldUpgradeMigration(t)
+
+	if _, err := runDevCmd("gen-migration", "--from", "0.14.0"); err == nil || !strings.Contains(err.Error(), "--to are required") {
+		t.Fatalf("expected missing version error, got %v", err)
+	}
+}
+
+func TestDevRenderCmd_SelectsCases(t *testing.T) {
+	original := renderTemplateMatrix
+	var gotDest string
+	var gotCases []string
+	renderTemplateMatrix = func(dest string, cases []outputdiff.MatrixCase) error {
+		gotDest = dest
+		for _, c := range cases {
+			gotCases = append(gotCases, c.Name)
+		}
+		return nil
+	}
+	t.Cleanup(func() { renderTemplateMatrix = original })
+
+	dest := t.TempDir()
+	out, err := runDevCmd("render", "--output", dest, "--case", "only-codex", "--case", "approvals-yolo")
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	if gotDest != dest || strings.Join(gotCases, ",") != "approvals-yolo,only-codex" {
+		t.Fatalf("unexpected render call: dest=%q cases=%v", gotDest, gotCases)
+	}
+	if !strings.Contains(out, "Rendered 2 case(s)") {
+		t.Fatalf("unexpected output: %q", out)
+	}
+
+	if _, err := runDevCmd("render", "--output", dest, "--case", "only-emacs"); err == nil || !strings.Contains(err.Error(), "unknown render case") {
+		t.Fatalf("expected unknown case error, got %v", err)
+	}
+	if _, err := runDevCmd("render"); err == nil || !strings.Contains(err.Error(), "--output is required") {
+		t.Fatalf("expected missing output error, got %v", err)
+	}
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/conn-castle/agent-layer/internal/config"
+	"github.com/conn-castle/agent-layer/internal/devcontainer"
+	"github.com/conn-castle/agent-layer/internal/errcode"
+	"github.com/conn-castle/agent-layer/internal/i18n"
+	"github.com/conn-castle/agent-layer/internal/messages"
+	"github.com/conn-castle/agent-layer/internal/version"
+)
+
+var loadDevcontainerProject = config.LoadProjectConfig
+
+func newDevcontainerCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   messages.DevcontainerUse,
+		Short: messages.DevcontainerShort,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Help()
+		},
+	}
+	cmd.AddCommand(newDevcontainerGenerateCmd())
+	return cmd
+}
+
+func newDevcontainerGenerateCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   messages.DevcontainerGenerateUse,
+		Short: messages.DevcontainerGenerateShort,
+		Long:  messages.DevcontainerGenerateLong,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			root, err := resolveRepoRoot()
+			if err != nil {
+				return err
+			}
+			project, err := loadDevcontainerProject(root)
+			if err != nil {
+				return errcode.Wrap(errcode.Config, err)
+			}
+			alVersion, err := devcontainer.PinnedVersion(root)
+			if err != nil {
+				return errcode.Wrap(errcode.Config, err)
+			}
+			if alVersion == "" {
+				if version.IsDev(Version) {
+					return i18n.Errorf(messages.DevcontainerNoVersion)
+				}
+				alVersion = Version
+			}
+			result, err := devcontainer.Generate(root, alVersion, devcontainer.Env(project.Config))
+			if err != nil {
+				return err
+			}
+			out := cmd.OutOrStdout()
+			if _, err := fmt.Fprintf(out, i18n.T(messages.DevcontainerResultFmt), strings.Join(result.Files, ", "), result.Version); err != nil {
+				return err
+			}
+			if _, err := fmt.Fprintln(out, i18n.T(messages.DevcontainerFragmentIntro)); err != nil {
+				return err
+			}
+			_, err = fmt.Fprint(out, result.Fragment)
+			return err
+		},
+	}
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/conn-castle/agent-layer/internal/config"
+)
+
+func runDevcontainerGenerate(t *testing.T) (string, error) {
+	t.Helper()
+	cmd := newDevcontainerCmd()
+	cmd.SilenceUsage = true
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs([]string{"generate"})
+	err := cmd.Execute()
+	return out.String(), err
+}
+
+func stubDevcontainerProject(t *testing.T, cfg config.Config) {
+	t.Helper()
+	original := loadDevcontainerProject
+	loadDevcontainerProject = func(string) (*config.ProjectConfig, error) {
+		return &config.ProjectConfig{Config: cfg}, nil
+	}
+	t.Cleanup(func() { loadDevcontainerProject = original })
+}
+
+func TestDevcontainerGenerateCmd(t *testing.T) {
+	root := stubRepoRoot(t)
+	enabled := true
+	var cfg config.Config
+	cfg.Agents.Codex.LocalConfigDir = &enabled
+	stubDevcontainerProject(t, cfg)
+	if err := os.WriteFile(filepath.Join(root, ".agent-layer", "al.version"), []byte("0.9.1\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := runDevcontainerGenerate(t)
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+	for _, want := range []string{"(al 0.9.1)", `"./agent-layer": {}`, `"CODEX_HOME": "${containerWorkspaceFolder}/.codex"`} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("expected %q in output:\n%s", want, out)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(root, ".devcontainer", "agent-layer", "install.sh")); err != nil {
+		t.Fatalf("expected install.sh: %v", err)
+	}
+}
+
+func TestDevcontainerGenerateCmd_VersionFallback(t *testing.T) {
+	stubRepoRoot(t)
+	stubDevcontainerProject(t, config.Config{})
+	original := Version
+	t.Cleanup(func() { Version = original })
+
+	Version = "dev"
+	if _, err := runDevcontainerGenerate(t); err == nil || !strings.Contains(err.Error(), "no al version to install") {
+		t.Fatalf("expected missing version error, got %v", err)
+	}
+
+	Version = "1.4.0"
+	out, err := runDevcontainerGenerate(t)
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+	if !strings.Contains(out, "(al 1.4.0)") || strings.Contains(out, "remoteEnv") {
+		t.Fatalf("unexpected output:\n%s", out)
+	}
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/aymanbagabas/go-udiff"
+	"github.com/spf13/cobra"
+
+	"github.com/conn-castle/agent-layer/internal/i18n"
+	"github.com/conn-castle/agent-layer/internal/messages"
+	"github.com/conn-castle/agent-layer/internal/outputdiff"
+)
+
+var compareOutputs = outputdiff.Compare
+
+func newDiffCmd() *cobra.Command {
+	var against string
+
+	cmd := &cobra.Command{
+		Use:   messages.DiffUse,
+		Short: messages.DiffShort,
+		Long:  messages.DiffLong,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			root, err := resolveRepoRoot()
+			if err != nil {
+				return err
+			}
+			result, err := compareOutputs(outputdiff.Options{
+				Root:     root,
+				Against:  against,
+				Progress: commandOutput(cmd, noiseModeFromConfig(root)).Info(),
+			})
+			if err != nil {
+				return err
+			}
+			out := cmd.OutOrStdout()
+			if len(result.Changes) == 0 {
+				_, err := fmt.Fprintf(out, i18n.T(messages.DiffNoChangesFmt), result.Against)
+				return err
+			}
+			colorize := shouldColorizeDiffOutput()
+			for _, change := range result.Changes {
+				fromName := i18n.Sprintf(messages.DiffLabelAgainstFmt, change.Path, result.Against)
+				toName := i18n.Sprintf(messages.DiffLabelCurrentFmt, change.Path)
+				if !change.InAgainst {
+					fromName = "/dev/null"
+				}
+				if !change.InCurrent {
+					toName = "/dev/null"
+				}
+				diff := udiff.Unified(fromName, toName, change.Against, change.Current)
+				if diff == "" {
+					continue
+				}
+				if err := writeUnifiedDiff(out, diff, colorize, ""); err != nil {
+					return err
+				}
+			}
+			_, err = fmt.Fprintf(out, i18n.T(messages.DiffSummaryFmt), len(result.Changes), result.Against)
+			return err
+		},
+	}
+	cmd.Flags().StringVar(&against, "against", "", messages.DiffAgainstFlag)
+	_ = cmd.MarkFlagRequired("against")
+	return cmd
+}
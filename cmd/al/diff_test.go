@@ -0,0 +1,83 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/conn-castle/agent-layer/internal/outputdiff"
+)
+
+func TestDiffCmd(t *testing.T) {
+	root := stubRepoRoot(t)
+	original := compareOutputs
+	var gotOpts outputdiff.Options
+	compareOutputs = func(opts outputdiff.Options) (*outputdiff.Result, error) {
+		gotOpts = opts
+		return &outputdiff.Result{
+			Kind:    outputdiff.KindVersion,
+			Against: "1.2.0",
+			Changes: []outputdiff.Change{
+				{Path: "CLAUDE.md", Against: "old\n", Current: "new\n", InAgainst: true, InCurrent: true},
+				{Path: ".mcp.json", Current: "{}\n", InCurrent: true},
+			},
+		}, nil
+	}
+	t.Cleanup(func() { compareOutputs = original })
+
+	cmd := newDiffCmd()
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs([]string{"--against", "v1.2.0"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("diff: %v", err)
+	}
+	if gotOpts.Root != root || gotOpts.Against != "v1.2.0" {
+		t.Fatalf("unexpected options %#v", gotOpts)
+	}
+	for _, want := range []string{
+		"--- CLAUDE.md (1.2.0)",
+		"+++ CLAUDE.md (working tree)",
+		"-old",
+		"+new",
+		"--- /dev/null",
+		"+++ .mcp.json (working tree)",
+		"2 generated file(s) differ against 1.2.0.",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Fatalf("expected %q in output:\n%s", want, out.String())
+		}
+	}
+}
+
+func TestDiffCmd_NoChanges(t *testing.T) {
+	stubRepoRoot(t)
+	original := compareOutputs
+	compareOutputs = func(opts outputdiff.Options) (*outputdiff.Result, error) {
+		return &outputdiff.Result{Kind: outputdiff.KindGitRef, Against: opts.Against}, nil
+	}
+	t.Cleanup(func() { compareOutputs = original })
+
+	cmd := newDiffCmd()
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"--against", "HEAD~1"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("diff: %v", err)
+	}
+	if out.String() != "No differences in generated outputs against HEAD~1.\n" {
+		t.Fatalf("unexpected output %q", out.String())
+	}
+}
+
+func TestDiffCmd_AgainstRequired(t *testing.T) {
+	stubRepoRoot(t)
+	cmd := newDiffCmd()
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs(nil)
+	if err := cmd.Execute(); err == nil {
+		t.Fatalf("expected error without --against")
+	}
+}
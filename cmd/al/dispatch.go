@@ -67,6 +67,8 @@ func newDispatchStartCmd() *cobra.Command {
 	cmd.Flags().StringVar(&model, "model", "", messages.DispatchModelFlag)
 	cmd.Flags().StringVar(&effort, "reasoning-effort", "", messages.DispatchReasoningEffortFlag)
 	cmd.Flags().StringVar(&skill, "skill", "", messages.DispatchSkillFlag)
+	_ = cmd.RegisterFlagCompletionFunc("agent", completeDispatchAgents)
+	_ = cmd.RegisterFlagCompletionFunc("skill", completeProjectSkills)
 	addDispatchPromptFlags(cmd, &prompt, &promptFile)
 	return cmd
 }
@@ -8,12 +8,15 @@ import (
 	"sync"
 	"time"
 
-	"github.com/fatih/color"
 	"github.com/spf13/cobra"
 
 	"github.com/conn-castle/agent-layer/internal/config"
 	"github.com/conn-castle/agent-layer/internal/doctor"
+	"github.com/conn-castle/agent-layer/internal/i18n"
 	"github.com/conn-castle/agent-layer/internal/messages"
+	"github.com/conn-castle/agent-layer/internal/offline"
+	"github.com/conn-castle/agent-layer/internal/output"
+	"github.com/conn-castle/agent-layer/internal/style"
 	"github.com/conn-castle/agent-layer/internal/update"
 	"github.com/conn-castle/agent-layer/internal/versiondispatch"
 	"github.com/conn-castle/agent-layer/internal/warnings"
@@ -33,28 +36,35 @@ func newDoctorCmd() *cobra.Command {
 		Args:  cobra.NoArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			out := cmd.OutOrStdout()
-			quiet, _ := cmd.Flags().GetBool("quiet")
+			// Doctor reports every finding unless --quiet is passed; a quiet
+			// noise_mode does not hide its results.
+			quiet := commandOutput(cmd, "").Level() == output.Quiet
 			root, err := resolveRepoRoot()
 			if err != nil {
 				return err
 			}
 
-			_, _ = fmt.Fprintf(out, messages.DoctorHealthCheckFmt, root)
+			_, _ = fmt.Fprintf(out, i18n.T(messages.DoctorHealthCheckFmt), root)
 
 			var allResults []doctor.Result
 
 			// 1. Check Structure
 			allResults = append(allResults, doctor.CheckStructure(root)...)
+			allResults = append(allResults, doctor.CheckInstallJournal(root)...)
 
 			// 2. Check Config
 			configResults, cfg := doctor.CheckConfig(root)
 			allResults = append(allResults, configResults...)
 
 			updateResult := doctor.Result{CheckName: messages.DoctorCheckNameUpdate}
-			if strings.TrimSpace(os.Getenv(versiondispatch.EnvNoNetwork)) != "" {
+			if offline.Enabled(os.Getenv) {
 				updateResult.Status = doctor.StatusWarn
-				updateResult.Message = fmt.Sprintf(messages.DoctorUpdateSkippedFmt, versiondispatch.EnvNoNetwork)
-				updateResult.Recommendation = fmt.Sprintf(messages.DoctorUpdateSkippedRecommendFmt, versiondispatch.EnvNoNetwork)
+				updateResult.Message = i18n.Sprintf(messages.DoctorUpdateSkippedFmt, offline.EnvVar)
+				updateResult.Recommendation = i18n.Sprintf(messages.DoctorUpdateSkippedRecommendFmt, offline.EnvVar)
+			} else if strings.TrimSpace(os.Getenv(versiondispatch.EnvNoNetwork)) != "" {
+				updateResult.Status = doctor.StatusWarn
+				updateResult.Message = i18n.Sprintf(messages.DoctorUpdateSkippedFmt, versiondispatch.EnvNoNetwork)
+				updateResult.Recommendation = i18n.Sprintf(messages.DoctorUpdateSkippedRecommendFmt, versiondispatch.EnvNoNetwork)
 			} else {
 				result, err := checkForUpdate(cmd.Context(), Version)
 				switch {
@@ -63,19 +73,19 @@ func newDoctorCmd() *cobra.Command {
 					updateResult.Message = messages.DoctorUpdateRateLimited
 				case err != nil:
 					updateResult.Status = doctor.StatusWarn
-					updateResult.Message = fmt.Sprintf(messages.DoctorUpdateFailedFmt, err)
+					updateResult.Message = i18n.Sprintf(messages.DoctorUpdateFailedFmt, err)
 					updateResult.Recommendation = messages.DoctorUpdateFailedRecommend
 				case result.CurrentIsDev:
 					updateResult.Status = doctor.StatusWarn
-					updateResult.Message = fmt.Sprintf(messages.DoctorUpdateDevBuildFmt, result.Latest)
+					updateResult.Message = i18n.Sprintf(messages.DoctorUpdateDevBuildFmt, result.Latest)
 					updateResult.Recommendation = messages.DoctorUpdateDevBuildRecommend
 				case result.Outdated:
 					updateResult.Status = doctor.StatusWarn
-					updateResult.Message = fmt.Sprintf(messages.DoctorUpdateAvailableFmt, result.Latest, result.Current)
+					updateResult.Message = i18n.Sprintf(messages.DoctorUpdateAvailableFmt, result.Latest, result.Current)
 					updateResult.Recommendation = messages.DoctorUpdateAvailableRecommend
 				default:
 					updateResult.Status = doctor.StatusOK
-					updateResult.Message = fmt.Sprintf(messages.DoctorUpToDateFmt, result.Current)
+					updateResult.Message = i18n.Sprintf(messages.DoctorUpToDateFmt, result.Current)
 				}
 			}
 			allResults = append(allResults, updateResult)
@@ -132,13 +142,13 @@ func newDoctorCmd() *cobra.Command {
 			)
 			if cfg != nil {
 				if !quiet {
-					_, _ = fmt.Fprintln(out, messages.DoctorWarningSystemHeader)
+					_, _ = fmt.Fprintln(out, style.Heading(messages.DoctorWarningSystemHeader))
 				}
 
 				// Instructions check
 				instWarnings, err := checkInstructions(root, cfg.Config.Warnings.InstructionTokenThreshold)
 				if err != nil {
-					_, _ = fmt.Fprintln(out, color.RedString(messages.DoctorInstructionsCheckFailedFmt, err))
+					_, _ = fmt.Fprintln(out, style.Failure(i18n.Sprintf(messages.DoctorInstructionsCheckFailedFmt, err)))
 					hasFail = true
 				} else {
 					warningList = append(warningList, instWarnings...)
@@ -157,7 +167,7 @@ func newDoctorCmd() *cobra.Command {
 				mcpWarnings, summary, err := checkMCPServers(cmd.Context(), cfg, nil, reportProgress)
 				stopProgress()
 				if err != nil {
-					_, _ = fmt.Fprintln(out, color.RedString(messages.DoctorMCPCheckFailedFmt, err))
+					_, _ = fmt.Fprintln(out, style.Failure(i18n.Sprintf(messages.DoctorMCPCheckFailedFmt, err)))
 					hasFail = true
 				} else {
 					mcpSummary = summary
@@ -197,10 +207,10 @@ func newDoctorCmd() *cobra.Command {
 			}
 
 			if hasFail {
-				_, _ = fmt.Fprintln(out, color.RedString(messages.DoctorFailureSummary))
-				return fmt.Errorf(messages.DoctorFailureError)
+				_, _ = fmt.Fprintln(out, style.Failure(messages.DoctorFailureSummary))
+				return i18n.Errorf(messages.DoctorFailureError)
 			} else {
-				_, _ = fmt.Fprintln(out, color.GreenString(messages.DoctorSuccessSummary))
+				_, _ = fmt.Fprintln(out, style.Success(messages.DoctorSuccessSummary))
 			}
 
 			return nil
@@ -212,14 +222,14 @@ func printResult(out io.Writer, r doctor.Result) {
 	var status string
 	switch r.Status {
 	case doctor.StatusOK:
-		status = color.GreenString(messages.DoctorStatusOKLabel)
+		status = style.Success(messages.DoctorStatusOKLabel)
 	case doctor.StatusWarn:
-		status = color.YellowString(messages.DoctorStatusWarnLabel)
+		status = style.Warning(messages.DoctorStatusWarnLabel)
 	case doctor.StatusFail:
-		status = color.RedString(messages.DoctorStatusFailLabel)
+		status = style.Failure(messages.DoctorStatusFailLabel)
 	}
 
-	_, _ = fmt.Fprintf(out, messages.DoctorResultLineFmt, status, r.CheckName, r.Message)
+	_, _ = fmt.Fprintf(out, i18n.T(messages.DoctorResultLineFmt), status, r.CheckName, r.Message)
 	if r.Recommendation != "" {
 		printRecommendation(out, r.Recommendation)
 	}
@@ -249,46 +259,46 @@ func printRecommendation(out io.Writer, recommendation string) {
 // (instructions + skill catalog + MCP tool schemas); any component that is unavailable is
 // named in an "(excludes ...)" note rather than being silently counted as zero.
 func renderSizeSummary(out io.Writer, w config.WarningsConfig, instTokens int, instSubject string, instErr error, skillTokens int, skillsAvailable bool, mcp warnings.MCPSummary) {
-	_, _ = fmt.Fprintln(out, messages.DoctorSizeSummaryHeader)
+	_, _ = fmt.Fprintln(out, style.Heading(messages.DoctorSizeSummaryHeader))
 
 	switch {
 	case instErr != nil:
-		_, _ = fmt.Fprintf(out, messages.DoctorSizeInstructionsUnavailableFmt, instErr)
+		_, _ = fmt.Fprintf(out, i18n.T(messages.DoctorSizeInstructionsUnavailableFmt), instErr)
 	case w.InstructionTokenThreshold != nil:
-		_, _ = fmt.Fprintf(out, messages.DoctorSizeInstructionsFmt, instSubject, instTokens, *w.InstructionTokenThreshold)
+		_, _ = fmt.Fprintf(out, i18n.T(messages.DoctorSizeInstructionsFmt), instSubject, instTokens, *w.InstructionTokenThreshold)
 	default:
-		_, _ = fmt.Fprintf(out, messages.DoctorSizeInstructionsNoLimitFmt, instSubject, instTokens)
+		_, _ = fmt.Fprintf(out, i18n.T(messages.DoctorSizeInstructionsNoLimitFmt), instSubject, instTokens)
 	}
 
 	if skillsAvailable {
-		_, _ = fmt.Fprintf(out, messages.DoctorSizeSkillsFmt, skillTokens, doctor.MaxSkillCatalogMetadataTokens)
+		_, _ = fmt.Fprintf(out, i18n.T(messages.DoctorSizeSkillsFmt), skillTokens, doctor.MaxSkillCatalogMetadataTokens)
 	} else {
-		_, _ = fmt.Fprint(out, messages.DoctorSizeSkillsUnavailable)
+		_, _ = fmt.Fprint(out, i18n.T(messages.DoctorSizeSkillsUnavailable))
 	}
 
 	if !mcp.Available {
-		_, _ = fmt.Fprint(out, messages.DoctorSizeMCPUnavailable)
+		_, _ = fmt.Fprint(out, i18n.T(messages.DoctorSizeMCPUnavailable))
 	} else {
 		if w.MCPServerThreshold != nil {
-			_, _ = fmt.Fprintf(out, messages.DoctorSizeMCPServersFmt, mcp.EnabledServers, *w.MCPServerThreshold)
+			_, _ = fmt.Fprintf(out, i18n.T(messages.DoctorSizeMCPServersFmt), mcp.EnabledServers, *w.MCPServerThreshold)
 		} else {
-			_, _ = fmt.Fprintf(out, messages.DoctorSizeMCPServersNoLimitFmt, mcp.EnabledServers)
+			_, _ = fmt.Fprintf(out, i18n.T(messages.DoctorSizeMCPServersNoLimitFmt), mcp.EnabledServers)
 		}
 
 		if w.MCPToolsTotalThreshold != nil {
-			_, _ = fmt.Fprintf(out, messages.DoctorSizeMCPToolsFmt, mcp.TotalTools, *w.MCPToolsTotalThreshold)
+			_, _ = fmt.Fprintf(out, i18n.T(messages.DoctorSizeMCPToolsFmt), mcp.TotalTools, *w.MCPToolsTotalThreshold)
 		} else {
-			_, _ = fmt.Fprintf(out, messages.DoctorSizeMCPToolsNoLimitFmt, mcp.TotalTools)
+			_, _ = fmt.Fprintf(out, i18n.T(messages.DoctorSizeMCPToolsNoLimitFmt), mcp.TotalTools)
 		}
 
 		if w.MCPSchemaTokensTotalThreshold != nil {
-			_, _ = fmt.Fprintf(out, messages.DoctorSizeMCPSchemaFmt, mcp.TotalSchemaTokens, *w.MCPSchemaTokensTotalThreshold)
+			_, _ = fmt.Fprintf(out, i18n.T(messages.DoctorSizeMCPSchemaFmt), mcp.TotalSchemaTokens, *w.MCPSchemaTokensTotalThreshold)
 		} else {
-			_, _ = fmt.Fprintf(out, messages.DoctorSizeMCPSchemaNoLimitFmt, mcp.TotalSchemaTokens)
+			_, _ = fmt.Fprintf(out, i18n.T(messages.DoctorSizeMCPSchemaNoLimitFmt), mcp.TotalSchemaTokens)
 		}
 
 		if mcp.ReachableServers < mcp.EnabledServers {
-			_, _ = fmt.Fprintf(out, messages.DoctorSizeMCPPartialFmt, mcp.EnabledServers-mcp.ReachableServers, mcp.EnabledServers)
+			_, _ = fmt.Fprintf(out, i18n.T(messages.DoctorSizeMCPPartialFmt), mcp.EnabledServers-mcp.ReachableServers, mcp.EnabledServers)
 		}
 	}
 
@@ -312,9 +322,9 @@ func renderSizeSummary(out io.Writer, w config.WarningsConfig, instTokens int, i
 		excludes = append(excludes, "MCP tool schemas")
 	}
 	if len(excludes) == 0 {
-		_, _ = fmt.Fprintf(out, messages.DoctorSizeTotalFmt, total)
+		_, _ = fmt.Fprintf(out, i18n.T(messages.DoctorSizeTotalFmt), total)
 	} else {
-		_, _ = fmt.Fprintf(out, messages.DoctorSizeTotalExcludesFmt, total, strings.Join(excludes, ", "))
+		_, _ = fmt.Fprintf(out, i18n.T(messages.DoctorSizeTotalExcludesFmt), total, strings.Join(excludes, ", "))
 	}
 }
 
@@ -332,9 +342,9 @@ func enabledMCPServerIDs(servers []config.MCPServer) []string {
 // startMCPDiscoveryReporter prints progress events from MCP discovery and returns a reporter + stop function.
 // serverIDs is the ordered list of enabled MCP server IDs; returns a nil reporter when none are enabled.
 func startMCPDiscoveryReporter(serverIDs []string, out io.Writer) (warnings.MCPDiscoveryStatusFunc, func()) {
-	_, _ = fmt.Fprintf(out, messages.DoctorMCPCheckStartFmt, len(serverIDs))
+	_, _ = fmt.Fprintf(out, i18n.T(messages.DoctorMCPCheckStartFmt), len(serverIDs))
 	if len(serverIDs) == 0 {
-		_, _ = fmt.Fprintln(out, messages.DoctorMCPCheckDone)
+		_, _ = fmt.Fprintln(out, i18n.T(messages.DoctorMCPCheckDone))
 		return nil, func() {}
 	}
 
@@ -346,7 +356,7 @@ func startMCPDiscoveryReporter(serverIDs []string, out io.Writer) (warnings.MCPD
 	stop := func() {
 		once.Do(func() {
 			reporter.stop()
-			_, _ = fmt.Fprintln(out, messages.DoctorMCPCheckDone)
+			_, _ = fmt.Fprintln(out, i18n.T(messages.DoctorMCPCheckDone))
 		})
 	}
 
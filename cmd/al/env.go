@@ -0,0 +1,199 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/spf13/cobra"
+
+	"github.com/conn-castle/agent-layer/internal/config"
+	"github.com/conn-castle/agent-layer/internal/errcode"
+	"github.com/conn-castle/agent-layer/internal/i18n"
+	"github.com/conn-castle/agent-layer/internal/install"
+	"github.com/conn-castle/agent-layer/internal/layerdir"
+	"github.com/conn-castle/agent-layer/internal/messages"
+	"github.com/conn-castle/agent-layer/internal/versiondispatch"
+)
+
+const commandEnv = "env"
+
+var (
+	executablePath  = os.Executable
+	resolveDispatch = func(cwd string) (versiondispatch.Resolution, error) {
+		return versiondispatch.Resolve(versiondispatch.RealSystem{}, Version, cwd)
+	}
+	readBaselineVersion = func(root string) (string, error) {
+		return install.ReadBaselineVersion(root, install.RealSystem{})
+	}
+)
+
+// envReport is the `al env --json` payload. Field names are part of the CLI
+// contract.
+type envReport struct {
+	Root                    string      `json:"root"`
+	Binary                  envBinary   `json:"binary"`
+	Pin                     envPin      `json:"pin"`
+	Dispatch                envDispatch `json:"dispatch"`
+	TemplateBaselineVersion string      `json:"template_baseline_version"`
+	Paths                   envPaths    `json:"paths"`
+}
+
+type envBinary struct {
+	Version string `json:"version"`
+	Path    string `json:"path"`
+}
+
+type envPin struct {
+	Path    string `json:"path"`
+	Version string `json:"version"`
+	Warning string `json:"warning,omitempty"`
+}
+
+type envDispatch struct {
+	// Decision is current, dispatch, bypass, or blocked.
+	Decision         string `json:"decision"`
+	Source           string `json:"source"`
+	RequestedVersion string `json:"requested_version"`
+	// Binary is the binary that would run the command; "" when blocked.
+	Binary string `json:"binary"`
+	// Cached reports whether Binary exists; a dispatch target may still need
+	// downloading.
+	Cached bool   `json:"cached"`
+	Reason string `json:"reason,omitempty"`
+}
+
+type envPaths struct {
+	StateDir   string   `json:"state_dir"`
+	StateFiles []string `json:"state_files"`
+	CacheDir   string   `json:"cache_dir"`
+}
+
+func newEnvCmd() *cobra.Command {
+	var asJSON bool
+
+	cmd := &cobra.Command{
+		Use:   messages.EnvUse,
+		Short: messages.EnvShort,
+		Long:  messages.EnvLong,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cwd, err := getwd()
+			if err != nil {
+				return err
+			}
+			report, err := buildEnvReport(cwd)
+			if err != nil {
+				return err
+			}
+			out := cmd.OutOrStdout()
+			if asJSON {
+				encoder := json.NewEncoder(out)
+				encoder.SetIndent("", "  ")
+				return encoder.Encode(report)
+			}
+			return writeEnvReport(out, report)
+		},
+	}
+	cmd.Flags().BoolVar(&asJSON, "json", false, messages.EnvJSONFlag)
+	return cmd
+}
+
+func buildEnvReport(cwd string) (envReport, error) {
+	resolution, err := resolveDispatch(cwd)
+	if err != nil {
+		return envReport{}, errcode.Wrap(errcode.Config, err)
+	}
+	executable, err := executablePath()
+	if err != nil {
+		return envReport{}, err
+	}
+	report := envReport{
+		Root:   resolution.Root,
+		Binary: envBinary{Version: resolution.CurrentVersion, Path: executable},
+		Pin:    envPin{Version: resolution.PinVersion, Warning: resolution.PinWarning},
+		Dispatch: envDispatch{
+			Decision:         string(resolution.Decision),
+			Source:           resolution.Source,
+			RequestedVersion: resolution.RequestedVersion,
+			Reason:           resolution.Reason,
+		},
+		Paths: envPaths{StateFiles: []string{}, CacheDir: resolution.CacheDir},
+	}
+	switch resolution.Decision {
+	case versiondispatch.DecisionDispatch:
+		report.Dispatch.Binary = resolution.CachedBinary
+		report.Dispatch.Cached = resolution.Cached
+	case versiondispatch.DecisionCurrent, versiondispatch.DecisionBypass:
+		report.Dispatch.Binary = executable
+		report.Dispatch.Cached = true
+	}
+	if resolution.Root == "" {
+		return report, nil
+	}
+
+	report.Pin.Path = filepath.Join(layerdir.Dir(resolution.Root), "al.version")
+	baseline, err := readBaselineVersion(resolution.Root)
+	if err != nil {
+		return envReport{}, errcode.Wrap(errcode.Config, err)
+	}
+	report.TemplateBaselineVersion = baseline
+	stateDir := config.StateDir(resolution.Root)
+	report.Paths.StateDir = stateDir
+	entries, err := os.ReadDir(stateDir)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return envReport{}, err
+	}
+	for _, entry := range entries {
+		report.Paths.StateFiles = append(report.Paths.StateFiles, filepath.Join(stateDir, entry.Name()))
+	}
+	sort.Strings(report.Paths.StateFiles)
+	return report, nil
+}
+
+func writeEnvReport(out io.Writer, report envReport) error {
+	binary := report.Dispatch.Binary
+	switch {
+	case report.Dispatch.Decision == string(versiondispatch.DecisionDispatch) && report.Dispatch.Cached:
+		binary += messages.EnvCachedNote
+	case report.Dispatch.Decision == string(versiondispatch.DecisionDispatch):
+		binary += messages.EnvMissing
+	case report.Dispatch.Decision == string(versiondispatch.DecisionBlocked):
+		binary = report.Dispatch.Reason
+	}
+	lines := [][2]string{
+		{"root:", report.Root},
+		{"binary:", report.Binary.Version + " " + report.Binary.Path},
+		{"pin:", report.Pin.Version},
+		{"pin file:", report.Pin.Path},
+	}
+	if report.Pin.Warning != "" {
+		lines = append(lines, [2]string{"pin warning:", report.Pin.Warning})
+	}
+	lines = append(lines,
+		[2]string{"dispatch:", report.Dispatch.Decision + " " + report.Dispatch.RequestedVersion + " (" + report.Dispatch.Source + ")"},
+		[2]string{"runs:", binary},
+		[2]string{"template baseline:", report.TemplateBaselineVersion},
+		[2]string{"state dir:", report.Paths.StateDir},
+		[2]string{"cache dir:", report.Paths.CacheDir},
+	)
+	for _, line := range lines {
+		value := line[1]
+		if value == "" {
+			value = messages.EnvNone
+		}
+		if _, err := fmt.Fprintf(out, i18n.T(messages.EnvLineFmt), line[0], value); err != nil {
+			return err
+		}
+	}
+	for _, path := range report.Paths.StateFiles {
+		if _, err := fmt.Fprintf(out, i18n.T(messages.EnvLineFmt), "state file:", path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
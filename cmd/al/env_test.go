@@ -0,0 +1,121 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/conn-castle/agent-layer/internal/versiondispatch"
+)
+
+func stubEnvReport(t *testing.T, resolution versiondispatch.Resolution, baseline string) {
+	t.Helper()
+	originalResolve, originalExecutable, originalBaseline := resolveDispatch, executablePath, readBaselineVersion
+	resolveDispatch = func(string) (versiondispatch.Resolution, error) { return resolution, nil }
+	executablePath = func() (string, error) { return "/usr/local/bin/al", nil }
+	readBaselineVersion = func(string) (string, error) { return baseline, nil }
+	t.Cleanup(func() {
+		resolveDispatch, executablePath, readBaselineVersion = originalResolve, originalExecutable, originalBaseline
+	})
+}
+
+func TestEnvCmdJSON(t *testing.T) {
+	root := stubRepoRoot(t)
+	stateDir := filepath.Join(root, ".agent-layer", "state")
+	if err := os.MkdirAll(stateDir, 0o700); err != nil {
+		t.Fatalf("mkdir state: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(stateDir, "managed-baseline.json"), []byte("{}"), 0o600); err != nil {
+		t.Fatalf("write baseline: %v", err)
+	}
+	stubEnvReport(t, versiondispatch.Resolution{
+		Root:             root,
+		CurrentVersion:   "1.0.0",
+		PinVersion:       "0.9.0",
+		RequestedVersion: "0.9.0",
+		Source:           "pin",
+		Decision:         versiondispatch.DecisionDispatch,
+		CacheDir:         "/cache/agent-layer",
+		CachedBinary:     "/cache/agent-layer/versions/0.9.0/linux-amd64/al-linux-amd64",
+	}, "0.9.0")
+
+	var out bytes.Buffer
+	cmd := newEnvCmd()
+	cmd.SilenceUsage = true
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"--json"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("env --json: %v", err)
+	}
+	var report envReport
+	if err := json.Unmarshal(out.Bytes(), &report); err != nil {
+		t.Fatalf("decode report: %v\n%s", err, out.String())
+	}
+	if report.Root != root || report.Binary.Version != "1.0.0" || report.Binary.Path != "/usr/local/bin/al" {
+		t.Fatalf("unexpected root or binary: %+v", report)
+	}
+	if report.Pin.Version != "0.9.0" || report.Pin.Path != filepath.Join(root, ".agent-layer", "al.version") {
+		t.Fatalf("unexpected pin: %+v", report.Pin)
+	}
+	if report.Dispatch.Decision != "dispatch" || report.Dispatch.Binary != "/cache/agent-layer/versions/0.9.0/linux-amd64/al-linux-amd64" || report.Dispatch.Cached {
+		t.Fatalf("unexpected dispatch: %+v", report.Dispatch)
+	}
+	if report.TemplateBaselineVersion != "0.9.0" {
+		t.Fatalf("template baseline = %q", report.TemplateBaselineVersion)
+	}
+	if report.Paths.StateDir != stateDir || len(report.Paths.StateFiles) != 1 || report.Paths.StateFiles[0] != filepath.Join(stateDir, "managed-baseline.json") {
+		t.Fatalf("unexpected paths: %+v", report.Paths)
+	}
+}
+
+func TestEnvCmdTextOutsideRepo(t *testing.T) {
+	stubEnvReport(t, versiondispatch.Resolution{
+		CurrentVersion:   "1.0.0",
+		RequestedVersion: "1.0.0",
+		Source:           "current",
+		Decision:         versiondispatch.DecisionCurrent,
+		CacheDir:         "/cache/agent-layer",
+	}, "")
+	originalGetwd := getwd
+	getwd = func() (string, error) { return t.TempDir(), nil }
+	t.Cleanup(func() { getwd = originalGetwd })
+
+	var out bytes.Buffer
+	cmd := newEnvCmd()
+	cmd.SilenceUsage = true
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("env: %v", err)
+	}
+	text := out.String()
+	for _, want := range []string{"root:              -", "dispatch:          current 1.0.0 (current)", "runs:              /usr/local/bin/al", "cache dir:         /cache/agent-layer"} {
+		if !strings.Contains(text, want) {
+			t.Fatalf("output missing %q:\n%s", want, text)
+		}
+	}
+	if strings.Contains(text, "state file:") {
+		t.Fatalf("unexpected state files outside a repo:\n%s", text)
+	}
+}
+
+func TestEnvCmdResolveError(t *testing.T) {
+	stubRepoRoot(t)
+	stubEnvReport(t, versiondispatch.Resolution{}, "")
+	resolveDispatch = func(string) (versiondispatch.Resolution, error) {
+		return versiondispatch.Resolution{}, errors.New("boom")
+	}
+
+	cmd := newEnvCmd()
+	cmd.SilenceUsage = true
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs([]string{})
+	if err := cmd.Execute(); err == nil || !strings.Contains(err.Error(), "boom") {
+		t.Fatalf("expected resolve error, got %v", err)
+	}
+}
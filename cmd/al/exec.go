@@ -0,0 +1,131 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/conn-castle/agent-layer/internal/audit"
+	"github.com/conn-castle/agent-layer/internal/config"
+	"github.com/conn-castle/agent-layer/internal/errcode"
+	"github.com/conn-castle/agent-layer/internal/execguard"
+	"github.com/conn-castle/agent-layer/internal/i18n"
+	"github.com/conn-castle/agent-layer/internal/messages"
+)
+
+var (
+	loadExecProject = config.LoadProjectConfig
+	loadExecDeny    = execguard.LoadDeny
+	appendAudit     = audit.Append
+	auditNow        = time.Now
+)
+
+func newExecCmd() *cobra.Command {
+	var client string
+	var restricted bool
+
+	cmd := &cobra.Command{
+		Use:   messages.ExecUse,
+		Short: messages.ExecShort,
+		Long:  messages.ExecLong,
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			root, err := resolveRepoRoot()
+			if err != nil {
+				return err
+			}
+			project, err := loadExecProject(root)
+			if err != nil {
+				return errcode.Wrap(errcode.Config, err)
+			}
+			deny, err := loadExecDeny(root)
+			if err != nil {
+				return errcode.Wrap(errcode.Config, err)
+			}
+
+			decision := execguard.Evaluate(project.Config, project.CommandsAllow, deny, args)
+			if decision.Outcome == execguard.NeedsApproval {
+				decision, err = confirmExec(cmd, args, decision)
+				if err != nil {
+					return err
+				}
+			}
+			line := execguard.CommandLine(args)
+			if err := appendAudit(root, audit.Entry{
+				Source:   audit.SourceExec,
+				Client:   strings.TrimSpace(client),
+				Command:  line,
+				Decision: string(decision.Outcome),
+				Rule:     decision.Rule,
+				Reason:   decision.Reason,
+			}, auditNow()); err != nil {
+				return err
+			}
+			if decision.Outcome != execguard.Allowed {
+				if decision.Rule != "" {
+					return i18n.Errorf(messages.ExecDeniedRuleFmt, line, decision.Reason, decision.Rule)
+				}
+				return i18n.Errorf(messages.ExecDeniedFmt, line, decision.Reason)
+			}
+			return runGuardedCommand(cmd, args, restricted)
+		},
+	}
+	cmd.Flags().SetInterspersed(false)
+	cmd.Flags().StringVar(&client, "client", "", messages.ExecClientFlag)
+	cmd.Flags().BoolVar(&restricted, "restricted", false, messages.ExecRestrictedFlag)
+	return cmd
+}
+
+// confirmExec asks the person at the terminal to approve a command that the
+// allowlist and approvals mode do not cover. Without a terminal the command
+// is denied, keeping the original reason.
+func confirmExec(cmd *cobra.Command, args []string, decision execguard.Decision) (execguard.Decision, error) {
+	denied := execguard.Decision{Outcome: execguard.Denied, Rule: decision.Rule, Reason: decision.Reason}
+	if !isTerminal() {
+		return denied, nil
+	}
+	if _, err := fmt.Fprintf(cmd.ErrOrStderr(), i18n.T(messages.ExecConfirmFmt), execguard.CommandLine(args), decision.Reason); err != nil {
+		return denied, err
+	}
+	answer, err := bufio.NewReader(cmd.InOrStdin()).ReadString('\n')
+	if err != nil && answer == "" {
+		return denied, nil
+	}
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return execguard.Decision{Outcome: execguard.Allowed, Rule: decision.Rule, Reason: messages.ExecGuardReasonConfirmed}, nil
+	}
+	return denied, nil
+}
+
+// runGuardedCommand runs an approved command in the current directory and
+// passes its exit code through.
+func runGuardedCommand(cmd *cobra.Command, args []string, restricted bool) error {
+	child := exec.CommandContext(cmd.Context(), args[0], args[1:]...) // #nosec G204 -- al exec runs the command only after the guard approved it.
+	child.Stdin = cmd.InOrStdin()
+	child.Stdout = cmd.OutOrStdout()
+	child.Stderr = cmd.ErrOrStderr()
+	child.Env = os.Environ()
+	if restricted {
+		child.Env = execguard.RestrictedEnv(child.Env)
+	}
+	err := child.Run()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		code := exitErr.ExitCode()
+		if code <= 0 {
+			code = 1
+		}
+		return &SilentExitError{Code: code}
+	}
+	if err != nil {
+		return i18n.Errorf(messages.ExecRunFailedFmt, args[0], err)
+	}
+	return nil
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/conn-castle/agent-layer/internal/audit"
+	"github.com/conn-castle/agent-layer/internal/config"
+)
+
+func stubExecGuard(t *testing.T, mode string, allow []string, deny []string) *[]audit.Entry {
+	t.Helper()
+	origProject, origDeny, origAudit, origTerminal := loadExecProject, loadExecDeny, appendAudit, isTerminal
+	t.Cleanup(func() {
+		loadExecProject, loadExecDeny, appendAudit, isTerminal = origProject, origDeny, origAudit, origTerminal
+	})
+	loadExecProject = func(root string) (*config.ProjectConfig, error) {
+		return &config.ProjectConfig{Root: root, Config: config.Config{Approvals: config.ApprovalsConfig{Mode: mode}}, CommandsAllow: allow}, nil
+	}
+	loadExecDeny = func(string) ([]string, error) { return deny, nil }
+	isTerminal = func() bool { return false }
+	var entries []audit.Entry
+	appendAudit = func(_ string, entry audit.Entry, _ time.Time) error {
+		entries = append(entries, entry)
+		return nil
+	}
+	return &entries
+}
+
+func runExecCmd(t *testing.T, stdin string, args ...string) (string, string, error) {
+	t.Helper()
+	cmd := newExecCmd()
+	cmd.SilenceUsage = true
+	var out, errOut bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(&errOut)
+	cmd.SetIn(strings.NewReader(stdin))
+	cmd.SetArgs(args)
+	err := cmd.Execute()
+	return out.String(), errOut.String(), err
+}
+
+func TestExecCmd_Allowed(t *testing.T) {
+	if _, err := exec.LookPath("echo"); err != nil {
+		t.Skip("echo not installed")
+	}
+	stubRepoRoot(t)
+	entries := stubExecGuard(t, config.ApprovalModeCommands, []string{"echo"}, nil)
+
+	out, _, err := runExecCmd(t, "", "--client", "claude", "--", "echo", "hello", "--client")
+	if err != nil {
+		t.Fatalf("exec: %v", err)
+	}
+	if out != "hello --client\n" {
+		t.Fatalf("unexpected output %q", out)
+	}
+	if len(*entries) != 1 {
+		t.Fatalf("expected one audit entry, got %#v", *entries)
+	}
+	entry := (*entries)[0]
+	if entry.Source != audit.SourceExec || entry.Client != "claude" || entry.Command != "echo hello --client" || entry.Decision != "allowed" || entry.Rule != "echo" {
+		t.Fatalf("unexpected audit entry %#v", entry)
+	}
+}
+
+func TestExecCmd_Denied(t *testing.T) {
+	stubRepoRoot(t)
+	entries := stubExecGuard(t, config.ApprovalModeYOLO, nil, []string{"git push"})
+
+	_, _, err := runExecCmd(t, "", "git", "push", "origin")
+	if err == nil || err.Error() != `al exec refused "git push origin": matches commands.deny (git push)` {
+		t.Fatalf("expected denial, got %v", err)
+	}
+	if len(*entries) != 1 || (*entries)[0].Decision != "denied" {
+		t.Fatalf("expected denial recorded, got %#v", *entries)
+	}
+}
+
+func TestExecCmd_NeedsApproval(t *testing.T) {
+	if _, err := exec.LookPath("true"); err != nil {
+		t.Skip("true not installed")
+	}
+	stubRepoRoot(t)
+	entries := stubExecGuard(t, config.ApprovalModeNone, nil, nil)
+
+	_, _, err := runExecCmd(t, "", "true")
+	if err == nil || !strings.Contains(err.Error(), "not in commands.allow") {
+		t.Fatalf("expected non-interactive refusal, got %v", err)
+	}
+
+	isTerminal = func() bool { return true }
+	_, prompt, err := runExecCmd(t, "n\n", "true")
+	if err == nil || !strings.Contains(prompt, `Run "true" (not in commands.allow)? [y/N]: `) {
+		t.Fatalf("expected declined prompt, got %v %q", err, prompt)
+	}
+	if _, _, err := runExecCmd(t, "y\n", "true"); err != nil {
+		t.Fatalf("expected confirmed run, got %v", err)
+	}
+	var decisions []string
+	for _, entry := range *entries {
+		decisions = append(decisions, entry.Decision+":"+entry.Reason)
+	}
+	if strings.Join(decisions, ",") != "denied:not in commands.allow,denied:not in commands.allow,allowed:approved at the prompt" {
+		t.Fatalf("unexpected audit decisions %v", decisions)
+	}
+}
+
+func TestExecCmd_ExitCodeAndRestrictedEnv(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not installed")
+	}
+	stubRepoRoot(t)
+	stubExecGuard(t, config.ApprovalModeYOLO, nil, nil)
+	t.Setenv("AL_EXEC_SECRET", "s3cret")
+
+	out, _, err := runExecCmd(t, "", "--restricted", "sh", "-c", "echo \"[$AL_EXEC_SECRET]\"; exit 3")
+	var silent *SilentExitError
+	if !errors.As(err, &silent) || silent.Code != 3 {
+		t.Fatalf("expected exit code 3 passed through, got %v", err)
+	}
+	if out != "[]\n" {
+		t.Fatalf("expected secret dropped from the environment, got %q", out)
+	}
+}
+
+func TestExecCmd_AuditFailureBlocksRun(t *testing.T) {
+	stubRepoRoot(t)
+	stubExecGuard(t, config.ApprovalModeYOLO, nil, nil)
+	appendAudit = func(string, audit.Entry, time.Time) error { return errors.New("disk full") }
+
+	path := filepath.Join(t.TempDir(), "marker")
+	_, _, err := runExecCmd(t, "", "touch", path)
+	if err == nil || err.Error() != "disk full" {
+		t.Fatalf("expected audit error, got %v", err)
+	}
+	if _, statErr := os.Stat(path); !os.IsNotExist(statErr) {
+		t.Fatalf("expected command not run, stat err %v", statErr)
+	}
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/conn-castle/agent-layer/internal/clientimport"
+	"github.com/conn-castle/agent-layer/internal/i18n"
+	"github.com/conn-castle/agent-layer/internal/install"
+	"github.com/conn-castle/agent-layer/internal/layerdir"
+	"github.com/conn-castle/agent-layer/internal/messages"
+)
+
+var (
+	clientImportSources = clientimport.Sources
+	importClientConfig  = clientimport.Import
+)
+
+func newImportCmd() *cobra.Command {
+	var from string
+	cmd := &cobra.Command{
+		Use:   messages.ImportUse,
+		Short: messages.ImportShort,
+		Long:  messages.ImportLong,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// Importing is how a repo without .agent-layer adopts one, so
+			// resolve the root the way init does.
+			root, _, err := resolveInitRoot(false)
+			if err != nil {
+				return err
+			}
+			if _, err := clientImportSources(root, from); err != nil {
+				return err
+			}
+			out := cmd.OutOrStdout()
+			agentLayerPath, _ := layerdir.InstallMarker(root)
+			if _, err := statAgentLayerPath(agentLayerPath); errors.Is(err, os.ErrNotExist) {
+				pinned, err := resolvePinVersion("", Version)
+				if err != nil {
+					return err
+				}
+				if err := installRun(root, install.Options{PinVersion: pinned, System: install.RealSystem{}}); err != nil {
+					return err
+				}
+				if _, err := fmt.Fprintf(out, i18n.T(messages.ImportInitializedFmt), root); err != nil {
+					return err
+				}
+			} else if err != nil {
+				return i18n.Errorf(messages.InstallFailedStatFmt, agentLayerPath, err)
+			}
+
+			result, err := importClientConfig(clientimport.Options{Root: root, Client: from})
+			if err != nil {
+				return err
+			}
+			for _, item := range append(result.Instructions, result.Servers...) {
+				format := messages.ImportItemFmt
+				if item.Disabled {
+					format = messages.ImportDisabledFmt
+				}
+				if _, err := fmt.Fprintf(out, format, item.Status, item.Name, item.Source); err != nil {
+					return err
+				}
+			}
+			if len(result.Secrets) > 0 {
+				if _, err := fmt.Fprintf(out, i18n.T(messages.ImportSecretsFmt), strings.Join(result.Secrets, ", ")); err != nil {
+					return err
+				}
+			}
+			if len(result.MissingEnv) > 0 {
+				if _, err := fmt.Fprintf(out, i18n.T(messages.ImportMissingEnvFmt), strings.Join(result.MissingEnv, ", ")); err != nil {
+					return err
+				}
+			}
+			if result.Agent != "" {
+				if _, err := fmt.Fprintf(out, i18n.T(messages.ImportAgentFmt), result.Agent); err != nil {
+					return err
+				}
+			}
+			_, err = fmt.Fprintln(out, i18n.T(messages.ImportSyncHint))
+			return err
+		},
+	}
+	cmd.Flags().StringVar(&from, "from", "", messages.ImportFlagFrom)
+	_ = cmd.MarkFlagRequired("from")
+	_ = cmd.RegisterFlagCompletionFunc("from", cobra.FixedCompletions(clientimport.Clients, cobra.ShellCompDirectiveNoFileComp))
+	return cmd
+}
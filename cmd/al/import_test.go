@@ -0,0 +1,110 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/conn-castle/agent-layer/internal/clientimport"
+	"github.com/conn-castle/agent-layer/internal/install"
+)
+
+func stubClientImport(t *testing.T, result *clientimport.Result) *clientimport.Options {
+	t.Helper()
+	originalSources, originalImport := clientImportSources, importClientConfig
+	var got clientimport.Options
+	clientImportSources = func(string, string) ([]string, error) { return []string{"CLAUDE.md"}, nil }
+	importClientConfig = func(opts clientimport.Options) (*clientimport.Result, error) {
+		got = opts
+		return result, nil
+	}
+	t.Cleanup(func() { clientImportSources, importClientConfig = originalSources, originalImport })
+	return &got
+}
+
+func TestImportCmd(t *testing.T) {
+	root := stubRepoRoot(t)
+	got := stubClientImport(t, &clientimport.Result{
+		Client:       clientimport.ClientClaude,
+		Instructions: []clientimport.ItemResult{{Name: ".agent-layer/instructions/90_imported_claude.md", Source: "CLAUDE.md", Status: clientimport.StatusAdded}},
+		Servers: []clientimport.ItemResult{
+			{Name: "github", Source: ".mcp.json", Status: clientimport.StatusExists},
+			{Name: "docs", Source: ".mcp.json", Status: clientimport.StatusAdded, Disabled: true},
+		},
+		Secrets:    []string{"AL_GITHUB_TOKEN"},
+		MissingEnv: []string{"AL_DOCS_TOKEN"},
+		Agent:      "claude",
+	})
+	originalInstall := installRun
+	installRun = func(string, install.Options) error { return errors.New("install must not run") }
+	t.Cleanup(func() { installRun = originalInstall })
+
+	cmd := newImportCmd()
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"--from", "claude"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("import: %v", err)
+	}
+	if got.Root != root || got.Client != "claude" {
+		t.Fatalf("unexpected options %#v", *got)
+	}
+	for _, want := range []string{
+		"added   .agent-layer/instructions/90_imported_claude.md (from CLAUDE.md)",
+		"exists  github (from .mcp.json)",
+		"added   docs (from .mcp.json; disabled until its .env values are set)",
+		"Moved literal values into .agent-layer/.env: AL_GITHUB_TOKEN",
+		"Set these in .agent-layer/.env, then enable the servers that use them: AL_DOCS_TOKEN",
+		"Enabled agents.claude.",
+		"run `al sync`",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Fatalf("expected %q in output:\n%s", want, out.String())
+		}
+	}
+}
+
+func TestImportCmd_InitializesMissingAgentLayer(t *testing.T) {
+	root := stubRepoRoot(t)
+	if err := os.RemoveAll(filepath.Join(root, ".agent-layer")); err != nil {
+		t.Fatalf("remove .agent-layer: %v", err)
+	}
+	if err := os.Mkdir(filepath.Join(root, ".git"), 0o700); err != nil {
+		t.Fatalf("mkdir .git: %v", err)
+	}
+	stubClientImport(t, &clientimport.Result{Client: clientimport.ClientCursor})
+	originalInstall := installRun
+	var installedAt string
+	installRun = func(gotRoot string, opts install.Options) error {
+		installedAt = gotRoot
+		return nil
+	}
+	t.Cleanup(func() { installRun = originalInstall })
+
+	cmd := newImportCmd()
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"--from", "cursor"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("import: %v", err)
+	}
+	if installedAt != root || !strings.Contains(out.String(), "Initialized .agent-layer/ in "+root) {
+		t.Fatalf("expected init at %s, got %q:\n%s", root, installedAt, out.String())
+	}
+}
+
+func TestImportCmd_SourcesError(t *testing.T) {
+	stubRepoRoot(t)
+	stubClientImport(t, nil)
+	clientImportSources = func(string, string) ([]string, error) { return nil, errors.New("no cursor configuration found") }
+
+	cmd := newImportCmd()
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetArgs([]string{"--from", "cursor"})
+	if err := cmd.Execute(); err == nil || !strings.Contains(err.Error(), "no cursor configuration") {
+		t.Fatalf("expected sources error, got %v", err)
+	}
+}
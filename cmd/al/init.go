@@ -7,15 +7,19 @@ import (
 	"io"
 	"net/http"
 	"os"
-	"path/filepath"
 	"strings"
 	"time"
 
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
 
+	"github.com/conn-castle/agent-layer/internal/ci"
+	"github.com/conn-castle/agent-layer/internal/config"
+	"github.com/conn-castle/agent-layer/internal/i18n"
 	"github.com/conn-castle/agent-layer/internal/install"
+	"github.com/conn-castle/agent-layer/internal/layerdir"
 	"github.com/conn-castle/agent-layer/internal/messages"
+	"github.com/conn-castle/agent-layer/internal/offline"
 	alsync "github.com/conn-castle/agent-layer/internal/sync"
 	"github.com/conn-castle/agent-layer/internal/update"
 	"github.com/conn-castle/agent-layer/internal/version"
@@ -33,7 +37,16 @@ var runWizardAfterInit = func(root string, pinVersion string) error {
 
 var installRun = install.Run
 var installRollbackUpgradeSnapshot = install.RollbackUpgradeSnapshot
-var syncRun = alsync.Run
+
+// syncRun is the sync after an upgrade and after an upgrade rollback. Its
+// [hooks] output goes to hookOutput.
+var syncRun = func(root string, hookOutput io.Writer) (*alsync.Result, error) {
+	project, err := config.LoadProjectConfig(root)
+	if err != nil {
+		return nil, err
+	}
+	return alsync.RunWithProjectOptions(alsync.RealSystem{}, root, project, alsync.RunOptions{HookOutput: hookOutput})
+}
 var statAgentLayerPath = os.Stat
 
 var resolveLatestPinVersion = func(ctx context.Context, currentVersion string) (string, error) {
@@ -43,7 +56,7 @@ var resolveLatestPinVersion = func(ctx context.Context, currentVersion string) (
 	}
 	latest := strings.TrimSpace(result.Latest)
 	if latest == "" {
-		return "", fmt.Errorf(messages.InitLatestVersionMissing)
+		return "", i18n.Errorf(messages.InitLatestVersionMissing)
 	}
 	return latest, nil
 }
@@ -56,6 +69,7 @@ func newInitCmd() *cobra.Command {
 	var noWizard bool
 	var pinVersion string
 	var here bool
+	var wait bool
 
 	cmd := &cobra.Command{
 		Use:   messages.InitUse,
@@ -66,17 +80,17 @@ func newInitCmd() *cobra.Command {
 			if err != nil {
 				return err
 			}
-			agentLayerPath := filepath.Join(root, ".agent-layer")
+			agentLayerPath, isDir := layerdir.InstallMarker(root)
 			if info, err := statAgentLayerPath(agentLayerPath); err == nil {
-				if !info.IsDir() {
-					return fmt.Errorf(messages.RootPathNotDirFmt, agentLayerPath)
+				if isDir && !info.IsDir() {
+					return i18n.Errorf(messages.RootPathNotDirFmt, agentLayerPath)
 				}
 				if root != cwd {
-					return fmt.Errorf(messages.InitAlreadyInitializedAncestorFmt, root, cwd)
+					return i18n.Errorf(messages.InitAlreadyInitializedAncestorFmt, root, cwd)
 				}
-				return fmt.Errorf(messages.InitAlreadyInitialized)
+				return i18n.Errorf(messages.InitAlreadyInitialized)
 			} else if !errors.Is(err, os.ErrNotExist) {
-				return fmt.Errorf(messages.InstallFailedStatFmt, agentLayerPath, err)
+				return i18n.Errorf(messages.InstallFailedStatFmt, agentLayerPath, err)
 			}
 			pinned, err := resolvePinVersionForInit(cmd.Context(), pinVersion, Version)
 			if err != nil {
@@ -88,10 +102,16 @@ func newInitCmd() *cobra.Command {
 				}
 			}
 			warnInitUpdate(cmd, pinVersion)
+			lock, err := lockRepoState(cmd.ErrOrStderr(), root, wait)
+			if err != nil {
+				return err
+			}
+			defer func() { _ = lock.Release() }()
 			opts := install.Options{
 				Overwrite:  false,
 				PinVersion: pinned,
 				System:     install.RealSystem{},
+				Output:     commandOutput(cmd, ""),
 			}
 			if err := installRun(root, opts); err != nil {
 				return err
@@ -113,6 +133,7 @@ func newInitCmd() *cobra.Command {
 	cmd.Flags().BoolVar(&noWizard, "no-wizard", false, messages.InitFlagNoWizard)
 	cmd.Flags().StringVar(&pinVersion, "version", "", messages.InitFlagVersion)
 	cmd.Flags().BoolVar(&here, "here", false, messages.InitFlagHere)
+	cmd.Flags().BoolVar(&wait, "wait", false, messages.StateLockFlagWait)
 
 	return cmd
 }
@@ -125,7 +146,7 @@ func warnInitUpdate(cmd *cobra.Command, flagVersion string) {
 	if strings.TrimSpace(os.Getenv(versiondispatch.EnvVersionOverride)) != "" {
 		return
 	}
-	if strings.TrimSpace(os.Getenv(versiondispatch.EnvNoNetwork)) != "" {
+	if strings.TrimSpace(os.Getenv(versiondispatch.EnvNoNetwork)) != "" || offline.Enabled(os.Getenv) {
 		return
 	}
 	warnColor := color.New(color.FgYellow)
@@ -171,7 +192,7 @@ func resolvePinVersionForInit(ctx context.Context, flagValue string, buildVersio
 	if strings.EqualFold(flag, "latest") {
 		latest, err := resolveLatestPinVersion(ctx, buildVersion)
 		if err != nil {
-			return "", fmt.Errorf(messages.InitResolveLatestVersionFmt, err)
+			return "", i18n.Errorf(messages.InitResolveLatestVersionFmt, err)
 		}
 		return latest, nil
 	}
@@ -184,28 +205,32 @@ func validatePinnedReleaseVersion(ctx context.Context, pinVersion string) error
 	if err != nil {
 		return err
 	}
+	if err := offline.Check(os.Getenv, i18n.Sprintf(messages.OfflineOpValidateFmt, normalized)); err != nil {
+		return err
+	}
 	releaseURL := fmt.Sprintf("%s/tag/v%s", releaseValidationBaseURL, normalized)
 	req, err := http.NewRequestWithContext(ctx, http.MethodHead, releaseURL, nil)
 	if err != nil {
-		return fmt.Errorf(messages.InitCreateReleaseValidationRequestFmt, err)
+		return i18n.Errorf(messages.InitCreateReleaseValidationRequestFmt, err)
 	}
 	req.Header.Set("User-Agent", "agent-layer")
 	resp, err := releaseValidationHTTPClient.Do(req) //nolint:gosec // URL is a validated GitHub release URL
 	if err != nil {
-		return fmt.Errorf(messages.InitValidateReleaseVersionRequestFmt, normalized, err)
+		return i18n.Errorf(messages.InitValidateReleaseVersionRequestFmt, normalized, err)
 	}
 	defer func() { _ = resp.Body.Close() }()
 	if resp.StatusCode == http.StatusNotFound {
-		return fmt.Errorf(messages.InitReleaseVersionNotFoundFmt, normalized, releaseValidationBaseURL)
+		return i18n.Errorf(messages.InitReleaseVersionNotFoundFmt, normalized, releaseValidationBaseURL)
 	}
 	if resp.StatusCode >= http.StatusBadRequest {
-		return fmt.Errorf(messages.InitValidateReleaseVersionStatusFmt, normalized, resp.Status)
+		return i18n.Errorf(messages.InitValidateReleaseVersionStatusFmt, normalized, resp.Status)
 	}
 	return nil
 }
 
 // promptYesNo asks a yes/no question and returns the user's choice or an error.
 // defaultYes controls the result when the user provides an empty response.
+// In CI mode it fails with errcode.InputRequired instead of reading.
 //
 // Reuses an existing `*bufio.Reader` via `bufferedReader` when one is passed
 // in so consecutive prompts share a single read-ahead buffer; otherwise
@@ -213,14 +238,17 @@ func validatePinnedReleaseVersion(ctx context.Context, pinVersion string) error
 // calls. Relying on `bufio.NewReader`'s same-size reuse optimization here
 // would make that invariant implicit and brittle.
 func promptYesNo(in io.Reader, out io.Writer, prompt string, defaultYes bool) (bool, error) {
+	if err := checkPrompt(prompt); err != nil {
+		return false, err
+	}
 	reader := bufferedReader(in)
 	for {
 		if defaultYes {
-			if _, err := fmt.Fprintf(out, messages.PromptYesDefaultFmt, prompt); err != nil {
+			if _, err := fmt.Fprintf(out, i18n.T(messages.PromptYesDefaultFmt), prompt); err != nil {
 				return false, err
 			}
 		} else {
-			if _, err := fmt.Fprintf(out, messages.PromptNoDefaultFmt, prompt); err != nil {
+			if _, err := fmt.Fprintf(out, i18n.T(messages.PromptNoDefaultFmt), prompt); err != nil {
 				return false, err
 			}
 		}
@@ -244,9 +272,9 @@ func promptYesNo(in io.Reader, out io.Writer, prompt string, defaultYes bool) (b
 			return false, nil
 		}
 		if errors.Is(err, io.EOF) {
-			return false, fmt.Errorf(messages.PromptInvalidResponse, response)
+			return false, i18n.Errorf(messages.PromptInvalidResponse, response)
 		}
-		if _, err := fmt.Fprintln(out, messages.PromptRetryYesNo); err != nil {
+		if _, err := fmt.Fprintln(out, i18n.T(messages.PromptRetryYesNo)); err != nil {
 			return false, err
 		}
 	}
@@ -264,7 +292,7 @@ func printFilePaths(out io.Writer, header string, paths []string) error {
 		return err
 	}
 	for _, path := range paths {
-		if _, err := fmt.Fprintf(out, messages.InstallDiffLineFmt, path); err != nil {
+		if _, err := fmt.Fprintf(out, i18n.T(messages.InstallDiffLineFmt), path); err != nil {
 			return err
 		}
 	}
@@ -273,3 +301,10 @@ func printFilePaths(out io.Writer, header string, paths []string) error {
 	}
 	return nil
 }
+
+// checkPrompt fails with errcode.InputRequired in CI mode, before a prompt
+// helper reads an answer. Commands that check isTerminal first fail earlier
+// with a message naming the flag that answers the prompt.
+func checkPrompt(prompt string) error {
+	return ci.CheckPrompt(os.Getenv, prompt)
+}
@@ -17,6 +17,8 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/conn-castle/agent-layer/internal/ci"
+	"github.com/conn-castle/agent-layer/internal/errcode"
 	"github.com/conn-castle/agent-layer/internal/install"
 	"github.com/conn-castle/agent-layer/internal/update"
 	"github.com/conn-castle/agent-layer/internal/versiondispatch"
@@ -921,7 +923,7 @@ func TestInitCmd_AgentLayerIsFileErrors(t *testing.T) {
 	getwd = func() (string, error) { return tmpDir, nil }
 	isTerminal = func() bool { return false }
 	installRun = func(string, install.Options) error {
-		t.Fatal("installRun should not be called when .agent-layer is not a directory")
+		t.Fatal("installRun should not be called when the .agent-layer redirect is broken")
 		return nil
 	}
 
@@ -935,10 +937,21 @@ func TestInitCmd_AgentLayerIsFileErrors(t *testing.T) {
 	if err == nil {
 		t.Fatal("expected error")
 	}
-	if !strings.Contains(err.Error(), "exists but is not a directory") {
+	// A regular .agent-layer file is a redirect; one naming a missing
+	// directory fails before anything is installed.
+	if !strings.Contains(err.Error(), "redirects to") || !strings.Contains(err.Error(), "not a directory") {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	if !strings.Contains(err.Error(), "move or remove") {
-		t.Fatalf("expected remediation guidance, got %v", err)
+}
+
+func TestPromptYesNoRefusedInCIMode(t *testing.T) {
+	t.Setenv(ci.EnvVar, "1")
+	var out bytes.Buffer
+	_, err := promptYesNo(strings.NewReader("y\n"), &out, "Continue?", true)
+	if errcode.Of(err) != errcode.InputRequired {
+		t.Fatalf("expected input_required error, got %v", err)
+	}
+	if out.Len() != 0 {
+		t.Fatalf("expected no prompt output in CI mode, got %q", out.String())
 	}
 }
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/conn-castle/agent-layer/internal/config"
+	"github.com/conn-castle/agent-layer/internal/i18n"
+)
+
+// applyLocale activates the message catalog that AL_LANG or the repo config's
+// language key selects. An unusable locale leaves output in English.
+func applyLocale(stderr io.Writer) {
+	configured := ""
+	if cwd, err := getwd(); err == nil {
+		if root, found, err := findAgentLayerRoot(cwd); err == nil && found {
+			configured = languageFromConfig(root)
+		}
+	}
+	if err := i18n.SetLocale(i18n.ResolveLocale(os.Getenv, configured)); err != nil {
+		_, _ = fmt.Fprintln(stderr, err)
+	}
+}
+
+// languageFromConfig returns the language key from root's config, or "" when
+// the config cannot be read.
+func languageFromConfig(root string) string {
+	cfg, err := config.LoadConfigLenient(config.DefaultPaths(root).ConfigPath)
+	if err != nil {
+		return ""
+	}
+	return cfg.Language
+}
+
+// localizeCommand translates the help text and flag usages of cmd and its
+// subcommands into the active locale.
+func localizeCommand(cmd *cobra.Command) {
+	seen := make(map[*pflag.Flag]struct{})
+	localizeFlag := func(flag *pflag.Flag) {
+		if _, ok := seen[flag]; ok {
+			return
+		}
+		seen[flag] = struct{}{}
+		flag.Usage = i18n.T(flag.Usage)
+	}
+	var walk func(*cobra.Command)
+	walk = func(c *cobra.Command) {
+		c.Short = i18n.T(c.Short)
+		c.Long = i18n.T(c.Long)
+		c.Flags().VisitAll(localizeFlag)
+		c.PersistentFlags().VisitAll(localizeFlag)
+		for _, child := range c.Commands() {
+			walk(child)
+		}
+	}
+	walk(cmd)
+}
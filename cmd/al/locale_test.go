@@ -0,0 +1,37 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/conn-castle/agent-layer/internal/i18n"
+	"github.com/conn-castle/agent-layer/internal/messages"
+)
+
+func TestRunMain_PrintsTranslatedError(t *testing.T) {
+	orig := maybeExecFunc
+	t.Cleanup(func() { maybeExecFunc = orig })
+	maybeExecFunc = func(args []string, currentVersion string, cwd string, stderr io.Writer, exit func(int)) error {
+		return nil
+	}
+	i18n.Register(i18n.FSLoader(fstest.MapFS{
+		"zz.json": {Data: []byte(`{"` + messages.RootMissingAgentLayer + `": "zz: not initialized"}`)},
+	}))
+	t.Cleanup(func() { _ = i18n.SetLocale(i18n.DefaultLocale) })
+	t.Setenv(i18n.EnvLang, "zz")
+	t.Chdir(t.TempDir())
+
+	var stderr bytes.Buffer
+	code := 0
+	runMain(context.Background(), []string{"al", "sync"}, io.Discard, &stderr, func(exitCode int) { code = exitCode })
+	if code == 0 {
+		t.Fatal("expected a non-zero exit")
+	}
+	if !strings.Contains(stderr.String(), "zz: not initialized") {
+		t.Fatalf("expected translated error, got %q", stderr.String())
+	}
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
@@ -12,12 +13,19 @@ import (
 	"strings"
 	"syscall"
 
+	"github.com/conn-castle/agent-layer/internal/ci"
 	"github.com/conn-castle/agent-layer/internal/config"
+	"github.com/conn-castle/agent-layer/internal/errcode"
+	"github.com/conn-castle/agent-layer/internal/i18n"
 	"github.com/conn-castle/agent-layer/internal/messages"
+	"github.com/conn-castle/agent-layer/internal/offline"
+	"github.com/conn-castle/agent-layer/internal/style"
+	alsync "github.com/conn-castle/agent-layer/internal/sync"
 	"github.com/conn-castle/agent-layer/internal/versiondispatch"
 )
 
 var maybeExecFunc = versiondispatch.MaybeExec
+var setenv = os.Setenv
 var executeFunc = execute
 
 // Version, Commit, and BuildDate are overridden at build time.
@@ -58,7 +66,10 @@ func (e SilentExitError) Error() string {
 
 // execute runs the CLI command with the provided args and output writers.
 func execute(ctx context.Context, args []string, stdout io.Writer, stderr io.Writer) error {
+	alsync.GeneratorVersion = Version
+	applyLocale(stderr)
 	cmd := newRootCmd()
+	localizeCommand(cmd)
 	cmd.Version = versionString()
 	cmd.SetVersionTemplate(messages.VersionTemplate)
 	if len(args) > 1 {
@@ -66,6 +77,9 @@ func execute(ctx context.Context, args []string, stdout io.Writer, stderr io.Wri
 	}
 	cmd.SetOut(stdout)
 	cmd.SetErr(stderr)
+	if handled, err := maybeRunPlugin(ctx, cmd, args, stdout, stderr); handled {
+		return err
+	}
 	return cmd.ExecuteContext(ctx)
 }
 
@@ -77,22 +91,133 @@ func runMain(ctx context.Context, args []string, stdout io.Writer, stderr io.Wri
 		exit(1)
 		return
 	}
+	format, err := errorFormatFromArgs(args)
+	if err != nil {
+		_, _ = fmt.Fprintln(stderr, err)
+		exit(1)
+		return
+	}
+	offlineFlag, args, err := splitOfflineArgs(args)
+	if err != nil {
+		writeFailure(stderr, format, err)
+		exit(1)
+		return
+	}
+	if offlineFlag {
+		_ = setenv(offline.EnvVar, "1")
+	}
+	if offlineFlag || offline.Enabled(os.Getenv) {
+		// Pinned releases that predate offline mode still honor AL_NO_NETWORK.
+		_ = setenv(versiondispatch.EnvNoNetwork, "1")
+	}
+	ciFlag, args, err := splitCIArgs(args)
+	if err != nil {
+		writeFailure(stderr, format, err)
+		exit(1)
+		return
+	}
+	if ciFlag {
+		_ = setenv(ci.EnvVar, "1")
+	}
+	ciMode := ciFlag || ci.Enabled(os.Getenv)
+	colorFlag, args := splitColorArgs(args)
+	colorMode, err := resolveColorMode(colorFlag, ciMode)
+	if err != nil {
+		writeFailure(stderr, format, err)
+		exit(1)
+		return
+	}
+	if colorFlag != "" {
+		_ = setenv(style.EnvVar, string(colorMode))
+	}
+	style.Apply(colorMode)
 	quiet := isQuiet(args, cwd)
 	dispatchStderr := stderr
 	if quiet {
 		dispatchStderr = io.Discard
 	}
 	if !shouldBypassDispatch(args) {
-		if handleRunError(maybeExecFunc(args, Version, cwd, dispatchStderr, exit), stderr, exit, true) {
+		if handleRunError(maybeExecFunc(args, Version, cwd, dispatchStderr, exit), stderr, format, ciMode, exit, true) {
 			return
 		}
 	}
-	if handleRunError(executeFunc(ctx, args, stdout, stderr), stderr, exit, false) {
+	if handleRunError(executeFunc(ctx, args, stdout, stderr), stderr, format, ciMode, exit, false) {
+		return
+	}
+}
+
+const (
+	errorFormatText = "text"
+	errorFormatJSON = "json"
+)
+
+// jsonFailure is the --error-format json payload. Field names are part of the
+// CLI contract.
+type jsonFailure struct {
+	Code    errcode.Code `json:"code"`
+	Message string       `json:"message"`
+	Hint    string       `json:"hint,omitempty"`
+}
+
+// errorFormatFromArgs reads --error-format from root args. Flags are scanned
+// before cobra parses them because dispatch failures are reported first.
+func errorFormatFromArgs(args []string) (string, error) {
+	format := errorFormatText
+	for i := 1; i < len(args); i++ {
+		arg := strings.TrimSpace(args[i])
+		if arg == "--" {
+			break
+		}
+		switch {
+		case arg == flagErrorFormat && i+1 < len(args):
+			i++
+			format = strings.TrimSpace(args[i])
+		case strings.HasPrefix(arg, flagErrorFormatPrefix):
+			format = strings.TrimPrefix(arg, flagErrorFormatPrefix)
+		default:
+			continue
+		}
+		if format != errorFormatText && format != errorFormatJSON {
+			return "", i18n.Errorf(messages.RootErrorFormatInvalidFmt, format)
+		}
+	}
+	return format, nil
+}
+
+// writeFailure prints err to stderr as text, or as one JSON object per line
+// when --error-format json was requested. Errors are translated when they are
+// created, so err prints as is.
+func writeFailure(stderr io.Writer, format string, err error) {
+	if format != errorFormatJSON {
+		_, _ = fmt.Fprintln(stderr, err)
+		return
+	}
+	code := errcode.Of(err)
+	data, marshalErr := json.Marshal(jsonFailure{Code: code, Message: err.Error(), Hint: i18n.T(errcode.Hint(code))})
+	if marshalErr != nil {
+		_, _ = fmt.Fprintln(stderr, err)
 		return
 	}
+	_, _ = fmt.Fprintln(stderr, string(data))
+}
+
+// resolveColorMode picks the color mode from --color, then AL_COLOR. CI mode
+// turns color off unless one of them asks for it.
+func resolveColorMode(flagValue string, ciMode bool) (style.Mode, error) {
+	value := flagValue
+	if value == "" {
+		value = os.Getenv(style.EnvVar)
+	}
+	if strings.TrimSpace(value) == "" && ciMode {
+		return style.Never, nil
+	}
+	return style.ParseMode(value)
 }
 
-func handleRunError(err error, stderr io.Writer, exit func(int), allowDispatched bool) bool {
+// handleRunError reports err and exits. In CI mode a classified failure exits
+// with errcode.ExitCode for its class; otherwise a failed child process passes
+// its exit code through and every other failure exits 1.
+func handleRunError(err error, stderr io.Writer, format string, ciMode bool, exit func(int), allowDispatched bool) bool {
 	if err == nil {
 		return false
 	}
@@ -104,9 +229,14 @@ func handleRunError(err error, stderr io.Writer, exit func(int), allowDispatched
 		exit(silent.Code)
 		return true
 	}
+	if code := errcode.Of(err); ciMode && code != errcode.Unknown {
+		writeFailure(stderr, format, err)
+		exit(errcode.ExitCode(code))
+		return true
+	}
 	var exitErr *exec.ExitError
 	if errors.As(err, &exitErr) {
-		_, _ = fmt.Fprintln(stderr, err)
+		writeFailure(stderr, format, err)
 		code := exitErr.ExitCode()
 		if code <= 0 {
 			code = 1
@@ -114,20 +244,21 @@ func handleRunError(err error, stderr io.Writer, exit func(int), allowDispatched
 		exit(code)
 		return true
 	}
-	_, _ = fmt.Fprintln(stderr, err)
+	writeFailure(stderr, format, err)
 	exit(1)
 	return true
 }
 
 // shouldBypassDispatch reports whether dispatch should be skipped for this invocation.
 // `al init` and `al upgrade` run through the invoking CLI so upgrade planning is based on
-// the currently installed binary templates, not an older repo-pinned version.
+// the currently installed binary templates, not an older repo-pinned version. `al env`
+// reports the dispatch decision instead of acting on it.
 func shouldBypassDispatch(args []string) bool {
 	if len(args) < 2 {
 		return false
 	}
 	command := firstCommandArg(args[1:])
-	return command == commandInit || command == commandUpgrade || command == "__dispatch-worker"
+	return command == commandInit || command == commandUpgrade || command == commandEnv || command == "__dispatch-worker"
 }
 
 // firstCommandArg extracts the first non-flag token from root command arguments.
@@ -185,12 +316,17 @@ func quietFromConfig(cwd string) bool {
 	if err != nil || !found {
 		return false
 	}
-	paths := config.DefaultPaths(rootDir)
-	cfg, err := config.LoadConfigLenient(paths.ConfigPath)
+	return strings.EqualFold(strings.TrimSpace(noiseModeFromConfig(rootDir)), "quiet")
+}
+
+// noiseModeFromConfig returns warnings.noise_mode from root's config, or ""
+// when the config cannot be read.
+func noiseModeFromConfig(root string) string {
+	cfg, err := config.LoadConfigLenient(config.DefaultPaths(root).ConfigPath)
 	if err != nil {
-		return false
+		return ""
 	}
-	return strings.EqualFold(strings.TrimSpace(cfg.Warnings.NoiseMode), "quiet")
+	return cfg.Warnings.NoiseMode
 }
 
 func isQuiet(args []string, cwd string) bool {
@@ -201,13 +337,13 @@ func isQuiet(args []string, cwd string) bool {
 func versionString() string {
 	meta := []string{}
 	if Commit != "" && Commit != unknownVersion {
-		meta = append(meta, fmt.Sprintf(messages.VersionCommitFmt, Commit))
+		meta = append(meta, i18n.Sprintf(messages.VersionCommitFmt, Commit))
 	}
 	if BuildDate != "" && BuildDate != unknownVersion {
-		meta = append(meta, fmt.Sprintf(messages.VersionBuildFmt, BuildDate))
+		meta = append(meta, i18n.Sprintf(messages.VersionBuildFmt, BuildDate))
 	}
 	if len(meta) == 0 {
 		return Version
 	}
-	return fmt.Sprintf(messages.VersionFullFmt, Version, strings.Join(meta, ", "))
+	return i18n.Sprintf(messages.VersionFullFmt, Version, strings.Join(meta, ", "))
 }
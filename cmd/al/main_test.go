@@ -3,6 +3,7 @@ package main
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
@@ -16,7 +17,13 @@ import (
 	"testing"
 	"time"
 
+	"github.com/fatih/color"
+
+	"github.com/conn-castle/agent-layer/internal/ci"
+	"github.com/conn-castle/agent-layer/internal/errcode"
+	"github.com/conn-castle/agent-layer/internal/offline"
 	"github.com/conn-castle/agent-layer/internal/probe/antigravity"
+	"github.com/conn-castle/agent-layer/internal/style"
 	"github.com/conn-castle/agent-layer/internal/testutil"
 	"github.com/conn-castle/agent-layer/internal/versiondispatch"
 )
@@ -247,6 +254,7 @@ func TestShouldBypassDispatch(t *testing.T) {
 		{name: "No subcommand", args: []string{"al"}, want: false},
 		{name: "Init command", args: []string{"al", "init"}, want: true},
 		{name: "Upgrade command", args: []string{"al", "upgrade"}, want: true},
+		{name: "Env command", args: []string{"al", "env", "--json"}, want: true},
 		{name: "Non-init command", args: []string{"al", "doctor"}, want: false},
 		{name: "Global version flag only", args: []string{"al", "--version"}, want: false},
 		{name: "Double-dash init", args: []string{"al", "--", "init"}, want: true},
@@ -387,6 +395,231 @@ func TestRunMain_ExecuteWrappedExitErrorPropagatesCode(t *testing.T) {
 	}
 }
 
+func TestRunMain_ErrorFormatJSON(t *testing.T) {
+	origMaybeExec := maybeExecFunc
+	maybeExecFunc = func(args []string, currentVersion string, cwd string, stderr io.Writer, exit func(int)) error {
+		return nil
+	}
+	t.Cleanup(func() { maybeExecFunc = origMaybeExec })
+
+	origExecute := executeFunc
+	executeFunc = func(context.Context, []string, io.Writer, io.Writer) error {
+		return fmt.Errorf("sync failed: %w", errcode.Wrap(errcode.Sync, errors.New("disk full")))
+	}
+	t.Cleanup(func() { executeFunc = origExecute })
+
+	for _, args := range [][]string{
+		{"al", "--error-format", "json", "sync"},
+		{"al", "sync", "--error-format=json"},
+	} {
+		var out bytes.Buffer
+		exitCode := 0
+		runMain(context.Background(), args, &out, &out, func(code int) { exitCode = code })
+		if exitCode != 1 {
+			t.Fatalf("%v: expected exit 1, got %d", args, exitCode)
+		}
+		var got jsonFailure
+		if err := json.Unmarshal(out.Bytes(), &got); err != nil {
+			t.Fatalf("%v: expected JSON output, got %q: %v", args, out.String(), err)
+		}
+		want := jsonFailure{Code: errcode.Sync, Message: "sync failed: disk full", Hint: errcode.Hint(errcode.Sync)}
+		if got != want {
+			t.Fatalf("%v: got %+v, want %+v", args, got, want)
+		}
+	}
+}
+
+func TestRunMain_ErrorFormatJSONUnclassified(t *testing.T) {
+	origMaybeExec := maybeExecFunc
+	maybeExecFunc = func(args []string, currentVersion string, cwd string, stderr io.Writer, exit func(int)) error {
+		return nil
+	}
+	t.Cleanup(func() { maybeExecFunc = origMaybeExec })
+
+	var out bytes.Buffer
+	runMain(context.Background(), []string{"al", "--error-format=json", "unknown"}, &out, &out, func(int) {})
+	if !strings.HasPrefix(out.String(), `{"code":"error","message":"unknown command`) {
+		t.Fatalf("expected unclassified JSON failure, got %q", out.String())
+	}
+}
+
+func TestRunMain_ErrorFormatInvalid(t *testing.T) {
+	var out bytes.Buffer
+	exitCode := 0
+	runMain(context.Background(), []string{"al", "--error-format", "yaml", "sync"}, &out, &out, func(code int) { exitCode = code })
+	if exitCode != 1 {
+		t.Fatalf("expected exit 1, got %d", exitCode)
+	}
+	if !strings.Contains(out.String(), `invalid value for --error-format: "yaml"`) {
+		t.Fatalf("expected invalid format error, got %q", out.String())
+	}
+}
+
+func TestRunMain_OfflineFlag(t *testing.T) {
+	origMaybeExec := maybeExecFunc
+	var dispatchedArgs []string
+	maybeExecFunc = func(args []string, currentVersion string, cwd string, stderr io.Writer, exit func(int)) error {
+		dispatchedArgs = args
+		return nil
+	}
+	t.Cleanup(func() { maybeExecFunc = origMaybeExec })
+	origExecute := executeFunc
+	var executedArgs []string
+	executeFunc = func(_ context.Context, args []string, _ io.Writer, _ io.Writer) error {
+		executedArgs = args
+		return nil
+	}
+	t.Cleanup(func() { executeFunc = origExecute })
+	origSetenv := setenv
+	env := map[string]string{}
+	setenv = func(key, value string) error {
+		env[key] = value
+		return nil
+	}
+	t.Cleanup(func() { setenv = origSetenv })
+	t.Setenv(offline.EnvVar, "")
+
+	var out bytes.Buffer
+	runMain(context.Background(), []string{"al", "--offline", "sync", "--", "--offline"}, &out, &out, func(code int) {
+		t.Fatalf("unexpected exit %d: %s", code, out.String())
+	})
+	want := []string{"al", "sync", "--", "--offline"}
+	if strings.Join(dispatchedArgs, " ") != strings.Join(want, " ") || strings.Join(executedArgs, " ") != strings.Join(want, " ") {
+		t.Fatalf("--offline should be consumed: dispatch %v, execute %v", dispatchedArgs, executedArgs)
+	}
+	if env[offline.EnvVar] != "1" || env[versiondispatch.EnvNoNetwork] != "1" {
+		t.Fatalf("expected AL_OFFLINE and AL_NO_NETWORK to be set, got %v", env)
+	}
+}
+
+func TestRunMain_OfflineFlagInvalid(t *testing.T) {
+	var out bytes.Buffer
+	exitCode := 0
+	runMain(context.Background(), []string{"al", "--offline=maybe", "sync"}, &out, &out, func(code int) { exitCode = code })
+	if exitCode != 1 || !strings.Contains(out.String(), `invalid value for --offline: "maybe"`) {
+		t.Fatalf("expected invalid --offline error, got exit %d: %q", exitCode, out.String())
+	}
+}
+
+func TestRunMain_CIFlag(t *testing.T) {
+	origMaybeExec := maybeExecFunc
+	var dispatchedArgs []string
+	maybeExecFunc = func(args []string, currentVersion string, cwd string, stderr io.Writer, exit func(int)) error {
+		dispatchedArgs = args
+		return nil
+	}
+	t.Cleanup(func() { maybeExecFunc = origMaybeExec })
+	origExecute := executeFunc
+	var executeErr error
+	executeFunc = func(context.Context, []string, io.Writer, io.Writer) error {
+		return executeErr
+	}
+	t.Cleanup(func() { executeFunc = origExecute })
+	origSetenv := setenv
+	env := map[string]string{}
+	setenv = func(key, value string) error {
+		env[key] = value
+		return nil
+	}
+	t.Cleanup(func() { setenv = origSetenv })
+	origNoColor := color.NoColor
+	t.Cleanup(func() { color.NoColor = origNoColor })
+	t.Setenv(ci.EnvVar, "")
+
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{name: "classified", err: fmt.Errorf("sync failed: %w", errcode.Wrap(errcode.Sync, errors.New("disk full"))), want: errcode.ExitCode(errcode.Sync)},
+		{name: "input required", err: errcode.Wrap(errcode.InputRequired, errors.New("needs --yes")), want: errcode.ExitCode(errcode.InputRequired)},
+		{name: "unclassified", err: errors.New("boom"), want: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			executeErr = tt.err
+			color.NoColor = false
+			var out bytes.Buffer
+			exitCode := 0
+			runMain(context.Background(), []string{"al", "--ci", "sync"}, &out, &out, func(code int) { exitCode = code })
+			if exitCode != tt.want {
+				t.Fatalf("exit = %d, want %d", exitCode, tt.want)
+			}
+			if strings.Join(dispatchedArgs, " ") != "al sync" {
+				t.Fatalf("--ci should be consumed, dispatched %v", dispatchedArgs)
+			}
+			if env[ci.EnvVar] != "1" || !color.NoColor {
+				t.Fatalf("expected AL_CI set and colors off, got env %v NoColor %v", env, color.NoColor)
+			}
+		})
+	}
+}
+
+func TestRunMain_ClassifiedErrorExitsOneWithoutCI(t *testing.T) {
+	origMaybeExec := maybeExecFunc
+	maybeExecFunc = func(args []string, currentVersion string, cwd string, stderr io.Writer, exit func(int)) error {
+		return nil
+	}
+	t.Cleanup(func() { maybeExecFunc = origMaybeExec })
+	origExecute := executeFunc
+	executeFunc = func(context.Context, []string, io.Writer, io.Writer) error {
+		return errcode.Wrap(errcode.Config, errors.New("bad config"))
+	}
+	t.Cleanup(func() { executeFunc = origExecute })
+	t.Setenv(ci.EnvVar, "")
+
+	var out bytes.Buffer
+	exitCode := 0
+	runMain(context.Background(), []string{"al", "sync"}, &out, &out, func(code int) { exitCode = code })
+	if exitCode != 1 {
+		t.Fatalf("expected exit 1 outside CI mode, got %d", exitCode)
+	}
+}
+
+func TestRunMain_CIFlagInvalid(t *testing.T) {
+	var out bytes.Buffer
+	exitCode := 0
+	runMain(context.Background(), []string{"al", "--ci=maybe", "sync"}, &out, &out, func(code int) { exitCode = code })
+	if exitCode != 1 || !strings.Contains(out.String(), `invalid value for --ci: "maybe"`) {
+		t.Fatalf("expected invalid --ci error, got exit %d: %q", exitCode, out.String())
+	}
+}
+
+func TestResolveColorMode(t *testing.T) {
+	tests := []struct {
+		name   string
+		flag   string
+		env    string
+		ciMode bool
+		want   style.Mode
+	}{
+		{name: "default", want: style.Auto},
+		{name: "flag", flag: "always", want: style.Always},
+		{name: "env", env: "never", want: style.Never},
+		{name: "flag beats env", flag: "auto", env: "never", want: style.Auto},
+		{name: "ci turns color off", ciMode: true, want: style.Never},
+		{name: "explicit color in ci", flag: "always", ciMode: true, want: style.Always},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(style.EnvVar, tt.env)
+			got, err := resolveColorMode(tt.flag, tt.ciMode)
+			if err != nil || got != tt.want {
+				t.Fatalf("resolveColorMode(%q, %v) = %q, %v; want %q", tt.flag, tt.ciMode, got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestRunMain_ColorFlagInvalid(t *testing.T) {
+	var out bytes.Buffer
+	exitCode := 0
+	runMain(context.Background(), []string{"al", "--color=sometimes", "sync"}, &out, &out, func(code int) { exitCode = code })
+	if exitCode != 1 || !strings.Contains(out.String(), `invalid color mode "sometimes"`) {
+		t.Fatalf("expected invalid --color error, got exit %d: %q", exitCode, out.String())
+	}
+}
+
 func TestRunMainCancellationReachesContextAwareCommand(t *testing.T) {
 	root := t.TempDir()
 	if err := os.MkdirAll(filepath.Join(root, ".agent-layer"), 0o700); err != nil {
@@ -0,0 +1,262 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"runtime"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/spf13/cobra"
+
+	"github.com/conn-castle/agent-layer/internal/config"
+	"github.com/conn-castle/agent-layer/internal/errcode"
+	"github.com/conn-castle/agent-layer/internal/fsutil"
+	"github.com/conn-castle/agent-layer/internal/i18n"
+	"github.com/conn-castle/agent-layer/internal/lockfile"
+	"github.com/conn-castle/agent-layer/internal/mcpgateway"
+	"github.com/conn-castle/agent-layer/internal/mcppin"
+	"github.com/conn-castle/agent-layer/internal/messages"
+	"github.com/conn-castle/agent-layer/internal/projection"
+	"github.com/conn-castle/agent-layer/internal/style"
+	"github.com/conn-castle/agent-layer/internal/warnings"
+	"github.com/conn-castle/agent-layer/internal/wizard"
+)
+
+var (
+	mcpStatus       = warnings.MCPStatus
+	serveMCPGateway = mcpgateway.Serve
+	mcpGatewayStdio = func() mcp.Transport { return &mcp.StdioTransport{} }
+	addMCPServer    = wizard.AddMCPServer
+	prefetchMCP     = func(ctx context.Context, root string, servers []config.MCPServer) ([]mcppin.Result, error) {
+		return mcppin.Prefetch(ctx, mcppin.RealSystem{}, root, servers)
+	}
+)
+
+func newMcpCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   messages.McpUse,
+		Short: messages.McpShort,
+	}
+	cmd.AddCommand(newMcpStatusCmd(), newMcpGatewayCmd(), newMcpAddCmd(), newMcpPrefetchCmd())
+	return cmd
+}
+
+func newMcpStatusCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   messages.McpStatusUse,
+		Short: messages.McpStatusShort,
+		Long:  messages.McpStatusLong,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			root, err := resolveRepoRoot()
+			if err != nil {
+				return err
+			}
+			cfg, err := config.LoadProjectConfig(root)
+			if err != nil {
+				return err
+			}
+			statuses, err := mcpStatus(cmd.Context(), cfg, nil, nil)
+			if err != nil {
+				return err
+			}
+			out := cmd.OutOrStdout()
+			if len(statuses) == 0 {
+				_, err := fmt.Fprintln(out, i18n.T(messages.McpStatusNoServers))
+				return err
+			}
+			failed := 0
+			for _, status := range statuses {
+				if status.Err != nil {
+					failed++
+					if _, err := fmt.Fprintf(out, i18n.T(messages.McpStatusFailFmt), style.Failure(messages.McpStatusFailLabel), status.ID, status.Transport, status.Err); err != nil {
+						return err
+					}
+					continue
+				}
+				if _, err := fmt.Fprintf(out, i18n.T(messages.McpStatusOKFmt), style.Success(messages.McpStatusOKLabel), status.ID, status.Transport, mcpServerLabel(status), status.Tools, status.SchemaTokens); err != nil {
+					return err
+				}
+			}
+			if failed > 0 {
+				return errcode.Wrap(errcode.MCP, i18n.Errorf(messages.McpStatusFailedFmt, failed, len(statuses)))
+			}
+			return nil
+		},
+	}
+}
+
+func newMcpGatewayCmd() *cobra.Command {
+	var client string
+	cmd := &cobra.Command{
+		Use:   messages.McpGatewayUse,
+		Short: messages.McpGatewayShort,
+		Long:  messages.McpGatewayLong,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			root, err := resolveRepoRoot()
+			if err != nil {
+				return err
+			}
+			cfg, err := config.LoadProjectConfig(root)
+			if err != nil {
+				return err
+			}
+			lock, err := lockfile.Load(root)
+			if err != nil {
+				return err
+			}
+			// Resolve from the configured servers, never ClientMCPServers: that
+			// would return the gateway itself. Locked npx/uvx servers launch
+			// pinned, like the client configs sync writes.
+			configured, _ := mcppin.Apply(cfg.Config.MCP.Servers, lock.MCPServers)
+			var servers []projection.ResolvedMCPServer
+			if client == "" {
+				servers, err = projection.ResolveEnabledMCPServers(configured, cfg.Env)
+			} else {
+				servers, err = projection.ResolveMCPServers(configured, cfg.Env, client, projection.FullValueResolver(cfg.Env))
+			}
+			if err != nil {
+				return err
+			}
+			// Stdout carries the MCP protocol; warnings must go to stderr.
+			return errcode.Wrap(errcode.MCP, serveMCPGateway(cmd.Context(), servers, mcpGatewayStdio(), mcpgateway.Options{
+				Version:   Version,
+				Warnings:  commandOutput(cmd, cfg.Config.Warnings.NoiseMode).Info(),
+				Project:   cfg,
+				AuditRoot: root,
+				Client:    client,
+			}))
+		},
+	}
+	cmd.Flags().StringVar(&client, "client", "", messages.McpGatewayFlagClient)
+	_ = cmd.RegisterFlagCompletionFunc("client", completeMCPClients)
+	return cmd
+}
+
+func newMcpAddCmd() *cobra.Command {
+	var wait bool
+	cmd := &cobra.Command{
+		Use:               messages.McpAddUse,
+		Short:             messages.McpAddShort,
+		Long:              messages.McpAddLong,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeMCPRegistryIDs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			root, err := resolveRepoRoot()
+			if err != nil {
+				return err
+			}
+			lock, err := lockRepoState(cmd.ErrOrStderr(), root, wait)
+			if err != nil {
+				return err
+			}
+			defer func() { _ = lock.Release() }()
+			configPath := config.DefaultPaths(root).ConfigPath
+			info, err := os.Stat(configPath)
+			if err != nil {
+				return errcode.Wrap(errcode.Config, i18n.Errorf(messages.ConfigMissingFileFmt, configPath, err))
+			}
+			data, err := os.ReadFile(configPath)
+			if err != nil {
+				return err
+			}
+			updated, entry, err := addMCPServer(string(data), args[0], runtime.GOOS)
+			if err != nil {
+				return errcode.Wrap(errcode.Config, err)
+			}
+			if err := fsutil.WriteFileAtomic(configPath, []byte(updated), info.Mode().Perm()); err != nil {
+				return err
+			}
+			out := cmd.OutOrStdout()
+			if _, err := fmt.Fprintf(out, i18n.T(messages.McpAddResultFmt), entry.ID, entry.Description, configPath); err != nil {
+				return err
+			}
+			if entry.Package != "" {
+				if _, err := fmt.Fprintf(out, i18n.T(messages.McpAddPinnedFmt), entry.Package, entry.Version); err != nil {
+					return err
+				}
+			}
+			for _, name := range entry.RequiredEnv {
+				if _, err := fmt.Fprintf(out, i18n.T(messages.McpAddEnvFmt), name); err != nil {
+					return err
+				}
+			}
+			_, err = fmt.Fprintln(out, i18n.T(messages.McpAddSyncHint))
+			return err
+		},
+	}
+	cmd.Flags().BoolVar(&wait, "wait", false, messages.StateLockFlagWait)
+	return cmd
+}
+
+func newMcpPrefetchCmd() *cobra.Command {
+	var wait bool
+	cmd := &cobra.Command{
+		Use:   messages.McpPrefetchUse,
+		Short: messages.McpPrefetchShort,
+		Long:  messages.McpPrefetchLong,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			root, err := resolveRepoRoot()
+			if err != nil {
+				return err
+			}
+			lock, err := lockRepoState(cmd.ErrOrStderr(), root, wait)
+			if err != nil {
+				return err
+			}
+			defer func() { _ = lock.Release() }()
+			cfg, err := config.LoadProjectConfig(root)
+			if err != nil {
+				return err
+			}
+			results, err := prefetchMCP(cmd.Context(), root, cfg.Config.MCP.Servers)
+			if err != nil {
+				return err
+			}
+			out := cmd.OutOrStdout()
+			if len(results) == 0 {
+				_, err := fmt.Fprintln(out, i18n.T(messages.McpPrefetchNoServers))
+				return err
+			}
+			failed := 0
+			for _, result := range results {
+				if result.Err != nil {
+					failed++
+					if _, err := fmt.Fprintf(out, i18n.T(messages.McpPrefetchFailFmt), result.ID, result.Err); err != nil {
+						return err
+					}
+					continue
+				}
+				if _, err := fmt.Fprintf(out, i18n.T(messages.McpPrefetchOKFmt), result.ID, result.Launch.Spec(result.Version)); err != nil {
+					return err
+				}
+			}
+			if _, err := fmt.Fprintf(out, i18n.T(messages.McpPrefetchLockedFmt), len(results)-failed, len(results), lockfile.Path(root)); err != nil {
+				return err
+			}
+			if failed > 0 {
+				return errcode.Wrap(errcode.MCP, i18n.Errorf(messages.McpPrefetchFailedFmt, failed, len(results)))
+			}
+			return nil
+		},
+	}
+	cmd.Flags().BoolVar(&wait, "wait", false, messages.StateLockFlagWait)
+	return cmd
+}
+
+// mcpServerLabel describes the server implementation reported during the
+// MCP handshake.
+func mcpServerLabel(status warnings.MCPServerStatus) string {
+	switch {
+	case status.ServerName == "" && status.ServerVersion == "":
+		return messages.McpStatusVersionUnknown
+	case status.ServerName == "":
+		return status.ServerVersion
+	case status.ServerVersion == "":
+		return i18n.Sprintf(messages.McpStatusVersionFmt, status.ServerName, messages.McpStatusVersionUnknown)
+	}
+	return i18n.Sprintf(messages.McpStatusVersionFmt, status.ServerName, status.ServerVersion)
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"github.com/conn-castle/agent-layer/internal/i18n"
 	"github.com/conn-castle/agent-layer/internal/messages"
 )
 
@@ -14,7 +15,7 @@ func newMcpPromptsCmd() *cobra.Command {
 		Short:  messages.McpPromptsShort,
 		Hidden: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			_, _ = fmt.Fprintln(cmd.ErrOrStderr(), messages.McpPromptsDeprecated)
+			_, _ = fmt.Fprintln(commandOutput(cmd, "").Info(), i18n.T(messages.McpPromptsDeprecated))
 			return nil
 		},
 	}
@@ -0,0 +1,258 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/conn-castle/agent-layer/internal/config"
+	"github.com/conn-castle/agent-layer/internal/mcpgateway"
+	"github.com/conn-castle/agent-layer/internal/mcppin"
+	"github.com/conn-castle/agent-layer/internal/projection"
+	"github.com/conn-castle/agent-layer/internal/warnings"
+)
+
+func stubMcpStatus(t *testing.T, statuses []warnings.MCPServerStatus) {
+	t.Helper()
+	original := mcpStatus
+	mcpStatus = func(context.Context, *config.ProjectConfig, warnings.Connector, warnings.MCPDiscoveryStatusFunc) ([]warnings.MCPServerStatus, error) {
+		return statuses, nil
+	}
+	t.Cleanup(func() { mcpStatus = original })
+}
+
+func TestMcpStatusCmd(t *testing.T) {
+	root := stubRepoRoot(t)
+	writeTestRepo(t, root)
+	stubMcpStatus(t, []warnings.MCPServerStatus{
+		{ID: "github", Transport: "stdio", ServerName: "github-mcp", ServerVersion: "1.2.0", Tools: 12, SchemaTokens: 3400},
+		{ID: "docs", Transport: "http", Tools: 1, SchemaTokens: 80},
+		{ID: "slack", Transport: "stdio", Err: errors.New("exec: \"slack-mcp\": executable file not found")},
+	})
+
+	cmd := newMcpCmd()
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs([]string{"status"})
+	err := cmd.Execute()
+	if err == nil || err.Error() != "1 of 3 MCP servers failed" {
+		t.Fatalf("expected failure summary, got %v", err)
+	}
+	for _, want := range []string{
+		"ok    github (stdio) github-mcp 1.2.0: 12 tools, ~3400 schema tokens",
+		"ok    docs (http) version unknown: 1 tools, ~80 schema tokens",
+		"FAIL  slack (stdio): exec: \"slack-mcp\": executable file not found",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Fatalf("expected %q in output:\n%s", want, out.String())
+		}
+	}
+}
+
+func TestMcpStatusCmd_NoServers(t *testing.T) {
+	root := stubRepoRoot(t)
+	writeTestRepo(t, root)
+	stubMcpStatus(t, nil)
+
+	cmd := newMcpCmd()
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"status"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("mcp status: %v", err)
+	}
+	if !strings.Contains(out.String(), "No MCP servers are enabled.") {
+		t.Fatalf("unexpected output:\n%s", out.String())
+	}
+}
+
+func TestMcpServerLabel(t *testing.T) {
+	cases := []struct {
+		status warnings.MCPServerStatus
+		want   string
+	}{
+		{warnings.MCPServerStatus{}, "version unknown"},
+		{warnings.MCPServerStatus{ServerName: "srv"}, "srv version unknown"},
+		{warnings.MCPServerStatus{ServerVersion: "2.0"}, "2.0"},
+		{warnings.MCPServerStatus{ServerName: "srv", ServerVersion: "2.0"}, "srv 2.0"},
+	}
+	for _, tc := range cases {
+		if got := mcpServerLabel(tc.status); got != tc.want {
+			t.Fatalf("mcpServerLabel(%+v) = %q, want %q", tc.status, got, tc.want)
+		}
+	}
+}
+
+func TestMcpGatewayCmd(t *testing.T) {
+	root := stubRepoRoot(t)
+	writeTestRepo(t, root)
+	paths := config.DefaultPaths(root)
+	servers := `
+[mcp]
+gateway = true
+
+[[mcp.servers]]
+id = "shared"
+enabled = true
+transport = "stdio"
+command = "shared-mcp"
+env = { TOKEN = "${AL_TOKEN}" }
+
+[[mcp.servers]]
+id = "codex-only"
+enabled = true
+transport = "stdio"
+command = "codex-mcp"
+clients = ["codex"]
+`
+	file, err := os.OpenFile(paths.ConfigPath, os.O_APPEND|os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := file.WriteString(servers); err != nil {
+		t.Fatal(err)
+	}
+	if err := file.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(paths.EnvPath, []byte("AL_TOKEN=secret\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var gotIDs []string
+	var gotEnv map[string]string
+	var gotOpts mcpgateway.Options
+	originalServe := serveMCPGateway
+	serveMCPGateway = func(_ context.Context, servers []projection.ResolvedMCPServer, _ mcp.Transport, opts mcpgateway.Options) error {
+		gotIDs = nil
+		for _, server := range servers {
+			gotIDs = append(gotIDs, server.ID)
+		}
+		gotEnv = servers[0].Env
+		gotOpts = opts
+		return nil
+	}
+	t.Cleanup(func() { serveMCPGateway = originalServe })
+
+	run := func(args ...string) {
+		t.Helper()
+		cmd := newMcpCmd()
+		cmd.SetOut(&bytes.Buffer{})
+		cmd.SetErr(&bytes.Buffer{})
+		cmd.SetArgs(append([]string{"gateway"}, args...))
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("mcp gateway %v: %v", args, err)
+		}
+	}
+
+	run("--client", "claude")
+	if !reflect.DeepEqual(gotIDs, []string{"shared"}) {
+		t.Fatalf("claude servers = %v", gotIDs)
+	}
+	if gotEnv["TOKEN"] != "secret" {
+		t.Fatalf("expected resolved secret, got %v", gotEnv)
+	}
+	if gotOpts.Version != Version || gotOpts.Warnings == nil {
+		t.Fatalf("unexpected options: %+v", gotOpts)
+	}
+
+	run()
+	if !reflect.DeepEqual(gotIDs, []string{"shared", "codex-only"}) {
+		t.Fatalf("all servers = %v", gotIDs)
+	}
+}
+
+func TestMcpAddCmd(t *testing.T) {
+	root := stubRepoRoot(t)
+	configPath := config.DefaultPaths(root).ConfigPath
+	if err := os.WriteFile(configPath, []byte("[mcp]\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := newMcpCmd()
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"add", "context7"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("add: %v", err)
+	}
+	for _, want := range []string{
+		"Added MCP server context7 (Context7 docs/code lookup)",
+		"Pinned: @upstash/context7-mcp@2.1.1",
+		"Set AL_CONTEXT7_API_KEY in .agent-layer/.env",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Fatalf("expected %q in output:\n%s", want, out.String())
+		}
+	}
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(data), "[mcp]\n\n[[mcp.servers]]\n") || !strings.Contains(string(data), "id = \"context7\"\nenabled = true\n") {
+		t.Fatalf("unexpected config:\n%s", data)
+	}
+	if info, err := os.Stat(configPath); err != nil || info.Mode().Perm() != 0o600 {
+		t.Fatalf("config mode not preserved: %v %v", info, err)
+	}
+
+	cmd = newMcpCmd()
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs([]string{"add", "context7"})
+	if err := cmd.Execute(); err == nil || !strings.Contains(err.Error(), "already defines") {
+		t.Fatalf("expected duplicate error, got %v", err)
+	}
+}
+
+func TestMcpPrefetchCmd(t *testing.T) {
+	root := stubRepoRoot(t)
+	writeTestRepo(t, root)
+
+	var results []mcppin.Result
+	original := prefetchMCP
+	prefetchMCP = func(context.Context, string, []config.MCPServer) ([]mcppin.Result, error) {
+		return results, nil
+	}
+	t.Cleanup(func() { prefetchMCP = original })
+
+	run := func() (string, error) {
+		cmd := newMcpCmd()
+		var out bytes.Buffer
+		cmd.SetOut(&out)
+		cmd.SetErr(&bytes.Buffer{})
+		cmd.SetArgs([]string{"prefetch"})
+		err := cmd.Execute()
+		return out.String(), err
+	}
+
+	out, err := run()
+	if err != nil || !strings.Contains(out, "No enabled MCP servers are launched through npx or uvx.") {
+		t.Fatalf("no servers: %q %v", out, err)
+	}
+
+	results = []mcppin.Result{
+		{ID: "playwright", Launch: mcppin.Launch{Runner: mcppin.RunnerNPX, Package: "@playwright/mcp"}, Version: "0.0.68"},
+		{ID: "fetch", Launch: mcppin.Launch{Runner: mcppin.RunnerUVX, Package: "mcp-server-fetch"}, Err: errors.New("resolution failed")},
+	}
+	out, err = run()
+	if err == nil || !strings.Contains(err.Error(), "1 of 2 MCP servers failed to prefetch") {
+		t.Fatalf("expected failure error, got %v", err)
+	}
+	for _, want := range []string{
+		"ok    playwright: @playwright/mcp@0.0.68",
+		"FAIL  fetch: resolution failed",
+		"Recorded 1 of 2 servers in",
+	} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("expected %q in output:\n%s", want, out)
+		}
+	}
+}
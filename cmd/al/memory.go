@@ -0,0 +1,149 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/conn-castle/agent-layer/internal/i18n"
+	"github.com/conn-castle/agent-layer/internal/memory"
+	"github.com/conn-castle/agent-layer/internal/messages"
+)
+
+var (
+	addMemoryEntry     = memory.Add
+	listMemoryEntries  = memory.List
+	resolveMemoryEntry = memory.Resolve
+)
+
+func newMemoryCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   messages.MemoryUse,
+		Short: messages.MemoryShort,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Help()
+		},
+	}
+	cmd.AddCommand(newMemoryAddCmd(), newMemoryListCmd(), newMemoryResolveCmd())
+	return cmd
+}
+
+func newMemoryAddCmd() *cobra.Command {
+	opts := memory.AddOptions{Values: make(map[string]string)}
+	values := map[string]*string{
+		memory.FieldDescription: new(string),
+		memory.FieldNextStep:    new(string),
+		memory.FieldAcceptance:  new(string),
+		memory.FieldNotes:       new(string),
+		memory.FieldDecision:    new(string),
+		memory.FieldReason:      new(string),
+		memory.FieldTradeoffs:   new(string),
+	}
+
+	cmd := &cobra.Command{
+		Use:       messages.MemoryAddUse,
+		Short:     messages.MemoryAddShort,
+		Long:      messages.MemoryAddLong,
+		Args:      cobra.ExactArgs(2),
+		ValidArgs: memory.Types,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			root, err := resolveRepoRoot()
+			if err != nil {
+				return err
+			}
+			opts.Root, opts.Type, opts.Title = root, args[0], args[1]
+			for key, value := range values {
+				opts.Values[key] = *value
+			}
+			entry, err := addMemoryEntry(opts)
+			if err != nil {
+				return err
+			}
+			_, err = fmt.Fprintf(cmd.OutOrStdout(), i18n.T(messages.MemoryAddedFmt), entry.ID, entry.Path)
+			return err
+		},
+	}
+	flags := cmd.Flags()
+	flags.StringVar(&opts.Priority, "priority", "", messages.MemoryFlagPriority)
+	flags.StringVar(&opts.Area, "area", "", messages.MemoryFlagArea)
+	flags.StringVar(values[memory.FieldDescription], "description", "", messages.MemoryFlagDescription)
+	flags.StringVar(values[memory.FieldNextStep], "next-step", "", messages.MemoryFlagNextStep)
+	flags.StringVar(values[memory.FieldAcceptance], "acceptance", "", messages.MemoryFlagAcceptance)
+	flags.StringVar(values[memory.FieldNotes], "notes", "", messages.MemoryFlagNotes)
+	flags.StringVar(values[memory.FieldDecision], "decision", "", messages.MemoryFlagDecision)
+	flags.StringVar(values[memory.FieldReason], "reason", "", messages.MemoryFlagReason)
+	flags.StringVar(values[memory.FieldTradeoffs], "tradeoffs", "", messages.MemoryFlagTradeoffs)
+	return cmd
+}
+
+func newMemoryListCmd() *cobra.Command {
+	var (
+		typ    string
+		asJSON bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   messages.MemoryListUse,
+		Short: messages.MemoryListShort,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			root, err := resolveRepoRoot()
+			if err != nil {
+				return err
+			}
+			entries, err := listMemoryEntries(root, typ)
+			if err != nil {
+				return err
+			}
+			out := cmd.OutOrStdout()
+			if asJSON {
+				if entries == nil {
+					entries = []memory.Entry{}
+				}
+				encoder := json.NewEncoder(out)
+				encoder.SetIndent("", "  ")
+				return encoder.Encode(entries)
+			}
+			if len(entries) == 0 {
+				_, err := fmt.Fprintln(out, i18n.T(messages.MemoryListEmpty))
+				return err
+			}
+			for _, entry := range entries {
+				if _, err := fmt.Fprintf(out, i18n.T(messages.MemoryListEntryFmt), entry.Type, entry.Date, entry.ID, entry.Title); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&typ, "type", "", messages.MemoryFlagType)
+	cmd.Flags().BoolVar(&asJSON, "json", false, messages.MemoryListJSONFlag)
+	return cmd
+}
+
+func newMemoryResolveCmd() *cobra.Command {
+	var typ string
+
+	cmd := &cobra.Command{
+		Use:   messages.MemoryResolveUse,
+		Short: messages.MemoryResolveShort,
+		Long:  messages.MemoryResolveLong,
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			root, err := resolveRepoRoot()
+			if err != nil {
+				return err
+			}
+			entry, err := resolveMemoryEntry(root, typ, args[0])
+			if err != nil {
+				return err
+			}
+			_, err = fmt.Fprintf(cmd.OutOrStdout(), i18n.T(messages.MemoryResolvedFmt), entry.ID, entry.Path)
+			return err
+		},
+	}
+	cmd.Flags().StringVar(&typ, "type", "", messages.MemoryFlagType)
+	return cmd
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/conn-castle/agent-layer/internal/memory"
+)
+
+func TestMemoryAddCmd(t *testing.T) {
+	root := stubRepoRoot(t)
+	original := addMemoryEntry
+	var got memory.AddOptions
+	addMemoryEntry = func(opts memory.AddOptions) (memory.Entry, error) {
+		got = opts
+		return memory.Entry{ID: "flaky-test", Path: "docs/agent-layer/ISSUES.md"}, nil
+	}
+	t.Cleanup(func() { addMemoryEntry = original })
+
+	cmd := newMemoryCmd()
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"add", "issue", "Flaky test", "--priority", "High", "--description", "Fails", "--next-step", "Pin seed"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("add: %v", err)
+	}
+	if got.Root != root || got.Type != "issue" || got.Title != "Flaky test" || got.Priority != "High" ||
+		got.Values[memory.FieldDescription] != "Fails" || got.Values[memory.FieldNextStep] != "Pin seed" || got.Values[memory.FieldReason] != "" {
+		t.Fatalf("unexpected options %#v", got)
+	}
+	if out.String() != "Added flaky-test to docs/agent-layer/ISSUES.md\n" {
+		t.Fatalf("unexpected output %q", out.String())
+	}
+}
+
+func TestMemoryListAndResolveCmd(t *testing.T) {
+	stubRepoRoot(t)
+	originalList, originalResolve := listMemoryEntries, resolveMemoryEntry
+	var gotType, gotID string
+	listMemoryEntries = func(root string, typ string) ([]memory.Entry, error) {
+		gotType = typ
+		return []memory.Entry{{Type: memory.TypeDecision, Date: "2026-01-02", ID: "use-toml", Title: "Use TOML"}}, nil
+	}
+	resolveMemoryEntry = func(root string, typ string, id string) (memory.Entry, error) {
+		gotType, gotID = typ, id
+		return memory.Entry{ID: id, Path: "docs/agent-layer/DECISIONS.md"}, nil
+	}
+	t.Cleanup(func() { listMemoryEntries, resolveMemoryEntry = originalList, originalResolve })
+
+	cmd := newMemoryCmd()
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"list", "--type", "decision"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if gotType != "decision" || out.String() != "decision 2026-01-02 use-toml: Use TOML\n" {
+		t.Fatalf("list type %q output %q", gotType, out.String())
+	}
+
+	out.Reset()
+	cmd = newMemoryCmd()
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"list", "--json"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("list --json: %v", err)
+	}
+	if !strings.Contains(out.String(), `"id": "use-toml"`) {
+		t.Fatalf("unexpected JSON %s", out.String())
+	}
+
+	out.Reset()
+	cmd = newMemoryCmd()
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"resolve", "use-toml", "--type", "decision"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("resolve: %v", err)
+	}
+	if gotType != "decision" || gotID != "use-toml" || out.String() != "Resolved use-toml in docs/agent-layer/DECISIONS.md\n" {
+		t.Fatalf("resolve type %q id %q output %q", gotType, gotID, out.String())
+	}
+}
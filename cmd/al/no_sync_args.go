@@ -1,13 +1,13 @@
 package main
 
 import (
-	"fmt"
 	"strconv"
 	"strings"
 
 	"github.com/spf13/cobra"
 
 	"github.com/conn-castle/agent-layer/internal/clients"
+	"github.com/conn-castle/agent-layer/internal/i18n"
 	"github.com/conn-castle/agent-layer/internal/messages"
 )
 
@@ -54,6 +54,10 @@ func splitNoSyncArgs(args []string) (bool, bool, []string, error) {
 			passArgs = append(passArgs, args[i+1:]...)
 			break
 		}
+		if width := errorFormatArgWidth(args[i:]); width > 0 {
+			i += width - 1
+			continue
+		}
 		if arg == noSyncFlag {
 			noSync = true
 			continue
@@ -62,7 +66,7 @@ func splitNoSyncArgs(args []string) (bool, bool, []string, error) {
 			value := strings.TrimPrefix(arg, "--no-sync=")
 			parsed, err := strconv.ParseBool(value)
 			if err != nil {
-				return false, false, nil, fmt.Errorf(messages.NoSyncInvalidFmt, value)
+				return false, false, nil, i18n.Errorf(messages.NoSyncInvalidFmt, value)
 			}
 			noSync = parsed
 			continue
@@ -75,7 +79,7 @@ func splitNoSyncArgs(args []string) (bool, bool, []string, error) {
 			value := strings.TrimPrefix(arg, flagQuietPrefix)
 			parsed, err := strconv.ParseBool(value)
 			if err != nil {
-				return false, false, nil, fmt.Errorf(messages.QuietInvalidFmt, value)
+				return false, false, nil, i18n.Errorf(messages.QuietInvalidFmt, value)
 			}
 			quiet = parsed
 			continue
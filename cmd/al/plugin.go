@@ -0,0 +1,87 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/conn-castle/agent-layer/internal/config"
+	"github.com/conn-castle/agent-layer/internal/i18n"
+	"github.com/conn-castle/agent-layer/internal/layerdir"
+	"github.com/conn-castle/agent-layer/internal/messages"
+	"github.com/conn-castle/agent-layer/internal/plugin"
+)
+
+var lookupPluginCommand = plugin.Lookup
+
+// runPluginCommand runs a plugin command with the caller's stdio.
+var runPluginCommand = func(ctx context.Context, path string, args []string, env []string, stdin io.Reader, stdout io.Writer, stderr io.Writer) error {
+	child := exec.CommandContext(ctx, path, args...) // #nosec G204 -- the user named the plugin on the command line.
+	child.Stdin = stdin
+	child.Stdout = stdout
+	child.Stderr = stderr
+	child.Env = env
+	return child.Run()
+}
+
+// maybeRunPlugin runs `al <name> [args...]` as the al-<name> plugin when
+// <name> is not a built-in command. It reports false, leaving cobra to report
+// the unknown command, when no plugin by that name exists. A plugin's exit
+// code passes through.
+func maybeRunPlugin(ctx context.Context, root *cobra.Command, args []string, stdout io.Writer, stderr io.Writer) (bool, error) {
+	if len(args) < 2 || !config.ValidPluginName(args[1]) {
+		return false, nil
+	}
+	name := args[1]
+	root.InitDefaultHelpCmd()
+	root.InitDefaultCompletionCmd()
+	if found, _, err := root.Find(args[1:]); err == nil && found != root {
+		return false, nil
+	}
+
+	repoRoot, paths := pluginRepoContext()
+	path, err := lookupPluginCommand(repoRoot, name, paths)
+	if err != nil {
+		if _, configured := paths[name]; configured {
+			return true, err
+		}
+		return false, nil
+	}
+	env := os.Environ()
+	if repoRoot != "" {
+		env = append(env, plugin.EnvRepoRoot+"="+repoRoot)
+	}
+	err = runPluginCommand(ctx, path, args[2:], env, os.Stdin, stdout, stderr)
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		code := exitErr.ExitCode()
+		if code <= 0 {
+			code = 1
+		}
+		return true, &SilentExitError{Code: code}
+	}
+	if err != nil {
+		return true, i18n.Errorf(messages.PluginRunFailedFmt, name, err)
+	}
+	return true, nil
+}
+
+// pluginRepoContext returns the repo root and its plugins.paths when `al`
+// runs inside a repo. Plugin commands also run outside one, so a missing
+// repo or unreadable config leaves them empty.
+func pluginRepoContext() (string, map[string]string) {
+	repoRoot, err := resolveRepoRoot()
+	if err != nil {
+		return "", nil
+	}
+	cfg, err := config.LoadConfigLenient(filepath.Join(layerdir.Dir(repoRoot), "config.toml"))
+	if err != nil {
+		return repoRoot, nil
+	}
+	return repoRoot, cfg.Plugins.Paths
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os/exec"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/conn-castle/agent-layer/internal/plugin"
+	"github.com/conn-castle/agent-layer/internal/testutil"
+)
+
+func stubPluginCommand(t *testing.T, lookup func(string, string, map[string]string) (string, error), run func(context.Context, string, []string, []string, io.Reader, io.Writer, io.Writer) error) {
+	t.Helper()
+	originalLookup, originalRun := lookupPluginCommand, runPluginCommand
+	t.Cleanup(func() { lookupPluginCommand, runPluginCommand = originalLookup, originalRun })
+	lookupPluginCommand = lookup
+	runPluginCommand = run
+}
+
+func TestMaybeRunPlugin_RunsPluginWithArgs(t *testing.T) {
+	var gotPath string
+	var gotArgs, gotEnv []string
+	stubPluginCommand(t,
+		func(_ string, name string, _ map[string]string) (string, error) { return "/bin/al-" + name, nil },
+		func(_ context.Context, path string, args []string, env []string, _ io.Reader, stdout io.Writer, _ io.Writer) error {
+			gotPath, gotArgs, gotEnv = path, args, env
+			_, err := io.WriteString(stdout, "hello from zed\n")
+			return err
+		})
+
+	root := t.TempDir()
+	writeTestRepo(t, root)
+	var out bytes.Buffer
+	var handled bool
+	var err error
+	testutil.WithWorkingDir(t, root, func() {
+		handled, err = maybeRunPlugin(context.Background(), newRootCmd(), []string{"al", "zed", "lint", "--fix"}, &out, io.Discard)
+	})
+	if !handled || err != nil {
+		t.Fatalf("maybeRunPlugin = %v, %v", handled, err)
+	}
+	if gotPath != "/bin/al-zed" || !slices.Equal(gotArgs, []string{"lint", "--fix"}) {
+		t.Fatalf("ran %s %v", gotPath, gotArgs)
+	}
+	if !slices.ContainsFunc(gotEnv, func(kv string) bool { return strings.HasPrefix(kv, plugin.EnvRepoRoot+"=") }) {
+		t.Fatalf("env missing %s", plugin.EnvRepoRoot)
+	}
+	if out.String() != "hello from zed\n" {
+		t.Fatalf("stdout = %q", out.String())
+	}
+}
+
+func TestMaybeRunPlugin_SkipsBuiltinsAndMissingPlugins(t *testing.T) {
+	ran := false
+	stubPluginCommand(t,
+		func(string, string, map[string]string) (string, error) { return "", errors.New("not found") },
+		func(context.Context, string, []string, []string, io.Reader, io.Writer, io.Writer) error {
+			ran = true
+			return nil
+		})
+	for _, args := range [][]string{
+		{"al"},
+		{"al", "sync"},
+		{"al", "help"},
+		{"al", "--version"},
+		{"al", "zed"},
+	} {
+		handled, err := maybeRunPlugin(context.Background(), newRootCmd(), args, io.Discard, io.Discard)
+		if handled || err != nil {
+			t.Fatalf("maybeRunPlugin(%v) = %v, %v", args, handled, err)
+		}
+	}
+	if ran {
+		t.Fatalf("expected no plugin to run")
+	}
+}
+
+func TestMaybeRunPlugin_PassesExitCode(t *testing.T) {
+	exitErr := exec.Command("sh", "-c", "exit 4").Run()
+	stubPluginCommand(t,
+		func(_ string, name string, _ map[string]string) (string, error) { return "/bin/al-" + name, nil },
+		func(context.Context, string, []string, []string, io.Reader, io.Writer, io.Writer) error {
+			return exitErr
+		})
+
+	handled, err := maybeRunPlugin(context.Background(), newRootCmd(), []string{"al", "zed"}, io.Discard, io.Discard)
+	var silent *SilentExitError
+	if !handled || !errors.As(err, &silent) || silent.Code != 4 {
+		t.Fatalf("maybeRunPlugin = %v, %v; want exit code 4", handled, err)
+	}
+}
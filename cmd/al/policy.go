@@ -0,0 +1,70 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/conn-castle/agent-layer/internal/config"
+	"github.com/conn-castle/agent-layer/internal/errcode"
+	"github.com/conn-castle/agent-layer/internal/i18n"
+	"github.com/conn-castle/agent-layer/internal/messages"
+	"github.com/conn-castle/agent-layer/internal/policy"
+)
+
+var (
+	loadPolicy        = policy.Load
+	loadPolicyProject = config.LoadProjectConfig
+)
+
+func newPolicyCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   messages.PolicyUse,
+		Short: messages.PolicyShort,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Help()
+		},
+	}
+	cmd.AddCommand(newPolicyCheckCmd())
+	return cmd
+}
+
+func newPolicyCheckCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   messages.PolicyCheckUse,
+		Short: messages.PolicyCheckShort,
+		Long:  messages.PolicyCheckLong,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			root, err := resolveRepoRoot()
+			if err != nil {
+				return err
+			}
+			contentPolicy, err := loadPolicy(root)
+			if err != nil {
+				return errcode.Wrap(errcode.Config, err)
+			}
+			out := cmd.OutOrStdout()
+			if contentPolicy == nil {
+				_, err := fmt.Fprintln(out, i18n.T(messages.PolicyCheckNoPolicy))
+				return err
+			}
+			project, err := loadPolicyProject(root)
+			if err != nil {
+				return errcode.Wrap(errcode.Config, err)
+			}
+			violations := policy.Check(contentPolicy, project)
+			if len(violations) == 0 {
+				_, err := fmt.Fprintf(out, i18n.T(messages.PolicyCheckCleanFmt), policy.Path(root))
+				return err
+			}
+			for _, violation := range violations {
+				if _, err := fmt.Fprintf(out, i18n.T(messages.PolicyCheckIssueFmt), violation.Rule, violation.Subject, violation.Message); err != nil {
+					return err
+				}
+			}
+			return errcode.Wrap(errcode.Config, i18n.Errorf(messages.PolicyCheckFailedFmt, len(violations)))
+		},
+	}
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/conn-castle/agent-layer/internal/config"
+	"github.com/conn-castle/agent-layer/internal/errcode"
+)
+
+func TestPolicyCheckCmd(t *testing.T) {
+	root := stubRepoRoot(t)
+	if err := os.WriteFile(filepath.Join(root, ".agent-layer", "policy.toml"), []byte("banned_phrases = [\"yolo\"]\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	original := loadPolicyProject
+	loadPolicyProject = func(string) (*config.ProjectConfig, error) {
+		return &config.ProjectConfig{Root: root, Instructions: []config.InstructionFile{{Name: "00_base.md", Content: "just YOLO it\n"}}}, nil
+	}
+	t.Cleanup(func() { loadPolicyProject = original })
+
+	cmd := newPolicyCmd()
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs([]string{"check"})
+	err := cmd.Execute()
+	if err == nil || err.Error() != "policy check found 1 violation(s)" || errcode.Of(err) != errcode.Config {
+		t.Fatalf("expected policy failure, got %v", err)
+	}
+	if !strings.Contains(out.String(), `banned_phrase         .agent-layer/instructions/00_base.md: contains banned phrase "yolo" (line 1)`) {
+		t.Fatalf("unexpected output:\n%s", out.String())
+	}
+
+	loadPolicyProject = func(string) (*config.ProjectConfig, error) {
+		return &config.ProjectConfig{Root: root}, nil
+	}
+	out.Reset()
+	cmd = newPolicyCmd()
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"check"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("check: %v", err)
+	}
+	if !strings.HasSuffix(out.String(), "policy.toml: no violations\n") {
+		t.Fatalf("unexpected output %q", out.String())
+	}
+}
+
+func TestPolicyCheckCmd_NoPolicy(t *testing.T) {
+	stubRepoRoot(t)
+	cmd := newPolicyCmd()
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"check"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("check: %v", err)
+	}
+	if out.String() != "No .agent-layer/policy.toml; nothing to check.\n" {
+		t.Fatalf("unexpected output %q", out.String())
+	}
+}
@@ -2,11 +2,12 @@ package main
 
 import (
 	"encoding/json"
-	"fmt"
 	"path/filepath"
 
 	"github.com/spf13/cobra"
 
+	"github.com/conn-castle/agent-layer/internal/i18n"
+	"github.com/conn-castle/agent-layer/internal/layerdir"
 	"github.com/conn-castle/agent-layer/internal/messages"
 	probeantigravity "github.com/conn-castle/agent-layer/internal/probe/antigravity"
 )
@@ -34,7 +35,7 @@ func newProbeAntigravityCmd() *cobra.Command {
 			if err != nil {
 				return err
 			}
-			result, err := runAntigravityProbe(cmd.Context(), filepath.Join(root, ".agent-layer", "tmp"))
+			result, err := runAntigravityProbe(cmd.Context(), filepath.Join(layerdir.Dir(root), "tmp"))
 			if err != nil {
 				return err
 			}
@@ -47,7 +48,7 @@ func newProbeAntigravityCmd() *cobra.Command {
 			// reported an internal error. Keep the JSON on stdout so callers
 			// piping into jq still get the full machine-readable output.
 			if result.ExitCode != 0 || result.Error != "" {
-				return fmt.Errorf(messages.ProbeAntigravityNonZeroExitFmt, result.ExitCode, result.Error)
+				return i18n.Errorf(messages.ProbeAntigravityNonZeroExitFmt, result.ExitCode, result.Error)
 			}
 			return nil
 		},
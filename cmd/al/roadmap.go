@@ -0,0 +1,176 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/conn-castle/agent-layer/internal/i18n"
+	"github.com/conn-castle/agent-layer/internal/memory"
+	"github.com/conn-castle/agent-layer/internal/messages"
+)
+
+var (
+	roadmapStatus   = memory.RoadmapStatus
+	addRoadmapPhase = memory.AddPhase
+	completePhase   = memory.CompletePhase
+)
+
+func newRoadmapCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   messages.RoadmapUse,
+		Short: messages.RoadmapShort,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Help()
+		},
+	}
+	cmd.AddCommand(newRoadmapStatusCmd(), newRoadmapAddPhaseCmd(), newRoadmapCompleteCmd())
+	return cmd
+}
+
+func newRoadmapStatusCmd() *cobra.Command {
+	var asJSON bool
+
+	cmd := &cobra.Command{
+		Use:   messages.RoadmapStatusUse,
+		Short: messages.RoadmapStatusShort,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			root, err := resolveRepoRoot()
+			if err != nil {
+				return err
+			}
+			phases, err := roadmapStatus(root)
+			if err != nil {
+				return err
+			}
+			out := cmd.OutOrStdout()
+			if asJSON {
+				if phases == nil {
+					phases = []memory.Phase{}
+				}
+				encoder := json.NewEncoder(out)
+				encoder.SetIndent("", "  ")
+				return encoder.Encode(phases)
+			}
+			if len(phases) == 0 {
+				_, err := fmt.Fprintln(out, i18n.T(messages.RoadmapStatusEmpty))
+				return err
+			}
+			archived := 0
+			for _, phase := range phases {
+				if phase.Archived {
+					archived++
+				}
+			}
+			if archived > 0 {
+				if _, err := fmt.Fprintf(out, i18n.T(messages.RoadmapStatusArchivedFmt), archived); err != nil {
+					return err
+				}
+			}
+			for _, phase := range phases {
+				if phase.Archived {
+					continue
+				}
+				state := messages.RoadmapStatusOpen
+				if phase.Complete {
+					state = messages.RoadmapStatusDone
+				}
+				tasks := ""
+				if !phase.Complete && phase.TasksTotal > 0 {
+					tasks = i18n.Sprintf(messages.RoadmapStatusTasksFmt, phase.TasksDone, phase.TasksTotal)
+				}
+				if _, err := fmt.Fprintf(out, i18n.T(messages.RoadmapStatusPhaseFmt), state, phase.Number, phase.Name, tasks); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+	cmd.Flags().BoolVar(&asJSON, "json", false, messages.RoadmapStatusJSONFlag)
+	return cmd
+}
+
+func newRoadmapAddPhaseCmd() *cobra.Command {
+	var opts memory.AddPhaseOptions
+
+	cmd := &cobra.Command{
+		Use:   messages.RoadmapAddPhaseUse,
+		Short: messages.RoadmapAddPhaseShort,
+		Long:  messages.RoadmapAddPhaseLong,
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			root, err := resolveRepoRoot()
+			if err != nil {
+				return err
+			}
+			opts.Root, opts.Name = root, args[0]
+			phase, err := addRoadmapPhase(opts)
+			if err != nil {
+				return err
+			}
+			_, err = fmt.Fprintf(cmd.OutOrStdout(), i18n.T(messages.RoadmapPhaseAddedFmt), phase.Number, phase.Name)
+			return err
+		},
+	}
+	cmd.Flags().StringArrayVar(&opts.Goals, "goal", nil, messages.RoadmapFlagGoal)
+	cmd.Flags().StringArrayVar(&opts.Tasks, "task", nil, messages.RoadmapFlagTask)
+	cmd.Flags().StringArrayVar(&opts.Exit, "exit", nil, messages.RoadmapFlagExit)
+	return cmd
+}
+
+func newRoadmapCompleteCmd() *cobra.Command {
+	var opts memory.CompletePhaseOptions
+
+	cmd := &cobra.Command{
+		Use:   messages.RoadmapCompleteUse,
+		Short: messages.RoadmapCompleteShort,
+		Long:  messages.RoadmapCompleteLong,
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			number, err := parsePhaseNumber(args[0])
+			if err != nil {
+				return err
+			}
+			root, err := resolveRepoRoot()
+			if err != nil {
+				return err
+			}
+			opts.Root, opts.Number = root, number
+			phase, archived, err := completePhase(opts)
+			if err != nil {
+				return err
+			}
+			out := cmd.OutOrStdout()
+			if _, err := fmt.Fprintf(out, i18n.T(messages.RoadmapPhaseCompletedFmt), phase.Number, phase.Name); err != nil {
+				return err
+			}
+			for _, n := range archived {
+				if _, err := fmt.Fprintf(out, i18n.T(messages.RoadmapPhaseArchivedFmt), n); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringArrayVar(&opts.Summary, "summary", nil, messages.RoadmapFlagSummary)
+	cmd.Flags().BoolVar(&opts.Force, "force", false, messages.RoadmapFlagForce)
+	return cmd
+}
+
+// parsePhaseNumber accepts "17" or "Phase 17".
+func parsePhaseNumber(raw string) (int, error) {
+	value := strings.TrimSpace(raw)
+	if len(value) > len("phase") && strings.EqualFold(value[:len("phase")], "phase") {
+		value = strings.TrimSpace(value[len("phase"):])
+	}
+	number, err := strconv.Atoi(value)
+	if err != nil || number < 1 {
+		return 0, i18n.Errorf(messages.RoadmapInvalidPhaseFmt, raw)
+	}
+	return number, nil
+}
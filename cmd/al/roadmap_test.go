@@ -0,0 +1,87 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/conn-castle/agent-layer/internal/memory"
+)
+
+func TestRoadmapStatusCmd(t *testing.T) {
+	stubRepoRoot(t)
+	original := roadmapStatus
+	roadmapStatus = func(string) ([]memory.Phase, error) {
+		return []memory.Phase{
+			{Number: 1, Name: "Start", Complete: true, Archived: true},
+			{Number: 2, Name: "Quick wins", Complete: true},
+			{Number: 3, Name: "Profiles", TasksDone: 1, TasksTotal: 4},
+		}, nil
+	}
+	t.Cleanup(func() { roadmapStatus = original })
+
+	cmd := newRoadmapCmd()
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"status"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("status: %v", err)
+	}
+	want := "archived 1 phase(s)\n" +
+		"done     Phase 2 — Quick wins\n" +
+		"open     Phase 3 — Profiles (1/4 tasks)\n"
+	if out.String() != want {
+		t.Fatalf("unexpected output %q", out.String())
+	}
+}
+
+func TestRoadmapAddPhaseAndCompleteCmd(t *testing.T) {
+	root := stubRepoRoot(t)
+	originalAdd, originalComplete := addRoadmapPhase, completePhase
+	var gotAdd memory.AddPhaseOptions
+	var gotComplete memory.CompletePhaseOptions
+	addRoadmapPhase = func(opts memory.AddPhaseOptions) (memory.Phase, error) {
+		gotAdd = opts
+		return memory.Phase{Number: 4, Name: opts.Name}, nil
+	}
+	completePhase = func(opts memory.CompletePhaseOptions) (memory.Phase, []int, error) {
+		gotComplete = opts
+		return memory.Phase{Number: opts.Number, Name: "Profiles", Complete: true}, []int{2}, nil
+	}
+	t.Cleanup(func() { addRoadmapPhase, completePhase = originalAdd, originalComplete })
+
+	cmd := newRoadmapCmd()
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"add-phase", "Plugins", "--goal", "g", "--task", "t1", "--task", "t2", "--exit", "e"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("add-phase: %v", err)
+	}
+	if gotAdd.Root != root || gotAdd.Name != "Plugins" || len(gotAdd.Tasks) != 2 || len(gotAdd.Goals) != 1 || len(gotAdd.Exit) != 1 {
+		t.Fatalf("unexpected add options %#v", gotAdd)
+	}
+	if out.String() != "Added Phase 4 — Plugins\n" {
+		t.Fatalf("unexpected output %q", out.String())
+	}
+
+	out.Reset()
+	cmd = newRoadmapCmd()
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"complete", "Phase 3", "--summary", "Shipped profiles.", "--force"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("complete: %v", err)
+	}
+	if gotComplete.Number != 3 || !gotComplete.Force || len(gotComplete.Summary) != 1 {
+		t.Fatalf("unexpected complete options %#v", gotComplete)
+	}
+	if out.String() != "Completed Phase 3 — Profiles\nArchived Phase 2\n" {
+		t.Fatalf("unexpected output %q", out.String())
+	}
+
+	cmd = newRoadmapCmd()
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs([]string{"complete", "next"})
+	if err := cmd.Execute(); err == nil {
+		t.Fatal("expected an invalid phase error")
+	}
+}
@@ -6,6 +6,8 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/conn-castle/agent-layer/internal/messages"
+	"github.com/conn-castle/agent-layer/internal/output"
+	"github.com/conn-castle/agent-layer/internal/style"
 )
 
 func newRootCmd() *cobra.Command {
@@ -28,12 +30,23 @@ func newRootCmd() *cobra.Command {
 
 	root.Flags().Bool("version", false, messages.RootVersionFlag)
 	root.PersistentFlags().BoolP("quiet", "q", false, messages.RootQuietFlag)
+	root.PersistentFlags().BoolP("verbose", "v", false, messages.RootVerboseFlag)
+	root.MarkFlagsMutuallyExclusive("quiet", "verbose")
+	root.PersistentFlags().String("error-format", errorFormatText, messages.RootErrorFormatFlag)
+	// main consumes --offline before cobra runs; it is declared for help and completion.
+	root.PersistentFlags().Bool("offline", false, messages.RootOfflineFlag)
+	// main consumes --ci and --color the same way.
+	root.PersistentFlags().Bool("ci", false, messages.RootCIFlag)
+	root.PersistentFlags().String("color", string(style.Auto), messages.RootColorFlag)
+	_ = root.RegisterFlagCompletionFunc("error-format", cobra.FixedCompletions([]string{errorFormatText, errorFormatJSON}, cobra.ShellCompDirectiveNoFileComp))
+	_ = root.RegisterFlagCompletionFunc("color", cobra.FixedCompletions([]string{string(style.Auto), string(style.Always), string(style.Never)}, cobra.ShellCompDirectiveNoFileComp))
 
 	root.AddCommand(
 		newInitCmd(),
 		newUpgradeCmd(),
 		newSyncCmd(),
 		newHookCmd(),
+		newMcpCmd(),
 		newMcpPromptsCmd(),
 		newProbeCmd(),
 		newDispatchCmd(),
@@ -45,7 +58,37 @@ func newRootCmd() *cobra.Command {
 		newCopilotCmd(),
 		newDoctorCmd(),
 		newWizardCmd(),
+		newAddCmd(),
+		newUpdateCmd(),
+		newVerifyCmd(),
+		newDiffCmd(),
+		newExecCmd(),
+		newAuditCmd(),
+		newTranscriptsCmd(),
+		newMemoryCmd(),
+		newRoadmapCmd(),
+		newPolicyCmd(),
+		newDevcontainerCmd(),
+		newServeCmd(),
+		newEnvCmd(),
+		newCleanCmd(),
+		newUninstallCmd(),
+		newBaselineCmd(),
+		newExportCmd(),
+		newExportConfigCmd(),
+		newImportConfigCmd(),
+		newImportCmd(),
+		newConfigCmd(),
 	)
 	addPlatformCommands(root)
+	addDevCommands(root)
 	return root
 }
+
+// commandOutput returns the output writer for cmd at the level its root
+// --quiet and --verbose flags and the config warnings.noise_mode select.
+func commandOutput(cmd *cobra.Command, noiseMode string) *output.Writer {
+	quiet, _ := cmd.Flags().GetBool("quiet")
+	verbose, _ := cmd.Flags().GetBool("verbose")
+	return output.New(cmd.OutOrStdout(), cmd.ErrOrStderr(), output.ResolveLevel(quiet, verbose, noiseMode))
+}
@@ -12,6 +12,8 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/conn-castle/agent-layer/internal/config"
+	"github.com/conn-castle/agent-layer/internal/messages"
+	"github.com/conn-castle/agent-layer/internal/sync"
 	"github.com/conn-castle/agent-layer/internal/testutil"
 	"github.com/conn-castle/agent-layer/internal/versiondispatch"
 )
@@ -270,6 +272,79 @@ func TestSyncCommand_QuietSuppressesWarnings(t *testing.T) {
 	})
 }
 
+func TestSyncCommand_PrintsClientFeatureSupport(t *testing.T) {
+	root := t.TempDir()
+	writeTestRepo(t, root)
+	binDir := t.TempDir()
+	testutil.WriteStub(t, binDir, "al")
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	testutil.WithWorkingDir(t, root, func() {
+		cmd := newSyncCmd()
+		var stderr bytes.Buffer
+		cmd.SetErr(&stderr)
+		if err := cmd.RunE(cmd, nil); err != nil {
+			t.Fatalf("sync: %v", err)
+		}
+		out := stderr.String()
+		if !strings.Contains(out, messages.SyncDegradationsHeader) ||
+			!strings.Contains(out, "  codex: slash commands not supported, projected as $<name> skill mentions") {
+			t.Fatalf("expected client feature summary, got %q", out)
+		}
+	})
+}
+
+func TestSyncCommand_EditedGeneratedFile(t *testing.T) {
+	root := t.TempDir()
+	writeTestRepo(t, root)
+	binDir := t.TempDir()
+	testutil.WriteStub(t, binDir, "al")
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	testutil.WithWorkingDir(t, root, func() {
+		first := newSyncCmd()
+		first.SetErr(&bytes.Buffer{})
+		if err := first.RunE(first, nil); err != nil {
+			t.Fatalf("sync: %v", err)
+		}
+		claudePath := filepath.Join(root, "CLAUDE.md")
+		generated, err := os.ReadFile(claudePath)
+		if err != nil {
+			t.Fatalf("read CLAUDE.md: %v", err)
+		}
+		if err := os.WriteFile(claudePath, append(append([]byte{}, generated...), "local tweak\n"...), 0o600); err != nil {
+			t.Fatalf("edit CLAUDE.md: %v", err)
+		}
+
+		cmd := newSyncCmd()
+		if err := cmd.Flags().Set("diff", "true"); err != nil {
+			t.Fatalf("set diff flag: %v", err)
+		}
+		var stdout, stderr bytes.Buffer
+		cmd.SetOut(&stdout)
+		cmd.SetErr(&stderr)
+		if err := cmd.RunE(cmd, nil); !errors.Is(err, ErrSyncCompletedWithWarnings) {
+			t.Fatalf("expected warnings for the edited file, got %v", err)
+		}
+		if !strings.Contains(stdout.String(), "CLAUDE.md (edited)") || !strings.Contains(stdout.String(), "-local tweak") {
+			t.Fatalf("expected diff for CLAUDE.md, got %q", stdout.String())
+		}
+		if !strings.Contains(stderr.String(), "GENERATED_FILE_EDITED") {
+			t.Fatalf("expected edited-file warning, got %q", stderr.String())
+		}
+
+		forced := newSyncCmd()
+		forced.SetErr(&bytes.Buffer{})
+		if err := forced.Flags().Set("force", "true"); err != nil {
+			t.Fatalf("set force flag: %v", err)
+		}
+		if err := forced.RunE(forced, nil); err != nil {
+			t.Fatalf("forced sync: %v", err)
+		}
+		if data, _ := os.ReadFile(claudePath); string(data) != string(generated) {
+			t.Fatalf("expected --force to regenerate CLAUDE.md")
+		}
+	})
+}
+
 func TestWizardCommand(t *testing.T) {
 	originalIsTerminal := isTerminal
 	isTerminal = func() bool { return false }
@@ -364,3 +439,231 @@ func TestInitCommandPromptError(t *testing.T) {
 		t.Fatalf("unexpected error: %v", err)
 	}
 }
+
+func TestSyncCommand_OutputRoot(t *testing.T) {
+	root := t.TempDir()
+	writeTestRepo(t, root)
+	binDir := t.TempDir()
+	testutil.WriteStub(t, binDir, "al")
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	dest := filepath.Join(t.TempDir(), "preview")
+	testutil.WithWorkingDir(t, root, func() {
+		cmd := newSyncCmd()
+		var stdout bytes.Buffer
+		cmd.SetOut(&stdout)
+		cmd.SetErr(&bytes.Buffer{})
+		if err := cmd.Flags().Set("output-root", dest); err != nil {
+			t.Fatalf("set output-root: %v", err)
+		}
+		if err := cmd.RunE(cmd, nil); err != nil {
+			t.Fatalf("sync --output-root: %v", err)
+		}
+		if !strings.Contains(stdout.String(), "generated files under "+dest) {
+			t.Fatalf("unexpected output: %q", stdout.String())
+		}
+		if _, err := os.Stat(filepath.Join(dest, "AGENTS.md")); err != nil {
+			t.Fatalf("expected AGENTS.md under output root: %v", err)
+		}
+		if _, err := os.Stat(filepath.Join(root, "AGENTS.md")); !os.IsNotExist(err) {
+			t.Fatalf("working tree must not be written, stat err %v", err)
+		}
+
+		same := newSyncCmd()
+		if err := same.Flags().Set("output-root", "."); err != nil {
+			t.Fatalf("set output-root: %v", err)
+		}
+		if err := same.RunE(same, nil); err == nil || err.Error() != messages.SyncOutputRootIsRepo {
+			t.Fatalf("expected repo-root rejection, got %v", err)
+		}
+	})
+}
+
+func TestSyncCommand_PrintChanges(t *testing.T) {
+	root := t.TempDir()
+	writeTestRepo(t, root)
+	binDir := t.TempDir()
+	testutil.WriteStub(t, binDir, "al")
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	testutil.WithWorkingDir(t, root, func() {
+		cmd := newSyncCmd()
+		var stdout bytes.Buffer
+		cmd.SetOut(&stdout)
+		cmd.SetErr(&bytes.Buffer{})
+		if err := cmd.Flags().Set("print-changes", "true"); err != nil {
+			t.Fatalf("set print-changes: %v", err)
+		}
+		if err := cmd.RunE(cmd, nil); err != nil {
+			t.Fatalf("sync --print-changes: %v", err)
+		}
+		if !strings.Contains(stdout.String(), "write      AGENTS.md\n") {
+			t.Fatalf("expected planned AGENTS.md write, got %q", stdout.String())
+		}
+		if _, err := os.Stat(filepath.Join(root, "AGENTS.md")); !os.IsNotExist(err) {
+			t.Fatalf("--print-changes must not write, stat err %v", err)
+		}
+
+		both := newSyncCmd()
+		_ = both.Flags().Set("print-changes", "true")
+		_ = both.Flags().Set("output-root", t.TempDir())
+		if err := both.RunE(both, nil); err == nil || err.Error() != messages.SyncPrintChangesOutputRoot {
+			t.Fatalf("expected flag conflict error, got %v", err)
+		}
+	})
+}
+
+func TestSyncCommand_SummaryOnly(t *testing.T) {
+	root := t.TempDir()
+	writeTestRepo(t, root)
+	binDir := t.TempDir()
+	testutil.WriteStub(t, binDir, "al")
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	testutil.WithWorkingDir(t, root, func() {
+		cmd := newSyncCmd()
+		var stdout bytes.Buffer
+		cmd.SetOut(&stdout)
+		cmd.SetErr(&bytes.Buffer{})
+		_ = cmd.Flags().Set("summary-only", "true")
+		if err := cmd.RunE(cmd, nil); err != nil {
+			t.Fatalf("sync --summary-only: %v", err)
+		}
+		got := stdout.String()
+		if !strings.HasPrefix(got, messages.SyncSummaryDryRunHeader+"\n") || !strings.Contains(got, "  shared: ") {
+			t.Fatalf("unexpected summary: %q", got)
+		}
+		if _, err := os.Stat(filepath.Join(root, "AGENTS.md")); !os.IsNotExist(err) {
+			t.Fatalf("--summary-only must not write, stat err %v", err)
+		}
+
+		apply := newSyncCmd()
+		var stderr bytes.Buffer
+		apply.SetOut(&bytes.Buffer{})
+		apply.SetErr(&stderr)
+		if err := apply.RunE(apply, nil); err != nil {
+			t.Fatalf("sync: %v", err)
+		}
+		if !strings.Contains(stderr.String(), messages.SyncSummaryHeader+"\n") {
+			t.Fatalf("expected summary after sync, got %q", stderr.String())
+		}
+
+		both := newSyncCmd()
+		_ = both.Flags().Set("summary-only", "true")
+		_ = both.Flags().Set("check", "true")
+		if err := both.RunE(both, nil); err == nil || err.Error() != messages.SyncSummaryOnlyConflictsFlags {
+			t.Fatalf("expected flag conflict error, got %v", err)
+		}
+	})
+}
+
+func TestWriteSyncSummary(t *testing.T) {
+	summaries := []sync.ClientSummary{
+		{Unchanged: []string{"AGENTS.md"}},
+		{Client: "claude", Written: []string{".mcp.json"}, Deleted: []string{".claude/skills/old"}},
+	}
+	var out bytes.Buffer
+	if err := writeSyncSummary(&out, messages.SyncSummaryHeader, summaries, false); err != nil {
+		t.Fatalf("writeSyncSummary: %v", err)
+	}
+	want := "Sync summary:\n  shared: 0 written, 1 unchanged, 0 deleted\n  claude: 1 written, 0 unchanged, 1 deleted\n"
+	if out.String() != want {
+		t.Fatalf("summary = %q, want %q", out.String(), want)
+	}
+
+	out.Reset()
+	if err := writeSyncSummary(&out, messages.SyncSummaryHeader, summaries, true); err != nil {
+		t.Fatalf("writeSyncSummary verbose: %v", err)
+	}
+	for _, line := range []string{"    unchanged AGENTS.md\n", "    written   .mcp.json\n", "    deleted   .claude/skills/old\n"} {
+		if !strings.Contains(out.String(), line) {
+			t.Fatalf("verbose summary missing %q: %q", line, out.String())
+		}
+	}
+
+	out.Reset()
+	if err := writeSyncSummary(&out, messages.SyncSummaryHeader, nil, true); err != nil || out.Len() != 0 {
+		t.Fatalf("empty summary wrote %q, err %v", out.String(), err)
+	}
+}
+
+func TestSyncCommand_Check(t *testing.T) {
+	root := t.TempDir()
+	writeTestRepo(t, root)
+	binDir := t.TempDir()
+	testutil.WriteStub(t, binDir, "al")
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	testutil.WithWorkingDir(t, root, func() {
+		check := func() (string, error) {
+			cmd := newSyncCmd()
+			var stderr bytes.Buffer
+			cmd.SetOut(&bytes.Buffer{})
+			cmd.SetErr(&stderr)
+			_ = cmd.Flags().Set("check", "true")
+			err := cmd.RunE(cmd, nil)
+			return stderr.String(), err
+		}
+		if got, err := check(); err == nil || !strings.Contains(got, "  - AGENTS.md\n") {
+			t.Fatalf("check before sync = %v, stderr %q", err, got)
+		}
+		if _, err := os.Stat(filepath.Join(root, "AGENTS.md")); !os.IsNotExist(err) {
+			t.Fatalf("--check must not write, stat err %v", err)
+		}
+		apply := newSyncCmd()
+		apply.SetOut(&bytes.Buffer{})
+		apply.SetErr(&bytes.Buffer{})
+		if err := apply.RunE(apply, nil); err != nil {
+			t.Fatalf("sync: %v", err)
+		}
+		if got, err := check(); err != nil || !strings.Contains(got, messages.SyncCheckUpToDate) {
+			t.Fatalf("check after sync = %v, stderr %q", err, got)
+		}
+
+		both := newSyncCmd()
+		_ = both.Flags().Set("check", "true")
+		_ = both.Flags().Set("print-changes", "true")
+		if err := both.RunE(both, nil); err == nil || err.Error() != messages.SyncCheckConflictsFlags {
+			t.Fatalf("expected flag conflict error, got %v", err)
+		}
+	})
+}
+
+func TestSyncCommand_ReportsConfigChanges(t *testing.T) {
+	root := t.TempDir()
+	writeTestRepo(t, root)
+	binDir := t.TempDir()
+	testutil.WriteStub(t, binDir, "al")
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	testutil.WithWorkingDir(t, root, func() {
+		runSync := func() string {
+			cmd := newSyncCmd()
+			var stderr bytes.Buffer
+			cmd.SetOut(&bytes.Buffer{})
+			cmd.SetErr(&stderr)
+			if err := cmd.RunE(cmd, nil); err != nil {
+				t.Fatalf("sync: %v", err)
+			}
+			return stderr.String()
+		}
+		if got := runSync(); strings.Contains(got, "Config changed") {
+			t.Fatalf("first sync must not report config changes, got %q", got)
+		}
+
+		configPath := config.DefaultPaths(root).ConfigPath
+		data, err := os.ReadFile(configPath) // #nosec G304 -- path is constructed from test-controlled inputs.
+		if err != nil {
+			t.Fatalf("read config: %v", err)
+		}
+		updated := strings.Replace(string(data), `mode = "all"`, `mode = "none"`, 1)
+		if err := os.WriteFile(configPath, []byte(updated), 0o600); err != nil {
+			t.Fatalf("write config: %v", err)
+		}
+		got := runSync()
+		if !strings.Contains(got, "Config changed since the last sync: approvals.mode\n") {
+			t.Fatalf("expected changed key, got %q", got)
+		}
+		if !strings.Contains(got, "Generated files updated: ") || !strings.Contains(got, ".claude/settings.json") {
+			t.Fatalf("expected affected files, got %q", got)
+		}
+		if got := runSync(); strings.Contains(got, "Config changed") {
+			t.Fatalf("unchanged config must not report, got %q", got)
+		}
+	})
+}
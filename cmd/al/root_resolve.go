@@ -1,9 +1,10 @@
 package main
 
 import (
-	"fmt"
 	"path/filepath"
 
+	"github.com/conn-castle/agent-layer/internal/errcode"
+	"github.com/conn-castle/agent-layer/internal/i18n"
 	"github.com/conn-castle/agent-layer/internal/messages"
 	"github.com/conn-castle/agent-layer/internal/root"
 )
@@ -32,7 +33,7 @@ func resolveRepoRootAndWorkingDir() (repoRoot string, workingDir string, err err
 		return "", "", err
 	}
 	if !found {
-		return "", "", fmt.Errorf(messages.RootMissingAgentLayer)
+		return "", "", errcode.Wrap(errcode.Config, i18n.Errorf(messages.RootMissingAgentLayer))
 	}
 	return repoRoot, cwd, nil
 }
@@ -48,7 +49,7 @@ func resolveInitRoot(here bool) (root string, cwd string, err error) {
 	}
 	absCwd, err := filepath.Abs(wd)
 	if err != nil {
-		return "", "", fmt.Errorf(messages.RootResolvePathFmt, wd, err)
+		return "", "", i18n.Errorf(messages.RootResolvePathFmt, wd, err)
 	}
 	if here {
 		return absCwd, absCwd, nil
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"net"
+
+	"github.com/spf13/cobra"
+
+	"github.com/conn-castle/agent-layer/internal/errcode"
+	"github.com/conn-castle/agent-layer/internal/messages"
+	"github.com/conn-castle/agent-layer/internal/serve"
+)
+
+// defaultServeAddr is the loopback address al serve listens on by default.
+const defaultServeAddr = "127.0.0.1:7878"
+
+func newServeCmd() *cobra.Command {
+	var listen string
+	var gateway bool
+
+	cmd := &cobra.Command{
+		Use:   messages.ServeUse,
+		Short: messages.ServeShort,
+		Long:  messages.ServeLong,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			root, err := resolveRepoRoot()
+			if err != nil {
+				return err
+			}
+			out := commandOutput(cmd, noiseModeFromConfig(root))
+			listener, err := serve.Listen(listen)
+			if err != nil {
+				return err
+			}
+			defer func() { _ = listener.Close() }()
+			// Any local process can reach a TCP port, so TCP requests need
+			// the session token; a Unix socket is owner-only.
+			var token string
+			if _, unix := listener.Addr().(*net.UnixAddr); !unix {
+				var removeToken func()
+				token, removeToken, err = serve.WriteToken(root)
+				if err != nil {
+					return err
+				}
+				defer removeToken()
+			}
+			handler, closeHandler, err := serve.NewHandler(cmd.Context(), serve.Options{
+				Root:       root,
+				Version:    Version,
+				Gateway:    gateway,
+				Warnings:   out.Info(),
+				HookOutput: out.Info(),
+				Token:      token,
+			})
+			if err != nil {
+				return errcode.Wrap(errcode.Config, err)
+			}
+			defer closeHandler()
+			out.Infof(messages.ServeListeningFmt, listener.Addr())
+			if token != "" {
+				out.Infof(messages.ServeTokenFmt, serve.TokenPath(root))
+			}
+			return serve.Run(cmd.Context(), listener, handler)
+		},
+	}
+	cmd.Flags().StringVar(&listen, "listen", defaultServeAddr, messages.ServeListenFlag)
+	cmd.Flags().BoolVar(&gateway, "gateway", true, messages.ServeGatewayFlag)
+	return cmd
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/conn-castle/agent-layer/internal/serve"
+)
+
+func TestServeCmd_StopsWithContext(t *testing.T) {
+	root := stubRepoRoot(t)
+	cmd := newServeCmd()
+	cmd.SilenceUsage = true
+	var stderr bytes.Buffer
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&stderr)
+	cmd.SetArgs([]string{"--listen", "127.0.0.1:0", "--gateway=false"})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := cmd.ExecuteContext(ctx); err != nil {
+		t.Fatalf("serve: %v", err)
+	}
+	if !strings.Contains(stderr.String(), "al serve listening on 127.0.0.1:") {
+		t.Fatalf("expected listening line, got %q", stderr.String())
+	}
+	if !strings.Contains(stderr.String(), serve.TokenFile) {
+		t.Fatalf("expected token line, got %q", stderr.String())
+	}
+	if _, err := os.Stat(serve.TokenPath(root)); !os.IsNotExist(err) {
+		t.Fatalf("expected token removed on exit, got %v", err)
+	}
+}
+
+func TestServeCmd_RejectsRemoteAddress(t *testing.T) {
+	stubRepoRoot(t)
+	cmd := newServeCmd()
+	cmd.SilenceUsage = true
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs([]string{"--listen", "0.0.0.0:7878", "--gateway=false"})
+	if err := cmd.Execute(); err == nil || !strings.Contains(err.Error(), "refusing to listen") {
+		t.Fatalf("expected loopback refusal, got %v", err)
+	}
+}
@@ -0,0 +1,25 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/conn-castle/agent-layer/internal/i18n"
+	"github.com/conn-castle/agent-layer/internal/messages"
+	"github.com/conn-castle/agent-layer/internal/statelock"
+)
+
+var acquireStateLock = statelock.Acquire
+
+// lockRepoState takes the repo's state lock so concurrent al processes cannot
+// interleave writes. With wait, it notes on out that it is waiting and blocks
+// until the other process finishes.
+func lockRepoState(out io.Writer, root string, wait bool) (*statelock.Lock, error) {
+	lock, err := acquireStateLock(root, false)
+	if errors.Is(err, statelock.ErrHeld) && wait {
+		_, _ = fmt.Fprintf(out, i18n.T(messages.StateLockWaitingFmt), root)
+		lock, err = acquireStateLock(root, true)
+	}
+	return lock, err
+}
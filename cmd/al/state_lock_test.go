@@ -0,0 +1,118 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/conn-castle/agent-layer/internal/clean"
+	"github.com/conn-castle/agent-layer/internal/statelock"
+	"github.com/conn-castle/agent-layer/internal/testutil"
+)
+
+func TestLockRepoState(t *testing.T) {
+	orig := acquireStateLock
+	t.Cleanup(func() { acquireStateLock = orig })
+	var calls []bool
+	acquireStateLock = func(_ string, wait bool) (*statelock.Lock, error) {
+		calls = append(calls, wait)
+		if !wait {
+			return nil, statelock.ErrHeld
+		}
+		return &statelock.Lock{}, nil
+	}
+
+	if _, err := lockRepoState(&bytes.Buffer{}, "/repo", false); !errors.Is(err, statelock.ErrHeld) || len(calls) != 1 {
+		t.Fatalf("without --wait = %v, calls %v", err, calls)
+	}
+
+	calls = nil
+	var out bytes.Buffer
+	if _, err := lockRepoState(&out, "/repo", true); err != nil || len(calls) != 2 || !calls[1] || !strings.Contains(out.String(), "Waiting") {
+		t.Fatalf("with --wait = %v, calls %v, output %q", err, calls, out.String())
+	}
+}
+
+func TestSyncCommand_StateLockHeld(t *testing.T) {
+	root := t.TempDir()
+	writeTestRepo(t, root)
+	held, err := statelock.Acquire(root, false)
+	if err != nil {
+		t.Fatalf("Acquire: %v", err)
+	}
+	t.Cleanup(func() { _ = held.Release() })
+	testutil.WithWorkingDir(t, root, func() {
+		cmd := newSyncCmd()
+		cmd.SetOut(&bytes.Buffer{})
+		cmd.SetErr(&bytes.Buffer{})
+		if err := cmd.RunE(cmd, nil); !errors.Is(err, statelock.ErrHeld) {
+			t.Fatalf("sync with held lock = %v", err)
+		}
+		cmd = newSyncCmd()
+		cmd.SetOut(&bytes.Buffer{})
+		cmd.SetErr(&bytes.Buffer{})
+		_ = cmd.Flags().Set("print-changes", "true")
+		if err := cmd.RunE(cmd, nil); err != nil {
+			t.Fatalf("--print-changes must not take the lock: %v", err)
+		}
+	})
+}
+
+func TestCleanAndSync_Concurrent(t *testing.T) {
+	root := t.TempDir()
+	writeTestRepo(t, root)
+	origPlan, origApply := planClean, applyClean
+	t.Cleanup(func() { planClean, applyClean = origPlan, origApply })
+	planClean = func(string, clean.Options) ([]clean.Entry, error) {
+		return []clean.Entry{{Path: ".mcp.json", Category: clean.CategoryGenerated, Remove: true}}, nil
+	}
+	applying := make(chan struct{})
+	finish := make(chan struct{})
+	applyClean = func(string, []clean.Entry) (int, error) {
+		close(applying)
+		<-finish
+		return 1, nil
+	}
+
+	testutil.WithWorkingDir(t, root, func() {
+		cleanDone := make(chan error, 1)
+		go func() {
+			cmd := newCleanCmd()
+			cmd.SetOut(&bytes.Buffer{})
+			cmd.SetErr(&bytes.Buffer{})
+			_ = cmd.Flags().Set("yes", "true")
+			cleanDone <- cmd.RunE(cmd, nil)
+		}()
+		<-applying
+
+		cmd := newSyncCmd()
+		cmd.SetOut(&bytes.Buffer{})
+		cmd.SetErr(&bytes.Buffer{})
+		if err := cmd.RunE(cmd, nil); !errors.Is(err, statelock.ErrHeld) {
+			t.Fatalf("sync during clean = %v, want the lock held", err)
+		}
+
+		syncDone := make(chan error, 1)
+		go func() {
+			cmd := newSyncCmd()
+			cmd.SetOut(&bytes.Buffer{})
+			cmd.SetErr(&bytes.Buffer{})
+			_ = cmd.Flags().Set("wait", "true")
+			syncDone <- cmd.RunE(cmd, nil)
+		}()
+		select {
+		case err := <-syncDone:
+			t.Fatalf("sync --wait finished while clean held the lock: %v", err)
+		case <-time.After(200 * time.Millisecond):
+		}
+		close(finish)
+		if err := <-cleanDone; err != nil {
+			t.Fatalf("clean: %v", err)
+		}
+		if err := <-syncDone; err != nil {
+			t.Fatalf("sync --wait after clean: %v", err)
+		}
+	})
+}
@@ -1,19 +1,18 @@
 package main
 
 import (
-	"fmt"
-
 	"github.com/spf13/cobra"
 
+	"github.com/conn-castle/agent-layer/internal/i18n"
 	"github.com/conn-castle/agent-layer/internal/messages"
 )
 
 func newStubCmd(name string) *cobra.Command {
 	return &cobra.Command{
 		Use:   name,
-		Short: fmt.Sprintf(messages.StubShortFmt, name),
+		Short: i18n.Sprintf(messages.StubShortFmt, name),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return fmt.Errorf(messages.StubNotImplementedFmt, name)
+			return i18n.Errorf(messages.StubNotImplementedFmt, name)
 		},
 	}
 }
@@ -4,19 +4,24 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"path/filepath"
 	"strings"
 
+	"github.com/aymanbagabas/go-udiff"
 	"github.com/spf13/cobra"
 
 	"github.com/conn-castle/agent-layer/internal/config"
+	"github.com/conn-castle/agent-layer/internal/errcode"
+	"github.com/conn-castle/agent-layer/internal/i18n"
 	"github.com/conn-castle/agent-layer/internal/messages"
+	"github.com/conn-castle/agent-layer/internal/output"
+	"github.com/conn-castle/agent-layer/internal/outputdiff"
 	"github.com/conn-castle/agent-layer/internal/sync"
 	"github.com/conn-castle/agent-layer/internal/updatewarn"
-	"github.com/conn-castle/agent-layer/internal/warnings"
 )
 
 // ErrSyncCompletedWithWarnings is returned when sync completes but warnings were generated.
-var ErrSyncCompletedWithWarnings = errors.New(messages.SyncCompletedWithWarnings)
+var ErrSyncCompletedWithWarnings = i18n.NewError(messages.SyncCompletedWithWarnings)
 
 func newSyncCmd() *cobra.Command {
 	cmd := &cobra.Command{
@@ -28,26 +33,80 @@ func newSyncCmd() *cobra.Command {
 			if err != nil {
 				return err
 			}
-			quietFlag, _ := cmd.Flags().GetBool("quiet")
+			force, _ := cmd.Flags().GetBool("force")
+			showDiff, _ := cmd.Flags().GetBool("diff")
+			outputRoot, _ := cmd.Flags().GetString("output-root")
+			printChanges, _ := cmd.Flags().GetBool("print-changes")
+			check, _ := cmd.Flags().GetBool("check")
+			summaryOnly, _ := cmd.Flags().GetBool("summary-only")
+			wait, _ := cmd.Flags().GetBool("wait")
+			if printChanges && outputRoot != "" {
+				return errors.New(i18n.T(messages.SyncPrintChangesOutputRoot))
+			}
+			if check && (printChanges || outputRoot != "") {
+				return errors.New(i18n.T(messages.SyncCheckConflictsFlags))
+			}
+			if summaryOnly && (printChanges || check || outputRoot != "") {
+				return errors.New(i18n.T(messages.SyncSummaryOnlyConflictsFlags))
+			}
+			dryRun := printChanges || check || summaryOnly
+			if !dryRun && outputRoot == "" {
+				lock, err := lockRepoState(cmd.ErrOrStderr(), root, wait)
+				if err != nil {
+					return err
+				}
+				defer func() { _ = lock.Release() }()
+			}
 			project, err := config.LoadProjectConfig(root)
 			if err != nil {
 				return err
 			}
-			effectiveQuiet := quietFlag || strings.EqualFold(strings.TrimSpace(project.Config.Warnings.NoiseMode), warnings.NoiseModeQuiet)
-			stderr := cmd.ErrOrStderr()
-			if effectiveQuiet {
-				stderr = io.Discard
-			}
+			out := commandOutput(cmd, project.Config.Warnings.NoiseMode)
+			stderr := out.Info()
 			if project.Config.Warnings.VersionUpdateOnSync != nil && *project.Config.Warnings.VersionUpdateOnSync {
 				updatewarn.WarnIfOutdated(cmd.Context(), Version, stderr)
 			}
-			result, err := sync.RunWithProject(sync.RealSystem{}, root, project)
+			var result *sync.Result
+			if outputRoot != "" {
+				result, err = syncToOutputRoot(cmd.OutOrStdout(), root, outputRoot)
+			} else {
+				result, err = sync.RunWithProjectOptions(sync.RealSystem{}, root, project, sync.RunOptions{Force: force, DryRun: dryRun, Output: out, HookOutput: stderr})
+			}
 			if err != nil {
 				return err
 			}
+			if check {
+				return reportStalePaths(stderr, result.StalePaths(root))
+			}
+			verbose := out.Level() == output.Verbose
+			switch {
+			case printChanges:
+				if err := writeChanges(cmd.OutOrStdout(), root, result.Changes); err != nil {
+					return err
+				}
+			case summaryOnly:
+				if err := writeSyncSummary(cmd.OutOrStdout(), messages.SyncSummaryDryRunHeader, result.Summary(root), verbose); err != nil {
+					return err
+				}
+			case outputRoot == "":
+				writeConfigChanges(stderr, result)
+				_ = writeSyncSummary(stderr, messages.SyncSummaryHeader, result.Summary(root), verbose)
+			}
+			if showDiff {
+				if err := writeEditedFileDiffs(cmd.OutOrStdout(), root, result.EditedFiles); err != nil {
+					return err
+				}
+			}
+
+			if len(result.Degradations) > 0 {
+				_, _ = fmt.Fprintln(stderr, i18n.T(messages.SyncDegradationsHeader))
+				for _, d := range result.Degradations {
+					_, _ = fmt.Fprintln(stderr, "  "+d.String())
+				}
+			}
 
 			if len(result.AllWarnings) > 0 {
-				if effectiveQuiet {
+				if out.Level() == output.Quiet {
 					return &SilentExitError{Code: 1}
 				}
 				if len(result.Warnings) > 0 {
@@ -58,11 +117,168 @@ func newSyncCmd() *cobra.Command {
 				}
 			}
 			if project.Config.Approvals.Mode == config.ApprovalModeYOLO {
-				_, _ = fmt.Fprintln(stderr, messages.WarningsPolicyYOLOAck)
+				_, _ = fmt.Fprintln(stderr, i18n.T(messages.WarningsPolicyYOLOAck))
 			}
 			return nil
 		},
 	}
 
+	cmd.Flags().Bool("force", false, messages.SyncFlagForce)
+	cmd.Flags().Bool("diff", false, messages.SyncFlagDiff)
+	cmd.Flags().String("output-root", "", messages.SyncFlagOutputRoot)
+	cmd.Flags().Bool("print-changes", false, messages.SyncFlagPrintChanges)
+	cmd.Flags().Bool("check", false, messages.SyncFlagCheck)
+	cmd.Flags().Bool("summary-only", false, messages.SyncFlagSummaryOnly)
+	cmd.Flags().Bool("wait", false, messages.StateLockFlagWait)
 	return cmd
 }
+
+// checkSyncOutputs runs sync as a dry run and returns the generated files
+// that are out of date.
+func checkSyncOutputs(root string) ([]string, error) {
+	project, err := config.LoadProjectConfig(root)
+	if err != nil {
+		return nil, err
+	}
+	result, err := sync.RunWithProjectOptions(sync.RealSystem{}, root, project, sync.RunOptions{DryRun: true})
+	if err != nil {
+		return nil, err
+	}
+	return result.StalePaths(root), nil
+}
+
+// reportStalePaths lists out-of-date generated files and returns an error
+// when there are any.
+func reportStalePaths(out io.Writer, stale []string) error {
+	if len(stale) == 0 {
+		_, err := io.WriteString(out, i18n.T(messages.SyncCheckUpToDate))
+		return err
+	}
+	_, _ = fmt.Fprintln(out, i18n.T(messages.SyncCheckStaleHeader))
+	for _, path := range stale {
+		_, _ = fmt.Fprintf(out, i18n.T(messages.SyncCheckStalePathFmt), path)
+	}
+	return errcode.Wrap(errcode.Sync, i18n.Errorf(messages.SyncCheckStaleFmt, len(stale)))
+}
+
+// syncToOutputRoot runs sync against a scratch copy of root's sources and
+// writes the generated files under outputRoot, leaving the working tree
+// untouched. Scratch outputs start empty, so no file counts as hand-edited.
+func syncToOutputRoot(out io.Writer, root string, outputRoot string) (*sync.Result, error) {
+	dest, err := filepath.Abs(outputRoot)
+	if err != nil {
+		return nil, err
+	}
+	if dest == root {
+		return nil, errors.New(i18n.T(messages.SyncOutputRootIsRepo))
+	}
+	var result *sync.Result
+	written, err := outputdiff.RenderTo(root, dest, func(dir string) error {
+		project, err := config.LoadProjectConfig(dir)
+		if err != nil {
+			return err
+		}
+		result, err = sync.RunWithProjectOptions(sync.RealSystem{}, dir, project, sync.RunOptions{SourceRoot: root})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if _, err := fmt.Fprintf(out, i18n.T(messages.SyncOutputRootResultFmt), len(written), dest); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// syncSummaryListLimit caps how many items the config change summary names.
+const syncSummaryListLimit = 5
+
+// writeConfigChanges summarizes the config keys that changed since the last
+// sync and the generated files this run changed as a result.
+func writeConfigChanges(out io.Writer, result *sync.Result) {
+	if len(result.ConfigKeys) == 0 {
+		return
+	}
+	_, _ = fmt.Fprintf(out, i18n.T(messages.SyncConfigChangedFmt), summarizeList(result.ConfigKeys))
+	if len(result.ConfigAffected) == 0 {
+		_, _ = io.WriteString(out, i18n.T(messages.SyncConfigNoOutputsAffected))
+		return
+	}
+	_, _ = fmt.Fprintf(out, i18n.T(messages.SyncConfigAffectedFmt), summarizeList(result.ConfigAffected))
+}
+
+// summarizeList joins items with commas, naming at most syncSummaryListLimit.
+func summarizeList(items []string) string {
+	if len(items) <= syncSummaryListLimit {
+		return strings.Join(items, ", ")
+	}
+	return i18n.Sprintf(messages.SyncListMoreFmt, strings.Join(items[:syncSummaryListLimit], ", "), len(items)-syncSummaryListLimit)
+}
+
+// writeSyncSummary prints how many outputs each client's sync steps wrote,
+// left unchanged, and deleted, and with verbose the paths themselves.
+func writeSyncSummary(out io.Writer, header string, summaries []sync.ClientSummary, verbose bool) error {
+	if len(summaries) == 0 {
+		return nil
+	}
+	ew := &errWriter{w: out}
+	ew.println(header)
+	for _, summary := range summaries {
+		client := summary.Client
+		if client == "" {
+			client = messages.SyncSummarySharedLabel
+		}
+		ew.printf(messages.SyncSummaryClientFmt, client, len(summary.Written), len(summary.Unchanged), len(summary.Deleted))
+		if !verbose {
+			continue
+		}
+		for _, group := range []struct {
+			label string
+			paths []string
+		}{
+			{messages.SyncSummaryWrittenLabel, summary.Written},
+			{messages.SyncSummaryUnchangedLabel, summary.Unchanged},
+			{messages.SyncSummaryDeletedLabel, summary.Deleted},
+		} {
+			for _, path := range group.paths {
+				ew.printf(messages.SyncSummaryPathFmt, group.label, path)
+			}
+		}
+	}
+	return ew.err
+}
+
+// writeChanges prints the planned changes one per line as kind and
+// repo-relative path.
+func writeChanges(out io.Writer, root string, changes []sync.Change) error {
+	if len(changes) == 0 {
+		_, err := io.WriteString(out, i18n.T(messages.SyncPrintChangesNone))
+		return err
+	}
+	for _, change := range changes {
+		rel := change.Path
+		if r, err := filepath.Rel(root, change.Path); err == nil {
+			rel = filepath.ToSlash(r)
+		}
+		if _, err := fmt.Fprintf(out, i18n.T(messages.SyncPrintChangeFmt), change.Kind, rel); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeEditedFileDiffs prints a unified diff from each hand-edited generated
+// file to the content sync would write.
+func writeEditedFileDiffs(out io.Writer, root string, edited []sync.EditedFile) error {
+	for _, file := range edited {
+		rel := file.Path
+		if r, err := filepath.Rel(root, file.Path); err == nil {
+			rel = filepath.ToSlash(r)
+		}
+		diff := udiff.Unified(rel+" (edited)", rel+" (generated)", file.Current, file.Generated)
+		if _, err := io.WriteString(out, diff); err != nil {
+			return err
+		}
+	}
+	return nil
+}
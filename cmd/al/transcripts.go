@@ -0,0 +1,62 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/conn-castle/agent-layer/internal/i18n"
+	"github.com/conn-castle/agent-layer/internal/messages"
+	"github.com/conn-castle/agent-layer/internal/transcripts"
+)
+
+var importTranscripts = transcripts.Import
+
+func newTranscriptsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   messages.TranscriptsUse,
+		Short: messages.TranscriptsShort,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Help()
+		},
+	}
+	cmd.AddCommand(newTranscriptsImportCmd())
+	return cmd
+}
+
+func newTranscriptsImportCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:       messages.TranscriptsImportUse,
+		Short:     messages.TranscriptsImportShort,
+		Long:      messages.TranscriptsImportLong,
+		Args:      cobra.ExactArgs(1),
+		ValidArgs: transcripts.Clients,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			root, err := resolveRepoRoot()
+			if err != nil {
+				return err
+			}
+			result, err := importTranscripts(transcripts.Options{Root: root, Client: args[0]})
+			if err != nil {
+				return err
+			}
+			out := cmd.OutOrStdout()
+			if len(result.Sessions) == 0 {
+				_, err := fmt.Fprintf(out, i18n.T(messages.TranscriptsImportNoneFmt), result.Client)
+				return err
+			}
+			unchanged := 0
+			for _, session := range result.Sessions {
+				if session.Status == transcripts.StatusUnchanged {
+					unchanged++
+				}
+				if _, err := fmt.Fprintf(out, i18n.T(messages.TranscriptsImportSessionFmt), session.Status, session.ID, session.Records); err != nil {
+					return err
+				}
+			}
+			_, err = fmt.Fprintf(out, i18n.T(messages.TranscriptsImportSummaryFmt), len(result.Sessions), result.Client, result.Dir, unchanged)
+			return err
+		},
+	}
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/conn-castle/agent-layer/internal/transcripts"
+)
+
+func TestTranscriptsImportCmd(t *testing.T) {
+	root := stubRepoRoot(t)
+	original := importTranscripts
+	var gotOpts transcripts.Options
+	importTranscripts = func(opts transcripts.Options) (*transcripts.Result, error) {
+		gotOpts = opts
+		return &transcripts.Result{
+			Client: transcripts.ClientCodex,
+			Dir:    "/repo/.agent-layer/transcripts/codex",
+			Sessions: []transcripts.SessionResult{
+				{ID: "a", Records: 3, Status: transcripts.StatusWritten},
+				{ID: "b", Records: 5, Status: transcripts.StatusUnchanged},
+			},
+		}, nil
+	}
+	t.Cleanup(func() { importTranscripts = original })
+
+	cmd := newTranscriptsCmd()
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"import", "codex"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("import: %v", err)
+	}
+	if gotOpts.Root != root || gotOpts.Client != "codex" {
+		t.Fatalf("unexpected options %#v", gotOpts)
+	}
+	for _, want := range []string{
+		"written   a (3 records)",
+		"unchanged b (5 records)",
+		"Imported 2 codex session(s) into /repo/.agent-layer/transcripts/codex (1 unchanged).",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Fatalf("expected %q in output:\n%s", want, out.String())
+		}
+	}
+}
+
+func TestTranscriptsImportCmd_NoSessions(t *testing.T) {
+	stubRepoRoot(t)
+	original := importTranscripts
+	importTranscripts = func(opts transcripts.Options) (*transcripts.Result, error) {
+		return &transcripts.Result{Client: opts.Client}, nil
+	}
+	t.Cleanup(func() { importTranscripts = original })
+
+	cmd := newTranscriptsCmd()
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"import", "claude"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("import: %v", err)
+	}
+	if out.String() != "No claude sessions found for this repo.\n" {
+		t.Fatalf("unexpected output %q", out.String())
+	}
+}
+
+func TestTranscriptsImportCmd_Error(t *testing.T) {
+	stubRepoRoot(t)
+	original := importTranscripts
+	importTranscripts = func(transcripts.Options) (*transcripts.Result, error) {
+		return nil, errors.New("boom")
+	}
+	t.Cleanup(func() { importTranscripts = original })
+
+	cmd := newTranscriptsCmd()
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs([]string{"import", "gemini"})
+	if err := cmd.Execute(); err == nil || err.Error() != "boom" {
+		t.Fatalf("expected import error, got %v", err)
+	}
+}
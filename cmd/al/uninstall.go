@@ -0,0 +1,87 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/conn-castle/agent-layer/internal/clean"
+	"github.com/conn-castle/agent-layer/internal/errcode"
+	"github.com/conn-castle/agent-layer/internal/i18n"
+	"github.com/conn-castle/agent-layer/internal/messages"
+)
+
+var (
+	planUninstall  = clean.PlanUninstall
+	applyUninstall = clean.ApplyUninstall
+)
+
+func newUninstallCmd() *cobra.Command {
+	var removeLayer, dryRun, yes, force, wait bool
+	cmd := &cobra.Command{
+		Use:   messages.UninstallUse,
+		Short: messages.UninstallShort,
+		Long:  messages.UninstallLong,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			root, err := resolveRepoRoot()
+			if err != nil {
+				return err
+			}
+			if !dryRun {
+				lock, err := lockRepoState(cmd.ErrOrStderr(), root, wait)
+				if err != nil {
+					return err
+				}
+				defer func() { _ = lock.Release() }()
+			}
+			entries, err := planUninstall(root, clean.UninstallOptions{RemoveLayer: removeLayer, Force: force})
+			if err != nil {
+				return err
+			}
+			out := cmd.OutOrStdout()
+			if len(entries) == 0 {
+				_, err := fmt.Fprintln(out, i18n.T(messages.UninstallNothing))
+				return err
+			}
+			removals, err := writeCleanPlan(out, entries)
+			if err != nil {
+				return err
+			}
+			if removals == 0 || dryRun {
+				return nil
+			}
+			if !yes {
+				if !isTerminal() {
+					return errcode.Wrap(errcode.InputRequired, errors.New(i18n.T(messages.UninstallNeedsYes)))
+				}
+				confirmed, err := promptYesNo(cmd.InOrStdin(), out, messages.UninstallConfirmPrompt, false)
+				if err != nil {
+					return err
+				}
+				if !confirmed {
+					_, err := fmt.Fprintln(out, i18n.T(messages.CleanCancelled))
+					return err
+				}
+			}
+			removed, err := applyUninstall(root, entries)
+			if err != nil {
+				return err
+			}
+			if _, err := fmt.Fprintf(out, i18n.T(messages.UninstallResultFmt), removed); err != nil {
+				return err
+			}
+			if !removeLayer {
+				_, err = fmt.Fprintln(out, i18n.T(messages.UninstallLayerHint))
+			}
+			return err
+		},
+	}
+	cmd.Flags().BoolVar(&removeLayer, "remove-layer", false, messages.UninstallFlagRemoveLayer)
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, messages.UninstallFlagDryRun)
+	cmd.Flags().BoolVar(&yes, "yes", false, messages.UninstallFlagYes)
+	cmd.Flags().BoolVar(&force, "force", false, messages.UninstallFlagForce)
+	cmd.Flags().BoolVar(&wait, "wait", false, messages.StateLockFlagWait)
+	return cmd
+}
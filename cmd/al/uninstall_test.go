@@ -0,0 +1,72 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/conn-castle/agent-layer/internal/clean"
+)
+
+func TestUninstallCmd(t *testing.T) {
+	stubRepoRoot(t)
+	entries := []clean.Entry{
+		{Path: ".gitignore", Category: clean.CategoryGit, Remove: true, Reason: "managed agent-layer block"},
+		{Path: "docs/agent-layer/", Category: clean.CategoryLayer, Reason: "project memory"},
+	}
+	originalPlan, originalApply := planUninstall, applyUninstall
+	var gotOpts clean.UninstallOptions
+	applied := false
+	planUninstall = func(_ string, opts clean.UninstallOptions) ([]clean.Entry, error) {
+		gotOpts = opts
+		return entries, nil
+	}
+	applyUninstall = func(string, []clean.Entry) (int, error) {
+		applied = true
+		return 1, nil
+	}
+	t.Cleanup(func() { planUninstall, applyUninstall = originalPlan, originalApply })
+
+	cases := []struct {
+		name        string
+		args        []string
+		wantOpts    clean.UninstallOptions
+		wantApplied bool
+		wantErr     string
+		wantOut     string
+	}{
+		{name: "yes", args: []string{"--yes"}, wantApplied: true, wantOut: "--remove-layer"},
+		{name: "remove layer", args: []string{"--remove-layer", "--force", "--yes"}, wantOpts: clean.UninstallOptions{RemoveLayer: true, Force: true}, wantApplied: true},
+		{name: "dry run", args: []string{"--dry-run"}, wantOut: "Will remove:"},
+		{name: "non-interactive", args: nil, wantErr: "--yes"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			applied = false
+			originalTerminal := isTerminal
+			isTerminal = func() bool { return false }
+			t.Cleanup(func() { isTerminal = originalTerminal })
+
+			cmd := newUninstallCmd()
+			var out bytes.Buffer
+			cmd.SetOut(&out)
+			cmd.SetArgs(tc.args)
+			err := cmd.Execute()
+			if tc.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Fatalf("err = %v, want %q", err, tc.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("uninstall: %v", err)
+			}
+			if gotOpts != tc.wantOpts || applied != tc.wantApplied {
+				t.Fatalf("opts=%+v applied=%v", gotOpts, applied)
+			}
+			if !strings.Contains(out.String(), tc.wantOut) {
+				t.Fatalf("output %q missing %q", out.String(), tc.wantOut)
+			}
+		})
+	}
+}
@@ -12,8 +12,11 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/conn-castle/agent-layer/internal/config"
+	"github.com/conn-castle/agent-layer/internal/errcode"
+	"github.com/conn-castle/agent-layer/internal/i18n"
 	"github.com/conn-castle/agent-layer/internal/install"
 	"github.com/conn-castle/agent-layer/internal/messages"
+	"github.com/conn-castle/agent-layer/internal/style"
 	"github.com/conn-castle/agent-layer/internal/versiondispatch"
 )
 
@@ -28,31 +31,51 @@ func newUpgradeCmd() *cobra.Command {
 	var applyTmpDeletions bool
 	var diffLines int
 	var pinVersion string
+	var maxRisk string
+	var answersPath string
+	var allowDirty bool
+	var autoRollback bool
+	var wait bool
+	var resume bool
+	var only []string
+	var skip []string
 
 	cmd := &cobra.Command{
 		Use:   messages.UpgradeUse,
 		Short: messages.UpgradeShort,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if diffLines <= 0 {
-				return fmt.Errorf(messages.UpgradeDiffLinesInvalidFmt, diffLines)
+				return i18n.Errorf(messages.UpgradeDiffLinesInvalidFmt, diffLines)
 			}
 			root, err := resolveRepoRoot()
 			if err != nil {
 				return err
 			}
 
-			policy, err := resolveUpgradeApplyPolicy(upgradeApplyInputs{
+			inputs := upgradeApplyInputs{
 				interactive:       isTerminal(),
 				yes:               yes,
 				applyManaged:      applyManagedUpdates,
 				applyMemory:       applyMemoryUpdates,
 				applyDeletions:    applyDeletions,
 				applyTmpDeletions: applyTmpDeletions,
-			})
-			if err != nil {
+				maxRisk:           maxRisk,
+			}
+			var answers *upgradeAnswers
+			var policy upgradeApplyPolicy
+			if path := resolveUpgradeAnswersPath(answersPath); path != "" {
+				if yes || inputs.hasAnyApply() || strings.TrimSpace(maxRisk) != "" {
+					return i18n.Errorf(messages.UpgradeAnswersConflictsFlags)
+				}
+				if answers, err = loadUpgradeAnswers(path); err != nil {
+					return err
+				}
+				policy = answers.policy()
+			} else if policy, err = resolveUpgradeApplyPolicy(inputs); err != nil {
 				return err
 			}
-			if err := writeUpgradeSkippedCategoryNotes(cmd.ErrOrStderr(), policy); err != nil {
+			out := commandOutput(cmd, noiseModeFromConfig(root))
+			if err := writeUpgradeSkippedCategoryNotes(out.Info(), policy); err != nil {
 				return err
 			}
 
@@ -65,30 +88,75 @@ func newUpgradeCmd() *cobra.Command {
 					return err
 				}
 			}
+			lock, err := lockRepoState(cmd.ErrOrStderr(), root, wait)
+			if err != nil {
+				return err
+			}
+			defer func() { _ = lock.Release() }()
+			// Share one buffered reader between the risk gate and install prompts.
+			cmd.SetIn(bufferedReader(cmd.InOrStdin()))
+			resumeInterrupted, rolledBack, err := resolveInterruptedOperation(bufferedReader(cmd.InOrStdin()), cmd.OutOrStdout(), root, resume, policy.interactive)
+			if err != nil || rolledBack {
+				return err
+			}
+			if !allowDirty {
+				if err := guardUpgradeWorkingTree(bufferedReader(cmd.InOrStdin()), cmd.OutOrStdout(), root, targetPin, policy.interactive); err != nil {
+					return err
+				}
+			}
 			reviewState := buildUpgradeReviewState(policy)
 			opts := install.Options{
-				Overwrite:    true,
-				PinVersion:   targetPin,
-				DiffMaxLines: diffLines,
-				System:       install.RealSystem{},
+				Overwrite:         true,
+				PinVersion:        targetPin,
+				DiffMaxLines:      diffLines,
+				System:            install.RealSystem{},
+				Output:            out,
+				ResumeInterrupted: resumeInterrupted,
+				OperationFilter:   install.UpgradeOperationFilter{Only: only, Skip: skip},
+			}
+			prompter := buildUpgradePrompter(cmd, policy, reviewState)
+			if answers != nil {
+				prompter = answers.applyTo(prompter)
 			}
-			opts.Prompter = buildUpgradePrompter(cmd, policy, reviewState)
+			if policy.riskGated {
+				plan, err := buildUpgradePlanFunc(root, install.UpgradePlanOptions{
+					TargetPinVersion: targetPin,
+					System:           install.RealSystem{},
+					BinaryVersion:    Version,
+					OperationFilter:  opts.OperationFilter,
+				})
+				if err != nil {
+					return err
+				}
+				decision, err := confirmUpgradeRisks(bufferedReader(cmd.InOrStdin()), cmd.OutOrStdout(), plan.RiskGroups, policy.maxRisk, policy.interactive)
+				if err != nil {
+					return err
+				}
+				prompter = applyUpgradeRiskDecision(prompter, decision)
+			}
+			opts.Prompter = prompter
 			if err := installRun(root, opts); err != nil {
 				return err
 			}
-			if err := runPostUpgradeSync(cmd.OutOrStdout(), cmd.ErrOrStderr(), root); err != nil {
-				return err
+			verifyErr := runPostUpgradeSync(cmd.OutOrStdout(), out.Info(), root)
+			if verifyErr == nil {
+				verifyErr = verifyUpgrade(cmd.OutOrStdout(), out.Info(), root)
+			}
+			if verifyErr != nil {
+				return handleUpgradeVerifyFailure(cmd.OutOrStdout(), out.Info(), root, verifyErr, autoRollback)
 			}
-			if _, writeErr := fmt.Fprintln(cmd.OutOrStdout(), messages.UpgradeSuccessful); writeErr != nil {
+			if _, writeErr := fmt.Fprintln(cmd.OutOrStdout(), i18n.T(messages.UpgradeSuccessful)); writeErr != nil {
 				return writeErr
 			}
-			_, writeErr := fmt.Fprintln(cmd.OutOrStdout(), messages.UpgradeReviewSettingsHint)
+			_, writeErr := fmt.Fprintln(cmd.OutOrStdout(), i18n.T(messages.UpgradeReviewSettingsHint))
 			return writeErr
 		},
 	}
 	cmd.AddCommand(
 		newUpgradePlanCmd(&diffLines),
 		newUpgradeRollbackCmd(),
+		newUpgradeHistoryCmd(),
+		newUpgradeShowCmd(),
 		newUpgradePrefetchCmd(),
 		newUpgradeRepairGitignoreBlockCmd(),
 	)
@@ -99,21 +167,31 @@ func newUpgradeCmd() *cobra.Command {
 	cmd.Flags().BoolVar(&applyDeletions, "apply-deletions", false, messages.UpgradeFlagApplyDeletions)
 	cmd.Flags().BoolVar(&applyTmpDeletions, "apply-tmp-deletions", false, messages.UpgradeFlagApplyTmpDeletions)
 	cmd.Flags().StringVar(&pinVersion, "version", "", messages.UpgradeFlagVersion)
+	cmd.Flags().StringVar(&maxRisk, "max-risk", "", messages.UpgradeFlagMaxRisk)
+	cmd.Flags().StringVar(&answersPath, "answers", "", messages.UpgradeFlagAnswers)
+	cmd.Flags().BoolVar(&allowDirty, "allow-dirty", false, messages.UpgradeFlagAllowDirty)
+	cmd.Flags().BoolVar(&autoRollback, "auto-rollback", false, messages.UpgradeFlagAutoRollback)
+	cmd.Flags().BoolVar(&wait, "wait", false, messages.StateLockFlagWait)
+	cmd.Flags().BoolVar(&resume, "resume", false, messages.UpgradeFlagResume)
+	cmd.Flags().StringArrayVar(&only, "only", nil, messages.UpgradeFlagOnly)
+	cmd.Flags().StringArrayVar(&skip, "skip", nil, messages.UpgradeFlagSkip)
 	cmd.PersistentFlags().IntVar(&diffLines, "diff-lines", install.DefaultDiffMaxLines, messages.UpgradeFlagDiffLines)
 	return cmd
 }
 
 func newUpgradeRollbackCmd() *cobra.Command {
 	var list bool
+	var wait bool
 	cmd := &cobra.Command{
-		Use:   messages.UpgradeRollbackUse,
-		Short: messages.UpgradeRollbackShort,
+		Use:               messages.UpgradeRollbackUse,
+		Short:             messages.UpgradeRollbackShort,
+		ValidArgsFunction: completeSnapshotIDs,
 		Args: func(cmd *cobra.Command, args []string) error {
 			if list {
 				return cobra.NoArgs(cmd, args)
 			}
 			if len(args) != 1 {
-				return fmt.Errorf(messages.UpgradeRollbackRequiresSnapshotID)
+				return i18n.Errorf(messages.UpgradeRollbackRequiresSnapshotID)
 			}
 			return nil
 		},
@@ -128,26 +206,32 @@ func newUpgradeRollbackCmd() *cobra.Command {
 					return err
 				}
 				if len(snapshots) == 0 {
-					_, err = fmt.Fprintln(cmd.OutOrStdout(), messages.UpgradeRollbackNoSnapshots)
+					_, err = fmt.Fprintln(cmd.OutOrStdout(), i18n.T(messages.UpgradeRollbackNoSnapshots))
 					return err
 				}
-				_, _ = fmt.Fprintln(cmd.OutOrStdout(), messages.UpgradeRollbackListHeader)
+				_, _ = fmt.Fprintln(cmd.OutOrStdout(), i18n.T(messages.UpgradeRollbackListHeader))
 				for _, s := range snapshots {
 					_, _ = fmt.Fprintf(cmd.OutOrStdout(), "  - %s (%s, status: %s)\n", s.ID, s.CreatedAtUTC, s.Status)
 				}
 				return nil
 			}
+			lock, err := lockRepoState(cmd.ErrOrStderr(), root, wait)
+			if err != nil {
+				return err
+			}
+			defer func() { _ = lock.Release() }()
 			snapshotID := strings.TrimSpace(args[0])
 			if err := installRollbackUpgradeSnapshot(root, snapshotID, install.RollbackUpgradeSnapshotOptions{
 				System: install.RealSystem{},
 			}); err != nil {
 				return err
 			}
-			_, err = fmt.Fprintf(cmd.OutOrStdout(), messages.UpgradeRollbackSuccessFmt, snapshotID)
+			_, err = fmt.Fprintf(cmd.OutOrStdout(), i18n.T(messages.UpgradeRollbackSuccessFmt), snapshotID)
 			return err
 		},
 	}
 	cmd.Flags().BoolVar(&list, "list", false, messages.UpgradeRollbackFlagList)
+	cmd.Flags().BoolVar(&wait, "wait", false, messages.StateLockFlagWait)
 	return cmd
 }
 
@@ -164,12 +248,12 @@ func newUpgradePrefetchCmd() *cobra.Command {
 			}
 			targetVersion = strings.TrimSpace(targetVersion)
 			if targetVersion == "" {
-				return fmt.Errorf(messages.UpgradePrefetchVersionRequired)
+				return i18n.Errorf(messages.UpgradePrefetchVersionRequired)
 			}
 			if err := dispatchPrefetchVersion(targetVersion, cmd.ErrOrStderr()); err != nil {
 				return err
 			}
-			_, err = fmt.Fprintf(cmd.OutOrStdout(), messages.UpgradePrefetchDoneFmt, targetVersion)
+			_, err = fmt.Fprintf(cmd.OutOrStdout(), i18n.T(messages.UpgradePrefetchDoneFmt), targetVersion)
 			return err
 		},
 	}
@@ -192,7 +276,7 @@ func newUpgradeRepairGitignoreBlockCmd() *cobra.Command {
 			}); err != nil {
 				return err
 			}
-			_, err = fmt.Fprint(cmd.OutOrStdout(), messages.UpgradeRepairGitignoreDone)
+			_, err = fmt.Fprint(cmd.OutOrStdout(), i18n.T(messages.UpgradeRepairGitignoreDone))
 			return err
 		},
 	}
@@ -204,10 +288,10 @@ func newUpgradeRepairGitignoreBlockCmd() *cobra.Command {
 // surfaced on stderr; sync errors are wrapped to make clear that the upgrade
 // itself succeeded.
 func runPostUpgradeSync(stdout, stderr io.Writer, root string) error {
-	_, _ = fmt.Fprintln(stdout, messages.UpgradeRunningSync)
-	result, err := syncRun(root)
+	_, _ = fmt.Fprintln(stdout, i18n.T(messages.UpgradeRunningSync))
+	result, err := syncRun(root, stderr)
 	if err != nil {
-		return fmt.Errorf(messages.UpgradeSyncFailedFmt, err)
+		return i18n.Errorf(messages.UpgradeSyncFailedFmt, err)
 	}
 	if result == nil {
 		return nil
@@ -228,6 +312,7 @@ type upgradeApplyInputs struct {
 	applyMemory       bool
 	applyDeletions    bool
 	applyTmpDeletions bool
+	maxRisk           string
 }
 
 func (in upgradeApplyInputs) hasAnyApply() bool {
@@ -242,6 +327,11 @@ type upgradeApplyPolicy struct {
 	applyMemory       bool
 	applyDeletions    bool
 	applyTmpDeletions bool
+	// riskGated routes the upgrade through the grouped risk confirmation.
+	// maxRisk is the highest risk applied without prompting ("" prompts for
+	// every group).
+	riskGated bool
+	maxRisk   install.UpgradeRisk
 }
 
 type upgradeReviewState struct {
@@ -256,7 +346,7 @@ type upgradeReviewState struct {
 
 func buildUpgradeReviewState(policy upgradeApplyPolicy) *upgradeReviewState {
 	state := &upgradeReviewState{enabled: false}
-	if !policy.interactive || policy.explicitCategory {
+	if !policy.interactive || policy.explicitCategory || policy.riskGated {
 		return state
 	}
 	state.enabled = true
@@ -264,19 +354,37 @@ func buildUpgradeReviewState(policy upgradeApplyPolicy) *upgradeReviewState {
 }
 
 func resolveUpgradeApplyPolicy(in upgradeApplyInputs) (upgradeApplyPolicy, error) {
+	if strings.TrimSpace(in.maxRisk) != "" {
+		if in.hasAnyApply() {
+			return upgradeApplyPolicy{}, i18n.Errorf(messages.UpgradeMaxRiskConflictsApplyFlags)
+		}
+		maxRisk, err := install.ParseUpgradeRisk(in.maxRisk)
+		if err != nil {
+			return upgradeApplyPolicy{}, err
+		}
+		// Without a terminal nobody can answer config value prompts, so
+		// --max-risk accepts manifest defaults the same way --yes does.
+		return upgradeApplyPolicy{
+			interactive: in.interactive,
+			yes:         in.yes || !in.interactive,
+			riskGated:   true,
+			maxRisk:     maxRisk,
+		}, nil
+	}
 	if in.yes && !in.hasAnyApply() {
-		return upgradeApplyPolicy{}, fmt.Errorf(messages.UpgradeYesRequiresApply)
+		return upgradeApplyPolicy{}, i18n.Errorf(messages.UpgradeYesRequiresApply)
 	}
 	if !in.interactive && !in.hasAnyApply() {
-		return upgradeApplyPolicy{}, fmt.Errorf(messages.UpgradeRequiresTerminal)
+		return upgradeApplyPolicy{}, errcode.Wrap(errcode.InputRequired, i18n.Errorf(messages.UpgradeRequiresTerminal))
 	}
 	if !in.interactive && !in.yes {
-		return upgradeApplyPolicy{}, fmt.Errorf(messages.UpgradeNonInteractiveRequiresYesApply)
+		return upgradeApplyPolicy{}, errcode.Wrap(errcode.InputRequired, i18n.Errorf(messages.UpgradeNonInteractiveRequiresYesApply))
 	}
 	return upgradeApplyPolicy{
 		interactive:       in.interactive,
 		yes:               in.yes,
 		explicitCategory:  in.hasAnyApply(),
+		riskGated:         in.interactive && !in.hasAnyApply(),
 		applyManaged:      in.applyManaged,
 		applyMemory:       in.applyMemory,
 		applyDeletions:    in.applyDeletions,
@@ -296,7 +404,7 @@ func buildUpgradePrompter(cmd *cobra.Command, policy upgradeApplyPolicy, reviewS
 			if policy.yes {
 				return manifestValue, nil
 			}
-			_, err := fmt.Fprintf(cmd.OutOrStdout(), messages.UpgradeNewConfigKeyFmt, key, rationale)
+			_, err := fmt.Fprintf(cmd.OutOrStdout(), i18n.T(messages.UpgradeNewConfigKeyFmt), key, rationale)
 			if err != nil {
 				return nil, err
 			}
@@ -304,14 +412,20 @@ func buildUpgradePrompter(cmd *cobra.Command, policy upgradeApplyPolicy, reviewS
 				return promptConfigChoice(stdinReader, cmd.OutOrStdout(), key, manifestValue, *field)
 			}
 			// Fallback for keys not in the catalog.
-			accept, promptErr := promptYesNo(stdinReader, cmd.OutOrStdout(), fmt.Sprintf(messages.UpgradeAcceptValueFmt, manifestValue, key), true)
+			accept, promptErr := promptYesNo(stdinReader, cmd.OutOrStdout(), i18n.Sprintf(messages.UpgradeAcceptValueFmt, manifestValue, key), true)
 			if promptErr != nil {
 				return nil, promptErr
 			}
 			if accept {
 				return manifestValue, nil
 			}
-			return nil, fmt.Errorf(messages.UpgradeDeclinedRequiredKeyFmt, key)
+			return nil, i18n.Errorf(messages.UpgradeDeclinedRequiredKeyFmt, key)
+		},
+		ChooseSourceVersionFunc: func(candidates []install.BaselineCandidate) (string, error) {
+			if policy.yes || !policy.interactive {
+				return "", nil
+			}
+			return promptUpgradeSourceVersion(stdinReader, cmd.OutOrStdout(), candidates)
 		},
 		OverwriteAllPreviewFunc: func(previews []install.DiffPreview) (bool, error) {
 			if policy.explicitCategory {
@@ -361,13 +475,13 @@ func buildUpgradePrompter(cmd *cobra.Command, policy upgradeApplyPolicy, reviewS
 			if err := printDiffPreviews(cmd.OutOrStdout(), "", []install.DiffPreview{preview}); err != nil {
 				return false, err
 			}
-			prompt := fmt.Sprintf(messages.UpgradeOverwritePromptFmt, preview.Path)
+			prompt := i18n.Sprintf(messages.UpgradeOverwritePromptFmt, preview.Path)
 			return promptYesNo(stdinReader, cmd.OutOrStdout(), prompt, true)
 		},
 		StatuslineSourcePreviewFunc: func(preview install.DiffPreview) (bool, error) {
 			if policy.yes || !policy.interactive {
 				if reviewState != nil && !reviewState.statuslineSourceSkipAnnounced {
-					if _, err := fmt.Fprintln(cmd.ErrOrStderr(), messages.UpgradeSkipStatuslineSourceUpdatesInfo); err != nil {
+					if _, err := fmt.Fprintln(cmd.ErrOrStderr(), i18n.T(messages.UpgradeSkipStatuslineSourceUpdatesInfo)); err != nil {
 						return false, err
 					}
 					reviewState.statuslineSourceSkipAnnounced = true
@@ -377,7 +491,7 @@ func buildUpgradePrompter(cmd *cobra.Command, policy upgradeApplyPolicy, reviewS
 			if err := printDiffPreviews(cmd.OutOrStdout(), messages.UpgradeStatuslineSourceDiffHeader, []install.DiffPreview{preview}); err != nil {
 				return false, err
 			}
-			prompt := fmt.Sprintf(messages.UpgradeOverwriteStatuslineSourcePromptFmt, preview.Path)
+			prompt := i18n.Sprintf(messages.UpgradeOverwriteStatuslineSourcePromptFmt, preview.Path)
 			return promptYesNo(stdinReader, cmd.OutOrStdout(), prompt, false)
 		},
 		DeleteUnknownAllFunc: func(paths []string) (bool, error) {
@@ -409,9 +523,29 @@ func buildUpgradePrompter(cmd *cobra.Command, policy upgradeApplyPolicy, reviewS
 					return true, nil
 				}
 			}
-			prompt := fmt.Sprintf(messages.UpgradeDeleteUnknownPromptFmt, path)
+			prompt := i18n.Sprintf(messages.UpgradeDeleteUnknownPromptFmt, path)
 			return promptYesNo(stdinReader, cmd.OutOrStdout(), prompt, false)
 		},
+		DeleteOrphanedAllFunc: func(orphans []install.OrphanedArtifact) (bool, error) {
+			// Orphans are snapshot-protected like other unknown files, so they
+			// follow the same --apply-deletions policy.
+			if policy.explicitCategory {
+				if !policy.applyDeletions {
+					return false, nil
+				}
+				if policy.yes {
+					return true, nil
+				}
+			}
+			lines := make([]string, 0, len(orphans))
+			for _, orphan := range orphans {
+				lines = append(lines, install.FormatOrphanedArtifact(orphan))
+			}
+			if err := printFilePaths(cmd.OutOrStdout(), messages.UpgradeDeleteOrphanedHeader, lines); err != nil {
+				return false, err
+			}
+			return promptYesNo(stdinReader, cmd.OutOrStdout(), messages.UpgradeDeleteOrphanedAllPrompt, false)
+		},
 		DeleteUnknownTmpAllFunc: func(paths []string) (bool, error) {
 			// Tmp deletion is destructive and not snapshot-rollback-protected,
 			// so it is gated by its own flag (--apply-tmp-deletions),
@@ -429,10 +563,10 @@ func buildUpgradePrompter(cmd *cobra.Command, policy upgradeApplyPolicy, reviewS
 			if err := printFilePaths(cmd.OutOrStdout(), messages.UpgradeDeleteUnknownTmpHeader, paths); err != nil {
 				return false, err
 			}
-			if _, err := fmt.Fprintln(cmd.OutOrStdout(), color.YellowString("%s", messages.UpgradeDeleteUnknownTmpDestructiveWarningHeader)); err != nil {
+			if _, err := fmt.Fprintln(cmd.OutOrStdout(), style.Warning(messages.UpgradeDeleteUnknownTmpDestructiveWarningHeader)); err != nil {
 				return false, err
 			}
-			prompt := fmt.Sprintf(messages.UpgradeDeleteUnknownTmpAllPromptFmt, len(paths))
+			prompt := i18n.Sprintf(messages.UpgradeDeleteUnknownTmpAllPromptFmt, len(paths))
 			answer, err := promptYesNo(stdinReader, cmd.OutOrStdout(), prompt, false)
 			if err != nil {
 				return false, err
@@ -450,7 +584,7 @@ func buildUpgradePrompter(cmd *cobra.Command, policy upgradeApplyPolicy, reviewS
 			if policy.yes {
 				return true, nil
 			}
-			prompt := fmt.Sprintf(messages.UpgradeSkillsMigrationPromptFmt, len(flatSkills))
+			prompt := i18n.Sprintf(messages.UpgradeSkillsMigrationPromptFmt, len(flatSkills))
 			return promptYesNo(stdinReader, cmd.OutOrStdout(), prompt, true)
 		},
 	}
@@ -574,22 +708,22 @@ func writeUpgradeSkippedCategoryNotes(out io.Writer, policy upgradeApplyPolicy)
 		return nil
 	}
 	if !policy.applyManaged {
-		if _, err := fmt.Fprintln(out, messages.UpgradeSkipManagedUpdatesInfo); err != nil {
+		if _, err := fmt.Fprintln(out, i18n.T(messages.UpgradeSkipManagedUpdatesInfo)); err != nil {
 			return err
 		}
 	}
 	if !policy.applyMemory {
-		if _, err := fmt.Fprintln(out, messages.UpgradeSkipMemoryUpdatesInfo); err != nil {
+		if _, err := fmt.Fprintln(out, i18n.T(messages.UpgradeSkipMemoryUpdatesInfo)); err != nil {
 			return err
 		}
 	}
 	if !policy.applyDeletions {
-		if _, err := fmt.Fprintln(out, messages.UpgradeSkipDeletionsInfo); err != nil {
+		if _, err := fmt.Fprintln(out, i18n.T(messages.UpgradeSkipDeletionsInfo)); err != nil {
 			return err
 		}
 	}
 	if !policy.applyTmpDeletions {
-		if _, err := fmt.Fprintln(out, messages.UpgradeSkipTmpDeletionsInfo); err != nil {
+		if _, err := fmt.Fprintln(out, i18n.T(messages.UpgradeSkipTmpDeletionsInfo)); err != nil {
 			return err
 		}
 	}
@@ -598,16 +732,18 @@ func writeUpgradeSkippedCategoryNotes(out io.Writer, policy upgradeApplyPolicy)
 
 func newUpgradePlanCmd(diffLines *int) *cobra.Command {
 	var pinVersion string
+	var only []string
+	var skip []string
 	cmd := &cobra.Command{
 		Use:   messages.UpgradePlanUse,
 		Short: messages.UpgradePlanShort,
 		Args:  cobra.NoArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if diffLines == nil {
-				return fmt.Errorf(messages.UpgradeDiffLinesInvalidFmt, 0)
+				return i18n.Errorf(messages.UpgradeDiffLinesInvalidFmt, 0)
 			}
 			if *diffLines <= 0 {
-				return fmt.Errorf(messages.UpgradeDiffLinesInvalidFmt, *diffLines)
+				return i18n.Errorf(messages.UpgradeDiffLinesInvalidFmt, *diffLines)
 			}
 			root, err := resolveRepoRoot()
 			if err != nil {
@@ -626,6 +762,8 @@ func newUpgradePlanCmd(diffLines *int) *cobra.Command {
 			plan, err := install.BuildUpgradePlan(root, install.UpgradePlanOptions{
 				TargetPinVersion: targetPin,
 				System:           install.RealSystem{},
+				BinaryVersion:    Version,
+				OperationFilter:  install.UpgradeOperationFilter{Only: only, Skip: skip},
 			})
 			if err != nil {
 				return err
@@ -641,16 +779,24 @@ func newUpgradePlanCmd(diffLines *int) *cobra.Command {
 		},
 	}
 	cmd.Flags().StringVar(&pinVersion, "version", "", messages.UpgradeFlagVersion)
+	cmd.Flags().StringArrayVar(&only, "only", nil, messages.UpgradeFlagOnly)
+	cmd.Flags().StringArrayVar(&skip, "skip", nil, messages.UpgradeFlagSkip)
 	return cmd
 }
 
 func renderUpgradePlanText(out io.Writer, plan install.UpgradePlan, previews map[string]install.DiffPreview) error {
-	if _, err := fmt.Fprintln(out, messages.UpgradePlanDryRunNoFiles); err != nil {
+	if _, err := fmt.Fprintln(out, i18n.T(messages.UpgradePlanDryRunNoFiles)); err != nil {
 		return err
 	}
 	if err := writeUpgradeSummary(out, plan); err != nil {
 		return err
 	}
+	if err := install.WriteUpgradeChangelog(out, plan.Changelog); err != nil {
+		return err
+	}
+	if err := writeUpgradePlanRiskSection(out, plan.RiskGroups); err != nil {
+		return err
+	}
 	allUpdates := make([]install.UpgradeChange, 0, len(plan.TemplateUpdates)+len(plan.SectionAwareUpdates))
 	allUpdates = append(allUpdates, plan.TemplateUpdates...)
 	allUpdates = append(allUpdates, plan.SectionAwareUpdates...)
@@ -672,6 +818,11 @@ func renderUpgradePlanText(out io.Writer, plan install.UpgradePlan, previews map
 	if err := writeUpgradeChangeSection(out, messages.UpgradePlanSectionFilesToReviewRemoval, plan.TemplateRemovalsOrOrphans, previews); err != nil {
 		return err
 	}
+	if len(plan.OptedOut) > 0 {
+		if err := writeUpgradeChangeSection(out, messages.UpgradePlanSectionOptedOut, plan.OptedOut, previews); err != nil {
+			return err
+		}
+	}
 	if err := writeConfigMigrationSection(out, messages.UpgradePlanSectionConfigUpdates, plan.ConfigKeyMigrations); err != nil {
 		return err
 	}
@@ -688,15 +839,15 @@ func renderUpgradePlanText(out io.Writer, plan install.UpgradePlan, previews map
 }
 
 func writeUpgradeChangeSection(out io.Writer, title string, changes []install.UpgradeChange, previews map[string]install.DiffPreview) error {
-	if _, err := fmt.Fprintf(out, messages.UpgradePlanSectionTitleFmt, title); err != nil {
+	if err := writeSectionTitle(out, title); err != nil {
 		return err
 	}
 	if len(changes) == 0 {
-		_, err := fmt.Fprintln(out, messages.UpgradePlanNone)
+		_, err := fmt.Fprintln(out, i18n.T(messages.UpgradePlanNone))
 		return err
 	}
 	for _, change := range changes {
-		if _, err := fmt.Fprintf(out, messages.UpgradePlanItemFmt, change.Path); err != nil {
+		if _, err := fmt.Fprintf(out, i18n.T(messages.UpgradePlanItemFmt), change.Path); err != nil {
 			return err
 		}
 		if err := writeSinglePreviewBlock(out, previews[change.Path]); err != nil {
@@ -707,15 +858,15 @@ func writeUpgradeChangeSection(out io.Writer, title string, changes []install.Up
 }
 
 func writeUpgradeRenameSection(out io.Writer, title string, renames []install.UpgradeRename) error {
-	if _, err := fmt.Fprintf(out, messages.UpgradePlanSectionTitleFmt, title); err != nil {
+	if err := writeSectionTitle(out, title); err != nil {
 		return err
 	}
 	if len(renames) == 0 {
-		_, err := fmt.Fprintln(out, messages.UpgradePlanNone)
+		_, err := fmt.Fprintln(out, i18n.T(messages.UpgradePlanNone))
 		return err
 	}
 	for _, rename := range renames {
-		if _, err := fmt.Fprintf(out, messages.UpgradePlanRenameItemFmt, rename.From, rename.To); err != nil {
+		if _, err := fmt.Fprintf(out, i18n.T(messages.UpgradePlanRenameItemFmt), rename.From, rename.To); err != nil {
 			return err
 		}
 	}
@@ -723,21 +874,27 @@ func writeUpgradeRenameSection(out io.Writer, title string, renames []install.Up
 }
 
 func writeConfigMigrationSection(out io.Writer, title string, migrations []install.ConfigKeyMigration) error {
-	if _, err := fmt.Fprintf(out, messages.UpgradePlanSectionTitleFmt, title); err != nil {
+	if err := writeSectionTitle(out, title); err != nil {
 		return err
 	}
 	if len(migrations) == 0 {
-		_, err := fmt.Fprintln(out, messages.UpgradePlanNone)
+		_, err := fmt.Fprintln(out, i18n.T(messages.UpgradePlanNone))
 		return err
 	}
 	for _, migration := range migrations {
-		if _, err := fmt.Fprintf(out, messages.UpgradePlanConfigItemFmt, migration.Key, migration.From, migration.To); err != nil {
+		if _, err := fmt.Fprintf(out, i18n.T(messages.UpgradePlanConfigItemFmt), migration.Key, migration.From, migration.To); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
+// writeSectionTitle writes an upgrade plan section heading.
+func writeSectionTitle(out io.Writer, title string) error {
+	_, err := fmt.Fprint(out, style.Heading(i18n.Sprintf(messages.UpgradePlanSectionTitleFmt, title)))
+	return err
+}
+
 // errWriter wraps an io.Writer and accumulates the first error encountered,
 // allowing sequential writes without per-call error checks.
 type errWriter struct {
@@ -761,8 +918,8 @@ func (ew *errWriter) println(args ...any) {
 
 func writeMigrationReportSection(out io.Writer, title string, report install.UpgradeMigrationReport) error { //nolint:unparam // title kept for consistency with other write*Section functions
 	ew := &errWriter{w: out}
-	ew.printf(messages.UpgradePlanSectionTitleFmt, title)
-	if len(report.Entries) == 0 {
+	ew.printf("%s", style.Heading(i18n.Sprintf(messages.UpgradePlanSectionTitleFmt, title)))
+	if len(report.Entries) == 0 && len(report.SkippedPaths) == 0 {
 		ew.println(messages.UpgradePlanNone)
 		return ew.err
 	}
@@ -776,22 +933,31 @@ func writeMigrationReportSection(out io.Writer, title string, report install.Upg
 		if entry.SkipReason != "" {
 			ew.printf(messages.UpgradePlanMigrationReasonFmt, entry.SkipReason)
 		}
+		if strings.TrimSpace(entry.Diff) != "" && ew.err == nil {
+			ew.println(messages.UpgradePlanDiffLabel)
+			if ew.err == nil {
+				ew.err = writeUnifiedDiff(out, entry.Diff, shouldColorizeDiffOutput(), "      ")
+			}
+		}
 		if entry.Breaking && entry.Status == install.UpgradeMigrationStatusPlanned {
 			if entry.BreakingNotice != "" {
-				ew.println(color.YellowString(messages.UpgradePlanMigrationBreakingNoticeFmt, entry.BreakingNotice))
+				ew.println(style.Warning(i18n.Sprintf(messages.UpgradePlanMigrationBreakingNoticeFmt, entry.BreakingNotice)))
 			}
 			for _, detail := range entry.BreakingDetails {
-				ew.println(color.YellowString(messages.UpgradePlanMigrationBreakingDetailFmt, detail))
+				ew.println(style.Warning(i18n.Sprintf(messages.UpgradePlanMigrationBreakingDetailFmt, detail)))
 			}
-			ew.println(color.YellowString(messages.UpgradePlanMigrationBreakingRunHint))
+			ew.println(style.Warning(messages.UpgradePlanMigrationBreakingRunHint))
 		}
 	}
+	for _, path := range report.SkippedPaths {
+		ew.printf(messages.UpgradePlanMigrationSkippedPathFmt, path)
+	}
 	return ew.err
 }
 
 func writePinVersionSection(out io.Writer, pin install.UpgradePinVersionDiff) error {
 	ew := &errWriter{w: out}
-	ew.println(messages.UpgradePlanPinVersionHeader)
+	ew.println(style.Heading(messages.UpgradePlanPinVersionHeader))
 	ew.printf(messages.UpgradePlanPinCurrentFmt, pin.Current)
 	ew.printf(messages.UpgradePlanPinTargetFmt, pin.Target)
 	ew.printf(messages.UpgradePlanPinActionFmt, pin.Action)
@@ -802,7 +968,7 @@ func writeSinglePreviewBlock(out io.Writer, preview install.DiffPreview) error {
 	if strings.TrimSpace(preview.UnifiedDiff) == "" {
 		return nil
 	}
-	if _, err := fmt.Fprintln(out, messages.UpgradePlanDiffLabel); err != nil {
+	if _, err := fmt.Fprintln(out, i18n.T(messages.UpgradePlanDiffLabel)); err != nil {
 		return err
 	}
 	if err := writeUnifiedDiff(out, preview.UnifiedDiff, shouldColorizeDiffOutput(), "      "); err != nil {
@@ -868,7 +1034,7 @@ func printDiffPreviewBodies(out io.Writer, previews []install.DiffPreview) error
 		if strings.TrimSpace(preview.UnifiedDiff) == "" {
 			continue
 		}
-		if _, err := fmt.Fprintf(out, messages.UpgradePlanDiffForFmt, preview.Path); err != nil {
+		if _, err := fmt.Fprintf(out, i18n.T(messages.UpgradePlanDiffForFmt), preview.Path); err != nil {
 			return err
 		}
 		if err := writeUnifiedDiff(out, preview.UnifiedDiff, colorize, ""); err != nil {
@@ -892,8 +1058,10 @@ func formatDiffStat(count int, sign string, c *color.Color, colorize bool) strin
 	return text
 }
 
+// shouldColorizeDiffOutput reports whether diffs are colored, which follows
+// the --color mode like the rest of the report.
 func shouldColorizeDiffOutput() bool {
-	return isTerminal() && !color.NoColor
+	return style.Enabled()
 }
 
 var (
@@ -942,20 +1110,20 @@ func writeUnifiedDiff(out io.Writer, diff string, colorize bool, indent string)
 }
 
 func writeReadinessSection(out io.Writer, checks []install.UpgradeReadinessCheck) error {
-	if _, err := fmt.Fprintln(out, messages.UpgradePlanReadinessHeader); err != nil {
+	if _, err := fmt.Fprintln(out, style.Heading(messages.UpgradePlanReadinessHeader)); err != nil {
 		return err
 	}
 	if len(checks) == 0 {
-		_, err := fmt.Fprintln(out, messages.UpgradePlanNone)
+		_, err := fmt.Fprintln(out, i18n.T(messages.UpgradePlanNone))
 		return err
 	}
 	for _, check := range checks {
-		if _, err := fmt.Fprintf(out, messages.UpgradePlanReadinessItemFmt, color.YellowString("%s", readinessSummary(check))); err != nil {
+		if _, err := fmt.Fprintf(out, i18n.T(messages.UpgradePlanReadinessItemFmt), style.Warning(readinessSummary(check))); err != nil {
 			return err
 		}
 		action := readinessAction(check.ID)
 		if action != "" {
-			if _, err := fmt.Fprintf(out, messages.UpgradePlanReadinessRecommendationFmt, action); err != nil {
+			if _, err := fmt.Fprintf(out, i18n.T(messages.UpgradePlanReadinessRecommendationFmt), action); err != nil {
 				return err
 			}
 		}
@@ -964,12 +1132,12 @@ func writeReadinessSection(out io.Writer, checks []install.UpgradeReadinessCheck
 			details = details[:3]
 		}
 		for _, detail := range details {
-			if _, err := fmt.Fprintf(out, messages.UpgradePlanReadinessNoteFmt, detail); err != nil {
+			if _, err := fmt.Fprintf(out, i18n.T(messages.UpgradePlanReadinessNoteFmt), detail); err != nil {
 				return err
 			}
 		}
 		if len(check.Details) > len(details) {
-			if _, err := fmt.Fprintf(out, messages.UpgradePlanReadinessNoteMoreFmt, len(check.Details)-len(details)); err != nil {
+			if _, err := fmt.Fprintf(out, i18n.T(messages.UpgradePlanReadinessNoteMoreFmt), len(check.Details)-len(details)); err != nil {
 				return err
 			}
 		}
@@ -990,26 +1158,26 @@ func writeUpgradeSummary(out io.Writer, plan install.UpgradePlan) error {
 	if !needsReview {
 		reviewState = "no"
 	}
-	if _, err := fmt.Fprintln(out, messages.UpgradePlanSummaryHeader); err != nil {
+	if _, err := fmt.Fprintln(out, style.Heading(messages.UpgradePlanSummaryHeader)); err != nil {
 		return err
 	}
-	if _, err := fmt.Fprintf(out, messages.UpgradePlanSummaryFilesToAddFmt, len(plan.TemplateAdditions)); err != nil {
+	if _, err := fmt.Fprintf(out, i18n.T(messages.UpgradePlanSummaryFilesToAddFmt), len(plan.TemplateAdditions)); err != nil {
 		return err
 	}
-	if _, err := fmt.Fprintf(out, messages.UpgradePlanSummaryFilesToUpdateFmt, filesToUpdate); err != nil {
+	if _, err := fmt.Fprintf(out, i18n.T(messages.UpgradePlanSummaryFilesToUpdateFmt), filesToUpdate); err != nil {
 		return err
 	}
-	if _, err := fmt.Fprintf(out, messages.UpgradePlanSummaryFilesToRenameFmt, len(plan.TemplateRenames)); err != nil {
+	if _, err := fmt.Fprintf(out, i18n.T(messages.UpgradePlanSummaryFilesToRenameFmt), len(plan.TemplateRenames)); err != nil {
 		return err
 	}
 	removals := len(plan.TemplateRemovalsOrOrphans)
 	if err := writeHighlightedSummaryLine(out, removals > 0, messages.UpgradePlanSummaryFilesToReviewFmt, removals); err != nil {
 		return err
 	}
-	if _, err := fmt.Fprintf(out, messages.UpgradePlanSummaryConfigUpdatesFmt, len(plan.ConfigKeyMigrations)); err != nil {
+	if _, err := fmt.Fprintf(out, i18n.T(messages.UpgradePlanSummaryConfigUpdatesFmt), len(plan.ConfigKeyMigrations)); err != nil {
 		return err
 	}
-	if _, err := fmt.Fprintf(out, messages.UpgradePlanSummaryMigrationsFmt, migrationsPlanned); err != nil {
+	if _, err := fmt.Fprintf(out, i18n.T(messages.UpgradePlanSummaryMigrationsFmt), migrationsPlanned); err != nil {
 		return err
 	}
 	if err := writeHighlightedSummaryLine(out, len(plan.ReadinessChecks) > 0, messages.UpgradePlanSummaryReadinessWarnFmt, len(plan.ReadinessChecks)); err != nil {
@@ -1025,7 +1193,7 @@ func writeUpgradeSummary(out io.Writer, plan install.UpgradePlan) error {
 // highlighted in yellow when highlight is true.
 func writeHighlightedSummaryLine(out io.Writer, highlight bool, format string, a ...any) error {
 	if highlight {
-		_, err := fmt.Fprintf(out, messages.UpgradePlanSummaryLineFmt, color.YellowString(format, a...))
+		_, err := fmt.Fprintf(out, i18n.T(messages.UpgradePlanSummaryLineFmt), style.Warning(fmt.Sprintf(format, a...)))
 		return err
 	}
 	_, err := fmt.Fprintf(out, "  - "+format+"\n", a...)
@@ -1093,17 +1261,47 @@ func promptConfigChoice(in *bufio.Reader, out io.Writer, key string, manifestVal
 		return promptEnumChoice(in, out, manifestValue, field)
 	default:
 		// Freetext / unknown — present the migration value for acceptance.
-		if _, err := fmt.Fprintf(out, messages.UpgradeConfigChoiceValueFmt, manifestValue); err != nil {
+		if _, err := fmt.Fprintf(out, i18n.T(messages.UpgradeConfigChoiceValueFmt), manifestValue); err != nil {
 			return nil, err
 		}
-		accept, err := promptYesNo(in, out, fmt.Sprintf(messages.UpgradeAcceptValueFmt, manifestValue, key), true)
+		accept, err := promptYesNo(in, out, i18n.Sprintf(messages.UpgradeAcceptValueFmt, manifestValue, key), true)
 		if err != nil {
 			return nil, err
 		}
 		if accept {
 			return manifestValue, nil
 		}
-		return nil, fmt.Errorf(messages.UpgradeDeclinedRequiredKeyFmt, key)
+		if field.Type == config.FieldFreetext || field.Type == config.FieldPositiveInt {
+			return promptConfigValue(in, out, field)
+		}
+		return nil, i18n.Errorf(messages.UpgradeDeclinedRequiredKeyFmt, key)
+	}
+}
+
+// promptConfigValue reads a typed-in override for field, re-prompting until
+// the value passes the field's constraints. Invalid input at EOF is an error.
+func promptConfigValue(in *bufio.Reader, out io.Writer, field config.FieldDef) (any, error) {
+	if err := checkPrompt(i18n.Sprintf(messages.UpgradeConfigEnterValueFmt, field.Key)); err != nil {
+		return nil, err
+	}
+	for {
+		if _, err := fmt.Fprintf(out, i18n.T(messages.UpgradeConfigEnterValueFmt), field.Key); err != nil {
+			return nil, err
+		}
+		line, err := in.ReadString('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			return nil, err
+		}
+		value, parseErr := config.ParseFieldValue(field, line)
+		if parseErr == nil {
+			return value, nil
+		}
+		if errors.Is(err, io.EOF) {
+			return nil, parseErr
+		}
+		if _, retryErr := fmt.Fprintf(out, i18n.T(messages.UpgradeConfigInvalidValueFmt), parseErr); retryErr != nil {
+			return nil, retryErr
+		}
 	}
 }
 
@@ -1112,7 +1310,7 @@ func promptConfigChoice(in *bufio.Reader, out io.Writer, key string, manifestVal
 func promptBoolChoice(in *bufio.Reader, out io.Writer, manifestValue any) (any, error) {
 	manBool, ok := manifestValue.(bool)
 	if !ok {
-		return nil, fmt.Errorf(messages.UpgradeManifestBoolValueErrFmt, manifestValue, manifestValue)
+		return nil, i18n.Errorf(messages.UpgradeManifestBoolValueErrFmt, manifestValue, manifestValue)
 	}
 	options := []string{"true", "false"}
 	defaultIdx := 1 // false
@@ -1127,6 +1325,7 @@ func promptBoolChoice(in *bufio.Reader, out io.Writer, manifestValue any) (any,
 }
 
 // promptEnumChoice presents a numbered list of enum options and returns the selected string.
+// AllowCustom fields get a final option that reads a typed-in value.
 // Returns an error if the manifest value is not in the option list for strict (non-AllowCustom) enums.
 func promptEnumChoice(in *bufio.Reader, out io.Writer, manifestValue any, field config.FieldDef) (any, error) {
 	manStr := fmt.Sprintf("%v", manifestValue)
@@ -1144,32 +1343,63 @@ func promptEnumChoice(in *bufio.Reader, out io.Writer, manifestValue any, field
 	}
 	if defaultIdx < 0 {
 		if !field.AllowCustom {
-			return nil, fmt.Errorf(messages.UpgradeManifestEnumValueErrFmt, manStr, field.Key)
+			return nil, i18n.Errorf(messages.UpgradeManifestEnumValueErrFmt, manStr, field.Key)
 		}
 		// AllowCustom field with a custom manifest value — default to first option.
 		defaultIdx = 0
 	}
+	if field.AllowCustom {
+		options = append(options, messages.UpgradeConfigCustomValueOption)
+	}
 	chosen, err := promptNumberedChoice(in, out, options, defaultIdx)
 	if err != nil {
 		return nil, err
 	}
+	if chosen == len(field.Options) {
+		return promptConfigValue(in, out, field)
+	}
 	return field.Options[chosen].Value, nil
 }
 
+// promptUpgradeSourceVersion offers the ranked manifest matches plus a final
+// "keep unknown" option. The best match is the default. It returns "" when
+// the user keeps the source unknown.
+func promptUpgradeSourceVersion(in *bufio.Reader, out io.Writer, candidates []install.BaselineCandidate) (string, error) {
+	if _, err := fmt.Fprintln(out, i18n.T(messages.UpgradeSourceVersionHeader)); err != nil {
+		return "", err
+	}
+	options := make([]string, 0, len(candidates)+1)
+	for _, candidate := range candidates {
+		options = append(options, i18n.Sprintf(messages.UpgradeSourceVersionOptionFmt, candidate.Version, candidate.Matched, candidate.Present))
+	}
+	options = append(options, messages.UpgradeSourceVersionUnknown)
+	idx, err := promptNumberedChoice(in, out, options, 0)
+	if err != nil {
+		return "", err
+	}
+	if idx == len(candidates) {
+		return "", nil
+	}
+	return candidates[idx].Version, nil
+}
+
 // promptNumberedChoice displays a numbered list and reads the user's selection.
 // options are display labels; defaultIdx is the 0-based pre-selected option (accepted on Enter).
 // Returns the 0-based index of the chosen option.
 func promptNumberedChoice(in *bufio.Reader, out io.Writer, options []string, defaultIdx int) (int, error) {
-	if _, err := fmt.Fprintln(out, messages.UpgradeNumberedChoiceHeader); err != nil {
+	if err := checkPrompt(messages.UpgradeNumberedChoiceHeader); err != nil {
+		return 0, err
+	}
+	if _, err := fmt.Fprintln(out, i18n.T(messages.UpgradeNumberedChoiceHeader)); err != nil {
 		return 0, err
 	}
 	for i, opt := range options {
-		if _, err := fmt.Fprintf(out, messages.UpgradeNumberedChoiceOptionFmt, i+1, opt); err != nil { //nolint:gosec // CLI output, not web
+		if _, err := fmt.Fprintf(out, i18n.T(messages.UpgradeNumberedChoiceOptionFmt), i+1, opt); err != nil { //nolint:gosec // CLI output, not web
 			return 0, err
 		}
 	}
 	for {
-		if _, err := fmt.Fprintf(out, messages.UpgradeNumberedChoiceEnterFmt, defaultIdx+1); err != nil {
+		if _, err := fmt.Fprintf(out, i18n.T(messages.UpgradeNumberedChoiceEnterFmt), defaultIdx+1); err != nil {
 			return 0, err
 		}
 		line, err := in.ReadString('\n')
@@ -1185,9 +1415,9 @@ func promptNumberedChoice(in *bufio.Reader, out io.Writer, options []string, def
 			return n - 1, nil
 		}
 		if errors.Is(err, io.EOF) {
-			return 0, fmt.Errorf(messages.UpgradeNumberedChoiceInvalidFmt, input)
+			return 0, i18n.Errorf(messages.UpgradeNumberedChoiceInvalidFmt, input)
 		}
-		if _, retryErr := fmt.Fprintf(out, messages.UpgradeNumberedChoiceRetryFmt, len(options)); retryErr != nil { //nolint:gosec // CLI output, not web
+		if _, retryErr := fmt.Fprintf(out, i18n.T(messages.UpgradeNumberedChoiceRetryFmt), len(options)); retryErr != nil { //nolint:gosec // CLI output, not web
 			return 0, retryErr
 		}
 	}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	yaml "go.yaml.in/yaml/v3"
+
+	"github.com/conn-castle/agent-layer/internal/config"
+	"github.com/conn-castle/agent-layer/internal/i18n"
+	"github.com/conn-castle/agent-layer/internal/install"
+	"github.com/conn-castle/agent-layer/internal/messages"
+)
+
+// envUpgradeAnswers names an answer file when --answers is not passed.
+const envUpgradeAnswers = "AL_UPGRADE_ANSWERS"
+
+// upgradeAnswers pre-supplies every upgrade prompt so automation can upgrade
+// without a terminal. The file is YAML; JSON is accepted as a YAML subset.
+type upgradeAnswers struct {
+	Apply struct {
+		ManagedUpdates bool `yaml:"managed_updates"`
+		MemoryUpdates  bool `yaml:"memory_updates"`
+		Deletions      bool `yaml:"deletions"`
+		TmpDeletions   bool `yaml:"tmp_deletions"`
+	} `yaml:"apply"`
+	// Config answers config_set_default prompts by config key. Keys without
+	// an answer take the migration manifest value, as --yes does.
+	Config map[string]any `yaml:"config"`
+	// SkillsMigration answers the skills-format migration confirmation;
+	// nil accepts it.
+	SkillsMigration *bool `yaml:"skills_migration"`
+}
+
+// resolveUpgradeAnswersPath returns the --answers value, falling back to
+// AL_UPGRADE_ANSWERS.
+func resolveUpgradeAnswersPath(flagValue string) string {
+	if path := strings.TrimSpace(flagValue); path != "" {
+		return path
+	}
+	return strings.TrimSpace(os.Getenv(envUpgradeAnswers))
+}
+
+// loadUpgradeAnswers reads and strictly decodes an answer file.
+func loadUpgradeAnswers(path string) (*upgradeAnswers, error) {
+	data, err := os.ReadFile(path) // #nosec G304 -- path is the user-supplied answer file.
+	if err != nil {
+		return nil, i18n.Errorf(messages.UpgradeAnswersReadFmt, path, err)
+	}
+	var answers upgradeAnswers
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&answers); err != nil && !errors.Is(err, io.EOF) {
+		return nil, i18n.Errorf(messages.UpgradeAnswersParseFmt, path, err)
+	}
+	return &answers, nil
+}
+
+// policy returns the apply policy the answer file selects. Answers stand in
+// for --yes, so deletions the file enables are not prompted again.
+func (a *upgradeAnswers) policy() upgradeApplyPolicy {
+	return upgradeApplyPolicy{
+		yes:               true,
+		explicitCategory:  true,
+		applyManaged:      a.Apply.ManagedUpdates,
+		applyMemory:       a.Apply.MemoryUpdates,
+		applyDeletions:    a.Apply.Deletions,
+		applyTmpDeletions: a.Apply.TmpDeletions,
+	}
+}
+
+// applyTo replaces the prompts an answer file covers.
+func (a *upgradeAnswers) applyTo(prompter install.PromptFuncs) install.PromptFuncs {
+	prompter.ConfigSetDefaultFunc = func(key string, manifestValue any, _ string, field *config.FieldDef) (any, error) {
+		value, ok := a.Config[key]
+		if !ok {
+			return manifestValue, nil
+		}
+		if err := validateUpgradeAnswer(key, value, field); err != nil {
+			return nil, err
+		}
+		return value, nil
+	}
+	prompter.ConfirmSkillsMigrationFunc = func(_ []string, conflicts []install.SkillsMigrationConflict) (bool, error) {
+		// Conflicts always block, even when the answer file accepts.
+		if len(conflicts) > 0 {
+			return false, nil
+		}
+		return a.SkillsMigration == nil || *a.SkillsMigration, nil
+	}
+	return prompter
+}
+
+// validateUpgradeAnswer checks an answered config value against its catalog
+// field, the same choices the interactive prompt offers.
+func validateUpgradeAnswer(key string, value any, field *config.FieldDef) error {
+	if field == nil {
+		return nil
+	}
+	switch field.Type {
+	case config.FieldBool:
+		if _, ok := value.(bool); !ok {
+			return i18n.Errorf(messages.UpgradeAnswersBoolFmt, key, value)
+		}
+	case config.FieldEnum:
+		options := make([]string, 0, len(field.Options))
+		for _, option := range field.Options {
+			options = append(options, option.Value)
+		}
+		text, ok := value.(string)
+		if !ok || (!field.AllowCustom && !slices.Contains(options, text)) {
+			return i18n.Errorf(messages.UpgradeAnswersEnumFmt, key, strings.Join(options, ", "), value)
+		}
+	}
+	return nil
+}
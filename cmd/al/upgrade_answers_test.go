@@ -0,0 +1,125 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/conn-castle/agent-layer/internal/config"
+	"github.com/conn-castle/agent-layer/internal/install"
+)
+
+func writeAnswers(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "answers.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("write answers: %v", err)
+	}
+	return path
+}
+
+func TestLoadUpgradeAnswers(t *testing.T) {
+	yamlPath := writeAnswers(t, "apply:\n  managed_updates: true\n  deletions: true\nconfig:\n  agents.claude.local_config_dir: true\nskills_migration: false\n")
+	answers, err := loadUpgradeAnswers(yamlPath)
+	if err != nil {
+		t.Fatalf("load yaml: %v", err)
+	}
+	policy := answers.policy()
+	if !policy.yes || !policy.explicitCategory || !policy.applyManaged || policy.applyMemory || !policy.applyDeletions || policy.applyTmpDeletions {
+		t.Fatalf("unexpected policy %#v", policy)
+	}
+	if answers.Config["agents.claude.local_config_dir"] != true || answers.SkillsMigration == nil || *answers.SkillsMigration {
+		t.Fatalf("unexpected answers %#v", answers)
+	}
+
+	jsonPath := writeAnswers(t, `{"apply": {"memory_updates": true}, "config": {"approvals.mode": "all"}}`)
+	answers, err = loadUpgradeAnswers(jsonPath)
+	if err != nil {
+		t.Fatalf("load json: %v", err)
+	}
+	if !answers.Apply.MemoryUpdates || answers.Config["approvals.mode"] != "all" {
+		t.Fatalf("unexpected json answers %#v", answers)
+	}
+
+	empty, err := loadUpgradeAnswers(writeAnswers(t, ""))
+	if err != nil || empty.policy().applyManaged {
+		t.Fatalf("expected empty answer file to apply nothing, got %#v %v", empty, err)
+	}
+}
+
+func TestLoadUpgradeAnswers_Errors(t *testing.T) {
+	if _, err := loadUpgradeAnswers(writeAnswers(t, "apply:\n  managed: true\n")); err == nil || !strings.Contains(err.Error(), "invalid upgrade answer file") {
+		t.Fatalf("expected unknown key error, got %v", err)
+	}
+	if _, err := loadUpgradeAnswers(filepath.Join(t.TempDir(), "missing.yaml")); err == nil || !strings.Contains(err.Error(), "failed to read upgrade answer file") {
+		t.Fatalf("expected read error, got %v", err)
+	}
+}
+
+func TestUpgradeAnswers_ApplyTo(t *testing.T) {
+	declined := false
+	answers := &upgradeAnswers{
+		Config: map[string]any{
+			"flag":  false,
+			"mode":  "custom",
+			"model": "my-model",
+		},
+		SkillsMigration: &declined,
+	}
+	prompter := answers.applyTo(install.PromptFuncs{})
+	boolField := &config.FieldDef{Key: "flag", Type: config.FieldBool}
+	enumField := &config.FieldDef{Key: "mode", Type: config.FieldEnum, Options: []config.FieldOption{{Value: "all"}, {Value: "none"}}}
+	customField := &config.FieldDef{Key: "model", Type: config.FieldEnum, AllowCustom: true}
+
+	if got, err := prompter.ConfigSetDefaultFunc("flag", true, "", boolField); err != nil || got != false {
+		t.Fatalf("expected answered bool, got %v %v", got, err)
+	}
+	if got, err := prompter.ConfigSetDefaultFunc("unanswered", "default", "", nil); err != nil || got != "default" {
+		t.Fatalf("expected manifest value for unanswered key, got %v %v", got, err)
+	}
+	if _, err := prompter.ConfigSetDefaultFunc("mode", "all", "", enumField); err == nil || !strings.Contains(err.Error(), "must be one of all, none, got custom") {
+		t.Fatalf("expected enum validation error, got %v", err)
+	}
+	if got, err := prompter.ConfigSetDefaultFunc("model", "x", "", customField); err != nil || got != "my-model" {
+		t.Fatalf("expected custom enum value accepted, got %v %v", got, err)
+	}
+	answers.Config["flag"] = "yes"
+	if _, err := prompter.ConfigSetDefaultFunc("flag", true, "", boolField); err == nil {
+		t.Fatalf("expected bool validation error")
+	}
+
+	if ok, err := prompter.ConfirmSkillsMigration([]string{"a"}, nil); err != nil || ok {
+		t.Fatalf("expected declined skills migration, got %v %v", ok, err)
+	}
+	answers.SkillsMigration = nil
+	if ok, _ := prompter.ConfirmSkillsMigration([]string{"a"}, nil); !ok {
+		t.Fatalf("expected skills migration accepted by default")
+	}
+	if ok, _ := prompter.ConfirmSkillsMigration([]string{"a"}, []install.SkillsMigrationConflict{{}}); ok {
+		t.Fatalf("expected conflicts to block the skills migration")
+	}
+}
+
+func TestUpgradeCmd_AnswersConflictWithFlags(t *testing.T) {
+	stubRepoRoot(t)
+	path := writeAnswers(t, "apply:\n  managed_updates: true\n")
+	cmd := newUpgradeCmd()
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs([]string{"--answers", path, "--yes"})
+	if err := cmd.Execute(); err == nil || !strings.Contains(err.Error(), "cannot be combined") {
+		t.Fatalf("expected conflict error, got %v", err)
+	}
+}
+
+func TestResolveUpgradeAnswersPath(t *testing.T) {
+	t.Setenv(envUpgradeAnswers, " /env/answers.yaml ")
+	if got := resolveUpgradeAnswersPath(""); got != "/env/answers.yaml" {
+		t.Fatalf("expected env path, got %q", got)
+	}
+	if got := resolveUpgradeAnswersPath("flag.yaml"); got != "flag.yaml" {
+		t.Fatalf("expected flag to win, got %q", got)
+	}
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+
+	"github.com/conn-castle/agent-layer/internal/errcode"
+	"github.com/conn-castle/agent-layer/internal/i18n"
+	"github.com/conn-castle/agent-layer/internal/install"
+	"github.com/conn-castle/agent-layer/internal/messages"
+)
+
+var checkUpgradeGitStatus = install.CheckUpgradeGitStatus
+var stashUpgradeChanges = install.StashUpgradeChanges
+var commitUpgradeCheckpoint = install.CommitUpgradeCheckpoint
+
+// upgradeDirtyListLimit caps how many dirty paths the guard prints.
+const upgradeDirtyListLimit = 10
+
+// guardUpgradeWorkingTree keeps an upgrade from mixing its rewrites into
+// uncommitted edits of .agent-layer/ or generated paths. Interactive upgrades
+// offer to stash or commit the edits first; other upgrades fail. Repos outside
+// git are not checked.
+func guardUpgradeWorkingTree(in *bufio.Reader, out io.Writer, root string, targetPin string, interactive bool) error {
+	status, err := checkUpgradeGitStatus(root)
+	if err != nil {
+		return err
+	}
+	if !status.InWorkTree || len(status.Dirty) == 0 {
+		return nil
+	}
+	if !interactive {
+		return errcode.Wrap(errcode.UpgradeConflict, i18n.Errorf(messages.UpgradeDirtyRefusedFmt, len(status.Dirty), status.Dirty[0]))
+	}
+	if _, err := fmt.Fprintln(out, i18n.T(messages.UpgradeDirtyHeader)); err != nil {
+		return err
+	}
+	for i, path := range status.Dirty {
+		if i == upgradeDirtyListLimit {
+			if _, err := fmt.Fprintf(out, i18n.T(messages.UpgradeDirtyMoreFmt), len(status.Dirty)-i); err != nil {
+				return err
+			}
+			break
+		}
+		if _, err := fmt.Fprintf(out, i18n.T(messages.UpgradeDirtyPathFmt), path); err != nil { //nolint:gosec // CLI output, not web
+			return err
+		}
+	}
+	choice, err := promptNumberedChoice(in, out, []string{
+		messages.UpgradeDirtyStashOption,
+		messages.UpgradeDirtyCommitOption,
+		messages.UpgradeDirtyCancelOption,
+	}, 2)
+	if err != nil {
+		return err
+	}
+	if targetPin == "" {
+		targetPin = Version
+	}
+	message := i18n.Sprintf(messages.UpgradeDirtyCheckpointMessageFmt, targetPin)
+	switch choice {
+	case 0:
+		if err := stashUpgradeChanges(root, status, message); err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(out, i18n.T(messages.UpgradeDirtyStashedFmt), len(status.Dirty))
+		return err
+	case 1:
+		if err := commitUpgradeCheckpoint(root, status, message); err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(out, i18n.T(messages.UpgradeDirtyCommittedFmt), len(status.Dirty))
+		return err
+	default:
+		return errUpgradeCancelled
+	}
+}
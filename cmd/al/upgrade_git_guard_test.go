@@ -0,0 +1,65 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/conn-castle/agent-layer/internal/errcode"
+	"github.com/conn-castle/agent-layer/internal/install"
+)
+
+func stubUpgradeGit(t *testing.T, status install.UpgradeGitStatus) *[]string {
+	t.Helper()
+	origCheck, origStash, origCommit := checkUpgradeGitStatus, stashUpgradeChanges, commitUpgradeCheckpoint
+	t.Cleanup(func() {
+		checkUpgradeGitStatus, stashUpgradeChanges, commitUpgradeCheckpoint = origCheck, origStash, origCommit
+	})
+	var calls []string
+	checkUpgradeGitStatus = func(string) (install.UpgradeGitStatus, error) { return status, nil }
+	stashUpgradeChanges = func(_ string, _ install.UpgradeGitStatus, message string) error {
+		calls = append(calls, "stash: "+message)
+		return nil
+	}
+	commitUpgradeCheckpoint = func(_ string, _ install.UpgradeGitStatus, message string) error {
+		calls = append(calls, "commit: "+message)
+		return nil
+	}
+	return &calls
+}
+
+func TestGuardUpgradeWorkingTree(t *testing.T) {
+	dirty := install.UpgradeGitStatus{InWorkTree: true, Dirty: []string{".agent-layer/config.toml"}}
+
+	calls := stubUpgradeGit(t, install.UpgradeGitStatus{InWorkTree: true})
+	if err := guardUpgradeWorkingTree(bufio.NewReader(strings.NewReader("")), &bytes.Buffer{}, "/repo", "0.9.0", false); err != nil || len(*calls) != 0 {
+		t.Fatalf("clean tree = %v, calls %v", err, *calls)
+	}
+
+	stubUpgradeGit(t, dirty)
+	err := guardUpgradeWorkingTree(bufio.NewReader(strings.NewReader("")), &bytes.Buffer{}, "/repo", "0.9.0", false)
+	if err == nil || errcode.Of(err) != errcode.UpgradeConflict || !strings.Contains(err.Error(), "--allow-dirty") {
+		t.Fatalf("non-interactive dirty tree = %v", err)
+	}
+
+	for input, want := range map[string]string{
+		"1\n": "stash: Checkpoint before Agent Layer upgrade to 0.9.0",
+		"2\n": "commit: Checkpoint before Agent Layer upgrade to 0.9.0",
+	} {
+		calls := stubUpgradeGit(t, dirty)
+		var out bytes.Buffer
+		if err := guardUpgradeWorkingTree(bufio.NewReader(strings.NewReader(input)), &out, "/repo", "0.9.0", true); err != nil {
+			t.Fatalf("choice %q: %v", input, err)
+		}
+		if len(*calls) != 1 || (*calls)[0] != want || !strings.Contains(out.String(), ".agent-layer/config.toml") {
+			t.Fatalf("choice %q: calls %v, output:\n%s", input, *calls, out.String())
+		}
+	}
+
+	calls = stubUpgradeGit(t, dirty)
+	if err := guardUpgradeWorkingTree(bufio.NewReader(strings.NewReader("\n")), &bytes.Buffer{}, "/repo", "0.9.0", true); !errors.Is(err, errUpgradeCancelled) || len(*calls) != 0 {
+		t.Fatalf("default choice must cancel, got %v, calls %v", err, *calls)
+	}
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+
+	"github.com/conn-castle/agent-layer/internal/i18n"
+	"github.com/conn-castle/agent-layer/internal/install"
+	"github.com/conn-castle/agent-layer/internal/messages"
+	"github.com/conn-castle/agent-layer/internal/style"
+)
+
+var readUpgradeHistory = install.ReadUpgradeHistory
+
+func newUpgradeHistoryCmd() *cobra.Command {
+	var asJSON bool
+	cmd := &cobra.Command{
+		Use:   messages.UpgradeHistoryUse,
+		Short: messages.UpgradeHistoryShort,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			root, err := resolveRepoRoot()
+			if err != nil {
+				return err
+			}
+			records, err := listUpgradeHistory(root, install.RealSystem{})
+			if err != nil {
+				return err
+			}
+			out := cmd.OutOrStdout()
+			if asJSON {
+				encoder := json.NewEncoder(out)
+				for _, record := range records {
+					if err := encoder.Encode(record); err != nil {
+						return err
+					}
+				}
+				return nil
+			}
+			if len(records) == 0 {
+				_, err := fmt.Fprintln(out, i18n.T(messages.UpgradeHistoryNone))
+				return err
+			}
+			ew := &errWriter{w: out}
+			ew.println(messages.UpgradeHistoryListHeader)
+			for _, record := range records {
+				changes := record.TemplateChanges
+				ew.printf(messages.UpgradeHistoryListEntryFmt, record.ID, record.CreatedAtUTC,
+					upgradeHistoryVersions(record.MigrationReport),
+					len(changes.Added), len(changes.Updated), len(changes.Removed),
+					appliedMigrationCount(record.MigrationReport))
+			}
+			return ew.err
+		},
+	}
+	cmd.Flags().BoolVar(&asJSON, "json", false, messages.UpgradeHistoryJSONFlag)
+	return cmd
+}
+
+func newUpgradeShowCmd() *cobra.Command {
+	var asJSON bool
+	cmd := &cobra.Command{
+		Use:               messages.UpgradeShowUse,
+		Short:             messages.UpgradeShowShort,
+		ValidArgsFunction: completeUpgradeHistoryIDs,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 1 {
+				return i18n.Errorf(messages.UpgradeShowRequiresID)
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			root, err := resolveRepoRoot()
+			if err != nil {
+				return err
+			}
+			record, err := readUpgradeHistory(root, args[0], install.RealSystem{})
+			if err != nil {
+				return err
+			}
+			out := cmd.OutOrStdout()
+			if asJSON {
+				encoder := json.NewEncoder(out)
+				encoder.SetIndent("", "  ")
+				return encoder.Encode(record)
+			}
+			return writeUpgradeHistoryRecord(out, record)
+		},
+	}
+	cmd.Flags().BoolVar(&asJSON, "json", false, messages.UpgradeShowJSONFlag)
+	return cmd
+}
+
+// writeUpgradeHistoryRecord renders one recorded upgrade: its migration
+// report in the same layout `al upgrade plan` uses, then the changed paths.
+func writeUpgradeHistoryRecord(out io.Writer, record install.UpgradeHistoryRecord) error {
+	ew := &errWriter{w: out}
+	ew.println(style.Heading(i18n.Sprintf(messages.UpgradeShowHeaderFmt, record.ID, record.CreatedAtUTC, upgradeHistoryVersions(record.MigrationReport))))
+	if ew.err == nil {
+		ew.err = writeMigrationReportSection(out, messages.UpgradePlanSectionMigrations, record.MigrationReport)
+	}
+	sections := []struct {
+		title string
+		paths []string
+	}{
+		{messages.UpgradeShowSectionAdded, record.TemplateChanges.Added},
+		{messages.UpgradeShowSectionUpdated, record.TemplateChanges.Updated},
+		{messages.UpgradeShowSectionRemoved, record.TemplateChanges.Removed},
+	}
+	for _, section := range sections {
+		if ew.err == nil {
+			ew.err = writeSectionTitle(out, section.title)
+		}
+		if len(section.paths) == 0 {
+			ew.println(messages.UpgradePlanNone)
+			continue
+		}
+		for _, path := range section.paths {
+			ew.printf(messages.UpgradePlanSummaryLineFmt, path)
+		}
+	}
+	return ew.err
+}
+
+// upgradeHistoryVersions renders "source -> target", naming either side
+// unknown when the upgrade could not resolve it.
+func upgradeHistoryVersions(report install.UpgradeMigrationReport) string {
+	source, target := report.SourceVersion, report.TargetVersion
+	if source == "" {
+		source = string(install.UpgradeMigrationSourceUnknown)
+	}
+	if target == "" {
+		target = string(install.UpgradeMigrationSourceUnknown)
+	}
+	return fmt.Sprintf("%s -> %s", source, target)
+}
+
+func appliedMigrationCount(report install.UpgradeMigrationReport) int {
+	count := 0
+	for _, entry := range report.Entries {
+		if entry.Status == install.UpgradeMigrationStatusApplied {
+			count++
+		}
+	}
+	return count
+}
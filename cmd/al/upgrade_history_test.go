@@ -0,0 +1,150 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/conn-castle/agent-layer/internal/install"
+	"github.com/conn-castle/agent-layer/internal/messages"
+	"github.com/conn-castle/agent-layer/internal/testutil"
+)
+
+func sampleUpgradeHistoryRecord() install.UpgradeHistoryRecord {
+	return install.UpgradeHistoryRecord{
+		SchemaVersion: 1,
+		ID:            "20260101T000000Z-1",
+		CreatedAtUTC:  "2026-01-01T00:00:00Z",
+		MigrationReport: install.UpgradeMigrationReport{
+			TargetVersion: "0.6.0",
+			SourceVersion: "0.5.0",
+			Entries: []install.UpgradeMigrationEntry{
+				{ID: "rename-docs", Kind: "rename_file", Rationale: "docs moved", Status: install.UpgradeMigrationStatusApplied},
+				{ID: "noop", Kind: "delete_file", Rationale: "already gone", Status: install.UpgradeMigrationStatusNoop},
+			},
+		},
+		TemplateChanges: install.UpgradeTemplateChanges{
+			Added:   []string{".agent-layer/new.md"},
+			Updated: []string{".agent-layer/al.version", ".agent-layer/config.toml"},
+			Removed: []string{},
+		},
+	}
+}
+
+func runUpgradeSubcommand(t *testing.T, args ...string) (string, error) {
+	t.Helper()
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, ".agent-layer"), 0o700); err != nil {
+		t.Fatalf("mkdir .agent-layer: %v", err)
+	}
+	var out bytes.Buffer
+	var err error
+	testutil.WithWorkingDir(t, root, func() {
+		cmd := newUpgradeCmd()
+		cmd.SetArgs(args)
+		cmd.SetOut(&out)
+		cmd.SetErr(&bytes.Buffer{})
+		err = cmd.Execute()
+	})
+	return out.String(), err
+}
+
+func TestUpgradeHistoryCmd_ListsRecords(t *testing.T) {
+	original := listUpgradeHistory
+	listUpgradeHistory = func(string, install.System) ([]install.UpgradeHistoryRecord, error) {
+		return []install.UpgradeHistoryRecord{sampleUpgradeHistoryRecord()}, nil
+	}
+	t.Cleanup(func() { listUpgradeHistory = original })
+
+	out, err := runUpgradeSubcommand(t, "history")
+	if err != nil {
+		t.Fatalf("history: %v", err)
+	}
+	if !strings.Contains(out, messages.UpgradeHistoryListHeader) {
+		t.Fatalf("missing header:\n%s", out)
+	}
+	want := "20260101T000000Z-1 (2026-01-01T00:00:00Z, 0.5.0 -> 0.6.0): 1 added, 2 updated, 0 removed, 1 migrations applied"
+	if !strings.Contains(out, want) {
+		t.Fatalf("output missing %q:\n%s", want, out)
+	}
+}
+
+func TestUpgradeHistoryCmd_EmptyAndJSON(t *testing.T) {
+	original := listUpgradeHistory
+	var records []install.UpgradeHistoryRecord
+	listUpgradeHistory = func(string, install.System) ([]install.UpgradeHistoryRecord, error) {
+		return records, nil
+	}
+	t.Cleanup(func() { listUpgradeHistory = original })
+
+	out, err := runUpgradeSubcommand(t, "history")
+	if err != nil {
+		t.Fatalf("history: %v", err)
+	}
+	if strings.TrimSpace(out) != messages.UpgradeHistoryNone {
+		t.Fatalf("output = %q, want %q", out, messages.UpgradeHistoryNone)
+	}
+
+	records = []install.UpgradeHistoryRecord{sampleUpgradeHistoryRecord(), sampleUpgradeHistoryRecord()}
+	out, err = runUpgradeSubcommand(t, "history", "--json")
+	if err != nil {
+		t.Fatalf("history --json: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("json lines = %d, want 2:\n%s", len(lines), out)
+	}
+	var decoded install.UpgradeHistoryRecord
+	if err := json.Unmarshal([]byte(lines[0]), &decoded); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if decoded.ID != "20260101T000000Z-1" {
+		t.Fatalf("decoded id = %q", decoded.ID)
+	}
+}
+
+func TestUpgradeShowCmd_RendersRecord(t *testing.T) {
+	original := readUpgradeHistory
+	var gotID string
+	readUpgradeHistory = func(_ string, id string, _ install.System) (install.UpgradeHistoryRecord, error) {
+		gotID = id
+		return sampleUpgradeHistoryRecord(), nil
+	}
+	t.Cleanup(func() { readUpgradeHistory = original })
+
+	out, err := runUpgradeSubcommand(t, "show", "20260101T000000Z-1")
+	if err != nil {
+		t.Fatalf("show: %v", err)
+	}
+	if gotID != "20260101T000000Z-1" {
+		t.Fatalf("read id = %q", gotID)
+	}
+	for _, want := range []string{
+		"Upgrade 20260101T000000Z-1 (2026-01-01T00:00:00Z, 0.5.0 -> 0.6.0)",
+		"[applied] rename-docs (rename_file): docs moved",
+		messages.UpgradeShowSectionAdded + ":\n  - .agent-layer/new.md",
+		messages.UpgradeShowSectionUpdated + ":\n  - .agent-layer/al.version\n  - .agent-layer/config.toml",
+		messages.UpgradeShowSectionRemoved + ":\n" + messages.UpgradePlanNone,
+	} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestUpgradeShowCmd_RequiresID(t *testing.T) {
+	_, err := runUpgradeSubcommand(t, "show")
+	if err == nil || err.Error() != messages.UpgradeShowRequiresID {
+		t.Fatalf("error = %v, want %q", err, messages.UpgradeShowRequiresID)
+	}
+}
+
+func TestUpgradeShowCmd_ReadError(t *testing.T) {
+	_, err := runUpgradeSubcommand(t, "show", "missing")
+	if err == nil || !strings.Contains(err.Error(), "upgrade history missing not found") {
+		t.Fatalf("error = %v, want not found", err)
+	}
+}
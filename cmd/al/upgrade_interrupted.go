@@ -0,0 +1,59 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+
+	"github.com/conn-castle/agent-layer/internal/i18n"
+	"github.com/conn-castle/agent-layer/internal/install"
+	"github.com/conn-castle/agent-layer/internal/messages"
+)
+
+var readInterruptedOperation = install.ReadInterruptedOperation
+
+// resolveInterruptedOperation handles an init or upgrade that the install
+// journal shows stopped partway. It reports whether the installer should run
+// over the interrupted operation, and whether it rolled the interrupted
+// upgrade back instead, which ends the command. With --resume it resumes;
+// interactive upgrades offer to resume or roll back; other upgrades fail.
+func resolveInterruptedOperation(in *bufio.Reader, out io.Writer, root string, resume bool, interactive bool) (resumeInstall bool, rolledBack bool, err error) {
+	op, err := readInterruptedOperation(root, install.RealSystem{})
+	if err != nil || op == nil {
+		return false, false, err
+	}
+	if resume {
+		return true, false, nil
+	}
+	if !interactive {
+		return false, false, install.InterruptedOperationError(op)
+	}
+	step := op.Step
+	if step == "" {
+		step = messages.InstallJournalNoStep
+	}
+	if _, err := fmt.Fprintf(out, i18n.T(messages.UpgradeInterruptedFmt), op.Operation, step, op.StartedAtUTC); err != nil {
+		return false, false, err
+	}
+	options := []string{messages.UpgradeInterruptedResumeOption}
+	if op.SnapshotID != "" {
+		options = append(options, i18n.Sprintf(messages.UpgradeInterruptedRollbackOption, op.SnapshotID))
+	}
+	options = append(options, messages.UpgradeInterruptedCancelOption)
+	choice, err := promptNumberedChoice(in, out, options, len(options)-1)
+	if err != nil {
+		return false, false, err
+	}
+	switch {
+	case choice == 0:
+		return true, false, nil
+	case choice == 1 && op.SnapshotID != "":
+		if err := installRollbackUpgradeSnapshot(root, op.SnapshotID, install.RollbackUpgradeSnapshotOptions{System: install.RealSystem{}}); err != nil {
+			return false, false, err
+		}
+		_, err := fmt.Fprintf(out, i18n.T(messages.UpgradeRollbackSuccessFmt), op.SnapshotID)
+		return false, true, err
+	default:
+		return false, false, errUpgradeCancelled
+	}
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/conn-castle/agent-layer/internal/install"
+)
+
+func TestResolveInterruptedOperation(t *testing.T) {
+	origRead, origRollback := readInterruptedOperation, installRollbackUpgradeSnapshot
+	t.Cleanup(func() { readInterruptedOperation, installRollbackUpgradeSnapshot = origRead, origRollback })
+	op := &install.InterruptedOperation{Operation: "upgrade", SnapshotID: "snap-1", Step: "writeTemplateFiles", StartedAtUTC: "2026-10-16T00:00:00Z"}
+	readInterruptedOperation = func(string, install.System) (*install.InterruptedOperation, error) { return op, nil }
+	var restored string
+	installRollbackUpgradeSnapshot = func(_ string, id string, _ install.RollbackUpgradeSnapshotOptions) error {
+		restored = id
+		return nil
+	}
+	resolve := func(input string, resume bool, interactive bool) (bool, bool, error) {
+		return resolveInterruptedOperation(bufio.NewReader(strings.NewReader(input)), &bytes.Buffer{}, "/repo", resume, interactive)
+	}
+
+	if resumeInstall, rolledBack, err := resolve("", true, false); err != nil || !resumeInstall || rolledBack {
+		t.Fatalf("--resume = %v %v %v", resumeInstall, rolledBack, err)
+	}
+	if _, _, err := resolve("", false, false); !errors.Is(err, install.ErrInterruptedOperation) || !strings.Contains(err.Error(), "--resume") {
+		t.Fatalf("non-interactive = %v", err)
+	}
+	if resumeInstall, _, err := resolve("1\n", false, true); err != nil || !resumeInstall {
+		t.Fatalf("choice resume = %v %v", resumeInstall, err)
+	}
+	if _, rolledBack, err := resolve("2\n", false, true); err != nil || !rolledBack || restored != "snap-1" {
+		t.Fatalf("choice rollback = %v %v, restored %q", rolledBack, err, restored)
+	}
+	if _, _, err := resolve("\n", false, true); !errors.Is(err, errUpgradeCancelled) {
+		t.Fatalf("default choice must cancel, got %v", err)
+	}
+
+	op = &install.InterruptedOperation{Operation: "init", Step: "writeTemplateFiles"}
+	if _, _, err := resolve("2\n", false, true); !errors.Is(err, errUpgradeCancelled) {
+		t.Fatalf("init offers no rollback, choice 2 must cancel, got %v", err)
+	}
+
+	readInterruptedOperation = func(string, install.System) (*install.InterruptedOperation, error) { return nil, nil }
+	if resumeInstall, rolledBack, err := resolve("", false, false); err != nil || resumeInstall || rolledBack {
+		t.Fatalf("no journal = %v %v %v", resumeInstall, rolledBack, err)
+	}
+}
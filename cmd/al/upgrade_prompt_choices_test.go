@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	"github.com/conn-castle/agent-layer/internal/config"
+	"github.com/conn-castle/agent-layer/internal/install"
 )
 
 func TestPromptNumberedChoice_DefaultOnEmpty(t *testing.T) {
@@ -193,3 +194,71 @@ func TestPromptEnumChoice_UnknownValue_AllowCustom_DefaultsToFirst(t *testing.T)
 		t.Errorf("expected 'alpha' (first option as default), got %v", result)
 	}
 }
+
+func TestPromptConfigChoice_CustomValueRePrompts(t *testing.T) {
+	t.Run("allow-custom enum", func(t *testing.T) {
+		field := config.FieldDef{Key: "test.model", Type: config.FieldEnum, Options: []config.FieldOption{{Value: "alpha"}}, AllowCustom: true}
+		out := &bytes.Buffer{}
+		result, err := promptConfigChoice(bufio.NewReader(strings.NewReader("2\n \nmy-model\n")), out, "test.model", "alpha", field)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result != "my-model" {
+			t.Fatalf("expected custom value, got %v", result)
+		}
+		if !strings.Contains(out.String(), "2) Enter a custom value") || !strings.Contains(out.String(), "Invalid value: test.model must not be empty") {
+			t.Fatalf("unexpected output:\n%s", out.String())
+		}
+	})
+
+	t.Run("positive int", func(t *testing.T) {
+		field := config.FieldDef{Key: "test.depth", Type: config.FieldPositiveInt}
+		out := &bytes.Buffer{}
+		result, err := promptConfigChoice(bufio.NewReader(strings.NewReader("n\n0\n5\n")), out, "test.depth", float64(3), field)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result != int64(5) {
+			t.Fatalf("expected 5, got %v (%T)", result, result)
+		}
+		if strings.Count(out.String(), "Enter value for test.depth") != 2 {
+			t.Fatalf("expected one re-prompt, got:\n%s", out.String())
+		}
+	})
+
+	t.Run("invalid at EOF", func(t *testing.T) {
+		field := config.FieldDef{Key: "test.depth", Type: config.FieldPositiveInt}
+		_, err := promptConfigChoice(bufio.NewReader(strings.NewReader("n\nabc")), &bytes.Buffer{}, "test.depth", float64(3), field)
+		if err == nil || !strings.Contains(err.Error(), "must be a positive integer") {
+			t.Fatalf("expected validation error, got %v", err)
+		}
+	})
+}
+
+func TestPromptUpgradeSourceVersion(t *testing.T) {
+	candidates := []install.BaselineCandidate{
+		{Version: "1.1.0", Matched: 4, Present: 5},
+		{Version: "1.0.0", Matched: 3, Present: 5},
+	}
+	cases := []struct {
+		input string
+		want  string
+	}{
+		{input: "\n", want: "1.1.0"},
+		{input: "2\n", want: "1.0.0"},
+		{input: "3\n", want: ""},
+	}
+	for _, tc := range cases {
+		out := &bytes.Buffer{}
+		got, err := promptUpgradeSourceVersion(bufio.NewReader(strings.NewReader(tc.input)), out, candidates)
+		if err != nil {
+			t.Fatalf("input %q: unexpected error: %v", tc.input, err)
+		}
+		if got != tc.want {
+			t.Errorf("input %q: got %q, want %q", tc.input, got, tc.want)
+		}
+		if !strings.Contains(out.String(), "1) v1.1.0 (4 of 5 present managed files match)") || !strings.Contains(out.String(), "3) None of these") {
+			t.Errorf("unexpected prompt output:\n%s", out.String())
+		}
+	}
+}
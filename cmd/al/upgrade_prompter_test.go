@@ -324,6 +324,7 @@ func enableTestColorOutput(t *testing.T) {
 	origIsTerminal := isTerminal
 	isTerminal = func() bool { return true }
 	t.Cleanup(func() { isTerminal = origIsTerminal })
+	stubUpgradePlan(t, install.UpgradePlan{})
 
 	origNoColor := color.NoColor
 	color.NoColor = false
@@ -351,6 +352,7 @@ func disableTestColorOutput(t *testing.T) {
 	origIsTerminal := isTerminal
 	isTerminal = func() bool { return true }
 	t.Cleanup(func() { isTerminal = origIsTerminal })
+	stubUpgradePlan(t, install.UpgradePlan{})
 
 	origNoColor := color.NoColor
 	color.NoColor = true
@@ -490,6 +492,13 @@ func TestWriteMigrationReportSection_WithEntries(t *testing.T) {
 				Status:     install.UpgradeMigrationStatusSkippedUnknownSource,
 				SkipReason: "source version is unknown",
 			},
+			{
+				ID:        "fix_dry_flag",
+				Kind:      "transform_file",
+				Rationale: "Fix flag reference",
+				Status:    install.UpgradeMigrationStatusPlanned,
+				Diff:      "--- a (current)\n+++ a (transformed)\n-old\n+new\n",
+			},
 		},
 	}
 	if err := writeMigrationReportSection(&buf, "Migrations", report); err != nil {
@@ -508,6 +517,9 @@ func TestWriteMigrationReportSection_WithEntries(t *testing.T) {
 	if !strings.Contains(output, "reason: source version is unknown") {
 		t.Fatalf("expected skip reason in output:\n%s", output)
 	}
+	if !strings.Contains(output, "      -old\n      +new") {
+		t.Fatalf("expected transform diff in output:\n%s", output)
+	}
 }
 
 func TestWriteUpgradeSkippedCategoryNotes_AllSkipped(t *testing.T) {
@@ -0,0 +1,234 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/conn-castle/agent-layer/internal/errcode"
+	"github.com/conn-castle/agent-layer/internal/i18n"
+	"github.com/conn-castle/agent-layer/internal/install"
+	"github.com/conn-castle/agent-layer/internal/messages"
+	"github.com/conn-castle/agent-layer/internal/style"
+)
+
+var buildUpgradePlanFunc = install.BuildUpgradePlan
+
+// upgradeRiskDecision records which risk groups the user approved before
+// apply, so install prompts can be answered without asking again.
+type upgradeRiskDecision struct {
+	approved map[install.UpgradeRisk]bool
+	pathRisk map[string]install.UpgradeRisk
+}
+
+func (d upgradeRiskDecision) allows(risk install.UpgradeRisk) bool {
+	return d.approved[risk]
+}
+
+// allowsPath reports whether overwriting path was approved. Paths the plan did
+// not foresee are treated as user-file overwrites.
+func (d upgradeRiskDecision) allowsPath(path string) bool {
+	risk, ok := d.pathRisk[strings.TrimSpace(path)]
+	if !ok {
+		risk = install.UpgradeRiskOverwrite
+	}
+	return d.allows(risk)
+}
+
+// errUpgradeCancelled is returned when the user declines a risk group that
+// cannot be skipped.
+var errUpgradeCancelled = i18n.NewError(messages.UpgradeRiskCancelled)
+
+// confirmUpgradeRisks prints the grouped risk summary and collects approvals.
+// Groups at or below maxRisk are approved without prompting. In interactive
+// mode the remaining low-risk groups share one yes/no prompt and each
+// high-risk group requires typing its risk name. Without a terminal, groups
+// above maxRisk are skipped when possible and fail otherwise.
+func confirmUpgradeRisks(in *bufio.Reader, out io.Writer, groups []install.UpgradeRiskGroup, maxRisk install.UpgradeRisk, interactive bool) (upgradeRiskDecision, error) {
+	decision := upgradeRiskDecision{
+		approved: make(map[install.UpgradeRisk]bool, len(groups)),
+		pathRisk: make(map[string]install.UpgradeRisk),
+	}
+	for _, group := range groups {
+		for _, item := range group.Items {
+			if !item.Migration {
+				decision.pathRisk[item.Path] = group.Risk
+			}
+		}
+	}
+	if len(groups) == 0 {
+		return decision, nil
+	}
+	if err := writeUpgradeRiskSummary(out, groups); err != nil {
+		return decision, err
+	}
+
+	var oneKeypress []install.UpgradeRiskGroup
+	var typed []install.UpgradeRiskGroup
+	for _, group := range groups {
+		switch {
+		case maxRisk != "" && group.Risk.AtMost(maxRisk):
+			decision.approved[group.Risk] = true
+		case !interactive:
+			if !group.Skippable {
+				return decision, errcode.Wrap(errcode.UpgradeConflict, i18n.Errorf(messages.UpgradeRiskExceedsMaxFmt, group.Risk.Label(), group.Risk, maxRisk, group.Risk))
+			}
+			if _, err := fmt.Fprintf(out, i18n.T(messages.UpgradeRiskSkippedFmt), group.Risk.Label(), maxRisk); err != nil {
+				return decision, err
+			}
+		case group.Risk.RequiresTypedConfirmation():
+			typed = append(typed, group)
+		default:
+			oneKeypress = append(oneKeypress, group)
+		}
+	}
+
+	if len(oneKeypress) > 0 {
+		labels := make([]string, 0, len(oneKeypress))
+		for _, group := range oneKeypress {
+			labels = append(labels, group.Risk.Label())
+		}
+		apply, err := promptYesNo(in, out, i18n.Sprintf(messages.UpgradeRiskApplyPromptFmt, strings.Join(labels, ", ")), true)
+		if err != nil {
+			return decision, err
+		}
+		if !apply {
+			return decision, errUpgradeCancelled
+		}
+		for _, group := range oneKeypress {
+			decision.approved[group.Risk] = true
+		}
+	}
+
+	for _, group := range typed {
+		approved, err := promptTypedRiskConfirmation(in, out, group)
+		if err != nil {
+			return decision, err
+		}
+		if !approved {
+			if !group.Skippable {
+				return decision, errUpgradeCancelled
+			}
+			if _, err := fmt.Fprintf(out, i18n.T(messages.UpgradeRiskDeclinedFmt), group.Risk.Label()); err != nil {
+				return decision, err
+			}
+			continue
+		}
+		decision.approved[group.Risk] = true
+	}
+	return decision, nil
+}
+
+// promptTypedRiskConfirmation asks the user to type the group's risk name.
+// An empty answer declines; any other text re-prompts.
+func promptTypedRiskConfirmation(in *bufio.Reader, out io.Writer, group install.UpgradeRiskGroup) (bool, error) {
+	declineHint := messages.UpgradeRiskTypedSkipHint
+	if !group.Skippable {
+		declineHint = messages.UpgradeRiskTypedCancelHint
+	}
+	if err := checkPrompt(i18n.Sprintf(messages.UpgradeRiskTypedPromptFmt, group.Risk, group.Risk.Label(), declineHint)); err != nil {
+		return false, err
+	}
+	for {
+		if _, err := fmt.Fprintf(out, i18n.T(messages.UpgradeRiskTypedPromptFmt), group.Risk, group.Risk.Label(), declineHint); err != nil {
+			return false, err
+		}
+		line, err := in.ReadString('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			return false, err
+		}
+		response := strings.TrimSpace(line)
+		if strings.EqualFold(response, string(group.Risk)) {
+			return true, nil
+		}
+		if response == "" || errors.Is(err, io.EOF) {
+			return false, nil
+		}
+		if _, err := fmt.Fprintf(out, i18n.T(messages.UpgradeRiskTypedRetryFmt), group.Risk); err != nil {
+			return false, err
+		}
+	}
+}
+
+// writeUpgradeRiskSummary renders planned changes grouped by risk label.
+func writeUpgradeRiskSummary(out io.Writer, groups []install.UpgradeRiskGroup) error {
+	if _, err := fmt.Fprintln(out, style.Heading(messages.UpgradeRiskSummaryHeader)); err != nil {
+		return err
+	}
+	for _, group := range groups {
+		heading := i18n.Sprintf(messages.UpgradeRiskGroupFmt, group.Risk, group.Risk.Label(), len(group.Items))
+		if group.Risk.RequiresTypedConfirmation() {
+			heading = style.Warning(heading)
+		}
+		if _, err := fmt.Fprintln(out, heading); err != nil {
+			return err
+		}
+		for _, item := range group.Items {
+			if _, err := fmt.Fprintf(out, i18n.T(messages.UpgradeRiskItemFmt), item.Path, item.Detail); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// applyUpgradeRiskDecision answers file-level install prompts from the risk
+// approvals collected up front. Config value prompts and the separately gated
+// tmp deletion prompt keep their existing behavior.
+func applyUpgradeRiskDecision(funcs install.PromptFuncs, decision upgradeRiskDecision) install.PromptFuncs {
+	managedAll := func(previews []install.DiffPreview) bool {
+		for _, preview := range previews {
+			if !decision.allowsPath(preview.Path) {
+				return false
+			}
+		}
+		return true
+	}
+	funcs.OverwriteAllPreviewFunc = func(previews []install.DiffPreview) (bool, error) {
+		return managedAll(previews), nil
+	}
+	funcs.OverwriteAllMemoryPreviewFunc = func([]install.DiffPreview) (bool, error) {
+		return decision.allows(install.UpgradeRiskOverwrite), nil
+	}
+	funcs.OverwriteAllUnifiedPreviewFunc = func(managedPreviews []install.DiffPreview, _ []install.DiffPreview) (bool, bool, error) {
+		return managedAll(managedPreviews), decision.allows(install.UpgradeRiskOverwrite), nil
+	}
+	funcs.OverwritePreviewFunc = func(preview install.DiffPreview) (bool, error) {
+		return decision.allowsPath(preview.Path), nil
+	}
+	funcs.StatuslineSourcePreviewFunc = func(install.DiffPreview) (bool, error) {
+		return decision.allows(install.UpgradeRiskOverwrite), nil
+	}
+	funcs.DeleteUnknownAllFunc = func([]string) (bool, error) {
+		return decision.allows(install.UpgradeRiskDestructive), nil
+	}
+	funcs.DeleteUnknownFunc = func(string) (bool, error) {
+		return decision.allows(install.UpgradeRiskDestructive), nil
+	}
+	funcs.ConfirmSkillsMigrationFunc = func(_ []string, conflicts []install.SkillsMigrationConflict) (bool, error) {
+		if len(conflicts) > 0 {
+			return false, nil
+		}
+		return decision.allows(install.UpgradeRiskDestructive), nil
+	}
+	return funcs
+}
+
+// writeUpgradePlanRiskSection renders per-risk change counts in the dry-run plan.
+func writeUpgradePlanRiskSection(out io.Writer, groups []install.UpgradeRiskGroup) error {
+	if err := writeSectionTitle(out, messages.UpgradePlanSectionRisk); err != nil {
+		return err
+	}
+	if len(groups) == 0 {
+		_, err := fmt.Fprintln(out, i18n.T(messages.UpgradePlanNone))
+		return err
+	}
+	for _, group := range groups {
+		if _, err := fmt.Fprintf(out, i18n.T(messages.UpgradePlanRiskItemFmt), group.Risk, group.Risk.Label(), len(group.Items)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
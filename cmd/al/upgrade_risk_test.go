@@ -0,0 +1,255 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/conn-castle/agent-layer/internal/install"
+	"github.com/conn-castle/agent-layer/internal/messages"
+	"github.com/conn-castle/agent-layer/internal/testutil"
+)
+
+func testRiskGroups() []install.UpgradeRiskGroup {
+	return []install.UpgradeRiskGroup{
+		{Risk: install.UpgradeRiskSafe, Items: []install.UpgradeRiskItem{{Path: ".agent-layer/new.md", Detail: "new file"}}},
+		{Risk: install.UpgradeRiskConfig, Items: []install.UpgradeRiskItem{{Path: "a.b -> a.c", Detail: "rename", Migration: true}}},
+		{Risk: install.UpgradeRiskOverwrite, Items: []install.UpgradeRiskItem{{Path: ".agent-layer/instructions/01.md", Detail: "local customization"}}, Skippable: true},
+		{Risk: install.UpgradeRiskDestructive, Items: []install.UpgradeRiskItem{{Path: ".agent-layer/stale.md", Detail: "delete"}}, Skippable: true},
+	}
+}
+
+func TestConfirmUpgradeRisks_InteractiveTypedConfirmation(t *testing.T) {
+	var out bytes.Buffer
+	in := bufio.NewReader(strings.NewReader("y\nwrong\noverwrite\n\n"))
+
+	decision, err := confirmUpgradeRisks(in, &out, testRiskGroups(), "", true)
+	if err != nil {
+		t.Fatalf("confirmUpgradeRisks error: %v", err)
+	}
+	for risk, want := range map[install.UpgradeRisk]bool{
+		install.UpgradeRiskSafe:        true,
+		install.UpgradeRiskConfig:      true,
+		install.UpgradeRiskOverwrite:   true,
+		install.UpgradeRiskDestructive: false,
+	} {
+		if decision.allows(risk) != want {
+			t.Fatalf("allows(%s) = %v, want %v", risk, !want, want)
+		}
+	}
+	output := out.String()
+	for _, want := range []string{
+		messages.UpgradeRiskSummaryHeader,
+		".agent-layer/stale.md",
+		"Type 'overwrite' exactly",
+		"Info: skipping destructive migration changes.",
+	} {
+		if !strings.Contains(output, want) {
+			t.Fatalf("expected %q in output:\n%s", want, output)
+		}
+	}
+	if decision.allowsPath(".agent-layer/stale.md") || !decision.allowsPath(".agent-layer/instructions/01.md") {
+		t.Fatalf("unexpected path approvals")
+	}
+	if !decision.allowsPath("unplanned.md") {
+		t.Fatalf("unplanned paths should follow overwrite approval")
+	}
+}
+
+func TestConfirmUpgradeRisks_DecliningUnskippableCancels(t *testing.T) {
+	in := bufio.NewReader(strings.NewReader("n\n"))
+	_, err := confirmUpgradeRisks(in, &bytes.Buffer{}, testRiskGroups(), "", true)
+	if !errors.Is(err, errUpgradeCancelled) {
+		t.Fatalf("expected cancellation, got %v", err)
+	}
+
+	groups := []install.UpgradeRiskGroup{{Risk: install.UpgradeRiskDestructive, Items: []install.UpgradeRiskItem{{Path: "x", Migration: true}}}}
+	_, err = confirmUpgradeRisks(bufio.NewReader(strings.NewReader("")), &bytes.Buffer{}, groups, "", true)
+	if !errors.Is(err, errUpgradeCancelled) {
+		t.Fatalf("expected cancellation on EOF, got %v", err)
+	}
+}
+
+func TestConfirmUpgradeRisks_MaxRiskWithoutTerminal(t *testing.T) {
+	var out bytes.Buffer
+	decision, err := confirmUpgradeRisks(bufio.NewReader(strings.NewReader("")), &out, testRiskGroups(), install.UpgradeRiskConfig, false)
+	if err != nil {
+		t.Fatalf("confirmUpgradeRisks error: %v", err)
+	}
+	if !decision.allows(install.UpgradeRiskConfig) || decision.allows(install.UpgradeRiskOverwrite) {
+		t.Fatalf("unexpected approvals: %+v", decision.approved)
+	}
+	if !strings.Contains(out.String(), "above --max-risk config") {
+		t.Fatalf("expected skip note, got:\n%s", out.String())
+	}
+
+	_, err = confirmUpgradeRisks(bufio.NewReader(strings.NewReader("")), &bytes.Buffer{}, testRiskGroups(), install.UpgradeRiskSafe, false)
+	if err == nil || !strings.Contains(err.Error(), "--max-risk config") {
+		t.Fatalf("expected unskippable config error, got %v", err)
+	}
+}
+
+func TestConfirmUpgradeRisks_NoGroups(t *testing.T) {
+	var out bytes.Buffer
+	decision, err := confirmUpgradeRisks(bufio.NewReader(strings.NewReader("")), &out, nil, "", true)
+	if err != nil || out.Len() != 0 {
+		t.Fatalf("expected silent no-op, got err=%v output=%q", err, out.String())
+	}
+	if decision.allows(install.UpgradeRiskSafe) {
+		t.Fatalf("expected no approvals")
+	}
+}
+
+func TestApplyUpgradeRiskDecision(t *testing.T) {
+	decision := upgradeRiskDecision{
+		approved: map[install.UpgradeRisk]bool{install.UpgradeRiskSafe: true},
+		pathRisk: map[string]install.UpgradeRisk{"safe.md": install.UpgradeRiskSafe, "edited.md": install.UpgradeRiskOverwrite},
+	}
+	funcs := applyUpgradeRiskDecision(install.PromptFuncs{}, decision)
+
+	if ok, _ := funcs.OverwritePreviewFunc(install.DiffPreview{Path: "safe.md"}); !ok {
+		t.Fatalf("expected safe path to be overwritten")
+	}
+	if ok, _ := funcs.OverwriteAllPreviewFunc([]install.DiffPreview{{Path: "safe.md"}, {Path: "edited.md"}}); ok {
+		t.Fatalf("expected overwrite-all to decline when an edited file is not approved")
+	}
+	managed, memory, _ := funcs.OverwriteAllUnifiedPreviewFunc([]install.DiffPreview{{Path: "safe.md"}}, nil)
+	if !managed || memory {
+		t.Fatalf("unified = (%v, %v), want (true, false)", managed, memory)
+	}
+	if ok, _ := funcs.DeleteUnknownAllFunc([]string{"x"}); ok {
+		t.Fatalf("expected deletions to be declined")
+	}
+
+	decision.approved[install.UpgradeRiskDestructive] = true
+	funcs = applyUpgradeRiskDecision(install.PromptFuncs{}, decision)
+	if ok, _ := funcs.DeleteUnknownFunc("x"); !ok {
+		t.Fatalf("expected deletion to be approved")
+	}
+	if ok, _ := funcs.ConfirmSkillsMigrationFunc(nil, []install.SkillsMigrationConflict{{}}); ok {
+		t.Fatalf("expected skills migration with conflicts to be declined")
+	}
+}
+
+func TestResolveUpgradeApplyPolicy_MaxRisk(t *testing.T) {
+	policy, err := resolveUpgradeApplyPolicy(upgradeApplyInputs{maxRisk: "Config"})
+	if err != nil {
+		t.Fatalf("resolveUpgradeApplyPolicy error: %v", err)
+	}
+	if !policy.riskGated || !policy.yes || policy.maxRisk != install.UpgradeRiskConfig {
+		t.Fatalf("policy = %+v", policy)
+	}
+
+	if _, err := resolveUpgradeApplyPolicy(upgradeApplyInputs{maxRisk: "safe", applyManaged: true}); err == nil || err.Error() != messages.UpgradeMaxRiskConflictsApplyFlags {
+		t.Fatalf("expected conflict error, got %v", err)
+	}
+	if _, err := resolveUpgradeApplyPolicy(upgradeApplyInputs{maxRisk: "nope"}); err == nil {
+		t.Fatal("expected invalid risk error")
+	}
+
+	policy, err = resolveUpgradeApplyPolicy(upgradeApplyInputs{interactive: true})
+	if err != nil || !policy.riskGated {
+		t.Fatalf("expected interactive upgrades to be risk gated, got %+v (%v)", policy, err)
+	}
+	policy, err = resolveUpgradeApplyPolicy(upgradeApplyInputs{interactive: true, applyManaged: true})
+	if err != nil || policy.riskGated {
+		t.Fatalf("expected explicit apply flags to bypass the risk gate, got %+v (%v)", policy, err)
+	}
+}
+
+func TestUpgradeCmd_MaxRiskNonInteractive(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, ".agent-layer"), 0o700); err != nil {
+		t.Fatalf("mkdir .agent-layer: %v", err)
+	}
+
+	origIsTerminal := isTerminal
+	isTerminal = func() bool { return false }
+	t.Cleanup(func() { isTerminal = origIsTerminal })
+	stubUpgradePlan(t, install.UpgradePlan{RiskGroups: testRiskGroups()})
+	stubSyncRunNoop(t)
+
+	var captured install.Options
+	origInstallRun := installRun
+	installRun = func(_ string, opts install.Options) error {
+		captured = opts
+		return nil
+	}
+	t.Cleanup(func() { installRun = origInstallRun })
+
+	testutil.WithWorkingDir(t, root, func() {
+		cmd := newUpgradeCmd()
+		var stdout bytes.Buffer
+		cmd.SetArgs([]string{"--max-risk", "config"})
+		cmd.SetOut(&stdout)
+		cmd.SetErr(&bytes.Buffer{})
+		cmd.SetIn(bytes.NewBufferString(""))
+
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("execute upgrade: %v", err)
+		}
+		if !strings.Contains(stdout.String(), "skipping user-file overwrite changes") {
+			t.Fatalf("expected overwrite skip note, got:\n%s", stdout.String())
+		}
+	})
+
+	if captured.Prompter == nil {
+		t.Fatal("expected prompter to be wired")
+	}
+	if ok, _ := captured.Prompter.OverwriteAll(nil); !ok {
+		t.Fatalf("expected empty overwrite-all to be approved")
+	}
+	if ok, _ := captured.Prompter.DeleteUnknownAll([]string{"x"}); ok {
+		t.Fatalf("expected deletions above --max-risk to be declined")
+	}
+}
+
+func TestUpgradeCmd_OperationFilterFlags(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, ".agent-layer"), 0o700); err != nil {
+		t.Fatalf("mkdir .agent-layer: %v", err)
+	}
+
+	origIsTerminal := isTerminal
+	isTerminal = func() bool { return false }
+	t.Cleanup(func() { isTerminal = origIsTerminal })
+	stubSyncRunNoop(t)
+
+	var planFilter install.UpgradeOperationFilter
+	origPlan := buildUpgradePlanFunc
+	buildUpgradePlanFunc = func(_ string, opts install.UpgradePlanOptions) (install.UpgradePlan, error) {
+		planFilter = opts.OperationFilter
+		return install.UpgradePlan{}, nil
+	}
+	t.Cleanup(func() { buildUpgradePlanFunc = origPlan })
+	var captured install.Options
+	origInstallRun := installRun
+	installRun = func(_ string, opts install.Options) error {
+		captured = opts
+		return nil
+	}
+	t.Cleanup(func() { installRun = origInstallRun })
+
+	testutil.WithWorkingDir(t, root, func() {
+		cmd := newUpgradeCmd()
+		cmd.SetArgs([]string{"--max-risk", "safe", "--skip", "skills_format_migration", "--skip", ".agent-layer/commands.allow"})
+		cmd.SetOut(&bytes.Buffer{})
+		cmd.SetErr(&bytes.Buffer{})
+		cmd.SetIn(bytes.NewBufferString(""))
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("execute upgrade: %v", err)
+		}
+	})
+
+	want := []string{"skills_format_migration", ".agent-layer/commands.allow"}
+	if strings.Join(captured.OperationFilter.Skip, ",") != strings.Join(want, ",") {
+		t.Fatalf("install filter = %+v", captured.OperationFilter)
+	}
+	if strings.Join(planFilter.Skip, ",") != strings.Join(want, ",") {
+		t.Fatalf("risk gate planned without the filter: %+v", planFilter)
+	}
+}
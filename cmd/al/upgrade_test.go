@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"errors"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
@@ -63,6 +64,7 @@ func TestUpgradeCmd_YesWithoutApplyFlagsErrors(t *testing.T) {
 	origIsTerminal := isTerminal
 	isTerminal = func() bool { return true }
 	t.Cleanup(func() { isTerminal = origIsTerminal })
+	stubUpgradePlan(t, install.UpgradePlan{})
 
 	origInstallRun := installRun
 	installCalled := false
@@ -217,6 +219,7 @@ func TestUpgradeCmd_SuccessMessageOnCompletion(t *testing.T) {
 	origIsTerminal := isTerminal
 	isTerminal = func() bool { return true }
 	t.Cleanup(func() { isTerminal = origIsTerminal })
+	stubUpgradePlan(t, install.UpgradePlan{})
 
 	origInstallRun := installRun
 	installRun = func(string, install.Options) error { return nil }
@@ -285,6 +288,8 @@ func TestUpgradeCmd_RunsSyncAfterSuccessfulInstall(t *testing.T) {
 	isTerminal = func() bool { return false }
 	t.Cleanup(func() { isTerminal = origIsTerminal })
 
+	stubUpgradeVerify(t, nil, nil, "")
+
 	origInstallRun := installRun
 	installRun = func(string, install.Options) error { return nil }
 	t.Cleanup(func() { installRun = origInstallRun })
@@ -292,7 +297,7 @@ func TestUpgradeCmd_RunsSyncAfterSuccessfulInstall(t *testing.T) {
 	origSyncRun := syncRun
 	var syncCalls int
 	var syncRoot string
-	syncRun = func(gotRoot string) (*alsync.Result, error) {
+	syncRun = func(gotRoot string, _ io.Writer) (*alsync.Result, error) {
 		syncCalls++
 		syncRoot = gotRoot
 		return &alsync.Result{}, nil
@@ -336,12 +341,14 @@ func TestUpgradeCmd_SyncWarningsPrintedToStderr(t *testing.T) {
 	isTerminal = func() bool { return false }
 	t.Cleanup(func() { isTerminal = origIsTerminal })
 
+	stubUpgradeVerify(t, nil, nil, "")
+
 	origInstallRun := installRun
 	installRun = func(string, install.Options) error { return nil }
 	t.Cleanup(func() { installRun = origInstallRun })
 
 	origSyncRun := syncRun
-	syncRun = func(string) (*alsync.Result, error) {
+	syncRun = func(string, io.Writer) (*alsync.Result, error) {
 		return &alsync.Result{
 			Warnings: []warnings.Warning{{Message: "trusted folders not set"}},
 		}, nil
@@ -384,7 +391,7 @@ func TestUpgradeCmd_SyncFailureWrappedNonFatalLoud(t *testing.T) {
 
 	syncErr := errors.New("config invalid")
 	origSyncRun := syncRun
-	syncRun = func(string) (*alsync.Result, error) {
+	syncRun = func(string, io.Writer) (*alsync.Result, error) {
 		return nil, syncErr
 	}
 	t.Cleanup(func() { syncRun = origSyncRun })
@@ -432,7 +439,7 @@ func TestUpgradeCmd_SyncNotRunWhenInstallFails(t *testing.T) {
 
 	origSyncRun := syncRun
 	syncCalled := false
-	syncRun = func(string) (*alsync.Result, error) {
+	syncRun = func(string, io.Writer) (*alsync.Result, error) {
 		syncCalled = true
 		return &alsync.Result{}, nil
 	}
@@ -463,6 +470,7 @@ func TestUpgradeCmd_InteractiveWiresPrompter(t *testing.T) {
 	origIsTerminal := isTerminal
 	isTerminal = func() bool { return true }
 	t.Cleanup(func() { isTerminal = origIsTerminal })
+	stubUpgradePlan(t, install.UpgradePlan{})
 
 	origInstallRun := installRun
 	installRun = func(string, install.Options) error {
@@ -522,6 +530,7 @@ func TestUpgradeCmd_InteractiveApplyManagedAutoApprovesOnlyManaged(t *testing.T)
 	origIsTerminal := isTerminal
 	isTerminal = func() bool { return true }
 	t.Cleanup(func() { isTerminal = origIsTerminal })
+	stubUpgradePlan(t, install.UpgradePlan{})
 
 	origInstallRun := installRun
 	var captured install.Options
@@ -608,6 +617,7 @@ func TestUpgradeCmd_MissingAgentLayerErrors(t *testing.T) {
 	origIsTerminal := isTerminal
 	isTerminal = func() bool { return true }
 	t.Cleanup(func() { isTerminal = origIsTerminal })
+	stubUpgradePlan(t, install.UpgradePlan{})
 
 	testutil.WithWorkingDir(t, root, func() {
 		cmd := newUpgradeCmd()
@@ -1,9 +1,11 @@
 package main
 
 import (
+	"io"
 	"path/filepath"
 	"testing"
 
+	"github.com/conn-castle/agent-layer/internal/install"
 	alsync "github.com/conn-castle/agent-layer/internal/sync"
 )
 
@@ -15,12 +17,22 @@ func canonicalPath(path string) string {
 	return filepath.Clean(path)
 }
 
-// stubSyncRunNoop replaces the package-level syncRun stub with a no-op for the
-// duration of the test. Used when a test mocks installRun to skip real install
-// work and therefore has no real `.agent-layer/config.toml` for sync to load.
+// stubSyncRunNoop replaces the package-level syncRun stub, and the
+// post-upgrade verification that follows it, with no-ops for the duration of
+// the test. Used when a test mocks installRun to skip real install work and
+// therefore has no real `.agent-layer/config.toml` for sync to load.
 func stubSyncRunNoop(t *testing.T) {
 	t.Helper()
 	orig := syncRun
-	syncRun = func(string) (*alsync.Result, error) { return &alsync.Result{}, nil }
+	syncRun = func(string, io.Writer) (*alsync.Result, error) { return &alsync.Result{}, nil }
 	t.Cleanup(func() { syncRun = orig })
+	stubUpgradeVerify(t, nil, nil, "")
+}
+
+// stubUpgradePlan replaces the risk-gate plan builder with one that returns plan.
+func stubUpgradePlan(t *testing.T, plan install.UpgradePlan) {
+	t.Helper()
+	orig := buildUpgradePlanFunc
+	buildUpgradePlanFunc = func(string, install.UpgradePlanOptions) (install.UpgradePlan, error) { return plan, nil }
+	t.Cleanup(func() { buildUpgradePlanFunc = orig })
 }
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+
+	"github.com/conn-castle/agent-layer/internal/config"
+	"github.com/conn-castle/agent-layer/internal/i18n"
+	"github.com/conn-castle/agent-layer/internal/install"
+	"github.com/conn-castle/agent-layer/internal/messages"
+)
+
+var syncCheck = checkSyncOutputs
+var loadUpgradeVerifyProject = config.LoadProjectConfig
+
+// runUpgradeVerifyCommand runs one [[upgrade.verify]] command from the repo
+// root with its output passed through.
+var runUpgradeVerifyCommand = func(root string, verify config.UpgradeVerifyCommand, stdout io.Writer, stderr io.Writer) error {
+	cmd := exec.Command(verify.Command, verify.Args...) // #nosec G204 -- commands come from the repo's own config.toml.
+	cmd.Dir = root
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	return cmd.Run()
+}
+
+// verifyUpgrade checks that the post-upgrade sync left no generated file out
+// of date, then runs the configured [[upgrade.verify]] commands in order,
+// stopping at the first failure.
+func verifyUpgrade(stdout io.Writer, stderr io.Writer, root string) error {
+	_, _ = fmt.Fprintln(stdout, i18n.T(messages.UpgradeVerifyCheckingSync))
+	stale, err := syncCheck(root)
+	if err != nil {
+		return err
+	}
+	if len(stale) > 0 {
+		return reportStalePaths(stderr, stale)
+	}
+	project, err := loadUpgradeVerifyProject(root)
+	if err != nil {
+		return err
+	}
+	for _, verify := range project.Config.Upgrade.Verify {
+		line := strings.Join(append([]string{verify.Command}, verify.Args...), " ")
+		_, _ = fmt.Fprintf(stdout, i18n.T(messages.UpgradeVerifyRunningFmt), line)
+		if err := runUpgradeVerifyCommand(root, verify, stdout, stderr); err != nil {
+			return i18n.Errorf(messages.UpgradeVerifyCommandFailedFmt, line, err)
+		}
+	}
+	return nil
+}
+
+// handleUpgradeVerifyFailure points at the snapshot the upgrade just applied.
+// With autoRollback it restores that snapshot and regenerates client outputs
+// from the restored sources instead.
+func handleUpgradeVerifyFailure(stdout io.Writer, stderr io.Writer, root string, verifyErr error, autoRollback bool) error {
+	snapshotID := latestAppliedSnapshotID(root)
+	if snapshotID == "" {
+		return i18n.Errorf(messages.UpgradeVerifyNoSnapshotFmt, verifyErr)
+	}
+	if !autoRollback {
+		return i18n.Errorf(messages.UpgradeVerifyFailedFmt, verifyErr, snapshotID)
+	}
+	_, _ = fmt.Fprintf(stdout, i18n.T(messages.UpgradeVerifyRollingBackFmt), snapshotID)
+	if err := installRollbackUpgradeSnapshot(root, snapshotID, install.RollbackUpgradeSnapshotOptions{
+		System: install.RealSystem{},
+	}); err != nil {
+		return i18n.Errorf(messages.UpgradeVerifyRollbackFailedFmt, verifyErr, snapshotID, err)
+	}
+	if _, err := syncRun(root, stderr); err != nil {
+		_, _ = fmt.Fprintf(stderr, i18n.T(messages.UpgradeVerifyResyncFailedFmt), snapshotID, err)
+	}
+	return i18n.Errorf(messages.UpgradeVerifyRolledBackFmt, snapshotID, verifyErr)
+}
+
+// latestAppliedSnapshotID returns the newest applied upgrade snapshot, which
+// is the one the current upgrade created, or "" when there is none.
+func latestAppliedSnapshotID(root string) string {
+	snapshots, err := listUpgradeSnapshots(root, install.RealSystem{})
+	if err != nil {
+		return ""
+	}
+	for _, snapshot := range snapshots {
+		if snapshot.Status == install.UpgradeSnapshotStatusApplied {
+			return snapshot.ID
+		}
+	}
+	return ""
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/conn-castle/agent-layer/internal/config"
+	"github.com/conn-castle/agent-layer/internal/install"
+	alsync "github.com/conn-castle/agent-layer/internal/sync"
+)
+
+func stubUpgradeVerify(t *testing.T, stale []string, verify []config.UpgradeVerifyCommand, failing string) *[]string {
+	t.Helper()
+	origCheck, origLoad, origRun := syncCheck, loadUpgradeVerifyProject, runUpgradeVerifyCommand
+	t.Cleanup(func() {
+		syncCheck, loadUpgradeVerifyProject, runUpgradeVerifyCommand = origCheck, origLoad, origRun
+	})
+	var ran []string
+	syncCheck = func(string) ([]string, error) { return stale, nil }
+	loadUpgradeVerifyProject = func(string) (*config.ProjectConfig, error) {
+		return &config.ProjectConfig{Config: config.Config{Upgrade: config.UpgradeConfig{Verify: verify}}}, nil
+	}
+	runUpgradeVerifyCommand = func(_ string, cmd config.UpgradeVerifyCommand, _ io.Writer, _ io.Writer) error {
+		ran = append(ran, cmd.Command)
+		if cmd.Command == failing {
+			return errors.New("exit status 2")
+		}
+		return nil
+	}
+	return &ran
+}
+
+func TestVerifyUpgrade(t *testing.T) {
+	verify := []config.UpgradeVerifyCommand{{Command: "make", Args: []string{"lint"}}, {Command: "go"}, {Command: "never"}}
+
+	ran := stubUpgradeVerify(t, nil, verify, "go")
+	err := verifyUpgrade(&bytes.Buffer{}, &bytes.Buffer{}, "/repo")
+	if err == nil || !strings.Contains(err.Error(), `"go" failed`) || len(*ran) != 2 {
+		t.Fatalf("verifyUpgrade = %v, ran %v", err, *ran)
+	}
+
+	ran = stubUpgradeVerify(t, []string{"CLAUDE.md"}, verify, "")
+	var stderr bytes.Buffer
+	if err := verifyUpgrade(&bytes.Buffer{}, &stderr, "/repo"); err == nil || len(*ran) != 0 || !strings.Contains(stderr.String(), "CLAUDE.md") {
+		t.Fatalf("stale outputs = %v, ran %v, stderr %q", err, *ran, stderr.String())
+	}
+
+	stubUpgradeVerify(t, nil, verify[:2], "")
+	if err := verifyUpgrade(&bytes.Buffer{}, &bytes.Buffer{}, "/repo"); err != nil {
+		t.Fatalf("passing verification = %v", err)
+	}
+}
+
+func TestHandleUpgradeVerifyFailure(t *testing.T) {
+	origList, origRollback, origSync := listUpgradeSnapshots, installRollbackUpgradeSnapshot, syncRun
+	t.Cleanup(func() {
+		listUpgradeSnapshots, installRollbackUpgradeSnapshot, syncRun = origList, origRollback, origSync
+	})
+	listUpgradeSnapshots = func(string, install.System) ([]install.UpgradeSnapshotMetadata, error) {
+		return []install.UpgradeSnapshotMetadata{
+			{ID: "snap-3", Status: "auto_rolled_back"},
+			{ID: "snap-2", Status: install.UpgradeSnapshotStatusApplied},
+		}, nil
+	}
+	var restored string
+	installRollbackUpgradeSnapshot = func(_ string, id string, _ install.RollbackUpgradeSnapshotOptions) error {
+		restored = id
+		return nil
+	}
+	var synced bool
+	syncRun = func(string, io.Writer) (*alsync.Result, error) {
+		synced = true
+		return &alsync.Result{}, nil
+	}
+	verifyErr := errors.New("tests failed")
+
+	err := handleUpgradeVerifyFailure(&bytes.Buffer{}, &bytes.Buffer{}, "/repo", verifyErr, false)
+	if !errors.Is(err, verifyErr) || !strings.Contains(err.Error(), "al upgrade rollback snap-2") || restored != "" {
+		t.Fatalf("without --auto-rollback = %v, restored %q", err, restored)
+	}
+
+	err = handleUpgradeVerifyFailure(&bytes.Buffer{}, &bytes.Buffer{}, "/repo", verifyErr, true)
+	if !errors.Is(err, verifyErr) || !strings.Contains(err.Error(), "rolled back to snapshot snap-2") || restored != "snap-2" || !synced {
+		t.Fatalf("with --auto-rollback = %v, restored %q, synced %v", err, restored, synced)
+	}
+}
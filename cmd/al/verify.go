@@ -0,0 +1,67 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/conn-castle/agent-layer/internal/i18n"
+	"github.com/conn-castle/agent-layer/internal/integrity"
+	"github.com/conn-castle/agent-layer/internal/messages"
+)
+
+var verifyIntegrity = integrity.Verify
+
+func newVerifyCmd() *cobra.Command {
+	var asJSON bool
+
+	cmd := &cobra.Command{
+		Use:   messages.VerifyUse,
+		Short: messages.VerifyShort,
+		Long:  messages.VerifyLong,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			root, err := resolveRepoRoot()
+			if err != nil {
+				return err
+			}
+			report, err := verifyIntegrity(root)
+			if err != nil {
+				return err
+			}
+			out := cmd.OutOrStdout()
+			if asJSON {
+				encoder := json.NewEncoder(out)
+				encoder.SetIndent("", "  ")
+				if err := encoder.Encode(report); err != nil {
+					return err
+				}
+			} else {
+				for _, check := range report.Checks {
+					if check.Status == integrity.StatusOK {
+						continue
+					}
+					label := check.Status
+					if label == integrity.StatusFail {
+						label = strings.ToUpper(label)
+					}
+					if _, err := fmt.Fprintf(out, i18n.T(messages.VerifyCheckFmt), label, check.Kind, check.Name, check.Detail); err != nil {
+						return err
+					}
+				}
+				counts := report.Counts
+				if _, err := fmt.Fprintf(out, i18n.T(messages.VerifySummaryFmt), len(report.Checks), counts[integrity.StatusOK], counts[integrity.StatusModified], counts[integrity.StatusMissing], counts[integrity.StatusSkipped], counts[integrity.StatusFail]); err != nil {
+					return err
+				}
+			}
+			if !report.OK {
+				return i18n.Errorf(messages.VerifyFailedFmt, report.Failed(), len(report.Checks))
+			}
+			return nil
+		},
+	}
+	cmd.Flags().BoolVar(&asJSON, "json", false, messages.VerifyJSONFlag)
+	return cmd
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/conn-castle/agent-layer/internal/integrity"
+)
+
+func stubVerifyIntegrity(t *testing.T, report integrity.Report) {
+	t.Helper()
+	original := verifyIntegrity
+	verifyIntegrity = func(string) (integrity.Report, error) { return report, nil }
+	t.Cleanup(func() { verifyIntegrity = original })
+}
+
+func TestVerifyCmd(t *testing.T) {
+	stubRepoRoot(t)
+	stubVerifyIntegrity(t, integrity.Report{
+		SchemaVersion: integrity.ReportSchemaVersion,
+		Counts:        map[string]int{integrity.StatusOK: 1, integrity.StatusModified: 1, integrity.StatusFail: 1},
+		Checks: []integrity.Check{
+			{Kind: "pin", Name: "0.14.0", Status: integrity.StatusOK},
+			{Kind: "managed", Name: ".agent-layer/instructions/00_rules.md", Status: integrity.StatusModified, Detail: "differs from the v0.14.0 template"},
+			{Kind: "skill", Name: "review", Status: integrity.StatusFail, Detail: "contents changed"},
+		},
+	})
+
+	cmd := newVerifyCmd()
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs(nil)
+	err := cmd.Execute()
+	if err == nil || err.Error() != "verification failed for 1 of 3 checks" {
+		t.Fatalf("expected verification failure, got %v", err)
+	}
+	for _, want := range []string{
+		"modified managed .agent-layer/instructions/00_rules.md: differs from the v0.14.0 template",
+		"FAIL     skill review: contents changed",
+		"Verified 3 checks: 1 ok, 1 modified, 0 missing, 0 skipped, 1 failed.",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Fatalf("expected %q in output:\n%s", want, out.String())
+		}
+	}
+	if strings.Contains(out.String(), "pin 0.14.0") {
+		t.Fatalf("ok checks should not be listed:\n%s", out.String())
+	}
+}
+
+func TestVerifyCmd_JSON(t *testing.T) {
+	stubRepoRoot(t)
+	stubVerifyIntegrity(t, integrity.Report{
+		SchemaVersion: integrity.ReportSchemaVersion,
+		OK:            true,
+		Counts:        map[string]int{integrity.StatusOK: 1},
+		Checks:        []integrity.Check{{Kind: "pin", Name: "0.14.0", Status: integrity.StatusOK}},
+	})
+
+	cmd := newVerifyCmd()
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"--json"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("verify: %v", err)
+	}
+	var decoded map[string]any
+	if err := json.Unmarshal(out.Bytes(), &decoded); err != nil {
+		t.Fatalf("decode report: %v\n%s", err, out.String())
+	}
+	if decoded["ok"] != true || decoded["schema_version"] != float64(1) {
+		t.Fatalf("unexpected report: %s", out.String())
+	}
+	checks, _ := decoded["checks"].([]any)
+	if len(checks) != 1 || checks[0].(map[string]any)["kind"] != "pin" {
+		t.Fatalf("unexpected checks: %s", out.String())
+	}
+}
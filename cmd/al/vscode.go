@@ -6,10 +6,12 @@ import (
 	"github.com/conn-castle/agent-layer/internal/clients/vscode"
 	"github.com/conn-castle/agent-layer/internal/config"
 	"github.com/conn-castle/agent-layer/internal/messages"
+	"github.com/conn-castle/agent-layer/internal/run"
 )
 
 func newVSCodeCmd() *cobra.Command {
-	return newNoSyncLaunchCmd(
+	workspace := false
+	cmd := newNoSyncLaunchCmd(
 		messages.VSCodeUse,
 		messages.VSCodeShort,
 		"vscode",
@@ -17,6 +19,37 @@ func newVSCodeCmd() *cobra.Command {
 			v := config.IsAgentEnabled(cfg.Agents.VSCode.Enabled) || config.IsAgentEnabled(cfg.Agents.ClaudeVSCode.Enabled)
 			return &v
 		},
-		vscode.Launch,
+		func(project *config.ProjectConfig, runInfo *run.Info, env []string, args []string) error {
+			if workspace {
+				return vscode.LaunchWorkspace(project, runInfo, env, args)
+			}
+			return vscode.Launch(project, runInfo, env, args)
+		},
 	)
+	runE := cmd.RunE
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		workspace, args = splitWorkspaceArg(args)
+		return runE(cmd, args)
+	}
+	cmd.Flags().Bool("workspace", false, "Open the generated .code-workspace file instead of the repo folder")
+	return cmd
+}
+
+// splitWorkspaceArg removes --workspace from the Agent Layer arguments before
+// "--", because flag parsing is disabled for pass-through.
+func splitWorkspaceArg(args []string) (bool, []string) {
+	workspace := false
+	out := make([]string, 0, len(args))
+	for i, arg := range args {
+		if arg == "--" {
+			out = append(out, args[i:]...)
+			break
+		}
+		if arg == workspaceFlag {
+			workspace = true
+			continue
+		}
+		out = append(out, arg)
+	}
+	return workspace, out
 }
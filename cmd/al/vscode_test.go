@@ -3,6 +3,7 @@ package main
 import (
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"testing"
 
@@ -140,3 +141,16 @@ func TestRunVSCodeNoSyncManagedBlockConflict(t *testing.T) {
 		t.Fatalf("unexpected error: %v", err)
 	}
 }
+
+func TestSplitWorkspaceArg(t *testing.T) {
+	workspace, args := splitWorkspaceArg([]string{"--workspace", "--no-sync", "--", "--workspace"})
+	if !workspace {
+		t.Fatal("expected --workspace before the separator to be consumed")
+	}
+	if want := []string{"--no-sync", "--", "--workspace"}; !slices.Equal(args, want) {
+		t.Fatalf("args = %v, want %v", args, want)
+	}
+	if workspace, _ := splitWorkspaceArg([]string{"--", "--workspace"}); workspace {
+		t.Fatal("expected --workspace after the separator to pass through")
+	}
+}
@@ -7,6 +7,8 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"github.com/conn-castle/agent-layer/internal/errcode"
+	"github.com/conn-castle/agent-layer/internal/i18n"
 	"github.com/conn-castle/agent-layer/internal/messages"
 	alsync "github.com/conn-castle/agent-layer/internal/sync"
 	"github.com/conn-castle/agent-layer/internal/terminal"
@@ -54,17 +56,17 @@ func newWizardCmd() *cobra.Command {
 					return cleanErr
 				}
 				if len(removed) == 0 {
-					_, _ = fmt.Fprintln(cmd.OutOrStdout(), messages.WizardCleanupBackupsNone)
+					_, _ = fmt.Fprintln(cmd.OutOrStdout(), i18n.T(messages.WizardCleanupBackupsNone))
 					return nil
 				}
-				_, _ = fmt.Fprintln(cmd.OutOrStdout(), messages.WizardCleanupBackupsHeader)
+				_, _ = fmt.Fprintln(cmd.OutOrStdout(), i18n.T(messages.WizardCleanupBackupsHeader))
 				for _, path := range removed {
-					_, _ = fmt.Fprintf(cmd.OutOrStdout(), messages.WizardCleanupBackupsPathFmt, path)
+					_, _ = fmt.Fprintf(cmd.OutOrStdout(), i18n.T(messages.WizardCleanupBackupsPathFmt), path)
 				}
 				return nil
 			}
 
-			updatewarn.WarnIfOutdated(cmd.Context(), Version, cmd.ErrOrStderr())
+			updatewarn.WarnIfOutdated(cmd.Context(), Version, commandOutput(cmd, noiseModeFromConfig(root)).Info())
 
 			pinned, err := resolvePinVersion("", Version)
 			if err != nil {
@@ -72,12 +74,12 @@ func newWizardCmd() *cobra.Command {
 			}
 
 			if profilePath != "" && answersPath != "" {
-				return errors.New(messages.WizardProfileAnswersConflict)
+				return errors.New(i18n.T(messages.WizardProfileAnswersConflict))
 			}
 			// --yes only applies to profile application; reject it for --answers
 			// and the interactive path so it never fails silently.
 			if yes && profilePath == "" {
-				return errors.New(messages.WizardYesRequiresProfile)
+				return errors.New(i18n.T(messages.WizardYesRequiresProfile))
 			}
 
 			if profilePath != "" {
@@ -89,7 +91,7 @@ func newWizardCmd() *cobra.Command {
 			}
 
 			if !isTerminal() {
-				return errors.New(messages.WizardRequiresTerminal)
+				return errcode.Wrap(errcode.InputRequired, errors.New(i18n.T(messages.WizardRequiresTerminal)))
 			}
 
 			return runWizard(root, pinned)
@@ -0,0 +1,218 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+var (
+	// markdownLinkRegexp matches [text](target) and ![alt](target), with an
+	// optional quoted title after the target.
+	markdownLinkRegexp = regexp.MustCompile(`!?\[[^\]]*\]\(\s*<?([^)\s>]+)>?(?:\s+"[^"]*")?\s*\)`)
+	// attrLinkRegexp matches href, src, and Link `to` attributes in MDX/JSX.
+	attrLinkRegexp = regexp.MustCompile(`\b(?:href|src|to)=["']([^"']+)["']`)
+	schemeRegexp   = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9+.-]*:`)
+	codeSpanRegexp = regexp.MustCompile("`[^`]*`")
+)
+
+// pageExtensions are the source extensions Docusaurus turns into routes.
+var pageExtensions = []string{".md", ".mdx", ".js", ".jsx", ".ts", ".tsx"}
+
+// siteLayout locates the trees a link check resolves against.
+type siteLayout struct {
+	docsDir   string
+	pagesDir  string
+	staticDir string
+}
+
+// brokenLink is one unresolved reference found by findBrokenLinks.
+type brokenLink struct {
+	file   string
+	line   int
+	target string
+	reason string
+}
+
+func (b brokenLink) String() string {
+	return fmt.Sprintf("%s:%d: %s (%s)", b.file, b.line, b.target, b.reason)
+}
+
+// findBrokenLinks scans the Markdown and MDX files under the docs and pages
+// trees for relative links, site routes, and images that do not resolve, and
+// for links into docs versions that retention will not keep. External URLs
+// and in-page anchors are not checked.
+func findBrokenLinks(site siteLayout, retained map[string]bool) ([]brokenLink, error) {
+	latestSlugs, err := collectDocSlugs(site.docsDir)
+	if err != nil {
+		return nil, err
+	}
+	var broken []brokenLink
+	for _, tree := range []struct {
+		label string
+		dir   string
+	}{{"docs", site.docsDir}, {"src/pages", site.pagesDir}} {
+		if _, err := os.Stat(tree.dir); os.IsNotExist(err) {
+			continue
+		}
+		err := filepathWalkFunc(tree.dir, func(filePath string, info os.FileInfo, walkErr error) error {
+			if walkErr != nil {
+				return walkErr
+			}
+			if info.IsDir() || !isDocMarkdownFile(filePath) {
+				return nil
+			}
+			rel, err := filepath.Rel(tree.dir, filePath)
+			if err != nil {
+				return err
+			}
+			data, err := osReadFileFunc(filePath)
+			if err != nil {
+				return err
+			}
+			label := tree.label + "/" + filepath.ToSlash(rel)
+			for _, ref := range extractLinks(string(data)) {
+				reason := checkLink(site, latestSlugs, retained, filepath.Dir(filePath), ref.target)
+				if reason != "" {
+					broken = append(broken, brokenLink{file: label, line: ref.line, target: ref.target, reason: reason})
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	sort.SliceStable(broken, func(i, j int) bool {
+		if broken[i].file != broken[j].file {
+			return broken[i].file < broken[j].file
+		}
+		return broken[i].line < broken[j].line
+	})
+	return broken, nil
+}
+
+type linkRef struct {
+	line   int
+	target string
+}
+
+// extractLinks returns link targets outside fenced code blocks and inline
+// code spans, with their 1-based line numbers.
+func extractLinks(content string) []linkRef {
+	var refs []linkRef
+	inFence := false
+	for idx, line := range strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			inFence = !inFence
+			continue
+		}
+		if inFence {
+			continue
+		}
+		line = codeSpanRegexp.ReplaceAllString(line, "")
+		for _, re := range []*regexp.Regexp{markdownLinkRegexp, attrLinkRegexp} {
+			for _, match := range re.FindAllStringSubmatch(line, -1) {
+				refs = append(refs, linkRef{line: idx + 1, target: match[1]})
+			}
+		}
+	}
+	return refs
+}
+
+// checkLink returns why target does not resolve, or "" when it does.
+func checkLink(site siteLayout, latestSlugs map[string]struct{}, retained map[string]bool, fromDir, target string) string {
+	if target == "" || strings.HasPrefix(target, "#") || schemeRegexp.MatchString(target) || strings.HasPrefix(target, "//") {
+		return ""
+	}
+	target, _, _ = strings.Cut(target, "#")
+	target, _, _ = strings.Cut(target, "?")
+	if target == "" {
+		return ""
+	}
+	if !strings.HasPrefix(target, "/") {
+		if resolvesToFile(filepath.Join(fromDir, filepath.FromSlash(target))) {
+			return ""
+		}
+		return "target not found"
+	}
+
+	route := path.Clean(target)
+	if route == "/docs" || strings.HasPrefix(route, "/docs/") {
+		rest := strings.Trim(strings.TrimPrefix(route, "/docs"), "/")
+		first, _, _ := strings.Cut(rest, "/")
+		if _, err := parseVersion(first); err == nil {
+			if !retained[first] {
+				return fmt.Sprintf("docs version %s is not retained", first)
+			}
+			return ""
+		}
+		if _, ok := latestSlugs[rest]; ok {
+			return ""
+		}
+		return "no doc with this slug"
+	}
+	if isFile(filepath.Join(site.staticDir, filepath.FromSlash(route))) {
+		return ""
+	}
+	if resolvesToPage(filepath.Join(site.pagesDir, filepath.FromSlash(strings.TrimPrefix(route, "/")))) {
+		return ""
+	}
+	if strings.HasPrefix(route, "/img/") {
+		return "image not found in static/"
+	}
+	return "no page or static file for this route"
+}
+
+// resolvesToFile reports whether a relative link target exists as written,
+// as a Markdown doc without its extension, or as a directory index.
+func resolvesToFile(target string) bool {
+	if isFile(target) {
+		return true
+	}
+	for _, ext := range []string{".md", ".mdx"} {
+		if isFile(target+ext) || isFile(filepath.Join(target, "index"+ext)) {
+			return true
+		}
+	}
+	return false
+}
+
+// resolvesToPage reports whether base names a src/pages route.
+func resolvesToPage(base string) bool {
+	for _, ext := range pageExtensions {
+		if isFile(base+ext) || isFile(filepath.Join(base, "index"+ext)) {
+			return true
+		}
+	}
+	return false
+}
+
+func isFile(p string) bool {
+	info, err := osStatFunc(p)
+	return err == nil && !info.IsDir()
+}
+
+// validateLinks fails with a report listing every broken link.
+func validateLinks(site siteLayout, retained []string) error {
+	keep := make(map[string]bool, len(retained))
+	for _, v := range retained {
+		keep[v] = true
+	}
+	broken, err := findBrokenLinks(site, keep)
+	if err != nil {
+		return fmt.Errorf("failed to check links: %w", err)
+	}
+	if len(broken) == 0 {
+		return nil
+	}
+	lines := make([]string, 0, len(broken))
+	for _, b := range broken {
+		lines = append(lines, "  "+b.String())
+	}
+	return fmt.Errorf("found %d broken link(s):\n%s", len(broken), strings.Join(lines, "\n"))
+}
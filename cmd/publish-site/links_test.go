@@ -0,0 +1,112 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/conn-castle/agent-layer/internal/testutil"
+)
+
+func TestExtractLinks(t *testing.T) {
+	content := strings.Join([]string{
+		"See [reference](./reference#sync) and ![logo](/img/logo.svg \"Logo\").",
+		"`[not a link](./code-span)`",
+		"```md",
+		"[also not](./fenced)",
+		"```",
+		`<a href="/docs" className="x">Docs</a> <Link to="/install">Install</Link>`,
+	}, "\n")
+	refs := extractLinks(content)
+	var got []string
+	for _, ref := range refs {
+		got = append(got, ref.target)
+	}
+	want := []string{"./reference#sync", "/img/logo.svg", "/docs", "/install"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Fatalf("links = %v, want %v", got, want)
+	}
+	if refs[2].line != 6 {
+		t.Fatalf("expected line 6 for attribute link, got %d", refs[2].line)
+	}
+}
+
+func TestFindBrokenLinks(t *testing.T) {
+	root := t.TempDir()
+	site := siteLayout{
+		docsDir:   filepath.Join(root, "docs"),
+		pagesDir:  filepath.Join(root, "src", "pages"),
+		staticDir: filepath.Join(root, "static"),
+	}
+	writeFile(t, filepath.Join(site.docsDir, "overview.mdx"), "---\nslug: /\n---\n"+strings.Join([]string{
+		"[ok relative](./guide)",
+		"[ok with anchor](./guide#setup)",
+		"[ok anchor only](#intro)",
+		"[ok external](https://example.com/missing)",
+		"[ok route](/docs/guide)",
+		"[ok retained](/docs/0.2.0/guide)",
+		"[ok page](/install)",
+		"[ok image](/img/logo.svg)",
+		"[broken relative](./missing)",
+		"[broken slug](/docs/missing)",
+		"[pruned](/docs/0.1.0/guide)",
+	}, "\n"))
+	writeFile(t, filepath.Join(site.docsDir, "guide.md"), "[home](/docs)\n")
+	writeFile(t, filepath.Join(site.pagesDir, "install.mdx"), "[root](/) ![gone](/img/gone.png) [nowhere](/nowhere)\n")
+	writeFile(t, filepath.Join(site.pagesDir, "index.mdx"), "# Home\n")
+	writeFile(t, filepath.Join(site.staticDir, "img", "logo.svg"), "<svg/>")
+
+	broken, err := findBrokenLinks(site, map[string]bool{"0.2.0": true})
+	if err != nil {
+		t.Fatalf("findBrokenLinks: %v", err)
+	}
+	var got []string
+	for _, b := range broken {
+		got = append(got, b.String())
+	}
+	want := []string{
+		"docs/overview.mdx:12: ./missing (target not found)",
+		"docs/overview.mdx:13: /docs/missing (no doc with this slug)",
+		"docs/overview.mdx:14: /docs/0.1.0/guide (docs version 0.1.0 is not retained)",
+		"src/pages/install.mdx:1: /img/gone.png (image not found in static/)",
+		"src/pages/install.mdx:1: /nowhere (no page or static file for this route)",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("broken links:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestValidateLinks(t *testing.T) {
+	root := t.TempDir()
+	site := siteLayout{docsDir: filepath.Join(root, "docs"), pagesDir: filepath.Join(root, "pages"), staticDir: filepath.Join(root, "static")}
+	writeFile(t, filepath.Join(site.docsDir, "a.md"), "[ok](./a)\n")
+	if err := validateLinks(site, nil); err != nil {
+		t.Fatalf("expected no broken links, got %v", err)
+	}
+
+	writeFile(t, filepath.Join(site.docsDir, "b.md"), "[bad](./c)\n")
+	err := validateLinks(site, nil)
+	if err == nil || !strings.Contains(err.Error(), "found 1 broken link(s):\n  docs/b.md:1: ./c (target not found)") {
+		t.Fatalf("expected broken link report, got %v", err)
+	}
+
+	withReadFileError(t, filepath.Join(site.docsDir, "b.md"), os.ErrPermission)
+	if err := validateLinks(site, nil); err == nil || !strings.Contains(err.Error(), "failed to check links") {
+		t.Fatalf("expected read error, got %v", err)
+	}
+}
+
+func TestRun_BrokenLinksFailBeforeDocusaurus(t *testing.T) {
+	repoA := setupRepoA(t, repoAOptions{withPages: true, withDocs: true, withChangelog: true})
+	repoB := setupRepoB(t)
+	writeFile(t, filepath.Join(repoA, "site", "docs", "reference.mdx"), "[gone](./gone)\n")
+	withHelperCommand(t, "HELPER_FAIL=1")
+
+	testutil.WithWorkingDir(t, repoA, func() {
+		setArgs(t, "--tag", "v0.1.0", "--repo-b-dir", repoB)
+		if err := run(); err == nil || !strings.Contains(err.Error(), "docs/reference.mdx:1: ./gone") {
+			t.Fatalf("expected broken link error, got %v", err)
+		}
+	})
+}
@@ -10,9 +10,11 @@ package main
 
 import (
 	"context"
+	"crypto/sha256"
 	"encoding/json"
 	"flag"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -35,6 +37,9 @@ func run() error {
 	tag := flag.String("tag", "", "Git tag to publish, e.g. v0.6.0 (required)")
 	repoBDir := flag.String("repo-b-dir", "", "Path to local checkout of agent-layer-web (required)")
 	docusaurusTimeout := flag.Duration("docusaurus-timeout", 5*time.Minute, "Timeout for docusaurus docs:version (e.g. 5m, 30s)")
+	newestMinorPatches := flag.Int("retain-newest-minor-patches", retainNewestMinorPatches, "Number of newest patch releases kept from the newest minor line")
+	recentMinorLines := flag.Int("retain-recent-minor-lines", retainRecentMinorLines, "Number of recent minor lines whose newest patch release is kept")
+	dryRun := flag.Bool("dry-run", false, "Report the files, versions, and versions.json ordering the publish would produce without touching Repo B or running docusaurus")
 	flag.Parse()
 
 	if *tag == "" {
@@ -46,6 +51,12 @@ func run() error {
 	if *docusaurusTimeout <= 0 {
 		return fmt.Errorf("--docusaurus-timeout must be a positive duration")
 	}
+	if *newestMinorPatches < 1 {
+		return fmt.Errorf("--retain-newest-minor-patches must be at least 1")
+	}
+	if *recentMinorLines < 1 {
+		return fmt.Errorf("--retain-recent-minor-lines must be at least 1")
+	}
 
 	if err := validateTagFormat(*tag); err != nil {
 		return err
@@ -65,6 +76,11 @@ func run() error {
 	if err := validateRepoBRoot(repoB); err != nil {
 		return err
 	}
+	keep, err := readKeepList(repoB)
+	if err != nil {
+		return err
+	}
+	policy := retentionPolicy{newestMinorPatches: *newestMinorPatches, recentMinorLines: *recentMinorLines, keep: keep}
 
 	sitePages := filepath.Join(repoA, "site", "pages")
 	siteDocs := filepath.Join(repoA, "site", "docs")
@@ -84,7 +100,11 @@ func run() error {
 		return fmt.Errorf("failed to stat Repo A changelog: %w", err)
 	}
 
-	// Publish unversioned pages by replacing Repo B src/pages.
+	if *dryRun {
+		return writePublishPlan(os.Stdout, repoA, repoB, docsVersion, policy)
+	}
+
+	// Publish unversioned pages by mirroring them into Repo B src/pages.
 	if err := publishPages(repoA, repoB); err != nil {
 		return fmt.Errorf("failed to copy pages: %w", err)
 	}
@@ -92,9 +112,11 @@ func run() error {
 	// Copy docs staging.
 	dstDocs := filepath.Join(repoB, "docs")
 	fmt.Printf("Copying %s -> %s\n", siteDocs, dstDocs)
-	if err := copyTree(siteDocs, dstDocs); err != nil {
+	docsResult, err := copyTree(siteDocs, dstDocs)
+	if err != nil {
 		return fmt.Errorf("failed to copy docs: %w", err)
 	}
+	fmt.Printf("Docs: %s\n", docsResult)
 
 	// Sync canonical changelog into Repo B root for website rendering.
 	changelogData, err := osReadFileFunc(changelogSrc)
@@ -112,6 +134,17 @@ func run() error {
 		return fmt.Errorf("failed to ensure idempotent version: %w", err)
 	}
 
+	// Fail before snapshotting when the published content has broken links,
+	// including links into versions retention is about to prune.
+	fmt.Println("Checking links...")
+	versions, err := planVersions(repoB, docsVersion, policy)
+	if err != nil {
+		return err
+	}
+	if err := validateLinks(siteLayout{docsDir: dstDocs, pagesDir: filepath.Join(repoB, "src", "pages"), staticDir: filepath.Join(repoB, "static")}, versions.retained); err != nil {
+		return err
+	}
+
 	// Snapshot docs version.
 	fmt.Printf("Running docusaurus docs:version %s...\n", docsVersion)
 	ctx, cancel := context.WithTimeout(context.Background(), *docusaurusTimeout)
@@ -131,7 +164,7 @@ func run() error {
 
 	// Normalize versions.json ordering.
 	fmt.Println("Normalizing versions.json...")
-	if err := normalizeVersionsJSON(repoB); err != nil {
+	if err := normalizeVersionsJSON(repoB, policy); err != nil {
 		return fmt.Errorf("failed to normalize versions.json: %w", err)
 	}
 
@@ -173,11 +206,28 @@ var osWriteFileFunc = os.WriteFile
 var filepathWalkFunc = filepath.Walk
 
 const (
+	// retainNewestMinorPatches and retainRecentMinorLines are the default
+	// retention policy; flags override them per publish.
 	retainNewestMinorPatches = 4
 	retainRecentMinorLines   = 4
 	redirectManifestName     = "redirect-manifest.json"
+	// keepListName is the Repo B file listing versions that are always
+	// retained regardless of the retention policy, e.g. LTS releases.
+	keepListName = "versions.keep.json"
 )
 
+// retentionPolicy decides which docs versions survive a publish.
+type retentionPolicy struct {
+	newestMinorPatches int
+	recentMinorLines   int
+	// keep holds pinned versions that are never pruned.
+	keep map[string]bool
+}
+
+func defaultRetentionPolicy() retentionPolicy {
+	return retentionPolicy{newestMinorPatches: retainNewestMinorPatches, recentMinorLines: retainRecentMinorLines}
+}
+
 // guidePageSpec maps a canonical Markdown guide and public header snippet to a
 // generated Docusaurus page filename.
 type guidePageSpec struct {
@@ -255,38 +305,187 @@ func validateRepoBRoot(repoB string) error {
 	return nil
 }
 
-// publishPages stages unversioned pages, overlays generated guide pages, and
-// replaces Repo B's src/pages tree with the staged output.
-func publishPages(repoA, repoB string) error {
+// stagePages copies site/pages into a temp dir and overlays the generated
+// guide pages. The returned cleanup removes the staging dir.
+func stagePages(repoA string) (string, func(), error) {
 	sitePages := filepath.Join(repoA, "site", "pages")
 	stagedPages, err := os.MkdirTemp("", "agent-layer-site-pages-*")
 	if err != nil {
-		return fmt.Errorf("failed to create page staging dir: %w", err)
+		return "", nil, fmt.Errorf("failed to create page staging dir: %w", err)
 	}
-	defer func() {
+	cleanup := func() {
 		_ = os.RemoveAll(stagedPages)
-	}()
+	}
 
-	if err := copyTree(sitePages, stagedPages); err != nil {
-		return fmt.Errorf("failed to stage pages: %w", err)
+	if _, err := copyTree(sitePages, stagedPages); err != nil {
+		cleanup()
+		return "", nil, fmt.Errorf("failed to stage pages: %w", err)
 	}
 	for _, spec := range defaultGuidePageSpecs {
 		if err := generateGuidePage(repoA, stagedPages, spec); err != nil {
-			return fmt.Errorf("failed to generate guide pages: %w", err)
+			cleanup()
+			return "", nil, fmt.Errorf("failed to generate guide pages: %w", err)
 		}
 	}
+	return stagedPages, cleanup, nil
+}
+
+// publishPages stages unversioned pages, overlays generated guide pages, and
+// mirrors the staged output into Repo B's src/pages tree.
+func publishPages(repoA, repoB string) error {
+	stagedPages, cleanup, err := stagePages(repoA)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
 
 	dstPages := filepath.Join(repoB, "src", "pages")
 	if err := os.MkdirAll(filepath.Join(repoB, "src"), 0o755); err != nil { // #nosec G301 -- publish tool runs in the developer's own checkout; the src/ tree it mirrors must be world-readable for Docusaurus builds.
 		return fmt.Errorf("failed to create src dir: %w", err)
 	}
 	fmt.Printf("Copying staged pages %s -> %s\n", stagedPages, dstPages)
-	if err := copyTree(stagedPages, dstPages); err != nil {
+	result, err := copyTree(stagedPages, dstPages)
+	if err != nil {
 		return fmt.Errorf("failed to copy staged pages: %w", err)
 	}
+	fmt.Printf("Pages: %s\n", result)
 	return nil
 }
 
+// versionPlan is the versions.json outcome of publishing one docs version.
+type versionPlan struct {
+	// replaced is true when the version already has a snapshot.
+	replaced bool
+	retained []string
+	dropped  []string
+}
+
+// planVersions predicts versions.json after docs:version adds docsVersion and
+// retention runs, without writing anything.
+func planVersions(repoB, docsVersion string, policy retentionPolicy) (versionPlan, error) {
+	existing, err := readVersionsJSON(repoB)
+	if err != nil {
+		return versionPlan{}, fmt.Errorf("failed to read versions.json: %w", err)
+	}
+	var plan versionPlan
+	candidates := []string{docsVersion}
+	for _, v := range existing {
+		if v == docsVersion {
+			plan.replaced = true
+			continue
+		}
+		candidates = append(candidates, v)
+	}
+	plan.retained, plan.dropped, err = selectRetainedVersions(sortVersionsNewestFirst(candidates), policy)
+	if err != nil {
+		return versionPlan{}, err
+	}
+	return plan, nil
+}
+
+// writePublishPlan reports what publishing docsVersion would change in Repo
+// B: the page and docs files to write or remove, the changelog, the version
+// snapshot, and the versions.json ordering after retention. Pages are staged
+// in a temp dir; Repo B is never written and docusaurus is not run. Broken
+// links in the staged content are reported after the plan and fail the run.
+func writePublishPlan(out io.Writer, repoA, repoB, docsVersion string, policy retentionPolicy) error {
+	stagedPages, cleanup, err := stagePages(repoA)
+	if err != nil {
+		return fmt.Errorf("failed to plan pages: %w", err)
+	}
+	defer cleanup()
+	pages, err := planTree(stagedPages, filepath.Join(repoB, "src", "pages"))
+	if err != nil {
+		return fmt.Errorf("failed to plan pages: %w", err)
+	}
+	siteDocs := filepath.Join(repoA, "site", "docs")
+	docs, err := planTree(siteDocs, filepath.Join(repoB, "docs"))
+	if err != nil {
+		return fmt.Errorf("failed to plan docs: %w", err)
+	}
+	changelogChanged, err := fileContentDiffers(filepath.Join(repoA, "CHANGELOG.md"), filepath.Join(repoB, "CHANGELOG.md"))
+	if err != nil {
+		return fmt.Errorf("failed to compare changelog: %w", err)
+	}
+	versions, err := planVersions(repoB, docsVersion, policy)
+	if err != nil {
+		return err
+	}
+
+	var b strings.Builder
+	b.WriteString("Dry run: Repo B was not modified and docusaurus was not run.\n")
+	writeTreePlan(&b, "src/pages", pages)
+	writeTreePlan(&b, "docs", docs)
+	if changelogChanged {
+		b.WriteString("\nCHANGELOG.md: would update\n")
+	} else {
+		b.WriteString("\nCHANGELOG.md: unchanged\n")
+	}
+	if versions.replaced {
+		fmt.Fprintf(&b, "\nVersion %s: would recreate the existing snapshot\n", docsVersion)
+	} else {
+		fmt.Fprintf(&b, "\nVersion %s: would create a new snapshot\n", docsVersion)
+	}
+	b.WriteString("\nversions.json after publish:\n")
+	for _, v := range versions.retained {
+		fmt.Fprintf(&b, "  %s\n", v)
+	}
+	b.WriteString("\nVersions to prune:\n")
+	if len(versions.dropped) == 0 {
+		b.WriteString("  (none)\n")
+	}
+	for _, v := range versions.dropped {
+		fmt.Fprintf(&b, "  %s\n", v)
+	}
+	if _, err := io.WriteString(out, b.String()); err != nil {
+		return err
+	}
+	return validateLinks(siteLayout{docsDir: siteDocs, pagesDir: stagedPages, staticDir: filepath.Join(repoB, "static")}, versions.retained)
+}
+
+func writeTreePlan(b *strings.Builder, label string, result copyTreeResult) {
+	fmt.Fprintf(b, "\n%s: %s\n", label, result)
+	for _, path := range result.written {
+		fmt.Fprintf(b, "  write  %s\n", filepath.ToSlash(path))
+	}
+	for _, path := range result.removed {
+		fmt.Fprintf(b, "  remove %s\n", filepath.ToSlash(path))
+	}
+}
+
+// fileContentDiffers reports whether dst is missing or differs from src.
+func fileContentDiffers(src, dst string) (bool, error) {
+	want, err := osReadFileFunc(src)
+	if err != nil {
+		return false, err
+	}
+	got, err := osReadFileFunc(dst)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return true, nil
+		}
+		return false, err
+	}
+	return sha256.Sum256(want) != sha256.Sum256(got), nil
+}
+
+// readVersionsJSON returns Repo B's versions.json entries, or nil when the
+// file does not exist yet.
+func readVersionsJSON(repoB string) ([]string, error) {
+	data, err := osReadFileFunc(filepath.Join(repoB, "versions.json"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var versions []string
+	if err := json.Unmarshal(data, &versions); err != nil {
+		return nil, err
+	}
+	return versions, nil
+}
+
 // generateGuidePage combines a public header snippet with extracted canonical
 // guide content and writes a Docusaurus page.
 func generateGuidePage(repoRoot, pagesDir string, spec guidePageSpec) error {
@@ -544,13 +743,36 @@ func slugifyHeading(text string) string {
 	return strings.Trim(builder.String(), "-")
 }
 
-func copyTree(src, dst string) error {
-	// Remove destination if exists.
-	if err := os.RemoveAll(dst); err != nil {
-		return err
-	}
+// copyTreeResult records how copyTree changed, or would change, the
+// destination tree. Paths are relative to the destination.
+type copyTreeResult struct {
+	written   []string
+	removed   []string
+	unchanged int
+}
+
+func (r copyTreeResult) String() string {
+	return fmt.Sprintf("%d written, %d unchanged, %d removed", len(r.written), r.unchanged, len(r.removed))
+}
+
+// copyTree mirrors src into dst incrementally. Files whose content hash and
+// mode already match are left untouched, new and changed files are written,
+// and anything under dst that src no longer has is removed, so Repo B commits
+// only show real changes.
+func copyTree(src, dst string) (copyTreeResult, error) {
+	return mirrorTree(src, dst, true)
+}
 
-	return filepathWalkFunc(src, func(path string, info os.FileInfo, err error) error {
+// planTree reports what copyTree would do without touching dst.
+func planTree(src, dst string) (copyTreeResult, error) {
+	return mirrorTree(src, dst, false)
+}
+
+func mirrorTree(src, dst string, apply bool) (copyTreeResult, error) {
+	var result copyTreeResult
+	// wanted maps each source path to whether it is a directory.
+	wanted := make(map[string]bool)
+	err := filepathWalkFunc(src, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
 			return err
 		}
@@ -559,9 +781,22 @@ func copyTree(src, dst string) error {
 		if err != nil {
 			return err
 		}
+		wanted[relPath] = info.IsDir()
 		dstPath := filepath.Join(dst, relPath)
+		existing, lstatErr := os.Lstat(dstPath)
 
 		if info.IsDir() {
+			if lstatErr == nil && !existing.IsDir() {
+				result.removed = append(result.removed, relPath)
+				if apply {
+					if err := os.Remove(dstPath); err != nil {
+						return err
+					}
+				}
+			}
+			if !apply {
+				return nil
+			}
 			return os.MkdirAll(dstPath, info.Mode())
 		}
 
@@ -569,8 +804,65 @@ func copyTree(src, dst string) error {
 		if err != nil {
 			return err
 		}
-		return osWriteFileFunc(dstPath, data, info.Mode())
+		if lstatErr == nil && existing.Mode().IsRegular() && existing.Mode() == info.Mode() {
+			current, readErr := osReadFileFunc(dstPath)
+			if readErr == nil && sha256.Sum256(current) == sha256.Sum256(data) {
+				result.unchanged++
+				return nil
+			}
+		}
+		result.written = append(result.written, relPath)
+		if lstatErr == nil && existing.IsDir() {
+			result.removed = append(result.removed, relPath)
+			if apply {
+				if err := os.RemoveAll(dstPath); err != nil {
+					return err
+				}
+			}
+		}
+		if !apply {
+			return nil
+		}
+		if err := osWriteFileFunc(dstPath, data, info.Mode()); err != nil {
+			return err
+		}
+		// WriteFile keeps the mode of an existing file; apply the source mode.
+		return os.Chmod(dstPath, info.Mode())
 	})
+	if err != nil {
+		return result, err
+	}
+
+	err = filepathWalkFunc(dst, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			if !apply && path == dst && os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		relPath, err := filepath.Rel(dst, path)
+		if err != nil {
+			return err
+		}
+		if isDir, ok := wanted[relPath]; ok {
+			// A directory replaced by a source file was already counted.
+			if info.IsDir() && !isDir {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		result.removed = append(result.removed, relPath)
+		if apply {
+			if err := os.RemoveAll(path); err != nil {
+				return err
+			}
+		}
+		if info.IsDir() {
+			return filepath.SkipDir
+		}
+		return nil
+	})
+	return result, err
 }
 
 func ensureIdempotentVersion(repoB, docsVersion string) error {
@@ -759,10 +1051,36 @@ func minorKey(v version) string {
 	return fmt.Sprintf("%d.%d", v.major, v.minor)
 }
 
+// readKeepList loads Repo B's versions.keep.json pin list. A missing file
+// pins nothing.
+func readKeepList(repoB string) (map[string]bool, error) {
+	path := filepath.Join(repoB, keepListName)
+	data, err := osReadFileFunc(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("read %s: %w", keepListName, err)
+	}
+	var versions []string
+	if err := json.Unmarshal(data, &versions); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", keepListName, err)
+	}
+	keep := make(map[string]bool, len(versions))
+	for _, v := range versions {
+		if _, err := parseVersion(v); err != nil {
+			return nil, fmt.Errorf("invalid version %q in %s: %w", v, keepListName, err)
+		}
+		keep[v] = true
+	}
+	return keep, nil
+}
+
 // selectRetainedVersions applies the release retention policy to a newest-first
 // sorted version list and returns the retained and dropped versions in
-// newest-first order. Prerelease versions are never retained.
-func selectRetainedVersions(sorted []string) (retained []string, dropped []string, err error) {
+// newest-first order. Prerelease versions are never retained unless pinned in
+// the policy's keep list, which always wins.
+func selectRetainedVersions(sorted []string, policy retentionPolicy) (retained []string, dropped []string, err error) {
 	if len(sorted) == 0 {
 		return nil, nil, nil
 	}
@@ -798,7 +1116,7 @@ func selectRetainedVersions(sorted []string) (retained []string, dropped []strin
 		if minorKey(stableParsed[i]) != newestMinor {
 			continue
 		}
-		if newestMinorCount >= retainNewestMinorPatches {
+		if newestMinorCount >= policy.newestMinorPatches {
 			continue
 		}
 		selected[v] = struct{}{}
@@ -814,13 +1132,17 @@ func selectRetainedVersions(sorted []string) (retained []string, dropped []strin
 			continue
 		}
 		minorLinesSeen[key] = struct{}{}
-		if minorLinesSelected >= retainRecentMinorLines {
+		if minorLinesSelected >= policy.recentMinorLines {
 			continue
 		}
 		selected[v] = struct{}{}
 		minorLinesSelected++
 	}
 
+	for v := range policy.keep {
+		selected[v] = struct{}{}
+	}
+
 	for _, v := range sorted {
 		if _, keep := selected[v]; keep {
 			retained = append(retained, v)
@@ -1041,7 +1363,7 @@ func docRoute(version, slug string) string {
 	return route
 }
 
-func normalizeVersionsJSON(repoB string) error {
+func normalizeVersionsJSON(repoB string, policy retentionPolicy) error {
 	versionsPath := filepath.Join(repoB, "versions.json")
 	if _, err := osStatFunc(versionsPath); os.IsNotExist(err) {
 		return fmt.Errorf("versions.json not found after docs:version")
@@ -1057,6 +1379,30 @@ func normalizeVersionsJSON(repoB string) error {
 		return err
 	}
 
+	retained, dropped, err := selectRetainedVersions(sortVersionsNewestFirst(versions), policy)
+	if err != nil {
+		return err
+	}
+
+	newData, err := json.MarshalIndent(retained, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := osWriteFileFunc(versionsPath, append(newData, '\n'), 0644); err != nil {
+		return err
+	}
+
+	if err := pruneDroppedVersionArtifacts(repoB, dropped); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// sortVersionsNewestFirst deduplicates versions and sorts them newest-first,
+// placing stable releases before prereleases of the same core version.
+func sortVersionsNewestFirst(versions []string) []string {
 	// Deduplicate.
 	seen := make(map[string]bool)
 	var unique []string
@@ -1099,24 +1445,5 @@ func normalizeVersionsJSON(repoB string) error {
 		// Both have prereleases, compare by SemVer precedence.
 		return comparePrerelease(vi.prerelease, vj.prerelease) > 0
 	})
-
-	retained, dropped, err := selectRetainedVersions(unique)
-	if err != nil {
-		return err
-	}
-
-	newData, err := json.MarshalIndent(retained, "", "  ")
-	if err != nil {
-		return err
-	}
-
-	if err := osWriteFileFunc(versionsPath, append(newData, '\n'), 0644); err != nil {
-		return err
-	}
-
-	if err := pruneDroppedVersionArtifacts(repoB, dropped); err != nil {
-		return err
-	}
-
-	return nil
+	return unique
 }
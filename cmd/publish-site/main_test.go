@@ -170,7 +170,7 @@ func TestNormalizeVersionsJSON(t *testing.T) {
 		t.Fatalf("write latest docs file: %v", err)
 	}
 
-	if err := normalizeVersionsJSON(repo); err != nil {
+	if err := normalizeVersionsJSON(repo, defaultRetentionPolicy()); err != nil {
 		t.Fatalf("normalize: %v", err)
 	}
 
@@ -238,7 +238,7 @@ func TestNormalizeVersionsJSON(t *testing.T) {
 func TestSelectRetainedVersions_SparseHistory(t *testing.T) {
 	sorted := []string{"1.3.2", "1.3.1", "1.2.0", "1.1.5"}
 
-	retained, dropped, err := selectRetainedVersions(sorted)
+	retained, dropped, err := selectRetainedVersions(sorted, defaultRetentionPolicy())
 	if err != nil {
 		t.Fatalf("selectRetainedVersions: %v", err)
 	}
@@ -263,7 +263,7 @@ func TestSelectRetainedVersions_DropsPrereleases(t *testing.T) {
 		"1.2.7",
 	}
 
-	retained, dropped, err := selectRetainedVersions(sorted)
+	retained, dropped, err := selectRetainedVersions(sorted, defaultRetentionPolicy())
 	if err != nil {
 		t.Fatalf("selectRetainedVersions: %v", err)
 	}
@@ -280,11 +280,81 @@ func TestSelectRetainedVersions_DropsPrereleases(t *testing.T) {
 }
 
 func TestSelectRetainedVersions_PrereleaseOnly(t *testing.T) {
-	if _, _, err := selectRetainedVersions([]string{"1.2.3-rc.1"}); err == nil || !strings.Contains(err.Error(), "no stable releases") {
+	if _, _, err := selectRetainedVersions([]string{"1.2.3-rc.1"}, defaultRetentionPolicy()); err == nil || !strings.Contains(err.Error(), "no stable releases") {
 		t.Fatalf("expected no stable releases error, got %v", err)
 	}
 }
 
+func TestSelectRetainedVersions_CustomPolicyAndKeepList(t *testing.T) {
+	sorted := []string{"2.1.3", "2.1.2", "2.1.1", "2.0.4", "1.9.0", "1.8.2", "1.7.0"}
+	policy := retentionPolicy{newestMinorPatches: 2, recentMinorLines: 2, keep: map[string]bool{"1.8.2": true, "0.1.0": true}}
+
+	retained, dropped, err := selectRetainedVersions(sorted, policy)
+	if err != nil {
+		t.Fatalf("selectRetainedVersions: %v", err)
+	}
+	if got, want := strings.Join(retained, ","), "2.1.3,2.1.2,2.0.4,1.8.2"; got != want {
+		t.Fatalf("retained = %s, want %s", got, want)
+	}
+	if got, want := strings.Join(dropped, ","), "2.1.1,1.9.0,1.7.0"; got != want {
+		t.Fatalf("dropped = %s, want %s", got, want)
+	}
+}
+
+func TestReadKeepList(t *testing.T) {
+	repo := t.TempDir()
+	keep, err := readKeepList(repo)
+	if err != nil || keep != nil {
+		t.Fatalf("missing keep list: got %v, %v", keep, err)
+	}
+
+	writeFile(t, filepath.Join(repo, keepListName), `["1.2.3", "1.0.0"]`)
+	keep, err = readKeepList(repo)
+	if err != nil {
+		t.Fatalf("readKeepList: %v", err)
+	}
+	if !keep["1.2.3"] || !keep["1.0.0"] || len(keep) != 2 {
+		t.Fatalf("unexpected keep list: %v", keep)
+	}
+
+	writeFile(t, filepath.Join(repo, keepListName), `["v1.2"]`)
+	if _, err := readKeepList(repo); err == nil || !strings.Contains(err.Error(), "invalid version") {
+		t.Fatalf("expected invalid version error, got %v", err)
+	}
+
+	writeFile(t, filepath.Join(repo, keepListName), `{}`)
+	if _, err := readKeepList(repo); err == nil || !strings.Contains(err.Error(), "parse versions.keep.json") {
+		t.Fatalf("expected parse error, got %v", err)
+	}
+
+	withReadFileError(t, filepath.Join(repo, keepListName), os.ErrPermission)
+	if _, err := readKeepList(repo); err == nil || !errors.Is(err, os.ErrPermission) {
+		t.Fatalf("expected read error, got %v", err)
+	}
+}
+
+func TestRun_InvalidRetentionFlags(t *testing.T) {
+	for _, flagName := range []string{"--retain-newest-minor-patches", "--retain-recent-minor-lines"} {
+		setArgs(t, "--tag", "v0.1.0", "--repo-b-dir", t.TempDir(), flagName, "0")
+		if err := run(); err == nil || !strings.Contains(err.Error(), flagName) {
+			t.Fatalf("expected %s error, got %v", flagName, err)
+		}
+	}
+}
+
+func TestRun_InvalidKeepList(t *testing.T) {
+	repoA := setupRepoA(t, repoAOptions{withPages: true, withDocs: true, withChangelog: true})
+	repoB := setupRepoB(t)
+	writeFile(t, filepath.Join(repoB, keepListName), "not json")
+
+	testutil.WithWorkingDir(t, repoA, func() {
+		setArgs(t, "--tag", "v0.1.0", "--repo-b-dir", repoB, "--dry-run")
+		if err := run(); err == nil || !strings.Contains(err.Error(), keepListName) {
+			t.Fatalf("expected keep list error, got %v", err)
+		}
+	})
+}
+
 func TestNormalizeVersionsJSON_Idempotent(t *testing.T) {
 	repo := t.TempDir()
 	versions := []string{
@@ -323,7 +393,7 @@ func TestNormalizeVersionsJSON_Idempotent(t *testing.T) {
 		}
 	}
 
-	if err := normalizeVersionsJSON(repo); err != nil {
+	if err := normalizeVersionsJSON(repo, defaultRetentionPolicy()); err != nil {
 		t.Fatalf("first normalize: %v", err)
 	}
 	firstOutput, err := os.ReadFile(versionsPath) // #nosec G304 -- path is constructed from test-controlled inputs.
@@ -331,7 +401,7 @@ func TestNormalizeVersionsJSON_Idempotent(t *testing.T) {
 		t.Fatalf("read first versions.json: %v", err)
 	}
 
-	if err := normalizeVersionsJSON(repo); err != nil {
+	if err := normalizeVersionsJSON(repo, defaultRetentionPolicy()); err != nil {
 		t.Fatalf("second normalize: %v", err)
 	}
 	secondOutput, err := os.ReadFile(versionsPath) // #nosec G304 -- path is constructed from test-controlled inputs.
@@ -494,7 +564,7 @@ func TestNormalizeVersionsJSON_WritesBeforePrune(t *testing.T) {
 		t.Fatalf("write nested sidebar file: %v", err)
 	}
 
-	if err := normalizeVersionsJSON(repo); err == nil {
+	if err := normalizeVersionsJSON(repo, defaultRetentionPolicy()); err == nil {
 		t.Fatal("expected normalize error when prune fails")
 	}
 
@@ -533,7 +603,7 @@ func TestNormalizeVersionsJSON_WriteErrorSkipsPrune(t *testing.T) {
 	}
 
 	withWriteFileError(t, versionsPath, os.ErrPermission)
-	if err := normalizeVersionsJSON(repo); err == nil || !errors.Is(err, os.ErrPermission) {
+	if err := normalizeVersionsJSON(repo, defaultRetentionPolicy()); err == nil || !errors.Is(err, os.ErrPermission) {
 		t.Fatalf("expected write error, got %v", err)
 	}
 
@@ -634,7 +704,7 @@ func TestCopyTree(t *testing.T) {
 		t.Fatalf("write file: %v", err)
 	}
 
-	if err := copyTree(src, dst); err != nil {
+	if _, err := copyTree(src, dst); err != nil {
 		t.Fatalf("copyTree: %v", err)
 	}
 
@@ -650,6 +720,92 @@ func TestCopyTree(t *testing.T) {
 	}
 }
 
+func TestCopyTree_Incremental(t *testing.T) {
+	src := t.TempDir()
+	dst := t.TempDir()
+	writeFile := func(path, content string) {
+		t.Helper()
+		if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+			t.Fatalf("mkdir %s: %v", path, err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatalf("write %s: %v", path, err)
+		}
+	}
+	writeFile(filepath.Join(src, "same.md"), "same")
+	writeFile(filepath.Join(src, "changed.md"), "new")
+	writeFile(filepath.Join(src, "added", "file.md"), "added")
+	writeFile(filepath.Join(src, "was-dir"), "now a file")
+	writeFile(filepath.Join(dst, "same.md"), "same")
+	writeFile(filepath.Join(dst, "changed.md"), "old")
+	writeFile(filepath.Join(dst, "gone", "file.md"), "gone")
+	writeFile(filepath.Join(dst, "was-dir", "file.md"), "dir")
+
+	past := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	samePath := filepath.Join(dst, "same.md")
+	if err := os.Chtimes(samePath, past, past); err != nil {
+		t.Fatalf("chtimes: %v", err)
+	}
+
+	result, err := copyTree(src, dst)
+	if err != nil {
+		t.Fatalf("copyTree: %v", err)
+	}
+	if len(result.written) != 3 || result.unchanged != 1 || len(result.removed) != 2 {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+	info, err := os.Stat(samePath)
+	if err != nil {
+		t.Fatalf("stat same: %v", err)
+	}
+	if !info.ModTime().Equal(past) {
+		t.Fatalf("unchanged file was rewritten: mtime %v", info.ModTime())
+	}
+	for path, want := range map[string]string{
+		"changed.md":    "new",
+		"added/file.md": "added",
+		"was-dir":       "now a file",
+	} {
+		data, err := os.ReadFile(filepath.Join(dst, filepath.FromSlash(path))) // #nosec G304 -- path is constructed from test-controlled inputs.
+		if err != nil || string(data) != want {
+			t.Fatalf("%s = %q (%v), want %q", path, data, err, want)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dst, "gone")); !os.IsNotExist(err) {
+		t.Fatalf("expected removed dir to be pruned, got %v", err)
+	}
+
+	again, err := copyTree(src, dst)
+	if err != nil {
+		t.Fatalf("second copyTree: %v", err)
+	}
+	if len(again.written) != 0 || len(again.removed) != 0 || again.unchanged != 4 {
+		t.Fatalf("expected no-op rerun, got %+v", again)
+	}
+}
+
+func TestCopyTree_ModeChangeRewrites(t *testing.T) {
+	src := t.TempDir()
+	dst := t.TempDir()
+	if err := os.WriteFile(filepath.Join(src, "run.sh"), []byte("x"), 0o700); err != nil { // #nosec G306 -- test fixture needs the exec bit.
+		t.Fatalf("write src: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dst, "run.sh"), []byte("x"), 0o600); err != nil {
+		t.Fatalf("write dst: %v", err)
+	}
+	result, err := copyTree(src, dst)
+	if err != nil {
+		t.Fatalf("copyTree: %v", err)
+	}
+	info, err := os.Stat(filepath.Join(dst, "run.sh"))
+	if err != nil {
+		t.Fatalf("stat: %v", err)
+	}
+	if len(result.written) != 1 || info.Mode().Perm() != 0o700 {
+		t.Fatalf("expected mode rewrite, got %+v mode %v", result, info.Mode())
+	}
+}
+
 func TestGenerateGuidePage_UsesFullCanonicalBodyAndHeader(t *testing.T) {
 	repo := t.TempDir()
 	sourceRel := filepath.Join("docs", "GUIDE.md")
@@ -989,7 +1145,7 @@ func TestRepoRoot_GoModStatError(t *testing.T) {
 
 func TestNormalizeVersionsJSON_Missing(t *testing.T) {
 	repo := t.TempDir()
-	if err := normalizeVersionsJSON(repo); err == nil {
+	if err := normalizeVersionsJSON(repo, defaultRetentionPolicy()); err == nil {
 		t.Fatal("expected error for missing versions.json")
 	}
 }
@@ -1188,7 +1344,7 @@ func TestCopyTree_RemoveAllError(t *testing.T) {
 	if err := os.WriteFile(filepath.Join(src, "file.txt"), []byte("x"), 0o600); err != nil {
 		t.Fatalf("write file: %v", err)
 	}
-	if err := copyTree(src, "bad\x00"); err == nil {
+	if _, err := copyTree(src, "bad\x00"); err == nil {
 		t.Fatal("expected error for invalid destination path")
 	}
 }
@@ -1206,7 +1362,7 @@ func TestCopyTree_WalkError(t *testing.T) {
 	withWalkError(t, blocked, os.ErrPermission)
 
 	dst := t.TempDir()
-	if err := copyTree(src, filepath.Join(dst, "out")); err == nil {
+	if _, err := copyTree(src, filepath.Join(dst, "out")); err == nil {
 		t.Fatal("expected walk error")
 	}
 }
@@ -1218,7 +1374,7 @@ func TestCopyTree_CallbackErrParameterPropagates(t *testing.T) {
 	}
 	t.Cleanup(func() { filepathWalkFunc = originalWalk })
 
-	if err := copyTree(t.TempDir(), filepath.Join(t.TempDir(), "out")); err == nil || !strings.Contains(err.Error(), "walk callback boom") {
+	if _, err := copyTree(t.TempDir(), filepath.Join(t.TempDir(), "out")); err == nil || !strings.Contains(err.Error(), "walk callback boom") {
 		t.Fatalf("expected callback err propagation, got %v", err)
 	}
 }
@@ -1230,7 +1386,7 @@ func TestCopyTree_RelPathErrorPropagates(t *testing.T) {
 	}
 	t.Cleanup(func() { filepathWalkFunc = originalWalk })
 
-	if err := copyTree(t.TempDir(), filepath.Join(t.TempDir(), "out")); err == nil {
+	if _, err := copyTree(t.TempDir(), filepath.Join(t.TempDir(), "out")); err == nil {
 		t.Fatal("expected filepath.Rel error")
 	}
 }
@@ -1245,7 +1401,7 @@ func TestCopyTree_WriteError(t *testing.T) {
 	target := filepath.Join(dst, "out", "file.txt")
 	withWriteFileError(t, target, os.ErrPermission)
 
-	if err := copyTree(src, filepath.Join(dst, "out")); err == nil || !errors.Is(err, os.ErrPermission) {
+	if _, err := copyTree(src, filepath.Join(dst, "out")); err == nil || !errors.Is(err, os.ErrPermission) {
 		t.Fatalf("expected write error, got %v", err)
 	}
 }
@@ -1340,6 +1496,92 @@ func TestRun_EnsureIdempotentVersionError(t *testing.T) {
 	})
 }
 
+func TestWritePublishPlan(t *testing.T) {
+	repoA := setupRepoA(t, repoAOptions{withPages: true, withDocs: true, withChangelog: true})
+	repoB := setupRepoB(t)
+	writeFile(t, filepath.Join(repoB, "docs", "reference.mdx"), "reference")
+	writeFile(t, filepath.Join(repoB, "docs", "stale.mdx"), "stale")
+	writeFile(t, filepath.Join(repoB, "CHANGELOG.md"), "# Changelog\n")
+	writeFile(t, filepath.Join(repoB, "versions.json"), `["0.1.1","0.1.0","0.1.2","0.1.3","0.1.4"]`)
+
+	var out strings.Builder
+	if err := writePublishPlan(&out, repoA, repoB, "0.1.4", defaultRetentionPolicy()); err != nil {
+		t.Fatalf("writePublishPlan: %v", err)
+	}
+	got := out.String()
+	for _, want := range []string{
+		"Dry run: Repo B was not modified",
+		"src/pages: ",
+		"  write  index.mdx",
+		"docs: 0 written, 1 unchanged, 1 removed",
+		"  remove stale.mdx",
+		"CHANGELOG.md: unchanged",
+		"Version 0.1.4: would recreate the existing snapshot",
+		"versions.json after publish:\n  0.1.4\n  0.1.3\n  0.1.2\n  0.1.1\n",
+		"Versions to prune:\n  0.1.0\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Fatalf("plan missing %q:\n%s", want, got)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(repoB, "docs", "stale.mdx")); err != nil {
+		t.Fatalf("dry run must not remove files: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(repoB, "src", "pages", "index.mdx")); !os.IsNotExist(err) {
+		t.Fatalf("dry run must not write pages, got %v", err)
+	}
+}
+
+func TestWritePublishPlan_NewRepoB(t *testing.T) {
+	repoA := setupRepoA(t, repoAOptions{withPages: true, withDocs: true, withChangelog: true})
+	repoB := setupRepoB(t)
+
+	var out strings.Builder
+	if err := writePublishPlan(&out, repoA, repoB, "0.2.0", defaultRetentionPolicy()); err != nil {
+		t.Fatalf("writePublishPlan: %v", err)
+	}
+	got := out.String()
+	for _, want := range []string{
+		"  write  reference.mdx",
+		"CHANGELOG.md: would update",
+		"Version 0.2.0: would create a new snapshot",
+		"Versions to prune:\n  (none)\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Fatalf("plan missing %q:\n%s", want, got)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(repoB, "docs")); !os.IsNotExist(err) {
+		t.Fatalf("dry run must not create docs dir, got %v", err)
+	}
+}
+
+func TestWritePublishPlan_InvalidVersionsJSON(t *testing.T) {
+	repoA := setupRepoA(t, repoAOptions{withPages: true, withDocs: true, withChangelog: true})
+	repoB := setupRepoB(t)
+	writeFile(t, filepath.Join(repoB, "versions.json"), "not json")
+
+	if err := writePublishPlan(io.Discard, repoA, repoB, "0.2.0", defaultRetentionPolicy()); err == nil || !strings.Contains(err.Error(), "failed to read versions.json") {
+		t.Fatalf("expected versions.json error, got %v", err)
+	}
+}
+
+func TestRun_DryRunSkipsDocusaurus(t *testing.T) {
+	repoA := setupRepoA(t, repoAOptions{withPages: true, withDocs: true, withChangelog: true})
+	repoB := setupRepoB(t)
+	withHelperCommand(t, "HELPER_FAIL=1")
+
+	testutil.WithWorkingDir(t, repoA, func() {
+		setArgs(t, "--tag", "v0.1.0", "--repo-b-dir", repoB, "--dry-run")
+		if err := run(); err != nil {
+			t.Fatalf("dry run: %v", err)
+		}
+	})
+	if _, err := os.Stat(filepath.Join(repoB, "CHANGELOG.md")); !os.IsNotExist(err) {
+		t.Fatalf("dry run must not write the changelog, got %v", err)
+	}
+}
+
 func TestRun_DocusaurusCommandError(t *testing.T) {
 	repoA := setupRepoA(t, repoAOptions{withPages: true, withDocs: true, withChangelog: true})
 	repoB := setupRepoB(t)
@@ -1439,7 +1681,7 @@ func TestNormalizeVersionsJSON_InvalidJSON(t *testing.T) {
 	if err := os.WriteFile(filepath.Join(repo, "versions.json"), []byte("not json"), 0o600); err != nil {
 		t.Fatalf("write versions.json: %v", err)
 	}
-	if err := normalizeVersionsJSON(repo); err == nil {
+	if err := normalizeVersionsJSON(repo, defaultRetentionPolicy()); err == nil {
 		t.Fatal("expected error for invalid json")
 	}
 }
@@ -1449,7 +1691,7 @@ func TestNormalizeVersionsJSON_ReadError(t *testing.T) {
 	if err := os.Mkdir(filepath.Join(repo, "versions.json"), 0o700); err != nil {
 		t.Fatalf("mkdir versions.json: %v", err)
 	}
-	if err := normalizeVersionsJSON(repo); err == nil {
+	if err := normalizeVersionsJSON(repo, defaultRetentionPolicy()); err == nil {
 		t.Fatal("expected read error for versions.json directory")
 	}
 }
@@ -1461,7 +1703,7 @@ func TestNormalizeVersionsJSON_WriteError(t *testing.T) {
 		t.Fatalf("write versions.json: %v", err)
 	}
 	withWriteFileError(t, versionsPath, os.ErrPermission)
-	if err := normalizeVersionsJSON(repo); err == nil || !errors.Is(err, os.ErrPermission) {
+	if err := normalizeVersionsJSON(repo, defaultRetentionPolicy()); err == nil || !errors.Is(err, os.ErrPermission) {
 		t.Fatalf("expected write error, got %v", err)
 	}
 }
@@ -1490,7 +1732,7 @@ func TestNormalizeVersionsJSON_StableVsPrerelease(t *testing.T) {
 		t.Fatalf("write prerelease sidebar: %v", err)
 	}
 
-	if err := normalizeVersionsJSON(repo); err != nil {
+	if err := normalizeVersionsJSON(repo, defaultRetentionPolicy()); err != nil {
 		t.Fatalf("normalize: %v", err)
 	}
 	out, err := os.ReadFile(filepath.Join(repo, "versions.json")) // #nosec G304 -- path is constructed from test-controlled inputs.
@@ -1522,7 +1764,7 @@ func TestNormalizeVersionsJSON_PrereleaseOnly(t *testing.T) {
 	if err := os.WriteFile(filepath.Join(repo, "versions.json"), data, 0o600); err != nil {
 		t.Fatalf("write versions.json: %v", err)
 	}
-	if err := normalizeVersionsJSON(repo); err == nil || !strings.Contains(err.Error(), "no stable releases") {
+	if err := normalizeVersionsJSON(repo, defaultRetentionPolicy()); err == nil || !strings.Contains(err.Error(), "no stable releases") {
 		t.Fatalf("expected no stable releases error, got %v", err)
 	}
 }
@@ -1537,7 +1779,7 @@ func TestNormalizeVersionsJSON_InvalidVersion(t *testing.T) {
 	if err := os.WriteFile(filepath.Join(repo, "versions.json"), data, 0o600); err != nil {
 		t.Fatalf("write versions.json: %v", err)
 	}
-	if err := normalizeVersionsJSON(repo); err == nil || !strings.Contains(err.Error(), "invalid version") {
+	if err := normalizeVersionsJSON(repo, defaultRetentionPolicy()); err == nil || !strings.Contains(err.Error(), "invalid version") {
 		t.Fatalf("expected invalid version error, got %v", err)
 	}
 }
@@ -1554,7 +1796,7 @@ func TestNormalizeVersionsJSON_PrereleasePathTraversalRejected(t *testing.T) {
 		t.Fatalf("write versions.json: %v", err)
 	}
 
-	if err := normalizeVersionsJSON(repo); err == nil || !strings.Contains(err.Error(), "invalid prerelease") {
+	if err := normalizeVersionsJSON(repo, defaultRetentionPolicy()); err == nil || !strings.Contains(err.Error(), "invalid prerelease") {
 		t.Fatalf("expected invalid prerelease error, got %v", err)
 	}
 
@@ -15,6 +15,7 @@ import (
 
 	"github.com/conn-castle/agent-layer/internal/clients"
 	"github.com/conn-castle/agent-layer/internal/config"
+	"github.com/conn-castle/agent-layer/internal/i18n"
 	"github.com/conn-castle/agent-layer/internal/messages"
 )
 
@@ -74,7 +75,7 @@ func Start(opts StartOptions) error {
 	}
 	requested, ok := lookupTarget(opts.Agent)
 	if !ok {
-		return exitError(ExitUsage, fmt.Sprintf(messages.DispatchUnknownTargetFmt, opts.Agent))
+		return exitError(ExitUsage, i18n.Sprintf(messages.DispatchUnknownTargetFmt, opts.Agent))
 	}
 	target, version, prompt, err := prepareFresh(project, requested, runOptions{
 		Root: opts.Root, Model: opts.Model, ReasoningEffort: opts.ReasoningEffort,
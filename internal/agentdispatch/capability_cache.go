@@ -8,6 +8,8 @@ import (
 	"syscall"
 
 	"golang.org/x/sys/unix"
+
+	"github.com/conn-castle/agent-layer/internal/layerdir"
 )
 
 type capabilityCache struct {
@@ -73,11 +75,11 @@ func providerBinaryIdentity(path string, agent string) (string, error) {
 }
 
 func capabilityCachePath(root string) string {
-	return filepath.Join(root, ".agent-layer", "state", "dispatch-capabilities", "cache.json")
+	return filepath.Join(layerdir.Dir(root), "state", "dispatch-capabilities", "cache.json")
 }
 
 func withCapabilityCacheLock(root string, fn func() error) error {
-	path := filepath.Join(root, ".agent-layer", "state", "dispatch-capabilities", "cache.lock")
+	path := filepath.Join(layerdir.Dir(root), "state", "dispatch-capabilities", "cache.lock")
 	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
 		return err
 	}
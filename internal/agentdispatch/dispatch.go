@@ -14,6 +14,7 @@ import (
 	"github.com/conn-castle/agent-layer/internal/agentoptions"
 	"github.com/conn-castle/agent-layer/internal/clients"
 	"github.com/conn-castle/agent-layer/internal/config"
+	"github.com/conn-castle/agent-layer/internal/i18n"
 	"github.com/conn-castle/agent-layer/internal/messages"
 	"github.com/conn-castle/agent-layer/internal/sync"
 )
@@ -385,7 +386,7 @@ func prepareProjection(project *config.ProjectConfig, root string, stderr io.Wri
 		}
 	}
 	if project.Config.Approvals.Mode == config.ApprovalModeYOLO {
-		if _, err := fmt.Fprintln(stderr, messages.WarningsPolicyYOLOAck); err != nil {
+		if _, err := fmt.Fprintln(stderr, i18n.T(messages.WarningsPolicyYOLOAck)); err != nil {
 			return wrapExitError(ExitTargetFailure, "write dispatch approvals acknowledgement", err)
 		}
 	}
@@ -409,7 +410,10 @@ func prepareTargetProjection(project *config.ProjectConfig, root string, working
 	if target.SharedSkillProject {
 		err = sync.WriteAgentSkills(sync.RealSystem{}, projectionRoot, project.Skills)
 	} else {
-		err = sync.WriteClaudeSkills(sync.RealSystem{}, projectionRoot, project.Skills)
+		var claudeProject *config.ProjectConfig
+		if claudeProject, err = project.ForVariantClient(config.VariantClientClaude); err == nil {
+			err = sync.WriteClaudeSkills(sync.RealSystem{}, projectionRoot, claudeProject.Skills)
+		}
 	}
 	if err != nil {
 		return "", syncRunExitError(err)
@@ -419,9 +423,9 @@ func prepareTargetProjection(project *config.ProjectConfig, root string, working
 
 func syncRunExitError(err error) *ExitError {
 	if errors.Is(err, sync.ErrPostWriteLockCleanup) {
-		return wrapExitError(ExitConfig, fmt.Sprintf(messages.DispatchRunSyncCleanupFailedFmt, err), err)
+		return wrapExitError(ExitConfig, i18n.Sprintf(messages.DispatchRunSyncCleanupFailedFmt, err), err)
 	}
-	return wrapExitError(ExitConfig, fmt.Sprintf(messages.DispatchRunSyncFailedFmt, err), err)
+	return wrapExitError(ExitConfig, i18n.Sprintf(messages.DispatchRunSyncFailedFmt, err), err)
 }
 
 func writerOrDiscard(writer io.Writer) io.Writer {
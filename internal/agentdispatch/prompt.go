@@ -1,12 +1,12 @@
 package agentdispatch
 
 import (
-	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
 
 	"github.com/conn-castle/agent-layer/internal/config"
+	"github.com/conn-castle/agent-layer/internal/i18n"
 	"github.com/conn-castle/agent-layer/internal/messages"
 )
 
@@ -28,10 +28,10 @@ func BuildChildPrompt(project *config.ProjectConfig, target string, prompt strin
 	}
 	targetInfo, ok := lookupTarget(target)
 	if !ok {
-		return nil, exitError(ExitUsage, fmt.Sprintf(messages.DispatchUnknownTargetFmt, target))
+		return nil, exitError(ExitUsage, i18n.Sprintf(messages.DispatchUnknownTargetFmt, target))
 	}
 	if !projectHasSkill(project, normalizedSkill) {
-		return nil, exitError(ExitConfig, fmt.Sprintf(messages.DispatchMissingSkillFmt, normalizedSkill))
+		return nil, exitError(ExitConfig, i18n.Sprintf(messages.DispatchMissingSkillFmt, normalizedSkill))
 	}
 	reference := targetInfo.SkillPrefix + normalizedSkill
 	if prompt == "" {
@@ -68,10 +68,10 @@ func validateSkillProjection(root string, target targetMeta, skill string) error
 	// been tampered with or corrupted and dispatch must refuse.
 	info, err := os.Lstat(path)
 	if err != nil {
-		return exitError(ExitConfig, fmt.Sprintf(messages.DispatchMissingSkillProjectionFmt, normalized, target.Name, path))
+		return exitError(ExitConfig, i18n.Sprintf(messages.DispatchMissingSkillProjectionFmt, normalized, target.Name, path))
 	}
 	if !info.Mode().IsRegular() {
-		return exitError(ExitConfig, fmt.Sprintf(messages.DispatchSkillProjectionNotRegularFmt, path, info.Mode().String()))
+		return exitError(ExitConfig, i18n.Sprintf(messages.DispatchSkillProjectionNotRegularFmt, path, info.Mode().String()))
 	}
 	return nil
 }
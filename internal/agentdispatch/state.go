@@ -17,6 +17,9 @@ import (
 	"time"
 
 	"golang.org/x/sys/unix"
+
+	"github.com/conn-castle/agent-layer/internal/config"
+	"github.com/conn-castle/agent-layer/internal/layerdir"
 )
 
 const (
@@ -111,11 +114,11 @@ type dispatchRun struct {
 }
 
 func dispatchStatePath(root string) string {
-	return filepath.Join(root, ".agent-layer", "state", dispatchStateDir)
+	return filepath.Join(config.StateDir(root), dispatchStateDir)
 }
 
 func dispatchRunPath(root string) string {
-	return filepath.Join(root, ".agent-layer", "tmp", "runs")
+	return filepath.Join(layerdir.Dir(root), "tmp", "runs")
 }
 
 func sessionPath(root string, name string) (string, error) {
@@ -507,7 +510,7 @@ func pruneDispatchEvidence(root string, now time.Time) error {
 // version, their synchronous coordinators are gone, and nothing can read or
 // change them again.
 func pruneLegacyFanoutState(root string) error {
-	if err := os.RemoveAll(filepath.Join(root, ".agent-layer", "tmp", "fanouts")); err != nil {
+	if err := os.RemoveAll(filepath.Join(layerdir.Dir(root), "tmp", "fanouts")); err != nil {
 		return wrapExitError(ExitConfig, "remove retired fanout state", err)
 	}
 	return nil
@@ -0,0 +1,147 @@
+// Package audit records command approval decisions made by Agent Layer in
+// .agent-layer/state/audit.jsonl, one JSON object per line.
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/conn-castle/agent-layer/internal/clock"
+	"github.com/conn-castle/agent-layer/internal/i18n"
+	"github.com/conn-castle/agent-layer/internal/layerdir"
+	"github.com/conn-castle/agent-layer/internal/messages"
+)
+
+// FileName is the audit log under .agent-layer/state/.
+const FileName = "audit.jsonl"
+
+// Sources identify the enforcement point that made a decision.
+const (
+	SourceExec    = "exec"
+	SourceGateway = "mcp_gateway"
+)
+
+// Decisions recorded in Entry.Decision.
+const (
+	DecisionAllowed = "allowed"
+	DecisionDenied  = "denied"
+)
+
+// Entry is one recorded decision. Field names are part of the log format.
+type Entry struct {
+	Time     string `json:"time"`
+	Source   string `json:"source"`
+	Client   string `json:"client,omitempty"`
+	Command  string `json:"command"`
+	Decision string `json:"decision"`
+	Rule     string `json:"rule,omitempty"`
+	Reason   string `json:"reason,omitempty"`
+}
+
+// Path returns the audit log path for root.
+func Path(root string) string {
+	return filepath.Join(layerdir.Dir(root), "state", FileName)
+}
+
+// Append stamps entry with now (when it has no time) and appends it to
+// root's audit log, creating the state directory as needed.
+func Append(root string, entry Entry, now time.Time) error {
+	if entry.Time == "" {
+		entry.Time = clock.Format(now)
+	}
+	path := Path(root)
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return i18n.Errorf(messages.AuditWriteFmt, path, err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return i18n.Errorf(messages.AuditWriteFmt, path, err)
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600) // #nosec G304 -- path is the fixed audit log under the repo's .agent-layer/state/.
+	if err != nil {
+		return i18n.Errorf(messages.AuditWriteFmt, path, err)
+	}
+	if _, err := file.Write(append(data, '\n')); err != nil {
+		_ = file.Close()
+		return i18n.Errorf(messages.AuditWriteFmt, path, err)
+	}
+	if err := file.Close(); err != nil {
+		return i18n.Errorf(messages.AuditWriteFmt, path, err)
+	}
+	return nil
+}
+
+// Load reads every entry in root's audit log, oldest first. A missing log
+// yields none.
+func Load(root string) ([]Entry, error) {
+	path := Path(root)
+	file, err := os.Open(path) // #nosec G304 -- path is the fixed audit log under the repo's .agent-layer/state/.
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, i18n.Errorf(messages.AuditReadFmt, path, err)
+	}
+	defer func() { _ = file.Close() }()
+
+	var entries []Entry
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	line := 0
+	for scanner.Scan() {
+		line++
+		if strings.TrimSpace(scanner.Text()) == "" {
+			continue
+		}
+		var entry Entry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return nil, i18n.Errorf(messages.AuditParseFmt, path, line, err)
+		}
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, i18n.Errorf(messages.AuditReadFmt, path, err)
+	}
+	return entries, nil
+}
+
+// Since returns the entries recorded at or after cutoff. Entries with an
+// unreadable time are kept so they are never hidden from review.
+func Since(entries []Entry, cutoff time.Time) []Entry {
+	var kept []Entry
+	for _, entry := range entries {
+		recorded, err := clock.Parse(entry.Time)
+		if err == nil && recorded.Before(cutoff) {
+			continue
+		}
+		kept = append(kept, entry)
+	}
+	return kept
+}
+
+// ParseSince reads a --since value relative to now: a duration such as 90m
+// or 24h, a whole number of days such as 7d, or an absolute timestamp.
+func ParseSince(raw string, now time.Time) (time.Time, error) {
+	value := strings.TrimSpace(raw)
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		if n, err := strconv.Atoi(days); err == nil && n >= 0 {
+			return now.AddDate(0, 0, -n), nil
+		}
+	}
+	if d, err := time.ParseDuration(value); err == nil && d >= 0 {
+		return now.Add(-d), nil
+	}
+	if t, err := clock.Parse(value); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation(time.DateOnly, value, time.Local); err == nil {
+		return t, nil
+	}
+	return time.Time{}, i18n.Errorf(messages.AuditSinceFmt, raw)
+}
@@ -0,0 +1,117 @@
+package audit
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAppend(t *testing.T) {
+	root := t.TempDir()
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	if err := Append(root, Entry{Source: SourceExec, Command: "go test", Decision: "allowed", Rule: "go test"}, now); err != nil {
+		t.Fatalf("append: %v", err)
+	}
+	if err := Append(root, Entry{Source: SourceExec, Client: "codex", Command: "git push", Decision: "denied"}, now.Add(time.Minute)); err != nil {
+		t.Fatalf("append: %v", err)
+	}
+	data, err := os.ReadFile(Path(root))
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected two lines, got %q", data)
+	}
+	var entry Entry
+	if err := json.Unmarshal([]byte(lines[1]), &entry); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if entry.Time != "2026-03-01T12:01:00Z" || entry.Client != "codex" || entry.Decision != "denied" {
+		t.Fatalf("unexpected entry %#v", entry)
+	}
+	if strings.Contains(lines[0], `"client"`) {
+		t.Fatalf("expected empty client omitted: %s", lines[0])
+	}
+	info, err := os.Stat(Path(root))
+	if err != nil || info.Mode().Perm() != 0o600 {
+		t.Fatalf("expected 0600 log, got %v %v", info, err)
+	}
+}
+
+func TestAppend_WriteError(t *testing.T) {
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, ".agent-layer"), []byte("file"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := Append(root, Entry{Command: "ls"}, time.Now()); err == nil || !strings.Contains(err.Error(), "audit log") {
+		t.Fatalf("expected write error, got %v", err)
+	}
+}
+
+func TestLoadAndSince(t *testing.T) {
+	root := t.TempDir()
+	if entries, err := Load(root); err != nil || entries != nil {
+		t.Fatalf("expected no entries without a log, got %v %v", entries, err)
+	}
+	base := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	for i, command := range []string{"old", "recent", "latest"} {
+		if err := Append(root, Entry{Source: SourceExec, Command: command, Decision: DecisionAllowed}, base.Add(time.Duration(i)*time.Hour)); err != nil {
+			t.Fatalf("append: %v", err)
+		}
+	}
+	entries, err := Load(root)
+	if err != nil || len(entries) != 3 {
+		t.Fatalf("expected three entries, got %v %v", entries, err)
+	}
+	entries = append(entries, Entry{Time: "garbled", Command: "unknown time"})
+	kept := Since(entries, base.Add(time.Hour))
+	var commands []string
+	for _, entry := range kept {
+		commands = append(commands, entry.Command)
+	}
+	if strings.Join(commands, ",") != "recent,latest,unknown time" {
+		t.Fatalf("unexpected entries %v", commands)
+	}
+}
+
+func TestLoad_InvalidLine(t *testing.T) {
+	root := t.TempDir()
+	if err := Append(root, Entry{Command: "ls"}, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	file, err := os.OpenFile(Path(root), os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = file.WriteString("\n{broken\n")
+	_ = file.Close()
+	if _, err := Load(root); err == nil || !strings.Contains(err.Error(), "line 3") {
+		t.Fatalf("expected parse error on line 3, got %v", err)
+	}
+}
+
+func TestParseSince(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	tests := map[string]time.Time{
+		"90m":                  now.Add(-90 * time.Minute),
+		"24h":                  now.Add(-24 * time.Hour),
+		"7d":                   now.AddDate(0, 0, -7),
+		"2026-03-01T08:00:00Z": time.Date(2026, 3, 1, 8, 0, 0, 0, time.UTC),
+		"2026-03-01":           time.Date(2026, 3, 1, 0, 0, 0, 0, time.Local),
+	}
+	for raw, want := range tests {
+		got, err := ParseSince(raw, now)
+		if err != nil || !got.Equal(want) {
+			t.Fatalf("ParseSince(%q) = %v, %v; want %v", raw, got, err, want)
+		}
+	}
+	for _, raw := range []string{"yesterday", "-1h", "xd"} {
+		if _, err := ParseSince(raw, now); err == nil {
+			t.Fatalf("expected error for %q", raw)
+		}
+	}
+}
@@ -0,0 +1,39 @@
+// Package ci implements CI mode. When AL_CI is set (the root --ci flag sets
+// it), Agent Layer never prompts: a command that would need an answer fails
+// with errcode.InputRequired instead. Output carries no colors or progress
+// redraws, and failures exit with the code errcode.ExitCode documents for
+// their class.
+package ci
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/conn-castle/agent-layer/internal/errcode"
+	"github.com/conn-castle/agent-layer/internal/i18n"
+	"github.com/conn-castle/agent-layer/internal/messages"
+)
+
+// EnvVar turns CI mode on when set to a value other than a false boolean.
+const EnvVar = "AL_CI"
+
+// Enabled reports whether CI mode is on in the environment read by getenv.
+func Enabled(getenv func(string) string) bool {
+	value := strings.TrimSpace(getenv(EnvVar))
+	if value == "" {
+		return false
+	}
+	if on, err := strconv.ParseBool(value); err == nil {
+		return on
+	}
+	return true
+}
+
+// CheckPrompt returns an errcode.InputRequired error quoting prompt when CI
+// mode is on, and nil otherwise. Call it before reading an answer.
+func CheckPrompt(getenv func(string) string, prompt string) error {
+	if !Enabled(getenv) {
+		return nil
+	}
+	return errcode.Wrap(errcode.InputRequired, i18n.Errorf(messages.CIPromptRefusedFmt, strings.TrimSpace(prompt)))
+}
@@ -0,0 +1,53 @@
+package ci
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/conn-castle/agent-layer/internal/errcode"
+)
+
+func envWith(value string) func(string) string {
+	return func(key string) string {
+		if key == EnvVar {
+			return value
+		}
+		return ""
+	}
+}
+
+func TestEnabled(t *testing.T) {
+	tests := []struct {
+		value string
+		want  bool
+	}{
+		{"", false},
+		{"  ", false},
+		{"0", false},
+		{"false", false},
+		{"1", true},
+		{"true", true},
+		{"yes", true},
+	}
+	for _, tt := range tests {
+		if got := Enabled(envWith(tt.value)); got != tt.want {
+			t.Errorf("Enabled(%q) = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestCheckPrompt(t *testing.T) {
+	if err := CheckPrompt(envWith(""), "Continue?"); err != nil {
+		t.Fatalf("expected nil outside CI mode, got %v", err)
+	}
+	err := CheckPrompt(envWith("1"), "Continue?")
+	if err == nil {
+		t.Fatal("expected error in CI mode")
+	}
+	if errcode.Of(err) != errcode.InputRequired {
+		t.Fatalf("code = %q, want %q", errcode.Of(err), errcode.InputRequired)
+	}
+	if !strings.Contains(err.Error(), "Continue?") {
+		t.Fatalf("expected error to quote the prompt, got %q", err.Error())
+	}
+}
@@ -0,0 +1,273 @@
+// Package clean plans and applies `al clean`: removing generated client
+// outputs and disposable runtime state without touching files the user owns.
+// The set of generated outputs comes from rendering the current sources in a
+// scratch copy, so only paths sync would write are ever candidates.
+package clean
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/conn-castle/agent-layer/internal/config"
+	"github.com/conn-castle/agent-layer/internal/i18n"
+	"github.com/conn-castle/agent-layer/internal/layerdir"
+	"github.com/conn-castle/agent-layer/internal/messages"
+	"github.com/conn-castle/agent-layer/internal/outputdiff"
+	"github.com/conn-castle/agent-layer/internal/sync"
+)
+
+// Categories of cleanable paths.
+const (
+	// CategoryGenerated covers client outputs written by `al sync`.
+	CategoryGenerated = "generated"
+	// CategoryState covers runtime state under .agent-layer/.
+	CategoryState = "state"
+)
+
+// Options selects what Plan considers.
+type Options struct {
+	// Generated includes generated client outputs.
+	Generated bool
+	// State includes snapshots, caches, and scratch directories.
+	State bool
+	// Force also removes generated files that were edited by hand or differ
+	// from what sync would write now.
+	Force bool
+}
+
+// Entry is one path Plan classified.
+type Entry struct {
+	// Path is slash-separated and relative to the repo root. Directories end
+	// with "/".
+	Path     string
+	Category string
+	// Remove is true when Apply deletes the path.
+	Remove bool
+	// Reason explains the classification.
+	Reason string
+}
+
+// renderOutputs is replaced in tests so plans do not run a full sync.
+var renderOutputs = outputdiff.Render
+
+// sharedStateOutputs are generated files sync patches in place; they hold
+// the user's own settings next to managed keys, so they are never removed.
+var sharedStateOutputs = map[string]bool{
+	".gitignore":                         true,
+	".agy/antigravity-cli/settings.json": true,
+	".claude/settings.json":              true,
+	".codex/config.toml":                 true,
+	".vscode/settings.json":              true,
+}
+
+// SharedStateOutput reports whether rel (slash-separated, relative to the
+// repo root) is a generated file sync patches in place rather than owns.
+func SharedStateOutput(rel string) bool {
+	return sharedStateOutputs[rel]
+}
+
+// stateEntries classifies the known entries of .agent-layer/state/ and
+// .agent-layer/tmp/. Entries not listed are kept.
+var stateEntries = map[string]struct {
+	remove bool
+	reason string
+}{
+	".agent-layer/state/upgrade-snapshots/":           {true, messages.CleanReasonSnapshots},
+	".agent-layer/state/dispatch-capabilities/":       {true, messages.CleanReasonDispatchCache},
+	".agent-layer/tmp/":                               {true, messages.CleanReasonScratch},
+	".agent-layer/state/managed-baseline.json":        {false, messages.CleanReasonBaseline},
+	".agent-layer/state/claude-settings-managed.json": {false, messages.CleanReasonClaudeKeys},
+	".agent-layer/state/dispatch/":                    {false, messages.CleanReasonDispatchRuns},
+	".agent-layer/state/audit.jsonl":                  {false, messages.CleanReasonAudit},
+	".agent-layer/state/upgrade-history/":             {false, messages.CleanReasonUpgradeHistory},
+	".agent-layer/state/renderer-outputs.json":        {false, messages.CleanReasonRendererOutputs},
+	".agent-layer/state/process.lock":                 {false, messages.CleanReasonStateLock},
+	".agent-layer/state/serve-token":                  {false, messages.CleanReasonServeToken},
+}
+
+// Plan classifies every candidate path under root for the selected
+// categories, in path order. It writes nothing.
+func Plan(root string, opts Options) ([]Entry, error) {
+	var entries []Entry
+	if opts.Generated {
+		generated, err := planGenerated(root, opts.Force, false)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, generated...)
+	}
+	if opts.State {
+		state, err := planState(root)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, state...)
+	}
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].Path < entries[j].Path })
+	return entries, nil
+}
+
+// planGenerated classifies rendered sync outputs present on disk, plus the
+// nested scoped instruction files. Outputs under .agent-layer/ are skipped
+// unless includeLayer is set.
+func planGenerated(root string, force bool, includeLayer bool) ([]Entry, error) {
+	outputs, err := renderOutputs(root, false)
+	if err != nil {
+		return nil, i18n.Errorf(messages.CleanRenderFmt, err)
+	}
+	var entries []Entry
+	for rel, rendered := range outputs {
+		if !includeLayer && (rel == ".agent-layer" || strings.HasPrefix(rel, ".agent-layer/")) {
+			continue
+		}
+		data, err := os.ReadFile(layerdir.Path(root, rel)) // #nosec G304 -- rel is a sync output path under the repo root or .agent-layer/.
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, i18n.Errorf(messages.CleanReadFmt, rel, err)
+		}
+		entry := Entry{Path: rel, Category: CategoryGenerated}
+		current := string(data)
+		switch {
+		case SharedStateOutput(rel):
+			entry.Reason = messages.CleanReasonSharedState
+		case sync.GeneratedContentUnchanged(current) || current == rendered:
+			entry.Remove, entry.Reason = true, messages.CleanReasonGenerated
+		case force:
+			entry.Remove, entry.Reason = true, messages.CleanReasonForced
+		case sync.HasContentHash(current):
+			entry.Reason = messages.CleanReasonEdited
+		default:
+			entry.Reason = messages.CleanReasonDiffers
+		}
+		entries = append(entries, entry)
+	}
+	scoped, err := planScopedOutputs(root, force, outputs)
+	if err != nil {
+		return nil, err
+	}
+	return append(entries, scoped...), nil
+}
+
+// planScopedOutputs classifies the nested AGENTS.md and CLAUDE.md files sync
+// writes for .agent-layer/scoped/<dir>/. The scratch render has no repo
+// subdirectories, so it never produces them. Only files carrying sync's
+// content hash are candidates; a user's own nested AGENTS.md is not listed.
+func planScopedOutputs(root string, force bool, rendered map[string]string) ([]Entry, error) {
+	scoped, err := config.LoadScopedInstructionsFS(layerdir.FS(root), root, config.DefaultPaths(root).ScopedDir)
+	if err != nil {
+		return nil, err
+	}
+	var entries []Entry
+	for _, instructions := range scoped {
+		if instructions.Dir == "" {
+			continue
+		}
+		for _, name := range sync.ScopedInstructionFiles() {
+			rel := path.Join(instructions.Dir, name)
+			if _, ok := rendered[rel]; ok {
+				continue
+			}
+			data, err := os.ReadFile(filepath.Join(root, filepath.FromSlash(rel))) // #nosec G304 -- rel is a scoped instruction output under the repo root.
+			if errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+			if err != nil {
+				return nil, i18n.Errorf(messages.CleanReadFmt, rel, err)
+			}
+			current := string(data)
+			entry := Entry{Path: rel, Category: CategoryGenerated}
+			switch {
+			case !sync.HasContentHash(current):
+				continue
+			case sync.GeneratedContentUnchanged(current):
+				entry.Remove, entry.Reason = true, messages.CleanReasonGenerated
+			case force:
+				entry.Remove, entry.Reason = true, messages.CleanReasonForced
+			default:
+				entry.Reason = messages.CleanReasonEdited
+			}
+			entries = append(entries, entry)
+		}
+	}
+	return entries, nil
+}
+
+func planState(root string) ([]Entry, error) {
+	var entries []Entry
+	for _, dir := range []string{".agent-layer/state", ".agent-layer/tmp"} {
+		infos, err := os.ReadDir(layerdir.Path(root, dir))
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, i18n.Errorf(messages.CleanReadFmt, dir, err)
+		}
+		if dir == ".agent-layer/tmp" {
+			if len(infos) > 0 {
+				entries = append(entries, stateEntry(dir+"/"))
+			}
+			continue
+		}
+		for _, info := range infos {
+			rel := path.Join(dir, info.Name())
+			if info.IsDir() {
+				rel += "/"
+			}
+			entries = append(entries, stateEntry(rel))
+		}
+	}
+	return entries, nil
+}
+
+func stateEntry(rel string) Entry {
+	known, ok := stateEntries[rel]
+	if !ok {
+		return Entry{Path: rel, Category: CategoryState, Reason: messages.CleanReasonUnknownState}
+	}
+	return Entry{Path: rel, Category: CategoryState, Remove: known.remove, Reason: known.reason}
+}
+
+// Apply removes every entry marked Remove, then prunes directories the
+// removals left empty, stopping at root and .agent-layer/. It returns the
+// number of entries removed.
+func Apply(root string, entries []Entry) (int, error) {
+	removed := 0
+	parents := make(map[string]bool)
+	for _, entry := range entries {
+		if !entry.Remove {
+			continue
+		}
+		full := layerdir.Path(root, strings.TrimSuffix(entry.Path, "/"))
+		if err := os.RemoveAll(full); err != nil {
+			return removed, i18n.Errorf(messages.CleanRemoveFmt, entry.Path, err)
+		}
+		removed++
+		for dir := path.Dir(strings.TrimSuffix(entry.Path, "/")); dir != "." && dir != ".agent-layer"; dir = path.Dir(dir) {
+			parents[dir] = true
+		}
+	}
+	dirs := make([]string, 0, len(parents))
+	for dir := range parents {
+		dirs = append(dirs, dir)
+	}
+	// Deepest first, so a parent is only checked after its children.
+	sort.Slice(dirs, func(i, j int) bool { return strings.Count(dirs[i], "/") > strings.Count(dirs[j], "/") })
+	for _, dir := range dirs {
+		full := layerdir.Path(root, dir)
+		infos, err := os.ReadDir(full)
+		if err != nil || len(infos) > 0 {
+			continue
+		}
+		if err := os.Remove(full); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return removed, i18n.Errorf(messages.CleanRemoveFmt, dir, err)
+		}
+	}
+	return removed, nil
+}
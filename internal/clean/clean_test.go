@@ -0,0 +1,158 @@
+package clean
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func writeFile(t *testing.T, root string, rel string, content string) {
+	t.Helper()
+	full := filepath.Join(root, filepath.FromSlash(rel))
+	if err := os.MkdirAll(filepath.Dir(full), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(full, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func stubRender(t *testing.T, outputs map[string]string) {
+	t.Helper()
+	original := renderOutputs
+	renderOutputs = func(string, bool) (map[string]string, error) { return outputs, nil }
+	t.Cleanup(func() { renderOutputs = original })
+}
+
+func TestPlanAndApply(t *testing.T) {
+	root := t.TempDir()
+	stubRender(t, map[string]string{
+		".mcp.json":                        "{}\n",
+		".vscode/mcp.json":                 "{\"servers\":{}}\n",
+		".claude/settings.json":            "{}\n",
+		".claude/skills/review/SKILL.md":   "review",
+		".copilot/mcp-config.json":         "{}\n",
+		"CLAUDE.md":                        "claude",
+		".agent-layer/state/claude-x.json": "{}",
+	})
+	writeFile(t, root, ".mcp.json", "{}\n")
+	writeFile(t, root, ".vscode/mcp.json", "{\"servers\":{\"mine\":{}}}\n")
+	writeFile(t, root, ".vscode/tasks.json", "user file")
+	writeFile(t, root, ".claude/settings.json", "{\"hooks\":{}}\n")
+	writeFile(t, root, ".claude/skills/review/SKILL.md", "review")
+	writeFile(t, root, "CLAUDE.md", "edited\nContent-Hash: sha256:0000\n")
+	writeFile(t, root, "notes.md", "mine")
+	writeFile(t, root, ".agent-layer/state/upgrade-snapshots/1.json", "{}")
+	writeFile(t, root, ".agent-layer/state/managed-baseline.json", "{}")
+	writeFile(t, root, ".agent-layer/state/mystery.txt", "?")
+	writeFile(t, root, ".agent-layer/tmp/runs/x", "x")
+
+	entries, err := Plan(root, Options{Generated: true, State: true})
+	if err != nil {
+		t.Fatalf("Plan: %v", err)
+	}
+	got := make(map[string]bool)
+	for _, entry := range entries {
+		got[entry.Path] = entry.Remove
+	}
+	want := map[string]bool{
+		".mcp.json":                                true,
+		".vscode/mcp.json":                         false,
+		".claude/settings.json":                    false,
+		".claude/skills/review/SKILL.md":           true,
+		"CLAUDE.md":                                false,
+		".agent-layer/state/upgrade-snapshots/":    true,
+		".agent-layer/state/managed-baseline.json": false,
+		".agent-layer/state/mystery.txt":           false,
+		".agent-layer/tmp/":                        true,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("plan = %v\nwant %v", got, want)
+	}
+	for _, entry := range entries {
+		if entry.Path == "CLAUDE.md" && !strings.Contains(entry.Reason, "edited by hand") {
+			t.Fatalf("CLAUDE.md reason = %q", entry.Reason)
+		}
+	}
+
+	removed, err := Apply(root, entries)
+	if err != nil || removed != 4 {
+		t.Fatalf("Apply = %d, %v", removed, err)
+	}
+	for _, gone := range []string{".mcp.json", ".claude/skills", ".agent-layer/tmp", ".agent-layer/state/upgrade-snapshots"} {
+		if _, err := os.Stat(filepath.Join(root, gone)); !os.IsNotExist(err) {
+			t.Fatalf("expected %s removed, got %v", gone, err)
+		}
+	}
+	for _, kept := range []string{".vscode/tasks.json", ".claude/settings.json", "CLAUDE.md", "notes.md", ".agent-layer/state/managed-baseline.json"} {
+		if _, err := os.Stat(filepath.Join(root, kept)); err != nil {
+			t.Fatalf("expected %s kept: %v", kept, err)
+		}
+	}
+}
+
+func TestPlan_Force(t *testing.T) {
+	root := t.TempDir()
+	stubRender(t, map[string]string{"AGENTS.md": "fresh", ".claude/settings.json": "{}"})
+	writeFile(t, root, "AGENTS.md", "stale")
+	writeFile(t, root, ".claude/settings.json", "{}")
+
+	entries, err := Plan(root, Options{Generated: true, Force: true})
+	if err != nil {
+		t.Fatalf("Plan: %v", err)
+	}
+	if len(entries) != 2 || entries[0].Path != ".claude/settings.json" || entries[0].Remove || !entries[1].Remove {
+		t.Fatalf("entries = %+v", entries)
+	}
+}
+
+func TestPlan_StateOnlySkipsRender(t *testing.T) {
+	root := t.TempDir()
+	original := renderOutputs
+	renderOutputs = func(string, bool) (map[string]string, error) {
+		t.Fatal("render must not run for --state")
+		return nil, nil
+	}
+	t.Cleanup(func() { renderOutputs = original })
+
+	entries, err := Plan(root, Options{State: true})
+	if err != nil || len(entries) != 0 {
+		t.Fatalf("Plan = %+v, %v", entries, err)
+	}
+}
+
+func TestPlanAndApply_StateFollowsRedirect(t *testing.T) {
+	root := t.TempDir()
+	layer := filepath.Join(t.TempDir(), "layer")
+	writeFile(t, root, ".agent-layer", layer+"\n")
+	writeFile(t, layer, "state/upgrade-snapshots/1.json", "{}")
+	writeFile(t, layer, "state/audit.jsonl", "")
+	writeFile(t, layer, "tmp/scratch", "x")
+
+	entries, err := Plan(root, Options{State: true})
+	if err != nil {
+		t.Fatalf("Plan: %v", err)
+	}
+	var paths []string
+	for _, entry := range entries {
+		paths = append(paths, entry.Path)
+	}
+	want := []string{".agent-layer/state/audit.jsonl", ".agent-layer/state/upgrade-snapshots/", ".agent-layer/tmp/"}
+	if !reflect.DeepEqual(paths, want) {
+		t.Fatalf("paths = %v, want %v", paths, want)
+	}
+	if _, err := Apply(root, entries); err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(layer, "state", "upgrade-snapshots")); !os.IsNotExist(err) {
+		t.Fatalf("snapshots not removed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(layer, "state", "audit.jsonl")); err != nil {
+		t.Fatalf("audit log removed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(layer, "tmp")); !os.IsNotExist(err) {
+		t.Fatalf("tmp not removed: %v", err)
+	}
+}
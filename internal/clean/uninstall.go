@@ -0,0 +1,243 @@
+package clean
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/conn-castle/agent-layer/internal/config"
+	"github.com/conn-castle/agent-layer/internal/i18n"
+	"github.com/conn-castle/agent-layer/internal/install"
+	"github.com/conn-castle/agent-layer/internal/layerdir"
+	"github.com/conn-castle/agent-layer/internal/messages"
+	"github.com/conn-castle/agent-layer/internal/sync"
+)
+
+// Categories only `al uninstall` plans.
+const (
+	// CategoryGit covers the managed block in .gitignore; removing it edits
+	// the file instead of deleting it.
+	CategoryGit = "git"
+	// CategoryLayer covers the .agent-layer/ directory itself.
+	CategoryLayer = "layer"
+	// CategorySettings covers settings files sync patches in place; removing
+	// one strips the managed entries, and deletes the file only when nothing
+	// else is left.
+	CategorySettings = "settings"
+)
+
+// strippedOutputs are the shared-state outputs whose managed entries
+// sync.StripManagedSettings can identify and remove.
+var strippedOutputs = map[string]bool{
+	".claude/settings.json": true,
+	".codex/config.toml":    true,
+	".vscode/settings.json": true,
+}
+
+// UninstallOptions selects what PlanUninstall considers.
+type UninstallOptions struct {
+	// RemoveLayer also removes .agent-layer/, keeping paths config.toml's
+	// [ownership] table marks as user-owned.
+	RemoveLayer bool
+	// Force also removes generated files that were edited by hand or differ
+	// from what sync would write now.
+	Force bool
+}
+
+// PlanUninstall classifies everything Agent Layer put in root: generated
+// client outputs (including those under .agent-layer/), all runtime state,
+// the managed .gitignore block, and with RemoveLayer the .agent-layer/
+// directory. Memory files under docs/agent-layer/ are always kept. It writes
+// nothing.
+func PlanUninstall(root string, opts UninstallOptions) ([]Entry, error) {
+	entries, err := planGenerated(root, opts.Force, !opts.RemoveLayer)
+	if err != nil {
+		return nil, err
+	}
+	kept := entries[:0]
+	for _, entry := range entries {
+		switch {
+		case strings.HasPrefix(entry.Path, ".agent-layer/state/"), strings.HasPrefix(entry.Path, ".agent-layer/tmp/"):
+			// Covered by the whole-directory state entries below.
+			continue
+		case entry.Path == ".gitignore":
+			entry = Entry{Path: entry.Path, Category: CategoryGit, Remove: true, Reason: messages.UninstallReasonGitignore}
+		case strippedOutputs[entry.Path]:
+			entry = Entry{Path: entry.Path, Category: CategorySettings, Remove: true, Reason: messages.UninstallReasonSettings}
+		}
+		kept = append(kept, entry)
+	}
+	entries = kept
+	if opts.RemoveLayer {
+		layer, err := planLayer(root)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, layer...)
+	} else {
+		for _, dir := range []string{".agent-layer/state", ".agent-layer/tmp"} {
+			if _, err := os.Stat(layerdir.Path(root, dir)); err != nil {
+				if errors.Is(err, fs.ErrNotExist) {
+					continue
+				}
+				return nil, i18n.Errorf(messages.CleanReadFmt, dir, err)
+			}
+			entries = append(entries, Entry{Path: dir + "/", Category: CategoryState, Remove: true, Reason: messages.UninstallReasonState})
+		}
+	}
+	if _, err := os.Stat(filepath.Join(root, "docs", "agent-layer")); err == nil {
+		entries = append(entries, Entry{Path: "docs/agent-layer/", Category: CategoryLayer, Reason: messages.UninstallReasonMemory})
+	}
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].Path < entries[j].Path })
+	return entries, nil
+}
+
+// planLayer plans removing .agent-layer/. Without user-owned paths inside it
+// the directory is one entry; otherwise each top-level child is listed so the
+// children holding user-owned paths can be kept. A redirect only removes the
+// redirect file: the directory it names lives outside the repo and may be
+// shared by other repos, so its path is reported for the user to delete.
+func planLayer(root string) ([]Entry, error) {
+	layerDir, redirected, err := layerdir.Resolve(root)
+	if err != nil {
+		return nil, err
+	}
+	if redirected {
+		return []Entry{{Path: layerdir.Name, Category: CategoryLayer, Remove: true, Reason: i18n.Sprintf(messages.UninstallReasonRedirectFmt, layerDir)}}, nil
+	}
+	if _, err := os.Stat(layerDir); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, i18n.Errorf(messages.CleanReadFmt, layerdir.Name, err)
+	}
+	owned, err := layerUserOwnedPaths(root)
+	if err != nil {
+		return nil, err
+	}
+	if len(owned) == 0 {
+		return []Entry{{Path: layerdir.Name + "/", Category: CategoryLayer, Remove: true, Reason: messages.UninstallReasonLayer}}, nil
+	}
+	infos, err := os.ReadDir(layerDir)
+	if err != nil {
+		return nil, i18n.Errorf(messages.CleanReadFmt, layerdir.Name, err)
+	}
+	entries := make([]Entry, 0, len(infos))
+	for _, info := range infos {
+		rel := path.Join(layerdir.Name, info.Name())
+		entry := Entry{Path: rel, Category: CategoryLayer, Remove: true, Reason: messages.UninstallReasonLayer}
+		if info.IsDir() {
+			entry.Path += "/"
+		}
+		for _, ownedPath := range owned {
+			if ownedPath == rel || strings.HasPrefix(ownedPath, rel+"/") {
+				entry.Remove, entry.Reason = false, messages.UninstallReasonUserOwned
+				break
+			}
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// layerUserOwnedPaths returns the [ownership] user-owned paths under
+// .agent-layer/. A repo whose config cannot be read has none.
+func layerUserOwnedPaths(root string) ([]string, error) {
+	cfg, err := config.LoadConfigLenient(layerdir.Path(root, ".agent-layer/config.toml"))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var owned []string
+	for _, ownedPath := range cfg.UserOwnedPaths() {
+		if strings.HasPrefix(ownedPath, ".agent-layer/") {
+			owned = append(owned, ownedPath)
+		}
+	}
+	return owned, nil
+}
+
+// ApplyUninstall strips the managed .gitignore block for CategoryGit
+// entries and the managed settings for CategorySettings entries, deleting a
+// settings file that held nothing else, then removes every other entry marked
+// Remove like Apply. Settings go first because the Claude record they need is
+// under .agent-layer/state/, and an .agent-layer redirect file goes last,
+// since the other .agent-layer/ paths resolve through it. It returns the
+// number of entries removed or edited.
+func ApplyUninstall(root string, entries []Entry) (int, error) {
+	edited := 0
+	redirect := false
+	rest := make([]Entry, 0, len(entries))
+	for _, entry := range entries {
+		if entry.Path == layerdir.Name && entry.Remove {
+			redirect = true
+			continue
+		}
+		if !entry.Remove || (entry.Category != CategoryGit && entry.Category != CategorySettings) {
+			rest = append(rest, entry)
+			continue
+		}
+		full := filepath.Join(root, filepath.FromSlash(entry.Path))
+		data, err := os.ReadFile(full) // #nosec G304 -- entry.Path is a sync output path under the repo root.
+		if err != nil {
+			return edited, i18n.Errorf(messages.CleanReadFmt, entry.Path, err)
+		}
+		var updated string
+		if entry.Category == CategoryGit {
+			var changed bool
+			updated, changed, err = install.RemoveGitignoreBlock(string(data), entry.Path)
+			if err != nil {
+				return edited, err
+			}
+			if !changed {
+				continue
+			}
+		} else {
+			var empty bool
+			updated, empty, _, err = sync.StripManagedSettings(root, entry.Path)
+			if err != nil {
+				return edited, i18n.Errorf(messages.UninstallStripFmt, entry.Path, err)
+			}
+			if empty {
+				// Apply deletes it and prunes the directory it leaves empty.
+				rest = append(rest, entry)
+				continue
+			}
+			if updated == string(data) {
+				continue
+			}
+		}
+		if updated == "" {
+			err = os.Remove(full)
+		} else {
+			err = os.WriteFile(full, []byte(updated), fileMode(full)) // #nosec G306 -- keeps the file's own mode.
+		}
+		if err != nil {
+			return edited, i18n.Errorf(messages.CleanRemoveFmt, entry.Path, err)
+		}
+		edited++
+	}
+	removed, err := Apply(root, rest)
+	if err != nil || !redirect {
+		return edited + removed, err
+	}
+	if err := os.Remove(filepath.Join(root, layerdir.Name)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return edited + removed, i18n.Errorf(messages.CleanRemoveFmt, layerdir.Name, err)
+	}
+	return edited + removed + 1, nil
+}
+
+// fileMode returns the permission bits of the existing file at path, or 0644.
+func fileMode(path string) os.FileMode {
+	info, err := os.Stat(path)
+	if err != nil {
+		return 0o644
+	}
+	return info.Mode().Perm()
+}
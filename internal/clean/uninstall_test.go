@@ -0,0 +1,215 @@
+package clean
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestPlanAndApplyUninstall(t *testing.T) {
+	root := t.TempDir()
+	stubRender(t, map[string]string{
+		".mcp.json":                        "{}\n",
+		".claude/settings.json":            "{}\n",
+		".gitignore":                       "",
+		".agent-layer/open-vscode.sh":      "launcher",
+		".agent-layer/state/claude-x.json": "{}",
+	})
+	writeFile(t, root, ".mcp.json", "{}\n")
+	writeFile(t, root, ".claude/settings.json", "{\"hooks\":{}}\n")
+	writeFile(t, root, ".gitignore", "node_modules/\n\n# >>> agent-layer\n.mcp.json\n# <<< agent-layer\n\ndist/\n")
+	writeFile(t, root, ".agent-layer/open-vscode.sh", "launcher")
+	writeFile(t, root, ".agent-layer/config.toml", "")
+	writeFile(t, root, ".agent-layer/state/managed-baseline.json", "{}")
+	writeFile(t, root, ".agent-layer/state/claude-x.json", "{}")
+	writeFile(t, root, "docs/agent-layer/ISSUES.md", "# Issues\n")
+
+	entries, err := PlanUninstall(root, UninstallOptions{})
+	if err != nil {
+		t.Fatalf("PlanUninstall: %v", err)
+	}
+	got := make(map[string]bool)
+	for _, entry := range entries {
+		got[entry.Path] = entry.Remove
+	}
+	want := map[string]bool{
+		".mcp.json":                   true,
+		".claude/settings.json":       true,
+		".gitignore":                  true,
+		".agent-layer/open-vscode.sh": true,
+		".agent-layer/state/":         true,
+		"docs/agent-layer/":           false,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("plan = %v\nwant %v", got, want)
+	}
+
+	if _, err := ApplyUninstall(root, entries); err != nil {
+		t.Fatalf("ApplyUninstall: %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(root, ".gitignore"))
+	if err != nil {
+		t.Fatalf("read .gitignore: %v", err)
+	}
+	if string(data) != "node_modules/\n\ndist/\n" {
+		t.Fatalf(".gitignore = %q", data)
+	}
+	for _, rel := range []string{".mcp.json", ".agent-layer/state", ".agent-layer/open-vscode.sh"} {
+		if _, err := os.Stat(filepath.Join(root, rel)); !os.IsNotExist(err) {
+			t.Fatalf("%s should be removed, stat err=%v", rel, err)
+		}
+	}
+	for _, rel := range []string{".agent-layer/config.toml", ".claude/settings.json", "docs/agent-layer/ISSUES.md"} {
+		if _, err := os.Stat(filepath.Join(root, rel)); err != nil {
+			t.Fatalf("%s should be kept: %v", rel, err)
+		}
+	}
+}
+
+func TestPlanUninstallRemoveLayerKeepsUserOwnedPaths(t *testing.T) {
+	root := t.TempDir()
+	stubRender(t, map[string]string{})
+	writeFile(t, root, ".agent-layer/config.toml", "[ownership]\n\".agent-layer/skills/mine\" = \"user\"\n")
+	writeFile(t, root, ".agent-layer/skills/mine/SKILL.md", "mine")
+	writeFile(t, root, ".agent-layer/instructions/00_base.md", "base")
+
+	entries, err := PlanUninstall(root, UninstallOptions{RemoveLayer: true})
+	if err != nil {
+		t.Fatalf("PlanUninstall: %v", err)
+	}
+	got := make(map[string]bool)
+	for _, entry := range entries {
+		got[entry.Path] = entry.Remove
+	}
+	want := map[string]bool{
+		".agent-layer/config.toml":   true,
+		".agent-layer/instructions/": true,
+		".agent-layer/skills/":       false,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("plan = %v\nwant %v", got, want)
+	}
+}
+
+// sealed returns body under a provenance header whose content hash matches,
+// as sync writes generated files.
+func TestApplyUninstallStripsManagedSettings(t *testing.T) {
+	root := t.TempDir()
+	stubRender(t, map[string]string{
+		".claude/settings.json": "{}\n",
+		".codex/config.toml":    "",
+		".vscode/settings.json": "{}\n",
+	})
+	writeFile(t, root, ".agent-layer/config.toml", "")
+	writeFile(t, root, ".agent-layer/state/claude-settings-managed.json", `{"paths": [["permissions", "allow"], ["effortLevel"]]}`)
+	writeFile(t, root, ".claude/settings.json", `{"effortLevel": "high", "env": {"MINE": "1"}, "permissions": {"allow": ["Bash(git status:*)"]}}`)
+	writeFile(t, root, ".codex/config.toml", "# PARTIALLY GENERATED FILE - MAY CONTAIN SECRETS\n"+
+		"# This file is gitignored. Do not commit or share it.\n"+
+		"# Agent Layer refreshes known managed entries from .agent-layer/config.toml.\n"+
+		"# Agent Layer-owned entries (model, approvals, statusline, feature toggles, mcp_servers, and this repo's project trust) are refreshed or replaced from source; unrelated Codex/user runtime entries are preserved.\n"+
+		"# Removed arbitrary passthrough entries outside known Agent Layer-owned paths may need manual cleanup.\n"+
+		"# Regenerate managed entries: al sync\n\n"+
+		"model = \"gpt-5\"\nmine = true\n\n"+
+		"[projects."+strconv.Quote(root)+"]\ntrust_level = \"trusted\"\n\n"+
+		"[mcp_servers.\"docs\"]\ncommand = \"docs-mcp\"\n")
+	writeFile(t, root, ".vscode/settings.json", "{\n  // >>> agent-layer\n  \"chat.tools.terminal.autoApprove\": {}\n  // <<< agent-layer\n}\n")
+
+	entries, err := PlanUninstall(root, UninstallOptions{})
+	if err != nil {
+		t.Fatalf("PlanUninstall: %v", err)
+	}
+	if _, err := ApplyUninstall(root, entries); err != nil {
+		t.Fatalf("ApplyUninstall: %v", err)
+	}
+
+	claude, err := os.ReadFile(filepath.Join(root, ".claude", "settings.json"))
+	if err != nil {
+		t.Fatalf("read .claude/settings.json: %v", err)
+	}
+	if strings.Contains(string(claude), "permissions") || strings.Contains(string(claude), "effortLevel") || !strings.Contains(string(claude), "MINE") {
+		t.Fatalf(".claude/settings.json = %s", claude)
+	}
+	codex, err := os.ReadFile(filepath.Join(root, ".codex", "config.toml"))
+	if err != nil {
+		t.Fatalf("read .codex/config.toml: %v", err)
+	}
+	if string(codex) != "mine = true\n" {
+		t.Fatalf(".codex/config.toml = %q", codex)
+	}
+	if _, err := os.Stat(filepath.Join(root, ".vscode")); !os.IsNotExist(err) {
+		t.Fatalf(".vscode/ held only managed settings and should be removed, stat err=%v", err)
+	}
+}
+
+func sealed(body string) string {
+	content := "<!-- Content-Hash: sha256:<content-hash> -->\n" + body
+	sum := sha256.Sum256([]byte(content))
+	return strings.Replace(content, "<content-hash>", hex.EncodeToString(sum[:]), 1)
+}
+
+func TestPlanUninstallIncludesScopedInstructionFiles(t *testing.T) {
+	root := t.TempDir()
+	stubRender(t, map[string]string{})
+	writeFile(t, root, ".agent-layer/scoped/api/00_api.md", "# API\n")
+	writeFile(t, root, ".agent-layer/scoped/web/00_web.md", "# Web\n")
+	writeFile(t, root, "api/AGENTS.md", sealed("api\n"))
+	writeFile(t, root, "api/CLAUDE.md", strings.Replace(sealed("api\n"), "api", "edited", 1))
+	writeFile(t, root, "web/AGENTS.md", "# Our own notes\n")
+
+	entries, err := PlanUninstall(root, UninstallOptions{})
+	if err != nil {
+		t.Fatalf("PlanUninstall: %v", err)
+	}
+	got := make(map[string]bool)
+	for _, entry := range entries {
+		if !strings.HasPrefix(entry.Path, ".agent-layer/") {
+			got[entry.Path] = entry.Remove
+		}
+	}
+	want := map[string]bool{"api/AGENTS.md": true, "api/CLAUDE.md": false}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("plan = %v\nwant %v", got, want)
+	}
+}
+
+func TestPlanAndApplyUninstallFollowsRedirect(t *testing.T) {
+	root := t.TempDir()
+	target := t.TempDir()
+	stubRender(t, map[string]string{})
+	writeFile(t, root, ".agent-layer", target+"\n")
+	writeFile(t, target, "config.toml", "")
+	writeFile(t, target, "state/audit.jsonl", "{}")
+	writeFile(t, target, ".git/HEAD", "ref: refs/heads/main\n")
+
+	entries, err := PlanUninstall(root, UninstallOptions{})
+	if err != nil {
+		t.Fatalf("PlanUninstall: %v", err)
+	}
+	if len(entries) != 1 || entries[0].Path != ".agent-layer/state/" || !entries[0].Remove {
+		t.Fatalf("plan = %+v, want the redirected state directory", entries)
+	}
+
+	entries, err = PlanUninstall(root, UninstallOptions{RemoveLayer: true})
+	if err != nil {
+		t.Fatalf("PlanUninstall: %v", err)
+	}
+	if len(entries) != 1 || entries[0].Path != ".agent-layer" || !entries[0].Remove || !strings.Contains(entries[0].Reason, target) {
+		t.Fatalf("plan = %+v, want only the redirect file", entries)
+	}
+	if _, err := ApplyUninstall(root, entries); err != nil {
+		t.Fatalf("ApplyUninstall: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(root, ".agent-layer")); !os.IsNotExist(err) {
+		t.Fatalf("redirect file should be removed, stat err=%v", err)
+	}
+	for _, rel := range []string{"config.toml", "state/audit.jsonl", ".git/HEAD"} {
+		if _, err := os.Stat(filepath.Join(target, rel)); err != nil {
+			t.Fatalf("redirected %s should be kept: %v", rel, err)
+		}
+	}
+}
@@ -0,0 +1,37 @@
+package clientimport
+
+import (
+	"encoding/json"
+
+	"github.com/conn-castle/agent-layer/internal/i18n"
+	"github.com/conn-castle/agent-layer/internal/messages"
+)
+
+// loadClaude reads CLAUDE.md, .claude/CLAUDE.md, .mcp.json, and the model
+// from .claude/settings.json.
+func loadClaude(root string) (found, error) {
+	data := found{agent: ClientClaude}
+	if err := addInstructionFile(root, "CLAUDE.md", "claude", &data); err != nil {
+		return found{}, err
+	}
+	if err := addInstructionFile(root, ".claude/CLAUDE.md", "claude_dir", &data); err != nil {
+		return found{}, err
+	}
+	if err := addJSONServers(root, ".mcp.json", false, &data); err != nil {
+		return found{}, err
+	}
+	content, ok, err := readSource(root, ".claude/settings.json", &data)
+	if err != nil {
+		return found{}, err
+	}
+	if ok {
+		var settings struct {
+			Model string `json:"model"`
+		}
+		if err := json.Unmarshal([]byte(content), &settings); err != nil {
+			return found{}, i18n.Errorf(messages.ClientImportParseFileFmt, ".claude/settings.json", err)
+		}
+		data.model = settings.Model
+	}
+	return data, nil
+}
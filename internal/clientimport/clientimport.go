@@ -0,0 +1,530 @@
+// Package clientimport synthesizes .agent-layer configuration from a client's
+// existing repo-local setup, so repos that already configure Claude, Codex,
+// Gemini, or Cursor by hand can adopt Agent Layer without retyping it.
+// Instructions become files under .agent-layer/instructions/, MCP servers
+// become [[mcp.servers]] entries in config.toml with literal secrets moved to
+// .agent-layer/.env, and the client's agent table is enabled. Importing is
+// additive: existing instruction files, servers, and models are never
+// overwritten, so re-running an import is safe.
+package clientimport
+
+import (
+	"encoding/json"
+	"errors"
+	"maps"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/pelletier/go-toml/v2"
+
+	"github.com/conn-castle/agent-layer/internal/envfile"
+	"github.com/conn-castle/agent-layer/internal/fsutil"
+	"github.com/conn-castle/agent-layer/internal/i18n"
+	"github.com/conn-castle/agent-layer/internal/layerdir"
+	"github.com/conn-castle/agent-layer/internal/messages"
+	"github.com/conn-castle/agent-layer/internal/tomlpatch"
+)
+
+// Supported clients.
+const (
+	ClientClaude = "claude"
+	ClientCodex  = "codex"
+	ClientGemini = "gemini"
+	ClientCursor = "cursor"
+)
+
+// Import statuses reported in ItemResult.Status.
+const (
+	StatusAdded  = "added"
+	StatusExists = "exists"
+)
+
+// Clients lists the supported client names in display order.
+var Clients = []string{ClientClaude, ClientCodex, ClientGemini, ClientCursor}
+
+// instructionPrefix sorts imported instructions after the template files.
+const instructionPrefix = "90_imported_"
+
+// agentLayerServerID is the reserved id of Agent Layer's own MCP server, which
+// sync projects into client configs and which must not be imported back.
+const agentLayerServerID = "agent-layer"
+
+// generatedMarker appears in the header of every file sync writes; such files
+// are Agent Layer output, not the client setup being imported.
+const generatedMarker = "GENERATED FILE"
+
+// Options configures Import.
+type Options struct {
+	// Root is the repo root holding .agent-layer/.
+	Root string
+	// Client is one of Clients.
+	Client string
+}
+
+// ItemResult describes one imported instruction file or MCP server.
+type ItemResult struct {
+	// Name is the instruction path relative to the repo root, or the server id.
+	Name string
+	// Source is the client file it came from, relative to the repo root.
+	Source string
+	Status string
+	// Disabled is set for servers imported with enabled = false because an
+	// .env value they reference is not set yet.
+	Disabled bool
+}
+
+// Result is the outcome of Import.
+type Result struct {
+	Client string
+	// Sources lists the client files that were read, relative to the repo root.
+	Sources      []string
+	Instructions []ItemResult
+	Servers      []ItemResult
+	// Secrets lists the .env keys that received literal values moved out of
+	// client configs.
+	Secrets []string
+	// MissingEnv lists referenced .env keys that still have no value.
+	MissingEnv []string
+	// Agent is the [agents.*] table that was enabled, or empty when the client
+	// has no Agent Layer agent.
+	Agent string
+}
+
+// found is what a client loader extracted from the repo.
+type found struct {
+	sources      []string
+	instructions []instruction
+	servers      []server
+	agent        string
+	model        string
+	reasoning    string
+}
+
+type instruction struct {
+	// name is the instruction file suffix, without the .md extension.
+	name    string
+	source  string
+	content string
+}
+
+type server struct {
+	id            string
+	source        string
+	disabled      bool
+	transport     string
+	httpTransport string
+	url           string
+	headers       map[string]string
+	command       string
+	args          []string
+	env           map[string]string
+}
+
+// Sources returns the client files Import would read for client under root,
+// relative to root. It fails when there are none, so callers can check for
+// an importable setup before creating .agent-layer/.
+func Sources(root string, client string) ([]string, error) {
+	data, err := load(root, client)
+	if err != nil {
+		return nil, err
+	}
+	return data.sources, nil
+}
+
+// Import reads the client's repo-local configuration at opts.Root and merges
+// it into the existing .agent-layer/ setup there.
+func Import(opts Options) (*Result, error) {
+	client := strings.ToLower(strings.TrimSpace(opts.Client))
+	data, err := load(opts.Root, client)
+	if err != nil {
+		return nil, err
+	}
+
+	agentDir := layerdir.Dir(opts.Root)
+	configPath := filepath.Join(agentDir, "config.toml")
+	configData, err := os.ReadFile(configPath) // #nosec G304 -- configPath is the repo's .agent-layer/config.toml.
+	if err != nil {
+		return nil, i18n.Errorf(messages.ClientImportReadFmt, configPath, err)
+	}
+	envPath := filepath.Join(agentDir, ".env")
+	envData, err := os.ReadFile(envPath) // #nosec G304 -- envPath is the repo's .agent-layer/.env.
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, i18n.Errorf(messages.ClientImportReadFmt, envPath, err)
+	}
+
+	result := &Result{Client: client, Sources: data.sources, Agent: data.agent}
+	for _, inst := range data.instructions {
+		item, err := writeInstruction(opts.Root, inst)
+		if err != nil {
+			return nil, err
+		}
+		result.Instructions = append(result.Instructions, item)
+	}
+
+	content, secrets, err := mergeServers(string(configData), string(envData), data.servers, result)
+	if err != nil {
+		return nil, i18n.Errorf(messages.ClientImportParseFmt, err)
+	}
+	if len(secrets) > 0 {
+		if err := fsutil.WriteFileAtomic(envPath, []byte(envfile.Patch(string(envData), secrets)), 0o600); err != nil {
+			return nil, i18n.Errorf(messages.ClientImportWriteFmt, envPath, err)
+		}
+	}
+	if data.agent != "" {
+		content = enableAgent(content, data)
+	}
+	if content != string(configData) {
+		if err := fsutil.WriteFileAtomic(configPath, []byte(content), 0o644); err != nil {
+			return nil, i18n.Errorf(messages.ClientImportWriteFmt, configPath, err)
+		}
+	}
+	return result, nil
+}
+
+func load(root string, client string) (found, error) {
+	var data found
+	var err error
+	switch strings.ToLower(strings.TrimSpace(client)) {
+	case ClientClaude:
+		data, err = loadClaude(root)
+	case ClientCodex:
+		data, err = loadCodex(root)
+	case ClientGemini:
+		data, err = loadGemini(root)
+	case ClientCursor:
+		data, err = loadCursor(root)
+	default:
+		return found{}, i18n.Errorf(messages.ClientImportUnknownClientFmt, client, strings.Join(Clients, ", "))
+	}
+	if err != nil {
+		return found{}, err
+	}
+	if len(data.sources) == 0 {
+		return found{}, i18n.Errorf(messages.ClientImportNothingFoundFmt, client, root)
+	}
+	return data, nil
+}
+
+// writeInstruction writes inst to .agent-layer/instructions/ unless a file of
+// that name already exists.
+func writeInstruction(root string, inst instruction) (ItemResult, error) {
+	rel := filepath.Join(".agent-layer", "instructions", instructionPrefix+inst.name+".md")
+	item := ItemResult{Name: filepath.ToSlash(rel), Source: inst.source, Status: StatusAdded}
+	path := layerdir.Path(root, rel)
+	if _, err := os.Stat(path); err == nil {
+		item.Status = StatusExists
+		return item, nil
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return ItemResult{}, i18n.Errorf(messages.ClientImportReadFmt, path, err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return ItemResult{}, i18n.Errorf(messages.ClientImportWriteFmt, path, err)
+	}
+	content := strings.TrimSpace(inst.content) + "\n"
+	if err := fsutil.WriteFileAtomic(path, []byte(content), 0o644); err != nil {
+		return ItemResult{}, i18n.Errorf(messages.ClientImportWriteFmt, path, err)
+	}
+	return item, nil
+}
+
+// mergeServers appends the servers config.toml does not already declare and
+// collects their literal secrets. It returns the new config content and the
+// .env values to add.
+func mergeServers(content string, envContent string, servers []server, result *Result) (string, map[string]string, error) {
+	var existing struct {
+		MCP struct {
+			Servers []struct {
+				ID string `toml:"id"`
+			} `toml:"servers"`
+		} `toml:"mcp"`
+	}
+	if err := toml.Unmarshal([]byte(content), &existing); err != nil {
+		return "", nil, err
+	}
+	declared := make(map[string]bool)
+	for _, s := range existing.MCP.Servers {
+		declared[s.ID] = true
+	}
+	env, err := envfile.Parse(envContent)
+	if err != nil {
+		return "", nil, err
+	}
+
+	secrets := make(map[string]string)
+	missing := make(map[string]bool)
+	var blocks []string
+	for _, s := range servers {
+		if s.id == "" || s.id == agentLayerServerID {
+			continue
+		}
+		item := ItemResult{Name: s.id, Source: s.source, Status: StatusAdded}
+		if declared[s.id] {
+			item.Status = StatusExists
+			result.Servers = append(result.Servers, item)
+			continue
+		}
+		declared[s.id] = true
+		refs := make(map[string]bool)
+		s.url = rewriteEnvRefs(s.url, refs)
+		for i, arg := range s.args {
+			s.args[i] = rewriteEnvRefs(arg, refs)
+		}
+		for key, value := range s.env {
+			s.env[key] = secretValue(s.id, key, value, false, secrets, refs)
+		}
+		for key, value := range s.headers {
+			s.headers[key] = secretValue(s.id, key, value, true, secrets, refs)
+		}
+		for name := range refs {
+			if env[name] == "" && secrets[name] == "" {
+				missing[name] = true
+				item.Disabled = true
+			}
+		}
+		blocks = append(blocks, renderServer(s, !s.disabled && !item.Disabled))
+		result.Servers = append(result.Servers, item)
+	}
+
+	for name := range secrets {
+		if env[name] != "" {
+			// Keep the value the user already has; the config references it either way.
+			delete(secrets, name)
+			continue
+		}
+		result.Secrets = append(result.Secrets, name)
+	}
+	sort.Strings(result.Secrets)
+	for name := range missing {
+		result.MissingEnv = append(result.MissingEnv, name)
+	}
+	sort.Strings(result.MissingEnv)
+	if len(blocks) == 0 {
+		return content, secrets, nil
+	}
+	return strings.TrimRight(content, "\n") + "\n\n" + strings.Join(blocks, "\n\n") + "\n", secrets, nil
+}
+
+// envRefPattern matches ${VAR} and ${VAR:-default} references in client
+// configs.
+var envRefPattern = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)(?::-[^}]*)?\}`)
+
+var unsafeEnvChars = regexp.MustCompile(`[^A-Z0-9]+`)
+
+// rewriteEnvRefs points ${VAR} references at the AL_ namespace Agent Layer
+// reads from .env and records the names it produced in refs.
+func rewriteEnvRefs(value string, refs map[string]bool) string {
+	return envRefPattern.ReplaceAllStringFunc(value, func(match string) string {
+		name := alEnvName(envRefPattern.FindStringSubmatch(match)[1])
+		refs[name] = true
+		return "${" + name + "}"
+	})
+}
+
+// secretValue returns the config value for an env or header entry. Values
+// that reference variables are rewritten to AL_ references; literal values
+// move to .env so config.toml stays free of secrets. An env entry keeps its
+// variable name (AL_<KEY>); a header, or a name another server already uses
+// for a different value, is qualified with the server id (AL_<SERVER>_<KEY>).
+func secretValue(id string, key string, value string, header bool, secrets map[string]string, refs map[string]bool) string {
+	if value == "" || envRefPattern.MatchString(value) {
+		return rewriteEnvRefs(value, refs)
+	}
+	name := alEnvName(key)
+	if current, taken := secrets[name]; header || (taken && current != value) {
+		name = alEnvName(id + "_" + key)
+	}
+	secrets[name] = value
+	refs[name] = true
+	return "${" + name + "}"
+}
+
+// alEnvName maps a client variable name into the AL_ namespace.
+func alEnvName(name string) string {
+	upper := strings.Trim(unsafeEnvChars.ReplaceAllString(strings.ToUpper(name), "_"), "_")
+	if strings.HasPrefix(upper, "AL_") {
+		return upper
+	}
+	return "AL_" + upper
+}
+
+// renderServer renders s as a [[mcp.servers]] block.
+func renderServer(s server, enabled bool) string {
+	lines := []string{
+		"[[mcp.servers]]",
+		i18n.Sprintf(messages.ClientImportServerComment, s.source),
+		"id = " + tomlpatch.FormatValue(s.id),
+		"enabled = " + tomlpatch.FormatValue(enabled),
+		"transport = " + tomlpatch.FormatValue(s.transport),
+	}
+	if s.httpTransport != "" {
+		lines = append(lines, "http_transport = "+tomlpatch.FormatValue(s.httpTransport))
+	}
+	if s.url != "" {
+		lines = append(lines, "url = "+tomlpatch.FormatValue(s.url))
+	}
+	if len(s.headers) > 0 {
+		lines = append(lines, "headers = "+inlineTable(s.headers))
+	}
+	if s.command != "" {
+		lines = append(lines, "command = "+tomlpatch.FormatValue(s.command))
+	}
+	if len(s.args) > 0 {
+		values := make([]string, len(s.args))
+		for i, arg := range s.args {
+			values[i] = tomlpatch.FormatValue(arg)
+		}
+		lines = append(lines, "args = ["+strings.Join(values, ", ")+"]")
+	}
+	if len(s.env) > 0 {
+		lines = append(lines, "env = "+inlineTable(s.env))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func inlineTable(values map[string]string) string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	parts := make([]string, len(keys))
+	for i, key := range keys {
+		parts[i] = tomlpatch.FormatKey(key) + " = " + tomlpatch.FormatValue(values[key])
+	}
+	return "{ " + strings.Join(parts, ", ") + " }"
+}
+
+// enableAgent sets enabled = true in the client's [agents.*] table and fills
+// model and reasoning_effort when the table does not set them yet.
+func enableAgent(content string, data found) string {
+	name := "agents." + data.agent
+	lines := strings.Split(content, "\n")
+	start, end := tableBounds(lines, name)
+	if start < 0 {
+		block := []string{"[" + name + "]", "enabled = true"}
+		if data.model != "" {
+			block = append(block, "model = "+tomlpatch.FormatValue(data.model))
+		}
+		if data.reasoning != "" {
+			block = append(block, "reasoning_effort = "+tomlpatch.FormatValue(data.reasoning))
+		}
+		return strings.TrimRight(content, "\n") + "\n\n" + strings.Join(block, "\n") + "\n"
+	}
+	block := &tomlpatch.Block{Name: name, Lines: tomlpatch.CloneLines(lines[start:end])}
+	tomlpatch.SetKeyValue(block, nil, "enabled", "true", "")
+	for _, field := range []struct{ key, value string }{{"model", data.model}, {"reasoning_effort", data.reasoning}} {
+		if field.value == "" {
+			continue
+		}
+		if line, ok := tomlpatch.FindKeyLine(block.Lines, field.key); ok && !line.Commented {
+			continue
+		}
+		tomlpatch.ReplaceOrInsertLine(block, field.key, field.key+" = "+tomlpatch.FormatValue(field.value), "enabled")
+	}
+	patched := append(append(tomlpatch.CloneLines(lines[:start]), block.Lines...), lines[end:]...)
+	return strings.Join(patched, "\n")
+}
+
+// tableBounds returns the line range of the [name] table, from its header to
+// the next header, or -1 when the table is absent.
+func tableBounds(lines []string, name string) (int, int) {
+	start, end := -1, len(lines)
+	tomlpatch.WalkLinesOutsideMultiline(lines, func(i int, line string, state tomlpatch.StringState) tomlpatch.LineWalkResult {
+		if tomlpatch.StateInMultiline(state) {
+			return tomlpatch.LineWalkResult{}
+		}
+		header, _, ok := tomlpatch.ParseHeader(line)
+		if !ok {
+			return tomlpatch.LineWalkResult{}
+		}
+		if start >= 0 {
+			end = i
+			return tomlpatch.LineWalkResult{Stop: true}
+		}
+		if path, ok := tomlpatch.ParseKeyPath(header); ok && tomlpatch.FormatDottedKeyPath(path) == name {
+			start = i
+		}
+		return tomlpatch.LineWalkResult{}
+	})
+	return start, end
+}
+
+// readSource reads the client file rel under root. It reports false when the
+// file is missing or was generated by Agent Layer, and records rel in
+// data.sources otherwise.
+func readSource(root string, rel string, data *found) (string, bool, error) {
+	path := filepath.Join(root, filepath.FromSlash(rel))
+	content, err := os.ReadFile(path) // #nosec G304 -- path is a fixed client config location under the repo root.
+	if errors.Is(err, os.ErrNotExist) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, i18n.Errorf(messages.ClientImportReadFmt, path, err)
+	}
+	if strings.Contains(string(content), generatedMarker) {
+		return "", false, nil
+	}
+	data.sources = append(data.sources, rel)
+	return string(content), true, nil
+}
+
+// addInstructionFile imports rel as an instruction named name when it exists
+// and has content.
+func addInstructionFile(root string, rel string, name string, data *found) error {
+	content, ok, err := readSource(root, rel, data)
+	if err != nil || !ok || strings.TrimSpace(content) == "" {
+		return err
+	}
+	data.instructions = append(data.instructions, instruction{name: name, source: rel, content: content})
+	return nil
+}
+
+// jsonServer is an entry of the mcpServers object Claude, Gemini, and Cursor
+// share in their JSON configs.
+type jsonServer struct {
+	Type    string            `json:"type"`
+	Command string            `json:"command"`
+	Args    []string          `json:"args"`
+	Env     map[string]string `json:"env"`
+	URL     string            `json:"url"`
+	HTTPURL string            `json:"httpUrl"`
+	Headers map[string]string `json:"headers"`
+}
+
+// addJSONServers imports the mcpServers object of the JSON file rel. sseURL
+// reports whether a plain url entry uses the SSE transport.
+func addJSONServers(root string, rel string, sseURL bool, data *found) error {
+	content, ok, err := readSource(root, rel, data)
+	if err != nil || !ok {
+		return err
+	}
+	var parsed struct {
+		MCPServers map[string]jsonServer `json:"mcpServers"`
+	}
+	if err := json.Unmarshal([]byte(content), &parsed); err != nil {
+		return i18n.Errorf(messages.ClientImportParseFileFmt, rel, err)
+	}
+	for _, id := range slices.Sorted(maps.Keys(parsed.MCPServers)) {
+		entry := parsed.MCPServers[id]
+		s := server{id: id, source: rel, command: entry.Command, args: entry.Args, env: entry.Env, headers: entry.Headers}
+		switch {
+		case entry.Command != "":
+			s.transport = "stdio"
+		case entry.HTTPURL != "":
+			s.transport, s.url = "http", entry.HTTPURL
+		case entry.URL != "":
+			s.transport, s.url = "http", entry.URL
+			if entry.Type == "sse" || (sseURL && entry.Type == "") {
+				s.httpTransport = "sse"
+			}
+		default:
+			continue
+		}
+		data.servers = append(data.servers, s)
+	}
+	return nil
+}
@@ -0,0 +1,257 @@
+package clientimport
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/pelletier/go-toml/v2"
+
+	"github.com/conn-castle/agent-layer/internal/config"
+	"github.com/conn-castle/agent-layer/internal/envfile"
+)
+
+const baseConfig = `[approvals]
+mode = "all"
+
+[agents.claude]
+enabled = false
+# model = "..."
+
+[agents.codex]
+enabled = false
+model = "keep-me"
+
+[[mcp.servers]]
+id = "existing"
+enabled = true
+transport = "stdio"
+command = "existing-mcp"
+`
+
+func writeFile(t *testing.T, path string, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read %s: %v", path, err)
+	}
+	return string(data)
+}
+
+func newRepo(t *testing.T) string {
+	t.Helper()
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, ".agent-layer", "config.toml"), baseConfig)
+	writeFile(t, filepath.Join(root, ".agent-layer", ".env"), "AL_GITHUB_TOKEN=\n")
+	return root
+}
+
+func loadConfig(t *testing.T, root string) config.Config {
+	t.Helper()
+	var cfg config.Config
+	if err := toml.Unmarshal([]byte(readFile(t, filepath.Join(root, ".agent-layer", "config.toml"))), &cfg); err != nil {
+		t.Fatalf("parse config: %v", err)
+	}
+	return cfg
+}
+
+func findServer(t *testing.T, cfg config.Config, id string) config.MCPServer {
+	t.Helper()
+	for _, server := range cfg.MCP.Servers {
+		if server.ID == id {
+			return server
+		}
+	}
+	t.Fatalf("server %s not found in %#v", id, cfg.MCP.Servers)
+	return config.MCPServer{}
+}
+
+func TestImportClaude(t *testing.T) {
+	root := newRepo(t)
+	writeFile(t, filepath.Join(root, "CLAUDE.md"), "# Rules\n\nUse tabs.\n")
+	writeFile(t, filepath.Join(root, ".claude", "settings.json"), `{"model": "opus"}`)
+	writeFile(t, filepath.Join(root, ".mcp.json"), `{"mcpServers": {
+		"github": {"command": "npx", "args": ["-y", "server-github"], "env": {"GITHUB_TOKEN": "ghp_secret"}},
+		"docs": {"type": "sse", "url": "https://docs.example.com/sse", "headers": {"Authorization": "Bearer ${DOCS_TOKEN:-x}"}},
+		"existing": {"command": "other"},
+		"agent-layer": {"command": "al"}
+	}}`)
+
+	result, err := Import(Options{Root: root, Client: "Claude"})
+	if err != nil {
+		t.Fatalf("Import: %v", err)
+	}
+	if strings.Join(result.Sources, ",") != "CLAUDE.md,.mcp.json,.claude/settings.json" {
+		t.Fatalf("unexpected sources %v", result.Sources)
+	}
+	if got := readFile(t, filepath.Join(root, ".agent-layer", "instructions", "90_imported_claude.md")); got != "# Rules\n\nUse tabs.\n" {
+		t.Fatalf("unexpected instruction %q", got)
+	}
+
+	cfg := loadConfig(t, root)
+	if !config.IsAgentEnabled(cfg.Agents.Claude.Enabled) || cfg.Agents.Claude.Model != "opus" {
+		t.Fatalf("unexpected claude agent %#v", cfg.Agents.Claude)
+	}
+	github := findServer(t, cfg, "github")
+	if !config.IsAgentEnabled(github.Enabled) || github.Transport != "stdio" || github.Env["GITHUB_TOKEN"] != "${AL_GITHUB_TOKEN}" {
+		t.Fatalf("unexpected github server %#v", github)
+	}
+	docs := findServer(t, cfg, "docs")
+	if config.IsAgentEnabled(docs.Enabled) || docs.HTTPTransport != "sse" || docs.Headers["Authorization"] != "Bearer ${AL_DOCS_TOKEN}" {
+		t.Fatalf("unexpected docs server %#v", docs)
+	}
+	if existing := findServer(t, cfg, "existing"); existing.Command != "existing-mcp" || len(cfg.MCP.Servers) != 3 {
+		t.Fatalf("existing server changed or agent-layer imported: %#v", cfg.MCP.Servers)
+	}
+	if strings.Contains(readFile(t, filepath.Join(root, ".agent-layer", "config.toml")), "ghp_secret") {
+		t.Fatal("literal secret written to config.toml")
+	}
+	env, err := envfile.Parse(readFile(t, filepath.Join(root, ".agent-layer", ".env")))
+	if err != nil || env["AL_GITHUB_TOKEN"] != "ghp_secret" {
+		t.Fatalf("unexpected .env %v, %v", env, err)
+	}
+	if strings.Join(result.Secrets, ",") != "AL_GITHUB_TOKEN" || strings.Join(result.MissingEnv, ",") != "AL_DOCS_TOKEN" {
+		t.Fatalf("unexpected secrets %v / missing %v", result.Secrets, result.MissingEnv)
+	}
+
+	again, err := Import(Options{Root: root, Client: ClientClaude})
+	if err != nil {
+		t.Fatalf("second Import: %v", err)
+	}
+	for _, item := range append(again.Instructions, again.Servers...) {
+		if item.Status != StatusExists {
+			t.Fatalf("expected re-import to add nothing, got %#v", item)
+		}
+	}
+}
+
+func TestImportCodex(t *testing.T) {
+	root := newRepo(t)
+	writeFile(t, filepath.Join(root, "AGENTS.md"), "Run make test.\n")
+	writeFile(t, filepath.Join(root, ".codex", "config.toml"), `model = "gpt-5"
+model_reasoning_effort = "high"
+
+[mcp_servers.search]
+url = "https://search.example.com/mcp"
+bearer_token_env_var = "SEARCH_KEY"
+
+[mcp_servers.local]
+command = "local-mcp"
+env_vars = ["AL_GITHUB_TOKEN"]
+enabled = false
+`)
+
+	result, err := Import(Options{Root: root, Client: ClientCodex})
+	if err != nil {
+		t.Fatalf("Import: %v", err)
+	}
+	cfg := loadConfig(t, root)
+	if !config.IsAgentEnabled(cfg.Agents.Codex.Enabled) || cfg.Agents.Codex.Model != "keep-me" || cfg.Agents.Codex.ReasoningEffort != "high" {
+		t.Fatalf("unexpected codex agent %#v", cfg.Agents.Codex)
+	}
+	search := findServer(t, cfg, "search")
+	if search.Transport != "http" || search.Headers["Authorization"] != "Bearer ${AL_SEARCH_KEY}" {
+		t.Fatalf("unexpected search server %#v", search)
+	}
+	local := findServer(t, cfg, "local")
+	if config.IsAgentEnabled(local.Enabled) || local.Env["AL_GITHUB_TOKEN"] != "${AL_GITHUB_TOKEN}" {
+		t.Fatalf("unexpected local server %#v", local)
+	}
+	if result.Agent != ClientCodex || len(result.Instructions) != 1 {
+		t.Fatalf("unexpected result %#v", result)
+	}
+}
+
+func TestImportGeminiAndCursor(t *testing.T) {
+	root := newRepo(t)
+	writeFile(t, filepath.Join(root, "GEMINI.md"), "Gemini rules.\n")
+	writeFile(t, filepath.Join(root, ".gemini", "settings.json"), `{"mcpServers": {"events": {"url": "https://e.example.com/sse"}, "stream": {"httpUrl": "https://s.example.com/mcp"}}}`)
+	writeFile(t, filepath.Join(root, ".cursorrules"), "Cursor rules.\n")
+	writeFile(t, filepath.Join(root, ".cursor", "rules", "go style.mdc"), "---\ndescription: Go\nglobs: \"*.go\"\n---\nPrefer table tests.\n")
+	writeFile(t, filepath.Join(root, ".cursor", "mcp.json"), `{"mcpServers": {"cursor-only": {"url": "https://c.example.com/mcp"}}}`)
+
+	gemini, err := Import(Options{Root: root, Client: ClientGemini})
+	if err != nil {
+		t.Fatalf("Import gemini: %v", err)
+	}
+	if gemini.Agent != "antigravity" {
+		t.Fatalf("expected gemini import to enable antigravity, got %q", gemini.Agent)
+	}
+	cursor, err := Import(Options{Root: root, Client: ClientCursor})
+	if err != nil {
+		t.Fatalf("Import cursor: %v", err)
+	}
+	if cursor.Agent != "" || len(cursor.Instructions) != 2 {
+		t.Fatalf("unexpected cursor result %#v", cursor)
+	}
+	if got := readFile(t, filepath.Join(root, ".agent-layer", "instructions", "90_imported_cursor_go_style.md")); got != "Prefer table tests.\n" {
+		t.Fatalf("unexpected cursor rule instruction %q", got)
+	}
+
+	cfg := loadConfig(t, root)
+	if !config.IsAgentEnabled(cfg.Agents.Antigravity.Enabled) {
+		t.Fatal("expected antigravity enabled")
+	}
+	if events := findServer(t, cfg, "events"); events.HTTPTransport != "sse" {
+		t.Fatalf("expected gemini url server to use sse, got %#v", events)
+	}
+	if stream := findServer(t, cfg, "stream"); stream.HTTPTransport != "" || stream.URL != "https://s.example.com/mcp" {
+		t.Fatalf("unexpected gemini httpUrl server %#v", stream)
+	}
+	if c := findServer(t, cfg, "cursor-only"); c.HTTPTransport != "" {
+		t.Fatalf("unexpected cursor server %#v", c)
+	}
+}
+
+func TestImportErrors(t *testing.T) {
+	root := newRepo(t)
+	if _, err := Import(Options{Root: root, Client: "vim"}); err == nil || !strings.Contains(err.Error(), "supported: claude, codex, gemini, cursor") {
+		t.Fatalf("expected unknown client error, got %v", err)
+	}
+	if _, err := Sources(root, ClientClaude); err == nil || !strings.Contains(err.Error(), "no claude configuration") {
+		t.Fatalf("expected nothing found error, got %v", err)
+	}
+
+	// Files sync generated are Agent Layer output, not client setup.
+	writeFile(t, filepath.Join(root, "CLAUDE.md"), "<!--\n  GENERATED FILE - DO NOT EDIT\n-->\nbody\n")
+	if _, err := Sources(root, ClientClaude); err == nil {
+		t.Fatal("expected generated CLAUDE.md to be skipped")
+	}
+
+	writeFile(t, filepath.Join(root, ".mcp.json"), "{not json")
+	if _, err := Import(Options{Root: root, Client: ClientClaude}); err == nil || !strings.Contains(err.Error(), ".mcp.json") {
+		t.Fatalf("expected parse error, got %v", err)
+	}
+}
+
+func TestEnableAgentAppendsMissingTable(t *testing.T) {
+	got := enableAgent("[approvals]\nmode = \"all\"\n", found{agent: "antigravity", model: "m"})
+	if !strings.HasSuffix(got, "\n\n[agents.antigravity]\nenabled = true\nmodel = \"m\"\n") {
+		t.Fatalf("unexpected config:\n%s", got)
+	}
+}
+
+func TestSecretValueQualifiesCollisions(t *testing.T) {
+	secrets := make(map[string]string)
+	refs := make(map[string]bool)
+	if got := secretValue("a", "API_KEY", "one", false, secrets, refs); got != "${AL_API_KEY}" {
+		t.Fatalf("got %q", got)
+	}
+	if got := secretValue("b", "API_KEY", "two", false, secrets, refs); got != "${AL_B_API_KEY}" {
+		t.Fatalf("got %q", got)
+	}
+	if got := secretValue("c", "X-Api-Key", "three", true, secrets, refs); got != "${AL_C_X_API_KEY}" {
+		t.Fatalf("got %q", got)
+	}
+}
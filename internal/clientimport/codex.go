@@ -0,0 +1,82 @@
+package clientimport
+
+import (
+	"maps"
+	"slices"
+
+	"github.com/pelletier/go-toml/v2"
+
+	"github.com/conn-castle/agent-layer/internal/i18n"
+	"github.com/conn-castle/agent-layer/internal/messages"
+)
+
+const codexConfig = ".codex/config.toml"
+
+type codexServer struct {
+	Command           string            `toml:"command"`
+	Args              []string          `toml:"args"`
+	Env               map[string]string `toml:"env"`
+	EnvVars           []string          `toml:"env_vars"`
+	URL               string            `toml:"url"`
+	BearerTokenEnvVar string            `toml:"bearer_token_env_var"`
+	HTTPHeaders       map[string]string `toml:"http_headers"`
+	EnvHTTPHeaders    map[string]string `toml:"env_http_headers"`
+	Enabled           *bool             `toml:"enabled"`
+}
+
+// loadCodex reads AGENTS.md and .codex/config.toml: its model,
+// model_reasoning_effort, and [mcp_servers.*] tables. Variables Codex forwards
+// by name (env_vars, bearer_token_env_var, env_http_headers) become AL_
+// references.
+func loadCodex(root string) (found, error) {
+	data := found{agent: ClientCodex}
+	if err := addInstructionFile(root, "AGENTS.md", "codex", &data); err != nil {
+		return found{}, err
+	}
+	content, ok, err := readSource(root, codexConfig, &data)
+	if err != nil || !ok {
+		return data, err
+	}
+	var parsed struct {
+		Model           string                 `toml:"model"`
+		ReasoningEffort string                 `toml:"model_reasoning_effort"`
+		MCPServers      map[string]codexServer `toml:"mcp_servers"`
+	}
+	if err := toml.Unmarshal([]byte(content), &parsed); err != nil {
+		return found{}, i18n.Errorf(messages.ClientImportParseFileFmt, codexConfig, err)
+	}
+	data.model = parsed.Model
+	data.reasoning = parsed.ReasoningEffort
+	for _, id := range slices.Sorted(maps.Keys(parsed.MCPServers)) {
+		entry := parsed.MCPServers[id]
+		s := server{id: id, source: codexConfig, command: entry.Command, args: entry.Args, env: entry.Env, headers: entry.HTTPHeaders}
+		s.disabled = entry.Enabled != nil && !*entry.Enabled
+		switch {
+		case entry.Command != "":
+			s.transport = "stdio"
+		case entry.URL != "":
+			s.transport, s.url = "http", entry.URL
+		default:
+			continue
+		}
+		for _, name := range entry.EnvVars {
+			if s.env == nil {
+				s.env = make(map[string]string)
+			}
+			s.env[name] = "${" + name + "}"
+		}
+		if entry.BearerTokenEnvVar != "" || len(entry.EnvHTTPHeaders) > 0 {
+			if s.headers == nil {
+				s.headers = make(map[string]string)
+			}
+			if entry.BearerTokenEnvVar != "" {
+				s.headers["Authorization"] = "Bearer ${" + entry.BearerTokenEnvVar + "}"
+			}
+			for header, name := range entry.EnvHTTPHeaders {
+				s.headers[header] = "${" + name + "}"
+			}
+		}
+		data.servers = append(data.servers, s)
+	}
+	return data, nil
+}
@@ -0,0 +1,72 @@
+package clientimport
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/conn-castle/agent-layer/internal/i18n"
+	"github.com/conn-castle/agent-layer/internal/messages"
+)
+
+// cursorRulesDir holds Cursor project rules, one .mdc file per rule.
+const cursorRulesDir = ".cursor/rules"
+
+var unsafeNameChars = regexp.MustCompile(`[^A-Za-z0-9_-]+`)
+
+// loadCursor reads .cursorrules, the rules in .cursor/rules/, and
+// .cursor/mcp.json. Cursor has no Agent Layer agent, so no agent is enabled.
+// Rule front matter (description, globs, alwaysApply) is dropped: every rule
+// becomes a repo-wide instruction.
+func loadCursor(root string) (found, error) {
+	var data found
+	if err := addInstructionFile(root, ".cursorrules", "cursor", &data); err != nil {
+		return found{}, err
+	}
+	entries, err := os.ReadDir(filepath.Join(root, filepath.FromSlash(cursorRulesDir)))
+	if err != nil && !os.IsNotExist(err) {
+		return found{}, i18n.Errorf(messages.ClientImportReadFmt, cursorRulesDir, err)
+	}
+	var names []string
+	for _, entry := range entries {
+		ext := path.Ext(entry.Name())
+		if !entry.IsDir() && (ext == ".mdc" || ext == ".md") {
+			names = append(names, entry.Name())
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		rel := path.Join(cursorRulesDir, name)
+		content, ok, err := readSource(root, rel, &data)
+		if err != nil {
+			return found{}, err
+		}
+		body := strings.TrimSpace(stripFrontMatter(content))
+		if !ok || body == "" {
+			continue
+		}
+		base := strings.TrimSuffix(name, path.Ext(name))
+		data.instructions = append(data.instructions, instruction{name: "cursor_" + unsafeNameChars.ReplaceAllString(base, "_"), source: rel, content: body})
+	}
+	if err := addJSONServers(root, ".cursor/mcp.json", false, &data); err != nil {
+		return found{}, err
+	}
+	return data, nil
+}
+
+// stripFrontMatter removes a leading --- delimited block.
+func stripFrontMatter(content string) string {
+	normalized := strings.ReplaceAll(content, "\r\n", "\n")
+	if !strings.HasPrefix(normalized, "---\n") {
+		return content
+	}
+	end := strings.Index(normalized[4:], "\n---")
+	if end < 0 {
+		return content
+	}
+	rest := normalized[4+end+4:]
+	return strings.TrimPrefix(rest, "\n")
+}
@@ -0,0 +1,15 @@
+package clientimport
+
+// loadGemini reads GEMINI.md and the MCP servers in .gemini/settings.json.
+// The Gemini CLI was retired in favor of Antigravity, so the import enables
+// agents.antigravity; Gemini model names do not carry over to agy.
+func loadGemini(root string) (found, error) {
+	data := found{agent: "antigravity"}
+	if err := addInstructionFile(root, "GEMINI.md", "gemini", &data); err != nil {
+		return found{}, err
+	}
+	if err := addJSONServers(root, ".gemini/settings.json", true, &data); err != nil {
+		return found{}, err
+	}
+	return data, nil
+}
@@ -1,13 +1,13 @@
 package antigravity
 
 import (
-	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
 
 	"github.com/conn-castle/agent-layer/internal/clients"
 	"github.com/conn-castle/agent-layer/internal/config"
+	"github.com/conn-castle/agent-layer/internal/i18n"
 	"github.com/conn-castle/agent-layer/internal/messages"
 	"github.com/conn-castle/agent-layer/internal/run"
 )
@@ -24,14 +24,14 @@ var execFunc = clients.ExecHandoff
 // Launch starts Antigravity through agy with a repo-local --gemini_dir.
 func Launch(cfg *config.ProjectConfig, runInfo *run.Info, env []string, passArgs []string) error {
 	if !filepath.IsAbs(cfg.Root) {
-		return fmt.Errorf(messages.ClientsAntigravityRelativeRootFmt, cfg.Root)
+		return i18n.Errorf(messages.ClientsAntigravityRelativeRootFmt, cfg.Root)
 	}
 	// Preflight `agy` discovery BEFORE creating `.agy/` so a missing-binary
 	// failure does not pollute the user's repo with a stray directory
 	// (Round 2 F-B2-5).
 	agyPath, err := lookPathFunc(executableName)
 	if err != nil {
-		return fmt.Errorf(messages.ClientsAntigravityBinaryNotFoundFmt, err)
+		return i18n.Errorf(messages.ClientsAntigravityBinaryNotFoundFmt, err)
 	}
 	args, err := BaseArgs(cfg.Root, cfg.Config)
 	if err != nil {
@@ -42,7 +42,7 @@ func Launch(cfg *config.ProjectConfig, runInfo *run.Info, env []string, passArgs
 
 	argv := append([]string{executableName}, args...)
 	if err := execFunc(agyPath, argv, env); err != nil {
-		return fmt.Errorf(messages.ClientsExecHandoffErrorFmt, "antigravity", err)
+		return i18n.Errorf(messages.ClientsExecHandoffErrorFmt, "antigravity", err)
 	}
 	return nil
 }
@@ -52,11 +52,11 @@ func Launch(cfg *config.ProjectConfig, runInfo *run.Info, env []string, passArgs
 // repository-local configuration directory.
 func BaseArgs(root string, cfg config.Config) ([]string, error) {
 	if !filepath.IsAbs(root) {
-		return nil, fmt.Errorf(messages.ClientsAntigravityRelativeRootFmt, root)
+		return nil, i18n.Errorf(messages.ClientsAntigravityRelativeRootFmt, root)
 	}
 	geminiDir := filepath.Join(root, ".agy")
 	if err := os.MkdirAll(geminiDir, 0o700); err != nil {
-		return nil, fmt.Errorf(messages.ClientsAntigravityMkdirFailedFmt, geminiDir, err)
+		return nil, i18n.Errorf(messages.ClientsAntigravityMkdirFailedFmt, geminiDir, err)
 	}
 	args := []string{"--gemini_dir=" + geminiDir}
 	if cfg.Approvals.Mode == config.ApprovalModeYOLO {
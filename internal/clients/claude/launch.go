@@ -10,8 +10,10 @@ import (
 
 	"github.com/conn-castle/agent-layer/internal/clients"
 	"github.com/conn-castle/agent-layer/internal/config"
+	"github.com/conn-castle/agent-layer/internal/i18n"
 	"github.com/conn-castle/agent-layer/internal/messages"
 	"github.com/conn-castle/agent-layer/internal/run"
+	"github.com/conn-castle/agent-layer/internal/worktree"
 )
 
 // execFunc is overridable for tests; on success it never returns.
@@ -41,12 +43,12 @@ func Launch(cfg *config.ProjectConfig, runInfo *run.Info, env []string, passArgs
 
 	path, err := exec.LookPath(executableName)
 	if err != nil {
-		return fmt.Errorf(messages.ClientsExecLookupErrorFmt, executableName, err)
+		return i18n.Errorf(messages.ClientsExecLookupErrorFmt, executableName, err)
 	}
 
 	argv := append([]string{executableName}, args...)
 	if err := execFunc(path, argv, env); err != nil {
-		return fmt.Errorf(messages.ClientsExecHandoffErrorFmt, executableName, err)
+		return i18n.Errorf(messages.ClientsExecHandoffErrorFmt, executableName, err)
 	}
 	return nil
 }
@@ -62,13 +64,14 @@ func ConfigureEnvironment(root string, env []string, cfg config.ClaudeConfig, wa
 }
 
 // clearStaleClaudeConfigDir removes CLAUDE_CONFIG_DIR from the environment
-// only when its value matches the repo-local path Agent Layer would have set.
+// only when its value matches the repo-local path Agent Layer would have set,
+// here or in another git worktree of the same repo.
 // This prevents a stale value from leaking across repos while preserving any
 // intentional user override that points elsewhere.
 func clearStaleClaudeConfigDir(root string, env []string) []string {
 	expected := filepath.Join(root, ".claude-config")
 	current, ok := clients.GetEnv(env, "CLAUDE_CONFIG_DIR")
-	if ok && clients.SamePath(current, expected) {
+	if ok && (clients.SamePath(current, expected) || worktree.SiblingPath(root, current, ".claude-config")) {
 		return clients.UnsetEnv(env, "CLAUDE_CONFIG_DIR")
 	}
 	return env
@@ -85,11 +88,18 @@ func ensureClaudeConfigDirWithWarning(root string, env []string, warning io.Writ
 		return clients.SetEnv(env, "CLAUDE_CONFIG_DIR", expected)
 	}
 
+	if worktree.SiblingPath(root, current, ".claude-config") {
+		if warning != nil {
+			_, _ = fmt.Fprintf(warning, i18n.T(messages.ClientsWorktreeEnvRepointedFmt), "CLAUDE_CONFIG_DIR", current, expected)
+		}
+		return clients.SetEnv(env, "CLAUDE_CONFIG_DIR", expected)
+	}
+
 	if !clients.SamePath(current, expected) {
 		// Best-effort warning; a stderr write failure does not change the returned
 		// env (the existing CLAUDE_CONFIG_DIR is preserved regardless).
 		if warning != nil {
-			_, _ = fmt.Fprintf(warning, messages.ClientsClaudeConfigDirWarningFmt, current, expected)
+			_, _ = fmt.Fprintf(warning, i18n.T(messages.ClientsClaudeConfigDirWarningFmt), current, expected)
 		}
 	}
 
@@ -9,8 +9,10 @@ import (
 
 	"github.com/conn-castle/agent-layer/internal/clients"
 	"github.com/conn-castle/agent-layer/internal/config"
+	"github.com/conn-castle/agent-layer/internal/i18n"
 	"github.com/conn-castle/agent-layer/internal/messages"
 	"github.com/conn-castle/agent-layer/internal/run"
+	"github.com/conn-castle/agent-layer/internal/worktree"
 )
 
 // execFunc is overridable for tests; on success it never returns.
@@ -24,12 +26,12 @@ func Launch(cfg *config.ProjectConfig, runInfo *run.Info, env []string, passArgs
 
 	path, err := exec.LookPath("codex")
 	if err != nil {
-		return fmt.Errorf(messages.ClientsExecLookupErrorFmt, "codex", err)
+		return i18n.Errorf(messages.ClientsExecLookupErrorFmt, "codex", err)
 	}
 
 	argv := append([]string{"codex"}, args...)
 	if err := execFunc(path, argv, env); err != nil {
-		return fmt.Errorf(messages.ClientsExecHandoffErrorFmt, "codex", err)
+		return i18n.Errorf(messages.ClientsExecHandoffErrorFmt, "codex", err)
 	}
 	return nil
 }
@@ -46,8 +48,19 @@ func ConfigureEnvironment(root string, env []string, cfg config.CodexConfig, war
 	if !ok || current == "" {
 		return clients.SetEnv(env, "CODEX_HOME", expected)
 	}
-	if !clients.SamePath(current, expected) && warning != nil {
-		_, _ = fmt.Fprintf(warning, messages.ClientsCodexHomeWarningFmt, current, expected)
+	if clients.SamePath(current, expected) {
+		return env
+	}
+	// A CODEX_HOME inherited from a shell opened in another worktree of this
+	// repo would run Codex against that worktree's config and sessions.
+	if worktree.SiblingPath(root, current, ".codex") {
+		if warning != nil {
+			_, _ = fmt.Fprintf(warning, i18n.T(messages.ClientsWorktreeEnvRepointedFmt), "CODEX_HOME", current, expected)
+		}
+		return clients.SetEnv(env, "CODEX_HOME", expected)
+	}
+	if warning != nil {
+		_, _ = fmt.Fprintf(warning, i18n.T(messages.ClientsCodexHomeWarningFmt), current, expected)
 	}
 	return env
 }
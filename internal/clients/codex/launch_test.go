@@ -225,3 +225,40 @@ func TestConfigureCodexHomeWarnsOnMismatchWhenEnabled(t *testing.T) {
 		t.Fatalf("expected stderr to contain warning %q, got %q", wantWarning, stderr)
 	}
 }
+
+func TestConfigureEnvironmentRepointsSiblingWorktreeCodexHome(t *testing.T) {
+	base := t.TempDir()
+	main := filepath.Join(base, "main")
+	linked := filepath.Join(base, "feature")
+	admin := filepath.Join(main, ".git", "worktrees", "feature")
+	if err := os.MkdirAll(admin, 0o755); err != nil {
+		t.Fatalf("mkdir admin: %v", err)
+	}
+	if err := os.MkdirAll(linked, 0o755); err != nil {
+		t.Fatalf("mkdir linked: %v", err)
+	}
+	files := map[string]string{
+		filepath.Join(admin, "commondir"): "../..\n",
+		filepath.Join(admin, "gitdir"):    filepath.Join(linked, ".git") + "\n",
+		filepath.Join(linked, ".git"):     "gitdir: " + admin + "\n",
+	}
+	for path, content := range files {
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatalf("write %s: %v", path, err)
+		}
+	}
+	current := filepath.Join(main, ".codex")
+	expected := filepath.Join(linked, ".codex")
+	localConfigDir := true
+	var warning strings.Builder
+
+	out := ConfigureEnvironment(linked, []string{"CODEX_HOME=" + current}, config.CodexConfig{LocalConfigDir: &localConfigDir}, &warning)
+
+	if value, _ := clients.GetEnv(out, "CODEX_HOME"); value != expected {
+		t.Fatalf("expected CODEX_HOME %s, got %s", expected, value)
+	}
+	want := fmt.Sprintf(messages.ClientsWorktreeEnvRepointedFmt, "CODEX_HOME", current, expected)
+	if warning.String() != want {
+		t.Fatalf("expected note %q, got %q", want, warning.String())
+	}
+}
@@ -1,11 +1,11 @@
 package copilotcli
 
 import (
-	"fmt"
 	"os/exec"
 
 	"github.com/conn-castle/agent-layer/internal/clients"
 	"github.com/conn-castle/agent-layer/internal/config"
+	"github.com/conn-castle/agent-layer/internal/i18n"
 	"github.com/conn-castle/agent-layer/internal/messages"
 	"github.com/conn-castle/agent-layer/internal/run"
 )
@@ -30,12 +30,12 @@ func Launch(cfg *config.ProjectConfig, runInfo *run.Info, env []string, passArgs
 
 	path, err := exec.LookPath(executableName)
 	if err != nil {
-		return fmt.Errorf(messages.ClientsExecLookupErrorFmt, executableName, err)
+		return i18n.Errorf(messages.ClientsExecLookupErrorFmt, executableName, err)
 	}
 
 	argv := append([]string{executableName}, args...)
 	if err := execFunc(path, argv, env); err != nil {
-		return fmt.Errorf(messages.ClientsExecHandoffErrorFmt, executableName, err)
+		return i18n.Errorf(messages.ClientsExecHandoffErrorFmt, executableName, err)
 	}
 	return nil
 }
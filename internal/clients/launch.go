@@ -0,0 +1,77 @@
+package clients
+
+import (
+	"fmt"
+	"io"
+	"maps"
+	"os/exec"
+	"slices"
+
+	"github.com/conn-castle/agent-layer/internal/config"
+	"github.com/conn-castle/agent-layer/internal/execguard"
+	"github.com/conn-castle/agent-layer/internal/i18n"
+	"github.com/conn-castle/agent-layer/internal/messages"
+)
+
+// runPreLaunchCommand runs one pre_launch hook from the repo root with the
+// client's environment.
+var runPreLaunchCommand = func(root string, argv []string, env []string, out io.Writer) error {
+	cmd := exec.Command(argv[0], argv[1:]...) // #nosec G204 -- hooks come from the repo's own config.toml and must match commands.allow.
+	cmd.Dir = root
+	cmd.Env = env
+	cmd.Stdout = out
+	cmd.Stderr = out
+	return cmd.Run()
+}
+
+// ApplyLaunchEnv sets [clients.<name>.launch.env] in env, resolving ${VAR}
+// placeholders from the project env. Configured values win over inherited
+// ones. replacer decides what a resolved placeholder becomes; nil
+// substitutes the .env value, as a launch does.
+func ApplyLaunchEnv(env []string, name string, project *config.ProjectConfig, replacer config.EnvVarReplacer) ([]string, error) {
+	launchEnv := project.Config.Clients[name].Launch.Env
+	for _, key := range slices.Sorted(maps.Keys(launchEnv)) {
+		value, err := config.SubstituteEnvVarsWith(launchEnv[key], project.Env, replacer)
+		if err != nil {
+			return nil, i18n.Errorf(messages.ClientsLaunchEnvFmt, name, key, err)
+		}
+		env = SetEnv(env, key, value)
+	}
+	return env, nil
+}
+
+// runPreLaunch runs [clients.<name>.launch] pre_launch hooks in order with
+// the client's environment. Every hook is checked against commands.allow and
+// commands.deny before any runs, and the first failure stops the launch.
+func runPreLaunch(root string, name string, project *config.ProjectConfig, env []string, out io.Writer) error {
+	hooks := project.Config.Clients[name].Launch.PreLaunch
+	if len(hooks) == 0 {
+		return nil
+	}
+	deny, err := execguard.LoadDeny(root)
+	if err != nil {
+		return err
+	}
+	argvs := make([][]string, len(hooks))
+	for i, hook := range hooks {
+		argv, err := config.SplitCommandLine(hook)
+		if err != nil {
+			return i18n.Errorf(messages.ClientsPreLaunchInvalidFmt, name, i, hook, err)
+		}
+		argvs[i] = argv
+		decision := execguard.EvaluateHook(project.CommandsAllow, deny, argv)
+		if decision.Outcome != execguard.Allowed {
+			return i18n.Errorf(messages.ClientsPreLaunchNotAllowedFmt, name, i, hook, decision.Reason)
+		}
+	}
+	if out == nil {
+		out = io.Discard
+	}
+	for i, hook := range hooks {
+		_, _ = fmt.Fprintf(out, i18n.T(messages.ClientsPreLaunchRunningFmt), hook)
+		if err := runPreLaunchCommand(root, argvs[i], env, out); err != nil {
+			return i18n.Errorf(messages.ClientsPreLaunchFailedFmt, hook, err)
+		}
+	}
+	return nil
+}
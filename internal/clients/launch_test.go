@@ -0,0 +1,132 @@
+package clients
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/conn-castle/agent-layer/internal/config"
+	"github.com/conn-castle/agent-layer/internal/errcode"
+	"github.com/conn-castle/agent-layer/internal/run"
+)
+
+func TestRunNoSync_AppliesLaunchEnvAndPreLaunchHooks(t *testing.T) {
+	root := t.TempDir()
+	writeMinimalRepo(t, root)
+	paths := config.DefaultPaths(root)
+	file, err := os.OpenFile(paths.ConfigPath, os.O_APPEND|os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := file.WriteString(`
+[clients.antigravity.launch]
+pre_launch = ["./setup-proxy --port 8080"]
+
+[clients.antigravity.launch.env]
+ANTHROPIC_BASE_URL = "http://localhost:8080"
+PROXY_TOKEN = "${AL_PROXY_TOKEN}"
+`); err != nil {
+		t.Fatal(err)
+	}
+	if err := file.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(paths.EnvPath, []byte("AL_PROXY_TOKEN=secret\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(paths.CommandsAllow, []byte("./setup-proxy\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("ANTHROPIC_BASE_URL", "https://inherited.example")
+
+	var hookEnv []string
+	var hooks []string
+	original := runPreLaunchCommand
+	runPreLaunchCommand = func(_ string, argv []string, env []string, _ io.Writer) error {
+		hooks = append(hooks, strings.Join(argv, " "))
+		hookEnv = env
+		return nil
+	}
+	t.Cleanup(func() { runPreLaunchCommand = original })
+
+	var launchEnv []string
+	var stderr bytes.Buffer
+	err = RunNoSyncWithStderr(root, "antigravity", func(cfg *config.Config) *bool {
+		return cfg.Agents.Antigravity.Enabled
+	}, func(_ *config.ProjectConfig, _ *run.Info, env []string, _ []string) error {
+		if len(hooks) != 1 {
+			t.Fatal("expected pre_launch hooks to run before the client")
+		}
+		launchEnv = env
+		return nil
+	}, false, nil, &stderr)
+	if err != nil {
+		t.Fatalf("RunNoSyncWithStderr: %v", err)
+	}
+	if hooks[0] != "./setup-proxy --port 8080" {
+		t.Fatalf("hooks = %v", hooks)
+	}
+	for _, env := range [][]string{hookEnv, launchEnv} {
+		if value, _ := GetEnv(env, "ANTHROPIC_BASE_URL"); value != "http://localhost:8080" {
+			t.Fatalf("ANTHROPIC_BASE_URL = %q", value)
+		}
+		if value, _ := GetEnv(env, "PROXY_TOKEN"); value != "secret" {
+			t.Fatalf("PROXY_TOKEN = %q", value)
+		}
+	}
+	if !strings.Contains(stderr.String(), "Running pre_launch hook: ./setup-proxy --port 8080") {
+		t.Fatalf("unexpected stderr:\n%s", stderr.String())
+	}
+}
+
+func TestApplyLaunchEnv_MissingPlaceholder(t *testing.T) {
+	project := &config.ProjectConfig{}
+	project.Config.Clients = map[string]config.ClientConfig{
+		"claude": {Launch: config.ClientLaunchConfig{Env: map[string]string{"TOKEN": "${AL_MISSING}"}}},
+	}
+	if _, err := ApplyLaunchEnv(nil, "claude", project, nil); err == nil || !strings.Contains(err.Error(), "clients.claude.launch.env.TOKEN") {
+		t.Fatalf("expected placeholder error, got %v", err)
+	}
+	env, err := ApplyLaunchEnv([]string{"A=1"}, "codex", project, nil)
+	if err != nil || len(env) != 1 {
+		t.Fatalf("expected other clients to be unaffected, got %v %v", env, err)
+	}
+}
+
+func TestRunPreLaunch_RejectsAndReportsFailures(t *testing.T) {
+	root := t.TempDir()
+	project := &config.ProjectConfig{CommandsAllow: []string{"make proxy"}}
+	project.Config.Clients = map[string]config.ClientConfig{
+		"claude": {Launch: config.ClientLaunchConfig{PreLaunch: []string{"make proxy", "curl example.com"}}},
+	}
+	var calls []string
+	original := runPreLaunchCommand
+	runPreLaunchCommand = func(_ string, argv []string, _ []string, _ io.Writer) error {
+		calls = append(calls, strings.Join(argv, " "))
+		return errors.New("exit status 1")
+	}
+	t.Cleanup(func() { runPreLaunchCommand = original })
+
+	err := runPreLaunch(root, "claude", project, nil, nil)
+	if err == nil || !strings.Contains(err.Error(), `clients.claude.launch.pre_launch[1] "curl example.com" cannot run: not in commands.allow`) {
+		t.Fatalf("expected allowlist error, got %v", err)
+	}
+	if len(calls) != 0 {
+		t.Fatalf("expected no hooks to run, got %v", calls)
+	}
+
+	project.CommandsAllow = append(project.CommandsAllow, "curl")
+	err = runPreLaunch(root, "claude", project, nil, nil)
+	if err == nil || !strings.Contains(err.Error(), `pre_launch hook "make proxy" failed: exit status 1`) {
+		t.Fatalf("expected hook failure, got %v", err)
+	}
+	if len(calls) != 1 {
+		t.Fatalf("expected the first failure to stop the hooks, got %v", calls)
+	}
+	if errcode.Of(launchWithRunInfo(root, "claude", project, nil, nil, io.Discard)) != errcode.ClientLaunch {
+		t.Fatal("expected a failing hook to be tagged client_launch_failed")
+	}
+}
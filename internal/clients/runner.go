@@ -2,19 +2,32 @@ package clients
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"os"
 	"strings"
 
 	"github.com/conn-castle/agent-layer/internal/config"
+	"github.com/conn-castle/agent-layer/internal/errcode"
+	"github.com/conn-castle/agent-layer/internal/i18n"
 	"github.com/conn-castle/agent-layer/internal/messages"
 	"github.com/conn-castle/agent-layer/internal/run"
+	"github.com/conn-castle/agent-layer/internal/statelock"
 	"github.com/conn-castle/agent-layer/internal/sync"
 	"github.com/conn-castle/agent-layer/internal/updatewarn"
 	"github.com/conn-castle/agent-layer/internal/warnings"
 )
 
+// staleReasonPathLimit caps how many changed sources a stale warning names.
+const staleReasonPathLimit = 3
+
+// checkSources is a test seam for the launch staleness check.
+var checkSources = sync.CheckSources
+
+// acquireStateLock is a test seam for the state lock sync takes before launch.
+var acquireStateLock = statelock.Acquire
+
 // LaunchFunc launches a client after sync and run setup.
 type LaunchFunc func(project *config.ProjectConfig, runInfo *run.Info, env []string, args []string) error
 
@@ -22,12 +35,15 @@ type LaunchFunc func(project *config.ProjectConfig, runInfo *run.Info, env []str
 type EnabledSelector func(cfg *config.Config) *bool
 
 // Run performs the standard client launch pipeline: load config, sync, create run dir, launch.
-// Warnings from sync are printed to stderr before launching.
+// launch.auto_sync decides whether sync runs: always, only when the sources
+// changed since the last sync, or never. Warnings from sync, or a stale-output
+// warning when sync is skipped, are printed to stderr before launching.
 func Run(ctx context.Context, root string, name string, enabled EnabledSelector, launch LaunchFunc, quiet bool, args []string, currentVersion string) error {
 	return RunWithStderr(ctx, root, name, enabled, launch, quiet, args, currentVersion, os.Stderr)
 }
 
 // RunNoSync performs the standard client launch pipeline without running sync.
+// It warns when the generated outputs are stale.
 func RunNoSync(root string, name string, enabled EnabledSelector, launch LaunchFunc, quiet bool, args []string) error {
 	return RunNoSyncWithStderr(root, name, enabled, launch, quiet, args, os.Stderr)
 }
@@ -44,11 +60,15 @@ func RunNoSyncWithStderr(root string, name string, enabled EnabledSelector, laun
 		stderr = io.Discard
 	}
 
+	if err := warnIfStale(root, project, stderr); err != nil {
+		return err
+	}
+
 	if project.Config.Approvals.Mode == config.ApprovalModeYOLO && stderr != nil {
-		_, _ = fmt.Fprintln(stderr, messages.WarningsPolicyYOLOAck)
+		_, _ = fmt.Fprintln(stderr, i18n.T(messages.WarningsPolicyYOLOAck))
 	}
 
-	return launchWithRunInfo(root, project, launch, args)
+	return launchWithRunInfo(root, name, project, launch, args, stderr)
 }
 
 // RunWithStderr is like Run but allows specifying a custom stderr writer for testing.
@@ -64,23 +84,92 @@ func RunWithStderr(ctx context.Context, root string, name string, enabled Enable
 	if project.Config.Warnings.VersionUpdateOnSync != nil && *project.Config.Warnings.VersionUpdateOnSync {
 		updatewarn.WarnIfOutdated(ctx, currentVersion, stderr)
 	}
-	result, err := sync.RunWithProject(sync.RealSystem{}, root, project)
+	switch project.Config.Launch.AutoSyncMode() {
+	case config.LaunchAutoSyncNever:
+		if err := warnIfStale(root, project, stderr); err != nil {
+			return err
+		}
+	case config.LaunchAutoSyncIfStale:
+		staleness, err := checkSources(sync.RealSystem{}, root, project)
+		if err != nil {
+			return err
+		}
+		if staleness.Stale() {
+			if err := syncBeforeLaunch(root, project, stderr); err != nil {
+				return err
+			}
+		}
+	default:
+		if err := syncBeforeLaunch(root, project, stderr); err != nil {
+			return err
+		}
+	}
+
+	if project.Config.Approvals.Mode == config.ApprovalModeYOLO && stderr != nil {
+		_, _ = fmt.Fprintln(stderr, i18n.T(messages.WarningsPolicyYOLOAck))
+	}
+
+	return launchWithRunInfo(root, name, project, launch, args, stderr)
+}
+
+// syncBeforeLaunch runs sync with its hooks under the repo's state lock, the
+// lock al sync takes, and prints the sync warnings. When another al process
+// holds the lock it notes that on stderr and waits for it.
+func syncBeforeLaunch(root string, project *config.ProjectConfig, stderr io.Writer) (err error) {
+	lock, err := acquireStateLock(root, false)
+	if errors.Is(err, statelock.ErrHeld) {
+		if stderr != nil {
+			_, _ = fmt.Fprintf(stderr, i18n.T(messages.StateLockWaitingFmt), root)
+		}
+		lock, err = acquireStateLock(root, true)
+	}
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if releaseErr := lock.Release(); err == nil {
+			err = releaseErr
+		}
+	}()
+	result, err := sync.RunWithProjectOptions(sync.RealSystem{}, root, project, sync.RunOptions{HookOutput: stderr})
 	if err != nil {
 		return err
 	}
-
-	// Print warnings to stderr before launching
 	if stderr != nil {
 		for _, w := range result.Warnings {
 			_, _ = fmt.Fprintln(stderr, w.String())
 		}
 	}
+	return nil
+}
 
-	if project.Config.Approvals.Mode == config.ApprovalModeYOLO && stderr != nil {
-		_, _ = fmt.Fprintln(stderr, messages.WarningsPolicyYOLOAck)
+// warnIfStale warns on stderr when the generated outputs may not match the
+// project's sources, so a launch without sync never uses stale outputs
+// silently.
+func warnIfStale(root string, project *config.ProjectConfig, stderr io.Writer) error {
+	staleness, err := checkSources(sync.RealSystem{}, root, project)
+	if err != nil {
+		return err
 	}
+	if staleness.Stale() && stderr != nil {
+		_, _ = fmt.Fprintf(stderr, i18n.T(messages.ClientsOutputsStaleFmt), staleReason(staleness))
+	}
+	return nil
+}
 
-	return launchWithRunInfo(root, project, launch, args)
+// staleReason describes why the outputs are stale.
+func staleReason(staleness sync.Staleness) string {
+	switch {
+	case !staleness.Recorded:
+		return messages.ClientsStaleNeverSynced
+	case len(staleness.Paths) > staleReasonPathLimit:
+		paths := strings.Join(staleness.Paths[:staleReasonPathLimit], ", ")
+		return i18n.Sprintf(messages.ClientsStaleSourcesFmt, i18n.Sprintf(messages.SyncListMoreFmt, paths, len(staleness.Paths)-staleReasonPathLimit))
+	case len(staleness.Paths) > 0:
+		return i18n.Sprintf(messages.ClientsStaleSourcesFmt, strings.Join(staleness.Paths, ", "))
+	default:
+		return i18n.Sprintf(messages.ClientsStaleVersionFmt, staleness.PreviousVersion)
+	}
 }
 
 // loadProject loads the project config and verifies the client is enabled.
@@ -95,16 +184,26 @@ func loadProject(root string, name string, enabled EnabledSelector) (*config.Pro
 	return project, nil
 }
 
-// launchWithRunInfo prepares the run info and environment before launching.
-func launchWithRunInfo(root string, project *config.ProjectConfig, launch LaunchFunc, args []string) error {
+// launchWithRunInfo prepares the run info and environment, applies the
+// client's [clients.<name>.launch] env, and runs its pre_launch hooks before
+// launching. Hook output goes to stderr. Launch failures, including a failing
+// hook and a client's non-zero exit, are tagged errcode.ClientLaunch.
+func launchWithRunInfo(root string, name string, project *config.ProjectConfig, launch LaunchFunc, args []string, stderr io.Writer) error {
 	runInfo, err := run.Create(root)
 	if err != nil {
 		return err
 	}
 
 	env := BuildEnv(os.Environ(), project.Env, runInfo)
+	env, err = ApplyLaunchEnv(env, name, project, nil)
+	if err != nil {
+		return errcode.Wrap(errcode.Config, err)
+	}
+	if err := runPreLaunch(root, name, project, env, stderr); err != nil {
+		return errcode.Wrap(errcode.ClientLaunch, err)
+	}
 
-	return launch(project, runInfo, env, args)
+	return errcode.Wrap(errcode.ClientLaunch, launch(project, runInfo, env, args))
 }
 
 func resolveQuiet(quiet bool, project *config.ProjectConfig) bool {
@@ -11,6 +11,7 @@ import (
 
 	"github.com/conn-castle/agent-layer/internal/config"
 	"github.com/conn-castle/agent-layer/internal/run"
+	"github.com/conn-castle/agent-layer/internal/statelock"
 	"github.com/conn-castle/agent-layer/internal/update"
 	"github.com/conn-castle/agent-layer/internal/updatewarn"
 )
@@ -210,6 +211,39 @@ mcp_server_threshold = 5
 	}
 }
 
+func TestRunWithStderr_WaitsForStateLock(t *testing.T) {
+	root := t.TempDir()
+	writeMinimalRepo(t, root)
+	var waits []bool
+	original := acquireStateLock
+	acquireStateLock = func(gotRoot string, wait bool) (*statelock.Lock, error) {
+		waits = append(waits, wait)
+		if !wait {
+			return nil, statelock.ErrHeld
+		}
+		return statelock.Acquire(gotRoot, wait)
+	}
+	t.Cleanup(func() { acquireStateLock = original })
+
+	var stderr bytes.Buffer
+	launched := false
+	err := RunWithStderr(context.Background(), root, "antigravity", func(cfg *config.Config) *bool {
+		return cfg.Agents.Antigravity.Enabled
+	}, func(project *config.ProjectConfig, runInfo *run.Info, env []string, args []string) error {
+		launched = true
+		return nil
+	}, false, nil, "v1.0.0", &stderr)
+	if err != nil {
+		t.Fatalf("RunWithStderr error: %v", err)
+	}
+	if len(waits) != 2 || waits[0] || !waits[1] || !launched {
+		t.Fatalf("waits = %v, launched = %v", waits, launched)
+	}
+	if !strings.Contains(stderr.String(), "Waiting for another al process") {
+		t.Fatalf("expected waiting note, got %q", stderr.String())
+	}
+}
+
 func TestRunWithStderr_QuietSuppressesOutput(t *testing.T) {
 	root := t.TempDir()
 	writeMinimalRepo(t, root)
@@ -556,3 +590,89 @@ func TestRunWithStderr_NilWriter(t *testing.T) {
 		t.Fatalf("RunWithStderr nil writer: %v", err)
 	}
 }
+
+func TestRunWithStderr_AutoSyncModes(t *testing.T) {
+	root := t.TempDir()
+	writeMinimalRepo(t, root)
+	paths := config.DefaultPaths(root)
+	setAutoSync := func(mode string) {
+		t.Helper()
+		data, err := os.ReadFile(paths.ConfigPath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		text := strings.Split(string(data), "\n[launch]\n")[0] + "\n[launch]\nauto_sync = \"" + mode + "\"\n"
+		if err := os.WriteFile(paths.ConfigPath, []byte(text), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	settingsPath := filepath.Join(root, ".agy", "antigravity-cli", "settings.json")
+	launch := func(cfg *config.Config) *bool { return cfg.Agents.Antigravity.Enabled }
+	runLaunch := func() string {
+		t.Helper()
+		var stderr bytes.Buffer
+		err := RunWithStderr(context.Background(), root, "antigravity", launch, func(*config.ProjectConfig, *run.Info, []string, []string) error {
+			return nil
+		}, false, nil, "v1.0.0", &stderr)
+		if err != nil {
+			t.Fatalf("RunWithStderr: %v", err)
+		}
+		return stderr.String()
+	}
+	synced := func() bool {
+		_, err := os.Stat(settingsPath)
+		return err == nil
+	}
+
+	setAutoSync(config.LaunchAutoSyncIfStale)
+	runLaunch()
+	if !synced() {
+		t.Fatal("expected if-stale to sync when no sync was recorded")
+	}
+
+	if err := os.Remove(settingsPath); err != nil {
+		t.Fatal(err)
+	}
+	runLaunch()
+	if synced() {
+		t.Fatal("expected if-stale to skip sync when the sources are unchanged")
+	}
+
+	if err := os.WriteFile(filepath.Join(paths.InstructionsDir, "00_base.md"), []byte("# Base\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	runLaunch()
+	if !synced() {
+		t.Fatal("expected if-stale to sync after a source changed")
+	}
+
+	if err := os.Remove(settingsPath); err != nil {
+		t.Fatal(err)
+	}
+	setAutoSync(config.LaunchAutoSyncNever)
+	output := runLaunch()
+	if synced() {
+		t.Fatal("expected never to skip sync")
+	}
+	if !strings.Contains(output, "generated outputs may be stale (changed since the last sync: config.toml)") {
+		t.Fatalf("expected stale warning, got %q", output)
+	}
+}
+
+func TestRunNoSync_WarnsWhenNeverSynced(t *testing.T) {
+	root := t.TempDir()
+	writeMinimalRepo(t, root)
+
+	var stderr bytes.Buffer
+	err := RunNoSyncWithStderr(root, "antigravity", func(cfg *config.Config) *bool {
+		return cfg.Agents.Antigravity.Enabled
+	}, func(*config.ProjectConfig, *run.Info, []string, []string) error {
+		return nil
+	}, false, nil, &stderr)
+	if err != nil {
+		t.Fatalf("RunNoSyncWithStderr: %v", err)
+	}
+	if !strings.Contains(stderr.String(), "generated outputs may be stale (no sync recorded)") {
+		t.Fatalf("expected stale warning, got %q", stderr.String())
+	}
+}
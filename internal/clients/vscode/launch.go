@@ -9,8 +9,11 @@ import (
 
 	"github.com/conn-castle/agent-layer/internal/clients"
 	"github.com/conn-castle/agent-layer/internal/config"
+	"github.com/conn-castle/agent-layer/internal/i18n"
 	"github.com/conn-castle/agent-layer/internal/messages"
 	"github.com/conn-castle/agent-layer/internal/run"
+	"github.com/conn-castle/agent-layer/internal/sync"
+	"github.com/conn-castle/agent-layer/internal/worktree"
 )
 
 const (
@@ -19,38 +22,43 @@ const (
 )
 
 var (
-	lookPath = exec.LookPath
-	readFile = os.ReadFile
+	lookPath       = exec.LookPath
+	readFile       = os.ReadFile
+	statFile       = os.Stat
+	writeWorkspace = sync.WriteVSCodeWorkspace
 )
 
 // Launch starts VS Code, optionally setting CODEX_HOME and/or CLAUDE_CONFIG_DIR
-// based on the enabled agent extensions.
+// based on the enabled agent extensions. Without a positional argument it
+// opens the repo, or the workspace file sync maintains when
+// clients.vscode.workspace is enabled.
 func Launch(cfg *config.ProjectConfig, runInfo *run.Info, env []string, passArgs []string) error {
-	if err := runPreflight(cfg.Root); err != nil {
+	return launch(cfg, env, passArgs, config.VSCodeWorkspaceEnabled(cfg.Config))
+}
+
+// LaunchWorkspace is Launch for `al vscode --workspace`: it writes the
+// workspace file, so it works without clients.vscode.workspace, and opens it.
+func LaunchWorkspace(cfg *config.ProjectConfig, runInfo *run.Info, env []string, passArgs []string) error {
+	if err := writeWorkspace(sync.RealSystem{}, cfg.Root, cfg); err != nil {
 		return err
 	}
+	return launch(cfg, env, passArgs, true)
+}
 
-	if config.IsAgentEnabled(cfg.Config.Agents.VSCode.Enabled) && config.CodexLocalConfigDirEnabled(cfg.Config.Agents.Codex) {
-		codexHome := filepath.Join(cfg.Root, ".codex")
-		env = clients.SetEnv(env, "CODEX_HOME", codexHome)
+func launch(cfg *config.ProjectConfig, env []string, passArgs []string, workspace bool) error {
+	if err := runPreflight(cfg.Root); err != nil {
+		return err
 	}
 
-	localConfigDir := cfg.Config.Agents.Claude.LocalConfigDir != nil && *cfg.Config.Agents.Claude.LocalConfigDir
-	if config.IsAgentEnabled(cfg.Config.Agents.ClaudeVSCode.Enabled) && localConfigDir {
-		claudeConfigDir := filepath.Join(cfg.Root, ".claude-config")
-		env = clients.SetEnv(env, "CLAUDE_CONFIG_DIR", claudeConfigDir)
-	} else {
-		// Clear only stale repo-local CLAUDE_CONFIG_DIR. Preserve user-defined
-		// values that point outside this repository.
-		expectedClaudeConfigDir := filepath.Join(cfg.Root, ".claude-config")
-		if current, ok := clients.GetEnv(env, "CLAUDE_CONFIG_DIR"); ok && clients.SamePath(current, expectedClaudeConfigDir) {
-			env = clients.UnsetEnv(env, "CLAUDE_CONFIG_DIR")
-		}
-	}
+	env = ConfigureEnvironment(cfg, env)
 
 	args := append([]string{}, passArgs...)
 	if !hasPositionalArg(passArgs) {
-		args = append(args, ".")
+		target, err := launchTarget(cfg.Root, workspace)
+		if err != nil {
+			return err
+		}
+		args = append(args, target)
 	}
 	cmd := exec.Command("code", args...)
 	cmd.Dir = cfg.Root
@@ -60,12 +68,54 @@ func Launch(cfg *config.ProjectConfig, runInfo *run.Info, env []string, passArgs
 	cmd.Env = env
 
 	if err := cmd.Run(); err != nil {
-		return fmt.Errorf(messages.ClientsVSCodeExitErrorFmt, err)
+		return i18n.Errorf(messages.ClientsVSCodeExitErrorFmt, err)
 	}
 
 	return nil
 }
 
+// launchTarget returns what VS Code opens: the workspace file when workspace
+// is set, otherwise the repo folder. Opening the file by absolute path keeps
+// the target independent of how `code` resolves its working directory.
+func launchTarget(root string, workspace bool) (string, error) {
+	if !workspace {
+		return ".", nil
+	}
+	path := sync.VSCodeWorkspacePath(root)
+	if _, err := statFile(path); err != nil {
+		if os.IsNotExist(err) {
+			return "", i18n.Errorf(messages.ClientsVSCodeWorkspaceMissingFmt, path)
+		}
+		return "", err
+	}
+	return path, nil
+}
+
+// ConfigureEnvironment sets CODEX_HOME and CLAUDE_CONFIG_DIR for the enabled
+// agent extensions that use a repo-local config directory.
+func ConfigureEnvironment(cfg *config.ProjectConfig, env []string) []string {
+	if config.IsAgentEnabled(cfg.Config.Agents.VSCode.Enabled) && config.CodexLocalConfigDirEnabled(cfg.Config.Agents.Codex) {
+		codexHome := filepath.Join(cfg.Root, ".codex")
+		env = clients.SetEnv(env, "CODEX_HOME", codexHome)
+	}
+
+	localConfigDir := cfg.Config.Agents.Claude.LocalConfigDir != nil && *cfg.Config.Agents.Claude.LocalConfigDir
+	if config.IsAgentEnabled(cfg.Config.Agents.ClaudeVSCode.Enabled) && localConfigDir {
+		claudeConfigDir := filepath.Join(cfg.Root, ".claude-config")
+		env = clients.SetEnv(env, "CLAUDE_CONFIG_DIR", claudeConfigDir)
+	} else {
+		// Clear only stale repo-local CLAUDE_CONFIG_DIR, including one set for
+		// another worktree of this repo. Preserve user-defined values that
+		// point outside the repository.
+		expectedClaudeConfigDir := filepath.Join(cfg.Root, ".claude-config")
+		if current, ok := clients.GetEnv(env, "CLAUDE_CONFIG_DIR"); ok && (clients.SamePath(current, expectedClaudeConfigDir) || worktree.SiblingPath(cfg.Root, current, ".claude-config")) {
+			env = clients.UnsetEnv(env, "CLAUDE_CONFIG_DIR")
+		}
+	}
+
+	return env
+}
+
 // hasPositionalArg returns true when passArgs contains at least one argument
 // that is not a flag (i.e., does not start with "-"). This lets callers pass
 // workspace files or folder paths without an extra "." being appended.
@@ -90,7 +140,7 @@ func hasPositionalArg(args []string) bool {
 
 func runPreflight(root string) error {
 	if _, err := lookPath("code"); err != nil {
-		return fmt.Errorf(messages.ClientsVSCodeCodeNotFoundFmt, err)
+		return i18n.Errorf(messages.ClientsVSCodeCodeNotFoundFmt, err)
 	}
 	if err := checkManagedSettingsConflict(root); err != nil {
 		return err
@@ -117,13 +167,13 @@ func checkManagedSettingsConflict(root string) error {
 
 	if startCount != 1 || endCount != 1 {
 		reason := fmt.Sprintf("start markers=%d, end markers=%d", startCount, endCount)
-		return fmt.Errorf(messages.ClientsVSCodeManagedBlockConflictFmt, settingsPath, reason)
+		return i18n.Errorf(messages.ClientsVSCodeManagedBlockConflictFmt, settingsPath, reason)
 	}
 
 	startIndex := strings.Index(text, vscodeSettingsManagedStart)
 	endIndex := strings.Index(text, vscodeSettingsManagedEnd)
 	if startIndex > endIndex {
-		return fmt.Errorf(messages.ClientsVSCodeManagedBlockConflictFmt, settingsPath, "start marker appears after end marker")
+		return i18n.Errorf(messages.ClientsVSCodeManagedBlockConflictFmt, settingsPath, "start marker appears after end marker")
 	}
 
 	return nil
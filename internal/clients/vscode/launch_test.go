@@ -323,3 +323,48 @@ func TestLaunchVSCode_AppendsDotWhenNoPositionalArg(t *testing.T) {
 		t.Fatalf("expected trailing '.', got args: %q", argsStr)
 	}
 }
+
+func TestLaunchVSCode_OpensWorkspaceFile(t *testing.T) {
+	origLookPath := lookPath
+	origReadFile := readFile
+	t.Cleanup(func() {
+		lookPath = origLookPath
+		readFile = origReadFile
+	})
+
+	root := t.TempDir()
+	binDir := t.TempDir()
+	argsFile := filepath.Join(t.TempDir(), "args.txt")
+	stubContent := fmt.Sprintf("#!/bin/sh\necho \"$@\" > %s\n", argsFile)
+	if err := os.WriteFile(filepath.Join(binDir, "code"), []byte(stubContent), 0o755); err != nil { // #nosec G306 -- test writes an executable shell stub (PATH-shadowed) for subprocess invocation.
+		t.Fatalf("write stub: %v", err)
+	}
+	t.Setenv("PATH", binDir)
+
+	enabled := true
+	cfg := &config.ProjectConfig{Root: root}
+	cfg.Config.Agents.VSCode.Enabled = &enabled
+	cfg.Config.Clients = map[string]config.ClientConfig{"vscode": {Workspace: &enabled}}
+	workspacePath := filepath.Join(root, ".vscode", "agent-layer.code-workspace")
+
+	err := Launch(cfg, &run.Info{ID: "id", Dir: root}, os.Environ(), nil)
+	if err == nil || !strings.Contains(err.Error(), "run `al sync` or `al vscode --workspace`") {
+		t.Fatalf("expected missing workspace error, got %v", err)
+	}
+
+	// --workspace writes the file even when the config does not enable it.
+	cfg.Config.Clients = nil
+	if err := LaunchWorkspace(cfg, &run.Info{ID: "id", Dir: root}, os.Environ(), []string{"--new-window"}); err != nil {
+		t.Fatalf("LaunchWorkspace error: %v", err)
+	}
+	if _, err := os.Stat(workspacePath); err != nil {
+		t.Fatalf("expected workspace file: %v", err)
+	}
+	got, err := os.ReadFile(argsFile) // #nosec G304 -- path is constructed from test-controlled inputs.
+	if err != nil {
+		t.Fatalf("read args file: %v", err)
+	}
+	if want := "--new-window " + workspacePath; strings.TrimSpace(string(got)) != want {
+		t.Fatalf("args = %q, want %q", strings.TrimSpace(string(got)), want)
+	}
+}
@@ -0,0 +1,91 @@
+// Package clock provides the wall clock and the canonical timestamp format used
+// in Agent Layer state files and reports.
+package clock
+
+import (
+	"strings"
+	"time"
+
+	"github.com/conn-castle/agent-layer/internal/i18n"
+	"github.com/conn-castle/agent-layer/internal/messages"
+)
+
+// Layout is the canonical timestamp format for persisted state and reports.
+// Timestamps are always written in UTC, so the zone suffix is always "Z".
+const Layout = time.RFC3339
+
+// StampLayout is the compact UTC format used in generated IDs and directory
+// names, where lexical order must match chronological order.
+const StampLayout = "20060102-150405"
+
+// legacyLayouts are formats written by older releases or by hand. Layouts
+// without a zone are interpreted in the local time zone, which is how those
+// releases produced them.
+var legacyLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05.999999999 -0700 MST",
+	"2006-01-02 15:04:05 -0700 MST",
+	"2006-01-02 15:04:05 -0700",
+	time.RFC1123Z,
+	time.RFC1123,
+	time.UnixDate,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+}
+
+// Clock reports the current time.
+type Clock interface {
+	Now() time.Time
+}
+
+// Real is the system wall clock. Now returns UTC.
+type Real struct{}
+
+// Now returns the current time in UTC.
+func (Real) Now() time.Time {
+	return time.Now().UTC()
+}
+
+// Fixed is a Clock that always returns T in UTC. Tests use it to make
+// timestamps deterministic.
+type Fixed struct {
+	T time.Time
+}
+
+// Now returns the fixed time in UTC.
+func (f Fixed) Now() time.Time {
+	return f.T.UTC()
+}
+
+// Or returns c, or Real when c is nil.
+func Or(c Clock) Clock {
+	if c == nil {
+		return Real{}
+	}
+	return c
+}
+
+// Format renders t in the canonical UTC layout.
+func Format(t time.Time) string {
+	return t.UTC().Format(Layout)
+}
+
+// Stamp renders t in the compact UTC layout used for IDs.
+func Stamp(t time.Time) string {
+	return t.UTC().Format(StampLayout)
+}
+
+// Parse reads a timestamp written in the canonical layout or any legacy
+// format and returns it in UTC.
+func Parse(raw string) (time.Time, error) {
+	trimmed := strings.TrimSpace(raw)
+	if parsed, err := time.Parse(Layout, trimmed); err == nil {
+		return parsed.UTC(), nil
+	}
+	for _, layout := range legacyLayouts {
+		if parsed, err := time.ParseInLocation(layout, trimmed, time.Local); err == nil {
+			return parsed.UTC(), nil
+		}
+	}
+	return time.Time{}, i18n.Errorf(messages.ClockInvalidTimestampFmt, raw)
+}
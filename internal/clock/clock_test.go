@@ -0,0 +1,75 @@
+package clock
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFormatAlwaysUTC(t *testing.T) {
+	zone := time.FixedZone("PDT", -7*60*60)
+	ts := time.Date(2026, time.March, 8, 1, 30, 0, 0, zone)
+	if got := Format(ts); got != "2026-03-08T08:30:00Z" {
+		t.Fatalf("Format = %q", got)
+	}
+	if got := Stamp(ts); got != "20260308-083000" {
+		t.Fatalf("Stamp = %q", got)
+	}
+}
+
+func TestParseCanonicalAndLegacy(t *testing.T) {
+	want := time.Date(2026, time.November, 1, 8, 30, 0, 0, time.UTC)
+	for _, raw := range []string{
+		"2026-11-01T08:30:00Z",
+		"2026-11-01T01:30:00-07:00",
+		"2026-11-01T08:30:00.000000000Z",
+		"2026-11-01 01:30:00 -0700 PDT",
+		"Sun, 01 Nov 2026 01:30:00 -0700",
+		" 2026-11-01T08:30:00Z\n",
+	} {
+		got, err := Parse(raw)
+		if err != nil {
+			t.Fatalf("Parse(%q) error: %v", raw, err)
+		}
+		if !got.Equal(want) || got.Location() != time.UTC {
+			t.Fatalf("Parse(%q) = %v, want %v in UTC", raw, got, want)
+		}
+	}
+}
+
+func TestParseZonelessUsesLocal(t *testing.T) {
+	orig := time.Local
+	time.Local = time.FixedZone("EST", -5*60*60)
+	t.Cleanup(func() { time.Local = orig })
+
+	got, err := Parse("2026-11-01 03:00:00")
+	if err != nil {
+		t.Fatalf("Parse error: %v", err)
+	}
+	if Format(got) != "2026-11-01T08:00:00Z" {
+		t.Fatalf("Parse zoneless = %s", Format(got))
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	_, err := Parse("yesterday")
+	if err == nil || !strings.Contains(err.Error(), "yesterday") {
+		t.Fatalf("expected invalid timestamp error, got %v", err)
+	}
+}
+
+func TestClocks(t *testing.T) {
+	fixed := Fixed{T: time.Date(2026, time.January, 2, 3, 4, 5, 0, time.FixedZone("X", 3600))}
+	if got := fixed.Now(); got.Location() != time.UTC || got.Hour() != 2 {
+		t.Fatalf("Fixed.Now = %v", got)
+	}
+	if Or(fixed) != Clock(fixed) {
+		t.Fatalf("Or should return the provided clock")
+	}
+	if _, ok := Or(nil).(Real); !ok {
+		t.Fatalf("Or(nil) should return Real")
+	}
+	if (Real{}).Now().Location() != time.UTC {
+		t.Fatalf("Real.Now should be UTC")
+	}
+}
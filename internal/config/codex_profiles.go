@@ -0,0 +1,57 @@
+package config
+
+import (
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/conn-castle/agent-layer/internal/i18n"
+	"github.com/conn-castle/agent-layer/internal/messages"
+)
+
+// codexProfileNamePattern keeps profile names usable as bare TOML keys and
+// as `codex --profile` arguments.
+var codexProfileNamePattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// CodexApprovalPolicies are the approval_policy values Codex accepts.
+var CodexApprovalPolicies = []string{"untrusted", "on-failure", "on-request", "never"}
+
+// CodexSandboxModes are the sandbox_mode values Codex accepts.
+var CodexSandboxModes = []string{"read-only", "workspace-write", "danger-full-access"}
+
+// CodexProfileNames returns the configured profile names in sorted order.
+func CodexProfileNames(profiles map[string]CodexProfile) []string {
+	names := make([]string, 0, len(profiles))
+	for name := range profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// validateCodexProfiles checks agents.codex.profiles names and the approval
+// and sandbox values. Models and reasoning efforts are not validated, matching
+// the top-level Codex fields. Profiles may not also be defined through
+// agent_specific, because both would write the same [profiles] tables.
+func validateCodexProfiles(path string, codex CodexConfig) []error {
+	profiles := codex.Profiles
+	var errs []error
+	if len(profiles) > 0 && HasProviderPassthroughKey(codex.AgentSpecific, CodexProfilesKey) {
+		errs = append(errs, i18n.Errorf(messages.ConfigCodexProfilesConflictFmt, path))
+	}
+	for _, name := range CodexProfileNames(profiles) {
+		if !codexProfileNamePattern.MatchString(name) {
+			errs = append(errs, i18n.Errorf(messages.ConfigCodexProfileNameInvalidFmt, path, name))
+			continue
+		}
+		profile := profiles[name]
+		if profile.ApprovalPolicy != "" && !slices.Contains(CodexApprovalPolicies, profile.ApprovalPolicy) {
+			errs = append(errs, i18n.Errorf(messages.ConfigCodexProfileValueInvalidFmt, path, name, CodexApprovalPolicyKey, profile.ApprovalPolicy, strings.Join(CodexApprovalPolicies, ", ")))
+		}
+		if profile.SandboxMode != "" && !slices.Contains(CodexSandboxModes, profile.SandboxMode) {
+			errs = append(errs, i18n.Errorf(messages.ConfigCodexProfileValueInvalidFmt, path, name, CodexSandboxModeKey, profile.SandboxMode, strings.Join(CodexSandboxModes, ", ")))
+		}
+	}
+	return errs
+}
@@ -0,0 +1,68 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateCodexProfiles(t *testing.T) {
+	valid := CodexConfig{Profiles: map[string]CodexProfile{
+		"fast": {Model: "any-model", ReasoningEffort: "custom-effort"},
+		"deep": {ApprovalPolicy: "on-request", SandboxMode: "workspace-write"},
+	}}
+	if errs := validateCodexProfiles("config.toml", valid); len(errs) != 0 {
+		t.Fatalf("expected valid profiles, got %v", errs)
+	}
+
+	invalid := CodexConfig{Profiles: map[string]CodexProfile{
+		"bad name": {},
+		"loose":    {ApprovalPolicy: "always", SandboxMode: "none"},
+	}}
+	errs := validateCodexProfiles("config.toml", invalid)
+	if len(errs) != 3 {
+		t.Fatalf("expected three errors, got %v", errs)
+	}
+	if !strings.Contains(errs[0].Error(), "agents.codex.profiles.bad name: profile names") {
+		t.Fatalf("unexpected name error %v", errs[0])
+	}
+	if !strings.Contains(errs[1].Error(), `agents.codex.profiles.loose.approval_policy "always" is invalid`) || !strings.Contains(errs[2].Error(), "sandbox_mode") {
+		t.Fatalf("unexpected value errors %v", errs[1:])
+	}
+
+	conflict := CodexConfig{
+		Profiles:      map[string]CodexProfile{"fast": {}},
+		AgentSpecific: ProviderPassthrough{"profiles": map[string]any{"slow": map[string]any{}}},
+	}
+	if errs := validateCodexProfiles("config.toml", conflict); len(errs) != 1 || !strings.Contains(errs[0].Error(), "cannot both be set") {
+		t.Fatalf("expected conflict error, got %v", errs)
+	}
+}
+
+func TestParseConfig_CodexProfiles(t *testing.T) {
+	cfg, err := ParseConfig([]byte(`
+[approvals]
+mode = "all"
+
+[agents.antigravity]
+enabled = false
+[agents.claude]
+enabled = false
+[agents.claude_vscode]
+enabled = false
+[agents.codex]
+enabled = true
+[agents.codex.profiles.fast]
+model = "gpt-5.3-codex-mini"
+approval_policy = "never"
+[agents.vscode]
+enabled = false
+[agents.copilot_cli]
+enabled = false
+`), "config.toml")
+	if err != nil {
+		t.Fatalf("ParseConfig: %v", err)
+	}
+	if got := cfg.Agents.Codex.Profiles["fast"]; got.Model != "gpt-5.3-codex-mini" || got.ApprovalPolicy != "never" {
+		t.Fatalf("unexpected profile %+v", got)
+	}
+}
@@ -0,0 +1,69 @@
+package config
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/conn-castle/agent-layer/internal/i18n"
+	"github.com/conn-castle/agent-layer/internal/messages"
+)
+
+// SplitCommandLine splits a hooks or pre_launch command line into argv the
+// way a POSIX shell splits words, without expanding variables or globs.
+// Single quotes keep their contents literally; double quotes keep spaces and
+// treat a backslash as an escape only before ", \, $ or `; outside quotes a
+// backslash escapes the next character.
+func SplitCommandLine(line string) ([]string, error) {
+	var args []string
+	var word strings.Builder
+	inWord := false
+	escaped := false
+	var quote rune
+	for _, r := range line {
+		switch {
+		case escaped:
+			if quote == '"' && !strings.ContainsRune("\"\\$`", r) {
+				word.WriteRune('\\')
+			}
+			word.WriteRune(r)
+			escaped = false
+		case quote == '\'':
+			if r == '\'' {
+				quote = 0
+			} else {
+				word.WriteRune(r)
+			}
+		case r == '\\':
+			escaped = true
+			inWord = true
+		case quote == '"':
+			if r == '"' {
+				quote = 0
+			} else {
+				word.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote = r
+			inWord = true
+		case unicode.IsSpace(r):
+			if inWord {
+				args = append(args, word.String())
+				word.Reset()
+				inWord = false
+			}
+		default:
+			word.WriteRune(r)
+			inWord = true
+		}
+	}
+	if quote != 0 {
+		return nil, i18n.Errorf(messages.ConfigCommandLineUnterminatedQuoteFmt, string(quote))
+	}
+	if escaped {
+		return nil, i18n.Errorf(messages.ConfigCommandLineTrailingBackslash)
+	}
+	if inWord {
+		args = append(args, word.String())
+	}
+	return args, nil
+}
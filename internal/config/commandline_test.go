@@ -0,0 +1,45 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSplitCommandLine(t *testing.T) {
+	tests := []struct {
+		line string
+		want []string
+	}{
+		{"make agent-context", []string{"make", "agent-context"}},
+		{"  ./notify   --synced\t", []string{"./notify", "--synced"}},
+		{`./notify --message "outputs synced"`, []string{"./notify", "--message", "outputs synced"}},
+		{`echo 'a "b" $c'`, []string{"echo", `a "b" $c`}},
+		{`echo "a \"b\" \$c \n"`, []string{"echo", `a "b" $c \n`}},
+		{`echo a\ b ""`, []string{"echo", "a b", ""}},
+		{`echo --name="x y"z`, []string{"echo", "--name=x yz"}},
+	}
+	for _, tt := range tests {
+		got, err := SplitCommandLine(tt.line)
+		if err != nil {
+			t.Fatalf("SplitCommandLine(%q): %v", tt.line, err)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Fatalf("SplitCommandLine(%q) = %q, want %q", tt.line, got, tt.want)
+		}
+	}
+}
+
+func TestSplitCommandLine_Errors(t *testing.T) {
+	tests := map[string]string{
+		`echo "open`: `unterminated " quote`,
+		`echo 'open`: "unterminated ' quote",
+		`echo open\`: "trailing backslash",
+	}
+	for line, want := range tests {
+		_, err := SplitCommandLine(line)
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Fatalf("SplitCommandLine(%q) error = %v, want %q", line, err, want)
+		}
+	}
+}
@@ -0,0 +1,168 @@
+package config
+
+import (
+	"strings"
+
+	"github.com/pelletier/go-toml/v2"
+
+	"github.com/conn-castle/agent-layer/internal/i18n"
+	"github.com/conn-castle/agent-layer/internal/messages"
+)
+
+// Deprecation describes a config key that was renamed or retired. The table
+// below is the single source of truth for legacy keys: the lenient loader,
+// `al config lint`, and the migration generator (internal/tools/genconfigmigrations)
+// all read it, so retiring a key is a one-line change here plus a regenerated
+// migration manifest.
+type Deprecation struct {
+	// Key is the dotted legacy key; it may name a whole table.
+	Key string
+	// Replacement is the dotted key that supersedes Key, or "" when the key
+	// was retired without a successor.
+	Replacement string
+	// Since is the release whose `al upgrade` migration rewrites Key. The
+	// generator emits the migration operation into that release's manifest.
+	Since string
+	// RemovedIn is the first release that rejects Key at load time.
+	RemovedIn string
+	// MigrationID is the operation id in the Since manifest. The letter
+	// prefix orders the operation among hand-written ones.
+	MigrationID string
+	// Rationale explains the change; it becomes the operation's rationale.
+	Rationale string
+}
+
+// deprecations lists every legacy config key, oldest first. Entries that
+// share a table (agents.gemini.enabled, then agents.gemini) are ordered the
+// way the migration applies them.
+var deprecations = []Deprecation{
+	{
+		Key:         "agents.claude-vscode.enabled",
+		Replacement: "agents.claude_vscode.enabled",
+		Since:       "0.8.8",
+		RemovedIn:   "0.8.8",
+		MigrationID: "a-rename-claude-vscode-enabled-key",
+		Rationale:   "Canonicalize config keys to snake_case by renaming the Claude VS Code agent table key.",
+	},
+	{
+		Key:         "agents.gemini.enabled",
+		Replacement: "agents.antigravity.enabled",
+		Since:       "0.10.2",
+		RemovedIn:   "0.10.2",
+		MigrationID: "b-rename-agents-gemini-enabled",
+		Rationale:   "Migrate the deprecated Gemini CLI enablement flag to the replacement agy-backed Antigravity client.",
+	},
+	{
+		Key:         "agents.gemini",
+		Since:       "0.10.2",
+		RemovedIn:   "0.10.2",
+		MigrationID: "c-delete-agents-gemini",
+		Rationale:   "Remove the leftover deprecated Gemini CLI table, including unsupported model and reasoning_effort keys.",
+	},
+	{
+		Key:         "agents.antigravity.agent_specific.model",
+		Replacement: AntigravityModelFieldKey,
+		Since:       "0.12.0",
+		RemovedIn:   "0.12.0",
+		MigrationID: "i-rename-antigravity-agent-specific-model",
+		Rationale:   "Promote Antigravity model selection from provider passthrough to Agent Layer's typed agents.antigravity.model field before runtime validation rejects the passthrough key.",
+	},
+	{
+		Key:         "agents.antigravity.dispatch",
+		Since:       "0.14.0",
+		RemovedIn:   "0.14.0",
+		MigrationID: "a-delete-retired-antigravity-dispatch-default",
+		Rationale:   "Remove the retired per-agent Agent Dispatch default table. The asynchronous dispatch interface requires callers to select a target explicitly.",
+	},
+	{
+		Key:         "agents.claude.dispatch",
+		Since:       "0.14.0",
+		RemovedIn:   "0.14.0",
+		MigrationID: "b-delete-retired-claude-dispatch-default",
+		Rationale:   "Remove the retired per-agent Agent Dispatch default table. The asynchronous dispatch interface requires callers to select a target explicitly.",
+	},
+	{
+		Key:         "agents.codex.dispatch",
+		Since:       "0.14.0",
+		RemovedIn:   "0.14.0",
+		MigrationID: "c-delete-retired-codex-dispatch-default",
+		Rationale:   "Remove the retired per-agent Agent Dispatch default table. The asynchronous dispatch interface requires callers to select a target explicitly.",
+	},
+}
+
+// Deprecations returns a copy of the deprecation table.
+func Deprecations() []Deprecation {
+	out := make([]Deprecation, len(deprecations))
+	copy(out, deprecations)
+	return out
+}
+
+// Message renders the deprecation as a one-line warning about source.
+func (d Deprecation) Message(source string) string {
+	if d.Replacement == "" {
+		return i18n.Sprintf(messages.ConfigDeprecatedKeyRemovedFmt, source, d.Key, d.RemovedIn)
+	}
+	return i18n.Sprintf(messages.ConfigDeprecatedKeyReplacedFmt, source, d.Key, d.RemovedIn, d.Replacement)
+}
+
+// FindDeprecations returns the table entries whose keys are present in data,
+// in table order. Entries are matched after earlier ones are removed, so a
+// retired table is not reported again once a rename emptied it (for example
+// agents.gemini when agents.gemini.enabled was its only key). Unparseable
+// TOML yields no entries.
+func FindDeprecations(data []byte) []Deprecation {
+	var raw map[string]any
+	if err := toml.Unmarshal(data, &raw); err != nil {
+		return nil
+	}
+	return takeDeprecations(raw)
+}
+
+// takeDeprecations reports and removes deprecated keys from raw.
+func takeDeprecations(raw map[string]any) []Deprecation {
+	var found []Deprecation
+	for _, deprecation := range deprecations {
+		if deleteRawKey(raw, deprecation.Key) {
+			found = append(found, deprecation)
+		}
+	}
+	return found
+}
+
+// lookupRawKey returns the value at the dotted key in raw.
+func lookupRawKey(raw map[string]any, key string) (any, bool) {
+	var current any = raw
+	for _, part := range strings.Split(key, ".") {
+		table, ok := current.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		if current, ok = table[part]; !ok {
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+// deleteRawKey removes the dotted key from raw and reports whether it was
+// present. A parent table left empty by the removal is removed too, so a
+// legacy table whose only key was renamed does not linger as an unknown key.
+func deleteRawKey(raw map[string]any, key string) bool {
+	table := raw
+	parentKey, last := "", key
+	if i := strings.LastIndex(key, "."); i >= 0 {
+		parentKey, last = key[:i], key[i+1:]
+		value, ok := lookupRawKey(raw, parentKey)
+		if table, ok = value.(map[string]any); !ok {
+			return false
+		}
+	}
+	if _, ok := table[last]; !ok {
+		return false
+	}
+	delete(table, last)
+	if parentKey != "" && len(table) == 0 {
+		deleteRawKey(raw, parentKey)
+	}
+	return true
+}
@@ -0,0 +1,76 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func deprecationKeys(found []Deprecation) []string {
+	keys := make([]string, len(found))
+	for i, deprecation := range found {
+		keys[i] = deprecation.Key
+	}
+	return keys
+}
+
+func TestFindDeprecations(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want []string
+	}{
+		{name: "none", data: "[agents.claude]\nenabled = true\n", want: []string{}},
+		{name: "renamed key empties legacy table", data: "[agents.gemini]\nenabled = true\n", want: []string{"agents.gemini.enabled"}},
+		{name: "legacy table keeps other keys", data: "[agents.gemini]\nenabled = true\nmodel = \"pro\"\n", want: []string{"agents.gemini.enabled", "agents.gemini"}},
+		{name: "retired table only", data: "[agents.gemini]\nmodel = \"pro\"\n", want: []string{"agents.gemini"}},
+		{name: "nested passthrough", data: "[agents.antigravity.agent_specific]\nmodel = \"x\"\n", want: []string{"agents.antigravity.agent_specific.model"}},
+		{name: "dispatch tables", data: "[agents.claude.dispatch]\ndefault_agent = \"codex\"\n[agents.codex.dispatch]\ndefault_agent = \"claude\"\n", want: []string{"agents.claude.dispatch", "agents.codex.dispatch"}},
+		{name: "invalid toml", data: "[agents", want: []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := deprecationKeys(FindDeprecations([]byte(tt.data)))
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Fatalf("FindDeprecations = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDeprecationMessage(t *testing.T) {
+	renamed := Deprecation{Key: "agents.old.enabled", Replacement: "agents.new.enabled", RemovedIn: "1.2.0"}
+	if got := renamed.Message("config.toml"); got != "config.toml: agents.old.enabled was removed in 1.2.0; use agents.new.enabled instead (run 'al upgrade' to migrate)" {
+		t.Fatalf("unexpected rename message: %s", got)
+	}
+	retired := Deprecation{Key: "agents.old", RemovedIn: "1.2.0"}
+	if got := retired.Message("config.toml"); got != "config.toml: agents.old was removed in 1.2.0; run 'al upgrade' to delete it" {
+		t.Fatalf("unexpected removal message: %s", got)
+	}
+}
+
+func TestDeprecationsTableIsWellFormed(t *testing.T) {
+	ids := make(map[string]string)
+	for _, deprecation := range Deprecations() {
+		if deprecation.Key == "" || deprecation.Since == "" || deprecation.RemovedIn == "" || deprecation.MigrationID == "" || deprecation.Rationale == "" {
+			t.Fatalf("incomplete deprecation entry: %+v", deprecation)
+		}
+		scoped := deprecation.Since + "/" + deprecation.MigrationID
+		if other, ok := ids[scoped]; ok {
+			t.Fatalf("migration id %s used by %s and %s", scoped, other, deprecation.Key)
+		}
+		ids[scoped] = deprecation.Key
+	}
+}
+
+func TestParseConfigLenient_RecordsDeprecations(t *testing.T) {
+	cfg, err := ParseConfigLenient([]byte("[agents.claude-vscode]\nenabled = true\n"), "config.toml")
+	if err != nil {
+		t.Fatalf("ParseConfigLenient error: %v", err)
+	}
+	if got := deprecationKeys(cfg.Deprecated); len(got) != 1 || got[0] != "agents.claude-vscode.enabled" {
+		t.Fatalf("Deprecated = %v", got)
+	}
+	if cfg.Agents.ClaudeVSCode.Enabled == nil || !*cfg.Agents.ClaudeVSCode.Enabled {
+		t.Fatalf("expected legacy alias to still apply")
+	}
+}
@@ -1,7 +1,6 @@
 package config
 
 import (
-	"fmt"
 	"path/filepath"
 	"regexp"
 	"sort"
@@ -9,6 +8,7 @@ import (
 
 	"github.com/mitchellh/go-homedir"
 
+	"github.com/conn-castle/agent-layer/internal/i18n"
 	"github.com/conn-castle/agent-layer/internal/messages"
 )
 
@@ -62,7 +62,7 @@ func SubstituteEnvVarsWith(input string, env map[string]string, replacer EnvVarR
 			names = append(names, name)
 		}
 		sort.Strings(names)
-		return "", fmt.Errorf(messages.ConfigMissingEnvVarsFmt, strings.Join(names, ", "))
+		return "", i18n.Errorf(messages.ConfigMissingEnvVarsFmt, strings.Join(names, ", "))
 	}
 
 	return result, nil
@@ -107,7 +107,7 @@ func ExpandPath(value string, repoRoot string) (string, error) {
 		return filepath.Clean(expanded), nil
 	}
 	if repoRoot == "" {
-		return "", fmt.Errorf(messages.ConfigRepoRootRequiredPath)
+		return "", i18n.Errorf(messages.ConfigRepoRootRequiredPath)
 	}
 	return filepath.Clean(filepath.Join(repoRoot, expanded)), nil
 }
@@ -77,3 +77,20 @@ func TestLoadEnvMissingKey(t *testing.T) {
 		t.Fatalf("unexpected error: %v", err)
 	}
 }
+
+func TestLoadEnvEncryptedWithoutIdentity(t *testing.T) {
+	t.Setenv("AL_AGE_KEY_FILE", filepath.Join(t.TempDir(), "missing.txt"))
+	dir := t.TempDir()
+	path := filepath.Join(dir, ".env")
+	if err := os.WriteFile(path, []byte("AL_PLAIN=ok\nAL_TOKEN=ENC[age:AAAA]\n"), 0o600); err != nil {
+		t.Fatalf("write env: %v", err)
+	}
+
+	_, err := LoadEnv(path)
+	if err == nil {
+		t.Fatalf("expected decryption error")
+	}
+	if !strings.Contains(err.Error(), "AL_TOKEN") || !strings.Contains(err.Error(), "no age identity found") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
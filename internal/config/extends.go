@@ -0,0 +1,329 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/pelletier/go-toml/v2"
+
+	"github.com/conn-castle/agent-layer/internal/i18n"
+	"github.com/conn-castle/agent-layer/internal/lockfile"
+	"github.com/conn-castle/agent-layer/internal/messages"
+	"github.com/conn-castle/agent-layer/internal/remotebase"
+)
+
+const (
+	extendsKey         = "extends"
+	extendsChecksumKey = "extends_checksum"
+	baseConfigFile     = "config.toml"
+	baseInstructions   = "instructions"
+	baseSkills         = "skills"
+	baseCommandsAllow  = "commands.allow"
+)
+
+// resolveExtendsFunc returns the local directory of a base bundle. Tests
+// replace it to avoid network access.
+var resolveExtendsFunc = func(source string, checksum string) (string, error) {
+	return remotebase.Resolve(remotebase.RealSystem{}, source, checksum)
+}
+
+// baseLayer is a resolved shared base bundle that the local repo layers on top of.
+// The bundle mirrors .agent-layer/: config.toml, instructions/, skills/, and
+// commands.allow are all optional.
+type baseLayer struct {
+	dir  string
+	fsys fs.FS
+}
+
+// validateExtends checks the extends source and checksum pin syntax.
+func validateExtends(path string, source string, checksum string) error {
+	if source == "" {
+		if checksum != "" {
+			return i18n.Errorf(messages.ConfigExtendsChecksumWithoutSourceFmt, path)
+		}
+		return nil
+	}
+	if _, err := remotebase.ParseSource(source); err != nil {
+		return i18n.Errorf(messages.ConfigExtendsSourceInvalidFmt, path, err)
+	}
+	if err := remotebase.ValidateChecksum(checksum); err != nil {
+		return i18n.Errorf(messages.ConfigExtendsChecksumInvalidFmt, path, err)
+	}
+	return nil
+}
+
+// loadLayeredConfigFS reads config.toml from fsys and, when it sets extends,
+// resolves the base bundle and parses the merged config. The returned layer is
+// nil when the config does not extend a base.
+func loadLayeredConfigFS(fsys fs.FS, root string, path string) (*Config, *baseLayer, error) {
+	data, err := readFileFS(fsys, root, path)
+	if err != nil {
+		return nil, nil, i18n.Errorf(messages.ConfigMissingFileFmt, path, err)
+	}
+	var raw map[string]any
+	if err := toml.Unmarshal(data, &raw); err != nil {
+		return nil, nil, i18n.Errorf(messages.ConfigInvalidConfigFmt, path, err)
+	}
+	source, _ := raw[extendsKey].(string)
+	if source == "" {
+		cfg, err := ParseConfig(data, path)
+		return cfg, nil, err
+	}
+	checksum, _ := raw[extendsChecksumKey].(string)
+	if err := validateExtends(path, source, checksum); err != nil {
+		return nil, nil, fmt.Errorf("%w: %w", ErrConfigValidation, err)
+	}
+
+	lockPath := lockfile.Path(root)
+	pinnedByLock := false
+	if checksum == "" {
+		locked, err := lockedExtendsChecksum(fsys, root, lockPath, source)
+		if err != nil {
+			return nil, nil, err
+		}
+		checksum = locked
+		pinnedByLock = locked != ""
+	}
+	dir, err := resolveExtendsFunc(source, checksum)
+	if err != nil {
+		if pinnedByLock {
+			return nil, nil, i18n.Errorf(messages.ConfigExtendsLockedResolveFmt, err, lockPath)
+		}
+		return nil, nil, err
+	}
+	layer := &baseLayer{dir: dir, fsys: os.DirFS(dir)}
+	merged, err := layer.mergeConfig(raw, path)
+	if err != nil {
+		return nil, nil, err
+	}
+	cfg, err := ParseConfig(merged, path)
+	if err != nil {
+		return nil, nil, err
+	}
+	return cfg, layer, nil
+}
+
+// lockedExtendsChecksum returns the checksum al.lock records for source, or ""
+// when the repo has no lock or the lock was recorded for a different source.
+func lockedExtendsChecksum(fsys fs.FS, root string, lockPath string, source string) (string, error) {
+	data, err := readFileFS(fsys, root, lockPath)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return "", nil
+		}
+		return "", i18n.Errorf(messages.LockfileReadFailedFmt, lockPath, err)
+	}
+	lock, err := lockfile.Parse(data, lockPath)
+	if err != nil {
+		return "", err
+	}
+	return lock.ExtendsChecksum(source), nil
+}
+
+// mergeConfig overlays the local raw config on the base config.toml and
+// returns the merged TOML document.
+func (b *baseLayer) mergeConfig(local map[string]any, path string) ([]byte, error) {
+	basePath := filepath.Join(b.dir, baseConfigFile)
+	base := map[string]any{}
+	data, err := fs.ReadFile(b.fsys, baseConfigFile)
+	switch {
+	case err == nil:
+		if err := toml.Unmarshal(data, &base); err != nil {
+			return nil, i18n.Errorf(messages.ConfigExtendsInvalidBaseConfigFmt, basePath, err)
+		}
+	case !errors.Is(err, fs.ErrNotExist):
+		return nil, i18n.Errorf(messages.ConfigExtendsInvalidBaseConfigFmt, basePath, err)
+	}
+	if _, ok := base[extendsKey]; ok {
+		return nil, fmt.Errorf("%w: "+messages.ConfigExtendsNestedFmt, ErrConfigValidation, path, basePath)
+	}
+	merged, err := toml.Marshal(mergeConfigTables(base, local))
+	if err != nil {
+		return nil, i18n.Errorf(messages.ConfigExtendsMergeFailedFmt, basePath, path, err)
+	}
+	return merged, nil
+}
+
+// mergeConfigTables deep-merges local over base. Tables merge key by key,
+// mcp.servers merge by id (local entries replace base entries with the same
+// id), and every other value in local replaces the base value.
+func mergeConfigTables(base map[string]any, local map[string]any) map[string]any {
+	out := make(map[string]any, len(base)+len(local))
+	for key, value := range base {
+		out[key] = value
+	}
+	for key, value := range local {
+		baseTable, baseIsTable := out[key].(map[string]any)
+		localTable, localIsTable := value.(map[string]any)
+		if baseIsTable && localIsTable {
+			if key == "mcp" {
+				out[key] = mergeMCPTables(baseTable, localTable)
+				continue
+			}
+			out[key] = mergeConfigTables(baseTable, localTable)
+			continue
+		}
+		out[key] = value
+	}
+	return out
+}
+
+func mergeMCPTables(base map[string]any, local map[string]any) map[string]any {
+	out := mergeConfigTables(withoutKey(base, "servers"), withoutKey(local, "servers"))
+	baseServers, _ := base["servers"].([]any)
+	localServers, _ := local["servers"].([]any)
+	if baseServers == nil && localServers == nil {
+		return out
+	}
+	localByID := make(map[string]any, len(localServers))
+	for _, server := range localServers {
+		if id := serverID(server); id != "" {
+			localByID[id] = server
+		}
+	}
+	servers := make([]any, 0, len(baseServers)+len(localServers))
+	used := make(map[string]bool, len(localByID))
+	for _, server := range baseServers {
+		id := serverID(server)
+		if replacement, ok := localByID[id]; ok && id != "" {
+			servers = append(servers, replacement)
+			used[id] = true
+			continue
+		}
+		servers = append(servers, server)
+	}
+	for _, server := range localServers {
+		if id := serverID(server); id != "" && used[id] {
+			continue
+		}
+		servers = append(servers, server)
+	}
+	out["servers"] = servers
+	return out
+}
+
+func serverID(server any) string {
+	table, ok := server.(map[string]any)
+	if !ok {
+		return ""
+	}
+	id, _ := table["id"].(string)
+	return id
+}
+
+func withoutKey(table map[string]any, key string) map[string]any {
+	out := make(map[string]any, len(table))
+	for k, v := range table {
+		if k != key {
+			out[k] = v
+		}
+	}
+	return out
+}
+
+// exists reports whether name exists in the base bundle.
+func (b *baseLayer) exists(name string) (bool, error) {
+	if _, err := fs.Stat(b.fsys, name); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// instructions returns base instruction files; local files with the same name win.
+func (b *baseLayer) instructions(local []InstructionFile) ([]InstructionFile, error) {
+	dir := filepath.Join(b.dir, baseInstructions)
+	ok, err := b.exists(baseInstructions)
+	if err != nil {
+		return nil, i18n.Errorf(messages.ConfigMissingInstructionsDirFmt, dir, err)
+	}
+	if !ok {
+		return local, nil
+	}
+	base, err := LoadInstructionsFS(b.fsys, b.dir, dir)
+	if err != nil {
+		return nil, err
+	}
+	byName := make(map[string]InstructionFile, len(base)+len(local))
+	for _, file := range base {
+		byName[file.Name] = file
+	}
+	for _, file := range local {
+		byName[file.Name] = file
+	}
+	names := make([]string, 0, len(byName))
+	for name := range byName {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	merged := make([]InstructionFile, 0, len(names))
+	for _, name := range names {
+		merged = append(merged, byName[name])
+	}
+	return merged, nil
+}
+
+// skills returns base skills; local skills with the same name win.
+func (b *baseLayer) skills(local []Skill) ([]Skill, error) {
+	dir := filepath.Join(b.dir, baseSkills)
+	ok, err := b.exists(baseSkills)
+	if err != nil {
+		return nil, i18n.Errorf(messages.ConfigMissingSkillsDirFmt, dir, err)
+	}
+	if !ok {
+		return local, nil
+	}
+	base, err := LoadSkillsFS(b.fsys, b.dir, dir)
+	if err != nil {
+		return nil, err
+	}
+	byName := make(map[string]Skill, len(base)+len(local))
+	for _, skill := range base {
+		byName[skillKey(skill)] = skill
+	}
+	for _, skill := range local {
+		byName[skillKey(skill)] = skill
+	}
+	names := make([]string, 0, len(byName))
+	for name := range byName {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	merged := make([]Skill, 0, len(names))
+	for _, name := range names {
+		merged = append(merged, byName[name])
+	}
+	return merged, nil
+}
+
+// commandsAllow returns the base allowlist followed by local prefixes, deduplicated.
+func (b *baseLayer) commandsAllow(local []string) ([]string, error) {
+	path := filepath.Join(b.dir, baseCommandsAllow)
+	ok, err := b.exists(baseCommandsAllow)
+	if err != nil {
+		return nil, i18n.Errorf(messages.ConfigMissingCommandsAllowlistFmt, path, err)
+	}
+	if !ok {
+		return local, nil
+	}
+	base, err := LoadCommandsAllowFS(b.fsys, b.dir, path)
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[string]bool, len(base)+len(local))
+	merged := make([]string, 0, len(base)+len(local))
+	for _, command := range append(base, local...) {
+		if seen[command] {
+			continue
+		}
+		seen[command] = true
+		merged = append(merged, command)
+	}
+	return merged, nil
+}
@@ -0,0 +1,285 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const extendsLocalConfig = `
+extends = "github.com/org/agent-layer-base@v1"
+
+[approvals]
+mode = "commands"
+
+[agents.claude]
+enabled = false
+
+[[mcp.servers]]
+id = "shared"
+enabled = false
+transport = "http"
+url = "https://local.example.com/mcp"
+
+[[mcp.servers]]
+id = "local-only"
+enabled = true
+transport = "stdio"
+command = "local-tool"
+`
+
+const extendsBaseConfig = `
+[approvals]
+mode = "all"
+
+[agents.antigravity]
+enabled = true
+
+[agents.claude]
+enabled = true
+model = "opus"
+
+[agents.claude_vscode]
+enabled = true
+
+[agents.codex]
+enabled = true
+
+[agents.vscode]
+enabled = true
+
+[agents.copilot_cli]
+enabled = false
+
+[[mcp.servers]]
+id = "base-only"
+enabled = true
+transport = "stdio"
+command = "base-tool"
+
+[[mcp.servers]]
+id = "shared"
+enabled = true
+transport = "http"
+url = "https://base.example.com/mcp"
+`
+
+func writeExtendsFile(t *testing.T, path string, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		t.Fatalf("mkdir %s: %v", filepath.Dir(path), err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("write %s: %v", path, err)
+	}
+}
+
+func stubResolveExtends(t *testing.T, dir string, gotSource *string) {
+	t.Helper()
+	original := resolveExtendsFunc
+	resolveExtendsFunc = func(source string, checksum string) (string, error) {
+		if gotSource != nil {
+			*gotSource = source
+		}
+		return dir, nil
+	}
+	t.Cleanup(func() { resolveExtendsFunc = original })
+}
+
+func setupExtendsRepo(t *testing.T, localConfig string) (string, string) {
+	t.Helper()
+	root := t.TempDir()
+	paths := DefaultPaths(root)
+	writeExtendsFile(t, paths.ConfigPath, localConfig)
+	writeExtendsFile(t, paths.EnvPath, "")
+	writeExtendsFile(t, filepath.Join(paths.InstructionsDir, "10_local.md"), "local rules")
+	writeExtendsFile(t, filepath.Join(paths.InstructionsDir, "00_shared.md"), "local override")
+	writeExtendsFile(t, filepath.Join(paths.SkillsDir, "review", "SKILL.md"), "---\nname: review\ndescription: local review\n---\n\nLocal.")
+	writeExtendsFile(t, paths.CommandsAllow, "git status\nmake test\n")
+
+	base := t.TempDir()
+	writeExtendsFile(t, filepath.Join(base, "config.toml"), extendsBaseConfig)
+	writeExtendsFile(t, filepath.Join(base, "instructions", "00_shared.md"), "base shared")
+	writeExtendsFile(t, filepath.Join(base, "instructions", "05_org.md"), "org rules")
+	writeExtendsFile(t, filepath.Join(base, "skills", "review", "SKILL.md"), "---\nname: review\ndescription: base review\n---\n\nBase.")
+	writeExtendsFile(t, filepath.Join(base, "skills", "deploy", "SKILL.md"), "---\nname: deploy\ndescription: base deploy\n---\n\nDeploy.")
+	writeExtendsFile(t, filepath.Join(base, "commands.allow"), "# org\ngit status\ngit diff\n")
+	return root, base
+}
+
+func TestLoadProjectConfig_ExtendsLayersLocalOverBase(t *testing.T) {
+	root, base := setupExtendsRepo(t, extendsLocalConfig)
+	var gotSource string
+	stubResolveExtends(t, base, &gotSource)
+
+	project, err := LoadProjectConfig(root)
+	if err != nil {
+		t.Fatalf("LoadProjectConfig error: %v", err)
+	}
+	if gotSource != "github.com/org/agent-layer-base@v1" {
+		t.Fatalf("resolved source = %q", gotSource)
+	}
+
+	cfg := project.Config
+	if cfg.Approvals.Mode != ApprovalModeCommands {
+		t.Fatalf("approvals.mode = %q, want local override", cfg.Approvals.Mode)
+	}
+	if IsAgentEnabled(cfg.Agents.Claude.Enabled) {
+		t.Fatalf("expected local agents.claude.enabled=false to win")
+	}
+	if cfg.Agents.Claude.Model != "opus" {
+		t.Fatalf("expected base agents.claude.model to be inherited, got %q", cfg.Agents.Claude.Model)
+	}
+	if !IsAgentEnabled(cfg.Agents.Codex.Enabled) {
+		t.Fatalf("expected base agents.codex.enabled to be inherited")
+	}
+
+	var ids []string
+	for _, server := range cfg.MCP.Servers {
+		ids = append(ids, server.ID)
+	}
+	if strings.Join(ids, ",") != "base-only,shared,local-only" {
+		t.Fatalf("mcp server order = %v", ids)
+	}
+	if cfg.MCP.Servers[1].URL != "https://local.example.com/mcp" {
+		t.Fatalf("expected local shared server to replace base entry, got %q", cfg.MCP.Servers[1].URL)
+	}
+
+	var names []string
+	for _, file := range project.Instructions {
+		names = append(names, file.Name+"="+file.Content)
+	}
+	if strings.Join(names, "|") != "00_shared.md=local override|05_org.md=org rules|10_local.md=local rules" {
+		t.Fatalf("instructions = %v", names)
+	}
+
+	if len(project.Skills) != 2 || project.Skills[0].Name != "deploy" || project.Skills[1].Description != "local review" {
+		t.Fatalf("skills = %+v", project.Skills)
+	}
+	if got := strings.Join(project.CommandsAllow, ","); got != "git status,git diff,make test" {
+		t.Fatalf("commands allow = %q", got)
+	}
+}
+
+func TestLoadProjectConfig_ExtendsOptionalBaseParts(t *testing.T) {
+	root, _ := setupExtendsRepo(t, extendsLocalConfig)
+	empty := t.TempDir()
+	writeExtendsFile(t, filepath.Join(empty, "config.toml"), extendsBaseConfig)
+	stubResolveExtends(t, empty, nil)
+
+	project, err := LoadProjectConfig(root)
+	if err != nil {
+		t.Fatalf("LoadProjectConfig error: %v", err)
+	}
+	if len(project.Instructions) != 2 || len(project.Skills) != 1 || len(project.CommandsAllow) != 2 {
+		t.Fatalf("expected local-only content, got %d instructions, %d skills, %d commands",
+			len(project.Instructions), len(project.Skills), len(project.CommandsAllow))
+	}
+}
+
+func TestLoadProjectConfig_ExtendsResolveError(t *testing.T) {
+	root, _ := setupExtendsRepo(t, extendsLocalConfig)
+	original := resolveExtendsFunc
+	resolveExtendsFunc = func(string, string) (string, error) {
+		return "", errors.New("offline")
+	}
+	t.Cleanup(func() { resolveExtendsFunc = original })
+
+	if _, err := LoadProjectConfig(root); err == nil || !strings.Contains(err.Error(), "offline") {
+		t.Fatalf("expected resolve error, got %v", err)
+	}
+}
+
+func TestLoadProjectConfig_ExtendsUsesLockedChecksum(t *testing.T) {
+	root, base := setupExtendsRepo(t, extendsLocalConfig)
+	lock := "version = 1\n\n[extends]\nsource = \"github.com/org/agent-layer-base@v1\"\nchecksum = \"sha256:" + strings.Repeat("a", 64) + "\"\n"
+	writeExtendsFile(t, filepath.Join(root, ".agent-layer", "al.lock"), lock)
+
+	var gotChecksum string
+	original := resolveExtendsFunc
+	resolveExtendsFunc = func(_ string, checksum string) (string, error) {
+		gotChecksum = checksum
+		return base, nil
+	}
+	t.Cleanup(func() { resolveExtendsFunc = original })
+
+	project, err := LoadProjectConfig(root)
+	if err != nil {
+		t.Fatalf("LoadProjectConfig error: %v", err)
+	}
+	if gotChecksum != "sha256:"+strings.Repeat("a", 64) {
+		t.Fatalf("resolve checksum = %q, want locked checksum", gotChecksum)
+	}
+	if project.ExtendsDir != base {
+		t.Fatalf("ExtendsDir = %q, want %q", project.ExtendsDir, base)
+	}
+
+	resolveExtendsFunc = func(string, string) (string, error) { return "", errors.New("checksum mismatch") }
+	if _, err := LoadProjectConfig(root); err == nil || !strings.Contains(err.Error(), "al.lock") {
+		t.Fatalf("expected lock hint in resolve error, got %v", err)
+	}
+
+	// A lock recorded for another source does not pin the new one.
+	writeExtendsFile(t, filepath.Join(root, ".agent-layer", "al.lock"), strings.Replace(lock, "@v1", "@v0", 1))
+	resolveExtendsFunc = func(_ string, checksum string) (string, error) {
+		gotChecksum = checksum
+		return base, nil
+	}
+	if _, err := LoadProjectConfig(root); err != nil || gotChecksum != "" {
+		t.Fatalf("expected unpinned resolve, got checksum %q (%v)", gotChecksum, err)
+	}
+}
+
+func TestLoadProjectConfig_ExtendsRejectsNestedExtends(t *testing.T) {
+	root, base := setupExtendsRepo(t, extendsLocalConfig)
+	writeExtendsFile(t, filepath.Join(base, "config.toml"), "extends = \"github.com/org/other@v1\"\n"+extendsBaseConfig)
+	stubResolveExtends(t, base, nil)
+
+	_, err := LoadProjectConfig(root)
+	if !errors.Is(err, ErrConfigValidation) || !strings.Contains(err.Error(), "nested extends") {
+		t.Fatalf("expected nested extends validation error, got %v", err)
+	}
+}
+
+func TestLoadProjectConfig_ExtendsInvalidSource(t *testing.T) {
+	root, base := setupExtendsRepo(t, strings.Replace(extendsLocalConfig, "github.com/org/agent-layer-base@v1", "not-a-source", 1))
+	stubResolveExtends(t, base, nil)
+
+	_, err := LoadProjectConfig(root)
+	if !errors.Is(err, ErrConfigValidation) || !strings.Contains(err.Error(), "invalid extends source") {
+		t.Fatalf("expected invalid source error, got %v", err)
+	}
+}
+
+func TestValidateExtends(t *testing.T) {
+	validChecksum := "sha256:" + strings.Repeat("a", 64)
+	tests := []struct {
+		name     string
+		source   string
+		checksum string
+		wantErr  string
+	}{
+		{name: "unset"},
+		{name: "source only", source: "github.com/org/base@v1"},
+		{name: "pinned", source: "github.com/org/base@v1", checksum: validChecksum},
+		{name: "checksum without source", checksum: validChecksum, wantErr: "extends_checksum requires extends"},
+		{name: "bad checksum", source: "github.com/org/base@v1", checksum: "md5:abc", wantErr: "invalid extends_checksum"},
+		{name: "bad source", source: "base@v1", wantErr: "invalid extends source"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateExtends("config.toml", tt.source, tt.checksum)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
@@ -1,6 +1,14 @@
 package config
 
-import "github.com/conn-castle/agent-layer/internal/messages"
+import (
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/conn-castle/agent-layer/internal/i18n"
+	"github.com/conn-castle/agent-layer/internal/messages"
+)
 
 // FieldType classifies the kind of value a config field accepts.
 type FieldType string
@@ -197,11 +205,7 @@ func FieldOptionValues(key string) []string {
 	if !ok || len(f.Options) == 0 {
 		return nil
 	}
-	values := make([]string, len(f.Options))
-	for i, opt := range f.Options {
-		values[i] = opt.Value
-	}
-	return values
+	return fieldOptionValues(f)
 }
 
 // copyFieldDef returns a deep copy of a FieldDef so callers cannot mutate the registry.
@@ -213,3 +217,85 @@ func copyFieldDef(f FieldDef) FieldDef {
 	}
 	return f
 }
+
+// ValidateFieldValue checks value against the field's type and options.
+// Integers are accepted as int, int64, or integral float64 so values decoded
+// from JSON migration manifests and TOML config validate the same way.
+func ValidateFieldValue(field FieldDef, value any) error {
+	switch field.Type {
+	case FieldBool:
+		if _, ok := value.(bool); !ok {
+			return i18n.Errorf(messages.ConfigFieldValueBoolFmt, field.Key, value)
+		}
+	case FieldEnum:
+		text, ok := value.(string)
+		if !ok {
+			return i18n.Errorf(messages.ConfigFieldValueStringFmt, field.Key, value)
+		}
+		if strings.TrimSpace(text) == "" {
+			return i18n.Errorf(messages.ConfigFieldValueEmptyFmt, field.Key)
+		}
+		if !field.AllowCustom && !slices.Contains(fieldOptionValues(field), text) {
+			return i18n.Errorf(messages.ConfigFieldValueEnumFmt, field.Key, strings.Join(fieldOptionValues(field), ", "), text)
+		}
+	case FieldFreetext:
+		text, ok := value.(string)
+		if !ok {
+			return i18n.Errorf(messages.ConfigFieldValueStringFmt, field.Key, value)
+		}
+		if field.Required && strings.TrimSpace(text) == "" {
+			return i18n.Errorf(messages.ConfigFieldValueEmptyFmt, field.Key)
+		}
+	case FieldPositiveInt:
+		if !isPositiveInt(value) {
+			return i18n.Errorf(messages.ConfigFieldValuePositiveIntFmt, field.Key, value)
+		}
+	}
+	return nil
+}
+
+// ParseFieldValue converts typed-in text to the field's value type and
+// validates it.
+func ParseFieldValue(field FieldDef, input string) (any, error) {
+	input = strings.TrimSpace(input)
+	var value any = input
+	switch field.Type {
+	case FieldBool:
+		parsed, err := strconv.ParseBool(input)
+		if err != nil {
+			return nil, i18n.Errorf(messages.ConfigFieldValueBoolFmt, field.Key, input)
+		}
+		value = parsed
+	case FieldPositiveInt:
+		parsed, err := strconv.ParseInt(input, 10, 64)
+		if err != nil {
+			return nil, i18n.Errorf(messages.ConfigFieldValuePositiveIntFmt, field.Key, input)
+		}
+		value = parsed
+	}
+	if err := ValidateFieldValue(field, value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+func fieldOptionValues(field FieldDef) []string {
+	values := make([]string, len(field.Options))
+	for i, opt := range field.Options {
+		values[i] = opt.Value
+	}
+	return values
+}
+
+func isPositiveInt(value any) bool {
+	switch v := value.(type) {
+	case int:
+		return v > 0
+	case int64:
+		return v > 0
+	case float64:
+		return v > 0 && v == math.Trunc(v) && v <= math.MaxInt64
+	default:
+		return false
+	}
+}
@@ -304,3 +304,62 @@ func TestFieldsRegistryConsistency(t *testing.T) {
 		}
 	}
 }
+
+func TestValidateFieldValue(t *testing.T) {
+	strictEnum := FieldDef{Key: "k.enum", Type: FieldEnum, Options: fieldOptions("a", "b")}
+	customEnum := FieldDef{Key: "k.model", Type: FieldEnum, Options: fieldOptions("a"), AllowCustom: true}
+	cases := []struct {
+		name    string
+		field   FieldDef
+		value   any
+		wantErr string
+	}{
+		{name: "bool", field: FieldDef{Key: "k.bool", Type: FieldBool}, value: true},
+		{name: "bool wrong type", field: FieldDef{Key: "k.bool", Type: FieldBool}, value: "yes", wantErr: "k.bool must be true or false"},
+		{name: "enum option", field: strictEnum, value: "b"},
+		{name: "enum unknown", field: strictEnum, value: "c", wantErr: "k.enum must be one of a, b"},
+		{name: "enum custom", field: customEnum, value: "my-model"},
+		{name: "enum custom empty", field: customEnum, value: " ", wantErr: "must not be empty"},
+		{name: "enum wrong type", field: strictEnum, value: 1.0, wantErr: "must be a string"},
+		{name: "freetext", field: FieldDef{Key: "k.text", Type: FieldFreetext}, value: ""},
+		{name: "freetext required", field: FieldDef{Key: "k.text", Type: FieldFreetext, Required: true}, value: "", wantErr: "must not be empty"},
+		{name: "positive int", field: FieldDef{Key: "k.n", Type: FieldPositiveInt}, value: int64(2)},
+		{name: "positive int from json", field: FieldDef{Key: "k.n", Type: FieldPositiveInt}, value: float64(3)},
+		{name: "positive int fractional", field: FieldDef{Key: "k.n", Type: FieldPositiveInt}, value: 1.5, wantErr: "must be a positive integer"},
+		{name: "positive int zero", field: FieldDef{Key: "k.n", Type: FieldPositiveInt}, value: 0, wantErr: "must be a positive integer"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateFieldValue(tc.field, tc.value)
+			if tc.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Fatalf("error = %v, want %q", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestParseFieldValue(t *testing.T) {
+	value, err := ParseFieldValue(FieldDef{Key: "k.n", Type: FieldPositiveInt}, " 4\n")
+	if err != nil || value != int64(4) {
+		t.Fatalf("positive int = %v (%T), err %v", value, value, err)
+	}
+	value, err = ParseFieldValue(FieldDef{Key: "k.bool", Type: FieldBool}, "false")
+	if err != nil || value != false {
+		t.Fatalf("bool = %v, err %v", value, err)
+	}
+	if _, err := ParseFieldValue(FieldDef{Key: "k.n", Type: FieldPositiveInt}, "many"); err == nil || !strings.Contains(err.Error(), "must be a positive integer") {
+		t.Fatalf("expected int parse error, got %v", err)
+	}
+	if _, err := ParseFieldValue(FieldDef{Key: "k.bool", Type: FieldBool}, "maybe"); err == nil {
+		t.Fatal("expected bool parse error")
+	}
+	if _, err := ParseFieldValue(FieldDef{Key: "k.n", Type: FieldPositiveInt}, "-1"); err == nil {
+		t.Fatal("expected validation error for negative int")
+	}
+}
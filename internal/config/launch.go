@@ -0,0 +1,45 @@
+package config
+
+import (
+	"strings"
+
+	"github.com/conn-castle/agent-layer/internal/i18n"
+	"github.com/conn-castle/agent-layer/internal/messages"
+)
+
+// Launch auto-sync modes.
+const (
+	// LaunchAutoSyncAlways syncs before every launch.
+	LaunchAutoSyncAlways = "always"
+	// LaunchAutoSyncIfStale syncs only when the sources changed since the last
+	// sync.
+	LaunchAutoSyncIfStale = "if-stale"
+	// LaunchAutoSyncNever never syncs before a launch and warns when the
+	// outputs are stale.
+	LaunchAutoSyncNever = "never"
+)
+
+// LaunchConfig controls what `al <client>` does before starting a client.
+type LaunchConfig struct {
+	// AutoSync is "always" (default), "if-stale", or "never".
+	AutoSync string `toml:"auto_sync"`
+}
+
+// AutoSyncMode returns the normalized auto-sync mode, defaulting to always.
+func (l LaunchConfig) AutoSyncMode() string {
+	mode := strings.ToLower(strings.TrimSpace(l.AutoSync))
+	if mode == "" {
+		return LaunchAutoSyncAlways
+	}
+	return mode
+}
+
+// validateLaunch checks launch.auto_sync.
+func validateLaunch(path string, cfg LaunchConfig) error {
+	switch cfg.AutoSyncMode() {
+	case LaunchAutoSyncAlways, LaunchAutoSyncIfStale, LaunchAutoSyncNever:
+		return nil
+	default:
+		return i18n.Errorf(messages.ConfigLaunchAutoSyncInvalidFmt, path, cfg.AutoSync)
+	}
+}
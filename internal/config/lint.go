@@ -0,0 +1,306 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/pelletier/go-toml/v2"
+
+	"github.com/conn-castle/agent-layer/internal/i18n"
+	"github.com/conn-castle/agent-layer/internal/layerdir"
+	"github.com/conn-castle/agent-layer/internal/lockfile"
+	"github.com/conn-castle/agent-layer/internal/messages"
+)
+
+// LintKind classifies a config lint finding.
+type LintKind string
+
+const (
+	// LintSyntax reports TOML that cannot be parsed at all.
+	LintSyntax LintKind = "syntax"
+	// LintUnknownKey reports a key the config schema does not define.
+	LintUnknownKey LintKind = "unknown_key"
+	// LintTypeError reports a value of the wrong TOML type.
+	LintTypeError LintKind = "type_error"
+	// LintDeprecatedKey reports a legacy key that has a replacement or was retired.
+	LintDeprecatedKey LintKind = "deprecated_key"
+	// LintInvalidValue reports a value that parses but fails validation.
+	LintInvalidValue LintKind = "invalid_value"
+)
+
+// LintIssue is a single problem found by LintConfig.
+type LintIssue struct {
+	Kind LintKind
+	// Key is the dotted config path the issue is about; empty when the
+	// issue is not tied to a single key.
+	Key     string
+	Message string
+}
+
+// LintConfig validates .agent-layer/config.toml under root strictly and
+// returns every problem found instead of stopping at the first one.
+// The error is non-nil only when the config file cannot be read.
+func LintConfig(root string) ([]LintIssue, error) {
+	path := DefaultPaths(root).ConfigPath
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, i18n.Errorf(messages.ConfigMissingFileFmt, path, err)
+	}
+	return lintConfigData(root, data, path), nil
+}
+
+// lintConfigData runs the lint passes over data. Keys reported by the
+// structural passes are removed before semantic validation so a single
+// mistake is not reported twice (for example a mistyped required field
+// showing up again as missing).
+func lintConfigData(root string, data []byte, source string) []LintIssue {
+	var raw map[string]any
+	if err := toml.Unmarshal(data, &raw); err != nil {
+		return []LintIssue{{Kind: LintSyntax, Message: i18n.Errorf(messages.ConfigInvalidConfigFmt, source, err).Error()}}
+	}
+
+	var issues []LintIssue
+	for _, deprecation := range takeDeprecations(raw) {
+		issues = append(issues, LintIssue{Kind: LintDeprecatedKey, Key: deprecation.Key, Message: deprecation.Message(source)})
+	}
+
+	var structural []LintIssue
+	lintRawTable(raw, reflect.TypeOf(Config{}), "", source, &structural)
+	sort.SliceStable(structural, func(i, j int) bool { return structural[i].Key < structural[j].Key })
+	issues = append(issues, structural...)
+
+	merged, err := lintMergedConfig(root, raw, source)
+	if err != nil {
+		return append(issues, LintIssue{Kind: LintInvalidValue, Message: err.Error()})
+	}
+	var cfg Config
+	if err := toml.Unmarshal(merged, &cfg); err != nil {
+		return append(issues, LintIssue{Kind: LintInvalidValue, Message: i18n.Errorf(messages.ConfigInvalidConfigFmt, source, err).Error()})
+	}
+	for _, err := range cfg.ValidationErrors(source) {
+		if lintIssueMentionsKey(issues, err.Error()) {
+			continue
+		}
+		issues = append(issues, LintIssue{Kind: LintInvalidValue, Message: err.Error()})
+	}
+	return issues
+}
+
+// lintMergedConfig returns the pruned local config as TOML, overlaid on the
+// extends base when the config sets one so required keys supplied by the
+// base are not reported as missing.
+func lintMergedConfig(root string, raw map[string]any, source string) ([]byte, error) {
+	extends, _ := raw[extendsKey].(string)
+	if extends == "" {
+		return toml.Marshal(raw)
+	}
+	checksum, _ := raw[extendsChecksumKey].(string)
+	if err := validateExtends(source, extends, checksum); err != nil {
+		return nil, err
+	}
+	if checksum == "" {
+		locked, err := lockedExtendsChecksum(layerdir.FS(root), root, lockfile.Path(root), extends)
+		if err != nil {
+			return nil, err
+		}
+		checksum = locked
+	}
+	dir, err := resolveExtendsFunc(extends, checksum)
+	if err != nil {
+		return nil, err
+	}
+	layer := &baseLayer{dir: dir, fsys: os.DirFS(dir)}
+	return layer.mergeConfig(raw, source)
+}
+
+// lintRawTable compares a decoded TOML table against the struct type t,
+// reporting unknown keys and type mismatches. Offending keys are deleted from
+// table so later passes see only values that decode cleanly.
+func lintRawTable(table map[string]any, t reflect.Type, path string, source string, issues *[]LintIssue) {
+	fields := tomlFields(t)
+	for key, value := range table {
+		keyPath := joinLintPath(path, key)
+		fieldType, ok := fields[key]
+		if !ok {
+			message := i18n.Sprintf(messages.ConfigLintUnknownKeyFmt, source, keyPath)
+			if suggestion := suggestConfigKey(key, fields); suggestion != "" {
+				message = i18n.Sprintf(messages.ConfigLintUnknownKeySuggestFmt, source, keyPath, joinLintPath(path, suggestion))
+			}
+			*issues = append(*issues, LintIssue{Kind: LintUnknownKey, Key: keyPath, Message: message})
+			delete(table, key)
+			continue
+		}
+		if !lintRawValue(value, fieldType, keyPath, source, issues) {
+			delete(table, key)
+		}
+	}
+}
+
+// lintRawValue checks value against t, recursing into tables and arrays, and
+// reports whether value can stay in its parent. Every problem is appended to
+// issues; a mistyped array element drops the whole array because the array
+// cannot decode without it.
+func lintRawValue(value any, t reflect.Type, path string, source string, issues *[]LintIssue) bool {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	ok := true
+	switch t.Kind() {
+	case reflect.Bool:
+		_, ok = value.(bool)
+	case reflect.String:
+		_, ok = value.(string)
+	case reflect.Int, reflect.Int64:
+		_, ok = value.(int64)
+	case reflect.Struct:
+		var table map[string]any
+		if table, ok = value.(map[string]any); ok {
+			lintRawTable(table, t, path, source, issues)
+		}
+	case reflect.Map:
+		var table map[string]any
+		if table, ok = value.(map[string]any); ok {
+			for key, item := range table {
+				if !lintRawValue(item, t.Elem(), joinLintPath(path, key), source, issues) {
+					delete(table, key)
+				}
+			}
+		}
+	case reflect.Slice:
+		var list []any
+		if list, ok = value.([]any); ok {
+			for i, item := range list {
+				if !lintRawValue(item, t.Elem(), fmt.Sprintf("%s[%d]", path, i), source, issues) {
+					return false
+				}
+			}
+		}
+	}
+	if !ok {
+		*issues = append(*issues, LintIssue{
+			Kind:    LintTypeError,
+			Key:     path,
+			Message: i18n.Sprintf(messages.ConfigLintTypeErrorFmt, source, path, describeConfigType(t), describeRawValue(value)),
+		})
+	}
+	return ok
+}
+
+// tomlFields maps the toml tag names of struct t to their field types.
+func tomlFields(t reflect.Type) map[string]reflect.Type {
+	fields := make(map[string]reflect.Type, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name := strings.Split(field.Tag.Get("toml"), ",")[0]
+		if name == "" || name == "-" {
+			continue
+		}
+		fields[name] = field.Type
+	}
+	return fields
+}
+
+// suggestConfigKey returns the known key closest to key, or "" when none is
+// close enough to be a plausible typo. Case and hyphen-for-underscore
+// differences are free; otherwise up to two edits are allowed (one for keys
+// of three characters or fewer).
+func suggestConfigKey(key string, fields map[string]reflect.Type) string {
+	normalized := strings.ReplaceAll(strings.ToLower(key), "-", "_")
+	maxDistance := 2
+	if len(normalized) <= 3 {
+		maxDistance = 1
+	}
+	best, bestDistance := "", maxDistance+1
+	for candidate := range fields {
+		distance := levenshtein(normalized, candidate)
+		if distance < bestDistance || (distance == bestDistance && candidate < best) {
+			best, bestDistance = candidate, distance
+		}
+	}
+	return best
+}
+
+// levenshtein returns the edit distance between a and b.
+func levenshtein(a string, b string) int {
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur := make([]int, len(b)+1)
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev = cur
+	}
+	return prev[len(b)]
+}
+
+// describeConfigType renders the TOML type a Go field expects.
+func describeConfigType(t reflect.Type) string {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return "a boolean"
+	case reflect.String:
+		return "a string"
+	case reflect.Int, reflect.Int64:
+		return "an integer"
+	case reflect.Struct, reflect.Map:
+		return "a table"
+	case reflect.Slice:
+		return "an array of " + strings.TrimPrefix(strings.TrimPrefix(describeConfigType(t.Elem()), "an "), "a ") + "s"
+	default:
+		return t.String()
+	}
+}
+
+// describeRawValue renders the TOML type of a decoded value.
+func describeRawValue(value any) string {
+	switch value.(type) {
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case int64:
+		return "integer"
+	case float64:
+		return "float"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "table"
+	default:
+		return "date/time"
+	}
+}
+
+// joinLintPath appends key to a dotted config path.
+func joinLintPath(path string, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// lintIssueMentionsKey reports whether message is about a key an earlier
+// pass already reported.
+func lintIssueMentionsKey(issues []LintIssue, message string) bool {
+	return slices.ContainsFunc(issues, func(issue LintIssue) bool {
+		return issue.Key != "" && strings.Contains(message, issue.Key)
+	})
+}
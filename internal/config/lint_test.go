@@ -0,0 +1,185 @@
+package config
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const lintValidConfig = `
+[approvals]
+mode = "all"
+
+[agents.antigravity]
+enabled = true
+
+[agents.claude]
+enabled = true
+
+[agents.claude_vscode]
+enabled = true
+
+[agents.codex]
+enabled = true
+
+[agents.vscode]
+enabled = true
+
+[agents.copilot_cli]
+enabled = false
+`
+
+func lintIssueKinds(issues []LintIssue) map[LintKind][]string {
+	kinds := make(map[LintKind][]string)
+	for _, issue := range issues {
+		kinds[issue.Kind] = append(kinds[issue.Kind], issue.Message)
+	}
+	return kinds
+}
+
+func TestLintConfig_Clean(t *testing.T) {
+	root := t.TempDir()
+	writeExtendsFile(t, DefaultPaths(root).ConfigPath, lintValidConfig)
+	issues, err := LintConfig(root)
+	if err != nil {
+		t.Fatalf("LintConfig error: %v", err)
+	}
+	if len(issues) != 0 {
+		t.Fatalf("expected no issues, got %#v", issues)
+	}
+}
+
+func TestLintConfig_ReportsAllProblems(t *testing.T) {
+	root := t.TempDir()
+	config := strings.Replace(lintValidConfig, `mode = "all"`, `mode = "everything"`, 1)
+	config = strings.Replace(config, "[agents.claude]\nenabled = true", "[agents.claude]\nenabled = \"yes\"\nmodle = \"opus\"", 1)
+	config += `
+[agents.gemini]
+enabled = true
+
+[warnings]
+noise_mode = "silent"
+instruction_token_threshold = 0
+
+[[mcp.servers]]
+id = "tools"
+enabled = true
+transport = "websocket"
+clients = ["claude", "emacs"]
+`
+	writeExtendsFile(t, DefaultPaths(root).ConfigPath, config)
+
+	issues, err := LintConfig(root)
+	if err != nil {
+		t.Fatalf("LintConfig error: %v", err)
+	}
+	kinds := lintIssueKinds(issues)
+	want := map[LintKind][]string{
+		LintDeprecatedKey: {"agents.gemini.enabled was removed in 0.10.2; use agents.antigravity.enabled"},
+		LintTypeError:     {"agents.claude.enabled must be a boolean, got string"},
+		LintUnknownKey:    {"agents.claude.modle is not a recognized config key (did you mean agents.claude.model?)"},
+		LintInvalidValue: {
+			"approvals.mode must be one of",
+			"warnings.noise_mode \"silent\" is invalid",
+			"warnings.instruction_token_threshold must be greater than zero",
+			"mcp.servers[0].transport must be http or stdio",
+			"mcp.servers[0].clients contains invalid client \"emacs\"",
+		},
+	}
+	for kind, substrings := range want {
+		if len(kinds[kind]) != len(substrings) {
+			t.Fatalf("expected %d %s issues, got %q", len(substrings), kind, kinds[kind])
+		}
+		joined := strings.Join(kinds[kind], "\n")
+		for _, substring := range substrings {
+			if !strings.Contains(joined, substring) {
+				t.Fatalf("expected %s issue containing %q, got:\n%s", kind, substring, joined)
+			}
+		}
+	}
+	// The mistyped required field must not be reported a second time as missing.
+	for _, message := range kinds[LintInvalidValue] {
+		if strings.Contains(message, "agents.claude.enabled is required") {
+			t.Fatalf("mistyped field reported twice: %q", message)
+		}
+	}
+}
+
+func TestLintConfig_SyntaxError(t *testing.T) {
+	root := t.TempDir()
+	writeExtendsFile(t, DefaultPaths(root).ConfigPath, "[approvals\nmode = ")
+	issues, err := LintConfig(root)
+	if err != nil {
+		t.Fatalf("LintConfig error: %v", err)
+	}
+	if len(issues) != 1 || issues[0].Kind != LintSyntax {
+		t.Fatalf("expected a single syntax issue, got %#v", issues)
+	}
+}
+
+func TestLintConfig_MissingFile(t *testing.T) {
+	if _, err := LintConfig(t.TempDir()); err == nil || !strings.Contains(err.Error(), "missing config file") {
+		t.Fatalf("expected missing config error, got %v", err)
+	}
+}
+
+func TestLintConfig_ArrayElementTypeError(t *testing.T) {
+	root := t.TempDir()
+	writeExtendsFile(t, DefaultPaths(root).ConfigPath, lintValidConfig+`
+[[mcp.servers]]
+id = "tools"
+enabled = true
+transport = "stdio"
+command = "tool"
+args = ["--flag", 3]
+`)
+	issues, err := LintConfig(root)
+	if err != nil {
+		t.Fatalf("LintConfig error: %v", err)
+	}
+	if len(issues) != 1 || issues[0].Key != "mcp.servers[0].args[1]" || !strings.Contains(issues[0].Message, "must be a string, got integer") {
+		t.Fatalf("expected one element type error, got %#v", issues)
+	}
+}
+
+func TestLintConfig_ExtendsSuppliesRequiredKeys(t *testing.T) {
+	root, base := setupExtendsRepo(t, extendsLocalConfig+"\n[agents.codex]\nreasoning_efort = \"high\"\n")
+	stubResolveExtends(t, base, nil)
+
+	issues, err := LintConfig(root)
+	if err != nil {
+		t.Fatalf("LintConfig error: %v", err)
+	}
+	if len(issues) != 1 || issues[0].Kind != LintUnknownKey || !strings.Contains(issues[0].Message, "did you mean agents.codex.reasoning_effort?") {
+		t.Fatalf("expected only the unknown key, got %#v", issues)
+	}
+}
+
+func TestLintConfig_DeprecatedModelPassthrough(t *testing.T) {
+	root := t.TempDir()
+	writeExtendsFile(t, filepath.Join(root, ".agent-layer", "config.toml"), strings.Replace(lintValidConfig,
+		"[agents.antigravity]\nenabled = true", "[agents.antigravity]\nenabled = true\n\n[agents.antigravity.agent_specific]\nmodel = \"x\"", 1))
+	issues, err := LintConfig(root)
+	if err != nil {
+		t.Fatalf("LintConfig error: %v", err)
+	}
+	if len(issues) != 1 || issues[0].Kind != LintDeprecatedKey || !strings.Contains(issues[0].Message, "use agents.antigravity.model instead") {
+		t.Fatalf("expected one deprecation, got %#v", issues)
+	}
+}
+
+func TestSuggestConfigKey(t *testing.T) {
+	fields := tomlFields(reflect.TypeOf(AgentConfig{}))
+	tests := map[string]string{
+		"Model":            "model",
+		"reasoning-effort": "reasoning_effort",
+		"enabeld":          "enabled",
+		"colour":           "",
+	}
+	for key, want := range tests {
+		if got := suggestConfigKey(key, fields); got != want {
+			t.Fatalf("suggestConfigKey(%q) = %q, want %q", key, got, want)
+		}
+	}
+}
@@ -9,7 +9,10 @@ import (
 
 	"github.com/pelletier/go-toml/v2"
 
+	"github.com/conn-castle/agent-layer/internal/envcrypt"
 	"github.com/conn-castle/agent-layer/internal/envfile"
+	"github.com/conn-castle/agent-layer/internal/i18n"
+	"github.com/conn-castle/agent-layer/internal/layerdir"
 	"github.com/conn-castle/agent-layer/internal/messages"
 	"github.com/conn-castle/agent-layer/internal/templates"
 )
@@ -30,30 +33,30 @@ var ErrConfigNeedsUpgrade = errors.New("config requires migration")
 
 // LoadProjectConfig reads and validates the full Agent Layer config from disk.
 func LoadProjectConfig(root string) (*ProjectConfig, error) {
-	return LoadProjectConfigFS(os.DirFS(root), root)
+	return LoadProjectConfigFS(layerdir.FS(root), root)
 }
 
 // LoadTemplateConfig returns the embedded default config template as a validated Config.
 func LoadTemplateConfig() (*Config, error) {
 	data, err := templates.Read("config.toml")
 	if err != nil {
-		return nil, fmt.Errorf(messages.ConfigFailedReadTemplateFmt, err)
+		return nil, i18n.Errorf(messages.ConfigFailedReadTemplateFmt, err)
 	}
 	return ParseConfig(data, "template config.toml")
 }
 
-// LoadEnv reads .agent-layer/.env into a key-value map.
+// LoadEnv reads .agent-layer/.env into a key-value map, decrypting ENC[age:...] values.
 func LoadEnv(path string) (map[string]string, error) {
 	data, err := os.ReadFile(path)
 	if err != nil {
-		return nil, fmt.Errorf(messages.ConfigMissingEnvFileFmt, path, err)
+		return nil, i18n.Errorf(messages.ConfigMissingEnvFileFmt, path, err)
 	}
 
 	env, err := envfile.Parse(string(data))
 	if err != nil {
-		return nil, fmt.Errorf(messages.ConfigInvalidEnvFileFmt, path, err)
+		return nil, i18n.Errorf(messages.ConfigInvalidEnvFileFmt, path, err)
 	}
-	return filterAgentLayerEnv(env), nil
+	return envcrypt.DecryptEnv(filterAgentLayerEnv(env))
 }
 
 // filterAgentLayerEnv restricts .env values to the AL_ namespace.
@@ -75,7 +78,7 @@ func filterAgentLayerEnv(env map[string]string) map[string]string {
 func ParseConfig(data []byte, source string) (*Config, error) {
 	var cfg Config
 	if err := toml.Unmarshal(data, &cfg); err != nil {
-		return nil, fmt.Errorf(messages.ConfigInvalidConfigFmt, source, err)
+		return nil, i18n.Errorf(messages.ConfigInvalidConfigFmt, source, err)
 	}
 	if err := decodeStrict(data); err != nil {
 		if HasLegacyGeminiConfig(data) {
@@ -177,13 +180,15 @@ func decodeStrict(data []byte) error {
 // ParseConfigLenient parses config TOML data without validation.
 // Returns an error only on TOML syntax errors. Missing or invalid fields
 // are not checked, making this suitable for repair tools (wizard, doctor)
-// that need to read partially valid configs.
+// that need to read partially valid configs. Legacy keys from the
+// deprecation table are recorded in Config.Deprecated.
 func ParseConfigLenient(data []byte, source string) (*Config, error) {
 	var cfg Config
 	if err := toml.Unmarshal(data, &cfg); err != nil {
-		return nil, fmt.Errorf(messages.ConfigInvalidConfigFmt, source, err)
+		return nil, i18n.Errorf(messages.ConfigInvalidConfigFmt, source, err)
 	}
 	applyLegacyConfigAliases(data, &cfg)
+	cfg.Deprecated = FindDeprecations(data)
 	return &cfg, nil
 }
 
@@ -227,7 +232,7 @@ func applyLegacyConfigAliases(data []byte, cfg *Config) {
 func LoadConfigLenient(path string) (*Config, error) {
 	data, err := os.ReadFile(path)
 	if err != nil {
-		return nil, fmt.Errorf(messages.ConfigMissingFileFmt, path, err)
+		return nil, i18n.Errorf(messages.ConfigMissingFileFmt, path, err)
 	}
 	return ParseConfigLenient(data, path)
 }
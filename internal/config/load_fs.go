@@ -3,29 +3,41 @@ package config
 import (
 	"bufio"
 	"bytes"
-	"fmt"
 	"io/fs"
 	pathpkg "path"
 	"path/filepath"
 	"sort"
 	"strings"
 
+	"github.com/conn-castle/agent-layer/internal/envcrypt"
 	"github.com/conn-castle/agent-layer/internal/envfile"
+	"github.com/conn-castle/agent-layer/internal/errcode"
+	"github.com/conn-castle/agent-layer/internal/i18n"
+	"github.com/conn-castle/agent-layer/internal/layerdir"
 	"github.com/conn-castle/agent-layer/internal/messages"
 )
 
 // LoadProjectConfigFS reads and validates the full Agent Layer config from an fs.FS rooted at repo root.
 // fsys is the filesystem to read from; root is used for error messages and built-in env values.
+// Failures are tagged errcode.Config.
 func LoadProjectConfigFS(fsys fs.FS, root string) (*ProjectConfig, error) {
+	project, err := loadProjectConfigFS(fsys, root)
+	if err != nil {
+		return nil, errcode.Wrap(errcode.Config, err)
+	}
+	return project, nil
+}
+
+func loadProjectConfigFS(fsys fs.FS, root string) (*ProjectConfig, error) {
 	if fsys == nil {
-		return nil, fmt.Errorf(messages.ConfigFSRequired)
+		return nil, i18n.Errorf(messages.ConfigFSRequired)
 	}
 	if root == "" {
-		return nil, fmt.Errorf(messages.ConfigRootRequired)
+		return nil, i18n.Errorf(messages.ConfigRootRequired)
 	}
 	paths := DefaultPaths(root)
 
-	cfg, err := LoadConfigFS(fsys, root, paths.ConfigPath)
+	cfg, base, err := loadLayeredConfigFS(fsys, root, paths.ConfigPath)
 	if err != nil {
 		return nil, err
 	}
@@ -41,6 +53,11 @@ func LoadProjectConfigFS(fsys fs.FS, root string) (*ProjectConfig, error) {
 		return nil, err
 	}
 
+	scoped, err := LoadScopedInstructionsFS(fsys, root, paths.ScopedDir)
+	if err != nil {
+		return nil, err
+	}
+
 	skills, err := LoadSkillsFS(fsys, root, paths.SkillsDir)
 	if err != nil {
 		return nil, err
@@ -51,13 +68,46 @@ func LoadProjectConfigFS(fsys fs.FS, root string) (*ProjectConfig, error) {
 		return nil, err
 	}
 
+	extendsDir := ""
+	if base != nil {
+		extendsDir = base.dir
+		if instructions, err = base.instructions(instructions); err != nil {
+			return nil, err
+		}
+		if skills, err = base.skills(skills); err != nil {
+			return nil, err
+		}
+		if commandsAllow, err = base.commandsAllow(commandsAllow); err != nil {
+			return nil, err
+		}
+	}
+	userSkills, err := LoadUserSkills()
+	if err != nil {
+		return nil, err
+	}
+	skills = mergeUserSkills(skills, userSkills)
+
+	profile, err := resolveVariantProfile(cfg.Variants, env)
+	if err != nil {
+		return nil, err
+	}
+	selectedInstructions, selectedSkills, err := selectVariants(instructions, skills, cfg.Variants, profile, "")
+	if err != nil {
+		return nil, err
+	}
+
 	return &ProjectConfig{
-		Config:        *cfg,
-		Env:           env,
-		Instructions:  instructions,
-		Skills:        skills,
-		CommandsAllow: commandsAllow,
-		Root:          root,
+		Config:              *cfg,
+		Env:                 env,
+		Instructions:        selectedInstructions,
+		Skills:              selectedSkills,
+		CommandsAllow:       commandsAllow,
+		InstructionVariants: instructions,
+		SkillVariants:       skills,
+		VariantProfile:      profile,
+		ScopedInstructions:  scoped,
+		Root:                root,
+		ExtendsDir:          extendsDir,
 	}, nil
 }
 
@@ -66,24 +116,24 @@ func LoadProjectConfigFS(fsys fs.FS, root string) (*ProjectConfig, error) {
 func LoadConfigFS(fsys fs.FS, root string, path string) (*Config, error) {
 	data, err := readFileFS(fsys, root, path)
 	if err != nil {
-		return nil, fmt.Errorf(messages.ConfigMissingFileFmt, path, err)
+		return nil, i18n.Errorf(messages.ConfigMissingFileFmt, path, err)
 	}
 	return ParseConfig(data, path)
 }
 
-// LoadEnvFS reads .agent-layer/.env from fsys into a key-value map.
+// LoadEnvFS reads .agent-layer/.env from fsys into a key-value map, decrypting ENC[age:...] values.
 // root is used for path resolution when path is absolute; path is used for error messages.
 func LoadEnvFS(fsys fs.FS, root string, path string) (map[string]string, error) {
 	data, err := readFileFS(fsys, root, path)
 	if err != nil {
-		return nil, fmt.Errorf(messages.ConfigMissingEnvFileFmt, path, err)
+		return nil, i18n.Errorf(messages.ConfigMissingEnvFileFmt, path, err)
 	}
 
 	env, err := envfile.Parse(string(data))
 	if err != nil {
-		return nil, fmt.Errorf(messages.ConfigInvalidEnvFileFmt, path, err)
+		return nil, i18n.Errorf(messages.ConfigInvalidEnvFileFmt, path, err)
 	}
-	return filterAgentLayerEnv(env), nil
+	return envcrypt.DecryptEnv(filterAgentLayerEnv(env))
 }
 
 // LoadInstructionsFS reads .agent-layer/instructions/*.md from fsys in lexicographic order.
@@ -91,7 +141,7 @@ func LoadEnvFS(fsys fs.FS, root string, path string) (map[string]string, error)
 func LoadInstructionsFS(fsys fs.FS, root string, dir string) ([]InstructionFile, error) {
 	entries, err := readDirFS(fsys, root, dir)
 	if err != nil {
-		return nil, fmt.Errorf(messages.ConfigMissingInstructionsDirFmt, dir, err)
+		return nil, i18n.Errorf(messages.ConfigMissingInstructionsDirFmt, dir, err)
 	}
 
 	var names []string
@@ -101,6 +151,9 @@ func LoadInstructionsFS(fsys fs.FS, root string, dir string) ([]InstructionFile,
 		}
 		name := entry.Name()
 		if strings.HasSuffix(name, ".md") {
+			if err := checkVariantName(filepath.Join(dir, name), strings.TrimSuffix(name, ".md")); err != nil {
+				return nil, err
+			}
 			names = append(names, name)
 		}
 	}
@@ -112,7 +165,7 @@ func LoadInstructionsFS(fsys fs.FS, root string, dir string) ([]InstructionFile,
 		path := filepath.Join(dir, name)
 		data, err := readFileFS(fsys, root, path)
 		if err != nil {
-			return nil, fmt.Errorf(messages.ConfigFailedReadInstructionFmt, path, err)
+			return nil, i18n.Errorf(messages.ConfigFailedReadInstructionFmt, path, err)
 		}
 		data = bytes.TrimPrefix(data, utf8BOM)
 		files = append(files, InstructionFile{
@@ -152,7 +205,7 @@ func LoadSkillsFS(fsys fs.FS, root string, dir string) ([]Skill, error) {
 func LoadCommandsAllowFS(fsys fs.FS, root string, path string) ([]string, error) {
 	data, err := readFileFS(fsys, root, path)
 	if err != nil {
-		return nil, fmt.Errorf(messages.ConfigMissingCommandsAllowlistFmt, path, err)
+		return nil, i18n.Errorf(messages.ConfigMissingCommandsAllowlistFmt, path, err)
 	}
 
 	var commands []string
@@ -165,7 +218,7 @@ func LoadCommandsAllowFS(fsys fs.FS, root string, path string) ([]string, error)
 		commands = append(commands, line)
 	}
 	if err := scanner.Err(); err != nil {
-		return nil, fmt.Errorf(messages.ConfigFailedReadCommandsAllowlistFmt, path, err)
+		return nil, i18n.Errorf(messages.ConfigFailedReadCommandsAllowlistFmt, path, err)
 	}
 
 	return commands, nil
@@ -196,15 +249,20 @@ func readDirFS(fsys fs.FS, root string, dir string) ([]fs.DirEntry, error) {
 func fsPathFromRoot(root string, targetPath string) (string, error) {
 	if filepath.IsAbs(targetPath) {
 		if root == "" {
-			return "", fmt.Errorf(messages.ConfigPathOutsideRootFmt, targetPath, root)
+			return "", i18n.Errorf(messages.ConfigPathOutsideRootFmt, targetPath, root)
 		}
 		rel, err := filepath.Rel(root, targetPath)
 		if err != nil {
-			return "", fmt.Errorf(messages.ConfigPathOutsideRootFmt, targetPath, root)
+			return "", i18n.Errorf(messages.ConfigPathOutsideRootFmt, targetPath, root)
 		}
 		rel = filepath.Clean(rel)
 		if rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
-			return "", fmt.Errorf(messages.ConfigPathOutsideRootFmt, targetPath, root)
+			// A redirected .agent-layer may live outside the repo; layerdir.FS
+			// serves it under its .agent-layer path.
+			if layerRel, err := filepath.Rel(layerdir.Dir(root), targetPath); err == nil && layerRel != ".." && !strings.HasPrefix(layerRel, ".."+string(filepath.Separator)) {
+				return pathpkg.Join(layerdir.Name, filepath.ToSlash(layerRel)), nil
+			}
+			return "", i18n.Errorf(messages.ConfigPathOutsideRootFmt, targetPath, root)
 		}
 		fsPath := filepath.ToSlash(rel)
 		return pathpkg.Clean(fsPath), nil
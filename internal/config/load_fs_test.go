@@ -9,6 +9,7 @@ import (
 	"testing"
 	"testing/fstest"
 
+	"github.com/conn-castle/agent-layer/internal/errcode"
 	"github.com/conn-castle/agent-layer/internal/messages"
 )
 
@@ -66,6 +67,9 @@ func TestLoadProjectConfigFS_NilFilesystem(t *testing.T) {
 	if err.Error() != messages.ConfigFSRequired {
 		t.Fatalf("expected %q, got %q", messages.ConfigFSRequired, err.Error())
 	}
+	if code := errcode.Of(err); code != errcode.Config {
+		t.Fatalf("expected code %q, got %q", errcode.Config, code)
+	}
 }
 
 func TestLoadProjectConfigFS_EmptyRoot(t *testing.T) {
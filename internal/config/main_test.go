@@ -0,0 +1,25 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"testing"
+)
+
+// TestMain points the user config home at an empty directory so personal
+// skills under the developer's ~/.config/agent-layer/skills never leak into
+// project loads under test.
+func TestMain(m *testing.M) {
+	dir, err := os.MkdirTemp("", "agent-layer-config-home-")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "create config home: %v\n", err)
+		os.Exit(1)
+	}
+	if err := os.Setenv("XDG_CONFIG_HOME", dir); err != nil {
+		fmt.Fprintf(os.Stderr, "set XDG_CONFIG_HOME: %v\n", err)
+		os.Exit(1)
+	}
+	code := m.Run()
+	_ = os.RemoveAll(dir)
+	os.Exit(code)
+}
@@ -12,3 +12,14 @@ func (s MCPServer) AppliesToClient(client string) bool {
 	}
 	return false
 }
+
+// HasToolFilter reports whether the server limits which of its tools clients see.
+func (s MCPServer) HasToolFilter() bool {
+	return len(s.ToolsAllow) > 0 || len(s.ToolsDeny) > 0
+}
+
+// GatewayEnabled reports whether clients should receive the aggregating
+// `al mcp gateway` server instead of the individual servers.
+func (m MCPConfig) GatewayEnabled() bool {
+	return m.Gateway != nil && *m.Gateway
+}
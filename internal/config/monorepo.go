@@ -0,0 +1,172 @@
+package config
+
+import (
+	"errors"
+	"io/fs"
+	pathpkg "path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/conn-castle/agent-layer/internal/i18n"
+	"github.com/conn-castle/agent-layer/internal/messages"
+)
+
+// Monorepo generation modes.
+const (
+	// MonorepoModeFull generates scoped instructions for every scoped directory.
+	MonorepoModeFull = "full"
+	// MonorepoModeSparse generates scoped instructions only for directories the
+	// current user works in.
+	MonorepoModeSparse = "sparse"
+)
+
+// EnvMonorepoOwner selects an entry of [monorepo.owners] in sparse mode. It is
+// read from the process environment first, then from .agent-layer/.env.
+const EnvMonorepoOwner = "AL_MONOREPO_OWNER"
+
+// ScopedInstructions holds the instruction files for one repo subdirectory or
+// path glob, loaded from .agent-layer/scoped/<dir>/*.md or
+// .agent-layer/scoped/<glob>/*.md. Exactly one of Dir and Glob is set.
+type ScopedInstructions struct {
+	// Dir is the slash-separated repo-relative directory the files apply to.
+	Dir string
+	// Glob is the slash-separated repo-relative pattern the files apply to
+	// when the path under scoped/ contains glob characters.
+	Glob  string
+	Files []InstructionFile
+}
+
+// ApplyTo returns the glob of files the instructions cover: Glob, or
+// "<dir>/**" for a directory.
+func (s ScopedInstructions) ApplyTo() string {
+	if s.Glob != "" {
+		return s.Glob
+	}
+	return s.Dir + "/**"
+}
+
+// Path returns the path under .agent-layer/scoped/ the instructions were
+// loaded from.
+func (s ScopedInstructions) Path() string {
+	if s.Glob != "" {
+		return s.Glob
+	}
+	return s.Dir
+}
+
+// hasGlobMeta reports whether s contains a glob metacharacter.
+func hasGlobMeta(s string) bool {
+	return strings.ContainsAny(s, "*?[{")
+}
+
+// IsSparse reports whether sparse generation is enabled.
+func (m MonorepoConfig) IsSparse() bool {
+	return strings.EqualFold(strings.TrimSpace(m.Mode), MonorepoModeSparse)
+}
+
+// validateMonorepo checks the mode and that owner directories are clean
+// repo-relative paths.
+func validateMonorepo(path string, cfg MonorepoConfig) error {
+	switch strings.ToLower(strings.TrimSpace(cfg.Mode)) {
+	case "", MonorepoModeFull, MonorepoModeSparse:
+	default:
+		return i18n.Errorf(messages.ConfigMonorepoModeInvalidFmt, path, cfg.Mode)
+	}
+	for owner, dirs := range cfg.Owners {
+		if strings.TrimSpace(owner) == "" {
+			return i18n.Errorf(messages.ConfigMonorepoOwnerNameRequiredFmt, path)
+		}
+		for _, dir := range dirs {
+			if _, err := CleanRepoDir(dir); err != nil {
+				return i18n.Errorf(messages.ConfigMonorepoOwnerDirInvalidFmt, path, owner, err)
+			}
+		}
+	}
+	return nil
+}
+
+// CleanRepoDir normalizes a repo-relative directory to slash form without a
+// leading "./" or trailing "/". It rejects absolute paths, the repo root, and
+// paths that escape the repo.
+func CleanRepoDir(dir string) (string, error) {
+	trimmed := strings.TrimSpace(filepath.ToSlash(dir))
+	if trimmed == "" || pathpkg.IsAbs(trimmed) || filepath.IsAbs(dir) {
+		return "", i18n.Errorf(messages.ConfigRepoDirInvalidFmt, dir)
+	}
+	clean := pathpkg.Clean(trimmed)
+	if clean == "." || clean == ".." || strings.HasPrefix(clean, "../") {
+		return "", i18n.Errorf(messages.ConfigRepoDirInvalidFmt, dir)
+	}
+	return clean, nil
+}
+
+// LoadScopedInstructionsFS reads .agent-layer/scoped/<path>/*.md from fsys.
+// Each directory under scoped/ that directly contains .md files mirrors the
+// repo directory of the same relative path, unless the path contains glob
+// characters, in which case it is a glob: scoped/**/*_test.go/ applies to
+// every Go test file. Hidden directories are skipped, so scoped instructions
+// never target .agent-layer/ or client config dirs. A missing scoped dir
+// yields nil.
+func LoadScopedInstructionsFS(fsys fs.FS, root string, dir string) ([]ScopedInstructions, error) {
+	fsDir, err := fsPathFromRoot(root, dir)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := fs.Stat(fsys, fsDir); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, i18n.Errorf(messages.ConfigScopedReadFailedFmt, dir, err)
+	}
+
+	byDir := make(map[string]bool)
+	walkErr := fs.WalkDir(fsys, fsDir, func(p string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			if p != fsDir && strings.HasPrefix(entry.Name(), ".") {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if !strings.HasSuffix(entry.Name(), ".md") {
+			return nil
+		}
+		rel := strings.TrimPrefix(pathpkg.Dir(p), fsDir)
+		rel = strings.TrimPrefix(rel, "/")
+		if rel == "" {
+			return i18n.Errorf(messages.ConfigScopedRootFileFmt, filepath.Join(dir, entry.Name()))
+		}
+		byDir[rel] = true
+		return nil
+	})
+	if walkErr != nil {
+		return nil, i18n.Errorf(messages.ConfigScopedReadFailedFmt, dir, walkErr)
+	}
+
+	dirs := make([]string, 0, len(byDir))
+	for rel := range byDir {
+		dirs = append(dirs, rel)
+	}
+	sort.Strings(dirs)
+	scoped := make([]ScopedInstructions, 0, len(dirs))
+	for _, rel := range dirs {
+		path := filepath.Join(dir, filepath.FromSlash(rel))
+		entry := ScopedInstructions{Dir: rel}
+		if hasGlobMeta(rel) {
+			if _, err := pathpkg.Match(rel, ""); err != nil {
+				return nil, i18n.Errorf(messages.ConfigScopedGlobInvalidFmt, path, err)
+			}
+			entry = ScopedInstructions{Glob: rel}
+		}
+		files, err := LoadInstructionsFS(fsys, root, path)
+		if err != nil {
+			return nil, err
+		}
+		entry.Files = files
+		scoped = append(scoped, entry)
+	}
+	return scoped, nil
+}
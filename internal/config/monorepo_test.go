@@ -0,0 +1,101 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadScopedInstructionsFS(t *testing.T) {
+	root := t.TempDir()
+	dir := DefaultPaths(root).ScopedDir
+	for path, content := range map[string]string{
+		"services/payments/00_rules.md": "payments rules",
+		"services/payments/10_more.md":  "more",
+		"services/README.txt":           "ignored",
+		"services/00_shared.md":         "shared",
+		"**/*_test.go/00_tests.md":      "table tests",
+		".hidden/00.md":                 "ignored",
+	} {
+		full := filepath.Join(dir, filepath.FromSlash(path))
+		if err := os.MkdirAll(filepath.Dir(full), 0o700); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(full, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	scoped, err := LoadScopedInstructionsFS(os.DirFS(root), root, dir)
+	if err != nil {
+		t.Fatalf("LoadScopedInstructionsFS error: %v", err)
+	}
+	if len(scoped) != 3 || scoped[0].Glob != "**/*_test.go" || scoped[0].Dir != "" || scoped[1].Dir != "services" || scoped[2].Dir != "services/payments" {
+		t.Fatalf("scoped = %+v", scoped)
+	}
+	if scoped[0].ApplyTo() != "**/*_test.go" || scoped[1].ApplyTo() != "services/**" {
+		t.Fatalf("ApplyTo() = %q, %q", scoped[0].ApplyTo(), scoped[1].ApplyTo())
+	}
+	if len(scoped[2].Files) != 2 || scoped[2].Files[0].Name != "00_rules.md" {
+		t.Fatalf("payments files = %+v", scoped[2].Files)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "root.md"), []byte("x"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadScopedInstructionsFS(os.DirFS(root), root, dir); err == nil || !strings.Contains(err.Error(), ".agent-layer/instructions/") {
+		t.Fatalf("expected root file error, got %v", err)
+	}
+}
+
+func TestLoadScopedInstructionsFS_InvalidGlob(t *testing.T) {
+	root := t.TempDir()
+	dir := DefaultPaths(root).ScopedDir
+	full := filepath.Join(dir, "src", "[a-", "00.md")
+	if err := os.MkdirAll(filepath.Dir(full), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(full, []byte("x"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadScopedInstructionsFS(os.DirFS(root), root, dir); err == nil || !strings.Contains(err.Error(), "invalid path glob") {
+		t.Fatalf("expected invalid glob error, got %v", err)
+	}
+}
+
+func TestLoadScopedInstructionsFS_Missing(t *testing.T) {
+	root := t.TempDir()
+	scoped, err := LoadScopedInstructionsFS(os.DirFS(root), root, DefaultPaths(root).ScopedDir)
+	if err != nil || scoped != nil {
+		t.Fatalf("expected nil, got %+v (%v)", scoped, err)
+	}
+}
+
+func TestValidateMonorepo(t *testing.T) {
+	valid := MonorepoConfig{Mode: "Sparse", Owners: map[string][]string{"payments": {"services/payments/", "./libs/billing"}}}
+	if err := validateMonorepo("config.toml", valid); err != nil {
+		t.Fatalf("validateMonorepo error: %v", err)
+	}
+	if !valid.IsSparse() || (MonorepoConfig{}).IsSparse() {
+		t.Fatal("unexpected IsSparse result")
+	}
+	for _, cfg := range []MonorepoConfig{
+		{Mode: "partial"},
+		{Owners: map[string][]string{"": {"a"}}},
+		{Owners: map[string][]string{"x": {"../outside"}}},
+		{Owners: map[string][]string{"x": {"/abs"}}},
+		{Owners: map[string][]string{"x": {"."}}},
+	} {
+		if err := validateMonorepo("config.toml", cfg); err == nil {
+			t.Fatalf("expected error for %+v", cfg)
+		}
+	}
+}
+
+func TestCleanRepoDir(t *testing.T) {
+	got, err := CleanRepoDir(" ./services//payments/ ")
+	if err != nil || got != "services/payments" {
+		t.Fatalf("CleanRepoDir = %q (%v)", got, err)
+	}
+}
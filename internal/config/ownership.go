@@ -0,0 +1,46 @@
+package config
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/conn-castle/agent-layer/internal/i18n"
+	"github.com/conn-castle/agent-layer/internal/messages"
+)
+
+// OwnershipUser marks an [ownership] path as user-owned.
+const OwnershipUser = "user"
+
+// UserOwnedPaths returns the cleaned, sorted paths that [ownership] marks as
+// user-owned.
+func (c *Config) UserOwnedPaths() []string {
+	paths := make([]string, 0, len(c.Ownership))
+	for path, owner := range c.Ownership {
+		if !strings.EqualFold(strings.TrimSpace(owner), OwnershipUser) {
+			continue
+		}
+		if clean, err := CleanRepoDir(path); err == nil {
+			paths = append(paths, clean)
+		}
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+func validateOwnership(path string, ownership map[string]string) []error {
+	keys := make([]string, 0, len(ownership))
+	for key := range ownership {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	var errs []error
+	for _, key := range keys {
+		if _, err := CleanRepoDir(key); err != nil {
+			errs = append(errs, i18n.Errorf(messages.ConfigOwnershipPathInvalidFmt, path, err))
+		}
+		if !strings.EqualFold(strings.TrimSpace(ownership[key]), OwnershipUser) {
+			errs = append(errs, i18n.Errorf(messages.ConfigOwnershipValueInvalidFmt, path, key, ownership[key]))
+		}
+	}
+	return errs
+}
@@ -1,6 +1,10 @@
 package config
 
-import "path/filepath"
+import (
+	"path/filepath"
+
+	"github.com/conn-castle/agent-layer/internal/layerdir"
+)
 
 // Paths holds resolved paths for config files and directories.
 type Paths struct {
@@ -8,18 +12,22 @@ type Paths struct {
 	ConfigPath      string
 	EnvPath         string
 	InstructionsDir string
+	ScopedDir       string
 	SkillsDir       string
 	CommandsAllow   string
 }
 
-// DefaultPaths returns the default config paths for a repo root.
+// DefaultPaths returns the default config paths for a repo root, following an
+// .agent-layer redirect file.
 func DefaultPaths(root string) Paths {
+	agentLayerDir := layerdir.Dir(root)
 	return Paths{
 		Root:            root,
-		ConfigPath:      filepath.Join(root, ".agent-layer", "config.toml"),
-		EnvPath:         filepath.Join(root, ".agent-layer", ".env"),
-		InstructionsDir: filepath.Join(root, ".agent-layer", "instructions"),
-		SkillsDir:       filepath.Join(root, ".agent-layer", "skills"),
-		CommandsAllow:   filepath.Join(root, ".agent-layer", "commands.allow"),
+		ConfigPath:      filepath.Join(agentLayerDir, "config.toml"),
+		EnvPath:         filepath.Join(agentLayerDir, ".env"),
+		InstructionsDir: filepath.Join(agentLayerDir, "instructions"),
+		ScopedDir:       filepath.Join(agentLayerDir, "scoped"),
+		SkillsDir:       filepath.Join(agentLayerDir, "skills"),
+		CommandsAllow:   filepath.Join(agentLayerDir, "commands.allow"),
 	}
 }
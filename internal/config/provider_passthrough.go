@@ -16,6 +16,8 @@ const (
 	CodexReasoningEffortKey = "model_reasoning_effort"
 	// CodexProjectsKey is the top-level Codex config key for trusted projects.
 	CodexProjectsKey = "projects"
+	// CodexProfilesKey is the top-level Codex config key for named profiles.
+	CodexProfilesKey = "profiles"
 	// CodexSandboxModeKey is the top-level Codex config key for sandbox mode.
 	CodexSandboxModeKey = "sandbox_mode"
 	// CodexWebSearchKey is the top-level Codex config key for web search.
@@ -0,0 +1,112 @@
+package config
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/conn-castle/agent-layer/internal/i18n"
+	"github.com/conn-castle/agent-layer/internal/messages"
+)
+
+const (
+	skillUnvisited = iota
+	skillVisiting
+	skillVisited
+)
+
+// skillGraph walks skills along their requires edges. Requirements that name
+// no configured skill are skipped; the first cycle found is recorded and
+// broken where it closes.
+type skillGraph struct {
+	skills []Skill
+	index  map[string]int
+	state  []int
+	order  []int
+	cycle  []string
+}
+
+func newSkillGraph(skills []Skill) *skillGraph {
+	index := make(map[string]int, len(skills))
+	for i, skill := range skills {
+		index[normalizeSkillName(skill.Name)] = i
+	}
+	return &skillGraph{skills: skills, index: index, state: make([]int, len(skills))}
+}
+
+// walk appends skill i to the order after every skill it requires.
+func (g *skillGraph) walk(i int, path []string) {
+	name := g.skills[i].Name
+	switch g.state[i] {
+	case skillVisited:
+		return
+	case skillVisiting:
+		if g.cycle == nil {
+			start := slices.Index(path, name)
+			g.cycle = append(slices.Clone(path[start:]), name)
+		}
+		return
+	}
+	g.state[i] = skillVisiting
+	path = append(path, name)
+	for _, required := range g.skills[i].Requires {
+		if j, ok := g.index[normalizeSkillName(required)]; ok {
+			g.walk(j, path)
+		}
+	}
+	g.state[i] = skillVisited
+	g.order = append(g.order, i)
+}
+
+// CheckSkillRequires reports a skill that requires a skill that is not
+// configured, then a cycle of skills that require each other.
+func CheckSkillRequires(skills []Skill) error {
+	g := newSkillGraph(skills)
+	for _, skill := range skills {
+		for _, required := range skill.Requires {
+			if _, ok := g.index[normalizeSkillName(required)]; !ok {
+				return i18n.Errorf(messages.ConfigSkillRequiresMissingFmt, skill.Name, required)
+			}
+		}
+	}
+	for i := range skills {
+		g.walk(i, nil)
+	}
+	if g.cycle != nil {
+		return i18n.Errorf(messages.ConfigSkillRequiresCycleFmt, strings.Join(g.cycle, " -> "))
+	}
+	return nil
+}
+
+// OrderSkills returns skills with each one after the skills it requires and
+// otherwise in their original order. Missing requirements and cycles do not
+// fail here; CheckSkillRequires reports them.
+func OrderSkills(skills []Skill) []Skill {
+	g := newSkillGraph(skills)
+	for i := range skills {
+		g.walk(i, nil)
+	}
+	ordered := make([]Skill, 0, len(skills))
+	for _, i := range g.order {
+		ordered = append(ordered, skills[i])
+	}
+	return ordered
+}
+
+// SkillRequirements returns the skills that skill name requires, directly or
+// through other skills, each after the skills it requires. It returns nil
+// when name is not configured or requires nothing.
+func SkillRequirements(skills []Skill, name string) []Skill {
+	g := newSkillGraph(skills)
+	start, ok := g.index[normalizeSkillName(name)]
+	if !ok {
+		return nil
+	}
+	g.walk(start, nil)
+	var required []Skill
+	for _, i := range g.order {
+		if i != start {
+			required = append(required, skills[i])
+		}
+	}
+	return required
+}
@@ -0,0 +1,83 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func skillNames(skills []Skill) string {
+	names := make([]string, 0, len(skills))
+	for _, skill := range skills {
+		names = append(names, skill.Name)
+	}
+	return strings.Join(names, ",")
+}
+
+func TestCheckSkillRequires(t *testing.T) {
+	tests := []struct {
+		name   string
+		skills []Skill
+		want   string
+	}{
+		{name: "ok", skills: []Skill{{Name: "deploy", Requires: []string{"test"}}, {Name: "test"}}},
+		{name: "missing", skills: []Skill{{Name: "deploy", Requires: []string{"build"}}}, want: `skill "deploy" requires "build", which is not a configured skill`},
+		{name: "self", skills: []Skill{{Name: "deploy", Requires: []string{"deploy"}}}, want: "cycle: deploy -> deploy"},
+		{
+			name: "cycle",
+			skills: []Skill{
+				{Name: "a", Requires: []string{"b"}},
+				{Name: "b", Requires: []string{"c"}},
+				{Name: "c", Requires: []string{"b"}},
+			},
+			want: "cycle: b -> c -> b",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckSkillRequires(tt.skills)
+			if tt.want == "" {
+				if err != nil {
+					t.Fatalf("CheckSkillRequires: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("error = %v, want containing %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestOrderSkills(t *testing.T) {
+	skills := []Skill{
+		{Name: "deploy", Requires: []string{"test", "build"}},
+		{Name: "lint"},
+		{Name: "build"},
+		{Name: "test", Requires: []string{"build", "missing"}},
+	}
+	if got := skillNames(OrderSkills(skills)); got != "build,test,deploy,lint" {
+		t.Fatalf("OrderSkills = %s", got)
+	}
+
+	cyclic := []Skill{{Name: "a", Requires: []string{"b"}}, {Name: "b", Requires: []string{"a"}}, {Name: "c"}}
+	if got := skillNames(OrderSkills(cyclic)); got != "b,a,c" {
+		t.Fatalf("OrderSkills(cyclic) = %s", got)
+	}
+}
+
+func TestSkillRequirements(t *testing.T) {
+	skills := []Skill{
+		{Name: "deploy", Requires: []string{"test"}},
+		{Name: "build"},
+		{Name: "test", Requires: []string{"build"}},
+	}
+	if got := skillNames(SkillRequirements(skills, "deploy")); got != "build,test" {
+		t.Fatalf("SkillRequirements(deploy) = %s", got)
+	}
+	if got := SkillRequirements(skills, "build"); got != nil {
+		t.Fatalf("SkillRequirements(build) = %v, want nil", got)
+	}
+	if got := SkillRequirements(skills, "missing"); got != nil {
+		t.Fatalf("SkillRequirements(missing) = %v, want nil", got)
+	}
+}
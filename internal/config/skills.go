@@ -4,14 +4,15 @@ import (
 	"bufio"
 	"bytes"
 	"errors"
-	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"sort"
 	"strings"
 
 	"golang.org/x/text/unicode/norm"
 
+	"github.com/conn-castle/agent-layer/internal/i18n"
 	"github.com/conn-castle/agent-layer/internal/messages"
 	"github.com/conn-castle/agent-layer/internal/skillfrontmatter"
 )
@@ -40,6 +41,8 @@ type parsedSkill struct {
 	compatibility string
 	metadata      map[string]string
 	allowedTools  string
+	requires      []string
+	enabledWhen   string
 	body          string
 	name          string
 }
@@ -74,7 +77,7 @@ func LoadSkills(dir string) ([]Skill, error) {
 func loadSkills(dir string, readDir skillReadDir, readFile skillReadFile) ([]Skill, error) {
 	entries, err := readDir(dir)
 	if err != nil {
-		return nil, fmt.Errorf(messages.ConfigMissingSkillsDirFmt, dir, err)
+		return nil, i18n.Errorf(messages.ConfigMissingSkillsDirFmt, dir, err)
 	}
 
 	sort.Slice(entries, func(i, j int) bool {
@@ -94,7 +97,7 @@ func loadSkills(dir string, readDir skillReadDir, readFile skillReadFile) ([]Ski
 		}
 		if strings.HasSuffix(entry.name, ".md") {
 			name := strings.TrimSuffix(entry.name, ".md")
-			return nil, fmt.Errorf(messages.ConfigSkillFlatFormatUnsupportedFmt, name, filepath.Join(dir, entry.name))
+			return nil, i18n.Errorf(messages.ConfigSkillFlatFormatUnsupportedFmt, name, filepath.Join(dir, entry.name))
 		}
 	}
 
@@ -115,7 +118,7 @@ func loadDirectorySkill(byName map[string]skillSource, root string, dirName stri
 	skillDirPath := filepath.Join(root, dirName)
 	entries, err := readDir(skillDirPath)
 	if err != nil {
-		return fmt.Errorf(messages.ConfigFailedReadSkillFmt, skillDirPath, err)
+		return i18n.Errorf(messages.ConfigFailedReadSkillFmt, skillDirPath, err)
 	}
 
 	hasCanonical := false
@@ -139,22 +142,25 @@ func loadDirectorySkill(byName map[string]skillSource, root string, dirName stri
 	case hasFallback:
 		skillPath = filepath.Join(skillDirPath, lowercaseSkillManifestName)
 	default:
-		return fmt.Errorf(messages.ConfigSkillDirEmptyFmt, skillDirPath)
+		return i18n.Errorf(messages.ConfigSkillDirEmptyFmt, skillDirPath)
 	}
 
 	data, err := readFile(skillPath)
 	if err != nil {
-		return fmt.Errorf(messages.ConfigFailedReadSkillFmt, skillPath, err)
+		return i18n.Errorf(messages.ConfigFailedReadSkillFmt, skillPath, err)
 	}
 
 	parsed, err := parseSkill(string(bytes.TrimPrefix(data, utf8BOM)))
 	if err != nil {
-		return fmt.Errorf(messages.ConfigInvalidSkillFmt, skillPath, err)
+		return i18n.Errorf(messages.ConfigInvalidSkillFmt, skillPath, err)
 	}
 
-	name := dirName
+	if err := checkVariantName(skillDirPath, dirName); err != nil {
+		return err
+	}
+	name, variant := splitVariant(dirName)
 	if parsed.name != "" && !skillNamesEqual(parsed.name, name) {
-		return fmt.Errorf(messages.ConfigSkillNameMismatchFmt, skillPath, parsed.name, name)
+		return i18n.Errorf(messages.ConfigSkillNameMismatchFmt, skillPath, parsed.name, name)
 	}
 
 	skill := Skill{
@@ -164,29 +170,42 @@ func loadDirectorySkill(byName map[string]skillSource, root string, dirName stri
 		Compatibility: parsed.compatibility,
 		Metadata:      parsed.metadata,
 		AllowedTools:  parsed.allowedTools,
+		Requires:      parsed.requires,
+		EnabledWhen:   parsed.enabledWhen,
 		Body:          parsed.body,
 		SourcePath:    skillPath,
 		SourceDir:     skillDirPath,
+		Variant:       variant,
 	}
 	return registerSkill(byName, skill)
 }
 
 func registerSkill(byName map[string]skillSource, skill Skill) error {
-	if existing, ok := byName[skill.Name]; ok {
-		return fmt.Errorf(messages.ConfigSkillDuplicateNameFmt, skill.Name, existing.path, skill.SourcePath)
+	key := skillKey(skill)
+	if existing, ok := byName[key]; ok {
+		return i18n.Errorf(messages.ConfigSkillDuplicateNameFmt, key, existing.path, skill.SourcePath)
 	}
-	byName[skill.Name] = skillSource{path: skill.SourcePath, skill: skill}
+	byName[key] = skillSource{path: skill.SourcePath, skill: skill}
 	return nil
 }
 
+// skillKey identifies a skill across layers: its name, plus @variant for
+// variants, so a variant never replaces its base.
+func skillKey(skill Skill) string {
+	if skill.Variant == "" {
+		return skill.Name
+	}
+	return skill.Name + variantSeparator + skill.Variant
+}
+
 func parseSkill(content string) (parsedSkill, error) {
 	scanner := bufio.NewScanner(strings.NewReader(content))
 	scanner.Buffer(make([]byte, skillScannerInitialBufferSize), skillScannerMaxTokenSize)
 	if !scanner.Scan() {
-		return parsedSkill{}, fmt.Errorf(messages.ConfigSkillMissingContent)
+		return parsedSkill{}, i18n.Errorf(messages.ConfigSkillMissingContent)
 	}
 	if strings.TrimSpace(scanner.Text()) != "---" {
-		return parsedSkill{}, fmt.Errorf(messages.ConfigSkillMissingFrontMatter)
+		return parsedSkill{}, i18n.Errorf(messages.ConfigSkillMissingFrontMatter)
 	}
 
 	var fmLines []string
@@ -200,7 +219,7 @@ func parseSkill(content string) (parsedSkill, error) {
 		fmLines = append(fmLines, line)
 	}
 	if !foundEnd {
-		return parsedSkill{}, fmt.Errorf(messages.ConfigSkillUnterminatedFrontMatter)
+		return parsedSkill{}, i18n.Errorf(messages.ConfigSkillUnterminatedFrontMatter)
 	}
 
 	var bodyBuilder strings.Builder
@@ -209,7 +228,7 @@ func parseSkill(content string) (parsedSkill, error) {
 		bodyBuilder.WriteString("\n")
 	}
 	if err := scanner.Err(); err != nil {
-		return parsedSkill{}, fmt.Errorf(messages.ConfigSkillFailedReadContentFmt, err)
+		return parsedSkill{}, i18n.Errorf(messages.ConfigSkillFailedReadContentFmt, err)
 	}
 
 	doc, err := skillfrontmatter.Parse(strings.Join(fmLines, "\n"))
@@ -227,6 +246,11 @@ func parseSkill(content string) (parsedSkill, error) {
 		return parsedSkill{}, err
 	}
 
+	requires, err := parseSkillRequires(doc.Requires)
+	if err != nil {
+		return parsedSkill{}, err
+	}
+
 	body := strings.TrimPrefix(bodyBuilder.String(), "\n")
 	body = strings.TrimRight(body, "\n")
 	return parsedSkill{
@@ -235,6 +259,8 @@ func parseSkill(content string) (parsedSkill, error) {
 		compatibility: normalizeOptionalSkillValue(skillFieldValue(doc.Compatibility)),
 		metadata:      normalizeSkillMetadata(doc.Metadata),
 		allowedTools:  normalizeOptionalSkillValue(skillFieldValue(doc.AllowedTools)),
+		requires:      requires,
+		enabledWhen:   normalizeOptionalSkillValue(skillFieldValue(doc.EnabledWhen)),
 		body:          body,
 		name:          name,
 	}, nil
@@ -247,14 +273,14 @@ func wrapFrontMatterError(err error) error {
 	if errors.As(err, &parseErr) {
 		switch parseErr.Kind {
 		case skillfrontmatter.KindDuplicateKey:
-			return fmt.Errorf(messages.ConfigSkillDuplicateKeyFmt, parseErr.Key)
+			return i18n.Errorf(messages.ConfigSkillDuplicateKeyFmt, parseErr.Key)
 		case skillfrontmatter.KindSyntax:
-			return fmt.Errorf(messages.ConfigSkillInvalidFrontMatterFmt, parseErr)
+			return i18n.Errorf(messages.ConfigSkillInvalidFrontMatterFmt, parseErr)
 		default:
-			return fmt.Errorf(messages.ConfigSkillInvalidFrontMatterTypeFmt, parseErr.Detail)
+			return i18n.Errorf(messages.ConfigSkillInvalidFrontMatterTypeFmt, parseErr.Detail)
 		}
 	}
-	return fmt.Errorf(messages.ConfigSkillInvalidFrontMatterFmt, err)
+	return i18n.Errorf(messages.ConfigSkillInvalidFrontMatterFmt, err)
 }
 
 // skillFieldValue maps a structural field to the config policy view: absent
@@ -274,11 +300,11 @@ func skillFieldValue(field skillfrontmatter.Field) *string {
 
 func parseSkillDescription(description *string) (string, error) {
 	if description == nil {
-		return "", fmt.Errorf(messages.ConfigSkillMissingDescription)
+		return "", i18n.Errorf(messages.ConfigSkillMissingDescription)
 	}
 	normalized := strings.TrimSpace(*description)
 	if normalized == "" {
-		return "", fmt.Errorf(messages.ConfigSkillDescriptionEmpty)
+		return "", i18n.Errorf(messages.ConfigSkillDescriptionEmpty)
 	}
 	return normalized, nil
 }
@@ -289,10 +315,28 @@ func parseSkillName(name *string, multiline bool) (string, error) {
 	}
 	normalized := strings.TrimSpace(*name)
 	if normalized == "" {
-		return "", fmt.Errorf(messages.ConfigSkillNameEmpty)
+		return "", i18n.Errorf(messages.ConfigSkillNameEmpty)
 	}
 	if multiline || strings.Contains(normalized, "\n") {
-		return "", fmt.Errorf(messages.ConfigSkillNameInvalidMultiline)
+		return "", i18n.Errorf(messages.ConfigSkillNameInvalidMultiline)
+	}
+	return normalized, nil
+}
+
+// parseSkillRequires trims each required skill name and drops repeats.
+func parseSkillRequires(requires []string) ([]string, error) {
+	if len(requires) == 0 {
+		return nil, nil
+	}
+	normalized := make([]string, 0, len(requires))
+	for _, name := range requires {
+		name = normalizeSkillName(name)
+		if name == "" {
+			return nil, i18n.Errorf(messages.ConfigSkillRequiresEmpty)
+		}
+		if !slices.Contains(normalized, name) {
+			normalized = append(normalized, name)
+		}
 	}
 	return normalized, nil
 }
@@ -364,6 +364,7 @@ description: desc
 license: "  MIT  "
 compatibility: "  needs docker  "
 allowed-tools: "  Bash(git:*) Read  "
+enabled_when: '  file_exists("go.mod")  '
 metadata:
   owner: team
 ---
@@ -372,11 +373,26 @@ metadata:
 	if err != nil {
 		t.Fatalf("parseSkill error: %v", err)
 	}
-	if parsed.license != "MIT" || parsed.compatibility != "needs docker" || parsed.allowedTools != "Bash(git:*) Read" {
+	if parsed.license != "MIT" || parsed.compatibility != "needs docker" || parsed.allowedTools != "Bash(git:*) Read" || parsed.enabledWhen != `file_exists("go.mod")` {
 		t.Fatalf("unexpected optional values: %#v", parsed)
 	}
 }
 
+func TestParseSkill_Requires(t *testing.T) {
+	parsed, err := parseSkill("---\ndescription: desc\nrequires: [\" setup \", lint, setup]\n---\n")
+	if err != nil {
+		t.Fatalf("parseSkill error: %v", err)
+	}
+	if strings.Join(parsed.requires, ",") != "setup,lint" {
+		t.Fatalf("requires = %#v, want [setup lint]", parsed.requires)
+	}
+
+	_, err = parseSkill("---\ndescription: desc\nrequires: [\"\"]\n---\n")
+	if err == nil || !strings.Contains(err.Error(), messages.ConfigSkillRequiresEmpty) {
+		t.Fatalf("expected empty-requires error, got %v", err)
+	}
+}
+
 func TestParseSkill_FoldedAndLiteralDescriptions(t *testing.T) {
 	folded, err := parseSkill(`---
 description: >-
@@ -14,12 +14,108 @@ const DefaultDispatchMaxDepth = 3
 
 // Config is the root configuration loaded from .agent-layer/config.toml.
 type Config struct {
-	Approvals     ApprovalsConfig     `toml:"approvals"`
-	Agents        AgentsConfig        `toml:"agents"`
-	Dispatch      DispatchLimits      `toml:"dispatch"`
-	MCP           MCPConfig           `toml:"mcp"`
-	Notifications NotificationsConfig `toml:"notifications"`
-	Warnings      WarningsConfig      `toml:"warnings"`
+	// Extends names a shared base bundle (host/owner/repo[/subdir]@ref) whose
+	// config, instructions, skills, and commands allowlist are layered beneath
+	// the local repo. ExtendsChecksum optionally pins the bundle contents.
+	Extends         string `toml:"extends"`
+	ExtendsChecksum string `toml:"extends_checksum"`
+	// Language selects the locale for Agent Layer's own output, such as "de"
+	// or "pt-BR". AL_LANG overrides it; empty means English.
+	Language  string          `toml:"language"`
+	Approvals ApprovalsConfig `toml:"approvals"`
+	Agents    AgentsConfig    `toml:"agents"`
+	// Clients holds per-client launch settings keyed by the `al <client>`
+	// name (antigravity, claude, codex, copilot, vscode).
+	Clients       map[string]ClientConfig `toml:"clients"`
+	Dispatch      DispatchLimits          `toml:"dispatch"`
+	Hooks         HooksConfig             `toml:"hooks"`
+	Launch        LaunchConfig            `toml:"launch"`
+	MCP           MCPConfig               `toml:"mcp"`
+	Monorepo      MonorepoConfig          `toml:"monorepo"`
+	Notifications NotificationsConfig     `toml:"notifications"`
+	// Ownership overrides template ownership per repo-relative path. A path
+	// set to "user" is never overwritten or removed by upgrades; a directory
+	// covers every file beneath it.
+	Ownership map[string]string `toml:"ownership"`
+	Plugins   PluginsConfig     `toml:"plugins"`
+	Upgrade   UpgradeConfig     `toml:"upgrade"`
+	Variants  VariantsConfig    `toml:"variants"`
+	Warnings  WarningsConfig    `toml:"warnings"`
+	Worktree  WorktreeConfig    `toml:"worktree"`
+
+	// Deprecated lists legacy keys found by ParseConfigLenient so repair
+	// tools can warn about them. It is never read from TOML and is empty for
+	// strictly parsed configs, which reject legacy keys outright.
+	Deprecated []Deprecation `toml:"-"`
+}
+
+// MonorepoConfig controls generation of directory-scoped instructions.
+type MonorepoConfig struct {
+	// Mode is "full" (default) or "sparse". Sparse mode only generates scoped
+	// instructions for directories the current user works in.
+	Mode string `toml:"mode"`
+	// Owners maps an owner name to the repo-relative directories it works in.
+	// AL_MONOREPO_OWNER selects the entry used in sparse mode.
+	Owners map[string][]string `toml:"owners"`
+}
+
+// ClientConfig holds settings for one client launched by `al <client>`.
+type ClientConfig struct {
+	Launch ClientLaunchConfig `toml:"launch"`
+	// Workspace, for vscode only, makes sync maintain
+	// .vscode/agent-layer.code-workspace and `al vscode` open it instead of
+	// the repo folder. It is explicit opt-in: only true enables it. Read via
+	// VSCodeWorkspaceEnabled.
+	Workspace *bool `toml:"workspace"`
+}
+
+// ClientLaunchConfig adjusts how `al <client>` starts the client.
+type ClientLaunchConfig struct {
+	// Env is exported to the client process and overrides the inherited
+	// environment. Values may use ${VAR} placeholders from .agent-layer/.env.
+	Env map[string]string `toml:"env"`
+	// PreLaunch lists command lines run from the repo root, in order, after
+	// sync and before the client starts. Like [hooks], each must match a
+	// commands.allow prefix.
+	PreLaunch []string `toml:"pre_launch"`
+}
+
+// HooksConfig lists commands run around a sync that writes outputs. Each
+// entry is a command line split into arguments by SplitCommandLine and run
+// from the repo root without a shell; it must match a commands.allow prefix.
+type HooksConfig struct {
+	// PreSync runs before sync reads the sources, so a hook can regenerate a
+	// file that instructions or skills include.
+	PreSync []string `toml:"pre_sync"`
+	// PostSync runs after sync writes the outputs.
+	PostSync []string `toml:"post_sync"`
+}
+
+// PluginsConfig declares external plugins: executables named al-<name> on
+// PATH, or at the path Paths gives.
+type PluginsConfig struct {
+	// Renderers lists the plugins sync runs as client renderers, in addition
+	// to the built-in clients. Like [hooks], each plugin's command must match
+	// a commands.allow prefix.
+	Renderers []string `toml:"renderers"`
+	// Paths maps a plugin name to its executable, relative to the repo root
+	// or absolute, in place of al-<name> on PATH.
+	Paths map[string]string `toml:"paths"`
+}
+
+// UpgradeConfig controls the checks `al upgrade` runs after it applies
+// templates and migrations.
+type UpgradeConfig struct {
+	// Verify lists commands run from the repo root after the post-upgrade
+	// sync and its check. A command that exits non-zero fails the upgrade.
+	Verify []UpgradeVerifyCommand `toml:"verify"`
+}
+
+// UpgradeVerifyCommand is one post-upgrade verification command. Args are
+// passed as-is, without a shell.
+type UpgradeVerifyCommand struct {
+	Command string   `toml:"command"`
+	Args    []string `toml:"args"`
 }
 
 // ApprovalsConfig controls auto-approval behavior per client.
@@ -103,10 +199,30 @@ type CodexConfig struct {
 	// CodexStatuslineEnabled.
 	Statusline    *bool               `toml:"statusline"`
 	AgentSpecific ProviderPassthrough `toml:"agent_specific"`
+	// Profiles are projected as [profiles.<name>] tables in
+	// .codex/config.toml, selectable with `codex --profile <name>`.
+	Profiles map[string]CodexProfile `toml:"profiles"`
+}
+
+// CodexProfile holds the settings for one named Codex profile. Empty fields
+// are omitted so Codex falls back to the top-level value.
+type CodexProfile struct {
+	Model           string `toml:"model"`
+	ReasoningEffort string `toml:"reasoning_effort"`
+	// ApprovalPolicy is a Codex approval_policy value: untrusted,
+	// on-failure, on-request, or never.
+	ApprovalPolicy string `toml:"approval_policy"`
+	// SandboxMode is a Codex sandbox_mode value: read-only,
+	// workspace-write, or danger-full-access.
+	SandboxMode string `toml:"sandbox_mode"`
 }
 
 // MCPConfig contains the external MCP servers configuration.
 type MCPConfig struct {
+	// Gateway projects a single `al mcp gateway` server to clients in place of
+	// the individual servers. It is explicit opt-in: only true enables it. Read
+	// via GatewayEnabled.
+	Gateway *bool       `toml:"gateway"`
 	Servers []MCPServer `toml:"servers"`
 }
 
@@ -124,9 +240,12 @@ type WarningsConfig struct {
 
 // MCPServer defines a single MCP server entry.
 type MCPServer struct {
-	ID            string            `toml:"id"`
-	Enabled       *bool             `toml:"enabled"`
-	Clients       []string          `toml:"clients"`
+	ID      string   `toml:"id"`
+	Enabled *bool    `toml:"enabled"`
+	Clients []string `toml:"clients"`
+	// Agents is an alternative to Clients that uses [agents.*] names (for
+	// example copilot_cli). Validation folds it into Clients.
+	Agents        []string          `toml:"agents"`
 	Transport     string            `toml:"transport"`
 	HTTPTransport string            `toml:"http_transport"`
 	URL           string            `toml:"url"`
@@ -134,6 +253,10 @@ type MCPServer struct {
 	Command       string            `toml:"command"`
 	Args          []string          `toml:"args"`
 	Env           map[string]string `toml:"env"`
+	// ToolsAllow, when set, limits the server to the named tools. ToolsDeny
+	// hides the named tools and applies after ToolsAllow.
+	ToolsAllow []string `toml:"tools_allow"`
+	ToolsDeny  []string `toml:"tools_deny"`
 }
 
 // IsAgentEnabled returns true if the agent-enabled pointer is non-nil and true.
@@ -147,6 +270,13 @@ func ClaudeStatuslineEnabled(c ClaudeConfig) bool {
 	return c.Statusline != nil && *c.Statusline
 }
 
+// VSCodeWorkspaceEnabled reports whether sync should maintain the VS Code
+// workspace file. It is explicit opt-in: only true enables it.
+func VSCodeWorkspaceEnabled(c Config) bool {
+	workspace := c.Clients["vscode"].Workspace
+	return workspace != nil && *workspace
+}
+
 // CodexStatuslineEnabled reports whether the Codex status line should be wired.
 // It is explicit opt-in: only true enables it.
 func CodexStatuslineEnabled(c CodexConfig) bool {
@@ -220,9 +350,13 @@ type Skill struct {
 	Compatibility string
 	Metadata      map[string]string
 	AllowedTools  string
+	Requires      []string // Names of skills this skill builds on, from front matter "requires"
+	EnabledWhen   string   // Condition sync evaluates to decide whether to project the skill; empty means always
 	Body          string
 	SourcePath    string
 	SourceDir     string // Absolute path to the skill directory (parent of SKILL.md)
+	Variant       string // Empty for the base skill; v2 for skills/<name>@v2
+	Scope         string // Empty for project skills; SkillScopeUser for user-global skills
 }
 
 // ProjectConfig is the fully loaded configuration state for sync and launch.
@@ -232,5 +366,19 @@ type ProjectConfig struct {
 	Instructions  []InstructionFile
 	Skills        []Skill
 	CommandsAllow []string
-	Root          string
+	// InstructionVariants and SkillVariants hold every loaded file, including
+	// name@variant alternatives. Instructions and Skills are the default
+	// selection from [variants]; see ForVariantClient.
+	InstructionVariants []InstructionFile
+	SkillVariants       []Skill
+	// VariantProfile is the [variants.profiles] entry AL_VARIANT_PROFILE
+	// selected, or empty.
+	VariantProfile string
+	// ScopedInstructions are per-directory and per-glob instructions from
+	// .agent-layer/scoped/.
+	ScopedInstructions []ScopedInstructions
+	Root               string
+	// ExtendsDir is the resolved local directory of the extends base bundle;
+	// empty when the config does not extend a base.
+	ExtendsDir string
 }
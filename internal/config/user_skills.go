@@ -0,0 +1,89 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/conn-castle/agent-layer/internal/i18n"
+	"github.com/conn-castle/agent-layer/internal/messages"
+)
+
+// SkillScopeUser marks a skill loaded from the user-global skills directory
+// rather than the repo. Project skills leave Skill.Scope empty.
+const SkillScopeUser = "user"
+
+// userConfigHome resolves the user's config directory. Tests replace it to
+// keep the developer's real personal skills out of project loads.
+var userConfigHome = func() (string, error) {
+	if value := strings.TrimSpace(os.Getenv("XDG_CONFIG_HOME")); value != "" {
+		return value, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".config"), nil
+}
+
+// UserSkillsDir returns the user-global skills directory:
+// $XDG_CONFIG_HOME/agent-layer/skills, defaulting to ~/.config/agent-layer/skills.
+func UserSkillsDir() (string, error) {
+	base, err := userConfigHome()
+	if err != nil {
+		return "", i18n.Errorf(messages.ConfigUserSkillsDirFmt, err)
+	}
+	return filepath.Join(base, "agent-layer", "skills"), nil
+}
+
+// LoadUserSkills reads the user-global skills directory with the same rules
+// as .agent-layer/skills and marks every skill SkillScopeUser. A missing
+// directory, or a home directory that cannot be resolved, yields no skills.
+func LoadUserSkills() ([]Skill, error) {
+	dir, err := UserSkillsDir()
+	if err != nil {
+		return nil, nil
+	}
+	if _, err := os.Stat(dir); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, i18n.Errorf(messages.ConfigMissingSkillsDirFmt, dir, err)
+	}
+	skills, err := LoadSkills(dir)
+	if err != nil {
+		return nil, err
+	}
+	for i := range skills {
+		skills[i].Scope = SkillScopeUser
+	}
+	return skills, nil
+}
+
+// mergeUserSkills returns project skills plus user skills whose names the
+// project does not define; a project skill always wins a name clash so a
+// repo behaves the same for every contributor who shares it.
+func mergeUserSkills(project []Skill, user []Skill) []Skill {
+	if len(user) == 0 {
+		return project
+	}
+	byName := make(map[string]Skill, len(project)+len(user))
+	for _, skill := range user {
+		byName[skillKey(skill)] = skill
+	}
+	for _, skill := range project {
+		byName[skillKey(skill)] = skill
+	}
+	names := make([]string, 0, len(byName))
+	for name := range byName {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	merged := make([]Skill, 0, len(names))
+	for _, name := range names {
+		merged = append(merged, byName[name])
+	}
+	return merged
+}
@@ -0,0 +1,124 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeUserSkillForTest(t *testing.T, dir string, name string, description string) {
+	t.Helper()
+	skillDir := filepath.Join(dir, name)
+	if err := os.MkdirAll(skillDir, 0o700); err != nil {
+		t.Fatalf("mkdir skill: %v", err)
+	}
+	content := "---\ndescription: " + description + "\n---\n\nBody of " + name + "."
+	if err := os.WriteFile(filepath.Join(skillDir, "SKILL.md"), []byte(content), 0o600); err != nil {
+		t.Fatalf("write skill: %v", err)
+	}
+}
+
+func TestUserSkillsDir(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", "/xdg")
+	dir, err := UserSkillsDir()
+	if err != nil {
+		t.Fatalf("UserSkillsDir: %v", err)
+	}
+	if dir != filepath.Join("/xdg", "agent-layer", "skills") {
+		t.Fatalf("unexpected dir %q", dir)
+	}
+
+	t.Setenv("XDG_CONFIG_HOME", "")
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	dir, err = UserSkillsDir()
+	if err != nil {
+		t.Fatalf("UserSkillsDir: %v", err)
+	}
+	if dir != filepath.Join(home, ".config", "agent-layer", "skills") {
+		t.Fatalf("unexpected dir %q", dir)
+	}
+
+	original := userConfigHome
+	userConfigHome = func() (string, error) { return "", errors.New("no home") }
+	t.Cleanup(func() { userConfigHome = original })
+	if _, err := UserSkillsDir(); err == nil || !strings.Contains(err.Error(), "no home") {
+		t.Fatalf("expected resolve error, got %v", err)
+	}
+	skills, err := LoadUserSkills()
+	if err != nil || skills != nil {
+		t.Fatalf("expected no user skills without a home, got %v, %v", skills, err)
+	}
+}
+
+func TestLoadUserSkills(t *testing.T) {
+	configHome := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", configHome)
+
+	skills, err := LoadUserSkills()
+	if err != nil || skills != nil {
+		t.Fatalf("expected no skills for a missing directory, got %v, %v", skills, err)
+	}
+
+	dir := filepath.Join(configHome, "agent-layer", "skills")
+	writeUserSkillForTest(t, dir, "journal", "personal journal")
+	skills, err = LoadUserSkills()
+	if err != nil {
+		t.Fatalf("LoadUserSkills: %v", err)
+	}
+	if len(skills) != 1 || skills[0].Name != "journal" || skills[0].Scope != SkillScopeUser {
+		t.Fatalf("expected one user-scope skill, got %#v", skills)
+	}
+
+	if err := os.MkdirAll(filepath.Join(dir, "broken"), 0o700); err != nil {
+		t.Fatalf("mkdir broken: %v", err)
+	}
+	if _, err := LoadUserSkills(); err == nil {
+		t.Fatalf("expected an invalid user skill to fail loudly")
+	}
+}
+
+func TestLoadProjectConfig_MergesUserSkills(t *testing.T) {
+	configHome := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", configHome)
+	userDir := filepath.Join(configHome, "agent-layer", "skills")
+	writeUserSkillForTest(t, userDir, "journal", "personal journal")
+	writeUserSkillForTest(t, userDir, "hello", "user hello")
+
+	root := t.TempDir()
+	paths := DefaultPaths(root)
+	for _, dir := range []string{paths.InstructionsDir, paths.SkillsDir} {
+		if err := os.MkdirAll(dir, 0o700); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+	}
+	config := "[approvals]\nmode = \"all\"\n\n[agents.antigravity]\nenabled = false\n\n[agents.claude]\nenabled = true\n\n[agents.claude_vscode]\nenabled = false\n\n[agents.codex]\nenabled = false\n\n[agents.vscode]\nenabled = false\n\n[agents.copilot_cli]\nenabled = false\n"
+	for path, content := range map[string]string{
+		paths.ConfigPath:    config,
+		paths.EnvPath:       "",
+		paths.CommandsAllow: "",
+		filepath.Join(paths.InstructionsDir, "00_rules.md"): "rules",
+	} {
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatalf("write %s: %v", path, err)
+		}
+	}
+	writeUserSkillForTest(t, paths.SkillsDir, "hello", "project hello")
+
+	project, err := LoadProjectConfig(root)
+	if err != nil {
+		t.Fatalf("LoadProjectConfig: %v", err)
+	}
+	if len(project.Skills) != 2 {
+		t.Fatalf("expected project and user skills merged, got %#v", project.Skills)
+	}
+	hello, journal := project.Skills[0], project.Skills[1]
+	if hello.Name != "hello" || hello.Description != "project hello" || hello.Scope != "" {
+		t.Fatalf("expected the project skill to win a name clash, got %#v", hello)
+	}
+	if journal.Name != "journal" || journal.Scope != SkillScopeUser {
+		t.Fatalf("expected user-scope journal skill, got %#v", journal)
+	}
+}
@@ -2,8 +2,12 @@ package config
 
 import (
 	"fmt"
+	"maps"
+	"regexp"
+	"slices"
 	"strings"
 
+	"github.com/conn-castle/agent-layer/internal/i18n"
 	"github.com/conn-castle/agent-layer/internal/messages"
 )
 
@@ -39,6 +43,39 @@ var validClients = map[string]struct{}{
 	"copilot":        {},
 }
 
+// agentClients maps [agents.*] names accepted in mcp.servers[].agents to the
+// client that receives the server's projection.
+var agentClients = map[string]string{
+	agentAntigravity: agentAntigravity,
+	agentClaude:      agentClaude,
+	"claude_vscode":  agentClaude,
+	agentCodex:       agentCodex,
+	"vscode":         "vscode",
+	"copilot_cli":    "copilot",
+}
+
+// MCPClients returns the sorted client names accepted by mcp.servers[].clients.
+func MCPClients() []string {
+	return slices.Sorted(maps.Keys(validClients))
+}
+
+// pluginNamePattern matches plugin names: the <name> in al-<name>.
+var pluginNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]*$`)
+
+// ValidPluginName reports whether name can name a plugin: lowercase letters,
+// digits, and hyphens, starting with a letter or digit.
+func ValidPluginName(name string) bool {
+	return pluginNamePattern.MatchString(name)
+}
+
+func isMCPClient(name string) bool {
+	_, ok := validClients[name]
+	return ok
+}
+
+// envVarNamePattern matches the variable names accepted in launch env.
+var envVarNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
 var validHTTPTransports = map[string]struct{}{
 	"sse":        {},
 	"streamable": {},
@@ -52,37 +89,55 @@ var validWarningNoiseModes = map[string]struct{}{
 }
 
 // Validate ensures the config is complete and consistent.
+// It returns the first problem found; use ValidationErrors to collect them all.
 func (c *Config) Validate(path string) error {
-	if !isValidApprovalMode(c.Approvals.Mode) {
-		return fmt.Errorf(messages.ConfigApprovalsModeInvalidFmt, path)
+	if errs := c.ValidationErrors(path); len(errs) > 0 {
+		return errs[0]
 	}
+	return nil
+}
 
-	if c.Agents.Antigravity.Enabled == nil {
-		return fmt.Errorf(messages.ConfigAntigravityEnabledRequiredFmt, path)
+// ValidationErrors checks the config like Validate but keeps going after a
+// failure, returning every problem in check order. Like Validate, it
+// normalizes MCP servers in place (stripping transport-irrelevant fields and
+// resolving agents to clients).
+func (c *Config) ValidationErrors(path string) []error {
+	var errs []error
+	if err := validateExtends(path, c.Extends, c.ExtendsChecksum); err != nil {
+		errs = append(errs, err)
 	}
-	if c.Agents.Claude.Enabled == nil {
-		return fmt.Errorf(messages.ConfigClaudeEnabledRequiredFmt, path)
+	if c.Language != "" && !i18n.Valid(c.Language) {
+		errs = append(errs, i18n.Errorf(messages.ConfigLanguageInvalidFmt, path, c.Language))
 	}
-	if c.Agents.ClaudeVSCode.Enabled == nil {
-		return fmt.Errorf(messages.ConfigClaudeVSCodeEnabledRequiredFmt, path)
-	}
-	if c.Agents.Codex.Enabled == nil {
-		return fmt.Errorf(messages.ConfigCodexEnabledRequiredFmt, path)
+	if !isValidApprovalMode(c.Approvals.Mode) {
+		errs = append(errs, i18n.Errorf(messages.ConfigApprovalsModeInvalidFmt, path))
 	}
-	if c.Agents.VSCode.Enabled == nil {
-		return fmt.Errorf(messages.ConfigVSCodeEnabledRequiredFmt, path)
+
+	requiredEnabled := []struct {
+		enabled *bool
+		format  string
+	}{
+		{c.Agents.Antigravity.Enabled, messages.ConfigAntigravityEnabledRequiredFmt},
+		{c.Agents.Claude.Enabled, messages.ConfigClaudeEnabledRequiredFmt},
+		{c.Agents.ClaudeVSCode.Enabled, messages.ConfigClaudeVSCodeEnabledRequiredFmt},
+		{c.Agents.Codex.Enabled, messages.ConfigCodexEnabledRequiredFmt},
+		{c.Agents.VSCode.Enabled, messages.ConfigVSCodeEnabledRequiredFmt},
+		{c.Agents.CopilotCLI.Enabled, messages.ConfigCopilotCLIEnabledRequiredFmt},
 	}
-	if c.Agents.CopilotCLI.Enabled == nil {
-		return fmt.Errorf(messages.ConfigCopilotCLIEnabledRequiredFmt, path)
+	for _, required := range requiredEnabled {
+		if required.enabled == nil {
+			errs = append(errs, fmt.Errorf(required.format, path))
+		}
 	}
 	if err := validateAntigravityModelSource(path, c.Agents.Antigravity); err != nil {
-		return err
+		errs = append(errs, err)
 	}
 	if strings.TrimSpace(c.Agents.CopilotCLI.ReasoningEffort) != "" {
-		return fmt.Errorf(messages.ConfigCopilotCLIReasoningEffortUnsupportedFmt, path)
+		errs = append(errs, i18n.Errorf(messages.ConfigCopilotCLIReasoningEffortUnsupportedFmt, path))
 	}
+	errs = append(errs, validateCodexProfiles(path, c.Agents.Codex)...)
 	if c.Dispatch.MaxDepth != nil && *c.Dispatch.MaxDepth <= 0 {
-		return fmt.Errorf(messages.ConfigDispatchMaxDepthInvalidFmt, path)
+		errs = append(errs, i18n.Errorf(messages.ConfigDispatchMaxDepthInvalidFmt, path))
 	}
 
 	// Model and reasoning-effort validation: agent model values
@@ -98,58 +153,178 @@ func (c *Config) Validate(path string) error {
 	// all, so a value there is rejected outright.)
 
 	seenServerIDs := make(map[string]int, len(c.MCP.Servers))
-	for i, server := range c.MCP.Servers {
-		if server.ID == "" {
-			return fmt.Errorf(messages.ConfigMcpServerIDRequiredFmt, path, i)
+	for i := range c.MCP.Servers {
+		errs = append(errs, c.validateMCPServer(path, i, seenServerIDs)...)
+	}
+
+	errs = append(errs, validateWarnings(path, c.Warnings)...)
+	if err := validateMonorepo(path, c.Monorepo); err != nil {
+		errs = append(errs, err)
+	}
+	if err := validateLaunch(path, c.Launch); err != nil {
+		errs = append(errs, err)
+	}
+	if err := validateWorktree(path, c.Worktree); err != nil {
+		errs = append(errs, err)
+	}
+	errs = append(errs, validateClients(path, c.Clients)...)
+	errs = append(errs, validateHooks(path, "pre_sync", c.Hooks.PreSync)...)
+	errs = append(errs, validateHooks(path, "post_sync", c.Hooks.PostSync)...)
+	errs = append(errs, validatePlugins(path, c.Plugins)...)
+	for i, verify := range c.Upgrade.Verify {
+		if strings.TrimSpace(verify.Command) == "" {
+			errs = append(errs, i18n.Errorf(messages.ConfigUpgradeVerifyCommandRequiredFmt, path, i))
 		}
-		if server.ID == agentLayerServerID {
-			return fmt.Errorf(messages.ConfigMcpServerIDReservedFmt, path, i)
+	}
+	errs = append(errs, validateOwnership(path, c.Ownership)...)
+	errs = append(errs, validateVariants(path, c.Variants)...)
+
+	return errs
+}
+
+// validateClients checks [clients.<name>] for known client names, workspace
+// only on vscode, valid launch env variable names, and non-empty, parsable
+// pre_launch commands.
+func validateClients(path string, clients map[string]ClientConfig) []error {
+	var errs []error
+	for _, name := range slices.Sorted(maps.Keys(clients)) {
+		if _, ok := validClients[name]; !ok {
+			errs = append(errs, i18n.Errorf(messages.ConfigClientUnknownFmt, path, name, strings.Join(MCPClients(), ", ")))
+			continue
 		}
-		if firstIndex, ok := seenServerIDs[server.ID]; ok {
-			return fmt.Errorf(messages.ConfigMcpServerIDDuplicateFmt, path, i, server.ID, firstIndex)
-		}
-		seenServerIDs[server.ID] = i
-		if server.Enabled == nil {
-			return fmt.Errorf(messages.ConfigMcpServerEnabledRequiredFmt, path, i)
-		}
-		switch server.Transport {
-		case TransportHTTP:
-			// Silently strip stdio-only fields; they are meaningless for HTTP.
-			c.MCP.Servers[i].Command = ""
-			c.MCP.Servers[i].Args = nil
-			c.MCP.Servers[i].Env = nil
-			if server.URL == "" {
-				return fmt.Errorf(messages.ConfigMcpServerURLRequiredFmt, path, i)
+		if clients[name].Workspace != nil && name != "vscode" {
+			errs = append(errs, i18n.Errorf(messages.ConfigClientWorkspaceUnsupportedFmt, path, name))
+		}
+		launch := clients[name].Launch
+		for _, key := range slices.Sorted(maps.Keys(launch.Env)) {
+			if !envVarNamePattern.MatchString(key) {
+				errs = append(errs, i18n.Errorf(messages.ConfigClientLaunchEnvKeyInvalidFmt, path, name, key))
 			}
-			if server.HTTPTransport != "" {
-				if _, ok := validHTTPTransports[server.HTTPTransport]; !ok {
-					return fmt.Errorf(messages.ConfigMcpServerHTTPTransportInvalidFmt, path, i)
-				}
+		}
+		for i, hook := range launch.PreLaunch {
+			if strings.TrimSpace(hook) == "" {
+				errs = append(errs, i18n.Errorf(messages.ConfigClientPreLaunchRequiredFmt, path, name, i))
+			} else if _, err := SplitCommandLine(hook); err != nil {
+				errs = append(errs, i18n.Errorf(messages.ConfigClientPreLaunchInvalidFmt, path, name, i, hook, err))
 			}
-		case TransportStdio:
-			// Silently strip HTTP-only fields; they are meaningless for stdio.
-			c.MCP.Servers[i].HTTPTransport = ""
-			c.MCP.Servers[i].URL = ""
-			c.MCP.Servers[i].Headers = nil
-			if server.Command == "" {
-				return fmt.Errorf(messages.ConfigMcpServerCommandRequiredFmt, path, i)
+		}
+	}
+	return errs
+}
+
+// validateHooks rejects empty or unparsable command lines in hooks.<phase>.
+func validateHooks(path string, phase string, hooks []string) []error {
+	var errs []error
+	for i, hook := range hooks {
+		if strings.TrimSpace(hook) == "" {
+			errs = append(errs, i18n.Errorf(messages.ConfigHookCommandRequiredFmt, path, phase, i))
+		} else if _, err := SplitCommandLine(hook); err != nil {
+			errs = append(errs, i18n.Errorf(messages.ConfigHookCommandInvalidFmt, path, phase, i, hook, err))
+		}
+	}
+	return errs
+}
+
+// validatePlugins checks plugin names in plugins.renderers and plugins.paths.
+// Renderer plugins may not reuse a built-in client name, since sync summaries
+// and stale-output cleanup key on it.
+func validatePlugins(path string, plugins PluginsConfig) []error {
+	var errs []error
+	seen := make(map[string]bool, len(plugins.Renderers))
+	for i, name := range plugins.Renderers {
+		switch {
+		case !ValidPluginName(name):
+			errs = append(errs, i18n.Errorf(messages.ConfigPluginNameInvalidFmt, path, i, name))
+		case isMCPClient(name):
+			errs = append(errs, i18n.Errorf(messages.ConfigPluginBuiltinClientFmt, path, i, name))
+		case seen[name]:
+			errs = append(errs, i18n.Errorf(messages.ConfigPluginDuplicateFmt, path, name))
+		}
+		seen[name] = true
+	}
+	for _, name := range slices.Sorted(maps.Keys(plugins.Paths)) {
+		if !ValidPluginName(name) {
+			errs = append(errs, i18n.Errorf(messages.ConfigPluginPathNameInvalidFmt, path, name))
+			continue
+		}
+		if strings.TrimSpace(plugins.Paths[name]) == "" {
+			errs = append(errs, i18n.Errorf(messages.ConfigPluginPathRequiredFmt, path, name))
+		}
+	}
+	return errs
+}
+
+// validateMCPServer checks mcp.servers[i] and normalizes it in place.
+// seenServerIDs records the first index of each ID for duplicate detection.
+func (c *Config) validateMCPServer(path string, i int, seenServerIDs map[string]int) []error {
+	var errs []error
+	server := c.MCP.Servers[i]
+	switch {
+	case server.ID == "":
+		errs = append(errs, i18n.Errorf(messages.ConfigMcpServerIDRequiredFmt, path, i))
+	case server.ID == agentLayerServerID:
+		errs = append(errs, i18n.Errorf(messages.ConfigMcpServerIDReservedFmt, path, i))
+	default:
+		if firstIndex, ok := seenServerIDs[server.ID]; ok {
+			errs = append(errs, i18n.Errorf(messages.ConfigMcpServerIDDuplicateFmt, path, i, server.ID, firstIndex))
+		} else {
+			seenServerIDs[server.ID] = i
+		}
+	}
+	if server.Enabled == nil {
+		errs = append(errs, i18n.Errorf(messages.ConfigMcpServerEnabledRequiredFmt, path, i))
+	}
+	switch server.Transport {
+	case TransportHTTP:
+		// Silently strip stdio-only fields; they are meaningless for HTTP.
+		c.MCP.Servers[i].Command = ""
+		c.MCP.Servers[i].Args = nil
+		c.MCP.Servers[i].Env = nil
+		if server.URL == "" {
+			errs = append(errs, i18n.Errorf(messages.ConfigMcpServerURLRequiredFmt, path, i))
+		}
+		if server.HTTPTransport != "" {
+			if _, ok := validHTTPTransports[server.HTTPTransport]; !ok {
+				errs = append(errs, i18n.Errorf(messages.ConfigMcpServerHTTPTransportInvalidFmt, path, i))
 			}
-		default:
-			return fmt.Errorf(messages.ConfigMcpServerTransportInvalidFmt, path, i)
 		}
+	case TransportStdio:
+		// Silently strip HTTP-only fields; they are meaningless for stdio.
+		c.MCP.Servers[i].HTTPTransport = ""
+		c.MCP.Servers[i].URL = ""
+		c.MCP.Servers[i].Headers = nil
+		if server.Command == "" {
+			errs = append(errs, i18n.Errorf(messages.ConfigMcpServerCommandRequiredFmt, path, i))
+		}
+	default:
+		errs = append(errs, i18n.Errorf(messages.ConfigMcpServerTransportInvalidFmt, path, i))
+	}
 
+	clientsResolved := true
+	if len(server.Agents) > 0 {
+		clients, err := agentsToClients(path, i, server)
+		if err != nil {
+			errs = append(errs, err)
+			clientsResolved = false
+		} else {
+			c.MCP.Servers[i].Clients = clients
+			server.Clients = clients
+		}
+	}
+	if clientsResolved {
 		for _, client := range server.Clients {
 			if _, ok := validClients[client]; !ok {
-				return fmt.Errorf(messages.ConfigMcpServerClientInvalidFmt, path, i, client)
+				errs = append(errs, i18n.Errorf(messages.ConfigMcpServerClientInvalidFmt, path, i, client))
 			}
 		}
 	}
-
-	if err := validateWarnings(path, c.Warnings); err != nil {
-		return err
+	if err := validateToolNames(path, i, "tools_allow", server.ToolsAllow); err != nil {
+		errs = append(errs, err)
 	}
-
-	return nil
+	if err := validateToolNames(path, i, "tools_deny", server.ToolsDeny); err != nil {
+		errs = append(errs, err)
+	}
+	return errs
 }
 
 func validateAntigravityModelSource(path string, cfg AntigravityConfig) error {
@@ -160,11 +335,12 @@ func validateAntigravityModelSource(path string, cfg AntigravityConfig) error {
 }
 
 // validateWarnings validates optional warning thresholds.
-// path is used for error context; warnings carries the thresholds; returns an error when a threshold is non-positive.
-func validateWarnings(path string, warnings WarningsConfig) error {
+// path is used for error context; warnings carries the thresholds; returns an error for an unknown noise mode and for each non-positive threshold.
+func validateWarnings(path string, warnings WarningsConfig) []error {
+	var errs []error
 	mode := strings.ToLower(strings.TrimSpace(warnings.NoiseMode))
 	if _, ok := validWarningNoiseModes[mode]; !ok {
-		return fmt.Errorf(messages.ConfigWarningNoiseModeInvalidFmt, path, warnings.NoiseMode)
+		errs = append(errs, i18n.Errorf(messages.ConfigWarningNoiseModeInvalidFmt, path, warnings.NoiseMode))
 	}
 
 	thresholds := []struct {
@@ -180,8 +356,37 @@ func validateWarnings(path string, warnings WarningsConfig) error {
 	}
 	for _, threshold := range thresholds {
 		if threshold.value != nil && *threshold.value <= 0 {
-			return fmt.Errorf(messages.ConfigWarningThresholdInvalidFmt, path, threshold.name)
+			errs = append(errs, i18n.Errorf(messages.ConfigWarningThresholdInvalidFmt, path, threshold.name))
+		}
+	}
+	return errs
+}
+
+// validateToolNames rejects blank entries in an MCP server tool filter.
+func validateToolNames(path string, index int, field string, names []string) error {
+	for _, name := range names {
+		if strings.TrimSpace(name) == "" {
+			return i18n.Errorf(messages.ConfigMcpServerToolNameEmptyFmt, path, index, field)
 		}
 	}
 	return nil
 }
+
+// agentsToClients resolves mcp.servers[].agents to client names. Setting both
+// agents and clients is rejected because the two lists could disagree.
+func agentsToClients(path string, index int, server MCPServer) ([]string, error) {
+	if len(server.Clients) > 0 {
+		return nil, i18n.Errorf(messages.ConfigMcpServerAgentsAndClientsFmt, path, index)
+	}
+	clients := make([]string, 0, len(server.Agents))
+	for _, agent := range server.Agents {
+		client, ok := agentClients[agent]
+		if !ok {
+			return nil, i18n.Errorf(messages.ConfigMcpServerAgentInvalidFmt, path, index, agent)
+		}
+		if !slices.Contains(clients, client) {
+			clients = append(clients, client)
+		}
+	}
+	return clients, nil
+}
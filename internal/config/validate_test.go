@@ -1,6 +1,7 @@
 package config
 
 import (
+	"slices"
 	"strings"
 	"testing"
 )
@@ -99,6 +100,27 @@ func TestValidateConfigErrors(t *testing.T) {
 			}),
 			wantErr: "invalid client",
 		},
+		{
+			name: "invalid agent",
+			cfg: withServers(valid, []MCPServer{
+				{ID: "x", Enabled: &trueVal, Transport: "http", URL: "https://example.com", Agents: []string{"copilot"}},
+			}),
+			wantErr: `mcp.servers[0].agents contains invalid agent "copilot"`,
+		},
+		{
+			name: "agents and clients",
+			cfg: withServers(valid, []MCPServer{
+				{ID: "x", Enabled: &trueVal, Transport: "http", URL: "https://example.com", Agents: []string{"codex"}, Clients: []string{"codex"}},
+			}),
+			wantErr: "sets both agents and clients",
+		},
+		{
+			name: "empty tool filter name",
+			cfg: withServers(valid, []MCPServer{
+				{ID: "x", Enabled: &trueVal, Transport: "http", URL: "https://example.com", ToolsDeny: []string{"ok", " "}},
+			}),
+			wantErr: "mcp.servers[0].tools_deny contains an empty tool name",
+		},
 		{
 			name:    "missing copilot_cli enabled",
 			cfg:     withCopilotCLIEnabled(valid, nil),
@@ -121,6 +143,86 @@ func TestValidateConfigErrors(t *testing.T) {
 			cfg:     withCopilotCLIReasoning(valid, "high"),
 			wantErr: "agents.copilot_cli.reasoning_effort is not supported",
 		},
+		{
+			name:    "upgrade verify command missing",
+			cfg:     withUpgradeVerify(valid, UpgradeVerifyCommand{Command: "make"}, UpgradeVerifyCommand{Args: []string{"test"}}),
+			wantErr: "upgrade.verify[1].command is required",
+		},
+		{
+			name:    "post_sync hook empty",
+			cfg:     withHooks(valid, HooksConfig{PreSync: []string{"make context"}, PostSync: []string{"  "}}),
+			wantErr: "hooks.post_sync[0] is empty",
+		},
+		{
+			name:    "pre_sync hook unterminated quote",
+			cfg:     withHooks(valid, HooksConfig{PreSync: []string{`make "context`}}),
+			wantErr: `hooks.pre_sync[0] "make \"context" is not a valid command line: unterminated " quote`,
+		},
+		{
+			name:    "plugin renderer name invalid",
+			cfg:     withPlugins(valid, PluginsConfig{Renderers: []string{"Zed_IDE"}}),
+			wantErr: `plugins.renderers[0] "Zed_IDE" is not a valid plugin name`,
+		},
+		{
+			name:    "plugin renderer built-in client",
+			cfg:     withPlugins(valid, PluginsConfig{Renderers: []string{"claude"}}),
+			wantErr: `plugins.renderers[0] "claude" is a built-in client`,
+		},
+		{
+			name:    "plugin renderer duplicate",
+			cfg:     withPlugins(valid, PluginsConfig{Renderers: []string{"zed", "zed"}}),
+			wantErr: `plugins.renderers lists "zed" more than once`,
+		},
+		{
+			name:    "plugin path empty",
+			cfg:     withPlugins(valid, PluginsConfig{Paths: map[string]string{"zed": " "}}),
+			wantErr: "plugins.paths.zed is empty",
+		},
+		{
+			name:    "plugin path name invalid",
+			cfg:     withPlugins(valid, PluginsConfig{Paths: map[string]string{"-zed": "./al-zed"}}),
+			wantErr: "plugins.paths.-zed is not a valid plugin name",
+		},
+		{
+			name:    "launch client unknown",
+			cfg:     withClients(valid, map[string]ClientConfig{"cursor": {}}),
+			wantErr: "clients.cursor is not a launch client (expected one of antigravity, claude, codex, copilot, vscode)",
+		},
+		{
+			name:    "launch auto_sync invalid",
+			cfg:     withLaunch(valid, LaunchConfig{AutoSync: "sometimes"}),
+			wantErr: `launch.auto_sync "sometimes" is invalid`,
+		},
+		{
+			name:    "launch env key invalid",
+			cfg:     withClients(valid, map[string]ClientConfig{"claude": {Launch: ClientLaunchConfig{Env: map[string]string{"BAD-NAME": "x"}}}}),
+			wantErr: `clients.claude.launch.env key "BAD-NAME" is not a valid variable name`,
+		},
+		{
+			name:    "workspace on non-vscode client",
+			cfg:     withClients(valid, map[string]ClientConfig{"claude": {Workspace: &trueVal}}),
+			wantErr: "clients.claude.workspace is only supported for vscode",
+		},
+		{
+			name:    "pre_launch hook empty",
+			cfg:     withClients(valid, map[string]ClientConfig{"vscode": {Launch: ClientLaunchConfig{PreLaunch: []string{""}}}}),
+			wantErr: "clients.vscode.launch.pre_launch[0] is empty",
+		},
+		{
+			name:    "pre_launch hook unterminated quote",
+			cfg:     withClients(valid, map[string]ClientConfig{"vscode": {Launch: ClientLaunchConfig{PreLaunch: []string{"make 'context"}}}}),
+			wantErr: `clients.vscode.launch.pre_launch[0] "make 'context" is not a valid command line: unterminated ' quote`,
+		},
+		{
+			name:    "ownership path escapes repo",
+			cfg:     withOwnership(valid, map[string]string{"../outside.md": "user"}),
+			wantErr: "ownership: invalid directory",
+		},
+		{
+			name:    "ownership value invalid",
+			cfg:     withOwnership(valid, map[string]string{"docs/agent-layer/COMMANDS.md": "managed"}),
+			wantErr: `ownership."docs/agent-layer/COMMANDS.md" = "managed" is invalid`,
+		},
 	}
 
 	for _, tc := range cases {
@@ -167,6 +269,48 @@ func withDispatchMaxDepth(cfg Config, maxDepth int) Config {
 	return cfg
 }
 
+func withUpgradeVerify(cfg Config, verify ...UpgradeVerifyCommand) Config {
+	cfg.Upgrade.Verify = verify
+	return cfg
+}
+
+func withClients(cfg Config, clients map[string]ClientConfig) Config {
+	cfg.Clients = clients
+	return cfg
+}
+
+func withLaunch(cfg Config, launch LaunchConfig) Config {
+	cfg.Launch = launch
+	return cfg
+}
+
+func withHooks(cfg Config, hooks HooksConfig) Config {
+	cfg.Hooks = hooks
+	return cfg
+}
+
+func withPlugins(cfg Config, plugins PluginsConfig) Config {
+	cfg.Plugins = plugins
+	return cfg
+}
+
+func withOwnership(cfg Config, ownership map[string]string) Config {
+	cfg.Ownership = ownership
+	return cfg
+}
+
+func TestUserOwnedPaths(t *testing.T) {
+	cfg := Config{Ownership: map[string]string{
+		"./docs/agent-layer/COMMANDS.md": "user",
+		".agent-layer/skills/deploy/":    "User",
+		"../outside.md":                  "user",
+	}}
+	got := strings.Join(cfg.UserOwnedPaths(), ",")
+	if got != ".agent-layer/skills/deploy,docs/agent-layer/COMMANDS.md" {
+		t.Fatalf("UserOwnedPaths = %s", got)
+	}
+}
+
 func TestValidateApprovalsYOLO(t *testing.T) {
 	trueVal := true
 	cfg := Config{
@@ -185,6 +329,38 @@ func TestValidateApprovalsYOLO(t *testing.T) {
 	}
 }
 
+func TestValidateMCPServerAgents(t *testing.T) {
+	trueVal := true
+	cfg := Config{
+		Approvals: ApprovalsConfig{Mode: ApprovalModeAll},
+		Agents: AgentsConfig{
+			Antigravity:  AntigravityConfig{Enabled: &trueVal},
+			Claude:       ClaudeConfig{Enabled: &trueVal},
+			ClaudeVSCode: EnableOnlyConfig{Enabled: &trueVal},
+			Codex:        CodexConfig{Enabled: &trueVal},
+			VSCode:       EnableOnlyConfig{Enabled: &trueVal},
+			CopilotCLI:   AgentConfig{Enabled: &trueVal},
+		},
+		MCP: MCPConfig{Servers: []MCPServer{{
+			ID:        "x",
+			Enabled:   &trueVal,
+			Transport: TransportStdio,
+			Command:   "tool",
+			Agents:    []string{"codex", "claude", "claude_vscode", "copilot_cli"},
+		}}},
+	}
+	if err := cfg.Validate("config.toml"); err != nil {
+		t.Fatalf("validate: %v", err)
+	}
+	want := []string{"codex", "claude", "copilot"}
+	if got := cfg.MCP.Servers[0].Clients; !slices.Equal(got, want) {
+		t.Fatalf("clients = %v, want %v", got, want)
+	}
+	if cfg.MCP.Servers[0].AppliesToClient("vscode") {
+		t.Fatalf("expected vscode to be excluded")
+	}
+}
+
 func TestValidateClaudeReasoningEffortWithOpusModel(t *testing.T) {
 	trueVal := true
 	cfg := Config{
@@ -362,6 +538,29 @@ func TestValidateWarningsNoiseModeQuiet(t *testing.T) {
 	}
 }
 
+func TestValidateLanguage(t *testing.T) {
+	enabled := true
+	cfg := Config{
+		Approvals: ApprovalsConfig{Mode: ApprovalModeAll},
+		Agents: AgentsConfig{
+			Antigravity:  AntigravityConfig{Enabled: &enabled},
+			Claude:       ClaudeConfig{Enabled: &enabled},
+			ClaudeVSCode: EnableOnlyConfig{Enabled: &enabled},
+			Codex:        CodexConfig{Enabled: &enabled},
+			VSCode:       EnableOnlyConfig{Enabled: &enabled},
+			CopilotCLI:   AgentConfig{Enabled: &enabled},
+		},
+		Language: "pt-BR",
+	}
+	if err := cfg.Validate("config.toml"); err != nil {
+		t.Fatalf("expected pt-BR to be valid, got %v", err)
+	}
+	cfg.Language = "Portuguese (Brazil)"
+	if err := cfg.Validate("config.toml"); err == nil || !strings.Contains(err.Error(), "language") {
+		t.Fatalf("expected language error, got %v", err)
+	}
+}
+
 func TestValidateSanitizesTransportIncompatibleFields(t *testing.T) {
 	enabled := true
 	base := Config{
@@ -434,3 +633,47 @@ func TestValidateSanitizesTransportIncompatibleFields(t *testing.T) {
 		}
 	})
 }
+
+func TestValidationErrors_CollectsEveryProblem(t *testing.T) {
+	trueVal := true
+	zero := 0
+	cfg := Config{
+		Approvals: ApprovalsConfig{Mode: "bad"},
+		Agents: AgentsConfig{
+			Antigravity:  AntigravityConfig{Enabled: &trueVal},
+			ClaudeVSCode: EnableOnlyConfig{Enabled: &trueVal},
+			Codex:        CodexConfig{Enabled: &trueVal},
+			VSCode:       EnableOnlyConfig{Enabled: &trueVal},
+		},
+		MCP: MCPConfig{Servers: []MCPServer{
+			{ID: "a", Transport: "ws", Clients: []string{"emacs"}},
+			{ID: "a", Enabled: &trueVal, Transport: TransportHTTP},
+		}},
+		Warnings: WarningsConfig{NoiseMode: "loud", MCPServerThreshold: &zero},
+	}
+
+	errs := cfg.ValidationErrors("config.toml")
+	want := []string{
+		"approvals.mode",
+		"agents.claude.enabled is required",
+		"agents.copilot_cli.enabled is required",
+		"mcp.servers[0].enabled is required",
+		"mcp.servers[0].transport must be http or stdio",
+		`mcp.servers[0].clients contains invalid client "emacs"`,
+		`mcp.servers[1].id "a" duplicates mcp.servers[0].id`,
+		"mcp.servers[1].url is required",
+		`warnings.noise_mode "loud" is invalid`,
+		"warnings.mcp_server_threshold must be greater than zero",
+	}
+	if len(errs) != len(want) {
+		t.Fatalf("expected %d errors, got %d: %v", len(want), len(errs), errs)
+	}
+	for i, substring := range want {
+		if !strings.Contains(errs[i].Error(), substring) {
+			t.Fatalf("error %d = %q, want it to contain %q", i, errs[i], substring)
+		}
+	}
+	if err := cfg.Validate("config.toml"); err == nil || err.Error() != errs[0].Error() {
+		t.Fatalf("Validate should return the first collected error, got %v", err)
+	}
+}
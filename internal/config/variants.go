@@ -0,0 +1,273 @@
+package config
+
+import (
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/conn-castle/agent-layer/internal/i18n"
+	"github.com/conn-castle/agent-layer/internal/messages"
+)
+
+// VariantBase selects the unsuffixed file of an instruction or skill, so a
+// profile or client can pin the original while the default moves on.
+const VariantBase = "base"
+
+// EnvVariantProfile selects an entry of [variants.profiles]. It is read from
+// the process environment first, then from .agent-layer/.env.
+const EnvVariantProfile = "AL_VARIANT_PROFILE"
+
+// Clients with their own instruction or skill outputs, and so their own
+// variant selection. Every other client reads the shared AGENTS.md and
+// .agents/skills, which follow the default selection.
+const (
+	// VariantClientClaude selects for CLAUDE.md and .claude/skills.
+	VariantClientClaude = "claude"
+	// VariantClientCopilot selects for .github/copilot-instructions.md.
+	VariantClientCopilot = "copilot"
+)
+
+// variantSeparator joins a name and its variant: deploy@v2/SKILL.md or
+// 01_style@terse.md.
+const variantSeparator = "@"
+
+var variantNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// VariantsConfig selects which variant of each instruction or skill sync
+// projects. Keys are instruction file names without .md and skill names;
+// values are variant names or VariantBase. Unlisted names use the base file.
+type VariantsConfig struct {
+	Instructions map[string]string `toml:"instructions"`
+	Skills       map[string]string `toml:"skills"`
+	// Profiles are alternative selections layered over the defaults;
+	// AL_VARIANT_PROFILE chooses one.
+	Profiles map[string]VariantSelection `toml:"profiles"`
+	// Clients override the default and profile selection for the clients
+	// with their own outputs (VariantClientClaude, VariantClientCopilot).
+	Clients map[string]VariantSelection `toml:"clients"`
+}
+
+// VariantSelection is one named set of variant choices.
+type VariantSelection struct {
+	Instructions map[string]string `toml:"instructions"`
+	Skills       map[string]string `toml:"skills"`
+}
+
+// splitVariant splits deploy@v2 into deploy and v2. Names without a variant
+// return an empty variant.
+func splitVariant(name string) (string, string) {
+	base, variant, ok := strings.Cut(name, variantSeparator)
+	if !ok {
+		return name, ""
+	}
+	return base, variant
+}
+
+// checkVariantName rejects variant file names that are not name@variant with
+// a usable variant.
+func checkVariantName(path string, name string) error {
+	if !strings.Contains(name, variantSeparator) {
+		return nil
+	}
+	base, variant := splitVariant(name)
+	if base == "" || variant == VariantBase || !variantNamePattern.MatchString(variant) {
+		return i18n.Errorf(messages.ConfigVariantFileNameInvalidFmt, path, VariantBase)
+	}
+	return nil
+}
+
+// validateVariants checks that selections name usable variants and that
+// client overrides target clients with their own outputs.
+func validateVariants(path string, cfg VariantsConfig) []error {
+	errs := validateVariantSelection(path, "variants", VariantSelection{Instructions: cfg.Instructions, Skills: cfg.Skills})
+	for _, name := range sortedKeys(cfg.Profiles) {
+		if strings.TrimSpace(name) == "" {
+			errs = append(errs, i18n.Errorf(messages.ConfigVariantProfileNameRequiredFmt, path))
+			continue
+		}
+		errs = append(errs, validateVariantSelection(path, "variants.profiles."+name, cfg.Profiles[name])...)
+	}
+	for _, client := range sortedKeys(cfg.Clients) {
+		selection := cfg.Clients[client]
+		switch client {
+		case VariantClientClaude:
+		case VariantClientCopilot:
+			if len(selection.Skills) > 0 {
+				errs = append(errs, i18n.Errorf(messages.ConfigVariantCopilotSkillsFmt, path))
+			}
+		default:
+			errs = append(errs, i18n.Errorf(messages.ConfigVariantClientUnknownFmt, path, client, VariantClientClaude, VariantClientCopilot))
+			continue
+		}
+		errs = append(errs, validateVariantSelection(path, "variants.clients."+client, selection)...)
+	}
+	return errs
+}
+
+func validateVariantSelection(path string, table string, selection VariantSelection) []error {
+	var errs []error
+	for _, kind := range []struct {
+		key   string
+		picks map[string]string
+	}{{"instructions", selection.Instructions}, {"skills", selection.Skills}} {
+		for _, name := range sortedKeys(kind.picks) {
+			variant := kind.picks[name]
+			if name == "" || strings.Contains(name, variantSeparator) || (variant != VariantBase && !variantNamePattern.MatchString(variant)) {
+				errs = append(errs, i18n.Errorf(messages.ConfigVariantSelectionInvalidFmt, path, table, kind.key, name, variant))
+			}
+		}
+	}
+	return errs
+}
+
+// variantPicks merges the default selection, the named profile, and the
+// client override, later layers winning.
+func (v VariantsConfig) variantPicks(profile string, client string) (instructions map[string]string, skills map[string]string) {
+	instructions, skills = map[string]string{}, map[string]string{}
+	layers := []VariantSelection{{Instructions: v.Instructions, Skills: v.Skills}, v.Profiles[profile]}
+	if client != "" {
+		layers = append(layers, v.Clients[client])
+	}
+	for _, layer := range layers {
+		for name, variant := range layer.Instructions {
+			instructions[name] = variant
+		}
+		for name, variant := range layer.Skills {
+			skills[name] = variant
+		}
+	}
+	return instructions, skills
+}
+
+// resolveVariantProfile returns the profile named by AL_VARIANT_PROFILE in the
+// process environment or .agent-layer/.env, and fails when it is not defined.
+func resolveVariantProfile(cfg VariantsConfig, env map[string]string) (string, error) {
+	profile := strings.TrimSpace(os.Getenv(EnvVariantProfile))
+	if profile == "" {
+		profile = strings.TrimSpace(env[EnvVariantProfile])
+	}
+	if profile == "" {
+		return "", nil
+	}
+	if _, ok := cfg.Profiles[profile]; !ok {
+		return "", i18n.Errorf(messages.ConfigVariantProfileUnknownFmt, EnvVariantProfile, profile, strings.Join(sortedKeys(cfg.Profiles), ", "))
+	}
+	return profile, nil
+}
+
+// ForVariantClient returns the project as client sees it: Instructions and
+// Skills reselected with client's [variants.clients] override. It returns p
+// unchanged when client has no override.
+func (p *ProjectConfig) ForVariantClient(client string) (*ProjectConfig, error) {
+	override, ok := p.Config.Variants.Clients[client]
+	if !ok || (len(override.Instructions) == 0 && len(override.Skills) == 0) {
+		return p, nil
+	}
+	instructions, skills, err := selectVariants(p.InstructionVariants, p.SkillVariants, p.Config.Variants, p.VariantProfile, client)
+	if err != nil {
+		return nil, err
+	}
+	selected := *p
+	selected.Instructions = instructions
+	selected.Skills = skills
+	return &selected, nil
+}
+
+// selectVariants picks one file per instruction and skill name for the given
+// profile and client.
+func selectVariants(allInstructions []InstructionFile, allSkills []Skill, cfg VariantsConfig, profile string, client string) ([]InstructionFile, []Skill, error) {
+	instructionPicks, skillPicks := cfg.variantPicks(profile, client)
+
+	instructionVariants := make(map[string]map[string]InstructionFile)
+	var instructionOrder []string
+	for _, file := range allInstructions {
+		base, variant := splitVariant(strings.TrimSuffix(file.Name, ".md"))
+		if instructionVariants[base] == nil {
+			instructionVariants[base] = map[string]InstructionFile{}
+			instructionOrder = append(instructionOrder, base)
+		}
+		instructionVariants[base][variant] = file
+	}
+	// A name defined only by variants sorts where its base file would.
+	sort.Slice(instructionOrder, func(i, j int) bool {
+		return instructionOrder[i]+".md" < instructionOrder[j]+".md"
+	})
+	instructions := make([]InstructionFile, 0, len(instructionOrder))
+	for _, base := range instructionOrder {
+		file, err := pickVariant(messages.ConfigVariantKindInstruction, base, instructionVariants[base], instructionPicks[base])
+		if err != nil {
+			return nil, nil, err
+		}
+		instructions = append(instructions, file)
+	}
+
+	skillVariants := make(map[string]map[string]Skill)
+	var skillOrder []string
+	for _, skill := range allSkills {
+		if skillVariants[skill.Name] == nil {
+			skillVariants[skill.Name] = map[string]Skill{}
+			skillOrder = append(skillOrder, skill.Name)
+		}
+		skillVariants[skill.Name][skill.Variant] = skill
+	}
+	sort.Strings(skillOrder)
+	skills := make([]Skill, 0, len(skillOrder))
+	for _, name := range skillOrder {
+		skill, err := pickVariant(messages.ConfigVariantKindSkill, name, skillVariants[name], skillPicks[name])
+		if err != nil {
+			return nil, nil, err
+		}
+		skills = append(skills, skill)
+	}
+
+	for _, check := range []struct {
+		kind  string
+		picks map[string]string
+		known func(string) bool
+	}{
+		{messages.ConfigVariantKindInstruction, instructionPicks, func(name string) bool { return instructionVariants[name] != nil }},
+		{messages.ConfigVariantKindSkill, skillPicks, func(name string) bool { return skillVariants[name] != nil }},
+	} {
+		for _, name := range sortedKeys(check.picks) {
+			if !check.known(name) {
+				return nil, nil, i18n.Errorf(messages.ConfigVariantTargetUnknownFmt, check.kind, name)
+			}
+		}
+	}
+	return instructions, skills, nil
+}
+
+// pickVariant returns the selected variant of name. The base file is used
+// when nothing is selected; a name defined only by variants must select one.
+func pickVariant[T any](kind string, name string, variants map[string]T, pick string) (T, error) {
+	var zero T
+	variant := pick
+	if variant == VariantBase {
+		variant = ""
+	}
+	if file, ok := variants[variant]; ok {
+		return file, nil
+	}
+	available := make([]string, 0, len(variants))
+	for key := range variants {
+		if key == "" {
+			key = VariantBase
+		}
+		available = append(available, key)
+	}
+	sort.Strings(available)
+	if pick == "" {
+		return zero, i18n.Errorf(messages.ConfigVariantBaseMissingFmt, kind, name, strings.Join(available, ", "))
+	}
+	return zero, i18n.Errorf(messages.ConfigVariantUnknownFmt, kind, name, pick, strings.Join(available, ", "))
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,139 @@
+package config
+
+import (
+	"io/fs"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func variantSkillFS() fstest.MapFS {
+	skill := func(name string, body string) *fstest.MapFile {
+		return &fstest.MapFile{Data: []byte("---\nname: " + name + "\ndescription: d\n---\n\n" + body)}
+	}
+	return fstest.MapFS{
+		"skills":                        {Mode: fs.ModeDir},
+		"skills/deploy":                 {Mode: fs.ModeDir},
+		"skills/deploy/SKILL.md":        skill("deploy", "v1 body"),
+		"skills/deploy@v2":              {Mode: fs.ModeDir},
+		"skills/deploy@v2/SKILL.md":     skill("deploy", "v2 body"),
+		"skills/triage@new":             {Mode: fs.ModeDir},
+		"skills/triage@new/SKILL.md":    skill("triage", "new body"),
+		"instructions":                  {Mode: fs.ModeDir},
+		"instructions/00_base.md":       {Data: []byte("base")},
+		"instructions/00_base@terse.md": {Data: []byte("terse")},
+		"instructions/10_tail.md":       {Data: []byte("tail")},
+	}
+}
+
+func TestSelectVariants(t *testing.T) {
+	fsys := variantSkillFS()
+	skills, err := LoadSkillsFS(fsys, "root", "skills")
+	if err != nil {
+		t.Fatalf("LoadSkillsFS: %v", err)
+	}
+	if len(skills) != 3 || skills[1].Name != "deploy" || skills[1].Variant != "v2" || skills[2].Name != "triage" {
+		t.Fatalf("loaded skills = %+v", skills)
+	}
+	instructions, err := LoadInstructionsFS(fsys, "root", "instructions")
+	if err != nil {
+		t.Fatalf("LoadInstructionsFS: %v", err)
+	}
+
+	cfg := VariantsConfig{
+		Instructions: map[string]string{"00_base": "terse"},
+		Skills:       map[string]string{"deploy": "v2", "triage": "new"},
+		Profiles:     map[string]VariantSelection{"stable": {Skills: map[string]string{"deploy": VariantBase}}},
+		Clients:      map[string]VariantSelection{VariantClientClaude: {Instructions: map[string]string{"00_base": VariantBase}}},
+	}
+	gotInstructions, gotSkills, err := selectVariants(instructions, skills, cfg, "", "")
+	if err != nil {
+		t.Fatalf("selectVariants: %v", err)
+	}
+	if len(gotInstructions) != 2 || gotInstructions[0].Content != "terse" || gotInstructions[1].Name != "10_tail.md" {
+		t.Fatalf("default instructions = %+v", gotInstructions)
+	}
+	if len(gotSkills) != 2 || gotSkills[0].Body != "v2 body" || gotSkills[1].Body != "new body" {
+		t.Fatalf("default skills = %+v", gotSkills)
+	}
+
+	gotInstructions, gotSkills, err = selectVariants(instructions, skills, cfg, "stable", VariantClientClaude)
+	if err != nil {
+		t.Fatalf("selectVariants: %v", err)
+	}
+	if gotInstructions[0].Content != "base" || gotSkills[0].Body != "v1 body" {
+		t.Fatalf("profile and client selection = %+v %+v", gotInstructions, gotSkills)
+	}
+
+	for _, tc := range []struct {
+		cfg  VariantsConfig
+		want string
+	}{
+		{VariantsConfig{Skills: map[string]string{"deploy": "v3"}}, `skill "deploy" has no variant "v3" (available: base, v2)`},
+		{VariantsConfig{}, `skill "triage" has no base file`},
+		{VariantsConfig{Skills: map[string]string{"triage": "new", "ghost": "v1"}}, `skill "ghost", which does not exist`},
+	} {
+		if _, _, err := selectVariants(instructions, skills, tc.cfg, "", ""); err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Fatalf("selectVariants(%+v) error = %v, want %q", tc.cfg, err, tc.want)
+		}
+	}
+}
+
+func TestForVariantClient(t *testing.T) {
+	project := &ProjectConfig{
+		Config: Config{Variants: VariantsConfig{Clients: map[string]VariantSelection{
+			VariantClientClaude: {Skills: map[string]string{"deploy": "v2"}},
+		}}},
+		SkillVariants: []Skill{{Name: "deploy", Body: "v1"}, {Name: "deploy", Variant: "v2", Body: "v2"}},
+		Skills:        []Skill{{Name: "deploy", Body: "v1"}},
+	}
+	if got, err := project.ForVariantClient(VariantClientCopilot); err != nil || got != project {
+		t.Fatalf("client without override must reuse the project, got %v (%v)", got, err)
+	}
+	got, err := project.ForVariantClient(VariantClientClaude)
+	if err != nil {
+		t.Fatalf("ForVariantClient: %v", err)
+	}
+	if got == project || got.Skills[0].Body != "v2" || project.Skills[0].Body != "v1" {
+		t.Fatalf("claude selection = %+v", got.Skills)
+	}
+}
+
+func TestValidateVariants(t *testing.T) {
+	valid := VariantsConfig{
+		Skills:   map[string]string{"deploy": "v2"},
+		Profiles: map[string]VariantSelection{"stable": {Skills: map[string]string{"deploy": VariantBase}}},
+		Clients:  map[string]VariantSelection{VariantClientCopilot: {Instructions: map[string]string{"00_base": "terse"}}},
+	}
+	if errs := validateVariants("config.toml", valid); len(errs) != 0 {
+		t.Fatalf("validateVariants errors: %v", errs)
+	}
+	for _, cfg := range []VariantsConfig{
+		{Skills: map[string]string{"deploy@v2": "v3"}},
+		{Instructions: map[string]string{"00_base": "bad variant"}},
+		{Profiles: map[string]VariantSelection{"": {}}},
+		{Clients: map[string]VariantSelection{"codex": {}}},
+		{Clients: map[string]VariantSelection{VariantClientCopilot: {Skills: map[string]string{"deploy": "v2"}}}},
+	} {
+		if errs := validateVariants("config.toml", cfg); len(errs) == 0 {
+			t.Fatalf("expected error for %+v", cfg)
+		}
+	}
+	for _, name := range []string{"deploy@", "@v2", "deploy@base", "deploy@a@b"} {
+		if err := checkVariantName(name, name); err == nil {
+			t.Fatalf("expected error for variant name %q", name)
+		}
+	}
+}
+
+func TestResolveVariantProfile(t *testing.T) {
+	cfg := VariantsConfig{Profiles: map[string]VariantSelection{"stable": {}}}
+	t.Setenv(EnvVariantProfile, "")
+	if got, err := resolveVariantProfile(cfg, map[string]string{EnvVariantProfile: "stable"}); err != nil || got != "stable" {
+		t.Fatalf("profile from .env = %q (%v)", got, err)
+	}
+	t.Setenv(EnvVariantProfile, "canary")
+	if _, err := resolveVariantProfile(cfg, nil); err == nil || !strings.Contains(err.Error(), "known: stable") {
+		t.Fatalf("expected unknown profile error, got %v", err)
+	}
+}
@@ -0,0 +1,82 @@
+package config
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/conn-castle/agent-layer/internal/i18n"
+	"github.com/conn-castle/agent-layer/internal/layerdir"
+	"github.com/conn-castle/agent-layer/internal/messages"
+	"github.com/conn-castle/agent-layer/internal/worktree"
+)
+
+// Worktree state modes.
+const (
+	// WorktreeStateAuto gives each linked git worktree its own runtime state
+	// only when the worktrees share one .agent-layer directory through a
+	// redirect outside the checkout.
+	WorktreeStateAuto = "auto"
+	// WorktreeStatePerWorktree always gives each linked worktree its own
+	// runtime state.
+	WorktreeStatePerWorktree = "per-worktree"
+	// WorktreeStateShared keeps one runtime state directory for every worktree.
+	WorktreeStateShared = "shared"
+)
+
+// WorktreeConfig controls how runtime state is scoped across git worktrees.
+type WorktreeConfig struct {
+	// State is "auto" (default), "per-worktree", or "shared".
+	State string `toml:"state"`
+}
+
+// StateMode returns the normalized worktree state mode, defaulting to auto.
+func (w WorktreeConfig) StateMode() string {
+	mode := strings.ToLower(strings.TrimSpace(w.State))
+	if mode == "" {
+		return WorktreeStateAuto
+	}
+	return mode
+}
+
+// validateWorktree checks worktree.state.
+func validateWorktree(path string, cfg WorktreeConfig) error {
+	switch cfg.StateMode() {
+	case WorktreeStateAuto, WorktreeStatePerWorktree, WorktreeStateShared:
+		return nil
+	default:
+		return i18n.Errorf(messages.ConfigWorktreeStateInvalidFmt, path, cfg.State)
+	}
+}
+
+// StateDir returns the directory for root's per-checkout runtime state: sync
+// staleness records, managed Claude keys, and dispatch runs. It is
+// .agent-layer/state unless root is a linked git worktree whose state is
+// scoped per worktree by [worktree] state, in which case it is
+// .agent-layer/state/worktrees/<name>. A config that cannot be read counts as
+// auto.
+func StateDir(root string) string {
+	layer := layerdir.Dir(root)
+	shared := filepath.Join(layer, "state")
+	mode := WorktreeStateAuto
+	if cfg, err := LoadConfigLenient(filepath.Join(layer, "config.toml")); err == nil {
+		mode = cfg.Worktree.StateMode()
+	}
+	if mode == WorktreeStateShared {
+		return shared
+	}
+	info, err := worktree.Detect(root)
+	if err != nil || !info.Linked {
+		return shared
+	}
+	if mode == WorktreeStateAuto && !layerOutside(root, layer) {
+		return shared
+	}
+	return filepath.Join(shared, "worktrees", info.Name)
+}
+
+// layerOutside reports whether the .agent-layer directory lives outside the
+// checkout, so every worktree resolves to the same directory.
+func layerOutside(root string, layer string) bool {
+	rel, err := filepath.Rel(root, layer)
+	return err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
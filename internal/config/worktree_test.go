@@ -0,0 +1,96 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// makeWorktrees lays out a main checkout and a linked worktree named
+// "feature", each with its own .agent-layer directory.
+func makeWorktrees(t *testing.T) (string, string) {
+	t.Helper()
+	base := t.TempDir()
+	main := filepath.Join(base, "main")
+	linked := filepath.Join(base, "feature")
+	admin := filepath.Join(main, ".git", "worktrees", "feature")
+	for _, dir := range []string{admin, filepath.Join(main, ".agent-layer"), filepath.Join(linked, ".agent-layer")} {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatalf("mkdir %s: %v", dir, err)
+		}
+	}
+	writeTestFile(t, filepath.Join(admin, "commondir"), "../..\n")
+	writeTestFile(t, filepath.Join(admin, "gitdir"), filepath.Join(linked, ".git")+"\n")
+	writeTestFile(t, filepath.Join(linked, ".git"), "gitdir: "+admin+"\n")
+	return main, linked
+}
+
+func writeTestFile(t *testing.T, path string, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("write %s: %v", path, err)
+	}
+}
+
+func TestStateDir(t *testing.T) {
+	t.Run("main worktree is shared", func(t *testing.T) {
+		main, _ := makeWorktrees(t)
+		writeTestFile(t, filepath.Join(main, ".agent-layer", "config.toml"), "[worktree]\nstate = \"per-worktree\"\n")
+		if got, want := StateDir(main), filepath.Join(main, ".agent-layer", "state"); got != want {
+			t.Fatalf("StateDir = %s, want %s", got, want)
+		}
+	})
+
+	t.Run("auto keeps checkout-local layer shared", func(t *testing.T) {
+		_, linked := makeWorktrees(t)
+		if got, want := StateDir(linked), filepath.Join(linked, ".agent-layer", "state"); got != want {
+			t.Fatalf("StateDir = %s, want %s", got, want)
+		}
+	})
+
+	t.Run("auto scopes redirected layer", func(t *testing.T) {
+		_, linked := makeWorktrees(t)
+		external := t.TempDir()
+		if err := os.RemoveAll(filepath.Join(linked, ".agent-layer")); err != nil {
+			t.Fatalf("remove layer dir: %v", err)
+		}
+		writeTestFile(t, filepath.Join(linked, ".agent-layer"), external+"\n")
+		if got, want := StateDir(linked), filepath.Join(external, "state", "worktrees", "feature"); got != want {
+			t.Fatalf("StateDir = %s, want %s", got, want)
+		}
+	})
+
+	t.Run("per-worktree scopes linked worktree", func(t *testing.T) {
+		_, linked := makeWorktrees(t)
+		writeTestFile(t, filepath.Join(linked, ".agent-layer", "config.toml"), "[worktree]\nstate = \"per-worktree\"\n")
+		if got, want := StateDir(linked), filepath.Join(linked, ".agent-layer", "state", "worktrees", "feature"); got != want {
+			t.Fatalf("StateDir = %s, want %s", got, want)
+		}
+	})
+
+	t.Run("shared ignores redirect", func(t *testing.T) {
+		_, linked := makeWorktrees(t)
+		external := t.TempDir()
+		if err := os.RemoveAll(filepath.Join(linked, ".agent-layer")); err != nil {
+			t.Fatalf("remove layer dir: %v", err)
+		}
+		writeTestFile(t, filepath.Join(linked, ".agent-layer"), external+"\n")
+		writeTestFile(t, filepath.Join(external, "config.toml"), "[worktree]\nstate = \"shared\"\n")
+		if got, want := StateDir(linked), filepath.Join(external, "state"); got != want {
+			t.Fatalf("StateDir = %s, want %s", got, want)
+		}
+	})
+}
+
+func TestValidateWorktree(t *testing.T) {
+	for _, state := range []string{"", "auto", "per-worktree", "Shared"} {
+		if err := validateWorktree("config.toml", WorktreeConfig{State: state}); err != nil {
+			t.Fatalf("state %q: unexpected error %v", state, err)
+		}
+	}
+	err := validateWorktree("config.toml", WorktreeConfig{State: "always"})
+	if err == nil || !strings.Contains(err.Error(), "always") {
+		t.Fatalf("expected invalid state error, got %v", err)
+	}
+}
@@ -0,0 +1,465 @@
+// Package configbundle exports the user-editable .agent-layer configuration to
+// a single gzip-compressed tar archive and imports it into another repo.
+// Secrets (.env), state, temporary files, and the sync lock are never included.
+package configbundle
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/conn-castle/agent-layer/internal/clock"
+	"github.com/conn-castle/agent-layer/internal/fsutil"
+	"github.com/conn-castle/agent-layer/internal/i18n"
+	"github.com/conn-castle/agent-layer/internal/layerdir"
+	"github.com/conn-castle/agent-layer/internal/messages"
+	"github.com/conn-castle/agent-layer/internal/templates"
+)
+
+// FormatVersion is the current bundle manifest format.
+const FormatVersion = 1
+
+// ManifestName is the archive entry holding the manifest.
+const ManifestName = "manifest.json"
+
+// rename is a test seam for the moves that swap an import into place.
+var rename = os.Rename
+
+// maxEntrySize caps a single archive entry so a corrupt or hostile bundle
+// cannot exhaust memory on import.
+const maxEntrySize = 16 << 20
+
+// includedFiles are the .agent-layer files a bundle carries.
+var includedFiles = []string{
+	"config.toml",
+	"policy.toml",
+	"commands.allow",
+	"gitignore.block",
+	"al.version",
+	"al.lock",
+	"claude-statusline.sh",
+	"codex-statusline.toml",
+}
+
+// includedDirs are the .agent-layer directories a bundle carries recursively.
+var includedDirs = []string{
+	"instructions",
+	"skills",
+	"scoped",
+}
+
+// Manifest describes a bundle.
+type Manifest struct {
+	Format int `json:"format"`
+	// Kind is empty for configuration bundles and KindEnvironment for
+	// environment snapshots written by ExportEnvironment.
+	Kind      string `json:"kind,omitempty"`
+	ALVersion string `json:"al_version"`
+	CreatedAt string `json:"created_at"`
+	// Secrets records whether an environment snapshot carries .env and
+	// outputs rendered with real secret values.
+	Secrets bool   `json:"secrets,omitempty"`
+	Files   []File `json:"files"`
+}
+
+// File is one manifest entry.
+type File struct {
+	// Path is slash-separated and relative to .agent-layer/ in configuration
+	// bundles, or to the archive root in environment snapshots.
+	Path   string `json:"path"`
+	SHA256 string `json:"sha256"`
+	// Executable records whether the file had an exec bit set.
+	Executable bool `json:"executable,omitempty"`
+}
+
+// ExportOptions controls Export.
+type ExportOptions struct {
+	// Version is the running al version stamped into the manifest.
+	Version string
+	// Clock supplies the manifest timestamp; nil uses the real clock.
+	Clock clock.Clock
+}
+
+// Export writes the bundle for root to dest and returns its manifest.
+func Export(root string, dest string, opts ExportOptions) (Manifest, error) {
+	if err := checkExtension(dest); err != nil {
+		return Manifest{}, err
+	}
+	agentDir := layerdir.Dir(root)
+	if _, err := os.Stat(filepath.Join(agentDir, "config.toml")); err != nil {
+		return Manifest{}, i18n.Errorf(messages.ConfigBundleNoConfigFmt, agentDir, err)
+	}
+	rels, err := collect(agentDir)
+	if err != nil {
+		return Manifest{}, err
+	}
+
+	manifest := Manifest{
+		Format:    FormatVersion,
+		ALVersion: opts.Version,
+		CreatedAt: clock.Format(clock.Or(opts.Clock).Now()),
+		Files:     make([]File, 0, len(rels)),
+	}
+	contents := make(map[string][]byte, len(rels))
+	for _, rel := range rels {
+		full := filepath.Join(agentDir, filepath.FromSlash(rel))
+		info, err := os.Stat(full)
+		if err != nil {
+			return Manifest{}, i18n.Errorf(messages.ConfigBundleReadFmt, full, err)
+		}
+		data, err := os.ReadFile(full) // #nosec G304 -- paths come from walking .agent-layer under the repo root.
+		if err != nil {
+			return Manifest{}, i18n.Errorf(messages.ConfigBundleReadFmt, full, err)
+		}
+		contents[rel] = data
+		manifest.Files = append(manifest.Files, File{Path: rel, SHA256: sha256Hex(data), Executable: info.Mode().Perm()&0o111 != 0})
+	}
+
+	if err := writeArchive(dest, manifest, contents, func(rel string) string { return path.Join(".agent-layer", rel) }); err != nil {
+		return Manifest{}, err
+	}
+	return manifest, nil
+}
+
+// writeArchive writes manifest and the listed files to dest as a gzip tar.
+// entryName maps a manifest path to its archive entry name.
+func writeArchive(dest string, manifest Manifest, contents map[string][]byte, entryName func(string) string) error {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	manifestData, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return i18n.Errorf(messages.ConfigBundleWriteFmt, dest, err)
+	}
+	modTime, _ := clock.Parse(manifest.CreatedAt)
+	if err := writeEntry(tw, ManifestName, append(manifestData, '\n'), 0o644, modTime); err != nil {
+		return i18n.Errorf(messages.ConfigBundleWriteFmt, dest, err)
+	}
+	for _, file := range manifest.Files {
+		mode := int64(0o644)
+		if file.Executable {
+			mode = 0o755
+		}
+		if err := writeEntry(tw, entryName(file.Path), contents[file.Path], mode, modTime); err != nil {
+			return i18n.Errorf(messages.ConfigBundleWriteFmt, dest, err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return i18n.Errorf(messages.ConfigBundleWriteFmt, dest, err)
+	}
+	if err := gz.Close(); err != nil {
+		return i18n.Errorf(messages.ConfigBundleWriteFmt, dest, err)
+	}
+	if err := fsutil.WriteFileAtomic(dest, buf.Bytes(), 0o600); err != nil {
+		return i18n.Errorf(messages.ConfigBundleWriteFmt, dest, err)
+	}
+	return nil
+}
+
+// Import unpacks the bundle at src into root/.agent-layer and returns its
+// manifest. Every entry is checked against the manifest before anything is
+// written. Without force, import refuses to touch a repo that already has a
+// config.toml; with force, the bundled files and directories replace the
+// local ones exactly while .env, state, and tmp are kept. The files are
+// written to a staging directory under .agent-layer/tmp/ first and then moved
+// into place, and a failed move restores the local files. A missing .env is
+// seeded from the template.
+func Import(root string, src string, force bool) (Manifest, error) {
+	if err := checkExtension(src); err != nil {
+		return Manifest{}, err
+	}
+	manifest, contents, err := read(src)
+	if err != nil {
+		return Manifest{}, err
+	}
+
+	agentDir := layerdir.Dir(root)
+	configPath := filepath.Join(agentDir, "config.toml")
+	if _, err := os.Stat(configPath); err == nil && !force {
+		return Manifest{}, i18n.Errorf(messages.ConfigBundleExistsFmt, configPath)
+	} else if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return Manifest{}, i18n.Errorf(messages.ConfigBundleReadFmt, configPath, err)
+	}
+
+	tmpDir := filepath.Join(agentDir, "tmp")
+	_, statErr := os.Stat(tmpDir)
+	createdTmp := errors.Is(statErr, fs.ErrNotExist)
+	if err := os.MkdirAll(tmpDir, 0o755); err != nil {
+		return Manifest{}, i18n.Errorf(messages.ConfigBundleWriteFmt, tmpDir, err)
+	}
+	staging, err := os.MkdirTemp(tmpDir, "import-config-")
+	if err != nil {
+		return Manifest{}, i18n.Errorf(messages.ConfigBundleWriteFmt, tmpDir, err)
+	}
+	defer func() {
+		_ = os.RemoveAll(staging)
+		if createdTmp {
+			_ = os.Remove(tmpDir)
+		}
+	}()
+	newDir := filepath.Join(staging, "new")
+	for _, file := range manifest.Files {
+		dest := filepath.Join(newDir, filepath.FromSlash(file.Path))
+		if err := os.MkdirAll(filepath.Dir(dest), 0o755); err != nil {
+			return Manifest{}, i18n.Errorf(messages.ConfigBundleWriteFmt, dest, err)
+		}
+		perm := os.FileMode(0o644)
+		if file.Executable {
+			perm = 0o755
+		}
+		if err := os.WriteFile(dest, contents[file.Path], perm); err != nil {
+			return Manifest{}, i18n.Errorf(messages.ConfigBundleWriteFmt, dest, err)
+		}
+	}
+	if err := swapIn(agentDir, newDir, filepath.Join(staging, "old")); err != nil {
+		return Manifest{}, err
+	}
+	if err := seedEnv(filepath.Join(agentDir, ".env")); err != nil {
+		return Manifest{}, err
+	}
+	return manifest, nil
+}
+
+// swapIn replaces every bundle file and directory in agentDir with the one
+// staged in newDir, moving the local ones to oldDir. Names newDir lacks are
+// removed from agentDir. When a move fails, the moves already made are undone
+// so agentDir keeps its previous configuration.
+func swapIn(agentDir string, newDir string, oldDir string) error {
+	if err := os.MkdirAll(oldDir, 0o755); err != nil {
+		return i18n.Errorf(messages.ConfigBundleWriteFmt, oldDir, err)
+	}
+	type move struct{ from, to string }
+	var done []move
+	undo := func() {
+		for i := len(done) - 1; i >= 0; i-- {
+			_ = rename(done[i].to, done[i].from)
+		}
+	}
+	moveIfExists := func(from string, to string) error {
+		if _, err := os.Lstat(from); err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if err := rename(from, to); err != nil {
+			return err
+		}
+		done = append(done, move{from: from, to: to})
+		return nil
+	}
+	for _, name := range append(append([]string{}, includedFiles...), includedDirs...) {
+		local := filepath.Join(agentDir, name)
+		if err := moveIfExists(local, filepath.Join(oldDir, name)); err != nil {
+			undo()
+			return i18n.Errorf(messages.ConfigBundleWriteFmt, local, err)
+		}
+		if err := moveIfExists(filepath.Join(newDir, name), local); err != nil {
+			undo()
+			return i18n.Errorf(messages.ConfigBundleWriteFmt, local, err)
+		}
+	}
+	return nil
+}
+
+// seedEnv writes the template .env when the repo has none, since bundles never
+// carry secrets but config loading requires the file.
+func seedEnv(envPath string) error {
+	if _, err := os.Stat(envPath); err == nil || !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	data, err := templates.Read("env")
+	if err != nil {
+		return i18n.Errorf(messages.ConfigBundleWriteFmt, envPath, err)
+	}
+	if err := fsutil.WriteFileAtomic(envPath, data, 0o600); err != nil {
+		return i18n.Errorf(messages.ConfigBundleWriteFmt, envPath, err)
+	}
+	return nil
+}
+
+// read loads and validates a bundle without touching the repo.
+func read(src string) (Manifest, map[string][]byte, error) {
+	f, err := os.Open(src) // #nosec G304 -- src is the bundle path the user passed on the command line.
+	if err != nil {
+		return Manifest{}, nil, i18n.Errorf(messages.ConfigBundleReadFmt, src, err)
+	}
+	defer func() { _ = f.Close() }()
+	gz, err := gzip.NewReader(f)
+	if errors.Is(err, gzip.ErrHeader) {
+		return Manifest{}, nil, i18n.Errorf(messages.ConfigBundleFormatUnsupportedFmt, src)
+	}
+	if err != nil {
+		return Manifest{}, nil, i18n.Errorf(messages.ConfigBundleInvalidFmt, src, err)
+	}
+	tr := tar.NewReader(gz)
+
+	var manifestData []byte
+	entries := make(map[string][]byte)
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return Manifest{}, nil, i18n.Errorf(messages.ConfigBundleInvalidFmt, src, err)
+		}
+		if header.Typeflag != tar.TypeReg {
+			return Manifest{}, nil, i18n.Errorf(messages.ConfigBundleUnexpectedEntryFmt, src, header.Name)
+		}
+		if header.Size > maxEntrySize {
+			return Manifest{}, nil, i18n.Errorf(messages.ConfigBundleEntryTooLargeFmt, src, header.Name)
+		}
+		data, err := io.ReadAll(io.LimitReader(tr, maxEntrySize))
+		if err != nil {
+			return Manifest{}, nil, i18n.Errorf(messages.ConfigBundleInvalidFmt, src, err)
+		}
+		if header.Name == ManifestName {
+			manifestData = data
+			var probe Manifest
+			if err := json.Unmarshal(data, &probe); err == nil && probe.Kind != "" {
+				return Manifest{}, nil, i18n.Errorf(messages.ConfigBundleWrongKindFmt, src, probe.Kind)
+			}
+			continue
+		}
+		rel, ok := strings.CutPrefix(header.Name, ".agent-layer/")
+		if !ok || !allowed(rel) {
+			return Manifest{}, nil, i18n.Errorf(messages.ConfigBundleUnexpectedEntryFmt, src, header.Name)
+		}
+		entries[rel] = data
+	}
+	if manifestData == nil {
+		return Manifest{}, nil, i18n.Errorf(messages.ConfigBundleMissingManifestFmt, src, ManifestName)
+	}
+	var manifest Manifest
+	if err := json.Unmarshal(manifestData, &manifest); err != nil {
+		return Manifest{}, nil, i18n.Errorf(messages.ConfigBundleInvalidFmt, src, err)
+	}
+	if manifest.Format != FormatVersion {
+		return Manifest{}, nil, i18n.Errorf(messages.ConfigBundleUnsupportedFormatFmt, src, manifest.Format, FormatVersion)
+	}
+	listed := make(map[string]bool, len(manifest.Files))
+	for _, file := range manifest.Files {
+		data, ok := entries[file.Path]
+		if !ok || !allowed(file.Path) {
+			return Manifest{}, nil, i18n.Errorf(messages.ConfigBundleMissingEntryFmt, src, file.Path)
+		}
+		if sha256Hex(data) != file.SHA256 {
+			return Manifest{}, nil, i18n.Errorf(messages.ConfigBundleChecksumMismatchFmt, src, file.Path)
+		}
+		listed[file.Path] = true
+	}
+	for rel := range entries {
+		if !listed[rel] {
+			return Manifest{}, nil, i18n.Errorf(messages.ConfigBundleUnlistedEntryFmt, src, rel)
+		}
+	}
+	if !listed["config.toml"] {
+		return Manifest{}, nil, i18n.Errorf(messages.ConfigBundleMissingEntryFmt, src, "config.toml")
+	}
+	return manifest, entries, nil
+}
+
+// collect lists the bundle files under agentDir, slash-separated and sorted.
+// Symlinks and other non-regular files are skipped.
+func collect(agentDir string) ([]string, error) {
+	var rels []string
+	for _, name := range includedFiles {
+		info, err := os.Lstat(filepath.Join(agentDir, name))
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+			return nil, i18n.Errorf(messages.ConfigBundleReadFmt, filepath.Join(agentDir, name), err)
+		}
+		if info.Mode().IsRegular() {
+			rels = append(rels, name)
+		}
+	}
+	for _, name := range includedDirs {
+		dir := filepath.Join(agentDir, name)
+		err := filepath.WalkDir(dir, func(p string, entry fs.DirEntry, err error) error {
+			if err != nil {
+				if errors.Is(err, fs.ErrNotExist) && p == dir {
+					return nil
+				}
+				return err
+			}
+			if !entry.Type().IsRegular() {
+				return nil
+			}
+			rel, err := filepath.Rel(agentDir, p)
+			if err != nil {
+				return err
+			}
+			rels = append(rels, filepath.ToSlash(rel))
+			return nil
+		})
+		if err != nil {
+			return nil, i18n.Errorf(messages.ConfigBundleReadFmt, dir, err)
+		}
+	}
+	sort.Strings(rels)
+	return rels, nil
+}
+
+// allowed reports whether rel is a clean path inside the bundle file set.
+func allowed(rel string) bool {
+	if rel == "" || path.Clean(rel) != rel || strings.HasPrefix(rel, "../") || path.IsAbs(rel) {
+		return false
+	}
+	for _, name := range includedFiles {
+		if rel == name {
+			return true
+		}
+	}
+	for _, name := range includedDirs {
+		if strings.HasPrefix(rel, name+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// checkExtension requires the .tar.gz or .tgz name of the only supported
+// bundle format, so a .tar.zst or plain .tar name fails before any work.
+func checkExtension(p string) error {
+	lower := strings.ToLower(p)
+	if strings.HasSuffix(lower, ".tar.gz") || strings.HasSuffix(lower, ".tgz") {
+		return nil
+	}
+	return i18n.Errorf(messages.ConfigBundleFormatUnsupportedFmt, p)
+}
+
+func writeEntry(tw *tar.Writer, name string, data []byte, mode int64, modTime time.Time) error {
+	header := &tar.Header{
+		Name:     name,
+		Mode:     mode,
+		Size:     int64(len(data)),
+		ModTime:  modTime,
+		Typeflag: tar.TypeReg,
+		Format:   tar.FormatPAX,
+	}
+	if err := tw.WriteHeader(header); err != nil {
+		return err
+	}
+	_, err := tw.Write(data)
+	return err
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,223 @@
+package configbundle
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/conn-castle/agent-layer/internal/clock"
+)
+
+func writeRepoFile(t *testing.T, root string, rel string, content string, perm os.FileMode) {
+	t.Helper()
+	path := filepath.Join(root, filepath.FromSlash(rel))
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), perm); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func setupSourceRepo(t *testing.T) string {
+	t.Helper()
+	root := t.TempDir()
+	writeRepoFile(t, root, ".agent-layer/config.toml", "[approvals]\nmode = \"all\"\n", 0o644)
+	writeRepoFile(t, root, ".agent-layer/.env", "AL_SECRET=hunter2\n", 0o600)
+	writeRepoFile(t, root, ".agent-layer/commands.allow", "git status\n", 0o644)
+	writeRepoFile(t, root, ".agent-layer/claude-statusline.sh", "#!/bin/sh\n", 0o755)
+	writeRepoFile(t, root, ".agent-layer/instructions/00_rules.md", "rules", 0o644)
+	writeRepoFile(t, root, ".agent-layer/skills/review/SKILL.md", "review", 0o644)
+	writeRepoFile(t, root, ".agent-layer/scoped/services/00.md", "services", 0o644)
+	writeRepoFile(t, root, ".agent-layer/state/managed-baseline.json", "{}", 0o644)
+	writeRepoFile(t, root, ".agent-layer/tmp/runs/x.log", "x", 0o644)
+	return root
+}
+
+func TestExportImportRoundTrip(t *testing.T) {
+	src := setupSourceRepo(t)
+	bundle := filepath.Join(t.TempDir(), "bundle.tar.gz")
+	fixed := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	manifest, err := Export(src, bundle, ExportOptions{Version: "1.2.3", Clock: clock.Fixed{T: fixed}})
+	if err != nil {
+		t.Fatalf("Export error: %v", err)
+	}
+	if manifest.ALVersion != "1.2.3" || manifest.CreatedAt != "2026-03-01T12:00:00Z" || manifest.Format != FormatVersion {
+		t.Fatalf("manifest = %+v", manifest)
+	}
+	var paths []string
+	for _, file := range manifest.Files {
+		paths = append(paths, file.Path)
+	}
+	want := "claude-statusline.sh,commands.allow,config.toml,instructions/00_rules.md,scoped/services/00.md,skills/review/SKILL.md"
+	if strings.Join(paths, ",") != want {
+		t.Fatalf("paths = %v", paths)
+	}
+
+	dest := t.TempDir()
+	writeRepoFile(t, dest, ".agent-layer/config.toml", "old", 0o644)
+	writeRepoFile(t, dest, ".agent-layer/instructions/99_stale.md", "stale", 0o644)
+	writeRepoFile(t, dest, ".agent-layer/.env", "AL_KEEP=1\n", 0o600)
+	if _, err := Import(dest, bundle, false); err == nil || !strings.Contains(err.Error(), "--force") {
+		t.Fatalf("expected existing config error, got %v", err)
+	}
+	if _, err := Import(dest, bundle, true); err != nil {
+		t.Fatalf("Import error: %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(dest, ".agent-layer", "config.toml"))
+	if err != nil || !strings.Contains(string(data), "mode = \"all\"") {
+		t.Fatalf("config.toml = %q (%v)", data, err)
+	}
+	if _, err := os.Stat(filepath.Join(dest, ".agent-layer", "instructions", "99_stale.md")); !os.IsNotExist(err) {
+		t.Fatalf("expected stale instruction to be replaced, got %v", err)
+	}
+	if env, _ := os.ReadFile(filepath.Join(dest, ".agent-layer", ".env")); string(env) != "AL_KEEP=1\n" {
+		t.Fatalf(".env = %q, want local secrets kept", env)
+	}
+	info, err := os.Stat(filepath.Join(dest, ".agent-layer", "claude-statusline.sh"))
+	if err != nil || info.Mode().Perm()&0o100 == 0 {
+		t.Fatalf("expected executable statusline, got %v (%v)", info, err)
+	}
+}
+
+func TestImport_SeedsEnvInFreshRepo(t *testing.T) {
+	bundle := filepath.Join(t.TempDir(), "bundle.tar.gz")
+	if _, err := Export(setupSourceRepo(t), bundle, ExportOptions{Version: "dev"}); err != nil {
+		t.Fatal(err)
+	}
+	dest := t.TempDir()
+	if _, err := Import(dest, bundle, false); err != nil {
+		t.Fatalf("Import error: %v", err)
+	}
+	env, err := os.ReadFile(filepath.Join(dest, ".agent-layer", ".env"))
+	if err != nil || strings.Contains(string(env), "hunter2") {
+		t.Fatalf(".env = %q (%v)", env, err)
+	}
+}
+
+func writeBundle(t *testing.T, entries map[string]string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "bundle.tar.gz")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+	for name, content := range entries {
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0o644, Size: int64(len(content)), Typeflag: tar.TypeReg}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestImport_RejectsInvalidBundles(t *testing.T) {
+	configSum := sha256Hex([]byte("x"))
+	manifest := `{"format":1,"files":[{"path":"config.toml","sha256":"` + configSum + `"}]}`
+	cases := map[string]struct {
+		entries map[string]string
+		want    string
+	}{
+		"missing manifest": {map[string]string{".agent-layer/config.toml": "x"}, "missing manifest.json"},
+		"traversal":        {map[string]string{ManifestName: manifest, ".agent-layer/../evil": "x"}, "unexpected entry"},
+		"secrets":          {map[string]string{ManifestName: manifest, ".agent-layer/.env": "x"}, "unexpected entry"},
+		"checksum":         {map[string]string{ManifestName: manifest, ".agent-layer/config.toml": "y"}, "manifest checksum"},
+		"unlisted":         {map[string]string{ManifestName: manifest, ".agent-layer/config.toml": "x", ".agent-layer/commands.allow": "x"}, "not listed"},
+		"future format":    {map[string]string{ManifestName: `{"format":9}`}, "format 9"},
+		"environment":      {map[string]string{ManifestName: `{"format":1,"kind":"environment"}`}, "not a config bundle"},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			dest := t.TempDir()
+			_, err := Import(dest, writeBundle(t, tc.entries), false)
+			if err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Fatalf("expected %q error, got %v", tc.want, err)
+			}
+			if _, statErr := os.Stat(filepath.Join(dest, ".agent-layer")); !os.IsNotExist(statErr) {
+				t.Fatalf("expected nothing written, got %v", statErr)
+			}
+		})
+	}
+}
+
+func TestExport_Errors(t *testing.T) {
+	if _, err := Export(t.TempDir(), filepath.Join(t.TempDir(), "b.tar.gz"), ExportOptions{}); err == nil || !strings.Contains(err.Error(), "no Agent Layer config") {
+		t.Fatalf("expected missing config error, got %v", err)
+	}
+	for _, name := range []string{"bundle.tar.zst", "bundle.tar"} {
+		if _, err := Export(setupSourceRepo(t), name, ExportOptions{}); err == nil || !strings.Contains(err.Error(), ".tar.gz or .tgz") {
+			t.Fatalf("%s: expected format error, got %v", name, err)
+		}
+	}
+}
+
+func TestImport_RejectsNonGzipContent(t *testing.T) {
+	bundle := filepath.Join(t.TempDir(), "bundle.tgz")
+	// A zstd frame header.
+	if err := os.WriteFile(bundle, append([]byte{0x28, 0xb5, 0x2f, 0xfd}, make([]byte, 28)...), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Import(t.TempDir(), bundle, false); err == nil || !strings.Contains(err.Error(), "zstd and other formats") {
+		t.Fatalf("expected format error, got %v", err)
+	}
+}
+
+func TestImport_FailedSwapKeepsLocalConfig(t *testing.T) {
+	bundle := filepath.Join(t.TempDir(), "bundle.tar.gz")
+	if _, err := Export(setupSourceRepo(t), bundle, ExportOptions{Version: "dev"}); err != nil {
+		t.Fatal(err)
+	}
+	dest := t.TempDir()
+	writeRepoFile(t, dest, ".agent-layer/config.toml", "local", 0o644)
+	writeRepoFile(t, dest, ".agent-layer/instructions/00_local.md", "local rules", 0o644)
+	writeRepoFile(t, dest, ".agent-layer/skills/mine/SKILL.md", "mine", 0o644)
+	agentDir := filepath.Join(dest, ".agent-layer")
+
+	original := rename
+	t.Cleanup(func() { rename = original })
+	failed := false
+	rename = func(from string, to string) error {
+		if to == filepath.Join(agentDir, "skills") && !failed {
+			failed = true
+			return errors.New("boom")
+		}
+		return original(from, to)
+	}
+	if _, err := Import(dest, bundle, true); err == nil || !strings.Contains(err.Error(), "boom") {
+		t.Fatalf("expected swap error, got %v", err)
+	}
+	for rel, want := range map[string]string{
+		"config.toml":              "local",
+		"instructions/00_local.md": "local rules",
+		"skills/mine/SKILL.md":     "mine",
+	} {
+		data, err := os.ReadFile(filepath.Join(agentDir, filepath.FromSlash(rel)))
+		if err != nil || string(data) != want {
+			t.Fatalf("%s = %q (%v), want %q", rel, data, err, want)
+		}
+	}
+	for _, rel := range []string{"commands.allow", "instructions/00_rules.md", "tmp"} {
+		if _, err := os.Stat(filepath.Join(agentDir, filepath.FromSlash(rel))); !os.IsNotExist(err) {
+			t.Fatalf("%s left behind: %v", rel, err)
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package configbundle
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+
+	"github.com/pelletier/go-toml/v2"
+
+	"github.com/conn-castle/agent-layer/internal/clock"
+	"github.com/conn-castle/agent-layer/internal/config"
+	"github.com/conn-castle/agent-layer/internal/i18n"
+	"github.com/conn-castle/agent-layer/internal/messages"
+	"github.com/conn-castle/agent-layer/internal/outputdiff"
+	"github.com/conn-castle/agent-layer/internal/sync"
+)
+
+// KindEnvironment marks a manifest written by ExportEnvironment.
+const KindEnvironment = "environment"
+
+// Environment snapshot layout, relative to the archive root.
+const (
+	// SourcesDir holds the .agent-layer files a configuration bundle carries.
+	SourcesDir = ".agent-layer"
+	// ResolvedConfig is config.toml after defaults and extends are applied.
+	ResolvedConfig = "resolved/config.toml"
+	// ResolvedInstructions is the composed instruction document sync writes
+	// to AGENTS.md and CLAUDE.md.
+	ResolvedInstructions = "resolved/instructions.md"
+	// OutputsDir holds every file `al sync` generates, at its repo path.
+	OutputsDir = "outputs"
+)
+
+// renderOutputs is replaced in tests so snapshots do not run a full sync.
+var renderOutputs = outputdiff.Render
+
+// EnvironmentOptions controls ExportEnvironment.
+type EnvironmentOptions struct {
+	// Version is the running al version stamped into the manifest.
+	Version string
+	// Clock supplies the manifest timestamp; nil uses the real clock.
+	Clock clock.Clock
+	// IncludeSecrets adds .agent-layer/.env and renders outputs with its
+	// values. Without it, outputs carry ${KEY} placeholders instead.
+	IncludeSecrets bool
+}
+
+// ExportEnvironment writes a snapshot of the repo's whole agent environment
+// to dest: the configuration bundle files, the resolved config and composed
+// instructions, and the client outputs sync generates from them. Skills are
+// part of the configuration files. It returns the manifest.
+func ExportEnvironment(root string, dest string, opts EnvironmentOptions) (Manifest, error) {
+	if err := checkExtension(dest); err != nil {
+		return Manifest{}, err
+	}
+	agentDir := filepath.Join(root, SourcesDir)
+	if _, err := os.Stat(filepath.Join(agentDir, "config.toml")); err != nil {
+		return Manifest{}, i18n.Errorf(messages.ConfigBundleNoConfigFmt, agentDir, err)
+	}
+	rels, err := collect(agentDir)
+	if err != nil {
+		return Manifest{}, err
+	}
+	if opts.IncludeSecrets {
+		if _, err := os.Stat(filepath.Join(agentDir, ".env")); err == nil {
+			rels = append(rels, ".env")
+		}
+	}
+
+	contents := make(map[string][]byte)
+	executable := make(map[string]bool)
+	for _, rel := range rels {
+		full := filepath.Join(agentDir, filepath.FromSlash(rel))
+		info, err := os.Stat(full)
+		if err != nil {
+			return Manifest{}, i18n.Errorf(messages.ConfigBundleReadFmt, full, err)
+		}
+		data, err := os.ReadFile(full) // #nosec G304 -- paths come from walking .agent-layer under the repo root.
+		if err != nil {
+			return Manifest{}, i18n.Errorf(messages.ConfigBundleReadFmt, full, err)
+		}
+		name := path.Join(SourcesDir, rel)
+		contents[name] = data
+		executable[name] = info.Mode().Perm()&0o111 != 0
+	}
+
+	project, err := config.LoadProjectConfig(root)
+	if err != nil {
+		return Manifest{}, i18n.Errorf(messages.ConfigBundleResolveFmt, root, err)
+	}
+	project, err = sync.ResolveInstructionVariables(root, project)
+	if err != nil {
+		return Manifest{}, i18n.Errorf(messages.ConfigBundleResolveFmt, root, err)
+	}
+	resolved, err := toml.Marshal(project.Config)
+	if err != nil {
+		return Manifest{}, i18n.Errorf(messages.ConfigBundleResolveFmt, root, err)
+	}
+	contents[ResolvedConfig] = resolved
+	contents[ResolvedInstructions] = []byte(sync.InstructionDocument(project.Instructions, project.ScopedInstructions))
+
+	outputs, err := renderOutputs(root, !opts.IncludeSecrets)
+	if err != nil {
+		return Manifest{}, i18n.Errorf(messages.ConfigBundleRenderFmt, err)
+	}
+	for rel, content := range outputs {
+		contents[path.Join(OutputsDir, rel)] = []byte(content)
+	}
+
+	names := make([]string, 0, len(contents))
+	for name := range contents {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	manifest := Manifest{
+		Format:    FormatVersion,
+		Kind:      KindEnvironment,
+		ALVersion: opts.Version,
+		CreatedAt: clock.Format(clock.Or(opts.Clock).Now()),
+		Secrets:   opts.IncludeSecrets,
+		Files:     make([]File, 0, len(names)),
+	}
+	for _, name := range names {
+		manifest.Files = append(manifest.Files, File{Path: name, SHA256: sha256Hex(contents[name]), Executable: executable[name]})
+	}
+	if err := writeArchive(dest, manifest, contents, func(name string) string { return name }); err != nil {
+		return Manifest{}, err
+	}
+	return manifest, nil
+}
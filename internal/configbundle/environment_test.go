@@ -0,0 +1,116 @@
+package configbundle
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/conn-castle/agent-layer/internal/clock"
+	"github.com/conn-castle/agent-layer/internal/templates"
+)
+
+func readArchive(t *testing.T, path string) map[string]string {
+	t.Helper()
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = f.Close() }()
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tr := tar.NewReader(gz)
+	entries := make(map[string]string)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		entries[header.Name] = string(data)
+	}
+	return entries
+}
+
+func TestExportEnvironment(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	root := setupSourceRepo(t)
+	config, err := templates.Read("config.toml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeRepoFile(t, root, ".agent-layer/config.toml", string(config), 0o644)
+	writeRepoFile(t, root, ".agent-layer/skills/review/SKILL.md", "---\nname: review\ndescription: Review a change.\n---\n\nreview\n", 0o644)
+
+	original := renderOutputs
+	var gotRedact []bool
+	renderOutputs = func(gotRoot string, redact bool) (map[string]string, error) {
+		if gotRoot != root {
+			t.Fatalf("Render root = %q", gotRoot)
+		}
+		gotRedact = append(gotRedact, redact)
+		return map[string]string{"CLAUDE.md": "generated", ".codex/config.toml": "codex"}, nil
+	}
+	t.Cleanup(func() { renderOutputs = original })
+
+	fixed := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	dest := t.TempDir() + "/env.tar.gz"
+	manifest, err := ExportEnvironment(root, dest, EnvironmentOptions{Version: "1.2.3", Clock: clock.Fixed{T: fixed}})
+	if err != nil {
+		t.Fatalf("ExportEnvironment: %v", err)
+	}
+	if manifest.Kind != KindEnvironment || manifest.Secrets || manifest.CreatedAt != "2026-03-01T12:00:00Z" {
+		t.Fatalf("manifest = %+v", manifest)
+	}
+	entries := readArchive(t, dest)
+	for _, want := range []string{ManifestName, ".agent-layer/config.toml", ".agent-layer/skills/review/SKILL.md", ResolvedConfig, ResolvedInstructions, "outputs/CLAUDE.md", "outputs/.codex/config.toml"} {
+		if _, ok := entries[want]; !ok {
+			t.Fatalf("missing %s in %v", want, entries)
+		}
+	}
+	for name := range entries {
+		if strings.HasSuffix(name, ".env") || strings.Contains(name, "/state/") {
+			t.Fatalf("unexpected entry %s", name)
+		}
+	}
+	if !strings.Contains(entries[ResolvedInstructions], "<!-- BEGIN: 00_rules.md -->\nrules") {
+		t.Fatalf("unexpected composed instructions:\n%s", entries[ResolvedInstructions])
+	}
+	if !strings.Contains(entries[ResolvedConfig], "[approvals]") {
+		t.Fatalf("unexpected resolved config:\n%s", entries[ResolvedConfig])
+	}
+	var listed Manifest
+	if err := json.Unmarshal([]byte(entries[ManifestName]), &listed); err != nil || len(listed.Files) != len(entries)-1 {
+		t.Fatalf("manifest lists %d files for %d entries (%v)", len(listed.Files), len(entries)-1, err)
+	}
+	if _, err := Import(t.TempDir(), dest, true); err == nil || !strings.Contains(err.Error(), "not a config bundle") {
+		t.Fatalf("expected import-config to refuse the snapshot, got %v", err)
+	}
+
+	manifest, err = ExportEnvironment(root, dest, EnvironmentOptions{IncludeSecrets: true})
+	if err != nil {
+		t.Fatalf("ExportEnvironment(secrets): %v", err)
+	}
+	if !manifest.Secrets || readArchive(t, dest)[".agent-layer/.env"] != "AL_SECRET=hunter2\n" {
+		t.Fatalf("expected .env in secrets snapshot, manifest %+v", manifest)
+	}
+	if len(gotRedact) != 2 || !gotRedact[0] || gotRedact[1] {
+		t.Fatalf("redact flags = %v", gotRedact)
+	}
+
+	if _, err := ExportEnvironment(t.TempDir(), dest, EnvironmentOptions{}); err == nil || !strings.Contains(err.Error(), "no Agent Layer config") {
+		t.Fatalf("expected missing config error, got %v", err)
+	}
+}
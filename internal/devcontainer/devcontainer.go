@@ -0,0 +1,178 @@
+// Package devcontainer renders a local devcontainer feature that installs the
+// repo's pinned al release and runs `al sync` when the container is created,
+// plus the devcontainer.json fragment that enables the feature and exports the
+// environment Agent Layer's launchers would otherwise set.
+package devcontainer
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/conn-castle/agent-layer/internal/config"
+	"github.com/conn-castle/agent-layer/internal/i18n"
+	"github.com/conn-castle/agent-layer/internal/layerdir"
+	"github.com/conn-castle/agent-layer/internal/messages"
+	"github.com/conn-castle/agent-layer/internal/version"
+)
+
+// FeatureID is the feature's id and its directory name under .devcontainer/.
+const FeatureID = "agent-layer"
+
+// workspaceVar is the devcontainer variable for the repo root in the container.
+const workspaceVar = "${containerWorkspaceFolder}"
+
+// installScriptFmt is the feature's install.sh; %s is the default version.
+// The devcontainer CLI exports the version option as VERSION.
+const installScriptFmt = `#!/usr/bin/env bash
+# Generated by al devcontainer generate; rerun it after changing .agent-layer/al.version.
+set -euo pipefail
+
+VERSION="${VERSION:-%s}"
+VERSION="${VERSION#v}"
+
+if ! command -v curl >/dev/null 2>&1; then
+  echo "agent-layer feature: curl is required; add the common-utils feature or install curl in the image" >&2
+  exit 1
+fi
+
+curl -fsSL "https://github.com/conn-castle/agent-layer/releases/download/v${VERSION}/al-install.sh" \
+  | bash -s -- --version "v${VERSION}" --prefix /usr/local --no-completions
+`
+
+// Result describes what Generate wrote.
+type Result struct {
+	// Version is the al release the feature installs.
+	Version string
+	// Files lists the written files as repo-relative slash paths.
+	Files []string
+	// Fragment is the devcontainer.json snippet that enables the feature.
+	Fragment string
+}
+
+// Dir returns the feature directory for root.
+func Dir(root string) string {
+	return filepath.Join(root, ".devcontainer", FeatureID)
+}
+
+// PinnedVersion reads .agent-layer/al.version. It returns "" when the repo
+// does not pin a version.
+func PinnedVersion(root string) (string, error) {
+	path := filepath.Join(layerdir.Dir(root), "al.version")
+	data, err := os.ReadFile(path) // #nosec G304 -- path is the fixed pin file under the repo's .agent-layer/.
+	if errors.Is(err, os.ErrNotExist) {
+		return "", nil
+	}
+	if err != nil {
+		return "", i18n.Errorf(messages.DevcontainerReadPinFmt, path, err)
+	}
+	raw := strings.TrimSpace(string(data))
+	if raw == "" {
+		return "", nil
+	}
+	normalized, err := version.Normalize(raw)
+	if err != nil {
+		return "", i18n.Errorf(messages.DevcontainerInvalidPinFmt, path, err)
+	}
+	return normalized, nil
+}
+
+// Env returns the variables `al <client>` would export for cfg, with paths
+// rooted at the container workspace.
+func Env(cfg config.Config) map[string]string {
+	env := make(map[string]string)
+	if config.CodexLocalConfigDirEnabled(cfg.Agents.Codex) {
+		env["CODEX_HOME"] = workspaceVar + "/.codex"
+	}
+	if cfg.Agents.Claude.LocalConfigDir != nil && *cfg.Agents.Claude.LocalConfigDir {
+		env["CLAUDE_CONFIG_DIR"] = workspaceVar + "/.claude-config"
+	}
+	return env
+}
+
+// Generate writes the feature for al release alVersion into root's
+// .devcontainer/agent-layer/, replacing any earlier output.
+func Generate(root string, alVersion string, env map[string]string) (Result, error) {
+	normalized, err := version.Normalize(alVersion)
+	if err != nil {
+		return Result{}, i18n.Errorf(messages.DevcontainerVersionFmt, alVersion, err)
+	}
+	feature, err := featureJSON(normalized)
+	if err != nil {
+		return Result{}, err
+	}
+	fragment, err := fragmentJSON(env)
+	if err != nil {
+		return Result{}, err
+	}
+
+	dir := Dir(root)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return Result{}, i18n.Errorf(messages.DevcontainerWriteFmt, dir, err)
+	}
+	files := []struct {
+		name    string
+		content []byte
+		mode    os.FileMode
+	}{
+		{"devcontainer-feature.json", feature, 0o644},
+		{"install.sh", []byte(fmt.Sprintf(installScriptFmt, normalized)), 0o755},
+	}
+	result := Result{Version: normalized, Fragment: string(fragment)}
+	for _, file := range files {
+		path := filepath.Join(dir, file.name)
+		if err := os.WriteFile(path, file.content, file.mode); err != nil {
+			return Result{}, i18n.Errorf(messages.DevcontainerWriteFmt, path, err)
+		}
+		// WriteFile keeps the mode of an existing file; the script must stay executable.
+		if err := os.Chmod(path, file.mode); err != nil {
+			return Result{}, i18n.Errorf(messages.DevcontainerWriteFmt, path, err)
+		}
+		result.Files = append(result.Files, filepath.ToSlash(filepath.Join(".devcontainer", FeatureID, file.name)))
+	}
+	return result, nil
+}
+
+func featureJSON(alVersion string) ([]byte, error) {
+	feature := map[string]any{
+		"id":          FeatureID,
+		"version":     "1.0.0",
+		"name":        "Agent Layer",
+		"description": "Installs the al CLI pinned by .agent-layer/al.version and runs al sync on container create.",
+		"options": map[string]any{
+			"version": map[string]any{
+				"type":        "string",
+				"default":     alVersion,
+				"description": "al release to install (X.Y.Z).",
+			},
+		},
+		"postCreateCommand": "al sync",
+		"installsAfter":     []string{"ghcr.io/devcontainers/features/common-utils"},
+	}
+	return marshal(feature)
+}
+
+func fragmentJSON(env map[string]string) ([]byte, error) {
+	fragment := map[string]any{
+		"features": map[string]any{"./" + FeatureID: map[string]any{}},
+	}
+	if len(env) > 0 {
+		fragment["remoteEnv"] = env
+	}
+	return marshal(fragment)
+}
+
+func marshal(value any) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(value); err != nil {
+		return nil, i18n.Errorf(messages.DevcontainerMarshalFmt, err)
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,125 @@
+package devcontainer
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/conn-castle/agent-layer/internal/config"
+)
+
+func TestGenerate(t *testing.T) {
+	root := t.TempDir()
+	result, err := Generate(root, "v1.2.3", map[string]string{"CODEX_HOME": "${containerWorkspaceFolder}/.codex"})
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+	if result.Version != "1.2.3" {
+		t.Fatalf("expected normalized version, got %q", result.Version)
+	}
+	if !reflect.DeepEqual(result.Files, []string{".devcontainer/agent-layer/devcontainer-feature.json", ".devcontainer/agent-layer/install.sh"}) {
+		t.Fatalf("unexpected files %v", result.Files)
+	}
+
+	data, err := os.ReadFile(filepath.Join(Dir(root), "devcontainer-feature.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var feature struct {
+		ID      string `json:"id"`
+		Options map[string]struct {
+			Default string `json:"default"`
+		} `json:"options"`
+		PostCreateCommand string `json:"postCreateCommand"`
+	}
+	if err := json.Unmarshal(data, &feature); err != nil {
+		t.Fatalf("decode feature: %v", err)
+	}
+	if feature.ID != FeatureID || feature.Options["version"].Default != "1.2.3" || feature.PostCreateCommand != "al sync" {
+		t.Fatalf("unexpected feature %+v", feature)
+	}
+
+	script := filepath.Join(Dir(root), "install.sh")
+	data, err = os.ReadFile(script)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{`VERSION="${VERSION:-1.2.3}"`, "releases/download/v${VERSION}/al-install.sh", "--prefix /usr/local"} {
+		if !strings.Contains(string(data), want) {
+			t.Fatalf("install.sh missing %q:\n%s", want, data)
+		}
+	}
+	info, err := os.Stat(script)
+	if err != nil || info.Mode().Perm() != 0o755 {
+		t.Fatalf("expected executable install.sh, got %v %v", info, err)
+	}
+
+	var fragment map[string]any
+	if err := json.Unmarshal([]byte(result.Fragment), &fragment); err != nil {
+		t.Fatalf("decode fragment: %v", err)
+	}
+	want := map[string]any{
+		"features":  map[string]any{"./agent-layer": map[string]any{}},
+		"remoteEnv": map[string]any{"CODEX_HOME": "${containerWorkspaceFolder}/.codex"},
+	}
+	if !reflect.DeepEqual(fragment, want) {
+		t.Fatalf("unexpected fragment %#v", fragment)
+	}
+}
+
+func TestGenerate_NoEnvAndInvalidVersion(t *testing.T) {
+	root := t.TempDir()
+	result, err := Generate(root, "1.0.0", nil)
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+	if strings.Contains(result.Fragment, "remoteEnv") {
+		t.Fatalf("expected no remoteEnv without env, got %s", result.Fragment)
+	}
+	if _, err := Generate(root, "dev", nil); err == nil || !strings.Contains(err.Error(), "invalid al version") {
+		t.Fatalf("expected version error, got %v", err)
+	}
+}
+
+func TestPinnedVersion(t *testing.T) {
+	root := t.TempDir()
+	if got, err := PinnedVersion(root); err != nil || got != "" {
+		t.Fatalf("expected no pin, got %q %v", got, err)
+	}
+	dir := filepath.Join(root, ".agent-layer")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "al.version"), []byte("v0.9.1\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := PinnedVersion(root); err != nil || got != "0.9.1" {
+		t.Fatalf("expected 0.9.1, got %q %v", got, err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "al.version"), []byte("latest\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := PinnedVersion(root); err == nil || !strings.Contains(err.Error(), "invalid pinned version") {
+		t.Fatalf("expected pin error, got %v", err)
+	}
+}
+
+func TestEnv(t *testing.T) {
+	enabled := true
+	var cfg config.Config
+	if env := Env(cfg); len(env) != 0 {
+		t.Fatalf("expected no env by default, got %v", env)
+	}
+	cfg.Agents.Codex.LocalConfigDir = &enabled
+	cfg.Agents.Claude.LocalConfigDir = &enabled
+	want := map[string]string{
+		"CODEX_HOME":        "${containerWorkspaceFolder}/.codex",
+		"CLAUDE_CONFIG_DIR": "${containerWorkspaceFolder}/.claude-config",
+	}
+	if env := Env(cfg); !reflect.DeepEqual(env, want) {
+		t.Fatalf("unexpected env %v", env)
+	}
+}
@@ -15,6 +15,9 @@ import (
 	"unicode/utf8"
 
 	"github.com/conn-castle/agent-layer/internal/config"
+	"github.com/conn-castle/agent-layer/internal/i18n"
+	"github.com/conn-castle/agent-layer/internal/install"
+	"github.com/conn-castle/agent-layer/internal/layerdir"
 	"github.com/conn-castle/agent-layer/internal/messages"
 	"github.com/conn-castle/agent-layer/internal/skillvalidator"
 	"github.com/conn-castle/agent-layer/internal/warnings"
@@ -27,6 +30,7 @@ var (
 	commandOutputFunc     = func(name string, args ...string) ([]byte, error) {
 		return commandOutputWithTimeout(antigravityVersionTimeout, name, args...)
 	}
+	readInterruptedOperationFunc = install.ReadInterruptedOperation
 )
 
 const antigravityVersionTimeout = 5 * time.Second
@@ -115,7 +119,7 @@ func CheckStructure(root string) []Result {
 	}
 
 	for _, entry := range paths {
-		fullPath := filepath.Join(root, entry.path)
+		fullPath := layerdir.Path(root, entry.path)
 		info, err := os.Stat(fullPath)
 		if err != nil {
 			if !entry.required && errors.Is(err, os.ErrNotExist) {
@@ -124,7 +128,7 @@ func CheckStructure(root string) []Result {
 					results = append(results, Result{
 						Status:         StatusFail,
 						CheckName:      messages.DoctorCheckNameStructure,
-						Message:        fmt.Sprintf(messages.DoctorInstructionReferenceScanFailedFmt, scanErr),
+						Message:        i18n.Sprintf(messages.DoctorInstructionReferenceScanFailedFmt, scanErr),
 						Recommendation: messages.DoctorInstructionReferenceScanFailedRecommend,
 					})
 				}
@@ -133,11 +137,11 @@ func CheckStructure(root string) []Result {
 				}
 			}
 			status := StatusWarn
-			message := fmt.Sprintf(messages.DoctorMissingOptionalDirFmt, entry.path)
-			recommendation := fmt.Sprintf(messages.DoctorMissingOptionalDirRecommend, entry.path)
+			message := i18n.Sprintf(messages.DoctorMissingOptionalDirFmt, entry.path)
+			recommendation := i18n.Sprintf(messages.DoctorMissingOptionalDirRecommend, entry.path)
 			if entry.required {
 				status = StatusFail
-				message = fmt.Sprintf(messages.DoctorMissingRequiredDirFmt, entry.path)
+				message = i18n.Sprintf(messages.DoctorMissingRequiredDirFmt, entry.path)
 				recommendation = messages.DoctorMissingRequiredDirRecommend
 			}
 			results = append(results, Result{
@@ -152,7 +156,7 @@ func CheckStructure(root string) []Result {
 			results = append(results, Result{
 				Status:         StatusFail,
 				CheckName:      messages.DoctorCheckNameStructure,
-				Message:        fmt.Sprintf(messages.DoctorPathNotDirFmt, entry.path),
+				Message:        i18n.Sprintf(messages.DoctorPathNotDirFmt, entry.path),
 				Recommendation: messages.DoctorPathNotDirRecommend,
 			})
 			continue
@@ -160,14 +164,14 @@ func CheckStructure(root string) []Result {
 		results = append(results, Result{
 			Status:    StatusOK,
 			CheckName: messages.DoctorCheckNameStructure,
-			Message:   fmt.Sprintf(messages.DoctorDirExistsFmt, entry.path),
+			Message:   i18n.Sprintf(messages.DoctorDirExistsFmt, entry.path),
 		})
 	}
 	return results
 }
 
 func instructionsReferencePath(root string, relPath string) (bool, error) {
-	instructionsDir := filepath.Join(root, ".agent-layer", "instructions")
+	instructionsDir := filepath.Join(layerdir.Dir(root), "instructions")
 	entries, err := os.ReadDir(instructionsDir)
 	if err != nil {
 		if errors.Is(err, os.ErrNotExist) {
@@ -213,7 +217,7 @@ func CheckConfig(root string) ([]Result, *config.ProjectConfig) {
 			results = append(results, Result{
 				Status:         StatusFail,
 				CheckName:      messages.DoctorCheckNameConfig,
-				Message:        fmt.Sprintf(messages.DoctorConfigLoadFailedFmt, err),
+				Message:        i18n.Sprintf(messages.DoctorConfigLoadFailedFmt, err),
 				Recommendation: messages.DoctorConfigLoadRecommend,
 			})
 			return results, nil
@@ -221,14 +225,14 @@ func CheckConfig(root string) ([]Result, *config.ProjectConfig) {
 
 		// Config has validation errors. Try lenient loading so downstream
 		// checks (secrets, agents) can still run.
-		configPath := filepath.Join(root, ".agent-layer", "config.toml")
+		configPath := filepath.Join(layerdir.Dir(root), "config.toml")
 		lenientCfg, lenientErr := loadConfigLenientFunc(configPath)
 		if lenientErr != nil {
 			// TOML syntax error or file unreadable — can't recover.
 			results = append(results, Result{
 				Status:         StatusFail,
 				CheckName:      messages.DoctorCheckNameConfig,
-				Message:        fmt.Sprintf(messages.DoctorConfigLoadFailedFmt, lenientErr),
+				Message:        i18n.Sprintf(messages.DoctorConfigLoadFailedFmt, lenientErr),
 				Recommendation: messages.DoctorConfigLoadRecommend,
 			})
 			return results, nil
@@ -237,13 +241,13 @@ func CheckConfig(root string) ([]Result, *config.ProjectConfig) {
 		// Lenient loading succeeded — report the validation error but return
 		// a partial config so downstream checks can still run.
 		configRelPath := relPathForDoctor(root, configPath)
-		message := fmt.Sprintf(messages.DoctorConfigLoadFailedFmt, err)
+		message := i18n.Sprintf(messages.DoctorConfigLoadFailedFmt, err)
 		recommendation := messages.DoctorConfigLoadLenientRecommend
 		if errors.Is(err, config.ErrConfigNeedsUpgrade) {
 			recommendation = messages.DoctorConfigNeedsUpgradeRecommend
 		}
 		if details, detailsErr := configUnknownKeys(configPath); detailsErr == nil && len(details) > 0 {
-			message = fmt.Sprintf(messages.DoctorConfigLoadFailedFmt, summarizeUnknownKeys(details))
+			message = i18n.Sprintf(messages.DoctorConfigLoadFailedFmt, summarizeUnknownKeys(details))
 			if errors.Is(err, config.ErrConfigNeedsUpgrade) && allConfigUnknownKeysNeedUpgrade(details) {
 				recommendation = formatNeedsUpgradeRecommendation(configRelPath, details)
 			} else {
@@ -265,7 +269,7 @@ func CheckConfig(root string) ([]Result, *config.ProjectConfig) {
 		// environment), but a malformed/unreadable .env must be surfaced loudly:
 		// silently swallowing it produces a misleading "Missing secret" cascade
 		// from CheckSecrets that points at the wrong root cause.
-		envPath := filepath.Join(root, ".agent-layer", ".env")
+		envPath := filepath.Join(layerdir.Dir(root), ".env")
 		var env map[string]string
 		if loaded, envErr := loadEnvFunc(envPath); envErr == nil {
 			env = loaded
@@ -273,7 +277,7 @@ func CheckConfig(root string) ([]Result, *config.ProjectConfig) {
 			results = append(results, Result{
 				Status:         StatusFail,
 				CheckName:      messages.DoctorCheckNameSecrets,
-				Message:        fmt.Sprintf(messages.DoctorEnvFileUnreadableFmt, envErr),
+				Message:        i18n.Sprintf(messages.DoctorEnvFileUnreadableFmt, envErr),
 				Recommendation: messages.DoctorEnvFileUnreadableRecommend,
 			})
 		}
@@ -291,14 +295,14 @@ func CheckConfig(root string) ([]Result, *config.ProjectConfig) {
 			results = append(results, Result{
 				Status:         StatusFail,
 				CheckName:      messages.DoctorCheckNameSkills,
-				Message:        fmt.Sprintf(messages.DoctorSkillsLoadFailedFmt, skillsRelPath, statErr),
+				Message:        i18n.Sprintf(messages.DoctorSkillsLoadFailedFmt, skillsRelPath, statErr),
 				Recommendation: messages.DoctorSkillValidationRecommend,
 			})
 		case !skillsDirInfo.IsDir():
 			results = append(results, Result{
 				Status:         StatusFail,
 				CheckName:      messages.DoctorCheckNameSkills,
-				Message:        fmt.Sprintf(messages.DoctorSkillsLoadFailedFmt, skillsRelPath, fmt.Sprintf(messages.DoctorPathNotDirFmt, skillsRelPath)),
+				Message:        i18n.Sprintf(messages.DoctorSkillsLoadFailedFmt, skillsRelPath, i18n.Sprintf(messages.DoctorPathNotDirFmt, skillsRelPath)),
 				Recommendation: messages.DoctorSkillValidationRecommend,
 			})
 		default:
@@ -307,7 +311,7 @@ func CheckConfig(root string) ([]Result, *config.ProjectConfig) {
 				results = append(results, Result{
 					Status:         StatusFail,
 					CheckName:      messages.DoctorCheckNameSkills,
-					Message:        fmt.Sprintf(messages.DoctorSkillsLoadFailedFmt, skillsRelPath, skillsErr),
+					Message:        i18n.Sprintf(messages.DoctorSkillsLoadFailedFmt, skillsRelPath, skillsErr),
 					Recommendation: messages.DoctorSkillValidationRecommend,
 				})
 			} else {
@@ -347,21 +351,21 @@ func CheckSecrets(cfg *config.ProjectConfig) []Result {
 				results = append(results, Result{
 					Status:         StatusFail,
 					CheckName:      messages.DoctorCheckNameSecrets,
-					Message:        fmt.Sprintf(messages.DoctorMissingSecretFmt, secret),
-					Recommendation: fmt.Sprintf(messages.DoctorMissingSecretRecommendFmt, secret),
+					Message:        i18n.Sprintf(messages.DoctorMissingSecretFmt, secret),
+					Recommendation: i18n.Sprintf(messages.DoctorMissingSecretRecommendFmt, secret),
 				})
 			} else {
 				results = append(results, Result{
 					Status:    StatusOK,
 					CheckName: messages.DoctorCheckNameSecrets,
-					Message:   fmt.Sprintf(messages.DoctorSecretFoundEnvFmt, secret),
+					Message:   i18n.Sprintf(messages.DoctorSecretFoundEnvFmt, secret),
 				})
 			}
 		} else {
 			results = append(results, Result{
 				Status:    StatusOK,
 				CheckName: messages.DoctorCheckNameSecrets,
-				Message:   fmt.Sprintf(messages.DoctorSecretFoundEnvFileFmt, secret),
+				Message:   i18n.Sprintf(messages.DoctorSecretFoundEnvFileFmt, secret),
 			})
 		}
 	}
@@ -397,13 +401,13 @@ func CheckAgents(cfg *config.ProjectConfig) []Result {
 			results = append(results, Result{
 				Status:    StatusOK,
 				CheckName: messages.DoctorCheckNameAgents,
-				Message:   fmt.Sprintf(messages.DoctorAgentEnabledFmt, a.Name),
+				Message:   i18n.Sprintf(messages.DoctorAgentEnabledFmt, a.Name),
 			})
 		} else {
 			results = append(results, Result{
 				Status:    StatusOK,
 				CheckName: messages.DoctorCheckNameAgents,
-				Message:   fmt.Sprintf(messages.DoctorAgentDisabledFmt, a.Name),
+				Message:   i18n.Sprintf(messages.DoctorAgentDisabledFmt, a.Name),
 			})
 		}
 	}
@@ -429,7 +433,7 @@ func CheckAntigravityBinary() []Result {
 		return []Result{{
 			Status:         StatusFail,
 			CheckName:      messages.DoctorCheckNameAgents,
-			Message:        fmt.Sprintf(messages.DoctorAntigravityVersionFailedFmt, err),
+			Message:        i18n.Sprintf(messages.DoctorAntigravityVersionFailedFmt, err),
 			Recommendation: messages.DoctorAntigravityInstallRecommend,
 		}}
 	}
@@ -442,7 +446,7 @@ func CheckAntigravityBinary() []Result {
 		return []Result{{
 			Status:         StatusFail,
 			CheckName:      messages.DoctorCheckNameAgents,
-			Message:        fmt.Sprintf(messages.DoctorAntigravityVersionUnknownFmt, strings.TrimSpace(versionText)),
+			Message:        i18n.Sprintf(messages.DoctorAntigravityVersionUnknownFmt, strings.TrimSpace(versionText)),
 			Recommendation: messages.DoctorAntigravityInstallRecommend,
 		}}
 	}
@@ -451,7 +455,7 @@ func CheckAntigravityBinary() []Result {
 		return []Result{{
 			Status:         StatusFail,
 			CheckName:      messages.DoctorCheckNameAgents,
-			Message:        fmt.Sprintf(messages.DoctorAntigravityVersionUnknownFmt, strings.TrimSpace(versionText)),
+			Message:        i18n.Sprintf(messages.DoctorAntigravityVersionUnknownFmt, strings.TrimSpace(versionText)),
 			Recommendation: messages.DoctorAntigravityInstallRecommend,
 		}}
 	}
@@ -459,14 +463,14 @@ func CheckAntigravityBinary() []Result {
 		return []Result{{
 			Status:         StatusFail,
 			CheckName:      messages.DoctorCheckNameAgents,
-			Message:        fmt.Sprintf(messages.DoctorAntigravityVersionTooOldFmt, versionValue),
+			Message:        i18n.Sprintf(messages.DoctorAntigravityVersionTooOldFmt, versionValue),
 			Recommendation: messages.DoctorAntigravityInstallRecommend,
 		}}
 	}
 	return []Result{{
 		Status:    StatusOK,
 		CheckName: messages.DoctorCheckNameAgents,
-		Message:   fmt.Sprintf(messages.DoctorAntigravityVersionOKFmt, versionValue),
+		Message:   i18n.Sprintf(messages.DoctorAntigravityVersionOKFmt, versionValue),
 	}}
 }
 
@@ -514,10 +518,32 @@ func parseDoctorSemver(raw string) ([3]int, error) {
 	return parsed, nil
 }
 
+// CheckInstallJournal reports an init or upgrade that stopped partway, as
+// recorded in the install journal. It returns nothing when none did.
+func CheckInstallJournal(root string) []Result {
+	op, err := readInterruptedOperationFunc(root, install.RealSystem{})
+	if err != nil {
+		return []Result{{
+			Status:    StatusFail,
+			CheckName: messages.DoctorCheckNameInstallJournal,
+			Message:   i18n.Sprintf(messages.DoctorInstallJournalReadFailedFmt, err),
+		}}
+	}
+	if op == nil {
+		return nil
+	}
+	return []Result{{
+		Status:         StatusFail,
+		CheckName:      messages.DoctorCheckNameInstallJournal,
+		Message:        install.InterruptedOperationError(op).Error(),
+		Recommendation: messages.DoctorInstallJournalRecommend,
+	}}
+}
+
 // CheckFlatFormatSkills scans .agent-layer/skills/ for stale flat-format .md files
 // at the root level. Returns a FAIL result for each, recommending `al upgrade`.
 func CheckFlatFormatSkills(root string) []Result {
-	skillsDir := filepath.Join(root, ".agent-layer", "skills")
+	skillsDir := filepath.Join(layerdir.Dir(root), "skills")
 	info, err := os.Stat(skillsDir)
 	if err != nil {
 		if errors.Is(err, os.ErrNotExist) {
@@ -526,7 +552,7 @@ func CheckFlatFormatSkills(root string) []Result {
 		return []Result{{
 			Status:         StatusFail,
 			CheckName:      messages.DoctorCheckNameFlatSkills,
-			Message:        fmt.Sprintf(messages.DoctorSkillFlatFormatScanFailedFmt, skillsDir, err),
+			Message:        i18n.Sprintf(messages.DoctorSkillFlatFormatScanFailedFmt, skillsDir, err),
 			Recommendation: messages.DoctorSkillFlatFormatScanRecommend,
 		}}
 	}
@@ -534,7 +560,7 @@ func CheckFlatFormatSkills(root string) []Result {
 		return []Result{{
 			Status:         StatusFail,
 			CheckName:      messages.DoctorCheckNameFlatSkills,
-			Message:        fmt.Sprintf(messages.DoctorPathNotDirFmt, ".agent-layer/skills"),
+			Message:        i18n.Sprintf(messages.DoctorPathNotDirFmt, ".agent-layer/skills"),
 			Recommendation: messages.DoctorPathNotDirRecommend,
 		}}
 	}
@@ -544,7 +570,7 @@ func CheckFlatFormatSkills(root string) []Result {
 		return []Result{{
 			Status:         StatusFail,
 			CheckName:      messages.DoctorCheckNameFlatSkills,
-			Message:        fmt.Sprintf(messages.DoctorSkillFlatFormatScanFailedFmt, skillsDir, readErr),
+			Message:        i18n.Sprintf(messages.DoctorSkillFlatFormatScanFailedFmt, skillsDir, readErr),
 			Recommendation: messages.DoctorSkillFlatFormatScanRecommend,
 		}}
 	}
@@ -560,7 +586,7 @@ func CheckFlatFormatSkills(root string) []Result {
 		results = append(results, Result{
 			Status:         StatusFail,
 			CheckName:      messages.DoctorCheckNameFlatSkills,
-			Message:        fmt.Sprintf(messages.DoctorSkillFlatFormatDetectedFmt, entry.Name()),
+			Message:        i18n.Sprintf(messages.DoctorSkillFlatFormatDetectedFmt, entry.Name()),
 			Recommendation: messages.DoctorSkillFlatFormatRecommend,
 		})
 	}
@@ -595,7 +621,7 @@ func CheckSkills(cfg *config.ProjectConfig) []Result {
 			results = append(results, Result{
 				Status:         StatusFail,
 				CheckName:      messages.DoctorCheckNameSkills,
-				Message:        fmt.Sprintf(messages.DoctorSkillValidationFailedFmt, relPathForDoctor(cfg.Root, skill.SourcePath), err),
+				Message:        i18n.Sprintf(messages.DoctorSkillValidationFailedFmt, relPathForDoctor(cfg.Root, skill.SourcePath), err),
 				Recommendation: messages.DoctorSkillValidationRecommend,
 			})
 			continue
@@ -608,18 +634,27 @@ func CheckSkills(cfg *config.ProjectConfig) []Result {
 			results = append(results, Result{
 				Status:         StatusWarn,
 				CheckName:      messages.DoctorCheckNameSkills,
-				Message:        fmt.Sprintf(messages.DoctorSkillValidationWarnFmt, relPathForDoctor(cfg.Root, finding.Path), finding.Message),
+				Message:        i18n.Sprintf(messages.DoctorSkillValidationWarnFmt, relPathForDoctor(cfg.Root, finding.Path), finding.Message),
 				Recommendation: messages.DoctorSkillValidationRecommend,
 			})
 		}
 	}
 
+	if err := config.CheckSkillRequires(cfg.Skills); err != nil {
+		results = append(results, Result{
+			Status:         StatusFail,
+			CheckName:      messages.DoctorCheckNameSkills,
+			Message:        i18n.Sprintf(messages.DoctorSkillRequiresInvalidFmt, err),
+			Recommendation: messages.DoctorSkillRequiresRecommend,
+		})
+	}
+
 	catalogText, _ := SkillCatalogMetadata(cfg)
 	if catalogTokens := warnings.EstimateTokens(catalogText); catalogTokens > MaxSkillCatalogMetadataTokens {
 		results = append(results, Result{
 			Status:         StatusWarn,
 			CheckName:      messages.DoctorCheckNameSkills,
-			Message:        fmt.Sprintf(messages.DoctorSkillCatalogTooLargeFmt, MaxSkillCatalogMetadataTokens, catalogTokens, len(skills)),
+			Message:        i18n.Sprintf(messages.DoctorSkillCatalogTooLargeFmt, MaxSkillCatalogMetadataTokens, catalogTokens, len(skills)),
 			Recommendation: messages.DoctorSkillValidationRecommend,
 		})
 	}
@@ -630,7 +665,7 @@ func CheckSkills(cfg *config.ProjectConfig) []Result {
 	return []Result{{
 		Status:    StatusOK,
 		CheckName: messages.DoctorCheckNameSkills,
-		Message:   fmt.Sprintf(messages.DoctorSkillsValidatedFmt, len(skills)),
+		Message:   i18n.Sprintf(messages.DoctorSkillsValidatedFmt, len(skills)),
 	}}
 }
 
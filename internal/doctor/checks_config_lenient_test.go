@@ -369,6 +369,9 @@ func TestConfigUnknownKeyNeedsUpgrade(t *testing.T) {
 		"agents.gemini",
 		"agents.gemini.enabled",
 		`agents.gemini["legacy-key"]`,
+		"agents.claude.dispatch",
+		"agents.codex.dispatch.default_agent",
+		"agents.antigravity.agent_specific.model",
 	} {
 		if !configUnknownKeyNeedsUpgrade(path) {
 			t.Fatalf("configUnknownKeyNeedsUpgrade(%q) = false, want true", path)
@@ -379,6 +382,7 @@ func TestConfigUnknownKeyNeedsUpgrade(t *testing.T) {
 		"agents.gemini_extra",
 		"agents.antigravity",
 		"agents.vscode.model",
+		"agents.claude.dispatcher",
 	} {
 		if configUnknownKeyNeedsUpgrade(path) {
 			t.Fatalf("configUnknownKeyNeedsUpgrade(%q) = true, want false", path)
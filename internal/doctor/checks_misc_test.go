@@ -13,6 +13,7 @@ import (
 	"unicode/utf8"
 
 	"github.com/conn-castle/agent-layer/internal/config"
+	"github.com/conn-castle/agent-layer/internal/install"
 	"github.com/conn-castle/agent-layer/internal/messages"
 	"github.com/conn-castle/agent-layer/internal/skillvalidator"
 )
@@ -584,6 +585,30 @@ Body.
 	}
 }
 
+func TestCheckSkills_RequiresCycleFails(t *testing.T) {
+	root := t.TempDir()
+	skillsDir := filepath.Join(root, ".agent-layer", "skills")
+	skills := make([]config.Skill, 0, 2)
+	for name, required := range map[string]string{"alpha": "beta", "beta": "alpha"} {
+		if err := os.MkdirAll(filepath.Join(skillsDir, name), 0o700); err != nil {
+			t.Fatalf("mkdir skills: %v", err)
+		}
+		skillPath := filepath.Join(skillsDir, name, "SKILL.md")
+		content := "---\nname: " + name + "\ndescription: test\nrequires: [" + required + "]\n---\nBody.\n"
+		if err := os.WriteFile(skillPath, []byte(content), 0o600); err != nil {
+			t.Fatalf("write skill: %v", err)
+		}
+		skills = append(skills, config.Skill{Name: name, SourcePath: skillPath, Requires: []string{required}})
+	}
+	results := CheckSkills(&config.ProjectConfig{Root: root, Skills: skills})
+	if len(results) != 1 {
+		t.Fatalf("expected 1 result, got %d: %#v", len(results), results)
+	}
+	if results[0].Status != StatusFail || !strings.Contains(results[0].Message, "skill requires form a cycle") {
+		t.Fatalf("unexpected result: %#v", results[0])
+	}
+}
+
 func TestCheckSkills_Warnings(t *testing.T) {
 	root := t.TempDir()
 	skillsDir := filepath.Join(root, ".agent-layer", "skills")
@@ -815,3 +840,26 @@ func TestCheckSkills_ParseFailure(t *testing.T) {
 		t.Fatalf("recommendation = %q, want %q", results[0].Recommendation, messages.DoctorSkillValidationRecommend)
 	}
 }
+
+func TestCheckInstallJournal(t *testing.T) {
+	orig := readInterruptedOperationFunc
+	t.Cleanup(func() { readInterruptedOperationFunc = orig })
+
+	readInterruptedOperationFunc = func(string, install.System) (*install.InterruptedOperation, error) { return nil, nil }
+	if results := CheckInstallJournal("/repo"); len(results) != 0 {
+		t.Fatalf("no journal = %+v", results)
+	}
+
+	readInterruptedOperationFunc = func(string, install.System) (*install.InterruptedOperation, error) {
+		return &install.InterruptedOperation{Operation: "upgrade", SnapshotID: "snap-1", Step: "writeTemplateFiles"}, nil
+	}
+	results := CheckInstallJournal("/repo")
+	if len(results) != 1 || results[0].Status != StatusFail || !strings.Contains(results[0].Message, "al upgrade rollback snap-1") {
+		t.Fatalf("interrupted upgrade = %+v", results)
+	}
+
+	readInterruptedOperationFunc = func(string, install.System) (*install.InterruptedOperation, error) { return nil, errors.New("boom") }
+	if results := CheckInstallJournal("/repo"); len(results) != 1 || !strings.Contains(results[0].Message, "boom") {
+		t.Fatalf("read failure = %+v", results)
+	}
+}
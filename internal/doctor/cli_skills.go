@@ -10,6 +10,8 @@ import (
 	toml "github.com/pelletier/go-toml/v2"
 
 	"github.com/conn-castle/agent-layer/internal/config"
+	"github.com/conn-castle/agent-layer/internal/i18n"
+	"github.com/conn-castle/agent-layer/internal/layerdir"
 	"github.com/conn-castle/agent-layer/internal/messages"
 	"github.com/conn-castle/agent-layer/internal/templates"
 )
@@ -82,7 +84,7 @@ func CheckCLISkills(cfg *config.ProjectConfig) []Result {
 		return []Result{{
 			Status:         StatusFail,
 			CheckName:      messages.DoctorCheckNameCLISkills,
-			Message:        fmt.Sprintf(messages.DoctorCLISkillCatalogLoadFailedFmt, err),
+			Message:        i18n.Sprintf(messages.DoctorCLISkillCatalogLoadFailedFmt, err),
 			Recommendation: messages.DoctorCLISkillCatalogLoadRecommend,
 		}}
 	}
@@ -92,7 +94,7 @@ func CheckCLISkills(cfg *config.ProjectConfig) []Result {
 		if entry.Binary == "" {
 			continue
 		}
-		dir := filepath.Join(cfg.Root, ".agent-layer", "skills", entry.ID)
+		dir := filepath.Join(layerdir.Dir(cfg.Root), "skills", entry.ID)
 		info, statErr := os.Stat(dir)
 		if statErr != nil || !info.IsDir() {
 			// Catalog skill not installed (or path is a file) — nothing to check.
@@ -102,15 +104,15 @@ func CheckCLISkills(cfg *config.ProjectConfig) []Result {
 			results = append(results, Result{
 				Status:         StatusFail,
 				CheckName:      messages.DoctorCheckNameCLISkills,
-				Message:        fmt.Sprintf(messages.DoctorCLISkillBinaryMissingFmt, entry.ID, entry.Binary),
-				Recommendation: fmt.Sprintf(messages.DoctorCLISkillBinaryMissingRecommend, entry.Binary),
+				Message:        i18n.Sprintf(messages.DoctorCLISkillBinaryMissingFmt, entry.ID, entry.Binary),
+				Recommendation: i18n.Sprintf(messages.DoctorCLISkillBinaryMissingRecommend, entry.Binary),
 			})
 			continue
 		}
 		results = append(results, Result{
 			Status:    StatusOK,
 			CheckName: messages.DoctorCheckNameCLISkills,
-			Message:   fmt.Sprintf(messages.DoctorCLISkillBinaryOKFmt, entry.ID, entry.Binary),
+			Message:   i18n.Sprintf(messages.DoctorCLISkillBinaryOKFmt, entry.ID, entry.Binary),
 		})
 	}
 	return results
@@ -112,10 +112,19 @@ func allConfigUnknownKeysNeedUpgrade(details []configUnknownKeyDetail) bool {
 	return true
 }
 
+// configUnknownKeyNeedsUpgrade reports whether path is, or lies under, a key
+// in the config deprecation table.
 func configUnknownKeyNeedsUpgrade(path string) bool {
-	return path == legacyGeminiSection ||
-		strings.HasPrefix(path, legacyGeminiSection+".") ||
-		strings.HasPrefix(path, legacyGeminiSection+"[")
+	for _, deprecation := range config.Deprecations() {
+		key := ""
+		for _, segment := range strings.Split(deprecation.Key, ".") {
+			key = joinConfigPath(key, segment)
+		}
+		if path == key || strings.HasPrefix(path, key+".") || strings.HasPrefix(path, key+"[") {
+			return true
+		}
+	}
+	return false
 }
 
 // configUnknownKeys returns detected unknown config keys using the current schema.
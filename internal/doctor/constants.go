@@ -1,3 +0,0 @@
-package doctor
-
-const legacyGeminiSection = "agents.gemini"
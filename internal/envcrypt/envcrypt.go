@@ -0,0 +1,251 @@
+// Package envcrypt encrypts individual .agent-layer/.env values with age.
+// An encrypted value is stored as ENC[age:<base64 ciphertext>] so the file
+// stays a valid dotenv file and unencrypted values keep working. Encryption
+// uses the public recipients in .agent-layer/age-recipients.txt, which is safe
+// to commit; decryption needs a private identity from AL_AGE_KEY_FILE or
+// $XDG_CONFIG_HOME/agent-layer/age/keys.txt. Both shell out to the age CLI.
+package envcrypt
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/conn-castle/agent-layer/internal/envfile"
+	"github.com/conn-castle/agent-layer/internal/fsutil"
+	"github.com/conn-castle/agent-layer/internal/i18n"
+	"github.com/conn-castle/agent-layer/internal/layerdir"
+	"github.com/conn-castle/agent-layer/internal/messages"
+)
+
+const (
+	// EnvKeyFile names the age identity file used for decryption.
+	EnvKeyFile = "AL_AGE_KEY_FILE"
+	// RecipientsFile is the recipients file name inside .agent-layer.
+	RecipientsFile = "age-recipients.txt"
+
+	valuePrefix = "ENC[age:"
+	valueSuffix = "]"
+	ageBinary   = "age"
+)
+
+var (
+	lookPath = exec.LookPath
+	// runAge runs the age binary with args, feeding stdin and returning stdout.
+	runAge = func(binary string, stdin []byte, args ...string) ([]byte, error) {
+		cmd := exec.Command(binary, args...) // #nosec G204 -- binary is age from PATH; arguments are fixed flags and file paths passed without a shell.
+		cmd.Stdin = bytes.NewReader(stdin)
+		var stderr bytes.Buffer
+		cmd.Stderr = &stderr
+		out, err := cmd.Output()
+		if err != nil {
+			if detail := strings.TrimSpace(stderr.String()); detail != "" {
+				return nil, i18n.Errorf(messages.EnvcryptAgeStderrFmt, err, detail)
+			}
+			return nil, err
+		}
+		return out, nil
+	}
+	getenv         = os.Getenv
+	userConfigHome = func() (string, error) {
+		if value := strings.TrimSpace(os.Getenv("XDG_CONFIG_HOME")); value != "" {
+			return value, nil
+		}
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		return filepath.Join(home, ".config"), nil
+	}
+)
+
+// IsEncrypted reports whether value is an ENC[age:...] envelope.
+func IsEncrypted(value string) bool {
+	return strings.HasPrefix(value, valuePrefix) && strings.HasSuffix(value, valueSuffix)
+}
+
+// RecipientsPath returns the recipients file for the repo at root.
+func RecipientsPath(root string) string {
+	return filepath.Join(layerdir.Dir(root), RecipientsFile)
+}
+
+// IdentityPath returns the age identity file: AL_AGE_KEY_FILE when set,
+// otherwise $XDG_CONFIG_HOME/agent-layer/age/keys.txt. It fails when the
+// file does not exist.
+func IdentityPath() (string, error) {
+	path := strings.TrimSpace(getenv(EnvKeyFile))
+	if path == "" {
+		base, err := userConfigHome()
+		if err != nil {
+			return "", i18n.Errorf(messages.EnvcryptIdentityMissingFmt, EnvKeyFile, err)
+		}
+		path = filepath.Join(base, "agent-layer", "age", "keys.txt")
+	}
+	if _, err := os.Stat(path); err != nil {
+		return "", i18n.Errorf(messages.EnvcryptIdentityMissingFmt, EnvKeyFile, err)
+	}
+	return path, nil
+}
+
+// Encrypt encrypts plaintext to the recipients listed in recipientsPath.
+func Encrypt(recipientsPath string, plaintext string) (string, error) {
+	if _, err := os.Stat(recipientsPath); err != nil {
+		return "", i18n.Errorf(messages.EnvcryptRecipientsMissingFmt, recipientsPath, err)
+	}
+	binary, err := ageBinaryPath()
+	if err != nil {
+		return "", err
+	}
+	ciphertext, err := runAge(binary, []byte(plaintext), "--encrypt", "--recipients-file", recipientsPath)
+	if err != nil {
+		return "", i18n.Errorf(messages.EnvcryptEncryptFailedFmt, err)
+	}
+	return valuePrefix + base64.StdEncoding.EncodeToString(ciphertext) + valueSuffix, nil
+}
+
+// Decrypt decrypts an ENC[age:...] value with the identity at identityPath.
+func Decrypt(identityPath string, value string) (string, error) {
+	if !IsEncrypted(value) {
+		return "", errors.New(i18n.T(messages.EnvcryptNotEncrypted))
+	}
+	ciphertext, err := base64.StdEncoding.DecodeString(strings.TrimSuffix(strings.TrimPrefix(value, valuePrefix), valueSuffix))
+	if err != nil {
+		return "", i18n.Errorf(messages.EnvcryptMalformedFmt, err)
+	}
+	binary, err := ageBinaryPath()
+	if err != nil {
+		return "", err
+	}
+	plaintext, err := runAge(binary, ciphertext, "--decrypt", "--identity", identityPath)
+	if err != nil {
+		return "", i18n.Errorf(messages.EnvcryptDecryptFailedFmt, err)
+	}
+	return string(plaintext), nil
+}
+
+// DecryptEnv returns env with every encrypted value decrypted. It returns env
+// itself when nothing is encrypted, so repos that never use encryption need
+// neither age nor an identity.
+func DecryptEnv(env map[string]string) (map[string]string, error) {
+	keys := encryptedKeys(env)
+	if len(keys) == 0 {
+		return env, nil
+	}
+	identity, err := IdentityPath()
+	if err != nil {
+		return nil, i18n.Errorf(messages.EnvcryptDecryptKeyFmt, keys[0], err)
+	}
+	decrypted := make(map[string]string, len(env))
+	for key, value := range env {
+		decrypted[key] = value
+	}
+	for _, key := range keys {
+		plaintext, err := Decrypt(identity, env[key])
+		if err != nil {
+			return nil, i18n.Errorf(messages.EnvcryptDecryptKeyFmt, key, err)
+		}
+		decrypted[key] = plaintext
+	}
+	return decrypted, nil
+}
+
+// EncryptFile encrypts keys in the .env file at envPath to the recipients in
+// recipientsPath. With no keys it encrypts every non-empty AL_ value. Values
+// that are already encrypted are left alone. It returns the keys it changed.
+func EncryptFile(envPath string, recipientsPath string, keys []string) ([]string, error) {
+	return rewriteFile(envPath, keys, func(value string) bool {
+		return value != "" && !IsEncrypted(value)
+	}, func(value string) (string, error) {
+		return Encrypt(recipientsPath, value)
+	})
+}
+
+// DecryptFile decrypts keys in the .env file at envPath back to plaintext.
+// With no keys it decrypts every encrypted value. It returns the keys it changed.
+func DecryptFile(envPath string, keys []string) ([]string, error) {
+	var identity string
+	return rewriteFile(envPath, keys, IsEncrypted, func(value string) (string, error) {
+		if identity == "" {
+			path, err := IdentityPath()
+			if err != nil {
+				return "", err
+			}
+			identity = path
+		}
+		return Decrypt(identity, value)
+	})
+}
+
+func rewriteFile(envPath string, keys []string, pending func(string) bool, transform func(string) (string, error)) ([]string, error) {
+	info, err := os.Stat(envPath)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(envPath)
+	if err != nil {
+		return nil, err
+	}
+	env, err := envfile.Parse(string(data))
+	if err != nil {
+		return nil, i18n.Errorf(messages.ConfigInvalidEnvFileFmt, envPath, err)
+	}
+	if len(keys) == 0 {
+		for key := range env {
+			if strings.HasPrefix(key, "AL_") {
+				keys = append(keys, key)
+			}
+		}
+	} else {
+		for _, key := range keys {
+			if _, ok := env[key]; !ok {
+				return nil, i18n.Errorf(messages.EnvcryptKeyNotFoundFmt, key, envPath)
+			}
+		}
+	}
+	sort.Strings(keys)
+
+	updates := make(map[string]string)
+	var changed []string
+	for _, key := range keys {
+		if !pending(env[key]) {
+			continue
+		}
+		value, err := transform(env[key])
+		if err != nil {
+			return nil, i18n.Errorf(messages.EnvcryptKeyFailedFmt, key, err)
+		}
+		updates[key] = value
+		changed = append(changed, key)
+	}
+	if len(updates) == 0 {
+		return nil, nil
+	}
+	if err := fsutil.WriteFileAtomic(envPath, []byte(envfile.Patch(string(data), updates)), info.Mode().Perm()); err != nil {
+		return nil, err
+	}
+	return changed, nil
+}
+
+func encryptedKeys(env map[string]string) []string {
+	var keys []string
+	for key, value := range env {
+		if IsEncrypted(value) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func ageBinaryPath() (string, error) {
+	binary, err := lookPath(ageBinary)
+	if err != nil {
+		return "", i18n.Errorf(messages.EnvcryptAgeMissingFmt, err)
+	}
+	return binary, nil
+}
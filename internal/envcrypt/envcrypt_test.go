@@ -0,0 +1,165 @@
+package envcrypt
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// stubAge replaces the age binary with a reversible fake that prefixes
+// "sealed:" on encrypt and strips it on decrypt.
+func stubAge(t *testing.T) *[][]string {
+	t.Helper()
+	originalLookPath, originalRun := lookPath, runAge
+	var calls [][]string
+	lookPath = func(string) (string, error) { return "/usr/bin/age", nil }
+	runAge = func(binary string, stdin []byte, args ...string) ([]byte, error) {
+		calls = append(calls, args)
+		switch args[0] {
+		case "--encrypt":
+			return append([]byte("sealed:"), stdin...), nil
+		case "--decrypt":
+			if !strings.HasPrefix(string(stdin), "sealed:") {
+				return nil, errors.New("no identity matched")
+			}
+			return []byte(strings.TrimPrefix(string(stdin), "sealed:")), nil
+		}
+		return nil, errors.New("unexpected args")
+	}
+	t.Cleanup(func() { lookPath, runAge = originalLookPath, originalRun })
+	return &calls
+}
+
+func writeRepo(t *testing.T, env string) (string, string) {
+	t.Helper()
+	root := t.TempDir()
+	dir := filepath.Join(root, ".agent-layer")
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	envPath := filepath.Join(dir, ".env")
+	if err := os.WriteFile(envPath, []byte(env), 0o600); err != nil {
+		t.Fatalf("write env: %v", err)
+	}
+	if err := os.WriteFile(RecipientsPath(root), []byte("age1example\n"), 0o600); err != nil {
+		t.Fatalf("write recipients: %v", err)
+	}
+	identity := filepath.Join(root, "keys.txt")
+	if err := os.WriteFile(identity, []byte("AGE-SECRET-KEY-1EXAMPLE\n"), 0o600); err != nil {
+		t.Fatalf("write identity: %v", err)
+	}
+	t.Setenv(EnvKeyFile, identity)
+	return root, envPath
+}
+
+func TestEncryptDecryptFileRoundTrip(t *testing.T) {
+	calls := stubAge(t)
+	root, envPath := writeRepo(t, "# tokens\nAL_TOKEN=abc123\nAL_EMPTY=\nAL_SPACED=\"a b\"\nOTHER=keep\n")
+
+	changed, err := EncryptFile(envPath, RecipientsPath(root), nil)
+	if err != nil {
+		t.Fatalf("EncryptFile: %v", err)
+	}
+	if strings.Join(changed, ",") != "AL_SPACED,AL_TOKEN" {
+		t.Fatalf("changed = %v", changed)
+	}
+	data, err := os.ReadFile(envPath)
+	if err != nil {
+		t.Fatalf("read env: %v", err)
+	}
+	text := string(data)
+	if strings.Contains(text, "abc123") || !strings.Contains(text, "# tokens\nAL_TOKEN=ENC[age:") || !strings.Contains(text, "OTHER=keep") {
+		t.Fatalf("unexpected encrypted file:\n%s", text)
+	}
+	if got := (*calls)[0]; got[1] != "--recipients-file" || got[2] != RecipientsPath(root) {
+		t.Fatalf("unexpected encrypt args: %v", got)
+	}
+
+	again, err := EncryptFile(envPath, RecipientsPath(root), nil)
+	if err != nil || len(again) != 0 {
+		t.Fatalf("second EncryptFile = %v, %v; want no changes", again, err)
+	}
+
+	changed, err = DecryptFile(envPath, []string{"AL_TOKEN"})
+	if err != nil {
+		t.Fatalf("DecryptFile: %v", err)
+	}
+	if len(changed) != 1 || changed[0] != "AL_TOKEN" {
+		t.Fatalf("changed = %v", changed)
+	}
+	data, err = os.ReadFile(envPath)
+	if err != nil {
+		t.Fatalf("read env: %v", err)
+	}
+	if !strings.Contains(string(data), "AL_TOKEN=abc123\n") || !strings.Contains(string(data), "AL_SPACED=ENC[age:") {
+		t.Fatalf("unexpected decrypted file:\n%s", data)
+	}
+}
+
+func TestEncryptFileErrors(t *testing.T) {
+	stubAge(t)
+	root, envPath := writeRepo(t, "AL_TOKEN=abc123\n")
+
+	if _, err := EncryptFile(envPath, RecipientsPath(root), []string{"AL_MISSING"}); err == nil || !strings.Contains(err.Error(), "AL_MISSING is not set") {
+		t.Fatalf("expected missing key error, got %v", err)
+	}
+	if _, err := EncryptFile(envPath, filepath.Join(root, "none.txt"), nil); err == nil || !strings.Contains(err.Error(), "no age recipients file") {
+		t.Fatalf("expected missing recipients error, got %v", err)
+	}
+	lookPath = func(string) (string, error) { return "", errors.New("not found") }
+	if _, err := EncryptFile(envPath, RecipientsPath(root), nil); err == nil || !strings.Contains(err.Error(), "age is required") {
+		t.Fatalf("expected missing age error, got %v", err)
+	}
+}
+
+func TestDecryptEnv(t *testing.T) {
+	stubAge(t)
+	writeRepo(t, "")
+
+	plain := map[string]string{"AL_PLAIN": "value"}
+	got, err := DecryptEnv(plain)
+	if err != nil || got["AL_PLAIN"] != "value" {
+		t.Fatalf("DecryptEnv(plain) = %v, %v", got, err)
+	}
+
+	env := map[string]string{"AL_PLAIN": "value", "AL_TOKEN": "ENC[age:c2VhbGVkOnNlY3JldA==]"}
+	got, err = DecryptEnv(env)
+	if err != nil {
+		t.Fatalf("DecryptEnv: %v", err)
+	}
+	if got["AL_TOKEN"] != "secret" || got["AL_PLAIN"] != "value" || env["AL_TOKEN"] == "secret" {
+		t.Fatalf("unexpected decrypted env: %v (input %v)", got, env)
+	}
+
+	if _, err := DecryptEnv(map[string]string{"AL_BAD": "ENC[age:!!]"}); err == nil || !strings.Contains(err.Error(), "AL_BAD") {
+		t.Fatalf("expected malformed value error, got %v", err)
+	}
+	if _, err := DecryptEnv(map[string]string{"AL_WRONG": "ENC[age:b3RoZXI=]"}); err == nil || !strings.Contains(err.Error(), "no identity matched") {
+		t.Fatalf("expected age failure, got %v", err)
+	}
+}
+
+func TestIdentityPathDefault(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv(EnvKeyFile, "")
+	originalHome := userConfigHome
+	userConfigHome = func() (string, error) { return home, nil }
+	t.Cleanup(func() { userConfigHome = originalHome })
+
+	if _, err := IdentityPath(); err == nil || !strings.Contains(err.Error(), EnvKeyFile) {
+		t.Fatalf("expected missing identity error, got %v", err)
+	}
+	want := filepath.Join(home, "agent-layer", "age", "keys.txt")
+	if err := os.MkdirAll(filepath.Dir(want), 0o700); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(want, []byte("key"), 0o600); err != nil {
+		t.Fatalf("write identity: %v", err)
+	}
+	got, err := IdentityPath()
+	if err != nil || got != want {
+		t.Fatalf("IdentityPath = %q, %v; want %q", got, err, want)
+	}
+}
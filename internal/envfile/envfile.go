@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/conn-castle/agent-layer/internal/i18n"
 	"github.com/conn-castle/agent-layer/internal/messages"
 )
 
@@ -22,7 +23,7 @@ func Parse(content string) (map[string]string, error) {
 		lineNo++
 		key, value, ok, err := parseLine(scanner.Text())
 		if err != nil {
-			return nil, fmt.Errorf(messages.EnvfileLineErrorFmt, lineNo, err)
+			return nil, i18n.Errorf(messages.EnvfileLineErrorFmt, lineNo, err)
 		}
 		if !ok {
 			continue
@@ -31,7 +32,7 @@ func Parse(content string) (map[string]string, error) {
 	}
 
 	if err := scanner.Err(); err != nil {
-		return nil, fmt.Errorf(messages.EnvfileReadFailedFmt, err)
+		return nil, i18n.Errorf(messages.EnvfileReadFailedFmt, err)
 	}
 
 	return env, nil
@@ -108,11 +109,11 @@ func parseLine(line string) (string, string, bool, error) {
 	}
 	idx := strings.Index(trimmed, "=")
 	if idx <= 0 {
-		return "", "", false, fmt.Errorf(messages.EnvfileExpectedKeyValue)
+		return "", "", false, i18n.Errorf(messages.EnvfileExpectedKeyValue)
 	}
 	key := strings.TrimSpace(trimmed[:idx])
 	if key == "" {
-		return "", "", false, fmt.Errorf(messages.EnvfileExpectedKeyValue)
+		return "", "", false, i18n.Errorf(messages.EnvfileExpectedKeyValue)
 	}
 	value := strings.TrimSpace(trimmed[idx+1:])
 	if strings.HasPrefix(value, `"`) {
@@ -136,7 +137,7 @@ func parseLine(line string) (string, string, bool, error) {
 func parseDoubleQuotedValue(value string) (string, error) {
 	closing := findClosingDoubleQuote(value)
 	if closing < 0 {
-		return "", fmt.Errorf(messages.EnvfileUnterminatedQuotedValue)
+		return "", i18n.Errorf(messages.EnvfileUnterminatedQuotedValue)
 	}
 	if err := validateQuotedValueSuffix(value[closing+1:]); err != nil {
 		return "", err
@@ -148,11 +149,11 @@ func parseDoubleQuotedValue(value string) (string, error) {
 // value is expected to start with a single quote.
 func parseSingleQuotedValue(value string) (string, error) {
 	if len(value) < 2 {
-		return "", fmt.Errorf(messages.EnvfileUnterminatedQuotedValue)
+		return "", i18n.Errorf(messages.EnvfileUnterminatedQuotedValue)
 	}
 	closingOffset := strings.IndexByte(value[1:], '\'')
 	if closingOffset < 0 {
-		return "", fmt.Errorf(messages.EnvfileUnterminatedQuotedValue)
+		return "", i18n.Errorf(messages.EnvfileUnterminatedQuotedValue)
 	}
 	closing := 1 + closingOffset
 	if err := validateQuotedValueSuffix(value[closing+1:]); err != nil {
@@ -187,7 +188,7 @@ func validateQuotedValueSuffix(suffix string) error {
 	if trimmed == "" || strings.HasPrefix(trimmed, "#") {
 		return nil
 	}
-	return fmt.Errorf(messages.EnvfileInvalidQuotedSuffix)
+	return i18n.Errorf(messages.EnvfileInvalidQuotedSuffix)
 }
 
 // unescapeDoubleQuotedValue decodes the escape forms produced by encodeValue.
@@ -0,0 +1,116 @@
+// Package errcode classifies CLI failures with stable codes so wrappers can
+// branch on the failure category instead of parsing stderr text.
+package errcode
+
+import (
+	"errors"
+
+	"github.com/conn-castle/agent-layer/internal/messages"
+)
+
+// Code is a stable, machine-readable failure category. Values are part of the
+// CLI contract and must not change once released.
+type Code string
+
+const (
+	// Config reports a missing, unreadable, or invalid .agent-layer/ project.
+	Config Code = "config_error"
+	// Sync reports a failure while generating client outputs.
+	Sync Code = "sync_error"
+	// UpgradeConflict reports an upgrade that cannot proceed without a decision
+	// or manual resolution.
+	UpgradeConflict Code = "upgrade_conflict"
+	// ClientLaunch reports a client that failed to start or exited with an error.
+	ClientLaunch Code = "client_launch_failed"
+	// MCP reports an MCP server that could not be reached or served.
+	MCP Code = "mcp_failure"
+	// Offline reports an operation refused because it needs the network while
+	// offline mode is on.
+	Offline Code = "offline"
+	// InputRequired reports a command that needed an interactive answer while
+	// prompts were unavailable: no terminal, or CI mode.
+	InputRequired Code = "input_required"
+	// Unknown is reported for failures that carry no classification.
+	Unknown Code = "error"
+)
+
+// Error attaches a Code to an underlying error without changing its message.
+type Error struct {
+	Code Code
+	Err  error
+}
+
+// Error returns the underlying error text.
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Wrap tags err with code. Nil errors and errors that already carry a code are
+// returned unchanged, so the classification closest to the failure wins.
+func Wrap(code Code, err error) error {
+	if err == nil {
+		return nil
+	}
+	var coded *Error
+	if errors.As(err, &coded) {
+		return err
+	}
+	return &Error{Code: code, Err: err}
+}
+
+// Of returns the code carried by err, or Unknown when it has none.
+func Of(err error) Code {
+	var coded *Error
+	if errors.As(err, &coded) {
+		return coded.Code
+	}
+	return Unknown
+}
+
+// Hint returns the remediation hint for code, or "" when there is none.
+func Hint(code Code) string {
+	switch code {
+	case Config:
+		return messages.ErrcodeHintConfig
+	case Sync:
+		return messages.ErrcodeHintSync
+	case UpgradeConflict:
+		return messages.ErrcodeHintUpgradeConflict
+	case ClientLaunch:
+		return messages.ErrcodeHintClientLaunch
+	case MCP:
+		return messages.ErrcodeHintMCP
+	case Offline:
+		return messages.ErrcodeHintOffline
+	case InputRequired:
+		return messages.ErrcodeHintInputRequired
+	}
+	return ""
+}
+
+// ExitCode returns the process exit code CI mode uses for code. Like the
+// codes themselves, these values are part of the CLI contract.
+func ExitCode(code Code) int {
+	switch code {
+	case Config:
+		return 2
+	case Sync:
+		return 3
+	case UpgradeConflict:
+		return 4
+	case ClientLaunch:
+		return 5
+	case MCP:
+		return 6
+	case Offline:
+		return 7
+	case InputRequired:
+		return 8
+	}
+	return 1
+}
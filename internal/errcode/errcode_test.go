@@ -0,0 +1,66 @@
+package errcode
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestWrap(t *testing.T) {
+	if Wrap(Config, nil) != nil {
+		t.Fatalf("expected nil for nil error")
+	}
+	base := errors.New("boom")
+	err := Wrap(Sync, base)
+	if err.Error() != "boom" {
+		t.Fatalf("message changed: %q", err.Error())
+	}
+	if !errors.Is(err, base) {
+		t.Fatalf("expected wrapped error to unwrap to base")
+	}
+	if Of(err) != Sync {
+		t.Fatalf("Of = %q, want %q", Of(err), Sync)
+	}
+}
+
+func TestWrapKeepsInnermostCode(t *testing.T) {
+	inner := Wrap(Config, errors.New("bad config"))
+	outer := Wrap(Sync, fmt.Errorf("sync: %w", inner))
+	if Of(outer) != Config {
+		t.Fatalf("Of = %q, want %q", Of(outer), Config)
+	}
+}
+
+func TestOfUnknown(t *testing.T) {
+	if Of(errors.New("plain")) != Unknown {
+		t.Fatalf("expected Unknown for unclassified error")
+	}
+}
+
+func TestHint(t *testing.T) {
+	for _, code := range []Code{Config, Sync, UpgradeConflict, ClientLaunch, MCP, Offline, InputRequired} {
+		if Hint(code) == "" {
+			t.Fatalf("missing hint for %q", code)
+		}
+	}
+	if Hint(Unknown) != "" {
+		t.Fatalf("expected no hint for Unknown")
+	}
+}
+
+func TestExitCode(t *testing.T) {
+	seen := map[int]Code{}
+	for _, code := range []Code{Config, Sync, UpgradeConflict, ClientLaunch, MCP, Offline, InputRequired} {
+		exit := ExitCode(code)
+		if exit <= 1 {
+			t.Fatalf("ExitCode(%q) = %d, want a code above 1", code, exit)
+		}
+		if other, ok := seen[exit]; ok {
+			t.Fatalf("ExitCode(%q) = %d, already used by %q", code, exit, other)
+		}
+		seen[exit] = code
+	}
+	if ExitCode(Unknown) != 1 {
+		t.Fatalf("ExitCode(Unknown) = %d, want 1", ExitCode(Unknown))
+	}
+}
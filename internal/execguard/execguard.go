@@ -0,0 +1,148 @@
+// Package execguard decides whether `al exec` may run a command, using the
+// same commands.allow prefixes and approvals.mode that sync projects into
+// each client, plus an optional commands.deny list that always wins.
+package execguard
+
+import (
+	"bufio"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/conn-castle/agent-layer/internal/audit"
+	"github.com/conn-castle/agent-layer/internal/config"
+	"github.com/conn-castle/agent-layer/internal/i18n"
+	"github.com/conn-castle/agent-layer/internal/layerdir"
+	"github.com/conn-castle/agent-layer/internal/messages"
+	"github.com/conn-castle/agent-layer/internal/projection"
+)
+
+// DenyFileName is the optional denylist under .agent-layer/.
+const DenyFileName = "commands.deny"
+
+// Outcome is the result of evaluating a command.
+type Outcome string
+
+// Outcomes returned by Evaluate.
+const (
+	// Allowed commands run without asking.
+	Allowed Outcome = audit.DecisionAllowed
+	// Denied commands never run.
+	Denied Outcome = audit.DecisionDenied
+	// NeedsApproval commands run only after a person confirms them.
+	NeedsApproval Outcome = "needs_approval"
+)
+
+// Decision explains why a command got its outcome.
+type Decision struct {
+	Outcome Outcome
+	// Rule is the commands.allow or commands.deny prefix that matched, if any.
+	Rule   string
+	Reason string
+}
+
+// restrictedEnvKeys are the only variables kept by RestrictedEnv.
+var restrictedEnvKeys = []string{"HOME", "LANG", "LC_ALL", "LOGNAME", "PATH", "SHELL", "TERM", "TMPDIR", "TZ", "USER"}
+
+// DenyPath returns the denylist path for root.
+func DenyPath(root string) string {
+	return filepath.Join(layerdir.Dir(root), DenyFileName)
+}
+
+// LoadDeny reads root's commands.deny prefixes. A missing file yields none.
+func LoadDeny(root string) ([]string, error) {
+	path := DenyPath(root)
+	file, err := os.Open(path) // #nosec G304 -- path is the fixed denylist under the repo's .agent-layer/.
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, i18n.Errorf(messages.ExecGuardReadDenyFmt, path, err)
+	}
+	defer func() { _ = file.Close() }()
+
+	var prefixes []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		prefixes = append(prefixes, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, i18n.Errorf(messages.ExecGuardReadDenyFmt, path, err)
+	}
+	return prefixes, nil
+}
+
+// CommandLine joins argv the way prefixes in commands.allow are written.
+func CommandLine(argv []string) string {
+	return strings.Join(argv, " ")
+}
+
+// Evaluate decides whether argv may run. A commands.deny match always
+// denies. Otherwise approvals.mode decides: yolo allows everything, all and
+// commands allow commands.allow matches, and anything else needs approval.
+func Evaluate(cfg config.Config, allow []string, deny []string, argv []string) Decision {
+	line := CommandLine(argv)
+	if rule, ok := matchPrefix(deny, line); ok {
+		return Decision{Outcome: Denied, Rule: rule, Reason: messages.ExecGuardReasonDenied}
+	}
+	if cfg.Approvals.Mode == config.ApprovalModeYOLO {
+		return Decision{Outcome: Allowed, Reason: messages.ExecGuardReasonYOLO}
+	}
+	approvals := projection.BuildApprovals(cfg, allow)
+	rule, ok := matchPrefix(approvals.Commands, line)
+	if !ok {
+		return Decision{Outcome: NeedsApproval, Reason: messages.ExecGuardReasonNotAllowed}
+	}
+	if !approvals.AllowCommands {
+		return Decision{Outcome: NeedsApproval, Rule: rule, Reason: i18n.Sprintf(messages.ExecGuardReasonModeFmt, cfg.Approvals.Mode)}
+	}
+	return Decision{Outcome: Allowed, Rule: rule, Reason: messages.ExecGuardReasonAllowed}
+}
+
+// EvaluateHook decides whether a configured sync hook may run. Hooks run
+// without a prompt, so only a commands.allow match allows one, whatever
+// approvals.mode says; a commands.deny match still wins.
+func EvaluateHook(allow []string, deny []string, argv []string) Decision {
+	line := CommandLine(argv)
+	if rule, ok := matchPrefix(deny, line); ok {
+		return Decision{Outcome: Denied, Rule: rule, Reason: messages.ExecGuardReasonDenied}
+	}
+	rule, ok := matchPrefix(allow, line)
+	if !ok {
+		return Decision{Outcome: Denied, Reason: messages.ExecGuardReasonNotAllowed}
+	}
+	return Decision{Outcome: Allowed, Rule: rule, Reason: messages.ExecGuardReasonAllowed}
+}
+
+// matchPrefix returns the first prefix that line equals or extends at a word
+// boundary, so "git" matches "git status" but not "gitk".
+func matchPrefix(prefixes []string, line string) (string, bool) {
+	for _, prefix := range prefixes {
+		if line == prefix || strings.HasPrefix(line, prefix+" ") {
+			return prefix, true
+		}
+	}
+	return "", false
+}
+
+// RestrictedEnv keeps only the variables a command needs to locate programs
+// and render output, dropping credentials and project secrets from base.
+func RestrictedEnv(base []string) []string {
+	keep := make(map[string]bool, len(restrictedEnvKeys))
+	for _, key := range restrictedEnvKeys {
+		keep[key] = true
+	}
+	var env []string
+	for _, entry := range base {
+		key, _, ok := strings.Cut(entry, "=")
+		if ok && keep[key] {
+			env = append(env, entry)
+		}
+	}
+	return env
+}
@@ -0,0 +1,104 @@
+package execguard
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/conn-castle/agent-layer/internal/config"
+)
+
+func cfgWithMode(mode string) config.Config {
+	return config.Config{Approvals: config.ApprovalsConfig{Mode: mode}}
+}
+
+func TestEvaluate(t *testing.T) {
+	allow := []string{"git status", "go test"}
+	deny := []string{"git push"}
+	tests := []struct {
+		name    string
+		mode    string
+		argv    []string
+		outcome Outcome
+		rule    string
+	}{
+		{"allowlisted", config.ApprovalModeCommands, []string{"go", "test", "./..."}, Allowed, "go test"},
+		{"exact match", config.ApprovalModeAll, []string{"git", "status"}, Allowed, "git status"},
+		{"word boundary", config.ApprovalModeAll, []string{"go", "tester"}, NeedsApproval, ""},
+		{"not allowlisted", config.ApprovalModeAll, []string{"rm", "-rf", "build"}, NeedsApproval, ""},
+		{"mode without commands", config.ApprovalModeMCP, []string{"go", "test"}, NeedsApproval, "go test"},
+		{"denied", config.ApprovalModeAll, []string{"git", "push", "origin"}, Denied, "git push"},
+		{"yolo", config.ApprovalModeYOLO, []string{"rm", "-rf", "build"}, Allowed, ""},
+		{"deny beats yolo", config.ApprovalModeYOLO, []string{"git", "push"}, Denied, "git push"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decision := Evaluate(cfgWithMode(tt.mode), allow, deny, tt.argv)
+			if decision.Outcome != tt.outcome || decision.Rule != tt.rule || decision.Reason == "" {
+				t.Fatalf("got %#v, want %s with rule %q", decision, tt.outcome, tt.rule)
+			}
+		})
+	}
+}
+
+func TestEvaluateHook(t *testing.T) {
+	allow := []string{"make context", "git"}
+	deny := []string{"git push"}
+	tests := []struct {
+		name    string
+		argv    []string
+		outcome Outcome
+		rule    string
+	}{
+		{"allowlisted", []string{"make", "context"}, Allowed, "make context"},
+		{"not allowlisted", []string{"make", "clean"}, Denied, ""},
+		{"denied", []string{"git", "push"}, Denied, "git push"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decision := EvaluateHook(allow, deny, tt.argv)
+			if decision.Outcome != tt.outcome || decision.Rule != tt.rule || decision.Reason == "" {
+				t.Fatalf("got %#v, want %s with rule %q", decision, tt.outcome, tt.rule)
+			}
+		})
+	}
+}
+
+func TestLoadDeny(t *testing.T) {
+	root := t.TempDir()
+	prefixes, err := LoadDeny(root)
+	if err != nil || prefixes != nil {
+		t.Fatalf("expected no prefixes without a file, got %v %v", prefixes, err)
+	}
+	if err := os.MkdirAll(filepath.Join(root, ".agent-layer"), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(DenyPath(root), []byte("# never\ngit push\n\n  rm -rf /  \n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	prefixes, err = LoadDeny(root)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if strings.Join(prefixes, "|") != "git push|rm -rf /" {
+		t.Fatalf("unexpected prefixes %q", prefixes)
+	}
+}
+
+func TestLoadDeny_ReadError(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(DenyPath(root), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadDeny(root); err == nil || !strings.Contains(err.Error(), "commands denylist") {
+		t.Fatalf("expected read error, got %v", err)
+	}
+}
+
+func TestRestrictedEnv(t *testing.T) {
+	env := RestrictedEnv([]string{"PATH=/bin", "AWS_SECRET_ACCESS_KEY=x", "HOME=/home/u", "AL_TOKEN=t", "malformed"})
+	if strings.Join(env, " ") != "PATH=/bin HOME=/home/u" {
+		t.Fatalf("unexpected env %q", env)
+	}
+}
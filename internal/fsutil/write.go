@@ -1,10 +1,10 @@
 package fsutil
 
 import (
-	"fmt"
 	"os"
 	"path/filepath"
 
+	"github.com/conn-castle/agent-layer/internal/i18n"
 	"github.com/conn-castle/agent-layer/internal/messages"
 )
 
@@ -26,7 +26,7 @@ func WriteFileAtomic(path string, data []byte, perm os.FileMode) error {
 
 	tmp, err := createTemp(dir, base+".tmp-*")
 	if err != nil {
-		return fmt.Errorf(messages.FsutilCreateTempFileFmt, path, err)
+		return i18n.Errorf(messages.FsutilCreateTempFileFmt, path, err)
 	}
 	tmpName := tmp.Name()
 	committed := false
@@ -38,22 +38,22 @@ func WriteFileAtomic(path string, data []byte, perm os.FileMode) error {
 
 	if err := chmodTempFile(tmp, perm); err != nil {
 		_ = tmp.Close()
-		return fmt.Errorf(messages.FsutilSetPermissionsFmt, tmpName, err)
+		return i18n.Errorf(messages.FsutilSetPermissionsFmt, tmpName, err)
 	}
 	if _, err := writeTempFile(tmp, data); err != nil {
 		_ = tmp.Close()
-		return fmt.Errorf(messages.FsutilWriteTempFileFmt, path, err)
+		return i18n.Errorf(messages.FsutilWriteTempFileFmt, path, err)
 	}
 	if err := syncTempFile(tmp); err != nil {
 		_ = tmp.Close()
-		return fmt.Errorf(messages.FsutilSyncTempFileFmt, path, err)
+		return i18n.Errorf(messages.FsutilSyncTempFileFmt, path, err)
 	}
 	if err := closeTempFile(tmp); err != nil {
-		return fmt.Errorf(messages.FsutilCloseTempFileFmt, path, err)
+		return i18n.Errorf(messages.FsutilCloseTempFileFmt, path, err)
 	}
 
 	if err := renameFile(tmpName, path); err != nil {
-		return fmt.Errorf(messages.FsutilRenameTempFileFmt, path, err)
+		return i18n.Errorf(messages.FsutilRenameTempFileFmt, path, err)
 	}
 	committed = true
 
@@ -68,11 +68,11 @@ func WriteFileAtomic(path string, data []byte, perm os.FileMode) error {
 func syncDir(dir string) error {
 	d, err := os.Open(dir) // #nosec G304 -- dir is the parent of a write target chosen by an internal caller of WriteFileAtomic; not user input.
 	if err != nil {
-		return fmt.Errorf(messages.FsutilOpenDirFmt, dir, err)
+		return i18n.Errorf(messages.FsutilOpenDirFmt, dir, err)
 	}
 	defer func() { _ = d.Close() }()
 	if err := d.Sync(); err != nil {
-		return fmt.Errorf(messages.FsutilSyncDirFmt, dir, err)
+		return i18n.Errorf(messages.FsutilSyncDirFmt, dir, err)
 	}
 	return nil
 }
@@ -0,0 +1,219 @@
+// Package i18n translates Agent Layer's user-facing text. The English strings
+// in internal/messages are the source language and the catalog keys: a
+// catalog maps each English message, format verbs included, to its
+// translation. Messages without a translation print in English, so a catalog
+// can cover as much or as little of the CLI as its translators have reached.
+// Callers format messages with Sprintf and Errorf, or look them up with T,
+// so text is translated when it is created.
+//
+// The locale comes from AL_LANG, then the config's top-level language key.
+// Catalogs come from loaders: the built-in loader reads locales/<locale>.json
+// embedded in the binary, and distributions can Register their own.
+package i18n
+
+import (
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/conn-castle/agent-layer/internal/messages"
+)
+
+// EnvLang names the environment variable that selects the locale.
+const EnvLang = "AL_LANG"
+
+// DefaultLocale is the source locale. It never loads a catalog.
+const DefaultLocale = "en"
+
+// Catalog maps English source messages to their translations for one locale.
+type Catalog map[string]string
+
+// Loader returns the catalog for locale, or nil when it has none.
+type Loader func(locale string) (Catalog, error)
+
+//go:embed all:locales
+var embeddedLocales embed.FS
+
+var localePattern = regexp.MustCompile(`^[a-z]{2,3}(-[a-z0-9]{2,8})*$`)
+
+// verbPattern matches one fmt verb, including an explicit argument index.
+var verbPattern = regexp.MustCompile(`%[-+# 0]*(?:\[\d+\])?[\d*]*(?:\.[\d*]*)?[a-zA-Z%]`)
+
+var (
+	mu      sync.RWMutex
+	loaders = []Loader{FSLoader(mustSub(embeddedLocales, "locales"))}
+	locale  = DefaultLocale
+	active  Catalog
+)
+
+// Register adds a loader. Later loaders take precedence over earlier ones
+// and over the built-in catalogs, message by message.
+func Register(loader Loader) {
+	mu.Lock()
+	defer mu.Unlock()
+	loaders = append(loaders, loader)
+}
+
+// FSLoader returns a loader that reads <locale>.json from fsys. A missing
+// file means the locale has no catalog there.
+func FSLoader(fsys fs.FS) Loader {
+	return func(locale string) (Catalog, error) {
+		name := locale + ".json"
+		data, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil, nil
+			}
+			return nil, fmt.Errorf(messages.I18nReadCatalogFmt, name, err)
+		}
+		var catalog Catalog
+		if err := json.Unmarshal(data, &catalog); err != nil {
+			return nil, fmt.Errorf(messages.I18nParseCatalogFmt, name, err)
+		}
+		return catalog, nil
+	}
+}
+
+// Normalize returns locale in lowercase hyphenated form without an encoding
+// or modifier suffix, so "de_DE.UTF-8" becomes "de-de". The POSIX locales
+// "C" and "POSIX" normalize to DefaultLocale.
+func Normalize(value string) string {
+	value = strings.TrimSpace(value)
+	if i := strings.IndexAny(value, ".@"); i >= 0 {
+		value = value[:i]
+	}
+	value = strings.ToLower(strings.ReplaceAll(value, "_", "-"))
+	if value == "c" || value == "posix" {
+		return DefaultLocale
+	}
+	return value
+}
+
+// Valid reports whether value names a locale, such as "de" or "pt-BR".
+func Valid(value string) bool {
+	return localePattern.MatchString(Normalize(value))
+}
+
+// ResolveLocale returns the locale AL_LANG selects, falling back to the
+// configured language and then DefaultLocale.
+func ResolveLocale(getenv func(string) string, configured string) string {
+	for _, candidate := range []string{getenv(EnvLang), configured} {
+		if strings.TrimSpace(candidate) != "" {
+			return Normalize(candidate)
+		}
+	}
+	return DefaultLocale
+}
+
+// SetLocale loads the catalogs for value and makes them active. A regional
+// locale such as "pt-br" also loads its base language "pt" beneath it. On
+// error the previous locale stays active.
+func SetLocale(value string) error {
+	normalized := Normalize(value)
+	if normalized == "" {
+		normalized = DefaultLocale
+	}
+	if !localePattern.MatchString(normalized) {
+		return fmt.Errorf(messages.I18nInvalidLocaleFmt, value)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	catalog := Catalog{}
+	if normalized != DefaultLocale {
+		names := []string{normalized}
+		if base, _, regional := strings.Cut(normalized, "-"); regional {
+			names = []string{base, normalized}
+		}
+		for _, name := range names {
+			for _, loader := range loaders {
+				loaded, err := loader(name)
+				if err != nil {
+					return err
+				}
+				for source, translated := range loaded {
+					if strings.TrimSpace(translated) != "" && sameVerbs(source, translated) {
+						catalog[source] = translated
+					}
+				}
+			}
+		}
+	}
+	locale = normalized
+	active = catalog
+	return nil
+}
+
+// Locale returns the active locale.
+func Locale() string {
+	mu.RLock()
+	defer mu.RUnlock()
+	return locale
+}
+
+// T returns the translation of message in the active locale, or message
+// itself when there is none.
+func T(message string) string {
+	mu.RLock()
+	defer mu.RUnlock()
+	if translated, ok := active[message]; ok {
+		return translated
+	}
+	return message
+}
+
+// Sprintf formats the translation of format.
+func Sprintf(format string, args ...any) string {
+	return fmt.Sprintf(T(format), args...)
+}
+
+// Errorf is fmt.Errorf with the translation of format.
+func Errorf(format string, args ...any) error {
+	return fmt.Errorf(T(format), args...)
+}
+
+// NewError returns an error whose text is message translated each time it is
+// printed. Package-level sentinels are created before the locale is set, so
+// they use it instead of errors.New(T(message)).
+func NewError(message string) error {
+	return &sentinelError{message: message}
+}
+
+type sentinelError struct {
+	message string
+}
+
+func (e *sentinelError) Error() string {
+	return T(e.message)
+}
+
+// sameVerbs reports whether translated uses the same fmt verbs as source, in
+// any order. Messages format errors, so a translation that drops a %w would
+// break error wrapping; such translations are ignored.
+func sameVerbs(source string, translated string) bool {
+	verbs := func(message string) []string {
+		var out []string
+		for _, verb := range verbPattern.FindAllString(message, -1) {
+			if verb != "%%" {
+				out = append(out, verb[len(verb)-1:])
+			}
+		}
+		sort.Strings(out)
+		return out
+	}
+	return slices.Equal(verbs(source), verbs(translated))
+}
+
+func mustSub(fsys fs.FS, dir string) fs.FS {
+	sub, err := fs.Sub(fsys, dir)
+	if err != nil {
+		panic(err)
+	}
+	return sub
+}
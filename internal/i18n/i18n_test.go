@@ -0,0 +1,150 @@
+package i18n
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+// withLoaders swaps in loaders for one test and restores English afterwards.
+func withLoaders(t *testing.T, extra ...Loader) {
+	t.Helper()
+	orig := loaders
+	t.Cleanup(func() {
+		loaders = orig
+		if err := SetLocale(DefaultLocale); err != nil {
+			t.Fatalf("reset locale: %v", err)
+		}
+	})
+	loaders = append([]Loader(nil), orig...)
+	for _, loader := range extra {
+		Register(loader)
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	for in, want := range map[string]string{
+		"de":            "de",
+		" pt_BR.UTF-8 ": "pt-br",
+		"sr@latin":      "sr",
+		"C":             DefaultLocale,
+		"POSIX":         DefaultLocale,
+	} {
+		if got := Normalize(in); got != want {
+			t.Fatalf("Normalize(%q) = %q, want %q", in, got, want)
+		}
+	}
+	if Valid("not a locale") || !Valid("pt-BR") {
+		t.Fatalf("Valid misclassified a locale")
+	}
+}
+
+func TestResolveLocale(t *testing.T) {
+	env := map[string]string{}
+	getenv := func(key string) string { return env[key] }
+
+	if got := ResolveLocale(getenv, ""); got != DefaultLocale {
+		t.Fatalf("default = %q", got)
+	}
+	if got := ResolveLocale(getenv, "fr"); got != "fr" {
+		t.Fatalf("config = %q", got)
+	}
+	env[EnvLang] = "de_DE"
+	if got := ResolveLocale(getenv, "fr"); got != "de-de" {
+		t.Fatalf("AL_LANG must win over config, got %q", got)
+	}
+}
+
+func TestSetLocaleLayersCatalogs(t *testing.T) {
+	withLoaders(t,
+		FSLoader(fstest.MapFS{
+			"pt.json":    {Data: []byte(`{"hello %s": "olá %s", "bye": "adeus", "skip": " "}`)},
+			"pt-br.json": {Data: []byte(`{"bye": "tchau"}`)},
+		}),
+	)
+
+	if err := SetLocale("pt_BR"); err != nil {
+		t.Fatalf("SetLocale: %v", err)
+	}
+	if Locale() != "pt-br" {
+		t.Fatalf("Locale = %q", Locale())
+	}
+	if got := Sprintf("hello %s", "al"); got != "olá al" {
+		t.Fatalf("base-language translation = %q", got)
+	}
+	if got := T("bye"); got != "tchau" {
+		t.Fatalf("regional translation must win, got %q", got)
+	}
+	if got := T("skip"); got != "skip" {
+		t.Fatalf("blank translation must fall back to English, got %q", got)
+	}
+	if got := Errorf("untranslated %d", 1).Error(); got != "untranslated 1" {
+		t.Fatalf("untranslated message = %q", got)
+	}
+}
+
+func TestSetLocaleErrorsKeepPreviousLocale(t *testing.T) {
+	boom := errors.New("boom")
+	withLoaders(t,
+		FSLoader(fstest.MapFS{"de.json": {Data: []byte(`{"yes": "ja"}`)}}),
+		func(locale string) (Catalog, error) {
+			if locale == "fr" {
+				return nil, boom
+			}
+			return nil, nil
+		},
+	)
+	if err := SetLocale("de"); err != nil {
+		t.Fatalf("SetLocale: %v", err)
+	}
+
+	if err := SetLocale("fr"); !errors.Is(err, boom) {
+		t.Fatalf("expected loader error, got %v", err)
+	}
+	if err := SetLocale("x!"); err == nil || !strings.Contains(err.Error(), "invalid locale") {
+		t.Fatalf("expected invalid locale error, got %v", err)
+	}
+	if Locale() != "de" || T("yes") != "ja" {
+		t.Fatalf("failed SetLocale must keep the previous catalog, got %q", Locale())
+	}
+}
+
+func TestFSLoaderRejectsMalformedCatalog(t *testing.T) {
+	loader := FSLoader(fstest.MapFS{"de.json": {Data: []byte(`[`)}})
+	if _, err := loader("de"); err == nil || !strings.Contains(err.Error(), "parse message catalog de.json") {
+		t.Fatalf("expected parse error, got %v", err)
+	}
+}
+
+func TestSetLocaleIgnoresTranslationsWithOtherVerbs(t *testing.T) {
+	withLoaders(t,
+		FSLoader(fstest.MapFS{"de.json": {Data: []byte(`{"read %s: %w": "%[2]w beim Lesen von %[1]s", "open %s: %w": "öffnen %s fehlgeschlagen"}`)}}),
+	)
+	if err := SetLocale("de"); err != nil {
+		t.Fatalf("SetLocale: %v", err)
+	}
+	inner := errors.New("boom")
+	err := Errorf("read %s: %w", "a.txt", inner)
+	if err.Error() != "boom beim Lesen von a.txt" || !errors.Is(err, inner) {
+		t.Fatalf("reordered translation = %v", err)
+	}
+	if got := T("open %s: %w"); got != "open %s: %w" {
+		t.Fatalf("translation without %%w must be ignored, got %q", got)
+	}
+}
+
+func TestNewErrorTranslatesWhenPrinted(t *testing.T) {
+	sentinel := NewError("offline")
+	withLoaders(t, FSLoader(fstest.MapFS{"de.json": {Data: []byte(`{"offline": "keine Verbindung"}`)}}))
+	if err := SetLocale("de"); err != nil {
+		t.Fatalf("SetLocale: %v", err)
+	}
+	wrapped := Errorf("fetch: %w", sentinel)
+	if wrapped.Error() != "fetch: keine Verbindung" || !errors.Is(wrapped, sentinel) {
+		t.Fatalf("wrapped sentinel = %v", wrapped)
+	}
+	if errors.Is(wrapped, NewError("offline")) {
+		t.Fatal("sentinels with the same message must stay distinct")
+	}
+}
@@ -0,0 +1,250 @@
+package install
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/conn-castle/agent-layer/internal/i18n"
+	"github.com/conn-castle/agent-layer/internal/messages"
+	"github.com/conn-castle/agent-layer/internal/version"
+)
+
+// BaselineStateSourceRebuilt indicates baseline was reconstructed by `al baseline rebuild`.
+const BaselineStateSourceRebuilt BaselineStateSource = "rebuilt_by_command"
+
+// Baseline rebuild origins recorded in the rebuilt state's metadata.
+const (
+	BaselineRebuildOriginAssumed       = "assumed_version"
+	BaselineRebuildOriginManifestMatch = "manifest_match"
+)
+
+// Previous baseline states recorded in the rebuilt state's metadata.
+const (
+	baselinePreviousMissing = "missing"
+	baselinePreviousInvalid = "invalid"
+	baselinePreviousValid   = "valid"
+)
+
+// BaselineRebuildOptions configures RebuildBaseline.
+type BaselineRebuildOptions struct {
+	System System
+	// AssumeVersion skips manifest matching and rebuilds from this release's
+	// embedded manifest.
+	AssumeVersion string
+	// ChooseVersion picks one version when several manifests match the repo
+	// equally well. Candidates are sorted newest first. When nil, an
+	// ambiguous match is an error.
+	ChooseVersion func(candidates []BaselineCandidate) (string, error)
+}
+
+// BaselineCandidate scores one embedded manifest against the repo.
+type BaselineCandidate struct {
+	Version string `json:"version"`
+	// Matched counts manifest files whose repo content matches the manifest.
+	Matched int `json:"matched"`
+	// Present counts manifest files that exist in the repo.
+	Present int `json:"present"`
+	Total   int `json:"total"`
+}
+
+// BaselineRebuildResult describes a rebuilt baseline.
+type BaselineRebuildResult struct {
+	Version       string `json:"version"`
+	Origin        string `json:"origin"`
+	PreviousState string `json:"previous_state"`
+	Files         int    `json:"files"`
+	// Candidates are the best-scoring manifests, newest first. Empty when
+	// the version was assumed.
+	Candidates []BaselineCandidate `json:"candidates,omitempty"`
+}
+
+// RebuildBaseline reconstructs .agent-layer/state/managed-baseline.json from
+// the embedded manifest that best matches the repo's managed files, or from
+// the manifest for opts.AssumeVersion. The rebuilt state records where it
+// came from so later ownership decisions can weigh it accordingly.
+func RebuildBaseline(root string, opts BaselineRebuildOptions) (BaselineRebuildResult, error) {
+	if root == "" {
+		return BaselineRebuildResult{}, i18n.Errorf(messages.InstallRootRequired)
+	}
+	if opts.System == nil {
+		return BaselineRebuildResult{}, i18n.Errorf(messages.InstallSystemRequired)
+	}
+	sys, err := layerSystem(root, opts.System)
+	if err != nil {
+		return BaselineRebuildResult{}, err
+	}
+	opts.System = sys
+	inst := &installer{root: root, sys: sys}
+
+	result := BaselineRebuildResult{PreviousState: baselinePreviousValid}
+	existingState, err := readManagedBaselineState(root, opts.System)
+	var existing *managedBaselineState
+	switch {
+	case err == nil:
+		existing = &existingState
+	case errors.Is(err, os.ErrNotExist):
+		result.PreviousState = baselinePreviousMissing
+	default:
+		result.PreviousState = baselinePreviousInvalid
+	}
+
+	var manifest templateManifest
+	if assumed := strings.TrimSpace(opts.AssumeVersion); assumed != "" {
+		normalized, err := version.Normalize(assumed)
+		if err != nil {
+			return BaselineRebuildResult{}, i18n.Errorf(messages.InstallBaselineAssumeVersionInvalidFmt, assumed, err)
+		}
+		manifest, err = loadTemplateManifestByVersion(normalized)
+		if errors.Is(err, os.ErrNotExist) {
+			return BaselineRebuildResult{}, i18n.Errorf(messages.InstallBaselineManifestMissingFmt, normalized)
+		}
+		if err != nil {
+			return BaselineRebuildResult{}, err
+		}
+		result.Origin = BaselineRebuildOriginAssumed
+	} else {
+		manifests, err := loadAllTemplateManifests()
+		if err != nil {
+			return BaselineRebuildResult{}, err
+		}
+		candidates, err := inst.scoreBaselineCandidates(manifests)
+		if err != nil {
+			return BaselineRebuildResult{}, err
+		}
+		if len(candidates) == 0 {
+			return BaselineRebuildResult{}, i18n.Errorf(messages.InstallBaselineNoMatch)
+		}
+		chosen := candidates[0].Version
+		if len(candidates) > 1 {
+			chosen, err = inst.disambiguateBaselineCandidates(candidates, opts.ChooseVersion)
+			if err != nil {
+				return BaselineRebuildResult{}, err
+			}
+		}
+		manifest = manifests[chosen]
+		result.Origin = BaselineRebuildOriginManifestMatch
+		result.Candidates = candidates
+	}
+
+	state := makeManagedBaselineState(manifest, BaselineStateSourceRebuilt, inst.now(), existing)
+	state.Metadata["rebuild_origin"] = result.Origin
+	state.Metadata["rebuild_previous_state"] = result.PreviousState
+	for _, candidate := range result.Candidates {
+		if candidate.Version == manifest.Version {
+			state.Metadata["rebuild_matched_files"] = candidate.Matched
+			state.Metadata["rebuild_present_files"] = candidate.Present
+		}
+	}
+	if err := writeManagedBaselineState(root, opts.System, state); err != nil {
+		return BaselineRebuildResult{}, err
+	}
+	result.Version = manifest.Version
+	result.Files = len(state.Files)
+	return result, nil
+}
+
+// scoreBaselineCandidates returns the manifests that match the most managed
+// files with the fewest mismatches, newest first. Manifests that match no
+// file are never candidates.
+func (inst *installer) scoreBaselineCandidates(manifests map[string]templateManifest) ([]BaselineCandidate, error) {
+	ranked, err := inst.rankBaselineCandidates(manifests)
+	if err != nil || len(ranked) == 0 {
+		return nil, err
+	}
+	best := ranked[0]
+	top := ranked[:1]
+	for _, candidate := range ranked[1:] {
+		if candidate.Matched != best.Matched || candidate.Present-candidate.Matched != best.Present-best.Matched {
+			break
+		}
+		top = append(top, candidate)
+	}
+	return top, nil
+}
+
+// rankBaselineCandidates scores every manifest against the repo's managed
+// files and returns those matching at least one file, best first: most
+// matches, then fewest mismatches, then newest version.
+func (inst *installer) rankBaselineCandidates(manifests map[string]templateManifest) ([]BaselineCandidate, error) {
+	scored := make([]BaselineCandidate, 0, len(manifests))
+	for versionValue, manifest := range manifests {
+		candidate := BaselineCandidate{Version: versionValue, Total: len(manifest.Files)}
+		for _, entry := range manifest.Files {
+			absPath := filepath.Join(inst.root, filepath.FromSlash(entry.Path))
+			content, err := inst.sys.ReadFile(absPath)
+			if err != nil {
+				if errors.Is(err, os.ErrNotExist) {
+					continue
+				}
+				return nil, i18n.Errorf(messages.InstallFailedReadFmt, absPath, err)
+			}
+			candidate.Present++
+			local, err := buildOwnershipComparable(entry.Path, content)
+			if err != nil {
+				// Unparseable managed sections cannot match any manifest.
+				continue
+			}
+			expected, err := comparableFromManifestEntry(entry)
+			if err != nil {
+				return nil, err
+			}
+			if local.PolicyID == expected.PolicyID && comparableKey(local) == comparableKey(expected) {
+				candidate.Matched++
+			}
+		}
+		if candidate.Matched > 0 {
+			scored = append(scored, candidate)
+		}
+	}
+	sort.Slice(scored, func(i, j int) bool {
+		if scored[i].Matched != scored[j].Matched {
+			return scored[i].Matched > scored[j].Matched
+		}
+		mismatchI, mismatchJ := scored[i].Present-scored[i].Matched, scored[j].Present-scored[j].Matched
+		if mismatchI != mismatchJ {
+			return mismatchI < mismatchJ
+		}
+		cmp, _ := version.Compare(scored[i].Version, scored[j].Version)
+		return cmp > 0
+	})
+	return scored, nil
+}
+
+// disambiguateBaselineCandidates resolves a tie between equally good
+// manifests: the pinned version wins when it is among them, otherwise choose
+// decides. Without choose, the tie is an error listing the candidates.
+func (inst *installer) disambiguateBaselineCandidates(candidates []BaselineCandidate, choose func([]BaselineCandidate) (string, error)) (string, error) {
+	pinned, err := readCurrentPinVersion(inst.root, inst.sys)
+	if err != nil {
+		return "", err
+	}
+	for _, candidate := range candidates {
+		if pinned != "" && candidate.Version == pinned {
+			return pinned, nil
+		}
+	}
+	if choose == nil {
+		return "", i18n.Errorf(messages.InstallBaselineAmbiguousFmt, joinCandidateVersions(candidates))
+	}
+	chosen, err := choose(candidates)
+	if err != nil {
+		return "", err
+	}
+	for _, candidate := range candidates {
+		if candidate.Version == chosen {
+			return chosen, nil
+		}
+	}
+	return "", i18n.Errorf(messages.InstallBaselineChoiceInvalidFmt, chosen, joinCandidateVersions(candidates))
+}
+
+func joinCandidateVersions(candidates []BaselineCandidate) string {
+	versions := make([]string, 0, len(candidates))
+	for _, candidate := range candidates {
+		versions = append(versions, candidate.Version)
+	}
+	return strings.Join(versions, ", ")
+}
@@ -0,0 +1,213 @@
+package install
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// stubTemplateManifests makes loadAllTemplateManifests return manifests.
+func stubTemplateManifests(t *testing.T, manifests ...templateManifest) {
+	t.Helper()
+	originalMap := allTemplateManifestByV
+	originalErr := allTemplateManifestErr
+	t.Cleanup(func() {
+		allTemplateManifestOnce = sync.Once{}
+		allTemplateManifestByV = originalMap
+		allTemplateManifestErr = originalErr
+	})
+	allTemplateManifestOnce = sync.Once{}
+	allTemplateManifestOnce.Do(func() {})
+	allTemplateManifestErr = nil
+	allTemplateManifestByV = make(map[string]templateManifest, len(manifests))
+	for _, manifest := range manifests {
+		allTemplateManifestByV[manifest.Version] = manifest
+	}
+}
+
+func rebuildTestManifest(versionValue string, files map[string]string) templateManifest {
+	manifest := templateManifest{
+		SchemaVersion: templateManifestSchemaVersion,
+		Version:       versionValue,
+		GeneratedAt:   "2026-01-01T00:00:00Z",
+	}
+	for path, content := range files {
+		manifest.Files = append(manifest.Files, manifestFileEntry{Path: path, FullHashNormalized: hashNormalizedContent([]byte(content))})
+	}
+	return manifest
+}
+
+func writeRebuildFile(t *testing.T, root, rel, content string) {
+	t.Helper()
+	path := filepath.Join(root, filepath.FromSlash(rel))
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+}
+
+func TestRebuildBaselinePicksBestMatch(t *testing.T) {
+	stubTemplateManifests(t,
+		rebuildTestManifest("1.0.0", map[string]string{".agent-layer/instructions/00.md": "old\n", ".agent-layer/skills/a/SKILL.md": "a\n"}),
+		rebuildTestManifest("1.1.0", map[string]string{".agent-layer/instructions/00.md": "new\n", ".agent-layer/skills/a/SKILL.md": "a\n"}),
+	)
+	root := t.TempDir()
+	writeRebuildFile(t, root, ".agent-layer/instructions/00.md", "new\n")
+	writeRebuildFile(t, root, ".agent-layer/skills/a/SKILL.md", "a\n")
+	writeRebuildFile(t, root, baselineStateRelPath, "{not json")
+
+	result, err := RebuildBaseline(root, BaselineRebuildOptions{System: RealSystem{}})
+	if err != nil {
+		t.Fatalf("RebuildBaseline: %v", err)
+	}
+	if result.Version != "1.1.0" || result.Origin != BaselineRebuildOriginManifestMatch || result.PreviousState != baselinePreviousInvalid || result.Files != 2 {
+		t.Fatalf("unexpected result: %#v", result)
+	}
+	state, err := readManagedBaselineState(root, RealSystem{})
+	if err != nil {
+		t.Fatalf("read rebuilt state: %v", err)
+	}
+	if state.BaselineVersion != "1.1.0" || state.Source != BaselineStateSourceRebuilt {
+		t.Fatalf("unexpected state: %#v", state)
+	}
+	if state.Metadata["rebuild_origin"] != BaselineRebuildOriginManifestMatch || state.Metadata["rebuild_matched_files"] != float64(2) {
+		t.Fatalf("unexpected provenance: %#v", state.Metadata)
+	}
+}
+
+func TestRebuildBaselineDisambiguation(t *testing.T) {
+	stubTemplateManifests(t,
+		rebuildTestManifest("1.0.0", map[string]string{".agent-layer/instructions/00.md": "same\n"}),
+		rebuildTestManifest("1.1.0", map[string]string{".agent-layer/instructions/00.md": "same\n"}),
+	)
+	root := t.TempDir()
+	writeRebuildFile(t, root, ".agent-layer/instructions/00.md", "same\n")
+
+	_, err := RebuildBaseline(root, BaselineRebuildOptions{System: RealSystem{}})
+	if err == nil || !strings.Contains(err.Error(), "1.1.0, 1.0.0") {
+		t.Fatalf("expected ambiguity error listing candidates, got %v", err)
+	}
+
+	var offered []string
+	result, err := RebuildBaseline(root, BaselineRebuildOptions{
+		System: RealSystem{},
+		ChooseVersion: func(candidates []BaselineCandidate) (string, error) {
+			for _, candidate := range candidates {
+				offered = append(offered, candidate.Version)
+			}
+			return "1.0.0", nil
+		},
+	})
+	if err != nil || result.Version != "1.0.0" || strings.Join(offered, ",") != "1.1.0,1.0.0" {
+		t.Fatalf("chosen result %#v offered %v err %v", result, offered, err)
+	}
+
+	_, err = RebuildBaseline(root, BaselineRebuildOptions{
+		System:        RealSystem{},
+		ChooseVersion: func([]BaselineCandidate) (string, error) { return "9.9.9", nil },
+	})
+	if err == nil || !strings.Contains(err.Error(), "not one of the matching releases") {
+		t.Fatalf("expected invalid choice error, got %v", err)
+	}
+
+	chooseErr := errors.New("cancelled")
+	if _, err := RebuildBaseline(root, BaselineRebuildOptions{
+		System:        RealSystem{},
+		ChooseVersion: func([]BaselineCandidate) (string, error) { return "", chooseErr },
+	}); !errors.Is(err, chooseErr) {
+		t.Fatalf("expected choose error, got %v", err)
+	}
+
+	writeRebuildFile(t, root, ".agent-layer/al.version", "1.0.0\n")
+	result, err = RebuildBaseline(root, BaselineRebuildOptions{System: RealSystem{}})
+	if err != nil || result.Version != "1.0.0" || result.PreviousState != baselinePreviousValid {
+		t.Fatalf("expected pinned version to break the tie, got %#v err %v", result, err)
+	}
+}
+
+func TestRebuildBaselineNoMatch(t *testing.T) {
+	stubTemplateManifests(t, rebuildTestManifest("1.0.0", map[string]string{".agent-layer/instructions/00.md": "upstream\n"}))
+	root := t.TempDir()
+	writeRebuildFile(t, root, ".agent-layer/instructions/00.md", "edited\n")
+
+	if _, err := RebuildBaseline(root, BaselineRebuildOptions{System: RealSystem{}}); err == nil || !strings.Contains(err.Error(), "--assume-version") {
+		t.Fatalf("expected no-match error, got %v", err)
+	}
+}
+
+func TestRebuildBaselineAssumeVersion(t *testing.T) {
+	root := t.TempDir()
+	result, err := RebuildBaseline(root, BaselineRebuildOptions{System: RealSystem{}, AssumeVersion: "v0.9.2"})
+	if err != nil {
+		t.Fatalf("RebuildBaseline: %v", err)
+	}
+	if result.Version != "0.9.2" || result.Origin != BaselineRebuildOriginAssumed || result.PreviousState != baselinePreviousMissing || len(result.Candidates) != 0 {
+		t.Fatalf("unexpected result: %#v", result)
+	}
+
+	if _, err := RebuildBaseline(root, BaselineRebuildOptions{System: RealSystem{}, AssumeVersion: "0.0.1"}); err == nil || !strings.Contains(err.Error(), "no embedded template manifest for version 0.0.1") {
+		t.Fatalf("expected missing manifest error, got %v", err)
+	}
+	if _, err := RebuildBaseline(root, BaselineRebuildOptions{System: RealSystem{}, AssumeVersion: "latest"}); err == nil || !strings.Contains(err.Error(), "invalid --assume-version") {
+		t.Fatalf("expected invalid version error, got %v", err)
+	}
+}
+
+func TestRebuildBaselineRequiresRootAndSystem(t *testing.T) {
+	if _, err := RebuildBaseline("", BaselineRebuildOptions{System: RealSystem{}}); err == nil {
+		t.Fatal("expected root error")
+	}
+	if _, err := RebuildBaseline(t.TempDir(), BaselineRebuildOptions{}); err == nil {
+		t.Fatal("expected system error")
+	}
+}
+
+func TestResolveUpgradeSourceVersionPromptsWithRankedManifests(t *testing.T) {
+	stubTemplateManifests(t,
+		rebuildTestManifest("1.0.0", map[string]string{".agent-layer/instructions/00.md": "same\n", ".agent-layer/skills/a/SKILL.md": "old\n"}),
+		rebuildTestManifest("1.1.0", map[string]string{".agent-layer/instructions/00.md": "same\n", ".agent-layer/skills/a/SKILL.md": "new\n"}),
+		rebuildTestManifest("1.2.0", map[string]string{".agent-layer/instructions/00.md": "other\n"}),
+	)
+	root := t.TempDir()
+	writeRebuildFile(t, root, ".agent-layer/instructions/00.md", "same\n")
+	writeRebuildFile(t, root, ".agent-layer/skills/a/SKILL.md", "local\n")
+
+	var offered []BaselineCandidate
+	choice := "1.0.0"
+	inst := &installer{root: root, sys: RealSystem{}, prompter: PromptFuncs{
+		ChooseSourceVersionFunc: func(candidates []BaselineCandidate) (string, error) {
+			offered = candidates
+			return choice, nil
+		},
+	}}
+	res := inst.resolveUpgradeMigrationSourceVersion()
+	if res.version != "1.0.0" || res.origin != UpgradeMigrationSourceUserSelected {
+		t.Fatalf("unexpected resolution: %#v", res)
+	}
+	if len(offered) != 2 || offered[0].Version != "1.1.0" || offered[0].Matched != 1 || offered[1].Version != "1.0.0" {
+		t.Fatalf("unexpected ranking: %#v", offered)
+	}
+
+	choice = ""
+	res = inst.resolveUpgradeMigrationSourceVersion()
+	if res.origin != UpgradeMigrationSourceUnknown {
+		t.Fatalf("declined choice should keep source unknown: %#v", res)
+	}
+
+	choice = "1.2.0"
+	res = inst.resolveUpgradeMigrationSourceVersion()
+	if res.origin != UpgradeMigrationSourceUnknown || len(res.notes) == 0 || !strings.Contains(strings.Join(res.notes, "\n"), "not one of the matching releases") {
+		t.Fatalf("unranked choice should be rejected with a note: %#v", res)
+	}
+
+	inst.prompter = nil
+	res = inst.resolveUpgradeMigrationSourceVersion()
+	if res.origin != UpgradeMigrationSourceUnknown || offered == nil {
+		t.Fatalf("no prompter should keep source unknown: %#v", res)
+	}
+}
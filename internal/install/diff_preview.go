@@ -7,6 +7,7 @@ import (
 
 	"github.com/aymanbagabas/go-udiff"
 
+	"github.com/conn-castle/agent-layer/internal/i18n"
 	"github.com/conn-castle/agent-layer/internal/messages"
 	"github.com/conn-castle/agent-layer/internal/templates"
 )
@@ -100,7 +101,7 @@ func (inst *installer) buildDiffPreviews(entries []LabeledPath, templatePathByRe
 func (inst *installer) buildSingleDiffPreview(entry LabeledPath, templatePathByRel map[string]string) (DiffPreview, error) {
 	relPath := filepath.ToSlash(entry.Path)
 	if relPath == "" {
-		return DiffPreview{}, fmt.Errorf(messages.InstallDiffPreviewPathRequired)
+		return DiffPreview{}, i18n.Errorf(messages.InstallDiffPreviewPathRequired)
 	}
 	if relPath == pinVersionRelPath {
 		return inst.pinVersionDiffPreview(relPath, entry.Ownership)
@@ -108,7 +109,7 @@ func (inst *installer) buildSingleDiffPreview(entry LabeledPath, templatePathByR
 
 	templatePath := templatePathByRel[relPath]
 	if strings.TrimSpace(templatePath) == "" {
-		return DiffPreview{}, fmt.Errorf(messages.InstallMissingTemplatePathMappingFmt, relPath)
+		return DiffPreview{}, i18n.Errorf(messages.InstallMissingTemplatePathMappingFmt, relPath)
 	}
 
 	localPath := filepath.Join(inst.root, filepath.FromSlash(relPath))
@@ -420,12 +421,12 @@ func splitSectionAwareContent(relPath string, marker string, content []byte) (ma
 			continue
 		}
 		if index >= 0 {
-			return "", "", fmt.Errorf(messages.InstallSectionAwareMarkerDuplicateFmt, marker, relPath)
+			return "", "", i18n.Errorf(messages.InstallSectionAwareMarkerDuplicateFmt, marker, relPath)
 		}
 		index = i
 	}
 	if index < 0 {
-		return "", "", fmt.Errorf(messages.InstallSectionAwareMarkerMissingFmt, marker, relPath)
+		return "", "", i18n.Errorf(messages.InstallSectionAwareMarkerMissingFmt, marker, relPath)
 	}
 	managed = strings.Join(lines[:index+1], "")
 	user = strings.Join(lines[index+1:], "")
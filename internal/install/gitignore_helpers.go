@@ -4,9 +4,9 @@ import (
 	"crypto/sha256"
 	"encoding/hex"
 	"errors"
-	"fmt"
 	"strings"
 
+	"github.com/conn-castle/agent-layer/internal/i18n"
 	"github.com/conn-castle/agent-layer/internal/messages"
 )
 
@@ -91,7 +91,7 @@ func stripGitignoreHash(block string) (string, string) {
 func ValidateGitignoreBlock(block string, blockPath string) (string, error) {
 	normalized := normalizeGitignoreBlock(block)
 	if containsManagedGitignoreMarkers(normalized) {
-		return "", fmt.Errorf(messages.InstallInvalidGitignoreBlockFmt, blockPath)
+		return "", i18n.Errorf(messages.InstallInvalidGitignoreBlockFmt, blockPath)
 	}
 	return normalized, nil
 }
@@ -108,7 +108,7 @@ func updateGitignoreContent(content string, block string, path string) (string,
 
 	start, end, err := findGitignoreBlock(lines)
 	if err != nil {
-		return "", fmt.Errorf(messages.InstallGitignoreUnterminatedBlockFmt, path, gitignoreStart, gitignoreEnd)
+		return "", i18n.Errorf(messages.InstallGitignoreUnterminatedBlockFmt, path, gitignoreStart, gitignoreEnd)
 	}
 	if start == -1 && end == -1 {
 		// No managed block: create or append one.
@@ -142,6 +142,35 @@ func updateGitignoreContent(content string, block string, path string) (string,
 	return strings.Join(updated, "\n") + "\n", nil
 }
 
+// RemoveGitignoreBlock strips the managed agent-layer block from .gitignore
+// content, collapsing the blank lines around it. path names the file in
+// errors. It reports false when there is no managed block, and fails on a
+// malformed one rather than guessing which lines are managed.
+func RemoveGitignoreBlock(content string, path string) (string, bool, error) {
+	lines := splitLines(content)
+	start, end, err := findGitignoreBlock(lines)
+	if err != nil {
+		return "", false, i18n.Errorf(messages.InstallGitignoreUnterminatedBlockFmt, path, gitignoreStart, gitignoreEnd)
+	}
+	if start == -1 {
+		return content, false, nil
+	}
+	pre := append([]string{}, lines[:start]...)
+	for len(pre) > 0 && strings.TrimSpace(pre[len(pre)-1]) == "" {
+		pre = pre[:len(pre)-1]
+	}
+	post := trimLeadingBlankLines(lines[end+1:])
+	updated := pre
+	if len(pre) > 0 && len(post) > 0 {
+		updated = append(updated, "")
+	}
+	updated = append(updated, post...)
+	if len(updated) == 0 {
+		return "", true, nil
+	}
+	return strings.Join(updated, "\n") + "\n", true, nil
+}
+
 // splitLines normalizes line endings and splits content into lines.
 // input is the raw text; returns normalized lines, preserving at most one trailing blank line.
 func splitLines(input string) []string {
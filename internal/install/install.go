@@ -9,9 +9,13 @@ import (
 	"path/filepath"
 	"sort"
 	"strings"
+	"time"
 
+	"github.com/conn-castle/agent-layer/internal/clock"
+	"github.com/conn-castle/agent-layer/internal/i18n"
 	"github.com/conn-castle/agent-layer/internal/launchers"
 	"github.com/conn-castle/agent-layer/internal/messages"
+	"github.com/conn-castle/agent-layer/internal/output"
 	"github.com/conn-castle/agent-layer/internal/version"
 )
 
@@ -31,12 +35,22 @@ type PromptDeleteUnknownTmpAllFunc func(paths []string) (bool, error)
 
 // Options controls installer behavior.
 type Options struct {
-	Overwrite    bool
-	Prompter     Prompter
-	WarnWriter   io.Writer
+	Overwrite bool
+	Prompter  Prompter
+	// Output receives notes, warnings, and progress. Nil writes to stderr.
+	Output       *output.Writer
 	PinVersion   string
 	DiffMaxLines int
 	System       System
+	// Clock stamps snapshots and baseline state. Nil uses the wall clock.
+	Clock clock.Clock
+	// ResumeInterrupted runs even when the install journal shows that an
+	// earlier init or upgrade stopped partway. Without it Run fails with
+	// ErrInterruptedOperation.
+	ResumeInterrupted bool
+	// OperationFilter limits an upgrade to chosen migrations and template
+	// updates. Init ignores it.
+	OperationFilter UpgradeOperationFilter
 }
 
 type installer struct {
@@ -48,6 +62,7 @@ type installer struct {
 	overwriteMemoryAllDecided bool
 	prompter                  Prompter
 	warnWriter                io.Writer
+	output                    *output.Writer
 	diffs                     []string
 	unknowns                  []string
 	pinVersion                string
@@ -64,6 +79,10 @@ type installer struct {
 	migrationsPrepared        bool
 	skillsMigrationConfirmed  bool
 	sys                       System
+	clock                     clock.Clock
+	journal                   *installJournal
+	operationFilter           UpgradeOperationFilter
+	userOwnedPaths            []string
 }
 
 type templateFile struct {
@@ -92,7 +111,7 @@ type matchCacheEntry struct {
 // Run initializes the repository with the required Agent Layer structure.
 func Run(root string, opts Options) error {
 	if root == "" {
-		return fmt.Errorf(messages.InstallRootRequired)
+		return i18n.Errorf(messages.InstallRootRequired)
 	}
 
 	overwrite := opts.Overwrite
@@ -100,29 +119,42 @@ func Run(root string, opts Options) error {
 		return err
 	}
 
-	sys := opts.System
-	if sys == nil {
-		return fmt.Errorf(messages.InstallSystemRequired)
+	if opts.System == nil {
+		return i18n.Errorf(messages.InstallSystemRequired)
 	}
-	warnWriter := opts.WarnWriter
-	if warnWriter == nil {
-		warnWriter = os.Stderr
+	sys, err := layerSystem(root, opts.System)
+	if err != nil {
+		return err
 	}
 	inst := &installer{
 		root:         root,
 		overwrite:    overwrite,
 		prompter:     opts.Prompter,
-		warnWriter:   warnWriter,
+		warnWriter:   opts.Output.Info(),
+		output:       opts.Output,
 		diffMaxLines: normalizeDiffMaxLines(opts.DiffMaxLines),
 		sys:          sys,
+		clock:        opts.Clock,
+	}
+	if overwrite {
+		inst.operationFilter = opts.OperationFilter
 	}
 	if strings.TrimSpace(opts.PinVersion) != "" {
 		normalized, err := version.Normalize(opts.PinVersion)
 		if err != nil {
-			return fmt.Errorf(messages.InstallInvalidPinVersionFmt, err)
+			return i18n.Errorf(messages.InstallInvalidPinVersionFmt, err)
 		}
 		inst.pinVersion = normalized
 	}
+	if err := inst.checkInterrupted(opts.ResumeInterrupted); err != nil {
+		return err
+	}
+	if err := inst.validateOperationFilter(); err != nil {
+		return err
+	}
+	if err := inst.loadOwnershipOverrides(); err != nil {
+		return err
+	}
 	if err := inst.upgrades().ensureBaseDirs(); err != nil {
 		return err
 	}
@@ -135,6 +167,9 @@ func Run(root string, opts Options) error {
 		if err := inst.prepareUpgradeMigrations(); err != nil {
 			return err
 		}
+		if err := WriteUpgradeChangelog(inst.warnOutput(), buildUpgradeChangelog(inst.migrationReport)); err != nil {
+			return err
+		}
 		if err := inst.preflightAndConfirmSkillsMigration(); err != nil {
 			return err
 		}
@@ -142,22 +177,29 @@ func Run(root string, opts Options) error {
 		if err != nil {
 			return err
 		}
+		if err := inst.beginJournal(installJournalOperationUpgrade, snapshot.SnapshotID); err != nil {
+			return err
+		}
 		if err := inst.upgrades().runUpgradeTransaction(&snapshot); err != nil {
 			return err
 		}
+		inst.recordUpgradeHistory(snapshot)
 	} else {
 		// scanUnknowns is a no-op for init (init requires no prior .agent-layer/),
 		// but is kept for symmetry and defensive coverage.
 		if err := inst.scanUnknowns(); err != nil {
 			return err
 		}
-		steps := []func() error{
-			inst.writeVersionFile,
-			inst.templates().writeTemplateFiles,
-			inst.updateGitignore,
-			inst.writeVSCodeLaunchers,
+		if err := inst.beginJournal(installJournalOperationInit, ""); err != nil {
+			return err
+		}
+		steps := []transactionStep{
+			{name: "writeVersionFile", run: inst.writeVersionFile},
+			{name: "writeTemplateFiles", run: inst.templates().writeTemplateFiles},
+			{name: "updateGitignore", run: inst.updateGitignore},
+			{name: stepWriteVSCodeLaunchers, run: inst.writeVSCodeLaunchers},
 		}
-		if err := runSteps(steps); err != nil {
+		if err := inst.runJournaledSteps(steps); err != nil {
 			return err
 		}
 	}
@@ -168,6 +210,9 @@ func Run(root string, opts Options) error {
 	if err := inst.writeManagedBaselineIfConsistent(baselineSource); err != nil {
 		return err
 	}
+	if err := inst.finishJournal(); err != nil {
+		return err
+	}
 
 	inst.warnDifferences()
 	inst.warnUnknowns()
@@ -200,7 +245,11 @@ func (inst upgradeOrchestrator) runUpgradeTransaction(snapshot *upgradeSnapshot)
 	completedTargets := make(map[string]struct{})
 	for _, step := range steps {
 		currentStepTargets := step.rollbackTargets()
-		if err := step.run(); err != nil {
+		inst.output.Detailf(messages.InstallUpgradeStepFmt, step.name)
+		if err := inst.journalStep(step.name, installJournalStepStarted); err != nil {
+			return err
+		}
+		if err := inst.runTransactionStep(step); err != nil {
 			snapshot.Status = upgradeSnapshotStatusRollbackFailed
 			snapshot.FailureStep = step.name
 			snapshot.FailureError = err.Error()
@@ -212,19 +261,27 @@ func (inst upgradeOrchestrator) runUpgradeTransaction(snapshot *upgradeSnapshot)
 				if writeErr := inst.writeUpgradeSnapshot(*snapshot, false); writeErr != nil {
 					return fmt.Errorf("upgrade step %s failed: %w; rollback failed: %v; failed to write snapshot state: %v", step.name, err, rollbackErr, writeErr)
 				}
-				_, _ = fmt.Fprintf(inst.warnOutput(), messages.InstallUpgradeSnapshotRollbackFailedFmt, step.name, snapshot.SnapshotID, rollbackErr)
+				_, _ = fmt.Fprintf(inst.warnOutput(), i18n.T(messages.InstallUpgradeSnapshotRollbackFailedFmt), step.name, snapshot.SnapshotID, rollbackErr)
 				return fmt.Errorf("upgrade step %s failed: %w; rollback failed: %v", step.name, err, rollbackErr)
 			}
 			snapshot.Status = upgradeSnapshotStatusAutoRolledBack
 			if writeErr := inst.writeUpgradeSnapshot(*snapshot, false); writeErr != nil {
 				return fmt.Errorf("upgrade step %s failed: %w; rollback succeeded; failed to write snapshot state: %v", step.name, err, writeErr)
 			}
-			_, _ = fmt.Fprintf(inst.warnOutput(), messages.InstallUpgradeSnapshotRolledBackFmt, step.name, snapshot.SnapshotID)
+			// The repo is back in its pre-upgrade state, so nothing is left
+			// to resume.
+			if clearErr := inst.finishJournal(); clearErr != nil {
+				return fmt.Errorf("upgrade step %s failed: %w; rollback succeeded; %v", step.name, err, clearErr)
+			}
+			_, _ = fmt.Fprintf(inst.warnOutput(), i18n.T(messages.InstallUpgradeSnapshotRolledBackFmt), step.name, snapshot.SnapshotID)
 			return fmt.Errorf("upgrade step %s failed (rolled back to pre-upgrade state): %w", step.name, err)
 		}
 		for _, path := range currentStepTargets {
 			completedTargets[filepath.Clean(path)] = struct{}{}
 		}
+		if err := inst.journalStep(step.name, installJournalStepDone); err != nil {
+			return err
+		}
 	}
 
 	snapshot.Status = upgradeSnapshotStatusApplied
@@ -252,9 +309,17 @@ func mergeRollbackTargets(completed map[string]struct{}, current []string) []str
 	return out
 }
 
-func runSteps(steps []func() error) error {
+// runJournaledSteps runs steps in order, recording each in the install
+// journal before and after it runs.
+func (inst *installer) runJournaledSteps(steps []transactionStep) error {
 	for _, step := range steps {
-		if err := step(); err != nil {
+		if err := inst.journalStep(step.name, installJournalStepStarted); err != nil {
+			return err
+		}
+		if err := step.run(); err != nil {
+			return err
+		}
+		if err := inst.journalStep(step.name, installJournalStepDone); err != nil {
 			return err
 		}
 	}
@@ -285,7 +350,7 @@ func (inst upgradeOrchestrator) ensureBaseDirs() error {
 	}
 	for _, dir := range dirs {
 		if err := sys.MkdirAll(dir, 0o755); err != nil {
-			return fmt.Errorf(messages.InstallCreateDirFailedFmt, dir, err)
+			return i18n.Errorf(messages.InstallCreateDirFailedFmt, dir, err)
 		}
 	}
 	return nil
@@ -311,8 +376,15 @@ func (inst templateManager) writeTemplateFiles() error {
 		}
 	}
 
-	// Upgrade-managed files: overwrite behavior is controlled by init/upgrade flags.
+	// Upgrade-managed files: overwrite behavior is controlled by init/upgrade
+	// flags, except for paths [ownership] gives to the user.
 	for _, file := range inst.managedTemplateFiles() {
+		if inst.isOwnershipOverride(inst.relativePath(file.path)) {
+			if err := writeTemplateIfMissing(inst.sys, file.path, file.template, file.perm); err != nil {
+				return err
+			}
+			continue
+		}
 		if file.template == templateGitignoreBlock {
 			if err := writeGitignoreBlock(inst.sys, file.path, file.template, file.perm, inst.shouldOverwrite, inst.recordDiff); err != nil {
 				return err
@@ -343,7 +415,7 @@ func (inst *installer) writeVersionFile() error {
 		switch {
 		case existing == "" || normErr != nil:
 			// Empty or corrupt pin file: auto-repair by falling through to write.
-			_, _ = fmt.Fprintf(inst.warnOutput(), messages.InstallAutoRepairPinWarningFmt, path, existing, inst.pinVersion)
+			_, _ = fmt.Fprintf(inst.warnOutput(), i18n.T(messages.InstallAutoRepairPinWarningFmt), path, existing, inst.pinVersion)
 		case normalized == inst.pinVersion:
 			return nil
 		default:
@@ -360,15 +432,15 @@ func (inst *installer) writeVersionFile() error {
 			}
 		}
 	} else if !errors.Is(err, os.ErrNotExist) {
-		return fmt.Errorf(messages.InstallFailedReadFmt, path, err)
+		return i18n.Errorf(messages.InstallFailedReadFmt, path, err)
 	}
 
 	if err := sys.MkdirAll(filepath.Dir(path), 0o755); err != nil {
-		return fmt.Errorf(messages.InstallFailedCreateDirForFmt, path, err)
+		return i18n.Errorf(messages.InstallFailedCreateDirForFmt, path, err)
 	}
 	content := []byte(inst.pinVersion + "\n")
 	if err := sys.WriteFileAtomic(path, content, 0o644); err != nil {
-		return fmt.Errorf(messages.InstallFailedWriteFmt, path, err)
+		return i18n.Errorf(messages.InstallFailedWriteFmt, path, err)
 	}
 	return nil
 }
@@ -401,6 +473,11 @@ func (inst *installer) warnOutput() io.Writer {
 	return os.Stderr
 }
 
+// now returns the current UTC time from the injected clock.
+func (inst *installer) now() time.Time {
+	return clock.Or(inst.clock).Now()
+}
+
 func (inst *installer) warnDifferences() {
 	if inst.overwrite || len(inst.diffs) == 0 {
 		return
@@ -411,16 +488,16 @@ func (inst *installer) warnDifferences() {
 	// NOTE: Errors from warning-output writes are intentionally discarded. These
 	// are non-critical warning messages; failing to display a warning should not
 	// abort the operation or propagate an error to the caller.
-	_, _ = fmt.Fprintln(out, messages.InstallDiffHeader)
+	_, _ = fmt.Fprintln(out, i18n.T(messages.InstallDiffHeader))
 	for _, path := range inst.diffs {
 		rel, err := filepath.Rel(inst.root, path)
 		if err != nil {
 			rel = path
 		}
-		_, _ = fmt.Fprintf(out, messages.InstallDiffLineFmt, rel)
+		_, _ = fmt.Fprintf(out, i18n.T(messages.InstallDiffLineFmt), rel)
 	}
 	_, _ = fmt.Fprintln(out)
-	_, _ = fmt.Fprintln(out, messages.InstallDiffFooter)
+	_, _ = fmt.Fprintln(out, i18n.T(messages.InstallDiffFooter))
 	_, _ = fmt.Fprintln(out)
 }
 
@@ -431,12 +508,12 @@ func (inst *installer) warnUnknowns() {
 
 	inst.sortUnknowns()
 	out := inst.warnOutput()
-	_, _ = fmt.Fprintln(out, messages.InstallUnknownHeader)
+	_, _ = fmt.Fprintln(out, i18n.T(messages.InstallUnknownHeader))
 	for _, path := range inst.unknowns {
 		rel := inst.relativePath(path)
-		_, _ = fmt.Fprintf(out, messages.InstallDiffLineFmt, rel)
+		_, _ = fmt.Fprintf(out, i18n.T(messages.InstallDiffLineFmt), rel)
 	}
 	_, _ = fmt.Fprintln(out)
-	_, _ = fmt.Fprintln(out, messages.InstallUnknownFooter)
+	_, _ = fmt.Fprintln(out, i18n.T(messages.InstallUnknownFooter))
 	_, _ = fmt.Fprintln(out)
 }
@@ -338,7 +338,8 @@ func (s *snapshotWriteFailOnNthSystem) WalkDir(root string, fn fs.WalkDirFunc) e
 
 func (s *snapshotWriteFailOnNthSystem) WriteFileAtomic(filename string, data []byte, perm os.FileMode) error {
 	snapshotPrefix := filepath.ToSlash(filepath.Join(s.failRoot, ".agent-layer", "state", "upgrade-snapshots")) + "/"
-	if strings.HasPrefix(filepath.ToSlash(normalizePath(filename)), snapshotPrefix) {
+	// Count manifest writes only; content blobs are written alongside them.
+	if strings.HasPrefix(filepath.ToSlash(normalizePath(filename)), snapshotPrefix) && strings.HasSuffix(filename, ".json") {
 		s.writes++
 		if s.writes == s.failOn {
 			return s.failErr
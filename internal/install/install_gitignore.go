@@ -2,11 +2,12 @@ package install
 
 import (
 	"errors"
-	"fmt"
 	"io/fs"
 	"os"
 	"path/filepath"
 
+	"github.com/conn-castle/agent-layer/internal/i18n"
+	"github.com/conn-castle/agent-layer/internal/layerdir"
 	"github.com/conn-castle/agent-layer/internal/messages"
 	"github.com/conn-castle/agent-layer/internal/templates"
 )
@@ -23,7 +24,7 @@ func (inst *installer) updateGitignore() error {
 	sys := inst.sys
 	blockBytes, err := sys.ReadFile(blockPath)
 	if err != nil {
-		return fmt.Errorf(messages.InstallFailedReadGitignoreBlockFmt, blockPath, err)
+		return i18n.Errorf(messages.InstallFailedReadGitignoreBlockFmt, blockPath, err)
 	}
 	block, err := ValidateGitignoreBlock(string(blockBytes), blockPath)
 	if err != nil {
@@ -42,12 +43,12 @@ func EnsureGitignore(sys GitignoreSystem, path string, block string) error {
 	block = wrapGitignoreBlock(renderGitignoreBlock(block))
 	contentBytes, err := sys.ReadFile(path)
 	if err != nil && !errors.Is(err, os.ErrNotExist) {
-		return fmt.Errorf(messages.InstallFailedReadFmt, path, err)
+		return i18n.Errorf(messages.InstallFailedReadFmt, path, err)
 	}
 
 	if errors.Is(err, os.ErrNotExist) {
 		if err := sys.WriteFileAtomic(path, []byte(block), 0o644); err != nil {
-			return fmt.Errorf(messages.InstallFailedWriteFmt, path, err)
+			return i18n.Errorf(messages.InstallFailedWriteFmt, path, err)
 		}
 		return nil
 	}
@@ -58,7 +59,7 @@ func EnsureGitignore(sys GitignoreSystem, path string, block string) error {
 		return err
 	}
 	if err := sys.WriteFileAtomic(path, []byte(updated), 0o644); err != nil {
-		return fmt.Errorf(messages.InstallFailedWriteFmt, path, err)
+		return i18n.Errorf(messages.InstallFailedWriteFmt, path, err)
 	}
 	return nil
 }
@@ -76,19 +77,19 @@ type RepairGitignoreBlockOptions struct {
 // and then reapplies the managed block to the repository root `.gitignore`.
 func RepairGitignoreBlock(root string, opts RepairGitignoreBlockOptions) error {
 	if root == "" {
-		return fmt.Errorf(messages.InstallRootRequired)
+		return i18n.Errorf(messages.InstallRootRequired)
 	}
 	sys := opts.System
 	if sys == nil {
-		return fmt.Errorf(messages.InstallSystemRequired)
+		return i18n.Errorf(messages.InstallSystemRequired)
 	}
 	blockBytes, err := templates.Read(templateGitignoreBlock)
 	if err != nil {
-		return fmt.Errorf(messages.InstallFailedReadTemplateFmt, templateGitignoreBlock, err)
+		return i18n.Errorf(messages.InstallFailedReadTemplateFmt, templateGitignoreBlock, err)
 	}
-	blockPath := filepath.Join(root, ".agent-layer", templateGitignoreBlock)
+	blockPath := filepath.Join(layerdir.Dir(root), templateGitignoreBlock)
 	if err := sys.WriteFileAtomic(blockPath, blockBytes, 0o644); err != nil {
-		return fmt.Errorf(messages.InstallFailedWriteFmt, blockPath, err)
+		return i18n.Errorf(messages.InstallFailedWriteFmt, blockPath, err)
 	}
 	block, err := ValidateGitignoreBlock(string(blockBytes), blockPath)
 	if err != nil {
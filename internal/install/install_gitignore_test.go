@@ -661,3 +661,31 @@ func TestRepairGitignoreBlock_WriteBlockError(t *testing.T) {
 		t.Fatal("expected write error when block path is a directory")
 	}
 }
+
+func TestRemoveGitignoreBlock(t *testing.T) {
+	cases := []struct {
+		name    string
+		content string
+		want    string
+		changed bool
+	}{
+		{name: "middle", content: "a\n\n# >>> agent-layer\nx\n# <<< agent-layer\n\nb\n", want: "a\n\nb\n", changed: true},
+		{name: "only block", content: "# >>> agent-layer\nx\n# <<< agent-layer\n", want: "", changed: true},
+		{name: "trailing block", content: "a\n# >>> agent-layer\nx\n# <<< agent-layer\n", want: "a\n", changed: true},
+		{name: "no block", content: "a\n", want: "a\n"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, changed, err := RemoveGitignoreBlock(tc.content, ".gitignore")
+			if err != nil {
+				t.Fatalf("RemoveGitignoreBlock: %v", err)
+			}
+			if got != tc.want || changed != tc.changed {
+				t.Fatalf("got (%q, %v), want (%q, %v)", got, changed, tc.want, tc.changed)
+			}
+		})
+	}
+	if _, _, err := RemoveGitignoreBlock("# >>> agent-layer\nx\n", ".gitignore"); err == nil {
+		t.Fatalf("expected error for unterminated block")
+	}
+}
@@ -0,0 +1,226 @@
+package install
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/conn-castle/agent-layer/internal/clock"
+	"github.com/conn-castle/agent-layer/internal/errcode"
+	"github.com/conn-castle/agent-layer/internal/i18n"
+	"github.com/conn-castle/agent-layer/internal/messages"
+)
+
+const (
+	installJournalRelPath       = ".agent-layer/state/install-journal.json"
+	installJournalSchemaVersion = 1
+
+	installJournalOperationInit    = "init"
+	installJournalOperationUpgrade = "upgrade"
+
+	installJournalStepStarted = "started"
+	installJournalStepDone    = "done"
+)
+
+// ErrInterruptedOperation matches the error Run returns when an earlier init
+// or upgrade stopped before finishing and Options.ResumeInterrupted is unset.
+var ErrInterruptedOperation = errors.New("interrupted install operation")
+
+// installJournal is the write-ahead record of a running init or upgrade. Each
+// step is recorded as started before it runs and done after it commits. Run
+// removes the journal when it finishes, so one left on disk means the process
+// stopped partway through.
+type installJournal struct {
+	SchemaVersion int                  `json:"schema_version"`
+	Operation     string               `json:"operation"`
+	SnapshotID    string               `json:"snapshot_id,omitempty"`
+	PinVersion    string               `json:"pin_version,omitempty"`
+	StartedAtUTC  string               `json:"started_at_utc"`
+	Steps         []installJournalStep `json:"steps"`
+}
+
+type installJournalStep struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+}
+
+// InterruptedOperation describes an init or upgrade that stopped before
+// finishing, as recorded in .agent-layer/state/install-journal.json.
+type InterruptedOperation struct {
+	// Operation is init or upgrade.
+	Operation string
+	// SnapshotID names the upgrade snapshot taken before the first step, or is
+	// empty for init, which takes none.
+	SnapshotID   string
+	PinVersion   string
+	StartedAtUTC string
+	// Step is the step that was running, or the last one that finished when
+	// the process stopped between steps.
+	Step string
+	// Completed lists the steps that finished.
+	Completed []string
+}
+
+// ReadInterruptedOperation returns the operation recorded in the install
+// journal under root, or nil when no operation was interrupted.
+func ReadInterruptedOperation(root string, sys System) (*InterruptedOperation, error) {
+	if strings.TrimSpace(root) == "" {
+		return nil, i18n.Errorf(messages.InstallRootRequired)
+	}
+	if sys == nil {
+		return nil, i18n.Errorf(messages.InstallSystemRequired)
+	}
+	sys, err := layerSystem(root, sys)
+	if err != nil {
+		return nil, err
+	}
+	journal, err := readInstallJournal(installJournalPath(root), sys)
+	if err != nil || journal == nil {
+		return nil, err
+	}
+	return journal.interruptedOperation(), nil
+}
+
+func (journal *installJournal) interruptedOperation() *InterruptedOperation {
+	op := &InterruptedOperation{
+		Operation:    journal.Operation,
+		SnapshotID:   journal.SnapshotID,
+		PinVersion:   journal.PinVersion,
+		StartedAtUTC: journal.StartedAtUTC,
+	}
+	for _, step := range journal.Steps {
+		op.Step = step.Name
+		if step.Status == installJournalStepDone {
+			op.Completed = append(op.Completed, step.Name)
+		}
+	}
+	return op
+}
+
+// ClearInterruptedOperation removes the install journal under root.
+func ClearInterruptedOperation(root string, sys System) error {
+	if sys == nil {
+		return i18n.Errorf(messages.InstallSystemRequired)
+	}
+	sys, err := layerSystem(root, sys)
+	if err != nil {
+		return err
+	}
+	path := installJournalPath(root)
+	if err := sys.RemoveAll(path); err != nil {
+		return i18n.Errorf(messages.InstallJournalRemoveFmt, path, err)
+	}
+	return nil
+}
+
+// InterruptedOperationError explains op and how to recover from it.
+func InterruptedOperationError(op *InterruptedOperation) error {
+	step := op.Step
+	if step == "" {
+		step = messages.InstallJournalNoStep
+	}
+	message := i18n.Sprintf(messages.InstallJournalInterruptedNoSnapshotFmt, op.Operation, step, op.StartedAtUTC)
+	if op.SnapshotID != "" {
+		message = i18n.Sprintf(messages.InstallJournalInterruptedFmt, op.Operation, step, op.StartedAtUTC, op.SnapshotID)
+	}
+	return errcode.Wrap(errcode.UpgradeConflict, &interruptedError{message: message})
+}
+
+type interruptedError struct {
+	message string
+}
+
+func (e *interruptedError) Error() string { return e.message }
+func (e *interruptedError) Unwrap() error { return ErrInterruptedOperation }
+
+func installJournalPath(root string) string {
+	return filepath.Join(root, filepath.FromSlash(installJournalRelPath))
+}
+
+func readInstallJournal(path string, sys System) (*installJournal, error) {
+	data, err := sys.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, i18n.Errorf(messages.InstallFailedReadFmt, path, err)
+	}
+	var journal installJournal
+	if err := json.Unmarshal(data, &journal); err != nil {
+		return nil, i18n.Errorf(messages.InstallJournalInvalidFmt, path, err)
+	}
+	if journal.SchemaVersion != installJournalSchemaVersion {
+		return nil, i18n.Errorf(messages.InstallJournalInvalidFmt, path, fmt.Errorf("unsupported schema_version %d", journal.SchemaVersion))
+	}
+	return &journal, nil
+}
+
+// checkInterrupted fails when an earlier operation left its journal behind,
+// unless the caller chose to resume over it.
+func (inst *installer) checkInterrupted(resume bool) error {
+	journal, err := readInstallJournal(installJournalPath(inst.root), inst.sys)
+	if err != nil || journal == nil || resume {
+		return err
+	}
+	return InterruptedOperationError(journal.interruptedOperation())
+}
+
+// beginJournal records the start of operation, replacing any journal an
+// interrupted run left behind.
+func (inst *installer) beginJournal(operation string, snapshotID string) error {
+	inst.journal = &installJournal{
+		SchemaVersion: installJournalSchemaVersion,
+		Operation:     operation,
+		SnapshotID:    snapshotID,
+		PinVersion:    inst.pinVersion,
+		StartedAtUTC:  clock.Format(inst.now()),
+		Steps:         []installJournalStep{},
+	}
+	return inst.writeJournal()
+}
+
+// journalStep records step as started or done. It is a no-op outside a
+// journaled operation.
+func (inst *installer) journalStep(name string, status string) error {
+	if inst.journal == nil {
+		return nil
+	}
+	steps := inst.journal.Steps
+	if n := len(steps); n > 0 && steps[n-1].Name == name {
+		steps[n-1].Status = status
+	} else {
+		inst.journal.Steps = append(steps, installJournalStep{Name: name, Status: status})
+	}
+	return inst.writeJournal()
+}
+
+// finishJournal removes the journal once the operation is complete.
+func (inst *installer) finishJournal() error {
+	if inst.journal == nil {
+		return nil
+	}
+	inst.journal = nil
+	path := installJournalPath(inst.root)
+	if err := inst.sys.RemoveAll(path); err != nil {
+		return i18n.Errorf(messages.InstallJournalRemoveFmt, path, err)
+	}
+	return nil
+}
+
+func (inst *installer) writeJournal() error {
+	path := installJournalPath(inst.root)
+	if err := inst.sys.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return i18n.Errorf(messages.InstallFailedCreateDirForFmt, filepath.Dir(path), err)
+	}
+	data, err := json.MarshalIndent(inst.journal, "", "  ")
+	if err != nil {
+		return i18n.Errorf(messages.InstallJournalWriteFmt, path, err)
+	}
+	if err := inst.sys.WriteFileAtomic(path, append(data, '\n'), 0o644); err != nil {
+		return i18n.Errorf(messages.InstallJournalWriteFmt, path, err)
+	}
+	return nil
+}
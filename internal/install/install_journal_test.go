@@ -0,0 +1,86 @@
+package install
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// interruptUpgrade leaves the journal an upgrade process would leave if it
+// died during runMigrations.
+func interruptUpgrade(t *testing.T, root string) string {
+	t.Helper()
+	inst := &installer{root: root, sys: RealSystem{}}
+	snapshot, err := inst.createUpgradeSnapshot()
+	if err != nil {
+		t.Fatalf("createUpgradeSnapshot: %v", err)
+	}
+	if err := inst.beginJournal(installJournalOperationUpgrade, snapshot.SnapshotID); err != nil {
+		t.Fatalf("beginJournal: %v", err)
+	}
+	if err := inst.journalStep("runMigrations", installJournalStepStarted); err != nil {
+		t.Fatalf("journalStep: %v", err)
+	}
+	return snapshot.SnapshotID
+}
+
+func TestRun_InstallJournal(t *testing.T) {
+	root := t.TempDir()
+	if err := Run(root, Options{System: RealSystem{}}); err != nil {
+		t.Fatalf("init: %v", err)
+	}
+	if _, err := os.Stat(installJournalPath(root)); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("finished init must remove the journal, stat err %v", err)
+	}
+
+	snapshotID := interruptUpgrade(t, root)
+	op, err := ReadInterruptedOperation(root, RealSystem{})
+	if err != nil || op == nil || op.Operation != "upgrade" || op.Step != "runMigrations" || op.SnapshotID != snapshotID || len(op.Completed) != 0 {
+		t.Fatalf("ReadInterruptedOperation = %+v (%v)", op, err)
+	}
+	err = Run(root, Options{Overwrite: true, Prompter: autoApprovePrompter(), System: RealSystem{}})
+	if !errors.Is(err, ErrInterruptedOperation) || !strings.Contains(err.Error(), "al upgrade rollback "+snapshotID) {
+		t.Fatalf("upgrade over interrupted journal = %v", err)
+	}
+
+	if err := RollbackUpgradeSnapshot(root, snapshotID, RollbackUpgradeSnapshotOptions{System: RealSystem{}}); err != nil {
+		t.Fatalf("RollbackUpgradeSnapshot: %v", err)
+	}
+	if op, err := ReadInterruptedOperation(root, RealSystem{}); err != nil || op != nil {
+		t.Fatalf("rollback must clear the journal, got %+v (%v)", op, err)
+	}
+
+	interruptUpgrade(t, root)
+	if err := Run(root, Options{Overwrite: true, Prompter: autoApprovePrompter(), System: RealSystem{}, ResumeInterrupted: true}); err != nil {
+		t.Fatalf("resumed upgrade: %v", err)
+	}
+	if op, err := ReadInterruptedOperation(root, RealSystem{}); err != nil || op != nil {
+		t.Fatalf("finished upgrade must clear the journal, got %+v (%v)", op, err)
+	}
+}
+
+func TestRun_FailedStepRecordedInJournal(t *testing.T) {
+	root := t.TempDir()
+	if err := Run(root, Options{System: RealSystem{}}); err != nil {
+		t.Fatalf("init: %v", err)
+	}
+	fs := newFaultSystem(RealSystem{})
+	fs.writeErrs[normalizePath(filepath.Join(root, ".agent-layer", "al.version"))] = errors.New("disk full")
+	inst := &installer{root: root, sys: fs, pinVersion: "0.9.0"}
+	if err := inst.beginJournal(installJournalOperationInit, ""); err != nil {
+		t.Fatal(err)
+	}
+	err := inst.runJournaledSteps([]transactionStep{{name: "writeVersionFile", run: inst.writeVersionFile}})
+	if err == nil {
+		t.Fatal("expected write failure")
+	}
+	op, err := ReadInterruptedOperation(root, RealSystem{})
+	if err != nil || op == nil || op.Operation != "init" || op.Step != "writeVersionFile" {
+		t.Fatalf("ReadInterruptedOperation = %+v (%v)", op, err)
+	}
+	if err := InterruptedOperationError(op); !strings.Contains(err.Error(), "al upgrade --resume") || strings.Contains(err.Error(), "rollback") {
+		t.Fatalf("init recovery hint = %v", err)
+	}
+}
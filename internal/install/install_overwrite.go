@@ -2,18 +2,18 @@ package install
 
 import (
 	"errors"
-	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
 
+	"github.com/conn-castle/agent-layer/internal/i18n"
 	"github.com/conn-castle/agent-layer/internal/messages"
 )
 
 // shouldOverwrite decides whether to overwrite the given path.
 // It returns true to overwrite, false to keep existing content, or an error.
 func (inst *installer) shouldOverwrite(path string) (bool, error) {
-	if !inst.overwrite {
+	if !inst.overwrite || inst.filteredTemplatePath(path) {
 		return false, nil
 	}
 	router := inst.promptRouter()
@@ -40,7 +40,7 @@ func (inst *installer) shouldOverwrite(path string) (bool, error) {
 		}
 	}
 	if inst.prompter == nil {
-		return false, fmt.Errorf(messages.InstallOverwritePromptRequired)
+		return false, i18n.Errorf(messages.InstallOverwritePromptRequired)
 	}
 	rel := path
 	if inst.root != "" {
@@ -65,7 +65,7 @@ func (inst *installer) resolveUnifiedOverwriteAllDecisions() error {
 	}
 	router := inst.promptRouter()
 	if !router.hasUnifiedOverwrite() {
-		return fmt.Errorf(messages.InstallOverwritePromptRequired)
+		return i18n.Errorf(messages.InstallOverwritePromptRequired)
 	}
 
 	managedDiffs, err := inst.templates().listManagedLabeledDiffs()
@@ -170,7 +170,7 @@ func (inst *installer) resolveOverwriteAllDecision(
 		return *decision, nil
 	}
 	if inst.prompter == nil {
-		return false, fmt.Errorf(messages.InstallOverwritePromptRequired)
+		return false, i18n.Errorf(messages.InstallOverwritePromptRequired)
 	}
 
 	previews, err := buildPreviews()
@@ -216,7 +216,7 @@ func (inst *installer) relativePath(path string) string {
 func (inst *installer) lookupDiffPreview(relPath string) (DiffPreview, error) {
 	relPath = filepath.ToSlash(relPath)
 	if relPath == "" {
-		return DiffPreview{}, fmt.Errorf(messages.InstallDiffPreviewPathRequired)
+		return DiffPreview{}, i18n.Errorf(messages.InstallDiffPreviewPathRequired)
 	}
 	if preview, ok := inst.managedDiffPreviews[relPath]; ok {
 		return preview, nil
@@ -261,7 +261,7 @@ func (inst *installer) diffPreviewEntry(relPath string, templatePathByRel map[st
 	}
 	templatePath := templatePathByRel[relPath]
 	if strings.TrimSpace(templatePath) == "" {
-		return LabeledPath{}, fmt.Errorf(messages.InstallMissingTemplatePathMappingFmt, relPath)
+		return LabeledPath{}, i18n.Errorf(messages.InstallMissingTemplatePathMappingFmt, relPath)
 	}
 	ownership, err := inst.ownership().classifyOwnership(relPath, templatePath)
 	if err != nil {
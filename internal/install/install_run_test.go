@@ -123,9 +123,10 @@ func TestEnsureBaseDirsError(t *testing.T) {
 	}
 }
 
-func TestRunStepsError(t *testing.T) {
-	err := runSteps([]func() error{
-		func() error { return fmt.Errorf("boom") },
+func TestRunJournaledStepsError(t *testing.T) {
+	inst := &installer{root: t.TempDir(), sys: RealSystem{}}
+	err := inst.runJournaledSteps([]transactionStep{
+		{name: "boom", run: func() error { return fmt.Errorf("boom") }},
 	})
 	if err == nil {
 		t.Fatalf("expected error")
@@ -2,13 +2,13 @@ package install
 
 import (
 	"errors"
-	"fmt"
 	"io/fs"
 	"os"
 	"path/filepath"
 	"sort"
 	"strings"
 
+	"github.com/conn-castle/agent-layer/internal/i18n"
 	"github.com/conn-castle/agent-layer/internal/messages"
 	"github.com/conn-castle/agent-layer/internal/templates"
 )
@@ -203,7 +203,7 @@ func (inst templateManager) anyExistingTemplateDirFile(dir templateDir) (bool, e
 		if _, statErr := inst.sys.Stat(entry.destPath); statErr == nil {
 			return true, nil
 		} else if !errors.Is(statErr, os.ErrNotExist) {
-			return false, fmt.Errorf(messages.InstallFailedStatFmt, entry.destPath, statErr)
+			return false, i18n.Errorf(messages.InstallFailedStatFmt, entry.destPath, statErr)
 		}
 	}
 	return false, nil
@@ -222,11 +222,11 @@ func (inst templateManager) installedCatalogSkillTemplateDirs() ([]templateDir,
 		}
 		rel := strings.TrimPrefix(path, "skills-catalog/")
 		if rel == path {
-			return fmt.Errorf(messages.InstallUnexpectedTemplatePathFmt, path)
+			return i18n.Errorf(messages.InstallUnexpectedTemplatePathFmt, path)
 		}
 		parts := strings.SplitN(rel, "/", 2)
 		if len(parts) != 2 || parts[0] == "" {
-			return fmt.Errorf(messages.InstallUnexpectedTemplatePathFmt, path)
+			return i18n.Errorf(messages.InstallUnexpectedTemplatePathFmt, path)
 		}
 		ids[parts[0]] = struct{}{}
 		return nil
@@ -247,7 +247,7 @@ func (inst templateManager) installedCatalogSkillTemplateDirs() ([]templateDir,
 			if errors.Is(err, os.ErrNotExist) {
 				continue
 			}
-			return nil, fmt.Errorf(messages.InstallFailedStatFmt, destRoot, err)
+			return nil, i18n.Errorf(messages.InstallFailedStatFmt, destRoot, err)
 		}
 		if !info.IsDir() {
 			continue
@@ -334,12 +334,15 @@ func (inst templateManager) listMemoryLabeledDiffs() ([]LabeledPath, error) {
 func (inst templateManager) appendTemplateFileDiffs(diffs map[string]struct{}, files []templateFile) error {
 	sys := inst.sys
 	for _, file := range files {
+		if inst.isOwnershipOverride(inst.relativePath(file.path)) {
+			continue
+		}
 		info, err := sys.Stat(file.path)
 		if err != nil {
 			if errors.Is(err, os.ErrNotExist) {
 				continue
 			}
-			return fmt.Errorf(messages.InstallFailedStatFmt, file.path, err)
+			return i18n.Errorf(messages.InstallFailedStatFmt, file.path, err)
 		}
 		matches, err := inst.matchTemplate(sys, file.path, file.template, info)
 		if err != nil {
@@ -360,8 +363,8 @@ func (inst templateManager) appendTemplateDirDiffs(diffs map[string]struct{}, di
 	}
 	sys := inst.sys
 	for _, entry := range entries {
-		// User-owned instruction files are never reported as managed diffs.
-		if IsUserOwnedInstructionFile(entry.destPath) {
+		// User-owned files are never reported as managed diffs.
+		if inst.isUserOwned(entry.destPath) {
 			continue
 		}
 		relPath := normalizeRelPath(inst.relativePath(entry.destPath))
@@ -370,7 +373,7 @@ func (inst templateManager) appendTemplateDirDiffs(diffs map[string]struct{}, di
 			if errors.Is(err, os.ErrNotExist) {
 				continue
 			}
-			return fmt.Errorf(messages.InstallFailedStatFmt, entry.destPath, err)
+			return i18n.Errorf(messages.InstallFailedStatFmt, entry.destPath, err)
 		}
 		if _, ok := sectionAwareMarkerForPath(relPath); ok {
 			matches, matchErr := inst.sectionAwareTemplateMatch(relPath, entry.destPath, entry.templatePath)
@@ -474,8 +477,8 @@ func (inst templateManager) writeTemplateDirCached(dir templateDir) error {
 	}
 	sys := inst.sys
 	for _, entry := range entries {
-		// User-owned instruction files: seed only; never overwrite.
-		if IsUserOwnedInstructionFile(entry.destPath) {
+		// User-owned files: seed only; never overwrite.
+		if inst.isUserOwned(entry.destPath) {
 			if err := writeTemplateIfMissing(sys, entry.destPath, entry.templatePath, entry.perm); err != nil {
 				return err
 			}
@@ -500,11 +503,11 @@ func (inst templateManager) writeSectionAwareTemplateFile(path string, templateP
 	if err == nil {
 		localBytes, readErr := inst.sys.ReadFile(path)
 		if readErr != nil {
-			return fmt.Errorf(messages.InstallFailedReadFmt, path, readErr)
+			return i18n.Errorf(messages.InstallFailedReadFmt, path, readErr)
 		}
 		templateBytes, templateErr := templates.Read(templatePath)
 		if templateErr != nil {
-			return fmt.Errorf(messages.InstallFailedReadTemplateFmt, templatePath, templateErr)
+			return i18n.Errorf(messages.InstallFailedReadTemplateFmt, templatePath, templateErr)
 		}
 
 		localManaged, localUser, splitErr := splitSectionAwareContent(relPath, marker, localBytes)
@@ -531,12 +534,12 @@ func (inst templateManager) writeSectionAwareTemplateFile(path string, templateP
 
 		merged := []byte(templateManaged + localUser)
 		if err := inst.sys.WriteFileAtomic(path, merged, perm); err != nil {
-			return fmt.Errorf(messages.InstallFailedWriteFmt, path, err)
+			return i18n.Errorf(messages.InstallFailedWriteFmt, path, err)
 		}
 		return nil
 	}
 	if !errors.Is(err, os.ErrNotExist) {
-		return fmt.Errorf(messages.InstallFailedStatFmt, path, err)
+		return i18n.Errorf(messages.InstallFailedStatFmt, path, err)
 	}
 	return writeTemplateFileWithMatch(inst.sys, path, templatePath, perm, inst.shouldOverwrite, inst.recordDiff, inst.matchTemplate)
 }
@@ -559,7 +562,7 @@ func (inst templateManager) templateDirEntries(dir templateDir) ([]templateEntry
 		}
 		rel := strings.TrimPrefix(path, dir.templateRoot+"/")
 		if rel == path {
-			return fmt.Errorf(messages.InstallUnexpectedTemplatePathFmt, path)
+			return i18n.Errorf(messages.InstallUnexpectedTemplatePathFmt, path)
 		}
 		destPath := filepath.Join(dir.destRoot, rel)
 		entries = append(entries, templateEntry{
@@ -661,18 +664,18 @@ func writeTemplateFileWithMatch(
 			return nil
 		}
 	} else if !errors.Is(err, os.ErrNotExist) {
-		return fmt.Errorf(messages.InstallFailedStatFmt, path, err)
+		return i18n.Errorf(messages.InstallFailedStatFmt, path, err)
 	}
 
 	data, err := templates.Read(templatePath)
 	if err != nil {
-		return fmt.Errorf(messages.InstallFailedReadTemplateFmt, templatePath, err)
+		return i18n.Errorf(messages.InstallFailedReadTemplateFmt, templatePath, err)
 	}
 	if err := sys.MkdirAll(filepath.Dir(path), 0o755); err != nil {
-		return fmt.Errorf(messages.InstallFailedCreateDirForFmt, path, err)
+		return i18n.Errorf(messages.InstallFailedCreateDirForFmt, path, err)
 	}
 	if err := sys.WriteFileAtomic(path, data, perm); err != nil {
-		return fmt.Errorf(messages.InstallFailedWriteFmt, path, err)
+		return i18n.Errorf(messages.InstallFailedWriteFmt, path, err)
 	}
 	return nil
 }
@@ -680,11 +683,11 @@ func writeTemplateFileWithMatch(
 func fileMatchesTemplate(sys System, path string, templatePath string) (bool, error) {
 	existing, err := sys.ReadFile(path)
 	if err != nil {
-		return false, fmt.Errorf(messages.InstallFailedReadFmt, path, err)
+		return false, i18n.Errorf(messages.InstallFailedReadFmt, path, err)
 	}
 	template, err := templates.Read(templatePath)
 	if err != nil {
-		return false, fmt.Errorf(messages.InstallFailedReadTemplateFmt, templatePath, err)
+		return false, i18n.Errorf(messages.InstallFailedReadTemplateFmt, templatePath, err)
 	}
 	return normalizeTemplateContent(string(existing)) == normalizeTemplateContent(string(template)), nil
 }
@@ -9,7 +9,9 @@ import (
 	"sort"
 	"strings"
 
+	"github.com/conn-castle/agent-layer/internal/i18n"
 	"github.com/conn-castle/agent-layer/internal/launchers"
+	"github.com/conn-castle/agent-layer/internal/lockfile"
 	"github.com/conn-castle/agent-layer/internal/messages"
 	"github.com/conn-castle/agent-layer/internal/templates"
 )
@@ -43,9 +45,9 @@ func (inst *installer) scanUnknownRoot(root string, known map[string]struct{}) e
 		if errors.Is(err, os.ErrNotExist) {
 			return nil
 		}
-		return fmt.Errorf(messages.InstallFailedStatFmt, root, err)
+		return i18n.Errorf(messages.InstallFailedStatFmt, root, err)
 	}
-	return sys.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+	return walkDirFollowingRoot(sys, root, func(path string, entry fs.DirEntry, err error) error {
 		if err != nil {
 			return err
 		}
@@ -56,6 +58,9 @@ func (inst *installer) scanUnknownRoot(root string, known map[string]struct{}) e
 		if _, ok := known[clean]; ok {
 			return nil
 		}
+		if skip, walkErr := inst.skipUserOwnedUnknown(clean, entry); skip {
+			return walkErr
+		}
 		inst.recordUnknown(clean)
 		if entry.IsDir() {
 			return filepath.SkipDir
@@ -70,6 +75,9 @@ func (inst *installer) scanUnknownRoot(root string, known map[string]struct{}) e
 // early scanUnknowns call captures unknowns for snapshot/rollback safety but
 // may include paths that migrations have since moved or deleted).
 //
+// Files an earlier release installed but the current one no longer ships are
+// offered for deletion first as one group (see handleOrphanedArtifacts).
+//
 // Tmp paths under `.agent-layer/tmp/` are routed exclusively through
 // handleTmpUnknowns — they are never deleted via the bulk "delete all" path,
 // nor via the per-file DeleteUnknown loop, so the destructive-action
@@ -87,7 +95,11 @@ func (inst *installer) handleUnknowns() error {
 		return nil
 	}
 	if inst.prompter == nil {
-		return fmt.Errorf(messages.InstallDeleteUnknownPromptRequired)
+		return i18n.Errorf(messages.InstallDeleteUnknownPromptRequired)
+	}
+	unknowns, err = inst.handleOrphanedArtifacts(unknowns)
+	if err != nil {
+		return err
 	}
 	tmpUnknowns, otherUnknowns := inst.partitionTmpUnknowns(unknowns)
 	if err := inst.handleNonTmpUnknowns(tmpUnknowns, otherUnknowns); err != nil {
@@ -126,7 +138,7 @@ func (inst *installer) handleNonTmpUnknowns(tmpUnknowns, otherUnknowns []string)
 		}
 		if deletePath.approved {
 			if err := inst.sys.RemoveAll(path); err != nil {
-				return fmt.Errorf(messages.InstallDeleteUnknownFailedFmt, relPath, err)
+				return i18n.Errorf(messages.InstallDeleteUnknownFailedFmt, relPath, err)
 			}
 		}
 	}
@@ -222,10 +234,10 @@ func (inst *installer) walkUnknownsInRoot(root string, known map[string]struct{}
 		if errors.Is(err, os.ErrNotExist) {
 			return nil, nil
 		}
-		return nil, fmt.Errorf(messages.InstallFailedStatFmt, root, err)
+		return nil, i18n.Errorf(messages.InstallFailedStatFmt, root, err)
 	}
 	var unknowns []string
-	walkErr := sys.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+	walkErr := walkDirFollowingRoot(sys, root, func(path string, entry fs.DirEntry, err error) error {
 		if err != nil {
 			return err
 		}
@@ -236,6 +248,9 @@ func (inst *installer) walkUnknownsInRoot(root string, known map[string]struct{}
 		if _, ok := known[clean]; ok {
 			return nil
 		}
+		if skip, walkErr := inst.skipUserOwnedUnknown(clean, entry); skip {
+			return walkErr
+		}
 		unknowns = append(unknowns, clean)
 		if entry.IsDir() {
 			return filepath.SkipDir
@@ -253,7 +268,7 @@ func (inst *installer) deleteUnknowns(paths []string) error {
 	for _, path := range paths {
 		rel := inst.relativePath(path)
 		if err := sys.RemoveAll(path); err != nil {
-			return fmt.Errorf(messages.InstallDeleteUnknownFailedFmt, rel, err)
+			return i18n.Errorf(messages.InstallDeleteUnknownFailedFmt, rel, err)
 		}
 	}
 	return nil
@@ -327,9 +342,27 @@ func (inst *installer) buildKnownPaths() (map[string]struct{}, error) {
 	// the lock would let a concurrent sync acquire a fresh flock and defeat
 	// serialization).
 	add(filepath.Join(root, ".agent-layer", SyncLockFileName))
+	// al.lock and the skills it records are managed by `al add skill` and
+	// `al update`, not by templates.
+	add(filepath.Join(root, ".agent-layer", lockfile.FileName))
+	lock, err := lockfile.Load(root)
+	if err != nil {
+		return nil, err
+	}
+	for _, skill := range lock.Skills {
+		if err := inst.addExistingKnownPaths(filepath.Join(root, ".agent-layer", "skills", skill.Name), add); err != nil {
+			return nil, err
+		}
+	}
+
+	// Directory-scoped instruction sources have no template counterpart; keep
+	// them out of the unknown-file prompts.
+	if err := inst.addExistingKnownPaths(filepath.Join(root, ".agent-layer", "scoped"), add); err != nil {
+		return nil, err
+	}
 
 	// VS Code launchers generated by sync.
-	for _, path := range launchers.VSCodePaths(root).All() {
+	for _, path := range launchers.VSCodePathsIn(filepath.Join(root, ".agent-layer")).All() {
 		add(path)
 	}
 
@@ -345,14 +378,14 @@ func (inst *installer) buildKnownPaths() (map[string]struct{}, error) {
 				}
 				rel := strings.TrimPrefix(path, templateRoot+"/")
 				if rel == path {
-					return fmt.Errorf(messages.InstallUnexpectedTemplatePathFmt, path)
+					return i18n.Errorf(messages.InstallUnexpectedTemplatePathFmt, path)
 				}
 				add(filepath.Join(destRoot, rel))
 				return nil
 			}
 			rel := strings.TrimPrefix(path, templateRoot+"/")
 			if rel == path {
-				return fmt.Errorf(messages.InstallUnexpectedTemplatePathFmt, path)
+				return i18n.Errorf(messages.InstallUnexpectedTemplatePathFmt, path)
 			}
 			add(filepath.Join(destRoot, rel))
 			return nil
@@ -397,9 +430,9 @@ func (inst *installer) addExistingKnownPaths(root string, add func(string)) erro
 		if errors.Is(err, os.ErrNotExist) {
 			return nil
 		}
-		return fmt.Errorf(messages.InstallFailedStatFmt, root, err)
+		return i18n.Errorf(messages.InstallFailedStatFmt, root, err)
 	}
-	return sys.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+	return walkDirFollowingRoot(sys, root, func(path string, entry fs.DirEntry, err error) error {
 		if err != nil {
 			return err
 		}
@@ -8,6 +8,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/conn-castle/agent-layer/internal/lockfile"
 	"github.com/conn-castle/agent-layer/internal/messages"
 )
 
@@ -749,7 +750,7 @@ func TestHandleUnknowns_TopLevelSummaryCollapsesTmp(t *testing.T) {
 
 func TestHandleUnknowns_TmpFallback_LegacyPrompterPreservesTmp(t *testing.T) {
 	// Tmp deletion is destructive (snapshots do not capture tmp). When the
-	// prompter does not implement the grouped tmpUnknownsPrompter capability,
+	// prompter does not implement the grouped TmpUnknownsPrompter capability,
 	// tmp paths must be left untouched rather than falling back to per-file
 	// prompts — the destructive double-confirm only exists in the grouped
 	// path, so the per-file fallback would silently bypass the safety guard.
@@ -777,7 +778,7 @@ func TestHandleUnknowns_TmpFallback_LegacyPrompterPreservesTmp(t *testing.T) {
 }
 
 // legacyDeleteOnlyPrompter satisfies Prompter without implementing the
-// optional tmpUnknownsPrompter interface, modelling a stale Prompter
+// optional TmpUnknownsPrompter interface, modelling a stale Prompter
 // implementation that only handles the original DeleteUnknown(All) prompts.
 type legacyDeleteOnlyPrompter struct {
 	deleteAll func([]string) (bool, error)
@@ -795,7 +796,7 @@ func (p legacyDeleteOnlyPrompter) DeleteUnknown(path string) (bool, error) {
 }
 
 func TestHandleUnknowns_TmpFallback_PromptFuncsWithoutTmpAllFuncPreservesTmp(t *testing.T) {
-	// PromptFuncs always satisfies tmpUnknownsPrompter (the method is defined
+	// PromptFuncs always satisfies TmpUnknownsPrompter (the method is defined
 	// on the struct), but a caller may construct it without wiring
 	// DeleteUnknownTmpAllFunc. In that case handleTmpUnknowns must preserve
 	// tmp content rather than surfacing the "prompt required" error or
@@ -836,3 +837,49 @@ func TestRelativeUnknowns_Empty(t *testing.T) {
 		t.Fatalf("expected nil for empty unknowns, got %v", rel)
 	}
 }
+
+func TestScanUnknowns_LockfileAndLockedSkillsAreKnown(t *testing.T) {
+	// Skills fetched by `al add skill` are recorded in al.lock; neither the lock
+	// nor the fetched skill directories should be offered for deletion.
+	root := t.TempDir()
+	skillPath := filepath.Join(root, ".agent-layer", "skills", "review", "SKILL.md")
+	if err := os.MkdirAll(filepath.Dir(skillPath), 0o700); err != nil {
+		t.Fatalf("mkdir skill: %v", err)
+	}
+	if err := os.WriteFile(skillPath, []byte("---\nname: review\n---\n"), 0o600); err != nil {
+		t.Fatalf("write skill: %v", err)
+	}
+	lock := lockfile.File{Version: lockfile.SchemaVersion}
+	lock.SetSkill(lockfile.Skill{Name: "review", Source: "https://example.com/review", Checksum: "sha256:abc"})
+	if err := lockfile.Save(root, lock); err != nil {
+		t.Fatalf("save lockfile: %v", err)
+	}
+
+	inst := &installer{root: root, sys: RealSystem{}}
+	if err := inst.scanUnknowns(); err != nil {
+		t.Fatalf("scanUnknowns: %v", err)
+	}
+	if rel := inst.relativeUnknowns(); len(rel) != 0 {
+		t.Fatalf("al.lock and locked skills should be known, got unknowns %v", rel)
+	}
+}
+
+func TestScanUnknowns_FollowsSymlinkedAgentLayerDir(t *testing.T) {
+	root := t.TempDir()
+	dotfiles := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dotfiles, "custom.md"), []byte("custom\n"), 0o600); err != nil {
+		t.Fatalf("write custom file: %v", err)
+	}
+	if err := os.Symlink(dotfiles, filepath.Join(root, ".agent-layer")); err != nil {
+		t.Fatalf("symlink .agent-layer: %v", err)
+	}
+
+	inst := &installer{root: root, sys: RealSystem{}}
+	if err := inst.scanUnknowns(); err != nil {
+		t.Fatalf("scanUnknowns: %v", err)
+	}
+	rel := inst.relativeUnknowns()
+	if len(rel) != 1 || rel[0] != filepath.Join(".agent-layer", "custom.md") {
+		t.Fatalf("expected the file behind the symlink as unknown, got %v", rel)
+	}
+}
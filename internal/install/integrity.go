@@ -0,0 +1,196 @@
+package install
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/conn-castle/agent-layer/internal/i18n"
+	"github.com/conn-castle/agent-layer/internal/messages"
+)
+
+// Integrity check kinds reported by CheckIntegrity.
+const (
+	IntegrityKindPin      = "pin"
+	IntegrityKindManaged  = "managed"
+	IntegrityKindSnapshot = "snapshot"
+)
+
+// Integrity check statuses. Only IntegrityStatusFail makes a repo fail
+// verification; modified and missing managed files are expected when users
+// customize or remove templates, so they are reported but tolerated.
+const (
+	IntegrityStatusOK       = "ok"
+	IntegrityStatusModified = "modified"
+	IntegrityStatusMissing  = "missing"
+	IntegrityStatusSkipped  = "skipped"
+	IntegrityStatusFail     = "fail"
+)
+
+// IntegrityCheck is the outcome of one integrity check. Name is a repo-relative
+// path for managed files and snapshots, and the pinned version for the pin.
+type IntegrityCheck struct {
+	Kind   string
+	Name   string
+	Status string
+	// Detail explains any status other than ok.
+	Detail string
+}
+
+// CheckIntegrity compares the repo's install state with what the installer
+// recorded: the pin file must agree with the managed baseline, each managed
+// file is compared with the baseline release's template manifest, and every
+// upgrade snapshot must parse. It writes nothing. The returned error is
+// non-nil only when the checks themselves cannot run.
+func CheckIntegrity(root string, sys System) ([]IntegrityCheck, error) {
+	if strings.TrimSpace(root) == "" {
+		return nil, i18n.Errorf(messages.InstallRootRequired)
+	}
+	if sys == nil {
+		return nil, i18n.Errorf(messages.InstallSystemRequired)
+	}
+	sys, err := layerSystem(root, sys)
+	if err != nil {
+		return nil, err
+	}
+	inst := &installer{root: root, sys: sys}
+
+	pinCheck, baselineVersion, err := inst.checkPinIntegrity()
+	if err != nil {
+		return nil, err
+	}
+	checks := []IntegrityCheck{pinCheck}
+	if baselineVersion != "" {
+		managed, err := inst.checkManagedIntegrity(baselineVersion)
+		if err != nil {
+			return nil, err
+		}
+		checks = append(checks, managed...)
+	}
+	snapshots, err := inst.checkSnapshotIntegrity()
+	if err != nil {
+		return nil, err
+	}
+	return append(checks, snapshots...), nil
+}
+
+// checkPinIntegrity compares .agent-layer/al.version with the managed
+// baseline and returns the baseline version managed files are checked
+// against ("" when there is no usable baseline).
+func (inst *installer) checkPinIntegrity() (IntegrityCheck, string, error) {
+	pinned, err := readCurrentPinVersion(inst.root, inst.sys)
+	if err != nil {
+		return IntegrityCheck{Kind: IntegrityKindPin, Name: ".agent-layer/al.version", Status: IntegrityStatusFail, Detail: err.Error()}, "", nil
+	}
+	name := pinned
+	if name == "" {
+		name = "unpinned"
+	}
+	check := IntegrityCheck{Kind: IntegrityKindPin, Name: name, Status: IntegrityStatusOK}
+	state, err := readManagedBaselineState(inst.root, inst.sys)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		check.Status, check.Detail = IntegrityStatusFail, i18n.Sprintf(messages.InstallIntegrityBaselineMissingFmt, baselineStateRelPath)
+		return check, "", nil
+	case err != nil:
+		check.Status, check.Detail = IntegrityStatusFail, err.Error()
+		return check, "", nil
+	}
+	if pinned == "" {
+		check.Status, check.Detail = IntegrityStatusSkipped, i18n.Sprintf(messages.InstallIntegrityUnpinnedFmt, state.BaselineVersion)
+		return check, state.BaselineVersion, nil
+	}
+	if state.BaselineVersion != pinned {
+		check.Status, check.Detail = IntegrityStatusFail, i18n.Sprintf(messages.InstallIntegrityPinMismatchFmt, state.BaselineVersion)
+	}
+	return check, state.BaselineVersion, nil
+}
+
+// checkManagedIntegrity compares each file in the baseline release's template
+// manifest with the repo, using the same ownership comparison as upgrades so
+// user entries in memory docs do not count as modifications.
+func (inst *installer) checkManagedIntegrity(baselineVersion string) ([]IntegrityCheck, error) {
+	manifest, err := loadTemplateManifestByVersion(baselineVersion)
+	if errors.Is(err, os.ErrNotExist) {
+		return []IntegrityCheck{{
+			Kind:   IntegrityKindManaged,
+			Name:   baselineVersion,
+			Status: IntegrityStatusSkipped,
+			Detail: i18n.Sprintf(messages.InstallBaselineManifestMissingFmt, baselineVersion),
+		}}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	checks := make([]IntegrityCheck, 0, len(manifest.Files))
+	for _, entry := range manifest.Files {
+		check := IntegrityCheck{Kind: IntegrityKindManaged, Name: entry.Path, Status: IntegrityStatusOK}
+		absPath := filepath.Join(inst.root, filepath.FromSlash(entry.Path))
+		content, err := inst.sys.ReadFile(absPath)
+		switch {
+		case errors.Is(err, os.ErrNotExist):
+			check.Status, check.Detail = IntegrityStatusMissing, messages.InstallIntegrityManagedMissing
+		case err != nil:
+			check.Status, check.Detail = IntegrityStatusFail, i18n.Errorf(messages.InstallFailedReadFmt, absPath, err).Error()
+		default:
+			expected, err := comparableFromManifestEntry(entry)
+			if err != nil {
+				return nil, err
+			}
+			local, err := buildOwnershipComparable(entry.Path, content)
+			if err != nil {
+				check.Status, check.Detail = IntegrityStatusModified, err.Error()
+			} else if local.PolicyID != expected.PolicyID || comparableKey(local) != comparableKey(expected) {
+				check.Status, check.Detail = IntegrityStatusModified, i18n.Sprintf(messages.InstallIntegrityManagedModifiedFmt, baselineVersion)
+			}
+		}
+		checks = append(checks, check)
+	}
+	sort.Slice(checks, func(i, j int) bool { return checks[i].Name < checks[j].Name })
+	return checks, nil
+}
+
+// checkSnapshotIntegrity parses every upgrade snapshot manifest. Unlike
+// ListUpgradeSnapshots, malformed snapshots are reported instead of skipped,
+// since rollback cannot use them.
+func (inst *installer) checkSnapshotIntegrity() ([]IntegrityCheck, error) {
+	dir := inst.upgradeSnapshotDirPath()
+	if _, err := inst.sys.Stat(dir); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, i18n.Errorf(messages.InstallFailedStatFmt, dir, err)
+	}
+	var checks []IntegrityCheck
+	err := inst.sys.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			if entry.Name() == upgradeSnapshotBlobDirName && filepath.Dir(path) == dir {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.HasSuffix(path, ".json") {
+			return nil
+		}
+		rel, relErr := filepath.Rel(inst.root, path)
+		if relErr != nil {
+			rel = path
+		}
+		check := IntegrityCheck{Kind: IntegrityKindSnapshot, Name: filepath.ToSlash(rel), Status: IntegrityStatusOK}
+		if _, readErr := readUpgradeSnapshotManifest(path, inst.sys); readErr != nil {
+			check.Status, check.Detail = IntegrityStatusFail, readErr.Error()
+		}
+		checks = append(checks, check)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return checks, nil
+}
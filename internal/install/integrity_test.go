@@ -0,0 +1,68 @@
+package install
+
+import (
+	"testing"
+	"time"
+)
+
+func integrityChecksByName(checks []IntegrityCheck) map[string]IntegrityCheck {
+	byName := make(map[string]IntegrityCheck, len(checks))
+	for _, check := range checks {
+		byName[check.Kind+" "+check.Name] = check
+	}
+	return byName
+}
+
+func TestCheckIntegrity(t *testing.T) {
+	root := t.TempDir()
+	manifest, err := loadTemplateManifestByVersion("0.14.0")
+	if err != nil {
+		t.Fatalf("load manifest: %v", err)
+	}
+	if err := writeManagedBaselineState(root, RealSystem{}, makeManagedBaselineState(manifest, BaselineStateSourceWrittenByInit, time.Now(), nil)); err != nil {
+		t.Fatalf("write baseline: %v", err)
+	}
+	writeRebuildFile(t, root, ".agent-layer/al.version", "0.13.0\n")
+	writeRebuildFile(t, root, ".agent-layer/instructions/00_rules.md", "my own rules\n")
+	writeRebuildFile(t, root, upgradeSnapshotDirRelPath+"/broken.json", "{not json")
+
+	checks, err := CheckIntegrity(root, RealSystem{})
+	if err != nil {
+		t.Fatalf("CheckIntegrity: %v", err)
+	}
+	byName := integrityChecksByName(checks)
+	if pin := byName["pin 0.13.0"]; pin.Status != IntegrityStatusFail || pin.Detail != "managed baseline records 0.14.0; run `al upgrade` or `al baseline rebuild`" {
+		t.Fatalf("unexpected pin check: %#v", pin)
+	}
+	if edited := byName["managed .agent-layer/instructions/00_rules.md"]; edited.Status != IntegrityStatusModified {
+		t.Fatalf("expected edited managed file to be modified: %#v", edited)
+	}
+	if absent := byName["managed .agent-layer/commands.allow"]; absent.Status != IntegrityStatusMissing {
+		t.Fatalf("expected absent managed file to be missing: %#v", absent)
+	}
+	if snapshot := byName["snapshot "+upgradeSnapshotDirRelPath+"/broken.json"]; snapshot.Status != IntegrityStatusFail {
+		t.Fatalf("expected malformed snapshot to fail: %#v", snapshot)
+	}
+	if len(checks) != len(manifest.Files)+2 {
+		t.Fatalf("expected pin, %d managed, and one snapshot check, got %d", len(manifest.Files), len(checks))
+	}
+}
+
+func TestCheckIntegrity_MissingBaseline(t *testing.T) {
+	root := t.TempDir()
+	writeRebuildFile(t, root, ".agent-layer/al.version", "0.14.0\n")
+
+	checks, err := CheckIntegrity(root, RealSystem{})
+	if err != nil {
+		t.Fatalf("CheckIntegrity: %v", err)
+	}
+	if len(checks) != 1 || checks[0].Status != IntegrityStatusFail || !containsAll(checks[0].Detail, "al baseline rebuild") {
+		t.Fatalf("expected a single failed pin check, got %#v", checks)
+	}
+	if _, err := CheckIntegrity("", RealSystem{}); err == nil {
+		t.Fatal("expected root error")
+	}
+	if _, err := CheckIntegrity(root, nil); err == nil {
+		t.Fatal("expected system error")
+	}
+}
@@ -0,0 +1,119 @@
+package install
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/conn-castle/agent-layer/internal/layerdir"
+)
+
+// redirectSystem serves paths under root/.agent-layer from the directory an
+// .agent-layer redirect file names. The installer keeps working with
+// repo-relative .agent-layer paths, so snapshots, ownership state, and unknown
+// scans record the same paths whether or not the directory is redirected.
+type redirectSystem struct {
+	System
+	virtual string
+	real    string
+}
+
+// layerSystem wraps sys in a redirectSystem when root's .agent-layer is a
+// redirect file, and returns sys unchanged otherwise.
+func layerSystem(root string, sys System) (System, error) {
+	real, redirected, err := layerdir.Resolve(root)
+	if err != nil {
+		return nil, err
+	}
+	if !redirected {
+		return sys, nil
+	}
+	return redirectSystem{System: sys, virtual: filepath.Join(root, layerdir.Name), real: real}, nil
+}
+
+func (s redirectSystem) toReal(path string) string {
+	return swapPrefix(path, s.virtual, s.real)
+}
+
+func (s redirectSystem) toVirtual(path string) string {
+	return swapPrefix(path, s.real, s.virtual)
+}
+
+func swapPrefix(path string, from string, to string) string {
+	clean := filepath.Clean(path)
+	if clean == from {
+		return to
+	}
+	if rest, ok := strings.CutPrefix(clean, from+string(os.PathSeparator)); ok {
+		return filepath.Join(to, rest)
+	}
+	return path
+}
+
+// Chmod changes the mode of the named file or directory.
+func (s redirectSystem) Chmod(name string, mode os.FileMode) error {
+	return s.System.Chmod(s.toReal(name), mode)
+}
+
+// EvalSymlinks returns the path after evaluating symbolic links.
+func (s redirectSystem) EvalSymlinks(path string) (string, error) {
+	resolved, err := s.System.EvalSymlinks(s.toReal(path))
+	if err != nil {
+		return "", err
+	}
+	return s.toVirtual(resolved), nil
+}
+
+// Lstat returns file info without following symlinks.
+func (s redirectSystem) Lstat(name string) (os.FileInfo, error) {
+	return s.System.Lstat(s.toReal(name))
+}
+
+// Stat returns file info for the named path.
+func (s redirectSystem) Stat(name string) (os.FileInfo, error) {
+	return s.System.Stat(s.toReal(name))
+}
+
+// ReadFile reads the named file.
+func (s redirectSystem) ReadFile(name string) ([]byte, error) {
+	return s.System.ReadFile(s.toReal(name))
+}
+
+// Readlink returns the destination of the named symbolic link.
+func (s redirectSystem) Readlink(name string) (string, error) {
+	return s.System.Readlink(s.toReal(name))
+}
+
+// MkdirAll creates a directory path and all parents if needed.
+func (s redirectSystem) MkdirAll(path string, perm os.FileMode) error {
+	return s.System.MkdirAll(s.toReal(path), perm)
+}
+
+// RemoveAll removes a path and any children.
+func (s redirectSystem) RemoveAll(path string) error {
+	return s.System.RemoveAll(s.toReal(path))
+}
+
+// Rename renames (moves) oldpath to newpath.
+func (s redirectSystem) Rename(oldpath string, newpath string) error {
+	return s.System.Rename(s.toReal(oldpath), s.toReal(newpath))
+}
+
+// Symlink creates newname as a symbolic link to oldname.
+func (s redirectSystem) Symlink(oldname string, newname string) error {
+	return s.System.Symlink(oldname, s.toReal(newname))
+}
+
+// WalkDir walks the file tree rooted at root, reporting paths under the
+// redirected directory by their .agent-layer path.
+func (s redirectSystem) WalkDir(root string, fn fs.WalkDirFunc) error {
+	return s.System.WalkDir(s.toReal(root), func(path string, entry fs.DirEntry, err error) error {
+		return fn(s.toVirtual(path), entry, err)
+	})
+}
+
+// WriteFileAtomic writes data to a file atomically.
+func (s redirectSystem) WriteFileAtomic(filename string, data []byte, perm os.FileMode) error {
+	return s.System.WriteFileAtomic(s.toReal(filename), data, perm)
+}
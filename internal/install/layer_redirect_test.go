@@ -0,0 +1,51 @@
+package install
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRun_FollowsAgentLayerRedirectFile(t *testing.T) {
+	root := t.TempDir()
+	private := t.TempDir()
+	redirect := filepath.Join(root, ".agent-layer")
+	if err := os.WriteFile(redirect, []byte(private+"\n"), 0o600); err != nil {
+		t.Fatalf("write redirect: %v", err)
+	}
+
+	if err := Run(root, Options{System: RealSystem{}, PinVersion: "1.0.0"}); err != nil {
+		t.Fatalf("Run error: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(private, "config.toml")); err != nil {
+		t.Fatalf("expected config.toml in the redirected directory: %v", err)
+	}
+	if info, err := os.Lstat(redirect); err != nil || !info.Mode().IsRegular() {
+		t.Fatalf("redirect file must stay a file: %v, %v", info, err)
+	}
+
+	unknownPath := filepath.Join(private, "custom.txt")
+	if err := os.WriteFile(unknownPath, []byte("custom"), 0o600); err != nil {
+		t.Fatalf("write unknown: %v", err)
+	}
+	sys, err := layerSystem(root, RealSystem{})
+	if err != nil {
+		t.Fatalf("layerSystem: %v", err)
+	}
+	inst := &installer{root: root, sys: sys}
+	if err := inst.scanUnknowns(); err != nil {
+		t.Fatalf("scanUnknowns: %v", err)
+	}
+	rel := inst.relativeUnknowns()
+	if len(rel) != 1 || rel[0] != filepath.Join(".agent-layer", "custom.txt") {
+		t.Fatalf("expected the redirected unknown by its .agent-layer path, got %v", rel)
+	}
+
+	if err := Run(root, Options{Overwrite: true, Prompter: autoApprovePrompter(), System: RealSystem{}}); err != nil {
+		t.Fatalf("overwrite Run error: %v", err)
+	}
+	if _, err := os.Stat(unknownPath); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("expected unknown file to be deleted, got %v", err)
+	}
+}
@@ -0,0 +1,383 @@
+//go:build tools
+// +build tools
+
+package install
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"path"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/pelletier/go-toml/v2"
+
+	"github.com/conn-castle/agent-layer/internal/config"
+	"github.com/conn-castle/agent-layer/internal/version"
+)
+
+// scaffoldRationalePrefix marks generated rationales the author must rewrite.
+const scaffoldRationalePrefix = "TODO: "
+
+// skillsDirPrefix is the template manifest prefix of managed skill directories.
+const skillsDirPrefix = ".agent-layer/skills/"
+
+// orderedPrefixPattern matches the NN_ ordering prefix of instruction files.
+var orderedPrefixPattern = regexp.MustCompile(`^[0-9]+_`)
+
+// MigrationScaffoldOptions configures ScaffoldUpgradeMigration.
+type MigrationScaffoldOptions struct {
+	// FromVersion is the previous release whose template manifest is the baseline.
+	FromVersion string
+	// ToVersion is the release the migration manifest targets. Its template
+	// manifest must already be generated.
+	ToVersion string
+	// MinPriorVersion is written as min_prior_version; it defaults to FromVersion.
+	MinPriorVersion string
+	// FromConfig and ToConfig are the config.toml templates of the two
+	// releases. New defaults are scaffolded only when both are set.
+	FromConfig []byte
+	ToConfig   []byte
+}
+
+// scaffoldOperation mirrors upgradeMigrationOperation in the field order the
+// committed manifests use, so scaffolds diff cleanly against hand edits.
+type scaffoldOperation struct {
+	ID             string          `json:"id"`
+	Kind           string          `json:"kind"`
+	From           string          `json:"from,omitempty"`
+	To             string          `json:"to,omitempty"`
+	Path           string          `json:"path,omitempty"`
+	Key            string          `json:"key,omitempty"`
+	Value          json.RawMessage `json:"value,omitempty"`
+	Rationale      string          `json:"rationale"`
+	SourceAgnostic bool            `json:"source_agnostic"`
+}
+
+type scaffoldManifest struct {
+	SchemaVersion   int                 `json:"schema_version"`
+	TargetVersion   string              `json:"target_version"`
+	MinPriorVersion string              `json:"min_prior_version"`
+	Operations      []scaffoldOperation `json:"operations"`
+}
+
+// ScaffoldUpgradeMigration diffs the embedded template manifests of two
+// releases and returns a migration manifest for ToVersion with rename_file,
+// delete_file, and config_set_default operations. Every rationale is a TODO
+// placeholder. The result is checked with validateUpgradeMigrationManifest
+// before it is returned.
+func ScaffoldUpgradeMigration(opts MigrationScaffoldOptions) ([]byte, error) {
+	fromManifest, err := loadTemplateManifestByVersion(opts.FromVersion)
+	if err != nil {
+		return nil, fmt.Errorf("load template manifest for %s: %w", opts.FromVersion, err)
+	}
+	toManifest, err := loadTemplateManifestByVersion(opts.ToVersion)
+	if err != nil {
+		return nil, fmt.Errorf("load template manifest for %s: %w", opts.ToVersion, err)
+	}
+	target, err := version.Normalize(opts.ToVersion)
+	if err != nil {
+		return nil, fmt.Errorf("invalid to version %q: %w", opts.ToVersion, err)
+	}
+	minPrior := opts.MinPriorVersion
+	if strings.TrimSpace(minPrior) == "" {
+		minPrior = opts.FromVersion
+	}
+	if minPrior, err = version.Normalize(minPrior); err != nil {
+		return nil, fmt.Errorf("invalid min prior version %q: %w", opts.MinPriorVersion, err)
+	}
+
+	ops := scaffoldFileOperations(fromManifest, toManifest)
+	if opts.FromConfig != nil && opts.ToConfig != nil {
+		defaults, err := scaffoldConfigDefaults(opts.FromConfig, opts.ToConfig, target)
+		if err != nil {
+			return nil, err
+		}
+		ops = append(ops, defaults...)
+	}
+	assignScaffoldIDs(ops)
+
+	return encodeScaffoldManifest(scaffoldManifest{
+		SchemaVersion:   upgradeMigrationManifestSchemaVersion,
+		TargetVersion:   target,
+		MinPriorVersion: minPrior,
+		Operations:      ops,
+	})
+}
+
+// scaffoldFileOperations pairs files removed since from with files added in
+// to. A removed file becomes a rename when an added file has the same
+// normalized content, or the same name in the same directory once an NN_
+// ordering prefix is ignored. Skill directories removed wholesale collapse to
+// one delete; the rest are per-file deletes.
+func scaffoldFileOperations(from templateManifest, to templateManifest) []scaffoldOperation {
+	fromPaths := manifestPathSet(from)
+	toPaths := manifestPathSet(to)
+	var removed []manifestFileEntry
+	added := make(map[string]manifestFileEntry)
+	for _, entry := range from.Files {
+		if _, ok := toPaths[entry.Path]; !ok {
+			removed = append(removed, entry)
+		}
+	}
+	for _, entry := range to.Files {
+		if _, ok := fromPaths[entry.Path]; !ok {
+			added[entry.Path] = entry
+		}
+	}
+
+	var renames, deletes []scaffoldOperation
+	var unmatched []string
+	for _, entry := range removed {
+		successor := renameSuccessor(entry, added)
+		if successor == "" {
+			unmatched = append(unmatched, entry.Path)
+			continue
+		}
+		delete(added, successor)
+		renames = append(renames, scaffoldOperation{
+			Kind:           string(upgradeMigrationKindRenameFile),
+			From:           entry.Path,
+			To:             successor,
+			Rationale:      scaffoldRationalePrefix + fmt.Sprintf("explain why %s moved to %s.", entry.Path, successor),
+			SourceAgnostic: true,
+		})
+	}
+
+	newSkills := newSkillDirs(slices.Sorted(maps.Keys(added)), fromPaths)
+	for _, dir := range removedSkillDirs(unmatched, toPaths) {
+		rationale := fmt.Sprintf("explain why the %s skill was removed.", path.Base(dir))
+		if len(newSkills) > 0 {
+			rationale += fmt.Sprintf(" If it was renamed to one of %s, change this to a rename_file with to set to the new directory.", strings.Join(newSkills, ", "))
+		}
+		deletes = append(deletes, scaffoldOperation{
+			Kind:           string(upgradeMigrationKindDeleteFile),
+			Path:           dir,
+			Rationale:      scaffoldRationalePrefix + rationale,
+			SourceAgnostic: true,
+		})
+	}
+	for _, removedPath := range unmatched {
+		if dir := skillDir(removedPath); dir != "" && !dirHasPaths(dir, toPaths) {
+			continue
+		}
+		deletes = append(deletes, scaffoldOperation{
+			Kind:           string(upgradeMigrationKindDeleteFile),
+			Path:           removedPath,
+			Rationale:      scaffoldRationalePrefix + fmt.Sprintf("explain why %s was removed.", removedPath),
+			SourceAgnostic: true,
+		})
+	}
+	return append(renames, deletes...)
+}
+
+// renameSuccessor returns the added path that entry was most likely renamed
+// to, or "" when there is no confident match. Content matches win over name
+// matches; ties resolve to the lexically first path.
+func renameSuccessor(entry manifestFileEntry, added map[string]manifestFileEntry) string {
+	for _, candidate := range slices.Sorted(maps.Keys(added)) {
+		if added[candidate].FullHashNormalized == entry.FullHashNormalized {
+			return candidate
+		}
+	}
+	name := orderedPrefixPattern.ReplaceAllString(path.Base(entry.Path), "")
+	for _, candidate := range slices.Sorted(maps.Keys(added)) {
+		if path.Dir(candidate) == path.Dir(entry.Path) && orderedPrefixPattern.ReplaceAllString(path.Base(candidate), "") == name {
+			return candidate
+		}
+	}
+	return ""
+}
+
+// removedSkillDirs returns the sorted skill directories of paths that have
+// no files left in present.
+func removedSkillDirs(paths []string, present map[string]struct{}) []string {
+	return skillDirsWhere(paths, func(dir string) bool { return !dirHasPaths(dir, present) })
+}
+
+// newSkillDirs returns the sorted skill directories of paths that had no
+// files in previous.
+func newSkillDirs(paths []string, previous map[string]struct{}) []string {
+	return skillDirsWhere(paths, func(dir string) bool { return !dirHasPaths(dir, previous) })
+}
+
+func skillDirsWhere(paths []string, keep func(dir string) bool) []string {
+	seen := make(map[string]struct{})
+	var dirs []string
+	for _, p := range paths {
+		dir := skillDir(p)
+		if dir == "" {
+			continue
+		}
+		if _, ok := seen[dir]; ok {
+			continue
+		}
+		seen[dir] = struct{}{}
+		if keep(dir) {
+			dirs = append(dirs, dir)
+		}
+	}
+	sort.Strings(dirs)
+	return dirs
+}
+
+// skillDir returns the .agent-layer/skills/<name> directory containing p, or
+// "" when p is not inside a skill directory.
+func skillDir(p string) string {
+	rest, ok := strings.CutPrefix(p, skillsDirPrefix)
+	if !ok {
+		return ""
+	}
+	name, _, ok := strings.Cut(rest, "/")
+	if !ok {
+		return ""
+	}
+	return skillsDirPrefix + name
+}
+
+// dirHasPaths reports whether any path in paths lives under dir.
+func dirHasPaths(dir string, paths map[string]struct{}) bool {
+	for p := range paths {
+		if strings.HasPrefix(p, dir+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+func manifestPathSet(manifest templateManifest) map[string]struct{} {
+	paths := make(map[string]struct{}, len(manifest.Files))
+	for _, entry := range manifest.Files {
+		paths[entry.Path] = struct{}{}
+	}
+	return paths
+}
+
+// scaffoldConfigDefaults returns a config_set_default for every scalar key
+// the new config template sets that the old one did not. Keys inside arrays
+// of tables cannot be addressed by a migration and are skipped, as are keys
+// that a config deprecation for target renames into place.
+func scaffoldConfigDefaults(fromConfig []byte, toConfig []byte, target string) ([]scaffoldOperation, error) {
+	var fromRaw, toRaw map[string]any
+	if err := toml.Unmarshal(fromConfig, &fromRaw); err != nil {
+		return nil, fmt.Errorf("parse previous config template: %w", err)
+	}
+	if err := toml.Unmarshal(toConfig, &toRaw); err != nil {
+		return nil, fmt.Errorf("parse new config template: %w", err)
+	}
+	renamed := make(map[string]struct{})
+	for _, deprecation := range config.Deprecations() {
+		if deprecation.Since == target && deprecation.Replacement != "" {
+			renamed[deprecation.Replacement] = struct{}{}
+		}
+	}
+
+	from := make(map[string]any)
+	flattenConfigLeaves(fromRaw, "", from)
+	to := make(map[string]any)
+	flattenConfigLeaves(toRaw, "", to)
+	var ops []scaffoldOperation
+	for _, key := range slices.Sorted(maps.Keys(to)) {
+		if _, ok := from[key]; ok {
+			continue
+		}
+		if _, ok := renamed[key]; ok {
+			continue
+		}
+		value, err := json.Marshal(to[key])
+		if err != nil {
+			return nil, fmt.Errorf("encode default for %s: %w", key, err)
+		}
+		ops = append(ops, scaffoldOperation{
+			Kind:           string(upgradeMigrationKindConfigSetDefault),
+			Key:            key,
+			Value:          value,
+			Rationale:      scaffoldRationalePrefix + fmt.Sprintf("describe %s, its default, and what each choice does.", key),
+			SourceAgnostic: true,
+		})
+	}
+	return ops, nil
+}
+
+// flattenConfigLeaves records the dotted path of every non-table value in
+// table, skipping arrays of tables.
+func flattenConfigLeaves(table map[string]any, prefix string, out map[string]any) {
+	for key, value := range table {
+		keyPath := key
+		if prefix != "" {
+			keyPath = prefix + "." + key
+		}
+		switch typed := value.(type) {
+		case map[string]any:
+			flattenConfigLeaves(typed, keyPath, out)
+		case []any:
+			if len(typed) > 0 {
+				if _, isTable := typed[0].(map[string]any); isTable {
+					continue
+				}
+			}
+			out[keyPath] = value
+		default:
+			out[keyPath] = value
+		}
+	}
+}
+
+// assignScaffoldIDs gives each operation a letter-prefixed id in slice order,
+// matching the a-, b-, ... convention the upgrader sorts by.
+func assignScaffoldIDs(ops []scaffoldOperation) {
+	for i := range ops {
+		op := &ops[i]
+		var verb, subject string
+		switch op.Kind {
+		case string(upgradeMigrationKindRenameFile):
+			verb, subject = "rename", op.From
+		case string(upgradeMigrationKindDeleteFile):
+			verb, subject = "delete", op.Path
+		default:
+			verb, subject = "set-default", op.Key
+		}
+		op.ID = fmt.Sprintf("%s-%s-%s", scaffoldIDPrefix(i), verb, scaffoldSlug(subject))
+	}
+}
+
+// scaffoldIDPrefix renders index as a, b, ..., y, za, zb, ... so ids sort
+// in slice order however many operations there are.
+func scaffoldIDPrefix(index int) string {
+	return strings.Repeat("z", index/25) + string(rune('a'+index%25))
+}
+
+// scaffoldSlug turns a path or dotted key into an id fragment.
+func scaffoldSlug(subject string) string {
+	subject = strings.TrimPrefix(subject, ".agent-layer/")
+	subject = strings.TrimSuffix(subject, path.Ext(subject))
+	replacer := strings.NewReplacer("/", "-", ".", "-", "_", "-")
+	return strings.ToLower(replacer.Replace(subject))
+}
+
+// encodeScaffoldManifest renders manifest like the committed migration files
+// and validates the bytes exactly as the upgrader will load them.
+func encodeScaffoldManifest(manifest scaffoldManifest) ([]byte, error) {
+	if manifest.Operations == nil {
+		manifest.Operations = []scaffoldOperation{}
+	}
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(manifest); err != nil {
+		return nil, err
+	}
+	var decoded upgradeMigrationManifest
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		return nil, fmt.Errorf("decode scaffolded migration manifest: %w", err)
+	}
+	if err := validateUpgradeMigrationManifest(decoded); err != nil {
+		return nil, fmt.Errorf("scaffolded migration manifest is invalid: %w", err)
+	}
+	return buf.Bytes(), nil
+}
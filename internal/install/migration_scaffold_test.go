@@ -0,0 +1,159 @@
+//go:build tools
+// +build tools
+
+package install
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestScaffoldFileOperations_RenamesAndDeletes(t *testing.T) {
+	from := templateManifest{Files: []manifestFileEntry{
+		{Path: ".agent-layer/instructions/02_rules.md", FullHashNormalized: "rules-old"},
+		{Path: ".agent-layer/templates/docs/NOTES.md", FullHashNormalized: "notes"},
+		{Path: ".agent-layer/skills/old-skill/SKILL.md", FullHashNormalized: "old-skill"},
+		{Path: ".agent-layer/skills/old-skill/references/guide.md", FullHashNormalized: "old-guide"},
+		{Path: ".agent-layer/skills/kept/SKILL.md", FullHashNormalized: "kept"},
+		{Path: ".agent-layer/skills/kept/reviewer-prompt.md", FullHashNormalized: "kept-prompt"},
+		{Path: ".agent-layer/commands.allow", FullHashNormalized: "allow"},
+	}}
+	to := templateManifest{Files: []manifestFileEntry{
+		{Path: ".agent-layer/instructions/00_rules.md", FullHashNormalized: "rules-new"},
+		{Path: ".agent-layer/templates/docs/JOURNAL.md", FullHashNormalized: "notes"},
+		{Path: ".agent-layer/skills/new-skill/SKILL.md", FullHashNormalized: "new-skill"},
+		{Path: ".agent-layer/skills/kept/SKILL.md", FullHashNormalized: "kept"},
+		{Path: ".agent-layer/commands.allow", FullHashNormalized: "allow"},
+	}}
+
+	ops := scaffoldFileOperations(from, to)
+
+	if len(ops) != 4 {
+		t.Fatalf("expected 4 operations, got %d: %#v", len(ops), ops)
+	}
+	if ops[0].Kind != "rename_file" || ops[0].From != ".agent-layer/instructions/02_rules.md" || ops[0].To != ".agent-layer/instructions/00_rules.md" {
+		t.Fatalf("expected prefix rename first, got %#v", ops[0])
+	}
+	if ops[1].Kind != "rename_file" || ops[1].From != ".agent-layer/templates/docs/NOTES.md" || ops[1].To != ".agent-layer/templates/docs/JOURNAL.md" {
+		t.Fatalf("expected content rename second, got %#v", ops[1])
+	}
+	if ops[2].Kind != "delete_file" || ops[2].Path != ".agent-layer/skills/old-skill" {
+		t.Fatalf("expected whole skill directory delete, got %#v", ops[2])
+	}
+	if !strings.Contains(ops[2].Rationale, ".agent-layer/skills/new-skill") {
+		t.Fatalf("expected skill delete to suggest the new skill, got %q", ops[2].Rationale)
+	}
+	if ops[3].Kind != "delete_file" || ops[3].Path != ".agent-layer/skills/kept/reviewer-prompt.md" {
+		t.Fatalf("expected per-file delete inside a kept skill, got %#v", ops[3])
+	}
+	for _, op := range ops {
+		if !strings.HasPrefix(op.Rationale, scaffoldRationalePrefix) || !op.SourceAgnostic {
+			t.Fatalf("expected TODO rationale and source_agnostic, got %#v", op)
+		}
+	}
+}
+
+func TestScaffoldConfigDefaults(t *testing.T) {
+	fromConfig := []byte(`
+[approvals]
+mode = "all"
+
+[agents.claude]
+enabled = true
+`)
+	toConfig := []byte(`
+[approvals]
+mode = "all"
+
+[agents.claude]
+enabled = true
+statusline = false
+
+[agents.antigravity]
+model = "flash"
+
+[[mcp.servers]]
+id = "example"
+enabled = false
+`)
+
+	ops, err := scaffoldConfigDefaults(fromConfig, toConfig, "0.12.0")
+	if err != nil {
+		t.Fatalf("scaffoldConfigDefaults: %v", err)
+	}
+	if len(ops) != 1 {
+		t.Fatalf("expected one default (rename target and array tables skipped), got %#v", ops)
+	}
+	if ops[0].Kind != "config_set_default" || ops[0].Key != "agents.claude.statusline" || string(ops[0].Value) != "false" {
+		t.Fatalf("unexpected default operation: %#v", ops[0])
+	}
+
+	if _, err := scaffoldConfigDefaults([]byte("not = [toml"), toConfig, "0.12.0"); err == nil {
+		t.Fatalf("expected parse error for invalid previous config template")
+	}
+}
+
+func TestScaffoldIDPrefix_SortsInOrder(t *testing.T) {
+	var prefixes []string
+	for i := 0; i < 60; i++ {
+		prefixes = append(prefixes, scaffoldIDPrefix(i)+"-")
+	}
+	if !sort.StringsAreSorted(prefixes) {
+		t.Fatalf("expected prefixes to sort in index order: %v", prefixes)
+	}
+	if prefixes[0] != "a-" || prefixes[25] != "za-" {
+		t.Fatalf("unexpected prefixes: %q, %q", prefixes[0], prefixes[25])
+	}
+}
+
+func TestEncodeScaffoldManifest_RejectsInvalidOperations(t *testing.T) {
+	_, err := encodeScaffoldManifest(scaffoldManifest{
+		SchemaVersion:   upgradeMigrationManifestSchemaVersion,
+		TargetVersion:   "0.13.0",
+		MinPriorVersion: "0.12.0",
+		Operations: []scaffoldOperation{{
+			ID: "a-rename-same", Kind: "rename_file", From: "a", To: "a", Rationale: "TODO: x",
+		}},
+	})
+	if err == nil || !strings.Contains(err.Error(), "distinct from/to") {
+		t.Fatalf("expected validation error, got %v", err)
+	}
+}
+
+func TestScaffoldUpgradeMigration_EmbeddedManifests(t *testing.T) {
+	data, err := ScaffoldUpgradeMigration(MigrationScaffoldOptions{FromVersion: "v0.12.3", ToVersion: "0.13.0"})
+	if err != nil {
+		t.Fatalf("ScaffoldUpgradeMigration: %v", err)
+	}
+	var manifest upgradeMigrationManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		t.Fatalf("decode scaffold: %v", err)
+	}
+	if manifest.TargetVersion != "0.13.0" || manifest.MinPriorVersion != "0.12.3" {
+		t.Fatalf("unexpected versions: %#v", manifest)
+	}
+	var deletesDebugIssue bool
+	for i, op := range manifest.Operations {
+		if i > 0 && manifest.Operations[i-1].ID >= op.ID {
+			t.Fatalf("expected ids in ascending order, got %q before %q", manifest.Operations[i-1].ID, op.ID)
+		}
+		if op.Kind == upgradeMigrationKindDeleteFile && op.Path == ".agent-layer/skills/debug-issue" {
+			deletesDebugIssue = strings.Contains(op.Rationale, ".agent-layer/skills/debug-and-fix-issue")
+		}
+	}
+	if !deletesDebugIssue {
+		t.Fatalf("expected a debug-issue delete suggesting debug-and-fix-issue:\n%s", data)
+	}
+	if !strings.HasSuffix(string(data), "}\n") {
+		t.Fatalf("expected trailing newline")
+	}
+
+	if _, err := ScaffoldUpgradeMigration(MigrationScaffoldOptions{FromVersion: "0.12.3", ToVersion: "9.9.9"}); err == nil {
+		t.Fatalf("expected error for a version without a template manifest")
+	}
+	if _, err := ScaffoldUpgradeMigration(MigrationScaffoldOptions{FromVersion: "0.12.3", ToVersion: "0.13.0", MinPriorVersion: "bogus"}); err == nil {
+		t.Fatalf("expected error for an invalid min prior version")
+	}
+}
@@ -13,6 +13,8 @@ import (
 	"sync"
 	"time"
 
+	"github.com/conn-castle/agent-layer/internal/clock"
+	"github.com/conn-castle/agent-layer/internal/i18n"
 	"github.com/conn-castle/agent-layer/internal/messages"
 	"github.com/conn-castle/agent-layer/internal/templates"
 	"github.com/conn-castle/agent-layer/internal/version"
@@ -77,7 +79,7 @@ var (
 func loadTemplateManifestByVersion(versionRaw string) (templateManifest, error) {
 	normalized, err := version.Normalize(versionRaw)
 	if err != nil {
-		return templateManifest{}, fmt.Errorf(messages.InstallInvalidPinVersionFmt, err)
+		return templateManifest{}, i18n.Errorf(messages.InstallInvalidPinVersionFmt, err)
 	}
 	manifestPath := path.Join(templateManifestDir, normalized+".json")
 	data, err := templates.Read(manifestPath)
@@ -164,7 +166,7 @@ func validateTemplateManifest(manifest templateManifest) error {
 	if strings.TrimSpace(manifest.GeneratedAt) == "" {
 		return fmt.Errorf("generated_at_utc is required")
 	}
-	if _, err := time.Parse(time.RFC3339, manifest.GeneratedAt); err != nil {
+	if _, err := clock.Parse(manifest.GeneratedAt); err != nil {
 		return fmt.Errorf("invalid generated_at_utc %q: %w", manifest.GeneratedAt, err)
 	}
 	if len(manifest.Files) == 0 {
@@ -211,7 +213,7 @@ func manifestFileMap(entries []manifestFileEntry) map[string]manifestFileEntry {
 
 func readManagedBaselineState(root string, sys System) (managedBaselineState, error) {
 	if sys == nil {
-		return managedBaselineState{}, fmt.Errorf(messages.InstallSystemRequired)
+		return managedBaselineState{}, i18n.Errorf(messages.InstallSystemRequired)
 	}
 	path := filepath.Join(root, filepath.FromSlash(baselineStateRelPath))
 	data, err := sys.ReadFile(path)
@@ -228,6 +230,23 @@ func readManagedBaselineState(root string, sys System) (managedBaselineState, er
 	return state, nil
 }
 
+// ReadBaselineVersion returns the template version recorded in
+// .agent-layer/state/managed-baseline.json, or "" when no baseline exists.
+func ReadBaselineVersion(root string, sys System) (string, error) {
+	sys, err := layerSystem(root, sys)
+	if err != nil {
+		return "", err
+	}
+	state, err := readManagedBaselineState(root, sys)
+	if errors.Is(err, os.ErrNotExist) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return state.BaselineVersion, nil
+}
+
 func validateManagedBaselineState(state managedBaselineState) error {
 	if state.SchemaVersion != baselineStateSchemaVersion {
 		return fmt.Errorf("unsupported schema_version %d", state.SchemaVersion)
@@ -238,10 +257,10 @@ func validateManagedBaselineState(state managedBaselineState) error {
 	if strings.TrimSpace(string(state.Source)) == "" {
 		return fmt.Errorf("source is required")
 	}
-	if _, err := time.Parse(time.RFC3339, state.CreatedAt); err != nil {
+	if _, err := clock.Parse(state.CreatedAt); err != nil {
 		return fmt.Errorf("invalid created_at_utc %q: %w", state.CreatedAt, err)
 	}
-	if _, err := time.Parse(time.RFC3339, state.UpdatedAt); err != nil {
+	if _, err := clock.Parse(state.UpdatedAt); err != nil {
 		return fmt.Errorf("invalid updated_at_utc %q: %w", state.UpdatedAt, err)
 	}
 	if len(state.Files) == 0 {
@@ -262,14 +281,14 @@ func validateManagedBaselineState(state managedBaselineState) error {
 
 func writeManagedBaselineState(root string, sys System, state managedBaselineState) error {
 	if sys == nil {
-		return fmt.Errorf(messages.InstallSystemRequired)
+		return i18n.Errorf(messages.InstallSystemRequired)
 	}
 	if err := validateManagedBaselineState(state); err != nil {
 		return err
 	}
 	path := filepath.Join(root, filepath.FromSlash(baselineStateRelPath))
 	if err := sys.MkdirAll(filepath.Dir(path), 0o755); err != nil {
-		return fmt.Errorf(messages.InstallFailedCreateDirForFmt, path, err)
+		return i18n.Errorf(messages.InstallFailedCreateDirForFmt, path, err)
 	}
 	data, err := json.MarshalIndent(state, "", "  ")
 	if err != nil {
@@ -277,7 +296,7 @@ func writeManagedBaselineState(root string, sys System, state managedBaselineSta
 	}
 	data = append(data, '\n')
 	if err := sys.WriteFileAtomic(path, data, 0o644); err != nil {
-		return fmt.Errorf(messages.InstallFailedWriteFmt, path, err)
+		return i18n.Errorf(messages.InstallFailedWriteFmt, path, err)
 	}
 	return nil
 }
@@ -295,7 +314,7 @@ func buildCurrentTemplateManifest(inst *installer, generatedAt time.Time) (templ
 	for _, entry := range entries {
 		templateBytes, err := templates.Read(entry.templatePath)
 		if err != nil {
-			return templateManifest{}, fmt.Errorf(messages.InstallFailedReadTemplateFmt, entry.templatePath, err)
+			return templateManifest{}, i18n.Errorf(messages.InstallFailedReadTemplateFmt, entry.templatePath, err)
 		}
 		comp, compErr := buildOwnershipComparable(entry.relPath, templateBytes)
 		if compErr != nil {
@@ -319,7 +338,7 @@ func buildCurrentTemplateManifest(inst *installer, generatedAt time.Time) (templ
 	return templateManifest{
 		SchemaVersion: templateManifestSchemaVersion,
 		Version:       baselineVersion,
-		GeneratedAt:   generatedAt.UTC().Format(time.RFC3339),
+		GeneratedAt:   clock.Format(generatedAt),
 		Files:         files,
 		Metadata: map[string]any{
 			"source": "embedded_templates",
@@ -337,7 +356,7 @@ func resolveBaselineVersion(inst *installer) (string, error) {
 	if trimmed := strings.TrimSpace(inst.pinVersion); trimmed != "" {
 		normalized, err := version.Normalize(trimmed)
 		if err != nil {
-			return "", fmt.Errorf(messages.InstallInvalidPinVersionFmt, err)
+			return "", i18n.Errorf(messages.InstallInvalidPinVersionFmt, err)
 		}
 		return normalized, nil
 	}
@@ -363,7 +382,7 @@ func makeManagedBaselineState(
 	now time.Time,
 	existing *managedBaselineState,
 ) managedBaselineState {
-	createdAt := now.UTC().Format(time.RFC3339)
+	createdAt := clock.Format(now)
 	if existing != nil && strings.TrimSpace(existing.CreatedAt) != "" {
 		createdAt = existing.CreatedAt
 	}
@@ -372,7 +391,7 @@ func makeManagedBaselineState(
 		BaselineVersion: manifest.Version,
 		Source:          source,
 		CreatedAt:       createdAt,
-		UpdatedAt:       now.UTC().Format(time.RFC3339),
+		UpdatedAt:       clock.Format(now),
 		Files:           baselineFileEntriesFromManifest(manifest),
 		Metadata: map[string]any{
 			"manifest_generated_at_utc": manifest.GeneratedAt,
@@ -382,7 +401,7 @@ func makeManagedBaselineState(
 
 func readCurrentPinVersion(root string, sys System) (string, error) {
 	if sys == nil {
-		return "", fmt.Errorf(messages.InstallSystemRequired)
+		return "", i18n.Errorf(messages.InstallSystemRequired)
 	}
 	path := filepath.Join(root, ".agent-layer", "al.version")
 	data, err := sys.ReadFile(path)
@@ -390,7 +409,7 @@ func readCurrentPinVersion(root string, sys System) (string, error) {
 		if errors.Is(err, os.ErrNotExist) {
 			return "", nil
 		}
-		return "", fmt.Errorf(messages.InstallFailedReadFmt, path, err)
+		return "", i18n.Errorf(messages.InstallFailedReadFmt, path, err)
 	}
 	trimmed := strings.TrimSpace(string(data))
 	if trimmed == "" {
@@ -398,7 +417,7 @@ func readCurrentPinVersion(root string, sys System) (string, error) {
 	}
 	normalized, normalizeErr := version.Normalize(trimmed)
 	if normalizeErr != nil {
-		return "", fmt.Errorf(messages.InstallInvalidPinVersionFmt, normalizeErr)
+		return "", i18n.Errorf(messages.InstallInvalidPinVersionFmt, normalizeErr)
 	}
 	return normalized, nil
 }
@@ -418,7 +437,7 @@ func (inst *installer) writeManagedBaselineIfConsistent(source BaselineStateSour
 	if len(managedDiffs) != 0 || len(memoryDiffs) != 0 {
 		return nil
 	}
-	now := time.Now().UTC()
+	now := inst.now()
 	manifest, err := buildCurrentTemplateManifest(inst, now)
 	if err != nil {
 		return err
@@ -82,6 +82,9 @@ func TestTemplateManifests_BackfillAndSizeBudget(t *testing.T) {
 func TestWriteReadManagedBaselineState_RoundTrip(t *testing.T) {
 	root := t.TempDir()
 	sys := RealSystem{}
+	if got, err := ReadBaselineVersion(root, sys); err != nil || got != "" {
+		t.Fatalf("ReadBaselineVersion without baseline = %q, %v; want empty", got, err)
+	}
 	state := managedBaselineState{
 		SchemaVersion:   baselineStateSchemaVersion,
 		BaselineVersion: "0.7.0",
@@ -112,6 +115,9 @@ func TestWriteReadManagedBaselineState_RoundTrip(t *testing.T) {
 	if len(readBack.Files) != 1 || readBack.Files[0].Path != commandsAllowRelPath {
 		t.Fatalf("unexpected files: %#v", readBack.Files)
 	}
+	if got, err := ReadBaselineVersion(root, sys); err != nil || got != "0.7.0" {
+		t.Fatalf("ReadBaselineVersion = %q, %v; want 0.7.0", got, err)
+	}
 }
 
 func TestRun_WritesManagedBaselineState(t *testing.T) {
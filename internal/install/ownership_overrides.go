@@ -0,0 +1,93 @@
+package install
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/conn-castle/agent-layer/internal/config"
+	"github.com/conn-castle/agent-layer/internal/layerdir"
+)
+
+// loadOwnershipOverrides reads the paths config.toml's [ownership] table marks
+// as user-owned. A repo without a config has none.
+func (inst *installer) loadOwnershipOverrides() error {
+	cfg, err := config.LoadConfigLenient(filepath.Join(layerdir.Dir(inst.root), configFileName))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+	inst.userOwnedPaths = cfg.UserOwnedPaths()
+	return nil
+}
+
+// isUserOwned reports whether the file at absPath belongs to the user, either
+// as a built-in user-owned instruction file or through an [ownership]
+// override. User-owned files are seeded when missing and otherwise left alone.
+func (inst *installer) isUserOwned(absPath string) bool {
+	if IsUserOwnedInstructionFile(absPath) {
+		return true
+	}
+	return inst.isOwnershipOverride(inst.relativePath(absPath))
+}
+
+// isOwnershipOverride reports whether [ownership] marks relPath as user-owned.
+func (inst *installer) isOwnershipOverride(relPath string) bool {
+	return len(inst.userOwnedPaths) > 0 && upgradeFilterMatches(inst.userOwnedPaths, filepath.ToSlash(relPath))
+}
+
+// skipUserOwnedUnknown keeps [ownership] user-owned paths out of the unknown
+// scan so upgrades never offer to delete them. A directory that holds one is
+// walked instead of being reported whole.
+func (inst *installer) skipUserOwnedUnknown(path string, entry fs.DirEntry) (bool, error) {
+	if len(inst.userOwnedPaths) == 0 {
+		return false, nil
+	}
+	rel := filepath.ToSlash(inst.relativePath(path))
+	if inst.isOwnershipOverride(rel) {
+		if entry.IsDir() {
+			return true, filepath.SkipDir
+		}
+		return true, nil
+	}
+	if entry.IsDir() {
+		for _, owned := range inst.userOwnedPaths {
+			if strings.HasPrefix(owned, rel+"/") {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+// splitOwnershipOverrides separates changes to [ownership] user-owned paths
+// from the rest.
+func (inst *installer) splitOwnershipOverrides(changes []upgradeChangeWithTemplate) (kept []upgradeChangeWithTemplate, optedOut []upgradeChangeWithTemplate) {
+	if len(inst.userOwnedPaths) == 0 {
+		return changes, nil
+	}
+	kept = make([]upgradeChangeWithTemplate, 0, len(changes))
+	for _, change := range changes {
+		if inst.isOwnershipOverride(change.path) {
+			optedOut = append(optedOut, change)
+			continue
+		}
+		kept = append(kept, change)
+	}
+	return kept, optedOut
+}
+
+// userOwnedMigrationPath returns the first path op would change that
+// [ownership] gives to the user, or "" when it touches none.
+func (inst *installer) userOwnedMigrationPath(op upgradeMigrationOperation) string {
+	for _, relPath := range migrationCoveredPaths(op) {
+		if inst.isOwnershipOverride(relPath) {
+			return relPath
+		}
+	}
+	return ""
+}
@@ -0,0 +1,59 @@
+package install
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestOwnershipOverrides(t *testing.T) {
+	root := t.TempDir()
+	if err := Run(root, Options{System: RealSystem{}}); err != nil {
+		t.Fatalf("init: %v", err)
+	}
+	write := func(rel string, content string) {
+		t.Helper()
+		path := filepath.Join(root, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	cfg, err := os.ReadFile(filepath.Join(root, ".agent-layer", "config.toml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	write(".agent-layer/config.toml", string(cfg)+"\n[ownership]\n\"docs/agent-layer/COMMANDS.md\" = \"user\"\n\".agent-layer/notes\" = \"user\"\n")
+	write("docs/agent-layer/COMMANDS.md", "# Our commands\n")
+	write(".agent-layer/commands.allow", "echo custom\n")
+	write(".agent-layer/notes/mine.md", "keep me\n")
+
+	plan, err := BuildUpgradePlan(root, UpgradePlanOptions{System: RealSystem{}})
+	if err != nil {
+		t.Fatalf("BuildUpgradePlan: %v", err)
+	}
+	if len(plan.OptedOut) != 1 || plan.OptedOut[0].Path != "docs/agent-layer/COMMANDS.md" {
+		t.Fatalf("OptedOut = %+v", plan.OptedOut)
+	}
+	if findUpgradeChange(plan.TemplateUpdates, "docs/agent-layer/COMMANDS.md") != nil || findUpgradeChange(plan.TemplateUpdates, ".agent-layer/commands.allow") == nil {
+		t.Fatalf("TemplateUpdates = %+v", plan.TemplateUpdates)
+	}
+
+	if err := Run(root, Options{Overwrite: true, Prompter: autoApprovePrompter(), System: RealSystem{}}); err != nil {
+		t.Fatalf("upgrade: %v", err)
+	}
+	for rel, want := range map[string]string{
+		"docs/agent-layer/COMMANDS.md": "# Our commands\n",
+		".agent-layer/notes/mine.md":   "keep me\n",
+	} {
+		data, err := os.ReadFile(filepath.Join(root, filepath.FromSlash(rel)))
+		if err != nil || string(data) != want {
+			t.Fatalf("user-owned %s = %q (%v)", rel, data, err)
+		}
+	}
+	if data, _ := os.ReadFile(filepath.Join(root, ".agent-layer", "commands.allow")); string(data) == "echo custom\n" {
+		t.Fatal("managed file without an override was not refreshed")
+	}
+}
@@ -1,11 +1,13 @@
 package install
 
 import (
+	"bytes"
 	"crypto/sha256"
 	"encoding/json"
 	"fmt"
 	"sort"
 	"strings"
+	"unicode/utf8"
 )
 
 const (
@@ -108,7 +110,7 @@ func buildOwnershipComparable(relPath string, content []byte) (ownershipComparab
 	normalizedContent := normalizeTemplateContent(string(content))
 	out := ownershipComparable{
 		PolicyID: policyID,
-		FullHash: hashOwnershipString(normalizedContent),
+		FullHash: templateFullHash(content),
 	}
 
 	switch policyID {
@@ -337,6 +339,23 @@ func parseAllowlistSet(normalizedContent string) ([]string, string) {
 	return set, hashOwnershipString(builder.String())
 }
 
+// templateFullHash is the manifest full_hash_normalized value for content.
+// Binary assets are hashed over their raw bytes, matching the manifest
+// generator; line-ending normalization would corrupt them.
+func templateFullHash(content []byte) string {
+	if isBinaryTemplateContent(content) {
+		sum := sha256.Sum256(content)
+		return fmt.Sprintf("%x", sum[:])
+	}
+	return hashOwnershipString(normalizeTemplateContent(string(content)))
+}
+
+// isBinaryTemplateContent reports whether content is a binary asset: it
+// contains a NUL byte or is not valid UTF-8.
+func isBinaryTemplateContent(content []byte) bool {
+	return bytes.IndexByte(content, 0) >= 0 || !utf8.Valid(content)
+}
+
 func hashOwnershipString(content string) string {
 	sum := sha256.Sum256([]byte(content))
 	return fmt.Sprintf("%x", sum[:])
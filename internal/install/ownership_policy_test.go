@@ -57,6 +57,25 @@ func TestBuildOwnershipComparable_MemoryMarkerErrors(t *testing.T) {
 	}
 }
 
+func TestBuildOwnershipComparable_BinaryHashesRawBytes(t *testing.T) {
+	binary := []byte{0x89, 'P', 'N', 'G', '\r', '\n', 0x1a, '\n', 0x00}
+	comp, err := buildOwnershipComparable(".agent-layer/skills/demo/assets/logo.png", binary)
+	if err != nil {
+		t.Fatalf("buildOwnershipComparable: %v", err)
+	}
+	if want := hashOwnershipString(string(binary)); comp.FullHash != want {
+		t.Fatalf("FullHash = %s, want raw-byte hash %s", comp.FullHash, want)
+	}
+
+	text, err := buildOwnershipComparable(".agent-layer/skills/demo/SKILL.md", []byte("body\r\n"))
+	if err != nil {
+		t.Fatalf("buildOwnershipComparable: %v", err)
+	}
+	if want := hashOwnershipString("body\n"); text.FullHash != want {
+		t.Fatalf("text FullHash = %s, want normalized hash %s", text.FullHash, want)
+	}
+}
+
 func TestOwnershipPolicyPayload_RoundTrip(t *testing.T) {
 	memoryContent := "# COMMANDS\n\nheader\n\n<!-- ENTRIES START -->\n\n- x\n"
 	memoryComp, err := buildOwnershipComparable("docs/agent-layer/COMMANDS.md", []byte(memoryContent))
@@ -4,10 +4,15 @@ import (
 	"fmt"
 
 	"github.com/conn-castle/agent-layer/internal/config"
+	"github.com/conn-castle/agent-layer/internal/i18n"
 	"github.com/conn-castle/agent-layer/internal/messages"
 )
 
-// Prompter provides user prompts for overwrite and delete decisions.
+// Prompter provides user prompts for overwrite and delete decisions. It is
+// the stable interface for embedders that drive install and upgrade with their
+// own prompts; a Prompter may also implement any of the optional *Prompter
+// interfaces below to answer more prompt categories. PromptFuncs adapts plain
+// callbacks and implements all of them.
 type Prompter interface {
 	OverwriteAll(previews []DiffPreview) (bool, error)
 	OverwriteAllMemory(previews []DiffPreview) (bool, error)
@@ -47,15 +52,17 @@ type PromptFuncs struct {
 	DeleteUnknownAllFunc           PromptDeleteUnknownAllFunc
 	DeleteUnknownFunc              PromptDeleteUnknownFunc
 	DeleteUnknownTmpAllFunc        PromptDeleteUnknownTmpAllFunc
+	DeleteOrphanedAllFunc          PromptDeleteOrphanedAllFunc
 	ConfigSetDefaultFunc           PromptConfigSetDefaultFunc
 	ConfirmSkillsMigrationFunc     PromptConfirmSkillsMigrationFunc
+	ChooseSourceVersionFunc        PromptChooseSourceVersionFunc
 }
 
 // OverwriteAll prompts the user to confirm overwriting all given paths.
 // Returns an error if no overwrite callback is configured.
 func (p PromptFuncs) OverwriteAll(previews []DiffPreview) (bool, error) {
 	if p.OverwriteAllPreviewFunc == nil {
-		return false, fmt.Errorf(messages.InstallOverwritePromptRequired)
+		return false, i18n.Errorf(messages.InstallOverwritePromptRequired)
 	}
 	return p.OverwriteAllPreviewFunc(previews)
 }
@@ -64,7 +71,7 @@ func (p PromptFuncs) OverwriteAll(previews []DiffPreview) (bool, error) {
 // Returns an error if no OverwriteAll callback is configured.
 func (p PromptFuncs) OverwriteAllMemory(previews []DiffPreview) (bool, error) {
 	if p.OverwriteAllMemoryPreviewFunc == nil {
-		return false, fmt.Errorf(messages.InstallOverwritePromptRequired)
+		return false, i18n.Errorf(messages.InstallOverwritePromptRequired)
 	}
 	return p.OverwriteAllMemoryPreviewFunc(previews)
 }
@@ -73,7 +80,7 @@ func (p PromptFuncs) OverwriteAllMemory(previews []DiffPreview) (bool, error) {
 // Returns an error if no unified callback is configured.
 func (p PromptFuncs) OverwriteAllUnified(managed []DiffPreview, memory []DiffPreview) (bool, bool, error) {
 	if p.OverwriteAllUnifiedPreviewFunc == nil {
-		return false, false, fmt.Errorf(messages.InstallOverwritePromptRequired)
+		return false, false, i18n.Errorf(messages.InstallOverwritePromptRequired)
 	}
 	return p.OverwriteAllUnifiedPreviewFunc(managed, memory)
 }
@@ -82,7 +89,7 @@ func (p PromptFuncs) OverwriteAllUnified(managed []DiffPreview, memory []DiffPre
 // Returns an error if no Overwrite callback is configured.
 func (p PromptFuncs) Overwrite(preview DiffPreview) (bool, error) {
 	if p.OverwritePreviewFunc == nil {
-		return false, fmt.Errorf(messages.InstallOverwritePromptRequired)
+		return false, i18n.Errorf(messages.InstallOverwritePromptRequired)
 	}
 	return p.OverwritePreviewFunc(preview)
 }
@@ -101,7 +108,7 @@ func (p PromptFuncs) StatuslineSource(preview DiffPreview) (bool, error) {
 // Returns an error if no DeleteUnknownAllFunc is configured.
 func (p PromptFuncs) DeleteUnknownAll(paths []string) (bool, error) {
 	if p.DeleteUnknownAllFunc == nil {
-		return false, fmt.Errorf(messages.InstallDeleteUnknownPromptRequired)
+		return false, i18n.Errorf(messages.InstallDeleteUnknownPromptRequired)
 	}
 	return p.DeleteUnknownAllFunc(paths)
 }
@@ -110,7 +117,7 @@ func (p PromptFuncs) DeleteUnknownAll(paths []string) (bool, error) {
 // Returns an error if no DeleteUnknownFunc is configured.
 func (p PromptFuncs) DeleteUnknown(path string) (bool, error) {
 	if p.DeleteUnknownFunc == nil {
-		return false, fmt.Errorf(messages.InstallDeleteUnknownPromptRequired)
+		return false, i18n.Errorf(messages.InstallDeleteUnknownPromptRequired)
 	}
 	return p.DeleteUnknownFunc(path)
 }
@@ -121,22 +128,42 @@ func (p PromptFuncs) DeleteUnknown(path string) (bool, error) {
 // behavior of the other prompt callbacks. The grouped-vs-untouched fallback for
 // callers that construct a PromptFuncs without wiring DeleteUnknownTmpAllFunc —
 // and for legacy Prompter implementations that don't implement
-// tmpUnknownsPrompter at all — is owned by the promptRouter, which probes
+// TmpUnknownsPrompter at all — is owned by the promptRouter, which probes
 // promptValidator (see newPromptRouter) and leaves tmp paths untouched rather
 // than invoking this method.
 func (p PromptFuncs) DeleteUnknownTmpAll(paths []string) (bool, error) {
 	if p.DeleteUnknownTmpAllFunc == nil {
-		return false, fmt.Errorf(messages.InstallDeleteUnknownPromptRequired)
+		return false, i18n.Errorf(messages.InstallDeleteUnknownPromptRequired)
 	}
 	return p.DeleteUnknownTmpAllFunc(paths)
 }
 
-// configSetDefaultPrompter is an optional interface that a Prompter can
+// PromptDeleteOrphanedAllFunc asks whether to delete every file an earlier
+// release installed that the current release no longer ships.
+type PromptDeleteOrphanedAllFunc func(orphans []OrphanedArtifact) (bool, error)
+
+// OrphanedArtifactsPrompter is an optional interface a Prompter can implement
+// to review files left behind by earlier releases as one group. Without it
+// (or with a nil callback) those files stay in the unknown-file prompts.
+type OrphanedArtifactsPrompter interface {
+	DeleteOrphanedAll(orphans []OrphanedArtifact) (bool, error)
+}
+
+// DeleteOrphanedAll prompts the user to confirm deleting orphaned files.
+// Returns false when no callback is set.
+func (p PromptFuncs) DeleteOrphanedAll(orphans []OrphanedArtifact) (bool, error) {
+	if p.DeleteOrphanedAllFunc == nil {
+		return false, nil
+	}
+	return p.DeleteOrphanedAllFunc(orphans)
+}
+
+// ConfigSetDefaultPrompter is an optional interface that a Prompter can
 // implement to interactively confirm or customize config_set_default
 // migration values. When the Prompter does not implement this interface (or
 // PromptFuncs has no callback wired), the migration uses the manifest value
 // directly.
-type configSetDefaultPrompter interface {
+type ConfigSetDefaultPrompter interface {
 	ConfigSetDefault(key string, manifestValue any, rationale string, field *config.FieldDef) (any, error)
 }
 
@@ -158,15 +185,18 @@ type SkillsMigrationConflict struct {
 	Reason    string
 }
 
-// skillsMigrationPrompter is an optional interface that a Prompter can
+// SkillsMigrationPrompter is an optional interface that a Prompter can
 // implement to confirm skills-format migration with the user. When the
 // Prompter does not implement this interface (or the callback is nil),
 // migration proceeds automatically (headless default).
-type skillsMigrationPrompter interface {
+type SkillsMigrationPrompter interface {
 	ConfirmSkillsMigration(flatSkills []string, conflicts []SkillsMigrationConflict) (bool, error)
 }
 
-type statuslineSourcePrompter interface {
+// StatuslineSourcePrompter is an optional interface a Prompter can implement
+// to replace a user-owned statusline source with the template version.
+// Without it the source is left alone.
+type StatuslineSourcePrompter interface {
 	StatuslineSource(preview DiffPreview) (bool, error)
 }
 
@@ -184,6 +214,28 @@ func (p PromptFuncs) ConfirmSkillsMigration(flatSkills []string, conflicts []Ski
 	return p.ConfirmSkillsMigrationFunc(flatSkills, conflicts)
 }
 
+// SourceVersionPrompter is an optional interface a Prompter can implement to
+// choose the upgrade source version when manifest fingerprinting finds no
+// unique match. Without it (or with a nil callback) the source stays unknown
+// and only source-agnostic migrations run.
+type SourceVersionPrompter interface {
+	ChooseSourceVersion(candidates []BaselineCandidate) (string, error)
+}
+
+// PromptChooseSourceVersionFunc asks the user which release the repo was last
+// installed or upgraded from. candidates are ranked best match first. It
+// returns the chosen version, or "" to keep the source unknown.
+type PromptChooseSourceVersionFunc func(candidates []BaselineCandidate) (string, error)
+
+// ChooseSourceVersion prompts for the upgrade source version. Returns "" when
+// no callback is set.
+func (p PromptFuncs) ChooseSourceVersion(candidates []BaselineCandidate) (string, error) {
+	if p.ChooseSourceVersionFunc == nil {
+		return "", nil
+	}
+	return p.ChooseSourceVersionFunc(candidates)
+}
+
 type promptValidator interface {
 	hasOverwriteAll() bool
 	hasOverwriteAllMemory() bool
@@ -194,7 +246,7 @@ type promptValidator interface {
 	hasDeleteUnknownTmpAll() bool
 }
 
-// tmpUnknownsPrompter is an optional capability a Prompter can implement to
+// TmpUnknownsPrompter is an optional capability a Prompter can implement to
 // collapse the per-file delete prompts for files under .agent-layer/tmp/ into
 // a single grouped yes/no question. Detection is two-step: a Prompter must
 // satisfy this interface AND, when it also implements promptValidator, report
@@ -203,7 +255,7 @@ type promptValidator interface {
 // callers that build a PromptFuncs without wiring DeleteUnknownTmpAllFunc
 // would otherwise hit the "prompt required" error instead of leaving tmp paths
 // untouched. newPromptRouter performs both probes.
-type tmpUnknownsPrompter interface {
+type TmpUnknownsPrompter interface {
 	DeleteUnknownTmpAll(paths []string) (bool, error)
 }
 
@@ -239,13 +291,21 @@ func (p PromptFuncs) hasStatuslineSource() bool {
 	return p.StatuslineSourcePreviewFunc != nil
 }
 
-// unifiedOverwritePrompter is an optional interface a Prompter can implement to
+func (p PromptFuncs) hasChooseSourceVersion() bool {
+	return p.ChooseSourceVersionFunc != nil
+}
+
+func (p PromptFuncs) hasDeleteOrphanedAll() bool {
+	return p.DeleteOrphanedAllFunc != nil
+}
+
+// UnifiedOverwritePrompter is an optional interface a Prompter can implement to
 // resolve the managed and memory overwrite-all decisions in a single pass. The
 // router only selects it when the prompter also implements promptValidator and
-// reports the unified callback as wired (see newPromptRouter); a prompter that
-// implements this interface without promptValidator keeps the separate managed
-// and memory overwrite-all prompts.
-type unifiedOverwritePrompter interface {
+// reports the unified callback as wired (see newPromptRouter), so only a
+// PromptFuncs with OverwriteAllUnifiedPreviewFunc set uses it; any other
+// prompter keeps the separate managed and memory overwrite-all prompts.
+type UnifiedOverwritePrompter interface {
 	OverwriteAllUnified(managed []DiffPreview, memory []DiffPreview) (bool, bool, error)
 }
 
@@ -253,6 +313,14 @@ type statuslineSourceValidator interface {
 	hasStatuslineSource() bool
 }
 
+type sourceVersionValidator interface {
+	hasChooseSourceVersion() bool
+}
+
+type orphanedArtifactsValidator interface {
+	hasDeleteOrphanedAll() bool
+}
+
 // promptKind identifies which prompt category a promptRequest represents.
 type promptKind int
 
@@ -265,8 +333,10 @@ const (
 	promptKindDeleteUnknownAll
 	promptKindDeleteUnknown
 	promptKindDeleteUnknownTmpAll
+	promptKindDeleteOrphanedAll
 	promptKindConfigSetDefault
 	promptKindConfirmSkillsMigration
+	promptKindChooseSourceVersion
 )
 
 // promptRequest carries the data a single prompt category needs. Only the
@@ -281,6 +351,8 @@ type promptRequest struct {
 	paths []string // delete-unknown-all / delete-unknown-tmp-all
 	path  string   // single delete-unknown
 
+	orphans []OrphanedArtifact
+
 	configKey     string
 	manifestValue any
 	rationale     string
@@ -288,16 +360,19 @@ type promptRequest struct {
 
 	flatSkills []string
 	conflicts  []SkillsMigrationConflict
+
+	sourceCandidates []BaselineCandidate
 }
 
 // promptResponse carries a prompt outcome. Which fields are meaningful depends
 // on the request kind: approved is the primary yes/no decision, approvedMemory
-// is the second unified overwrite-all decision, and value is the resolved
-// config default.
+// is the second unified overwrite-all decision, value is the resolved config
+// default, and version is the chosen upgrade source version.
 type promptResponse struct {
 	approved       bool
 	approvedMemory bool
 	value          any
+	version        string
 }
 
 // promptRouter is the single place install/upgrade prompt decisions flow
@@ -306,11 +381,13 @@ type promptResponse struct {
 // prompt category so callers no longer repeat optional-interface probing.
 type promptRouter struct {
 	prompter      Prompter
-	unified       unifiedOverwritePrompter
-	tmpUnknowns   tmpUnknownsPrompter
-	statusline    statuslineSourcePrompter
-	configDefault configSetDefaultPrompter
-	skills        skillsMigrationPrompter
+	unified       UnifiedOverwritePrompter
+	tmpUnknowns   TmpUnknownsPrompter
+	statusline    StatuslineSourcePrompter
+	configDefault ConfigSetDefaultPrompter
+	skills        SkillsMigrationPrompter
+	sourceVersion SourceVersionPrompter
+	orphans       OrphanedArtifactsPrompter
 }
 
 // newPromptRouter resolves prompter's optional prompt capabilities under the
@@ -325,7 +402,7 @@ func newPromptRouter(prompter Prompter) *promptRouter {
 	// that reports the unified callback as wired. A prompter that implements
 	// the interface but not promptValidator does NOT get unified overwrite,
 	// preserving the separate managed and memory overwrite-all prompts.
-	if unified, ok := prompter.(unifiedOverwritePrompter); ok && unified != nil {
+	if unified, ok := prompter.(UnifiedOverwritePrompter); ok && unified != nil {
 		if validator, vok := prompter.(promptValidator); vok && validator.hasOverwriteAllUnified() {
 			r.unified = unified
 		}
@@ -336,7 +413,7 @@ func newPromptRouter(prompter Prompter) *promptRouter {
 	// implementations). PromptFuncs always satisfies the interface, so the
 	// validator probe distinguishes a wired callback from a zero value; when it
 	// is unwired, tmp paths are left untouched rather than routed elsewhere.
-	if grouped, ok := prompter.(tmpUnknownsPrompter); ok {
+	if grouped, ok := prompter.(TmpUnknownsPrompter); ok {
 		wired := true
 		if validator, vok := prompter.(promptValidator); vok && !validator.hasDeleteUnknownTmpAll() {
 			wired = false
@@ -345,7 +422,7 @@ func newPromptRouter(prompter Prompter) *promptRouter {
 			r.tmpUnknowns = grouped
 		}
 	}
-	if statusline, ok := prompter.(statuslineSourcePrompter); ok {
+	if statusline, ok := prompter.(StatuslineSourcePrompter); ok {
 		wired := true
 		if validator, vok := prompter.(statuslineSourceValidator); vok && !validator.hasStatuslineSource() {
 			wired = false
@@ -354,12 +431,32 @@ func newPromptRouter(prompter Prompter) *promptRouter {
 			r.statusline = statusline
 		}
 	}
-	if configDefault, ok := prompter.(configSetDefaultPrompter); ok {
+	if configDefault, ok := prompter.(ConfigSetDefaultPrompter); ok {
 		r.configDefault = configDefault
 	}
-	if skills, ok := prompter.(skillsMigrationPrompter); ok {
+	if skills, ok := prompter.(SkillsMigrationPrompter); ok {
 		r.skills = skills
 	}
+	// PromptFuncs always satisfies the interface, so the validator probe keeps
+	// an unwired callback from triggering the ranked-manifest scan.
+	if sourceVersion, ok := prompter.(SourceVersionPrompter); ok {
+		wired := true
+		if validator, vok := prompter.(sourceVersionValidator); vok && !validator.hasChooseSourceVersion() {
+			wired = false
+		}
+		if wired {
+			r.sourceVersion = sourceVersion
+		}
+	}
+	if orphans, ok := prompter.(OrphanedArtifactsPrompter); ok {
+		wired := true
+		if validator, vok := prompter.(orphanedArtifactsValidator); vok && !validator.hasDeleteOrphanedAll() {
+			wired = false
+		}
+		if wired {
+			r.orphans = orphans
+		}
+	}
 	return r
 }
 
@@ -372,31 +469,39 @@ func (r *promptRouter) hasUnifiedOverwrite() bool { return r.unified != nil }
 // diff-preview build on this when the prompter lacks the optional interface.
 func (r *promptRouter) hasStatuslineSource() bool { return r.statusline != nil }
 
+// hasSourceVersion reports whether the wrapped prompter can choose an upgrade
+// source version. Callers gate the manifest ranking scan on it.
+func (r *promptRouter) hasSourceVersion() bool { return r.sourceVersion != nil }
+
+// hasOrphanedArtifacts reports whether the wrapped prompter reviews orphaned
+// files as a group. Callers gate the historical-manifest scan on it.
+func (r *promptRouter) hasOrphanedArtifacts() bool { return r.orphans != nil }
+
 // validateRequiredOverwrite enforces that a Prompter used in overwrite mode
 // wires the required core overwrite and delete callbacks before any overwrite
 // work begins. It preserves the historical early-error messages.
 func (r *promptRouter) validateRequiredOverwrite() error {
 	if r.prompter == nil {
-		return fmt.Errorf(messages.InstallOverwritePromptRequired)
+		return i18n.Errorf(messages.InstallOverwritePromptRequired)
 	}
 	validator, ok := r.prompter.(promptValidator)
 	if !ok {
 		return nil
 	}
 	if !validator.hasOverwriteAll() {
-		return fmt.Errorf(messages.InstallOverwritePromptRequired)
+		return i18n.Errorf(messages.InstallOverwritePromptRequired)
 	}
 	if !validator.hasOverwriteAllMemory() {
-		return fmt.Errorf(messages.InstallOverwritePromptRequired)
+		return i18n.Errorf(messages.InstallOverwritePromptRequired)
 	}
 	if !validator.hasOverwrite() {
-		return fmt.Errorf(messages.InstallOverwritePromptRequired)
+		return i18n.Errorf(messages.InstallOverwritePromptRequired)
 	}
 	if !validator.hasDeleteUnknownAll() {
-		return fmt.Errorf(messages.InstallDeleteUnknownPromptRequired)
+		return i18n.Errorf(messages.InstallDeleteUnknownPromptRequired)
 	}
 	if !validator.hasDeleteUnknown() {
-		return fmt.Errorf(messages.InstallDeleteUnknownPromptRequired)
+		return i18n.Errorf(messages.InstallDeleteUnknownPromptRequired)
 	}
 	return nil
 }
@@ -439,6 +544,14 @@ func (r *promptRouter) route(req promptRequest) (promptResponse, error) {
 		}
 		approved, err := r.tmpUnknowns.DeleteUnknownTmpAll(req.paths)
 		return promptResponse{approved: approved}, err
+	case promptKindDeleteOrphanedAll:
+		// Missing orphan prompt keeps the files; handleOrphanedArtifacts only
+		// routes here when the capability is wired.
+		if r.orphans == nil {
+			return promptResponse{}, nil
+		}
+		approved, err := r.orphans.DeleteOrphanedAll(req.orphans)
+		return promptResponse{approved: approved}, err
 	case promptKindConfigSetDefault:
 		// Missing config-default prompt uses the migration manifest value.
 		if r.configDefault == nil {
@@ -453,6 +566,13 @@ func (r *promptRouter) route(req promptRequest) (promptResponse, error) {
 		}
 		approved, err := r.skills.ConfirmSkillsMigration(req.flatSkills, req.conflicts)
 		return promptResponse{approved: approved}, err
+	case promptKindChooseSourceVersion:
+		// Missing source-version prompt keeps the source unknown.
+		if r.sourceVersion == nil {
+			return promptResponse{}, nil
+		}
+		chosen, err := r.sourceVersion.ChooseSourceVersion(req.sourceCandidates)
+		return promptResponse{version: chosen}, err
 	default:
 		return promptResponse{}, fmt.Errorf("install: unknown prompt kind %d", req.kind)
 	}
@@ -194,6 +194,34 @@ func TestPromptRouter_ConfirmSkillsMigration_FallbackProceeds(t *testing.T) {
 	}
 }
 
+func TestPromptRouter_ChooseSourceVersion(t *testing.T) {
+	// Without a wired callback the source stays unknown and the router reports
+	// no capability, so callers skip the manifest ranking scan.
+	for _, prompter := range []Prompter{plainPrompter{}, PromptFuncs{}} {
+		router := newPromptRouter(prompter)
+		if router.hasSourceVersion() {
+			t.Fatalf("%T must not report a source-version capability", prompter)
+		}
+		resp, err := router.route(promptRequest{kind: promptKindChooseSourceVersion})
+		if err != nil || resp.version != "" {
+			t.Fatalf("%T fallback = %q, %v; want empty version", prompter, resp.version, err)
+		}
+	}
+
+	router := newPromptRouter(PromptFuncs{
+		ChooseSourceVersionFunc: func(candidates []BaselineCandidate) (string, error) {
+			return candidates[1].Version, nil
+		},
+	})
+	if !router.hasSourceVersion() {
+		t.Fatal("wired callback must report the source-version capability")
+	}
+	resp, err := router.route(promptRequest{kind: promptKindChooseSourceVersion, sourceCandidates: []BaselineCandidate{{Version: "1.1.0"}, {Version: "1.0.0"}}})
+	if err != nil || resp.version != "1.0.0" {
+		t.Fatalf("wired prompt = %q, %v; want 1.0.0", resp.version, err)
+	}
+}
+
 func TestPromptRouter_ValidateRequiredOverwrite(t *testing.T) {
 	if err := newPromptRouter(nil).validateRequiredOverwrite(); err == nil ||
 		!strings.Contains(err.Error(), messages.InstallOverwritePromptRequired) {
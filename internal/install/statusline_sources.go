@@ -2,11 +2,12 @@ package install
 
 import (
 	"errors"
-	"fmt"
 	"os"
 	"path/filepath"
 
 	"github.com/conn-castle/agent-layer/internal/config"
+	"github.com/conn-castle/agent-layer/internal/i18n"
+	"github.com/conn-castle/agent-layer/internal/layerdir"
 	"github.com/conn-castle/agent-layer/internal/messages"
 	"github.com/conn-castle/agent-layer/internal/templates"
 )
@@ -47,7 +48,7 @@ func StatuslineSourceTemplates() []StatuslineSourceTemplate {
 }
 
 func (inst *installer) writeStatuslineSources() error {
-	cfg, err := config.LoadConfigLenient(filepath.Join(inst.root, ".agent-layer", configFileName))
+	cfg, err := config.LoadConfigLenient(filepath.Join(layerdir.Dir(inst.root), configFileName))
 	if err != nil {
 		if errors.Is(err, os.ErrNotExist) {
 			return nil
@@ -81,18 +82,18 @@ func (inst *installer) writeStatuslineSource(source StatuslineSourceTemplate) er
 	info, err := inst.sys.Stat(path)
 	if err != nil {
 		if !errors.Is(err, os.ErrNotExist) {
-			return fmt.Errorf(messages.InstallFailedStatFmt, path, err)
+			return i18n.Errorf(messages.InstallFailedStatFmt, path, err)
 		}
 		return inst.seedMissingStatuslineSource(source, path)
 	}
 	if info.IsDir() {
-		return fmt.Errorf(messages.InstallFailedReadFmt, path, errors.New("is a directory"))
+		return i18n.Errorf(messages.InstallFailedReadFmt, path, errors.New("is a directory"))
 	}
 	matches, err := inst.templates().matchTemplate(inst.sys, path, source.TemplatePath, info)
 	if err != nil {
 		return err
 	}
-	if matches {
+	if matches || inst.isOwnershipOverride(source.RelPath) || inst.filteredTemplatePath(path) {
 		return nil
 	}
 	overwrite := false
@@ -125,17 +126,17 @@ func (inst *installer) seedMissingStatuslineSource(source StatuslineSourceTempla
 func (inst *installer) writeStatuslineSourceTemplate(source StatuslineSourceTemplate, path string) error {
 	data, err := templates.Read(source.TemplatePath)
 	if err != nil {
-		return fmt.Errorf(messages.InstallFailedReadTemplateFmt, source.TemplatePath, err)
+		return i18n.Errorf(messages.InstallFailedReadTemplateFmt, source.TemplatePath, err)
 	}
 	return inst.writeStatuslineSourceBytes(path, data, source.Perm)
 }
 
 func (inst *installer) writeStatuslineSourceBytes(path string, data []byte, perm os.FileMode) error {
 	if err := inst.sys.MkdirAll(filepath.Dir(path), 0o755); err != nil {
-		return fmt.Errorf(messages.InstallFailedCreateDirForFmt, path, err)
+		return i18n.Errorf(messages.InstallFailedCreateDirForFmt, path, err)
 	}
 	if err := inst.sys.WriteFileAtomic(path, data, perm); err != nil {
-		return fmt.Errorf(messages.InstallFailedWriteFmt, path, err)
+		return i18n.Errorf(messages.InstallFailedWriteFmt, path, err)
 	}
 	return nil
 }
@@ -144,11 +145,11 @@ func (inst *installer) buildStatuslineSourceDiffPreview(source StatuslineSourceT
 	path := filepath.Join(inst.root, filepath.FromSlash(source.RelPath))
 	localBytes, err := inst.sys.ReadFile(path)
 	if err != nil {
-		return DiffPreview{}, fmt.Errorf(messages.InstallFailedReadFmt, path, err)
+		return DiffPreview{}, i18n.Errorf(messages.InstallFailedReadFmt, path, err)
 	}
 	templateBytes, err := templates.Read(source.TemplatePath)
 	if err != nil {
-		return DiffPreview{}, fmt.Errorf(messages.InstallFailedReadTemplateFmt, source.TemplatePath, err)
+		return DiffPreview{}, i18n.Errorf(messages.InstallFailedReadTemplateFmt, source.TemplatePath, err)
 	}
 	rendered, truncated, added, removed := renderTruncatedUnifiedDiff(
 		source.RelPath+" (current)",
@@ -177,7 +178,7 @@ func (inst *installer) writeStatuslineSourcesTargetPaths() []string {
 }
 
 func (inst *installer) planStatuslineSourceChanges(plan migrationPlan) ([]upgradeChangeWithTemplate, []upgradeChangeWithTemplate, error) {
-	cfg, err := config.LoadConfigLenient(filepath.Join(inst.root, ".agent-layer", configFileName))
+	cfg, err := config.LoadConfigLenient(filepath.Join(layerdir.Dir(inst.root), configFileName))
 	if err != nil {
 		if errors.Is(err, os.ErrNotExist) {
 			return nil, nil, nil
@@ -197,7 +198,7 @@ func (inst *installer) planStatuslineSourceChanges(plan migrationPlan) ([]upgrad
 				additions = append(additions, statuslineSourceUpgradeChange(source))
 				continue
 			}
-			return nil, nil, fmt.Errorf(messages.InstallFailedStatFmt, path, err)
+			return nil, nil, i18n.Errorf(messages.InstallFailedStatFmt, path, err)
 		}
 		if info.IsDir() {
 			continue
@@ -243,18 +244,18 @@ func StatuslineSourceSeedBytes(root string, source StatuslineSourceTemplate) ([]
 // returns the bytes and a short origin label (legacy rel-path or "template").
 func statuslineSeedBytes(root string, readFile func(string) ([]byte, error), source StatuslineSourceTemplate) ([]byte, string, error) {
 	if source.LegacyRelPath != "" {
-		legacyPath := filepath.Join(root, filepath.FromSlash(source.LegacyRelPath))
+		legacyPath := layerdir.Path(root, source.LegacyRelPath)
 		data, err := readFile(legacyPath)
 		if err == nil {
 			return data, source.LegacyRelPath, nil
 		}
 		if !errors.Is(err, os.ErrNotExist) {
-			return nil, "", fmt.Errorf(messages.InstallFailedReadFmt, legacyPath, err)
+			return nil, "", i18n.Errorf(messages.InstallFailedReadFmt, legacyPath, err)
 		}
 	}
 	data, err := templates.Read(source.TemplatePath)
 	if err != nil {
-		return nil, "", fmt.Errorf(messages.InstallFailedReadTemplateFmt, source.TemplatePath, err)
+		return nil, "", i18n.Errorf(messages.InstallFailedReadTemplateFmt, source.TemplatePath, err)
 	}
 	return data, statuslineSeedOriginTemplate, nil
 }
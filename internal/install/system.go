@@ -95,3 +95,24 @@ func (RealSystem) WalkDir(root string, fn fs.WalkDirFunc) error {
 func (RealSystem) WriteFileAtomic(filename string, data []byte, perm os.FileMode) error {
 	return fsutil.WriteFileAtomic(filename, data, perm)
 }
+
+// walkDirFollowingRoot walks root like sys.WalkDir, except that a symlinked
+// root (such as an .agent-layer directory managed by a dotfile manager) is
+// followed. Paths passed to fn stay under root, not the link target.
+func walkDirFollowingRoot(sys System, root string, fn fs.WalkDirFunc) error {
+	info, err := sys.Lstat(root)
+	if err != nil || info.Mode()&os.ModeSymlink == 0 {
+		return sys.WalkDir(root, fn)
+	}
+	resolved, err := sys.EvalSymlinks(root)
+	if err != nil {
+		return fn(root, nil, err)
+	}
+	return sys.WalkDir(resolved, func(path string, entry fs.DirEntry, walkErr error) error {
+		rel, relErr := filepath.Rel(resolved, path)
+		if relErr != nil {
+			return relErr
+		}
+		return fn(filepath.Join(root, rel), entry, walkErr)
+	})
+}
@@ -0,0 +1,248 @@
+package install
+
+import (
+	"bytes"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/conn-castle/agent-layer/internal/i18n"
+	"github.com/conn-castle/agent-layer/internal/messages"
+)
+
+// fileTransaction is a System that holds WriteFileAtomic calls in memory and
+// writes them together on Commit, so a failing or interrupted upgrade step
+// never leaves some of its files rewritten and others not yet computed. Each
+// file is still written with a temp file and rename, so no file is ever
+// half-written; if a later file fails, the files already committed are
+// restored to what they held before.
+//
+// Reads of pending files see the pending content. Any other operation that
+// touches a pending path, and any operation that changes the filesystem,
+// commits the pending writes first so operations keep their original order.
+type fileTransaction struct {
+	System
+	order   []string
+	pending map[string]pendingWrite
+}
+
+type pendingWrite struct {
+	data []byte
+	perm os.FileMode
+}
+
+// priorFile is what a path held before a transaction overwrote it.
+type priorFile struct {
+	path   string
+	exists bool
+	link   string
+	data   []byte
+	perm   os.FileMode
+	// skip marks a path restore must leave alone, such as a directory the
+	// write could not have replaced.
+	skip bool
+}
+
+func newFileTransaction(sys System) *fileTransaction {
+	return &fileTransaction{System: sys, pending: make(map[string]pendingWrite)}
+}
+
+// WriteFileAtomic records a write to perform on Commit.
+func (txn *fileTransaction) WriteFileAtomic(filename string, data []byte, perm os.FileMode) error {
+	path := filepath.Clean(filename)
+	if _, ok := txn.pending[path]; !ok {
+		txn.order = append(txn.order, path)
+	}
+	txn.pending[path] = pendingWrite{data: bytes.Clone(data), perm: perm}
+	return nil
+}
+
+// ReadFile returns pending content for paths written in this transaction.
+func (txn *fileTransaction) ReadFile(name string) ([]byte, error) {
+	if write, ok := txn.pending[filepath.Clean(name)]; ok {
+		return bytes.Clone(write.data), nil
+	}
+	return txn.System.ReadFile(name)
+}
+
+// Stat commits first when name has a pending write.
+func (txn *fileTransaction) Stat(name string) (os.FileInfo, error) {
+	if err := txn.commitIfPending(name); err != nil {
+		return nil, err
+	}
+	return txn.System.Stat(name)
+}
+
+// Lstat commits first when name has a pending write.
+func (txn *fileTransaction) Lstat(name string) (os.FileInfo, error) {
+	if err := txn.commitIfPending(name); err != nil {
+		return nil, err
+	}
+	return txn.System.Lstat(name)
+}
+
+// Readlink commits first when name has a pending write.
+func (txn *fileTransaction) Readlink(name string) (string, error) {
+	if err := txn.commitIfPending(name); err != nil {
+		return "", err
+	}
+	return txn.System.Readlink(name)
+}
+
+// EvalSymlinks commits pending writes first.
+func (txn *fileTransaction) EvalSymlinks(path string) (string, error) {
+	if err := txn.Commit(); err != nil {
+		return "", err
+	}
+	return txn.System.EvalSymlinks(path)
+}
+
+// Chmod commits pending writes first.
+func (txn *fileTransaction) Chmod(name string, mode os.FileMode) error {
+	if err := txn.Commit(); err != nil {
+		return err
+	}
+	return txn.System.Chmod(name, mode)
+}
+
+// RemoveAll commits pending writes first.
+func (txn *fileTransaction) RemoveAll(path string) error {
+	if err := txn.Commit(); err != nil {
+		return err
+	}
+	return txn.System.RemoveAll(path)
+}
+
+// Rename commits pending writes first.
+func (txn *fileTransaction) Rename(oldpath string, newpath string) error {
+	if err := txn.Commit(); err != nil {
+		return err
+	}
+	return txn.System.Rename(oldpath, newpath)
+}
+
+// Symlink commits pending writes first.
+func (txn *fileTransaction) Symlink(oldname string, newname string) error {
+	if err := txn.Commit(); err != nil {
+		return err
+	}
+	return txn.System.Symlink(oldname, newname)
+}
+
+// WalkDir commits pending writes first so the walk sees them.
+func (txn *fileTransaction) WalkDir(root string, fn fs.WalkDirFunc) error {
+	if err := txn.Commit(); err != nil {
+		return err
+	}
+	return txn.System.WalkDir(root, fn)
+}
+
+func (txn *fileTransaction) commitIfPending(name string) error {
+	if _, ok := txn.pending[filepath.Clean(name)]; !ok {
+		return nil
+	}
+	return txn.Commit()
+}
+
+// Commit writes every pending file in the order it was first written. When a
+// write fails, the files this Commit already wrote are restored and the
+// write error is returned.
+func (txn *fileTransaction) Commit() error {
+	order, pending := txn.order, txn.pending
+	txn.order, txn.pending = nil, make(map[string]pendingWrite)
+	committed := make([]priorFile, 0, len(order))
+	for _, path := range order {
+		prior, err := txn.capturePrior(path)
+		if err == nil {
+			write := pending[path]
+			if wErr := txn.System.WriteFileAtomic(path, write.data, write.perm); wErr != nil {
+				err = i18n.Errorf(messages.InstallFailedWriteFmt, path, wErr)
+			}
+		}
+		if err != nil {
+			if restoreErr := txn.restore(committed); restoreErr != nil {
+				return i18n.Errorf(messages.InstallTransactionRestoreFailedFmt, err, restoreErr)
+			}
+			return err
+		}
+		committed = append(committed, prior)
+	}
+	return nil
+}
+
+// Discard drops pending writes without writing them.
+func (txn *fileTransaction) Discard() {
+	txn.order, txn.pending = nil, make(map[string]pendingWrite)
+}
+
+func (txn *fileTransaction) capturePrior(path string) (priorFile, error) {
+	info, err := txn.System.Lstat(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return priorFile{path: path}, nil
+	}
+	if err != nil {
+		return priorFile{}, i18n.Errorf(messages.InstallFailedStatFmt, path, err)
+	}
+	prior := priorFile{path: path, exists: true, perm: info.Mode().Perm()}
+	switch {
+	case info.Mode()&os.ModeSymlink != 0:
+		link, err := txn.System.Readlink(path)
+		if err != nil {
+			return priorFile{}, i18n.Errorf(messages.InstallFailedReadFmt, path, err)
+		}
+		prior.link = link
+	case info.IsDir():
+		prior.skip = true
+	default:
+		data, err := txn.System.ReadFile(path)
+		if err != nil {
+			return priorFile{}, i18n.Errorf(messages.InstallFailedReadFmt, path, err)
+		}
+		prior.data = data
+	}
+	return prior, nil
+}
+
+// restore puts committed paths back in reverse order.
+func (txn *fileTransaction) restore(committed []priorFile) error {
+	var errs []error
+	for i := len(committed) - 1; i >= 0; i-- {
+		prior := committed[i]
+		switch {
+		case prior.skip:
+		case !prior.exists:
+			if err := txn.System.RemoveAll(prior.path); err != nil {
+				errs = append(errs, err)
+			}
+		case prior.link != "":
+			if err := txn.System.RemoveAll(prior.path); err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			if err := txn.System.Symlink(prior.link, prior.path); err != nil {
+				errs = append(errs, err)
+			}
+		default:
+			if err := txn.System.WriteFileAtomic(prior.path, prior.data, prior.perm); err != nil {
+				errs = append(errs, i18n.Errorf(messages.InstallFailedWriteFmt, prior.path, err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// runTransactionStep runs step with its file writes held in a fileTransaction
+// and commits them once the step succeeds. A failed step writes nothing it
+// had not already committed.
+func (inst upgradeOrchestrator) runTransactionStep(step transactionStep) error {
+	base := inst.sys
+	txn := newFileTransaction(base)
+	inst.sys = txn
+	defer func() { inst.sys = base }()
+	if err := step.run(); err != nil {
+		txn.Discard()
+		return err
+	}
+	return txn.Commit()
+}
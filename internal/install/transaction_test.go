@@ -0,0 +1,112 @@
+package install
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFileTransaction_CommitRestoresOnFailure(t *testing.T) {
+	root := t.TempDir()
+	existing := filepath.Join(root, "existing.md")
+	link := filepath.Join(root, "link.md")
+	created := filepath.Join(root, "created.md")
+	failing := filepath.Join(root, "failing.md")
+	writeRebuildFile(t, root, "existing.md", "before\n")
+	writeRebuildFile(t, root, "target.md", "target\n")
+	if err := os.Symlink("target.md", link); err != nil {
+		t.Fatalf("symlink: %v", err)
+	}
+	sys := newFaultSystem(RealSystem{})
+	sys.writeErrs[failing] = errors.New("disk full")
+	txn := newFileTransaction(sys)
+
+	for _, path := range []string{existing, link, created, failing} {
+		if err := txn.WriteFileAtomic(path, []byte("after\n"), 0o644); err != nil {
+			t.Fatalf("stage %s: %v", path, err)
+		}
+	}
+	if data, err := txn.ReadFile(existing); err != nil || string(data) != "after\n" {
+		t.Fatalf("pending read = %q, %v", data, err)
+	}
+	if data, _ := os.ReadFile(existing); string(data) != "before\n" { // #nosec G304 -- path is constructed from test-controlled inputs.
+		t.Fatalf("staging must not write, got %q", data)
+	}
+
+	err := txn.Commit()
+	if err == nil || !strings.Contains(err.Error(), "disk full") {
+		t.Fatalf("expected write failure, got %v", err)
+	}
+	if data, _ := os.ReadFile(existing); string(data) != "before\n" { // #nosec G304 -- path is constructed from test-controlled inputs.
+		t.Fatalf("existing file not restored, got %q", data)
+	}
+	if target, err := os.Readlink(link); err != nil || target != "target.md" {
+		t.Fatalf("symlink not restored: %q, %v", target, err)
+	}
+	if _, err := os.Stat(created); !os.IsNotExist(err) {
+		t.Fatalf("created file must be removed, stat err %v", err)
+	}
+	if err := txn.Commit(); err != nil {
+		t.Fatalf("commit after failure must have nothing pending: %v", err)
+	}
+}
+
+func TestFileTransaction_OrderedOperationsCommitFirst(t *testing.T) {
+	root := t.TempDir()
+	src := filepath.Join(root, "a.md")
+	dst := filepath.Join(root, "b.md")
+	txn := newFileTransaction(RealSystem{})
+	if err := txn.WriteFileAtomic(src, []byte("a\n"), 0o644); err != nil {
+		t.Fatalf("stage: %v", err)
+	}
+	if err := txn.Rename(src, dst); err != nil {
+		t.Fatalf("rename must see the pending write: %v", err)
+	}
+	if data, err := os.ReadFile(dst); err != nil || string(data) != "a\n" { // #nosec G304 -- path is constructed from test-controlled inputs.
+		t.Fatalf("renamed content = %q, %v", data, err)
+	}
+
+	if err := txn.WriteFileAtomic(src, []byte("again\n"), 0o600); err != nil {
+		t.Fatalf("stage: %v", err)
+	}
+	info, err := txn.Stat(src)
+	if err != nil || info.Mode().Perm() != 0o600 {
+		t.Fatalf("stat must commit the pending write: %v, %v", info, err)
+	}
+}
+
+func TestRunUpgradeTransaction_FailedStepWritesNothing(t *testing.T) {
+	root := t.TempDir()
+	kept := filepath.Join(root, "kept.md")
+	writeRebuildFile(t, root, "kept.md", "original\n")
+	inst := &installer{root: root, sys: RealSystem{}}
+	step := transactionStep{
+		name: "partial",
+		run: func() error {
+			if err := inst.sys.WriteFileAtomic(kept, []byte("half\n"), 0o644); err != nil {
+				return err
+			}
+			return errors.New("step failed")
+		},
+	}
+
+	if err := inst.upgrades().runTransactionStep(step); err == nil || err.Error() != "step failed" {
+		t.Fatalf("expected step error, got %v", err)
+	}
+	if data, _ := os.ReadFile(kept); string(data) != "original\n" { // #nosec G304 -- path is constructed from test-controlled inputs.
+		t.Fatalf("failed step must not write, got %q", data)
+	}
+	if _, ok := inst.sys.(RealSystem); !ok {
+		t.Fatalf("step must restore the installer system, got %T", inst.sys)
+	}
+
+	step.run = func() error { return inst.sys.WriteFileAtomic(kept, []byte("done\n"), 0o644) }
+	if err := inst.upgrades().runTransactionStep(step); err != nil {
+		t.Fatalf("step: %v", err)
+	}
+	if data, _ := os.ReadFile(kept); string(data) != "done\n" { // #nosec G304 -- path is constructed from test-controlled inputs.
+		t.Fatalf("successful step must commit, got %q", data)
+	}
+}
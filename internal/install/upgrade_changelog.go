@@ -0,0 +1,201 @@
+package install
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"regexp"
+	"strings"
+
+	agentlayer "github.com/conn-castle/agent-layer"
+	"github.com/conn-castle/agent-layer/internal/messages"
+	"github.com/conn-castle/agent-layer/internal/version"
+)
+
+// upgradeChangelogHighlightLimit caps the non-breaking items shown per release.
+const upgradeChangelogHighlightLimit = 3
+
+// upgradeChangelogSource is the embedded CHANGELOG.md. Tests replace it.
+var upgradeChangelogSource = agentlayer.Changelog
+
+var (
+	changelogReleasePattern = regexp.MustCompile(`^## v?(\d+\.\d+\.\d+)(?:\s+-\s+(\S+))?`)
+	changelogBreakingPrefix = "**Breaking:**"
+)
+
+// changelogHighlightSections are the changelog subsections whose items count
+// as user-facing highlights. Fixed, Documentation, and Internal items are
+// only counted as omitted.
+var changelogHighlightSections = map[string]bool{
+	"added":    true,
+	"changed":  true,
+	"removed":  true,
+	"security": true,
+}
+
+// UpgradeChangelog condenses the release notes between the source and target
+// versions of an upgrade.
+type UpgradeChangelog struct {
+	SourceVersion string                    `json:"source_version"`
+	TargetVersion string                    `json:"target_version"`
+	Releases      []UpgradeChangelogRelease `json:"releases"`
+	// BreakingMigrations are planned breaking migrations, listed so each
+	// breaking release note is tied to the change the upgrade makes for it.
+	BreakingMigrations []UpgradeChangelogMigration `json:"breaking_migrations,omitempty"`
+}
+
+// UpgradeChangelogRelease is the condensed notes for one release.
+type UpgradeChangelogRelease struct {
+	Version    string   `json:"version"`
+	Date       string   `json:"date,omitempty"`
+	Breaking   []string `json:"breaking,omitempty"`
+	Highlights []string `json:"highlights,omitempty"`
+	// Omitted counts items left out of Highlights.
+	Omitted int `json:"omitted,omitempty"`
+}
+
+// UpgradeChangelogMigration is a planned breaking migration.
+type UpgradeChangelogMigration struct {
+	ID     string `json:"id"`
+	Notice string `json:"notice"`
+}
+
+// buildUpgradeChangelog condenses the embedded changelog for the versions
+// after report.SourceVersion up to and including report.TargetVersion. An
+// unknown source shows only the target release. It returns nil when there is
+// no target, the source is not older than the target, or no release notes
+// fall in range.
+func buildUpgradeChangelog(report UpgradeMigrationReport) *UpgradeChangelog {
+	target := strings.TrimSpace(report.TargetVersion)
+	if _, err := version.Parse(target); err != nil {
+		return nil
+	}
+	source := strings.TrimSpace(report.SourceVersion)
+	if _, err := version.Parse(source); err != nil {
+		source = ""
+	}
+	changelog := &UpgradeChangelog{SourceVersion: source, TargetVersion: target}
+	for _, release := range parseChangelog(upgradeChangelogSource) {
+		toTarget, _ := version.Compare(release.Version, target)
+		fromSource := 1
+		if source != "" {
+			fromSource, _ = version.Compare(release.Version, source)
+		} else if toTarget != 0 {
+			continue
+		}
+		if toTarget <= 0 && fromSource > 0 {
+			changelog.Releases = append(changelog.Releases, release)
+		}
+	}
+	if len(changelog.Releases) == 0 {
+		return nil
+	}
+	for _, entry := range report.Entries {
+		if entry.Breaking && entry.Status == UpgradeMigrationStatusPlanned {
+			changelog.BreakingMigrations = append(changelog.BreakingMigrations, UpgradeChangelogMigration{ID: entry.ID, Notice: entry.BreakingNotice})
+		}
+	}
+	return changelog
+}
+
+// parseChangelog reads "## vX.Y.Z - DATE" releases and their "### Section"
+// bullet items, newest first as written. Wrapped bullet lines are joined.
+func parseChangelog(data []byte) []UpgradeChangelogRelease {
+	var releases []UpgradeChangelogRelease
+	var current *UpgradeChangelogRelease
+	section := ""
+	var item strings.Builder
+	flush := func() {
+		text := strings.TrimSpace(item.String())
+		item.Reset()
+		if current == nil || text == "" {
+			return
+		}
+		switch {
+		case strings.HasPrefix(text, changelogBreakingPrefix):
+			current.Breaking = append(current.Breaking, strings.TrimSpace(strings.TrimPrefix(text, changelogBreakingPrefix)))
+		case changelogHighlightSections[section] && len(current.Highlights) < upgradeChangelogHighlightLimit:
+			current.Highlights = append(current.Highlights, firstSentence(text))
+		default:
+			current.Omitted++
+		}
+	}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "## "):
+			flush()
+			if current != nil {
+				releases = append(releases, *current)
+				current = nil
+			}
+			if match := changelogReleasePattern.FindStringSubmatch(line); match != nil {
+				current = &UpgradeChangelogRelease{Version: match[1], Date: match[2]}
+			}
+			section = ""
+		case strings.HasPrefix(line, "### "):
+			flush()
+			section = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(line, "### ")))
+		case strings.HasPrefix(line, "- "):
+			flush()
+			item.WriteString(strings.TrimPrefix(line, "- "))
+		case item.Len() > 0 && strings.HasPrefix(line, "  "):
+			item.WriteString(" ")
+			item.WriteString(strings.TrimSpace(line))
+		default:
+			flush()
+		}
+	}
+	flush()
+	if current != nil {
+		releases = append(releases, *current)
+	}
+	return releases
+}
+
+// firstSentence shortens a changelog item to its first sentence.
+func firstSentence(text string) string {
+	if idx := strings.Index(text, ". "); idx >= 0 {
+		return text[:idx+1]
+	}
+	return text
+}
+
+// WriteUpgradeChangelog renders the condensed "what's changing for you"
+// summary. A nil changelog writes nothing.
+func WriteUpgradeChangelog(out io.Writer, changelog *UpgradeChangelog) error {
+	if changelog == nil {
+		return nil
+	}
+	ew := &errWriter{w: out}
+	if changelog.SourceVersion == "" {
+		ew.printf(messages.InstallUpgradeChangelogHeaderTargetFmt, changelog.TargetVersion)
+	} else {
+		ew.printf(messages.InstallUpgradeChangelogHeaderFmt, changelog.SourceVersion, changelog.TargetVersion)
+	}
+	for _, release := range changelog.Releases {
+		if release.Date == "" {
+			ew.printf(messages.InstallUpgradeChangelogReleaseFmt, release.Version)
+		} else {
+			ew.printf(messages.InstallUpgradeChangelogReleaseDateFmt, release.Version, release.Date)
+		}
+		for _, breaking := range release.Breaking {
+			ew.printf(messages.InstallUpgradeChangelogBreakingFmt, breaking)
+		}
+		for _, highlight := range release.Highlights {
+			ew.printf(messages.InstallUpgradeChangelogItemFmt, highlight)
+		}
+		if release.Omitted > 0 {
+			ew.printf(messages.InstallUpgradeChangelogOmittedFmt, release.Omitted)
+		}
+	}
+	if len(changelog.BreakingMigrations) > 0 {
+		ew.println(messages.InstallUpgradeChangelogMigrationsHeader)
+		for _, migration := range changelog.BreakingMigrations {
+			ew.printf(messages.InstallUpgradeChangelogMigrationFmt, migration.ID, migration.Notice)
+		}
+	}
+	ew.println()
+	return ew.err
+}
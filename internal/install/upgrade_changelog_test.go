@@ -0,0 +1,165 @@
+package install
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+const testUpgradeChangelog = `# Changelog
+
+## v0.3.0 - 2026-03-01
+
+Third release.
+
+### Added
+- First feature. With detail.
+- Second feature
+  wrapped onto two lines.
+- Third feature.
+- Fourth feature.
+
+### Changed
+- **Breaking:** ` + "`old`" + ` is removed; use ` + "`new`" + `.
+
+### Internal
+- Refactor.
+
+## v0.2.0 - 2026-02-01
+
+### Fixed
+- A fix.
+
+## v0.1.0 - 2026-01-01
+
+### Added
+- Initial release.
+`
+
+func stubUpgradeChangelog(t *testing.T, data string) {
+	t.Helper()
+	orig := upgradeChangelogSource
+	upgradeChangelogSource = []byte(data)
+	t.Cleanup(func() { upgradeChangelogSource = orig })
+}
+
+func TestParseChangelog(t *testing.T) {
+	releases := parseChangelog([]byte(testUpgradeChangelog))
+	if len(releases) != 3 {
+		t.Fatalf("expected 3 releases, got %d: %#v", len(releases), releases)
+	}
+	latest := releases[0]
+	if latest.Version != "0.3.0" || latest.Date != "2026-03-01" {
+		t.Fatalf("unexpected release header: %#v", latest)
+	}
+	wantHighlights := []string{"First feature.", "Second feature wrapped onto two lines.", "Third feature."}
+	if strings.Join(latest.Highlights, "|") != strings.Join(wantHighlights, "|") {
+		t.Fatalf("highlights = %q, want %q", latest.Highlights, wantHighlights)
+	}
+	if len(latest.Breaking) != 1 || latest.Breaking[0] != "`old` is removed; use `new`." {
+		t.Fatalf("breaking = %q", latest.Breaking)
+	}
+	// Fourth feature (over the limit) and the Internal item.
+	if latest.Omitted != 2 {
+		t.Fatalf("omitted = %d, want 2", latest.Omitted)
+	}
+	if releases[1].Omitted != 1 || len(releases[1].Highlights) != 0 {
+		t.Fatalf("fixed-only release should be all omitted: %#v", releases[1])
+	}
+}
+
+func TestBuildUpgradeChangelogRange(t *testing.T) {
+	stubUpgradeChangelog(t, testUpgradeChangelog)
+	report := UpgradeMigrationReport{
+		SourceVersion: "0.1.0",
+		TargetVersion: "0.3.0",
+		Entries: []UpgradeMigrationEntry{
+			{ID: "remove-old", Status: UpgradeMigrationStatusPlanned, Breaking: true, BreakingNotice: "old is removed"},
+			{ID: "skipped", Status: UpgradeMigrationStatusSkippedUnknownSource, Breaking: true, BreakingNotice: "n/a"},
+			{ID: "quiet", Status: UpgradeMigrationStatusPlanned},
+		},
+	}
+	changelog := buildUpgradeChangelog(report)
+	if changelog == nil {
+		t.Fatal("expected changelog")
+	}
+	var versions []string
+	for _, release := range changelog.Releases {
+		versions = append(versions, release.Version)
+	}
+	if strings.Join(versions, ",") != "0.3.0,0.2.0" {
+		t.Fatalf("versions = %v", versions)
+	}
+	if len(changelog.BreakingMigrations) != 1 || changelog.BreakingMigrations[0].ID != "remove-old" {
+		t.Fatalf("breaking migrations = %#v", changelog.BreakingMigrations)
+	}
+}
+
+func TestBuildUpgradeChangelogUnknownSourceShowsTargetOnly(t *testing.T) {
+	stubUpgradeChangelog(t, testUpgradeChangelog)
+	changelog := buildUpgradeChangelog(UpgradeMigrationReport{SourceVersion: string(UpgradeMigrationSourceUnknown), TargetVersion: "0.2.0"})
+	if changelog == nil || changelog.SourceVersion != "" || len(changelog.Releases) != 1 || changelog.Releases[0].Version != "0.2.0" {
+		t.Fatalf("unexpected changelog: %#v", changelog)
+	}
+}
+
+func TestBuildUpgradeChangelogNothingInRange(t *testing.T) {
+	stubUpgradeChangelog(t, testUpgradeChangelog)
+	for _, report := range []UpgradeMigrationReport{
+		{SourceVersion: "0.3.0", TargetVersion: "0.3.0"},
+		{SourceVersion: "0.3.0", TargetVersion: "0.2.0"},
+		{SourceVersion: "0.1.0", TargetVersion: ""},
+		{SourceVersion: "unknown", TargetVersion: "0.9.0"},
+	} {
+		if changelog := buildUpgradeChangelog(report); changelog != nil {
+			t.Fatalf("expected nil for %#v, got %#v", report, changelog)
+		}
+	}
+}
+
+func TestWriteUpgradeChangelog(t *testing.T) {
+	stubUpgradeChangelog(t, testUpgradeChangelog)
+	changelog := buildUpgradeChangelog(UpgradeMigrationReport{
+		SourceVersion: "0.2.0",
+		TargetVersion: "0.3.0",
+		Entries:       []UpgradeMigrationEntry{{ID: "remove-old", Status: UpgradeMigrationStatusPlanned, Breaking: true, BreakingNotice: "old is removed"}},
+	})
+	var out bytes.Buffer
+	if err := WriteUpgradeChangelog(&out, changelog); err != nil {
+		t.Fatalf("WriteUpgradeChangelog: %v", err)
+	}
+	got := out.String()
+	for _, want := range []string{
+		"What's changing for you (0.2.0 -> 0.3.0):",
+		"  v0.3.0 (2026-03-01)",
+		"    ! BREAKING: `old` is removed; use `new`.",
+		"    - First feature.",
+		"    ... and 2 more (see CHANGELOG.md)",
+		"  Breaking changes this upgrade migrates for you:",
+		"    - remove-old: old is removed",
+	} {
+		if !strings.Contains(got, want) {
+			t.Fatalf("output missing %q:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "v0.2.0") {
+		t.Fatalf("source release should be excluded:\n%s", got)
+	}
+
+	out.Reset()
+	if err := WriteUpgradeChangelog(&out, nil); err != nil || out.Len() != 0 {
+		t.Fatalf("nil changelog wrote %q, err %v", out.String(), err)
+	}
+}
+
+func TestEmbeddedChangelogParses(t *testing.T) {
+	releases := parseChangelog(upgradeChangelogSource)
+	if len(releases) == 0 {
+		t.Fatal("embedded CHANGELOG.md has no releases")
+	}
+	for _, release := range releases {
+		if release.Version == "" {
+			t.Fatalf("release without version: %#v", release)
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package install
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/conn-castle/agent-layer/internal/i18n"
+	"github.com/conn-castle/agent-layer/internal/messages"
+)
+
+// UpgradeOperationFilter narrows an upgrade to chosen migration operations
+// and template paths. Each entry is a migration operation ID or a
+// repo-relative template path; a directory path matches every template
+// beneath it. Filtered migrations are reported as skipped_by_user and
+// filtered templates keep their current content. Templates that do not exist
+// yet are still added.
+type UpgradeOperationFilter struct {
+	// Only, when set, skips every migration and template update it does not
+	// match.
+	Only []string
+	// Skip skips every migration and template update it matches.
+	Skip []string
+}
+
+// IsZero reports whether the filter lets every operation through.
+func (f UpgradeOperationFilter) IsZero() bool {
+	return len(f.Only) == 0 && len(f.Skip) == 0
+}
+
+// skipReason returns why the filter skips the operation or template path
+// subject, or "" when it runs.
+func (f UpgradeOperationFilter) skipReason(subject string) string {
+	if len(f.Only) > 0 && !upgradeFilterMatches(f.Only, subject) {
+		return messages.InstallUpgradeFilterNotOnly
+	}
+	if upgradeFilterMatches(f.Skip, subject) {
+		return messages.InstallUpgradeFilterSkipped
+	}
+	return ""
+}
+
+// cacheKey folds the filter into the upgrade plan cache key.
+func (f UpgradeOperationFilter) cacheKey() string {
+	return "only=" + strings.Join(f.Only, ",") + "\x00skip=" + strings.Join(f.Skip, ",")
+}
+
+func upgradeFilterMatches(entries []string, subject string) bool {
+	subject = normalizeRelPath(subject)
+	for _, entry := range entries {
+		entry = normalizeUpgradeFilterEntry(entry)
+		if subject == entry || strings.HasPrefix(subject, entry+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+func normalizeUpgradeFilterEntry(entry string) string {
+	return path.Clean(normalizeRelPath(strings.TrimSpace(entry)))
+}
+
+// validateOperationFilter rejects entries that match no embedded migration
+// operation and no template path, so a typo cannot silently filter nothing.
+func (inst *installer) validateOperationFilter() error {
+	if inst.operationFilter.IsZero() {
+		return nil
+	}
+	subjects := make([]string, 0)
+	versions, err := listMigrationManifestVersions()
+	if err != nil {
+		return err
+	}
+	for _, ver := range versions {
+		manifest, _, err := loadUpgradeMigrationManifestByVersion(ver)
+		if err != nil {
+			return err
+		}
+		for _, op := range manifest.Operations {
+			subjects = append(subjects, op.ID)
+		}
+	}
+	entries, err := inst.templates().currentTemplateEntries()
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		subjects = append(subjects, entry.relPath)
+	}
+	for _, source := range StatuslineSourceTemplates() {
+		subjects = append(subjects, source.RelPath)
+	}
+	for _, entry := range append(append([]string(nil), inst.operationFilter.Only...), inst.operationFilter.Skip...) {
+		if strings.TrimSpace(entry) == "" {
+			return i18n.Errorf(messages.InstallUpgradeFilterUnknownFmt, entry)
+		}
+		matched := false
+		for _, subject := range subjects {
+			if upgradeFilterMatches([]string{entry}, subject) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return i18n.Errorf(messages.InstallUpgradeFilterUnknownFmt, entry)
+		}
+	}
+	return nil
+}
+
+// filteredTemplatePath reports whether the filter keeps the template at
+// absPath from being overwritten, noting the skip on the output.
+func (inst *installer) filteredTemplatePath(absPath string) bool {
+	rel := filepath.ToSlash(inst.relativePath(absPath))
+	reason := inst.operationFilter.skipReason(rel)
+	if reason == "" {
+		return false
+	}
+	_, _ = fmt.Fprintf(inst.warnOutput(), i18n.T(messages.InstallUpgradeFilterSkippedPathFmt), rel, reason)
+	return true
+}
+
+// filterUpgradeChanges drops changes the filter skips and returns their paths.
+func (f UpgradeOperationFilter) filterUpgradeChanges(changes []upgradeChangeWithTemplate) ([]upgradeChangeWithTemplate, []string) {
+	if f.IsZero() {
+		return changes, nil
+	}
+	kept := make([]upgradeChangeWithTemplate, 0, len(changes))
+	var skipped []string
+	for _, change := range changes {
+		if f.skipReason(change.path) != "" {
+			skipped = append(skipped, change.path)
+			continue
+		}
+		kept = append(kept, change)
+	}
+	return kept, skipped
+}
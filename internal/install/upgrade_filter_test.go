@@ -0,0 +1,123 @@
+package install
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/conn-castle/agent-layer/internal/output"
+)
+
+const filterTestManifest = `{
+  "schema_version": 1,
+  "target_version": "0.7.0",
+  "min_prior_version": "0.6.0",
+  "operations": [
+    {
+      "id": "rename_managed",
+      "kind": "rename_file",
+      "rationale": "Move managed file",
+      "source_agnostic": true,
+      "from": ".agent-layer/legacy.md",
+      "to": ".agent-layer/new.md"
+    }
+  ]
+}`
+
+// seedFilterTestRepo installs 0.6.0 with a legacy file for the rename
+// migration and a locally edited commands.allow for a template update.
+func seedFilterTestRepo(t *testing.T) string {
+	t.Helper()
+	root := t.TempDir()
+	if err := Run(root, Options{System: RealSystem{}, PinVersion: "0.6.0"}); err != nil {
+		t.Fatalf("seed repo: %v", err)
+	}
+	for rel, content := range map[string]string{
+		".agent-layer/legacy.md":      "legacy\n",
+		".agent-layer/commands.allow": "echo custom\n",
+	} {
+		if err := os.WriteFile(filepath.Join(root, filepath.FromSlash(rel)), []byte(content), 0o600); err != nil {
+			t.Fatalf("write %s: %v", rel, err)
+		}
+	}
+	withMigrationManifestChainOverride(t, map[string]string{"0.7.0": filterTestManifest})
+	return root
+}
+
+func TestBuildUpgradePlan_OperationFilter(t *testing.T) {
+	root := seedFilterTestRepo(t)
+
+	plan, err := BuildUpgradePlan(root, UpgradePlanOptions{
+		TargetPinVersion: "0.7.0",
+		System:           RealSystem{},
+		OperationFilter:  UpgradeOperationFilter{Skip: []string{"rename_managed", ".agent-layer/commands.allow"}},
+	})
+	if err != nil {
+		t.Fatalf("BuildUpgradePlan: %v", err)
+	}
+	if entries := plan.MigrationReport.Entries; len(entries) != 1 || entries[0].Status != UpgradeMigrationStatusSkippedByUser || entries[0].SkipReason == "" {
+		t.Fatalf("migration entries = %+v", entries)
+	}
+	if findUpgradeChange(plan.TemplateUpdates, ".agent-layer/commands.allow") != nil {
+		t.Fatalf("skipped path still planned: %+v", plan.TemplateUpdates)
+	}
+	if got := plan.MigrationReport.SkippedPaths; len(got) != 1 || got[0] != ".agent-layer/commands.allow" {
+		t.Fatalf("SkippedPaths = %v", got)
+	}
+	for _, group := range plan.RiskGroups {
+		for _, item := range group.Items {
+			if item.Migration {
+				t.Fatalf("skipped migration still gates the upgrade: %+v", group)
+			}
+		}
+	}
+
+	plan, err = BuildUpgradePlan(root, UpgradePlanOptions{
+		TargetPinVersion: "0.7.0",
+		System:           RealSystem{},
+		OperationFilter:  UpgradeOperationFilter{Only: []string{".agent-layer"}},
+	})
+	if err != nil {
+		t.Fatalf("BuildUpgradePlan --only: %v", err)
+	}
+	if plan.MigrationReport.Entries[0].Status != UpgradeMigrationStatusSkippedByUser || findUpgradeChange(plan.TemplateUpdates, ".agent-layer/commands.allow") == nil {
+		t.Fatalf("--only .agent-layer must keep template updates and skip migrations: %+v", plan.MigrationReport.Entries)
+	}
+
+	if _, err := BuildUpgradePlan(root, UpgradePlanOptions{
+		TargetPinVersion: "0.7.0",
+		System:           RealSystem{},
+		OperationFilter:  UpgradeOperationFilter{Skip: []string{"rename_managd"}},
+	}); err == nil || !strings.Contains(err.Error(), "rename_managd") {
+		t.Fatalf("typo in --skip = %v", err)
+	}
+}
+
+func TestRun_OperationFilterSkipsMigrationAndTemplate(t *testing.T) {
+	root := seedFilterTestRepo(t)
+
+	var warn bytes.Buffer
+	err := Run(root, Options{
+		Overwrite:       true,
+		Prompter:        autoApprovePrompter(),
+		PinVersion:      "0.7.0",
+		System:          RealSystem{},
+		Output:          output.New(&warn, &warn, output.Normal),
+		OperationFilter: UpgradeOperationFilter{Skip: []string{"rename_managed", ".agent-layer/commands.allow"}},
+	})
+	if err != nil {
+		t.Fatalf("upgrade: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(root, ".agent-layer", "new.md")); !os.IsNotExist(err) {
+		t.Fatalf("skipped rename ran, stat err %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(root, ".agent-layer", "commands.allow"))
+	if err != nil || string(data) != "echo custom\n" {
+		t.Fatalf("skipped template was overwritten: %q (%v)", data, err)
+	}
+	if !containsAll(warn.String(), "[skipped_by_user] rename_managed", "Skipped template update .agent-layer/commands.allow") {
+		t.Fatalf("report does not record the skips:\n%s", warn.String())
+	}
+}
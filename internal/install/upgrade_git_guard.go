@@ -0,0 +1,146 @@
+package install
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/conn-castle/agent-layer/internal/i18n"
+	"github.com/conn-castle/agent-layer/internal/layerdir"
+	"github.com/conn-castle/agent-layer/internal/messages"
+	"github.com/conn-castle/agent-layer/internal/templates"
+)
+
+// gitCommandFunc runs git in root and returns its stdout. ok is false when
+// git is not installed. Tests replace it to avoid depending on git.
+var gitCommandFunc = func(root string, args ...string) ([]byte, bool, error) {
+	if _, err := exec.LookPath("git"); err != nil {
+		return nil, false, nil
+	}
+	cmd := exec.Command("git", append([]string{"-C", root}, args...)...) // #nosec G204 -- fixed git subcommands; pathspecs are passed as separate arguments after "--".
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, true, fmt.Errorf("%w: %s", err, msg)
+		}
+		return nil, true, err
+	}
+	return out, true, nil
+}
+
+// UpgradeGitStatus describes the uncommitted changes an upgrade could mix
+// its rewrites into.
+type UpgradeGitStatus struct {
+	// InWorkTree is false when root is not inside a git work tree or git is
+	// not installed; the guard does not apply then.
+	InWorkTree bool
+	// Dirty lists the paths, relative to the top of the work tree, that are
+	// modified, staged, or untracked under the paths an upgrade may rewrite.
+	Dirty []string
+}
+
+// CheckUpgradeGitStatus reports uncommitted changes under .agent-layer/,
+// .gitignore, and the generated paths listed in the template gitignore
+// block. A configuration directory redirected outside the repo is not checked.
+func CheckUpgradeGitStatus(root string) (UpgradeGitStatus, error) {
+	out, ok, err := gitCommandFunc(root, "rev-parse", "--is-inside-work-tree")
+	if !ok || err != nil || strings.TrimSpace(string(out)) != "true" {
+		return UpgradeGitStatus{}, nil
+	}
+	pathspecs, err := upgradeGitPathspecs(root)
+	if err != nil {
+		return UpgradeGitStatus{}, err
+	}
+	out, _, err = gitCommandFunc(root, append([]string{"status", "--porcelain", "-z", "--untracked-files=all", "--"}, pathspecs...)...)
+	if err != nil {
+		return UpgradeGitStatus{}, i18n.Errorf(messages.InstallGitStatusFailedFmt, err)
+	}
+	return UpgradeGitStatus{InWorkTree: true, Dirty: parseGitStatusPaths(out)}, nil
+}
+
+// parseGitStatusPaths returns the paths in `git status --porcelain -z`
+// output. A rename or copy contributes both its new and original path.
+func parseGitStatusPaths(out []byte) []string {
+	var paths []string
+	records := strings.Split(string(out), "\x00")
+	for i := 0; i < len(records); i++ {
+		record := records[i]
+		if len(record) < 4 {
+			continue
+		}
+		paths = append(paths, record[3:])
+		if (record[0] == 'R' || record[0] == 'C') && i+1 < len(records) {
+			i++
+			paths = append(paths, records[i])
+		}
+	}
+	return paths
+}
+
+// StashUpgradeChanges stashes the dirty paths, untracked files included, so
+// the upgrade starts from committed state. `git stash pop` restores them.
+func StashUpgradeChanges(root string, status UpgradeGitStatus, message string) error {
+	args := append([]string{"stash", "push", "--include-untracked", "--message", message, "--"}, topPathspecs(status.Dirty)...)
+	if _, _, err := gitCommandFunc(root, args...); err != nil {
+		return i18n.Errorf(messages.InstallGitStashFailedFmt, err)
+	}
+	return nil
+}
+
+// CommitUpgradeCheckpoint commits the dirty paths, untracked files included,
+// so the upgrade's rewrites show up as a separate diff.
+func CommitUpgradeCheckpoint(root string, status UpgradeGitStatus, message string) error {
+	pathspecs := topPathspecs(status.Dirty)
+	if _, _, err := gitCommandFunc(root, append([]string{"add", "--all", "--"}, pathspecs...)...); err != nil {
+		return i18n.Errorf(messages.InstallGitCommitFailedFmt, err)
+	}
+	args := append([]string{"commit", "--no-verify", "--message", message, "--"}, pathspecs...)
+	if _, _, err := gitCommandFunc(root, args...); err != nil {
+		return i18n.Errorf(messages.InstallGitCommitFailedFmt, err)
+	}
+	return nil
+}
+
+// topPathspecs turns paths from git status, which are relative to the top of
+// the work tree, into literal pathspecs that work from any subdirectory.
+func topPathspecs(paths []string) []string {
+	out := make([]string, 0, len(paths))
+	for _, path := range paths {
+		out = append(out, ":(top,literal)"+path)
+	}
+	return out
+}
+
+// upgradeGitPathspecs returns the paths an upgrade and its follow-up sync may
+// rewrite, as pathspecs relative to root. Generated paths come from the
+// template gitignore block, including its commented-out optional entries.
+func upgradeGitPathspecs(root string) ([]string, error) {
+	block, err := templates.Read("gitignore.block")
+	if err != nil {
+		return nil, i18n.Errorf(messages.InstallFailedReadTemplateFmt, "gitignore.block", err)
+	}
+	seen := map[string]bool{".gitignore": true, layerdir.Name: true}
+	if rel, err := filepath.Rel(root, layerdir.Dir(root)); err == nil && rel != "." && !strings.HasPrefix(rel, "..") {
+		seen[filepath.ToSlash(rel)] = true
+	}
+	for _, line := range strings.Split(string(block), "\n") {
+		line = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(line), "#"))
+		if !strings.HasPrefix(line, "/") {
+			continue
+		}
+		if path := strings.TrimSuffix(strings.TrimPrefix(line, "/"), "/"); path != "" {
+			seen[path] = true
+		}
+	}
+	pathspecs := make([]string, 0, len(seen))
+	for path := range seen {
+		pathspecs = append(pathspecs, path)
+	}
+	sort.Strings(pathspecs)
+	return pathspecs, nil
+}
@@ -0,0 +1,112 @@
+package install
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func gitGuardRepo(t *testing.T) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	for key, value := range map[string]string{
+		"GIT_AUTHOR_NAME": "t", "GIT_AUTHOR_EMAIL": "t@example.com",
+		"GIT_COMMITTER_NAME": "t", "GIT_COMMITTER_EMAIL": "t@example.com",
+	} {
+		t.Setenv(key, value)
+	}
+	root := t.TempDir()
+	writeGuardFile(t, root, ".agent-layer/config.toml", "base\n")
+	writeGuardFile(t, root, "src/main.go", "package main\n")
+	for _, args := range [][]string{{"init", "-q"}, {"add", "-A"}, {"commit", "-q", "-m", "init"}} {
+		if _, _, err := gitCommandFunc(root, args...); err != nil {
+			t.Fatalf("git %v: %v", args, err)
+		}
+	}
+	return root
+}
+
+func writeGuardFile(t *testing.T, root string, rel string, content string) {
+	t.Helper()
+	path := filepath.Join(root, filepath.FromSlash(rel))
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("write %s: %v", rel, err)
+	}
+}
+
+func TestCheckUpgradeGitStatus_OnlyUpgradePaths(t *testing.T) {
+	root := gitGuardRepo(t)
+	status, err := CheckUpgradeGitStatus(root)
+	if err != nil || !status.InWorkTree || len(status.Dirty) != 0 {
+		t.Fatalf("clean repo = %+v (%v)", status, err)
+	}
+
+	writeGuardFile(t, root, "src/main.go", "package main // edited\n")
+	writeGuardFile(t, root, ".agent-layer/config.toml", "edited\n")
+	writeGuardFile(t, root, "AGENTS.md", "untracked\n")
+	status, err = CheckUpgradeGitStatus(root)
+	if err != nil {
+		t.Fatalf("CheckUpgradeGitStatus: %v", err)
+	}
+	if want := []string{".agent-layer/config.toml", "AGENTS.md"}; !reflect.DeepEqual(status.Dirty, want) {
+		t.Fatalf("dirty = %v, want %v", status.Dirty, want)
+	}
+
+	if err := StashUpgradeChanges(root, status, "before upgrade"); err != nil {
+		t.Fatalf("StashUpgradeChanges: %v", err)
+	}
+	status, err = CheckUpgradeGitStatus(root)
+	if err != nil || len(status.Dirty) != 0 {
+		t.Fatalf("after stash = %+v (%v)", status, err)
+	}
+	if data, _ := os.ReadFile(filepath.Join(root, "src", "main.go")); !strings.Contains(string(data), "edited") {
+		t.Fatalf("stash must leave unrelated edits alone, got %q", data)
+	}
+}
+
+func TestCommitUpgradeCheckpoint(t *testing.T) {
+	root := gitGuardRepo(t)
+	writeGuardFile(t, root, ".agent-layer/instructions/00_base.md", "new\n")
+	writeGuardFile(t, root, "src/main.go", "package main // edited\n")
+	status, err := CheckUpgradeGitStatus(root)
+	if err != nil {
+		t.Fatalf("CheckUpgradeGitStatus: %v", err)
+	}
+
+	if err := CommitUpgradeCheckpoint(root, status, "checkpoint"); err != nil {
+		t.Fatalf("CommitUpgradeCheckpoint: %v", err)
+	}
+	out, _, err := gitCommandFunc(root, "status", "--porcelain")
+	if err != nil {
+		t.Fatalf("git status: %v", err)
+	}
+	if got := strings.TrimSpace(string(out)); got != "M src/main.go" {
+		t.Fatalf("checkpoint must commit only upgrade paths, status = %q", got)
+	}
+}
+
+func TestCheckUpgradeGitStatus_OutsideGit(t *testing.T) {
+	orig := gitCommandFunc
+	t.Cleanup(func() { gitCommandFunc = orig })
+	gitCommandFunc = func(string, ...string) ([]byte, bool, error) { return nil, false, nil }
+
+	status, err := CheckUpgradeGitStatus(t.TempDir())
+	if err != nil || status.InWorkTree {
+		t.Fatalf("without git = %+v (%v)", status, err)
+	}
+}
+
+func TestParseGitStatusPaths(t *testing.T) {
+	got := parseGitStatusPaths([]byte("R  new.md\x00old.md\x00?? a b.md\x00"))
+	if want := []string{"new.md", "old.md", "a b.md"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("paths = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,262 @@
+package install
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/conn-castle/agent-layer/internal/clock"
+	"github.com/conn-castle/agent-layer/internal/i18n"
+	"github.com/conn-castle/agent-layer/internal/messages"
+)
+
+const (
+	upgradeHistorySchemaVersion = 1
+	upgradeHistoryDirRelPath    = ".agent-layer/state/upgrade-history"
+	// upgradeHistoryMaxRetained is larger than upgradeSnapshotMaxRetained:
+	// records hold no file contents, and the point of keeping them is to
+	// explain a regression noticed long after the upgrade.
+	upgradeHistoryMaxRetained = 200
+)
+
+// UpgradeHistoryRecord describes one applied upgrade. Its ID is the ID of the
+// snapshot the upgrade captured, so `al upgrade rollback <id>` undoes the
+// upgrade `al upgrade show <id>` describes while the snapshot is retained.
+type UpgradeHistoryRecord struct {
+	SchemaVersion   int                    `json:"schema_version"`
+	ID              string                 `json:"id"`
+	CreatedAtUTC    string                 `json:"created_at_utc"`
+	MigrationReport UpgradeMigrationReport `json:"migration_report"`
+	TemplateChanges UpgradeTemplateChanges `json:"template_changes"`
+}
+
+// UpgradeTemplateChanges lists the repo-relative paths an upgrade added,
+// updated, or removed, each sorted.
+type UpgradeTemplateChanges struct {
+	Added   []string `json:"added"`
+	Updated []string `json:"updated"`
+	Removed []string `json:"removed"`
+}
+
+// recordUpgradeHistory writes the history record for an applied upgrade by
+// comparing the pre-upgrade snapshot with the files now on disk. The upgrade
+// has already succeeded, so failures only warn.
+func (inst *installer) recordUpgradeHistory(snapshot upgradeSnapshot) {
+	if err := inst.writeUpgradeHistory(snapshot); err != nil {
+		_, _ = fmt.Fprintf(inst.warnOutput(), i18n.T(messages.InstallUpgradeHistoryWriteWarningFmt), err)
+	}
+}
+
+func (inst *installer) writeUpgradeHistory(snapshot upgradeSnapshot) error {
+	after, err := inst.captureUpgradeEntries(inst.upgradeSnapshotTargetPaths(), func() {})
+	if err != nil {
+		return err
+	}
+	record := UpgradeHistoryRecord{
+		SchemaVersion:   upgradeHistorySchemaVersion,
+		ID:              snapshot.SnapshotID,
+		CreatedAtUTC:    snapshot.CreatedAtUTC,
+		MigrationReport: inst.migrationReport,
+		TemplateChanges: diffUpgradeEntries(snapshot.Entries, after),
+	}
+	if err := inst.pruneUpgradeHistory(upgradeHistoryMaxRetained - 1); err != nil {
+		return err
+	}
+	dir := upgradeHistoryDirPath(inst.root)
+	if err := inst.sys.MkdirAll(dir, 0o755); err != nil {
+		return i18n.Errorf(messages.InstallFailedCreateDirForFmt, dir, err)
+	}
+	data, err := json.MarshalIndent(record, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal upgrade history: %w", err)
+	}
+	data = append(data, '\n')
+	path := filepath.Join(dir, record.ID+".json")
+	if err := inst.sys.WriteFileAtomic(path, data, 0o644); err != nil {
+		return i18n.Errorf(messages.InstallFailedWriteFmt, path, err)
+	}
+	return nil
+}
+
+// diffUpgradeEntries classifies file and symlink paths that differ between
+// two captures. Directories are ignored; their contents are listed instead.
+func diffUpgradeEntries(before []upgradeSnapshotEntry, after []upgradeSnapshotEntry) UpgradeTemplateChanges {
+	present := func(entry upgradeSnapshotEntry) bool {
+		return entry.Kind == upgradeSnapshotEntryKindFile || entry.Kind == upgradeSnapshotEntryKindSymlink
+	}
+	beforeByPath := make(map[string]upgradeSnapshotEntry, len(before))
+	for _, entry := range before {
+		if present(entry) {
+			beforeByPath[entry.Path] = entry
+		}
+	}
+	changes := UpgradeTemplateChanges{Added: []string{}, Updated: []string{}, Removed: []string{}}
+	for _, entry := range after {
+		if !present(entry) {
+			continue
+		}
+		old, ok := beforeByPath[entry.Path]
+		delete(beforeByPath, entry.Path)
+		switch {
+		case !ok:
+			changes.Added = append(changes.Added, entry.Path)
+		case old.Kind != entry.Kind || old.ContentBase64 != entry.ContentBase64 || old.LinkTarget != entry.LinkTarget:
+			changes.Updated = append(changes.Updated, entry.Path)
+		}
+	}
+	for path := range beforeByPath {
+		changes.Removed = append(changes.Removed, path)
+	}
+	sort.Strings(changes.Added)
+	sort.Strings(changes.Updated)
+	sort.Strings(changes.Removed)
+	return changes
+}
+
+func (inst *installer) pruneUpgradeHistory(retain int) error {
+	records, err := listUpgradeHistoryFiles(inst.root, inst.sys)
+	if err != nil {
+		return err
+	}
+	for i := 0; i < len(records)-retain; i++ {
+		if err := inst.sys.RemoveAll(records[i].path); err != nil {
+			return fmt.Errorf("delete old upgrade history %s: %w", records[i].path, err)
+		}
+	}
+	return nil
+}
+
+// ListUpgradeHistory returns the recorded upgrades, newest first. Unreadable
+// records are skipped.
+func ListUpgradeHistory(root string, sys System) ([]UpgradeHistoryRecord, error) {
+	if strings.TrimSpace(root) == "" {
+		return nil, i18n.Errorf(messages.InstallRootRequired)
+	}
+	if sys == nil {
+		return nil, i18n.Errorf(messages.InstallSystemRequired)
+	}
+	sys, err := layerSystem(root, sys)
+	if err != nil {
+		return nil, err
+	}
+	files, err := listUpgradeHistoryFiles(root, sys)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]UpgradeHistoryRecord, 0, len(files))
+	for i := len(files) - 1; i >= 0; i-- {
+		out = append(out, files[i].record)
+	}
+	return out, nil
+}
+
+// ReadUpgradeHistory returns the history record with the given ID.
+func ReadUpgradeHistory(root string, id string, sys System) (UpgradeHistoryRecord, error) {
+	if strings.TrimSpace(root) == "" {
+		return UpgradeHistoryRecord{}, i18n.Errorf(messages.InstallRootRequired)
+	}
+	id = strings.TrimSpace(id)
+	if id == "" {
+		return UpgradeHistoryRecord{}, i18n.Errorf(messages.InstallUpgradeHistoryIDRequired)
+	}
+	// Reject path traversal: id must be a bare filename component.
+	if filepath.Base(id) != id {
+		return UpgradeHistoryRecord{}, i18n.Errorf(messages.InstallUpgradeHistoryIDInvalidFmt, id)
+	}
+	if sys == nil {
+		return UpgradeHistoryRecord{}, i18n.Errorf(messages.InstallSystemRequired)
+	}
+	sys, err := layerSystem(root, sys)
+	if err != nil {
+		return UpgradeHistoryRecord{}, err
+	}
+	dir := upgradeHistoryDirPath(root)
+	path := filepath.Join(dir, id+".json")
+	if _, err := sys.Stat(path); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return UpgradeHistoryRecord{}, i18n.Errorf(messages.InstallUpgradeHistoryNotFoundFmt, id, dir)
+		}
+		return UpgradeHistoryRecord{}, i18n.Errorf(messages.InstallFailedStatFmt, path, err)
+	}
+	return readUpgradeHistoryFile(path, sys)
+}
+
+type upgradeHistoryFile struct {
+	path      string
+	createdAt time.Time
+	record    UpgradeHistoryRecord
+}
+
+// listUpgradeHistoryFiles returns the readable history records, oldest first.
+func listUpgradeHistoryFiles(root string, sys System) ([]upgradeHistoryFile, error) {
+	dir := upgradeHistoryDirPath(root)
+	if _, err := sys.Stat(dir); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, i18n.Errorf(messages.InstallFailedStatFmt, dir, err)
+	}
+	files := make([]upgradeHistoryFile, 0)
+	if err := sys.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			if path != dir {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.HasSuffix(path, ".json") {
+			return nil
+		}
+		record, err := readUpgradeHistoryFile(path, sys)
+		if err != nil {
+			// A malformed record must not hide the rest of the history.
+			return nil
+		}
+		createdAt, err := clock.Parse(record.CreatedAtUTC)
+		if err != nil {
+			return nil
+		}
+		files = append(files, upgradeHistoryFile{path: path, createdAt: createdAt, record: record})
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	sort.Slice(files, func(i, j int) bool {
+		if files[i].createdAt.Equal(files[j].createdAt) {
+			return files[i].record.ID < files[j].record.ID
+		}
+		return files[i].createdAt.Before(files[j].createdAt)
+	})
+	return files, nil
+}
+
+func readUpgradeHistoryFile(path string, sys System) (UpgradeHistoryRecord, error) {
+	data, err := sys.ReadFile(path)
+	if err != nil {
+		return UpgradeHistoryRecord{}, i18n.Errorf(messages.InstallFailedReadFmt, path, err)
+	}
+	var record UpgradeHistoryRecord
+	if err := json.Unmarshal(data, &record); err != nil {
+		return UpgradeHistoryRecord{}, fmt.Errorf("decode upgrade history %s: %w", path, err)
+	}
+	if record.SchemaVersion != upgradeHistorySchemaVersion {
+		return UpgradeHistoryRecord{}, fmt.Errorf("decode upgrade history %s: unsupported schema_version %d", path, record.SchemaVersion)
+	}
+	if strings.TrimSpace(record.ID) == "" {
+		return UpgradeHistoryRecord{}, fmt.Errorf("decode upgrade history %s: id is required", path)
+	}
+	return record, nil
+}
+
+func upgradeHistoryDirPath(root string) string {
+	return filepath.Join(root, filepath.FromSlash(upgradeHistoryDirRelPath))
+}
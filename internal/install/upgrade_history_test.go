@@ -0,0 +1,166 @@
+package install
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/conn-castle/agent-layer/internal/messages"
+)
+
+func TestRunWithOverwrite_WritesUpgradeHistory(t *testing.T) {
+	root := t.TempDir()
+	if err := Run(root, Options{System: RealSystem{}, PinVersion: "0.5.0"}); err != nil {
+		t.Fatalf("seed repo: %v", err)
+	}
+	if err := Run(root, Options{System: RealSystem{}, Overwrite: true, Prompter: autoApprovePrompter(), PinVersion: "0.6.0"}); err != nil {
+		t.Fatalf("overwrite run: %v", err)
+	}
+
+	records, err := ListUpgradeHistory(root, RealSystem{})
+	if err != nil {
+		t.Fatalf("ListUpgradeHistory: %v", err)
+	}
+	if len(records) != 1 {
+		t.Fatalf("history records = %d, want 1", len(records))
+	}
+	snapshot := latestSnapshot(t, root)
+	record := records[0]
+	if record.ID != snapshot.SnapshotID {
+		t.Fatalf("history id = %q, want snapshot id %q", record.ID, snapshot.SnapshotID)
+	}
+	if record.MigrationReport.TargetVersion != "0.6.0" {
+		t.Fatalf("target version = %q, want 0.6.0", record.MigrationReport.TargetVersion)
+	}
+	found := false
+	for _, path := range record.TemplateChanges.Updated {
+		if path == ".agent-layer/al.version" {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatalf("updated paths %v missing .agent-layer/al.version", record.TemplateChanges.Updated)
+	}
+
+	read, err := ReadUpgradeHistory(root, record.ID, RealSystem{})
+	if err != nil {
+		t.Fatalf("ReadUpgradeHistory: %v", err)
+	}
+	if !reflect.DeepEqual(read, record) {
+		t.Fatalf("ReadUpgradeHistory = %+v, want %+v", read, record)
+	}
+}
+
+func TestDiffUpgradeEntries(t *testing.T) {
+	before := []upgradeSnapshotEntry{
+		{Path: ".agent-layer", Kind: upgradeSnapshotEntryKindDir},
+		{Path: "a.md", Kind: upgradeSnapshotEntryKindFile, ContentBase64: "YQ=="},
+		{Path: "b.md", Kind: upgradeSnapshotEntryKindFile, ContentBase64: "Yg=="},
+		{Path: "gone.md", Kind: upgradeSnapshotEntryKindFile, ContentBase64: "Zw=="},
+		{Path: "new", Kind: upgradeSnapshotEntryKindAbsent},
+	}
+	after := []upgradeSnapshotEntry{
+		{Path: ".agent-layer", Kind: upgradeSnapshotEntryKindDir},
+		{Path: "a.md", Kind: upgradeSnapshotEntryKindFile, ContentBase64: "YQ=="},
+		{Path: "b.md", Kind: upgradeSnapshotEntryKindFile, ContentBase64: "Yw=="},
+		{Path: "new", Kind: upgradeSnapshotEntryKindDir},
+		{Path: "new/file.md", Kind: upgradeSnapshotEntryKindFile, ContentBase64: "bg=="},
+	}
+	got := diffUpgradeEntries(before, after)
+	want := UpgradeTemplateChanges{
+		Added:   []string{"new/file.md"},
+		Updated: []string{"b.md"},
+		Removed: []string{"gone.md"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("diffUpgradeEntries = %+v, want %+v", got, want)
+	}
+}
+
+func TestListUpgradeHistory_NewestFirstAndSkipsMalformed(t *testing.T) {
+	root := t.TempDir()
+	dir := upgradeHistoryDirPath(root)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	writeHistory := func(name string, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+	}
+	writeHistory("old.json", `{"schema_version":1,"id":"old","created_at_utc":"2026-01-01T00:00:00Z"}`)
+	writeHistory("new.json", `{"schema_version":1,"id":"new","created_at_utc":"2026-02-01T00:00:00Z"}`)
+	writeHistory("bad.json", `{`)
+
+	records, err := ListUpgradeHistory(root, RealSystem{})
+	if err != nil {
+		t.Fatalf("ListUpgradeHistory: %v", err)
+	}
+	if len(records) != 2 || records[0].ID != "new" || records[1].ID != "old" {
+		t.Fatalf("records = %+v, want new then old", records)
+	}
+}
+
+func TestListUpgradeHistory_Empty(t *testing.T) {
+	records, err := ListUpgradeHistory(t.TempDir(), RealSystem{})
+	if err != nil {
+		t.Fatalf("ListUpgradeHistory: %v", err)
+	}
+	if len(records) != 0 {
+		t.Fatalf("records = %+v, want none", records)
+	}
+}
+
+func TestPruneUpgradeHistory(t *testing.T) {
+	root := t.TempDir()
+	dir := upgradeHistoryDirPath(root)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	for i, id := range []string{"a", "b", "c"} {
+		content := fmt.Sprintf(`{"schema_version":1,"id":%q,"created_at_utc":"2026-01-0%dT00:00:00Z"}`, id, i+1)
+		if err := os.WriteFile(filepath.Join(dir, id+".json"), []byte(content), 0o644); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+	}
+	inst := &installer{root: root, sys: RealSystem{}}
+	if err := inst.pruneUpgradeHistory(1); err != nil {
+		t.Fatalf("pruneUpgradeHistory: %v", err)
+	}
+	records, err := ListUpgradeHistory(root, RealSystem{})
+	if err != nil {
+		t.Fatalf("ListUpgradeHistory: %v", err)
+	}
+	if len(records) != 1 || records[0].ID != "c" {
+		t.Fatalf("records = %+v, want only c", records)
+	}
+}
+
+func TestReadUpgradeHistory_Errors(t *testing.T) {
+	root := t.TempDir()
+	tests := []struct {
+		name string
+		root string
+		id   string
+		sys  System
+		want string
+	}{
+		{name: "root required", root: "", id: "x", sys: RealSystem{}, want: messages.InstallRootRequired},
+		{name: "id required", root: root, id: " ", sys: RealSystem{}, want: messages.InstallUpgradeHistoryIDRequired},
+		{name: "path traversal", root: root, id: "../x", sys: RealSystem{}, want: "must not contain path separators"},
+		{name: "system required", root: root, id: "x", sys: nil, want: messages.InstallSystemRequired},
+		{name: "not found", root: root, id: "missing", sys: RealSystem{}, want: "upgrade history missing not found"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ReadUpgradeHistory(tt.root, tt.id, tt.sys)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("error = %v, want containing %q", err, tt.want)
+			}
+		})
+	}
+}
@@ -11,12 +11,15 @@ import (
 	"path"
 	"path/filepath"
 	"reflect"
+	"regexp"
 	"sort"
 	"strings"
 
 	tomlv2 "github.com/pelletier/go-toml/v2"
 
 	"github.com/conn-castle/agent-layer/internal/config"
+	"github.com/conn-castle/agent-layer/internal/errcode"
+	"github.com/conn-castle/agent-layer/internal/i18n"
 	"github.com/conn-castle/agent-layer/internal/messages"
 	"github.com/conn-castle/agent-layer/internal/templates"
 	"github.com/conn-castle/agent-layer/internal/version"
@@ -42,8 +45,14 @@ const (
 	UpgradeMigrationSourceSnapshot UpgradeMigrationSourceOrigin = "upgrade_snapshot"
 	// UpgradeMigrationSourceManifestMatch means source version was inferred from embedded manifest fingerprint matching.
 	UpgradeMigrationSourceManifestMatch UpgradeMigrationSourceOrigin = "manifest_match"
+	// UpgradeMigrationSourceUserSelected means the user chose the source version from ranked manifest matches.
+	UpgradeMigrationSourceUserSelected UpgradeMigrationSourceOrigin = "user_selected"
 )
 
+// sourceVersionCandidateLimit caps the ranked manifest matches offered when
+// the upgrade source version is ambiguous.
+const sourceVersionCandidateLimit = 5
+
 // UpgradeMigrationStatus describes migration execution/planning status.
 type UpgradeMigrationStatus string
 
@@ -58,6 +67,8 @@ const (
 	UpgradeMigrationStatusSkippedUnknownSource UpgradeMigrationStatus = "skipped_unknown_source"
 	// UpgradeMigrationStatusSkippedSourceTooOld means migration requires a newer prior version than the resolved source.
 	UpgradeMigrationStatusSkippedSourceTooOld UpgradeMigrationStatus = "skipped_source_too_old"
+	// UpgradeMigrationStatusSkippedByUser means the upgrade's --only or --skip filter excluded the migration.
+	UpgradeMigrationStatusSkippedByUser UpgradeMigrationStatus = "skipped_by_user"
 )
 
 // UpgradeMigrationEntry is a deterministic migration-plan/apply record.
@@ -73,6 +84,7 @@ type UpgradeMigrationEntry struct {
 	Path            string                 `json:"path,omitempty"`
 	Key             string                 `json:"key,omitempty"`
 	Value           json.RawMessage        `json:"value,omitempty"`
+	Diff            string                 `json:"diff,omitempty"`
 	Breaking        bool                   `json:"breaking,omitempty"`
 	BreakingNotice  string                 `json:"breaking_notice,omitempty"`
 	BreakingDetails []string               `json:"breaking_details,omitempty"`
@@ -87,6 +99,8 @@ type UpgradeMigrationReport struct {
 	SourceVersionOrigin   UpgradeMigrationSourceOrigin `json:"source_version_origin"`
 	SourceResolutionNotes []string                     `json:"source_resolution_notes,omitempty"`
 	Entries               []UpgradeMigrationEntry      `json:"entries"`
+	// SkippedPaths lists template updates the --only or --skip filter left out.
+	SkippedPaths []string `json:"skipped_paths,omitempty"`
 }
 
 type upgradeMigrationOperationKind string
@@ -102,6 +116,8 @@ const (
 	upgradeMigrationKindConfigSetDefault        upgradeMigrationOperationKind = "config_set_default"
 	upgradeMigrationKindMigrateSkillsFormat     upgradeMigrationOperationKind = "migrate_skills_format"
 	upgradeMigrationKindAppendToFile            upgradeMigrationOperationKind = "append_to_file"
+	upgradeMigrationKindTransformFile           upgradeMigrationOperationKind = "transform_file"
+	upgradeMigrationKindAppendManagedSection    upgradeMigrationOperationKind = "append_managed_section"
 )
 
 type upgradeMigrationOperation struct {
@@ -114,6 +130,7 @@ type upgradeMigrationOperation struct {
 	Path            string                        `json:"path,omitempty"`
 	Key             string                        `json:"key,omitempty"`
 	Value           json.RawMessage               `json:"value,omitempty"`
+	ExpectedMatches int                           `json:"expected_matches,omitempty"`
 	Breaking        bool                          `json:"breaking,omitempty"`
 	BreakingNotice  string                        `json:"breaking_notice,omitempty"`
 	BreakingDetails []string                      `json:"breaking_details,omitempty"`
@@ -245,6 +262,15 @@ func (inst *installer) planUpgradeMigrations() (migrationPlan, error) {
 					}
 				}
 			}
+			if status == UpgradeMigrationStatusPlanned {
+				if reason := inst.operationFilter.skipReason(op.ID); reason != "" {
+					status = UpgradeMigrationStatusSkippedByUser
+					skipReason = reason
+				} else if owned := inst.userOwnedMigrationPath(op); owned != "" {
+					status = UpgradeMigrationStatusSkippedByUser
+					skipReason = i18n.Sprintf(messages.InstallOwnershipMigrationSkippedFmt, owned)
+				}
+			}
 			if status == UpgradeMigrationStatusPlanned {
 				skip, reason, conditionalErr := inst.shouldSkipConditionalMigration(op, resolution)
 				if conditionalErr != nil {
@@ -255,6 +281,17 @@ func (inst *installer) planUpgradeMigrations() (migrationPlan, error) {
 					skipReason = reason
 				}
 			}
+			if status == UpgradeMigrationStatusPlanned && isContentMigrationKind(op.Kind) {
+				diff, reason, previewErr := inst.previewContentMigration(op)
+				if previewErr != nil {
+					return migrationPlan{}, previewErr
+				}
+				if reason != "" {
+					status = UpgradeMigrationStatusNoop
+					skipReason = reason
+				}
+				entry.Diff = diff
+			}
 			entry.Status = status
 			entry.SkipReason = skipReason
 			entries = append(entries, entry)
@@ -327,7 +364,7 @@ func (inst *installer) hasUnpinnedMigrationTrigger(targetVersion string) (bool,
 		if errors.Is(err, os.ErrNotExist) {
 			return false, nil
 		}
-		return false, fmt.Errorf(messages.InstallFailedReadFmt, upgradeMigrationConfigPath, err)
+		return false, i18n.Errorf(messages.InstallFailedReadFmt, upgradeMigrationConfigPath, err)
 	}
 	if config.HasLegacyGeminiConfig(data) || config.HasLegacyDispatchConfig(data) || config.HasLegacyAntigravityAgentSpecificModel(data) || hasLegacyGeminiMCPClient(data) {
 		return true, nil
@@ -392,7 +429,7 @@ func (inst *installer) shouldSkipConditionalMigration(op upgradeMigrationOperati
 		if errors.Is(err, os.ErrNotExist) {
 			return true, "legacy agents.gemini table is absent", nil
 		}
-		return false, "", fmt.Errorf(messages.InstallFailedReadFmt, upgradeMigrationConfigPath, err)
+		return false, "", i18n.Errorf(messages.InstallFailedReadFmt, upgradeMigrationConfigPath, err)
 	}
 	if !config.HasLegacyGeminiConfig(data) {
 		return true, "legacy agents.gemini table is absent", nil
@@ -518,6 +555,10 @@ func (inst *installer) executeUpgradeMigrationOperation(op upgradeMigrationOpera
 		return inst.executeMigrateSkillsFormat(op.Path)
 	case upgradeMigrationKindAppendToFile:
 		return inst.executeAppendToFile(op)
+	case upgradeMigrationKindTransformFile:
+		return inst.executeTransformFile(op)
+	case upgradeMigrationKindAppendManagedSection:
+		return inst.executeAppendManagedSection(op)
 	default:
 		return false, fmt.Errorf("unsupported migration kind %q", op.Kind)
 	}
@@ -543,9 +584,9 @@ func (inst *installer) executeRenameMigration(fromRel string, toRel string) (boo
 			if statErr == nil || errors.Is(statErr, os.ErrNotExist) {
 				return false, nil
 			}
-			return false, fmt.Errorf(messages.InstallFailedStatFmt, toPath, statErr)
+			return false, i18n.Errorf(messages.InstallFailedStatFmt, toPath, statErr)
 		}
-		return false, fmt.Errorf(messages.InstallFailedStatFmt, fromPath, err)
+		return false, i18n.Errorf(messages.InstallFailedStatFmt, fromPath, err)
 	}
 
 	toInfo, err := inst.sys.Stat(toPath)
@@ -562,7 +603,7 @@ func (inst *installer) executeRenameMigration(fromRel string, toRel string) (boo
 				return dirNotEmptyErr
 			})
 			if walkErr != nil && !errors.Is(walkErr, dirNotEmptyErr) {
-				return false, fmt.Errorf(messages.InstallFailedReadFmt, toPath, walkErr)
+				return false, i18n.Errorf(messages.InstallFailedReadFmt, toPath, walkErr)
 			}
 			if walkErr == nil {
 				if removeErr := inst.sys.RemoveAll(toPath); removeErr != nil {
@@ -577,11 +618,11 @@ func (inst *installer) executeRenameMigration(fromRel string, toRel string) (boo
 		if fromInfo.Mode().IsRegular() && toInfo.Mode().IsRegular() {
 			fromBytes, readFromErr := inst.sys.ReadFile(fromPath)
 			if readFromErr != nil {
-				return false, fmt.Errorf(messages.InstallFailedReadFmt, fromPath, readFromErr)
+				return false, i18n.Errorf(messages.InstallFailedReadFmt, fromPath, readFromErr)
 			}
 			toBytes, readToErr := inst.sys.ReadFile(toPath)
 			if readToErr != nil {
-				return false, fmt.Errorf(messages.InstallFailedReadFmt, toPath, readToErr)
+				return false, i18n.Errorf(messages.InstallFailedReadFmt, toPath, readToErr)
 			}
 			if normalizeTemplateContent(string(fromBytes)) == normalizeTemplateContent(string(toBytes)) {
 				if removeErr := inst.sys.RemoveAll(fromPath); removeErr != nil {
@@ -593,11 +634,11 @@ func (inst *installer) executeRenameMigration(fromRel string, toRel string) (boo
 		return false, fmt.Errorf("rename migration target already exists: %s", toRel)
 	}
 	if !errors.Is(err, os.ErrNotExist) {
-		return false, fmt.Errorf(messages.InstallFailedStatFmt, toPath, err)
+		return false, i18n.Errorf(messages.InstallFailedStatFmt, toPath, err)
 	}
 
 	if mkErr := inst.sys.MkdirAll(filepath.Dir(toPath), 0o755); mkErr != nil {
-		return false, fmt.Errorf(messages.InstallFailedCreateDirForFmt, toPath, mkErr)
+		return false, i18n.Errorf(messages.InstallFailedCreateDirForFmt, toPath, mkErr)
 	}
 	if renameErr := inst.sys.Rename(fromPath, toPath); renameErr != nil {
 		return false, fmt.Errorf("rename %s -> %s: %w", fromRel, toRel, renameErr)
@@ -613,7 +654,7 @@ func (inst *installer) executeDeleteMigration(relPath string, requireGeneratedWa
 	info, statErr := inst.sys.Stat(absPath)
 	if statErr != nil {
 		if !errors.Is(statErr, os.ErrNotExist) {
-			return false, fmt.Errorf(messages.InstallFailedStatFmt, absPath, statErr)
+			return false, i18n.Errorf(messages.InstallFailedStatFmt, absPath, statErr)
 		}
 		// Stat follows symlinks; a dangling symlink at this path returns
 		// ErrNotExist but the link entity itself still exists. Lstat
@@ -624,7 +665,7 @@ func (inst *installer) executeDeleteMigration(relPath string, requireGeneratedWa
 			if errors.Is(lstatErr, os.ErrNotExist) {
 				return false, nil
 			}
-			return false, fmt.Errorf(messages.InstallFailedStatFmt, absPath, lstatErr)
+			return false, i18n.Errorf(messages.InstallFailedStatFmt, absPath, lstatErr)
 		}
 		// We found a dangling symlink. Watermarked-only deletion cannot
 		// verify the (missing) target's content, so we conservatively
@@ -635,7 +676,7 @@ func (inst *installer) executeDeleteMigration(relPath string, requireGeneratedWa
 		// through and remove the link entity.
 		if requireGeneratedWatermark {
 			if inst.warnWriter != nil {
-				_, _ = fmt.Fprintf(inst.warnWriter, messages.InstallMigrationSkipDanglingSymlinkFmt, relPath)
+				_, _ = fmt.Fprintf(inst.warnWriter, i18n.T(messages.InstallMigrationSkipDanglingSymlinkFmt), relPath)
 			}
 			return false, nil
 		}
@@ -646,13 +687,13 @@ func (inst *installer) executeDeleteMigration(relPath string, requireGeneratedWa
 	if requireGeneratedWatermark && info != nil {
 		if info.IsDir() {
 			if inst.warnWriter != nil {
-				_, _ = fmt.Fprintf(inst.warnWriter, messages.InstallMigrationSkipGeneratedDirFmt, relPath)
+				_, _ = fmt.Fprintf(inst.warnWriter, i18n.T(messages.InstallMigrationSkipGeneratedDirFmt), relPath)
 			}
 			return false, nil
 		}
 		data, readErr := inst.sys.ReadFile(absPath)
 		if readErr != nil {
-			return false, fmt.Errorf(messages.InstallFailedStatFmt, absPath, readErr)
+			return false, i18n.Errorf(messages.InstallFailedStatFmt, absPath, readErr)
 		}
 		if !strings.Contains(string(data), generatedFileMarker) {
 			return false, nil
@@ -708,7 +749,7 @@ func (inst *installer) executeConfigRenameKeyMigration(fromKey string, toKey str
 			}
 			return true, nil
 		}
-		return false, fmt.Errorf("config key rename conflict: destination key %s already exists", toKey)
+		return false, errcode.Wrap(errcode.UpgradeConflict, fmt.Errorf("config key rename conflict: destination key %s already exists", toKey))
 	}
 	if setErr := setNestedConfigValue(cfg, toParts, fromValue, true); setErr != nil {
 		return false, setErr
@@ -810,6 +851,13 @@ func (inst *installer) executeConfigSetDefaultMigration(op upgradeMigrationOpera
 		return false, fmt.Errorf("prompt for config key %s: %w", keyPath, promptErr)
 	}
 	decoded = resp.value
+	if fieldPtr != nil {
+		// Prompters may return user-typed overrides; never persist a value
+		// the field catalog would reject on the next config load.
+		if validateErr := config.ValidateFieldValue(*fieldPtr, decoded); validateErr != nil {
+			return false, fmt.Errorf("invalid value for config key %s: %w", keyPath, validateErr)
+		}
+	}
 	if setErr := setNestedConfigValue(cfg, parts, decoded, true); setErr != nil {
 		return false, setErr
 	}
@@ -838,7 +886,7 @@ func (inst *installer) executeAppendToFile(op upgradeMigrationOperation) (bool,
 	existing, err := inst.sys.ReadFile(absPath)
 	if err != nil {
 		if !errors.Is(err, os.ErrNotExist) {
-			return false, fmt.Errorf(messages.InstallFailedReadFmt, absPath, err)
+			return false, i18n.Errorf(messages.InstallFailedReadFmt, absPath, err)
 		}
 		// File doesn't exist. If a template exists for this path, seed the
 		// full template first so the user gets the complete file rather than
@@ -849,13 +897,13 @@ func (inst *installer) executeAppendToFile(op upgradeMigrationOperation) (bool,
 			base = string(tpl)
 		}
 		if mkErr := inst.sys.MkdirAll(filepath.Dir(absPath), 0o755); mkErr != nil {
-			return false, fmt.Errorf(messages.InstallFailedCreateDirForFmt, absPath, mkErr)
+			return false, i18n.Errorf(messages.InstallFailedCreateDirForFmt, absPath, mkErr)
 		}
 		// If the template already contains the match string, the append
 		// content is already present — just seed the template as-is.
 		if base != "" && op.From != "" && strings.Contains(base, op.From) {
 			if wErr := inst.sys.WriteFileAtomic(absPath, []byte(base), 0o644); wErr != nil {
-				return false, fmt.Errorf(messages.InstallFailedWriteFmt, absPath, wErr)
+				return false, i18n.Errorf(messages.InstallFailedWriteFmt, absPath, wErr)
 			}
 			return true, nil
 		}
@@ -866,7 +914,7 @@ func (inst *installer) executeAppendToFile(op upgradeMigrationOperation) (bool,
 		}
 		merged += content
 		if wErr := inst.sys.WriteFileAtomic(absPath, []byte(merged), 0o644); wErr != nil {
-			return false, fmt.Errorf(messages.InstallFailedWriteFmt, absPath, wErr)
+			return false, i18n.Errorf(messages.InstallFailedWriteFmt, absPath, wErr)
 		}
 		return true, nil
 	}
@@ -883,11 +931,87 @@ func (inst *installer) executeAppendToFile(op upgradeMigrationOperation) (bool,
 	}
 	merged += content
 	if wErr := inst.sys.WriteFileAtomic(absPath, []byte(merged), 0o644); wErr != nil {
-		return false, fmt.Errorf(messages.InstallFailedWriteFmt, absPath, wErr)
+		return false, i18n.Errorf(messages.InstallFailedWriteFmt, absPath, wErr)
 	}
 	return true, nil
 }
 
+// executeTransformFile rewrites every match of the op.From regular expression
+// in op.Path with op.To ($1-style group references expand). The file is
+// changed only when the pattern matches exactly op.ExpectedMatches times, so
+// a release can fix a known fragment of a managed file without clobbering
+// user additions; a missing file or any other match count is a no-op.
+func (inst *installer) executeTransformFile(op upgradeMigrationOperation) (bool, error) {
+	absPath, current, transformed, reason, err := inst.evaluateTransformFile(op)
+	if err != nil || reason != "" {
+		return false, err
+	}
+	if transformed == current {
+		return false, nil
+	}
+	if wErr := inst.sys.WriteFileAtomic(absPath, []byte(transformed), 0o644); wErr != nil {
+		return false, i18n.Errorf(messages.InstallFailedWriteFmt, absPath, wErr)
+	}
+	return true, nil
+}
+
+// isContentMigrationKind reports whether the kind edits file content in place
+// and therefore gets a diff preview and a plan-time applicability check.
+func isContentMigrationKind(kind upgradeMigrationOperationKind) bool {
+	return kind == upgradeMigrationKindTransformFile || kind == upgradeMigrationKindAppendManagedSection
+}
+
+// previewContentMigration dispatches to the kind's preview. A non-empty
+// reason means the operation will not apply.
+func (inst *installer) previewContentMigration(op upgradeMigrationOperation) (string, string, error) {
+	if op.Kind == upgradeMigrationKindAppendManagedSection {
+		return inst.previewAppendManagedSection(op)
+	}
+	return inst.previewTransformFileMigration(op)
+}
+
+// previewTransformFileMigration renders the planned transform as a unified
+// diff. A non-empty reason means the transform will not apply.
+func (inst *installer) previewTransformFileMigration(op upgradeMigrationOperation) (string, string, error) {
+	_, current, transformed, reason, err := inst.evaluateTransformFile(op)
+	if err != nil || reason != "" {
+		return "", reason, err
+	}
+	name := normalizeRelPath(filepath.Clean(filepath.FromSlash(op.Path)))
+	diff, _, _, _ := renderTruncatedUnifiedDiff(name+" (current)", name+" (transformed)", current, transformed, inst.diffMaxLines)
+	return diff, "", nil
+}
+
+// evaluateTransformFile reads op.Path and applies the transform in memory.
+// It returns a skip reason instead of content when the file is absent or the
+// match count differs from op.ExpectedMatches.
+func (inst *installer) evaluateTransformFile(op upgradeMigrationOperation) (string, string, string, string, error) {
+	absPath, err := snapshotEntryAbsPath(inst.root, op.Path)
+	if err != nil {
+		return "", "", "", "", err
+	}
+	pattern, err := regexp.Compile(op.From)
+	if err != nil {
+		return "", "", "", "", fmt.Errorf("compile transform_file pattern for %s: %w", op.Path, err)
+	}
+	data, err := inst.sys.ReadFile(absPath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return absPath, "", "", "file is absent", nil
+		}
+		return "", "", "", "", i18n.Errorf(messages.InstallFailedReadFmt, absPath, err)
+	}
+	current := string(data)
+	matches := len(pattern.FindAllStringIndex(current, -1))
+	switch {
+	case matches == 0:
+		return absPath, current, "", "pattern not found (already fixed or locally edited)", nil
+	case matches != op.ExpectedMatches:
+		return absPath, current, "", fmt.Sprintf("pattern matched %d times, expected %d; file left unchanged", matches, op.ExpectedMatches), nil
+	}
+	return absPath, current, pattern.ReplaceAllString(current, op.To), "", nil
+}
+
 func (inst *installer) readMigrationConfigMap() (map[string]any, string, bool, error) {
 	cfgPath := filepath.Join(inst.root, filepath.FromSlash(upgradeMigrationConfigPath))
 	data, err := inst.sys.ReadFile(cfgPath)
@@ -895,7 +1019,7 @@ func (inst *installer) readMigrationConfigMap() (map[string]any, string, bool, e
 		if errors.Is(err, os.ErrNotExist) {
 			return nil, cfgPath, false, nil
 		}
-		return nil, cfgPath, false, fmt.Errorf(messages.InstallFailedReadFmt, cfgPath, err)
+		return nil, cfgPath, false, i18n.Errorf(messages.InstallFailedReadFmt, cfgPath, err)
 	}
 	var cfg map[string]any
 	if unmarshalErr := tomlv2.Unmarshal(data, &cfg); unmarshalErr != nil {
@@ -920,7 +1044,7 @@ func (inst *installer) writeMigrationConfigMap(cfgPath string, cfg map[string]an
 		encoded = append(encoded, '\n')
 	}
 	if writeErr := inst.sys.WriteFileAtomic(cfgPath, encoded, 0o644); writeErr != nil {
-		return fmt.Errorf(messages.InstallFailedWriteFmt, cfgPath, writeErr)
+		return i18n.Errorf(messages.InstallFailedWriteFmt, cfgPath, writeErr)
 	}
 	return nil
 }
@@ -1230,7 +1354,7 @@ func migrationCoveredPaths(op upgradeMigrationOperation) []string {
 		if strings.TrimSpace(pathValue) != "" {
 			paths = append(paths, pathValue)
 		}
-	case upgradeMigrationKindAppendToFile:
+	case upgradeMigrationKindAppendToFile, upgradeMigrationKindTransformFile, upgradeMigrationKindAppendManagedSection:
 		pathValue := normalizeRelPath(filepath.Clean(filepath.FromSlash(op.Path)))
 		if strings.TrimSpace(pathValue) != "" {
 			paths = append(paths, pathValue)
@@ -1283,6 +1407,10 @@ func migrationWillCoverPath(sys System, root string, op upgradeMigrationOperatio
 	case upgradeMigrationKindAppendToFile:
 		// Append migrations always cover their target path.
 		return true
+	case upgradeMigrationKindTransformFile, upgradeMigrationKindAppendManagedSection:
+		// Content migrations edit part of a file; the rest of it is still
+		// subject to the normal template diff.
+		return false
 	default:
 		// Config migrations don't cover file paths in the template sense.
 		return false
@@ -1383,7 +1511,10 @@ func (inst *installer) resolveUpgradeMigrationSourceVersion() sourceVersionResol
 		return resolution
 	}
 
-	manifestVersion, manifestErr := inst.inferSourceVersionFromManifestMatch()
+	manifestVersion, manifestNote, manifestErr := inst.inferSourceVersionFromManifestMatch()
+	if manifestNote != "" {
+		resolution.notes = append(resolution.notes, manifestNote)
+	}
 	if manifestErr != nil {
 		resolution.notes = append(resolution.notes, fmt.Sprintf("manifest source inference failed: %v", manifestErr))
 	} else if strings.TrimSpace(manifestVersion) != "" {
@@ -1392,17 +1523,64 @@ func (inst *installer) resolveUpgradeMigrationSourceVersion() sourceVersionResol
 		return resolution
 	}
 
+	selectedVersion, selectErr := inst.selectSourceVersionFromRankedManifests()
+	if selectErr != nil {
+		resolution.notes = append(resolution.notes, fmt.Sprintf("source version selection failed: %v", selectErr))
+	} else if selectedVersion != "" {
+		resolution.version = selectedVersion
+		resolution.origin = UpgradeMigrationSourceUserSelected
+		return resolution
+	}
+
 	resolution.notes = dedupSortedStrings(resolution.notes)
 	return resolution
 }
 
+// selectSourceVersionFromRankedManifests offers the best-matching embedded
+// manifests to the prompter when fingerprint matching found no unique source.
+// It returns "" when the prompter cannot choose, nothing matches, or the user
+// keeps the source unknown.
+func (inst *installer) selectSourceVersionFromRankedManifests() (string, error) {
+	router := inst.promptRouter()
+	if !router.hasSourceVersion() {
+		return "", nil
+	}
+	manifests, err := loadAllTemplateManifests()
+	if err != nil {
+		return "", err
+	}
+	ranked, err := inst.rankBaselineCandidates(manifests)
+	if err != nil {
+		return "", err
+	}
+	if len(ranked) == 0 {
+		return "", nil
+	}
+	if len(ranked) > sourceVersionCandidateLimit {
+		ranked = ranked[:sourceVersionCandidateLimit]
+	}
+	resp, err := router.route(promptRequest{kind: promptKindChooseSourceVersion, sourceCandidates: ranked})
+	if err != nil {
+		return "", err
+	}
+	if resp.version == "" {
+		return "", nil
+	}
+	for _, candidate := range ranked {
+		if candidate.Version == resp.version {
+			return resp.version, nil
+		}
+	}
+	return "", i18n.Errorf(messages.InstallBaselineChoiceInvalidFmt, resp.version, joinCandidateVersions(ranked))
+}
+
 func (inst *installer) inferSourceVersionFromLatestSnapshot() (string, error) {
 	snapshotDir := inst.upgradeSnapshotDirPath()
 	if _, err := inst.sys.Stat(snapshotDir); err != nil {
 		if errors.Is(err, os.ErrNotExist) {
 			return "", nil
 		}
-		return "", fmt.Errorf(messages.InstallFailedStatFmt, snapshotDir, err)
+		return "", i18n.Errorf(messages.InstallFailedStatFmt, snapshotDir, err)
 	}
 	files, err := inst.listUpgradeSnapshotFiles()
 	if err != nil {
@@ -1431,52 +1609,111 @@ func (inst *installer) inferSourceVersionFromLatestSnapshot() (string, error) {
 	return "", nil
 }
 
-func (inst *installer) inferSourceVersionFromManifestMatch() (string, error) {
+// inferSourceVersionFromManifestMatch attributes the repo to the embedded
+// manifest whose managed files it matches best. Every manifest entry present
+// in the repo contributes its weight (see manifestEntryMatchWeight) to the
+// present total and, when its content hash matches, to the matched total.
+// The best manifest is accepted only when its matched share reaches
+// manifestMatchMinScore and its matched weight strictly beats the runner-up,
+// so a repo with a locally edited doc is still attributed while releases that
+// ship identical files stay ambiguous. The returned note records the scores
+// for SourceResolutionNotes.
+func (inst *installer) inferSourceVersionFromManifestMatch() (string, string, error) {
 	manifests, err := loadAllTemplateManifests()
 	if err != nil {
-		return "", err
+		return "", "", err
 	}
-	candidates := make([]string, 0, len(manifests))
+	scores := make([]manifestMatchScore, 0, len(manifests))
 	for versionValue, manifest := range manifests {
-		match, matchErr := inst.matchesTemplateDocsManifest(manifest)
-		if matchErr != nil {
-			return "", matchErr
+		score, scoreErr := inst.scoreTemplateManifestMatch(manifest)
+		if scoreErr != nil {
+			return "", "", scoreErr
 		}
-		if match {
-			candidates = append(candidates, versionValue)
+		if score.matched == 0 {
+			continue
 		}
+		score.version = versionValue
+		scores = append(scores, score)
 	}
-	sort.Strings(candidates)
-	if len(candidates) == 1 {
-		return candidates[0], nil
+	if len(scores) == 0 {
+		return "", "", nil
 	}
-	return "", nil
+	sort.Slice(scores, func(i, j int) bool {
+		if scores[i].matched != scores[j].matched {
+			return scores[i].matched > scores[j].matched
+		}
+		if scores[i].present != scores[j].present {
+			return scores[i].present < scores[j].present
+		}
+		cmp, _ := version.Compare(scores[i].version, scores[j].version)
+		return cmp > 0
+	})
+	best := scores[0]
+	note := fmt.Sprintf("manifest match: %s scored %.2f (%d of %d weighted managed files)", best.version, best.ratio(), best.matched, best.present)
+	if len(scores) > 1 {
+		runnerUp := scores[1]
+		note += fmt.Sprintf("; runner-up %s scored %.2f (%d of %d)", runnerUp.version, runnerUp.ratio(), runnerUp.matched, runnerUp.present)
+		if runnerUp.matched == best.matched {
+			return "", note + "; tie, source not inferred", nil
+		}
+	}
+	if best.ratio() < manifestMatchMinScore {
+		return "", note + fmt.Sprintf("; below %.2f, source not inferred", manifestMatchMinScore), nil
+	}
+	return best.version, note, nil
 }
 
-func (inst *installer) matchesTemplateDocsManifest(manifest templateManifest) (bool, error) {
-	entries := make([]manifestFileEntry, 0)
-	for _, entry := range manifest.Files {
-		if strings.HasPrefix(entry.Path, "docs/agent-layer/") {
-			entries = append(entries, entry)
-		}
+// manifestMatchMinScore is the matched share of weighted managed files a
+// manifest needs before it is accepted as the upgrade source.
+const manifestMatchMinScore = 0.75
+
+// manifestMatchScore is the weighted agreement between one manifest and the
+// repo's managed files.
+type manifestMatchScore struct {
+	version string
+	matched int
+	present int
+}
+
+func (s manifestMatchScore) ratio() float64 {
+	if s.present == 0 {
+		return 0
 	}
-	if len(entries) == 0 {
-		return false, nil
+	return float64(s.matched) / float64(s.present)
+}
+
+// manifestEntryMatchWeight weights a manifest entry for source inference.
+// Memory docs under docs/agent-layer/ are meant to be edited, so they count
+// less than template files users rarely touch.
+func manifestEntryMatchWeight(relPath string) int {
+	if strings.HasPrefix(relPath, "docs/agent-layer/") {
+		return 1
 	}
-	for _, entry := range entries {
+	return 3
+}
+
+// scoreTemplateManifestMatch compares each manifest entry's full hash with
+// the repo file at its path. Missing files are skipped rather than counted
+// against the manifest, since optional files (catalog skills, removed docs)
+// are legitimately absent.
+func (inst *installer) scoreTemplateManifestMatch(manifest templateManifest) (manifestMatchScore, error) {
+	var score manifestMatchScore
+	for _, entry := range manifest.Files {
 		absPath := filepath.Join(inst.root, filepath.FromSlash(entry.Path))
 		content, err := inst.sys.ReadFile(absPath)
 		if err != nil {
 			if errors.Is(err, os.ErrNotExist) {
-				return false, nil
+				continue
 			}
-			return false, fmt.Errorf(messages.InstallFailedReadFmt, absPath, err)
+			return manifestMatchScore{}, i18n.Errorf(messages.InstallFailedReadFmt, absPath, err)
 		}
-		if hashNormalizedContent(content) != entry.FullHashNormalized {
-			return false, nil
+		weight := manifestEntryMatchWeight(entry.Path)
+		score.present += weight
+		if templateFullHash(content) == entry.FullHashNormalized {
+			score.matched += weight
 		}
 	}
-	return true, nil
+	return score, nil
 }
 
 func dedupSortedStrings(values []string) []string {
@@ -1502,7 +1739,7 @@ func dedupSortedStrings(values []string) []string {
 func loadUpgradeMigrationManifestByVersion(versionRaw string) (upgradeMigrationManifest, string, error) {
 	normalized, err := version.Normalize(versionRaw)
 	if err != nil {
-		return upgradeMigrationManifest{}, "", fmt.Errorf(messages.InstallInvalidPinVersionFmt, err)
+		return upgradeMigrationManifest{}, "", i18n.Errorf(messages.InstallInvalidPinVersionFmt, err)
 	}
 	manifestPath := path.Join(upgradeMigrationManifestDir, normalized+".json")
 	data, err := templates.Read(manifestPath)
@@ -1741,6 +1978,21 @@ func validateUpgradeMigrationOperation(op upgradeMigrationOperation) error {
 		if err := json.Unmarshal(op.Value, &decoded); err != nil {
 			return fmt.Errorf("migration %s (%s) value must be a JSON string: %w", op.ID, op.Kind, err)
 		}
+	case upgradeMigrationKindTransformFile:
+		if strings.TrimSpace(op.Path) == "" {
+			return fmt.Errorf("migration %s (%s) requires path", op.ID, op.Kind)
+		}
+		if op.From == "" {
+			return fmt.Errorf("migration %s (%s) requires from pattern", op.ID, op.Kind)
+		}
+		if _, err := regexp.Compile(op.From); err != nil {
+			return fmt.Errorf("migration %s (%s) has invalid from pattern: %w", op.ID, op.Kind, err)
+		}
+		if op.ExpectedMatches <= 0 {
+			return fmt.Errorf("migration %s (%s) requires expected_matches greater than zero", op.ID, op.Kind)
+		}
+	case upgradeMigrationKindAppendManagedSection:
+		return validateAppendManagedSectionOperation(op)
 	default:
 		return fmt.Errorf("migration %s has unsupported kind %q", op.ID, op.Kind)
 	}
@@ -0,0 +1,105 @@
+package install
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/conn-castle/agent-layer/internal/version"
+)
+
+// VerifyMigrationChain checks that the embedded migration manifests form an
+// unbroken upgrade chain and that every release with an embedded template
+// manifest also ships a migration manifest. It returns one error listing every
+// problem found, or nil when the chain is continuous.
+func VerifyMigrationChain() error {
+	versions, err := listMigrationManifestVersions()
+	if err != nil {
+		return err
+	}
+	manifests := make([]upgradeMigrationManifest, 0, len(versions))
+	for _, ver := range versions {
+		manifest, _, loadErr := loadUpgradeMigrationManifestByVersion(ver)
+		if loadErr != nil {
+			return loadErr
+		}
+		manifests = append(manifests, manifest)
+	}
+	templateManifests, err := loadAllTemplateManifests()
+	if err != nil {
+		return err
+	}
+	releases := make([]string, 0, len(templateManifests))
+	for ver := range templateManifests {
+		releases = append(releases, ver)
+	}
+	problems := checkMigrationChain(manifests, releases)
+	if len(problems) == 0 {
+		return nil
+	}
+	errs := make([]error, 0, len(problems))
+	for _, problem := range problems {
+		errs = append(errs, errors.New(problem))
+	}
+	return fmt.Errorf("migration chain is broken:\n%w", errors.Join(errs...))
+}
+
+// checkMigrationChain returns the continuity problems in manifests, ordered by
+// target version. Each manifest must:
+//   - have a target_version no other manifest claims (no overlap),
+//   - have a min_prior_version below its target_version,
+//   - have a min_prior_version no newer than the previous manifest's target
+//     (no gap: upgrading from the previous release must be supported), and
+//   - name a min_prior_version that is itself a chained target, except for
+//     the first manifest whose floor predates the chain.
+//
+// A min_prior_version older than the previous target is allowed: patch
+// releases keep the floor of their release line (see docs/RELEASE.md).
+// Every version in releases (the embedded template manifests) must also have
+// a migration manifest, which catches a release that forgot one.
+func checkMigrationChain(manifests []upgradeMigrationManifest, releases []string) []string {
+	sorted := append([]upgradeMigrationManifest(nil), manifests...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		cmp, _ := version.Compare(sorted[i].TargetVersion, sorted[j].TargetVersion)
+		return cmp < 0
+	})
+
+	var problems []string
+	targets := make(map[string]struct{}, len(sorted))
+	for i, manifest := range sorted {
+		target, floor := manifest.TargetVersion, manifest.MinPriorVersion
+		if _, exists := targets[target]; exists {
+			problems = append(problems, fmt.Sprintf("target_version %s has more than one migration manifest", target))
+			continue
+		}
+		if cmp, err := version.Compare(floor, target); err != nil {
+			problems = append(problems, fmt.Sprintf("%s: compare min_prior_version %s: %v", target, floor, err))
+		} else if cmp >= 0 {
+			problems = append(problems, fmt.Sprintf("%s: min_prior_version %s is not older than its target_version", target, floor))
+		}
+		if i > 0 {
+			previous := sorted[i-1].TargetVersion
+			if cmp, err := version.Compare(floor, previous); err == nil && cmp > 0 {
+				problems = append(problems, fmt.Sprintf("%s: min_prior_version %s skips the previous target %s (gap)", target, floor, previous))
+			} else if _, known := targets[floor]; !known {
+				problems = append(problems, fmt.Sprintf("%s: min_prior_version %s is not the target of any earlier migration manifest", target, floor))
+			}
+		}
+		targets[target] = struct{}{}
+	}
+
+	var missing []string
+	for _, release := range releases {
+		if _, ok := targets[release]; !ok {
+			missing = append(missing, release)
+		}
+	}
+	sort.Slice(missing, func(i, j int) bool {
+		cmp, _ := version.Compare(missing[i], missing[j])
+		return cmp < 0
+	})
+	for _, release := range missing {
+		problems = append(problems, fmt.Sprintf("release %s has a template manifest but no migration manifest", release))
+	}
+	return problems
+}
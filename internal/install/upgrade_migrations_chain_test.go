@@ -0,0 +1,71 @@
+package install
+
+import (
+	"strings"
+	"testing"
+)
+
+func chainManifest(target string, minPrior string) upgradeMigrationManifest {
+	return upgradeMigrationManifest{SchemaVersion: upgradeMigrationManifestSchemaVersion, TargetVersion: target, MinPriorVersion: minPrior}
+}
+
+func TestVerifyMigrationChain_EmbeddedManifests(t *testing.T) {
+	if err := VerifyMigrationChain(); err != nil {
+		t.Fatalf("embedded migration chain: %v", err)
+	}
+}
+
+func TestCheckMigrationChain_AllowsSharedFloor(t *testing.T) {
+	manifests := []upgradeMigrationManifest{
+		chainManifest("1.1.0", "1.0.0"),
+		chainManifest("1.0.0", "0.9.0"),
+		chainManifest("1.0.1", "1.0.0"),
+	}
+	if problems := checkMigrationChain(manifests, []string{"1.0.0", "1.0.1", "1.1.0"}); len(problems) != 0 {
+		t.Fatalf("expected continuous chain, got %v", problems)
+	}
+}
+
+func TestCheckMigrationChain_ReportsBreaks(t *testing.T) {
+	cases := []struct {
+		name      string
+		manifests []upgradeMigrationManifest
+		releases  []string
+		want      string
+	}{
+		{
+			name:      "gap",
+			manifests: []upgradeMigrationManifest{chainManifest("1.0.0", "0.9.0"), chainManifest("1.2.0", "1.1.0")},
+			want:      "1.2.0: min_prior_version 1.1.0 skips the previous target 1.0.0 (gap)",
+		},
+		{
+			name:      "overlap",
+			manifests: []upgradeMigrationManifest{chainManifest("1.0.0", "0.9.0"), chainManifest("1.0.0", "0.9.0")},
+			want:      "target_version 1.0.0 has more than one migration manifest",
+		},
+		{
+			name:      "floor not older than target",
+			manifests: []upgradeMigrationManifest{chainManifest("1.0.0", "0.9.0"), chainManifest("1.1.0", "1.1.0")},
+			want:      "1.1.0: min_prior_version 1.1.0 is not older than its target_version",
+		},
+		{
+			name:      "unknown floor",
+			manifests: []upgradeMigrationManifest{chainManifest("1.0.0", "0.9.0"), chainManifest("1.1.0", "0.9.5")},
+			want:      "1.1.0: min_prior_version 0.9.5 is not the target of any earlier migration manifest",
+		},
+		{
+			name:      "release without manifest",
+			manifests: []upgradeMigrationManifest{chainManifest("1.0.0", "0.9.0")},
+			releases:  []string{"1.0.0", "1.0.1"},
+			want:      "release 1.0.1 has a template manifest but no migration manifest",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			problems := checkMigrationChain(tc.manifests, tc.releases)
+			if !strings.Contains(strings.Join(problems, "\n"), tc.want) {
+				t.Fatalf("expected %q in %v", tc.want, problems)
+			}
+		})
+	}
+}
@@ -1173,71 +1173,86 @@ func TestLoadAndValidateUpgradeMigrationManifest_Errors(t *testing.T) {
 	})
 }
 
-func TestMatchesTemplateDocsManifest_UsesDocsPaths(t *testing.T) {
+func TestScoreTemplateManifestMatch_WeightsEntries(t *testing.T) {
 	root := t.TempDir()
 	docsPath := filepath.Join(root, "docs", "agent-layer", "ROADMAP.md")
-	if err := os.MkdirAll(filepath.Dir(docsPath), 0o700); err != nil {
-		t.Fatalf("mkdir docs path: %v", err)
+	instructionsPath := filepath.Join(root, ".agent-layer", "instructions", "00_base.md")
+	for _, p := range []string{docsPath, instructionsPath} {
+		if err := os.MkdirAll(filepath.Dir(p), 0o700); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
 	}
 	content := []byte("roadmap content\n")
 	if err := os.WriteFile(docsPath, content, 0o600); err != nil {
 		t.Fatalf("write docs file: %v", err)
 	}
+	if err := os.WriteFile(instructionsPath, []byte("base\r\n"), 0o600); err != nil {
+		t.Fatalf("write instructions file: %v", err)
+	}
 	inst := &installer{root: root, sys: RealSystem{}}
 
-	matchManifest := templateManifest{
+	manifest := templateManifest{
 		Files: []manifestFileEntry{
-			{
-				Path:               "docs/agent-layer/ROADMAP.md",
-				FullHashNormalized: hashNormalizedContent(content),
-			},
+			{Path: "docs/agent-layer/ROADMAP.md", FullHashNormalized: "wrong-hash"},
+			{Path: ".agent-layer/instructions/00_base.md", FullHashNormalized: hashNormalizedContent([]byte("base\n"))},
+			{Path: "docs/agent-layer/MISSING.md", FullHashNormalized: "unused"},
 		},
 	}
-	match, err := inst.matchesTemplateDocsManifest(matchManifest)
+	score, err := inst.scoreTemplateManifestMatch(manifest)
 	if err != nil {
-		t.Fatalf("matchesTemplateDocsManifest match: %v", err)
+		t.Fatalf("scoreTemplateManifestMatch: %v", err)
 	}
-	if !match {
-		t.Fatal("expected docs manifest match")
+	if score.matched != 3 || score.present != 4 || score.ratio() != 0.75 {
+		t.Fatalf("unexpected score: %#v", score)
 	}
 
-	mismatchManifest := templateManifest{
-		Files: []manifestFileEntry{
-			{
-				Path:               "docs/agent-layer/ROADMAP.md",
-				FullHashNormalized: "wrong-hash",
-			},
-		},
+	readFault := newFaultSystem(RealSystem{})
+	readFault.readErrs[normalizePath(docsPath)] = errors.New("read boom")
+	inst = &installer{root: root, sys: readFault}
+	if _, err := inst.scoreTemplateManifestMatch(manifest); err == nil || !strings.Contains(err.Error(), "read boom") {
+		t.Fatalf("expected read error from scoreTemplateManifestMatch, got %v", err)
 	}
-	match, err = inst.matchesTemplateDocsManifest(mismatchManifest)
-	if err != nil {
-		t.Fatalf("matchesTemplateDocsManifest mismatch: %v", err)
+}
+
+func TestInferSourceVersionFromManifestMatch_ToleratesEditedDoc(t *testing.T) {
+	files := map[string]string{
+		".agent-layer/instructions/00_base.md": "base\n",
+		".agent-layer/skills/a/SKILL.md":       "skill\n",
+		"docs/agent-layer/ROADMAP.md":          "roadmap\n",
 	}
-	if match {
-		t.Fatal("expected docs manifest mismatch")
+	older := map[string]string{
+		".agent-layer/instructions/00_base.md": "old base\n",
+		".agent-layer/skills/a/SKILL.md":       "skill\n",
+		"docs/agent-layer/ROADMAP.md":          "roadmap\n",
 	}
-
-	missingManifest := templateManifest{
-		Files: []manifestFileEntry{
-			{
-				Path:               "docs/agent-layer/MISSING.md",
-				FullHashNormalized: "unused",
-			},
-		},
+	stubTemplateManifests(t, rebuildTestManifest("2.0.0", files), rebuildTestManifest("1.0.0", older))
+	root := t.TempDir()
+	for rel, content := range files {
+		writeRebuildFile(t, root, rel, content)
 	}
-	match, err = inst.matchesTemplateDocsManifest(missingManifest)
+	writeRebuildFile(t, root, "docs/agent-layer/ROADMAP.md", "locally edited\n")
+
+	inst := &installer{root: root, sys: RealSystem{}}
+	versionValue, note, err := inst.inferSourceVersionFromManifestMatch()
 	if err != nil {
-		t.Fatalf("matchesTemplateDocsManifest missing file: %v", err)
+		t.Fatalf("inferSourceVersionFromManifestMatch: %v", err)
+	}
+	if versionValue != "2.0.0" {
+		t.Fatalf("version = %q, want 2.0.0 (note %q)", versionValue, note)
 	}
-	if match {
-		t.Fatal("expected missing docs file to not match")
+	if !strings.Contains(note, "2.0.0 scored 0.86 (6 of 7") || !strings.Contains(note, "runner-up 1.0.0 scored 0.43") {
+		t.Fatalf("unexpected note: %q", note)
 	}
 
-	readFault := newFaultSystem(RealSystem{})
-	readFault.readErrs[normalizePath(docsPath)] = errors.New("read boom")
-	inst = &installer{root: root, sys: readFault}
-	if _, err := inst.matchesTemplateDocsManifest(matchManifest); err == nil || !strings.Contains(err.Error(), "read boom") {
-		t.Fatalf("expected read error from matchesTemplateDocsManifest, got %v", err)
+	res := inst.resolveUpgradeMigrationSourceVersion()
+	if res.origin != UpgradeMigrationSourceManifestMatch || res.version != "2.0.0" || !strings.Contains(strings.Join(res.notes, "\n"), "scored 0.86") {
+		t.Fatalf("unexpected resolution: %#v", res)
+	}
+
+	writeRebuildFile(t, root, ".agent-layer/skills/a/SKILL.md", "rewritten\n")
+	versionValue, note, err = inst.inferSourceVersionFromManifestMatch()
+	if err != nil || versionValue != "" || !strings.Contains(note, "below 0.75") {
+		t.Fatalf("expected low score to stay unresolved, got %q note %q err %v", versionValue, note, err)
 	}
 }
 
@@ -1322,7 +1337,7 @@ func TestInferSourceVersionFromManifestMatch_ReturnsSingleCandidate(t *testing.T
 	}
 
 	inst := &installer{root: root, sys: RealSystem{}}
-	versionValue, err := inst.inferSourceVersionFromManifestMatch()
+	versionValue, _, err := inst.inferSourceVersionFromManifestMatch()
 	if err != nil {
 		t.Fatalf("inferSourceVersionFromManifestMatch: %v", err)
 	}
@@ -1757,7 +1772,7 @@ func TestInferSourceVersionFromManifestMatch_SuccessfulMatch(t *testing.T) {
 	}
 
 	inst := &installer{root: root, sys: RealSystem{}}
-	version, err := inst.inferSourceVersionFromManifestMatch()
+	version, _, err := inst.inferSourceVersionFromManifestMatch()
 	if err != nil {
 		t.Fatalf("inferSourceVersionFromManifestMatch: %v", err)
 	}
@@ -1831,7 +1846,7 @@ func TestInferSourceVersionFromManifestMatch_MultipleMatchesReturnsEmpty(t *test
 	}
 
 	inst := &installer{root: root, sys: RealSystem{}}
-	version, err := inst.inferSourceVersionFromManifestMatch()
+	version, _, err := inst.inferSourceVersionFromManifestMatch()
 	if err != nil {
 		t.Fatalf("inferSourceVersionFromManifestMatch: %v", err)
 	}
@@ -2279,7 +2294,7 @@ func TestInferSourceVersionFromManifestMatch_MatchError(t *testing.T) {
 	}
 
 	inst := &installer{root: root, sys: fault}
-	_, err := inst.inferSourceVersionFromManifestMatch()
+	_, _, err := inst.inferSourceVersionFromManifestMatch()
 	if err == nil || !strings.Contains(err.Error(), "read boom match") {
 		t.Fatalf("expected match error, got %v", err)
 	}
@@ -2376,16 +2391,16 @@ func TestWriteMigrationConfigMap_EmptyMapAddsTrailingNewline(t *testing.T) {
 	}
 }
 
-func TestMatchesTemplateDocsManifest_NoDocsEntries(t *testing.T) {
+func TestScoreTemplateManifestMatch_MissingFilesScoreZero(t *testing.T) {
 	inst := &installer{root: t.TempDir(), sys: RealSystem{}}
-	match, err := inst.matchesTemplateDocsManifest(templateManifest{
+	score, err := inst.scoreTemplateManifestMatch(templateManifest{
 		Files: []manifestFileEntry{{Path: ".agent-layer/config.toml"}},
 	})
 	if err != nil {
-		t.Fatalf("matchesTemplateDocsManifest: %v", err)
+		t.Fatalf("scoreTemplateManifestMatch: %v", err)
 	}
-	if match {
-		t.Fatal("expected no match when manifest has no docs entries")
+	if score.matched != 0 || score.present != 0 || score.ratio() != 0 {
+		t.Fatalf("expected zero score when no manifest files exist, got %#v", score)
 	}
 }
 
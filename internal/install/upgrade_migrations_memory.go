@@ -0,0 +1,167 @@
+package install
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/conn-castle/agent-layer/internal/i18n"
+	"github.com/conn-castle/agent-layer/internal/messages"
+)
+
+// Placements accepted by append_managed_section operations (the op's `to`).
+const (
+	// managedSectionAboveMarker replaces the managed header that ends at the
+	// memory doc's marker line.
+	managedSectionAboveMarker = "above_marker"
+	// managedSectionBelowMarker inserts a block immediately below the marker,
+	// where memory docs keep their newest entries.
+	managedSectionBelowMarker = "below_marker"
+)
+
+// executeAppendManagedSection updates a memory doc around its section marker
+// without touching user entries. See evaluateAppendManagedSection.
+func (inst *installer) executeAppendManagedSection(op upgradeMigrationOperation) (bool, error) {
+	absPath, _, updated, reason, err := inst.evaluateAppendManagedSection(op)
+	if err != nil || reason != "" {
+		return false, err
+	}
+	if wErr := inst.sys.WriteFileAtomic(absPath, []byte(updated), 0o644); wErr != nil {
+		return false, i18n.Errorf(messages.InstallFailedWriteFmt, absPath, wErr)
+	}
+	return true, nil
+}
+
+// previewAppendManagedSection renders the planned section update as a unified
+// diff. A non-empty reason means the operation will not apply.
+func (inst *installer) previewAppendManagedSection(op upgradeMigrationOperation) (string, string, error) {
+	_, current, updated, reason, err := inst.evaluateAppendManagedSection(op)
+	if err != nil || reason != "" {
+		return "", reason, err
+	}
+	name := normalizeRelPath(filepath.Clean(filepath.FromSlash(op.Path)))
+	diff, _, _, _ := renderTruncatedUnifiedDiff(name+" (current)", name+" (migrated)", current, updated, inst.diffMaxLines)
+	return diff, "", nil
+}
+
+// evaluateAppendManagedSection computes the migrated memory doc in memory.
+//
+// For above_marker, op.Value is the new managed header (everything before the
+// marker line). It replaces the current header only when that header hashes
+// to the managed_section_hash of some released manifest for the path, i.e.
+// the user has not edited it; a customized header is left for review.
+//
+// For below_marker, op.Value is inserted right after the marker unless op.From
+// (a duplicate-detection string) already appears in the file.
+func (inst *installer) evaluateAppendManagedSection(op upgradeMigrationOperation) (string, string, string, string, error) {
+	relPath := normalizeRelPath(filepath.Clean(filepath.FromSlash(op.Path)))
+	marker, ok := sectionAwareMarkerForPath(relPath)
+	if !ok {
+		return "", "", "", "", fmt.Errorf("append_managed_section path %s is not a memory doc with a section marker", relPath)
+	}
+	absPath, err := snapshotEntryAbsPath(inst.root, op.Path)
+	if err != nil {
+		return "", "", "", "", err
+	}
+	var block string
+	if err := json.Unmarshal(op.Value, &block); err != nil {
+		return "", "", "", "", fmt.Errorf("decode append_managed_section content for %s: %w", relPath, err)
+	}
+	data, err := inst.sys.ReadFile(absPath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return absPath, "", "", "file is absent", nil
+		}
+		return "", "", "", "", i18n.Errorf(messages.InstallFailedReadFmt, absPath, err)
+	}
+	current := normalizeTemplateContent(string(data))
+	managed, user, err := splitSectionAwareContent(relPath, marker, []byte(current))
+	if err != nil {
+		return absPath, current, "", fmt.Sprintf("section marker unusable: %v", err), nil
+	}
+	block = strings.ReplaceAll(block, "\r\n", "\n")
+	if !strings.HasSuffix(block, "\n") {
+		block += "\n"
+	}
+
+	switch op.To {
+	case managedSectionAboveMarker:
+		updatedManaged := block + marker + "\n"
+		if managed == updatedManaged {
+			return absPath, current, "", "managed section is already up to date", nil
+		}
+		pristine, err := isReleasedManagedSection(relPath, managed, marker)
+		if err != nil {
+			return "", "", "", "", err
+		}
+		if !pristine {
+			return absPath, current, "", "managed section has local edits; review the memory file diff instead", nil
+		}
+		return absPath, current, updatedManaged + user, "", nil
+	case managedSectionBelowMarker:
+		if op.From != "" && strings.Contains(current, op.From) {
+			return absPath, current, "", "block is already present", nil
+		}
+		return absPath, current, managed + block + user, "", nil
+	default:
+		return "", "", "", "", fmt.Errorf("append_managed_section placement %q is not supported", op.To)
+	}
+}
+
+// isReleasedManagedSection reports whether managed (the content up to and
+// including the marker line) matches the managed_section_hash recorded in any
+// embedded manifest's memory policy payload for relPath.
+func isReleasedManagedSection(relPath string, managed string, marker string) (bool, error) {
+	localHash, _, err := hashManagedMarkerSection(managed, marker)
+	if err != nil {
+		return false, err
+	}
+	manifests, err := loadAllTemplateManifests()
+	if err != nil {
+		return false, err
+	}
+	for _, manifest := range manifests {
+		for _, entry := range manifest.Files {
+			if entry.Path != relPath || len(entry.PolicyPayload) == 0 {
+				continue
+			}
+			payload, err := parseMemoryPolicyPayload(entry.PolicyID, entry.PolicyPayload)
+			if err != nil {
+				return false, err
+			}
+			if payload.ManagedSectionHash == localHash {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+func validateAppendManagedSectionOperation(op upgradeMigrationOperation) error {
+	if strings.TrimSpace(op.Path) == "" {
+		return fmt.Errorf("migration %s (%s) requires path", op.ID, op.Kind)
+	}
+	if _, ok := sectionAwareMarkerForPath(normalizeRelPath(filepath.Clean(filepath.FromSlash(op.Path)))); !ok {
+		return fmt.Errorf("migration %s (%s) path %s is not a memory doc with a section marker", op.ID, op.Kind, op.Path)
+	}
+	if op.To != managedSectionAboveMarker && op.To != managedSectionBelowMarker {
+		return fmt.Errorf("migration %s (%s) requires to of %s or %s", op.ID, op.Kind, managedSectionAboveMarker, managedSectionBelowMarker)
+	}
+	if len(op.Value) == 0 {
+		return fmt.Errorf("migration %s (%s) requires value", op.ID, op.Kind)
+	}
+	var block string
+	if err := json.Unmarshal(op.Value, &block); err != nil {
+		return fmt.Errorf("migration %s (%s) value must be a JSON string: %w", op.ID, op.Kind, err)
+	}
+	if strings.TrimSpace(block) == "" {
+		return fmt.Errorf("migration %s (%s) value must not be empty", op.ID, op.Kind)
+	}
+	if marker, _ := sectionAwareMarkerForPath(normalizeRelPath(filepath.Clean(filepath.FromSlash(op.Path)))); strings.Contains(block, marker) {
+		return fmt.Errorf("migration %s (%s) value must not contain the section marker", op.ID, op.Kind)
+	}
+	return nil
+}
@@ -0,0 +1,141 @@
+package install
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const (
+	memoryTestHeaderV1 = "# Issues\n\nOld format notes.\n\n"
+	memoryTestHeaderV2 = "# Issues\n\nNew format notes.\n\n"
+)
+
+func stubMemoryManifest(t *testing.T, header string) {
+	t.Helper()
+	hash, _, err := hashManagedMarkerSection(header+ownershipMarkerEntriesStart+"\n", ownershipMarkerEntriesStart)
+	if err != nil {
+		t.Fatalf("hash managed section: %v", err)
+	}
+	payload, err := json.Marshal(memoryPolicyPayload{Marker: ownershipMarkerEntriesStart, ManagedSectionHash: hash})
+	if err != nil {
+		t.Fatalf("marshal payload: %v", err)
+	}
+	stubTemplateManifests(t, templateManifest{
+		SchemaVersion: templateManifestSchemaVersion,
+		Version:       "1.0.0",
+		Files:         []manifestFileEntry{{Path: issuesPath, PolicyID: ownershipPolicyMemoryEntries, PolicyPayload: payload}},
+	})
+}
+
+func appendManagedSectionOp(to string, value string, from string) upgradeMigrationOperation {
+	encoded, _ := json.Marshal(value)
+	return upgradeMigrationOperation{
+		ID:        "issues_section",
+		Kind:      upgradeMigrationKindAppendManagedSection,
+		Rationale: "Update issues format",
+		Path:      issuesPath,
+		To:        to,
+		From:      from,
+		Value:     encoded,
+	}
+}
+
+func TestAppendManagedSection_AboveMarkerReplacesPristineHeader(t *testing.T) {
+	stubMemoryManifest(t, memoryTestHeaderV1)
+	root := t.TempDir()
+	userEntries := "- Issue 2026-01-01 keep-me: User entry\n"
+	writeRebuildFile(t, root, issuesPath, memoryTestHeaderV1+ownershipMarkerEntriesStart+"\n"+userEntries)
+	inst := &installer{root: root, sys: RealSystem{}}
+	op := appendManagedSectionOp(managedSectionAboveMarker, memoryTestHeaderV2, "")
+
+	diff, reason, err := inst.previewAppendManagedSection(op)
+	if err != nil || reason != "" || !containsAll(diff, "-Old format notes.", "+New format notes.") {
+		t.Fatalf("unexpected preview: diff %q reason %q err %v", diff, reason, err)
+	}
+	changed, err := inst.executeAppendManagedSection(op)
+	if err != nil || !changed {
+		t.Fatalf("execute: changed=%v err=%v", changed, err)
+	}
+	data, err := os.ReadFile(filepath.Join(root, issuesPath)) // #nosec G304 -- path is constructed from test-controlled inputs.
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	if string(data) != memoryTestHeaderV2+ownershipMarkerEntriesStart+"\n"+userEntries {
+		t.Fatalf("unexpected content:\n%s", string(data))
+	}
+	if _, reason, _ := inst.previewAppendManagedSection(op); reason != "managed section is already up to date" {
+		t.Fatalf("expected up-to-date reason on rerun, got %q", reason)
+	}
+}
+
+func TestAppendManagedSection_AboveMarkerSkipsEditedHeader(t *testing.T) {
+	stubMemoryManifest(t, memoryTestHeaderV1)
+	root := t.TempDir()
+	writeRebuildFile(t, root, issuesPath, "# Issues\n\nMy own notes.\n\n"+ownershipMarkerEntriesStart+"\n")
+	inst := &installer{root: root, sys: RealSystem{}}
+
+	changed, err := inst.executeAppendManagedSection(appendManagedSectionOp(managedSectionAboveMarker, memoryTestHeaderV2, ""))
+	if err != nil || changed {
+		t.Fatalf("edited header must be left alone: changed=%v err=%v", changed, err)
+	}
+	_, reason, err := inst.previewAppendManagedSection(appendManagedSectionOp(managedSectionAboveMarker, memoryTestHeaderV2, ""))
+	if err != nil || !strings.Contains(reason, "local edits") {
+		t.Fatalf("expected local edits reason, got %q err %v", reason, err)
+	}
+}
+
+func TestAppendManagedSection_BelowMarkerInsertsOnce(t *testing.T) {
+	root := t.TempDir()
+	writeRebuildFile(t, root, issuesPath, memoryTestHeaderV1+ownershipMarkerEntriesStart+"\n- Issue old: entry\n")
+	inst := &installer{root: root, sys: RealSystem{}}
+	op := appendManagedSectionOp(managedSectionBelowMarker, "- Issue 2026-02-01 seeded: Seeded entry\n\n", "seeded: Seeded entry")
+
+	changed, err := inst.executeAppendManagedSection(op)
+	if err != nil || !changed {
+		t.Fatalf("execute: changed=%v err=%v", changed, err)
+	}
+	data, err := os.ReadFile(filepath.Join(root, issuesPath)) // #nosec G304 -- path is constructed from test-controlled inputs.
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	want := memoryTestHeaderV1 + ownershipMarkerEntriesStart + "\n- Issue 2026-02-01 seeded: Seeded entry\n\n- Issue old: entry\n"
+	if string(data) != want {
+		t.Fatalf("unexpected content:\n%s", string(data))
+	}
+	if changed, err := inst.executeAppendManagedSection(op); err != nil || changed {
+		t.Fatalf("rerun must be a no-op: changed=%v err=%v", changed, err)
+	}
+
+	missing := &installer{root: t.TempDir(), sys: RealSystem{}}
+	if _, reason, err := missing.previewAppendManagedSection(op); err != nil || reason != "file is absent" {
+		t.Fatalf("expected absent reason, got %q err %v", reason, err)
+	}
+}
+
+func TestValidateUpgradeMigrationOperation_AppendManagedSection(t *testing.T) {
+	valid := appendManagedSectionOp(managedSectionBelowMarker, "- entry\n", "entry")
+	if err := validateUpgradeMigrationOperation(valid); err != nil {
+		t.Fatalf("expected valid operation, got %v", err)
+	}
+	cases := map[string]func(op *upgradeMigrationOperation){
+		"requires path":         func(op *upgradeMigrationOperation) { op.Path = "" },
+		"not a memory doc":      func(op *upgradeMigrationOperation) { op.Path = ".agent-layer/instructions/00_rules.md" },
+		"requires to of":        func(op *upgradeMigrationOperation) { op.To = "middle" },
+		"requires value":        func(op *upgradeMigrationOperation) { op.Value = nil },
+		"must be a JSON string": func(op *upgradeMigrationOperation) { op.Value = []byte(`1`) },
+		"must not be empty":     func(op *upgradeMigrationOperation) { op.Value = []byte(`"  "`) },
+		"must not contain the section marker": func(op *upgradeMigrationOperation) {
+			op.Value, _ = json.Marshal(ownershipMarkerEntriesStart)
+		},
+	}
+	for wantErr, mutate := range cases {
+		op := valid
+		mutate(&op)
+		if err := validateUpgradeMigrationOperation(op); err == nil || !strings.Contains(err.Error(), wantErr) {
+			t.Fatalf("expected %q error, got %v", wantErr, err)
+		}
+	}
+}
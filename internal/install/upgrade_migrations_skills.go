@@ -9,6 +9,8 @@ import (
 	"sort"
 	"strings"
 
+	"github.com/conn-castle/agent-layer/internal/errcode"
+	"github.com/conn-castle/agent-layer/internal/i18n"
 	"github.com/conn-castle/agent-layer/internal/messages"
 )
 
@@ -41,7 +43,7 @@ func (inst *installer) preflightAndConfirmSkillsMigration() error {
 
 	if _, statErr := inst.sys.Stat(absSkillsDir); statErr != nil {
 		if !errors.Is(statErr, os.ErrNotExist) {
-			return fmt.Errorf(messages.InstallFailedStatFmt, absSkillsDir, statErr)
+			return i18n.Errorf(messages.InstallFailedStatFmt, absSkillsDir, statErr)
 		}
 		// Try the legacy pre-rename path.
 		legacyPath := filepath.Join(inst.root, ".agent-layer", "slash-commands")
@@ -50,7 +52,7 @@ func (inst *installer) preflightAndConfirmSkillsMigration() error {
 				// Neither directory exists — no skills to migrate.
 				return nil
 			}
-			return fmt.Errorf(messages.InstallFailedStatFmt, legacyPath, legacyStatErr)
+			return i18n.Errorf(messages.InstallFailedStatFmt, legacyPath, legacyStatErr)
 		}
 		absSkillsDir = legacyPath
 	}
@@ -114,7 +116,7 @@ func (inst *installer) preflightAndConfirmSkillsMigration() error {
 		if ew.err != nil {
 			return ew.err
 		}
-		return fmt.Errorf(messages.InstallSkillsMigrationBlockedErrFmt, len(conflicts))
+		return errcode.Wrap(errcode.UpgradeConflict, i18n.Errorf(messages.InstallSkillsMigrationBlockedErrFmt, len(conflicts)))
 	}
 
 	ew.println()
@@ -131,10 +133,10 @@ func (inst *installer) preflightAndConfirmSkillsMigration() error {
 		conflicts:  conflicts,
 	})
 	if promptErr != nil {
-		return fmt.Errorf(messages.InstallSkillsMigrationPromptErrFmt, promptErr)
+		return i18n.Errorf(messages.InstallSkillsMigrationPromptErrFmt, promptErr)
 	}
 	if !resp.approved {
-		return fmt.Errorf(messages.InstallSkillsMigrationDeclinedErr)
+		return i18n.Errorf(messages.InstallSkillsMigrationDeclinedErr)
 	}
 
 	inst.skillsMigrationConfirmed = true
@@ -154,7 +156,7 @@ func (inst *installer) executeMigrateSkillsFormat(relSkillsDir string) (bool, er
 		if errors.Is(statErr, os.ErrNotExist) {
 			return false, nil // no skills directory — no-op
 		}
-		return false, fmt.Errorf(messages.InstallFailedStatFmt, absSkillsDir, statErr)
+		return false, i18n.Errorf(messages.InstallFailedStatFmt, absSkillsDir, statErr)
 	}
 
 	flatSkills, scanErr := listFlatSkillNames(inst.sys, absSkillsDir)
@@ -174,7 +176,7 @@ func (inst *installer) executeMigrateSkillsFormat(relSkillsDir string) (bool, er
 			return false, preErr
 		}
 		if len(conflicts) > 0 {
-			return false, fmt.Errorf(messages.InstallSkillsMigrationBlockedErrFmt, len(conflicts))
+			return false, errcode.Wrap(errcode.UpgradeConflict, i18n.Errorf(messages.InstallSkillsMigrationBlockedErrFmt, len(conflicts)))
 		}
 		resp, promptErr := inst.promptRouter().route(promptRequest{
 			kind:       promptKindConfirmSkillsMigration,
@@ -182,10 +184,10 @@ func (inst *installer) executeMigrateSkillsFormat(relSkillsDir string) (bool, er
 			conflicts:  conflicts,
 		})
 		if promptErr != nil {
-			return false, fmt.Errorf(messages.InstallSkillsMigrationPromptErrFmt, promptErr)
+			return false, i18n.Errorf(messages.InstallSkillsMigrationPromptErrFmt, promptErr)
 		}
 		if !resp.approved {
-			return false, fmt.Errorf(messages.InstallSkillsMigrationDeclinedErr)
+			return false, i18n.Errorf(messages.InstallSkillsMigrationDeclinedErr)
 		}
 	}
 
@@ -202,7 +204,7 @@ func (inst *installer) executeMigrateSkillsFormat(relSkillsDir string) (bool, er
 		destInfo, destStatErr := inst.sys.Stat(destPath)
 		destExisted := destStatErr == nil && !destInfo.IsDir()
 		if destStatErr != nil && !errors.Is(destStatErr, os.ErrNotExist) {
-			return false, fmt.Errorf(messages.InstallFailedStatFmt, destPath, destStatErr)
+			return false, i18n.Errorf(messages.InstallFailedStatFmt, destPath, destStatErr)
 		}
 
 		migrated, migErr := migrateSingleFlatSkill(inst.sys, flatPath, destDir, destPath)
@@ -262,7 +264,7 @@ func preflightSkillsMigration(sys System, absSkillsDir string) (flatCount int, c
 			if errors.Is(statErr, os.ErrNotExist) {
 				continue // no conflict
 			}
-			return 0, nil, fmt.Errorf(messages.InstallFailedStatFmt, destPath, statErr)
+			return 0, nil, i18n.Errorf(messages.InstallFailedStatFmt, destPath, statErr)
 		}
 		if destInfo.IsDir() {
 			continue
@@ -271,18 +273,18 @@ func preflightSkillsMigration(sys System, absSkillsDir string) (flatCount int, c
 		// Both exist — check content.
 		flatData, flatReadErr := sys.ReadFile(flatPath)
 		if flatReadErr != nil {
-			return 0, nil, fmt.Errorf(messages.InstallFailedReadFmt, flatPath, flatReadErr)
+			return 0, nil, i18n.Errorf(messages.InstallFailedReadFmt, flatPath, flatReadErr)
 		}
 		destData, destReadErr := sys.ReadFile(destPath)
 		if destReadErr != nil {
-			return 0, nil, fmt.Errorf(messages.InstallFailedReadFmt, destPath, destReadErr)
+			return 0, nil, i18n.Errorf(messages.InstallFailedReadFmt, destPath, destReadErr)
 		}
 		if normalizeTemplateContent(string(flatData)) != normalizeTemplateContent(string(destData)) {
 			conflicts = append(conflicts, SkillsMigrationConflict{
 				SkillName: name,
 				FlatPath:  flatPath,
 				DirPath:   destPath,
-				Reason:    fmt.Sprintf(messages.InstallSkillsMigrationConflictReasonFmt, name, name),
+				Reason:    i18n.Sprintf(messages.InstallSkillsMigrationConflictReasonFmt, name, name),
 			})
 		}
 	}
@@ -317,7 +319,7 @@ type skillsDirEntry struct {
 func readSkillsDirEntries(sys System, dir string) ([]skillsDirEntry, error) {
 	info, statErr := sys.Stat(dir)
 	if statErr != nil {
-		return nil, fmt.Errorf(messages.InstallFailedStatFmt, dir, statErr)
+		return nil, i18n.Errorf(messages.InstallFailedStatFmt, dir, statErr)
 	}
 	if !info.IsDir() {
 		return nil, fmt.Errorf("%s is not a directory", dir)
@@ -350,22 +352,22 @@ func migrateSingleFlatSkill(sys System, flatPath string, destDir string, destPat
 		if errors.Is(statErr, os.ErrNotExist) {
 			return false, nil
 		}
-		return false, fmt.Errorf(messages.InstallFailedStatFmt, flatPath, statErr)
+		return false, i18n.Errorf(messages.InstallFailedStatFmt, flatPath, statErr)
 	}
 
 	destInfo, destStatErr := sys.Stat(destPath)
 	if destStatErr != nil && !errors.Is(destStatErr, os.ErrNotExist) {
-		return false, fmt.Errorf(messages.InstallFailedStatFmt, destPath, destStatErr)
+		return false, i18n.Errorf(messages.InstallFailedStatFmt, destPath, destStatErr)
 	}
 	if destStatErr == nil && !destInfo.IsDir() {
 		// Destination exists — check for same content (duplicate cleanup).
 		flatData, readErr := sys.ReadFile(flatPath)
 		if readErr != nil {
-			return false, fmt.Errorf(messages.InstallFailedReadFmt, flatPath, readErr)
+			return false, i18n.Errorf(messages.InstallFailedReadFmt, flatPath, readErr)
 		}
 		destData, readErr := sys.ReadFile(destPath)
 		if readErr != nil {
-			return false, fmt.Errorf(messages.InstallFailedReadFmt, destPath, readErr)
+			return false, i18n.Errorf(messages.InstallFailedReadFmt, destPath, readErr)
 		}
 		if normalizeTemplateContent(string(flatData)) == normalizeTemplateContent(string(destData)) {
 			// Same content — remove flat file.
@@ -380,7 +382,7 @@ func migrateSingleFlatSkill(sys System, flatPath string, destDir string, destPat
 
 	// Create destination directory and move.
 	if mkErr := sys.MkdirAll(destDir, 0o755); mkErr != nil {
-		return false, fmt.Errorf(messages.InstallFailedCreateDirForFmt, destPath, mkErr)
+		return false, i18n.Errorf(messages.InstallFailedCreateDirForFmt, destPath, mkErr)
 	}
 	if renameErr := sys.Rename(flatPath, destPath); renameErr != nil {
 		return false, fmt.Errorf("rename %s -> %s: %w", flatPath, destPath, renameErr)
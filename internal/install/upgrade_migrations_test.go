@@ -13,6 +13,7 @@ import (
 	"testing"
 
 	"github.com/conn-castle/agent-layer/internal/config"
+	"github.com/conn-castle/agent-layer/internal/output"
 	"github.com/conn-castle/agent-layer/internal/templates"
 	"github.com/conn-castle/agent-layer/internal/version"
 )
@@ -488,6 +489,39 @@ func TestExecuteConfigSetDefaultMigration_CallsPrompt(t *testing.T) {
 	}
 }
 
+func TestExecuteConfigSetDefaultMigration_RejectsInvalidPromptValue(t *testing.T) {
+	root := t.TempDir()
+	configPath := filepath.Join(root, ".agent-layer", "config.toml")
+	if err := os.MkdirAll(filepath.Dir(configPath), 0o700); err != nil {
+		t.Fatalf("mkdir config dir: %v", err)
+	}
+	if err := os.WriteFile(configPath, []byte("[dispatch]\n"), 0o600); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	prompter := PromptFuncs{
+		ConfigSetDefaultFunc: func(string, any, string, *config.FieldDef) (any, error) {
+			return int64(0), nil
+		},
+	}
+	inst := &installer{root: root, prompter: prompter, sys: RealSystem{}}
+	op := upgradeMigrationOperation{
+		ID:    "set-max-depth",
+		Kind:  upgradeMigrationKindConfigSetDefault,
+		Key:   "dispatch.max_depth",
+		Value: []byte(`3`),
+	}
+	if _, err := inst.executeConfigSetDefaultMigration(op); err == nil || !strings.Contains(err.Error(), "dispatch.max_depth must be a positive integer") {
+		t.Fatalf("expected validation error, got %v", err)
+	}
+	data, err := os.ReadFile(configPath) // #nosec G304 -- path is constructed from test-controlled inputs.
+	if err != nil {
+		t.Fatalf("read config: %v", err)
+	}
+	if strings.Contains(string(data), "max_depth") {
+		t.Fatalf("invalid value must not be written, got:\n%s", string(data))
+	}
+}
+
 func TestExecuteConfigSetDefaultMigration_NoPromptUsesDefault(t *testing.T) {
 	root := t.TempDir()
 
@@ -3322,7 +3356,7 @@ func TestRun_SkillsMigrationDeclinedBeforeMutations(t *testing.T) {
 		Overwrite:  true,
 		Prompter:   prompter,
 		PinVersion: "0.9.0",
-		WarnWriter: &warn,
+		Output:     output.New(&warn, &warn, output.Normal),
 	})
 
 	// ── Verify error ──
@@ -3455,7 +3489,7 @@ func TestRun_SkillsMigrationBlockedByConflict(t *testing.T) {
 		Overwrite:  true,
 		Prompter:   prompter,
 		PinVersion: "0.9.0",
-		WarnWriter: &warn,
+		Output:     output.New(&warn, &warn, output.Normal),
 	})
 
 	// ── Verify error ──
@@ -3890,6 +3924,134 @@ func TestRunMigrations_AppendToFileAppliesAndReports(t *testing.T) {
 	}
 }
 
+func TestRunMigrations_TransformFilePlansDiffAndApplies(t *testing.T) {
+	root := t.TempDir()
+	targetPath := filepath.Join(root, "docs", "agent-layer", "ISSUES.md")
+	if err := os.MkdirAll(filepath.Dir(targetPath), 0o700); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	original := "# Issues\nSee al sync --dry for details.\n- user entry\n"
+	if err := os.WriteFile(targetPath, []byte(original), 0o600); err != nil {
+		t.Fatalf("write target: %v", err)
+	}
+
+	withMigrationManifestOverride(t, "0.7.0", `{
+  "schema_version": 1,
+  "target_version": "0.7.0",
+  "min_prior_version": "0.6.0",
+  "operations": [
+    {
+      "id": "fix_dry_flag",
+      "kind": "transform_file",
+      "rationale": "Fix the retired --dry flag reference",
+      "source_agnostic": true,
+      "path": "docs/agent-layer/ISSUES.md",
+      "from": "al (sync) --dry ",
+      "to": "al $1 --dry-run ",
+      "expected_matches": 1
+    }
+  ]
+}`)
+
+	var warn bytes.Buffer
+	inst := &installer{root: root, pinVersion: "0.7.0", sys: RealSystem{}, warnWriter: &warn}
+	if err := inst.prepareUpgradeMigrations(); err != nil {
+		t.Fatalf("prepareUpgradeMigrations: %v", err)
+	}
+	entry := inst.migrationReport.Entries[0]
+	if entry.Status != UpgradeMigrationStatusPlanned || !containsAll(entry.Diff, "-See al sync --dry for details.", "+See al sync --dry-run for details.") {
+		t.Fatalf("unexpected planned entry: %#v", entry)
+	}
+	targetAbs := filepath.Clean(targetPath)
+	if !containsString(inst.migrationRollbackTargets, targetAbs) {
+		t.Fatalf("rollback targets missing transform path %q: %#v", targetAbs, inst.migrationRollbackTargets)
+	}
+	if _, ok := inst.migrationManifestCoverage["docs/agent-layer/ISSUES.md"]; ok {
+		t.Fatal("transform_file must not hide template diffs for its path")
+	}
+	if err := inst.runMigrations(); err != nil {
+		t.Fatalf("runMigrations: %v", err)
+	}
+	if inst.migrationReport.Entries[0].Status != UpgradeMigrationStatusApplied {
+		t.Fatalf("migration status = %q, want applied", inst.migrationReport.Entries[0].Status)
+	}
+	data, err := os.ReadFile(targetPath) // #nosec G304 -- path is constructed from test-controlled inputs.
+	if err != nil {
+		t.Fatalf("read file: %v", err)
+	}
+	if string(data) != "# Issues\nSee al sync --dry-run for details.\n- user entry\n" {
+		t.Fatalf("unexpected transformed content:\n%s", string(data))
+	}
+
+	// Re-planning finds nothing left to fix.
+	inst = &installer{root: root, pinVersion: "0.7.0", sys: RealSystem{}}
+	plan, err := inst.planUpgradeMigrations()
+	if err != nil {
+		t.Fatalf("planUpgradeMigrations: %v", err)
+	}
+	if plan.report.Entries[0].Status != UpgradeMigrationStatusNoop || !strings.Contains(plan.report.Entries[0].SkipReason, "pattern not found") {
+		t.Fatalf("expected no-op after transform, got %#v", plan.report.Entries[0])
+	}
+}
+
+func TestEvaluateTransformFile_MatchCountGuards(t *testing.T) {
+	root := t.TempDir()
+	targetPath := filepath.Join(root, "NOTES.md")
+	if err := os.WriteFile(targetPath, []byte("old\nold\n"), 0o600); err != nil {
+		t.Fatalf("write target: %v", err)
+	}
+	inst := &installer{root: root, sys: RealSystem{}}
+	op := upgradeMigrationOperation{ID: "t", Kind: upgradeMigrationKindTransformFile, Path: "NOTES.md", From: "old", To: "new", ExpectedMatches: 1}
+
+	if _, reason, err := inst.previewTransformFileMigration(op); err != nil || !strings.Contains(reason, "matched 2 times, expected 1") {
+		t.Fatalf("expected match-count skip, got reason %q err %v", reason, err)
+	}
+	changed, err := inst.executeTransformFile(op)
+	if err != nil || changed {
+		t.Fatalf("mismatched count must leave file unchanged: changed=%v err=%v", changed, err)
+	}
+
+	op.Path = "MISSING.md"
+	if _, reason, err := inst.previewTransformFileMigration(op); err != nil || reason != "file is absent" {
+		t.Fatalf("expected absent skip, got reason %q err %v", reason, err)
+	}
+
+	readFault := newFaultSystem(RealSystem{})
+	readFault.readErrs[normalizePath(targetPath)] = errors.New("read boom")
+	inst = &installer{root: root, sys: readFault}
+	op.Path = "NOTES.md"
+	if _, err := inst.executeTransformFile(op); err == nil || !strings.Contains(err.Error(), "read boom") {
+		t.Fatalf("expected read error, got %v", err)
+	}
+}
+
+func TestValidateUpgradeMigrationOperation_TransformFile(t *testing.T) {
+	validOp := upgradeMigrationOperation{
+		ID:              "transform_test",
+		Kind:            upgradeMigrationKindTransformFile,
+		Rationale:       "Test transform",
+		Path:            "docs/agent-layer/ISSUES.md",
+		From:            `(?m)^old$`,
+		ExpectedMatches: 1,
+	}
+	if err := validateUpgradeMigrationOperation(validOp); err != nil {
+		t.Fatalf("expected valid operation to pass, got: %v", err)
+	}
+	cases := map[string]func(op *upgradeMigrationOperation){
+		"requires path":                      func(op *upgradeMigrationOperation) { op.Path = "" },
+		"requires from pattern":              func(op *upgradeMigrationOperation) { op.From = "" },
+		"invalid from pattern":               func(op *upgradeMigrationOperation) { op.From = "(" },
+		"expected_matches greater than zero": func(op *upgradeMigrationOperation) { op.ExpectedMatches = 0 },
+	}
+	for wantErr, mutate := range cases {
+		op := validOp
+		mutate(&op)
+		if err := validateUpgradeMigrationOperation(op); err == nil || !strings.Contains(err.Error(), wantErr) {
+			t.Fatalf("expected %q error, got %v", wantErr, err)
+		}
+	}
+}
+
 func containsString(values []string, want string) bool {
 	for _, value := range values {
 		if value == want {
@@ -3907,3 +4069,44 @@ func containsAll(text string, parts ...string) bool {
 	}
 	return true
 }
+
+// TestConfigDeprecationsMatchMigrationManifests keeps the config deprecation
+// table and the shipped manifests in sync: every entry must appear, as the
+// generator would write it, in the manifest for the release that migrates it.
+func TestConfigDeprecationsMatchMigrationManifests(t *testing.T) {
+	for _, deprecation := range config.Deprecations() {
+		manifest, manifestPath, err := loadUpgradeMigrationManifestByVersion(deprecation.Since)
+		if err != nil {
+			t.Fatalf("load manifest for %s: %v", deprecation.Key, err)
+		}
+		want := upgradeMigrationOperation{
+			ID:             deprecation.MigrationID,
+			Kind:           upgradeMigrationKindConfigDeleteKey,
+			Rationale:      deprecation.Rationale,
+			SourceAgnostic: true,
+			Key:            deprecation.Key,
+		}
+		if deprecation.Replacement != "" {
+			want.Kind = upgradeMigrationKindConfigRenameKey
+			want.Key = ""
+			want.From = deprecation.Key
+			want.To = deprecation.Replacement
+		}
+		found := false
+		for _, op := range manifest.Operations {
+			if op.ID != want.ID {
+				continue
+			}
+			found = true
+			if !reflect.DeepEqual(op, want) {
+				t.Fatalf("%s operation %s = %+v, want %+v (run scripts/generate-config-migrations.sh --tag v%s)", manifestPath, op.ID, op, want, deprecation.Since)
+			}
+		}
+		if !found {
+			t.Fatalf("%s has no operation %s for deprecated key %s", manifestPath, want.ID, deprecation.Key)
+		}
+		if cmp, err := version.Compare(deprecation.RemovedIn, deprecation.Since); err != nil || cmp < 0 {
+			t.Fatalf("%s: removed_in %s must be a version no earlier than since %s (err=%v)", deprecation.Key, deprecation.RemovedIn, deprecation.Since, err)
+		}
+	}
+}
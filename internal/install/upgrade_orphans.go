@@ -0,0 +1,272 @@
+package install
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/conn-castle/agent-layer/internal/i18n"
+	"github.com/conn-castle/agent-layer/internal/messages"
+	"github.com/conn-castle/agent-layer/internal/version"
+)
+
+const readinessCheckOrphanedArtifacts = "orphaned_generated_files"
+
+// OrphanedArtifact is a file an earlier Agent Layer release installed that the
+// current release no longer ships.
+type OrphanedArtifact struct {
+	// Path is the repo-relative, slash-separated path.
+	Path string
+	// LastVersion is the newest release whose manifest lists the path.
+	LastVersion string
+	// Modified reports that the content matches no released version of the
+	// file, so it was likely edited locally.
+	Modified bool
+}
+
+// historicalArtifact aggregates every embedded manifest entry for one path.
+type historicalArtifact struct {
+	lastVersion string
+	hashes      map[string]struct{}
+}
+
+// historicalArtifacts indexes every path listed by an embedded release
+// manifest, keyed by repo-relative slash path.
+func historicalArtifacts() (map[string]*historicalArtifact, error) {
+	manifests, err := loadAllTemplateManifests()
+	if err != nil {
+		return nil, err
+	}
+	byPath := make(map[string]*historicalArtifact)
+	for manifestVersion, manifest := range manifests {
+		for _, entry := range manifest.Files {
+			artifact, ok := byPath[entry.Path]
+			if !ok {
+				artifact = &historicalArtifact{hashes: make(map[string]struct{})}
+				byPath[entry.Path] = artifact
+			}
+			artifact.hashes[entry.FullHashNormalized] = struct{}{}
+			if artifact.lastVersion == "" {
+				artifact.lastVersion = manifestVersion
+				continue
+			}
+			cmp, err := version.Compare(manifestVersion, artifact.lastVersion)
+			if err != nil {
+				return nil, err
+			}
+			if cmp > 0 {
+				artifact.lastVersion = manifestVersion
+			}
+		}
+	}
+	return byPath, nil
+}
+
+// detectOrphanedArtifacts returns files on disk at a path an older release
+// manifest lists but the current templates no longer produce. User-owned
+// paths are never reported.
+func (inst *installer) detectOrphanedArtifacts() ([]OrphanedArtifact, error) {
+	historical, err := historicalArtifacts()
+	if err != nil {
+		return nil, err
+	}
+	known, err := inst.buildKnownPaths()
+	if err != nil {
+		return nil, err
+	}
+	orphans := make([]OrphanedArtifact, 0)
+	for relPath, artifact := range historical {
+		absPath := filepath.Join(inst.root, filepath.FromSlash(relPath))
+		if _, ok := known[filepath.Clean(absPath)]; ok {
+			continue
+		}
+		if inst.isUserOwned(absPath) {
+			continue
+		}
+		info, err := inst.sys.Stat(absPath)
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+			return nil, i18n.Errorf(messages.InstallFailedStatFmt, absPath, err)
+		}
+		if info.IsDir() {
+			continue
+		}
+		data, err := inst.sys.ReadFile(absPath)
+		if err != nil {
+			return nil, i18n.Errorf(messages.InstallFailedReadFmt, absPath, err)
+		}
+		_, released := artifact.hashes[templateFullHash(data)]
+		orphans = append(orphans, OrphanedArtifact{
+			Path:        relPath,
+			LastVersion: artifact.lastVersion,
+			Modified:    !released,
+		})
+	}
+	sort.Slice(orphans, func(i, j int) bool {
+		return orphans[i].Path < orphans[j].Path
+	})
+	return orphans, nil
+}
+
+// handleOrphanedArtifacts offers to delete orphaned files as one group before
+// the generic unknown-file prompts, and returns the unknowns that still need
+// a decision. Orphans are removed from that list whether or not the user
+// approves deletion so they are never prompted twice. Prompters without the
+// optional OrphanedArtifactsPrompter keep the previous behavior: orphans stay
+// in the unknown-file prompts.
+func (inst *installer) handleOrphanedArtifacts(unknowns []string) ([]string, error) {
+	router := inst.promptRouter()
+	if !router.hasOrphanedArtifacts() {
+		return unknowns, nil
+	}
+	orphans, err := inst.detectOrphanedArtifacts()
+	if err != nil {
+		return nil, err
+	}
+	if len(orphans) == 0 {
+		return unknowns, nil
+	}
+	orphanSet := make(map[string]struct{}, len(orphans))
+	for _, orphan := range orphans {
+		orphanSet[filepath.Join(inst.root, filepath.FromSlash(orphan.Path))] = struct{}{}
+	}
+	remaining := make([]string, 0, len(unknowns))
+	for _, path := range unknowns {
+		covered, err := inst.coveredByOrphans(path, orphanSet)
+		if err != nil {
+			return nil, err
+		}
+		if !covered {
+			remaining = append(remaining, path)
+		}
+	}
+	resp, err := router.route(promptRequest{kind: promptKindDeleteOrphanedAll, orphans: orphans})
+	if err != nil {
+		return nil, err
+	}
+	if !resp.approved {
+		return remaining, nil
+	}
+	if err := inst.deleteOrphanedArtifacts(orphans); err != nil {
+		return nil, err
+	}
+	return remaining, nil
+}
+
+// coveredByOrphans reports whether an unknown path is fully accounted for by
+// orphaned files: either it is one, or it is a directory whose files all are.
+func (inst *installer) coveredByOrphans(path string, orphanSet map[string]struct{}) (bool, error) {
+	if _, ok := orphanSet[path]; ok {
+		return true, nil
+	}
+	info, err := inst.sys.Stat(path)
+	if err != nil || !info.IsDir() {
+		return false, nil
+	}
+	files := 0
+	covered := true
+	walkErr := walkDirFollowingRoot(inst.sys, path, func(walkPath string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			return nil
+		}
+		files++
+		if _, ok := orphanSet[filepath.Clean(walkPath)]; !ok {
+			covered = false
+			return filepath.SkipAll
+		}
+		return nil
+	})
+	if walkErr != nil {
+		return false, walkErr
+	}
+	return covered && files > 0, nil
+}
+
+// deleteOrphanedArtifacts removes orphaned files and then prunes parent
+// directories the removal left empty, stopping at directories Agent Layer
+// still manages.
+func (inst *installer) deleteOrphanedArtifacts(orphans []OrphanedArtifact) error {
+	known, err := inst.buildKnownPaths()
+	if err != nil {
+		return err
+	}
+	for _, orphan := range orphans {
+		absPath := filepath.Join(inst.root, filepath.FromSlash(orphan.Path))
+		if err := inst.sys.RemoveAll(absPath); err != nil {
+			return i18n.Errorf(messages.InstallDeleteUnknownFailedFmt, orphan.Path, err)
+		}
+		for dir := filepath.Dir(absPath); dir != inst.root; dir = filepath.Dir(dir) {
+			if _, ok := known[filepath.Clean(dir)]; ok {
+				break
+			}
+			empty, err := inst.isEmptyDir(dir)
+			if err != nil {
+				return err
+			}
+			if !empty {
+				break
+			}
+			if err := inst.sys.RemoveAll(dir); err != nil {
+				return i18n.Errorf(messages.InstallDeleteUnknownFailedFmt, inst.relativePath(dir), err)
+			}
+		}
+	}
+	return nil
+}
+
+// isEmptyDir reports whether dir exists and has no entries.
+func (inst *installer) isEmptyDir(dir string) (bool, error) {
+	empty := true
+	err := inst.sys.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if filepath.Clean(path) == filepath.Clean(dir) {
+			return nil
+		}
+		empty = false
+		return filepath.SkipAll
+	})
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return false, nil
+		}
+		return false, i18n.Errorf(messages.InstallFailedStatFmt, dir, err)
+	}
+	return empty, nil
+}
+
+// detectOrphanedArtifactsReadiness reports orphaned files in the upgrade plan.
+func detectOrphanedArtifactsReadiness(inst *installer) (*UpgradeReadinessCheck, error) {
+	orphans, err := inst.detectOrphanedArtifacts()
+	if err != nil {
+		return nil, err
+	}
+	if len(orphans) == 0 {
+		return nil, nil
+	}
+	details := make([]string, 0, len(orphans))
+	for _, orphan := range orphans {
+		details = append(details, FormatOrphanedArtifact(orphan))
+	}
+	return &UpgradeReadinessCheck{
+		ID:      readinessCheckOrphanedArtifacts,
+		Summary: "Files from earlier Agent Layer releases are no longer generated and can be deleted.",
+		Details: details,
+	}, nil
+}
+
+// FormatOrphanedArtifact renders an orphan as a single report line.
+func FormatOrphanedArtifact(orphan OrphanedArtifact) string {
+	if orphan.Modified {
+		return i18n.Sprintf(messages.InstallOrphanedArtifactModifiedFmt, orphan.Path, orphan.LastVersion)
+	}
+	return i18n.Sprintf(messages.InstallOrphanedArtifactFmt, orphan.Path, orphan.LastVersion)
+}
@@ -0,0 +1,134 @@
+package install
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeOrphanTestFile(t *testing.T, root string, rel string, content string) string {
+	t.Helper()
+	path := filepath.Join(root, filepath.FromSlash(rel))
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		t.Fatalf("mkdir %s: %v", rel, err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("write %s: %v", rel, err)
+	}
+	return path
+}
+
+func TestDetectOrphanedArtifacts_ReportsRetiredManifestPaths(t *testing.T) {
+	root := t.TempDir()
+	writeOrphanTestFile(t, root, ".agent-layer/slash-commands/fix-tests.md", "# edited locally\n")
+	writeOrphanTestFile(t, root, ".agent-layer/notes.md", "not from any release\n")
+
+	inst := &installer{root: root, sys: RealSystem{}}
+	orphans, err := inst.detectOrphanedArtifacts()
+	if err != nil {
+		t.Fatalf("detectOrphanedArtifacts: %v", err)
+	}
+	if len(orphans) != 1 {
+		t.Fatalf("expected one orphan, got %#v", orphans)
+	}
+	got := orphans[0]
+	if got.Path != ".agent-layer/slash-commands/fix-tests.md" {
+		t.Fatalf("unexpected orphan path %q", got.Path)
+	}
+	if got.LastVersion != "0.8.8" {
+		t.Fatalf("expected last version 0.8.8, got %q", got.LastVersion)
+	}
+	if !got.Modified {
+		t.Fatalf("expected orphan with unreleased content to be marked modified")
+	}
+}
+
+func TestDetectOrphanedArtifacts_SkipsUserOwnedPaths(t *testing.T) {
+	root := t.TempDir()
+	writeOrphanTestFile(t, root, ".agent-layer/slash-commands/fix-tests.md", "# mine\n")
+
+	inst := &installer{root: root, sys: RealSystem{}, userOwnedPaths: []string{".agent-layer/slash-commands"}}
+	orphans, err := inst.detectOrphanedArtifacts()
+	if err != nil {
+		t.Fatalf("detectOrphanedArtifacts: %v", err)
+	}
+	if len(orphans) != 0 {
+		t.Fatalf("expected user-owned path to be skipped, got %#v", orphans)
+	}
+}
+
+func TestHandleOrphanedArtifacts_DeletesApprovedAndPrunesDirs(t *testing.T) {
+	root := t.TempDir()
+	orphanPath := writeOrphanTestFile(t, root, ".agent-layer/slash-commands/fix-tests.md", "# old\n")
+	notesPath := writeOrphanTestFile(t, root, ".agent-layer/notes.md", "keep\n")
+
+	var prompted []OrphanedArtifact
+	inst := &installer{
+		root: root,
+		sys:  RealSystem{},
+		prompter: PromptFuncs{
+			DeleteOrphanedAllFunc: func(orphans []OrphanedArtifact) (bool, error) {
+				prompted = orphans
+				return true, nil
+			},
+		},
+	}
+	unknowns := []string{filepath.Join(root, ".agent-layer", "notes.md"), filepath.Join(root, ".agent-layer", "slash-commands")}
+	remaining, err := inst.handleOrphanedArtifacts(unknowns)
+	if err != nil {
+		t.Fatalf("handleOrphanedArtifacts: %v", err)
+	}
+	if len(prompted) != 1 {
+		t.Fatalf("expected one orphan in prompt, got %#v", prompted)
+	}
+	if len(remaining) != 1 || remaining[0] != notesPath {
+		t.Fatalf("expected only notes.md to remain unknown, got %v", remaining)
+	}
+	if _, err := os.Stat(orphanPath); !os.IsNotExist(err) {
+		t.Fatalf("expected orphan to be deleted, stat err=%v", err)
+	}
+	if _, err := os.Stat(filepath.Dir(orphanPath)); !os.IsNotExist(err) {
+		t.Fatalf("expected emptied slash-commands dir to be pruned, stat err=%v", err)
+	}
+	if _, err := os.Stat(filepath.Join(root, ".agent-layer")); err != nil {
+		t.Fatalf("expected .agent-layer to remain: %v", err)
+	}
+}
+
+func TestHandleOrphanedArtifacts_DeclinedKeepsFilesOutOfUnknowns(t *testing.T) {
+	root := t.TempDir()
+	orphanPath := writeOrphanTestFile(t, root, ".agent-layer/slash-commands/fix-tests.md", "# old\n")
+
+	inst := &installer{
+		root: root,
+		sys:  RealSystem{},
+		prompter: PromptFuncs{
+			DeleteOrphanedAllFunc: func([]OrphanedArtifact) (bool, error) { return false, nil },
+		},
+	}
+	remaining, err := inst.handleOrphanedArtifacts([]string{filepath.Dir(orphanPath)})
+	if err != nil {
+		t.Fatalf("handleOrphanedArtifacts: %v", err)
+	}
+	if len(remaining) != 0 {
+		t.Fatalf("expected orphans to leave the unknown prompts, got %v", remaining)
+	}
+	if _, err := os.Stat(orphanPath); err != nil {
+		t.Fatalf("expected declined orphan to remain: %v", err)
+	}
+}
+
+func TestHandleOrphanedArtifacts_UnwiredPrompterKeepsUnknowns(t *testing.T) {
+	root := t.TempDir()
+	orphanPath := writeOrphanTestFile(t, root, ".agent-layer/slash-commands/fix-tests.md", "# old\n")
+
+	inst := &installer{root: root, sys: RealSystem{}, prompter: PromptFuncs{}}
+	unknowns := []string{filepath.Dir(orphanPath)}
+	remaining, err := inst.handleOrphanedArtifacts(unknowns)
+	if err != nil {
+		t.Fatalf("handleOrphanedArtifacts: %v", err)
+	}
+	if len(remaining) != 1 {
+		t.Fatalf("expected unknowns unchanged without an orphan prompt, got %v", remaining)
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"sort"
 	"strings"
 
+	"github.com/conn-castle/agent-layer/internal/i18n"
 	"github.com/conn-castle/agent-layer/internal/messages"
 	"github.com/conn-castle/agent-layer/internal/templates"
 	"github.com/conn-castle/agent-layer/internal/version"
@@ -42,6 +43,12 @@ const (
 type UpgradePlanOptions struct {
 	TargetPinVersion string
 	System           System
+	// BinaryVersion is the running al version. Release builds cache the
+	// template and migration analysis under .agent-layer/state so repeated
+	// plans against an unchanged repo skip it; dev builds never cache.
+	BinaryVersion string
+	// OperationFilter previews an upgrade narrowed by --only or --skip.
+	OperationFilter UpgradeOperationFilter
 }
 
 // UpgradePlan is the machine-readable output of `al upgrade plan`.
@@ -59,6 +66,11 @@ type UpgradePlan struct {
 	MigrationReport           UpgradeMigrationReport  `json:"migration_report"`
 	PinVersionChange          UpgradePinVersionDiff   `json:"pin_version_change"`
 	ReadinessChecks           []UpgradeReadinessCheck `json:"readiness_checks"`
+	RiskGroups                []UpgradeRiskGroup      `json:"risk_groups"`
+	Changelog                 *UpgradeChangelog       `json:"changelog,omitempty"`
+	// OptedOut lists differing templates that config.toml's [ownership]
+	// table gives to the user, which the upgrade leaves untouched.
+	OptedOut []UpgradeChange `json:"opted_out"`
 }
 
 // UpgradeChange describes a single template delta entry.
@@ -112,26 +124,71 @@ type upgradeChangeWithTemplate struct {
 // BuildUpgradePlan computes a dry-run upgrade plan against the running binary's embedded templates.
 func BuildUpgradePlan(root string, opts UpgradePlanOptions) (UpgradePlan, error) {
 	if root == "" {
-		return UpgradePlan{}, fmt.Errorf(messages.InstallRootRequired)
+		return UpgradePlan{}, i18n.Errorf(messages.InstallRootRequired)
 	}
 	if opts.System == nil {
-		return UpgradePlan{}, fmt.Errorf(messages.InstallSystemRequired)
+		return UpgradePlan{}, i18n.Errorf(messages.InstallSystemRequired)
 	}
 
 	targetPinVersion := strings.TrimSpace(opts.TargetPinVersion)
 	if targetPinVersion != "" {
 		normalized, err := version.Normalize(targetPinVersion)
 		if err != nil {
-			return UpgradePlan{}, fmt.Errorf(messages.InstallInvalidPinVersionFmt, err)
+			return UpgradePlan{}, i18n.Errorf(messages.InstallInvalidPinVersionFmt, err)
 		}
 		targetPinVersion = normalized
 	}
 
+	sys, err := layerSystem(root, opts.System)
+	if err != nil {
+		return UpgradePlan{}, err
+	}
 	inst := &installer{
-		root:       root,
-		pinVersion: targetPinVersion,
-		sys:        opts.System,
+		root:            root,
+		pinVersion:      targetPinVersion,
+		sys:             sys,
+		operationFilter: opts.OperationFilter,
+	}
+	if err := inst.validateOperationFilter(); err != nil {
+		return UpgradePlan{}, err
+	}
+	if err := inst.loadOwnershipOverrides(); err != nil {
+		return UpgradePlan{}, err
+	}
+	cacheKey, cacheable := inst.upgradePlanCacheKey(opts.BinaryVersion)
+	plan, cached := UpgradePlan{}, false
+	if cacheable {
+		plan, cached = inst.readCachedUpgradePlan(cacheKey)
+	}
+	if !cached {
+		computed, err := inst.computeUpgradePlan()
+		if err != nil {
+			return UpgradePlan{}, err
+		}
+		plan = computed
+		if cacheable {
+			inst.writeCachedUpgradePlan(cacheKey, plan)
+		}
 	}
+
+	// Readiness checks inspect generated files and the environment, which the
+	// cache key does not cover, so they and the risk groups built from them
+	// are always recomputed. The changelog summary is cheap and derived from
+	// the migration report, so it is rebuilt rather than cached.
+	readinessChecks, err := buildUpgradeReadinessChecks(inst)
+	if err != nil {
+		return UpgradePlan{}, err
+	}
+	plan.ReadinessChecks = readinessChecks
+	plan.RiskGroups = ClassifyUpgradeRisks(plan)
+	plan.Changelog = buildUpgradeChangelog(plan.MigrationReport)
+	return plan, nil
+}
+
+// computeUpgradePlan runs the template, ownership, and migration analysis
+// for an upgrade plan. ReadinessChecks and RiskGroups are left empty.
+func (inst *installer) computeUpgradePlan() (UpgradePlan, error) {
+	root := inst.root
 	migrationPlan, err := inst.planUpgradeMigrations()
 	if err != nil {
 		return UpgradePlan{}, err
@@ -159,7 +216,7 @@ func BuildUpgradePlan(root string, opts UpgradePlanOptions) (UpgradePlan, error)
 				})
 				continue
 			}
-			return UpgradePlan{}, fmt.Errorf(messages.InstallFailedStatFmt, absPath, err)
+			return UpgradePlan{}, i18n.Errorf(messages.InstallFailedStatFmt, absPath, err)
 		}
 		matches, err := inst.templates().matchTemplate(inst.sys, absPath, entry.templatePath, info)
 		if err != nil {
@@ -207,12 +264,16 @@ func BuildUpgradePlan(root string, opts UpgradePlanOptions) (UpgradePlan, error)
 		return UpgradePlan{}, err
 	}
 
-	regularUpdates, sectionUpdates := splitSectionAwareUpdates(updates)
-	readinessChecks, err := buildUpgradeReadinessChecks(inst)
-	if err != nil {
-		return UpgradePlan{}, err
-	}
+	updates, optedOutUpdates := inst.splitOwnershipOverrides(updates)
+	orphans, optedOutOrphans := inst.splitOwnershipOverrides(orphans)
+	statuslineUpdates, optedOutStatusline := inst.splitOwnershipOverrides(statuslineUpdates)
+	optedOut := append(append(optedOutUpdates, optedOutOrphans...), optedOutStatusline...)
+	sort.Slice(optedOut, func(i, j int) bool { return optedOut[i].path < optedOut[j].path })
+	updates, skippedPaths := inst.operationFilter.filterUpgradeChanges(updates)
+	statuslineUpdates, skippedStatusline := inst.operationFilter.filterUpgradeChanges(statuslineUpdates)
+	migrationPlan.report.SkippedPaths = append(skippedPaths, skippedStatusline...)
 
+	regularUpdates, sectionUpdates := splitSectionAwareUpdates(updates)
 	return UpgradePlan{
 		SchemaVersion:             UpgradePlanSchemaVersion,
 		DryRun:                    true,
@@ -226,7 +287,7 @@ func BuildUpgradePlan(root string, opts UpgradePlanOptions) (UpgradePlan, error)
 		ConfigKeyMigrations:       migrationPlan.configMigrations,
 		MigrationReport:           migrationPlan.report,
 		PinVersionChange:          pinDiff,
-		ReadinessChecks:           readinessChecks,
+		OptedOut:                  toUpgradeChanges(optedOut),
 	}, nil
 }
 
@@ -356,10 +417,10 @@ func (inst templateManager) walkTemplateOrphans(root string, templatePaths map[s
 		if errors.Is(err, os.ErrNotExist) {
 			return nil
 		}
-		return fmt.Errorf(messages.InstallFailedStatFmt, root, err)
+		return i18n.Errorf(messages.InstallFailedStatFmt, root, err)
 	}
 
-	return inst.sys.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+	return walkDirFollowingRoot(inst.sys, root, func(path string, entry fs.DirEntry, err error) error {
 		if err != nil {
 			return err
 		}
@@ -399,7 +460,7 @@ func detectUpgradeRenames(
 		path := filepath.Join(inst.root, filepath.FromSlash(orphan.path))
 		data, err := inst.sys.ReadFile(path)
 		if err != nil {
-			return nil, nil, nil, fmt.Errorf(messages.InstallFailedReadFmt, path, err)
+			return nil, nil, nil, i18n.Errorf(messages.InstallFailedReadFmt, path, err)
 		}
 		hash := hashNormalizedContent(data)
 		orphansByHash[hash] = append(orphansByHash[hash], idx)
@@ -527,7 +588,7 @@ func (inst templateManager) pinVersionDiff() (UpgradePinVersionDiff, error) {
 	current := ""
 	if err != nil {
 		if !errors.Is(err, os.ErrNotExist) {
-			return UpgradePinVersionDiff{}, fmt.Errorf(messages.InstallFailedReadFmt, path, err)
+			return UpgradePinVersionDiff{}, i18n.Errorf(messages.InstallFailedReadFmt, path, err)
 		}
 	} else {
 		current = strings.TrimSpace(string(data))
@@ -0,0 +1,210 @@
+package install
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/conn-castle/agent-layer/internal/launchers"
+	"github.com/conn-castle/agent-layer/internal/version"
+)
+
+const (
+	upgradePlanCacheRelPath       = ".agent-layer/state/upgrade-plan-cache.json"
+	upgradePlanCacheSchemaVersion = 1
+)
+
+// upgradePlanCache is the single cached plan under .agent-layer/state. Only
+// the latest plan is kept; any repo change produces a new key and the next
+// plan overwrites the entry.
+type upgradePlanCache struct {
+	SchemaVersion int         `json:"schema_version"`
+	Key           string      `json:"key"`
+	Plan          UpgradePlan `json:"plan"`
+}
+
+// upgradePlanCacheKey hashes everything computeUpgradePlan reads: the binary
+// version (which fixes the embedded templates and migration manifests), the
+// target pin, the operation filter, and the type, mode, and content of the
+// repo's managed files.
+// It reports false when the plan must not be cached: dev builds, whose
+// templates change without a version bump, and repos that cannot be hashed.
+func (inst *installer) upgradePlanCacheKey(binaryVersion string) (string, bool) {
+	normalized, err := version.Normalize(strings.TrimSpace(binaryVersion))
+	if err != nil {
+		return "", false
+	}
+	hasher := sha256.New()
+	_, _ = fmt.Fprintf(hasher, "schema=%d\x00binary=%s\x00target=%s\x00%s\x00", upgradePlanCacheSchemaVersion, normalized, inst.pinVersion, inst.operationFilter.cacheKey())
+	paths, err := inst.upgradePlanCachePaths()
+	if err != nil {
+		return "", false
+	}
+	for _, path := range paths {
+		if err := inst.hashUpgradePlanCachePath(hasher, path); err != nil {
+			return "", false
+		}
+	}
+	return hex.EncodeToString(hasher.Sum(nil)), true
+}
+
+// upgradePlanCachePaths lists the files the plan depends on, sorted: the
+// .agent-layer tree minus tmp/ and runtime state, docs/agent-layer, the
+// gitignore and VS Code launchers, and every path an embedded migration
+// operation can touch.
+func (inst *installer) upgradePlanCachePaths() ([]string, error) {
+	root := inst.root
+	agentLayerDir := filepath.Join(root, ".agent-layer")
+	stateDir := filepath.Join(agentLayerDir, "state")
+	baselinePath := filepath.Join(root, filepath.FromSlash(baselineStateRelPath))
+	seen := make(map[string]struct{})
+	add := func(path string) {
+		seen[filepath.Clean(path)] = struct{}{}
+	}
+	walk := func(dir string) error {
+		err := inst.sys.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if entry.IsDir() && (path == stateDir || inst.isUnderAgentLayerTmp(path)) {
+				return fs.SkipDir
+			}
+			add(path)
+			return nil
+		})
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+	if err := walk(agentLayerDir); err != nil {
+		return nil, err
+	}
+	if err := walk(filepath.Join(root, "docs", "agent-layer")); err != nil {
+		return nil, err
+	}
+	add(baselinePath)
+	add(filepath.Join(root, ".gitignore"))
+	for _, path := range launchers.VSCodePathsIn(filepath.Join(root, ".agent-layer")).All() {
+		add(path)
+	}
+	migrationPaths, err := upgradeMigrationReferencedPaths()
+	if err != nil {
+		return nil, err
+	}
+	for _, rel := range migrationPaths {
+		add(filepath.Join(root, filepath.FromSlash(rel)))
+	}
+
+	paths := make([]string, 0, len(seen))
+	for path := range seen {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return paths, nil
+}
+
+// upgradeMigrationReferencedPaths returns the repo-relative paths named by
+// file and generated-artifact operations in every embedded migration
+// manifest. Paths outside .agent-layer (generated client config, for
+// example) decide source-version inference and operation status too.
+func upgradeMigrationReferencedPaths() ([]string, error) {
+	versions, err := listMigrationManifestVersions()
+	if err != nil {
+		return nil, err
+	}
+	var paths []string
+	for _, ver := range versions {
+		manifest, _, err := loadUpgradeMigrationManifestByVersion(ver)
+		if err != nil {
+			return nil, err
+		}
+		for _, op := range manifest.Operations {
+			for _, path := range []string{op.From, op.To, op.Path} {
+				if strings.TrimSpace(path) != "" && !strings.HasPrefix(string(op.Kind), "config_") {
+					paths = append(paths, path)
+				}
+			}
+		}
+	}
+	return paths, nil
+}
+
+// hashUpgradePlanCachePath writes one path's relative name, type, mode, and
+// content (or symlink target) to hasher. Missing paths are hashed as absent
+// so creating one changes the key.
+func (inst *installer) hashUpgradePlanCachePath(hasher hash.Hash, path string) error {
+	rel, err := filepath.Rel(inst.root, path)
+	if err != nil {
+		return err
+	}
+	_, _ = fmt.Fprintf(hasher, "%s\x00", filepath.ToSlash(rel))
+	info, err := inst.sys.Lstat(path)
+	if errors.Is(err, os.ErrNotExist) {
+		_, _ = hasher.Write([]byte("absent\x00"))
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	_, _ = fmt.Fprintf(hasher, "%s\x00", info.Mode().String())
+	switch {
+	case info.Mode()&os.ModeSymlink != 0:
+		target, err := inst.sys.Readlink(path)
+		if err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintf(hasher, "%s\x00", target)
+	case info.Mode().IsRegular():
+		data, err := inst.sys.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		sum := sha256.Sum256(data)
+		_, _ = hasher.Write(sum[:])
+	}
+	return nil
+}
+
+// readCachedUpgradePlan returns the cached plan when its key matches. A
+// missing, unreadable, or stale cache is a miss.
+func (inst *installer) readCachedUpgradePlan(key string) (UpgradePlan, bool) {
+	data, err := inst.sys.ReadFile(filepath.Join(inst.root, filepath.FromSlash(upgradePlanCacheRelPath)))
+	if err != nil {
+		return UpgradePlan{}, false
+	}
+	var cache upgradePlanCache
+	if err := json.Unmarshal(data, &cache); err != nil {
+		return UpgradePlan{}, false
+	}
+	if cache.SchemaVersion != upgradePlanCacheSchemaVersion || cache.Key != key {
+		return UpgradePlan{}, false
+	}
+	return cache.Plan, true
+}
+
+// writeCachedUpgradePlan stores plan under key. The cache is an optimization,
+// so failures are ignored and the next plan is simply recomputed.
+func (inst *installer) writeCachedUpgradePlan(key string, plan UpgradePlan) {
+	data, err := json.Marshal(upgradePlanCache{
+		SchemaVersion: upgradePlanCacheSchemaVersion,
+		Key:           key,
+		Plan:          plan,
+	})
+	if err != nil {
+		return
+	}
+	path := filepath.Join(inst.root, filepath.FromSlash(upgradePlanCacheRelPath))
+	if err := inst.sys.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return
+	}
+	_ = inst.sys.WriteFileAtomic(path, data, 0o644)
+}
@@ -0,0 +1,169 @@
+package install
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func seedUpgradePlanCacheRepo(t *testing.T) string {
+	t.Helper()
+	root := t.TempDir()
+	if err := Run(root, Options{System: RealSystem{}, PinVersion: "0.6.0"}); err != nil {
+		t.Fatalf("seed repo: %v", err)
+	}
+	return root
+}
+
+func readUpgradePlanCacheForTest(t *testing.T, root string) upgradePlanCache {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(root, filepath.FromSlash(upgradePlanCacheRelPath)))
+	if err != nil {
+		t.Fatalf("read plan cache: %v", err)
+	}
+	var cache upgradePlanCache
+	if err := json.Unmarshal(data, &cache); err != nil {
+		t.Fatalf("decode plan cache: %v", err)
+	}
+	return cache
+}
+
+func writeUpgradePlanCacheForTest(t *testing.T, root string, cache upgradePlanCache) {
+	t.Helper()
+	data, err := json.Marshal(cache)
+	if err != nil {
+		t.Fatalf("encode plan cache: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(root, filepath.FromSlash(upgradePlanCacheRelPath)), data, 0o600); err != nil {
+		t.Fatalf("write plan cache: %v", err)
+	}
+}
+
+func TestBuildUpgradePlan_CachedPlanMatchesFreshPlan(t *testing.T) {
+	root := seedUpgradePlanCacheRepo(t)
+	opts := UpgradePlanOptions{TargetPinVersion: "0.7.0", System: RealSystem{}, BinaryVersion: "v0.7.0"}
+
+	first, err := BuildUpgradePlan(root, opts)
+	if err != nil {
+		t.Fatalf("first plan: %v", err)
+	}
+	cache := readUpgradePlanCacheForTest(t, root)
+	if cache.Key == "" || cache.Plan.ReadinessChecks != nil || cache.Plan.RiskGroups != nil {
+		t.Fatalf("expected keyed cache without readiness or risk data, got %#v", cache)
+	}
+	second, err := BuildUpgradePlan(root, opts)
+	if err != nil {
+		t.Fatalf("cached plan: %v", err)
+	}
+	fresh, err := BuildUpgradePlan(root, UpgradePlanOptions{TargetPinVersion: "0.7.0", System: RealSystem{}})
+	if err != nil {
+		t.Fatalf("fresh plan: %v", err)
+	}
+	if !reflect.DeepEqual(first, second) || !reflect.DeepEqual(second, fresh) {
+		t.Fatalf("cached plan differs from fresh plan:\nfirst=%#v\nsecond=%#v\nfresh=%#v", first, second, fresh)
+	}
+}
+
+func TestBuildUpgradePlan_CacheHitSkipsAnalysisUntilRepoChanges(t *testing.T) {
+	root := seedUpgradePlanCacheRepo(t)
+	opts := UpgradePlanOptions{TargetPinVersion: "0.7.0", System: RealSystem{}, BinaryVersion: "0.7.0"}
+	if _, err := BuildUpgradePlan(root, opts); err != nil {
+		t.Fatalf("first plan: %v", err)
+	}
+
+	// Mark the cached entry so a hit is distinguishable from a recompute.
+	cache := readUpgradePlanCacheForTest(t, root)
+	cache.Plan.PinVersionChange.Current = "from-cache"
+	writeUpgradePlanCacheForTest(t, root, cache)
+
+	hit, err := BuildUpgradePlan(root, opts)
+	if err != nil {
+		t.Fatalf("cached plan: %v", err)
+	}
+	if hit.PinVersionChange.Current != "from-cache" {
+		t.Fatalf("expected cache hit, got pin change %#v", hit.PinVersionChange)
+	}
+	if hit.RiskGroups == nil {
+		t.Fatalf("expected risk groups to be recomputed on a cache hit")
+	}
+
+	otherTarget, err := BuildUpgradePlan(root, UpgradePlanOptions{TargetPinVersion: "0.6.0", System: RealSystem{}, BinaryVersion: "0.7.0"})
+	if err != nil {
+		t.Fatalf("other target plan: %v", err)
+	}
+	if otherTarget.PinVersionChange.Current == "from-cache" {
+		t.Fatalf("expected a different target pin to miss the cache")
+	}
+
+	// The other target overwrote the single cache entry; re-prime it.
+	if _, err := BuildUpgradePlan(root, opts); err != nil {
+		t.Fatalf("rebuild plan: %v", err)
+	}
+	cache = readUpgradePlanCacheForTest(t, root)
+	cache.Plan.PinVersionChange.Current = "from-cache"
+	writeUpgradePlanCacheForTest(t, root, cache)
+	allowPath := filepath.Join(root, ".agent-layer", "commands.allow")
+	if err := os.WriteFile(allowPath, []byte("git status\n"), 0o600); err != nil {
+		t.Fatalf("edit managed file: %v", err)
+	}
+	changed, err := BuildUpgradePlan(root, opts)
+	if err != nil {
+		t.Fatalf("plan after edit: %v", err)
+	}
+	if changed.PinVersionChange.Current == "from-cache" {
+		t.Fatalf("expected a managed file edit to invalidate the cache")
+	}
+}
+
+func TestBuildUpgradePlan_DevBuildDoesNotCache(t *testing.T) {
+	root := seedUpgradePlanCacheRepo(t)
+	if _, err := BuildUpgradePlan(root, UpgradePlanOptions{TargetPinVersion: "0.7.0", System: RealSystem{}, BinaryVersion: "dev"}); err != nil {
+		t.Fatalf("plan: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(root, filepath.FromSlash(upgradePlanCacheRelPath))); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("expected no plan cache for a dev build, got %v", err)
+	}
+}
+
+func TestBuildUpgradePlan_CacheWriteFailureIsIgnored(t *testing.T) {
+	root := seedUpgradePlanCacheRepo(t)
+	sys := newFaultSystem(RealSystem{})
+	sys.writeErrs[filepath.Join(root, filepath.FromSlash(upgradePlanCacheRelPath))] = errors.New("disk full")
+
+	if _, err := BuildUpgradePlan(root, UpgradePlanOptions{TargetPinVersion: "0.7.0", System: sys, BinaryVersion: "0.7.0"}); err != nil {
+		t.Fatalf("expected plan despite cache write failure, got %v", err)
+	}
+}
+
+func TestUpgradePlanCacheKey_UnhashableRepoDisablesCache(t *testing.T) {
+	root := seedUpgradePlanCacheRepo(t)
+	sys := newFaultSystem(RealSystem{})
+	sys.readErrs[filepath.Join(root, ".agent-layer", "commands.allow")] = errors.New("read failed")
+	inst := &installer{root: root, pinVersion: "0.7.0", sys: sys}
+
+	if _, ok := inst.upgradePlanCacheKey("0.7.0"); ok {
+		t.Fatalf("expected an unreadable managed file to disable caching")
+	}
+}
+
+func TestReadCachedUpgradePlan_Misses(t *testing.T) {
+	root := seedUpgradePlanCacheRepo(t)
+	inst := &installer{root: root, sys: RealSystem{}}
+	if _, ok := inst.readCachedUpgradePlan("key"); ok {
+		t.Fatalf("expected miss without a cache file")
+	}
+	path := filepath.Join(root, filepath.FromSlash(upgradePlanCacheRelPath))
+	if err := os.WriteFile(path, []byte("{not json"), 0o600); err != nil {
+		t.Fatalf("write cache: %v", err)
+	}
+	if _, ok := inst.readCachedUpgradePlan("key"); ok {
+		t.Fatalf("expected miss for a corrupt cache")
+	}
+	writeUpgradePlanCacheForTest(t, root, upgradePlanCache{SchemaVersion: upgradePlanCacheSchemaVersion + 1, Key: "key"})
+	if _, ok := inst.readCachedUpgradePlan("key"); ok {
+		t.Fatalf("expected miss for another schema version")
+	}
+}
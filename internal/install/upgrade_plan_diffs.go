@@ -1,10 +1,10 @@
 package install
 
 import (
-	"fmt"
 	"path/filepath"
 	"strings"
 
+	"github.com/conn-castle/agent-layer/internal/i18n"
 	"github.com/conn-castle/agent-layer/internal/messages"
 	"github.com/conn-castle/agent-layer/internal/templates"
 )
@@ -26,14 +26,18 @@ const (
 // BuildUpgradePlanDiffPreviews builds line-level diff previews for upgrade-plan text rendering.
 func BuildUpgradePlanDiffPreviews(root string, plan UpgradePlan, opts UpgradePlanDiffPreviewOptions) (map[string]DiffPreview, error) {
 	if strings.TrimSpace(root) == "" {
-		return nil, fmt.Errorf(messages.InstallRootRequired)
+		return nil, i18n.Errorf(messages.InstallRootRequired)
 	}
 	if opts.System == nil {
-		return nil, fmt.Errorf(messages.InstallSystemRequired)
+		return nil, i18n.Errorf(messages.InstallSystemRequired)
+	}
+	sys, err := layerSystem(root, opts.System)
+	if err != nil {
+		return nil, err
 	}
 	inst := &installer{
 		root:         root,
-		sys:          opts.System,
+		sys:          sys,
 		pinVersion:   plan.PinVersionChange.Target,
 		diffMaxLines: normalizeDiffMaxLines(opts.MaxDiffLines),
 	}
@@ -128,7 +132,7 @@ func (inst *installer) buildPlanChangeDiffPreview(change UpgradeChange, mode pla
 			LinesRemoved: removed,
 		}, nil
 	default:
-		return DiffPreview{}, fmt.Errorf(messages.InstallUnknownPlanDiffModeFmt, mode)
+		return DiffPreview{}, i18n.Errorf(messages.InstallUnknownPlanDiffModeFmt, mode)
 	}
 }
 
@@ -139,7 +143,7 @@ func (inst *installer) additionPreviewBytes(relPath string, templatePathByRel ma
 	}
 	templatePath := templatePathByRel[relPath]
 	if strings.TrimSpace(templatePath) == "" {
-		return nil, "", fmt.Errorf(messages.InstallMissingTemplatePathMappingFmt, relPath)
+		return nil, "", i18n.Errorf(messages.InstallMissingTemplatePathMappingFmt, relPath)
 	}
 	templateBytes, err := templates.Read(templatePath)
 	if err != nil {
@@ -13,6 +13,7 @@ import (
 
 	"github.com/pelletier/go-toml/v2"
 
+	"github.com/conn-castle/agent-layer/internal/clock"
 	"github.com/conn-castle/agent-layer/internal/config"
 	"github.com/conn-castle/agent-layer/internal/envfile"
 	"github.com/conn-castle/agent-layer/internal/launchers"
@@ -69,7 +70,7 @@ func buildUpgradeReadinessChecks(inst *installer) ([]UpgradeReadinessCheck, erro
 		return nil, readinessErr("read", configPath, err)
 	}
 
-	checks := make([]UpgradeReadinessCheck, 0, 9)
+	checks := make([]UpgradeReadinessCheck, 0, 10)
 	if strictErr := decodeConfigStrict(configBytes); strictErr != nil {
 		checks = append(checks, UpgradeReadinessCheck{
 			ID:      readinessCheckUnrecognizedConfigKeys,
@@ -136,6 +137,12 @@ func buildUpgradeReadinessChecks(inst *installer) ([]UpgradeReadinessCheck, erro
 		checks = append(checks, *check)
 	}
 
+	if check, err := detectOrphanedArtifactsReadiness(inst); err != nil {
+		return nil, err
+	} else if check != nil {
+		checks = append(checks, *check)
+	}
+
 	sortReadinessChecks(checks)
 	return checks, nil
 }
@@ -486,8 +493,8 @@ func detectVSCodeNoSyncStaleness(inst *installer, cfg *config.Config, configPath
 		details = append(details, fmt.Sprintf(
 			"%s is newer than generated VS Code outputs (config=%s, outputs=%s)",
 			filepath.ToSlash(inst.relativePath(configPath)),
-			configMTime.UTC().Format(time.RFC3339),
-			latestGenerated.UTC().Format(time.RFC3339),
+			clock.Format(configMTime),
+			clock.Format(latestGenerated),
 		))
 	}
 
@@ -556,7 +563,7 @@ type disabledAgentArtifactRule struct {
 }
 
 func detectDisabledAgentArtifacts(inst *installer, cfg *config.Config) (*UpgradeReadinessCheck, error) {
-	launcherPaths := launchers.VSCodePaths(inst.root)
+	launcherPaths := launchers.VSCodePathsIn(filepath.Join(inst.root, ".agent-layer"))
 	rules := []disabledAgentArtifactRule{
 		{
 			agent:   agentAntigravity,
@@ -758,7 +765,7 @@ func countMarkdownFiles(inst *installer, root string) (int, error) {
 	}
 
 	count := 0
-	err := inst.sys.WalkDir(root, func(path string, entry os.DirEntry, walkErr error) error {
+	err := walkDirFollowingRoot(inst.sys, root, func(path string, entry os.DirEntry, walkErr error) error {
 		if walkErr != nil {
 			return walkErr
 		}
@@ -786,7 +793,7 @@ func listGeneratedFilesWithSuffix(inst *installer, root string, suffix string) (
 
 	paths := make([]string, 0)
 	latest := time.Time{}
-	err := inst.sys.WalkDir(root, func(path string, entry os.DirEntry, walkErr error) error {
+	err := walkDirFollowingRoot(inst.sys, root, func(path string, entry os.DirEntry, walkErr error) error {
 		if walkErr != nil {
 			return walkErr
 		}
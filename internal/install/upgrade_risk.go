@@ -0,0 +1,220 @@
+package install
+
+import (
+	"strings"
+
+	"github.com/conn-castle/agent-layer/internal/i18n"
+	"github.com/conn-castle/agent-layer/internal/messages"
+)
+
+// UpgradeRisk labels how much care a group of planned upgrade changes needs
+// before it is applied. Risks are ordered from least to most dangerous.
+type UpgradeRisk string
+
+const (
+	// UpgradeRiskSafe covers new templates, refreshes of unmodified managed
+	// files, and pin updates.
+	UpgradeRiskSafe UpgradeRisk = "safe"
+	// UpgradeRiskConfig covers migrations that rewrite keys in config.toml.
+	UpgradeRiskConfig UpgradeRisk = "config"
+	// UpgradeRiskOverwrite covers replacing files that carry local edits,
+	// including memory docs and the editable status line sources.
+	UpgradeRiskOverwrite UpgradeRisk = "overwrite"
+	// UpgradeRiskDestructive covers deletions, renames, and breaking migrations.
+	UpgradeRiskDestructive UpgradeRisk = "destructive"
+)
+
+// UpgradeRisks lists every risk level in ascending order.
+var UpgradeRisks = []UpgradeRisk{UpgradeRiskSafe, UpgradeRiskConfig, UpgradeRiskOverwrite, UpgradeRiskDestructive}
+
+// ParseUpgradeRisk parses a risk level name such as "config".
+func ParseUpgradeRisk(raw string) (UpgradeRisk, error) {
+	normalized := UpgradeRisk(strings.ToLower(strings.TrimSpace(raw)))
+	for _, risk := range UpgradeRisks {
+		if risk == normalized {
+			return risk, nil
+		}
+	}
+	return "", i18n.Errorf(messages.InstallUpgradeRiskInvalidFmt, raw)
+}
+
+// Rank returns the position of r in UpgradeRisks, or -1 when unknown.
+func (r UpgradeRisk) Rank() int {
+	for i, risk := range UpgradeRisks {
+		if risk == r {
+			return i
+		}
+	}
+	return -1
+}
+
+// AtMost reports whether r is no riskier than limit.
+func (r UpgradeRisk) AtMost(limit UpgradeRisk) bool {
+	return r.Rank() <= limit.Rank()
+}
+
+// RequiresTypedConfirmation reports whether interactive upgrades must ask
+// the user to type the risk name before applying a group at this level.
+func (r UpgradeRisk) RequiresTypedConfirmation() bool {
+	return r.Rank() >= UpgradeRiskOverwrite.Rank()
+}
+
+// Label returns the human-readable group label for r.
+func (r UpgradeRisk) Label() string {
+	switch r {
+	case UpgradeRiskSafe:
+		return messages.InstallUpgradeRiskSafeLabel
+	case UpgradeRiskConfig:
+		return messages.InstallUpgradeRiskConfigLabel
+	case UpgradeRiskOverwrite:
+		return messages.InstallUpgradeRiskOverwriteLabel
+	case UpgradeRiskDestructive:
+		return messages.InstallUpgradeRiskDestructiveLabel
+	default:
+		return string(r)
+	}
+}
+
+// UpgradeRiskItem is one planned change inside a risk group.
+type UpgradeRiskItem struct {
+	Path   string `json:"path"`
+	Detail string `json:"detail"`
+	// Migration is true for migration operations, which the installer always
+	// applies as a unit and therefore cannot be skipped individually.
+	Migration bool `json:"migration,omitempty"`
+}
+
+// UpgradeRiskGroup collects planned changes that share a risk level.
+type UpgradeRiskGroup struct {
+	Risk  UpgradeRisk       `json:"risk"`
+	Items []UpgradeRiskItem `json:"items"`
+	// Skippable is false when declining the group must cancel the upgrade
+	// because it contains changes the installer cannot leave out.
+	Skippable bool `json:"skippable"`
+}
+
+// ClassifyUpgradeRisks groups a plan's changes by risk level. Only non-empty
+// groups are returned, in ascending risk order.
+func ClassifyUpgradeRisks(plan UpgradePlan) []UpgradeRiskGroup {
+	items := make(map[UpgradeRisk][]UpgradeRiskItem, len(UpgradeRisks))
+	add := func(risk UpgradeRisk, item UpgradeRiskItem) {
+		items[risk] = append(items[risk], item)
+	}
+
+	for _, change := range plan.TemplateAdditions {
+		add(UpgradeRiskSafe, UpgradeRiskItem{Path: change.Path, Detail: messages.InstallUpgradeRiskDetailAdd})
+	}
+	for _, change := range plan.StatuslineSourceAdditions {
+		add(UpgradeRiskSafe, UpgradeRiskItem{Path: change.Path, Detail: messages.InstallUpgradeRiskDetailAdd})
+	}
+	for _, change := range plan.TemplateUpdates {
+		add(UpgradeChangeRisk(change), UpgradeRiskItem{Path: change.Path, Detail: change.Ownership.Display()})
+	}
+	for _, change := range plan.SectionAwareUpdates {
+		add(UpgradeRiskOverwrite, UpgradeRiskItem{Path: change.Path, Detail: change.Ownership.Display()})
+	}
+	for _, change := range plan.StatuslineSourceUpdates {
+		add(UpgradeRiskOverwrite, UpgradeRiskItem{Path: change.Path, Detail: change.Ownership.Display()})
+	}
+	for _, rename := range plan.TemplateRenames {
+		add(UpgradeRiskDestructive, UpgradeRiskItem{
+			Path:   rename.From,
+			Detail: i18n.Sprintf(messages.InstallUpgradeRiskDetailRenameFmt, rename.To),
+		})
+	}
+	for _, change := range plan.TemplateRemovalsOrOrphans {
+		add(UpgradeRiskDestructive, UpgradeRiskItem{Path: change.Path, Detail: messages.InstallUpgradeRiskDetailRemove})
+	}
+	for _, migration := range plan.ConfigKeyMigrations {
+		add(UpgradeRiskConfig, UpgradeRiskItem{
+			Path:      migration.Key,
+			Detail:    i18n.Sprintf(messages.InstallUpgradeRiskDetailConfigFmt, migration.From, migration.To),
+			Migration: true,
+		})
+	}
+	if plan.PinVersionChange.Action != "" && plan.PinVersionChange.Action != UpgradePinActionNone {
+		add(UpgradeRiskSafe, UpgradeRiskItem{
+			Path:   pinVersionRelPath,
+			Detail: i18n.Sprintf(messages.InstallUpgradeRiskDetailPinFmt, plan.PinVersionChange.Action, plan.PinVersionChange.Target),
+		})
+	}
+	for _, entry := range plan.MigrationReport.Entries {
+		if entry.Status != UpgradeMigrationStatusPlanned {
+			continue
+		}
+		add(migrationEntryRisk(entry), UpgradeRiskItem{
+			Path:      migrationEntrySubject(entry),
+			Detail:    entry.Rationale,
+			Migration: true,
+		})
+	}
+
+	groups := make([]UpgradeRiskGroup, 0, len(items))
+	for _, risk := range UpgradeRisks {
+		if len(items[risk]) == 0 {
+			continue
+		}
+		skippable := risk != UpgradeRiskSafe
+		for _, item := range items[risk] {
+			if item.Migration {
+				skippable = false
+				break
+			}
+		}
+		groups = append(groups, UpgradeRiskGroup{Risk: risk, Items: items[risk], Skippable: skippable})
+	}
+	return groups
+}
+
+// UpgradeChangeRisk returns the risk of overwriting a managed file: refreshing
+// an untouched template is safe; anything with local edits is an overwrite.
+func UpgradeChangeRisk(change UpgradeChange) UpgradeRisk {
+	if change.Ownership == OwnershipUpstreamTemplateDelta {
+		return UpgradeRiskSafe
+	}
+	return UpgradeRiskOverwrite
+}
+
+// MaxUpgradeRisk returns the highest risk among groups, or UpgradeRiskSafe
+// when there are no groups.
+func MaxUpgradeRisk(groups []UpgradeRiskGroup) UpgradeRisk {
+	highest := UpgradeRiskSafe
+	for _, group := range groups {
+		if group.Risk.Rank() > highest.Rank() {
+			highest = group.Risk
+		}
+	}
+	return highest
+}
+
+func migrationEntryRisk(entry UpgradeMigrationEntry) UpgradeRisk {
+	if entry.Breaking {
+		return UpgradeRiskDestructive
+	}
+	switch upgradeMigrationOperationKind(entry.Kind) {
+	case upgradeMigrationKindConfigRenameKey,
+		upgradeMigrationKindConfigDeleteKey,
+		upgradeMigrationKindConfigReplaceString,
+		upgradeMigrationKindConfigSetDefault:
+		return UpgradeRiskConfig
+	case upgradeMigrationKindAppendToFile, upgradeMigrationKindTransformFile, upgradeMigrationKindAppendManagedSection:
+		return UpgradeRiskOverwrite
+	default:
+		return UpgradeRiskDestructive
+	}
+}
+
+func migrationEntrySubject(entry UpgradeMigrationEntry) string {
+	switch {
+	case entry.Key != "":
+		return entry.Key
+	case entry.From != "" && entry.To != "":
+		return entry.From + " -> " + entry.To
+	case entry.From != "":
+		return entry.From
+	case entry.Path != "":
+		return entry.Path
+	default:
+		return entry.ID
+	}
+}
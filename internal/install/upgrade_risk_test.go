@@ -0,0 +1,98 @@
+package install
+
+import (
+	"testing"
+)
+
+func TestParseUpgradeRisk(t *testing.T) {
+	for _, raw := range []string{"safe", " Config ", "OVERWRITE", "destructive"} {
+		if _, err := ParseUpgradeRisk(raw); err != nil {
+			t.Fatalf("ParseUpgradeRisk(%q) error: %v", raw, err)
+		}
+	}
+	if _, err := ParseUpgradeRisk("risky"); err == nil {
+		t.Fatal("expected error for unknown risk")
+	}
+}
+
+func TestUpgradeRiskOrdering(t *testing.T) {
+	if !UpgradeRiskSafe.AtMost(UpgradeRiskConfig) || UpgradeRiskDestructive.AtMost(UpgradeRiskOverwrite) {
+		t.Fatal("unexpected AtMost ordering")
+	}
+	if UpgradeRiskConfig.RequiresTypedConfirmation() || !UpgradeRiskOverwrite.RequiresTypedConfirmation() {
+		t.Fatal("typed confirmation should start at overwrite")
+	}
+	if got := UpgradeRisk("other").Label(); got != "other" {
+		t.Fatalf("unknown Label = %q", got)
+	}
+}
+
+func TestClassifyUpgradeRisks(t *testing.T) {
+	plan := UpgradePlan{
+		TemplateAdditions: []UpgradeChange{{Path: ".agent-layer/skills/new/SKILL.md"}},
+		TemplateUpdates: []UpgradeChange{
+			{Path: ".agent-layer/instructions/00_base.md", Ownership: OwnershipUpstreamTemplateDelta},
+			{Path: ".agent-layer/instructions/01_style.md", Ownership: OwnershipLocalCustomization},
+		},
+		SectionAwareUpdates:       []UpgradeChange{{Path: "docs/agent-layer/ISSUES.md", Ownership: OwnershipLocalCustomization}},
+		TemplateRenames:           []UpgradeRename{{From: ".agent-layer/old.md", To: ".agent-layer/new.md"}},
+		TemplateRemovalsOrOrphans: []UpgradeChange{{Path: ".agent-layer/stale.md"}},
+		PinVersionChange:          UpgradePinVersionDiff{Current: "0.8.0", Target: "0.9.0", Action: UpgradePinActionUpdate},
+		MigrationReport: UpgradeMigrationReport{Entries: []UpgradeMigrationEntry{
+			{ID: "rename-key", Kind: string(upgradeMigrationKindConfigRenameKey), Status: UpgradeMigrationStatusPlanned, From: "a.b", To: "a.c"},
+			{ID: "skipped", Kind: string(upgradeMigrationKindDeleteFile), Status: UpgradeMigrationStatusSkippedSourceTooOld, Path: "x"},
+		}},
+	}
+
+	groups := ClassifyUpgradeRisks(plan)
+	want := map[UpgradeRisk]struct {
+		count     int
+		skippable bool
+	}{
+		UpgradeRiskSafe:        {count: 3, skippable: false},
+		UpgradeRiskConfig:      {count: 1, skippable: false},
+		UpgradeRiskOverwrite:   {count: 2, skippable: true},
+		UpgradeRiskDestructive: {count: 2, skippable: true},
+	}
+	if len(groups) != len(want) {
+		t.Fatalf("groups = %+v", groups)
+	}
+	for i, group := range groups {
+		if group.Risk != UpgradeRisks[i] {
+			t.Fatalf("group %d risk = %s, want %s", i, group.Risk, UpgradeRisks[i])
+		}
+		expected := want[group.Risk]
+		if len(group.Items) != expected.count || group.Skippable != expected.skippable {
+			t.Fatalf("group %s = %d items (skippable=%v), want %d (skippable=%v)",
+				group.Risk, len(group.Items), group.Skippable, expected.count, expected.skippable)
+		}
+	}
+	if groups[1].Items[0].Path != "a.b -> a.c" || !groups[1].Items[0].Migration {
+		t.Fatalf("config item = %+v", groups[1].Items[0])
+	}
+	if MaxUpgradeRisk(groups) != UpgradeRiskDestructive {
+		t.Fatalf("MaxUpgradeRisk = %s", MaxUpgradeRisk(groups))
+	}
+}
+
+func TestClassifyUpgradeRisks_BreakingMigrationBlocksSkip(t *testing.T) {
+	plan := UpgradePlan{
+		TemplateRemovalsOrOrphans: []UpgradeChange{{Path: ".agent-layer/stale.md"}},
+		MigrationReport: UpgradeMigrationReport{Entries: []UpgradeMigrationEntry{
+			{ID: "breaking", Kind: string(upgradeMigrationKindConfigDeleteKey), Status: UpgradeMigrationStatusPlanned, Key: "old", Breaking: true},
+		}},
+	}
+	groups := ClassifyUpgradeRisks(plan)
+	if len(groups) != 1 || groups[0].Risk != UpgradeRiskDestructive || groups[0].Skippable {
+		t.Fatalf("groups = %+v", groups)
+	}
+}
+
+func TestClassifyUpgradeRisks_Empty(t *testing.T) {
+	if groups := ClassifyUpgradeRisks(UpgradePlan{PinVersionChange: UpgradePinVersionDiff{Action: UpgradePinActionNone}}); len(groups) != 0 {
+		t.Fatalf("expected no groups, got %+v", groups)
+	}
+	if MaxUpgradeRisk(nil) != UpgradeRiskSafe {
+		t.Fatal("expected safe for no groups")
+	}
+}
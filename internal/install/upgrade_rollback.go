@@ -9,6 +9,7 @@ import (
 	"sort"
 	"strings"
 
+	"github.com/conn-castle/agent-layer/internal/i18n"
 	"github.com/conn-castle/agent-layer/internal/messages"
 )
 
@@ -22,28 +23,31 @@ type RollbackUpgradeSnapshotOptions struct {
 // RollbackUpgradeSnapshot restores a previously captured managed-file snapshot by ID.
 func RollbackUpgradeSnapshot(root string, snapshotID string, opts RollbackUpgradeSnapshotOptions) error {
 	if strings.TrimSpace(root) == "" {
-		return fmt.Errorf(messages.InstallRootRequired)
+		return i18n.Errorf(messages.InstallRootRequired)
 	}
 	snapshotID = strings.TrimSpace(snapshotID)
 	if snapshotID == "" {
-		return fmt.Errorf(messages.InstallUpgradeRollbackSnapshotIDRequired)
+		return i18n.Errorf(messages.InstallUpgradeRollbackSnapshotIDRequired)
 	}
 	// Reject path traversal: snapshotID must be a bare filename component.
 	if filepath.Base(snapshotID) != snapshotID {
-		return fmt.Errorf(messages.InstallUpgradeRollbackSnapshotIDInvalid, snapshotID)
+		return i18n.Errorf(messages.InstallUpgradeRollbackSnapshotIDInvalid, snapshotID)
 	}
-	sys := opts.System
-	if sys == nil {
-		return fmt.Errorf(messages.InstallSystemRequired)
+	if opts.System == nil {
+		return i18n.Errorf(messages.InstallSystemRequired)
+	}
+	sys, err := layerSystem(root, opts.System)
+	if err != nil {
+		return err
 	}
 
 	snapshotDir := filepath.Join(root, filepath.FromSlash(upgradeSnapshotDirRelPath))
 	snapshotPath := filepath.Join(snapshotDir, snapshotID+".json")
 	if _, err := sys.Stat(snapshotPath); err != nil {
 		if errors.Is(err, os.ErrNotExist) {
-			return fmt.Errorf(messages.InstallUpgradeRollbackSnapshotNotFoundFmt, snapshotID, snapshotDir)
+			return i18n.Errorf(messages.InstallUpgradeRollbackSnapshotNotFoundFmt, snapshotID, snapshotDir)
 		}
-		return fmt.Errorf(messages.InstallFailedStatFmt, snapshotPath, err)
+		return i18n.Errorf(messages.InstallFailedStatFmt, snapshotPath, err)
 	}
 
 	snapshot, err := readUpgradeSnapshot(snapshotPath, sys)
@@ -53,7 +57,7 @@ func RollbackUpgradeSnapshot(root string, snapshotID string, opts RollbackUpgrad
 	if snapshot.Status != upgradeSnapshotStatusApplied &&
 		snapshot.Status != upgradeSnapshotStatusCreated &&
 		snapshot.Status != upgradeSnapshotStatusRollbackFailed {
-		return fmt.Errorf(messages.InstallUpgradeRollbackSnapshotNotRollbackableFmt, snapshotID, snapshot.Status)
+		return i18n.Errorf(messages.InstallUpgradeRollbackSnapshotNotRollbackableFmt, snapshotID, snapshot.Status)
 	}
 
 	targets, err := rollbackTargetsForSnapshot(root, snapshot)
@@ -80,13 +84,20 @@ func RollbackUpgradeSnapshot(root string, snapshotID string, opts RollbackUpgrad
 		if writeErr := writeUpgradeSnapshotFile(snapshotPath, snapshot, sys); writeErr != nil {
 			return fmt.Errorf("rollback snapshot %s failed: %w; failed to persist rollback_failed state: %v", snapshotID, err, writeErr)
 		}
-		return fmt.Errorf(messages.InstallUpgradeRollbackFailedFmt, snapshotID, err)
+		return i18n.Errorf(messages.InstallUpgradeRollbackFailedFmt, snapshotID, err)
 	}
 
 	snapshot.Status = upgradeSnapshotStatusManuallyRolledBack
 	if err := writeUpgradeSnapshotFile(snapshotPath, snapshot, sys); err != nil {
 		return fmt.Errorf("rollback snapshot %s succeeded but failed to persist manually_rolled_back state: %w", snapshotID, err)
 	}
+	// Restoring the snapshot an interrupted upgrade took resolves it.
+	journalPath := installJournalPath(root)
+	if journal, err := readInstallJournal(journalPath, sys); err == nil && journal != nil && journal.SnapshotID == snapshotID {
+		if err := sys.RemoveAll(journalPath); err != nil {
+			return i18n.Errorf(messages.InstallJournalRemoveFmt, journalPath, err)
+		}
+	}
 	return nil
 }
 
@@ -382,10 +393,10 @@ func restoreUpgradeSnapshotEntriesAtRoot(root string, sys System, entries []upgr
 			return fmt.Errorf("decode content for %s: %w", entry.Path, err)
 		}
 		if err := sys.MkdirAll(filepath.Dir(absPath), 0o755); err != nil {
-			return fmt.Errorf(messages.InstallFailedCreateDirForFmt, absPath, err)
+			return i18n.Errorf(messages.InstallFailedCreateDirForFmt, absPath, err)
 		}
 		if err := sys.WriteFileAtomic(absPath, content, permFromSnapshot(entry.Perm, 0o644)); err != nil {
-			return fmt.Errorf(messages.InstallFailedWriteFmt, absPath, err)
+			return i18n.Errorf(messages.InstallFailedWriteFmt, absPath, err)
 		}
 	}
 	for _, entry := range symlinks {
@@ -397,14 +408,14 @@ func restoreUpgradeSnapshotEntriesAtRoot(root string, sys System, entries []upgr
 			return fmt.Errorf("symlink snapshot entry %s requires link_target", entry.Path)
 		}
 		if err := sys.MkdirAll(filepath.Dir(absPath), 0o755); err != nil {
-			return fmt.Errorf(messages.InstallFailedCreateDirForFmt, absPath, err)
+			return i18n.Errorf(messages.InstallFailedCreateDirForFmt, absPath, err)
 		}
 		// Defensively remove any pre-existing file/symlink at the target path.
 		// The rollback reset phase should have already cleared it, but this
 		// prevents EEXIST if the function is called outside a full rollback flow.
 		_ = sys.RemoveAll(absPath)
 		if err := sys.Symlink(entry.LinkTarget, absPath); err != nil {
-			return fmt.Errorf(messages.InstallFailedRestoreSymlinkFmt, entry.Path, err)
+			return i18n.Errorf(messages.InstallFailedRestoreSymlinkFmt, entry.Path, err)
 		}
 	}
 	// Apply final directory modes only after every descendant is restored. The
@@ -12,16 +12,35 @@ import (
 	"strings"
 	"time"
 
+	"github.com/conn-castle/agent-layer/internal/clock"
+	"github.com/conn-castle/agent-layer/internal/i18n"
 	"github.com/conn-castle/agent-layer/internal/launchers"
 	"github.com/conn-castle/agent-layer/internal/messages"
 )
 
 const (
-	upgradeSnapshotSchemaVersion = 1
-	upgradeSnapshotDirRelPath    = ".agent-layer/state/upgrade-snapshots"
-	upgradeSnapshotMaxRetained   = 20
+	// upgradeSnapshotSchemaVersion is written by new snapshots. Version 3
+	// stores file contents as shared blobs referenced by content_sha256.
+	upgradeSnapshotSchemaVersion = 3
+	// upgradeSnapshotSchemaVersionV2 snapshots embed file contents as
+	// content_base64. Version 2 perm values carry the setuid, setgid, and
+	// sticky bits (0o7000) alongside the permission bits.
+	upgradeSnapshotSchemaVersionV2 = 2
+	// upgradeSnapshotSchemaVersionV1 snapshots record only permission bits
+	// (0o777). Older snapshots remain readable and restorable, and keep their
+	// version when their status is rewritten.
+	upgradeSnapshotSchemaVersionV1 = 1
+	upgradeSnapshotDirRelPath      = ".agent-layer/state/upgrade-snapshots"
+	upgradeSnapshotMaxRetained     = 20
 )
 
+// upgradeSnapshotPermMask bounds the perm values each schema version may record.
+var upgradeSnapshotPermMask = map[int]uint32{
+	upgradeSnapshotSchemaVersionV1: 0o777,
+	upgradeSnapshotSchemaVersionV2: 0o7777,
+	upgradeSnapshotSchemaVersion:   0o7777,
+}
+
 var upgradeSnapshotSizeWarningBytes int64 = 50 * 1024 * 1024 // 50MB
 
 type upgradeSnapshotStatus string
@@ -48,6 +67,7 @@ type upgradeSnapshotEntry struct {
 	Kind          upgradeSnapshotEntryKind `json:"kind"`
 	Perm          *uint32                  `json:"perm,omitempty"`
 	ContentBase64 string                   `json:"content_base64,omitempty"`
+	ContentSHA256 string                   `json:"content_sha256,omitempty"`
 	LinkTarget    string                   `json:"link_target,omitempty"`
 }
 
@@ -75,13 +95,21 @@ type UpgradeSnapshotMetadata struct {
 	Status       string
 }
 
+// UpgradeSnapshotStatusApplied is the UpgradeSnapshotMetadata.Status of a
+// snapshot whose upgrade completed and has not been rolled back.
+const UpgradeSnapshotStatusApplied = string(upgradeSnapshotStatusApplied)
+
 // ListUpgradeSnapshots returns metadata for all available upgrade snapshots, sorted by creation time (newest first).
 func ListUpgradeSnapshots(root string, sys System) ([]UpgradeSnapshotMetadata, error) {
 	if strings.TrimSpace(root) == "" {
-		return nil, fmt.Errorf(messages.InstallRootRequired)
+		return nil, i18n.Errorf(messages.InstallRootRequired)
 	}
 	if sys == nil {
-		return nil, fmt.Errorf(messages.InstallSystemRequired)
+		return nil, i18n.Errorf(messages.InstallSystemRequired)
+	}
+	sys, err := layerSystem(root, sys)
+	if err != nil {
+		return nil, err
 	}
 	inst := &installer{root: root, sys: sys}
 	files, err := inst.listUpgradeSnapshotFiles()
@@ -91,14 +119,14 @@ func ListUpgradeSnapshots(root string, sys System) ([]UpgradeSnapshotMetadata, e
 	// listUpgradeSnapshotFiles returns oldest first; we want newest first.
 	out := make([]UpgradeSnapshotMetadata, 0, len(files))
 	for i := len(files) - 1; i >= 0; i-- {
-		snapshot, err := readUpgradeSnapshot(files[i].path, sys)
+		snapshot, err := readUpgradeSnapshotManifest(files[i].path, sys)
 		if err != nil {
 			// Skip unreadable/malformed snapshots instead of aborting the list.
 			continue
 		}
 		out = append(out, UpgradeSnapshotMetadata{
 			ID:           snapshot.SnapshotID,
-			CreatedAtUTC: snapshot.CreatedAtUTC,
+			CreatedAtUTC: clock.Format(files[i].createdAt),
 			Status:       string(snapshot.Status),
 		})
 	}
@@ -110,23 +138,23 @@ func (inst *installer) createUpgradeSnapshot() (upgradeSnapshot, error) {
 	if err != nil {
 		return upgradeSnapshot{}, err
 	}
-	now := time.Now().UTC()
+	now := inst.now()
 	snapshot := upgradeSnapshot{
 		SchemaVersion: upgradeSnapshotSchemaVersion,
 		SnapshotID:    newUpgradeSnapshotID(now),
-		CreatedAtUTC:  now.Format(time.RFC3339),
+		CreatedAtUTC:  clock.Format(now),
 		Status:        upgradeSnapshotStatusCreated,
 		Entries:       entries,
 	}
 	if err := inst.writeUpgradeSnapshot(snapshot, true); err != nil {
 		return upgradeSnapshot{}, err
 	}
-	_, _ = fmt.Fprintf(inst.warnOutput(), messages.InstallUpgradeSnapshotCreatedFmt, snapshot.SnapshotID, snapshot.SnapshotID)
+	_, _ = fmt.Fprintf(inst.warnOutput(), i18n.T(messages.InstallUpgradeSnapshotCreatedFmt), snapshot.SnapshotID, snapshot.SnapshotID)
 	return snapshot, nil
 }
 
 func newUpgradeSnapshotID(now time.Time) string {
-	return fmt.Sprintf("%s-%d", now.UTC().Format("20060102-150405"), now.UTC().UnixNano())
+	return fmt.Sprintf("%s-%d", clock.Stamp(now), now.UTC().UnixNano())
 }
 
 func (inst *installer) writeUpgradeSnapshot(snapshot upgradeSnapshot, pruneBeforeCreate bool) error {
@@ -140,39 +168,70 @@ func (inst *installer) writeUpgradeSnapshot(snapshot upgradeSnapshot, pruneBefor
 	}
 	dir := inst.upgradeSnapshotDirPath()
 	if err := inst.sys.MkdirAll(dir, 0o755); err != nil {
-		return fmt.Errorf(messages.InstallFailedCreateDirForFmt, dir, err)
+		return i18n.Errorf(messages.InstallFailedCreateDirForFmt, dir, err)
 	}
 	path := filepath.Join(dir, snapshot.SnapshotID+".json")
 	if err := writeUpgradeSnapshotFile(path, snapshot, inst.sys); err != nil {
 		return err
 	}
 
-	// Check size and warn if it exceeds threshold.
-	if info, err := inst.sys.Stat(path); err == nil && info.Size() > upgradeSnapshotSizeWarningBytes {
-		_, _ = fmt.Fprintf(inst.warnOutput(), messages.InstallUpgradeSnapshotLargeWarningFmt, path, info.Size()/1024/1024, upgradeSnapshotSizeWarningBytes/1024/1024)
+	// Warn on the captured content size; blob dedup and compression shrink
+	// what lands on disk, but restore still has to rewrite all of it.
+	if size := upgradeSnapshotContentBytes(snapshot); size > upgradeSnapshotSizeWarningBytes {
+		_, _ = fmt.Fprintf(inst.warnOutput(), i18n.T(messages.InstallUpgradeSnapshotLargeWarningFmt), path, size/1024/1024, upgradeSnapshotSizeWarningBytes/1024/1024)
 	}
 	return nil
 }
 
+// upgradeSnapshotContentBytes sums the decoded size of every file entry.
+func upgradeSnapshotContentBytes(snapshot upgradeSnapshot) int64 {
+	var size int64
+	for _, entry := range snapshot.Entries {
+		if entry.Kind == upgradeSnapshotEntryKindFile {
+			size += int64(base64.StdEncoding.DecodedLen(len(entry.ContentBase64)))
+		}
+	}
+	return size
+}
+
 func writeUpgradeSnapshotFile(path string, snapshot upgradeSnapshot, sys System) error {
 	if err := validateUpgradeSnapshot(snapshot); err != nil {
 		return fmt.Errorf("validate upgrade snapshot: %w", err)
 	}
+	snapshot, err := externalizeUpgradeSnapshotContent(snapshot, upgradeSnapshotBlobDir(path), sys)
+	if err != nil {
+		return err
+	}
 	data, err := json.MarshalIndent(snapshot, "", "  ")
 	if err != nil {
 		return fmt.Errorf("marshal upgrade snapshot: %w", err)
 	}
 	data = append(data, '\n')
 	if err := sys.WriteFileAtomic(path, data, 0o644); err != nil {
-		return fmt.Errorf(messages.InstallFailedWriteFmt, path, err)
+		return i18n.Errorf(messages.InstallFailedWriteFmt, path, err)
 	}
 	return nil
 }
 
+// readUpgradeSnapshot reads a snapshot manifest and loads any blob-backed file
+// contents, so callers always receive a hydrated snapshot.
 func readUpgradeSnapshot(path string, sys System) (upgradeSnapshot, error) {
+	snapshot, err := readUpgradeSnapshotManifest(path, sys)
+	if err != nil {
+		return upgradeSnapshot{}, err
+	}
+	if err := hydrateUpgradeSnapshotContent(&snapshot, upgradeSnapshotBlobDir(path), sys); err != nil {
+		return upgradeSnapshot{}, fmt.Errorf("load upgrade snapshot %s: %w", path, err)
+	}
+	return snapshot, nil
+}
+
+// readUpgradeSnapshotManifest reads and validates a snapshot without loading
+// blob-backed contents; listing and pruning need only its metadata.
+func readUpgradeSnapshotManifest(path string, sys System) (upgradeSnapshot, error) {
 	data, err := sys.ReadFile(path)
 	if err != nil {
-		return upgradeSnapshot{}, fmt.Errorf(messages.InstallFailedReadFmt, path, err)
+		return upgradeSnapshot{}, i18n.Errorf(messages.InstallFailedReadFmt, path, err)
 	}
 	var snapshot upgradeSnapshot
 	if err := json.Unmarshal(data, &snapshot); err != nil {
@@ -181,11 +240,19 @@ func readUpgradeSnapshot(path string, sys System) (upgradeSnapshot, error) {
 	if err := validateUpgradeSnapshot(snapshot); err != nil {
 		return upgradeSnapshot{}, fmt.Errorf("validate upgrade snapshot %s: %w", path, err)
 	}
+	if snapshot.SchemaVersion >= upgradeSnapshotSchemaVersion {
+		for _, entry := range snapshot.Entries {
+			if entry.Kind == upgradeSnapshotEntryKindFile && entry.ContentSHA256 == "" {
+				return upgradeSnapshot{}, fmt.Errorf("validate upgrade snapshot %s: file snapshot entry %s requires content_sha256", path, entry.Path)
+			}
+		}
+	}
 	return snapshot, nil
 }
 
 func validateUpgradeSnapshot(snapshot upgradeSnapshot) error {
-	if snapshot.SchemaVersion != upgradeSnapshotSchemaVersion {
+	permMask, ok := upgradeSnapshotPermMask[snapshot.SchemaVersion]
+	if !ok {
 		return fmt.Errorf("unsupported schema_version %d", snapshot.SchemaVersion)
 	}
 	if strings.TrimSpace(snapshot.SnapshotID) == "" {
@@ -194,7 +261,7 @@ func validateUpgradeSnapshot(snapshot upgradeSnapshot) error {
 	if strings.TrimSpace(snapshot.CreatedAtUTC) == "" {
 		return fmt.Errorf("created_at_utc is required")
 	}
-	if _, err := time.Parse(time.RFC3339, snapshot.CreatedAtUTC); err != nil {
+	if _, err := clock.Parse(snapshot.CreatedAtUTC); err != nil {
 		return fmt.Errorf("invalid created_at_utc %q: %w", snapshot.CreatedAtUTC, err)
 	}
 	switch snapshot.Status {
@@ -207,6 +274,12 @@ func validateUpgradeSnapshot(snapshot upgradeSnapshot) error {
 		if err := validateUpgradeSnapshotEntry(entry); err != nil {
 			return err
 		}
+		if entry.ContentSHA256 != "" && snapshot.SchemaVersion < upgradeSnapshotSchemaVersion {
+			return fmt.Errorf("snapshot entry %s sets content_sha256, which schema_version %d does not support", entry.Path, snapshot.SchemaVersion)
+		}
+		if entry.Perm != nil && *entry.Perm&^permMask != 0 {
+			return fmt.Errorf("snapshot entry %s has perm %#o outside schema_version %d range %#o", entry.Path, *entry.Perm, snapshot.SchemaVersion, permMask)
+		}
 		if _, ok := seen[entry.Path]; ok {
 			return fmt.Errorf("duplicate snapshot entry path %q", entry.Path)
 		}
@@ -227,6 +300,9 @@ func validateUpgradeSnapshotEntry(entry upgradeSnapshotEntry) error {
 		if entry.LinkTarget != "" {
 			return fmt.Errorf("file snapshot entry %s must not set link_target", entry.Path)
 		}
+		if entry.ContentSHA256 != "" && !isValidContentSHA256(entry.ContentSHA256) {
+			return fmt.Errorf("file snapshot entry %s has invalid content_sha256 %q", entry.Path, entry.ContentSHA256)
+		}
 	case upgradeSnapshotEntryKindDir:
 		if entry.ContentBase64 != "" {
 			return fmt.Errorf("dir snapshot entry %s must not set content_base64", entry.Path)
@@ -257,6 +333,9 @@ func validateUpgradeSnapshotEntry(entry upgradeSnapshotEntry) error {
 	default:
 		return fmt.Errorf("invalid snapshot entry kind %q", entry.Kind)
 	}
+	if entry.Kind != upgradeSnapshotEntryKindFile && entry.ContentSHA256 != "" {
+		return fmt.Errorf("%s snapshot entry %s must not set content_sha256", entry.Kind, entry.Path)
+	}
 	return nil
 }
 
@@ -269,7 +348,7 @@ func (inst *installer) pruneUpgradeSnapshots(retain int) error {
 		if errors.Is(err, os.ErrNotExist) {
 			return nil
 		}
-		return fmt.Errorf(messages.InstallFailedStatFmt, dir, err)
+		return i18n.Errorf(messages.InstallFailedStatFmt, dir, err)
 	}
 	files, err := inst.listUpgradeSnapshotFiles()
 	if err != nil {
@@ -283,7 +362,7 @@ func (inst *installer) pruneUpgradeSnapshots(retain int) error {
 			return fmt.Errorf("delete old upgrade snapshot %s: %w", files[i].path, err)
 		}
 	}
-	return nil
+	return inst.pruneUpgradeSnapshotBlobs(files[len(files)-retain:])
 }
 
 func (inst *installer) listUpgradeSnapshotFiles() ([]upgradeSnapshotFile, error) {
@@ -292,7 +371,7 @@ func (inst *installer) listUpgradeSnapshotFiles() ([]upgradeSnapshotFile, error)
 		if errors.Is(err, os.ErrNotExist) {
 			return nil, nil
 		}
-		return nil, fmt.Errorf(messages.InstallFailedStatFmt, dir, err)
+		return nil, i18n.Errorf(messages.InstallFailedStatFmt, dir, err)
 	}
 	files := make([]upgradeSnapshotFile, 0)
 	if err := inst.sys.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
@@ -300,6 +379,9 @@ func (inst *installer) listUpgradeSnapshotFiles() ([]upgradeSnapshotFile, error)
 			return err
 		}
 		if entry.IsDir() {
+			if entry.Name() == upgradeSnapshotBlobDirName && filepath.Dir(path) == dir {
+				return filepath.SkipDir
+			}
 			return nil
 		}
 		if !strings.HasSuffix(path, ".json") {
@@ -312,7 +394,7 @@ func (inst *installer) listUpgradeSnapshotFiles() ([]upgradeSnapshotFile, error)
 			// should not block the entire upgrade lifecycle.
 			return nil
 		}
-		createdAt, parseErr := time.Parse(time.RFC3339, snapshot.CreatedAtUTC)
+		createdAt, parseErr := clock.Parse(snapshot.CreatedAtUTC)
 		if parseErr != nil {
 			return fmt.Errorf("parse created_at_utc for %s: %w", path, parseErr)
 		}
@@ -335,7 +417,7 @@ func (inst *installer) listUpgradeSnapshotFiles() ([]upgradeSnapshotFile, error)
 }
 
 func readUpgradeSnapshotIfValid(path string, sys System) (upgradeSnapshot, bool) {
-	snapshot, err := readUpgradeSnapshot(path, sys)
+	snapshot, err := readUpgradeSnapshotManifest(path, sys)
 	if err != nil {
 		return upgradeSnapshot{}, false
 	}
@@ -356,11 +438,20 @@ func (inst *installer) rollbackUpgradeSnapshot(snapshot *upgradeSnapshot, target
 
 func (inst *installer) captureUpgradeSnapshotEntries() ([]upgradeSnapshotEntry, error) {
 	targets := inst.upgradeSnapshotTargetPaths()
+	progress := inst.output.Progress(messages.InstallUpgradeSnapshotProgressLabel, len(targets))
+	defer progress.Done()
+	return inst.captureUpgradeEntries(targets, progress.Step)
+}
+
+// captureUpgradeEntries captures targets in snapshot form, sorted by path,
+// calling step after each target.
+func (inst *installer) captureUpgradeEntries(targets []string, step func()) ([]upgradeSnapshotEntry, error) {
 	entries := make(map[string]upgradeSnapshotEntry)
 	for _, target := range targets {
 		if err := inst.captureUpgradeSnapshotTarget(target, entries); err != nil {
 			return nil, err
 		}
+		step()
 	}
 	out := make([]upgradeSnapshotEntry, 0, len(entries))
 	for _, entry := range entries {
@@ -478,7 +569,7 @@ func (inst *installer) captureUpgradeSnapshotFile(path string, mode fs.FileMode,
 	}
 	content, err := inst.sys.ReadFile(path)
 	if err != nil {
-		return fmt.Errorf(messages.InstallFailedReadFmt, path, err)
+		return i18n.Errorf(messages.InstallFailedReadFmt, path, err)
 	}
 	upsertUpgradeSnapshotEntry(entries, upgradeSnapshotEntry{
 		Path:          relPath,
@@ -535,7 +626,7 @@ func (inst *installer) updateGitignoreTargetPaths() []string {
 }
 
 func (inst *installer) writeVSCodeLaunchersTargetPaths() []string {
-	return uniqueNormalizedPaths(launchers.VSCodePaths(inst.root).All())
+	return uniqueNormalizedPaths(launchers.VSCodePathsIn(filepath.Join(inst.root, ".agent-layer")).All())
 }
 
 func (inst *installer) handleUnknownsTargetPaths() []string {
@@ -589,7 +680,7 @@ func (inst *installer) upgradeSnapshotTargetPaths() []string {
 		add(path)
 	}
 	add(filepath.Join(root, ".gitignore"))
-	for _, path := range launchers.VSCodePaths(root).All() {
+	for _, path := range launchers.VSCodePathsIn(filepath.Join(root, ".agent-layer")).All() {
 		add(path)
 	}
 	for _, path := range inst.runMigrationsTargetPaths() {
@@ -654,8 +745,25 @@ func (inst *installer) upgradeSnapshotDirPath() string {
 	return filepath.Join(inst.root, filepath.FromSlash(upgradeSnapshotDirRelPath))
 }
 
+// Snapshot perm values use Unix octal notation, where the special mode bits
+// sit above the permission bits rather than in fs.FileMode's high flags.
+const (
+	snapshotPermSetuid = 0o4000
+	snapshotPermSetgid = 0o2000
+	snapshotPermSticky = 0o1000
+)
+
 func permToSnapshot(mode fs.FileMode) *uint32 {
 	perm := uint32(mode.Perm())
+	if mode&os.ModeSetuid != 0 {
+		perm |= snapshotPermSetuid
+	}
+	if mode&os.ModeSetgid != 0 {
+		perm |= snapshotPermSetgid
+	}
+	if mode&os.ModeSticky != 0 {
+		perm |= snapshotPermSticky
+	}
 	return &perm
 }
 
@@ -663,5 +771,15 @@ func permFromSnapshot(perm *uint32, fallback fs.FileMode) fs.FileMode {
 	if perm == nil {
 		return fallback
 	}
-	return fs.FileMode(*perm) & os.ModePerm
+	mode := fs.FileMode(*perm) & os.ModePerm
+	if *perm&snapshotPermSetuid != 0 {
+		mode |= os.ModeSetuid
+	}
+	if *perm&snapshotPermSetgid != 0 {
+		mode |= os.ModeSetgid
+	}
+	if *perm&snapshotPermSticky != 0 {
+		mode |= os.ModeSticky
+	}
+	return mode
 }
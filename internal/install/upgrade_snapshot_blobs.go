@@ -0,0 +1,188 @@
+package install
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/conn-castle/agent-layer/internal/i18n"
+	"github.com/conn-castle/agent-layer/internal/messages"
+)
+
+// Schema v3 snapshots keep file contents out of the JSON manifest. Each file
+// body is stored once under blobs/ as <sha256>.gz, so unchanged files are
+// shared by every snapshot that captured them. In memory a snapshot is always
+// hydrated: file entries carry ContentBase64 regardless of schema version.
+const upgradeSnapshotBlobDirName = "blobs"
+
+// upgradeSnapshotBlobDir returns the blob directory next to a snapshot manifest.
+func upgradeSnapshotBlobDir(manifestPath string) string {
+	return filepath.Join(filepath.Dir(manifestPath), upgradeSnapshotBlobDirName)
+}
+
+func upgradeSnapshotBlobPath(blobDir string, sum string) string {
+	return filepath.Join(blobDir, sum+".gz")
+}
+
+func isValidContentSHA256(sum string) bool {
+	if len(sum) != sha256.Size*2 {
+		return false
+	}
+	_, err := hex.DecodeString(sum)
+	return err == nil && strings.ToLower(sum) == sum
+}
+
+// externalizeUpgradeSnapshotContent writes each file body of a v3 snapshot to
+// the blob store and returns a copy whose file entries reference blobs instead
+// of embedding content. Snapshots with an older schema are returned unchanged.
+func externalizeUpgradeSnapshotContent(snapshot upgradeSnapshot, blobDir string, sys System) (upgradeSnapshot, error) {
+	if snapshot.SchemaVersion < upgradeSnapshotSchemaVersion {
+		return snapshot, nil
+	}
+	entries := make([]upgradeSnapshotEntry, len(snapshot.Entries))
+	copy(entries, snapshot.Entries)
+	createdDir := false
+	for i, entry := range entries {
+		if entry.Kind != upgradeSnapshotEntryKindFile {
+			continue
+		}
+		content, err := base64.StdEncoding.DecodeString(entry.ContentBase64)
+		if err != nil {
+			return upgradeSnapshot{}, fmt.Errorf("decode content for %s: %w", entry.Path, err)
+		}
+		digest := sha256.Sum256(content)
+		sum := hex.EncodeToString(digest[:])
+		path := upgradeSnapshotBlobPath(blobDir, sum)
+		if _, err := sys.Stat(path); err != nil {
+			if !errors.Is(err, os.ErrNotExist) {
+				return upgradeSnapshot{}, i18n.Errorf(messages.InstallFailedStatFmt, path, err)
+			}
+			if !createdDir {
+				if err := sys.MkdirAll(blobDir, 0o755); err != nil {
+					return upgradeSnapshot{}, i18n.Errorf(messages.InstallFailedCreateDirForFmt, blobDir, err)
+				}
+				createdDir = true
+			}
+			compressed, err := gzipBytes(content)
+			if err != nil {
+				return upgradeSnapshot{}, fmt.Errorf("compress content for %s: %w", entry.Path, err)
+			}
+			if err := sys.WriteFileAtomic(path, compressed, 0o644); err != nil {
+				return upgradeSnapshot{}, i18n.Errorf(messages.InstallFailedWriteFmt, path, err)
+			}
+		}
+		entries[i].ContentSHA256 = sum
+		entries[i].ContentBase64 = ""
+	}
+	snapshot.Entries = entries
+	return snapshot, nil
+}
+
+// hydrateUpgradeSnapshotContent loads blob-backed file contents into
+// ContentBase64, verifying each blob against its recorded checksum.
+func hydrateUpgradeSnapshotContent(snapshot *upgradeSnapshot, blobDir string, sys System) error {
+	for i, entry := range snapshot.Entries {
+		if entry.Kind != upgradeSnapshotEntryKindFile || entry.ContentSHA256 == "" {
+			continue
+		}
+		path := upgradeSnapshotBlobPath(blobDir, entry.ContentSHA256)
+		compressed, err := sys.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("read content blob for %s: %w", entry.Path, err)
+		}
+		content, err := gunzipBytes(compressed)
+		if err != nil {
+			return fmt.Errorf("decompress content blob for %s: %w", entry.Path, err)
+		}
+		digest := sha256.Sum256(content)
+		if hex.EncodeToString(digest[:]) != entry.ContentSHA256 {
+			return fmt.Errorf("content blob for %s does not match content_sha256 %s", entry.Path, entry.ContentSHA256)
+		}
+		snapshot.Entries[i].ContentBase64 = base64.StdEncoding.EncodeToString(content)
+	}
+	return nil
+}
+
+// pruneUpgradeSnapshotBlobs removes blobs no longer referenced by any
+// remaining snapshot manifest.
+func (inst *installer) pruneUpgradeSnapshotBlobs(remaining []upgradeSnapshotFile) error {
+	blobDir := filepath.Join(inst.upgradeSnapshotDirPath(), upgradeSnapshotBlobDirName)
+	if _, err := inst.sys.Stat(blobDir); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return i18n.Errorf(messages.InstallFailedStatFmt, blobDir, err)
+	}
+	referenced := make(map[string]struct{})
+	for _, file := range remaining {
+		snapshot, err := readUpgradeSnapshotManifest(file.path, inst.sys)
+		if err != nil {
+			// Keep every blob when a remaining manifest cannot be read; its
+			// references are unknown.
+			return nil
+		}
+		for _, entry := range snapshot.Entries {
+			if entry.ContentSHA256 != "" {
+				referenced[entry.ContentSHA256] = struct{}{}
+			}
+		}
+	}
+	var unreferenced []string
+	if err := inst.sys.WalkDir(blobDir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			if path != blobDir {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		sum, ok := strings.CutSuffix(entry.Name(), ".gz")
+		if !ok || !isValidContentSHA256(sum) {
+			return nil
+		}
+		if _, keep := referenced[sum]; !keep {
+			unreferenced = append(unreferenced, path)
+		}
+		return nil
+	}); err != nil {
+		return fmt.Errorf("list upgrade snapshot blobs under %s: %w", blobDir, err)
+	}
+	for _, path := range unreferenced {
+		if err := inst.sys.RemoveAll(path); err != nil {
+			return fmt.Errorf("delete unreferenced upgrade snapshot blob %s: %w", path, err)
+		}
+	}
+	return nil
+}
+
+func gzipBytes(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	writer := gzip.NewWriter(&buf)
+	if _, err := writer.Write(data); err != nil {
+		return nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func gunzipBytes(data []byte) ([]byte, error) {
+	reader, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = reader.Close() }()
+	return io.ReadAll(reader)
+}
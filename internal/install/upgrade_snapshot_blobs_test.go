@@ -0,0 +1,213 @@
+package install
+
+import (
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func blobTestSnapshot(id string, version int, contents map[string]string) upgradeSnapshot {
+	snapshot := upgradeSnapshot{
+		SchemaVersion: version,
+		SnapshotID:    id,
+		CreatedAtUTC:  "2026-01-01T00:00:00Z",
+		Status:        upgradeSnapshotStatusApplied,
+	}
+	for path, content := range contents {
+		perm := uint32(0o644)
+		snapshot.Entries = append(snapshot.Entries, upgradeSnapshotEntry{
+			Path:          path,
+			Kind:          upgradeSnapshotEntryKindFile,
+			Perm:          &perm,
+			ContentBase64: base64.StdEncoding.EncodeToString([]byte(content)),
+		})
+	}
+	return snapshot
+}
+
+func listBlobs(t *testing.T, dir string) []string {
+	t.Helper()
+	entries, err := os.ReadDir(filepath.Join(dir, upgradeSnapshotBlobDirName))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		t.Fatalf("read blob dir: %v", err)
+	}
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	return names
+}
+
+func TestWriteUpgradeSnapshotFile_StoresDedupedBlobs(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "s1.json")
+	snapshot := blobTestSnapshot("s1", upgradeSnapshotSchemaVersion, map[string]string{
+		"a.txt": "same content",
+		"b.txt": "same content",
+		"c.bin": "\x00\x01binary",
+	})
+	if err := writeUpgradeSnapshotFile(path, snapshot, RealSystem{}); err != nil {
+		t.Fatalf("write snapshot: %v", err)
+	}
+
+	if blobs := listBlobs(t, dir); len(blobs) != 2 {
+		t.Fatalf("expected 2 deduplicated blobs, got %v", blobs)
+	}
+	manifest, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read manifest: %v", err)
+	}
+	if strings.Contains(string(manifest), "content_base64") || !strings.Contains(string(manifest), "content_sha256") {
+		t.Fatalf("expected blob references in manifest, got %s", manifest)
+	}
+
+	got, err := readUpgradeSnapshot(path, RealSystem{})
+	if err != nil {
+		t.Fatalf("read snapshot: %v", err)
+	}
+	for _, entry := range got.Entries {
+		want, _ := findSnapshotEntry(snapshot, entry.Path)
+		if entry.ContentBase64 != want.ContentBase64 {
+			t.Fatalf("entry %s content = %q, want %q", entry.Path, entry.ContentBase64, want.ContentBase64)
+		}
+	}
+	if snapshot.Entries[0].ContentSHA256 != "" {
+		t.Fatalf("write must not mutate the caller's entries")
+	}
+}
+
+func TestWriteUpgradeSnapshotFile_OlderSchemaStaysInline(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "s1.json")
+	snapshot := blobTestSnapshot("s1", upgradeSnapshotSchemaVersionV2, map[string]string{"a.txt": "inline"})
+	if err := writeUpgradeSnapshotFile(path, snapshot, RealSystem{}); err != nil {
+		t.Fatalf("write snapshot: %v", err)
+	}
+	if blobs := listBlobs(t, dir); len(blobs) != 0 {
+		t.Fatalf("expected no blobs for schema v2, got %v", blobs)
+	}
+	got, err := readUpgradeSnapshot(path, RealSystem{})
+	if err != nil {
+		t.Fatalf("read snapshot: %v", err)
+	}
+	if got.Entries[0].ContentBase64 != snapshot.Entries[0].ContentBase64 {
+		t.Fatalf("unexpected inline content %q", got.Entries[0].ContentBase64)
+	}
+}
+
+func TestReadUpgradeSnapshot_BlobErrors(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "s1.json")
+	if err := writeUpgradeSnapshotFile(path, blobTestSnapshot("s1", upgradeSnapshotSchemaVersion, map[string]string{"a.txt": "original"}), RealSystem{}); err != nil {
+		t.Fatalf("write snapshot: %v", err)
+	}
+	blobPath := filepath.Join(dir, upgradeSnapshotBlobDirName, listBlobs(t, dir)[0])
+
+	corrupt, err := gzipBytes([]byte("tampered"))
+	if err != nil {
+		t.Fatalf("gzip: %v", err)
+	}
+	if err := os.WriteFile(blobPath, corrupt, 0o600); err != nil {
+		t.Fatalf("write corrupt blob: %v", err)
+	}
+	if _, err := readUpgradeSnapshot(path, RealSystem{}); err == nil || !strings.Contains(err.Error(), "does not match content_sha256") {
+		t.Fatalf("expected checksum mismatch, got %v", err)
+	}
+
+	if err := os.WriteFile(blobPath, []byte("not gzip"), 0o600); err != nil {
+		t.Fatalf("write invalid blob: %v", err)
+	}
+	if _, err := readUpgradeSnapshot(path, RealSystem{}); err == nil || !strings.Contains(err.Error(), "decompress content blob") {
+		t.Fatalf("expected decompress error, got %v", err)
+	}
+
+	if err := os.Remove(blobPath); err != nil {
+		t.Fatalf("remove blob: %v", err)
+	}
+	if _, err := readUpgradeSnapshot(path, RealSystem{}); err == nil || !strings.Contains(err.Error(), "read content blob") {
+		t.Fatalf("expected missing blob error, got %v", err)
+	}
+	if _, err := readUpgradeSnapshotManifest(path, RealSystem{}); err != nil {
+		t.Fatalf("manifest read should not load blobs: %v", err)
+	}
+}
+
+func TestReadUpgradeSnapshotManifest_V3RequiresContentSHA256(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "s1.json")
+	data := `{"schema_version": 3, "snapshot_id": "s1", "created_at_utc": "2026-01-01T00:00:00Z", "status": "applied",
+  "entries": [{"path": "a.txt", "kind": "file", "content_base64": "YQ=="}]}`
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatalf("write manifest: %v", err)
+	}
+	if _, err := readUpgradeSnapshotManifest(path, RealSystem{}); err == nil || !strings.Contains(err.Error(), "requires content_sha256") {
+		t.Fatalf("expected missing content_sha256 error, got %v", err)
+	}
+}
+
+func TestValidateUpgradeSnapshot_ContentSHA256(t *testing.T) {
+	valid := strings.Repeat("a", 64)
+	tests := []struct {
+		name     string
+		version  int
+		kind     upgradeSnapshotEntryKind
+		checksum string
+		want     string
+	}{
+		{name: "older schema", version: upgradeSnapshotSchemaVersionV2, kind: upgradeSnapshotEntryKindFile, checksum: valid, want: "does not support"},
+		{name: "malformed", version: upgradeSnapshotSchemaVersion, kind: upgradeSnapshotEntryKindFile, checksum: "xyz", want: "invalid content_sha256"},
+		{name: "non-file", version: upgradeSnapshotSchemaVersion, kind: upgradeSnapshotEntryKindDir, checksum: valid, want: "must not set content_sha256"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			snapshot := upgradeSnapshot{
+				SchemaVersion: tt.version,
+				SnapshotID:    "s1",
+				CreatedAtUTC:  "2026-01-01T00:00:00Z",
+				Status:        upgradeSnapshotStatusApplied,
+				Entries:       []upgradeSnapshotEntry{{Path: "a", Kind: tt.kind, ContentSHA256: tt.checksum}},
+			}
+			if err := validateUpgradeSnapshot(snapshot); err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("expected %q error, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestPruneUpgradeSnapshots_RemovesUnreferencedBlobs(t *testing.T) {
+	root := t.TempDir()
+	inst := &installer{root: root, sys: RealSystem{}}
+	dir := inst.upgradeSnapshotDirPath()
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	old := blobTestSnapshot("s1", upgradeSnapshotSchemaVersion, map[string]string{"a.txt": "shared", "b.txt": "old only"})
+	newer := blobTestSnapshot("s2", upgradeSnapshotSchemaVersion, map[string]string{"a.txt": "shared"})
+	newer.CreatedAtUTC = "2026-01-02T00:00:00Z"
+	for _, snapshot := range []upgradeSnapshot{old, newer} {
+		if err := writeUpgradeSnapshotFile(filepath.Join(dir, snapshot.SnapshotID+".json"), snapshot, RealSystem{}); err != nil {
+			t.Fatalf("write snapshot: %v", err)
+		}
+	}
+	if blobs := listBlobs(t, dir); len(blobs) != 2 {
+		t.Fatalf("expected 2 blobs before prune, got %v", blobs)
+	}
+
+	if err := inst.pruneUpgradeSnapshots(1); err != nil {
+		t.Fatalf("prune: %v", err)
+	}
+	if blobs := listBlobs(t, dir); len(blobs) != 1 {
+		t.Fatalf("expected only the shared blob after prune, got %v", blobs)
+	}
+	if _, err := readUpgradeSnapshot(filepath.Join(dir, "s2.json"), RealSystem{}); err != nil {
+		t.Fatalf("remaining snapshot must stay readable: %v", err)
+	}
+	files, err := inst.listUpgradeSnapshotFiles()
+	if err != nil || len(files) != 1 {
+		t.Fatalf("expected one listed snapshot, got %v, %v", files, err)
+	}
+}
@@ -13,6 +13,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/conn-castle/agent-layer/internal/clock"
 	"github.com/conn-castle/agent-layer/internal/launchers"
 	"github.com/conn-castle/agent-layer/internal/messages"
 )
@@ -2813,6 +2814,62 @@ func TestListUpgradeSnapshots(t *testing.T) {
 	}
 }
 
+func TestListUpgradeSnapshots_LegacyTimestampsAcrossDST(t *testing.T) {
+	root := t.TempDir()
+	inst := &installer{root: root, sys: RealSystem{}}
+
+	// Snapshots written around a DST fall-back: the legacy local strings sort
+	// lexically in the wrong order, but their instants are an hour apart.
+	for id, createdAt := range map[string]string{
+		"before": "2026-11-01 01:30:00 -0700 PDT",
+		"after":  "2026-11-01 01:10:00 -0800 PST",
+		"latest": "2026-11-01T10:00:00Z",
+	} {
+		snapshot := upgradeSnapshot{
+			SchemaVersion: upgradeSnapshotSchemaVersion,
+			SnapshotID:    id,
+			CreatedAtUTC:  createdAt,
+			Status:        upgradeSnapshotStatusApplied,
+		}
+		if err := inst.writeUpgradeSnapshot(snapshot, false); err != nil {
+			t.Fatalf("write snapshot %s: %v", id, err)
+		}
+	}
+
+	snapshots, err := ListUpgradeSnapshots(root, RealSystem{})
+	if err != nil {
+		t.Fatalf("ListUpgradeSnapshots: %v", err)
+	}
+	var got []string
+	for _, snapshot := range snapshots {
+		got = append(got, snapshot.ID+"="+snapshot.CreatedAtUTC)
+	}
+	want := "latest=2026-11-01T10:00:00Z,after=2026-11-01T09:10:00Z,before=2026-11-01T08:30:00Z"
+	if strings.Join(got, ",") != want {
+		t.Fatalf("snapshots = %v, want %s", got, want)
+	}
+}
+
+func TestCreateUpgradeSnapshot_UsesInjectedClock(t *testing.T) {
+	root := t.TempDir()
+	inst := &installer{
+		root:       root,
+		sys:        RealSystem{},
+		warnWriter: &bytes.Buffer{},
+		clock:      clock.Fixed{T: time.Date(2026, time.March, 8, 1, 59, 0, 0, time.FixedZone("PST", -8*60*60))},
+	}
+	snapshot, err := inst.createUpgradeSnapshot()
+	if err != nil {
+		t.Fatalf("createUpgradeSnapshot: %v", err)
+	}
+	if snapshot.CreatedAtUTC != "2026-03-08T09:59:00Z" {
+		t.Fatalf("CreatedAtUTC = %q", snapshot.CreatedAtUTC)
+	}
+	if !strings.HasPrefix(snapshot.SnapshotID, "20260308-095900-") {
+		t.Fatalf("SnapshotID = %q", snapshot.SnapshotID)
+	}
+}
+
 func TestWriteUpgradeSnapshot_SizeWarning(t *testing.T) {
 	root := t.TempDir()
 	var warn bytes.Buffer
@@ -2981,3 +3038,116 @@ func TestIsUnderAgentLayerTmp(t *testing.T) {
 		}
 	}
 }
+
+func TestPermSnapshot_RoundTripsSpecialBits(t *testing.T) {
+	modes := []fs.FileMode{0o644, 0o755, 0o755 | os.ModeSetuid, 0o755 | os.ModeSetgid, 0o777 | os.ModeSticky}
+	for _, mode := range modes {
+		perm := permToSnapshot(mode)
+		if got := permFromSnapshot(perm, 0); got != mode {
+			t.Fatalf("round trip of %v = %v (perm %#o)", mode, got, *perm)
+		}
+	}
+	if perm := permToSnapshot(0o750 | os.ModeSetgid); *perm != 0o2750 {
+		t.Fatalf("setgid perm = %#o, want %#o", *perm, 0o2750)
+	}
+}
+
+func TestValidateUpgradeSnapshot_PermRangeBySchemaVersion(t *testing.T) {
+	perm := uint32(0o4755)
+	snapshot := upgradeSnapshot{
+		SchemaVersion: upgradeSnapshotSchemaVersion,
+		SnapshotID:    "snapshot-1",
+		CreatedAtUTC:  "2026-01-01T00:00:00Z",
+		Status:        upgradeSnapshotStatusApplied,
+		Entries: []upgradeSnapshotEntry{{
+			Path: ".agent-layer/skills/tool/run.sh",
+			Kind: upgradeSnapshotEntryKindFile,
+			Perm: &perm,
+		}},
+	}
+	if err := validateUpgradeSnapshot(snapshot); err != nil {
+		t.Fatalf("v2 snapshot with setuid perm: %v", err)
+	}
+	snapshot.SchemaVersion = upgradeSnapshotSchemaVersionV1
+	err := validateUpgradeSnapshot(snapshot)
+	if err == nil || !strings.Contains(err.Error(), "outside schema_version 1") {
+		t.Fatalf("expected v1 perm range error, got %v", err)
+	}
+}
+
+func TestCaptureAndRestoreUpgradeSnapshot_PreservesModesAndEmptyDirs(t *testing.T) {
+	root := t.TempDir()
+	skillDir := filepath.Join(root, ".agent-layer", "skills", "tool")
+	if err := os.MkdirAll(filepath.Join(skillDir, "empty"), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	script := filepath.Join(skillDir, "run.sh")
+	if err := os.WriteFile(script, []byte("#!/bin/sh\n\x00binary\n"), 0o755); err != nil {
+		t.Fatalf("write script: %v", err)
+	}
+	if err := os.Chmod(script, 0o755); err != nil {
+		t.Fatalf("chmod script: %v", err)
+	}
+
+	inst := &installer{root: root, sys: RealSystem{}}
+	entries := make(map[string]upgradeSnapshotEntry)
+	if err := inst.captureUpgradeSnapshotTarget(filepath.Join(root, ".agent-layer", "skills"), entries); err != nil {
+		t.Fatalf("capture: %v", err)
+	}
+	captured := make([]upgradeSnapshotEntry, 0, len(entries))
+	for _, entry := range entries {
+		captured = append(captured, entry)
+	}
+
+	restoreRoot := t.TempDir()
+	if err := restoreUpgradeSnapshotEntriesAtRoot(restoreRoot, RealSystem{}, captured); err != nil {
+		t.Fatalf("restore: %v", err)
+	}
+	restoredScript := filepath.Join(restoreRoot, ".agent-layer", "skills", "tool", "run.sh")
+	info, err := os.Stat(restoredScript)
+	if err != nil {
+		t.Fatalf("stat restored script: %v", err)
+	}
+	if info.Mode().Perm() != 0o755 {
+		t.Fatalf("restored script mode = %v, want 0755", info.Mode().Perm())
+	}
+	content, err := os.ReadFile(restoredScript)
+	if err != nil || string(content) != "#!/bin/sh\n\x00binary\n" {
+		t.Fatalf("restored script content = %q, %v", content, err)
+	}
+	if info, err := os.Stat(filepath.Join(restoreRoot, ".agent-layer", "skills", "tool", "empty")); err != nil || !info.IsDir() {
+		t.Fatalf("expected restored empty directory, got %v", err)
+	}
+}
+
+func TestReadUpgradeSnapshot_AcceptsSchemaVersion1(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "snapshot.json")
+	data := `{
+  "schema_version": 1,
+  "snapshot_id": "20260101T000000Z",
+  "created_at_utc": "2026-01-01T00:00:00Z",
+  "status": "applied",
+  "entries": [
+    {"path": ".agent-layer/skills/tool/run.sh", "kind": "file", "perm": 493, "content_base64": "IyEvYmluL3NoCg=="}
+  ]
+}
+`
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatalf("write snapshot: %v", err)
+	}
+	snapshot, err := readUpgradeSnapshot(path, RealSystem{})
+	if err != nil {
+		t.Fatalf("read v1 snapshot: %v", err)
+	}
+	if snapshot.SchemaVersion != upgradeSnapshotSchemaVersionV1 {
+		t.Fatalf("schema version = %d, want 1", snapshot.SchemaVersion)
+	}
+	root := t.TempDir()
+	if err := restoreUpgradeSnapshotEntriesAtRoot(root, RealSystem{}, snapshot.Entries); err != nil {
+		t.Fatalf("restore v1 snapshot: %v", err)
+	}
+	info, err := os.Stat(filepath.Join(root, ".agent-layer", "skills", "tool", "run.sh"))
+	if err != nil || info.Mode().Perm() != 0o755 {
+		t.Fatalf("restored v1 script = %v, %v", info, err)
+	}
+}
@@ -0,0 +1,159 @@
+// Package integrity checks that a repo's Agent Layer state is consistent for
+// `al verify`: the pin agrees with the managed baseline, managed files match
+// their release templates, generated client outputs match what sync renders
+// from the current sources, upgrade snapshots parse, and al.lock checksums
+// hold. It writes nothing, so CI can run it on every change.
+package integrity
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/conn-castle/agent-layer/internal/clean"
+	"github.com/conn-castle/agent-layer/internal/config"
+	"github.com/conn-castle/agent-layer/internal/i18n"
+	"github.com/conn-castle/agent-layer/internal/install"
+	"github.com/conn-castle/agent-layer/internal/lockfile"
+	"github.com/conn-castle/agent-layer/internal/messages"
+	"github.com/conn-castle/agent-layer/internal/outputdiff"
+	"github.com/conn-castle/agent-layer/internal/sync"
+)
+
+// Check kinds beyond those install and lockfile report.
+const (
+	KindGenerated = "generated"
+	KindLock      = "lock"
+)
+
+// Statuses a check can report; see install.IntegrityStatusOK and friends.
+const (
+	StatusOK       = install.IntegrityStatusOK
+	StatusModified = install.IntegrityStatusModified
+	StatusMissing  = install.IntegrityStatusMissing
+	StatusSkipped  = install.IntegrityStatusSkipped
+	StatusFail     = install.IntegrityStatusFail
+)
+
+const lockRelPath = ".agent-layer/al.lock"
+
+// ReportSchemaVersion identifies the JSON shape of Report.
+const ReportSchemaVersion = 1
+
+// Check is one integrity check in a Report.
+type Check struct {
+	Kind   string `json:"kind"`
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// Report is the machine-readable outcome of Verify.
+type Report struct {
+	SchemaVersion int `json:"schema_version"`
+	// OK is false when any check failed.
+	OK bool `json:"ok"`
+	// Counts maps each status to how many checks reported it.
+	Counts map[string]int `json:"counts"`
+	Checks []Check        `json:"checks"`
+}
+
+// Failed returns the number of failed checks.
+func (r Report) Failed() int {
+	return r.Counts[StatusFail]
+}
+
+var (
+	checkInstall  = install.CheckIntegrity
+	renderOutputs = outputdiff.Render
+	verifyLock    = lockfile.Verify
+)
+
+// Verify runs every integrity check against the repo at root. The returned
+// error is non-nil only when the checks cannot run; problems in the repo are
+// reported as failed checks.
+func Verify(root string) (Report, error) {
+	installChecks, err := checkInstall(root, install.RealSystem{})
+	if err != nil {
+		return Report{}, err
+	}
+	checks := make([]Check, 0, len(installChecks))
+	for _, check := range installChecks {
+		checks = append(checks, Check(check))
+	}
+	generated, err := checkGenerated(root)
+	if err != nil {
+		return Report{}, err
+	}
+	checks = append(checks, generated...)
+	checks = append(checks, checkLock(root)...)
+
+	report := Report{SchemaVersion: ReportSchemaVersion, Counts: make(map[string]int), Checks: checks}
+	for _, check := range checks {
+		report.Counts[check.Status]++
+	}
+	report.OK = report.Failed() == 0
+	return report, nil
+}
+
+// checkGenerated renders the current sources in a scratch copy and compares
+// every output outside .agent-layer/ with the repo. Files sync patches in
+// place hold the user's own settings, so a difference there is reported as
+// modified rather than failed.
+func checkGenerated(root string) ([]Check, error) {
+	outputs, err := renderOutputs(root, false)
+	if err != nil {
+		return []Check{{Kind: KindGenerated, Name: "render", Status: StatusFail, Detail: err.Error()}}, nil
+	}
+	var checks []Check
+	for rel, rendered := range outputs {
+		if rel == ".agent-layer" || strings.HasPrefix(rel, ".agent-layer/") {
+			continue
+		}
+		check := Check{Kind: KindGenerated, Name: rel, Status: StatusOK}
+		data, err := os.ReadFile(filepath.Join(root, filepath.FromSlash(rel))) // #nosec G304 -- rel is a sync output path under the repo root.
+		current := string(data)
+		switch {
+		case errors.Is(err, fs.ErrNotExist):
+			check.Status, check.Detail = StatusFail, messages.IntegrityGeneratedMissing
+		case err != nil:
+			return nil, i18n.Errorf(messages.IntegrityReadFmt, rel, err)
+		case current == rendered:
+		case clean.SharedStateOutput(rel):
+			check.Status, check.Detail = StatusModified, messages.IntegrityGeneratedShared
+		case sync.HasContentHash(current) && !sync.GeneratedContentUnchanged(current):
+			check.Status, check.Detail = StatusFail, messages.IntegrityGeneratedEdited
+		default:
+			check.Status, check.Detail = StatusFail, messages.IntegrityGeneratedStale
+		}
+		checks = append(checks, check)
+	}
+	sort.Slice(checks, func(i, j int) bool { return checks[i].Name < checks[j].Name })
+	return checks, nil
+}
+
+// checkLock verifies al.lock. The config is loaded leniently: only the
+// extends source matters here, and lock drift must still be reported when
+// unrelated config fields fail validation.
+func checkLock(root string) []Check {
+	cfg, err := config.LoadConfigLenient(config.DefaultPaths(root).ConfigPath)
+	if err != nil {
+		return []Check{{Kind: KindLock, Name: lockRelPath, Status: StatusFail, Detail: err.Error()}}
+	}
+	lockChecks, err := verifyLock(root, cfg.Extends)
+	if err != nil {
+		return []Check{{Kind: KindLock, Name: lockRelPath, Status: StatusFail, Detail: err.Error()}}
+	}
+	checks := make([]Check, 0, len(lockChecks))
+	for _, lockCheck := range lockChecks {
+		check := Check{Kind: lockCheck.Kind, Name: lockCheck.Name, Status: StatusOK}
+		if lockCheck.Err != nil {
+			check.Status, check.Detail = StatusFail, lockCheck.Err.Error()
+		}
+		checks = append(checks, check)
+	}
+	return checks
+}
@@ -0,0 +1,96 @@
+package integrity
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/conn-castle/agent-layer/internal/install"
+	"github.com/conn-castle/agent-layer/internal/lockfile"
+)
+
+func writeFile(t *testing.T, root string, rel string, content string) {
+	t.Helper()
+	full := filepath.Join(root, filepath.FromSlash(rel))
+	if err := os.MkdirAll(filepath.Dir(full), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(full, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func stubChecks(t *testing.T, installChecks []install.IntegrityCheck, outputs map[string]string, renderErr error, lockChecks []lockfile.Check) {
+	t.Helper()
+	originalInstall, originalRender, originalLock := checkInstall, renderOutputs, verifyLock
+	t.Cleanup(func() { checkInstall, renderOutputs, verifyLock = originalInstall, originalRender, originalLock })
+	checkInstall = func(string, install.System) ([]install.IntegrityCheck, error) { return installChecks, nil }
+	renderOutputs = func(string, bool) (map[string]string, error) { return outputs, renderErr }
+	verifyLock = func(string, string) ([]lockfile.Check, error) { return lockChecks, nil }
+}
+
+func TestVerify(t *testing.T) {
+	root := t.TempDir()
+	stubChecks(t,
+		[]install.IntegrityCheck{
+			{Kind: install.IntegrityKindPin, Name: "0.14.0", Status: StatusOK},
+			{Kind: install.IntegrityKindManaged, Name: ".agent-layer/instructions/00_rules.md", Status: StatusModified, Detail: "differs"},
+		},
+		map[string]string{
+			".mcp.json":                        "{}\n",
+			"CLAUDE.md":                        "claude\n",
+			"AGENTS.md":                        "agents\n",
+			".vscode/settings.json":            "{}\n",
+			".codex/config.toml":               "x\n",
+			".agent-layer/state/claude-x.json": "{}",
+		},
+		nil,
+		[]lockfile.Check{{Kind: lockfile.KindSkill, Name: "review", Err: errors.New("contents changed")}},
+	)
+	writeFile(t, root, ".agent-layer/config.toml", "")
+	writeFile(t, root, ".mcp.json", "{}\n")
+	writeFile(t, root, "CLAUDE.md", "edited\nContent-Hash: sha256:0000\n")
+	writeFile(t, root, "AGENTS.md", "older agents\n")
+	writeFile(t, root, ".vscode/settings.json", "{\"mine\":true}\n")
+
+	report, err := Verify(root)
+	if err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+	got := make(map[string]string)
+	for _, check := range report.Checks {
+		got[check.Kind+" "+check.Name] = check.Status
+	}
+	want := map[string]string{
+		"pin 0.14.0": StatusOK,
+		"managed .agent-layer/instructions/00_rules.md": StatusModified,
+		"generated .mcp.json":                           StatusOK,
+		"generated CLAUDE.md":                           StatusFail,
+		"generated AGENTS.md":                           StatusFail,
+		"generated .vscode/settings.json":               StatusModified,
+		"generated .codex/config.toml":                  StatusFail,
+		"skill review":                                  StatusFail,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("checks = %v, want %v", got, want)
+	}
+	if report.OK || report.Failed() != 4 || report.Counts[StatusModified] != 2 || report.SchemaVersion != ReportSchemaVersion {
+		t.Fatalf("unexpected report summary: %#v", report)
+	}
+}
+
+func TestVerify_RenderFailureIsAFailedCheck(t *testing.T) {
+	root := t.TempDir()
+	stubChecks(t, []install.IntegrityCheck{{Kind: install.IntegrityKindPin, Name: "0.14.0", Status: StatusOK}}, nil, errors.New("bad config"), nil)
+	writeFile(t, root, ".agent-layer/config.toml", "")
+
+	report, err := Verify(root)
+	if err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+	if report.OK || len(report.Checks) != 2 || report.Checks[1].Kind != KindGenerated || report.Checks[1].Detail != "bad config" {
+		t.Fatalf("unexpected report: %#v", report)
+	}
+}
@@ -1,6 +1,10 @@
 package launchers
 
-import "path/filepath"
+import (
+	"path/filepath"
+
+	"github.com/conn-castle/agent-layer/internal/layerdir"
+)
 
 // VSCodeLauncherPaths describes absolute paths for VS Code launcher artifacts under .agent-layer.
 type VSCodeLauncherPaths struct {
@@ -19,7 +23,12 @@ type VSCodeLauncherPaths struct {
 // Args: root is the repo root directory.
 // Returns: VSCodeLauncherPaths containing all launcher paths.
 func VSCodePaths(root string) VSCodeLauncherPaths {
-	agentLayerDir := filepath.Join(root, ".agent-layer")
+	return VSCodePathsIn(layerdir.Dir(root))
+}
+
+// VSCodePathsIn returns launcher paths under agentLayerDir. The installer uses
+// it to name launchers by their repo .agent-layer path when the directory is redirected.
+func VSCodePathsIn(agentLayerDir string) VSCodeLauncherPaths {
 	appDir := filepath.Join(agentLayerDir, "open-vscode.app")
 	appContents := filepath.Join(appDir, "Contents")
 	appMacOS := filepath.Join(appContents, "MacOS")
@@ -1,10 +1,10 @@
 package launchers
 
 import (
-	"fmt"
 	"os"
 
 	"github.com/conn-castle/agent-layer/internal/fsutil"
+	"github.com/conn-castle/agent-layer/internal/i18n"
 	"github.com/conn-castle/agent-layer/internal/messages"
 	"github.com/conn-castle/agent-layer/internal/templates"
 )
@@ -45,7 +45,7 @@ func (RealSystem) WriteFileAtomic(path string, data []byte, perm os.FileMode) er
 func WriteVSCodeLaunchers(sys System, root string) error {
 	paths := VSCodePaths(root)
 	if err := sys.MkdirAll(paths.AgentLayerDir, 0o755); err != nil {
-		return fmt.Errorf(messages.SyncCreateDirFailedFmt, paths.AgentLayerDir, err)
+		return i18n.Errorf(messages.SyncCreateDirFailedFmt, paths.AgentLayerDir, err)
 	}
 
 	if err := writeTemplateFile(sys, paths.Command, openVSCodeCommandTemplatePath, 0o755); err != nil {
@@ -77,7 +77,7 @@ func WriteVSCodeLaunchers(sys System, root string) error {
 // writeVSCodeAppBundle creates a macOS .app bundle that launches VS Code without opening Terminal.
 func writeVSCodeAppBundle(sys System, paths VSCodeLauncherPaths) error {
 	if err := sys.MkdirAll(paths.AppMacOS, 0o755); err != nil {
-		return fmt.Errorf(messages.SyncCreateDirFailedFmt, paths.AppMacOS, err)
+		return i18n.Errorf(messages.SyncCreateDirFailedFmt, paths.AppMacOS, err)
 	}
 
 	writes := []struct {
@@ -101,10 +101,10 @@ func writeVSCodeAppBundle(sys System, paths VSCodeLauncherPaths) error {
 func writeTemplateFile(sys System, destinationPath string, templatePath string, perm os.FileMode) error {
 	data, err := readTemplate(templatePath)
 	if err != nil {
-		return fmt.Errorf(messages.SyncReadTemplateFailedFmt, templatePath, err)
+		return i18n.Errorf(messages.SyncReadTemplateFailedFmt, templatePath, err)
 	}
 	if err := sys.WriteFileAtomic(destinationPath, data, perm); err != nil {
-		return fmt.Errorf(messages.SyncWriteFileFailedFmt, destinationPath, err)
+		return i18n.Errorf(messages.SyncWriteFileFailedFmt, destinationPath, err)
 	}
 	return nil
 }
@@ -0,0 +1,131 @@
+// Package layerdir locates a repo's .agent-layer directory. It is normally the
+// .agent-layer directory at the repo root. When .agent-layer is a regular file
+// instead, the file is a redirect: its first line that is not blank or a #
+// comment names the real directory, either absolute or relative to the repo
+// root. This lets the configuration live elsewhere, such as a separate private
+// repo.
+package layerdir
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/conn-castle/agent-layer/internal/i18n"
+	"github.com/conn-castle/agent-layer/internal/messages"
+)
+
+// Name is the name of the .agent-layer directory or redirect file at the repo root.
+const Name = ".agent-layer"
+
+// Resolve returns the .agent-layer directory for repoRoot and whether a
+// redirect file named it. A missing .agent-layer resolves to the default
+// location so callers can create it.
+func Resolve(repoRoot string) (string, bool, error) {
+	path := filepath.Join(repoRoot, Name)
+	info, err := os.Stat(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return path, false, nil
+		}
+		return "", false, i18n.Errorf(messages.RootCheckPathFmt, path, err)
+	}
+	if !info.Mode().IsRegular() {
+		return path, false, nil
+	}
+	data, err := os.ReadFile(path) // #nosec G304 -- path is the .agent-layer entry under the resolved repo root.
+	if err != nil {
+		return "", false, i18n.Errorf(messages.LayerDirRedirectReadFmt, path, err)
+	}
+	target := redirectTarget(data)
+	if target == "" {
+		return "", false, i18n.Errorf(messages.LayerDirRedirectEmptyFmt, path)
+	}
+	if !filepath.IsAbs(target) {
+		target = filepath.Join(repoRoot, target)
+	}
+	target = filepath.Clean(target)
+	targetInfo, err := os.Stat(target)
+	if err != nil || !targetInfo.IsDir() {
+		return "", false, i18n.Errorf(messages.LayerDirRedirectTargetFmt, path, target)
+	}
+	return target, true, nil
+}
+
+// Dir returns the .agent-layer directory for repoRoot, or the default location
+// when a redirect cannot be resolved. Root discovery reports redirect errors,
+// so callers that only build paths do not need to.
+func Dir(repoRoot string) string {
+	dir, _, err := Resolve(repoRoot)
+	if err != nil {
+		return filepath.Join(repoRoot, Name)
+	}
+	return dir
+}
+
+// InstallMarker returns the path whose presence means Agent Layer is already
+// installed in repoRoot, and whether that path is the .agent-layer directory
+// itself. A redirect target must exist before anything is installed into it,
+// so for a redirect the marker is the config.toml inside it.
+func InstallMarker(repoRoot string) (string, bool) {
+	dir, redirected, err := Resolve(repoRoot)
+	if err != nil || !redirected {
+		return filepath.Join(repoRoot, Name), true
+	}
+	return filepath.Join(dir, "config.toml"), false
+}
+
+// Path returns the absolute path for a repo-relative path, following the
+// redirect when rel is under .agent-layer/.
+func Path(repoRoot string, rel string) string {
+	rel = filepath.Clean(filepath.FromSlash(rel))
+	if rel == Name {
+		return Dir(repoRoot)
+	}
+	if inner, ok := strings.CutPrefix(rel, Name+string(os.PathSeparator)); ok {
+		return filepath.Join(Dir(repoRoot), inner)
+	}
+	return filepath.Join(repoRoot, rel)
+}
+
+// FS returns a filesystem rooted at repoRoot that serves .agent-layer/ from
+// the redirected directory, for loaders that read the repo through fs.FS.
+func FS(repoRoot string) fs.FS {
+	repo := os.DirFS(repoRoot)
+	dir, redirected, err := Resolve(repoRoot)
+	if err != nil || !redirected {
+		return repo
+	}
+	return redirectFS{repo: repo, layer: os.DirFS(dir)}
+}
+
+type redirectFS struct {
+	repo  fs.FS
+	layer fs.FS
+}
+
+func (f redirectFS) Open(name string) (fs.File, error) {
+	if name == Name {
+		return f.layer.Open(".")
+	}
+	if rest, ok := strings.CutPrefix(name, Name+"/"); ok {
+		return f.layer.Open(rest)
+	}
+	return f.repo.Open(name)
+}
+
+func redirectTarget(data []byte) string {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		return line
+	}
+	return ""
+}
@@ -0,0 +1,80 @@
+package layerdir
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestResolve_DefaultDirectory(t *testing.T) {
+	root := t.TempDir()
+	want := filepath.Join(root, Name)
+
+	dir, redirected, err := Resolve(root)
+	if err != nil || redirected || dir != want {
+		t.Fatalf("missing .agent-layer = %q, %v, %v", dir, redirected, err)
+	}
+	if err := os.Mkdir(want, 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	dir, redirected, err = Resolve(root)
+	if err != nil || redirected || dir != want {
+		t.Fatalf("directory .agent-layer = %q, %v, %v", dir, redirected, err)
+	}
+}
+
+func TestResolve_RedirectFile(t *testing.T) {
+	root := t.TempDir()
+	real := filepath.Join(root, "private", "agent-layer")
+	if err := os.MkdirAll(real, 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	redirect := "# configuration lives in the private repo\n\nprivate/agent-layer\n"
+	if err := os.WriteFile(filepath.Join(root, Name), []byte(redirect), 0o644); err != nil {
+		t.Fatalf("write redirect: %v", err)
+	}
+
+	dir, redirected, err := Resolve(root)
+	if err != nil || !redirected || dir != real {
+		t.Fatalf("relative redirect = %q, %v, %v", dir, redirected, err)
+	}
+	if got := Path(root, ".agent-layer/config.toml"); got != filepath.Join(real, "config.toml") {
+		t.Fatalf("Path under .agent-layer = %q", got)
+	}
+	if got := Path(root, "docs/agent-layer"); got != filepath.Join(root, "docs", "agent-layer") {
+		t.Fatalf("Path outside .agent-layer = %q", got)
+	}
+
+	if err := os.WriteFile(filepath.Join(root, Name), []byte(real+"\n"), 0o644); err != nil {
+		t.Fatalf("write redirect: %v", err)
+	}
+	if dir, _, err := Resolve(root); err != nil || dir != real {
+		t.Fatalf("absolute redirect = %q, %v", dir, err)
+	}
+}
+
+func TestResolve_InvalidRedirect(t *testing.T) {
+	root := t.TempDir()
+	path := filepath.Join(root, Name)
+	for _, tc := range []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{name: "empty", content: "# nothing here\n", want: "names no directory"},
+		{name: "missing target", content: "missing\n", want: "which is not a directory"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := os.WriteFile(path, []byte(tc.content), 0o644); err != nil {
+				t.Fatalf("write redirect: %v", err)
+			}
+			if _, _, err := Resolve(root); err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Fatalf("expected %q error, got %v", tc.want, err)
+			}
+			if got := Dir(root); got != path {
+				t.Fatalf("Dir must fall back to the default path, got %q", got)
+			}
+		})
+	}
+}
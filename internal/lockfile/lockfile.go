@@ -0,0 +1,176 @@
+// Package lockfile reads and writes .agent-layer/al.lock, which records the
+// resolved source and content checksum of everything Agent Layer fetches from
+// outside the repository.
+package lockfile
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/pelletier/go-toml/v2"
+
+	"github.com/conn-castle/agent-layer/internal/fsutil"
+	"github.com/conn-castle/agent-layer/internal/i18n"
+	"github.com/conn-castle/agent-layer/internal/layerdir"
+	"github.com/conn-castle/agent-layer/internal/messages"
+)
+
+// SchemaVersion is the current al.lock schema version.
+const SchemaVersion = 1
+
+const header = "# Managed by al. Commit this file so every machine syncs the same content.\n\n"
+
+// File is the parsed contents of al.lock.
+type File struct {
+	Version    int         `toml:"version"`
+	Extends    *Extends    `toml:"extends,omitempty"`
+	Skills     []Skill     `toml:"skills,omitempty"`
+	MCPServers []MCPServer `toml:"mcp_servers,omitempty"`
+}
+
+// Extends records the shared base bundle named by config.toml extends.
+type Extends struct {
+	// Source is the extends value the checksum was recorded for.
+	Source string `toml:"source"`
+	// Checksum is the sha256 content checksum of the resolved bundle.
+	Checksum string `toml:"checksum"`
+}
+
+// Skill records a skill fetched with `al add skill`.
+type Skill struct {
+	// Name is the skill directory name under .agent-layer/skills/.
+	Name string `toml:"name"`
+	// Source is the canonical host/owner/repo[/subdir]@ref reference.
+	Source string `toml:"source"`
+	// Checksum is the sha256 content checksum of the installed skill bundle.
+	Checksum string `toml:"checksum"`
+}
+
+// MCPServer records the version `al mcp prefetch` resolved and cached for an
+// npx- or uvx-launched MCP server.
+type MCPServer struct {
+	// ID is the [[mcp.servers]] id.
+	ID string `toml:"id"`
+	// Runner is the launcher, npx or uvx.
+	Runner string `toml:"runner"`
+	// Package is the npm or PyPI package name the server runs.
+	Package string `toml:"package"`
+	// Requested is the version spec written in config.toml ("" when none). A
+	// config edit that changes it makes the entry stale until the next
+	// prefetch.
+	Requested string `toml:"requested"`
+	// Version is the exact resolved version.
+	Version string `toml:"version"`
+}
+
+// FileName is the name of the lock file inside .agent-layer/.
+const FileName = "al.lock"
+
+// Path returns the al.lock path for a repo root.
+func Path(root string) string {
+	return filepath.Join(layerdir.Dir(root), FileName)
+}
+
+// Load reads al.lock under root. A missing file yields an empty lock.
+func Load(root string) (File, error) {
+	path := Path(root)
+	data, err := os.ReadFile(path) // #nosec G304 -- path is derived from the resolved repo root.
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return File{Version: SchemaVersion}, nil
+		}
+		return File{}, i18n.Errorf(messages.LockfileReadFailedFmt, path, err)
+	}
+	return Parse(data, path)
+}
+
+// Parse decodes al.lock contents; path is used for error messages.
+func Parse(data []byte, path string) (File, error) {
+	var lock File
+	if err := toml.Unmarshal(data, &lock); err != nil {
+		return File{}, i18n.Errorf(messages.LockfileInvalidFmt, path, err)
+	}
+	if lock.Version != SchemaVersion {
+		return File{}, i18n.Errorf(messages.LockfileUnsupportedVersionFmt, path, lock.Version, SchemaVersion)
+	}
+	return lock, nil
+}
+
+// Save writes lock to al.lock under root with entries sorted by name.
+func Save(root string, lock File) error {
+	data, err := Encode(lock)
+	if err != nil {
+		return i18n.Errorf(messages.LockfileWriteFailedFmt, Path(root), err)
+	}
+	if err := fsutil.WriteFileAtomic(Path(root), data, 0o644); err != nil {
+		return i18n.Errorf(messages.LockfileWriteFailedFmt, Path(root), err)
+	}
+	return nil
+}
+
+// Encode renders lock as al.lock contents, with skills sorted by name and MCP
+// servers sorted by id.
+func Encode(lock File) ([]byte, error) {
+	lock.Version = SchemaVersion
+	sort.Slice(lock.Skills, func(i, j int) bool { return lock.Skills[i].Name < lock.Skills[j].Name })
+	sort.Slice(lock.MCPServers, func(i, j int) bool { return lock.MCPServers[i].ID < lock.MCPServers[j].ID })
+	data, err := toml.Marshal(lock)
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(header), data...), nil
+}
+
+// ExtendsChecksum returns the locked checksum for source, or "" when the lock
+// has no entry for that exact source.
+func (f File) ExtendsChecksum(source string) string {
+	if f.Extends == nil || f.Extends.Source != source {
+		return ""
+	}
+	return f.Extends.Checksum
+}
+
+// Skill returns the locked skill named name.
+func (f File) Skill(name string) (Skill, bool) {
+	for _, skill := range f.Skills {
+		if skill.Name == name {
+			return skill, true
+		}
+	}
+	return Skill{}, false
+}
+
+// SetSkill adds skill or replaces the entry with the same name.
+func (f *File) SetSkill(skill Skill) {
+	for i := range f.Skills {
+		if f.Skills[i].Name == skill.Name {
+			f.Skills[i] = skill
+			return
+		}
+	}
+	f.Skills = append(f.Skills, skill)
+}
+
+// MCPServer returns the locked MCP server with id.
+func (f File) MCPServer(id string) (MCPServer, bool) {
+	for _, server := range f.MCPServers {
+		if server.ID == id {
+			return server, true
+		}
+	}
+	return MCPServer{}, false
+}
+
+// SetMCPServer adds server or replaces the entry with the same id.
+func (f *File) SetMCPServer(server MCPServer) {
+	for i := range f.MCPServers {
+		if f.MCPServers[i].ID == server.ID {
+			f.MCPServers[i] = server
+			return
+		}
+	}
+	f.MCPServers = append(f.MCPServers, server)
+}
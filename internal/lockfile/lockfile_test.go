@@ -0,0 +1,103 @@
+package lockfile
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoad_MissingFileIsEmpty(t *testing.T) {
+	lock, err := Load(t.TempDir())
+	if err != nil {
+		t.Fatalf("Load error: %v", err)
+	}
+	if lock.Version != SchemaVersion || len(lock.Skills) != 0 {
+		t.Fatalf("unexpected lock: %+v", lock)
+	}
+}
+
+func TestSaveAndLoad_RoundTripSorted(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, ".agent-layer"), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	var lock File
+	lock.SetSkill(Skill{Name: "review", Source: "github.com/org/skills/review@v1", Checksum: "sha256:a"})
+	lock.SetSkill(Skill{Name: "deploy", Source: "github.com/org/skills/deploy@v1", Checksum: "sha256:b"})
+	lock.SetSkill(Skill{Name: "review", Source: "github.com/org/skills/review@v2", Checksum: "sha256:c"})
+	if err := Save(root, lock); err != nil {
+		t.Fatalf("Save error: %v", err)
+	}
+
+	data, err := os.ReadFile(Path(root))
+	if err != nil {
+		t.Fatalf("read lock: %v", err)
+	}
+	if !strings.HasPrefix(string(data), "# Managed by al.") {
+		t.Fatalf("expected header, got:\n%s", data)
+	}
+
+	loaded, err := Load(root)
+	if err != nil {
+		t.Fatalf("Load error: %v", err)
+	}
+	if len(loaded.Skills) != 2 || loaded.Skills[0].Name != "deploy" {
+		t.Fatalf("skills = %+v", loaded.Skills)
+	}
+	review, ok := loaded.Skill("review")
+	if !ok || review.Source != "github.com/org/skills/review@v2" || review.Checksum != "sha256:c" {
+		t.Fatalf("review = %+v (%v)", review, ok)
+	}
+	if _, ok := loaded.Skill("missing"); ok {
+		t.Fatal("expected missing skill lookup to fail")
+	}
+}
+
+func TestSaveAndLoad_MCPServersSortedByID(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, ".agent-layer"), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	var lock File
+	lock.SetMCPServer(MCPServer{ID: "fetch", Runner: "uvx", Package: "mcp-server-fetch", Version: "2025.4.7"})
+	lock.SetMCPServer(MCPServer{ID: "context7", Runner: "npx", Package: "@upstash/context7-mcp", Requested: "^2", Version: "2.1.0"})
+	lock.SetMCPServer(MCPServer{ID: "context7", Runner: "npx", Package: "@upstash/context7-mcp", Requested: "^2", Version: "2.1.1"})
+	if err := Save(root, lock); err != nil {
+		t.Fatalf("Save error: %v", err)
+	}
+
+	loaded, err := Load(root)
+	if err != nil {
+		t.Fatalf("Load error: %v", err)
+	}
+	if len(loaded.MCPServers) != 2 || loaded.MCPServers[0].ID != "context7" {
+		t.Fatalf("mcp servers = %+v", loaded.MCPServers)
+	}
+	context7, ok := loaded.MCPServer("context7")
+	if !ok || context7.Version != "2.1.1" || context7.Requested != "^2" {
+		t.Fatalf("context7 = %+v (%v)", context7, ok)
+	}
+	if _, ok := loaded.MCPServer("missing"); ok {
+		t.Fatal("expected missing server lookup to fail")
+	}
+}
+
+func TestLoad_RejectsInvalidAndFutureVersions(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, ".agent-layer"), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(Path(root), []byte("version = ["), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(root); err == nil || !strings.Contains(err.Error(), "invalid lockfile") {
+		t.Fatalf("expected invalid lockfile error, got %v", err)
+	}
+	if err := os.WriteFile(Path(root), []byte("version = 99\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(root); err == nil || !strings.Contains(err.Error(), "unsupported lockfile version 99") {
+		t.Fatalf("expected version error, got %v", err)
+	}
+}
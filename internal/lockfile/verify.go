@@ -0,0 +1,91 @@
+package lockfile
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/conn-castle/agent-layer/internal/i18n"
+	"github.com/conn-castle/agent-layer/internal/layerdir"
+	"github.com/conn-castle/agent-layer/internal/messages"
+	"github.com/conn-castle/agent-layer/internal/remotebase"
+)
+
+// resolveExtendsFunc resolves an extends bundle against its locked checksum.
+// Tests replace it to avoid network access.
+var resolveExtendsFunc = func(source string, checksum string) (string, error) {
+	return remotebase.Resolve(remotebase.RealSystem{}, source, checksum)
+}
+
+// Entry kinds reported by Verify.
+const (
+	KindExtends = "extends"
+	KindSkill   = "skill"
+)
+
+// Check is the verification outcome for one al.lock entry.
+type Check struct {
+	Kind string
+	Name string
+	// Err is nil when the entry matches what is on disk.
+	Err error
+}
+
+// Verify checks that al.lock agrees with the repo. extends is the config.toml
+// extends value ("" when unset). The extends bundle must resolve to its locked
+// checksum, and every locked skill must be installed with unmodified contents.
+// The returned error is non-nil only when al.lock itself cannot be read.
+func Verify(root string, extends string) ([]Check, error) {
+	lock, err := Load(root)
+	if err != nil {
+		return nil, err
+	}
+	path := Path(root)
+	var checks []Check
+	if extends != "" || lock.Extends != nil {
+		checks = append(checks, verifyExtends(lock, path, extends))
+	}
+	for _, skill := range lock.Skills {
+		checks = append(checks, Check{Kind: KindSkill, Name: skill.Name, Err: verifySkill(root, skill)})
+	}
+	return checks, nil
+}
+
+func verifyExtends(lock File, path string, extends string) Check {
+	switch {
+	case extends == "":
+		return Check{Kind: KindExtends, Name: lock.Extends.Source, Err: i18n.Errorf(messages.LockfileVerifyExtendsUnusedFmt, path)}
+	case lock.Extends == nil:
+		return Check{Kind: KindExtends, Name: extends, Err: i18n.Errorf(messages.LockfileVerifyExtendsUnlockedFmt, path)}
+	case lock.Extends.Source != extends:
+		return Check{Kind: KindExtends, Name: extends, Err: i18n.Errorf(messages.LockfileVerifyExtendsSourceFmt, path, lock.Extends.Source)}
+	}
+	if err := remotebase.ValidateChecksum(lock.Extends.Checksum); err != nil || lock.Extends.Checksum == "" {
+		return Check{Kind: KindExtends, Name: extends, Err: i18n.Errorf(messages.LockfileVerifyChecksumInvalidFmt, lock.Extends.Checksum)}
+	}
+	_, err := resolveExtendsFunc(extends, lock.Extends.Checksum)
+	return Check{Kind: KindExtends, Name: extends, Err: err}
+}
+
+func verifySkill(root string, skill Skill) error {
+	dir := filepath.Join(layerdir.Dir(root), "skills", skill.Name)
+	info, err := os.Stat(dir)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return i18n.Errorf(messages.LockfileVerifySkillMissingFmt, dir, skill.Name)
+		}
+		return i18n.Errorf(messages.LockfileVerifyStatFmt, dir, err)
+	}
+	if !info.IsDir() {
+		return i18n.Errorf(messages.LockfileVerifySkillMissingFmt, dir, skill.Name)
+	}
+	actual, err := remotebase.Checksum(dir)
+	if err != nil {
+		return err
+	}
+	if actual != skill.Checksum {
+		return i18n.Errorf(messages.LockfileVerifySkillModifiedFmt, skill.Checksum, actual, skill.Name)
+	}
+	return nil
+}
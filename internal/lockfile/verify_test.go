@@ -0,0 +1,110 @@
+package lockfile
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/conn-castle/agent-layer/internal/remotebase"
+)
+
+func writeLockedSkill(t *testing.T, root string, name string, body string) string {
+	t.Helper()
+	dir := filepath.Join(root, ".agent-layer", "skills", name)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "SKILL.md"), []byte(body), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	checksum, err := remotebase.Checksum(dir)
+	if err != nil {
+		t.Fatalf("checksum: %v", err)
+	}
+	return checksum
+}
+
+func TestVerify_Skills(t *testing.T) {
+	root := t.TempDir()
+	okSum := writeLockedSkill(t, root, "review", "review")
+	editedSum := writeLockedSkill(t, root, "deploy", "deploy")
+	if err := os.WriteFile(filepath.Join(root, ".agent-layer", "skills", "deploy", "SKILL.md"), []byte("edited"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	var lock File
+	lock.SetSkill(Skill{Name: "review", Source: "github.com/org/review@v1", Checksum: okSum})
+	lock.SetSkill(Skill{Name: "deploy", Source: "github.com/org/deploy@v1", Checksum: editedSum})
+	lock.SetSkill(Skill{Name: "gone", Source: "github.com/org/gone@v1", Checksum: okSum})
+	if err := Save(root, lock); err != nil {
+		t.Fatalf("Save error: %v", err)
+	}
+
+	checks, err := Verify(root, "")
+	if err != nil {
+		t.Fatalf("Verify error: %v", err)
+	}
+	got := map[string]error{}
+	for _, check := range checks {
+		if check.Kind != KindSkill {
+			t.Fatalf("unexpected check %+v", check)
+		}
+		got[check.Name] = check.Err
+	}
+	if got["review"] != nil {
+		t.Fatalf("review: %v", got["review"])
+	}
+	if got["deploy"] == nil || !strings.Contains(got["deploy"].Error(), "al update --force deploy") {
+		t.Fatalf("deploy: %v", got["deploy"])
+	}
+	if got["gone"] == nil || !strings.Contains(got["gone"].Error(), "is missing") {
+		t.Fatalf("gone: %v", got["gone"])
+	}
+}
+
+func TestVerify_Extends(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, ".agent-layer"), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	checksum := "sha256:" + strings.Repeat("b", 64)
+	original := resolveExtendsFunc
+	var gotChecksum string
+	resolveExtendsFunc = func(_ string, sum string) (string, error) {
+		gotChecksum = sum
+		return t.TempDir(), nil
+	}
+	t.Cleanup(func() { resolveExtendsFunc = original })
+
+	checks, err := Verify(root, "github.com/org/base@v1")
+	if err != nil || len(checks) != 1 || checks[0].Err == nil || !strings.Contains(checks[0].Err.Error(), "not recorded") {
+		t.Fatalf("expected unlocked extends failure, got %+v (%v)", checks, err)
+	}
+
+	if err := Save(root, File{Extends: &Extends{Source: "github.com/org/base@v1", Checksum: checksum}}); err != nil {
+		t.Fatal(err)
+	}
+	checks, err = Verify(root, "github.com/org/base@v1")
+	if err != nil || len(checks) != 1 || checks[0].Err != nil || gotChecksum != checksum {
+		t.Fatalf("expected locked extends to verify, got %+v (%v), checksum %q", checks, err, gotChecksum)
+	}
+
+	resolveExtendsFunc = func(string, string) (string, error) { return "", errors.New("checksum mismatch") }
+	if checks, _ := Verify(root, "github.com/org/base@v1"); checks[0].Err == nil {
+		t.Fatal("expected resolve failure to fail verification")
+	}
+	if checks, _ := Verify(root, "github.com/org/base@v2"); checks[0].Err == nil || !strings.Contains(checks[0].Err.Error(), "was recorded for github.com/org/base@v1") {
+		t.Fatalf("expected source drift failure, got %+v", checks)
+	}
+	if checks, _ := Verify(root, ""); checks[0].Err == nil || !strings.Contains(checks[0].Err.Error(), "no longer sets extends") {
+		t.Fatalf("expected unused entry failure, got %+v", checks)
+	}
+}
+
+func TestVerify_EmptyLock(t *testing.T) {
+	checks, err := Verify(t.TempDir(), "")
+	if err != nil || len(checks) != 0 {
+		t.Fatalf("expected no checks, got %+v (%v)", checks, err)
+	}
+}
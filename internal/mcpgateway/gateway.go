@@ -0,0 +1,246 @@
+// Package mcpgateway serves every configured MCP server behind a single MCP
+// endpoint, so clients connect to one server managed by Agent Layer. The
+// gateway also serves the repo's instructions as MCP resources, its skills as
+// prompts, and tools that edit its memory files.
+package mcpgateway
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/conn-castle/agent-layer/internal/audit"
+	"github.com/conn-castle/agent-layer/internal/config"
+	"github.com/conn-castle/agent-layer/internal/i18n"
+	"github.com/conn-castle/agent-layer/internal/messages"
+	"github.com/conn-castle/agent-layer/internal/projection"
+	"github.com/conn-castle/agent-layer/internal/warnings"
+)
+
+// ToolSeparator joins a server id and a downstream tool name, so tool "search"
+// from server "github" is exposed as "github.search".
+const ToolSeparator = "."
+
+// connectTimeout bounds the handshake and tool listing for each downstream
+// server. It matches the per-server timeout used by `al doctor`.
+const connectTimeout = 30 * time.Second
+
+// newTransport builds downstream transports. Tests replace it with in-memory
+// transports.
+var newTransport = warnings.NewMCPTransport
+
+// appendAudit writes audit entries; tests replace it to capture them.
+var appendAudit = audit.Append
+
+// Options configures Serve.
+type Options struct {
+	// Version is reported to both the client and downstream servers.
+	Version string
+	// Warnings receives one line per downstream server or tool that is
+	// skipped. It must not be the writer behind the gateway transport.
+	Warnings io.Writer
+	// Project, when set, exposes its instructions as agent-layer://instructions/
+	// resources, its enabled skills as prompts, and its memory files through
+	// the memory_* tools.
+	Project *config.ProjectConfig
+	// AuditRoot, when set, records every forwarded tool call and every tool
+	// hidden by tools_allow/tools_deny in that repo's audit log.
+	AuditRoot string
+	// Client names the caller in audit entries. When empty, the name the
+	// client reports during the MCP handshake is used.
+	Client string
+}
+
+// Gateway is a started gateway: downstream servers are connected and their
+// tools registered on Server, ready to be served on any MCP transport.
+type Gateway struct {
+	// Server is the aggregated MCP server.
+	Server   *mcp.Server
+	sessions []*mcp.ClientSession
+}
+
+// Start connects to every server and registers their tools under namespaced
+// names. Downstream sessions stay bound to ctx; call Close when done. Servers
+// that fail to start are reported to opts.Warnings and left out; the gateway
+// still serves the rest.
+func Start(ctx context.Context, servers []projection.ResolvedMCPServer, opts Options) *Gateway {
+	impl := &mcp.Implementation{Name: "agent-layer-gateway", Version: opts.Version}
+	gateway := &Gateway{Server: mcp.NewServer(impl, nil)}
+	client := mcp.NewClient(impl, nil)
+	if opts.Project != nil {
+		addInstructionResources(gateway.Server, opts.Project)
+		addSkillPrompts(gateway.Server, opts.Project, opts.Warnings)
+		addMemoryTools(gateway.Server, opts.Project.Root)
+	}
+
+	recorder := auditRecorder{root: opts.AuditRoot, client: opts.Client, warnings: opts.Warnings}
+	for _, server := range servers {
+		session, tools, err := connect(ctx, client, server)
+		if err != nil {
+			warn(opts.Warnings, messages.McpGatewayServerSkippedFmt, server.ID, err)
+			continue
+		}
+		gateway.sessions = append(gateway.sessions, session)
+		for _, tool := range tools {
+			if !server.AllowsTool(tool.Name) {
+				recorder.record(toolDecision(server, tool.Name), "")
+				continue
+			}
+			if !isObjectSchema(tool.InputSchema) {
+				warn(opts.Warnings, messages.McpGatewayToolSkippedFmt, tool.Name, server.ID)
+				continue
+			}
+			gateway.Server.AddTool(namespacedTool(server.ID, tool), forwardTool(session, tool.Name, recorder, toolDecision(server, tool.Name)))
+		}
+	}
+	return gateway
+}
+
+// Close ends every downstream session.
+func (g *Gateway) Close() {
+	for _, session := range g.sessions {
+		_ = session.Close()
+	}
+}
+
+// Serve starts the gateway and serves it on transport until ctx is done or
+// the client disconnects.
+func Serve(ctx context.Context, servers []projection.ResolvedMCPServer, transport mcp.Transport, opts Options) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	gateway := Start(ctx, servers, opts)
+	defer gateway.Close()
+	return gateway.Server.Run(ctx, transport)
+}
+
+// connect starts one downstream server and lists its tools. The session stays
+// bound to ctx; connectTimeout only bounds startup, because SSE transports
+// tie the whole stream to the context passed to Connect.
+func connect(ctx context.Context, client *mcp.Client, server projection.ResolvedMCPServer) (*mcp.ClientSession, []*mcp.Tool, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	timer := time.AfterFunc(connectTimeout, cancel)
+
+	session, tools, err := connectAndList(ctx, client, server)
+	if !timer.Stop() {
+		if session != nil {
+			_ = session.Close()
+		}
+		return nil, nil, i18n.Errorf(messages.McpGatewayTimeoutFmt, connectTimeout)
+	}
+	if err != nil {
+		cancel()
+		return nil, nil, err
+	}
+	return session, tools, nil
+}
+
+func connectAndList(ctx context.Context, client *mcp.Client, server projection.ResolvedMCPServer) (*mcp.ClientSession, []*mcp.Tool, error) {
+	transport, err := newTransport(ctx, server)
+	if err != nil {
+		return nil, nil, err
+	}
+	session, err := client.Connect(ctx, transport, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	var tools []*mcp.Tool
+	for tool, err := range session.Tools(ctx, nil) {
+		if err != nil {
+			_ = session.Close()
+			return nil, nil, err
+		}
+		tools = append(tools, tool)
+	}
+	return session, tools, nil
+}
+
+// namespacedTool copies tool under its gateway name. Output schemas that are
+// not objects are dropped because the SDK only accepts object schemas.
+func namespacedTool(serverID string, tool *mcp.Tool) *mcp.Tool {
+	renamed := *tool
+	renamed.Name = serverID + ToolSeparator + tool.Name
+	if renamed.OutputSchema != nil && !isObjectSchema(renamed.OutputSchema) {
+		renamed.OutputSchema = nil
+	}
+	return &renamed
+}
+
+// forwardTool relays calls to the downstream tool unchanged, recording each
+// call as an allowed decision.
+func forwardTool(session *mcp.ClientSession, name string, recorder auditRecorder, decision audit.Entry) mcp.ToolHandler {
+	return func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		recorder.record(decision, handshakeClient(req.Session))
+		params := &mcp.CallToolParams{Meta: req.Params.Meta, Name: name}
+		if len(req.Params.Arguments) > 0 {
+			params.Arguments = req.Params.Arguments
+		}
+		return session.CallTool(ctx, params)
+	}
+}
+
+// auditRecorder appends gateway decisions to the audit log. Write failures
+// are reported as warnings and never block a tool call.
+type auditRecorder struct {
+	root     string
+	client   string
+	warnings io.Writer
+}
+
+func (r auditRecorder) record(entry audit.Entry, handshake string) {
+	if r.root == "" {
+		return
+	}
+	entry.Source = audit.SourceGateway
+	entry.Client = r.client
+	if entry.Client == "" {
+		entry.Client = handshake
+	}
+	if err := appendAudit(r.root, entry, time.Now()); err != nil {
+		warn(r.warnings, messages.McpGatewayAuditFailedFmt, err)
+	}
+}
+
+// toolDecision describes how server's tool filter treats the named tool.
+func toolDecision(server projection.ResolvedMCPServer, name string) audit.Entry {
+	entry := audit.Entry{Command: server.ID + ToolSeparator + name, Decision: audit.DecisionAllowed, Reason: messages.McpGatewayAuditReasonForwarded}
+	switch {
+	case slices.Contains(server.ToolsDeny, name):
+		entry.Decision, entry.Rule, entry.Reason = audit.DecisionDenied, messages.McpGatewayAuditRuleDeny, messages.McpGatewayAuditReasonDenied
+	case len(server.ToolsAllow) > 0 && !slices.Contains(server.ToolsAllow, name):
+		entry.Decision, entry.Reason = audit.DecisionDenied, messages.McpGatewayAuditReasonNotAllowed
+	case len(server.ToolsAllow) > 0:
+		entry.Rule = messages.McpGatewayAuditRuleAllow
+	}
+	return entry
+}
+
+// handshakeClient returns the client name reported during initialization.
+func handshakeClient(session *mcp.ServerSession) string {
+	if session == nil {
+		return ""
+	}
+	params := session.InitializeParams()
+	if params == nil || params.ClientInfo == nil {
+		return ""
+	}
+	return params.ClientInfo.Name
+}
+
+// isObjectSchema reports whether a schema decoded from a downstream server
+// describes a JSON object.
+func isObjectSchema(schema any) bool {
+	m, ok := schema.(map[string]any)
+	return ok && m["type"] == "object"
+}
+
+func warn(out io.Writer, format string, args ...any) {
+	if out == nil {
+		return
+	}
+	_, _ = fmt.Fprintf(out, format, args...)
+}
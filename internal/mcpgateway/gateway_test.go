@@ -0,0 +1,211 @@
+package mcpgateway
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/conn-castle/agent-layer/internal/audit"
+	"github.com/conn-castle/agent-layer/internal/projection"
+)
+
+type sayArgs struct {
+	Text string `json:"text"`
+}
+
+// stubDownstream serves an "echo" server with one "say" tool over an
+// in-memory transport and fails every other server.
+func stubDownstream(t *testing.T) {
+	t.Helper()
+	original := newTransport
+	newTransport = func(ctx context.Context, server projection.ResolvedMCPServer) (mcp.Transport, error) {
+		if server.ID != "echo" {
+			return nil, errors.New("command not found")
+		}
+		downstream := mcp.NewServer(&mcp.Implementation{Name: "echo", Version: "1.0.0"}, nil)
+		mcp.AddTool(downstream, &mcp.Tool{Name: "say", Description: "Echo text"}, func(ctx context.Context, req *mcp.CallToolRequest, args sayArgs) (*mcp.CallToolResult, any, error) {
+			return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: "echo: " + args.Text}}}, nil, nil
+		})
+		serverTransport, clientTransport := mcp.NewInMemoryTransports()
+		if _, err := downstream.Connect(ctx, serverTransport, nil); err != nil {
+			return nil, err
+		}
+		return clientTransport, nil
+	}
+	t.Cleanup(func() { newTransport = original })
+}
+
+func TestServe(t *testing.T) {
+	stubDownstream(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	gatewayTransport, clientTransport := mcp.NewInMemoryTransports()
+	var warnings bytes.Buffer
+	done := make(chan error, 1)
+	go func() {
+		done <- Serve(ctx, []projection.ResolvedMCPServer{{ID: "echo"}, {ID: "broken"}}, gatewayTransport, Options{Version: "test", Warnings: &warnings})
+	}()
+
+	client := mcp.NewClient(&mcp.Implementation{Name: "test-client", Version: "1.0.0"}, nil)
+	session, err := client.Connect(ctx, clientTransport, nil)
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	if info := session.InitializeResult().ServerInfo; info.Name != "agent-layer-gateway" || info.Version != "test" {
+		t.Fatalf("unexpected server info: %+v", info)
+	}
+
+	list, err := session.ListTools(ctx, nil)
+	if err != nil {
+		t.Fatalf("list tools: %v", err)
+	}
+	if len(list.Tools) != 1 || list.Tools[0].Name != "echo.say" || list.Tools[0].Description != "Echo text" {
+		t.Fatalf("unexpected tools: %+v", list.Tools)
+	}
+
+	result, err := session.CallTool(ctx, &mcp.CallToolParams{Name: "echo.say", Arguments: map[string]any{"text": "hi"}})
+	if err != nil {
+		t.Fatalf("call tool: %v", err)
+	}
+	if len(result.Content) != 1 || result.Content[0].(*mcp.TextContent).Text != "echo: hi" {
+		t.Fatalf("unexpected result: %+v", result.Content)
+	}
+
+	if !strings.Contains(warnings.String(), "skipping MCP server broken: command not found") {
+		t.Fatalf("expected skipped server warning, got %q", warnings.String())
+	}
+
+	_ = session.Close()
+	cancel()
+	<-done
+}
+
+func TestNamespacedTool(t *testing.T) {
+	tool := &mcp.Tool{
+		Name:         "search",
+		InputSchema:  map[string]any{"type": "object"},
+		OutputSchema: map[string]any{"type": "array"},
+	}
+	got := namespacedTool("github", tool)
+	if got.Name != "github.search" {
+		t.Fatalf("name = %q", got.Name)
+	}
+	if got.OutputSchema != nil {
+		t.Fatalf("expected non-object output schema to be dropped")
+	}
+	if tool.Name != "search" || tool.OutputSchema == nil {
+		t.Fatalf("original tool was modified: %+v", tool)
+	}
+}
+
+func TestIsObjectSchema(t *testing.T) {
+	if !isObjectSchema(map[string]any{"type": "object"}) {
+		t.Fatalf("expected object schema")
+	}
+	for _, schema := range []any{nil, map[string]any{"type": "string"}, "object"} {
+		if isObjectSchema(schema) {
+			t.Fatalf("expected %v not to be an object schema", schema)
+		}
+	}
+}
+
+func TestServe_ToolFilter(t *testing.T) {
+	stubDownstream(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	gatewayTransport, clientTransport := mcp.NewInMemoryTransports()
+	done := make(chan error, 1)
+	go func() {
+		done <- Serve(ctx, []projection.ResolvedMCPServer{{ID: "echo", ToolsDeny: []string{"say"}}}, gatewayTransport, Options{})
+	}()
+
+	client := mcp.NewClient(&mcp.Implementation{Name: "test-client", Version: "1.0.0"}, nil)
+	session, err := client.Connect(ctx, clientTransport, nil)
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	list, err := session.ListTools(ctx, nil)
+	if err != nil {
+		t.Fatalf("list tools: %v", err)
+	}
+	if len(list.Tools) != 0 {
+		t.Fatalf("expected denied tool to be hidden, got %+v", list.Tools)
+	}
+
+	_ = session.Close()
+	cancel()
+	<-done
+}
+
+func TestServe_AuditsToolDecisions(t *testing.T) {
+	stubDownstream(t)
+	original := appendAudit
+	var entries []audit.Entry
+	appendAudit = func(root string, entry audit.Entry, _ time.Time) error {
+		if root != "/repo" {
+			t.Errorf("unexpected audit root %q", root)
+		}
+		entries = append(entries, entry)
+		return nil
+	}
+	t.Cleanup(func() { appendAudit = original })
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	gatewayTransport, clientTransport := mcp.NewInMemoryTransports()
+	done := make(chan error, 1)
+	go func() {
+		done <- Serve(ctx, []projection.ResolvedMCPServer{{ID: "echo", ToolsAllow: []string{"say"}}, {ID: "echo", ToolsDeny: []string{"say"}}}, gatewayTransport, Options{AuditRoot: "/repo"})
+	}()
+
+	client := mcp.NewClient(&mcp.Implementation{Name: "test-client", Version: "1.0.0"}, nil)
+	session, err := client.Connect(ctx, clientTransport, nil)
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	if _, err := session.CallTool(ctx, &mcp.CallToolParams{Name: "echo.say", Arguments: map[string]any{"text": "hi"}}); err != nil {
+		t.Fatalf("call tool: %v", err)
+	}
+	_ = session.Close()
+	cancel()
+	<-done
+
+	if len(entries) != 2 {
+		t.Fatalf("expected two audit entries, got %#v", entries)
+	}
+	denied, allowed := entries[0], entries[1]
+	if denied.Decision != audit.DecisionDenied || denied.Rule != "tools_deny" || denied.Command != "echo.say" || denied.Source != audit.SourceGateway {
+		t.Fatalf("unexpected denial %#v", denied)
+	}
+	if allowed.Decision != audit.DecisionAllowed || allowed.Rule != "tools_allow" || allowed.Client != "test-client" {
+		t.Fatalf("unexpected allowed call %#v", allowed)
+	}
+}
+
+func TestToolDecision(t *testing.T) {
+	server := projection.ResolvedMCPServer{ID: "gh", ToolsAllow: []string{"search"}}
+	if entry := toolDecision(server, "delete"); entry.Decision != audit.DecisionDenied || entry.Rule != "" || entry.Reason != "not in tools_allow" {
+		t.Fatalf("unexpected decision %#v", entry)
+	}
+	if entry := toolDecision(projection.ResolvedMCPServer{ID: "gh"}, "search"); entry.Decision != audit.DecisionAllowed || entry.Rule != "" {
+		t.Fatalf("unexpected decision %#v", entry)
+	}
+}
+
+func TestAuditRecorder_WriteFailureWarns(t *testing.T) {
+	original := appendAudit
+	appendAudit = func(string, audit.Entry, time.Time) error { return errors.New("disk full") }
+	t.Cleanup(func() { appendAudit = original })
+	var warnings bytes.Buffer
+	auditRecorder{root: "/repo", client: "codex", warnings: &warnings}.record(audit.Entry{Command: "gh.search"}, "ignored")
+	if warnings.String() != "al mcp gateway: disk full\n" {
+		t.Fatalf("unexpected warning %q", warnings.String())
+	}
+}
@@ -0,0 +1,106 @@
+package mcpgateway
+
+import (
+	"context"
+	"net/url"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/conn-castle/agent-layer/internal/config"
+	"github.com/conn-castle/agent-layer/internal/i18n"
+	"github.com/conn-castle/agent-layer/internal/messages"
+	"github.com/conn-castle/agent-layer/internal/sync"
+)
+
+// Instruction resource URIs. Files keep their .md suffix, so no file name can
+// collide with "combined" or the scoped/ prefix.
+const (
+	instructionsScheme      = "agent-layer"
+	instructionsHost        = "instructions"
+	combinedInstructionName = "combined"
+	scopedInstructionPrefix = "scoped/"
+	instructionsMIMEType    = "text/markdown"
+)
+
+// loadProject reloads the project on every resource read so agents see edits
+// made during the session. Tests replace it.
+var loadProject = config.LoadProjectConfig
+
+// InstructionURI returns the resource URI for an instruction document name:
+// "combined", an instruction file name such as "00_rules.md", or
+// "scoped/<dir>".
+func InstructionURI(name string) string {
+	return (&url.URL{Scheme: instructionsScheme, Host: instructionsHost, Path: "/" + name}).String()
+}
+
+// addInstructionResources exposes the composed instructions, each instruction
+// file, and each scoped directory as read-only resources. The resource list is
+// fixed at startup; contents are recomposed on every read.
+func addInstructionResources(server *mcp.Server, project *config.ProjectConfig) {
+	root := project.Root
+	server.AddResource(&mcp.Resource{
+		URI:         InstructionURI(combinedInstructionName),
+		Name:        combinedInstructionName,
+		Title:       messages.McpGatewayCombinedInstructionsTitle,
+		Description: messages.McpGatewayCombinedInstructionsDescription,
+		MIMEType:    instructionsMIMEType,
+	}, readInstructions(root, func(project *config.ProjectConfig) (string, bool) {
+		return sync.InstructionDocument(project.Instructions, project.ScopedInstructions), true
+	}))
+	for _, file := range project.Instructions {
+		name := file.Name
+		server.AddResource(&mcp.Resource{
+			URI:         InstructionURI(name),
+			Name:        name,
+			Description: i18n.Sprintf(messages.McpGatewayInstructionFileDescriptionFmt, name),
+			MIMEType:    instructionsMIMEType,
+		}, readInstructions(root, func(project *config.ProjectConfig) (string, bool) {
+			for _, file := range project.Instructions {
+				if file.Name == name {
+					return file.Content, true
+				}
+			}
+			return "", false
+		}))
+	}
+	for _, scoped := range project.ScopedInstructions {
+		dir := scoped.Dir
+		if dir == "" {
+			continue
+		}
+		server.AddResource(&mcp.Resource{
+			URI:         InstructionURI(scopedInstructionPrefix + dir),
+			Name:        scopedInstructionPrefix + dir,
+			Description: i18n.Sprintf(messages.McpGatewayScopedInstructionsDescriptionFmt, dir),
+			MIMEType:    instructionsMIMEType,
+		}, readInstructions(root, func(project *config.ProjectConfig) (string, bool) {
+			for _, scoped := range project.ScopedInstructions {
+				if scoped.Dir == dir {
+					return sync.ScopedInstructionDocument(scoped), true
+				}
+			}
+			return "", false
+		}))
+	}
+}
+
+// readInstructions builds a resource handler that reloads the project and
+// extracts one document. A document removed since startup reads as not found.
+func readInstructions(root string, document func(*config.ProjectConfig) (string, bool)) mcp.ResourceHandler {
+	return func(ctx context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+		uri := req.Params.URI
+		project, err := loadProject(root)
+		if err != nil {
+			return nil, err
+		}
+		text, ok := document(project)
+		if !ok {
+			return nil, mcp.ResourceNotFoundError(uri)
+		}
+		return &mcp.ReadResourceResult{Contents: []*mcp.ResourceContents{{
+			URI:      uri,
+			MIMEType: instructionsMIMEType,
+			Text:     text,
+		}}}, nil
+	}
+}
@@ -0,0 +1,101 @@
+package mcpgateway
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/conn-castle/agent-layer/internal/config"
+)
+
+func TestInstructionURI(t *testing.T) {
+	cases := map[string]string{
+		"combined":      "agent-layer://instructions/combined",
+		"00_rules.md":   "agent-layer://instructions/00_rules.md",
+		"scoped/api/v1": "agent-layer://instructions/scoped/api/v1",
+	}
+	for name, want := range cases {
+		if got := InstructionURI(name); got != want {
+			t.Fatalf("InstructionURI(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestServe_InstructionResources(t *testing.T) {
+	project := &config.ProjectConfig{
+		Root:         "/repo",
+		Instructions: []config.InstructionFile{{Name: "00_rules.md", Content: "# Rules\n"}},
+		ScopedInstructions: []config.ScopedInstructions{{
+			Dir:   "api",
+			Files: []config.InstructionFile{{Name: "00_api.md", Content: "# API\n"}},
+		}},
+	}
+	current := project
+	original := loadProject
+	loadProject = func(root string) (*config.ProjectConfig, error) {
+		if root != "/repo" {
+			t.Fatalf("unexpected root %q", root)
+		}
+		return current, nil
+	}
+	t.Cleanup(func() { loadProject = original })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	gatewayTransport, clientTransport := mcp.NewInMemoryTransports()
+	go func() {
+		_ = Serve(ctx, nil, gatewayTransport, Options{Version: "test", Project: project})
+	}()
+	session, err := mcp.NewClient(&mcp.Implementation{Name: "test-client", Version: "1.0.0"}, nil).Connect(ctx, clientTransport, nil)
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+
+	list, err := session.ListResources(ctx, nil)
+	if err != nil {
+		t.Fatalf("list resources: %v", err)
+	}
+	uris := make([]string, 0, len(list.Resources))
+	for _, resource := range list.Resources {
+		uris = append(uris, resource.URI)
+	}
+	want := []string{
+		"agent-layer://instructions/00_rules.md",
+		"agent-layer://instructions/combined",
+		"agent-layer://instructions/scoped/api",
+	}
+	if strings.Join(uris, ",") != strings.Join(want, ",") {
+		t.Fatalf("unexpected resources: %v", uris)
+	}
+
+	read := func(uri string) (string, error) {
+		result, err := session.ReadResource(ctx, &mcp.ReadResourceParams{URI: uri})
+		if err != nil {
+			return "", err
+		}
+		return result.Contents[0].Text, nil
+	}
+	combined, err := read("agent-layer://instructions/combined")
+	if err != nil || !strings.Contains(combined, "# Rules") {
+		t.Fatalf("combined = %q, %v", combined, err)
+	}
+	scoped, err := read("agent-layer://instructions/scoped/api")
+	if err != nil || !strings.Contains(scoped, "# API") {
+		t.Fatalf("scoped = %q, %v", scoped, err)
+	}
+
+	// Reads reflect edits made after startup.
+	current = &config.ProjectConfig{
+		Root:         "/repo",
+		Instructions: []config.InstructionFile{{Name: "00_rules.md", Content: "# Updated\n"}},
+	}
+	file, err := read("agent-layer://instructions/00_rules.md")
+	if err != nil || file != "# Updated\n" {
+		t.Fatalf("file = %q, %v", file, err)
+	}
+	if _, err := read("agent-layer://instructions/scoped/api"); err == nil {
+		t.Fatalf("expected not found for removed scoped directory")
+	}
+}
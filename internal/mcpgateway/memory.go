@@ -0,0 +1,99 @@
+package mcpgateway
+
+import (
+	"context"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/conn-castle/agent-layer/internal/memory"
+	"github.com/conn-castle/agent-layer/internal/messages"
+)
+
+// Memory tool names. They carry no server prefix, so they cannot collide with
+// a namespaced downstream tool.
+const (
+	MemoryListTool           = "memory_list"
+	MemoryAddIssueTool       = "memory_add_issue"
+	MemoryRecordDecisionTool = "memory_record_decision"
+)
+
+// memoryListInput is the memory_list argument object.
+type memoryListInput struct {
+	Type string `json:"type,omitempty" jsonschema:"issue, backlog, or decision; omit to list every type"`
+}
+
+// memoryListOutput wraps the entries because tool output must be an object.
+type memoryListOutput struct {
+	Entries []memory.Entry `json:"entries"`
+}
+
+// memoryAddIssueInput is the memory_add_issue argument object.
+type memoryAddIssueInput struct {
+	Title       string `json:"title" jsonschema:"short issue title"`
+	Priority    string `json:"priority,omitempty" jsonschema:"Critical, High, Medium, or Low (default Medium)"`
+	Area        string `json:"area,omitempty" jsonschema:"area of the codebase the issue affects"`
+	Description string `json:"description" jsonschema:"observed problem or risk"`
+	NextStep    string `json:"next_step,omitempty" jsonschema:"smallest concrete next action"`
+	Notes       string `json:"notes,omitempty" jsonschema:"optional dependencies or constraints"`
+}
+
+// memoryRecordDecisionInput is the memory_record_decision argument object.
+type memoryRecordDecisionInput struct {
+	Title     string `json:"title" jsonschema:"short decision title"`
+	Decision  string `json:"decision" jsonschema:"what was chosen"`
+	Reason    string `json:"reason,omitempty" jsonschema:"why it was chosen"`
+	Tradeoffs string `json:"tradeoffs,omitempty" jsonschema:"what is gained and what is lost"`
+}
+
+// addMemoryTools exposes the repo's memory files as tools that make the same
+// validated edits as `al memory`, so agents never rewrite the markdown by
+// hand.
+func addMemoryTools(server *mcp.Server, root string) {
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        MemoryListTool,
+		Description: messages.McpGatewayMemoryListDescription,
+	}, func(ctx context.Context, req *mcp.CallToolRequest, in memoryListInput) (*mcp.CallToolResult, memoryListOutput, error) {
+		entries, err := memory.List(root, in.Type)
+		if err != nil {
+			return nil, memoryListOutput{}, err
+		}
+		if entries == nil {
+			entries = []memory.Entry{}
+		}
+		return nil, memoryListOutput{Entries: entries}, nil
+	})
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        MemoryAddIssueTool,
+		Description: messages.McpGatewayMemoryAddIssueDescription,
+	}, func(ctx context.Context, req *mcp.CallToolRequest, in memoryAddIssueInput) (*mcp.CallToolResult, memory.Entry, error) {
+		entry, err := memory.Add(memory.AddOptions{
+			Root:     root,
+			Type:     memory.TypeIssue,
+			Title:    in.Title,
+			Priority: in.Priority,
+			Area:     in.Area,
+			Values: map[string]string{
+				memory.FieldDescription: in.Description,
+				memory.FieldNextStep:    in.NextStep,
+				memory.FieldNotes:       in.Notes,
+			},
+		})
+		return nil, entry, err
+	})
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        MemoryRecordDecisionTool,
+		Description: messages.McpGatewayMemoryRecordDecisionDescription,
+	}, func(ctx context.Context, req *mcp.CallToolRequest, in memoryRecordDecisionInput) (*mcp.CallToolResult, memory.Entry, error) {
+		entry, err := memory.Add(memory.AddOptions{
+			Root:  root,
+			Type:  memory.TypeDecision,
+			Title: in.Title,
+			Values: map[string]string{
+				memory.FieldDecision:  in.Decision,
+				memory.FieldReason:    in.Reason,
+				memory.FieldTradeoffs: in.Tradeoffs,
+			},
+		})
+		return nil, entry, err
+	})
+}
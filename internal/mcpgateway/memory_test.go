@@ -0,0 +1,75 @@
+package mcpgateway
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/conn-castle/agent-layer/internal/config"
+	"github.com/conn-castle/agent-layer/internal/memory"
+)
+
+func TestServe_MemoryTools(t *testing.T) {
+	root := t.TempDir()
+	for _, name := range []string{"ISSUES.md", "DECISIONS.md"} {
+		path := filepath.Join(memory.Dir(root), name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("# Memory\n\n"+memory.EntriesMarker+"\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	gatewayTransport, clientTransport := mcp.NewInMemoryTransports()
+	go func() {
+		_ = Serve(ctx, nil, gatewayTransport, Options{Version: "test", Project: &config.ProjectConfig{Root: root}})
+	}()
+	session, err := mcp.NewClient(&mcp.Implementation{Name: "test-client", Version: "1.0.0"}, nil).Connect(ctx, clientTransport, nil)
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	call := func(name string, args map[string]any) *mcp.CallToolResult {
+		t.Helper()
+		result, err := session.CallTool(ctx, &mcp.CallToolParams{Name: name, Arguments: args})
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		return result
+	}
+
+	added := call(MemoryAddIssueTool, map[string]any{"title": "Flaky test", "priority": "high", "description": "Fails under load."})
+	if added.IsError {
+		t.Fatalf("add issue failed: %+v", added.Content)
+	}
+	if result := call(MemoryRecordDecisionTool, map[string]any{"title": "Use TOML", "decision": "TOML config.", "reason": "Comments."}); result.IsError {
+		t.Fatalf("record decision failed: %+v", result.Content)
+	}
+	if result := call(MemoryAddIssueTool, map[string]any{"title": "No description"}); !result.IsError {
+		t.Fatal("an issue without a description must be rejected")
+	}
+
+	data, err := os.ReadFile(filepath.Join(memory.Dir(root), "ISSUES.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), " flaky-test: Flaky test\n    Priority: High.\n    Description: Fails under load.\n") {
+		t.Fatalf("unexpected ISSUES.md:\n%s", data)
+	}
+
+	listed := call(MemoryListTool, map[string]any{"type": "decision"})
+	output, ok := listed.StructuredContent.(map[string]any)
+	if listed.IsError || !ok {
+		t.Fatalf("list failed: %+v", listed)
+	}
+	entries, _ := output["entries"].([]any)
+	if len(entries) != 1 || entries[0].(map[string]any)["id"] != "use-toml" {
+		t.Fatalf("unexpected entries %+v", output)
+	}
+}
@@ -0,0 +1,148 @@
+package mcpgateway
+
+import (
+	"context"
+	"io"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/conn-castle/agent-layer/internal/config"
+	"github.com/conn-castle/agent-layer/internal/i18n"
+	"github.com/conn-castle/agent-layer/internal/messages"
+	"github.com/conn-castle/agent-layer/internal/sync"
+)
+
+// skillManifestNames are the skill files whose content is the prompt body, so
+// they are not bundled again as resources.
+var skillManifestNames = []string{"SKILL.md", "skill.md"}
+
+// addSkillPrompts exposes each skill enabled for the repo as a prompt named
+// after it. Prompts are listed with every skill after the skills it requires.
+// Getting a prompt reloads the project and returns those required skills
+// first, then the skill itself, each followed by the files in its skill
+// directory. The prompt list is fixed at startup; when the enabled_when
+// conditions cannot be evaluated, no skill prompts are served.
+func addSkillPrompts(server *mcp.Server, project *config.ProjectConfig, warnings io.Writer) {
+	enabled, err := sync.EnabledSkills(project.Root, project)
+	if err != nil {
+		warn(warnings, messages.McpGatewaySkillPromptsSkippedFmt, err)
+		return
+	}
+	skills := config.OrderSkills(enabled)
+	if len(skills) == 0 {
+		return
+	}
+	order := make(map[string]int, len(skills))
+	for i, skill := range skills {
+		order[skill.Name] = i
+		server.AddPrompt(&mcp.Prompt{
+			Name:        skill.Name,
+			Description: skill.Description,
+		}, getSkillPrompt(project.Root, skill.Name))
+	}
+	server.AddReceivingMiddleware(orderPromptList(order))
+}
+
+// orderPromptList lists prompts in order instead of the SDK's name order.
+// Prompts missing from order keep their name order after the rest.
+func orderPromptList(order map[string]int) mcp.Middleware {
+	rank := func(name string) int {
+		if i, ok := order[name]; ok {
+			return i
+		}
+		return len(order)
+	}
+	return func(next mcp.MethodHandler) mcp.MethodHandler {
+		return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+			result, err := next(ctx, method, req)
+			if list, ok := result.(*mcp.ListPromptsResult); ok && err == nil {
+				slices.SortStableFunc(list.Prompts, func(a, b *mcp.Prompt) int {
+					return rank(a.Name) - rank(b.Name)
+				})
+			}
+			return result, err
+		}
+	}
+}
+
+// getSkillPrompt builds the handler for skill name's prompt.
+func getSkillPrompt(root string, name string) mcp.PromptHandler {
+	return func(ctx context.Context, req *mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+		project, err := loadProject(root)
+		if err != nil {
+			return nil, err
+		}
+		skills, err := sync.EnabledSkills(root, project)
+		if err != nil {
+			return nil, err
+		}
+		index := slices.IndexFunc(skills, func(skill config.Skill) bool { return skill.Name == name })
+		if index < 0 {
+			return nil, i18n.Errorf(messages.McpGatewaySkillNotFoundFmt, name)
+		}
+		skill := skills[index]
+		result := &mcp.GetPromptResult{Description: skill.Description}
+		for _, required := range config.SkillRequirements(skills, name) {
+			text := i18n.Sprintf(messages.McpGatewaySkillRequiredPromptFmt, required.Name, name, required.Body)
+			if err := appendSkillMessages(result, required, text); err != nil {
+				return nil, err
+			}
+		}
+		if err := appendSkillMessages(result, skill, skill.Body); err != nil {
+			return nil, err
+		}
+		return result, nil
+	}
+}
+
+// appendSkillMessages adds text and then each file in skill's directory as
+// an embedded resource. Hidden files and symlinks are skipped, as sync skips
+// them when it copies skill directories.
+func appendSkillMessages(result *mcp.GetPromptResult, skill config.Skill, text string) error {
+	result.Messages = append(result.Messages, &mcp.PromptMessage{Role: "user", Content: &mcp.TextContent{Text: text}})
+	if skill.SourceDir == "" {
+		return nil
+	}
+	return filepath.WalkDir(skill.SourceDir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) && path == skill.SourceDir {
+				return filepath.SkipDir
+			}
+			return err
+		}
+		if path == skill.SourceDir {
+			return nil
+		}
+		if strings.HasPrefix(entry.Name(), ".") {
+			if entry.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if entry.IsDir() || entry.Type()&fs.ModeSymlink != 0 {
+			return nil
+		}
+		if filepath.Dir(path) == skill.SourceDir && slices.Contains(skillManifestNames, entry.Name()) {
+			return nil
+		}
+		data, err := os.ReadFile(path) // #nosec G304 -- path is inside a configured skill directory.
+		if err != nil {
+			return err
+		}
+		contents := &mcp.ResourceContents{URI: (&url.URL{Scheme: "file", Path: filepath.ToSlash(path)}).String()}
+		if utf8.Valid(data) {
+			contents.Text = string(data)
+		} else {
+			contents.Blob = data
+		}
+		result.Messages = append(result.Messages, &mcp.PromptMessage{Role: "user", Content: &mcp.EmbeddedResource{Resource: contents}})
+		return nil
+	})
+}
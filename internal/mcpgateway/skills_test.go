@@ -0,0 +1,97 @@
+package mcpgateway
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/conn-castle/agent-layer/internal/config"
+)
+
+func TestServe_SkillPrompts(t *testing.T) {
+	dir := t.TempDir()
+	buildDir := filepath.Join(dir, "build")
+	if err := os.MkdirAll(filepath.Join(buildDir, "scripts"), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	for name, content := range map[string]string{
+		"SKILL.md":          "---\ndescription: Build\n---\nRun make.\n",
+		"scripts/build.sh":  "make\n",
+		".hidden":           "secret\n",
+		"scripts/.cache.sh": "cached\n",
+	} {
+		if err := os.WriteFile(filepath.Join(buildDir, filepath.FromSlash(name)), []byte(content), 0o600); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+	}
+	project := &config.ProjectConfig{
+		Root: dir,
+		Skills: []config.Skill{
+			{Name: "audit", Description: "Audit"},
+			{Name: "build", Description: "Build", Body: "Run make.", SourceDir: buildDir, SourcePath: filepath.Join(buildDir, "SKILL.md")},
+			{Name: "deploy", Description: "Deploy", Body: "Ship it.", Requires: []string{"test"}},
+			{Name: "npm", Description: "npm", EnabledWhen: `file_exists("package.json")`},
+			{Name: "test", Description: "Test", Body: "Run tests.", Requires: []string{"build"}},
+		},
+	}
+	original := loadProject
+	loadProject = func(string) (*config.ProjectConfig, error) { return project, nil }
+	t.Cleanup(func() { loadProject = original })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	gatewayTransport, clientTransport := mcp.NewInMemoryTransports()
+	go func() {
+		_ = Serve(ctx, nil, gatewayTransport, Options{Version: "test", Project: project})
+	}()
+	session, err := mcp.NewClient(&mcp.Implementation{Name: "test-client", Version: "1.0.0"}, nil).Connect(ctx, clientTransport, nil)
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+
+	list, err := session.ListPrompts(ctx, nil)
+	if err != nil {
+		t.Fatalf("list prompts: %v", err)
+	}
+	names := make([]string, 0, len(list.Prompts))
+	for _, prompt := range list.Prompts {
+		names = append(names, prompt.Name)
+	}
+	if got := strings.Join(names, ","); got != "audit,build,test,deploy" {
+		t.Fatalf("prompt order = %s", got)
+	}
+
+	result, err := session.GetPrompt(ctx, &mcp.GetPromptParams{Name: "deploy"})
+	if err != nil {
+		t.Fatalf("get prompt: %v", err)
+	}
+	var parts []string
+	for _, message := range result.Messages {
+		switch content := message.Content.(type) {
+		case *mcp.TextContent:
+			parts = append(parts, content.Text)
+		case *mcp.EmbeddedResource:
+			parts = append(parts, filepath.Base(content.Resource.URI)+"="+content.Resource.Text)
+		default:
+			t.Fatalf("unexpected content %T", content)
+		}
+	}
+	want := []string{
+		"Skill build, required by deploy:\n\nRun make.",
+		"build.sh=make\n",
+		"Skill test, required by deploy:\n\nRun tests.",
+		"Ship it.",
+	}
+	if strings.Join(parts, "|") != strings.Join(want, "|") {
+		t.Fatalf("prompt messages = %q", parts)
+	}
+
+	project = &config.ProjectConfig{Root: "/repo"}
+	if _, err := session.GetPrompt(ctx, &mcp.GetPromptParams{Name: "deploy"}); err == nil || !strings.Contains(err.Error(), "no longer configured") {
+		t.Fatalf("expected removed-skill error, got %v", err)
+	}
+}
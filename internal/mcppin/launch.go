@@ -0,0 +1,291 @@
+// Package mcppin resolves npx- and uvx-launched MCP servers to exact package
+// versions, warms the runner caches for them, records the result in al.lock,
+// and rewrites server launches to the pinned, cache-preferring invocation.
+package mcppin
+
+import (
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/conn-castle/agent-layer/internal/config"
+)
+
+// Runners whose launches can be pinned.
+const (
+	RunnerNPX = "npx"
+	RunnerUVX = "uvx"
+)
+
+// Launch is the package an npx or uvx server command runs.
+type Launch struct {
+	Runner string
+	// Package is the npm or PyPI package name.
+	Package string
+	// Extras is the PyPI extras suffix including brackets (for example
+	// "[cli]"); always empty for npx.
+	Extras string
+	// Requested is the version spec written after the package name, without a
+	// leading "@": an exact version, an npm range or dist-tag, a PEP 440
+	// specifier such as ">=1.2", or "" when none is given.
+	Requested string
+
+	// arg is the index in args of the token that holds the package spec, and
+	// prefix is the flag text before the spec when the token is --flag=spec.
+	arg    int
+	prefix string
+}
+
+// npxValueFlags are npx options that consume the next argument.
+var npxValueFlags = map[string]bool{
+	"--cache":      true,
+	"--registry":   true,
+	"--userconfig": true,
+	"--prefix":     true,
+	"-w":           true,
+	"--workspace":  true,
+}
+
+// uvxValueFlags are uvx options that consume the next argument.
+var uvxValueFlags = map[string]bool{
+	"--with":                true,
+	"--with-editable":       true,
+	"--with-requirements":   true,
+	"-p":                    true,
+	"--python":              true,
+	"--index":               true,
+	"--default-index":       true,
+	"-i":                    true,
+	"--index-url":           true,
+	"--extra-index-url":     true,
+	"-f":                    true,
+	"--find-links":          true,
+	"-c":                    true,
+	"--constraints":         true,
+	"--overrides":           true,
+	"--build-constraints":   true,
+	"--cache-dir":           true,
+	"--directory":           true,
+	"--project":             true,
+	"--config-file":         true,
+	"--index-strategy":      true,
+	"--keyring-provider":    true,
+	"--resolution":          true,
+	"--prerelease":          true,
+	"--exclude-newer":       true,
+	"--python-preference":   true,
+	"--color":               true,
+	"--allow-insecure-host": true,
+	"--env-file":            true,
+}
+
+var (
+	exactNPMVersion  = regexp.MustCompile(`^v?\d+\.\d+\.\d+(-[0-9A-Za-z.-]+)?(\+[0-9A-Za-z.-]+)?$`)
+	exactPyPIVersion = regexp.MustCompile(`^\d+(\.\d+)*((a|b|rc)\d+)?(\.post\d+)?(\.dev\d+)?$`)
+	pypiName         = regexp.MustCompile(`^[A-Za-z0-9]([A-Za-z0-9._-]*[A-Za-z0-9])?`)
+	pypiNameSep      = regexp.MustCompile(`[-_.]+`)
+)
+
+// ParseLaunch reports the package a stdio server launches through npx or uvx.
+// ok is false for other commands and for launches that do not name a registry
+// package (paths, URLs, git specs, npx -c).
+func ParseLaunch(server config.MCPServer) (Launch, bool) {
+	if server.Transport != "" && server.Transport != config.TransportStdio {
+		return Launch{}, false
+	}
+	var launch Launch
+	var ok bool
+	switch runnerName(server.Command) {
+	case RunnerNPX:
+		launch, ok = parseNPX(server.Args)
+	case RunnerUVX:
+		launch, ok = parseUVX(server.Args)
+	}
+	// A spec built from env placeholders is only known at launch time.
+	if !ok || strings.Contains(server.Args[launch.arg], "${") {
+		return Launch{}, false
+	}
+	return launch, true
+}
+
+// Exact reports whether Requested already names one version.
+func (l Launch) Exact() bool {
+	return l.exactVersion() != ""
+}
+
+// exactVersion returns the version Requested pins, or "".
+func (l Launch) exactVersion() string {
+	switch l.Runner {
+	case RunnerNPX:
+		if exactNPMVersion.MatchString(l.Requested) {
+			return strings.TrimPrefix(l.Requested, "v")
+		}
+	case RunnerUVX:
+		version := strings.TrimPrefix(l.Requested, "==")
+		if exactPyPIVersion.MatchString(version) {
+			return version
+		}
+	}
+	return ""
+}
+
+// Spec renders the package spec for version in the runner's syntax.
+func (l Launch) Spec(version string) string {
+	if l.Runner == RunnerUVX {
+		return l.Package + l.Extras + "==" + version
+	}
+	return l.Package + "@" + version
+}
+
+// RequestedLabel renders the package and requested spec for messages.
+func (l Launch) RequestedLabel() string {
+	if l.Requested == "" {
+		return l.Package
+	}
+	if l.Runner == RunnerUVX && strings.ContainsAny(l.Requested[:1], "=<>!~") {
+		return l.Package + l.Extras + l.Requested
+	}
+	return l.Package + l.Extras + "@" + l.Requested
+}
+
+// runnerName returns the launcher name for command, ignoring the directory
+// and Windows shim extensions.
+func runnerName(command string) string {
+	name := filepath.Base(strings.ReplaceAll(command, `\`, "/"))
+	for _, ext := range []string{".cmd", ".exe"} {
+		name = strings.TrimSuffix(name, ext)
+	}
+	return name
+}
+
+func parseNPX(args []string) (Launch, bool) {
+	launch := Launch{Runner: RunnerNPX, arg: -1}
+	positional := -1
+	for i := 0; i < len(args) && positional < 0; i++ {
+		arg := args[i]
+		switch {
+		case arg == "--":
+			if i+1 < len(args) {
+				positional = i + 1
+			}
+			i = len(args)
+		case arg == "-c" || arg == "--call" || strings.HasPrefix(arg, "--call="):
+			return Launch{}, false
+		case arg == "-p" || arg == "--package" || strings.HasPrefix(arg, "--package="):
+			// Several packages have no single version to pin.
+			if launch.arg >= 0 {
+				return Launch{}, false
+			}
+			if arg == "-p" || arg == "--package" {
+				i++
+				launch.arg = i
+			} else {
+				launch.arg = i
+				launch.prefix = "--package="
+			}
+		case npxValueFlags[arg]:
+			i++
+		case strings.HasPrefix(arg, "-"):
+		default:
+			positional = i
+		}
+	}
+	// The first positional is the package unless -p named it, in which case
+	// it is the bin to run.
+	if launch.arg < 0 {
+		launch.arg = positional
+	}
+	if launch.arg < 0 || launch.arg >= len(args) {
+		return Launch{}, false
+	}
+	name, requested, ok := splitNPMSpec(strings.TrimPrefix(args[launch.arg], launch.prefix))
+	if !ok {
+		return Launch{}, false
+	}
+	launch.Package = name
+	launch.Requested = requested
+	return launch, true
+}
+
+// splitNPMSpec splits name@spec, keeping the leading @ of a scoped name.
+func splitNPMSpec(spec string) (string, string, bool) {
+	if spec == "" || strings.ContainsAny(spec, ":\\") || strings.HasPrefix(spec, ".") || strings.HasPrefix(spec, "/") || strings.HasPrefix(spec, "~") {
+		return "", "", false
+	}
+	name, requested := spec, ""
+	if at := strings.LastIndex(spec, "@"); at > 0 {
+		name, requested = spec[:at], spec[at+1:]
+	}
+	slashes := strings.Count(name, "/")
+	if strings.HasPrefix(name, "@") {
+		if slashes != 1 || strings.HasSuffix(name, "/") || strings.HasPrefix(name, "@/") {
+			return "", "", false
+		}
+	} else if slashes != 0 {
+		// owner/repo is GitHub shorthand, not a registry package.
+		return "", "", false
+	}
+	return name, requested, true
+}
+
+func parseUVX(args []string) (Launch, bool) {
+	launch := Launch{Runner: RunnerUVX, arg: -1}
+	from := false
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--from":
+			if i+1 >= len(args) {
+				return Launch{}, false
+			}
+			i++
+			launch.arg = i
+			from = true
+		case strings.HasPrefix(arg, "--from="):
+			launch.arg = i
+			launch.prefix = "--from="
+			from = true
+		case uvxValueFlags[arg]:
+			i++
+		case strings.HasPrefix(arg, "-"):
+		default:
+			if !from {
+				launch.arg = i
+			}
+			i = len(args)
+		}
+	}
+	if launch.arg < 0 {
+		return Launch{}, false
+	}
+	spec := strings.TrimPrefix(args[launch.arg], launch.prefix)
+	if strings.Contains(spec, "://") || strings.HasPrefix(spec, "git+") || strings.ContainsAny(spec, "/\\;") {
+		return Launch{}, false
+	}
+	name := pypiName.FindString(spec)
+	if name == "" {
+		return Launch{}, false
+	}
+	rest := spec[len(name):]
+	if strings.HasPrefix(rest, "[") {
+		end := strings.Index(rest, "]")
+		if end < 0 {
+			return Launch{}, false
+		}
+		launch.Extras, rest = rest[:end+1], rest[end+1:]
+	}
+	rest = strings.TrimSpace(rest)
+	if strings.HasPrefix(rest, "@") {
+		rest = strings.TrimPrefix(rest, "@")
+	} else if rest != "" && !strings.ContainsAny(rest[:1], "=<>!~") {
+		return Launch{}, false
+	}
+	launch.Package = name
+	launch.Requested = rest
+	return launch, true
+}
+
+// normalizePyPIName returns the PEP 503 normalized form of name.
+func normalizePyPIName(name string) string {
+	return strings.ToLower(pypiNameSep.ReplaceAllString(name, "-"))
+}
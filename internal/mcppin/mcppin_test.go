@@ -0,0 +1,201 @@
+package mcppin
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/conn-castle/agent-layer/internal/config"
+	"github.com/conn-castle/agent-layer/internal/errcode"
+	"github.com/conn-castle/agent-layer/internal/lockfile"
+)
+
+type fakeSystem struct {
+	env      map[string]string
+	outputs  map[string]string
+	failures map[string]error
+	calls    []string
+}
+
+func (f *fakeSystem) Getenv(key string) string { return f.env[key] }
+
+func (f *fakeSystem) MkdirTemp(dir, pattern string) (string, error) { return "/tmp/prefetch", nil }
+
+func (f *fakeSystem) RemoveAll(path string) error { return nil }
+
+func (f *fakeSystem) Run(_ context.Context, stdin string, name string, args ...string) ([]byte, error) {
+	call := strings.TrimSpace(stdin + " " + name + " " + strings.Join(args, " "))
+	f.calls = append(f.calls, call)
+	for prefix, err := range f.failures {
+		if strings.HasPrefix(call, prefix) {
+			return nil, err
+		}
+	}
+	for prefix, out := range f.outputs {
+		if strings.HasPrefix(call, prefix) {
+			return []byte(out), nil
+		}
+	}
+	return nil, nil
+}
+
+func stdio(id string, command string, args ...string) config.MCPServer {
+	enabled := true
+	return config.MCPServer{ID: id, Enabled: &enabled, Transport: "stdio", Command: command, Args: args}
+}
+
+func TestParseLaunch(t *testing.T) {
+	tests := []struct {
+		name   string
+		server config.MCPServer
+		want   Launch
+		ok     bool
+	}{
+		{"npx scoped pinned", stdio("a", "npx", "-y", "@upstash/context7-mcp@2.1.1"), Launch{Runner: RunnerNPX, Package: "@upstash/context7-mcp", Requested: "2.1.1", arg: 1}, true},
+		{"npx scoped unpinned", stdio("a", "npx", "-y", "@playwright/mcp", "--headless"), Launch{Runner: RunnerNPX, Package: "@playwright/mcp", arg: 1}, true},
+		{"npx package flag", stdio("a", "npx", "--registry", "https://r.example", "-p", "pkg@^1", "pkg-bin"), Launch{Runner: RunnerNPX, Package: "pkg", Requested: "^1", arg: 3}, true},
+		{"npx package equals", stdio("a", "/usr/bin/npx.cmd", "--package=pkg@latest", "bin"), Launch{Runner: RunnerNPX, Package: "pkg", Requested: "latest", arg: 0, prefix: "--package="}, true},
+		{"npx github shorthand", stdio("a", "npx", "owner/repo"), Launch{}, false},
+		{"npx local path", stdio("a", "npx", "./server"), Launch{}, false},
+		{"npx call", stdio("a", "npx", "-c", "server"), Launch{}, false},
+		{"npx placeholder", stdio("a", "npx", "pkg@${VERSION}"), Launch{}, false},
+		{"uvx pinned", stdio("a", "uvx", "mcp-server-fetch==2025.4.7"), Launch{Runner: RunnerUVX, Package: "mcp-server-fetch", Requested: "==2025.4.7", arg: 0}, true},
+		{"uvx at form", stdio("a", "uvx", "--python", "3.12", "tool[cli]@latest", "--flag"), Launch{Runner: RunnerUVX, Package: "tool", Extras: "[cli]", Requested: "latest", arg: 2}, true},
+		{"uvx from", stdio("a", "uvx", "--from", "pkg>=1.2", "pkg-cmd"), Launch{Runner: RunnerUVX, Package: "pkg", Requested: ">=1.2", arg: 1}, true},
+		{"uvx git", stdio("a", "uvx", "--from", "git+https://example.com/x", "x"), Launch{}, false},
+		{"other command", stdio("a", "node", "server.js"), Launch{}, false},
+		{"http server", config.MCPServer{ID: "a", Transport: "http", Command: "npx", Args: []string{"pkg"}}, Launch{}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := ParseLaunch(tt.server)
+			if ok != tt.ok || !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("ParseLaunch = %+v, %v; want %+v, %v", got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+func TestApply(t *testing.T) {
+	servers := []config.MCPServer{
+		stdio("context7", "npx", "-y", "@upstash/context7-mcp@^2"),
+		stdio("fetch", "uvx", "mcp-server-fetch"),
+		stdio("stale", "npx", "-y", "other@3"),
+		stdio("plain", "node", "server.js"),
+	}
+	locked := []lockfile.MCPServer{
+		{ID: "context7", Runner: "npx", Package: "@upstash/context7-mcp", Requested: "^2", Version: "2.1.1"},
+		{ID: "fetch", Runner: "uvx", Package: "mcp-server-fetch", Version: "2025.4.7"},
+		{ID: "stale", Runner: "npx", Package: "other", Requested: "2", Version: "2.0.0"},
+	}
+	pinned, changed := Apply(servers, locked)
+	if !changed {
+		t.Fatal("expected servers to change")
+	}
+	if want := []string{"--prefer-offline", "-y", "@upstash/context7-mcp@2.1.1"}; !reflect.DeepEqual(pinned[0].Args, want) {
+		t.Fatalf("context7 args = %v, want %v", pinned[0].Args, want)
+	}
+	if want := []string{"mcp-server-fetch==2025.4.7"}; !reflect.DeepEqual(pinned[1].Args, want) {
+		t.Fatalf("fetch args = %v, want %v", pinned[1].Args, want)
+	}
+	if !reflect.DeepEqual(pinned[2], servers[2]) || !reflect.DeepEqual(pinned[3], servers[3]) {
+		t.Fatalf("stale or non-runner servers changed: %+v", pinned[2:])
+	}
+	if servers[0].Args[1] != "@upstash/context7-mcp@^2" {
+		t.Fatalf("input servers were modified: %v", servers[0].Args)
+	}
+
+	if _, changed := Apply(servers, nil); changed {
+		t.Fatal("expected no change without lock entries")
+	}
+}
+
+func TestPrefetch_RecordsResolvedVersions(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, ".agent-layer"), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	var lock lockfile.File
+	lock.SetMCPServer(lockfile.MCPServer{ID: "broken", Runner: "npx", Package: "broken", Version: "0.9.0"})
+	lock.SetMCPServer(lockfile.MCPServer{ID: "removed", Runner: "npx", Package: "gone", Version: "1.0.0"})
+	if err := lockfile.Save(root, lock); err != nil {
+		t.Fatal(err)
+	}
+
+	disabled := stdio("disabled", "npx", "disabled-pkg")
+	disabled.Enabled = nil
+	servers := []config.MCPServer{
+		stdio("context7", "npx", "-y", "@upstash/context7-mcp@^2"),
+		stdio("pinned", "npx", "-y", "pinned@1.0.0"),
+		stdio("fetch", "uvx", "mcp-server-fetch"),
+		stdio("broken", "npx", "broken"),
+		disabled,
+		stdio("plain", "node", "server.js"),
+	}
+	sys := &fakeSystem{
+		outputs: map[string]string{
+			"npm view @upstash/context7-mcp@^2 version --json": `["2.0.0", "2.1.1"]`,
+			"mcp-server-fetch\n uv pip compile":                "httpx==0.28.1\nmcp_server_fetch==2025.4.7\n",
+		},
+		failures: map[string]error{
+			"npm view broken@latest": errors.New("E404"),
+		},
+	}
+	results, err := Prefetch(context.Background(), sys, root, servers)
+	if err != nil {
+		t.Fatalf("Prefetch error: %v", err)
+	}
+	if len(results) != 4 {
+		t.Fatalf("results = %+v", results)
+	}
+	versions := map[string]string{}
+	for _, result := range results {
+		versions[result.ID] = result.Version
+	}
+	if want := map[string]string{"context7": "2.1.1", "pinned": "1.0.0", "fetch": "2025.4.7", "broken": ""}; !reflect.DeepEqual(versions, want) {
+		t.Fatalf("versions = %v, want %v", versions, want)
+	}
+	if results[3].Err == nil || !strings.Contains(results[3].Err.Error(), "E404") {
+		t.Fatalf("broken err = %v", results[3].Err)
+	}
+	for _, want := range []string{
+		"npm exec --yes --package=@upstash/context7-mcp@2.1.1 -- node --version",
+		"npm exec --yes --package=pinned@1.0.0 -- node --version",
+		"uv pip install --quiet --target /tmp/prefetch mcp-server-fetch==2025.4.7",
+	} {
+		found := false
+		for _, call := range sys.calls {
+			found = found || call == want
+		}
+		if !found {
+			t.Fatalf("missing call %q in %v", want, sys.calls)
+		}
+	}
+
+	loaded, err := lockfile.Load(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ids := make([]string, 0, len(loaded.MCPServers))
+	for _, entry := range loaded.MCPServers {
+		ids = append(ids, entry.ID+"="+entry.Version)
+	}
+	if want := []string{"broken=0.9.0", "context7=2.1.1", "fetch=2025.4.7", "pinned=1.0.0"}; !reflect.DeepEqual(ids, want) {
+		t.Fatalf("locked = %v, want %v", ids, want)
+	}
+}
+
+func TestPrefetch_OfflineRefuses(t *testing.T) {
+	sys := &fakeSystem{env: map[string]string{"AL_OFFLINE": "1"}}
+	_, err := Prefetch(context.Background(), sys, t.TempDir(), []config.MCPServer{stdio("a", "npx", "pkg")})
+	if errcode.Of(err) != errcode.Offline {
+		t.Fatalf("expected offline error, got %v", err)
+	}
+	if len(sys.calls) != 0 {
+		t.Fatalf("expected no runner calls, got %v", sys.calls)
+	}
+}
@@ -0,0 +1,58 @@
+package mcppin
+
+import (
+	"slices"
+
+	"github.com/conn-castle/agent-layer/internal/config"
+	"github.com/conn-castle/agent-layer/internal/lockfile"
+)
+
+// preferOffline makes npx use its cache when the pinned package is already
+// there and reach the registry only when it is not.
+const preferOffline = "--prefer-offline"
+
+// Apply returns servers with every launch that has a current al.lock entry
+// rewritten to the pinned invocation: npx runs --prefer-offline
+// <package>@<version> and uvx runs <package>==<version>. An entry is current
+// when its id, runner, package, and requested spec match config.toml, so
+// editing the spec in config.toml leaves that server unpinned until the next
+// `al mcp prefetch` instead of silently running the old version. changed
+// reports whether any server was rewritten; the input slice is not modified.
+func Apply(servers []config.MCPServer, locked []lockfile.MCPServer) ([]config.MCPServer, bool) {
+	if len(locked) == 0 {
+		return servers, false
+	}
+	pinned := slices.Clone(servers)
+	changed := false
+	for i, server := range servers {
+		launch, ok := ParseLaunch(server)
+		if !ok {
+			continue
+		}
+		idx := slices.IndexFunc(locked, func(entry lockfile.MCPServer) bool { return entry.ID == server.ID })
+		if idx < 0 || !launch.matches(locked[idx]) {
+			continue
+		}
+		pinned[i].Args = launch.pinnedArgs(server.Args, locked[idx].Version)
+		changed = true
+	}
+	if !changed {
+		return servers, false
+	}
+	return pinned, true
+}
+
+// matches reports whether entry was recorded for this launch.
+func (l Launch) matches(entry lockfile.MCPServer) bool {
+	return entry.Runner == l.Runner && entry.Package == l.Package && entry.Requested == l.Requested && entry.Version != ""
+}
+
+// pinnedArgs returns a copy of args launching version.
+func (l Launch) pinnedArgs(args []string, version string) []string {
+	pinned := slices.Clone(args)
+	pinned[l.arg] = l.prefix + l.Spec(version)
+	if l.Runner == RunnerNPX && !slices.Contains(args, preferOffline) {
+		pinned = append([]string{preferOffline}, pinned...)
+	}
+	return pinned
+}
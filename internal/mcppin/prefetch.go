@@ -0,0 +1,175 @@
+package mcppin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+
+	"github.com/conn-castle/agent-layer/internal/config"
+	"github.com/conn-castle/agent-layer/internal/i18n"
+	"github.com/conn-castle/agent-layer/internal/lockfile"
+	"github.com/conn-castle/agent-layer/internal/messages"
+	"github.com/conn-castle/agent-layer/internal/offline"
+)
+
+// Result reports the prefetch outcome for one server.
+type Result struct {
+	ID     string
+	Launch Launch
+	// Version is the resolved version; empty when Err is set.
+	Version string
+	Err     error
+}
+
+// Prefetch resolves every enabled npx or uvx server in servers to an exact
+// version, warms the runner's cache with that version, and records it in
+// al.lock under root. Servers that fail keep a still-current lock entry, and
+// entries for servers no longer launched through npx or uvx are dropped. The
+// lock is only written when it changes. Per-server failures are reported in
+// the results; the error is for offline mode and lock I/O.
+func Prefetch(ctx context.Context, sys System, root string, servers []config.MCPServer) ([]Result, error) {
+	var results []Result
+	for _, server := range servers {
+		if !config.IsAgentEnabled(server.Enabled) {
+			continue
+		}
+		if launch, ok := ParseLaunch(server); ok {
+			results = append(results, Result{ID: server.ID, Launch: launch})
+		}
+	}
+	if len(results) > 0 {
+		if err := offline.Check(sys.Getenv, messages.OfflineOpMCPPrefetch); err != nil {
+			return nil, err
+		}
+	}
+	lock, err := lockfile.Load(root)
+	if err != nil {
+		return nil, err
+	}
+	before, err := lockfile.Encode(lock)
+	if err != nil {
+		return nil, i18n.Errorf(messages.LockfileWriteFailedFmt, lockfile.Path(root), err)
+	}
+
+	kept := make([]lockfile.MCPServer, 0, len(results))
+	for i := range results {
+		result := &results[i]
+		result.Version, result.Err = prefetchLaunch(ctx, sys, result.Launch)
+		if result.Err != nil {
+			result.Version = ""
+			if entry, ok := lock.MCPServer(result.ID); ok && result.Launch.matches(entry) {
+				kept = append(kept, entry)
+			}
+			continue
+		}
+		kept = append(kept, lockfile.MCPServer{
+			ID:        result.ID,
+			Runner:    result.Launch.Runner,
+			Package:   result.Launch.Package,
+			Requested: result.Launch.Requested,
+			Version:   result.Version,
+		})
+	}
+	lock.MCPServers = kept
+	after, err := lockfile.Encode(lock)
+	if err != nil {
+		return nil, i18n.Errorf(messages.LockfileWriteFailedFmt, lockfile.Path(root), err)
+	}
+	if !bytes.Equal(before, after) {
+		if err := lockfile.Save(root, lock); err != nil {
+			return nil, err
+		}
+	}
+	return results, nil
+}
+
+// prefetchLaunch resolves launch and caches the resolved version.
+func prefetchLaunch(ctx context.Context, sys System, launch Launch) (string, error) {
+	version, err := resolveVersion(ctx, sys, launch)
+	if err != nil {
+		return "", i18n.Errorf(messages.MCPPinResolveFailedFmt, launch.RequestedLabel(), err)
+	}
+	if err := warmCache(ctx, sys, launch, version); err != nil {
+		return "", i18n.Errorf(messages.MCPPinCacheFailedFmt, launch.Spec(version), err)
+	}
+	return version, nil
+}
+
+// resolveVersion returns the exact version launch runs today: the pinned
+// version itself, or the registry's answer for a range, tag, or no spec.
+func resolveVersion(ctx context.Context, sys System, launch Launch) (string, error) {
+	if version := launch.exactVersion(); version != "" {
+		return version, nil
+	}
+	if launch.Runner == RunnerNPX {
+		requested := launch.Requested
+		if requested == "" {
+			requested = "latest"
+		}
+		out, err := sys.Run(ctx, "", "npm", "view", launch.Package+"@"+requested, "version", "--json")
+		if err != nil {
+			return "", err
+		}
+		return parseNPMViewVersion(out)
+	}
+	requirement := launch.Package + launch.Extras
+	switch {
+	case launch.Requested == "" || launch.Requested == "latest":
+	case strings.ContainsAny(launch.Requested[:1], "=<>!~"):
+		requirement += launch.Requested
+	default:
+		// uvx reads name@version as name==version.
+		requirement += "==" + launch.Requested
+	}
+	out, err := sys.Run(ctx, requirement+"\n", "uv", "pip", "compile", "-", "--quiet", "--no-header", "--no-annotate")
+	if err != nil {
+		return "", err
+	}
+	want := normalizePyPIName(launch.Package)
+	for _, line := range strings.Split(string(out), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		name, version, ok := strings.Cut(fields[0], "==")
+		if ok && normalizePyPIName(strings.SplitN(name, "[", 2)[0]) == want {
+			return version, nil
+		}
+	}
+	return "", i18n.Errorf(messages.MCPPinNoVersionFmt, "uv pip compile", requirement)
+}
+
+// parseNPMViewVersion reads `npm view --json version` output: one version,
+// or every matching version in ascending order when the spec is a range.
+func parseNPMViewVersion(out []byte) (string, error) {
+	out = bytes.TrimSpace(out)
+	var version string
+	if err := json.Unmarshal(out, &version); err == nil && version != "" {
+		return version, nil
+	}
+	var versions []string
+	if err := json.Unmarshal(out, &versions); err == nil && len(versions) > 0 {
+		return versions[len(versions)-1], nil
+	}
+	return "", i18n.Errorf(messages.MCPPinNoVersionFmt, "npm view", string(out))
+}
+
+// warmCache installs version into the runner's cache so the pinned launch
+// starts without a registry round trip. npx keys its cache by package spec, so
+// `npm exec --package=<spec>` fills the same entry `npx <spec>` reads; uv
+// shares one wheel cache across pip installs and uvx environments.
+func warmCache(ctx context.Context, sys System, launch Launch, version string) error {
+	spec := launch.Spec(version)
+	if launch.Runner == RunnerNPX {
+		_, err := sys.Run(ctx, "", "npm", "exec", "--yes", "--package="+spec, "--", "node", "--version")
+		return err
+	}
+	dir, err := sys.MkdirTemp("", "al-mcp-prefetch-*")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = sys.RemoveAll(dir) }()
+	_, err = sys.Run(ctx, "", "uv", "pip", "install", "--quiet", "--target", dir, spec)
+	return err
+}
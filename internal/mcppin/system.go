@@ -0,0 +1,76 @@
+package mcppin
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// maxCommandOutputSize caps runner output included in errors.
+const maxCommandOutputSize = 2000
+
+// System abstracts the OS and package-manager access prefetch needs. Like the
+// remotebase System, it is package-local so tests can replace npm and uv
+// without touching the network.
+type System interface {
+	Getenv(key string) string
+	MkdirTemp(dir, pattern string) (string, error)
+	RemoveAll(path string) error
+	// Run executes name with args, feeding stdin, and returns stdout.
+	Run(ctx context.Context, stdin string, name string, args ...string) ([]byte, error)
+}
+
+// RealSystem implements System using the OS and the npm and uv CLIs.
+type RealSystem struct{}
+
+// Getenv returns the value of the environment variable named by key.
+func (RealSystem) Getenv(key string) string {
+	return os.Getenv(key)
+}
+
+// MkdirTemp creates a new temporary directory in dir.
+func (RealSystem) MkdirTemp(dir, pattern string) (string, error) {
+	return os.MkdirTemp(dir, pattern)
+}
+
+// RemoveAll removes path and any children it contains.
+func (RealSystem) RemoveAll(path string) error {
+	return os.RemoveAll(path)
+}
+
+// Run executes name with args and returns stdout. Errors carry trimmed
+// stderr so registry failures read as the runner reported them.
+func (RealSystem) Run(ctx context.Context, stdin string, name string, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, name, args...) // #nosec G204 -- name is npm or uv; package specs come from config.toml and are passed without a shell.
+	cmd.Stdin = strings.NewReader(stdin)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return out, &commandError{err: err, output: stderr.String()}
+	}
+	return out, nil
+}
+
+// commandError carries trimmed runner output alongside the process error.
+type commandError struct {
+	err    error
+	output string
+}
+
+func (e *commandError) Error() string {
+	output := strings.TrimSpace(e.output)
+	if output == "" {
+		return e.err.Error()
+	}
+	if len(output) > maxCommandOutputSize {
+		output = output[:maxCommandOutputSize] + "..."
+	}
+	return e.err.Error() + ": " + output
+}
+
+func (e *commandError) Unwrap() error {
+	return e.err
+}
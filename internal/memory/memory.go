@@ -0,0 +1,494 @@
+// Package memory reads and edits the entry logs in the repo's memory files
+// (docs/agent-layer/ISSUES.md, BACKLOG.md, and DECISIONS.md) and the phases in
+// ROADMAP.md. Entries live below the `<!-- ENTRIES START -->` marker and phases
+// below `<!-- PHASES START -->`, in the format each file documents, so edits
+// made here and edits made by hand stay interchangeable.
+package memory
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/conn-castle/agent-layer/internal/clock"
+	"github.com/conn-castle/agent-layer/internal/fsutil"
+	"github.com/conn-castle/agent-layer/internal/i18n"
+	"github.com/conn-castle/agent-layer/internal/messages"
+)
+
+// Entry types.
+const (
+	TypeIssue    = "issue"
+	TypeBacklog  = "backlog"
+	TypeDecision = "decision"
+)
+
+// Types lists the entry types in display order.
+var Types = []string{TypeIssue, TypeBacklog, TypeDecision}
+
+// EntriesMarker separates a memory file's instructions from its entries.
+const EntriesMarker = "<!-- ENTRIES START -->"
+
+const (
+	dateLayout  = "2006-01-02"
+	fieldIndent = "    "
+	maxIDLength = 40
+)
+
+// kind describes one memory file and the entry format it documents.
+type kind struct {
+	file  string
+	label string
+	// newestFirst places new entries directly below the marker; otherwise
+	// they are appended after the last entry.
+	newestFirst bool
+	// fields are the Key: Value lines an entry may carry, in order.
+	fields []string
+}
+
+var kinds = map[string]kind{
+	TypeIssue: {
+		file:        "ISSUES.md",
+		label:       "Issue",
+		newestFirst: true,
+		fields:      []string{FieldPriority, FieldDescription, FieldNextStep, FieldNotes},
+	},
+	TypeBacklog: {
+		file:        "BACKLOG.md",
+		label:       "Backlog",
+		newestFirst: true,
+		fields:      []string{FieldPriority, FieldDescription, FieldAcceptance, FieldNotes},
+	},
+	TypeDecision: {
+		file:   "DECISIONS.md",
+		label:  "Decision",
+		fields: []string{FieldDecision, FieldReason, FieldTradeoffs},
+	},
+}
+
+// Field keys used by the entry templates. FieldPriority holds the whole
+// "Priority: <p>. Area: <a>" line value.
+const (
+	FieldPriority    = "Priority"
+	FieldDescription = "Description"
+	FieldNextStep    = "Next step"
+	FieldAcceptance  = "Acceptance criteria"
+	FieldNotes       = "Notes"
+	FieldDecision    = "Decision"
+	FieldReason      = "Reason"
+	FieldTradeoffs   = "Tradeoffs"
+)
+
+// Priorities lists the accepted priority values.
+var Priorities = []string{"Critical", "High", "Medium", "Low"}
+
+// DefaultPriority is used when an issue or backlog entry names none.
+const DefaultPriority = "Medium"
+
+var (
+	headerPattern = regexp.MustCompile(`^- (Issue|Backlog|Decision) (\d{4}-\d{2}-\d{2}) ([^\s:]+):\s*(.*)$`)
+	slugUnsafe    = regexp.MustCompile(`[^a-z0-9]+`)
+)
+
+// Field is one indented Key: Value line of an entry.
+type Field struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+// Entry is one parsed memory entry.
+type Entry struct {
+	Type   string  `json:"type"`
+	Date   string  `json:"date"`
+	ID     string  `json:"id"`
+	Title  string  `json:"title"`
+	Fields []Field `json:"fields,omitempty"`
+	// Path is the repo-relative memory file holding the entry.
+	Path string `json:"path"`
+}
+
+// AddOptions describes a new entry.
+type AddOptions struct {
+	// Root is the repo root holding docs/agent-layer/.
+	Root  string
+	Type  string
+	Title string
+	// Priority and Area fill the issue and backlog "Priority" line.
+	Priority string
+	Area     string
+	// Values maps the type's remaining field keys to their text. Empty
+	// values are omitted.
+	Values map[string]string
+	Clock  clock.Clock
+}
+
+// Dir returns the memory directory under root.
+func Dir(root string) string {
+	return filepath.Join(root, "docs", "agent-layer")
+}
+
+// NormalizeType maps a type name, singular or plural and in any case, to one
+// of Types.
+func NormalizeType(raw string) (string, error) {
+	value := strings.ToLower(strings.TrimSpace(raw))
+	switch value {
+	case TypeIssue, "issues":
+		return TypeIssue, nil
+	case TypeBacklog, "backlogs":
+		return TypeBacklog, nil
+	case TypeDecision, "decisions":
+		return TypeDecision, nil
+	}
+	return "", i18n.Errorf(messages.MemoryUnknownTypeFmt, raw, strings.Join(Types, ", "))
+}
+
+// FieldsFor returns the field keys entries of typ may carry, in order.
+func FieldsFor(typ string) []string {
+	return append([]string(nil), kinds[typ].fields...)
+}
+
+// List returns the entries of typ, or of every type when typ is empty, in
+// file order. Missing memory files hold no entries.
+func List(root string, typ string) ([]Entry, error) {
+	types := Types
+	if typ != "" {
+		normalized, err := NormalizeType(typ)
+		if err != nil {
+			return nil, err
+		}
+		types = []string{normalized}
+	}
+	var entries []Entry
+	for _, t := range types {
+		doc, err := load(root, t)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, doc.entries()...)
+	}
+	return entries, nil
+}
+
+// Add writes a new entry with a stable ID derived from its title and returns
+// it. Issues and backlog items are inserted newest first; decisions are
+// appended, as each file's format section asks.
+func Add(opts AddOptions) (Entry, error) {
+	typ, err := NormalizeType(opts.Type)
+	if err != nil {
+		return Entry{}, err
+	}
+	title := collapse(opts.Title)
+	if title == "" {
+		return Entry{}, errors.New(i18n.T(messages.MemoryTitleRequired))
+	}
+	fields, err := buildFields(typ, opts)
+	if err != nil {
+		return Entry{}, err
+	}
+	doc, err := load(opts.Root, typ)
+	if err != nil {
+		return Entry{}, err
+	}
+	entry := Entry{
+		Type:   typ,
+		Date:   clock.Or(opts.Clock).Now().Format(dateLayout),
+		ID:     doc.uniqueID(slugify(title)),
+		Title:  title,
+		Fields: fields,
+		Path:   doc.relPath,
+	}
+	block := entry.lines()
+	if kinds[typ].newestFirst {
+		doc.blocks = append([][]string{block}, doc.blocks...)
+	} else {
+		doc.blocks = append(doc.blocks, block)
+	}
+	if err := doc.save(); err != nil {
+		return Entry{}, err
+	}
+	return entry, nil
+}
+
+// Resolve removes the entry with id and returns it. When typ is empty every
+// memory file is searched and the ID must be unique across them.
+func Resolve(root string, typ string, id string) (Entry, error) {
+	id = strings.TrimSpace(id)
+	types := Types
+	if typ != "" {
+		normalized, err := NormalizeType(typ)
+		if err != nil {
+			return Entry{}, err
+		}
+		types = []string{normalized}
+	}
+	var (
+		found    *document
+		foundIdx int
+		matches  []string
+	)
+	for _, t := range types {
+		doc, err := load(root, t)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return Entry{}, err
+		}
+		for i, block := range doc.blocks {
+			if entry, ok := parseEntry(block); ok && entry.ID == id {
+				found, foundIdx = doc, i
+				matches = append(matches, doc.relPath)
+			}
+		}
+	}
+	switch {
+	case len(matches) == 0:
+		return Entry{}, i18n.Errorf(messages.MemoryEntryNotFoundFmt, id)
+	case len(matches) > 1:
+		return Entry{}, i18n.Errorf(messages.MemoryEntryAmbiguousFmt, id, strings.Join(matches, ", "))
+	}
+	entry, _ := parseEntry(found.blocks[foundIdx])
+	entry.Type, entry.Path = found.typ, found.relPath
+	found.blocks = append(found.blocks[:foundIdx], found.blocks[foundIdx+1:]...)
+	if err := found.save(); err != nil {
+		return Entry{}, err
+	}
+	return entry, nil
+}
+
+// buildFields validates opts against typ's template and returns the entry's
+// Key: Value lines in template order.
+func buildFields(typ string, opts AddOptions) ([]Field, error) {
+	allowed := kinds[typ].fields
+	for key, value := range opts.Values {
+		if collapse(value) != "" && (key == FieldPriority || !containsString(allowed, key)) {
+			return nil, i18n.Errorf(messages.MemoryFieldNotAllowedFmt, key, typ)
+		}
+	}
+	var fields []Field
+	for _, key := range allowed {
+		if key == FieldPriority {
+			priority, err := normalizePriority(opts.Priority)
+			if err != nil {
+				return nil, err
+			}
+			value := priority + "."
+			if area := collapse(opts.Area); area != "" {
+				value += " Area: " + area
+			}
+			fields = append(fields, Field{Key: key, Value: value})
+			continue
+		}
+		if value := collapse(opts.Values[key]); value != "" {
+			fields = append(fields, Field{Key: key, Value: value})
+		}
+	}
+	if !containsString(allowed, FieldPriority) && (strings.TrimSpace(opts.Priority) != "" || strings.TrimSpace(opts.Area) != "") {
+		return nil, i18n.Errorf(messages.MemoryFieldNotAllowedFmt, FieldPriority, typ)
+	}
+	required := FieldDescription
+	if typ == TypeDecision {
+		required = FieldDecision
+	}
+	if collapse(opts.Values[required]) == "" {
+		return nil, i18n.Errorf(messages.MemoryFieldRequiredFmt, required, typ)
+	}
+	return fields, nil
+}
+
+func normalizePriority(raw string) (string, error) {
+	value := strings.TrimSpace(raw)
+	if value == "" {
+		return DefaultPriority, nil
+	}
+	for _, priority := range Priorities {
+		if strings.EqualFold(value, priority) {
+			return priority, nil
+		}
+	}
+	return "", i18n.Errorf(messages.MemoryInvalidPriorityFmt, raw, strings.Join(Priorities, ", "))
+}
+
+// lines renders the entry in its file format.
+func (e Entry) lines() []string {
+	out := []string{fmt.Sprintf("- %s %s %s: %s", kinds[e.Type].label, e.Date, e.ID, e.Title)}
+	for _, field := range e.Fields {
+		out = append(out, fieldIndent+field.Key+": "+field.Value)
+	}
+	return out
+}
+
+// parseEntry reads block as an entry. Blocks that do not start with an entry
+// header are not entries and are preserved as written.
+func parseEntry(block []string) (Entry, bool) {
+	if len(block) == 0 {
+		return Entry{}, false
+	}
+	match := headerPattern.FindStringSubmatch(block[0])
+	if match == nil {
+		return Entry{}, false
+	}
+	entry := Entry{Date: match[2], ID: match[3], Title: strings.TrimSpace(match[4])}
+	for _, line := range block[1:] {
+		key, value, ok := strings.Cut(strings.TrimSpace(line), ":")
+		if !ok {
+			// A wrapped continuation of the previous field.
+			if n := len(entry.Fields); n > 0 {
+				entry.Fields[n-1].Value += " " + strings.TrimSpace(line)
+			}
+			continue
+		}
+		entry.Fields = append(entry.Fields, Field{Key: strings.TrimSpace(key), Value: strings.TrimSpace(value)})
+	}
+	return entry, true
+}
+
+// document is a memory file split at the entries marker. blocks are the
+// blank-line separated paragraphs below it.
+type document struct {
+	typ     string
+	path    string
+	relPath string
+	head    string
+	blocks  [][]string
+	perm    os.FileMode
+}
+
+func load(root string, typ string) (*document, error) {
+	k := kinds[typ]
+	path := filepath.Join(Dir(root), k.file)
+	relPath := filepath.ToSlash(filepath.Join("docs", "agent-layer", k.file))
+	info, err := os.Stat(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, i18n.Errorf(messages.MemoryFileMissingFmt, relPath, err)
+		}
+		return nil, i18n.Errorf(messages.MemoryReadFmt, relPath, err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, i18n.Errorf(messages.MemoryReadFmt, relPath, err)
+	}
+	content := strings.ReplaceAll(string(data), "\r\n", "\n")
+	idx := lineIndex(content, EntriesMarker)
+	if idx < 0 {
+		return nil, i18n.Errorf(messages.MemoryMarkerMissingFmt, relPath, EntriesMarker)
+	}
+	doc := &document{
+		typ:     typ,
+		path:    path,
+		relPath: relPath,
+		head:    content[:idx+len(EntriesMarker)],
+		perm:    info.Mode().Perm(),
+	}
+	var block []string
+	for _, line := range strings.Split(content[idx+len(EntriesMarker):], "\n") {
+		if strings.TrimSpace(line) == "" {
+			if len(block) > 0 {
+				doc.blocks = append(doc.blocks, block)
+				block = nil
+			}
+			continue
+		}
+		block = append(block, strings.TrimRight(line, " \t"))
+	}
+	if len(block) > 0 {
+		doc.blocks = append(doc.blocks, block)
+	}
+	return doc, nil
+}
+
+// lineIndex returns the offset of the first line that holds only marker, or
+// -1. The format sections quote their marker inline, so a plain substring
+// search would split the file in the wrong place.
+func lineIndex(content string, marker string) int {
+	offset := 0
+	for _, line := range strings.SplitAfter(content, "\n") {
+		if strings.TrimSpace(line) == marker {
+			return offset + strings.Index(line, marker)
+		}
+		offset += len(line)
+	}
+	return -1
+}
+
+// save rewrites the file with exactly one blank line after the marker and
+// between blocks.
+func (d *document) save() error {
+	var b strings.Builder
+	b.WriteString(d.head)
+	b.WriteString("\n")
+	for _, block := range d.blocks {
+		b.WriteString("\n")
+		b.WriteString(strings.Join(block, "\n"))
+		b.WriteString("\n")
+	}
+	if err := fsutil.WriteFileAtomic(d.path, []byte(b.String()), d.perm); err != nil {
+		return i18n.Errorf(messages.MemoryWriteFmt, d.relPath, err)
+	}
+	return nil
+}
+
+func (d *document) entries() []Entry {
+	var entries []Entry
+	for _, block := range d.blocks {
+		if entry, ok := parseEntry(block); ok {
+			entry.Type, entry.Path = d.typ, d.relPath
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
+
+// uniqueID returns base, or base with the smallest numeric suffix that no
+// entry in the file already uses.
+func (d *document) uniqueID(base string) string {
+	taken := make(map[string]bool)
+	for _, entry := range d.entries() {
+		taken[entry.ID] = true
+	}
+	if !taken[base] {
+		return base
+	}
+	for n := 2; ; n++ {
+		candidate := fmt.Sprintf("%s-%d", base, n)
+		if !taken[candidate] {
+			return candidate
+		}
+	}
+}
+
+// slugify turns a title into a short lowercase ID, cut at a word boundary.
+func slugify(title string) string {
+	slug := strings.Trim(slugUnsafe.ReplaceAllString(strings.ToLower(title), "-"), "-")
+	if len(slug) > maxIDLength {
+		slug = slug[:maxIDLength]
+		if cut := strings.LastIndex(slug, "-"); cut > 0 {
+			slug = slug[:cut]
+		}
+	}
+	if slug == "" {
+		return "entry"
+	}
+	return slug
+}
+
+// collapse joins value onto one line so it cannot break the entry format.
+func collapse(value string) string {
+	return strings.Join(strings.Fields(value), " ")
+}
+
+func containsString(values []string, want string) bool {
+	for _, value := range values {
+		if value == want {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,151 @@
+package memory
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/conn-castle/agent-layer/internal/clock"
+)
+
+const issuesFixture = `# Issues
+
+## Format
+- Insert new entries immediately below ` + "`<!-- ENTRIES START -->`" + `.
+
+## Open issues
+
+<!-- ENTRIES START -->
+
+- Issue 2026-01-02 flaky-test: Flaky test
+    Priority: Low. Area: tests
+    Description: Sometimes fails.
+`
+
+func writeMemoryFile(t *testing.T, root string, name string, content string) string {
+	t.Helper()
+	path := filepath.Join(Dir(root), name)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestAddListResolve(t *testing.T) {
+	root := t.TempDir()
+	issues := writeMemoryFile(t, root, "ISSUES.md", issuesFixture)
+	writeMemoryFile(t, root, "DECISIONS.md", "# Decisions\n\n<!-- ENTRIES START -->\n")
+	fixed := clock.Fixed{T: time.Date(2026, 3, 4, 12, 0, 0, 0, time.UTC)}
+
+	entry, err := Add(AddOptions{
+		Root:     root,
+		Type:     "issues",
+		Title:    "Flaky  test",
+		Priority: "high",
+		Area:     "ci",
+		Values:   map[string]string{FieldDescription: "Fails\non retry.", FieldNextStep: "Pin the seed."},
+		Clock:    fixed,
+	})
+	if err != nil {
+		t.Fatalf("Add issue: %v", err)
+	}
+	if entry.ID != "flaky-test-2" || entry.Path != "docs/agent-layer/ISSUES.md" {
+		t.Fatalf("entry = %+v", entry)
+	}
+	data, err := os.ReadFile(issues)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "<!-- ENTRIES START -->\n\n" +
+		"- Issue 2026-03-04 flaky-test-2: Flaky test\n" +
+		"    Priority: High. Area: ci\n" +
+		"    Description: Fails on retry.\n" +
+		"    Next step: Pin the seed.\n\n" +
+		"- Issue 2026-01-02 flaky-test: Flaky test\n"
+	if !strings.Contains(string(data), want) {
+		t.Fatalf("new issue not inserted newest first:\n%s", data)
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, err := Add(AddOptions{Root: root, Type: TypeDecision, Title: fmt.Sprint("Use TOML ", i), Values: map[string]string{FieldDecision: "TOML config."}, Clock: fixed}); err != nil {
+			t.Fatalf("Add decision: %v", err)
+		}
+	}
+	decisions, err := List(root, "decision")
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(decisions) != 2 || decisions[0].ID != "use-toml-0" || decisions[1].ID != "use-toml-1" {
+		t.Fatalf("decisions not appended in order: %+v", decisions)
+	}
+
+	all, err := List(root, "")
+	if err != nil {
+		t.Fatalf("List all: %v", err)
+	}
+	if len(all) != 4 || all[0].Type != TypeIssue || all[0].Fields[0].Value != "High. Area: ci" {
+		t.Fatalf("List all = %+v", all)
+	}
+
+	resolved, err := Resolve(root, "", "flaky-test")
+	if err != nil || resolved.Title != "Flaky test" {
+		t.Fatalf("Resolve = %+v, %v", resolved, err)
+	}
+	data, _ = os.ReadFile(issues)
+	if strings.Contains(string(data), "2026-01-02") || !strings.HasSuffix(string(data), "Next step: Pin the seed.\n") {
+		t.Fatalf("resolved entry not removed cleanly:\n%s", data)
+	}
+	if _, err := Resolve(root, "", "flaky-test"); err == nil {
+		t.Fatal("resolving a removed entry must fail")
+	}
+}
+
+func TestAddValidation(t *testing.T) {
+	root := t.TempDir()
+	writeMemoryFile(t, root, "BACKLOG.md", "# Backlog\n")
+	for name, opts := range map[string]AddOptions{
+		"unknown type":      {Type: "todo", Title: "x"},
+		"empty title":       {Type: TypeIssue, Title: " "},
+		"missing required":  {Type: TypeIssue, Title: "x"},
+		"foreign field":     {Type: TypeIssue, Title: "x", Values: map[string]string{FieldDescription: "d", FieldReason: "r"}},
+		"priority decision": {Type: TypeDecision, Title: "x", Priority: "High", Values: map[string]string{FieldDecision: "d"}},
+		"bad priority":      {Type: TypeIssue, Title: "x", Priority: "urgent", Values: map[string]string{FieldDescription: "d"}},
+		"no marker":         {Type: TypeBacklog, Title: "x", Values: map[string]string{FieldDescription: "d"}},
+		"missing file":      {Type: TypeDecision, Title: "x", Values: map[string]string{FieldDecision: "d"}},
+	} {
+		opts.Root = root
+		if _, err := Add(opts); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
+
+func TestResolveAmbiguousID(t *testing.T) {
+	root := t.TempDir()
+	writeMemoryFile(t, root, "ISSUES.md", issuesFixture)
+	writeMemoryFile(t, root, "BACKLOG.md", "<!-- ENTRIES START -->\n- Backlog 2026-01-01 flaky-test: Flaky test\n    Description: x\n")
+	if _, err := Resolve(root, "", "flaky-test"); err == nil || !strings.Contains(err.Error(), "--type") {
+		t.Fatalf("ambiguous resolve = %v", err)
+	}
+	if entry, err := Resolve(root, "backlog", "flaky-test"); err != nil || entry.Type != TypeBacklog {
+		t.Fatalf("typed resolve = %+v, %v", entry, err)
+	}
+}
+
+func TestSlugify(t *testing.T) {
+	for title, want := range map[string]string{
+		"Fix `al sync` on Windows!": "fix-al-sync-on-windows",
+		"???":                       "entry",
+		strings.Repeat("word ", 20): "word-word-word-word-word-word-word-word",
+	} {
+		if got := slugify(title); got != want {
+			t.Errorf("slugify(%q) = %q, want %q", title, got, want)
+		}
+	}
+}
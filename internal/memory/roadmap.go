@@ -0,0 +1,367 @@
+package memory
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/conn-castle/agent-layer/internal/fsutil"
+	"github.com/conn-castle/agent-layer/internal/i18n"
+	"github.com/conn-castle/agent-layer/internal/messages"
+)
+
+// PhasesMarker separates ROADMAP.md's instructions from its phases.
+const PhasesMarker = "<!-- PHASES START -->"
+
+// RoadmapFile is the roadmap's file name under Dir.
+const RoadmapFile = "ROADMAP.md"
+
+// MaxCompletedPhases is how many completed phases ROADMAP.md keeps as
+// individual sections before the oldest are folded into the archive.
+const MaxCompletedPhases = 5
+
+const (
+	completeMark   = "✅"
+	archiveHeading = "## Archived phases"
+)
+
+var (
+	phaseHeadingPattern = regexp.MustCompile(`^## Phase (\d+)(\s+` + completeMark + `)?\s+—\s+(.*)$`)
+	archiveLinePattern  = regexp.MustCompile(`^- Phase (\d+) — ([^:]*):\s*(.*)$`)
+	taskPattern         = regexp.MustCompile(`^- \[([ xX])\]\s+(.*)$`)
+)
+
+// Phase is one roadmap phase.
+type Phase struct {
+	Number   int    `json:"number"`
+	Name     string `json:"name"`
+	Complete bool   `json:"complete"`
+	// Archived phases are the one-line entries in the archive section.
+	Archived   bool `json:"archived,omitempty"`
+	TasksDone  int  `json:"tasks_done"`
+	TasksTotal int  `json:"tasks_total"`
+}
+
+// AddPhaseOptions describes a new incomplete phase.
+type AddPhaseOptions struct {
+	Root  string
+	Name  string
+	Goals []string
+	Tasks []string
+	Exit  []string
+}
+
+// CompletePhaseOptions describes how a phase is marked complete.
+type CompletePhaseOptions struct {
+	Root   string
+	Number int
+	// Summary lists what the phase accomplished. When empty, the phase's
+	// task lines are used.
+	Summary []string
+	// Force completes a phase that still has unchecked tasks.
+	Force bool
+}
+
+// roadmap is ROADMAP.md split at the phases marker. sections are the
+// level-two heading blocks below it, each with trailing blank lines removed.
+type roadmap struct {
+	path     string
+	relPath  string
+	head     string
+	preamble []string
+	sections [][]string
+	perm     os.FileMode
+}
+
+// RoadmapStatus returns every phase in ROADMAP.md in file order, archived
+// phases first.
+func RoadmapStatus(root string) ([]Phase, error) {
+	doc, err := loadRoadmap(root)
+	if err != nil {
+		return nil, err
+	}
+	return doc.phases(), nil
+}
+
+// AddPhase appends an incomplete phase numbered after the last phase and
+// returns it.
+func AddPhase(opts AddPhaseOptions) (Phase, error) {
+	name := collapse(opts.Name)
+	if name == "" {
+		return Phase{}, errors.New(i18n.T(messages.RoadmapPhaseNameRequired))
+	}
+	goals, tasks, exit := collapseAll(opts.Goals), collapseAll(opts.Tasks), collapseAll(opts.Exit)
+	if len(goals) == 0 || len(tasks) == 0 || len(exit) == 0 {
+		return Phase{}, errors.New(i18n.T(messages.RoadmapPhaseSectionsRequired))
+	}
+	doc, err := loadRoadmap(opts.Root)
+	if err != nil {
+		return Phase{}, err
+	}
+	number := 1
+	for _, phase := range doc.phases() {
+		if phase.Number >= number {
+			number = phase.Number + 1
+		}
+	}
+	section := []string{fmt.Sprintf("## Phase %d — %s", number, name), "", "### Goal"}
+	section = append(section, bullets(goals, "- ")...)
+	section = append(section, "", "### Tasks")
+	section = append(section, bullets(tasks, "- [ ] ")...)
+	section = append(section, "", "### Exit criteria")
+	section = append(section, bullets(exit, "- ")...)
+	doc.sections = append(doc.sections, section)
+	if err := doc.save(); err != nil {
+		return Phase{}, err
+	}
+	return Phase{Number: number, Name: name, TasksTotal: len(tasks)}, nil
+}
+
+// CompletePhase marks a phase complete, replaces its content with a summary,
+// and folds the oldest completed phases into the archive once more than
+// MaxCompletedPhases remain. It returns the completed phase and the numbers
+// of any phases it archived.
+func CompletePhase(opts CompletePhaseOptions) (Phase, []int, error) {
+	doc, err := loadRoadmap(opts.Root)
+	if err != nil {
+		return Phase{}, nil, err
+	}
+	idx := -1
+	for i, section := range doc.sections {
+		if phase, ok := parsePhaseSection(section); ok && phase.Number == opts.Number {
+			idx = i
+			break
+		}
+	}
+	if idx < 0 {
+		return Phase{}, nil, i18n.Errorf(messages.RoadmapPhaseNotFoundFmt, opts.Number)
+	}
+	phase, _ := parsePhaseSection(doc.sections[idx])
+	if phase.Complete {
+		return Phase{}, nil, i18n.Errorf(messages.RoadmapPhaseAlreadyCompleteFmt, phase.Number)
+	}
+	if open := phase.TasksTotal - phase.TasksDone; open > 0 && !opts.Force {
+		return Phase{}, nil, i18n.Errorf(messages.RoadmapPhaseOpenTasksFmt, phase.Number, open)
+	}
+	summary := collapseAll(opts.Summary)
+	if len(summary) == 0 {
+		for _, line := range doc.sections[idx][1:] {
+			if match := taskPattern.FindStringSubmatch(strings.TrimSpace(line)); match != nil {
+				summary = append(summary, collapse(match[2]))
+			}
+		}
+	}
+	if len(summary) == 0 {
+		return Phase{}, nil, i18n.Errorf(messages.RoadmapPhaseSummaryRequiredFmt, phase.Number)
+	}
+	doc.sections[idx] = append([]string{fmt.Sprintf("## Phase %d %s — %s", phase.Number, completeMark, phase.Name)}, bullets(summary, "- ")...)
+	phase.Complete = true
+	archived := doc.archiveOldest()
+	if err := doc.save(); err != nil {
+		return Phase{}, nil, err
+	}
+	return phase, archived, nil
+}
+
+// archiveOldest moves completed phases beyond MaxCompletedPhases, oldest
+// first, into the archive section as one line each.
+func (d *roadmap) archiveOldest() []int {
+	var completed []int
+	for i, section := range d.sections {
+		if phase, ok := parsePhaseSection(section); ok && phase.Complete {
+			completed = append(completed, i)
+		}
+	}
+	if len(completed) <= MaxCompletedPhases {
+		return nil
+	}
+	move := make(map[int]bool)
+	for _, i := range completed[:len(completed)-MaxCompletedPhases] {
+		move[i] = true
+	}
+	archiveIdx := -1
+	var lines []string
+	for i, section := range d.sections {
+		if strings.HasPrefix(section[0], archiveHeading) {
+			archiveIdx = i
+			lines = append(lines, section[1:]...)
+		}
+	}
+	var (
+		kept     [][]string
+		archived []int
+	)
+	for i, section := range d.sections {
+		if !move[i] {
+			kept = append(kept, section)
+			continue
+		}
+		phase, _ := parsePhaseSection(section)
+		line := fmt.Sprintf("- Phase %d — %s", phase.Number, phase.Name)
+		if len(section) > 1 {
+			line += ": " + strings.TrimPrefix(strings.TrimSpace(section[1]), "- ")
+		}
+		lines = append(lines, line)
+		archived = append(archived, phase.Number)
+	}
+	first, last := 0, 0
+	for _, line := range lines {
+		if match := archiveLinePattern.FindStringSubmatch(line); match != nil {
+			n, _ := strconv.Atoi(match[1])
+			if first == 0 || n < first {
+				first = n
+			}
+			if n > last {
+				last = n
+			}
+		}
+	}
+	archive := append([]string{fmt.Sprintf("%s (%d–%d)", archiveHeading, first, last)}, lines...)
+	d.sections = nil
+	if archiveIdx < 0 {
+		d.sections = append(d.sections, archive)
+	}
+	for _, section := range kept {
+		if strings.HasPrefix(section[0], archiveHeading) {
+			section = archive
+		}
+		d.sections = append(d.sections, section)
+	}
+	return archived
+}
+
+func (d *roadmap) phases() []Phase {
+	var phases []Phase
+	for _, section := range d.sections {
+		if strings.HasPrefix(section[0], archiveHeading) {
+			for _, line := range section[1:] {
+				if match := archiveLinePattern.FindStringSubmatch(strings.TrimSpace(line)); match != nil {
+					n, _ := strconv.Atoi(match[1])
+					phases = append(phases, Phase{Number: n, Name: strings.TrimSpace(match[2]), Complete: true, Archived: true})
+				}
+			}
+			continue
+		}
+		if phase, ok := parsePhaseSection(section); ok {
+			phases = append(phases, phase)
+		}
+	}
+	return phases
+}
+
+// parsePhaseSection reads a "## Phase N — name" section and counts its tasks.
+func parsePhaseSection(section []string) (Phase, bool) {
+	match := phaseHeadingPattern.FindStringSubmatch(section[0])
+	if match == nil {
+		return Phase{}, false
+	}
+	n, _ := strconv.Atoi(match[1])
+	phase := Phase{Number: n, Name: strings.TrimSpace(match[3]), Complete: match[2] != ""}
+	for _, line := range section[1:] {
+		if task := taskPattern.FindStringSubmatch(strings.TrimSpace(line)); task != nil {
+			phase.TasksTotal++
+			if task[1] != " " {
+				phase.TasksDone++
+			}
+		}
+	}
+	return phase, true
+}
+
+func loadRoadmap(root string) (*roadmap, error) {
+	path := filepath.Join(Dir(root), RoadmapFile)
+	relPath := filepath.ToSlash(filepath.Join("docs", "agent-layer", RoadmapFile))
+	info, err := os.Stat(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, i18n.Errorf(messages.MemoryFileMissingFmt, relPath, err)
+		}
+		return nil, i18n.Errorf(messages.MemoryReadFmt, relPath, err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, i18n.Errorf(messages.MemoryReadFmt, relPath, err)
+	}
+	content := strings.ReplaceAll(string(data), "\r\n", "\n")
+	idx := lineIndex(content, PhasesMarker)
+	if idx < 0 {
+		return nil, i18n.Errorf(messages.MemoryMarkerMissingFmt, relPath, PhasesMarker)
+	}
+	doc := &roadmap{
+		path:    path,
+		relPath: relPath,
+		head:    content[:idx+len(PhasesMarker)],
+		perm:    info.Mode().Perm(),
+	}
+	var section []string
+	for _, line := range strings.Split(content[idx+len(PhasesMarker):], "\n") {
+		line = strings.TrimRight(line, " \t")
+		if strings.HasPrefix(line, "## ") {
+			if section != nil {
+				doc.sections = append(doc.sections, trimBlankTail(section))
+			}
+			section = []string{line}
+			continue
+		}
+		if section == nil {
+			if line != "" {
+				doc.preamble = append(doc.preamble, line)
+			}
+			continue
+		}
+		section = append(section, line)
+	}
+	if section != nil {
+		doc.sections = append(doc.sections, trimBlankTail(section))
+	}
+	return doc, nil
+}
+
+// save rewrites the roadmap with one blank line after the marker and between
+// sections.
+func (d *roadmap) save() error {
+	var b strings.Builder
+	b.WriteString(d.head)
+	b.WriteString("\n")
+	for _, block := range append([][]string{d.preamble}, d.sections...) {
+		if len(block) == 0 {
+			continue
+		}
+		b.WriteString("\n")
+		b.WriteString(strings.Join(block, "\n"))
+		b.WriteString("\n")
+	}
+	if err := fsutil.WriteFileAtomic(d.path, []byte(b.String()), d.perm); err != nil {
+		return i18n.Errorf(messages.MemoryWriteFmt, d.relPath, err)
+	}
+	return nil
+}
+
+func trimBlankTail(lines []string) []string {
+	for len(lines) > 1 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+func collapseAll(values []string) []string {
+	var out []string
+	for _, value := range values {
+		if value = collapse(value); value != "" {
+			out = append(out, value)
+		}
+	}
+	return out
+}
+
+func bullets(values []string, prefix string) []string {
+	out := make([]string, 0, len(values))
+	for _, value := range values {
+		out = append(out, prefix+value)
+	}
+	return out
+}
@@ -0,0 +1,88 @@
+package memory
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+)
+
+func roadmapFixture(completed int) string {
+	var b strings.Builder
+	b.WriteString("# Roadmap\n\n- Phases go under `<!-- PHASES START -->`.\n\n## Phases\n\n<!-- PHASES START -->\n\n")
+	b.WriteString("## Archived phases (1–1)\n- Phase 1 — Start: Began.\n")
+	for n := 2; n < completed+2; n++ {
+		fmt.Fprintf(&b, "\n## Phase %d ✅ — Done %d\n- Shipped %d.\n", n, n, n)
+	}
+	fmt.Fprintf(&b, "\n## Phase %d — Next\n\n### Goal\n- Go.\n\n### Tasks\n- [x] first: One.\n- [ ] second: Two.\n\n### Exit criteria\n- Done.\n", completed+2)
+	return b.String()
+}
+
+func TestRoadmapRoundTripAndStatus(t *testing.T) {
+	root := t.TempDir()
+	fixture := roadmapFixture(2)
+	path := writeMemoryFile(t, root, RoadmapFile, fixture)
+
+	phases, err := RoadmapStatus(root)
+	if err != nil {
+		t.Fatalf("RoadmapStatus: %v", err)
+	}
+	want := []Phase{
+		{Number: 1, Name: "Start", Complete: true, Archived: true},
+		{Number: 2, Name: "Done 2", Complete: true},
+		{Number: 3, Name: "Done 3", Complete: true},
+		{Number: 4, Name: "Next", TasksDone: 1, TasksTotal: 2},
+	}
+	if fmt.Sprint(phases) != fmt.Sprint(want) {
+		t.Fatalf("phases = %+v", phases)
+	}
+
+	phase, err := AddPhase(AddPhaseOptions{Root: root, Name: "Later", Goals: []string{"Win."}, Tasks: []string{"a: Do a."}, Exit: []string{"A done."}})
+	if err != nil || phase.Number != 5 {
+		t.Fatalf("AddPhase = %+v, %v", phase, err)
+	}
+	data, _ := os.ReadFile(path)
+	wantTail := fixture + "\n## Phase 5 — Later\n\n### Goal\n- Win.\n\n### Tasks\n- [ ] a: Do a.\n\n### Exit criteria\n- A done.\n"
+	if string(data) != wantTail {
+		t.Fatalf("AddPhase rewrote more than the new phase:\n%s", data)
+	}
+	if _, err := AddPhase(AddPhaseOptions{Root: root, Name: "Empty", Goals: []string{"g"}}); err == nil {
+		t.Fatal("phase without tasks and exit criteria must fail")
+	}
+}
+
+func TestCompletePhase(t *testing.T) {
+	root := t.TempDir()
+	path := writeMemoryFile(t, root, RoadmapFile, roadmapFixture(MaxCompletedPhases))
+	next := MaxCompletedPhases + 2
+
+	if _, _, err := CompletePhase(CompletePhaseOptions{Root: root, Number: next}); err == nil || !strings.Contains(err.Error(), "1 unchecked") {
+		t.Fatalf("open tasks = %v", err)
+	}
+	if _, _, err := CompletePhase(CompletePhaseOptions{Root: root, Number: 2}); err == nil {
+		t.Fatal("completing a completed phase must fail")
+	}
+	if _, _, err := CompletePhase(CompletePhaseOptions{Root: root, Number: 1}); err == nil {
+		t.Fatal("completing an archived phase must fail")
+	}
+
+	phase, archived, err := CompletePhase(CompletePhaseOptions{Root: root, Number: next, Force: true})
+	if err != nil || !phase.Complete {
+		t.Fatalf("CompletePhase = %+v, %v", phase, err)
+	}
+	if len(archived) != 1 || archived[0] != 2 {
+		t.Fatalf("archived = %v", archived)
+	}
+	data, _ := os.ReadFile(path)
+	for _, want := range []string{
+		"## Archived phases (1–2)\n- Phase 1 — Start: Began.\n- Phase 2 — Done 2: Shipped 2.\n\n## Phase 3 ✅",
+		fmt.Sprintf("## Phase %d ✅ — Next\n- first: One.\n- second: Two.\n", next),
+	} {
+		if !strings.Contains(string(data), want) {
+			t.Fatalf("missing %q in:\n%s", want, data)
+		}
+	}
+	if strings.Contains(string(data), "## Phase 2 ✅") || strings.Contains(string(data), "### Goal") {
+		t.Fatalf("completed content not replaced:\n%s", data)
+	}
+}
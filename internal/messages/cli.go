@@ -5,10 +5,19 @@ const (
 	// RootUse is the CLI command name.
 	RootUse = "al"
 	// RootShort is the short description for the root command.
-	RootShort             = "Agent Layer CLI"
-	RootVersionFlag       = "Print version and exit"
-	RootQuietFlag         = "Suppress agent-layer informational output"
-	RootMissingAgentLayer = "agent layer isn't initialized in this repository (missing .agent-layer); run 'al init' to initialize"
+	RootShort           = "Agent Layer CLI"
+	RootVersionFlag     = "Print version and exit"
+	RootQuietFlag       = "Suppress agent-layer informational output"
+	RootVerboseFlag     = "Print per-step detail and progress summaries"
+	RootErrorFormatFlag = "Failure output format: text or json"
+	RootOfflineFlag     = "Refuse every operation that needs the network (same as AL_OFFLINE=1)"
+	RootColorFlag       = "Color output: auto, always, or never (auto honors NO_COLOR and colors only a terminal)"
+	RootCIFlag          = "Never prompt, print plain output, and exit with a code per failure class (same as AL_CI=1)"
+	// RootErrorFormatInvalidFmt reports an unsupported --error-format value.
+	RootErrorFormatInvalidFmt = "invalid value for --error-format: %q (must be text or json)"
+	OfflineInvalidFmt         = "invalid value for --offline: %q (must be true or false)"
+	CIInvalidFmt              = "invalid value for --ci: %q (must be true or false)"
+	RootMissingAgentLayer     = "agent layer isn't initialized in this repository (missing .agent-layer); run 'al init' to initialize"
 
 	// VersionCommitFmt formats the commit hash for version display.
 	VersionCommitFmt  = "commit %s"
@@ -51,6 +60,20 @@ const (
 	UpgradeRollbackFlagList               = "List available upgrade snapshots"
 	UpgradeRollbackListHeader             = "Available upgrade snapshots (newest first):"
 	UpgradeRollbackNoSnapshots            = "No upgrade snapshots found."
+	UpgradeHistoryUse                     = "history"
+	UpgradeHistoryShort                   = "List recorded upgrades and what each changed"
+	UpgradeHistoryJSONFlag                = "Print one JSON record per upgrade"
+	UpgradeHistoryNone                    = "No recorded upgrades found."
+	UpgradeHistoryListHeader              = "Recorded upgrades (newest first):"
+	UpgradeHistoryListEntryFmt            = "  - %s (%s, %s): %d added, %d updated, %d removed, %d migrations applied\n"
+	UpgradeShowUse                        = "show <upgrade-id>"
+	UpgradeShowShort                      = "Show the migration report and file changes of a recorded upgrade"
+	UpgradeShowRequiresID                 = "show requires an upgrade id: `al upgrade show <upgrade-id>` (list ids with `al upgrade history`)"
+	UpgradeShowJSONFlag                   = "Print the recorded upgrade as JSON"
+	UpgradeShowHeaderFmt                  = "Upgrade %s (%s, %s)"
+	UpgradeShowSectionAdded               = "Files added"
+	UpgradeShowSectionUpdated             = "Files updated"
+	UpgradeShowSectionRemoved             = "Files removed"
 	UpgradeRequiresTerminal               = "upgrade prompts require an interactive terminal; re-run `al upgrade` in a terminal, or run non-interactively with `--yes` and one or more apply flags"
 	UpgradeNonInteractiveRequiresYesApply = "non-interactive upgrade requires `--yes` and one or more apply flags: `--apply-managed-updates`, `--apply-memory-updates`, `--apply-deletions`, `--apply-tmp-deletions`"
 	UpgradeYesRequiresApply               = "`--yes` requires one or more apply flags: `--apply-managed-updates`, `--apply-memory-updates`, `--apply-deletions`, `--apply-tmp-deletions`"
@@ -71,6 +94,8 @@ const (
 	UpgradeViewDiffPrompt                           = "View the full diff?"
 	UpgradeDeleteUnknownAllPrompt                   = "Delete all unknown files found during upgrade scan (excludes .agent-layer/tmp/, which is prompted separately)?"
 	UpgradeDeleteUnknownPromptFmt                   = "Delete %s?"
+	UpgradeDeleteOrphanedHeader                     = "Files from earlier Agent Layer releases that are no longer generated:"
+	UpgradeDeleteOrphanedAllPrompt                  = "Delete all files left behind by earlier Agent Layer releases?"
 	UpgradeDeleteUnknownTmpAllPromptFmt             = "Delete all %d file(s) under .agent-layer/tmp/?"
 	UpgradeDeleteUnknownTmpHeader                   = "Files under .agent-layer/tmp/:"
 	UpgradeDeleteUnknownTmpDestructiveConfirmPrompt = "DESTRUCTIVE: deleting .agent-layer/tmp/ permanently removes ephemeral agent run artifacts and may impact ongoing work. Are you absolutely sure?"
@@ -100,6 +125,78 @@ const (
 	UpgradeNumberedChoiceInvalidFmt = "invalid choice %q"
 	UpgradeNumberedChoiceRetryFmt   = "Invalid choice. Enter a number between 1 and %d.\n"
 
+	// Typed-in config overrides, validated against the field catalog.
+	UpgradeConfigCustomValueOption = "Enter a custom value"
+	UpgradeConfigEnterValueFmt     = "Enter value for %s: "
+	UpgradeConfigInvalidValueFmt   = "Invalid value: %v\n"
+
+	// Source-version selection when manifest fingerprinting is inconclusive.
+	UpgradeSourceVersionHeader    = "\nCould not tell which release this repo was last upgraded from, so version-specific migrations would be skipped.\nClosest matching releases by managed file content:"
+	UpgradeSourceVersionOptionFmt = "v%s (%d of %d present managed files match)"
+	UpgradeSourceVersionUnknown   = "None of these (keep the source unknown and run only version-independent migrations)"
+
+	// Upgrade risk summary and confirmation (interactive and --max-risk upgrades).
+	UpgradeFlagMaxRisk                = "Apply planned changes up to this risk level without prompts (safe, config, overwrite, destructive); higher-risk groups are skipped or fail when they cannot be skipped"
+	UpgradeMaxRiskConflictsApplyFlags = "`--max-risk` cannot be combined with `--apply-managed-updates`, `--apply-memory-updates`, `--apply-deletions`, or `--apply-tmp-deletions`"
+	UpgradeRiskSummaryHeader          = "Planned changes by risk:"
+	UpgradeRiskGroupFmt               = "  [%s] %s (%d)"
+	UpgradeRiskItemFmt                = "    - %s (%s)\n"
+	UpgradeRiskApplyPromptFmt         = "Apply %s changes?"
+	UpgradeRiskTypedPromptFmt         = "Type '%s' to apply the %s changes %s: "
+	UpgradeRiskTypedSkipHint          = "(press Enter to skip them)"
+	UpgradeRiskTypedCancelHint        = "(press Enter to cancel the upgrade)"
+	UpgradeRiskTypedRetryFmt          = "Type '%s' exactly, or press Enter.\n"
+	UpgradeRiskDeclinedFmt            = "Info: skipping %s changes.\n"
+	UpgradeRiskSkippedFmt             = "Info: skipping %s changes (above --max-risk %s).\n"
+	UpgradeRiskExceedsMaxFmt          = "plan contains %s changes (risk %s) above --max-risk %s that cannot be skipped; re-run with --max-risk %s or interactively"
+	UpgradeRiskCancelled              = "upgrade cancelled; no files were changed"
+	UpgradePlanSectionRisk            = "Risk summary"
+	UpgradePlanRiskItemFmt            = "  - [%s] %s: %d change(s)\n"
+
+	// Git working-tree guard (interactive and --allow-dirty upgrades).
+	UpgradeFlagAllowDirty            = "Upgrade even when .agent-layer/ or generated paths have uncommitted git changes"
+	UpgradeDirtyHeader               = "Uncommitted changes in paths this upgrade may rewrite:"
+	UpgradeDirtyPathFmt              = "  - %s\n"
+	UpgradeDirtyMoreFmt              = "  ... and %d more\n"
+	UpgradeDirtyStashOption          = "Stash them (restore later with `git stash pop`)"
+	UpgradeDirtyCommitOption         = "Commit them as a checkpoint"
+	UpgradeDirtyCancelOption         = "Cancel the upgrade"
+	UpgradeDirtyRefusedFmt           = "%d path(s) this upgrade may rewrite have uncommitted git changes (first: %s); commit or stash them, or re-run with --allow-dirty"
+	UpgradeDirtyCheckpointMessageFmt = "Checkpoint before Agent Layer upgrade to %s"
+	UpgradeDirtyStashedFmt           = "Info: stashed %d path(s); run `git stash pop` after reviewing the upgrade.\n"
+	UpgradeDirtyCommittedFmt         = "Info: committed %d path(s) as a checkpoint.\n"
+
+	// Post-upgrade verification ([[upgrade.verify]] and --auto-rollback).
+	UpgradeFlagAutoRollback        = "Restore the upgrade snapshot if the post-upgrade sync, sync check, or [[upgrade.verify]] commands fail"
+	UpgradeVerifyCheckingSync      = "Checking generated outputs..."
+	UpgradeVerifyRunningFmt        = "Running verification: %s\n"
+	UpgradeVerifyCommandFailedFmt  = "verification command %q failed: %w"
+	UpgradeVerifyFailedFmt         = "upgrade verification failed: %w; restore the previous state with `al upgrade rollback %s`"
+	UpgradeVerifyNoSnapshotFmt     = "upgrade verification failed: %w; no applied upgrade snapshot was found to roll back to"
+	UpgradeVerifyRollingBackFmt    = "Verification failed; restoring snapshot %s...\n"
+	UpgradeVerifyRollbackFailedFmt = "upgrade verification failed: %w; rollback to snapshot %s also failed: %v"
+	UpgradeVerifyRolledBackFmt     = "upgrade verification failed and was rolled back to snapshot %s: %w"
+	UpgradeVerifyResyncFailedFmt   = "Warning: restored snapshot %s, but regenerating client outputs failed: %v (run `al sync` to retry)\n"
+
+	// Interrupted init or upgrade recorded in the install journal.
+	UpgradeFlagResume                = "Run the upgrade even though an earlier init or upgrade stopped partway"
+	UpgradeInterruptedFmt            = "An earlier al %s stopped during %s (started %s).\n"
+	UpgradeInterruptedResumeOption   = "Resume: run the upgrade again over the partly upgraded files"
+	UpgradeInterruptedRollbackOption = "Roll back to snapshot %s, taken before it started"
+	UpgradeInterruptedCancelOption   = "Cancel"
+
+	// Upgrade operation filters (--only and --skip).
+	UpgradeFlagOnly = "Run only this migration operation ID or template path (repeatable); everything else is reported as skipped"
+	UpgradeFlagSkip = "Skip this migration operation ID or template path (repeatable) and report it as skipped"
+
+	// Upgrade answer files (--answers or AL_UPGRADE_ANSWERS).
+	UpgradeFlagAnswers           = "YAML or JSON file answering every upgrade prompt, for upgrades without a terminal (defaults to $AL_UPGRADE_ANSWERS)"
+	UpgradeAnswersConflictsFlags = "an answer file (`--answers` or AL_UPGRADE_ANSWERS) cannot be combined with `--yes`, `--max-risk`, or apply flags; set `apply` in the answer file instead"
+	UpgradeAnswersReadFmt        = "failed to read upgrade answer file %s: %w"
+	UpgradeAnswersParseFmt       = "invalid upgrade answer file %s: %w"
+	UpgradeAnswersBoolFmt        = "upgrade answer for %s must be true or false, got %v"
+	UpgradeAnswersEnumFmt        = "upgrade answer for %s must be one of %s, got %v"
+
 	// Statusline-source review header (interactive upgrade).
 	UpgradeStatuslineSourceDiffHeader = "User-owned statusline source that differs from the template:"
 
@@ -111,6 +208,7 @@ const (
 	UpgradePlanSectionStatuslineToReview   = "Statusline source files to review"
 	UpgradePlanSectionFilesToRename        = "Files to rename"
 	UpgradePlanSectionFilesToReviewRemoval = "Files to review for removal"
+	UpgradePlanSectionOptedOut             = "Opted out (user-owned in config.toml [ownership])"
 	UpgradePlanSectionConfigUpdates        = "Config updates"
 	UpgradePlanSectionMigrations           = "Migrations"
 	UpgradePlanSectionTitleFmt             = "\n%s:\n"
@@ -123,6 +221,7 @@ const (
 	UpgradePlanMigrationSourceNoteFmt      = "  - source note: %s\n"
 	UpgradePlanMigrationEntryFmt           = "  - [%s] %s (%s): %s\n"
 	UpgradePlanMigrationReasonFmt          = "    reason: %s\n"
+	UpgradePlanMigrationSkippedPathFmt     = "  - [skipped_by_user] template update %s\n"
 	UpgradePlanMigrationBreakingNoticeFmt  = "    BREAKING CHANGE: %s"
 	UpgradePlanMigrationBreakingDetailFmt  = "    %s"
 	UpgradePlanMigrationBreakingRunHint    = "    Run 'al upgrade' to confirm and apply the migration."
@@ -190,10 +289,12 @@ const (
 	InitWarnUpdateAvailableFmt = "Warning: agent-layer update available: %s (current %s)\n\n" + UpdateUpgradeBlock + "\n\n" + UpdateSafetyBlock + "\n\n" + UpdateSilenceBlock + "\n"
 
 	// CompletionUse is the completion command usage.
-	CompletionUse                 = "completion [bash|zsh|fish]"
+	CompletionUse                 = "completion [bash|zsh|fish|powershell]"
 	CompletionShort               = "Generate shell completion scripts"
 	CompletionInstall             = "Install the completion script for the specified shell"
-	CompletionUnsupportedShellFmt = "unsupported shell %q (supported: bash, zsh, fish)"
+	CompletionUnsupportedShellFmt = "unsupported shell %q (supported: bash, zsh, fish, powershell)"
+	// CompletionPowerShellInstallUnsupported explains how to load PowerShell completions without --install.
+	CompletionPowerShellInstallUnsupported = "--install is not supported for powershell; add `al completion powershell | Out-String | Invoke-Expression` to your $PROFILE"
 
 	CompletionCreateDirErrFmt   = "create completion dir: %w"
 	CompletionWriteFileErrFmt   = "write completion file: %w"
@@ -242,6 +343,16 @@ const (
 	NoSyncInvalidFmt = "invalid value for --no-sync: %q"
 	QuietInvalidFmt  = "invalid value for --quiet: %q"
 
+	ClientsOutputsStaleFmt        = "Warning: generated outputs may be stale (%s); run `al sync` to regenerate them.\n"
+	ClientsStaleNeverSynced       = "no sync recorded"
+	ClientsStaleVersionFmt        = "last synced by Agent Layer %s"
+	ClientsStaleSourcesFmt        = "changed since the last sync: %s"
+	ClientsLaunchEnvFmt           = "clients.%s.launch.env.%s: %w"
+	ClientsPreLaunchRunningFmt    = "Running pre_launch hook: %s\n"
+	ClientsPreLaunchNotAllowedFmt = "clients.%s.launch.pre_launch[%d] %q cannot run: %s; add it to .agent-layer/commands.allow"
+	ClientsPreLaunchFailedFmt     = "pre_launch hook %q failed: %w"
+	ClientsPreLaunchInvalidFmt    = "clients.%s.launch.pre_launch[%d] %q is not a valid command line: %w"
+
 	ProbeUse                       = "probe"
 	ProbeShort                     = "Run client capability probes"
 	ProbeLong                      = "Run a client capability probe and emit JSON. Probes confirm what a client actually does at runtime (permissions, MCP, instruction/skill visibility) so Agent Layer can detect upstream behavior drift."
@@ -271,6 +382,358 @@ const (
 	CopilotUse   = "copilot"
 	CopilotShort = "Sync and launch GitHub Copilot CLI"
 
+	AddUse               = "add"
+	AddShort             = "Fetch skills from git sources into .agent-layer"
+	AddSkillUse          = "skill <source>"
+	AddSkillShort        = "Download a skill bundle into .agent-layer/skills and record it in al.lock"
+	AddSkillLong         = "Download a skill bundle (SKILL.md plus resources) and install it under .agent-layer/skills/<name>.\n\nThe source is a git reference such as github.com/org/skills/review@v1, a git URL such as https://github.com/org/review.git@v1, or a bare registry name resolved against AL_SKILL_REGISTRY. The resolved source and content checksum are recorded in .agent-layer/al.lock so `al update` can refresh the skill later."
+	AddSkillFlagForce    = "Replace an existing skill even if it has local edits or was not added with `al add skill`"
+	AddSkillResultFmt    = "Skill %s %s from %s\n"
+	AddSkillSyncHint     = "Run `al sync` to project skills to enabled clients."
+	UpdateUse            = "update [skill...]"
+	UpdateShort          = "Refetch skills recorded in al.lock"
+	UpdateLong           = "Refetch skills added with `al add skill` (all of them, or only the named skills) and reinstall any whose upstream content changed. Skills edited locally since they were fetched are skipped unless --force is set."
+	UpdateFlagForce      = "Overwrite local edits to fetched skills"
+	UpdateNothingLocked  = "No fetched skills are recorded in .agent-layer/al.lock."
+	UpdateResultFmt      = "  %s: %s (%s)\n"
+	UpdateSkippedHint    = "Skipped skills have local edits; pass --force to overwrite them."
+	SkillFetchWarningFmt = "Warning: skill %s: %s\n"
+
+	ExportConfigUse         = "export-config <bundle.tar.gz>"
+	ExportConfigShort       = "Export .agent-layer configuration to a single archive"
+	ExportConfigLong        = "Write config.toml, instructions, skills, scoped instructions, commands.allow, statusline sources, gitignore.block, al.version, and al.lock to a gzip-compressed tar archive (.tar.gz or .tgz) with a manifest of checksums and the al version. Secrets (.env), state, and temporary files are never included."
+	ExportConfigResultFmt   = "Exported %d files to %s (al %s)\n"
+	ImportConfigUse         = "import-config <bundle.tar.gz>"
+	ImportConfigShort       = "Import .agent-layer configuration from an archive"
+	ImportConfigLong        = "Verify every file in an archive written by `al export-config` against its manifest, then install it into .agent-layer/. Files are staged under .agent-layer/tmp/ and moved into place, so a failed import leaves the local configuration intact. With --force, the bundled files replace the local configuration exactly; .env, state, and temporary files are kept, and a missing .env is created from the template."
+	ImportConfigFlagForce   = "Replace an existing .agent-layer configuration"
+	ImportConfigResultFmt   = "Imported %d files from %s (exported by al %s at %s)\n"
+	ImportConfigVersionNote = "Note: the bundle was exported by al %s; this is al %s. Run `al upgrade plan` if the configuration needs migrating.\n"
+	ImportConfigSyncHint    = "Run `al sync` to regenerate client configs."
+
+	ExportUse                = "export"
+	ExportShort              = "Snapshot the whole agent environment to a single archive"
+	ExportLong               = "Write a gzip-compressed tar archive holding everything `al export-config` exports under .agent-layer/, the resolved config (resolved/config.toml, after defaults and extends), the composed instructions (resolved/instructions.md), and every client file `al sync` generates, under outputs/. Outputs are rendered in a scratch copy, so the repo is not modified. By default .env is left out and secrets appear in outputs as ${KEY} placeholders; --include-secrets adds .env and renders its real values. The manifest records checksums, the al version, and whether secrets are included. Use `al export-config` to move a configuration to another repo."
+	ExportFlagOutput         = "Archive to write (.tar.gz)"
+	ExportFlagIncludeSecrets = "Include .agent-layer/.env and render outputs with its real values"
+	ExportResultFmt          = "Exported %d files to %s (al %s)\n"
+	ExportSecretsWarningFmt  = "Warning: %s contains secrets from .agent-layer/.env; do not share it.\n"
+
+	CleanUse                   = "clean"
+	CleanShort                 = "Remove generated client outputs and disposable state"
+	CleanLong                  = "List what would be removed and why, then remove it after confirmation. --generated (the default) covers client files `al sync` writes: the current sources are rendered in a scratch copy, and only paths that render produces are candidates. A generated file is removed when it is exactly what sync wrote; files edited by hand or that differ from what sync would write now are kept unless --force is set. Files sync patches in place (.gitignore, .claude/settings.json, .codex/config.toml, .agy/antigravity-cli/settings.json, .vscode/settings.json) are always kept. --state covers upgrade snapshots, the dispatch capability cache, and .agent-layer/tmp/; the upgrade baseline, audit log, dispatch run records, and unrecognized state are kept. --all covers both. Run `al sync` to regenerate outputs."
+	CleanFlagGenerated         = "Remove generated client outputs (default)"
+	CleanFlagState             = "Remove upgrade snapshots, caches, and scratch directories under .agent-layer/"
+	CleanFlagAll               = "Remove generated outputs and state"
+	CleanFlagDryRun            = "List what would be removed without removing anything"
+	CleanFlagYes               = "Remove without asking for confirmation"
+	CleanFlagForce             = "Also remove generated files edited by hand or out of date"
+	CleanRemoveHeader          = "Will remove:"
+	CleanKeepHeader            = "Keeping:"
+	CleanEntryFmt              = "  %s (%s)\n"
+	CleanNothing               = "Nothing to clean."
+	CleanConfirmPrompt         = "Remove these paths?"
+	CleanCancelled             = "Nothing removed."
+	CleanNeedsYes              = "al clean needs confirmation; rerun with --yes to remove the listed paths, or --dry-run to only list them"
+	CleanResultFmt             = "Removed %d paths.\n"
+	CleanSyncHint              = "Run `al sync` to regenerate client outputs."
+	CleanReasonGenerated       = "generated by al sync"
+	CleanReasonForced          = "generated path, removed with --force"
+	CleanReasonEdited          = "edited by hand; --force removes it"
+	CleanReasonDiffers         = "differs from what al sync writes now; --force removes it"
+	CleanReasonSharedState     = "patched in place and holds your own settings"
+	CleanReasonSnapshots       = "upgrade snapshots; rollback points are lost"
+	CleanReasonDispatchCache   = "dispatch capability cache"
+	CleanReasonScratch         = "scratch files"
+	CleanReasonBaseline        = "upgrade baseline; al upgrade needs it"
+	CleanReasonClaudeKeys      = "keys al sync manages in .claude/settings.json"
+	CleanReasonDispatchRuns    = "dispatch run records"
+	CleanReasonAudit           = "audit log"
+	CleanReasonUpgradeHistory  = "upgrade history records"
+	CleanReasonRendererOutputs = "outputs of registered renderers; al sync removes stale ones with it"
+	CleanReasonStateLock       = "state lock held while al writes to the repo"
+	CleanReasonServeToken      = "bearer token of a running al serve"
+	CleanReasonUnknownState    = "not recognized by al clean"
+
+	UninstallUse               = "uninstall"
+	UninstallShort             = "Remove Agent Layer's generated files, state, and managed .gitignore block"
+	UninstallLong              = "List everything Agent Layer added to the repo and why, then remove it after confirmation. Generated client outputs are found by rendering the current sources in a scratch copy, exactly like `al clean`: files sync wrote unchanged are removed, while files edited by hand or out of date are kept unless --force is set, Settings files sync patches in place lose the entries Agent Layer manages: the recorded keys in .claude/settings.json, the managed entries and this repo's project trust in .codex/config.toml, and the managed block in .vscode/settings.json; a file left with nothing else is removed. .agy/antigravity-cli/settings.json is kept because Agent Layer keeps no record of the keys it wrote there. All of .agent-layer/state/ and .agent-layer/tmp/ are removed, and the managed block is stripped from .gitignore. --remove-layer also removes .agent-layer/ (config, instructions, skills, and secrets in .env), keeping anything config.toml's [ownership] table marks as user-owned; when .agent-layer is a redirect file, only the file is removed and the directory it names is left for you to delete. Memory files in docs/agent-layer/ are always kept."
+	UninstallFlagRemoveLayer   = "Also remove the .agent-layer/ directory"
+	UninstallFlagDryRun        = "List what would be removed without removing anything"
+	UninstallFlagYes           = "Remove without asking for confirmation"
+	UninstallFlagForce         = "Also remove generated files edited by hand or out of date"
+	UninstallNothing           = "Nothing to uninstall."
+	UninstallConfirmPrompt     = "Remove these paths?"
+	UninstallNeedsYes          = "al uninstall needs confirmation; rerun with --yes to remove the listed paths, or --dry-run to only list them"
+	UninstallResultFmt         = "Removed %d paths.\n"
+	UninstallReasonGitignore   = "managed agent-layer block; the rest of the file is kept"
+	UninstallReasonSettings    = "managed entries are stripped; the file is removed if nothing else is left"
+	UninstallStripFmt          = "strip managed settings from %s: %w"
+	UninstallReasonState       = "Agent Layer runtime state"
+	UninstallReasonLayer       = "Agent Layer configuration and sources"
+	UninstallReasonUserOwned   = "holds [ownership] user-owned paths"
+	UninstallReasonRedirectFmt = "redirect file; %s is kept, delete it yourself if no other repo uses it"
+	UninstallReasonMemory      = "project memory; delete it yourself if unwanted"
+	UninstallLayerHint         = "Sources in .agent-layer/ were kept; rerun with --remove-layer to remove them too."
+
+	BaselineUse                      = "baseline"
+	BaselineShort                    = "Inspect and repair the managed template baseline"
+	BaselineRebuildUse               = "rebuild"
+	BaselineRebuildShort             = "Reconstruct .agent-layer/state/managed-baseline.json from an embedded release manifest"
+	BaselineRebuildLong              = "Rewrite .agent-layer/state/managed-baseline.json when it is missing or corrupted. Each release manifest embedded in this binary is scored by how many managed files in the repo match it exactly; the best match becomes the baseline. When several releases match equally well, the pinned version in .agent-layer/al.version wins if it is one of them, otherwise you are asked to choose (non-interactive runs fail and list the candidates). --assume-version skips matching and uses that release's manifest. The rebuilt baseline records that it was rebuilt, how it was chosen, and the state it replaced. No managed files are changed."
+	BaselineRebuildAssumeVersionFlag = "Rebuild from this release's manifest instead of matching (X.Y.Z)"
+	BaselineRebuildAmbiguousHeader   = "Managed files match several releases equally well. Which release was this repo last installed or upgraded from?"
+	BaselineRebuildCandidateFmt      = "v%s (%d of %d present managed files match)"
+	BaselineRebuildMatchedFmt        = "Rebuilt managed baseline from v%s: %d of %d present managed files match; %d files recorded (previous baseline: %s).\n"
+	BaselineRebuildAssumedFmt        = "Rebuilt managed baseline from v%s (assumed version); %d files recorded (previous baseline: %s).\n"
+
+	ImportUse            = "import"
+	ImportShort          = "Create .agent-layer configuration from a client's existing setup"
+	ImportLong           = "Read a client's repo-local configuration and merge it into .agent-layer/, creating .agent-layer/ first when the repo has none. Instructions become .agent-layer/instructions/90_imported_<name>.md, MCP servers become [[mcp.servers]] entries in config.toml, and the client's agent is enabled with its model when it has one. Literal env and header values move to .agent-layer/.env; ${VAR} references are renamed to ${AL_VAR}. Existing instruction files, servers, and models are kept, so re-running is safe.\n\nSources: claude reads CLAUDE.md, .claude/CLAUDE.md, .mcp.json, and .claude/settings.json; codex reads AGENTS.md and .codex/config.toml; gemini reads GEMINI.md and .gemini/settings.json and enables antigravity; cursor reads .cursorrules, .cursor/rules/, and .cursor/mcp.json. Files generated by `al sync` are skipped."
+	ImportFlagFrom       = "Client to import from (claude, codex, gemini, cursor)"
+	ImportInitializedFmt = "Initialized .agent-layer/ in %s\n"
+	ImportItemFmt        = "%-7s %s (from %s)\n"
+	ImportDisabledFmt    = "%-7s %s (from %s; disabled until its .env values are set)\n"
+	ImportSecretsFmt     = "Moved literal values into .agent-layer/.env: %s\n"
+	ImportMissingEnvFmt  = "Set these in .agent-layer/.env, then enable the servers that use them: %s\n"
+	ImportAgentFmt       = "Enabled agents.%s.\n"
+	ImportSyncHint       = "Review the changes, then run `al sync` to regenerate client configs. Sync replaces CLAUDE.md, AGENTS.md, and other generated files with output built from .agent-layer/."
+
+	DiffUse             = "diff"
+	DiffShort           = "Preview how generated client outputs differ under another version or commit"
+	DiffLong            = "Render the generated client outputs twice in scratch directories and print a unified diff of every file that differs. --against takes a release version (X.Y.Z or vX.Y.Z), which renders the working tree's .agent-layer/ with that release, or a git ref, which renders the .agent-layer/ committed at that ref with this binary. The repo itself is not modified. To diff against a tag that looks like a version, pass refs/tags/<tag>."
+	DiffAgainstFlag     = "Release version (X.Y.Z) or git ref to compare the working tree against"
+	DiffNoChangesFmt    = "No differences in generated outputs against %s.\n"
+	DiffSummaryFmt      = "%d generated file(s) differ against %s.\n"
+	DiffLabelAgainstFmt = "%s (%s)"
+	DiffLabelCurrentFmt = "%s (working tree)"
+
+	AuditUse           = "audit"
+	AuditShort         = "Review command and tool approval decisions"
+	AuditShowUse       = "show"
+	AuditShowShort     = "Print the decisions recorded in .agent-layer/state/audit.jsonl"
+	AuditShowLong      = "Print every allow and deny decision recorded in .agent-layer/state/audit.jsonl, oldest first: commands run through `al exec` and MCP tools forwarded or hidden by `al mcp gateway`. Each line shows the time, source, decision, client, command or tool, and the rule that matched. --since limits output to recent entries and takes a duration (90m, 24h), a number of days (7d), a date (2026-01-02), or a timestamp."
+	AuditShowSinceFlag = "Only show decisions recorded after this time (duration, days like 7d, date, or timestamp)"
+	AuditShowJSONFlag  = "Print matching entries as JSON lines"
+	AuditShowNone      = "No audit entries recorded."
+	AuditShowEntryFmt  = "%s  %-11s %-7s  %-8s %s%s\n"
+	AuditShowRuleFmt   = " (%s)"
+
+	ExecUse            = "exec -- <command> [args...]"
+	ExecShort          = "Run a command through commands.allow, commands.deny, and approvals.mode"
+	ExecLong           = "Check the command against .agent-layer/commands.deny and commands.allow and the configured approvals.mode, record the decision in .agent-layer/state/audit.jsonl, and run the command when it is permitted. A commands.deny prefix always blocks the command. Otherwise approvals.mode yolo runs anything, all and commands run commands.allow matches, and every other command needs confirmation at an interactive prompt; without a terminal it is refused. The command's exit code is passed through. --restricted runs it with only PATH, HOME, locale, and terminal variables, so project secrets and credentials in the environment are not inherited."
+	ExecClientFlag     = "Client name recorded in the audit log (for example claude or codex)"
+	ExecRestrictedFlag = "Run the command with a minimal environment"
+	ExecConfirmFmt     = "Run %q (%s)? [y/N]: "
+	ExecDeniedFmt      = "al exec refused %q: %s"
+	ExecDeniedRuleFmt  = "al exec refused %q: %s (%s)"
+	ExecRunFailedFmt   = "failed to run %s: %w"
+
+	DevcontainerUse           = "devcontainer"
+	DevcontainerShort         = "Set up Agent Layer in devcontainers and Codespaces"
+	DevcontainerGenerateUse   = "generate"
+	DevcontainerGenerateShort = "Write a devcontainer feature that installs the pinned al and runs al sync"
+	DevcontainerGenerateLong  = "Write .devcontainer/agent-layer/, a local devcontainer feature that installs the al version pinned in .agent-layer/al.version (or this binary's version when nothing is pinned) and runs al sync when the container is created. Prints the devcontainer.json fragment that enables the feature and exports the environment al would set when launching clients, such as CODEX_HOME when agents.codex.local_config_dir is true. Rerun after changing the pin or those settings."
+	DevcontainerNoVersion     = "no al version to install: pin one in .agent-layer/al.version (al upgrade does this) or run a release build of al"
+	DevcontainerResultFmt     = "Wrote %s (al %s).\n"
+	DevcontainerFragmentIntro = "Merge this into .devcontainer/devcontainer.json:"
+
+	ServeUse          = "serve"
+	ServeShort        = "Serve a local JSON API for sync, status, config, and the MCP gateway"
+	ServeLong         = "Run until interrupted, serving a versioned JSON API for this repo on a loopback address or Unix socket: GET /v1/health, POST /v1/sync (reload config and regenerate outputs), GET /v1/status (start each enabled MCP server briefly and report it), GET /v1/config (resolved config.toml), GET /v1/env (variables each al <client> launcher sets), GET /v1/upgrade-plan (the al upgrade plan as JSON), and GET /v1/skills. Unless --gateway=false, the MCP gateway is served at /mcp over streamable HTTP; its servers start once, so restart al serve after changing [mcp]. Over TCP, every request must send the per-run token written to .agent-layer/state/serve-token (owner-only) as Authorization: Bearer <token>; a unix: socket is owner-only and needs no token. al serve never listens on other hosts."
+	ServeListenFlag   = "Address to listen on: host:port on a loopback address, or unix:<path>"
+	ServeGatewayFlag  = "Serve the MCP gateway at /mcp"
+	ServeListeningFmt = "al serve listening on %s\n"
+	ServeTokenFmt     = "Send the token in %s as Authorization: Bearer <token>; it changes every run.\n"
+
+	EnvUse        = "env"
+	EnvShort      = "Show which al binary, pin, and state files this repo resolves to"
+	EnvLong       = "Print how al resolves in the current directory: the repo root, the running binary's version and path, the version pinned in .agent-layer/al.version, the dispatch decision (whether this binary runs the command or a cached release does, and which one), the template version recorded in the managed baseline, and the state paths under .agent-layer/state/ and the version cache. al env always runs in the invoking binary and never downloads or dispatches, so it works even when the pin is broken or not cached. --json prints one JSON object for scripts."
+	EnvJSONFlag   = "Print the report as one JSON object"
+	EnvLineFmt    = "%-18s %s\n"
+	EnvNone       = "-"
+	EnvCachedNote = " (cached)"
+	EnvMissing    = " (not downloaded)"
+
+	PolicyUse            = "policy"
+	PolicyShort          = "Enforce content rules from .agent-layer/policy.toml"
+	PolicyCheckUse       = "check"
+	PolicyCheckShort     = "Check instructions and skills against .agent-layer/policy.toml"
+	PolicyCheckLong      = "Evaluate every rule in .agent-layer/policy.toml against .agent-layer/instructions/ and .agent-layer/skills/ and list each violation: banned phrases, missing instruction sections, skills over the line limit, and skills missing required front matter. Exits non-zero when any rule is violated, whatever sync.severity says, so CI can gate on it."
+	PolicyCheckNoPolicy  = "No .agent-layer/policy.toml; nothing to check."
+	PolicyCheckIssueFmt  = "%-21s %s: %s\n"
+	PolicyCheckCleanFmt  = "%s: no violations\n"
+	PolicyCheckFailedFmt = "policy check found %d violation(s)"
+
+	MemoryUse             = "memory"
+	MemoryShort           = "Add, list, and resolve entries in the memory files"
+	MemoryAddUse          = "add <issue|backlog|decision> <title>"
+	MemoryAddShort        = "Add an entry to ISSUES.md, BACKLOG.md, or DECISIONS.md"
+	MemoryAddLong         = "Add an entry below the <!-- ENTRIES START --> marker of docs/agent-layer/ISSUES.md, BACKLOG.md, or DECISIONS.md in the format the file documents. The entry gets today's date and a stable ID derived from its title (a numeric suffix keeps it unique within the file). Issues and backlog items are inserted newest first with --priority (default Medium), --area, --description (required), and --next-step or --acceptance; decisions are appended with --decision (required), --reason, and --tradeoffs. --notes applies to issues and backlog items."
+	MemoryAddedFmt        = "Added %s to %s\n"
+	MemoryFlagPriority    = "Priority for issues and backlog items: Critical, High, Medium, or Low"
+	MemoryFlagArea        = "Area for issues and backlog items"
+	MemoryFlagDescription = "Description for issues and backlog items"
+	MemoryFlagNextStep    = "Next step for issues"
+	MemoryFlagAcceptance  = "Acceptance criteria for backlog items"
+	MemoryFlagNotes       = "Notes for issues and backlog items"
+	MemoryFlagDecision    = "What was chosen, for decisions"
+	MemoryFlagReason      = "Why it was chosen, for decisions"
+	MemoryFlagTradeoffs   = "What is gained and lost, for decisions"
+	MemoryFlagType        = "Limit to one memory type: issue, backlog, or decision"
+	MemoryListUse         = "list"
+	MemoryListShort       = "List entries in the memory files"
+	MemoryListJSONFlag    = "Print the entries, with their fields, as a JSON array"
+	MemoryListEntryFmt    = "%-8s %s %s: %s\n"
+	MemoryListEmpty       = "No memory entries."
+	MemoryResolveUse      = "resolve <id>"
+	MemoryResolveShort    = "Remove a fixed issue, shipped backlog item, or superseded decision"
+	MemoryResolveLong     = "Remove the entry with the given ID from its memory file, as each file's format asks once an issue is fixed, a backlog item is implemented, or a decision no longer applies. Pass --type when the same ID appears in more than one file."
+	MemoryResolvedFmt     = "Resolved %s in %s\n"
+
+	RoadmapUse               = "roadmap"
+	RoadmapShort             = "Add, complete, and review phases in ROADMAP.md"
+	RoadmapStatusUse         = "status"
+	RoadmapStatusShort       = "List the phases in ROADMAP.md with task progress"
+	RoadmapStatusJSONFlag    = "Print the phases as a JSON array"
+	RoadmapStatusEmpty       = "ROADMAP.md has no phases."
+	RoadmapStatusArchivedFmt = "archived %d phase(s)\n"
+	RoadmapStatusOpen        = "open"
+	RoadmapStatusDone        = "done"
+	RoadmapStatusTasksFmt    = " (%d/%d tasks)"
+	RoadmapStatusPhaseFmt    = "%-8s Phase %d — %s%s\n"
+	RoadmapAddPhaseUse       = "add-phase <name>"
+	RoadmapAddPhaseShort     = "Append an incomplete phase to ROADMAP.md"
+	RoadmapAddPhaseLong      = "Append a phase below the <!-- PHASES START --> marker of docs/agent-layer/ROADMAP.md, numbered after the last phase, with the Goal, Tasks, and Exit criteria sections the file's format asks for. Pass each bullet as its own --goal, --task, or --exit flag; at least one of each is required."
+	RoadmapPhaseAddedFmt     = "Added Phase %d — %s\n"
+	RoadmapFlagGoal          = "Goal bullet (repeatable)"
+	RoadmapFlagTask          = "Task checkbox (repeatable)"
+	RoadmapFlagExit          = "Exit criterion bullet (repeatable)"
+	RoadmapCompleteUse       = "complete <phase>"
+	RoadmapCompleteShort     = "Mark a ROADMAP.md phase complete and archive old phases"
+	RoadmapCompleteLong      = "Mark the phase complete (## Phase N ✅ — <name>) and replace its content with summary bullets: one per --summary flag, or the phase's task lines when none are given. A phase with unchecked tasks is refused unless --force is set. Once more than five completed phases remain, the oldest are folded into the \"Archived phases\" section, one line each."
+	RoadmapFlagSummary       = "Accomplishment bullet for the completed phase (repeatable)"
+	RoadmapFlagForce         = "Complete the phase even if tasks are unchecked"
+	RoadmapPhaseCompletedFmt = "Completed Phase %d — %s\n"
+	RoadmapPhaseArchivedFmt  = "Archived Phase %d\n"
+	RoadmapInvalidPhaseFmt   = "invalid phase %q (expected a phase number such as 17)"
+
+	TranscriptsUse              = "transcripts"
+	TranscriptsShort            = "Collect client session logs into .agent-layer/transcripts/"
+	TranscriptsImportUse        = "import <client>"
+	TranscriptsImportShort      = "Normalize a client's session logs for this repo into JSONL"
+	TranscriptsImportLong       = "Read the session logs a client kept for this repo and write each session to .agent-layer/transcripts/<client>/<session>.jsonl in one format shared by all clients: one JSON object per message, tool call, or tool result. Supported clients: claude, codex, gemini. Logs are read from the client's environment override (CLAUDE_CONFIG_DIR, CODEX_HOME, GEMINI_CLI_HOME), its repo-local config directory, and its default home directory. Re-running rewrites only sessions that changed."
+	TranscriptsImportSessionFmt = "%-9s %s (%d records)\n"
+	TranscriptsImportNoneFmt    = "No %s sessions found for this repo.\n"
+	TranscriptsImportSummaryFmt = "Imported %d %s session(s) into %s (%d unchanged).\n"
+
+	VerifyUse        = "verify"
+	VerifyShort      = "Check the repo's Agent Layer state for drift"
+	VerifyLong       = "Check that the repo is consistent without writing anything: .agent-layer/al.version must match the release recorded in the managed baseline, each managed template file is compared with that release (files you customized or removed are reported, not failed), every generated client output must match what `al sync` renders from the current sources, upgrade snapshots must parse, and every .agent-layer/al.lock entry must hold its checksum. Prints the checks that are not ok and a summary, or one JSON report with --json. Exits non-zero when any check fails, so CI can gate on it."
+	VerifyJSONFlag   = "Print the integrity report as one JSON object"
+	VerifyCheckFmt   = "%-9s%s %s: %s\n"
+	VerifySummaryFmt = "Verified %d checks: %d ok, %d modified, %d missing, %d skipped, %d failed.\n"
+	VerifyFailedFmt  = "verification failed for %d of %d checks"
+
+	ConfigUse           = "config"
+	ConfigShort         = "Inspect and reconcile .agent-layer/config.toml and encrypt .env values"
+	ConfigLintUse       = "lint"
+	ConfigLintShort     = "Report every problem in .agent-layer/config.toml"
+	ConfigLintLong      = "Validate .agent-layer/config.toml strictly and report all problems at once: unknown keys (with did-you-mean suggestions), values of the wrong type, deprecated keys and their replacements, and invalid or missing values. Other commands stop at the first config error; run this to see the full list. Exits non-zero when any problem is found."
+	ConfigLintIssueFmt  = "%-15s %s\n"
+	ConfigLintCleanFmt  = "%s: no problems found\n"
+	ConfigLintFailedFmt = "config lint found %d problem(s) in %s"
+
+	ConfigEncryptUse      = "encrypt [KEY...]"
+	ConfigEncryptShort    = "Encrypt .agent-layer/.env values with age"
+	ConfigEncryptLong     = "Encrypt the named AL_ values in .agent-layer/.env, or every non-empty value when no key is given, to the age public keys listed in .agent-layer/age-recipients.txt. Each value is replaced in place with ENC[age:...]; comments and other lines are kept. Sync and launch decrypt these values transparently with the identity in AL_AGE_KEY_FILE (default ~/.config/agent-layer/age/keys.txt). Requires the age CLI."
+	ConfigDecryptUse      = "decrypt [KEY...]"
+	ConfigDecryptShort    = "Decrypt age-encrypted .agent-layer/.env values in place"
+	ConfigDecryptLong     = "Replace the named ENC[age:...] values in .agent-layer/.env, or every encrypted value when no key is given, with their plaintext. Uses the age identity in AL_AGE_KEY_FILE (default ~/.config/agent-layer/age/keys.txt). Requires the age CLI."
+	ConfigCryptChangedFmt = "%s %d value(s) in %s: %s\n"
+	ConfigCryptNoneFmt    = "No values to %s in %s\n"
+	ConfigCryptEncrypted  = "Encrypted"
+	ConfigCryptDecrypted  = "Decrypted"
+	ConfigCryptEncrypt    = "encrypt"
+	ConfigCryptDecrypt    = "decrypt"
+
+	ConfigReconcileUse            = "reconcile"
+	ConfigReconcileShort          = "Bring a drifted .agent-layer/config.toml back in line with the template"
+	ConfigReconcileLong           = "Compare .agent-layer/config.toml against the current config template and offer three steps, each confirmed on its own: add keys and sections the config is missing, with the template's default values and documentation; restore documentation comments that were removed; and rewrite sections in the order `al wizard` uses. Values the config already sets are never changed. --dry-run lists the changes without writing; --yes applies every step without asking."
+	ConfigReconcileFlagDryRun     = "List the changes without writing config.toml"
+	ConfigReconcileFlagYes        = "Apply every step without asking for confirmation"
+	ConfigReconcileNothingFmt     = "%s already matches the template; nothing to reconcile\n"
+	ConfigReconcileAddKeysHeader  = "Missing keys and sections:"
+	ConfigReconcileCommentsHeader = "Missing documentation:"
+	ConfigReconcileReorderHeader  = "Section order differs from the canonical order:"
+	ConfigReconcileEntryFmt       = "  %s\n"
+	ConfigReconcilePreamble       = "header comment at the top of the file"
+	ConfigReconcileAddKeysPrompt  = "Add the missing keys and sections with their template defaults?"
+	ConfigReconcileCommentsPrompt = "Restore the missing documentation comments?"
+	ConfigReconcileReorderPrompt  = "Reorder sections canonically?"
+	ConfigReconcileNeedsYes       = "al config reconcile needs confirmation; rerun with --yes to apply every step, or --dry-run to only list the changes"
+	ConfigReconcileCancelled      = "No changes written."
+	ConfigReconcileResultFmt      = "Updated %s (%s)\n"
+	ConfigReconcileSyncHint       = "Run `al sync` to regenerate client configs."
+
+	DevUse                          = "dev"
+	DevShort                        = "Maintainer tools for developing Agent Layer (tools builds only)"
+	DevGenMigrationUse              = "gen-migration"
+	DevGenMigrationShort            = "Scaffold a release's upgrade migration manifest"
+	DevGenMigrationLong             = "Diff the template ownership manifests of two releases and write internal/templates/migrations/<to>.json with rename_file and delete_file operations for templates that moved or were removed, plus config_set_default operations for keys the config template gained when --from-config is set. Every rationale is a TODO placeholder to rewrite before committing. Generate the target release's template manifest first (scripts/generate-template-manifest.sh); the scaffold is validated like the manifests the upgrader loads."
+	DevGenMigrationFromFlag         = "Previous release whose template manifest is the baseline (vX.Y.Z or X.Y.Z)"
+	DevGenMigrationToFlag           = "Release the migration manifest targets (vX.Y.Z or X.Y.Z)"
+	DevGenMigrationMinPriorFlag     = "min_prior_version to write (defaults to --from)"
+	DevGenMigrationFromConfigFlag   = "Previous release's config.toml template; enables config_set_default scaffolding against the embedded template"
+	DevGenMigrationOutputFlag       = "Output path (defaults to internal/templates/migrations/<to>.json; - writes to stdout)"
+	DevGenMigrationFlagForce        = "Overwrite an existing migration manifest"
+	DevGenMigrationVersionsRequired = "--from and --to are required"
+	DevGenMigrationExistsFmt        = "%s already exists; edit it by hand or pass --force to overwrite it"
+	DevGenMigrationReadConfigFmt    = "read previous config template %s: %w"
+	DevGenMigrationWroteFmt         = "Wrote %s; replace every TODO rationale before committing.\n"
+
+	DevRenderUse            = "render"
+	DevRenderShort          = "Render the embedded templates across a config matrix"
+	DevRenderLong           = "Render the embedded templates into golden client outputs for a matrix of synthetic configs: every approval mode with all clients enabled, each client enabled on its own, and no clients enabled. Each case is a fresh `al init` plus the embedded instructions and workflow skills, synced in a scratch repo, and is written to <output>/<case>/ with the scratch path replaced by /repo. Render before and after a template change and diff the two directories to see its effect on every configuration."
+	DevRenderOutputFlag     = "Directory to write one subdirectory of generated outputs per case into"
+	DevRenderCaseFlag       = "Render only this case (repeatable)"
+	DevRenderOutputRequired = "--output is required"
+	DevRenderUnknownCaseFmt = "unknown render case %q (cases: %s)"
+	DevRenderWroteFmt       = "Rendered %d case(s) into %s\n"
+
+	McpUse                  = "mcp"
+	McpShort                = "Inspect, add, and prefetch configured MCP servers"
+	McpStatusUse            = "status"
+	McpStatusShort          = "Start each enabled MCP server briefly and report its health"
+	McpStatusLong           = "Connect to every enabled MCP server in .agent-layer/config.toml (stdio servers are launched and complete the MCP handshake; HTTP servers are contacted at their URL), list its tools, and report the server version, tool count, and estimated schema token cost. Exits non-zero when any server fails to start or respond."
+	McpStatusNoServers      = "No MCP servers are enabled."
+	McpStatusOKFmt          = "%s  %s (%s) %s: %d tools, ~%d schema tokens\n"
+	McpStatusFailFmt        = "%s  %s (%s): %v\n"
+	McpStatusOKLabel        = "ok  "
+	McpStatusFailLabel      = "FAIL"
+	McpStatusVersionFmt     = "%s %s"
+	McpStatusVersionUnknown = "version unknown"
+	McpStatusFailedFmt      = "%d of %d MCP servers failed"
+	McpGatewayUse           = "gateway"
+	McpGatewayShort         = "Serve all enabled MCP servers as one MCP server over stdio"
+	McpGatewayLong          = "Start every enabled MCP server and serve their tools from a single stdio MCP server, namespaced as <server>.<tool> (for example github.search_issues). Secrets from .agent-layer/.env are resolved here at runtime, so they never reach client configs.\n\nSet `gateway = true` under [mcp] in config.toml and run `al sync` to point every client at this gateway instead of the individual servers. Servers that fail to start are reported on stderr and left out."
+	McpGatewayFlagClient    = "Only serve servers enabled for this client (antigravity, claude, vscode, codex, copilot)"
+	McpAddUse               = "add <registry-id>"
+	McpAddShort             = "Add a recommended MCP server block from the registry to config.toml"
+	McpAddLong              = "Insert the registry's recommended [[mcp.servers]] block for <registry-id> into .agent-layer/config.toml, enabled, after the existing MCP servers. The block pins its package version and uses the command variant for this platform; nothing else in config.toml changes. Fails when config.toml already defines a server with that id; an unknown id error lists the registry ids."
+	McpAddResultFmt         = "Added MCP server %s (%s) to %s\n"
+	McpAddPinnedFmt         = "  Pinned: %s@%s\n"
+	McpAddEnvFmt            = "  Set %s in .agent-layer/.env before running al sync.\n"
+	McpAddSyncHint          = "Run `al sync` to write the server into client configs."
+	McpPrefetchUse          = "prefetch"
+	McpPrefetchShort        = "Resolve, cache, and lock npx/uvx MCP server package versions"
+	McpPrefetchLong         = "Resolve every enabled MCP server launched through npx or uvx to an exact package version, install that version into the npm or uv cache, and record it in .agent-layer/al.lock. `al sync` and `al mcp gateway` then launch locked servers pinned to the recorded version and preferring the cache (`npx --prefer-offline <package>@<version>`, `uvx <package>==<version>`), so agent startup does not depend on live registry resolution.\n\nA lock entry applies only while the server's package and version spec in config.toml are unchanged; re-run prefetch after editing them. Servers that fail keep their existing lock entry. Exits non-zero when any server fails."
+	McpPrefetchNoServers    = "No enabled MCP servers are launched through npx or uvx."
+	McpPrefetchOKFmt        = "ok    %s: %s\n"
+	McpPrefetchFailFmt      = "FAIL  %s: %v\n"
+	McpPrefetchLockedFmt    = "Recorded %d of %d servers in %s. Run `al sync` to pin them in client configs.\n"
+	McpPrefetchFailedFmt    = "%d of %d MCP servers failed to prefetch"
+
 	McpPromptsUse        = "mcp-prompts"
 	McpPromptsShort      = "Start the MCP prompt server (deprecated)"
 	McpPromptsDeprecated = "al mcp-prompts is deprecated: skills are now synced natively. Run 'al sync' to update."
@@ -279,10 +742,12 @@ const (
 	ClientsExecHandoffErrorFmt           = "%s exec handoff failed: %w"
 	ClientsVSCodeExitErrorFmt            = "vscode exited with error: %w"
 	ClientsVSCodeCodeNotFoundFmt         = "vscode preflight failed: 'code' command not found on PATH: %w"
+	ClientsVSCodeWorkspaceMissingFmt     = "vscode workspace file %s is missing; run `al sync` or `al vscode --workspace`"
 	ClientsVSCodeManagedBlockConflictFmt = "vscode preflight failed: managed settings block conflict in %s (%s); run `al sync` to repair `.vscode/settings.json`"
 
 	ClientsCodexHomeWarningFmt       = "Warning: CODEX_HOME is set to %s; expected %s\n"
 	ClientsClaudeConfigDirWarningFmt = "Warning: CLAUDE_CONFIG_DIR is set to %s; expected %s\n"
+	ClientsWorktreeEnvRepointedFmt   = "Note: %s pointed at another git worktree (%s); using %s\n"
 
 	// StubShortFmt formats stub command descriptions.
 	StubShortFmt          = "%s (not implemented yet)"
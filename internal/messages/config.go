@@ -30,7 +30,11 @@ const (
 	ConfigAntigravityAgentSpecificModelInvalidFmt = "%s: agents.antigravity.agent_specific.model is not supported; use agents.antigravity.model for Antigravity model selection"
 	ConfigCopilotCLIEnabledRequiredFmt            = "%s: agents.copilot_cli.enabled is required"
 	ConfigCopilotCLIReasoningEffortUnsupportedFmt = "%s: agents.copilot_cli.reasoning_effort is not supported in this release"
+	ConfigCodexProfileNameInvalidFmt              = "%s: agents.codex.profiles.%s: profile names may only contain letters, digits, '-', and '_'"
+	ConfigCodexProfileValueInvalidFmt             = "%s: agents.codex.profiles.%s.%s %q is invalid (expected one of %s)"
+	ConfigCodexProfilesConflictFmt                = "%s: agents.codex.profiles and agents.codex.agent_specific.profiles cannot both be set; move the profiles into one of them"
 	ConfigDispatchMaxDepthInvalidFmt              = "%s: dispatch.max_depth must be greater than zero"
+	ConfigLanguageInvalidFmt                      = "%s: language %q is not a language tag (for example de or pt-BR)"
 	ConfigMcpServerIDRequiredFmt                  = "%s: mcp.servers[%d].id is required"
 	ConfigMcpServerIDReservedFmt                  = "%s: mcp.servers[%d].id is reserved"
 	ConfigMcpServerIDDuplicateFmt                 = "%s: mcp.servers[%d].id %q duplicates mcp.servers[%d].id"
@@ -40,13 +44,29 @@ const (
 	ConfigMcpServerCommandRequiredFmt             = "%s: mcp.servers[%d].command is required for stdio transport"
 	ConfigMcpServerTransportInvalidFmt            = "%s: mcp.servers[%d].transport must be http or stdio"
 	ConfigMcpServerClientInvalidFmt               = "%s: mcp.servers[%d].clients contains invalid client %q"
+	ConfigMcpServerAgentInvalidFmt                = "%s: mcp.servers[%d].agents contains invalid agent %q"
+	ConfigMcpServerAgentsAndClientsFmt            = "%s: mcp.servers[%d] sets both agents and clients; use one"
+	ConfigMcpServerToolNameEmptyFmt               = "%s: mcp.servers[%d].%s contains an empty tool name"
 	ConfigUnrecognizedKeysFmt                     = "%s: unrecognized config keys: %w"
 	ConfigLegacyGeminiUnsupportedFmt              = "%s: agents.gemini is no longer supported; run 'al upgrade' to migrate to agents.antigravity (renames agents.gemini.enabled, drops legacy gemini.model/reasoning_effort keys, and rewrites mcp.servers[].clients gemini→antigravity)"
 	ConfigLegacyDispatchUnsupportedFmt            = "%s: agents.<agent>.dispatch.default_agent is no longer supported; run 'al upgrade' to remove the retired dispatch defaults"
 	ConfigWarningNoiseModeInvalidFmt              = "%s: warnings.noise_mode %q is invalid (allowed: default, reduce, quiet)"
 	ConfigWarningThresholdInvalidFmt              = "%s: %s must be greater than zero"
 
+	ConfigFieldValueBoolFmt        = "%s must be true or false, got %v"
+	ConfigFieldValueStringFmt      = "%s must be a string, got %v"
+	ConfigFieldValueEmptyFmt       = "%s must not be empty"
+	ConfigFieldValueEnumFmt        = "%s must be one of %s, got %q"
+	ConfigFieldValuePositiveIntFmt = "%s must be a positive integer, got %v"
+
+	ConfigLintUnknownKeyFmt        = "%s: %s is not a recognized config key"
+	ConfigLintUnknownKeySuggestFmt = "%s: %s is not a recognized config key (did you mean %s?)"
+	ConfigLintTypeErrorFmt         = "%s: %s must be %s, got %s"
+	ConfigDeprecatedKeyReplacedFmt = "%s: %s was removed in %s; use %s instead (run 'al upgrade' to migrate)"
+	ConfigDeprecatedKeyRemovedFmt  = "%s: %s was removed in %s; run 'al upgrade' to delete it"
+
 	ConfigMissingSkillsDirFmt            = "missing skills directory %s: %w"
+	ConfigUserSkillsDirFmt               = "resolve user skills directory: %w"
 	ConfigFailedReadSkillFmt             = "failed to read skill %s: %w"
 	ConfigInvalidSkillFmt                = "invalid skill %s: %w"
 	ConfigSkillMissingContent            = "missing content"
@@ -63,6 +83,9 @@ const (
 	ConfigSkillNameMismatchFmt           = "skill in %s has name %q, expected %q"
 	ConfigSkillDirEmptyFmt               = "skill directory %s has no SKILL.md"
 	ConfigSkillDuplicateNameFmt          = "duplicate skill name %q from %s and %s"
+	ConfigSkillRequiresEmpty             = "requires contains an empty skill name"
+	ConfigSkillRequiresMissingFmt        = "skill %q requires %q, which is not a configured skill"
+	ConfigSkillRequiresCycleFmt          = "skill requires form a cycle: %s"
 	ConfigSkillFlatFormatUnsupportedFmt  = "found flat-format skill %q (%s) in skills directory; flat format is no longer supported -- run 'al upgrade' to migrate to directory format"
 
 	ConfigMissingInstructionsDirFmt = "missing instructions directory %s: %w"
@@ -71,8 +94,73 @@ const (
 	ConfigMissingEnvVarsFmt = "missing environment variables: %s"
 
 	// ConfigValidationGuidance is appended to validation errors to direct users to repair tools.
-	ConfigValidationGuidance = "(run 'al wizard' to fix or 'al doctor' to diagnose)"
+	ConfigValidationGuidance = "(run 'al config lint' to list every problem, 'al wizard' to fix, or 'al doctor' to diagnose)"
 
 	// ConfigLenientLoadInfoFmt is used when repair tools fall back to lenient config loading.
 	ConfigLenientLoadInfoFmt = "Config has validation errors; %s will help you fix them: %v"
 )
+
+// Config extends messages for shared base config bundles.
+const (
+	ConfigExtendsChecksumWithoutSourceFmt = "%s: extends_checksum requires extends"
+	ConfigExtendsNestedFmt                = "%s: base config %s sets extends; nested extends are not supported"
+	ConfigExtendsInvalidBaseConfigFmt     = "invalid base config %s: %w"
+	ConfigExtendsMergeFailedFmt           = "failed to merge base config %s into %s: %w"
+	ConfigExtendsSourceInvalidFmt         = "%s: invalid extends source: %w"
+	ConfigExtendsChecksumInvalidFmt       = "%s: invalid extends_checksum: %w"
+	ConfigMonorepoModeInvalidFmt          = "%s: monorepo.mode %q is invalid (expected full or sparse)"
+	ConfigMonorepoOwnerNameRequiredFmt    = "%s: monorepo.owners keys must be non-empty"
+	ConfigMonorepoOwnerDirInvalidFmt      = "%s: monorepo.owners.%s: %w"
+	ConfigVariantFileNameInvalidFmt       = "%s: variant files must be named <name>@<variant>, where the variant uses letters, digits, '.', '_', or '-' and is not %q"
+	ConfigVariantProfileNameRequiredFmt   = "%s: variants.profiles keys must be non-empty"
+	ConfigVariantCopilotSkillsFmt         = "%s: variants.clients.copilot cannot select skills; Copilot reads the shared .agents/skills, which follow the default selection"
+	ConfigVariantClientUnknownFmt         = "%s: variants.clients.%s is not supported (expected %s or %s; other clients read the shared AGENTS.md and .agents/skills, which follow the default selection)"
+	ConfigVariantSelectionInvalidFmt      = "%s: %s.%s: %q = %q is invalid (expected a name without @ and a variant name or \"base\")"
+	ConfigVariantProfileUnknownFmt        = "%s=%q does not match a [variants.profiles] entry (known: %s)"
+	ConfigVariantTargetUnknownFmt         = "[variants] selects a variant of %s %q, which does not exist"
+	ConfigVariantBaseMissingFmt           = "%s %q has no base file; select one of its variants in [variants] (available: %s)"
+	ConfigVariantUnknownFmt               = "%s %q has no variant %q (available: %s)"
+	ConfigVariantKindInstruction          = "instruction"
+	ConfigVariantKindSkill                = "skill"
+	ConfigUpgradeVerifyCommandRequiredFmt = "%s: upgrade.verify[%d].command is required"
+	ConfigHookCommandRequiredFmt          = "%s: hooks.%s[%d] is empty (expected a command line)"
+	ConfigHookCommandInvalidFmt           = "%s: hooks.%s[%d] %q is not a valid command line: %w"
+	ConfigPluginNameInvalidFmt            = "%s: plugins.renderers[%d] %q is not a valid plugin name (expected lowercase letters, digits, and hyphens)"
+	ConfigPluginBuiltinClientFmt          = "%s: plugins.renderers[%d] %q is a built-in client"
+	ConfigPluginDuplicateFmt              = "%s: plugins.renderers lists %q more than once"
+	ConfigPluginPathNameInvalidFmt        = "%s: plugins.paths.%s is not a valid plugin name (expected lowercase letters, digits, and hyphens)"
+	ConfigPluginPathRequiredFmt           = "%s: plugins.paths.%s is empty (expected an executable path)"
+	ConfigLaunchAutoSyncInvalidFmt        = "%s: launch.auto_sync %q is invalid (expected always, if-stale, or never)"
+	ConfigWorktreeStateInvalidFmt         = "%s: worktree.state %q is invalid (expected auto, per-worktree, or shared)"
+	ConfigClientUnknownFmt                = "%s: clients.%s is not a launch client (expected one of %s)"
+	ConfigClientLaunchEnvKeyInvalidFmt    = "%s: clients.%s.launch.env key %q is not a valid variable name"
+	ConfigClientWorkspaceUnsupportedFmt   = "%s: clients.%s.workspace is only supported for vscode"
+	ConfigClientPreLaunchRequiredFmt      = "%s: clients.%s.launch.pre_launch[%d] is empty (expected a command line)"
+	ConfigClientPreLaunchInvalidFmt       = "%s: clients.%s.launch.pre_launch[%d] %q is not a valid command line: %w"
+	ConfigCommandLineUnterminatedQuoteFmt = "unterminated %s quote"
+	ConfigCommandLineTrailingBackslash    = "trailing backslash"
+	ConfigOwnershipPathInvalidFmt         = "%s: ownership: %w"
+	ConfigOwnershipValueInvalidFmt        = "%s: ownership.%q = %q is invalid (expected \"user\")"
+	ConfigRepoDirInvalidFmt               = "invalid directory %q (expected a path relative to the repo root)"
+	ConfigScopedReadFailedFmt             = "failed to read scoped instructions %s: %w"
+	ConfigScopedGlobInvalidFmt            = "%s: invalid path glob: %w"
+	ConfigScopedRootFileFmt               = "%s: scoped instructions must live in a subdirectory named after the repo directory or glob they apply to; put repo-wide instructions in .agent-layer/instructions/"
+	ConfigExtendsLockedResolveFmt         = "%w (checksum recorded in %s; if the base changed intentionally, remove its [extends] entry and run `al sync` to record the new checksum)"
+)
+
+// Remote bundle messages for git-hosted bundles (extends bases and added skills).
+const (
+	RemoteBundleSystemRequired      = "remote bundle system is required"
+	RemoteBundleSourceInvalidFmt    = "invalid source %q (expected host/owner/repo[/subdir]@ref, e.g. github.com/org/agent-layer-base@v1)"
+	RemoteBundleChecksumInvalidFmt  = "invalid checksum %q (expected sha256:<64 lowercase hex characters>)"
+	RemoteBundleChecksumMismatchFmt = "%s checksum mismatch: expected %s, got %s (verify the bundle, then update the pinned checksum or delete %s to refetch)"
+	RemoteBundleChecksumFailedFmt   = "failed to checksum bundle %s: %w"
+	RemoteBundleCheckCacheFmt       = "failed to check cached bundle %s: %w"
+	RemoteBundleNotCachedFmt        = "%s is not cached at %s and %s is set"
+	RemoteBundleCreateCacheDirFmt   = "failed to create bundle cache dir: %w"
+	RemoteBundleResolveCacheDirFmt  = "failed to resolve user cache dir: %w"
+	RemoteBundleFetchFailedFmt      = "failed to fetch %s: %w"
+	RemoteBundleMoveCacheFmt        = "failed to move bundle into cache %s: %w"
+	RemoteBundleMissingFmt          = "%s: bundle directory %s does not exist"
+	RemoteBundleSymlinkFmt          = "bundle contains symlink %s; bundles must not contain symlinks"
+)
@@ -54,8 +54,15 @@ const (
 	DoctorSkillValidationRecommend = "Update skill frontmatter/path conventions in .agent-layer/skills to match agentskills.io recommendations."
 	DoctorSkillValidationFailedFmt = "Failed to validate skill %s: %v"
 	DoctorSkillsLoadFailedFmt      = "Failed to load skills from %s: %v"
+	DoctorSkillRequiresInvalidFmt  = "Skill requirements are invalid: %v"
+	DoctorSkillRequiresRecommend   = "Fix the requires lists in .agent-layer/skills so each names a configured skill and no skills require each other in a cycle."
 	DoctorSkillCatalogTooLargeFmt  = "Skill catalog metadata exceeds %d tokens (%d across %d skills)"
 
+	DoctorCheckNameInstallJournal = "InstallJournal"
+
+	DoctorInstallJournalReadFailedFmt = "Failed to read the install journal: %v"
+	DoctorInstallJournalRecommend     = "Run 'al upgrade' in a terminal to choose between resuming and rolling back."
+
 	DoctorCheckNameFlatSkills = "FlatSkills"
 
 	DoctorSkillFlatFormatDetectedFmt   = "Found flat-format skill file %q in .agent-layer/skills/; flat format is no longer supported."
@@ -13,8 +13,12 @@ const (
 	InstallAutoRepairPinWarningFmt                   = "Auto-repairing invalid pin file %s (was %q, now %s)\n"
 	InstallFailedReadFmt                             = "failed to read %s: %w"
 	InstallFailedReadTemplateFmt                     = "failed to read template %s: %w"
+	InstallGitStatusFailedFmt                        = "failed to check git status: %w"
+	InstallGitStashFailedFmt                         = "failed to stash uncommitted changes: %w"
+	InstallGitCommitFailedFmt                        = "failed to commit checkpoint: %w"
 	InstallFailedCreateDirForFmt                     = "failed to create directory for %s: %w"
 	InstallFailedWriteFmt                            = "failed to write %s: %w"
+	InstallTransactionRestoreFailedFmt               = "%w; restoring files written before the failure also failed: %v"
 	InstallFailedStatFmt                             = "failed to stat %s: %w"
 	InstallFailedReadGitignoreBlockFmt               = "failed to read gitignore block %s: %w"
 	InstallInvalidGitignoreBlockFmt                  = "gitignore block %s must not include managed markers or template hash; run `al upgrade` to review regenerating it"
@@ -27,7 +31,11 @@ const (
 	InstallUnknownFooter                             = "Run `al upgrade` to review deleting them. Non-interactive deletion apply: `al upgrade --yes --apply-deletions`."
 	InstallDeleteUnknownPromptRequired               = "delete prompts require a prompt handler; run in an interactive terminal or include `--apply-deletions` with explicit confirmation settings"
 	InstallDeleteUnknownFailedFmt                    = "failed to delete %s: %w"
+	InstallOrphanedArtifactFmt                       = "%s (last shipped in %s)"
+	InstallOrphanedArtifactModifiedFmt               = "%s (last shipped in %s; modified locally)"
 	InstallUpgradeSnapshotCreatedFmt                 = "Created upgrade snapshot: %s\nIf the upgrade completes, restore with: al upgrade rollback %s\n"
+	InstallUpgradeSnapshotProgressLabel              = "Capturing upgrade snapshot"
+	InstallUpgradeStepFmt                            = "Upgrade step: %s\n"
 	InstallUpgradeSnapshotRolledBackFmt              = "Upgrade failed during %s. Changes were rolled back using snapshot %s.\n"
 	InstallUpgradeSnapshotRollbackFailedFmt          = "Upgrade failed during %[1]s. Rollback using snapshot %[2]s failed: %[3]v\nRetry with: al upgrade rollback %[2]s\n"
 	InstallUpgradeRollbackSnapshotIDRequired         = "upgrade rollback requires a snapshot id"
@@ -35,7 +43,26 @@ const (
 	InstallUpgradeRollbackSnapshotNotFoundFmt        = "upgrade snapshot %s not found under %s"
 	InstallUpgradeRollbackSnapshotNotRollbackableFmt = "upgrade snapshot %s is not rollbackable (status %s): snapshots are only rollbackable in created, applied, or rollback_failed state"
 	InstallUpgradeRollbackFailedFmt                  = "rollback snapshot %s failed: %w"
+	InstallUpgradeHistoryWriteWarningFmt             = "Warning: the upgrade succeeded but its history record could not be written: %v\n"
+	InstallUpgradeHistoryIDRequired                  = "upgrade show requires an upgrade id"
+	InstallUpgradeHistoryIDInvalidFmt                = "invalid upgrade id %q: must not contain path separators"
+	InstallUpgradeHistoryNotFoundFmt                 = "upgrade history %s not found under %s"
+	InstallJournalWriteFmt                           = "write install journal %s: %w"
+	InstallJournalRemoveFmt                          = "remove install journal %s: %w"
+	InstallJournalInvalidFmt                         = "invalid install journal %s: %w"
+	InstallJournalNoStep                             = "before its first step"
+	InstallJournalInterruptedFmt                     = "an earlier al %s stopped during %s (started %s); run `al upgrade rollback %s` to restore the pre-upgrade files, or `al upgrade --resume` to run the upgrade again"
+	InstallJournalInterruptedNoSnapshotFmt           = "an earlier al %s stopped during %s (started %s); run `al upgrade --resume` to finish installing the templates"
 	InstallUpgradeSnapshotLargeWarningFmt            = "Warning: upgrade snapshot %s is large (%d MB); consider cleaning old snapshots under .agent-layer/state/upgrade-snapshots (threshold: %d MB)\n"
+	InstallUpgradeChangelogHeaderFmt                 = "\nWhat's changing for you (%s -> %s):\n"
+	InstallUpgradeChangelogHeaderTargetFmt           = "\nWhat's changing for you (%s):\n"
+	InstallUpgradeChangelogReleaseFmt                = "  v%s\n"
+	InstallUpgradeChangelogReleaseDateFmt            = "  v%s (%s)\n"
+	InstallUpgradeChangelogBreakingFmt               = "    ! BREAKING: %s\n"
+	InstallUpgradeChangelogItemFmt                   = "    - %s\n"
+	InstallUpgradeChangelogOmittedFmt                = "    ... and %d more (see CHANGELOG.md)\n"
+	InstallUpgradeChangelogMigrationsHeader          = "  Breaking changes this upgrade migrates for you:"
+	InstallUpgradeChangelogMigrationFmt              = "    - %s: %s\n"
 	InstallDiffPreviewPathRequired                   = "diff preview path is required"
 	InstallMissingTemplatePathMappingFmt             = "missing template path mapping for %s"
 	InstallSectionAwareMarkerDuplicateFmt            = "section-aware marker %q appears multiple times in %s"
@@ -45,6 +72,20 @@ const (
 	InstallMigrationSkipDanglingSymlinkFmt           = "skipped %s: dangling symlink, watermarked-delete refuses to remove without verifying target content; resolve manually\n"
 	InstallMigrationSkipGeneratedDirFmt              = "skipped %s: generated-artifact deletion refuses to remove directories without explicit generated ownership proof\n"
 
+	// Baseline rebuild errors (baseline_rebuild.go).
+	InstallBaselineAssumeVersionInvalidFmt = "invalid --assume-version %q: %w"
+	InstallBaselineManifestMissingFmt      = "no embedded template manifest for version %s; pass a released version this binary knows"
+	InstallBaselineNoMatch                 = "no embedded template manifest matches any managed file in this repo; rerun with --assume-version X.Y.Z"
+	InstallBaselineAmbiguousFmt            = "managed files match several releases equally well (%s); rerun interactively or with --assume-version X.Y.Z"
+	InstallBaselineChoiceInvalidFmt        = "version %s is not one of the matching releases (%s)"
+
+	// Integrity check details (integrity.go).
+	InstallIntegrityBaselineMissingFmt = "%s is missing; run `al baseline rebuild` to reconstruct it"
+	InstallIntegrityUnpinnedFmt        = "no pinned version; managed baseline records %s"
+	InstallIntegrityPinMismatchFmt     = "managed baseline records %s; run `al upgrade` or `al baseline rebuild`"
+	InstallIntegrityManagedMissing     = "managed file is absent"
+	InstallIntegrityManagedModifiedFmt = "differs from the v%s template"
+
 	// Skills format migration notice banner and summary text (upgrade_migrations_skills.go).
 	InstallSkillsMigrationBannerRule        = "============================================================="
 	InstallSkillsMigrationBannerTitle       = "  BREAKING CHANGE: Slash-commands renamed to skills"
@@ -73,6 +114,23 @@ const (
 	InstallSkillsMigrationDeclinedErr       = "skills format migration declined by user"
 	InstallSkillsMigrationConflictReasonFmt = "%s.md and %s/SKILL.md have different content"
 
+	InstallUpgradeRiskInvalidFmt       = "invalid upgrade risk %q (allowed: safe, config, overwrite, destructive)"
+	InstallUpgradeRiskSafeLabel        = "safe template refresh"
+	InstallUpgradeRiskConfigLabel      = "config rewrite"
+	InstallUpgradeRiskOverwriteLabel   = "user-file overwrite"
+	InstallUpgradeRiskDestructiveLabel = "destructive migration"
+	InstallUpgradeRiskDetailAdd        = "new file"
+	InstallUpgradeRiskDetailRemove     = "no longer shipped; removal offered"
+	InstallUpgradeRiskDetailRenameFmt  = "renamed to %s"
+	InstallUpgradeRiskDetailConfigFmt  = "%s -> %s"
+	InstallUpgradeRiskDetailPinFmt     = "pin %s to %s"
+
+	InstallUpgradeFilterNotOnly         = "not selected by --only"
+	InstallUpgradeFilterSkipped         = "excluded by --skip"
+	InstallUpgradeFilterUnknownFmt      = "--only/--skip entry %q matches no migration operation ID or template path"
+	InstallUpgradeFilterSkippedPathFmt  = "Skipped template update %s (%s)\n"
+	InstallOwnershipMigrationSkippedFmt = "%s is user-owned in config.toml [ownership]"
+
 	// UpdateCreateRequestErrFmt formats request creation errors.
 	UpdateCreateRequestErrFmt         = "create latest release request: %w"
 	UpdateFetchLatestReleaseErrFmt    = "fetch latest release: %w"
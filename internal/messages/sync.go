@@ -6,14 +6,53 @@ const (
 	SyncUse                                         = "sync"
 	SyncShort                                       = "Regenerate client outputs from .agent-layer"
 	SyncCompletedWithWarnings                       = "sync completed with warnings"
+	SyncFlagForce                                   = "Overwrite generated files even if they were edited by hand"
+	SyncFlagDiff                                    = "Print a diff for each hand-edited generated file sync kept"
+	SyncFlagOutputRoot                              = "Write generated files under this directory instead of the working tree"
+	SyncOutputRootIsRepo                            = "--output-root is the repo root; run `al sync` without it"
+	SyncOutputRootResultFmt                         = "Wrote %d generated files under %s.\n"
+	SyncFlagPrintChanges                            = "Print the changes sync would make without writing anything"
+	SyncPrintChangesNone                            = "No changes: generated outputs are up to date.\n"
+	SyncPrintChangeFmt                              = "%-10s %s\n"
+	SyncPrintChangesOutputRoot                      = "--print-changes cannot be combined with --output-root"
+	SyncFlagCheck                                   = "Fail if generated files are out of date, without writing anything"
+	SyncCheckConflictsFlags                         = "--check cannot be combined with --output-root or --print-changes"
+	SyncCheckUpToDate                               = "Generated outputs are up to date.\n"
+	SyncCheckStaleHeader                            = "Generated files out of date:"
+	SyncCheckStalePathFmt                           = "  - %s\n"
+	SyncCheckStaleFmt                               = "%d generated file(s) are out of date; run `al sync` to regenerate them"
+	SyncProgressLabel                               = "Syncing client outputs"
+	SyncFlagSummaryOnly                             = "Print the per-client summary of what sync would write, leave unchanged, and delete, without writing anything"
+	SyncSummaryOnlyConflictsFlags                   = "--summary-only cannot be combined with --check, --print-changes, or --output-root"
+	SyncSummaryHeader                               = "Sync summary:"
+	SyncSummaryDryRunHeader                         = "Sync summary (nothing written):"
+	SyncSummaryClientFmt                            = "  %s: %d written, %d unchanged, %d deleted\n"
+	SyncSummarySharedLabel                          = "shared"
+	SyncSummaryPathFmt                              = "    %-9s %s\n"
+	SyncSummaryWrittenLabel                         = "written"
+	SyncSummaryUnchangedLabel                       = "unchanged"
+	SyncSummaryDeletedLabel                         = "deleted"
+	SyncConfigChangedFmt                            = "Config changed since the last sync: %s\n"
+	SyncConfigAffectedFmt                           = "Generated files updated: %s\n"
+	SyncConfigNoOutputsAffected                     = "No generated files changed.\n"
+	SyncListMoreFmt                                 = "%s, and %d more"
+	SyncReadConfigStateFailedFmt                    = "failed to read sync config state %s: %w"
+	SyncMarshalConfigStateFailedFmt                 = "failed to encode sync config state: %w"
+	SyncReadSourcesStateFailedFmt                   = "failed to read sync sources state %s: %w"
+	SyncMarshalSourcesStateFailedFmt                = "failed to encode sync sources state: %w"
+	SyncReadSourcesFailedFmt                        = "failed to read sync source %s: %w"
 	SyncAgentEnabledFlagMissingFmt                  = "agent %s is missing enabled flag in config"
 	SyncAgentDisabledFmt                            = "agent %s is disabled in config"
 	SyncMarshalMCPConfigFailedFmt                   = "failed to marshal mcp config: %w"
 	SyncCreateDirFailedFmt                          = "failed to create %s: %w"
 	SyncWriteFileFailedFmt                          = "failed to write %s: %w"
 	SyncMarshalClaudeSettingsFailedFmt              = "failed to marshal claude settings: %w"
+	SyncMergeClaudeSettingsFailedFmt                = "failed to merge claude settings %s: %w"
+	SyncReadClaudeManagedKeysFailedFmt              = "failed to read claude managed settings keys %s; delete the file and run al sync again: %w"
+	SyncMarshalClaudeManagedKeysFailedFmt           = "failed to marshal claude managed settings keys: %w"
 	SyncMarshalVSCodeSettingsFailedFmt              = "failed to marshal vscode settings: %w"
 	SyncMarshalVSCodeMCPConfigFailedFmt             = "failed to marshal vscode mcp config: %w"
+	SyncMarshalVSCodeWorkspaceFailedFmt             = "failed to marshal vscode workspace: %w"
 	SyncMarshalCodexAgentSpecificFailedFmt          = "failed to marshal codex agent-specific config: %w"
 	SyncCodexTrustRootRequired                      = "repo root required for codex trust stanza"
 	SyncCodexTrustRootResolveFailedFmt              = "failed to resolve repo root for codex trust stanza %q: %w"
@@ -46,6 +85,10 @@ const (
 	SyncReadTemplateFailedFmt                       = "failed to read template %s: %w"
 	SyncReadFailedFmt                               = "failed to read %s: %w"
 	SyncRemoveFailedFmt                             = "failed to remove %s: %w"
+	SyncStatFailedFmt                               = "failed to check %s: %w"
+	SyncScopedTargetNotGeneratedFmt                 = "%s was not generated by al; move its content into .agent-layer/scoped/%s/ and delete it so sync can manage it"
+	SyncInstructionVariablesUnresolvedFmt           = "unresolved instruction variables: %s; use {{project.name}}, {{git.default_branch}}, {{git.remote_url}}, or {{config.<key>}} with a scalar config key"
+	SyncMonorepoOwnerUnknownFmt                     = "%s=%q does not match a [monorepo.owners] entry (known: %s)"
 	SyncMCPServerErrorFmt                           = "mcp server %s: %w"
 	SyncMCPServerArgFailedFmt                       = "mcp server %s arg: %w"
 	SyncCodexHeaderPlaceholderUnsupportedFmt        = "codex header %s must be literal or use ${VAR}"
@@ -59,6 +102,17 @@ const (
 	SyncLockTimeoutFmt                              = "timed out after %s waiting for sync lock %s; another sync may still be generating files. Wait for it to finish, then retry"
 	SyncUnlockFmt                                   = "failed to unlock sync %s: %w"
 	SyncCloseLockFmt                                = "failed to close sync lock %s: %w"
+	SyncDegradationsHeader                          = "Client feature support:"
+	SyncDegradationFmt                              = "%s: %s not supported, projected as %s"
+	SyncFeatureSlashCommands                        = "slash commands"
+	SyncFeatureCommandAllowlist                     = "commands.allow approvals"
+	SyncFeatureMCPAutoApprove                       = "MCP tool auto-approval"
+	SyncFeatureMCPTransportFmt                      = "%s transport (mcp server %s)"
+	SyncProjectedSkillMentionFmt                    = "%s<name> skill mentions"
+	SyncProjectedClientApprovalDefaults             = "the client's default approval prompts"
+	SyncFeatureFileGlobInstructions                 = "file-glob scoped instructions"
+	SyncProjectedAppliesToSections                  = "\"applies to\" sections in AGENTS.md and CLAUDE.md"
+	SyncProjectedServerOmitted                      = "nothing (server omitted)"
 
 	MCPServerResolveFmt              = "mcp server %s: %w"
 	MCPServerURLFmt                  = "mcp server %s url: %w"
@@ -69,3 +123,60 @@ const (
 	MCPUnsupportedTransportFmt       = "unsupported transport %s"
 	MCPServerUnsupportedTransportFmt = "mcp server %s: unsupported transport %s"
 )
+
+// Sync apply-phase messages.
+const (
+	SyncApplyRevertedFmt     = "sync failed while writing and its earlier changes were reverted: %w"
+	SyncApplyRevertFailedFmt = "sync failed while writing (%w) and reverting its earlier changes also failed; run al sync again: %v"
+	SyncSymlinkFailedFmt     = "failed to create symlink %s: %w"
+	SyncChmodFailedFmt       = "failed to change mode of %s: %w"
+)
+
+// Sync hook messages for [hooks] pre_sync and post_sync.
+const (
+	SyncHookRunningFmt    = "Running %s hook: %s\n"
+	SyncHookNotAllowedFmt = "hooks.%s[%d] %q cannot run: %s; add it to .agent-layer/commands.allow"
+	SyncHookFailedFmt     = "%s hook %q failed: %w"
+	SyncHookInvalidFmt    = "hooks.%s[%d] %q is not a valid command line: %w"
+)
+
+// Sync renderer messages for clients registered through sync.RegisterRenderer.
+const (
+	SyncRendererNil                    = "sync: RegisterRenderer called with a nil renderer"
+	SyncRendererClientRequired         = "sync: RegisterRenderer called with a renderer that has no client name"
+	SyncRendererDuplicateFmt           = "sync: RegisterRenderer called twice for client %s"
+	SyncRendererBuiltinFmt             = "sync: RegisterRenderer called for built-in client %s"
+	SyncRendererFailedFmt              = "renderer %s: %w"
+	SyncRendererArtifactPathInvalidFmt = "renderer %s: artifact path %q must be a relative path inside the repo and outside .agent-layer"
+	SyncRendererArtifactDuplicateFmt   = "renderer %s: artifact %s is also written by renderer %s"
+	SyncRendererArtifactReservedFmt    = "renderer %s: artifact path %q is reserved for git metadata or a built-in client output"
+	SyncRendererArtifactUnownedFmt     = "renderer %s: %s already exists and was not generated by Agent Layer; move it aside so the renderer can write it"
+	SyncReadRendererStateFailedFmt     = "failed to read renderer outputs %s: %w"
+	SyncMarshalRendererStateFailedFmt  = "failed to encode renderer outputs: %w"
+)
+
+// Skill enabled_when messages.
+const (
+	SyncSkillEnabledWhenInvalidFmt      = "skill %s: enabled_when %q: %w"
+	SyncSkillConditionUnexpectedFmt     = "unexpected %q"
+	SyncSkillConditionUnexpectedEnd     = "unexpected end of expression"
+	SyncSkillConditionUnterminatedQuote = "unterminated string"
+	SyncSkillConditionUnknownNameFmt    = "unknown name %s; use true, false, config.<key>, or file_exists(\"<path>\")"
+	SyncSkillConditionUnknownFuncFmt    = "unknown function %s; supported: file_exists"
+	SyncSkillConditionUnknownConfigFmt  = "unknown config key %s"
+	SyncSkillConditionArgsFmt           = "%s takes one string argument"
+	SyncSkillConditionPathInvalidFmt    = "file_exists path %q must be relative and inside the repo"
+)
+
+// Plugin messages for external al-<name> executables.
+const (
+	PluginNotFoundFmt             = "plugin %s: %s not found on PATH"
+	PluginNotFoundPathFmt         = "plugin %s: executable %s: %w"
+	PluginPathIsDir               = "is a directory"
+	PluginEncodeRequestFailedFmt  = "plugin %s: failed to encode render request: %w"
+	PluginRunFailedFmt            = "plugin %s failed: %w"
+	PluginDecodeResponseFailedFmt = "plugin %s: invalid render response on stdout: %w"
+	PluginSchemaVersionFmt        = "plugin %s: render response schema_version %d is not supported (expected %d)"
+	PluginRendererNotAllowedFmt   = "plugins.renderers %q cannot run: %s; add %q to .agent-layer/commands.allow"
+	PluginRendererDuplicateFmt    = "plugin %s renders client %s, which a registered renderer already renders"
+)
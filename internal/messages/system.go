@@ -59,6 +59,11 @@ const (
 	RootCheckPathFmt        = "check %s: %w"
 	RootPathNotDirOrFileFmt = "%s exists but is not a directory or file"
 
+	// Redirect file messages for a .agent-layer file that names the real directory.
+	LayerDirRedirectReadFmt   = "read .agent-layer redirect %s: %w"
+	LayerDirRedirectEmptyFmt  = "%s is a file but names no directory; write the path of the real .agent-layer directory in it, or replace it with the directory"
+	LayerDirRedirectTargetFmt = "%s redirects to %s, which is not a directory"
+
 	// RunRootPathRequired indicates root path is required for run metadata.
 	RunRootPathRequired    = "root path is required"
 	RunGenerateIDFailedFmt = "failed to generate run id: %w"
@@ -103,6 +108,11 @@ const (
 	WarningsPolicySecretInURLFix            = "Move secrets out of URL query/userinfo. Use .agent-layer/.env AL_* keys and projected headers/env placeholders instead."
 	WarningsPolicyCodexHeaderForm           = "one or more MCP header values are incompatible with Codex header projection"
 	WarningsPolicyCodexHeaderFormFix        = "For Codex-targeted servers, use literal values, ${VAR}, or Authorization: Bearer ${VAR} only."
+	WarningsPolicyToolFilterUnsupported     = "tools_allow/tools_deny cannot be enforced natively by every client that receives this server"
+	WarningsPolicyToolFilterUnsupportedFix  = "Set gateway = true under [mcp] so `al mcp gateway` enforces the filter for every client, or restrict the server with clients = [...]."
+	WarningsGeneratedFileEdited             = "generated file was edited by hand; sync kept the edits instead of regenerating it"
+	WarningsGeneratedFileEditedFix          = "Move the change into the source named in the file header, then run `al sync --force` to regenerate; `al sync --diff` shows what sync would write."
+	WarningsPolicyContentRuleFix            = "Edit the file to satisfy .agent-layer/policy.toml; `al policy check` lists every violation."
 	WarningsPolicyAgentSpecificOverridesFmt = "agent-specific %s config overrides Agent Layer-managed keys"
 	WarningsPolicyAgentSpecificOverridesFix = "Remove the override if you want Agent Layer to manage those keys, or keep it to take full control."
 	WarningsPolicyClaudeReasoningUnknownFmt = "agents.claude.reasoning_effort=%q is not a known value (known: %s); sync still proceeds"
@@ -145,3 +155,299 @@ const (
 	UpdateFormulaChecksumMissingFmt = "Error: checksum for %s not found in %s\n"
 	UpdateFormulaRenderFailedFmt    = "Error: failed to render formula: %v\n"
 )
+
+// Clock messages.
+const (
+	ClockInvalidTimestampFmt = "invalid timestamp %q: expected UTC RFC3339 (for example 2006-01-02T15:04:05Z)"
+)
+
+// Lockfile messages.
+const (
+	LockfileReadFailedFmt         = "failed to read %s: %w"
+	LockfileInvalidFmt            = "invalid lockfile %s: %w"
+	LockfileUnsupportedVersionFmt = "%s: unsupported lockfile version %d (expected %d); upgrade al"
+	LockfileWriteFailedFmt        = "failed to write %s: %w"
+
+	LockfileVerifyExtendsUnusedFmt   = "%s records an extends base but config.toml no longer sets extends; run `al sync` to drop it"
+	LockfileVerifyExtendsUnlockedFmt = "extends base is not recorded in %s; run `al sync` to record it"
+	LockfileVerifyExtendsSourceFmt   = "%s was recorded for %s; run `al sync` to record the current extends base"
+	LockfileVerifyChecksumInvalidFmt = "invalid locked checksum %q"
+	LockfileVerifySkillMissingFmt    = "%s is missing; run `al update %s` to reinstall it"
+	LockfileVerifyStatFmt            = "failed to check %s: %w"
+	LockfileVerifySkillModifiedFmt   = "contents changed since they were locked (expected %s, got %s); run `al update --force %s` to restore them"
+)
+
+// MCP pin messages for `al mcp prefetch`.
+const (
+	MCPPinResolveFailedFmt = "failed to resolve %s: %w"
+	MCPPinCacheFailedFmt   = "failed to cache %s: %w"
+	MCPPinNoVersionFmt     = "%s reported no version for %s"
+)
+
+// Content policy messages for .agent-layer/policy.toml.
+const (
+	PolicyReadFmt               = "failed to read policy %s: %w"
+	PolicyInvalidFmt            = "invalid policy %s: %w"
+	PolicyInvalidSeverityFmt    = "sync.severity must be off, warning, or error, got %q"
+	PolicyInvalidMaxLinesFmt    = "skills.max_lines must not be negative, got %d"
+	PolicyEmptyBannedPhrase     = "banned_phrases must not contain empty entries"
+	PolicyEmptyRequiredSection  = "instructions.required_sections must not contain empty entries"
+	PolicyUnknownFrontmatterFmt = "skills.required_frontmatter: unknown field %q (expected one of %s, or metadata.<key>)"
+	PolicyBannedPhraseFmt       = "contains banned phrase %q (line %d)"
+	PolicyMissingSectionFmt     = "no instruction file has a %q section"
+	PolicyMaxLinesFmt           = "skill %s is %d lines long; the limit is %d"
+	PolicyMissingFrontmatterFmt = "skill %s does not set %s in its front matter"
+	PolicySyncFailedFmt         = "sync blocked by %d policy violation(s) in .agent-layer/policy.toml:\n%s"
+)
+
+// Transcript import messages for `al transcripts import`.
+const (
+	TranscriptsUnknownClientFmt = "unknown transcript client %q (supported: %s)"
+	TranscriptsReadFmt          = "failed to read session logs in %s: %w"
+	TranscriptsParseFmt         = "failed to parse session log %s: %w"
+	TranscriptsWriteFmt         = "failed to write transcript %s: %w"
+)
+
+// Memory file messages for `al memory`.
+const (
+	MemoryUnknownTypeFmt     = "unknown memory type %q (supported: %s)"
+	MemoryTitleRequired      = "memory entry title must not be empty"
+	MemoryFieldNotAllowedFmt = "%s is not a field of %s entries"
+	MemoryFieldRequiredFmt   = "%s is required for %s entries"
+	MemoryInvalidPriorityFmt = "invalid priority %q (expected one of %s)"
+	MemoryFileMissingFmt     = "%s not found; run `al init` to create it: %w"
+	MemoryReadFmt            = "failed to read %s: %w"
+	MemoryWriteFmt           = "failed to write %s: %w"
+	MemoryMarkerMissingFmt   = "%s has no %s marker; restore it before editing entries"
+	MemoryEntryNotFoundFmt   = "no memory entry with id %q"
+	MemoryEntryAmbiguousFmt  = "memory entry id %q appears in %s; pass --type to choose one"
+)
+
+// Roadmap messages for `al roadmap`.
+const (
+	RoadmapPhaseNameRequired       = "phase name must not be empty"
+	RoadmapPhaseSectionsRequired   = "a phase needs at least one --goal, --task, and --exit"
+	RoadmapPhaseNotFoundFmt        = "ROADMAP.md has no section for phase %d (archived phases cannot be changed)"
+	RoadmapPhaseAlreadyCompleteFmt = "phase %d is already complete"
+	RoadmapPhaseOpenTasksFmt       = "phase %d has %d unchecked task(s); check them off or pass --force"
+	RoadmapPhaseSummaryRequiredFmt = "phase %d has no tasks to summarize; pass --summary"
+)
+
+// Command guard messages for `al exec`.
+const (
+	ExecGuardReadDenyFmt      = "failed to read commands denylist %s: %w"
+	ExecGuardReasonDenied     = "matches commands.deny"
+	ExecGuardReasonYOLO       = "approvals.mode is yolo"
+	ExecGuardReasonNotAllowed = "not in commands.allow"
+	ExecGuardReasonModeFmt    = "approvals.mode %q does not auto-approve commands"
+	ExecGuardReasonAllowed    = "matches commands.allow"
+	ExecGuardReasonConfirmed  = "approved at the prompt"
+)
+
+// Audit log messages for .agent-layer/state/audit.jsonl.
+const (
+	AuditWriteFmt = "failed to write audit log %s: %w"
+	AuditReadFmt  = "failed to read audit log %s: %w"
+	AuditParseFmt = "invalid audit log %s line %d: %w"
+	AuditSinceFmt = "invalid --since %q: use a duration like 24h or 7d, or a timestamp like 2026-01-02T15:04:05Z"
+)
+
+// Devcontainer feature messages for `al devcontainer generate`.
+const (
+	DevcontainerReadPinFmt    = "failed to read %s: %w"
+	DevcontainerInvalidPinFmt = "invalid pinned version in %s: %w"
+	DevcontainerVersionFmt    = "invalid al version %q: %w"
+	DevcontainerWriteFmt      = "failed to write %s: %w"
+	DevcontainerMarshalFmt    = "failed to encode devcontainer JSON: %w"
+)
+
+// Serve messages for `al serve`.
+const (
+	ServeAddrFmt        = "invalid listen address %q: use host:port or unix:<path>"
+	ServeNotLoopbackFmt = "refusing to listen on %s: al serve only listens on a loopback address or a unix: socket"
+	ServeListenFmt      = "failed to listen on %s: %w"
+	ServeTokenWriteFmt  = "failed to write the al serve token to %s: %w"
+
+	ServeHostNotAllowedFmt = "host %q is not allowed: al serve only answers requests addressed to a loopback host"
+	ServeUnauthorized      = "missing or wrong bearer token: send the token from .agent-layer/state/serve-token as Authorization: Bearer <token>"
+)
+
+// Offline mode messages for --offline and AL_OFFLINE.
+const (
+	OfflineErr        = "offline mode is on (--offline or AL_OFFLINE)"
+	OfflineRefusedFmt = "%s needs network access, but %w"
+
+	OfflineOpUpdateCheck = "checking for a newer al release"
+	OfflineOpValidateFmt = "checking that al release %s exists"
+	OfflineOpDownloadFmt = "downloading al %s (not cached at %s)"
+	OfflineOpFetchFmt    = "fetching %s"
+	OfflineOpMCPPrefetch = "resolving and caching npx/uvx MCP server packages"
+)
+
+// Color mode messages for --color and AL_COLOR.
+const (
+	StyleColorInvalidFmt = "invalid color mode %q (must be auto, always, or never)"
+)
+
+// CI mode messages for --ci and AL_CI.
+const (
+	CIPromptRefusedFmt = "CI mode is on (--ci or AL_CI), so Agent Layer cannot ask: %s"
+)
+
+// Env encryption messages for `al config encrypt` and encrypted .env values.
+const (
+	EnvcryptAgeMissingFmt        = "age is required for encrypted .env values but was not found on PATH (install it from https://age-encryption.org): %w"
+	EnvcryptAgeStderrFmt         = "%w: %s"
+	EnvcryptIdentityMissingFmt   = "no age identity found: set %s or create ~/.config/agent-layer/age/keys.txt with age-keygen: %w"
+	EnvcryptRecipientsMissingFmt = "no age recipients file at %s (add one public key per line, e.g. from age-keygen -y): %w"
+	EnvcryptEncryptFailedFmt     = "age encryption failed: %w"
+	EnvcryptDecryptFailedFmt     = "age decryption failed: %w"
+	EnvcryptNotEncrypted         = "value is not an ENC[age:...] value"
+	EnvcryptMalformedFmt         = "malformed ENC[age:...] value: %w"
+	EnvcryptDecryptKeyFmt        = "failed to decrypt %s from .agent-layer/.env: %w"
+	EnvcryptKeyNotFoundFmt       = "%s is not set in %s"
+	EnvcryptKeyFailedFmt         = "%s: %w"
+)
+
+// Client import messages for `al import`.
+const (
+	ClientImportUnknownClientFmt = "unknown client %q (supported: %s)"
+	ClientImportNothingFoundFmt  = "no %s configuration found in %s"
+	ClientImportReadFmt          = "failed to read %s: %w"
+	ClientImportParseFileFmt     = "failed to parse %s: %w"
+	ClientImportParseFmt         = "failed to merge imported MCP servers: %w"
+	ClientImportWriteFmt         = "failed to write %s: %w"
+	ClientImportServerComment    = "# Imported from %s by `al import`."
+)
+
+// Output diff messages for `al diff`.
+const (
+	OutputDiffAgainstRequired = "--against is required (a release version such as 1.2.0, or a git ref)"
+	OutputDiffScratchFmt      = "failed to create scratch directory: %w"
+	OutputDiffCopyFmt         = "failed to copy %s: %w"
+	OutputDiffRenderFmt       = "render %s: %w"
+	OutputDiffReleaseSyncFmt  = "al %s sync failed: %w\n%s"
+	OutputDiffGitRefFmt       = "read .agent-layer/ at %s: %w; is .agent-layer/ committed at that ref?"
+	OutputDiffArchiveFmt      = "read .agent-layer/ archive at %s: %w"
+	OutputDiffArchivePathFmt  = "archive at %s contains unsafe path %q"
+	OutputDiffCollectFmt      = "failed to read generated outputs in %s: %w"
+	OutputDiffMatrixWriteFmt  = "failed to write render output %s: %w"
+	OutputDiffWriteFmt        = "failed to write %s: %w"
+)
+
+// Config bundle messages for `al export-config` and `al import-config`.
+const (
+	ConfigBundleNoConfigFmt          = "no Agent Layer config to export in %s: %w"
+	ConfigBundleReadFmt              = "failed to read %s: %w"
+	ConfigBundleWriteFmt             = "failed to write %s: %w"
+	ConfigBundleExistsFmt            = "%s already exists; pass --force to replace the local configuration with the bundle"
+	ConfigBundleInvalidFmt           = "invalid config bundle %s: %w"
+	ConfigBundleMissingManifestFmt   = "invalid config bundle %s: missing %s"
+	ConfigBundleUnsupportedFormatFmt = "config bundle %s uses format %d (expected %d); upgrade al"
+	ConfigBundleUnexpectedEntryFmt   = "config bundle %s contains unexpected entry %q"
+	ConfigBundleEntryTooLargeFmt     = "config bundle %s entry %q is too large"
+	ConfigBundleMissingEntryFmt      = "config bundle %s is missing %q"
+	ConfigBundleChecksumMismatchFmt  = "config bundle %s: %q does not match its manifest checksum"
+	ConfigBundleUnlistedEntryFmt     = "config bundle %s: %q is not listed in the manifest"
+	ConfigBundleFormatUnsupportedFmt = "%s: bundles must be gzip-compressed tar archives named .tar.gz or .tgz; zstd and other formats are not supported"
+	ConfigBundleWrongKindFmt         = "%s is an %s snapshot from `al export`, not a config bundle; extract it with tar instead"
+	ConfigBundleResolveFmt           = "failed to resolve the configuration in %s: %w"
+	ConfigBundleRenderFmt            = "failed to render client outputs: %w"
+)
+
+// Clean messages for `al clean`.
+const (
+	CleanRenderFmt = "failed to determine generated outputs; fix the configuration or pass --state only: %w"
+	CleanReadFmt   = "failed to read %s: %w"
+	CleanRemoveFmt = "failed to remove %s: %w"
+)
+
+// Integrity messages for `al verify`.
+const (
+	IntegrityReadFmt          = "failed to read %s: %w"
+	IntegrityGeneratedMissing = "not generated; run `al sync`"
+	IntegrityGeneratedShared  = "patched in place and holds your own settings"
+	IntegrityGeneratedEdited  = "edited by hand since `al sync` wrote it; run `al sync --force` to restore it"
+	IntegrityGeneratedStale   = "differs from what `al sync` writes from the current sources; run `al sync`"
+)
+
+// Skill fetch messages for `al add skill` and `al update`.
+const (
+	SkillFetchSourceRequired      = "skill source is required"
+	SkillFetchRegistryUnsetFmt    = "%q looks like a registry name but %s is not set; pass a source like github.com/org/skills/%s@v1"
+	SkillFetchRegistryInvalidFmt  = "invalid %s: %w"
+	SkillFetchMissingSkillFileFmt = "%s does not contain %s at the bundle root"
+	SkillFetchInvalidFmt          = "invalid skill %s: %w"
+	SkillFetchNameChangedFmt      = "%s now provides skill %q instead of %q; remove the old skill and run `al add skill` again"
+	SkillFetchExistsFmt           = "skill %q already exists at %s and was not added with `al add skill`; pass --force to replace it"
+	SkillFetchNotLockedFmt        = "skill %q is not recorded in %s; add it with `al add skill` first"
+	SkillFetchStatFmt             = "failed to check %s: %w"
+	SkillFetchWriteFmt            = "failed to install skill into %s: %w"
+)
+
+// MCP gateway messages for `al mcp gateway`.
+const (
+	McpGatewayServerSkippedFmt = "al mcp gateway: skipping MCP server %s: %v\n"
+	McpGatewayToolSkippedFmt   = "al mcp gateway: skipping tool %s from MCP server %s: input schema is not a JSON object\n"
+	McpGatewayTimeoutFmt       = "no response within %s"
+
+	McpGatewayAuditFailedFmt        = "al mcp gateway: %v\n"
+	McpGatewayAuditReasonForwarded  = "forwarded by the gateway"
+	McpGatewayAuditReasonDenied     = "matches tools_deny"
+	McpGatewayAuditReasonNotAllowed = "not in tools_allow"
+	McpGatewayAuditRuleAllow        = "tools_allow"
+	McpGatewayAuditRuleDeny         = "tools_deny"
+
+	McpGatewayCombinedInstructionsTitle        = "Agent Layer instructions"
+	McpGatewayCombinedInstructionsDescription  = "All instruction files composed in order, as written to AGENTS.md, CLAUDE.md, and .github/copilot-instructions.md for every client."
+	McpGatewayInstructionFileDescriptionFmt    = "Instruction file .agent-layer/instructions/%s."
+	McpGatewayScopedInstructionsDescriptionFmt = "Composed instructions for %s/, as written to its AGENTS.md and CLAUDE.md."
+
+	McpGatewaySkillPromptsSkippedFmt = "al mcp gateway: skipping skill prompts: %v\n"
+	McpGatewaySkillNotFoundFmt       = "skill %s is no longer configured or enabled"
+	McpGatewaySkillRequiredPromptFmt = "Skill %s, required by %s:\n\n%s"
+
+	McpGatewayMemoryListDescription           = "List entries in the repo's memory files (docs/agent-layer/ISSUES.md, BACKLOG.md, DECISIONS.md) with their IDs, dates, and fields. Read this before adding an entry to avoid duplicates."
+	McpGatewayMemoryAddIssueDescription       = "Record a deferred defect, refactor, or risk in docs/agent-layer/ISSUES.md in the file's entry format. Use this instead of editing ISSUES.md by hand. Returns the new entry and its stable ID."
+	McpGatewayMemoryRecordDecisionDescription = "Append a non-obvious, durable decision to docs/agent-layer/DECISIONS.md in the file's entry format. Use this instead of editing DECISIONS.md by hand. Returns the new entry and its stable ID."
+
+	// Errcode remediation hints, included in --error-format json output.
+	ErrcodeHintConfig          = "Fix .agent-layer/config.toml or the file named in the message; run `al doctor` for details, or `al init` if the repo is not initialized."
+	ErrcodeHintSync            = "Fix the cause named in the message and re-run `al sync`."
+	ErrcodeHintUpgradeConflict = "Re-run `al upgrade` in a terminal to review the conflicting changes, or pass `--yes` with explicit apply flags."
+	ErrcodeHintClientLaunch    = "Check that the client is installed and on PATH, then re-run the command."
+	ErrcodeHintMCP             = "Run `al mcp status` to check each MCP server's command, URL, and credentials."
+	ErrcodeHintOffline         = "Re-run without --offline and AL_OFFLINE where the network is reachable, or fill the caches first: `al upgrade prefetch` for releases, `al sync` for a remote extends base."
+	ErrcodeHintInputRequired   = "Pass the answer as flags instead: `--yes` with explicit apply flags for `al upgrade`, `--yes` for `al clean`, `al uninstall`, and `al config reconcile`, or `--answers` for `al wizard`."
+)
+
+// Output level and progress messages.
+const (
+	OutputProgressFmt      = "%s... %d"
+	OutputProgressTotalFmt = "%s... %d/%d"
+	OutputProgressDoneFmt  = "%s: %d done\n"
+)
+
+// Message catalog messages.
+const (
+	I18nReadCatalogFmt   = "read message catalog %s: %w"
+	I18nParseCatalogFmt  = "parse message catalog %s: %w"
+	I18nInvalidLocaleFmt = "invalid locale %q (expected a language tag such as de or pt-BR)"
+)
+
+// State lock messages.
+const (
+	StateLockOpenFmt    = "open state lock %s: %w"
+	StateLockFmt        = "lock %s: %w"
+	StateLockReleaseFmt = "release state lock %s: %w"
+	StateLockHeldFmt    = "another al process is running in %s; wait for it to finish, or rerun with --wait"
+	StateLockHeldPIDFmt = "another al process (pid %d) is running in %s; wait for it to finish, or rerun with --wait"
+	StateLockWaitingFmt = "Waiting for another al process in %s to finish...\n"
+	StateLockFlagWait   = "Wait for another al process in this repo to finish instead of failing"
+)
+
+// Git worktree messages.
+const (
+	WorktreeReadFmt           = "read git worktree metadata %s: %w"
+	WorktreeGitFileInvalidFmt = "%s names no git directory"
+)
@@ -8,6 +8,7 @@ const (
 	WizardPartialInstallUpgradeRequired   = "agent layer is partially initialized in this repository (missing .agent-layer/config.toml); run 'al upgrade' to repair templates before using 'al wizard'"
 	WizardLoadConfigFailedFmt             = "failed to load config: %w"
 	WizardConfigNeedsUpgradeFmt           = "The wizard can't fix this config — it needs a migration:\n  %v\nRun 'al upgrade' to migrate, then re-run 'al wizard'."
+	WizardDeprecatedKeyWarningFmt         = "Warning: %s"
 	WizardLoadDefaultMCPServersFailedFmt  = "failed to load default MCP servers: %w"
 	WizardLoadWarningDefaultsFailedFmt    = "failed to load warning defaults: %w"
 	WizardUnknownApprovalModeFmt          = "unknown approval mode: %q"
@@ -32,3 +33,18 @@ const (
 	WizardTOMLUnterminatedMultiline       = "unterminated multiline string in TOML output"
 	WizardApplySkillsFailedFmt            = "failed to apply skill changes: %w"
 )
+
+// Wizard round-trip check messages for wizard.RoundTrip.
+const (
+	WizardRoundTripRepatchFmt      = "patched config cannot be patched again: %w"
+	WizardRoundTripNotIdempotent   = "patching the patched config changed it again"
+	WizardRoundTripTableChangedFmt = "patching changed table %s, which the wizard does not manage"
+	WizardRoundTripCommentLostFmt  = "patching dropped the comment %q from %s"
+	WizardRoundTripMultilineFmt    = "patching changed the multiline string %s"
+	WizardRoundTripPreamble        = "the top of the file"
+)
+
+// Wizard reconcile messages for wizard.Reconcile.
+const (
+	WizardReconcileValueChangedFmt = "reconcile would change %s; no changes were written"
+)
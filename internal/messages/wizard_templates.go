@@ -9,6 +9,12 @@ const (
 	WizardLoadMCPCatalogFailedFmt            = "failed to load MCP catalog mcp-catalog.toml: %w"
 	WizardTemplateWarningsDefaultsIncomplete = "template config warnings defaults are incomplete"
 
+	WizardMCPRegistryVersionFmt      = "MCP catalog mcp-catalog.toml has registry version %d; this al reads version %d"
+	WizardMCPRegistryEntryMissingFmt = "MCP catalog server %q has no [registry.servers.%[1]s] entry"
+	WizardMCPRegistryBlockMissingFmt = "MCP catalog registry entry %q has no [[mcp.servers]] block"
+	WizardMCPRegistryUnknownIDFmt    = "unknown MCP registry id %q (available: %s)"
+	WizardMCPServerExistsFmt         = "config.toml already defines MCP server %q"
+
 	WizardLoadCLISkillsCatalogFailedFmt        = "failed to load CLI skills catalog cli-skills-catalog.toml: %w"
 	WizardCatalogNoCLISkills                   = "CLI skills catalog cli-skills-catalog.toml contains no entries"
 	WizardCLISkillCatalogEntryMissingIDFmt     = "CLI skills catalog entry %d is missing required id"
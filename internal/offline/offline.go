@@ -0,0 +1,41 @@
+// Package offline implements offline mode. When AL_OFFLINE is set (the root
+// --offline flag sets it), every Agent Layer operation that would reach the
+// network fails fast instead: update checks, release downloads and
+// validation, and fetches of remote extends bases and skills.
+package offline
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/conn-castle/agent-layer/internal/errcode"
+	"github.com/conn-castle/agent-layer/internal/i18n"
+	"github.com/conn-castle/agent-layer/internal/messages"
+)
+
+// EnvVar turns offline mode on when set to a value other than a false boolean.
+const EnvVar = "AL_OFFLINE"
+
+// ErrOffline is wrapped by every refusal so callers can detect it with errors.Is.
+var ErrOffline = i18n.NewError(messages.OfflineErr)
+
+// Enabled reports whether offline mode is on in the environment read by getenv.
+func Enabled(getenv func(string) string) bool {
+	value := strings.TrimSpace(getenv(EnvVar))
+	if value == "" {
+		return false
+	}
+	if on, err := strconv.ParseBool(value); err == nil {
+		return on
+	}
+	return true
+}
+
+// Check returns an errcode.Offline error naming operation when offline mode
+// is on, and nil otherwise. Call it before any network access.
+func Check(getenv func(string) string, operation string) error {
+	if !Enabled(getenv) {
+		return nil
+	}
+	return errcode.Wrap(errcode.Offline, i18n.Errorf(messages.OfflineRefusedFmt, operation, ErrOffline))
+}
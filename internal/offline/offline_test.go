@@ -0,0 +1,57 @@
+package offline
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/conn-castle/agent-layer/internal/errcode"
+)
+
+func envWith(value string) func(string) string {
+	return func(key string) string {
+		if key == EnvVar {
+			return value
+		}
+		return ""
+	}
+}
+
+func TestEnabled(t *testing.T) {
+	tests := []struct {
+		value string
+		want  bool
+	}{
+		{"", false},
+		{"  ", false},
+		{"0", false},
+		{"false", false},
+		{"1", true},
+		{"true", true},
+		{"yes", true},
+	}
+	for _, tt := range tests {
+		if got := Enabled(envWith(tt.value)); got != tt.want {
+			t.Errorf("Enabled(%q) = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestCheck(t *testing.T) {
+	if err := Check(envWith(""), "fetching x"); err != nil {
+		t.Fatalf("expected nil when online, got %v", err)
+	}
+	err := Check(envWith("1"), "fetching github.com/org/skills@v1")
+	if err == nil {
+		t.Fatal("expected offline error")
+	}
+	if !errors.Is(err, ErrOffline) {
+		t.Fatalf("expected ErrOffline, got %v", err)
+	}
+	if errcode.Of(err) != errcode.Offline {
+		t.Fatalf("code = %q, want %q", errcode.Of(err), errcode.Offline)
+	}
+	if !strings.HasPrefix(err.Error(), "fetching github.com/org/skills@v1 needs network access") {
+		t.Fatalf("unexpected message: %v", err)
+	}
+}
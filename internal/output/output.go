@@ -0,0 +1,168 @@
+// Package output writes Agent Layer's own messages at one of three levels, so
+// the root --quiet and --verbose flags and warnings.noise_mode behave the same
+// in every command. Command results go to stdout at every level; notes,
+// warnings, and progress go to stderr and follow the level.
+package output
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"golang.org/x/term"
+
+	"github.com/conn-castle/agent-layer/internal/ci"
+	"github.com/conn-castle/agent-layer/internal/i18n"
+	"github.com/conn-castle/agent-layer/internal/messages"
+)
+
+// Level selects how much informational output a command prints.
+type Level int
+
+const (
+	// Quiet prints command results and errors only.
+	Quiet Level = iota
+	// Normal adds notes, warnings, and progress on a terminal.
+	Normal
+	// Verbose adds per-step detail.
+	Verbose
+)
+
+// noiseModeQuiet is the warnings.noise_mode value that silences output.
+const noiseModeQuiet = "quiet"
+
+// ResolveLevel returns the level for the root flags and the config
+// warnings.noise_mode value. The flags win over the config.
+func ResolveLevel(quiet bool, verbose bool, noiseMode string) Level {
+	switch {
+	case quiet:
+		return Quiet
+	case verbose:
+		return Verbose
+	case strings.EqualFold(strings.TrimSpace(noiseMode), noiseModeQuiet):
+		return Quiet
+	default:
+		return Normal
+	}
+}
+
+// isTerminal reports whether w is an interactive terminal. CI mode never
+// redraws progress, so it reports false there. Tests replace it.
+var isTerminal = func(w io.Writer) bool {
+	if ci.Enabled(os.Getenv) {
+		return false
+	}
+	file, ok := w.(*os.File)
+	return ok && term.IsTerminal(int(file.Fd())) //nolint:gosec // file descriptors are small non-negative ints
+}
+
+// Writer routes command output by level. A nil *Writer writes like a Normal
+// writer on os.Stdout and os.Stderr.
+type Writer struct {
+	stdout io.Writer
+	stderr io.Writer
+	level  Level
+}
+
+// New returns a Writer for stdout and stderr at level.
+func New(stdout io.Writer, stderr io.Writer, level Level) *Writer {
+	return &Writer{stdout: stdout, stderr: stderr, level: level}
+}
+
+// Level returns the writer's level.
+func (w *Writer) Level() Level {
+	if w == nil {
+		return Normal
+	}
+	return w.level
+}
+
+// Out returns stdout, which receives command results at every level.
+func (w *Writer) Out() io.Writer {
+	if w == nil {
+		return os.Stdout
+	}
+	return w.stdout
+}
+
+// Info returns stderr, or io.Discard when quiet.
+func (w *Writer) Info() io.Writer {
+	return w.at(Normal)
+}
+
+// Detail returns stderr when verbose, or io.Discard otherwise.
+func (w *Writer) Detail() io.Writer {
+	return w.at(Verbose)
+}
+
+// Infof writes a note to stderr unless quiet.
+func (w *Writer) Infof(format string, args ...any) {
+	_, _ = fmt.Fprint(w.Info(), i18n.Sprintf(format, args...))
+}
+
+// Detailf writes a note to stderr when verbose.
+func (w *Writer) Detailf(format string, args ...any) {
+	_, _ = fmt.Fprint(w.Detail(), i18n.Sprintf(format, args...))
+}
+
+func (w *Writer) at(level Level) io.Writer {
+	if w.Level() < level {
+		return io.Discard
+	}
+	if w == nil {
+		return os.Stderr
+	}
+	return w.stderr
+}
+
+// Progress starts a progress indicator for label. total is the expected
+// number of steps, or 0 when unknown. On a terminal the indicator redraws one
+// stderr line; elsewhere it stays silent until Done, which prints a summary
+// line when verbose.
+func (w *Writer) Progress(label string, total int) *Progress {
+	return &Progress{
+		w:       w.Info(),
+		detail:  w.Detail(),
+		label:   i18n.T(label),
+		total:   total,
+		redraw:  w.Level() >= Normal && isTerminal(w.Info()),
+		verbose: w.Level() >= Verbose,
+	}
+}
+
+// Progress reports how far a long operation has come.
+type Progress struct {
+	w       io.Writer
+	detail  io.Writer
+	label   string
+	total   int
+	done    int
+	redraw  bool
+	verbose bool
+	width   int
+}
+
+// Step records one finished step.
+func (p *Progress) Step() {
+	p.done++
+	if !p.redraw {
+		return
+	}
+	line := i18n.Sprintf(messages.OutputProgressFmt, p.label, p.done)
+	if p.total > 0 {
+		line = i18n.Sprintf(messages.OutputProgressTotalFmt, p.label, p.done, p.total)
+	}
+	p.width = max(p.width, len(line))
+	_, _ = fmt.Fprintf(p.w, "\r%-*s", p.width, line)
+}
+
+// Done clears the indicator, and prints a summary line when verbose.
+func (p *Progress) Done() {
+	if p.redraw && p.width > 0 {
+		_, _ = fmt.Fprintf(p.w, "\r%-*s\r", p.width, "")
+	}
+	if p.verbose {
+		_, _ = fmt.Fprint(p.detail, i18n.Sprintf(messages.OutputProgressDoneFmt, p.label, p.done))
+	}
+}
@@ -0,0 +1,113 @@
+package output
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestResolveLevel(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		quiet     bool
+		verbose   bool
+		noiseMode string
+		want      Level
+	}{
+		{name: "default", want: Normal},
+		{name: "quiet flag", quiet: true, want: Quiet},
+		{name: "verbose flag", verbose: true, want: Verbose},
+		{name: "quiet noise mode", noiseMode: " Quiet ", want: Quiet},
+		{name: "reduce noise mode", noiseMode: "reduce", want: Normal},
+		{name: "verbose flag beats noise mode", verbose: true, noiseMode: "quiet", want: Verbose},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := ResolveLevel(tc.quiet, tc.verbose, tc.noiseMode); got != tc.want {
+				t.Fatalf("ResolveLevel = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestWriterRoutesByLevel(t *testing.T) {
+	for _, tc := range []struct {
+		level      Level
+		wantInfo   bool
+		wantDetail bool
+	}{
+		{level: Quiet},
+		{level: Normal, wantInfo: true},
+		{level: Verbose, wantInfo: true, wantDetail: true},
+	} {
+		var stdout, stderr bytes.Buffer
+		w := New(&stdout, &stderr, tc.level)
+		_, _ = io.WriteString(w.Out(), "result\n")
+		w.Infof("note\n")
+		w.Detailf("detail\n")
+
+		if stdout.String() != "result\n" {
+			t.Fatalf("level %v: stdout = %q", tc.level, stdout.String())
+		}
+		if got := strings.Contains(stderr.String(), "note"); got != tc.wantInfo {
+			t.Fatalf("level %v: info written = %v, stderr %q", tc.level, got, stderr.String())
+		}
+		if got := strings.Contains(stderr.String(), "detail"); got != tc.wantDetail {
+			t.Fatalf("level %v: detail written = %v, stderr %q", tc.level, got, stderr.String())
+		}
+	}
+}
+
+func TestNilWriterIsNormal(t *testing.T) {
+	var w *Writer
+	if w.Level() != Normal {
+		t.Fatalf("nil writer level = %v", w.Level())
+	}
+	if w.Detail() != io.Discard {
+		t.Fatalf("nil writer must discard detail")
+	}
+	w.Progress("Working", 1).Step()
+}
+
+func TestProgress(t *testing.T) {
+	orig := isTerminal
+	t.Cleanup(func() { isTerminal = orig })
+
+	t.Run("terminal redraws and clears", func(t *testing.T) {
+		isTerminal = func(io.Writer) bool { return true }
+		var stderr bytes.Buffer
+		p := New(io.Discard, &stderr, Normal).Progress("Working", 2)
+		p.Step()
+		p.Step()
+		p.Done()
+		got := stderr.String()
+		if !strings.Contains(got, "\rWorking... 1/2") || !strings.Contains(got, "\rWorking... 2/2") {
+			t.Fatalf("missing progress lines: %q", got)
+		}
+		if !strings.HasSuffix(got, "\r") || strings.Contains(got, "done") {
+			t.Fatalf("progress must clear its line without a summary: %q", got)
+		}
+	})
+
+	t.Run("not a terminal prints a verbose summary only", func(t *testing.T) {
+		isTerminal = func(io.Writer) bool { return false }
+		var stderr bytes.Buffer
+		p := New(io.Discard, &stderr, Verbose).Progress("Working", 0)
+		p.Step()
+		p.Done()
+		if got := stderr.String(); got != "Working: 1 done\n" {
+			t.Fatalf("stderr = %q", got)
+		}
+	})
+
+	t.Run("quiet prints nothing", func(t *testing.T) {
+		isTerminal = func(io.Writer) bool { return true }
+		var stderr bytes.Buffer
+		p := New(io.Discard, &stderr, Quiet).Progress("Working", 1)
+		p.Step()
+		p.Done()
+		if stderr.Len() != 0 {
+			t.Fatalf("stderr = %q", stderr.String())
+		}
+	})
+}
@@ -0,0 +1,166 @@
+package outputdiff
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/conn-castle/agent-layer/internal/config"
+	"github.com/conn-castle/agent-layer/internal/i18n"
+	"github.com/conn-castle/agent-layer/internal/install"
+	"github.com/conn-castle/agent-layer/internal/messages"
+	"github.com/conn-castle/agent-layer/internal/sync"
+	"github.com/conn-castle/agent-layer/internal/templates"
+)
+
+// MatrixRoot stands in for the scratch repo root in matrix outputs so golden
+// files do not depend on where they were rendered.
+const MatrixRoot = "/repo"
+
+// MatrixCase is one synthetic configuration rendered by RenderMatrix. Apply
+// edits the config produced by `al init` before sync runs.
+type MatrixCase struct {
+	Name  string
+	Apply func(cfg *config.Config)
+}
+
+// DefaultMatrix returns the maintainer render matrix: every approval mode
+// with all clients enabled, each client enabled on its own, and no clients.
+func DefaultMatrix() []MatrixCase {
+	var cases []MatrixCase
+	for _, mode := range []string{config.ApprovalModeAll, config.ApprovalModeCommands, config.ApprovalModeMCP, config.ApprovalModeNone, config.ApprovalModeYOLO} {
+		cases = append(cases, MatrixCase{
+			Name: "approvals-" + mode,
+			Apply: func(cfg *config.Config) {
+				setAllAgents(cfg, true)
+				cfg.Approvals.Mode = mode
+			},
+		})
+	}
+	for _, agent := range matrixAgents {
+		cases = append(cases, MatrixCase{
+			Name: "only-" + agent.name,
+			Apply: func(cfg *config.Config) {
+				setAllAgents(cfg, false)
+				*agent.enabled(cfg) = boolPtr(true)
+			},
+		})
+	}
+	cases = append(cases, MatrixCase{
+		Name:  "no-clients",
+		Apply: func(cfg *config.Config) { setAllAgents(cfg, false) },
+	})
+	return cases
+}
+
+// matrixAgents names each client's enablement field by its config key.
+var matrixAgents = []struct {
+	name    string
+	enabled func(cfg *config.Config) **bool
+}{
+	{"antigravity", func(cfg *config.Config) **bool { return &cfg.Agents.Antigravity.Enabled }},
+	{"claude", func(cfg *config.Config) **bool { return &cfg.Agents.Claude.Enabled }},
+	{"claude_vscode", func(cfg *config.Config) **bool { return &cfg.Agents.ClaudeVSCode.Enabled }},
+	{"codex", func(cfg *config.Config) **bool { return &cfg.Agents.Codex.Enabled }},
+	{"vscode", func(cfg *config.Config) **bool { return &cfg.Agents.VSCode.Enabled }},
+	{"copilot_cli", func(cfg *config.Config) **bool { return &cfg.Agents.CopilotCLI.Enabled }},
+}
+
+func setAllAgents(cfg *config.Config, enabled bool) {
+	for _, agent := range matrixAgents {
+		*agent.enabled(cfg) = boolPtr(enabled)
+	}
+}
+
+func boolPtr(value bool) *bool {
+	return &value
+}
+
+// RenderMatrix renders the embedded templates once per case and writes the
+// generated outputs to dest/<case name>/. Each case starts from a fresh
+// `al init` in a scratch repo plus the embedded instructions and workflow
+// skills, so only the templates and the case's config
+// edits affect the result. A case directory is replaced wholesale, so files a
+// template change stops generating disappear from it.
+func RenderMatrix(dest string, cases []MatrixCase) error {
+	for _, c := range cases {
+		outputs, err := renderCase(c)
+		if err != nil {
+			return i18n.Errorf(messages.OutputDiffRenderFmt, c.Name, err)
+		}
+		caseDir := filepath.Join(dest, c.Name)
+		if err := os.RemoveAll(caseDir); err != nil {
+			return i18n.Errorf(messages.OutputDiffMatrixWriteFmt, caseDir, err)
+		}
+		for rel, content := range outputs {
+			target := filepath.Join(caseDir, filepath.FromSlash(rel))
+			if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+				return i18n.Errorf(messages.OutputDiffMatrixWriteFmt, target, err)
+			}
+			if err := os.WriteFile(target, []byte(content), 0o644); err != nil { // #nosec G306 -- golden outputs are ordinary repo files.
+				return i18n.Errorf(messages.OutputDiffMatrixWriteFmt, target, err)
+			}
+		}
+	}
+	return nil
+}
+
+func renderCase(c MatrixCase) (map[string]string, error) {
+	scratch, err := os.MkdirTemp("", "al-render-")
+	if err != nil {
+		return nil, i18n.Errorf(messages.OutputDiffScratchFmt, err)
+	}
+	defer func() { _ = os.RemoveAll(scratch) }()
+
+	if err := install.Run(scratch, install.Options{System: install.RealSystem{}}); err != nil {
+		return nil, err
+	}
+	if err := seedTemplateDirs(scratch); err != nil {
+		return nil, err
+	}
+	return render(scratch, MatrixRoot, func(dir string) error {
+		project, err := config.LoadProjectConfig(dir)
+		if err != nil {
+			return err
+		}
+		// User-global skills belong to whoever runs the render, not the templates.
+		skills := project.Skills[:0]
+		for _, skill := range project.Skills {
+			if skill.Scope != config.SkillScopeUser {
+				skills = append(skills, skill)
+			}
+		}
+		project.Skills = skills
+		if c.Apply != nil {
+			c.Apply(&project.Config)
+		}
+		_, err = sync.RunWithProject(sync.RealSystem{}, dir, project)
+		return err
+	})
+}
+
+// seedTemplateDirs copies the embedded instructions and workflow skills into
+// root's .agent-layer/. `al init` leaves them to the wizard, but their
+// rendering is part of what the matrix checks.
+func seedTemplateDirs(root string) error {
+	for _, dir := range []string{"instructions", "skills"} {
+		err := templates.Walk(dir, func(p string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			target := filepath.Join(root, agentLayerDir, filepath.FromSlash(p))
+			if d.IsDir() {
+				return os.MkdirAll(target, 0o755)
+			}
+			data, err := templates.Read(p)
+			if err != nil {
+				return err
+			}
+			return os.WriteFile(target, data, 0o644) // #nosec G306 -- scratch copy of embedded templates.
+		})
+		if err != nil {
+			return i18n.Errorf(messages.OutputDiffCopyFmt, dir, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package outputdiff
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRenderMatrix(t *testing.T) {
+	dest := t.TempDir()
+	stale := filepath.Join(dest, "only-claude", "stale.txt")
+	if err := os.MkdirAll(filepath.Dir(stale), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(stale, []byte("old"), 0o644); err != nil {
+		t.Fatalf("write stale: %v", err)
+	}
+
+	var cases []MatrixCase
+	for _, c := range DefaultMatrix() {
+		switch c.Name {
+		case "approvals-all", "approvals-none", "only-claude", "no-clients":
+			cases = append(cases, c)
+		}
+	}
+	if len(cases) != 4 {
+		t.Fatalf("default matrix is missing expected cases: %v", cases)
+	}
+	if err := RenderMatrix(dest, cases); err != nil {
+		t.Fatalf("RenderMatrix: %v", err)
+	}
+
+	exists := func(rel string) bool {
+		_, err := os.Stat(filepath.Join(dest, filepath.FromSlash(rel)))
+		return err == nil
+	}
+	for _, rel := range []string{"only-claude/.claude/settings.json", "only-claude/CLAUDE.md", "approvals-all/.codex/config.toml", "no-clients/AGENTS.md"} {
+		if !exists(rel) {
+			t.Fatalf("expected %s to be rendered", rel)
+		}
+	}
+	for _, rel := range []string{"only-claude/.codex/config.toml", "only-claude/stale.txt", "no-clients/.claude/settings.json"} {
+		if exists(rel) {
+			t.Fatalf("did not expect %s", rel)
+		}
+	}
+
+	all, err := os.ReadFile(filepath.Join(dest, "approvals-all", ".claude", "settings.json"))
+	if err != nil {
+		t.Fatalf("read settings: %v", err)
+	}
+	none, err := os.ReadFile(filepath.Join(dest, "approvals-none", ".claude", "settings.json"))
+	if err != nil {
+		t.Fatalf("read settings: %v", err)
+	}
+	if string(all) == string(none) {
+		t.Fatalf("approval modes rendered identical Claude settings:\n%s", all)
+	}
+
+	err = filepath.WalkDir(dest, func(p string, d os.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		data, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		if strings.Contains(string(data), os.TempDir()+string(filepath.Separator)+"al-render-") {
+			t.Errorf("%s contains a scratch path", p)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("walk: %v", err)
+	}
+}
@@ -0,0 +1,488 @@
+// Package outputdiff renders the generated client outputs of a repo twice, in
+// scratch directories, and reports the files that differ. The current side is
+// the working tree's .agent-layer/ rendered by the running binary; the other
+// side is the same sources rendered by another Agent Layer release, or the
+// .agent-layer/ tree of another git commit rendered by the running binary.
+// The repo itself is never written. Render renders the working tree alone, for
+// `al export`; RenderTo writes that render to another directory, for
+// `al sync --output-root`; RenderMatrix renders the embedded templates for a
+// matrix of synthetic configs, for `al dev render`.
+package outputdiff
+
+import (
+	"archive/tar"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/conn-castle/agent-layer/internal/config"
+	"github.com/conn-castle/agent-layer/internal/envfile"
+	"github.com/conn-castle/agent-layer/internal/i18n"
+	"github.com/conn-castle/agent-layer/internal/install"
+	"github.com/conn-castle/agent-layer/internal/layerdir"
+	"github.com/conn-castle/agent-layer/internal/messages"
+	"github.com/conn-castle/agent-layer/internal/sync"
+	"github.com/conn-castle/agent-layer/internal/version"
+	"github.com/conn-castle/agent-layer/internal/versiondispatch"
+)
+
+// Target kinds reported in Result.Kind.
+const (
+	KindVersion = "version"
+	KindGitRef  = "git-ref"
+)
+
+const agentLayerDir = ".agent-layer"
+
+// skippedSourceDirs are runtime directories under .agent-layer/ that never
+// feed sync, so they are not copied into scratch renders.
+var skippedSourceDirs = []string{"tmp", "state", "transcripts"}
+
+var (
+	cachedBinaryPath = versiondispatch.CachedBinaryPath
+	runReleaseSync   = runReleaseSyncCommand
+	gitOutput        = gitCommandOutput
+)
+
+// Options configures Compare.
+type Options struct {
+	// Root is the repo root holding .agent-layer/.
+	Root string
+	// Against is a release version (X.Y.Z or vX.Y.Z) or a git ref. Values
+	// that parse as versions are treated as versions; write a tag that looks
+	// like one as refs/tags/<tag> to diff against the commit instead.
+	Against string
+	// Progress receives release download progress.
+	Progress io.Writer
+}
+
+// Change is one generated file whose content differs between the renders.
+type Change struct {
+	// Path is the slash-separated repo-relative path.
+	Path string
+	// Against is the file as rendered for the comparison target.
+	Against string
+	// Current is the file as rendered from the working tree.
+	Current string
+	// InAgainst and InCurrent report whether each render produced the file.
+	InAgainst bool
+	InCurrent bool
+}
+
+// Result is the outcome of Compare.
+type Result struct {
+	// Kind is KindVersion or KindGitRef.
+	Kind string
+	// Against is the target as resolved: a normalized version or the ref.
+	Against string
+	// Changes lists differing files sorted by path.
+	Changes []Change
+}
+
+// Compare renders the working tree and the target described by opts and
+// returns the generated files that differ.
+func Compare(opts Options) (*Result, error) {
+	against := strings.TrimSpace(opts.Against)
+	if against == "" {
+		return nil, errors.New(i18n.T(messages.OutputDiffAgainstRequired))
+	}
+	scratch, err := os.MkdirTemp("", "al-diff-")
+	if err != nil {
+		return nil, i18n.Errorf(messages.OutputDiffScratchFmt, err)
+	}
+	defer func() { _ = os.RemoveAll(scratch) }()
+
+	currentDir := filepath.Join(scratch, "current")
+	if err := copySources(opts.Root, currentDir); err != nil {
+		return nil, err
+	}
+	current, err := render(currentDir, opts.Root, renderInProcess(opts.Root))
+	if err != nil {
+		return nil, i18n.Errorf(messages.OutputDiffRenderFmt, "working tree", err)
+	}
+
+	result := &Result{Kind: KindGitRef, Against: against}
+	againstDir := filepath.Join(scratch, "against")
+	var renderAgainst func(string) error
+	if normalized, err := version.Normalize(against); err == nil {
+		result.Kind = KindVersion
+		result.Against = normalized
+		if err := copySources(opts.Root, againstDir); err != nil {
+			return nil, err
+		}
+		binary, err := cachedBinaryPath(normalized, opts.Progress)
+		if err != nil {
+			return nil, err
+		}
+		renderAgainst = func(dir string) error { return runReleaseSync(binary, normalized, dir) }
+	} else {
+		if err := extractGitSources(opts.Root, against, againstDir); err != nil {
+			return nil, err
+		}
+		renderAgainst = renderInProcess(opts.Root)
+	}
+	other, err := render(againstDir, opts.Root, renderAgainst)
+	if err != nil {
+		return nil, i18n.Errorf(messages.OutputDiffRenderFmt, against, err)
+	}
+	result.Changes = diffOutputs(other, current)
+	return result, nil
+}
+
+// Render renders the generated client outputs of the working tree at root in
+// a scratch copy and returns them keyed by slash-separated path. The repo is
+// not written. With redact, every non-empty .env value is replaced by its own
+// ${KEY} placeholder before rendering, so outputs carry placeholders instead
+// of secrets and encrypted values need no identity.
+func Render(root string, redact bool) (map[string]string, error) {
+	scratch, err := os.MkdirTemp("", "al-render-")
+	if err != nil {
+		return nil, i18n.Errorf(messages.OutputDiffScratchFmt, err)
+	}
+	defer func() { _ = os.RemoveAll(scratch) }()
+
+	if err := copySources(root, scratch); err != nil {
+		return nil, err
+	}
+	if redact {
+		if err := redactEnv(filepath.Join(scratch, agentLayerDir, ".env")); err != nil {
+			return nil, err
+		}
+	}
+	outputs, err := render(scratch, root, renderInProcess(root))
+	if err != nil {
+		return nil, i18n.Errorf(messages.OutputDiffRenderFmt, "working tree", err)
+	}
+	return outputs, nil
+}
+
+// runtimeOutputs are files sync writes under .agent-layer/ for its own
+// bookkeeping rather than for a client. RenderTo does not copy them out.
+var runtimeOutputs = []string{"state/", "tmp/", install.SyncLockFileName, "al.lock"}
+
+// RenderTo renders the generated client outputs of the working tree at root
+// in a scratch copy with renderFn, then writes them under dest at the same
+// relative paths, keeping file modes. Contents read as if sync had run in
+// root. The repo is not written. It returns the written paths, sorted.
+func RenderTo(root string, dest string, renderFn func(dir string) error) ([]string, error) {
+	scratch, err := os.MkdirTemp("", "al-render-")
+	if err != nil {
+		return nil, i18n.Errorf(messages.OutputDiffScratchFmt, err)
+	}
+	defer func() { _ = os.RemoveAll(scratch) }()
+
+	if err := copySources(root, scratch); err != nil {
+		return nil, err
+	}
+	sources, err := listFiles(scratch)
+	if err != nil {
+		return nil, err
+	}
+	if err := renderFn(scratch); err != nil {
+		return nil, i18n.Errorf(messages.OutputDiffRenderFmt, "working tree", err)
+	}
+	files, err := listFiles(scratch)
+	if err != nil {
+		return nil, err
+	}
+	var written []string
+	for rel := range files {
+		if _, ok := sources[rel]; ok || isRuntimeOutput(rel) {
+			continue
+		}
+		src := filepath.Join(scratch, filepath.FromSlash(rel))
+		info, err := os.Stat(src)
+		if err != nil {
+			return nil, i18n.Errorf(messages.OutputDiffCollectFmt, scratch, err)
+		}
+		data, err := os.ReadFile(src) // #nosec G304 -- src is a file sync generated in the scratch copy.
+		if err != nil {
+			return nil, i18n.Errorf(messages.OutputDiffCollectFmt, scratch, err)
+		}
+		target := filepath.Join(dest, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+			return nil, i18n.Errorf(messages.OutputDiffWriteFmt, target, err)
+		}
+		content := strings.ReplaceAll(string(data), scratch, root)
+		if err := os.WriteFile(target, []byte(content), info.Mode().Perm()); err != nil {
+			return nil, i18n.Errorf(messages.OutputDiffWriteFmt, target, err)
+		}
+		written = append(written, rel)
+	}
+	sort.Strings(written)
+	return written, nil
+}
+
+func isRuntimeOutput(rel string) bool {
+	inner, ok := strings.CutPrefix(rel, agentLayerDir+"/")
+	if !ok {
+		return false
+	}
+	for _, runtime := range runtimeOutputs {
+		if inner == runtime || (strings.HasSuffix(runtime, "/") && strings.HasPrefix(inner, runtime)) {
+			return true
+		}
+	}
+	return false
+}
+
+// redactEnv rewrites the .env at envPath so each non-empty value is its own
+// placeholder. A missing .env is left missing.
+func redactEnv(envPath string) error {
+	data, err := os.ReadFile(envPath) // #nosec G304 -- envPath is the scratch copy's .agent-layer/.env.
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return i18n.Errorf(messages.OutputDiffCopyFmt, envPath, err)
+	}
+	env, err := envfile.Parse(string(data))
+	if err != nil {
+		return i18n.Errorf(messages.ConfigInvalidEnvFileFmt, envPath, err)
+	}
+	placeholders := make(map[string]string, len(env))
+	for key, value := range env {
+		if value != "" {
+			placeholders[key] = "${" + key + "}"
+		}
+	}
+	if err := os.WriteFile(envPath, []byte(envfile.Patch(string(data), placeholders)), 0o600); err != nil {
+		return i18n.Errorf(messages.OutputDiffCopyFmt, envPath, err)
+	}
+	return nil
+}
+
+// render runs renderFn in dir and returns the files it generated, keyed by
+// slash-separated relative path. Scratch paths in file contents are rewritten
+// to root so both renders compare as if generated in the repo.
+func render(dir string, root string, renderFn func(string) error) (map[string]string, error) {
+	sources, err := listFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+	if err := renderFn(dir); err != nil {
+		return nil, err
+	}
+	files, err := listFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+	outputs := make(map[string]string)
+	for rel := range files {
+		if _, ok := sources[rel]; ok || isRuntimeOutput(rel) {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(rel)))
+		if err != nil {
+			return nil, i18n.Errorf(messages.OutputDiffCollectFmt, dir, err)
+		}
+		outputs[rel] = strings.ReplaceAll(string(data), dir, root)
+	}
+	return outputs, nil
+}
+
+// renderInProcess returns a render function that runs sync for the sources
+// in a scratch dir with the running binary. Project and git variables resolve
+// against root, the repo the sources came from. Sync warnings do not matter
+// for a comparison and are dropped.
+func renderInProcess(root string) func(dir string) error {
+	return func(dir string) error {
+		project, err := config.LoadProjectConfig(dir)
+		if err != nil {
+			return err
+		}
+		_, err = sync.RunWithProjectOptions(sync.RealSystem{}, dir, project, sync.RunOptions{SourceRoot: root})
+		return err
+	}
+}
+
+// runReleaseSyncCommand runs `al sync` from a cached release binary in dir.
+// AL_VERSION keeps the binary from dispatching to the version pinned in the
+// copied sources. Exiting with warnings still counts as a render.
+func runReleaseSyncCommand(binary string, release string, dir string) error {
+	cmd := exec.Command(binary, "sync") // #nosec G204 -- binary is a checksum-verified cached release.
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), versiondispatch.EnvVersionOverride+"="+release)
+	out, err := cmd.CombinedOutput()
+	if err != nil && !bytes.Contains(out, []byte(messages.SyncCompletedWithWarnings)) {
+		return i18n.Errorf(messages.OutputDiffReleaseSyncFmt, release, err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// copySources copies root's .agent-layer/ into dest/.agent-layer/, skipping
+// runtime directories.
+func copySources(root string, dest string) error {
+	src := layerdir.Dir(root)
+	return filepath.WalkDir(src, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return i18n.Errorf(messages.OutputDiffCopyFmt, p, err)
+		}
+		rel, err := filepath.Rel(src, p)
+		if err != nil {
+			return i18n.Errorf(messages.OutputDiffCopyFmt, p, err)
+		}
+		target := filepath.Join(dest, agentLayerDir, rel)
+		if d.IsDir() {
+			for _, skipped := range skippedSourceDirs {
+				if rel == skipped {
+					return filepath.SkipDir
+				}
+			}
+			if err := os.MkdirAll(target, 0o755); err != nil {
+				return i18n.Errorf(messages.OutputDiffCopyFmt, p, err)
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return i18n.Errorf(messages.OutputDiffCopyFmt, p, err)
+		}
+		data, err := os.ReadFile(p)
+		if err != nil {
+			return i18n.Errorf(messages.OutputDiffCopyFmt, p, err)
+		}
+		if err := os.WriteFile(target, data, info.Mode().Perm()); err != nil {
+			return i18n.Errorf(messages.OutputDiffCopyFmt, p, err)
+		}
+		return nil
+	})
+}
+
+// extractGitSources writes the .agent-layer/ tree of ref into
+// dest/.agent-layer/. The working tree's .env is copied alongside because
+// secrets are never committed.
+func extractGitSources(root string, ref string, dest string) error {
+	prefix, err := gitOutput(root, "rev-parse", "--show-prefix")
+	if err != nil {
+		return i18n.Errorf(messages.OutputDiffGitRefFmt, ref, err)
+	}
+	tree := ref + ":" + strings.TrimSpace(string(prefix)) + agentLayerDir
+	archive, err := gitOutput(root, "archive", "--format=tar", tree)
+	if err != nil {
+		return i18n.Errorf(messages.OutputDiffGitRefFmt, ref, err)
+	}
+	target := filepath.Join(dest, agentLayerDir)
+	reader := tar.NewReader(bytes.NewReader(archive))
+	for {
+		header, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return i18n.Errorf(messages.OutputDiffArchiveFmt, ref, err)
+		}
+		name := path.Clean(header.Name)
+		if !filepath.IsLocal(filepath.FromSlash(name)) {
+			return i18n.Errorf(messages.OutputDiffArchivePathFmt, ref, header.Name)
+		}
+		p := filepath.Join(target, filepath.FromSlash(name))
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(p, 0o755); err != nil {
+				return i18n.Errorf(messages.OutputDiffCopyFmt, name, err)
+			}
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+				return i18n.Errorf(messages.OutputDiffCopyFmt, name, err)
+			}
+			data, err := io.ReadAll(reader)
+			if err != nil {
+				return i18n.Errorf(messages.OutputDiffArchiveFmt, ref, err)
+			}
+			if err := os.WriteFile(p, data, header.FileInfo().Mode().Perm()); err != nil {
+				return i18n.Errorf(messages.OutputDiffCopyFmt, name, err)
+			}
+		}
+	}
+	// git does not track empty directories, but sync requires skills/.
+	if err := os.MkdirAll(filepath.Join(target, "skills"), 0o755); err != nil {
+		return i18n.Errorf(messages.OutputDiffCopyFmt, target, err)
+	}
+	envPath := filepath.Join(layerdir.Dir(root), ".env")
+	data, err := os.ReadFile(envPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return i18n.Errorf(messages.OutputDiffCopyFmt, envPath, err)
+	}
+	if err := os.WriteFile(filepath.Join(target, ".env"), data, 0o600); err != nil {
+		return i18n.Errorf(messages.OutputDiffCopyFmt, envPath, err)
+	}
+	return nil
+}
+
+// gitCommandOutput runs git in root and returns its stdout. Stderr is folded
+// into the error.
+func gitCommandOutput(root string, args ...string) ([]byte, error) {
+	cmd := exec.Command("git", append([]string{"-C", root}, args...)...) // #nosec G204 -- fixed git subcommands; the ref is passed as a single argument without a shell.
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("%w: %s", err, msg)
+		}
+		return nil, err
+	}
+	return out, nil
+}
+
+// listFiles returns the regular files under dir as a set of slash-separated
+// relative paths.
+func listFiles(dir string) (map[string]struct{}, error) {
+	files := make(map[string]struct{})
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		files[filepath.ToSlash(rel)] = struct{}{}
+		return nil
+	})
+	if err != nil {
+		return nil, i18n.Errorf(messages.OutputDiffCollectFmt, dir, err)
+	}
+	return files, nil
+}
+
+// diffOutputs returns the files whose content or presence differs, sorted by
+// path.
+func diffOutputs(against map[string]string, current map[string]string) []Change {
+	paths := make(map[string]struct{}, len(against)+len(current))
+	for p := range against {
+		paths[p] = struct{}{}
+	}
+	for p := range current {
+		paths[p] = struct{}{}
+	}
+	var changes []Change
+	for p := range paths {
+		a, inAgainst := against[p]
+		c, inCurrent := current[p]
+		if inAgainst == inCurrent && a == c {
+			continue
+		}
+		changes = append(changes, Change{Path: p, Against: a, Current: c, InAgainst: inAgainst, InCurrent: inCurrent})
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Path < changes[j].Path })
+	return changes
+}
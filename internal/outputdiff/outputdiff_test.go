@@ -0,0 +1,279 @@
+package outputdiff
+
+import (
+	"errors"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func writeRepo(t *testing.T, root string, rules string) {
+	t.Helper()
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	agentDir := filepath.Join(root, agentLayerDir)
+	files := map[string]string{
+		"config.toml":             "[approvals]\nmode = \"none\"\n\n[agents.antigravity]\nenabled = false\n[agents.claude]\nenabled = true\n[agents.claude_vscode]\nenabled = false\n[agents.codex]\nenabled = false\n[agents.vscode]\nenabled = false\n[agents.copilot_cli]\nenabled = false\n",
+		"commands.allow":          "",
+		"gitignore.block":         "/CLAUDE.md\n",
+		".env":                    "",
+		"instructions/00_base.md": rules,
+		"tmp/runs/ignored.txt":    "runtime state",
+	}
+	for rel, content := range files {
+		p := filepath.Join(agentDir, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(p), 0o700); err != nil {
+			t.Fatalf("mkdir %s: %v", rel, err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0o600); err != nil {
+			t.Fatalf("write %s: %v", rel, err)
+		}
+	}
+	if err := os.MkdirAll(filepath.Join(agentDir, "skills"), 0o700); err != nil {
+		t.Fatalf("mkdir skills: %v", err)
+	}
+}
+
+func findChange(changes []Change, path string) (Change, bool) {
+	for _, change := range changes {
+		if change.Path == path {
+			return change, true
+		}
+	}
+	return Change{}, false
+}
+
+func git(t *testing.T, root string, args ...string) {
+	t.Helper()
+	cmd := exec.Command("git", append([]string{"-C", root}, args...)...)
+	cmd.Env = append(os.Environ(), "GIT_AUTHOR_NAME=t", "GIT_AUTHOR_EMAIL=t@example.com", "GIT_COMMITTER_NAME=t", "GIT_COMMITTER_EMAIL=t@example.com")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git %v: %v\n%s", args, err, out)
+	}
+}
+
+func TestCompare_GitRef(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	root := t.TempDir()
+	writeRepo(t, root, "old rule\n")
+	git(t, root, "init", "--quiet")
+	git(t, root, "add", "-f", ".agent-layer/config.toml", ".agent-layer/commands.allow", ".agent-layer/gitignore.block", ".agent-layer/instructions")
+	git(t, root, "commit", "--quiet", "-m", "base")
+	if err := os.WriteFile(filepath.Join(root, agentLayerDir, "instructions", "00_base.md"), []byte("new rule\n"), 0o600); err != nil {
+		t.Fatalf("edit rules: %v", err)
+	}
+
+	result, err := Compare(Options{Root: root, Against: "HEAD"})
+	if err != nil {
+		t.Fatalf("compare: %v", err)
+	}
+	if result.Kind != KindGitRef || result.Against != "HEAD" {
+		t.Fatalf("unexpected target %s %s", result.Kind, result.Against)
+	}
+	change, ok := findChange(result.Changes, "CLAUDE.md")
+	if !ok {
+		t.Fatalf("expected CLAUDE.md to differ, got %#v", result.Changes)
+	}
+	if !strings.Contains(change.Against, "old rule") || !strings.Contains(change.Current, "new rule") {
+		t.Fatalf("unexpected CLAUDE.md renders:\nagainst %q\ncurrent %q", change.Against, change.Current)
+	}
+	for _, change := range result.Changes {
+		if strings.HasPrefix(change.Path, ".agent-layer/") {
+			t.Fatalf("expected sources excluded from outputs, got %s", change.Path)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(root, "CLAUDE.md")); !os.IsNotExist(err) {
+		t.Fatalf("expected the repo left untouched, stat err %v", err)
+	}
+}
+
+func TestCompare_GitRefWithoutSources(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	root := t.TempDir()
+	writeRepo(t, root, "rule\n")
+	git(t, root, "init", "--quiet")
+	if err := os.WriteFile(filepath.Join(root, "README.md"), []byte("readme"), 0o600); err != nil {
+		t.Fatalf("write readme: %v", err)
+	}
+	git(t, root, "add", "README.md")
+	git(t, root, "commit", "--quiet", "-m", "base")
+
+	_, err := Compare(Options{Root: root, Against: "HEAD"})
+	if err == nil || !strings.Contains(err.Error(), "is .agent-layer/ committed") {
+		t.Fatalf("expected missing-sources error, got %v", err)
+	}
+}
+
+func TestCompare_Version(t *testing.T) {
+	root := t.TempDir()
+	writeRepo(t, root, "rule\n")
+
+	origBinary, origRun := cachedBinaryPath, runReleaseSync
+	t.Cleanup(func() { cachedBinaryPath, runReleaseSync = origBinary, origRun })
+	cachedBinaryPath = func(v string, _ io.Writer) (string, error) {
+		if v != "1.2.0" {
+			t.Fatalf("expected normalized version, got %q", v)
+		}
+		return "/cache/al", nil
+	}
+	var renderedDir string
+	runReleaseSync = func(binary string, release string, dir string) error {
+		if binary != "/cache/al" || release != "1.2.0" {
+			t.Fatalf("unexpected release run %s %s", binary, release)
+		}
+		if _, err := os.Stat(filepath.Join(dir, agentLayerDir, "tmp")); !os.IsNotExist(err) {
+			t.Fatalf("expected runtime directories skipped, stat err %v", err)
+		}
+		renderedDir = dir
+		return os.WriteFile(filepath.Join(dir, "CLAUDE.md"), []byte("old render in "+dir+"\n"), 0o600)
+	}
+
+	result, err := Compare(Options{Root: root, Against: "v1.2.0"})
+	if err != nil {
+		t.Fatalf("compare: %v", err)
+	}
+	if result.Kind != KindVersion || result.Against != "1.2.0" {
+		t.Fatalf("unexpected target %s %s", result.Kind, result.Against)
+	}
+	change, ok := findChange(result.Changes, "CLAUDE.md")
+	if !ok || change.Against != "old render in "+root+"\n" || !change.InCurrent {
+		t.Fatalf("expected scratch path rewritten to the repo root, got %#v", change)
+	}
+	if renderedDir == "" || strings.Contains(change.Against, renderedDir) {
+		t.Fatalf("expected scratch directory hidden from output")
+	}
+	if _, ok := findChange(result.Changes, ".gitignore"); !ok {
+		t.Fatalf("expected files only the current render writes to be reported")
+	}
+}
+
+func TestCompare_VersionDownloadError(t *testing.T) {
+	root := t.TempDir()
+	writeRepo(t, root, "rule\n")
+	orig := cachedBinaryPath
+	t.Cleanup(func() { cachedBinaryPath = orig })
+	cachedBinaryPath = func(string, io.Writer) (string, error) { return "", errors.New("offline") }
+
+	if _, err := Compare(Options{Root: root, Against: "1.2.0"}); err == nil || !strings.Contains(err.Error(), "offline") {
+		t.Fatalf("expected download error, got %v", err)
+	}
+}
+
+func TestCompare_AgainstRequired(t *testing.T) {
+	if _, err := Compare(Options{Root: t.TempDir(), Against: " "}); err == nil {
+		t.Fatalf("expected error for an empty target")
+	}
+}
+
+func TestDiffOutputs(t *testing.T) {
+	changes := diffOutputs(
+		map[string]string{"a": "1", "b": "same", "gone": "x"},
+		map[string]string{"a": "2", "b": "same", "new": ""},
+	)
+	var paths []string
+	for _, change := range changes {
+		paths = append(paths, change.Path)
+	}
+	if strings.Join(paths, ",") != "a,gone,new" {
+		t.Fatalf("unexpected changes %v", paths)
+	}
+	if changes[1].InCurrent || !changes[2].InCurrent || changes[2].InAgainst {
+		t.Fatalf("unexpected presence flags %#v", changes)
+	}
+}
+
+func TestRender_RedactsEnvValues(t *testing.T) {
+	root := t.TempDir()
+	writeRepo(t, root, "rules")
+	agentDir := filepath.Join(root, agentLayerDir)
+	base, err := os.ReadFile(filepath.Join(agentDir, "config.toml"))
+	if err != nil {
+		t.Fatalf("read config: %v", err)
+	}
+	// Codex receives resolved values; Claude keeps the placeholder itself.
+	config := strings.Replace(string(base), "[agents.codex]\nenabled = false", "[agents.codex]\nenabled = true", 1) + "\n[[mcp.servers]]\nid = \"docs\"\nenabled = true\ntransport = \"stdio\"\ncommand = \"docs-mcp\"\nenv = { DOCS_TOKEN = \"${AL_DOCS_TOKEN}\" }\n"
+	if err := os.WriteFile(filepath.Join(agentDir, "config.toml"), []byte(config), 0o600); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(agentDir, ".env"), []byte("AL_DOCS_TOKEN=s3cret\n"), 0o600); err != nil {
+		t.Fatalf("write env: %v", err)
+	}
+
+	redacted, err := Render(root, true)
+	if err != nil {
+		t.Fatalf("Render(redact): %v", err)
+	}
+	if _, ok := redacted["CLAUDE.md"]; !ok {
+		t.Fatalf("expected CLAUDE.md in outputs: %v", redacted)
+	}
+	for rel, content := range redacted {
+		if strings.Contains(content, "s3cret") {
+			t.Fatalf("%s leaks the secret:\n%s", rel, content)
+		}
+	}
+	plain, err := Render(root, false)
+	if err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+	leaked := false
+	for _, content := range plain {
+		leaked = leaked || strings.Contains(content, "s3cret")
+	}
+	if !leaked {
+		t.Fatal("expected unredacted outputs to carry the secret")
+	}
+	if env, _ := os.ReadFile(filepath.Join(agentDir, ".env")); string(env) != "AL_DOCS_TOKEN=s3cret\n" {
+		t.Fatalf("repo .env changed: %q", env)
+	}
+}
+
+func TestRenderTo_WritesOutputsOutsideRepo(t *testing.T) {
+	root := t.TempDir()
+	writeRepo(t, root, "rules")
+	dest := filepath.Join(t.TempDir(), "preview")
+
+	written, err := RenderTo(root, dest, renderInProcess(root))
+	if err != nil {
+		t.Fatalf("RenderTo: %v", err)
+	}
+	if !slices.Contains(written, "CLAUDE.md") || !slices.IsSorted(written) {
+		t.Fatalf("unexpected written paths: %v", written)
+	}
+	for _, rel := range written {
+		if strings.HasPrefix(rel, ".agent-layer/state/") || rel == ".agent-layer/sync.lock" {
+			t.Fatalf("runtime file %s was written", rel)
+		}
+	}
+	claude, err := os.ReadFile(filepath.Join(dest, "CLAUDE.md"))
+	if err != nil || !strings.Contains(string(claude), "rules") {
+		t.Fatalf("expected rendered CLAUDE.md under dest, got %q err %v", claude, err)
+	}
+	if _, err := os.Stat(filepath.Join(root, "CLAUDE.md")); !os.IsNotExist(err) {
+		t.Fatalf("repo must not be written, stat err %v", err)
+	}
+
+	failing := func(string) error { return errors.New("boom") }
+	if _, err := RenderTo(root, dest, failing); err == nil || !strings.Contains(err.Error(), "boom") {
+		t.Fatalf("expected render error, got %v", err)
+	}
+}
+
+func TestRender_ResolvesProjectVariablesAgainstRepo(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "myproj")
+	writeRepo(t, root, "Project: {{project.name}}\n")
+
+	outputs, err := Render(root, false)
+	if err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+	if !strings.Contains(outputs["CLAUDE.md"], "Project: myproj") {
+		t.Fatalf("CLAUDE.md does not name the repo:\n%s", outputs["CLAUDE.md"])
+	}
+}
@@ -0,0 +1,200 @@
+// Package plugin runs external Agent Layer plugins: executables named
+// al-<name> on PATH, or at a path [plugins.paths] declares. A plugin adds a
+// command (`al <name>` runs it with the remaining arguments) or a client
+// projection (sync sends it the resolved project as JSON on stdin and writes
+// the artifacts it prints as JSON on stdout).
+package plugin
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/pelletier/go-toml/v2"
+
+	"github.com/conn-castle/agent-layer/internal/config"
+	"github.com/conn-castle/agent-layer/internal/i18n"
+	"github.com/conn-castle/agent-layer/internal/messages"
+)
+
+// SchemaVersion is the version of the render request and response this build
+// speaks. A plugin must answer with the same version.
+const SchemaVersion = 1
+
+// ExecutablePrefix prefixes a plugin's name to form its executable name.
+const ExecutablePrefix = "al-"
+
+// EnvRepoRoot tells a plugin command the repo root `al` resolved, when there
+// is one.
+const EnvRepoRoot = "AL_REPO_ROOT"
+
+// Command returns the command line that runs plugin name: its configured
+// path as written in config.toml, or al-<name>. commands.allow is matched
+// against it.
+func Command(name string, paths map[string]string) string {
+	if path := strings.TrimSpace(paths[name]); path != "" {
+		return path
+	}
+	return ExecutablePrefix + name
+}
+
+// Lookup resolves plugin name to an executable. A configured path is
+// resolved against root; otherwise al-<name> is looked up on PATH.
+func Lookup(root string, name string, paths map[string]string) (string, error) {
+	if path := strings.TrimSpace(paths[name]); path != "" {
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(root, filepath.FromSlash(path))
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			return "", i18n.Errorf(messages.PluginNotFoundPathFmt, name, path, err)
+		}
+		if info.IsDir() {
+			return "", i18n.Errorf(messages.PluginNotFoundPathFmt, name, path, errors.New(i18n.T(messages.PluginPathIsDir)))
+		}
+		return path, nil
+	}
+	path, err := lookPath(ExecutablePrefix + name)
+	if err != nil {
+		return "", i18n.Errorf(messages.PluginNotFoundFmt, name, ExecutablePrefix+name)
+	}
+	return path, nil
+}
+
+var lookPath = exec.LookPath
+
+// Request is the JSON a renderer plugin reads on stdin.
+type Request struct {
+	SchemaVersion int    `json:"schema_version"`
+	Plugin        string `json:"plugin"`
+	Root          string `json:"root"`
+	// Config is the effective config.toml (after extends) with its TOML key
+	// names. Secrets from .agent-layer/.env are not included.
+	Config  map[string]any `json:"config"`
+	Sources Sources        `json:"sources"`
+}
+
+// Sources are the instructions and skills sync renders for every client.
+type Sources struct {
+	Instructions []Instruction `json:"instructions"`
+	Skills       []Skill       `json:"skills"`
+}
+
+// Instruction is one file under .agent-layer/instructions/, with instruction
+// variables expanded.
+type Instruction struct {
+	Name    string `json:"name"`
+	Content string `json:"content"`
+}
+
+// Skill is one skill from .agent-layer/skills/.
+type Skill struct {
+	Name          string            `json:"name"`
+	Description   string            `json:"description"`
+	License       string            `json:"license,omitempty"`
+	Compatibility string            `json:"compatibility,omitempty"`
+	Metadata      map[string]string `json:"metadata,omitempty"`
+	AllowedTools  string            `json:"allowed_tools,omitempty"`
+	Requires      []string          `json:"requires,omitempty"`
+	Body          string            `json:"body"`
+}
+
+// Response is the JSON a renderer plugin prints on stdout.
+type Response struct {
+	SchemaVersion int        `json:"schema_version"`
+	Artifacts     []Artifact `json:"artifacts"`
+}
+
+// Artifact is one file a renderer plugin asks sync to write. Path is
+// repo-relative and slash-separated.
+type Artifact struct {
+	Path       string `json:"path"`
+	Content    string `json:"content"`
+	Executable bool   `json:"executable,omitempty"`
+}
+
+// NewRequest builds the render request for plugin name from the resolved
+// project.
+func NewRequest(name string, root string, project *config.ProjectConfig) (Request, error) {
+	data, err := toml.Marshal(project.Config)
+	if err != nil {
+		return Request{}, i18n.Errorf(messages.PluginEncodeRequestFailedFmt, name, err)
+	}
+	var cfg map[string]any
+	if err := toml.Unmarshal(data, &cfg); err != nil {
+		return Request{}, i18n.Errorf(messages.PluginEncodeRequestFailedFmt, name, err)
+	}
+	req := Request{
+		SchemaVersion: SchemaVersion,
+		Plugin:        name,
+		Root:          root,
+		Config:        cfg,
+		Sources: Sources{
+			Instructions: make([]Instruction, 0, len(project.Instructions)),
+			Skills:       make([]Skill, 0, len(project.Skills)),
+		},
+	}
+	for _, instruction := range project.Instructions {
+		req.Sources.Instructions = append(req.Sources.Instructions, Instruction{Name: instruction.Name, Content: instruction.Content})
+	}
+	for _, skill := range project.Skills {
+		req.Sources.Skills = append(req.Sources.Skills, Skill{
+			Name:          skill.Name,
+			Description:   skill.Description,
+			License:       skill.License,
+			Compatibility: skill.Compatibility,
+			Metadata:      skill.Metadata,
+			AllowedTools:  skill.AllowedTools,
+			Requires:      skill.Requires,
+			Body:          skill.Body,
+		})
+	}
+	return req, nil
+}
+
+// runExecutable runs path from dir with stdin and returns its stdout. A
+// non-zero exit reports the plugin's stderr.
+var runExecutable = func(path string, dir string, stdin []byte) ([]byte, error) {
+	cmd := exec.Command(path) // #nosec G204 -- renderer plugins come from the repo's own config.toml and must match commands.allow.
+	cmd.Dir = dir
+	cmd.Env = os.Environ()
+	cmd.Stdin = bytes.NewReader(stdin)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if detail := strings.TrimSpace(stderr.String()); detail != "" {
+			return nil, fmt.Errorf("%w: %s", err, detail)
+		}
+		return nil, err
+	}
+	return stdout.Bytes(), nil
+}
+
+// Render runs the renderer plugin at path from root with req on stdin and
+// decodes its response.
+func Render(path string, root string, req Request) (Response, error) {
+	input, err := json.Marshal(req)
+	if err != nil {
+		return Response{}, i18n.Errorf(messages.PluginEncodeRequestFailedFmt, req.Plugin, err)
+	}
+	output, err := runExecutable(path, root, input)
+	if err != nil {
+		return Response{}, i18n.Errorf(messages.PluginRunFailedFmt, req.Plugin, err)
+	}
+	var resp Response
+	decoder := json.NewDecoder(bytes.NewReader(output))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&resp); err != nil {
+		return Response{}, i18n.Errorf(messages.PluginDecodeResponseFailedFmt, req.Plugin, err)
+	}
+	if resp.SchemaVersion != SchemaVersion {
+		return Response{}, i18n.Errorf(messages.PluginSchemaVersionFmt, req.Plugin, resp.SchemaVersion, SchemaVersion)
+	}
+	return resp, nil
+}
@@ -0,0 +1,154 @@
+package plugin
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/conn-castle/agent-layer/internal/config"
+)
+
+func TestCommand(t *testing.T) {
+	paths := map[string]string{"zed": "./tools/al-zed"}
+	if got := Command("zed", paths); got != "./tools/al-zed" {
+		t.Fatalf("Command(zed) = %q", got)
+	}
+	if got := Command("helix", paths); got != "al-helix" {
+		t.Fatalf("Command(helix) = %q", got)
+	}
+}
+
+func TestLookup(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "tools", "dir"), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "tools", "al-zed"), []byte("#!/bin/sh\n"), 0o700); err != nil { // #nosec G306 -- test writes an executable plugin stub.
+		t.Fatalf("write plugin: %v", err)
+	}
+	paths := map[string]string{"zed": "tools/al-zed", "dir": "tools/dir", "gone": "tools/al-gone"}
+
+	got, err := Lookup(root, "zed", paths)
+	if err != nil || got != filepath.Join(root, "tools", "al-zed") {
+		t.Fatalf("Lookup(zed) = %q, %v", got, err)
+	}
+	if _, err := Lookup(root, "dir", paths); err == nil || !strings.Contains(err.Error(), "is a directory") {
+		t.Fatalf("Lookup(dir) error = %v", err)
+	}
+	if _, err := Lookup(root, "gone", paths); err == nil || !strings.Contains(err.Error(), "plugin gone") {
+		t.Fatalf("Lookup(gone) error = %v", err)
+	}
+
+	original := lookPath
+	t.Cleanup(func() { lookPath = original })
+	lookPath = func(file string) (string, error) {
+		if file == "al-helix" {
+			return "/usr/local/bin/al-helix", nil
+		}
+		return "", errors.New("not found")
+	}
+	if got, err := Lookup(root, "helix", nil); err != nil || got != "/usr/local/bin/al-helix" {
+		t.Fatalf("Lookup(helix) = %q, %v", got, err)
+	}
+	if _, err := Lookup(root, "missing", nil); err == nil || !strings.Contains(err.Error(), "al-missing not found on PATH") {
+		t.Fatalf("Lookup(missing) error = %v", err)
+	}
+}
+
+func TestNewRequest(t *testing.T) {
+	enabled := true
+	project := &config.ProjectConfig{
+		Config:       config.Config{Agents: config.AgentsConfig{Claude: config.ClaudeConfig{Enabled: &enabled}}},
+		Env:          map[string]string{"SECRET": "value"},
+		Instructions: []config.InstructionFile{{Name: "00_base.md", Content: "base"}},
+		Skills:       []config.Skill{{Name: "audit", Description: "Audit code", Body: "steps", SourceDir: "/abs/skills/audit"}},
+	}
+	req, err := NewRequest("zed", "/repo", project)
+	if err != nil {
+		t.Fatalf("NewRequest: %v", err)
+	}
+	data, err := json.Marshal(req)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	encoded := string(data)
+	for _, want := range []string{`"schema_version":1`, `"plugin":"zed"`, `"claude":{"enabled":true`, `"name":"00_base.md"`, `"description":"Audit code"`} {
+		if !strings.Contains(encoded, want) {
+			t.Fatalf("request missing %s:\n%s", want, encoded)
+		}
+	}
+	for _, unwanted := range []string{"SECRET", "/abs/skills"} {
+		if strings.Contains(encoded, unwanted) {
+			t.Fatalf("request leaks %s:\n%s", unwanted, encoded)
+		}
+	}
+}
+
+func TestRender(t *testing.T) {
+	original := runExecutable
+	t.Cleanup(func() { runExecutable = original })
+	tests := []struct {
+		name   string
+		output string
+		err    error
+		want   string
+	}{
+		{name: "ok", output: `{"schema_version":1,"artifacts":[{"path":".zed/rules.md","content":"rules\n","executable":true}]}`},
+		{name: "run error", err: errors.New("exit status 2: boom"), want: "plugin zed failed: exit status 2: boom"},
+		{name: "invalid json", output: `not json`, want: "invalid render response"},
+		{name: "unknown field", output: `{"schema_version":1,"artifacts":[{"path":"a","contents":"x"}]}`, want: "invalid render response"},
+		{name: "schema version", output: `{"schema_version":2,"artifacts":[]}`, want: "schema_version 2 is not supported"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdin []byte
+			runExecutable = func(path string, dir string, input []byte) ([]byte, error) {
+				stdin = input
+				return []byte(tt.output), tt.err
+			}
+			resp, err := Render("/bin/al-zed", "/repo", Request{SchemaVersion: SchemaVersion, Plugin: "zed", Root: "/repo"})
+			if tt.want != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.want) {
+					t.Fatalf("error = %v, want containing %q", err, tt.want)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Render: %v", err)
+			}
+			if !strings.Contains(string(stdin), `"plugin":"zed"`) {
+				t.Fatalf("stdin = %s", stdin)
+			}
+			if len(resp.Artifacts) != 1 || resp.Artifacts[0].Path != ".zed/rules.md" || !resp.Artifacts[0].Executable {
+				t.Fatalf("artifacts = %+v", resp.Artifacts)
+			}
+		})
+	}
+}
+
+func TestRender_RunsExecutable(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "al-zed")
+	script := "#!/bin/sh\ncat >/dev/null\nprintf '{\"schema_version\":1,\"artifacts\":[{\"path\":\"out.md\",\"content\":\"hi\"}]}'\n"
+	if err := os.WriteFile(path, []byte(script), 0o700); err != nil { // #nosec G306 -- test writes an executable plugin stub.
+		t.Fatalf("write plugin: %v", err)
+	}
+	resp, err := Render(path, dir, Request{SchemaVersion: SchemaVersion, Plugin: "zed"})
+	if err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+	if len(resp.Artifacts) != 1 || resp.Artifacts[0].Content != "hi" {
+		t.Fatalf("artifacts = %+v", resp.Artifacts)
+	}
+
+	failing := filepath.Join(dir, "al-fail")
+	if err := os.WriteFile(failing, []byte("#!/bin/sh\necho broken >&2\nexit 3\n"), 0o700); err != nil { // #nosec G306 -- test writes an executable plugin stub.
+		t.Fatalf("write plugin: %v", err)
+	}
+	if _, err := Render(failing, dir, Request{SchemaVersion: SchemaVersion, Plugin: "fail"}); err == nil || !strings.Contains(err.Error(), "broken") {
+		t.Fatalf("error = %v, want stderr detail", err)
+	}
+}
@@ -0,0 +1,159 @@
+package policy
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/conn-castle/agent-layer/internal/config"
+	"github.com/conn-castle/agent-layer/internal/i18n"
+	"github.com/conn-castle/agent-layer/internal/messages"
+)
+
+// Rule names reported in Violation.Rule.
+const (
+	RuleBannedPhrase        = "banned_phrase"
+	RuleRequiredSection     = "required_section"
+	RuleMaxLines            = "max_lines"
+	RuleRequiredFrontmatter = "required_frontmatter"
+)
+
+// Violation is one broken rule.
+type Violation struct {
+	Rule string
+	// Subject is the repo-relative file the violation is in, or the
+	// instructions directory for rules that span every instruction file.
+	Subject string
+	Message string
+}
+
+// Check evaluates p against the project's instructions and skills and returns
+// every violation in a stable order. User-global skills are not repo content
+// and are skipped.
+func Check(p *Policy, project *config.ProjectConfig) []Violation {
+	if p == nil || project == nil {
+		return nil
+	}
+	var violations []Violation
+	for _, file := range project.Instructions {
+		subject := filepath.ToSlash(filepath.Join(".agent-layer", "instructions", file.Name))
+		violations = append(violations, bannedPhrases(p.BannedPhrases, subject, file.Content)...)
+	}
+	violations = append(violations, missingSections(p.Instructions.RequiredSections, project.Instructions)...)
+	for _, skill := range project.Skills {
+		if skill.Scope == config.SkillScopeUser {
+			continue
+		}
+		subject := skillSubject(project.Root, skill)
+		violations = append(violations, bannedPhrases(p.BannedPhrases, subject, skill.Description+"\n"+skill.Body)...)
+		if p.Skills.MaxLines > 0 {
+			if lines := countLines(skill.Body); lines > p.Skills.MaxLines {
+				violations = append(violations, Violation{
+					Rule:    RuleMaxLines,
+					Subject: subject,
+					Message: i18n.Sprintf(messages.PolicyMaxLinesFmt, skill.Name, lines, p.Skills.MaxLines),
+				})
+			}
+		}
+		for _, field := range p.Skills.RequiredFrontmatter {
+			if frontmatterValue(skill, field) == "" {
+				violations = append(violations, Violation{
+					Rule:    RuleRequiredFrontmatter,
+					Subject: subject,
+					Message: i18n.Sprintf(messages.PolicyMissingFrontmatterFmt, skill.Name, field),
+				})
+			}
+		}
+	}
+	return violations
+}
+
+// bannedPhrases reports each banned phrase found in content, with the first
+// line it appears on.
+func bannedPhrases(phrases []string, subject string, content string) []Violation {
+	if len(phrases) == 0 {
+		return nil
+	}
+	lines := strings.Split(strings.ToLower(content), "\n")
+	var violations []Violation
+	for _, phrase := range phrases {
+		needle := strings.ToLower(phrase)
+		for i, line := range lines {
+			if strings.Contains(line, needle) {
+				violations = append(violations, Violation{
+					Rule:    RuleBannedPhrase,
+					Subject: subject,
+					Message: i18n.Sprintf(messages.PolicyBannedPhraseFmt, phrase, i+1),
+				})
+				break
+			}
+		}
+	}
+	return violations
+}
+
+// missingSections reports required sections that no instruction file has as
+// a Markdown heading. Headings match case-insensitively at any level.
+func missingSections(required []string, files []config.InstructionFile) []Violation {
+	if len(required) == 0 {
+		return nil
+	}
+	headings := make(map[string]bool)
+	for _, file := range files {
+		for _, line := range strings.Split(file.Content, "\n") {
+			if strings.HasPrefix(strings.TrimSpace(line), "#") {
+				headings[strings.ToLower(headingText(line))] = true
+			}
+		}
+	}
+	var violations []Violation
+	for _, section := range required {
+		if !headings[strings.ToLower(headingText(section))] {
+			violations = append(violations, Violation{
+				Rule:    RuleRequiredSection,
+				Subject: ".agent-layer/instructions",
+				Message: i18n.Sprintf(messages.PolicyMissingSectionFmt, headingText(section)),
+			})
+		}
+	}
+	return violations
+}
+
+// headingText strips Markdown heading markers, so "## Testing" and "Testing"
+// compare equal.
+func headingText(line string) string {
+	return strings.TrimSpace(strings.TrimLeft(strings.TrimSpace(line), "#"))
+}
+
+func frontmatterValue(skill config.Skill, field string) string {
+	if key, ok := strings.CutPrefix(field, "metadata."); ok {
+		return strings.TrimSpace(skill.Metadata[key])
+	}
+	switch field {
+	case "name":
+		return strings.TrimSpace(skill.Name)
+	case "description":
+		return strings.TrimSpace(skill.Description)
+	case "license":
+		return strings.TrimSpace(skill.License)
+	case "compatibility":
+		return strings.TrimSpace(skill.Compatibility)
+	case "allowed-tools":
+		return strings.TrimSpace(skill.AllowedTools)
+	}
+	return ""
+}
+
+func skillSubject(root string, skill config.Skill) string {
+	if rel, err := filepath.Rel(root, skill.SourcePath); err == nil && skill.SourcePath != "" {
+		return filepath.ToSlash(rel)
+	}
+	return skill.Name
+}
+
+func countLines(body string) int {
+	trimmed := strings.TrimRight(body, "\n")
+	if trimmed == "" {
+		return 0
+	}
+	return strings.Count(trimmed, "\n") + 1
+}
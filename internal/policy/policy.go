@@ -0,0 +1,142 @@
+// Package policy enforces repo-defined content rules on instructions and
+// skills. Rules live in .agent-layer/policy.toml; `al policy check` reports
+// every violation, and sync reports them at the severity the file selects.
+package policy
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pelletier/go-toml/v2"
+
+	"github.com/conn-castle/agent-layer/internal/i18n"
+	"github.com/conn-castle/agent-layer/internal/layerdir"
+	"github.com/conn-castle/agent-layer/internal/messages"
+)
+
+// FileName is the policy file under .agent-layer/.
+const FileName = "policy.toml"
+
+// Sync severities for Policy.Sync.Severity.
+const (
+	SeverityOff     = "off"
+	SeverityWarning = "warning"
+	SeverityError   = "error"
+)
+
+// frontmatterFields are the SKILL.md keys a policy may require. metadata.<key>
+// is accepted in addition to these.
+var frontmatterFields = []string{"name", "description", "license", "compatibility", "allowed-tools"}
+
+// Policy is the parsed policy.toml.
+type Policy struct {
+	// BannedPhrases must not appear (case-insensitively) in any instruction
+	// file or skill.
+	BannedPhrases []string         `toml:"banned_phrases"`
+	Instructions  InstructionRules `toml:"instructions"`
+	Skills        SkillRules       `toml:"skills"`
+	Sync          SyncRules        `toml:"sync"`
+}
+
+// InstructionRules constrain .agent-layer/instructions/.
+type InstructionRules struct {
+	// RequiredSections are Markdown heading texts that must appear in at
+	// least one instruction file.
+	RequiredSections []string `toml:"required_sections"`
+}
+
+// SkillRules constrain every skill under .agent-layer/skills/.
+type SkillRules struct {
+	// MaxLines caps the SKILL.md body length; zero means no limit.
+	MaxLines int `toml:"max_lines"`
+	// RequiredFrontmatter lists SKILL.md keys every skill must set, such as
+	// "license" or "metadata.owner".
+	RequiredFrontmatter []string `toml:"required_frontmatter"`
+}
+
+// SyncRules control enforcement during `al sync`.
+type SyncRules struct {
+	// Severity is SeverityOff, SeverityWarning (the default), or SeverityError.
+	Severity string `toml:"severity"`
+}
+
+// Path returns the policy file path for root.
+func Path(root string) string {
+	return filepath.Join(layerdir.Dir(root), FileName)
+}
+
+// Load reads and validates root's policy file. It returns nil without error
+// when the repo has no policy.
+func Load(root string) (*Policy, error) {
+	path := Path(root)
+	data, err := os.ReadFile(path) // #nosec G304 -- path is the fixed policy file under the repo's .agent-layer/.
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, i18n.Errorf(messages.PolicyReadFmt, path, err)
+	}
+	var p Policy
+	decoder := toml.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&p); err != nil {
+		return nil, i18n.Errorf(messages.PolicyInvalidFmt, path, err)
+	}
+	if err := p.validate(); err != nil {
+		return nil, i18n.Errorf(messages.PolicyInvalidFmt, path, err)
+	}
+	return &p, nil
+}
+
+// SyncSeverity returns the effective sync severity.
+func (p *Policy) SyncSeverity() string {
+	if p == nil {
+		return SeverityOff
+	}
+	if p.Sync.Severity == "" {
+		return SeverityWarning
+	}
+	return p.Sync.Severity
+}
+
+func (p *Policy) validate() error {
+	switch p.Sync.Severity {
+	case "", SeverityOff, SeverityWarning, SeverityError:
+	default:
+		return i18n.Errorf(messages.PolicyInvalidSeverityFmt, p.Sync.Severity)
+	}
+	if p.Skills.MaxLines < 0 {
+		return i18n.Errorf(messages.PolicyInvalidMaxLinesFmt, p.Skills.MaxLines)
+	}
+	for _, phrase := range p.BannedPhrases {
+		if strings.TrimSpace(phrase) == "" {
+			return errors.New(i18n.T(messages.PolicyEmptyBannedPhrase))
+		}
+	}
+	for _, section := range p.Instructions.RequiredSections {
+		if headingText(section) == "" {
+			return errors.New(i18n.T(messages.PolicyEmptyRequiredSection))
+		}
+	}
+	for _, field := range p.Skills.RequiredFrontmatter {
+		if !knownFrontmatterField(field) {
+			return i18n.Errorf(messages.PolicyUnknownFrontmatterFmt, field, strings.Join(frontmatterFields, ", "))
+		}
+	}
+	return nil
+}
+
+func knownFrontmatterField(field string) bool {
+	if key, ok := strings.CutPrefix(field, "metadata."); ok {
+		return strings.TrimSpace(key) != ""
+	}
+	for _, known := range frontmatterFields {
+		if field == known {
+			return true
+		}
+	}
+	return false
+}
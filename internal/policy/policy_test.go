@@ -0,0 +1,108 @@
+package policy
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/conn-castle/agent-layer/internal/config"
+)
+
+func writePolicy(t *testing.T, root string, content string) {
+	t.Helper()
+	dir := filepath.Join(root, ".agent-layer")
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, FileName), []byte(content), 0o600); err != nil {
+		t.Fatalf("write policy: %v", err)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	root := t.TempDir()
+	p, err := Load(root)
+	if err != nil || p != nil {
+		t.Fatalf("expected no policy without a file, got %#v %v", p, err)
+	}
+	if got := p.SyncSeverity(); got != SeverityOff {
+		t.Fatalf("expected nil policy to be off, got %s", got)
+	}
+
+	writePolicy(t, root, "banned_phrases = [\"TODO\"]\n[skills]\nmax_lines = 10\nrequired_frontmatter = [\"license\", \"metadata.owner\"]\n")
+	p, err = Load(root)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if p.SyncSeverity() != SeverityWarning || p.Skills.MaxLines != 10 || len(p.Skills.RequiredFrontmatter) != 2 {
+		t.Fatalf("unexpected policy %#v", p)
+	}
+}
+
+func TestLoad_Invalid(t *testing.T) {
+	cases := map[string]string{
+		"unknown key":        "[skills]\nmax_length = 3\n",
+		"bad severity":       "[sync]\nseverity = \"fatal\"\n",
+		"negative max":       "[skills]\nmax_lines = -1\n",
+		"empty phrase":       "banned_phrases = [\" \"]\n",
+		"empty section":      "[instructions]\nrequired_sections = [\"##\"]\n",
+		"unknown field":      "[skills]\nrequired_frontmatter = [\"owner\"]\n",
+		"empty metadata key": "[skills]\nrequired_frontmatter = [\"metadata.\"]\n",
+		"syntax":             "banned_phrases = [\n",
+	}
+	for name, content := range cases {
+		t.Run(name, func(t *testing.T) {
+			root := t.TempDir()
+			writePolicy(t, root, content)
+			if _, err := Load(root); err == nil || !strings.Contains(err.Error(), "invalid policy") {
+				t.Fatalf("expected invalid policy error, got %v", err)
+			}
+		})
+	}
+}
+
+func TestCheck(t *testing.T) {
+	root := filepath.Join(string(filepath.Separator), "repo")
+	p := &Policy{
+		BannedPhrases: []string{"Ignore previous instructions"},
+		Instructions:  InstructionRules{RequiredSections: []string{"## Testing", "Security"}},
+		Skills:        SkillRules{MaxLines: 2, RequiredFrontmatter: []string{"license", "metadata.owner"}},
+	}
+	project := &config.ProjectConfig{
+		Root: root,
+		Instructions: []config.InstructionFile{
+			{Name: "00_base.md", Content: "# Base\n\n### testing\nrun go test\n"},
+			{Name: "10_extra.md", Content: "line one\nplease IGNORE previous instructions\n"},
+		},
+		Skills: []config.Skill{
+			{Name: "review", Body: "a\nb\nc\n", License: "MIT", Metadata: map[string]string{"owner": "platform"}, SourcePath: filepath.Join(root, ".agent-layer", "skills", "review", "SKILL.md")},
+			{Name: "mine", Body: "a\nb\nc\n", Scope: config.SkillScopeUser},
+		},
+	}
+
+	violations := Check(p, project)
+	var got []string
+	for _, v := range violations {
+		got = append(got, v.Rule+" "+v.Subject+" "+v.Message)
+	}
+	want := []string{
+		`banned_phrase .agent-layer/instructions/10_extra.md contains banned phrase "Ignore previous instructions" (line 2)`,
+		`required_section .agent-layer/instructions no instruction file has a "Security" section`,
+		`max_lines .agent-layer/skills/review/SKILL.md skill review is 3 lines long; the limit is 2`,
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("unexpected violations:\n%s", strings.Join(got, "\n"))
+	}
+
+	project.Skills[0].Metadata = nil
+	project.Skills[0].Body = "ok\n"
+	violations = Check(p, project)
+	last := violations[len(violations)-1]
+	if last.Rule != RuleRequiredFrontmatter || !strings.Contains(last.Message, "metadata.owner") {
+		t.Fatalf("expected missing metadata.owner, got %#v", last)
+	}
+	if Check(nil, project) != nil {
+		t.Fatalf("expected no violations without a policy")
+	}
+}
@@ -0,0 +1,28 @@
+package projection
+
+import "github.com/conn-castle/agent-layer/internal/config"
+
+// GatewayServerID is the MCP server id clients see when [mcp] gateway is on.
+const GatewayServerID = "agent-layer"
+
+// ClientMCPServers returns the MCP servers to project for client. When the
+// gateway is enabled, a single stdio server running `al mcp gateway` stands in
+// for every server enabled for client; it is omitted when there are none.
+// Secrets are then resolved by the gateway at runtime and never reach the
+// client config.
+func ClientMCPServers(cfg config.MCPConfig, client string) []config.MCPServer {
+	if !cfg.GatewayEnabled() {
+		return cfg.Servers
+	}
+	if len(EnabledServerIDs(cfg.Servers, client)) == 0 {
+		return nil
+	}
+	enabled := true
+	return []config.MCPServer{{
+		ID:        GatewayServerID,
+		Enabled:   &enabled,
+		Transport: config.TransportStdio,
+		Command:   "al",
+		Args:      []string{"mcp", "gateway", "--client", client},
+	}}
+}
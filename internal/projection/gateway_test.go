@@ -0,0 +1,51 @@
+package projection
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/conn-castle/agent-layer/internal/config"
+)
+
+func TestClientMCPServers(t *testing.T) {
+	enabled := true
+	servers := []config.MCPServer{
+		{ID: "github", Enabled: &enabled, Transport: config.TransportHTTP, URL: "https://example.com/mcp"},
+		{ID: "local", Enabled: &enabled, Transport: config.TransportStdio, Command: "tool", Clients: []string{"codex"}},
+	}
+
+	if got := ClientMCPServers(config.MCPConfig{Servers: servers}, "claude"); !reflect.DeepEqual(got, servers) {
+		t.Fatalf("gateway off: got %+v", got)
+	}
+
+	cfg := config.MCPConfig{Gateway: &enabled, Servers: servers}
+	got := ClientMCPServers(cfg, "codex")
+	if len(got) != 1 {
+		t.Fatalf("expected one gateway server, got %+v", got)
+	}
+	gateway := got[0]
+	if gateway.ID != GatewayServerID || gateway.Command != "al" || gateway.Transport != config.TransportStdio {
+		t.Fatalf("unexpected gateway server: %+v", gateway)
+	}
+	if want := []string{"mcp", "gateway", "--client", "codex"}; !reflect.DeepEqual(gateway.Args, want) {
+		t.Fatalf("args = %v, want %v", gateway.Args, want)
+	}
+	if ids := EnabledServerIDs(got, "codex"); !reflect.DeepEqual(ids, []string{GatewayServerID}) {
+		t.Fatalf("enabled ids = %v", ids)
+	}
+}
+
+func TestClientMCPServers_GatewayWithoutServers(t *testing.T) {
+	enabled := true
+	disabled := false
+	cfg := config.MCPConfig{
+		Gateway: &enabled,
+		Servers: []config.MCPServer{
+			{ID: "off", Enabled: &disabled, Transport: config.TransportStdio, Command: "tool"},
+			{ID: "codex-only", Enabled: &enabled, Transport: config.TransportStdio, Command: "tool", Clients: []string{"codex"}},
+		},
+	}
+	if got := ClientMCPServers(cfg, "claude"); got != nil {
+		t.Fatalf("expected no servers for claude, got %+v", got)
+	}
+}
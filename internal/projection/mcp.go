@@ -1,6 +1,7 @@
 package projection
 
 import (
+	"slices"
 	"sort"
 
 	"github.com/conn-castle/agent-layer/internal/config"
@@ -19,6 +20,31 @@ type ResolvedMCPServer struct {
 	Command       string
 	Args          []string
 	Env           map[string]string
+	ToolsAllow    []string
+	ToolsDeny     []string
+}
+
+// AllowsTool reports whether the server's tool filter exposes the named tool.
+func (s ResolvedMCPServer) AllowsTool(name string) bool {
+	if len(s.ToolsAllow) > 0 && !slices.Contains(s.ToolsAllow, name) {
+		return false
+	}
+	return !slices.Contains(s.ToolsDeny, name)
+}
+
+// AllowedTools returns ToolsAllow without the denied tools, or nil when no
+// allow list is set.
+func (s ResolvedMCPServer) AllowedTools() []string {
+	if len(s.ToolsAllow) == 0 {
+		return nil
+	}
+	allowed := make([]string, 0, len(s.ToolsAllow))
+	for _, name := range s.ToolsAllow {
+		if !slices.Contains(s.ToolsDeny, name) {
+			allowed = append(allowed, name)
+		}
+	}
+	return allowed
 }
 
 // EnabledServerIDs returns sorted MCP server ids enabled for the client.
@@ -160,3 +160,26 @@ func TestResolveMCPServersPathExpansionFailsWithoutRepoRoot(t *testing.T) {
 		t.Fatalf("unexpected error message: %v", err)
 	}
 }
+
+func TestResolvedMCPServerToolFilter(t *testing.T) {
+	unfiltered := ResolvedMCPServer{}
+	if !unfiltered.AllowsTool("any") || unfiltered.AllowedTools() != nil {
+		t.Fatalf("expected an unfiltered server to allow every tool")
+	}
+
+	server := ResolvedMCPServer{ToolsAllow: []string{"read", "write"}, ToolsDeny: []string{"write"}}
+	if !server.AllowsTool("read") {
+		t.Fatalf("expected read to be allowed")
+	}
+	if server.AllowsTool("write") || server.AllowsTool("delete") {
+		t.Fatalf("expected write and delete to be hidden")
+	}
+	if got := server.AllowedTools(); len(got) != 1 || got[0] != "read" {
+		t.Fatalf("AllowedTools = %v", got)
+	}
+
+	denyOnly := ResolvedMCPServer{ToolsDeny: []string{"write"}}
+	if !denyOnly.AllowsTool("read") || denyOnly.AllowsTool("write") {
+		t.Fatalf("unexpected deny-only filter result")
+	}
+}
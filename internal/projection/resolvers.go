@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	"github.com/conn-castle/agent-layer/internal/config"
+	"github.com/conn-castle/agent-layer/internal/i18n"
 	"github.com/conn-castle/agent-layer/internal/messages"
 )
 
@@ -65,8 +66,10 @@ func ResolveEnabledMCPServers(servers []config.MCPServer, env map[string]string)
 // resolveSingleServer resolves a single MCP server configuration.
 func resolveSingleServer(server config.MCPServer, env map[string]string, resolver EnvVarResolver) (ResolvedMCPServer, error) {
 	entry := ResolvedMCPServer{
-		ID:        server.ID,
-		Transport: server.Transport,
+		ID:         server.ID,
+		Transport:  server.Transport,
+		ToolsAllow: server.ToolsAllow,
+		ToolsDeny:  server.ToolsDeny,
 	}
 	repoRoot := env[config.BuiltinRepoRootEnvVar]
 
@@ -134,7 +137,7 @@ func resolveSingleServer(server config.MCPServer, env map[string]string, resolve
 			entry.Env = envMap
 		}
 	default:
-		return entry, fmt.Errorf(messages.MCPUnsupportedTransportFmt, server.Transport)
+		return entry, i18n.Errorf(messages.MCPUnsupportedTransportFmt, server.Transport)
 	}
 
 	return entry, nil
@@ -0,0 +1,312 @@
+// Package remotebase fetches, caches, and verifies git-hosted bundles: shared
+// base configs referenced by the `extends` key in .agent-layer/config.toml and
+// skills added with `al add skill`.
+package remotebase
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/conn-castle/agent-layer/internal/i18n"
+	"github.com/conn-castle/agent-layer/internal/messages"
+	"github.com/conn-castle/agent-layer/internal/offline"
+	"github.com/conn-castle/agent-layer/internal/versiondispatch"
+)
+
+const (
+	// ChecksumPrefix is the algorithm prefix required on pinned checksums.
+	ChecksumPrefix = "sha256:"
+
+	cacheSubdir      = "extends"
+	gitMetadataDir   = ".git"
+	fetchTimeout     = 2 * time.Minute
+	maxGitOutputSize = 512
+)
+
+var checksumPattern = regexp.MustCompile(`^sha256:[0-9a-f]{64}$`)
+
+// Source is a parsed bundle reference of the form host/owner/repo[/subdir]@ref.
+type Source struct {
+	Host   string
+	Owner  string
+	Repo   string
+	Subdir string
+	Ref    string
+}
+
+// ParseSource parses a bundle reference such as
+// "github.com/org/agent-layer-base@v1" or
+// "github.com/org/platform/agent-layer@v2" (bundle in a subdirectory).
+func ParseSource(raw string) (Source, error) {
+	trimmed := strings.TrimSpace(raw)
+	at := strings.LastIndex(trimmed, "@")
+	if at <= 0 || at == len(trimmed)-1 {
+		return Source{}, i18n.Errorf(messages.RemoteBundleSourceInvalidFmt, raw)
+	}
+	location, ref := trimmed[:at], trimmed[at+1:]
+	if strings.ContainsAny(ref, " \t") || strings.HasPrefix(ref, "-") {
+		return Source{}, i18n.Errorf(messages.RemoteBundleSourceInvalidFmt, raw)
+	}
+	parts := strings.Split(location, "/")
+	if len(parts) < 3 {
+		return Source{}, i18n.Errorf(messages.RemoteBundleSourceInvalidFmt, raw)
+	}
+	for _, part := range parts {
+		if part == "" || part == "." || part == ".." || strings.HasPrefix(part, "-") {
+			return Source{}, i18n.Errorf(messages.RemoteBundleSourceInvalidFmt, raw)
+		}
+	}
+	if !strings.Contains(parts[0], ".") {
+		return Source{}, i18n.Errorf(messages.RemoteBundleSourceInvalidFmt, raw)
+	}
+	return Source{
+		Host:   parts[0],
+		Owner:  parts[1],
+		Repo:   strings.TrimSuffix(parts[2], ".git"),
+		Subdir: strings.Join(parts[3:], "/"),
+		Ref:    ref,
+	}, nil
+}
+
+// String returns the canonical bundle reference.
+func (s Source) String() string {
+	location := path.Join(s.Host, s.Owner, s.Repo)
+	if s.Subdir != "" {
+		location = path.Join(location, s.Subdir)
+	}
+	return location + "@" + s.Ref
+}
+
+// CloneURL returns the HTTPS clone URL for the source repository.
+func (s Source) CloneURL() string {
+	return "https://" + path.Join(s.Host, s.Owner, s.Repo) + ".git"
+}
+
+// ValidateChecksum reports whether checksum is empty or a well-formed pin.
+func ValidateChecksum(checksum string) error {
+	if checksum == "" || checksumPattern.MatchString(checksum) {
+		return nil
+	}
+	return i18n.Errorf(messages.RemoteBundleChecksumInvalidFmt, checksum)
+}
+
+// Resolve returns the local directory holding the bundle for source, fetching
+// it into the user cache when missing. The cache is keyed by ref name, so a
+// moved ref is not picked up; extends supports tags only. When checksum is
+// non-empty the bundle contents must match it exactly.
+func Resolve(sys System, raw string, checksum string) (string, error) {
+	return resolve(sys, raw, checksum, false)
+}
+
+// Refresh refetches source even when it is cached, so a moved ref (for
+// example a branch) picks up new content, and returns the bundle directory.
+func Refresh(sys System, raw string) (string, error) {
+	return resolve(sys, raw, "", true)
+}
+
+func resolve(sys System, raw string, checksum string, refresh bool) (string, error) {
+	if sys == nil {
+		return "", errors.New(i18n.T(messages.RemoteBundleSystemRequired))
+	}
+	source, err := ParseSource(raw)
+	if err != nil {
+		return "", err
+	}
+	if err := ValidateChecksum(checksum); err != nil {
+		return "", err
+	}
+	cacheRoot, err := cacheRootDir(sys)
+	if err != nil {
+		return "", err
+	}
+	checkoutDir := filepath.Join(cacheRoot, cacheSubdir, source.Host, source.Owner, source.Repo, url.PathEscape(source.Ref))
+
+	_, statErr := sys.Stat(checkoutDir)
+	if statErr != nil && !errors.Is(statErr, fs.ErrNotExist) {
+		return "", i18n.Errorf(messages.RemoteBundleCheckCacheFmt, checkoutDir, statErr)
+	}
+	if statErr != nil || refresh {
+		if err := fetch(sys, source, checkoutDir, statErr == nil); err != nil {
+			return "", err
+		}
+	}
+
+	bundleDir := checkoutDir
+	if source.Subdir != "" {
+		bundleDir = filepath.Join(checkoutDir, filepath.FromSlash(source.Subdir))
+	}
+	info, err := sys.Stat(bundleDir)
+	if err != nil || !info.IsDir() {
+		return "", i18n.Errorf(messages.RemoteBundleMissingFmt, source.String(), bundleDir)
+	}
+	if checksum != "" {
+		actual, err := Checksum(bundleDir)
+		if err != nil {
+			return "", err
+		}
+		if actual != checksum {
+			return "", i18n.Errorf(messages.RemoteBundleChecksumMismatchFmt, source.String(), checksum, actual, checkoutDir)
+		}
+	}
+	return bundleDir, nil
+}
+
+// fetch clones source into a temporary sibling of dest and renames it into
+// place so concurrent readers never observe a partial checkout. When replace
+// is set, an existing dest is moved aside only after the clone succeeds.
+func fetch(sys System, source Source, dest string, replace bool) error {
+	if err := offline.Check(sys.Getenv, i18n.Sprintf(messages.OfflineOpFetchFmt, source.String())); err != nil {
+		return err
+	}
+	if strings.TrimSpace(sys.Getenv(versiondispatch.EnvNoNetwork)) != "" {
+		return i18n.Errorf(messages.RemoteBundleNotCachedFmt, source.String(), dest, versiondispatch.EnvNoNetwork)
+	}
+	parent := filepath.Dir(dest)
+	if err := sys.MkdirAll(parent, 0o755); err != nil { // #nosec G301 -- user-level cache dir holds non-secret shared config.
+		return i18n.Errorf(messages.RemoteBundleCreateCacheDirFmt, err)
+	}
+	tmp, err := sys.MkdirTemp(parent, ".fetch-*")
+	if err != nil {
+		return i18n.Errorf(messages.RemoteBundleCreateCacheDirFmt, err)
+	}
+	committed := false
+	defer func() {
+		if !committed {
+			_ = sys.RemoveAll(tmp)
+		}
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), fetchTimeout)
+	defer cancel()
+	checkout := filepath.Join(tmp, "checkout")
+	if err := sys.GitClone(ctx, source.CloneURL(), source.Ref, checkout); err != nil {
+		return i18n.Errorf(messages.RemoteBundleFetchFailedFmt, source.String(), err)
+	}
+	if err := sys.RemoveAll(filepath.Join(checkout, gitMetadataDir)); err != nil {
+		return i18n.Errorf(messages.RemoteBundleFetchFailedFmt, source.String(), err)
+	}
+	if err := rejectSymlinks(checkout); err != nil {
+		return i18n.Errorf(messages.RemoteBundleFetchFailedFmt, source.String(), err)
+	}
+	previous := filepath.Join(tmp, "previous")
+	if replace {
+		if err := sys.Rename(dest, previous); err != nil {
+			return i18n.Errorf(messages.RemoteBundleMoveCacheFmt, dest, err)
+		}
+	}
+	if err := sys.Rename(checkout, dest); err != nil {
+		// Another process may have populated the cache first; reuse it.
+		if _, statErr := sys.Stat(dest); statErr == nil {
+			return nil
+		}
+		if replace {
+			_ = sys.Rename(previous, dest)
+		}
+		return i18n.Errorf(messages.RemoteBundleMoveCacheFmt, dest, err)
+	}
+	committed = true
+	_ = sys.RemoveAll(tmp)
+	return nil
+}
+
+// cacheRootDir resolves the cache root directory, honoring AL_CACHE_DIR when set.
+func cacheRootDir(sys System) (string, error) {
+	if override := strings.TrimSpace(sys.Getenv(versiondispatch.EnvCacheDir)); override != "" {
+		return override, nil
+	}
+	base, err := sys.UserCacheDir()
+	if err != nil {
+		return "", i18n.Errorf(messages.RemoteBundleResolveCacheDirFmt, err)
+	}
+	return filepath.Join(base, "agent-layer"), nil
+}
+
+// Checksum returns the content checksum of every regular file under dir.
+// The digest covers slash-separated relative paths and file contents in
+// lexicographic order, so it is stable across machines and checkouts. A
+// symlink is an error: bundles are read through os.DirFS, which follows
+// links, so a link could pull in content the digest does not cover.
+func Checksum(dir string) (string, error) {
+	var files []string
+	err := walkBundle(dir, func(rel string) {
+		files = append(files, rel)
+	})
+	if err != nil {
+		return "", i18n.Errorf(messages.RemoteBundleChecksumFailedFmt, dir, err)
+	}
+	sort.Strings(files)
+
+	digest := sha256.New()
+	for _, rel := range files {
+		fileDigest, err := fileSHA256(filepath.Join(dir, filepath.FromSlash(rel)))
+		if err != nil {
+			return "", i18n.Errorf(messages.RemoteBundleChecksumFailedFmt, dir, err)
+		}
+		_, _ = fmt.Fprintf(digest, "%s\x00%s\n", rel, fileDigest)
+	}
+	return ChecksumPrefix + hex.EncodeToString(digest.Sum(nil)), nil
+}
+
+// rejectSymlinks reports the first symlink under dir.
+func rejectSymlinks(dir string) error {
+	return walkBundle(dir, func(string) {})
+}
+
+// walkBundle calls file with the slash-separated relative path of every
+// regular file under dir, skipping git metadata, and fails on a symlink.
+func walkBundle(dir string, file func(rel string)) error {
+	return filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() && d.Name() == gitMetadataDir {
+			return filepath.SkipDir
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		if d.Type()&fs.ModeSymlink != 0 {
+			return i18n.Errorf(messages.RemoteBundleSymlinkFmt, filepath.ToSlash(rel))
+		}
+		if d.Type().IsRegular() {
+			file(filepath.ToSlash(rel))
+		}
+		return nil
+	})
+}
+
+func fileSHA256(p string) (string, error) {
+	f, err := os.Open(p) // #nosec G304 -- p is produced by walking the cached bundle directory.
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = f.Close() }()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// trimOutput shortens git output for inclusion in error messages.
+func trimOutput(out string) string {
+	out = strings.TrimSpace(out)
+	if len(out) > maxGitOutputSize {
+		out = out[:maxGitOutputSize] + "..."
+	}
+	return out
+}
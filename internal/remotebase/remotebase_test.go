@@ -0,0 +1,300 @@
+package remotebase
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/conn-castle/agent-layer/internal/offline"
+	"github.com/conn-castle/agent-layer/internal/versiondispatch"
+)
+
+// fakeSystem uses the real filesystem but replaces git and environment access.
+type fakeSystem struct {
+	RealSystem
+	env    map[string]string
+	clones []string
+	files  map[string]string
+	links  map[string]string
+	err    error
+}
+
+func (f *fakeSystem) Getenv(key string) string {
+	return f.env[key]
+}
+
+func (f *fakeSystem) GitClone(_ context.Context, url string, ref string, dest string) error {
+	f.clones = append(f.clones, url+"@"+ref)
+	if f.err != nil {
+		return f.err
+	}
+	for rel, content := range f.files {
+		path := filepath.Join(dest, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+			return err
+		}
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			return err
+		}
+	}
+	for rel, target := range f.links {
+		if err := os.Symlink(target, filepath.Join(dest, filepath.FromSlash(rel))); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func newFakeSystem(t *testing.T) *fakeSystem {
+	t.Helper()
+	return &fakeSystem{
+		env: map[string]string{versiondispatch.EnvCacheDir: t.TempDir()},
+		files: map[string]string{
+			"config.toml":             "[approvals]\nmode = \"all\"\n",
+			"instructions/00_base.md": "base",
+			".git/HEAD":               "ref: refs/heads/main",
+		},
+	}
+}
+
+func TestParseSource(t *testing.T) {
+	source, err := ParseSource("github.com/org/platform.git/agent-layer/base@release/v2")
+	if err != nil {
+		t.Fatalf("ParseSource error: %v", err)
+	}
+	want := Source{Host: "github.com", Owner: "org", Repo: "platform", Subdir: "agent-layer/base", Ref: "release/v2"}
+	if source != want {
+		t.Fatalf("ParseSource = %+v, want %+v", source, want)
+	}
+	if source.CloneURL() != "https://github.com/org/platform.git" {
+		t.Fatalf("CloneURL = %q", source.CloneURL())
+	}
+	if source.String() != "github.com/org/platform/agent-layer/base@release/v2" {
+		t.Fatalf("String = %q", source.String())
+	}
+}
+
+func TestParseSource_Invalid(t *testing.T) {
+	for _, raw := range []string{
+		"",
+		"github.com/org/repo",
+		"github.com/org/repo@",
+		"github.com/org@v1",
+		"localhost/org/repo@v1",
+		"github.com/org/../repo@v1",
+		"github.com//repo@v1",
+		"github.com/org/repo@-upload-pack",
+		"github.com/-org/repo@v1",
+	} {
+		if _, err := ParseSource(raw); err == nil {
+			t.Fatalf("expected error for %q", raw)
+		}
+	}
+}
+
+func TestResolve_FetchesOnceAndCaches(t *testing.T) {
+	sys := newFakeSystem(t)
+
+	dir, err := Resolve(sys, "github.com/org/base@v1", "")
+	if err != nil {
+		t.Fatalf("Resolve error: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "config.toml")); err != nil {
+		t.Fatalf("expected cached config.toml: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, ".git")); !os.IsNotExist(err) {
+		t.Fatalf("expected git metadata to be removed, got %v", err)
+	}
+	if _, err := Resolve(sys, "github.com/org/base@v1", ""); err != nil {
+		t.Fatalf("second Resolve error: %v", err)
+	}
+	if len(sys.clones) != 1 || sys.clones[0] != "https://github.com/org/base.git@v1" {
+		t.Fatalf("clones = %v", sys.clones)
+	}
+}
+
+func TestResolve_ChecksumPinning(t *testing.T) {
+	sys := newFakeSystem(t)
+	dir, err := Resolve(sys, "github.com/org/base@v1", "")
+	if err != nil {
+		t.Fatalf("Resolve error: %v", err)
+	}
+	sum, err := Checksum(dir)
+	if err != nil {
+		t.Fatalf("Checksum error: %v", err)
+	}
+	if _, err := Resolve(sys, "github.com/org/base@v1", sum); err != nil {
+		t.Fatalf("Resolve with matching checksum: %v", err)
+	}
+
+	mismatch := ChecksumPrefix + strings.Repeat("0", 64)
+	_, err = Resolve(sys, "github.com/org/base@v1", mismatch)
+	if err == nil || !strings.Contains(err.Error(), "checksum mismatch") {
+		t.Fatalf("expected checksum mismatch, got %v", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "config.toml"), []byte("tampered"), 0o600); err != nil {
+		t.Fatalf("tamper: %v", err)
+	}
+	if _, err := Resolve(sys, "github.com/org/base@v1", sum); err == nil {
+		t.Fatalf("expected tampered cache to fail checksum")
+	}
+}
+
+func TestResolve_Subdir(t *testing.T) {
+	sys := newFakeSystem(t)
+	sys.files = map[string]string{"agent-layer/config.toml": "", "README.md": "root"}
+
+	dir, err := Resolve(sys, "github.com/org/platform/agent-layer@v3", "")
+	if err != nil {
+		t.Fatalf("Resolve error: %v", err)
+	}
+	if filepath.Base(dir) != "agent-layer" {
+		t.Fatalf("expected subdir bundle, got %s", dir)
+	}
+
+	if _, err := Resolve(sys, "github.com/org/platform/missing@v3", ""); err == nil || !strings.Contains(err.Error(), "does not exist") {
+		t.Fatalf("expected missing subdir error, got %v", err)
+	}
+}
+
+func TestResolve_NoNetwork(t *testing.T) {
+	sys := newFakeSystem(t)
+	sys.env[versiondispatch.EnvNoNetwork] = "1"
+
+	_, err := Resolve(sys, "github.com/org/base@v1", "")
+	if err == nil || !strings.Contains(err.Error(), versiondispatch.EnvNoNetwork) {
+		t.Fatalf("expected no-network error, got %v", err)
+	}
+	if len(sys.clones) != 0 {
+		t.Fatalf("expected no clone attempts, got %v", sys.clones)
+	}
+}
+
+func TestResolve_Offline(t *testing.T) {
+	sys := newFakeSystem(t)
+	sys.env[offline.EnvVar] = "1"
+
+	_, err := Resolve(sys, "github.com/org/base@v1", "")
+	if !errors.Is(err, offline.ErrOffline) {
+		t.Fatalf("expected offline error, got %v", err)
+	}
+	if len(sys.clones) != 0 {
+		t.Fatalf("expected no clone attempts, got %v", sys.clones)
+	}
+}
+
+func TestResolve_CloneFailureLeavesNoCache(t *testing.T) {
+	sys := newFakeSystem(t)
+	sys.err = errors.New("repository not found")
+
+	_, err := Resolve(sys, "github.com/org/base@v1", "")
+	if err == nil || !strings.Contains(err.Error(), "repository not found") {
+		t.Fatalf("expected clone error, got %v", err)
+	}
+	entries, err := os.ReadDir(filepath.Join(sys.env[versiondispatch.EnvCacheDir], cacheSubdir, "github.com", "org", "base"))
+	if err != nil {
+		t.Fatalf("read cache parent: %v", err)
+	}
+	if len(entries) != 0 {
+		t.Fatalf("expected temporary checkout cleanup, found %d entries", len(entries))
+	}
+}
+
+func TestResolve_InvalidInputs(t *testing.T) {
+	if _, err := Resolve(nil, "github.com/org/base@v1", ""); err == nil {
+		t.Fatalf("expected nil system error")
+	}
+	sys := newFakeSystem(t)
+	if _, err := Resolve(sys, "github.com/org/base@v1", "sha256:short"); err == nil {
+		t.Fatalf("expected invalid checksum error")
+	}
+}
+
+func TestChecksum_StableAndContentSensitive(t *testing.T) {
+	a := t.TempDir()
+	b := t.TempDir()
+	for _, dir := range []string{a, b} {
+		if err := os.MkdirAll(filepath.Join(dir, "skills", "x"), 0o700); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, "skills", "x", "SKILL.md"), []byte("same"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	sumA, err := Checksum(a)
+	if err != nil {
+		t.Fatalf("Checksum error: %v", err)
+	}
+	sumB, _ := Checksum(b)
+	if sumA != sumB {
+		t.Fatalf("expected identical trees to share checksum: %s vs %s", sumA, sumB)
+	}
+	if err := ValidateChecksum(sumA); err != nil {
+		t.Fatalf("computed checksum failed validation: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(b, "skills", "x", "SKILL.md"), []byte("different"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if sumC, _ := Checksum(b); sumC == sumA {
+		t.Fatalf("expected checksum to change with content")
+	}
+}
+
+func TestRefresh_RefetchesCachedBundle(t *testing.T) {
+	sys := newFakeSystem(t)
+	dir, err := Resolve(sys, "github.com/org/base@main", "")
+	if err != nil {
+		t.Fatalf("Resolve error: %v", err)
+	}
+
+	sys.files["config.toml"] = "[approvals]\nmode = \"none\"\n"
+	refreshed, err := Refresh(sys, "github.com/org/base@main")
+	if err != nil {
+		t.Fatalf("Refresh error: %v", err)
+	}
+	if refreshed != dir || len(sys.clones) != 2 {
+		t.Fatalf("refreshed = %s (clones %v), want %s after two clones", refreshed, sys.clones, dir)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "config.toml"))
+	if err != nil || !strings.Contains(string(data), "none") {
+		t.Fatalf("expected refreshed content, got %q (%v)", data, err)
+	}
+
+	sys.err = errors.New("network down")
+	if _, err := Refresh(sys, "github.com/org/base@main"); err == nil {
+		t.Fatal("expected refresh error")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "config.toml")); err != nil {
+		t.Fatalf("expected cached bundle to survive failed refresh: %v", err)
+	}
+}
+
+func TestResolve_RejectsSymlinks(t *testing.T) {
+	sys := newFakeSystem(t)
+	sys.links = map[string]string{"instructions/01_secrets.md": "/etc/passwd"}
+
+	_, err := Resolve(sys, "github.com/org/base@v1", "")
+	if err == nil || !strings.Contains(err.Error(), "instructions/01_secrets.md") {
+		t.Fatalf("expected symlink error, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(sys.env[versiondispatch.EnvCacheDir], cacheSubdir, "github.com", "org", "base", "v1")); !os.IsNotExist(err) {
+		t.Fatalf("rejected bundle should not be cached, stat err=%v", err)
+	}
+}
+
+func TestChecksum_RejectsSymlinks(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "config.toml"), []byte(""), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(t.TempDir(), filepath.Join(dir, "skills")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Checksum(dir); err == nil || !strings.Contains(err.Error(), "symlink skills") {
+		t.Fatalf("expected symlink error, got %v", err)
+	}
+}
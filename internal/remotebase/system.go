@@ -0,0 +1,86 @@
+package remotebase
+
+import (
+	"context"
+	"os"
+	"os/exec"
+)
+
+// System abstracts OS operations needed to fetch and cache base bundles.
+// Like the dispatch and sync System interfaces, it is package-local so tests
+// can replace network and git access without shared global state.
+type System interface {
+	Getenv(key string) string
+	UserCacheDir() (string, error)
+	Stat(name string) (os.FileInfo, error)
+	MkdirAll(path string, perm os.FileMode) error
+	MkdirTemp(dir, pattern string) (string, error)
+	RemoveAll(path string) error
+	Rename(oldpath, newpath string) error
+	GitClone(ctx context.Context, url string, ref string, dest string) error
+}
+
+// RealSystem implements System using the OS and the git CLI.
+type RealSystem struct{}
+
+// Getenv returns the value of the environment variable named by key.
+func (RealSystem) Getenv(key string) string {
+	return os.Getenv(key)
+}
+
+// UserCacheDir returns the default user cache directory.
+func (RealSystem) UserCacheDir() (string, error) {
+	return os.UserCacheDir()
+}
+
+// Stat returns a FileInfo describing the named file.
+func (RealSystem) Stat(name string) (os.FileInfo, error) {
+	return os.Stat(name)
+}
+
+// MkdirAll creates a directory and any missing parents.
+func (RealSystem) MkdirAll(path string, perm os.FileMode) error {
+	return os.MkdirAll(path, perm)
+}
+
+// MkdirTemp creates a new temporary directory in dir.
+func (RealSystem) MkdirTemp(dir, pattern string) (string, error) {
+	return os.MkdirTemp(dir, pattern)
+}
+
+// RemoveAll removes path and any children it contains.
+func (RealSystem) RemoveAll(path string) error {
+	return os.RemoveAll(path)
+}
+
+// Rename renames (moves) oldpath to newpath.
+func (RealSystem) Rename(oldpath, newpath string) error {
+	return os.Rename(oldpath, newpath)
+}
+
+// GitClone performs a shallow clone of ref from url into dest.
+func (RealSystem) GitClone(ctx context.Context, url string, ref string, dest string) error {
+	cmd := exec.CommandContext(ctx, "git", "clone", "--quiet", "--depth", "1", "--branch", ref, "--", url, dest) // #nosec G204 -- url and ref come from a validated extends source; arguments are passed without a shell.
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return &gitError{err: err, output: string(out)}
+	}
+	return nil
+}
+
+// gitError carries trimmed git output alongside the process error.
+type gitError struct {
+	err    error
+	output string
+}
+
+func (e *gitError) Error() string {
+	if e.output == "" {
+		return e.err.Error()
+	}
+	return e.err.Error() + ": " + trimOutput(e.output)
+}
+
+func (e *gitError) Unwrap() error {
+	return e.err
+}
@@ -2,19 +2,21 @@ package root
 
 import (
 	"errors"
-	"fmt"
 	"os"
 	"path/filepath"
 
+	"github.com/conn-castle/agent-layer/internal/i18n"
+	"github.com/conn-castle/agent-layer/internal/layerdir"
 	"github.com/conn-castle/agent-layer/internal/messages"
 )
 
 const (
-	agentLayerDir = ".agent-layer"
+	agentLayerDir = layerdir.Name
 	gitDir        = ".git"
 )
 
-// FindAgentLayerRoot walks upward from start until it finds a directory containing .agent-layer/.
+// FindAgentLayerRoot walks upward from start until it finds a directory containing .agent-layer/,
+// or an .agent-layer redirect file naming the real directory (see package layerdir).
 // It returns the root path, whether it was found, and any error encountered.
 func FindAgentLayerRoot(start string) (string, bool, error) {
 	logical, physical, err := resolveStartPaths(start)
@@ -41,13 +43,19 @@ func findAgentLayerRoot(start string) (string, bool, error) {
 		candidate := filepath.Join(dir, agentLayerDir)
 		info, err := os.Stat(candidate)
 		if err == nil {
+			if info.Mode().IsRegular() {
+				if _, _, err := layerdir.Resolve(dir); err != nil {
+					return "", false, err
+				}
+				return dir, true, nil
+			}
 			if !info.IsDir() {
-				return "", false, fmt.Errorf(messages.RootPathNotDirFmt, candidate)
+				return "", false, i18n.Errorf(messages.RootPathNotDirFmt, candidate)
 			}
 			return dir, true, nil
 		}
 		if err != nil && !errors.Is(err, os.ErrNotExist) {
-			return "", false, fmt.Errorf(messages.RootCheckPathFmt, candidate, err)
+			return "", false, i18n.Errorf(messages.RootCheckPathFmt, candidate, err)
 		}
 
 		parent := filepath.Dir(dir)
@@ -98,10 +106,10 @@ func findGitRoot(start string) (string, bool, error) {
 			if info.IsDir() || info.Mode().IsRegular() {
 				return dir, true, nil
 			}
-			return "", false, fmt.Errorf(messages.RootPathNotDirOrFileFmt, candidate)
+			return "", false, i18n.Errorf(messages.RootPathNotDirOrFileFmt, candidate)
 		}
 		if err != nil && !errors.Is(err, os.ErrNotExist) {
-			return "", false, fmt.Errorf(messages.RootCheckPathFmt, candidate, err)
+			return "", false, i18n.Errorf(messages.RootCheckPathFmt, candidate, err)
 		}
 
 		parent := filepath.Dir(dir)
@@ -114,15 +122,15 @@ func findGitRoot(start string) (string, bool, error) {
 
 func resolveStartPaths(start string) (string, string, error) {
 	if start == "" {
-		return "", "", fmt.Errorf(messages.RootStartPathRequired)
+		return "", "", i18n.Errorf(messages.RootStartPathRequired)
 	}
 	abs, err := filepath.Abs(start)
 	if err != nil {
-		return "", "", fmt.Errorf(messages.RootResolvePathFmt, start, err)
+		return "", "", i18n.Errorf(messages.RootResolvePathFmt, start, err)
 	}
 	resolved, err := filepath.EvalSymlinks(abs)
 	if err != nil {
-		return "", "", fmt.Errorf(messages.RootResolvePathFmt, start, err)
+		return "", "", i18n.Errorf(messages.RootResolvePathFmt, start, err)
 	}
 	return abs, resolved, nil
 }
@@ -137,7 +145,7 @@ func distinctStartPaths(logical string, physical string) []string {
 func resolveFoundRoot(start string, root string) (string, error) {
 	resolved, err := filepath.EvalSymlinks(root)
 	if err != nil {
-		return "", fmt.Errorf(messages.RootResolvePathFmt, start, err)
+		return "", i18n.Errorf(messages.RootResolvePathFmt, start, err)
 	}
 	return resolved, nil
 }
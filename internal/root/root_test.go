@@ -298,3 +298,30 @@ func resolvedTestPath(t *testing.T, path string) string {
 	}
 	return resolved
 }
+
+func TestFindAgentLayerRootFollowsRedirectFile(t *testing.T) {
+	root := t.TempDir()
+	private := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, ".agent-layer"), []byte(private+"\n"), 0o600); err != nil {
+		t.Fatalf("write redirect: %v", err)
+	}
+	sub := filepath.Join(root, "a")
+	if err := os.Mkdir(sub, 0o700); err != nil {
+		t.Fatalf("mkdir sub: %v", err)
+	}
+
+	got, found, err := FindAgentLayerRoot(sub)
+	if err != nil || !found {
+		t.Fatalf("FindAgentLayerRoot = %q, %v, %v", got, found, err)
+	}
+	if want := resolvedTestPath(t, root); got != want {
+		t.Fatalf("expected root %s, got %s", want, got)
+	}
+
+	if err := os.WriteFile(filepath.Join(root, ".agent-layer"), []byte("missing\n"), 0o600); err != nil {
+		t.Fatalf("write redirect: %v", err)
+	}
+	if _, _, err := FindAgentLayerRoot(sub); err == nil || !strings.Contains(err.Error(), "not a directory") {
+		t.Fatalf("expected broken redirect error, got %v", err)
+	}
+}
@@ -6,11 +6,16 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
-	"time"
 
+	"github.com/conn-castle/agent-layer/internal/clock"
+	"github.com/conn-castle/agent-layer/internal/i18n"
+	"github.com/conn-castle/agent-layer/internal/layerdir"
 	"github.com/conn-castle/agent-layer/internal/messages"
 )
 
+// runClock stamps run IDs. Tests replace it with a fixed clock.
+var runClock clock.Clock = clock.Real{}
+
 // Info describes a single Agent Layer run directory.
 type Info struct {
 	ID  string
@@ -20,18 +25,18 @@ type Info struct {
 // Create creates a new run directory under .agent-layer/tmp/runs.
 func Create(root string) (*Info, error) {
 	if root == "" {
-		return nil, fmt.Errorf(messages.RunRootPathRequired)
+		return nil, i18n.Errorf(messages.RunRootPathRequired)
 	}
 
-	stamp := time.Now().UTC().Format("20060102-150405")
+	stamp := clock.Stamp(runClock.Now())
 	suffix, err := randomSuffix(4)
 	if err != nil {
-		return nil, fmt.Errorf(messages.RunGenerateIDFailedFmt, err)
+		return nil, i18n.Errorf(messages.RunGenerateIDFailedFmt, err)
 	}
 	runID := fmt.Sprintf("%s-%s", stamp, suffix)
-	dir := filepath.Join(root, ".agent-layer", "tmp", "runs", runID)
+	dir := filepath.Join(layerdir.Dir(root), "tmp", "runs", runID)
 	if err := os.MkdirAll(dir, 0o700); err != nil {
-		return nil, fmt.Errorf(messages.RunCreateDirFailedFmt, dir, err)
+		return nil, i18n.Errorf(messages.RunCreateDirFailedFmt, dir, err)
 	}
 	return &Info{ID: runID, Dir: dir}, nil
 }
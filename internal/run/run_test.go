@@ -7,8 +7,25 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
+
+	"github.com/conn-castle/agent-layer/internal/clock"
 )
 
+func TestCreateRunDirUsesUTCStamp(t *testing.T) {
+	orig := runClock
+	runClock = clock.Fixed{T: time.Date(2026, time.November, 1, 1, 30, 0, 0, time.FixedZone("PDT", -7*60*60))}
+	t.Cleanup(func() { runClock = orig })
+
+	info, err := Create(t.TempDir())
+	if err != nil {
+		t.Fatalf("Create error: %v", err)
+	}
+	if !strings.HasPrefix(info.ID, "20261101-083000-") {
+		t.Fatalf("expected UTC stamp in run id, got %s", info.ID)
+	}
+}
+
 func TestCreateRunDir(t *testing.T) {
 	root := t.TempDir()
 	info, err := Create(root)
@@ -0,0 +1,163 @@
+package serve
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/conn-castle/agent-layer/internal/config"
+	"github.com/conn-castle/agent-layer/internal/install"
+	"github.com/conn-castle/agent-layer/internal/sync"
+	"github.com/conn-castle/agent-layer/internal/warnings"
+)
+
+// TestContractV1 checks every v1 route still returns the fields listed in
+// testdata/contract-v1.json. Integrations depend on these names: within v1,
+// fields may be added but never renamed or removed.
+func TestContractV1(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("testdata", "contract-v1.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var contract map[string][]string
+	if err := json.Unmarshal(data, &contract); err != nil {
+		t.Fatalf("decode contract: %v", err)
+	}
+
+	root := t.TempDir()
+	stubContractProject(t, root)
+	server := newTestServer(t, Options{Root: root, Version: "1.2.3"})
+
+	routes := make([]string, 0, len(contract))
+	for route := range contract {
+		routes = append(routes, route)
+	}
+	sort.Strings(routes)
+	for _, route := range routes {
+		method, path, _ := strings.Cut(route, " ")
+		req, err := http.NewRequest(method, server.URL+path, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("%s: %v", route, err)
+		}
+		var body any
+		decode(t, resp, http.StatusOK, &body)
+		if list, ok := body.([]any); ok {
+			if len(list) == 0 {
+				t.Fatalf("%s: expected at least one entry to check", route)
+			}
+			body = list[0]
+		}
+		object, ok := body.(map[string]any)
+		if !ok {
+			t.Fatalf("%s: expected a JSON object, got %T", route, body)
+		}
+		for _, field := range contract[route] {
+			if _, ok := object[field]; !ok {
+				t.Errorf("%s: missing contract field %q in %v", route, field, object)
+			}
+		}
+	}
+}
+
+func stubContractProject(t *testing.T, root string) {
+	t.Helper()
+	enabled := true
+	originalLoad, originalSync, originalStatus, originalPlan := loadProject, runSync, mcpStatus, buildUpgradePlan
+	t.Cleanup(func() {
+		loadProject, runSync, mcpStatus, buildUpgradePlan = originalLoad, originalSync, originalStatus, originalPlan
+	})
+	loadProject = func(string) (*config.ProjectConfig, error) {
+		project := &config.ProjectConfig{
+			Env:           map[string]string{"GITHUB_TOKEN": "secret"},
+			CommandsAllow: []string{"git status"},
+			Skills: []config.Skill{
+				{Name: "review", Description: "Review a diff", SourcePath: filepath.Join(root, ".agent-layer", "skills", "review", "SKILL.md")},
+			},
+		}
+		project.Config.Approvals.Mode = config.ApprovalModeCommands
+		project.Config.Agents.Codex.Enabled = &enabled
+		project.Config.Agents.Codex.LocalConfigDir = &enabled
+		project.Config.Clients = map[string]config.ClientConfig{
+			"claude": {Launch: config.ClientLaunchConfig{Env: map[string]string{
+				"ANTHROPIC_BASE_URL": "http://localhost:8080",
+				"GH_TOKEN":           "${GITHUB_TOKEN}",
+			}}},
+		}
+		return project, nil
+	}
+	runSync = func(string, *config.ProjectConfig, io.Writer) (*sync.Result, error) { return &sync.Result{}, nil }
+	mcpStatus = func(context.Context, *config.ProjectConfig, warnings.Connector, warnings.MCPDiscoveryStatusFunc) ([]warnings.MCPServerStatus, error) {
+		return []warnings.MCPServerStatus{{ID: "github", Transport: "http", ServerName: "gh", ServerVersion: "1.0", Tools: 2}}, nil
+	}
+	buildUpgradePlan = func(string, install.UpgradePlanOptions) (install.UpgradePlan, error) {
+		return install.UpgradePlan{SchemaVersion: 1, DryRun: true}, nil
+	}
+}
+
+func TestConfigEnvAndSkills(t *testing.T) {
+	root := t.TempDir()
+	stubContractProject(t, root)
+	server := newTestServer(t, Options{Root: root})
+
+	resp, err := http.Get(server.URL + "/v1/config")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var cfg ConfigResponse
+	decode(t, resp, http.StatusOK, &cfg)
+	approvals, _ := cfg.Config["approvals"].(map[string]any)
+	if approvals["mode"] != config.ApprovalModeCommands {
+		t.Fatalf("expected config.toml keys, got %v", cfg.Config)
+	}
+	if strings.Join(cfg.EnvKeys, ",") != "GITHUB_TOKEN" || strings.Contains(mustJSON(t, cfg), "secret") {
+		t.Fatalf("expected env key names without values, got %+v", cfg)
+	}
+
+	resp, err = http.Get(server.URL + "/v1/env")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var env EnvResponse
+	decode(t, resp, http.StatusOK, &env)
+	if env.Clients["codex"]["CODEX_HOME"] != filepath.Join(root, ".codex") {
+		t.Fatalf("expected codex CODEX_HOME, got %v", env.Clients)
+	}
+	if _, ok := env.Clients["claude"]["CLAUDE_CONFIG_DIR"]; ok {
+		t.Fatalf("expected no CLAUDE_CONFIG_DIR without local_config_dir, got %v", env.Clients)
+	}
+	if env.Clients["claude"]["ANTHROPIC_BASE_URL"] != "http://localhost:8080" || env.Clients["claude"]["GH_TOKEN"] != "${GITHUB_TOKEN}" {
+		t.Fatalf("expected claude launch env with placeholders kept, got %v", env.Clients["claude"])
+	}
+	if _, ok := env.Clients["codex"]["ANTHROPIC_BASE_URL"]; ok || strings.Contains(mustJSON(t, env), "secret") {
+		t.Fatalf("expected launch env only for claude and no .env values, got %v", env.Clients)
+	}
+
+	resp, err = http.Get(server.URL + "/v1/skills")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var skills []SkillResponse
+	decode(t, resp, http.StatusOK, &skills)
+	if len(skills) != 1 || skills[0].Path != ".agent-layer/skills/review/SKILL.md" || skills[0].Scope != "project" {
+		t.Fatalf("unexpected skills %+v", skills)
+	}
+}
+
+func mustJSON(t *testing.T, value any) string {
+	t.Helper()
+	data, err := json.Marshal(value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
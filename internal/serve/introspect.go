@@ -0,0 +1,172 @@
+package serve
+
+import (
+	"io"
+	"net/http"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	toml "github.com/pelletier/go-toml/v2"
+
+	"github.com/conn-castle/agent-layer/internal/clients"
+	"github.com/conn-castle/agent-layer/internal/clients/antigravity"
+	"github.com/conn-castle/agent-layer/internal/clients/claude"
+	"github.com/conn-castle/agent-layer/internal/clients/codex"
+	"github.com/conn-castle/agent-layer/internal/clients/vscode"
+	"github.com/conn-castle/agent-layer/internal/config"
+	"github.com/conn-castle/agent-layer/internal/install"
+	"github.com/conn-castle/agent-layer/internal/version"
+)
+
+var buildUpgradePlan = install.BuildUpgradePlan
+
+// ConfigResponse is the body of GET /v1/config.
+type ConfigResponse struct {
+	Root string `json:"root"`
+	// Config is config.toml after defaults and extends, keyed as in the file.
+	Config map[string]any `json:"config"`
+	// EnvKeys names the variables loaded from .agent-layer/.env. Values are
+	// never returned.
+	EnvKeys       []string `json:"env_keys"`
+	CommandsAllow []string `json:"commands_allow"`
+}
+
+// EnvResponse is the body of GET /v1/env.
+type EnvResponse struct {
+	// Clients maps each launcher (agy, claude, codex, vscode) to the variables
+	// `al <client>` sets on top of the caller's environment and .env,
+	// including [clients.<name>.launch.env].
+	Clients map[string]map[string]string `json:"clients"`
+}
+
+// SkillResponse is one entry in the body of GET /v1/skills.
+type SkillResponse struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	// Scope is "project" or "user".
+	Scope string `json:"scope"`
+	// Path is the SKILL.md path, relative to the repo for project skills.
+	Path string `json:"path"`
+}
+
+func handleConfig(w http.ResponseWriter, root string) {
+	project, err := loadProject(root)
+	if err != nil {
+		writeError(w, http.StatusUnprocessableEntity, err)
+		return
+	}
+	// Round-trip through TOML so keys match config.toml rather than Go names.
+	data, err := toml.Marshal(project.Config)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	resolved := map[string]any{}
+	if err := toml.Unmarshal(data, &resolved); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	envKeys := make([]string, 0, len(project.Env))
+	for key := range project.Env {
+		envKeys = append(envKeys, key)
+	}
+	sort.Strings(envKeys)
+	commandsAllow := project.CommandsAllow
+	if commandsAllow == nil {
+		commandsAllow = []string{}
+	}
+	writeJSON(w, http.StatusOK, ConfigResponse{Root: root, Config: resolved, EnvKeys: envKeys, CommandsAllow: commandsAllow})
+}
+
+// handleEnv reports, per launcher, [clients.<name>.launch.env] followed by
+// the client's own variables, in the order a launch applies them. ${VAR}
+// placeholders that .env resolves are reported as written, so .env values
+// are never returned.
+func handleEnv(w http.ResponseWriter, root string) {
+	project, err := loadProject(root)
+	if err != nil {
+		writeError(w, http.StatusUnprocessableEntity, err)
+		return
+	}
+	project.Root = root
+	keepPlaceholder := func(name string, _ string) string { return "${" + name + "}" }
+	agents := project.Config.Agents
+	env := map[string]map[string]string{}
+	for _, launcher := range []struct {
+		name      string
+		client    string
+		configure func([]string) []string
+	}{
+		{"agy", "antigravity", antigravity.ConfigureEnvironment},
+		{"claude", "claude", func(env []string) []string {
+			return claude.ConfigureEnvironment(root, env, agents.Claude, io.Discard)
+		}},
+		{"codex", "codex", func(env []string) []string {
+			return codex.ConfigureEnvironment(root, env, agents.Codex, io.Discard)
+		}},
+		{"vscode", "vscode", func(env []string) []string {
+			return vscode.ConfigureEnvironment(project, env)
+		}},
+	} {
+		values, err := clients.ApplyLaunchEnv(nil, launcher.client, project, keepPlaceholder)
+		if err != nil {
+			writeError(w, http.StatusUnprocessableEntity, err)
+			return
+		}
+		env[launcher.name] = envMap(launcher.configure(values))
+	}
+	writeJSON(w, http.StatusOK, EnvResponse{Clients: env})
+}
+
+// envMap turns KEY=VALUE entries into a map.
+func envMap(env []string) map[string]string {
+	values := make(map[string]string, len(env))
+	for _, entry := range env {
+		if key, value, ok := strings.Cut(entry, "="); ok {
+			values[key] = value
+		}
+	}
+	return values
+}
+
+func handleUpgradePlan(w http.ResponseWriter, root string, binaryVersion string) {
+	target := ""
+	if !version.IsDev(binaryVersion) {
+		normalized, err := version.Normalize(binaryVersion)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+		target = normalized
+	}
+	plan, err := buildUpgradePlan(root, install.UpgradePlanOptions{
+		TargetPinVersion: target,
+		System:           install.RealSystem{},
+		BinaryVersion:    binaryVersion,
+	})
+	if err != nil {
+		writeError(w, http.StatusUnprocessableEntity, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, plan)
+}
+
+func handleSkills(w http.ResponseWriter, root string) {
+	project, err := loadProject(root)
+	if err != nil {
+		writeError(w, http.StatusUnprocessableEntity, err)
+		return
+	}
+	skills := make([]SkillResponse, 0, len(project.Skills))
+	for _, skill := range project.Skills {
+		entry := SkillResponse{Name: skill.Name, Description: skill.Description, Scope: "project", Path: skill.SourcePath}
+		if skill.Scope == config.SkillScopeUser {
+			entry.Scope = "user"
+		} else if rel, err := filepath.Rel(root, skill.SourcePath); err == nil {
+			entry.Path = filepath.ToSlash(rel)
+		}
+		skills = append(skills, entry)
+	}
+	writeJSON(w, http.StatusOK, skills)
+}
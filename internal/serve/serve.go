@@ -0,0 +1,373 @@
+// Package serve exposes sync, MCP server status, and the MCP gateway over a
+// local HTTP API, so Agent Layer can run as a long-lived process next to agent
+// runtimes, for example as a container entrypoint.
+package serve
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/conn-castle/agent-layer/internal/config"
+	"github.com/conn-castle/agent-layer/internal/fsutil"
+	"github.com/conn-castle/agent-layer/internal/i18n"
+	"github.com/conn-castle/agent-layer/internal/mcpgateway"
+	"github.com/conn-castle/agent-layer/internal/messages"
+	"github.com/conn-castle/agent-layer/internal/projection"
+	"github.com/conn-castle/agent-layer/internal/statelock"
+	"github.com/conn-castle/agent-layer/internal/sync"
+	"github.com/conn-castle/agent-layer/internal/warnings"
+)
+
+// APIVersion is the version in every route prefix (/v1/...). Response fields
+// are only added within a version; renaming or removing one needs a new
+// version.
+const APIVersion = 1
+
+// unixPrefix marks a listen address as a Unix socket path.
+const unixPrefix = "unix:"
+
+// TokenFile is the file under .agent-layer/state/ holding the bearer token of
+// the running al serve.
+const TokenFile = "serve-token"
+
+// shutdownTimeout bounds how long Run waits for in-flight requests on exit.
+const shutdownTimeout = 10 * time.Second
+
+var (
+	loadProject = config.LoadProjectConfig
+	runSync     = func(root string, project *config.ProjectConfig, hookOutput io.Writer) (*sync.Result, error) {
+		return sync.RunWithProjectOptions(sync.RealSystem{}, root, project, sync.RunOptions{HookOutput: hookOutput})
+	}
+	mcpStatus        = warnings.MCPStatus
+	startGateway     = mcpgateway.Start
+	acquireStateLock = statelock.Acquire
+)
+
+// Options configures NewHandler.
+type Options struct {
+	// Root is the repo root holding .agent-layer/.
+	Root string
+	// Version is reported by /v1/health and the MCP gateway.
+	Version string
+	// Gateway serves the MCP gateway at /mcp over streamable HTTP.
+	Gateway bool
+	// Warnings receives gateway startup warnings.
+	Warnings io.Writer
+	// HookOutput receives the output of [hooks] commands that /v1/sync runs.
+	HookOutput io.Writer
+	// Token is the bearer token every request over TCP must send. Requests
+	// over a Unix socket need none. An empty Token refuses all TCP requests.
+	Token string
+}
+
+// HealthResponse is the body of GET /v1/health.
+type HealthResponse struct {
+	Status  string `json:"status"`
+	API     int    `json:"api"`
+	Version string `json:"version"`
+	Gateway bool   `json:"gateway"`
+}
+
+// SyncResponse is the body of a successful POST /v1/sync.
+type SyncResponse struct {
+	Warnings     []string `json:"warnings"`
+	Degradations []string `json:"degradations"`
+	EditedFiles  []string `json:"edited_files"`
+}
+
+// ServerStatus is one entry in the body of GET /v1/status.
+type ServerStatus struct {
+	ID            string `json:"id"`
+	Transport     string `json:"transport"`
+	ServerName    string `json:"server_name,omitempty"`
+	ServerVersion string `json:"server_version,omitempty"`
+	Tools         int    `json:"tools"`
+	SchemaTokens  int    `json:"schema_tokens"`
+	Error         string `json:"error,omitempty"`
+}
+
+// ErrorResponse is the body of every failed request.
+type ErrorResponse struct {
+	Error string `json:"error"`
+}
+
+// NewHandler builds the API. With opts.Gateway, the configured MCP servers
+// are started once, bound to ctx; the returned close function stops them.
+func NewHandler(ctx context.Context, opts Options) (http.Handler, func(), error) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /v1/health", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, HealthResponse{Status: "ok", API: APIVersion, Version: opts.Version, Gateway: opts.Gateway})
+	})
+	mux.HandleFunc("POST /v1/sync", func(w http.ResponseWriter, r *http.Request) {
+		handleSync(w, opts.Root, opts.HookOutput)
+	})
+	mux.HandleFunc("GET /v1/status", func(w http.ResponseWriter, r *http.Request) {
+		handleStatus(w, r, opts.Root)
+	})
+	mux.HandleFunc("GET /v1/config", func(w http.ResponseWriter, r *http.Request) {
+		handleConfig(w, opts.Root)
+	})
+	mux.HandleFunc("GET /v1/env", func(w http.ResponseWriter, r *http.Request) {
+		handleEnv(w, opts.Root)
+	})
+	mux.HandleFunc("GET /v1/upgrade-plan", func(w http.ResponseWriter, r *http.Request) {
+		handleUpgradePlan(w, opts.Root, opts.Version)
+	})
+	mux.HandleFunc("GET /v1/skills", func(w http.ResponseWriter, r *http.Request) {
+		handleSkills(w, opts.Root)
+	})
+
+	closeGateway := func() {}
+	if opts.Gateway {
+		project, err := loadProject(opts.Root)
+		if err != nil {
+			return nil, nil, err
+		}
+		servers, err := projection.ResolveEnabledMCPServers(project.Config.MCP.Servers, project.Env)
+		if err != nil {
+			return nil, nil, err
+		}
+		gateway := startGateway(ctx, servers, mcpgateway.Options{
+			Version:   opts.Version,
+			Warnings:  opts.Warnings,
+			Project:   project,
+			AuditRoot: opts.Root,
+		})
+		closeGateway = gateway.Close
+		mux.Handle("/mcp", mcp.NewStreamableHTTPHandler(func(*http.Request) *mcp.Server { return gateway.Server }, nil))
+	}
+	return protect(mux, opts.Token), closeGateway, nil
+}
+
+// protect guards the API against other local users and the web pages the
+// user visits. Over TCP, which any local process can reach, requests must
+// carry token as a bearer token and address a loopback host, so a
+// DNS-rebinding page cannot reach /v1/config or /v1/env through its own
+// domain name. Cross-origin browser requests that could change state, such
+// as a form POST to /v1/sync, are refused. Unix sockets are owner-only and
+// out of a browser's reach, so they need no token.
+func protect(next http.Handler, token string) http.Handler {
+	next = http.NewCrossOriginProtection().Handler(next)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, unix := r.Context().Value(http.LocalAddrContextKey).(*net.UnixAddr); !unix {
+			if !loopbackHost(r.Host) {
+				writeError(w, http.StatusForbidden, i18n.Errorf(messages.ServeHostNotAllowedFmt, r.Host))
+				return
+			}
+			if !validToken(r.Header.Get("Authorization"), token) {
+				w.Header().Set("WWW-Authenticate", "Bearer")
+				writeError(w, http.StatusUnauthorized, errors.New(i18n.T(messages.ServeUnauthorized)))
+				return
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// validToken reports whether an Authorization header carries token as a
+// bearer token. An empty token matches nothing.
+func validToken(header string, token string) bool {
+	got, ok := strings.CutPrefix(header, "Bearer ")
+	return ok && token != "" && subtle.ConstantTimeCompare([]byte(got), []byte(token)) == 1
+}
+
+// TokenPath returns where WriteToken stores the token for root.
+func TokenPath(root string) string {
+	return filepath.Join(config.StateDir(root), TokenFile)
+}
+
+// WriteToken generates a bearer token for a new al serve session and writes
+// it to TokenPath, readable only by the owner. remove deletes the file unless
+// a later session has replaced it.
+func WriteToken(root string) (token string, remove func(), err error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", nil, i18n.Errorf(messages.ServeTokenWriteFmt, TokenPath(root), err)
+	}
+	token = hex.EncodeToString(raw)
+	path := TokenPath(root)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return "", nil, i18n.Errorf(messages.ServeTokenWriteFmt, path, err)
+	}
+	if err := fsutil.WriteFileAtomic(path, []byte(token+"\n"), 0o600); err != nil {
+		return "", nil, i18n.Errorf(messages.ServeTokenWriteFmt, path, err)
+	}
+	remove = func() {
+		if data, err := os.ReadFile(path); err == nil && strings.TrimSpace(string(data)) == token { // #nosec G304 -- path is under the repo's state directory.
+			_ = os.Remove(path)
+		}
+	}
+	return token, remove, nil
+}
+
+// loopbackHost reports whether a Host header names a loopback address, with
+// or without a port.
+func loopbackHost(hostport string) bool {
+	host := hostport
+	if h, _, err := net.SplitHostPort(hostport); err == nil {
+		host = h
+	}
+	return isLoopback(strings.Trim(host, "[]"))
+}
+
+// handleSync reloads the project config and regenerates client outputs,
+// with the configured [hooks], under the repo's state lock. It answers 409 Conflict while another al process
+// holds the lock rather than queueing behind it.
+func handleSync(w http.ResponseWriter, root string, hookOutput io.Writer) {
+	lock, err := acquireStateLock(root, false)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, statelock.ErrHeld) {
+			status = http.StatusConflict
+		}
+		writeError(w, status, err)
+		return
+	}
+	defer func() { _ = lock.Release() }()
+	project, err := loadProject(root)
+	if err != nil {
+		writeError(w, http.StatusUnprocessableEntity, err)
+		return
+	}
+	result, err := runSync(root, project, hookOutput)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	response := SyncResponse{Warnings: []string{}, Degradations: []string{}, EditedFiles: []string{}}
+	for _, warning := range result.Warnings {
+		response.Warnings = append(response.Warnings, warning.String())
+	}
+	for _, degradation := range result.Degradations {
+		response.Degradations = append(response.Degradations, degradation.String())
+	}
+	for _, edited := range result.EditedFiles {
+		rel, err := filepath.Rel(root, edited.Path)
+		if err != nil {
+			rel = edited.Path
+		}
+		response.EditedFiles = append(response.EditedFiles, filepath.ToSlash(rel))
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+// handleStatus starts each enabled MCP server briefly, like `al mcp status`.
+func handleStatus(w http.ResponseWriter, r *http.Request, root string) {
+	project, err := loadProject(root)
+	if err != nil {
+		writeError(w, http.StatusUnprocessableEntity, err)
+		return
+	}
+	statuses, err := mcpStatus(r.Context(), project, nil, nil)
+	if err != nil {
+		writeError(w, http.StatusUnprocessableEntity, err)
+		return
+	}
+	response := make([]ServerStatus, 0, len(statuses))
+	for _, status := range statuses {
+		entry := ServerStatus{
+			ID:            status.ID,
+			Transport:     status.Transport,
+			ServerName:    status.ServerName,
+			ServerVersion: status.ServerVersion,
+			Tools:         status.Tools,
+			SchemaTokens:  status.SchemaTokens,
+		}
+		if status.Err != nil {
+			entry.Error = status.Err.Error()
+		}
+		response = append(response, entry)
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+func writeJSON(w http.ResponseWriter, code int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func writeError(w http.ResponseWriter, code int, err error) {
+	writeJSON(w, code, ErrorResponse{Error: err.Error()})
+}
+
+// Listen opens addr: "unix:<path>" for a Unix socket, or host:port on a
+// loopback address. Other hosts are refused, since the token in TokenPath
+// only reaches clients on this machine; share a socket or the network
+// namespace with the runtime instead.
+func Listen(addr string) (net.Listener, error) {
+	if path, ok := strings.CutPrefix(addr, unixPrefix); ok {
+		if path == "" {
+			return nil, i18n.Errorf(messages.ServeAddrFmt, addr)
+		}
+		// A socket left by a process that did not shut down cleanly blocks bind.
+		if info, err := os.Lstat(path); err == nil && info.Mode()&os.ModeSocket != 0 {
+			_ = os.Remove(path)
+		}
+		listener, err := net.Listen("unix", path)
+		if err != nil {
+			return nil, i18n.Errorf(messages.ServeListenFmt, addr, err)
+		}
+		if err := os.Chmod(path, 0o600); err != nil {
+			_ = listener.Close()
+			return nil, i18n.Errorf(messages.ServeListenFmt, addr, err)
+		}
+		return listener, nil
+	}
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, i18n.Errorf(messages.ServeAddrFmt, addr)
+	}
+	if !isLoopback(host) {
+		return nil, i18n.Errorf(messages.ServeNotLoopbackFmt, addr)
+	}
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, i18n.Errorf(messages.ServeListenFmt, addr, err)
+	}
+	return listener, nil
+}
+
+func isLoopback(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// Run serves handler on listener until ctx is done, then waits for in-flight
+// requests to finish.
+func Run(ctx context.Context, listener net.Listener, handler http.Handler) error {
+	server := &http.Server{Handler: handler, ReadHeaderTimeout: 10 * time.Second}
+	errs := make(chan error, 1)
+	go func() { errs <- server.Serve(listener) }()
+	select {
+	case err := <-errs:
+		return err
+	case <-ctx.Done():
+	}
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		// Streaming MCP sessions can outlive the grace period; drop them.
+		_ = server.Close()
+	}
+	if err := <-errs; err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
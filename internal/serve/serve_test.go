@@ -0,0 +1,415 @@
+package serve
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/conn-castle/agent-layer/internal/config"
+	"github.com/conn-castle/agent-layer/internal/mcpgateway"
+	"github.com/conn-castle/agent-layer/internal/projection"
+	"github.com/conn-castle/agent-layer/internal/statelock"
+	"github.com/conn-castle/agent-layer/internal/sync"
+	"github.com/conn-castle/agent-layer/internal/warnings"
+)
+
+func stubProject(t *testing.T, err error) {
+	t.Helper()
+	original := loadProject
+	loadProject = func(string) (*config.ProjectConfig, error) {
+		if err != nil {
+			return nil, err
+		}
+		return &config.ProjectConfig{}, nil
+	}
+	t.Cleanup(func() { loadProject = original })
+}
+
+// testToken is the bearer token newTestServer requires.
+const testToken = "test-token"
+
+// bearerTransport adds the test token to every request.
+type bearerTransport struct{}
+
+func (bearerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	if req.Header.Get("Authorization") == "" {
+		req.Header.Set("Authorization", "Bearer "+testToken)
+	}
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+// newTestServer serves the API over TCP with testToken, and makes
+// http.DefaultClient send it for the rest of the test.
+func newTestServer(t *testing.T, opts Options) *httptest.Server {
+	t.Helper()
+	opts.Token = testToken
+	originalClient := http.DefaultClient
+	http.DefaultClient = &http.Client{Transport: bearerTransport{}}
+	t.Cleanup(func() { http.DefaultClient = originalClient })
+	handler, closeHandler, err := NewHandler(context.Background(), opts)
+	if err != nil {
+		t.Fatalf("NewHandler: %v", err)
+	}
+	server := httptest.NewServer(handler)
+	t.Cleanup(func() {
+		server.Close()
+		closeHandler()
+	})
+	return server
+}
+
+func decode(t *testing.T, resp *http.Response, wantCode int, into any) {
+	t.Helper()
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != wantCode {
+		t.Fatalf("expected status %d, got %d", wantCode, resp.StatusCode)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(into); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+}
+
+func TestHealth(t *testing.T) {
+	server := newTestServer(t, Options{Root: t.TempDir(), Version: "1.2.3"})
+	resp, err := http.Get(server.URL + "/v1/health")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var health HealthResponse
+	decode(t, resp, http.StatusOK, &health)
+	if health != (HealthResponse{Status: "ok", API: APIVersion, Version: "1.2.3"}) {
+		t.Fatalf("unexpected health %+v", health)
+	}
+
+	resp, err = http.Post(server.URL+"/v1/health", "application/json", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusMethodNotAllowed {
+		t.Fatalf("expected 405, got %d", resp.StatusCode)
+	}
+}
+
+func TestProtect_RefusesBrowserAttacks(t *testing.T) {
+	synced := false
+	original := runSync
+	runSync = func(string, *config.ProjectConfig, io.Writer) (*sync.Result, error) {
+		synced = true
+		return &sync.Result{}, nil
+	}
+	t.Cleanup(func() { runSync = original })
+	stubProject(t, nil)
+	server := newTestServer(t, Options{Root: t.TempDir()})
+
+	do := func(method string, path string, header http.Header, host string) int {
+		t.Helper()
+		req, err := http.NewRequest(method, server.URL+path, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header = header
+		if host != "" {
+			req.Host = host
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_ = resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	crossSite := http.Header{"Origin": {"https://evil.example"}, "Sec-Fetch-Site": {"cross-site"}}
+	if code := do(http.MethodPost, "/v1/sync", crossSite, ""); code != http.StatusForbidden || synced {
+		t.Fatalf("cross-site sync = %d (synced %v), want 403", code, synced)
+	}
+	if code := do(http.MethodGet, "/v1/config", http.Header{}, "rebind.evil.example:7878"); code != http.StatusForbidden {
+		t.Fatalf("rebinding host = %d, want 403", code)
+	}
+	if code := do(http.MethodGet, "/v1/health", http.Header{}, "localhost:7878"); code != http.StatusOK {
+		t.Fatalf("localhost host = %d, want 200", code)
+	}
+	if code := do(http.MethodPost, "/v1/sync", http.Header{}, ""); code != http.StatusOK || !synced {
+		t.Fatalf("same-origin sync = %d (synced %v), want 200", code, synced)
+	}
+}
+
+func TestProtect_RequiresToken(t *testing.T) {
+	server := newTestServer(t, Options{Root: t.TempDir()})
+	for name, header := range map[string]string{
+		"missing": "",
+		"wrong":   "Bearer nope",
+		"scheme":  "Basic " + testToken,
+	} {
+		req, err := http.NewRequest(http.MethodGet, server.URL+"/v1/health", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if header != "" {
+			req.Header.Set("Authorization", header)
+		}
+		resp, err := http.DefaultTransport.RoundTrip(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_ = resp.Body.Close()
+		if resp.StatusCode != http.StatusUnauthorized || resp.Header.Get("WWW-Authenticate") != "Bearer" {
+			t.Fatalf("%s token = %d, want 401", name, resp.StatusCode)
+		}
+	}
+
+	// An empty token refuses every TCP request.
+	handler, closeHandler, err := NewHandler(context.Background(), Options{Root: t.TempDir()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer closeHandler()
+	open := httptest.NewServer(handler)
+	defer open.Close()
+	resp, err := http.Get(open.URL + "/v1/health")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("no configured token = %d, want 401", resp.StatusCode)
+	}
+}
+
+func TestWriteToken(t *testing.T) {
+	root := t.TempDir()
+	token, remove, err := WriteToken(root)
+	if err != nil {
+		t.Fatalf("WriteToken: %v", err)
+	}
+	path := TokenPath(root)
+	info, err := os.Stat(path)
+	if err != nil || info.Mode().Perm() != 0o600 {
+		t.Fatalf("expected owner-only token file, got %v %v", info, err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil || strings.TrimSpace(string(data)) != token || len(token) != 64 {
+		t.Fatalf("token file = %q (%v), token %q", data, err, token)
+	}
+
+	// A later session's token is left in place.
+	next, removeNext, err := WriteToken(root)
+	if err != nil || next == token {
+		t.Fatalf("second WriteToken = %q, %v", next, err)
+	}
+	remove()
+	if _, err := os.Stat(path); err != nil {
+		t.Fatalf("first remove deleted the second token: %v", err)
+	}
+	removeNext()
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatalf("expected token file removed, got %v", err)
+	}
+}
+
+func TestSync(t *testing.T) {
+	root := t.TempDir()
+	stubProject(t, nil)
+	original := runSync
+	runSync = func(gotRoot string, project *config.ProjectConfig, _ io.Writer) (*sync.Result, error) {
+		if gotRoot != root {
+			t.Fatalf("unexpected root %q", gotRoot)
+		}
+		return &sync.Result{
+			Warnings:    []warnings.Warning{{Code: "W1", Message: "careful"}},
+			EditedFiles: []sync.EditedFile{{Path: filepath.Join(root, "AGENTS.md")}},
+		}, nil
+	}
+	t.Cleanup(func() { runSync = original })
+
+	server := newTestServer(t, Options{Root: root})
+	resp, err := http.Post(server.URL+"/v1/sync", "application/json", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var result SyncResponse
+	decode(t, resp, http.StatusOK, &result)
+	if len(result.Warnings) != 1 || !strings.Contains(result.Warnings[0], "careful") {
+		t.Fatalf("unexpected warnings %v", result.Warnings)
+	}
+	if len(result.EditedFiles) != 1 || result.EditedFiles[0] != "AGENTS.md" || result.Degradations == nil {
+		t.Fatalf("unexpected result %+v", result)
+	}
+
+	runSync = func(string, *config.ProjectConfig, io.Writer) (*sync.Result, error) { return nil, errors.New("locked") }
+	resp, err = http.Post(server.URL+"/v1/sync", "application/json", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var failure ErrorResponse
+	decode(t, resp, http.StatusInternalServerError, &failure)
+	if failure.Error != "locked" {
+		t.Fatalf("unexpected error %+v", failure)
+	}
+}
+
+func TestSync_StateLockHeld(t *testing.T) {
+	root := t.TempDir()
+	stubProject(t, nil)
+	original := runSync
+	runSync = func(string, *config.ProjectConfig, io.Writer) (*sync.Result, error) {
+		t.Fatal("sync must not run while another process holds the state lock")
+		return nil, nil
+	}
+	t.Cleanup(func() { runSync = original })
+	held, err := statelock.Acquire(root, false)
+	if err != nil {
+		t.Fatalf("Acquire: %v", err)
+	}
+	t.Cleanup(func() { _ = held.Release() })
+
+	server := newTestServer(t, Options{Root: root})
+	resp, err := http.Post(server.URL+"/v1/sync", "application/json", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var failure ErrorResponse
+	decode(t, resp, http.StatusConflict, &failure)
+	if !strings.Contains(failure.Error, "another al process") {
+		t.Fatalf("unexpected error %+v", failure)
+	}
+}
+
+func TestSync_ConfigError(t *testing.T) {
+	stubProject(t, errors.New("bad config"))
+	server := newTestServer(t, Options{Root: t.TempDir()})
+	resp, err := http.Post(server.URL+"/v1/sync", "application/json", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var failure ErrorResponse
+	decode(t, resp, http.StatusUnprocessableEntity, &failure)
+	if failure.Error != "bad config" {
+		t.Fatalf("unexpected error %+v", failure)
+	}
+}
+
+func TestStatus(t *testing.T) {
+	stubProject(t, nil)
+	original := mcpStatus
+	mcpStatus = func(context.Context, *config.ProjectConfig, warnings.Connector, warnings.MCPDiscoveryStatusFunc) ([]warnings.MCPServerStatus, error) {
+		return []warnings.MCPServerStatus{
+			{ID: "github", Transport: "http", ServerName: "gh", ServerVersion: "1.0", Tools: 3, SchemaTokens: 120},
+			{ID: "broken", Transport: "stdio", Err: errors.New("exit 1")},
+		}, nil
+	}
+	t.Cleanup(func() { mcpStatus = original })
+
+	server := newTestServer(t, Options{Root: t.TempDir()})
+	resp, err := http.Get(server.URL + "/v1/status")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var statuses []ServerStatus
+	decode(t, resp, http.StatusOK, &statuses)
+	if len(statuses) != 2 || statuses[0].Tools != 3 || statuses[0].ServerName != "gh" || statuses[1].Error != "exit 1" {
+		t.Fatalf("unexpected statuses %+v", statuses)
+	}
+}
+
+func TestGateway(t *testing.T) {
+	stubProject(t, nil)
+	original := startGateway
+	startGateway = func(ctx context.Context, servers []projection.ResolvedMCPServer, opts mcpgateway.Options) *mcpgateway.Gateway {
+		server := mcp.NewServer(&mcp.Implementation{Name: "agent-layer-gateway", Version: opts.Version}, nil)
+		mcp.AddTool(server, &mcp.Tool{Name: "github.search"}, func(context.Context, *mcp.CallToolRequest, struct{}) (*mcp.CallToolResult, any, error) {
+			return &mcp.CallToolResult{}, nil, nil
+		})
+		return &mcpgateway.Gateway{Server: server}
+	}
+	t.Cleanup(func() { startGateway = original })
+
+	server := newTestServer(t, Options{Root: t.TempDir(), Version: "1.2.3", Gateway: true})
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	client := mcp.NewClient(&mcp.Implementation{Name: "test"}, nil)
+	session, err := client.Connect(ctx, &mcp.StreamableClientTransport{Endpoint: server.URL + "/mcp"}, nil)
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	defer func() { _ = session.Close() }()
+	tools, err := session.ListTools(ctx, nil)
+	if err != nil {
+		t.Fatalf("list tools: %v", err)
+	}
+	if len(tools.Tools) != 1 || tools.Tools[0].Name != "github.search" {
+		t.Fatalf("unexpected tools %+v", tools.Tools)
+	}
+}
+
+func TestListen(t *testing.T) {
+	for _, addr := range []string{"0.0.0.0:0", "example.com:80"} {
+		if _, err := Listen(addr); err == nil || !strings.Contains(err.Error(), "refusing to listen") {
+			t.Fatalf("expected loopback refusal for %s, got %v", addr, err)
+		}
+	}
+	for _, addr := range []string{"7878", "unix:"} {
+		if _, err := Listen(addr); err == nil || !strings.Contains(err.Error(), "invalid listen address") {
+			t.Fatalf("expected invalid address for %s, got %v", addr, err)
+		}
+	}
+
+	listener, err := Listen("127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen tcp: %v", err)
+	}
+	_ = listener.Close()
+
+	dir, err := os.MkdirTemp("", "al-serve")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = os.RemoveAll(dir) })
+	socket := filepath.Join(dir, "al.sock")
+	listener, err = Listen("unix:" + socket)
+	if err != nil {
+		t.Fatalf("listen unix: %v", err)
+	}
+	info, err := os.Stat(socket)
+	if err != nil || info.Mode().Perm() != 0o600 {
+		t.Fatalf("expected owner-only socket, got %v %v", info, err)
+	}
+	_ = listener.Close()
+}
+
+func TestRun_StopsOnCancel(t *testing.T) {
+	listener, err := Listen("127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- Run(ctx, listener, http.NotFoundHandler()) }()
+
+	resp, err := http.Get("http://" + listener.Addr().String() + "/")
+	if err != nil {
+		t.Fatalf("request: %v", err)
+	}
+	_ = resp.Body.Close()
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("run: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Run did not stop after cancel")
+	}
+}
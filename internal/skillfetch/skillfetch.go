@@ -0,0 +1,319 @@
+// Package skillfetch installs skill bundles from git sources into
+// .agent-layer/skills/ and keeps them current through al.lock.
+package skillfetch
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/conn-castle/agent-layer/internal/config"
+	"github.com/conn-castle/agent-layer/internal/i18n"
+	"github.com/conn-castle/agent-layer/internal/lockfile"
+	"github.com/conn-castle/agent-layer/internal/messages"
+	"github.com/conn-castle/agent-layer/internal/remotebase"
+	"github.com/conn-castle/agent-layer/internal/skillvalidator"
+)
+
+// EnvRegistry names a bundle source whose subdirectories are skills, so
+// `al add skill <name>` can resolve bare registry names.
+const EnvRegistry = "AL_SKILL_REGISTRY"
+
+const skillFileName = "SKILL.md"
+
+// fetchSourceFunc refetches a bundle and returns its local directory. Tests
+// replace it to avoid network access.
+var fetchSourceFunc = func(source string) (string, error) {
+	return remotebase.Refresh(remotebase.RealSystem{}, source)
+}
+
+// blockingFindings are validator findings that make a fetched skill unusable.
+var blockingFindings = map[string]bool{
+	skillvalidator.FindingCodeNameMissing:            true,
+	skillvalidator.FindingCodeNameInvalid:            true,
+	skillvalidator.FindingCodeNameTooLong:            true,
+	skillvalidator.FindingCodeNameConsecutiveHyphens: true,
+	skillvalidator.FindingCodeDescriptionMissing:     true,
+	skillvalidator.FindingCodeDescriptionTooLong:     true,
+}
+
+// Status describes what happened to a skill during add or update.
+type Status string
+
+const (
+	// StatusInstalled means the skill was written for the first time.
+	StatusInstalled Status = "installed"
+	// StatusUpdated means the skill content changed.
+	StatusUpdated Status = "updated"
+	// StatusUnchanged means the fetched content matched the lock.
+	StatusUnchanged Status = "unchanged"
+	// StatusSkipped means local edits blocked the update.
+	StatusSkipped Status = "skipped"
+)
+
+// Result reports the outcome for one skill.
+type Result struct {
+	Name     string
+	Source   string
+	Checksum string
+	Status   Status
+	// Warnings are non-blocking validator findings for the fetched SKILL.md.
+	Warnings []skillvalidator.Finding
+}
+
+// NormalizeSource turns a git URL, bundle reference, or registry name into a
+// canonical host/owner/repo[/subdir]@ref reference. Bare names resolve against
+// the bundle named by AL_SKILL_REGISTRY.
+func NormalizeSource(raw string, getenv func(string) string) (string, error) {
+	trimmed := strings.TrimSpace(raw)
+	if trimmed == "" {
+		return "", errors.New(i18n.T(messages.SkillFetchSourceRequired))
+	}
+	if !strings.ContainsAny(trimmed, "/@") {
+		registry := strings.TrimSpace(getenv(EnvRegistry))
+		if registry == "" {
+			return "", i18n.Errorf(messages.SkillFetchRegistryUnsetFmt, trimmed, EnvRegistry, trimmed)
+		}
+		base, err := remotebase.ParseSource(registry)
+		if err != nil {
+			return "", i18n.Errorf(messages.SkillFetchRegistryInvalidFmt, EnvRegistry, err)
+		}
+		base.Subdir = path.Join(base.Subdir, trimmed)
+		return base.String(), nil
+	}
+	trimmed = strings.TrimPrefix(trimmed, "https://")
+	trimmed = strings.TrimPrefix(trimmed, "git@")
+	trimmed = strings.Replace(trimmed, ".git/", "/", 1)
+	trimmed = strings.Replace(trimmed, ".git@", "@", 1)
+	if host, rest, ok := strings.Cut(trimmed, ":"); ok && !strings.Contains(host, "/") {
+		trimmed = host + "/" + rest
+	}
+	source, err := remotebase.ParseSource(trimmed)
+	if err != nil {
+		return "", err
+	}
+	return source.String(), nil
+}
+
+// Add fetches raw and installs it under .agent-layer/skills/<name>, recording
+// it in al.lock. An existing skill is only replaced when force is set or when
+// it was previously added from a lock entry and has no local edits.
+func Add(root string, raw string, force bool) (Result, error) {
+	source, err := NormalizeSource(raw, os.Getenv)
+	if err != nil {
+		return Result{}, err
+	}
+	lock, err := lockfile.Load(root)
+	if err != nil {
+		return Result{}, err
+	}
+	result, err := install(root, &lock, source, "", force)
+	if err != nil {
+		return Result{}, err
+	}
+	if err := lockfile.Save(root, lock); err != nil {
+		return Result{}, err
+	}
+	return result, nil
+}
+
+// Update refetches locked skills (all of them when names is empty) and
+// reinstalls any whose content changed. Skills with local edits are skipped
+// unless force is set.
+func Update(root string, names []string, force bool) ([]Result, error) {
+	lock, err := lockfile.Load(root)
+	if err != nil {
+		return nil, err
+	}
+	entries := lock.Skills
+	if len(names) > 0 {
+		entries = make([]lockfile.Skill, 0, len(names))
+		for _, name := range names {
+			entry, ok := lock.Skill(name)
+			if !ok {
+				return nil, i18n.Errorf(messages.SkillFetchNotLockedFmt, name, lockfile.Path(root))
+			}
+			entries = append(entries, entry)
+		}
+	}
+	if len(entries) == 0 {
+		return nil, nil
+	}
+	results := make([]Result, 0, len(entries))
+	for _, entry := range entries {
+		result, err := install(root, &lock, entry.Source, entry.Name, force)
+		if err != nil {
+			return results, err
+		}
+		results = append(results, result)
+	}
+	if err := lockfile.Save(root, lock); err != nil {
+		return results, err
+	}
+	return results, nil
+}
+
+// install fetches source, validates the bundle, and copies it into place.
+// expectedName is set during update so a renamed upstream skill is rejected.
+func install(root string, lock *lockfile.File, source string, expectedName string, force bool) (Result, error) {
+	bundleDir, err := fetchSourceFunc(source)
+	if err != nil {
+		return Result{}, err
+	}
+	name, warnings, err := validateBundle(source, bundleDir)
+	if err != nil {
+		return Result{}, err
+	}
+	if expectedName != "" && name != expectedName {
+		return Result{}, i18n.Errorf(messages.SkillFetchNameChangedFmt, source, expectedName, name)
+	}
+	checksum, err := remotebase.Checksum(bundleDir)
+	if err != nil {
+		return Result{}, err
+	}
+	result := Result{Name: name, Source: source, Checksum: checksum, Warnings: warnings}
+
+	skillsDir := config.DefaultPaths(root).SkillsDir
+	dest := filepath.Join(skillsDir, name)
+	locked, isLocked := lock.Skill(name)
+	result.Status = StatusInstalled
+	if _, err := os.Stat(dest); err == nil {
+		result.Status = StatusUpdated
+		switch {
+		case force:
+		case !isLocked:
+			return Result{}, i18n.Errorf(messages.SkillFetchExistsFmt, name, dest)
+		default:
+			installed, err := remotebase.Checksum(dest)
+			if err != nil {
+				return Result{}, err
+			}
+			if installed == checksum {
+				result.Status = StatusUnchanged
+				lock.SetSkill(lockfile.Skill{Name: name, Source: source, Checksum: checksum})
+				return result, nil
+			}
+			if installed != locked.Checksum {
+				result.Status = StatusSkipped
+				return result, nil
+			}
+		}
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return Result{}, i18n.Errorf(messages.SkillFetchStatFmt, dest, err)
+	}
+
+	if err := replaceDir(bundleDir, dest); err != nil {
+		return Result{}, err
+	}
+	lock.SetSkill(lockfile.Skill{Name: name, Source: source, Checksum: checksum})
+	return result, nil
+}
+
+// validateBundle checks that bundleDir holds a directory-format skill and
+// returns its name and non-blocking findings.
+func validateBundle(source string, bundleDir string) (string, []skillvalidator.Finding, error) {
+	skillPath := filepath.Join(bundleDir, skillFileName)
+	if _, err := os.Stat(skillPath); err != nil {
+		return "", nil, i18n.Errorf(messages.SkillFetchMissingSkillFileFmt, source, skillFileName)
+	}
+	parsed, err := skillvalidator.ParseSkillSource(skillPath)
+	if err != nil {
+		return "", nil, i18n.Errorf(messages.SkillFetchInvalidFmt, source, err)
+	}
+	name := ""
+	if parsed.Name != nil {
+		name = strings.TrimSpace(*parsed.Name)
+	}
+	parsed.CanonicalName = name
+	parsed.SourceFormat = skillvalidator.SourceFormatDirectory
+
+	var blocking []string
+	var warnings []skillvalidator.Finding
+	for _, finding := range skillvalidator.ValidateParsedSkill(parsed) {
+		if blockingFindings[finding.Code] {
+			blocking = append(blocking, finding.Message)
+			continue
+		}
+		warnings = append(warnings, finding)
+	}
+	if len(blocking) > 0 {
+		return "", nil, i18n.Errorf(messages.SkillFetchInvalidFmt, source, errors.New(strings.Join(blocking, "; ")))
+	}
+	return name, warnings, nil
+}
+
+// replaceDir copies src into a staging sibling of dest and swaps it into place.
+func replaceDir(src string, dest string) error {
+	parent := filepath.Dir(dest)
+	if err := os.MkdirAll(parent, 0o755); err != nil { // #nosec G301 -- skills are non-secret project files.
+		return i18n.Errorf(messages.SkillFetchWriteFmt, dest, err)
+	}
+	staging, err := os.MkdirTemp(parent, "."+filepath.Base(dest)+".add-*")
+	if err != nil {
+		return i18n.Errorf(messages.SkillFetchWriteFmt, dest, err)
+	}
+	defer func() { _ = os.RemoveAll(staging) }()
+
+	next := filepath.Join(staging, "next")
+	if err := copyTree(src, next); err != nil {
+		return i18n.Errorf(messages.SkillFetchWriteFmt, dest, err)
+	}
+	previous := filepath.Join(staging, "previous")
+	if err := os.Rename(dest, previous); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return i18n.Errorf(messages.SkillFetchWriteFmt, dest, err)
+	}
+	if err := os.Rename(next, dest); err != nil {
+		_ = os.Rename(previous, dest)
+		return i18n.Errorf(messages.SkillFetchWriteFmt, dest, err)
+	}
+	return nil
+}
+
+// copyTree copies regular files and directories from src to dest. Symlinks
+// and git metadata are skipped so a bundle cannot point outside itself.
+func copyTree(src string, dest string) error {
+	return filepath.WalkDir(src, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, p)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dest, rel)
+		switch {
+		case d.IsDir() && d.Name() == ".git":
+			return filepath.SkipDir
+		case d.IsDir():
+			return os.MkdirAll(target, 0o755) // #nosec G301 -- skills are non-secret project files.
+		case !d.Type().IsRegular():
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		return copyFile(p, target, info.Mode().Perm()|0o600)
+	})
+}
+
+func copyFile(src string, dest string, perm fs.FileMode) error {
+	in, err := os.Open(src) // #nosec G304 -- src is produced by walking the cached bundle directory.
+	if err != nil {
+		return err
+	}
+	defer func() { _ = in.Close() }()
+	out, err := os.OpenFile(dest, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, perm) // #nosec G304 -- dest is inside the staging directory.
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		_ = out.Close()
+		return err
+	}
+	return out.Close()
+}
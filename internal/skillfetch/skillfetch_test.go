@@ -0,0 +1,219 @@
+package skillfetch
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/conn-castle/agent-layer/internal/lockfile"
+)
+
+const reviewSkill = "---\nname: review\ndescription: Review a change\n---\n\nReview carefully.\n"
+
+func writeBundleFile(t *testing.T, dir string, rel string, content string) {
+	t.Helper()
+	path := filepath.Join(dir, filepath.FromSlash(rel))
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("write %s: %v", rel, err)
+	}
+}
+
+// stubFetch serves bundles from local directories keyed by canonical source.
+func stubFetch(t *testing.T, bundles map[string]string) *[]string {
+	t.Helper()
+	var fetched []string
+	orig := fetchSourceFunc
+	fetchSourceFunc = func(source string) (string, error) {
+		fetched = append(fetched, source)
+		dir, ok := bundles[source]
+		if !ok {
+			t.Fatalf("unexpected fetch of %s", source)
+		}
+		return dir, nil
+	}
+	t.Cleanup(func() { fetchSourceFunc = orig })
+	return &fetched
+}
+
+func newRepo(t *testing.T) string {
+	t.Helper()
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, ".agent-layer", "skills"), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	return root
+}
+
+func TestNormalizeSource(t *testing.T) {
+	env := func(key string) string {
+		if key == EnvRegistry {
+			return "github.com/org/catalog/skills@v2"
+		}
+		return ""
+	}
+	tests := map[string]string{
+		"github.com/org/skills/review@v1":             "github.com/org/skills/review@v1",
+		"https://github.com/org/review.git@v1":        "github.com/org/review@v1",
+		"https://github.com/org/skills.git/review@v1": "github.com/org/skills/review@v1",
+		"git@github.com:org/review.git@main":          "github.com/org/review@main",
+		"review":                                      "github.com/org/catalog/skills/review@v2",
+	}
+	for raw, want := range tests {
+		got, err := NormalizeSource(raw, env)
+		if err != nil {
+			t.Fatalf("NormalizeSource(%q) error: %v", raw, err)
+		}
+		if got != want {
+			t.Fatalf("NormalizeSource(%q) = %q, want %q", raw, got, want)
+		}
+	}
+
+	noEnv := func(string) string { return "" }
+	if _, err := NormalizeSource("review", noEnv); err == nil || !strings.Contains(err.Error(), EnvRegistry) {
+		t.Fatalf("expected registry error, got %v", err)
+	}
+	if _, err := NormalizeSource(" ", noEnv); err == nil {
+		t.Fatal("expected empty source error")
+	}
+	if _, err := NormalizeSource("github.com/org/review", noEnv); err == nil {
+		t.Fatal("expected missing ref error")
+	}
+}
+
+func TestAdd_InstallsAndLocks(t *testing.T) {
+	root := newRepo(t)
+	bundle := t.TempDir()
+	writeBundleFile(t, bundle, "SKILL.md", reviewSkill)
+	writeBundleFile(t, bundle, "scripts/check.sh", "echo ok\n")
+	writeBundleFile(t, bundle, ".git/HEAD", "ref")
+	stubFetch(t, map[string]string{"github.com/org/skills/review@v1": bundle})
+
+	result, err := Add(root, "https://github.com/org/skills.git/review@v1", false)
+	if err != nil {
+		t.Fatalf("Add error: %v", err)
+	}
+	if result.Name != "review" || result.Status != StatusInstalled {
+		t.Fatalf("result = %+v", result)
+	}
+	dest := filepath.Join(root, ".agent-layer", "skills", "review")
+	if _, err := os.Stat(filepath.Join(dest, "scripts", "check.sh")); err != nil {
+		t.Fatalf("expected resources to be copied: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dest, ".git")); !os.IsNotExist(err) {
+		t.Fatalf("expected git metadata to be skipped, got %v", err)
+	}
+
+	lock, err := lockfile.Load(root)
+	if err != nil {
+		t.Fatalf("load lock: %v", err)
+	}
+	entry, ok := lock.Skill("review")
+	if !ok || entry.Source != "github.com/org/skills/review@v1" || entry.Checksum != result.Checksum {
+		t.Fatalf("lock entry = %+v (%v)", entry, ok)
+	}
+
+	again, err := Add(root, "github.com/org/skills/review@v1", false)
+	if err != nil || again.Status != StatusUnchanged {
+		t.Fatalf("re-add = %+v (%v), want unchanged", again, err)
+	}
+}
+
+func TestAdd_RefusesUnmanagedExistingSkill(t *testing.T) {
+	root := newRepo(t)
+	writeBundleFile(t, filepath.Join(root, ".agent-layer", "skills"), "review/SKILL.md", reviewSkill)
+	bundle := t.TempDir()
+	writeBundleFile(t, bundle, "SKILL.md", reviewSkill+"\nUpstream.\n")
+	stubFetch(t, map[string]string{"github.com/org/review@v1": bundle})
+
+	if _, err := Add(root, "github.com/org/review@v1", false); err == nil || !strings.Contains(err.Error(), "--force") {
+		t.Fatalf("expected existing-skill error, got %v", err)
+	}
+	result, err := Add(root, "github.com/org/review@v1", true)
+	if err != nil || result.Status != StatusUpdated {
+		t.Fatalf("forced add = %+v (%v)", result, err)
+	}
+}
+
+func TestAdd_RejectsInvalidBundles(t *testing.T) {
+	root := newRepo(t)
+	empty := t.TempDir()
+	noName := t.TempDir()
+	writeBundleFile(t, noName, "SKILL.md", "---\ndescription: missing name\n---\n\nBody.\n")
+	stubFetch(t, map[string]string{
+		"github.com/org/empty@v1":  empty,
+		"github.com/org/noname@v1": noName,
+	})
+
+	if _, err := Add(root, "github.com/org/empty@v1", false); err == nil || !strings.Contains(err.Error(), "SKILL.md") {
+		t.Fatalf("expected missing SKILL.md error, got %v", err)
+	}
+	if _, err := Add(root, "github.com/org/noname@v1", false); err == nil || !strings.Contains(err.Error(), "invalid skill") {
+		t.Fatalf("expected invalid skill error, got %v", err)
+	}
+	if _, err := os.Stat(lockfile.Path(root)); !os.IsNotExist(err) {
+		t.Fatalf("expected no lockfile after failed adds, got %v", err)
+	}
+}
+
+func TestUpdate_RefreshesAndSkipsLocalEdits(t *testing.T) {
+	root := newRepo(t)
+	review := t.TempDir()
+	writeBundleFile(t, review, "SKILL.md", reviewSkill)
+	deploy := t.TempDir()
+	writeBundleFile(t, deploy, "SKILL.md", "---\nname: deploy\ndescription: Deploy\n---\n\nDeploy.\n")
+	stubFetch(t, map[string]string{
+		"github.com/org/review@main": review,
+		"github.com/org/deploy@main": deploy,
+	})
+	for _, source := range []string{"github.com/org/review@main", "github.com/org/deploy@main"} {
+		if _, err := Add(root, source, false); err != nil {
+			t.Fatalf("Add %s: %v", source, err)
+		}
+	}
+
+	writeBundleFile(t, review, "SKILL.md", reviewSkill+"\nNew step.\n")
+	writeBundleFile(t, deploy, "SKILL.md", "---\nname: deploy\ndescription: Deploy\n---\n\nDeploy v2.\n")
+	writeBundleFile(t, filepath.Join(root, ".agent-layer", "skills"), "deploy/SKILL.md", "local edit")
+
+	results, err := Update(root, nil, false)
+	if err != nil {
+		t.Fatalf("Update error: %v", err)
+	}
+	statuses := map[string]Status{}
+	for _, result := range results {
+		statuses[result.Name] = result.Status
+	}
+	if statuses["review"] != StatusUpdated || statuses["deploy"] != StatusSkipped {
+		t.Fatalf("statuses = %v", statuses)
+	}
+	data, _ := os.ReadFile(filepath.Join(root, ".agent-layer", "skills", "review", "SKILL.md"))
+	if !strings.Contains(string(data), "New step.") {
+		t.Fatalf("expected updated review skill, got %q", data)
+	}
+
+	results, err = Update(root, []string{"deploy"}, true)
+	if err != nil || len(results) != 1 || results[0].Status != StatusUpdated {
+		t.Fatalf("forced update = %+v (%v)", results, err)
+	}
+	if _, err := Update(root, []string{"unknown"}, false); err == nil || !strings.Contains(err.Error(), "not recorded") {
+		t.Fatalf("expected unknown skill error, got %v", err)
+	}
+}
+
+func TestUpdate_RejectsRenamedUpstreamSkill(t *testing.T) {
+	root := newRepo(t)
+	bundle := t.TempDir()
+	writeBundleFile(t, bundle, "SKILL.md", reviewSkill)
+	stubFetch(t, map[string]string{"github.com/org/review@main": bundle})
+	if _, err := Add(root, "github.com/org/review@main", false); err != nil {
+		t.Fatalf("Add error: %v", err)
+	}
+	writeBundleFile(t, bundle, "SKILL.md", "---\nname: critique\ndescription: Renamed\n---\n\nBody.\n")
+	if _, err := Update(root, nil, false); err == nil || !strings.Contains(err.Error(), "critique") {
+		t.Fatalf("expected rename error, got %v", err)
+	}
+}
@@ -1,6 +1,7 @@
 // Package skillfrontmatter is the canonical structural parser for SKILL.md
 // YAML front matter. It owns root-mapping validation, duplicate-key
-// rejection, scalar field typing, and metadata string-map validation.
+// rejection, scalar field typing, metadata string-map validation, and the
+// requires string list.
 // Consumer-specific policy (required fields, standards warnings, sync
 // semantics) stays with the consumers.
 package skillfrontmatter
@@ -26,7 +27,8 @@ const (
 	// KindSyntax reports YAML that could not be parsed at all.
 	KindSyntax ErrorKind = iota + 1
 	// KindType reports a structural or type violation: a non-mapping root,
-	// a non-string scalar field, or a malformed metadata map.
+	// a non-string scalar field, a malformed metadata map, or a requires
+	// value that is not a list of strings.
 	KindType
 	// KindDuplicateKey reports a duplicate top-level or metadata key.
 	KindDuplicateKey
@@ -89,9 +91,14 @@ type Document struct {
 	Compatibility Field
 	// AllowedTools is the "allowed-tools" field.
 	AllowedTools Field
+	// EnabledWhen is the "enabled_when" field.
+	EnabledWhen Field
 	// Metadata holds the "metadata" string map; nil when the key is absent
 	// or null, possibly empty when an empty map was supplied.
 	Metadata map[string]string
+	// Requires holds the "requires" list of skill names in document order;
+	// nil when the key is absent or null.
+	Requires []string
 }
 
 // Parse parses SKILL.md YAML front-matter content into a Document.
@@ -142,6 +149,8 @@ func Parse(content string) (Document, error) {
 			target = &doc.Compatibility
 		case "allowed-tools":
 			target = &doc.AllowedTools
+		case "enabled_when":
+			target = &doc.EnabledWhen
 		case "metadata":
 			metadata, err := parseMetadata(valueNode)
 			if err != nil {
@@ -149,6 +158,13 @@ func Parse(content string) (Document, error) {
 			}
 			doc.Metadata = metadata
 			continue
+		case "requires":
+			requires, err := parseRequires(valueNode)
+			if err != nil {
+				return Document{}, err
+			}
+			doc.Requires = requires
+			continue
 		default:
 			// Unknown fields are tolerated at parse time; consumers decide
 			// whether to warn on them.
@@ -205,6 +221,23 @@ func parseMetadata(node *yaml.Node) (map[string]string, error) {
 	return metadata, nil
 }
 
+func parseRequires(node *yaml.Node) ([]string, error) {
+	if node.Kind == yaml.ScalarNode && node.Tag == yamlTagNull {
+		return nil, nil
+	}
+	if node.Kind != yaml.SequenceNode {
+		return nil, typeError("field \"requires\" must be a list of skill names")
+	}
+	requires := make([]string, 0, len(node.Content))
+	for _, item := range node.Content {
+		if item.Kind != yaml.ScalarNode || (item.Tag != "" && item.Tag != yamlTagStr) {
+			return nil, typeError("field \"requires\" must be a list of skill names")
+		}
+		requires = append(requires, item.Value)
+	}
+	return requires, nil
+}
+
 func typeError(detail string) *Error {
 	return &Error{Kind: KindType, Detail: detail}
 }
@@ -72,6 +72,7 @@ func TestParse_NonStringScalarFieldsRejected(t *testing.T) {
 		"license:\n  - item\n",
 		"compatibility:\n  codex: \">=0.1\"\n",
 		"allowed-tools:\n  - Read\n",
+		"enabled_when: true\n",
 	}
 	for _, content := range cases {
 		parseErr := parseKindErr(t, content, KindType)
@@ -117,6 +118,28 @@ func TestParse_MetadataNullAndValues(t *testing.T) {
 	}
 }
 
+func TestParse_Requires(t *testing.T) {
+	doc, err := Parse("requires: [setup, \"lint\"]\n")
+	if err != nil {
+		t.Fatalf("Parse requires error: %v", err)
+	}
+	if len(doc.Requires) != 2 || doc.Requires[0] != "setup" || doc.Requires[1] != "lint" {
+		t.Fatalf("requires = %#v, want [setup lint]", doc.Requires)
+	}
+
+	doc, err = Parse("requires: ~\n")
+	if err != nil || doc.Requires != nil {
+		t.Fatalf("Parse(null requires) = %#v, %v; want nil", doc.Requires, err)
+	}
+
+	for _, content := range []string{"requires: setup\n", "requires:\n  - 42\n", "requires:\n  - [nested]\n"} {
+		parseErr := parseKindErr(t, content, KindType)
+		if !strings.Contains(parseErr.Detail, "list of skill names") {
+			t.Fatalf("Parse(%q) detail = %q, want list-type violation", content, parseErr.Detail)
+		}
+	}
+}
+
 func TestParse_FieldStateDistinguishesAbsentNullValue(t *testing.T) {
 	doc, err := Parse("description: here\nlicense: null\n")
 	if err != nil {
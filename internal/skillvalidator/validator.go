@@ -104,6 +104,8 @@ var allowedFrontMatterFields = map[string]struct{}{
 	"compatibility":  {},
 	"metadata":       {},
 	"allowed-tools":  {},
+	"requires":       {},
+	"enabled_when":   {},
 }
 
 // ParseSkillSource reads and parses a skill source file into validator input.
@@ -183,7 +185,7 @@ func ValidateMetadata(parsed ParsedSkill) []Finding {
 			findings = append(findings, warning(
 				FindingCodeUnknownField,
 				parsed.SourcePath,
-				fmt.Sprintf("unknown frontmatter field %q (allowed: name, description, license, compatibility, metadata, allowed-tools)", key),
+				fmt.Sprintf("unknown frontmatter field %q (allowed: name, description, license, compatibility, metadata, allowed-tools, requires, enabled_when)", key),
 			))
 		}
 	}
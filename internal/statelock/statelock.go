@@ -0,0 +1,127 @@
+// Package statelock keeps concurrent al processes from interleaving writes to
+// a repo. Commands that write generated outputs or .agent-layer/ state, such as
+// sync, upgrade, and init, hold an exclusive advisory lock on
+// .agent-layer/state/process.lock while they run. The holder records its PID
+// in the file so a blocked process can name it.
+package statelock
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/sys/unix"
+
+	"github.com/conn-castle/agent-layer/internal/i18n"
+	"github.com/conn-castle/agent-layer/internal/layerdir"
+	"github.com/conn-castle/agent-layer/internal/messages"
+)
+
+// FileName is the name of the lock file inside .agent-layer/state/.
+const FileName = "process.lock"
+
+const pollEvery = 100 * time.Millisecond
+
+// ErrHeld matches the error Acquire returns when another process holds the lock.
+var ErrHeld = errors.New("state lock held by another process")
+
+// Test seams.
+var (
+	flock = unix.Flock
+	sleep = time.Sleep
+)
+
+// Lock is a held state lock.
+type Lock struct {
+	file *os.File
+	path string
+}
+
+type heldError struct {
+	message string
+}
+
+func (e *heldError) Error() string { return e.message }
+func (e *heldError) Unwrap() error { return ErrHeld }
+
+// Path returns the state lock path for a repo root.
+func Path(root string) string {
+	return filepath.Join(layerdir.Dir(root), "state", FileName)
+}
+
+// Acquire takes the state lock for root. When another process holds it,
+// Acquire returns an error matching ErrHeld, or blocks until the lock is free
+// when wait is true.
+func Acquire(root string, wait bool) (*Lock, error) {
+	path := Path(root)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil { // #nosec G301 -- state/ holds no secrets; matches the other .agent-layer directories.
+		return nil, i18n.Errorf(messages.StateLockOpenFmt, path, err)
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0o644) // #nosec G304,G302 -- lock path is rooted under the caller-resolved repo's .agent-layer directory; the file holds only a PID.
+	if err != nil {
+		return nil, i18n.Errorf(messages.StateLockOpenFmt, path, err)
+	}
+	for {
+		err = flock(int(file.Fd()), unix.LOCK_EX|unix.LOCK_NB) //nolint:gosec // Unix file descriptors are small non-negative ints on supported platforms.
+		if err == nil {
+			break
+		}
+		if errors.Is(err, unix.EINTR) {
+			continue
+		}
+		if !errors.Is(err, unix.EWOULDBLOCK) && !errors.Is(err, unix.EAGAIN) {
+			_ = file.Close()
+			return nil, i18n.Errorf(messages.StateLockFmt, path, err)
+		}
+		if !wait {
+			pid := holderPID(file)
+			_ = file.Close()
+			return nil, heldErrorFor(root, pid)
+		}
+		sleep(pollEvery)
+	}
+	if err := file.Truncate(0); err == nil {
+		_, _ = file.WriteAt([]byte(strconv.Itoa(os.Getpid())+"\n"), 0)
+	}
+	return &Lock{file: file, path: path}, nil
+}
+
+// Release unlocks and closes the state lock. The file stays in place so a
+// waiting process keeps locking the same inode.
+func (l *Lock) Release() error {
+	if l == nil || l.file == nil {
+		return nil
+	}
+	unlockErr := flock(int(l.file.Fd()), unix.LOCK_UN) //nolint:gosec // Unix file descriptors are small non-negative ints on supported platforms.
+	closeErr := l.file.Close()
+	l.file = nil
+	if unlockErr != nil {
+		return i18n.Errorf(messages.StateLockReleaseFmt, l.path, unlockErr)
+	}
+	if closeErr != nil {
+		return i18n.Errorf(messages.StateLockReleaseFmt, l.path, closeErr)
+	}
+	return nil
+}
+
+// holderPID reads the PID the current holder recorded, or 0 when it has not
+// written one yet.
+func holderPID(file *os.File) int {
+	buf := make([]byte, 32)
+	n, _ := file.ReadAt(buf, 0)
+	pid, err := strconv.Atoi(strings.TrimSpace(string(buf[:n])))
+	if err != nil || pid <= 0 {
+		return 0
+	}
+	return pid
+}
+
+func heldErrorFor(root string, pid int) error {
+	if pid == 0 {
+		return &heldError{message: i18n.Sprintf(messages.StateLockHeldFmt, root)}
+	}
+	return &heldError{message: i18n.Sprintf(messages.StateLockHeldPIDFmt, pid, root)}
+}
@@ -0,0 +1,50 @@
+package statelock
+
+import (
+	"errors"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAcquire_HeldAndWait(t *testing.T) {
+	root := t.TempDir()
+	first, err := Acquire(root, false)
+	if err != nil {
+		t.Fatalf("Acquire: %v", err)
+	}
+
+	_, err = Acquire(root, false)
+	if !errors.Is(err, ErrHeld) || !strings.Contains(err.Error(), "pid "+strconv.Itoa(os.Getpid())) || !strings.Contains(err.Error(), "--wait") {
+		t.Fatalf("second Acquire = %v", err)
+	}
+
+	origSleep := sleep
+	t.Cleanup(func() { sleep = origSleep })
+	var waits int
+	sleep = func(time.Duration) {
+		waits++
+		if err := first.Release(); err != nil {
+			t.Errorf("Release: %v", err)
+		}
+	}
+	second, err := Acquire(root, true)
+	if err != nil || waits != 1 {
+		t.Fatalf("waiting Acquire = %v after %d waits", err, waits)
+	}
+	if err := second.Release(); err != nil {
+		t.Fatalf("Release: %v", err)
+	}
+	if _, err := os.Stat(Path(root)); err != nil {
+		t.Fatalf("lock file must stay in place: %v", err)
+	}
+}
+
+func TestHeldErrorFor_WithoutPID(t *testing.T) {
+	err := heldErrorFor("/repo", 0)
+	if !errors.Is(err, ErrHeld) || strings.Contains(err.Error(), "pid") || !strings.Contains(err.Error(), "/repo") {
+		t.Fatalf("heldErrorFor = %v", err)
+	}
+}
@@ -0,0 +1,102 @@
+// Package style applies Agent Layer's optional ANSI styling to report text
+// such as the upgrade plan, al doctor, and al mcp status. Every helper returns
+// its input unchanged when color is off, so reports read the same in logs.
+//
+// The color mode is auto, always, or never. Auto colors only when stdout is a
+// terminal, NO_COLOR is unset, and TERM is not "dumb", which is the detection
+// fatih/color performs at startup.
+package style
+
+import (
+	"strings"
+
+	"github.com/fatih/color"
+
+	"github.com/conn-castle/agent-layer/internal/i18n"
+	"github.com/conn-castle/agent-layer/internal/messages"
+)
+
+// EnvVar sets the color mode when the root --color flag is not passed.
+const EnvVar = "AL_COLOR"
+
+// Mode selects when output is colored.
+type Mode string
+
+const (
+	// Auto colors a terminal unless NO_COLOR is set.
+	Auto Mode = "auto"
+	// Always colors even when output is redirected.
+	Always Mode = "always"
+	// Never prints plain text.
+	Never Mode = "never"
+)
+
+// autoNoColor is fatih/color's startup detection, kept so Apply(Auto) can
+// restore it after another mode was applied.
+var autoNoColor = color.NoColor
+
+// ParseMode parses a --color or AL_COLOR value. Empty means auto.
+func ParseMode(value string) (Mode, error) {
+	switch mode := Mode(strings.ToLower(strings.TrimSpace(value))); mode {
+	case "":
+		return Auto, nil
+	case Auto, Always, Never:
+		return mode, nil
+	default:
+		return "", i18n.Errorf(messages.StyleColorInvalidFmt, value)
+	}
+}
+
+// Apply sets the process-wide color switch for mode.
+func Apply(mode Mode) {
+	switch mode {
+	case Always:
+		color.NoColor = false
+	case Never:
+		color.NoColor = true
+	default:
+		color.NoColor = autoNoColor
+	}
+}
+
+// Enabled reports whether output is currently colored.
+func Enabled() bool {
+	return !color.NoColor
+}
+
+var (
+	headingColor = color.New(color.Bold)
+	successColor = color.New(color.FgGreen)
+	warningColor = color.New(color.FgYellow)
+	failureColor = color.New(color.FgRed)
+)
+
+// Heading styles a section heading. Leading and trailing whitespace, such as
+// the blank line a heading message starts with, stays unstyled.
+func Heading(text string) string {
+	return paint(headingColor, text)
+}
+
+// Success styles text that reports a passing check.
+func Success(text string) string {
+	return paint(successColor, text)
+}
+
+// Warning styles text that needs the reader's attention.
+func Warning(text string) string {
+	return paint(warningColor, text)
+}
+
+// Failure styles text that reports a failed check.
+func Failure(text string) string {
+	return paint(failureColor, text)
+}
+
+func paint(c *color.Color, text string) string {
+	trimmed := strings.TrimSpace(text)
+	if color.NoColor || trimmed == "" {
+		return text
+	}
+	start := strings.Index(text, trimmed)
+	return text[:start] + c.Sprint(trimmed) + text[start+len(trimmed):]
+}
@@ -0,0 +1,64 @@
+package style
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/fatih/color"
+)
+
+func withNoColor(t *testing.T, noColor bool) {
+	t.Helper()
+	orig := color.NoColor
+	color.NoColor = noColor
+	t.Cleanup(func() { color.NoColor = orig })
+}
+
+func TestParseMode(t *testing.T) {
+	tests := map[string]Mode{"": Auto, "auto": Auto, " Always ": Always, "never": Never}
+	for value, want := range tests {
+		got, err := ParseMode(value)
+		if err != nil || got != want {
+			t.Fatalf("ParseMode(%q) = %q, %v; want %q", value, got, err, want)
+		}
+	}
+	if _, err := ParseMode("sometimes"); err == nil || !strings.Contains(err.Error(), "sometimes") {
+		t.Fatalf("expected invalid mode error, got %v", err)
+	}
+}
+
+func TestApply(t *testing.T) {
+	withNoColor(t, autoNoColor)
+	Apply(Always)
+	if !Enabled() {
+		t.Fatal("expected color on for always")
+	}
+	Apply(Never)
+	if Enabled() {
+		t.Fatal("expected color off for never")
+	}
+	Apply(Auto)
+	if Enabled() == autoNoColor {
+		t.Fatalf("expected auto to restore startup detection (NoColor=%v)", autoNoColor)
+	}
+}
+
+func TestHelpersPlainWhenColorOff(t *testing.T) {
+	withNoColor(t, true)
+	for _, fn := range []func(string) string{Heading, Success, Warning, Failure} {
+		if got := fn("\nSummary:"); got != "\nSummary:" {
+			t.Fatalf("expected plain text, got %q", got)
+		}
+	}
+}
+
+func TestHeadingKeepsSurroundingWhitespacePlain(t *testing.T) {
+	withNoColor(t, false)
+	got := Heading("\nSummary:\n")
+	if !strings.HasPrefix(got, "\n\x1b[") || !strings.HasSuffix(got, "m\n") || !strings.Contains(got, "Summary:") {
+		t.Fatalf("unexpected styled heading %q", got)
+	}
+	if Failure("   ") != "   " {
+		t.Fatal("expected blank text to stay unstyled")
+	}
+}
@@ -1,15 +1,13 @@
 package sync
 
 import (
-	"bytes"
-	"encoding/json"
 	"fmt"
-	"io"
 	"os"
 	"path/filepath"
 	"strings"
 
 	"github.com/conn-castle/agent-layer/internal/config"
+	"github.com/conn-castle/agent-layer/internal/i18n"
 	"github.com/conn-castle/agent-layer/internal/messages"
 	"github.com/conn-castle/agent-layer/internal/projection"
 )
@@ -56,63 +54,22 @@ func writeAntigravitySettings(sys System, root string, project *config.ProjectCo
 	}
 	data, err := sys.MarshalIndent(settings, "", "  ")
 	if err != nil {
-		return fmt.Errorf(messages.SyncMarshalAntigravitySettingsFailedFmt, err)
+		return i18n.Errorf(messages.SyncMarshalAntigravitySettingsFailedFmt, err)
 	}
 	data = append(data, '\n')
 	if err := sys.MkdirAll(filepath.Dir(path), 0o755); err != nil {
-		return fmt.Errorf(messages.SyncCreateDirFailedFmt, filepath.Dir(path), err)
+		return i18n.Errorf(messages.SyncCreateDirFailedFmt, filepath.Dir(path), err)
 	}
 	if err := sys.WriteFileAtomic(path, data, antigravitySettingsFileMode(sys, path)); err != nil {
-		return fmt.Errorf(messages.SyncWriteFileFailedFmt, path, err)
+		return i18n.Errorf(messages.SyncWriteFileFailedFmt, path, err)
 	}
 	return nil
 }
 
-// readAntigravitySettings loads and validates the user's native Antigravity
-// settings.json for merging. A missing, empty, or whitespace-only file yields a
-// fresh empty object because there is no native state to preserve. It rejects a
-// symlink or non-regular target, malformed or non-object JSON, and trailing
-// data so a corrupt native file fails loud before any write, and uses a
-// number-preserving decoder so large or high-precision native numbers survive
-// the round trip.
+// readAntigravitySettings loads the user's native Antigravity settings.json
+// for merging; see readNativeSettings.
 func readAntigravitySettings(sys System, path string) (map[string]any, error) {
-	info, err := sys.Lstat(path)
-	if err != nil {
-		if os.IsNotExist(err) {
-			return make(map[string]any), nil
-		}
-		return nil, fmt.Errorf(messages.InstallFailedStatFmt, path, err)
-	}
-	if info.Mode()&os.ModeSymlink != 0 || !info.Mode().IsRegular() {
-		return nil, fmt.Errorf("antigravity settings must be a regular file, not a symlink or special file: %s", path)
-	}
-	data, err := sys.ReadFile(path)
-	if err != nil {
-		return nil, fmt.Errorf("read Antigravity settings %s: %w", path, err)
-	}
-	if len(bytes.TrimSpace(data)) == 0 {
-		// An empty or whitespace-only file holds no native state to preserve;
-		// treat it like a missing file so a truncated or editor-created empty
-		// file does not fail the whole sync.
-		return make(map[string]any), nil
-	}
-	decoder := json.NewDecoder(bytes.NewReader(data))
-	decoder.UseNumber()
-	var settings map[string]any
-	if err := decoder.Decode(&settings); err != nil {
-		return nil, fmt.Errorf("decode Antigravity settings %s: %w", path, err)
-	}
-	if settings == nil {
-		return nil, fmt.Errorf("decode Antigravity settings %s: top-level JSON value must be an object", path)
-	}
-	var trailing any
-	if err := decoder.Decode(&trailing); err != io.EOF {
-		if err == nil {
-			return nil, fmt.Errorf("decode Antigravity settings %s: trailing JSON value", path)
-		}
-		return nil, fmt.Errorf("decode Antigravity settings %s: trailing data: %w", path, err)
-	}
-	return settings, nil
+	return readNativeSettings(sys, path, "Antigravity")
 }
 
 // mergeAntigravitySettings overlays the Agent Layer-managed projection (model,
@@ -130,61 +87,17 @@ func mergeAntigravitySettings(existing, desired map[string]any) (map[string]any,
 	if !ok {
 		return nil, fmt.Errorf("existing Antigravity settings must be a JSON object")
 	}
-	if err := overlayAntigravityManagedMap(merged, desired, nil); err != nil {
+	if err := overlayManagedSettings(merged, desired, nil); err != nil {
 		return nil, err
 	}
 	return merged, nil
 }
 
 // antigravitySettingsFileMode preserves the existing settings.json permission
-// bits when the file is already a regular file, falling back to owner-only
-// 0o600 for a newly created file so native trust or approval state is never
-// written with widened permissions.
+// bits, falling back to owner-only 0o600 for a newly created file so native
+// trust or approval state is never written with widened permissions.
 func antigravitySettingsFileMode(sys System, path string) os.FileMode {
-	if info, err := sys.Lstat(path); err == nil && info.Mode().IsRegular() {
-		return info.Mode().Perm()
-	}
-	return 0o600
-}
-
-// overlayAntigravityManagedMap recursively overlays the managed desired map onto
-// target, preserving native sibling keys. Nested objects are merged; scalar and
-// array values replace the value at their key. It returns an error when a
-// desired object would overwrite a native scalar or vice versa, so Agent Layer
-// never silently reshapes native state. prefix carries the path walked so far
-// for error messages.
-func overlayAntigravityManagedMap(target, desired map[string]any, prefix []string) error {
-	for key, value := range desired {
-		path := append(append([]string{}, prefix...), key)
-		if desiredMap, ok := value.(map[string]any); ok {
-			current, exists := target[key]
-			if !exists {
-				current = make(map[string]any)
-				target[key] = current
-			}
-			targetMap, ok := current.(map[string]any)
-			if !ok {
-				return fmt.Errorf("managed path %s requires an object", strings.Join(path, "."))
-			}
-			if err := overlayAntigravityManagedMap(targetMap, desiredMap, path); err != nil {
-				return err
-			}
-			continue
-		}
-		if current, exists := target[key]; exists && antigravityShapeConflict(current, value) {
-			return fmt.Errorf("managed path %s has incompatible existing shape", strings.Join(path, "."))
-		}
-		target[key] = cloneAgentSpecificValue(value)
-	}
-	return nil
-}
-
-// antigravityShapeConflict reports whether current and desired disagree on being
-// a JSON object, which the overlay treats as an incompatible managed-path shape.
-func antigravityShapeConflict(current, desired any) bool {
-	_, currentObject := current.(map[string]any)
-	_, desiredObject := desired.(map[string]any)
-	return currentObject != desiredObject
+	return nativeSettingsFileMode(sys, path, 0o600)
 }
 
 func buildAntigravitySettings(project *config.ProjectConfig) map[string]any {
@@ -195,7 +108,7 @@ func buildAntigravitySettings(project *config.ProjectConfig) map[string]any {
 	permissions := buildPermissionsBlock(
 		project.Config,
 		project.CommandsAllow,
-		projection.EnabledServerIDs(project.Config.MCP.Servers, antigravityClientID),
+		projection.EnabledServerIDs(clientMCPServers(project, antigravityClientID), antigravityClientID),
 		antigravityRenderer{},
 	)
 	if permissions != nil {
@@ -213,7 +126,7 @@ func writeAntigravityMCPConfig(sys System, root string, project *config.ProjectC
 	}
 	data, err := sys.MarshalIndent(cfg, "", "  ")
 	if err != nil {
-		return fmt.Errorf(messages.SyncMarshalAntigravityMCPConfigFailedFmt, err)
+		return i18n.Errorf(messages.SyncMarshalAntigravityMCPConfigFailedFmt, err)
 	}
 	data = append(data, '\n')
 
@@ -225,10 +138,10 @@ func writeAntigravityMCPConfig(sys System, root string, project *config.ProjectC
 		return err
 	}
 	if err := sys.MkdirAll(filepath.Dir(path), 0o755); err != nil {
-		return fmt.Errorf(messages.SyncCreateDirFailedFmt, filepath.Dir(path), err)
+		return i18n.Errorf(messages.SyncCreateDirFailedFmt, filepath.Dir(path), err)
 	}
 	if err := sys.WriteFileAtomic(path, data, 0o644); err != nil {
-		return fmt.Errorf(messages.SyncWriteFileFailedFmt, path, err)
+		return i18n.Errorf(messages.SyncWriteFileFailedFmt, path, err)
 	}
 	return nil
 }
@@ -256,7 +169,7 @@ func antigravityMCPConfigWritePath(sys System, root string) (string, error) {
 		return target, nil
 	}
 	if err != nil && !os.IsNotExist(err) {
-		return "", fmt.Errorf(messages.InstallFailedStatFmt, legacyPath, err)
+		return "", i18n.Errorf(messages.InstallFailedStatFmt, legacyPath, err)
 	}
 	if migratedInfo, statErr := sys.Stat(migratedPath); statErr == nil && !migratedInfo.IsDir() {
 		if err := ensureAntigravityPathRealParentContained(root, migratedPath); err != nil {
@@ -264,7 +177,7 @@ func antigravityMCPConfigWritePath(sys System, root string) (string, error) {
 		}
 		return migratedPath, nil
 	} else if statErr != nil && !os.IsNotExist(statErr) {
-		return "", fmt.Errorf(messages.InstallFailedStatFmt, migratedPath, statErr)
+		return "", i18n.Errorf(messages.InstallFailedStatFmt, migratedPath, statErr)
 	}
 	return legacyPath, nil
 }
@@ -283,14 +196,14 @@ func ensureAntigravityPathRealParentContained(root string, path string) error {
 	if info, err := os.Lstat(agyRoot); err == nil && info.Mode()&os.ModeSymlink != 0 {
 		return fmt.Errorf("antigravity data dir must not be a symlink: %s", agyRoot)
 	} else if err != nil && !os.IsNotExist(err) {
-		return fmt.Errorf(messages.InstallFailedStatFmt, agyRoot, err)
+		return i18n.Errorf(messages.InstallFailedStatFmt, agyRoot, err)
 	}
 	realAgyRoot, rootErr := filepath.EvalSymlinks(agyRoot)
 	if rootErr != nil {
 		if os.IsNotExist(rootErr) {
 			return nil
 		}
-		return fmt.Errorf(messages.InstallFailedStatFmt, agyRoot, rootErr)
+		return i18n.Errorf(messages.InstallFailedStatFmt, agyRoot, rootErr)
 	}
 	parent := filepath.Dir(filepath.Clean(path))
 	realParent, parentErr := filepath.EvalSymlinks(parent)
@@ -298,7 +211,7 @@ func ensureAntigravityPathRealParentContained(root string, path string) error {
 		if os.IsNotExist(parentErr) {
 			return nil
 		}
-		return fmt.Errorf(messages.InstallFailedStatFmt, parent, parentErr)
+		return i18n.Errorf(messages.InstallFailedStatFmt, parent, parentErr)
 	}
 	realAgyRoot = filepath.Clean(realAgyRoot)
 	realParent = filepath.Clean(realParent)
@@ -313,7 +226,7 @@ func buildAntigravityMCPConfig(project *config.ProjectConfig) (*antigravityMCPCo
 		Servers: make(OrderedMap[antigravityMCPServer]),
 	}
 	resolved, err := projection.ResolveMCPServers(
-		project.Config.MCP.Servers,
+		clientMCPServers(project, antigravityClientID),
 		project.Env,
 		antigravityClientID,
 		projection.ClientPlaceholderResolver("${%s}"),
@@ -358,10 +271,10 @@ func cleanAntigravityOutputs(sys System, root string) error {
 				return err
 			}
 		} else if !os.IsNotExist(statErr) {
-			return fmt.Errorf(messages.InstallFailedStatFmt, path, statErr)
+			return i18n.Errorf(messages.InstallFailedStatFmt, path, statErr)
 		}
 		if err := sys.Remove(path); err != nil && !os.IsNotExist(err) {
-			return fmt.Errorf(messages.SyncRemoveFailedFmt, path, err)
+			return i18n.Errorf(messages.SyncRemoveFailedFmt, path, err)
 		}
 	}
 	return nil
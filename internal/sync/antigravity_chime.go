@@ -9,6 +9,7 @@ import (
 	"strings"
 
 	"github.com/conn-castle/agent-layer/internal/config"
+	"github.com/conn-castle/agent-layer/internal/i18n"
 	"github.com/conn-castle/agent-layer/internal/messages"
 )
 
@@ -32,22 +33,22 @@ func writeAntigravityChimePlugin(sys System, root string, project *config.Projec
 	info, err := sys.Lstat(dir)
 	if err != nil {
 		if !os.IsNotExist(err) {
-			return fmt.Errorf(messages.InstallFailedStatFmt, dir, err)
+			return i18n.Errorf(messages.InstallFailedStatFmt, dir, err)
 		}
 		created = true
 	} else if info.Mode()&os.ModeSymlink != 0 || !info.IsDir() {
-		return fmt.Errorf(messages.SyncAntigravityChimePluginConflictFmt, dir)
+		return i18n.Errorf(messages.SyncAntigravityChimePluginConflictFmt, dir)
 	} else if err := antigravityChimePluginIsManaged(sys, dir); err != nil {
 		return err
 	}
 	if err := sys.MkdirAll(dir, 0o755); err != nil {
-		return fmt.Errorf(messages.SyncCreateDirFailedFmt, dir, err)
+		return i18n.Errorf(messages.SyncCreateDirFailedFmt, dir, err)
 	}
 	written := make([]antigravityChimePluginFile, 0, len(antigravityChimePluginFiles()))
 	for _, file := range antigravityChimePluginFiles() {
 		path := filepath.Join(dir, file.name)
 		if err := sys.WriteFileAtomic(path, file.data, 0o644); err != nil {
-			writeErr := fmt.Errorf(messages.SyncWriteFileFailedFmt, path, err)
+			writeErr := i18n.Errorf(messages.SyncWriteFileFailedFmt, path, err)
 			if !created {
 				return writeErr
 			}
@@ -70,29 +71,29 @@ func rollbackNewAntigravityChimePlugin(sys System, dir string, written []antigra
 		info, err := sys.Lstat(path)
 		if err != nil {
 			if !os.IsNotExist(err) {
-				rollbackErrs = append(rollbackErrs, fmt.Errorf(messages.InstallFailedStatFmt, path, err))
+				rollbackErrs = append(rollbackErrs, i18n.Errorf(messages.InstallFailedStatFmt, path, err))
 			}
 			continue
 		}
 		if info.Mode()&os.ModeSymlink != 0 || !info.Mode().IsRegular() {
-			rollbackErrs = append(rollbackErrs, fmt.Errorf(messages.SyncAntigravityChimePluginConflictFmt, path))
+			rollbackErrs = append(rollbackErrs, i18n.Errorf(messages.SyncAntigravityChimePluginConflictFmt, path))
 			continue
 		}
 		data, err := sys.ReadFile(path)
 		if err != nil {
-			rollbackErrs = append(rollbackErrs, fmt.Errorf(messages.SyncReadFailedFmt, path, err))
+			rollbackErrs = append(rollbackErrs, i18n.Errorf(messages.SyncReadFailedFmt, path, err))
 			continue
 		}
 		if !bytes.Equal(data, written[i].data) {
-			rollbackErrs = append(rollbackErrs, fmt.Errorf(messages.SyncAntigravityChimePluginConflictFmt, path))
+			rollbackErrs = append(rollbackErrs, i18n.Errorf(messages.SyncAntigravityChimePluginConflictFmt, path))
 			continue
 		}
 		if err := sys.Remove(path); err != nil && !os.IsNotExist(err) {
-			rollbackErrs = append(rollbackErrs, fmt.Errorf(messages.SyncRemoveFailedFmt, path, err))
+			rollbackErrs = append(rollbackErrs, i18n.Errorf(messages.SyncRemoveFailedFmt, path, err))
 		}
 	}
 	if err := sys.Remove(dir); err != nil && !os.IsNotExist(err) {
-		rollbackErrs = append(rollbackErrs, fmt.Errorf(messages.SyncRemoveFailedFmt, dir, err))
+		rollbackErrs = append(rollbackErrs, i18n.Errorf(messages.SyncRemoveFailedFmt, dir, err))
 	}
 	if len(rollbackErrs) == 0 {
 		return nil
@@ -112,16 +113,16 @@ func cleanAntigravityChimePlugin(sys System, root string) error {
 		if os.IsNotExist(err) {
 			return nil
 		}
-		return fmt.Errorf(messages.InstallFailedStatFmt, dir, err)
+		return i18n.Errorf(messages.InstallFailedStatFmt, dir, err)
 	}
 	if info.Mode()&os.ModeSymlink != 0 || !info.IsDir() {
-		return fmt.Errorf(messages.SyncAntigravityChimePluginConflictFmt, dir)
+		return i18n.Errorf(messages.SyncAntigravityChimePluginConflictFmt, dir)
 	}
 	if err := antigravityChimePluginIsManaged(sys, dir); err != nil {
 		return err
 	}
 	if err := sys.RemoveAll(dir); err != nil {
-		return fmt.Errorf(messages.SyncRemoveFailedFmt, dir, err)
+		return i18n.Errorf(messages.SyncRemoveFailedFmt, dir, err)
 	}
 	return nil
 }
@@ -158,22 +159,22 @@ func antigravityChimePluginFilesForCommand(command string) []antigravityChimePlu
 func antigravityChimePluginIsManaged(sys System, dir string) error {
 	entries, err := sys.ReadDir(dir)
 	if err != nil {
-		return fmt.Errorf(messages.SyncReadFailedFmt, dir, err)
+		return i18n.Errorf(messages.SyncReadFailedFmt, dir, err)
 	}
 	if len(entries) != len(antigravityChimePluginFiles()) {
-		return fmt.Errorf(messages.SyncAntigravityChimePluginConflictFmt, dir)
+		return i18n.Errorf(messages.SyncAntigravityChimePluginConflictFmt, dir)
 	}
 	for _, file := range antigravityChimePluginFiles() {
 		path := filepath.Join(dir, file.name)
 		info, err := sys.Lstat(path)
 		if err != nil {
 			if os.IsNotExist(err) {
-				return fmt.Errorf(messages.SyncAntigravityChimePluginConflictFmt, dir)
+				return i18n.Errorf(messages.SyncAntigravityChimePluginConflictFmt, dir)
 			}
-			return fmt.Errorf(messages.InstallFailedStatFmt, path, err)
+			return i18n.Errorf(messages.InstallFailedStatFmt, path, err)
 		}
 		if info.Mode()&os.ModeSymlink != 0 || !info.Mode().IsRegular() {
-			return fmt.Errorf(messages.SyncAntigravityChimePluginConflictFmt, dir)
+			return i18n.Errorf(messages.SyncAntigravityChimePluginConflictFmt, dir)
 		}
 	}
 	for _, files := range [][]antigravityChimePluginFile{antigravityChimePluginFiles(), legacyAntigravityChimePluginFiles()} {
@@ -189,7 +190,7 @@ func antigravityChimePluginIsManaged(sys System, dir string) error {
 			return nil
 		}
 	}
-	return fmt.Errorf(messages.SyncAntigravityChimePluginConflictFmt, dir)
+	return i18n.Errorf(messages.SyncAntigravityChimePluginConflictFmt, dir)
 }
 
 func ensureAntigravityChimePathContained(sys System, root string, target string) error {
@@ -208,10 +209,10 @@ func ensureAntigravityChimePathContained(sys System, root string, target string)
 			if os.IsNotExist(err) {
 				continue
 			}
-			return fmt.Errorf(messages.InstallFailedStatFmt, path, err)
+			return i18n.Errorf(messages.InstallFailedStatFmt, path, err)
 		}
 		if info.Mode()&os.ModeSymlink != 0 {
-			return fmt.Errorf(messages.SyncAntigravityChimePluginConflictFmt, path)
+			return i18n.Errorf(messages.SyncAntigravityChimePluginConflictFmt, path)
 		}
 	}
 	return nil
@@ -0,0 +1,193 @@
+package sync
+
+import (
+	"slices"
+
+	"github.com/conn-castle/agent-layer/internal/config"
+	"github.com/conn-castle/agent-layer/internal/i18n"
+	"github.com/conn-castle/agent-layer/internal/messages"
+	"github.com/conn-castle/agent-layer/internal/projection"
+)
+
+// clientCapabilities records which shared features sync can project natively
+// into one client. Sync consults it to skip or adapt features a client cannot
+// express and reports each adaptation as a Degradation.
+type clientCapabilities struct {
+	// Agent is the [agents.*] key that enables the client.
+	Agent string
+	// MCPClient is the mcp.servers[].clients name the client's MCP config uses.
+	MCPClient string
+	// Enabled reports whether the client is enabled in config.
+	Enabled func(config.AgentsConfig) bool
+	// SkillPrefix is how a user invokes a projected skill: "/" for slash
+	// commands, "$" for Codex skill mentions.
+	SkillPrefix string
+	// MCPTransports lists the mcp.servers[].transport values the client's
+	// generated MCP config can express.
+	MCPTransports []string
+	// CommandAllowlist reports whether commands.allow is projected into a
+	// client permission format.
+	CommandAllowlist bool
+	// MCPAutoApprove reports whether per-server MCP approvals are projected.
+	MCPAutoApprove bool
+	// LaunchAllowsAll reports whether approvals.mode = "all" is applied by a
+	// launch flag that approves every tool, covering both approval kinds.
+	LaunchAllowsAll bool
+	// FileGlobInstructions reports whether the client reads instructions
+	// scoped to a file glob, as Copilot does with applyTo.
+	FileGlobInstructions bool
+}
+
+var allMCPTransports = []string{config.TransportHTTP, config.TransportStdio}
+
+// clientCapabilityRegistry returns the capability matrix. Tests replace it to
+// exercise degradations no shipped client has yet.
+var clientCapabilityRegistry = func() []clientCapabilities {
+	return []clientCapabilities{
+		{
+			Agent:            "antigravity",
+			MCPClient:        antigravityClientID,
+			Enabled:          func(a config.AgentsConfig) bool { return config.IsAgentEnabled(a.Antigravity.Enabled) },
+			SkillPrefix:      "/",
+			MCPTransports:    allMCPTransports,
+			CommandAllowlist: true,
+			MCPAutoApprove:   true,
+		},
+		{
+			Agent:            "claude",
+			MCPClient:        "claude",
+			Enabled:          func(a config.AgentsConfig) bool { return config.IsAgentEnabled(a.Claude.Enabled) },
+			SkillPrefix:      "/",
+			MCPTransports:    allMCPTransports,
+			CommandAllowlist: true,
+			MCPAutoApprove:   true,
+		},
+		{
+			Agent:            "claude_vscode",
+			MCPClient:        "claude",
+			Enabled:          func(a config.AgentsConfig) bool { return config.IsAgentEnabled(a.ClaudeVSCode.Enabled) },
+			SkillPrefix:      "/",
+			MCPTransports:    allMCPTransports,
+			CommandAllowlist: true,
+			MCPAutoApprove:   true,
+		},
+		{
+			Agent:            "codex",
+			MCPClient:        "codex",
+			Enabled:          func(a config.AgentsConfig) bool { return config.IsAgentEnabled(a.Codex.Enabled) },
+			SkillPrefix:      "$",
+			MCPTransports:    allMCPTransports,
+			CommandAllowlist: true,
+		},
+		{
+			Agent:                "copilot_cli",
+			MCPClient:            "copilot",
+			Enabled:              func(a config.AgentsConfig) bool { return config.IsAgentEnabled(a.CopilotCLI.Enabled) },
+			SkillPrefix:          "/",
+			MCPTransports:        allMCPTransports,
+			LaunchAllowsAll:      true,
+			FileGlobInstructions: true,
+		},
+		{
+			Agent:                "vscode",
+			MCPClient:            "vscode",
+			Enabled:              func(a config.AgentsConfig) bool { return config.IsAgentEnabled(a.VSCode.Enabled) },
+			SkillPrefix:          "/",
+			MCPTransports:        allMCPTransports,
+			CommandAllowlist:     true,
+			FileGlobInstructions: true,
+		},
+	}
+}
+
+// mcpClientCapabilities returns the capabilities of the first registry entry
+// that writes MCP config for client.
+func mcpClientCapabilities(client string) (clientCapabilities, bool) {
+	for _, caps := range clientCapabilityRegistry() {
+		if caps.MCPClient == client {
+			return caps, true
+		}
+	}
+	return clientCapabilities{}, false
+}
+
+// supportsMCPTransport reports whether the client can express transport.
+// Transports outside allMCPTransports are left for validation to reject.
+func (caps clientCapabilities) supportsMCPTransport(transport string) bool {
+	return !slices.Contains(allMCPTransports, transport) || slices.Contains(caps.MCPTransports, transport)
+}
+
+// clientMCPServers returns the MCP servers projected for client, dropping
+// servers whose known transport the client's config cannot express. Unknown
+// transports pass through so the writers still reject them.
+func clientMCPServers(project *config.ProjectConfig, client string) []config.MCPServer {
+	servers := projection.ClientMCPServers(project.Config.MCP, client)
+	caps, ok := mcpClientCapabilities(client)
+	if !ok {
+		return servers
+	}
+	supported := make([]config.MCPServer, 0, len(servers))
+	for _, server := range servers {
+		if caps.supportsMCPTransport(server.Transport) {
+			supported = append(supported, server)
+		}
+	}
+	return supported
+}
+
+// Degradation records a feature an enabled client cannot receive natively
+// and what sync projected in its place.
+type Degradation struct {
+	Agent      string
+	Feature    string
+	Projection string
+}
+
+func (d Degradation) String() string {
+	return i18n.Sprintf(messages.SyncDegradationFmt, d.Agent, d.Feature, d.Projection)
+}
+
+// collectDegradations compares the project's features against the capability
+// of every enabled client, in registry order. yolo mode is skipped: it bypasses approvals through
+// each client's own flags and is acknowledged separately.
+func collectDegradations(project *config.ProjectConfig) []Degradation {
+	cfg := project.Config
+	approvals := projection.BuildApprovals(cfg, project.CommandsAllow)
+	yolo := cfg.Approvals.Mode == config.ApprovalModeYOLO
+	launchAllowsAll := cfg.Approvals.Mode == config.ApprovalModeAll
+	fileGlobInstructions := len(pathInstructionFallbacks(project.ScopedInstructions)) > 0
+
+	var out []Degradation
+	for _, caps := range clientCapabilityRegistry() {
+		if !caps.Enabled(cfg.Agents) {
+			continue
+		}
+		add := func(feature, projected string) {
+			out = append(out, Degradation{Agent: caps.Agent, Feature: feature, Projection: projected})
+		}
+		if len(project.Skills) > 0 && caps.SkillPrefix != "/" {
+			add(messages.SyncFeatureSlashCommands, i18n.Sprintf(messages.SyncProjectedSkillMentionFmt, caps.SkillPrefix))
+		}
+		if fileGlobInstructions && !caps.FileGlobInstructions {
+			add(messages.SyncFeatureFileGlobInstructions, messages.SyncProjectedAppliesToSections)
+		}
+		for _, server := range projection.ClientMCPServers(cfg.MCP, caps.MCPClient) {
+			if server.Enabled == nil || !*server.Enabled || !server.AppliesToClient(caps.MCPClient) {
+				continue
+			}
+			if !caps.supportsMCPTransport(server.Transport) {
+				add(i18n.Sprintf(messages.SyncFeatureMCPTransportFmt, server.Transport, server.ID), messages.SyncProjectedServerOmitted)
+			}
+		}
+		if yolo || (launchAllowsAll && caps.LaunchAllowsAll) {
+			continue
+		}
+		if approvals.AllowCommands && len(approvals.Commands) > 0 && !caps.CommandAllowlist {
+			add(messages.SyncFeatureCommandAllowlist, messages.SyncProjectedClientApprovalDefaults)
+		}
+		if approvals.AllowMCP && !caps.MCPAutoApprove && len(projection.EnabledServerIDs(clientMCPServers(project, caps.MCPClient), caps.MCPClient)) > 0 {
+			add(messages.SyncFeatureMCPAutoApprove, messages.SyncProjectedClientApprovalDefaults)
+		}
+	}
+	return out
+}
@@ -0,0 +1,116 @@
+package sync
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/conn-castle/agent-layer/internal/config"
+	"github.com/conn-castle/agent-layer/internal/messages"
+)
+
+func capabilityTestProject(mode string) *config.ProjectConfig {
+	enabled := true
+	disabled := false
+	return &config.ProjectConfig{
+		Config: config.Config{
+			Approvals: config.ApprovalsConfig{Mode: mode},
+			Agents: config.AgentsConfig{
+				Antigravity:  config.AntigravityConfig{Enabled: &disabled},
+				Claude:       config.ClaudeConfig{Enabled: &enabled},
+				ClaudeVSCode: config.EnableOnlyConfig{Enabled: &disabled},
+				Codex:        config.CodexConfig{Enabled: &enabled},
+				VSCode:       config.EnableOnlyConfig{Enabled: &disabled},
+				CopilotCLI:   config.AgentConfig{Enabled: &enabled},
+			},
+			MCP: config.MCPConfig{Servers: []config.MCPServer{
+				{ID: "docs", Enabled: &enabled, Transport: config.TransportHTTP, URL: "https://example.com/mcp"},
+			}},
+		},
+		CommandsAllow: []string{"git status"},
+		Skills:        []config.Skill{{Name: "review"}},
+	}
+}
+
+func TestCollectDegradations(t *testing.T) {
+	got := collectDegradations(capabilityTestProject(config.ApprovalModeCommands))
+	want := []Degradation{
+		{Agent: "codex", Feature: messages.SyncFeatureSlashCommands, Projection: "$<name> skill mentions"},
+		{Agent: "copilot_cli", Feature: messages.SyncFeatureCommandAllowlist, Projection: messages.SyncProjectedClientApprovalDefaults},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("unexpected degradations:\n got %#v\nwant %#v", got, want)
+	}
+	if s := got[1].String(); s != "copilot_cli: commands.allow approvals not supported, projected as the client's default approval prompts" {
+		t.Fatalf("unexpected summary line %q", s)
+	}
+}
+
+func TestCollectDegradations_MCPAutoApprove(t *testing.T) {
+	got := collectDegradations(capabilityTestProject(config.ApprovalModeMCP))
+	want := []Degradation{
+		{Agent: "codex", Feature: messages.SyncFeatureSlashCommands, Projection: "$<name> skill mentions"},
+		{Agent: "codex", Feature: messages.SyncFeatureMCPAutoApprove, Projection: messages.SyncProjectedClientApprovalDefaults},
+		{Agent: "copilot_cli", Feature: messages.SyncFeatureMCPAutoApprove, Projection: messages.SyncProjectedClientApprovalDefaults},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("unexpected degradations:\n got %#v\nwant %#v", got, want)
+	}
+}
+
+func TestCollectDegradations_LaunchFlagsCoverApprovals(t *testing.T) {
+	for _, mode := range []string{config.ApprovalModeAll, config.ApprovalModeYOLO} {
+		project := capabilityTestProject(mode)
+		project.Skills = nil
+		for _, d := range collectDegradations(project) {
+			if d.Agent == "copilot_cli" {
+				t.Fatalf("mode %s: expected copilot launch flags to cover approvals, got %v", mode, d)
+			}
+		}
+	}
+	project := capabilityTestProject(config.ApprovalModeYOLO)
+	if got := collectDegradations(project); len(got) != 1 || got[0].Feature != messages.SyncFeatureSlashCommands {
+		t.Fatalf("expected only the skill invocation note under yolo, got %#v", got)
+	}
+}
+
+func TestClientMCPServers_SkipsUnsupportedTransport(t *testing.T) {
+	original := clientCapabilityRegistry
+	clientCapabilityRegistry = func() []clientCapabilities {
+		registry := original()
+		for i := range registry {
+			if registry[i].Agent == "codex" {
+				registry[i].MCPTransports = []string{config.TransportStdio}
+			}
+		}
+		return registry
+	}
+	t.Cleanup(func() { clientCapabilityRegistry = original })
+
+	project := capabilityTestProject(config.ApprovalModeNone)
+	enabled := true
+	project.Config.MCP.Servers = append(project.Config.MCP.Servers,
+		config.MCPServer{ID: "local", Enabled: &enabled, Transport: config.TransportStdio, Command: "tool"},
+		config.MCPServer{ID: "odd", Enabled: &enabled, Transport: "websocket"},
+	)
+	servers := clientMCPServers(project, "codex")
+	var ids []string
+	for _, server := range servers {
+		ids = append(ids, server.ID)
+	}
+	if !reflect.DeepEqual(ids, []string{"local", "odd"}) {
+		t.Fatalf("expected http server dropped and unknown transport kept, got %v", ids)
+	}
+	if got := clientMCPServers(project, "claude"); len(got) != 3 {
+		t.Fatalf("expected claude to keep every server, got %d", len(got))
+	}
+
+	var omitted []Degradation
+	for _, d := range collectDegradations(project) {
+		if d.Projection == messages.SyncProjectedServerOmitted {
+			omitted = append(omitted, d)
+		}
+	}
+	if len(omitted) != 1 || omitted[0].Agent != "codex" || omitted[0].Feature != "http transport (mcp server docs)" {
+		t.Fatalf("expected one omitted-server degradation for codex, got %#v", omitted)
+	}
+}
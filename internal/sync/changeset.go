@@ -0,0 +1,619 @@
+package sync
+
+import (
+	"bytes"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/conn-castle/agent-layer/internal/i18n"
+	"github.com/conn-castle/agent-layer/internal/messages"
+)
+
+// Change kinds recorded in a sync changeset.
+const (
+	ChangeMkdir     = "mkdir"
+	ChangeWrite     = "write"
+	ChangeRemove    = "remove"
+	ChangeRemoveAll = "remove_all"
+	ChangeSymlink   = "symlink"
+	ChangeChmod     = "chmod"
+)
+
+// Change is one filesystem change a sync run intends to make. Path is
+// absolute. Data holds file content for writes and the link target for
+// symlinks; Perm is set for writes, mkdirs, and chmods. Client names the
+// client whose sync step made the change, or is empty for shared outputs.
+type Change struct {
+	Kind   string
+	Path   string
+	Data   []byte
+	Perm   os.FileMode
+	Client string
+}
+
+// stagingSystem records writes in memory instead of performing them. Reads
+// see the staged state layered over the base System, so sync writers behave
+// exactly as they would against disk. Writes that would leave a file as it is
+// are not recorded, so an up-to-date repo yields an empty changeset.
+type stagingSystem struct {
+	System
+	changes []Change
+	// files, dirs, and links hold paths created or rewritten by staged
+	// changes; modes holds staged permission changes to base paths.
+	files map[string]stagedFile
+	dirs  map[string]os.FileMode
+	links map[string]string
+	modes map[string]os.FileMode
+	// removed hides base paths, and everything under them, that staged
+	// removals deleted.
+	removed map[string]bool
+	// unchanged records writes skipped because the path already held the
+	// data, so the run can report what it left alone.
+	unchanged []Change
+	// client tags recorded changes with the client whose step is running.
+	client string
+}
+
+type stagedFile struct {
+	data []byte
+	perm os.FileMode
+}
+
+func newStagingSystem(base System) *stagingSystem {
+	return &stagingSystem{
+		System:  base,
+		files:   make(map[string]stagedFile),
+		dirs:    make(map[string]os.FileMode),
+		links:   make(map[string]string),
+		modes:   make(map[string]os.FileMode),
+		removed: make(map[string]bool),
+	}
+}
+
+// hidden reports whether a staged removal deleted path from the base System.
+func (s *stagingSystem) hidden(path string) bool {
+	for p := path; ; p = filepath.Dir(p) {
+		if s.removed[p] {
+			return true
+		}
+		if p == filepath.Dir(p) {
+			return false
+		}
+	}
+}
+
+// lstat returns the staged or base info for path without following symlinks.
+func (s *stagingSystem) lstat(path string) (os.FileInfo, error) {
+	if file, ok := s.files[path]; ok {
+		return stagedInfo{name: filepath.Base(path), size: int64(len(file.data)), mode: file.perm}, nil
+	}
+	if perm, ok := s.dirs[path]; ok {
+		return stagedInfo{name: filepath.Base(path), mode: fs.ModeDir | perm}, nil
+	}
+	if target, ok := s.links[path]; ok {
+		return stagedInfo{name: filepath.Base(path), size: int64(len(target)), mode: fs.ModeSymlink | 0o777}, nil
+	}
+	if s.hidden(path) {
+		return nil, &fs.PathError{Op: "lstat", Path: path, Err: fs.ErrNotExist}
+	}
+	info, err := s.System.Lstat(path)
+	if perm, ok := s.modes[path]; ok && err == nil {
+		return modeInfo{FileInfo: info, mode: info.Mode()&^fs.ModePerm | perm}, nil
+	}
+	return info, err
+}
+
+// linkTarget resolves a staged symlink's target against its directory.
+func (s *stagingSystem) linkTarget(path string) (string, bool) {
+	target, ok := s.links[path]
+	if !ok {
+		return "", false
+	}
+	if !filepath.IsAbs(target) {
+		target = filepath.Join(filepath.Dir(path), target)
+	}
+	return filepath.Clean(target), true
+}
+
+// Stat returns staged info for path, following base symlinks.
+func (s *stagingSystem) Stat(name string) (os.FileInfo, error) {
+	path := filepath.Clean(name)
+	if target, ok := s.linkTarget(path); ok {
+		return s.Stat(target)
+	}
+	if _, staged := s.files[path]; staged || s.hidden(path) {
+		return s.lstat(path)
+	}
+	if _, staged := s.dirs[path]; staged {
+		return s.lstat(path)
+	}
+	if _, staged := s.modes[path]; staged {
+		return s.lstat(path)
+	}
+	return s.System.Stat(path)
+}
+
+// Lstat returns staged info for name without following symlinks.
+func (s *stagingSystem) Lstat(name string) (os.FileInfo, error) {
+	return s.lstat(filepath.Clean(name))
+}
+
+// Readlink reads base symlinks that no staged change replaced.
+func (s *stagingSystem) Readlink(name string) (string, error) {
+	path := filepath.Clean(name)
+	if target, ok := s.links[path]; ok {
+		return target, nil
+	}
+	if _, staged := s.files[path]; staged {
+		return "", &fs.PathError{Op: "readlink", Path: path, Err: syscall.EINVAL}
+	}
+	if _, staged := s.dirs[path]; staged {
+		return "", &fs.PathError{Op: "readlink", Path: path, Err: syscall.EINVAL}
+	}
+	if s.hidden(path) {
+		return "", &fs.PathError{Op: "readlink", Path: path, Err: fs.ErrNotExist}
+	}
+	return s.System.Readlink(path)
+}
+
+// ReadFile returns staged content when a staged change wrote name.
+func (s *stagingSystem) ReadFile(name string) ([]byte, error) {
+	path := filepath.Clean(name)
+	if target, ok := s.linkTarget(path); ok {
+		return s.ReadFile(target)
+	}
+	if file, ok := s.files[path]; ok {
+		return bytes.Clone(file.data), nil
+	}
+	if _, ok := s.dirs[path]; ok {
+		return nil, &fs.PathError{Op: "read", Path: path, Err: syscall.EISDIR}
+	}
+	if s.hidden(path) {
+		return nil, &fs.PathError{Op: "open", Path: path, Err: fs.ErrNotExist}
+	}
+	return s.System.ReadFile(path)
+}
+
+// ReadDir merges staged entries into the base directory listing.
+func (s *stagingSystem) ReadDir(name string) ([]os.DirEntry, error) {
+	dir := filepath.Clean(name)
+	entries := make(map[string]os.DirEntry)
+	_, stagedDir := s.dirs[dir]
+	var baseErr error
+	if !s.hidden(dir) {
+		base, err := s.System.ReadDir(dir)
+		if err != nil && !(stagedDir && errors.Is(err, fs.ErrNotExist)) {
+			baseErr = err
+		}
+		for _, entry := range base {
+			if !s.removed[filepath.Join(dir, entry.Name())] {
+				entries[entry.Name()] = entry
+			}
+		}
+	} else if !stagedDir {
+		baseErr = &fs.PathError{Op: "open", Path: dir, Err: fs.ErrNotExist}
+	}
+	for path := range s.files {
+		if filepath.Dir(path) == dir {
+			info, _ := s.lstat(path)
+			entries[filepath.Base(path)] = fs.FileInfoToDirEntry(info)
+		}
+	}
+	for path := range s.dirs {
+		if filepath.Dir(path) == dir && path != dir {
+			info, _ := s.lstat(path)
+			entries[filepath.Base(path)] = fs.FileInfoToDirEntry(info)
+		}
+	}
+	for path := range s.links {
+		if filepath.Dir(path) == dir {
+			info, _ := s.lstat(path)
+			entries[filepath.Base(path)] = fs.FileInfoToDirEntry(info)
+		}
+	}
+	if baseErr != nil && len(entries) == 0 {
+		return nil, baseErr
+	}
+	out := make([]os.DirEntry, 0, len(entries))
+	for _, entry := range entries {
+		out = append(out, entry)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name() < out[j].Name() })
+	return out, nil
+}
+
+// MkdirAll stages the directories of path that do not exist yet.
+func (s *stagingSystem) MkdirAll(name string, perm os.FileMode) error {
+	path := filepath.Clean(name)
+	var missing []string
+	for p := path; ; p = filepath.Dir(p) {
+		info, err := s.lstat(p)
+		if err == nil {
+			if !info.IsDir() {
+				if target, statErr := s.Stat(p); statErr != nil || !target.IsDir() {
+					return &fs.PathError{Op: "mkdir", Path: p, Err: syscall.ENOTDIR}
+				}
+			}
+			break
+		}
+		if !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+		missing = append(missing, p)
+		if p == filepath.Dir(p) {
+			break
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+	for _, dir := range missing {
+		s.dirs[dir] = perm.Perm()
+	}
+	s.changes = append(s.changes, Change{Kind: ChangeMkdir, Path: path, Perm: perm, Client: s.client})
+	return nil
+}
+
+// WriteFileAtomic stages a write unless path already holds data with perm.
+func (s *stagingSystem) WriteFileAtomic(filename string, data []byte, perm os.FileMode) error {
+	path := filepath.Clean(filename)
+	parent, err := s.Stat(filepath.Dir(path))
+	if err != nil {
+		return err
+	}
+	if !parent.IsDir() {
+		return &fs.PathError{Op: "write", Path: path, Err: syscall.ENOTDIR}
+	}
+	if info, err := s.lstat(path); err == nil && info.Mode().IsRegular() && info.Mode().Perm() == perm.Perm() {
+		if current, err := s.ReadFile(path); err == nil && bytes.Equal(current, data) {
+			s.unchanged = append(s.unchanged, Change{Kind: ChangeWrite, Path: path, Perm: perm, Client: s.client})
+			return nil
+		}
+	}
+	s.files[path] = stagedFile{data: bytes.Clone(data), perm: perm.Perm()}
+	delete(s.dirs, path)
+	delete(s.links, path)
+	delete(s.modes, path)
+	s.changes = append(s.changes, Change{Kind: ChangeWrite, Path: path, Data: bytes.Clone(data), Perm: perm, Client: s.client})
+	return nil
+}
+
+// Symlink stages newname as a symbolic link to oldname.
+func (s *stagingSystem) Symlink(oldname string, newname string) error {
+	path := filepath.Clean(newname)
+	if _, err := s.lstat(path); err == nil {
+		return &os.LinkError{Op: "symlink", Old: oldname, New: path, Err: fs.ErrExist}
+	}
+	parent, err := s.Stat(filepath.Dir(path))
+	if err != nil {
+		return err
+	}
+	if !parent.IsDir() {
+		return &os.LinkError{Op: "symlink", Old: oldname, New: path, Err: syscall.ENOTDIR}
+	}
+	s.links[path] = oldname
+	s.changes = append(s.changes, Change{Kind: ChangeSymlink, Path: path, Data: []byte(oldname), Client: s.client})
+	return nil
+}
+
+// Chmod stages a permission change unless name already has mode.
+func (s *stagingSystem) Chmod(name string, mode os.FileMode) error {
+	path := filepath.Clean(name)
+	if target, ok := s.linkTarget(path); ok {
+		return s.Chmod(target, mode)
+	}
+	info, err := s.lstat(path)
+	if err != nil {
+		return err
+	}
+	perm := mode.Perm()
+	if info.Mode().Perm() == perm {
+		return nil
+	}
+	switch {
+	case s.hasStagedFile(path):
+		file := s.files[path]
+		file.perm = perm
+		s.files[path] = file
+	case s.hasStagedDir(path):
+		s.dirs[path] = perm
+	default:
+		s.modes[path] = perm
+	}
+	s.changes = append(s.changes, Change{Kind: ChangeChmod, Path: path, Perm: mode, Client: s.client})
+	return nil
+}
+
+func (s *stagingSystem) hasStagedFile(path string) bool {
+	_, ok := s.files[path]
+	return ok
+}
+
+func (s *stagingSystem) hasStagedDir(path string) bool {
+	_, ok := s.dirs[path]
+	return ok
+}
+
+// Remove stages the removal of a file or empty directory.
+func (s *stagingSystem) Remove(name string) error {
+	path := filepath.Clean(name)
+	info, err := s.lstat(path)
+	if err != nil {
+		return &fs.PathError{Op: "remove", Path: path, Err: fs.ErrNotExist}
+	}
+	if info.IsDir() {
+		entries, err := s.ReadDir(path)
+		if err != nil {
+			return err
+		}
+		if len(entries) > 0 {
+			return &fs.PathError{Op: "remove", Path: path, Err: syscall.ENOTEMPTY}
+		}
+	}
+	s.forget(path)
+	s.changes = append(s.changes, Change{Kind: ChangeRemove, Path: path, Client: s.client})
+	return nil
+}
+
+// RemoveAll stages the removal of path and everything under it.
+func (s *stagingSystem) RemoveAll(name string) error {
+	path := filepath.Clean(name)
+	if _, err := s.lstat(path); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+	s.forget(path)
+	s.changes = append(s.changes, Change{Kind: ChangeRemoveAll, Path: path, Client: s.client})
+	return nil
+}
+
+// forget drops staged state at or under path and hides its base content.
+func (s *stagingSystem) forget(path string) {
+	prefix := path + string(os.PathSeparator)
+	for p := range s.files {
+		if p == path || strings.HasPrefix(p, prefix) {
+			delete(s.files, p)
+		}
+	}
+	for p := range s.dirs {
+		if p == path || strings.HasPrefix(p, prefix) {
+			delete(s.dirs, p)
+		}
+	}
+	for p := range s.links {
+		if p == path || strings.HasPrefix(p, prefix) {
+			delete(s.links, p)
+		}
+	}
+	for p := range s.modes {
+		if p == path || strings.HasPrefix(p, prefix) {
+			delete(s.modes, p)
+		}
+	}
+	s.removed[path] = true
+}
+
+type stagedInfo struct {
+	name string
+	size int64
+	mode os.FileMode
+}
+
+func (i stagedInfo) Name() string       { return i.name }
+func (i stagedInfo) Size() int64        { return i.size }
+func (i stagedInfo) Mode() os.FileMode  { return i.mode }
+func (i stagedInfo) ModTime() time.Time { return time.Time{} }
+func (i stagedInfo) IsDir() bool        { return i.mode.IsDir() }
+func (i stagedInfo) Sys() any           { return nil }
+
+// modeInfo is a base FileInfo with a staged permission change applied.
+type modeInfo struct {
+	os.FileInfo
+	mode os.FileMode
+}
+
+func (i modeInfo) Mode() os.FileMode { return i.mode }
+
+// savedNode is the on-disk state of a path before the apply phase changed it.
+type savedNode struct {
+	path     string
+	exists   bool
+	mode     os.FileMode
+	data     []byte
+	children []savedNode
+	// modeOnly marks a node whose revert only restores its permissions.
+	modeOnly bool
+}
+
+// applyChanges is the only place a sync run writes generated outputs. It
+// performs changes in order; when one fails, every change already made is
+// reverted from the state captured just before it, so the repo is left as it
+// was before sync started or the error says it could not be.
+func applyChanges(sys System, changes []Change) error {
+	saved := make([]savedNode, 0, len(changes))
+	for _, change := range changes {
+		node, err := captureChange(sys, change)
+		if err != nil {
+			return revertFailedApply(sys, saved, err)
+		}
+		saved = append(saved, node)
+		if err := applyChange(sys, change); err != nil {
+			return revertFailedApply(sys, saved, err)
+		}
+	}
+	return nil
+}
+
+func applyChange(sys System, change Change) error {
+	switch change.Kind {
+	case ChangeMkdir:
+		if err := sys.MkdirAll(change.Path, change.Perm); err != nil {
+			return i18n.Errorf(messages.SyncCreateDirFailedFmt, change.Path, err)
+		}
+	case ChangeWrite:
+		if err := sys.WriteFileAtomic(change.Path, change.Data, change.Perm); err != nil {
+			return i18n.Errorf(messages.SyncWriteFileFailedFmt, change.Path, err)
+		}
+	case ChangeRemove:
+		if err := sys.Remove(change.Path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return i18n.Errorf(messages.SyncRemoveFailedFmt, change.Path, err)
+		}
+	case ChangeRemoveAll:
+		if err := sys.RemoveAll(change.Path); err != nil {
+			return i18n.Errorf(messages.SyncRemoveFailedFmt, change.Path, err)
+		}
+	case ChangeSymlink:
+		if err := sys.Symlink(string(change.Data), change.Path); err != nil {
+			return i18n.Errorf(messages.SyncSymlinkFailedFmt, change.Path, err)
+		}
+	case ChangeChmod:
+		if err := sys.Chmod(change.Path, change.Perm); err != nil {
+			return i18n.Errorf(messages.SyncChmodFailedFmt, change.Path, err)
+		}
+	}
+	return nil
+}
+
+// captureChange records the state change must be reverted to. A chmod only
+// needs the current mode; everything else captures the path's contents.
+func captureChange(sys System, change Change) (savedNode, error) {
+	if change.Kind != ChangeChmod {
+		return captureNode(sys, changeRevertPath(sys, change))
+	}
+	info, err := sys.Stat(change.Path)
+	if err != nil {
+		return savedNode{}, i18n.Errorf(messages.SyncStatFailedFmt, change.Path, err)
+	}
+	return savedNode{path: change.Path, exists: true, mode: info.Mode(), modeOnly: true}, nil
+}
+
+// changeRevertPath returns the path whose prior state undoes change: for a
+// mkdir that is the outermost directory it creates.
+func changeRevertPath(sys System, change Change) string {
+	if change.Kind != ChangeMkdir {
+		return change.Path
+	}
+	path := change.Path
+	for parent := filepath.Dir(path); parent != path; parent = filepath.Dir(path) {
+		if _, err := sys.Lstat(parent); err == nil {
+			break
+		}
+		path = parent
+	}
+	return path
+}
+
+func revertFailedApply(sys System, saved []savedNode, applyErr error) error {
+	var revertErrs []error
+	for i := len(saved) - 1; i >= 0; i-- {
+		if err := restoreNode(sys, saved[i]); err != nil {
+			revertErrs = append(revertErrs, err)
+		}
+	}
+	if len(revertErrs) > 0 {
+		return i18n.Errorf(messages.SyncApplyRevertFailedFmt, applyErr, errors.Join(revertErrs...))
+	}
+	return i18n.Errorf(messages.SyncApplyRevertedFmt, applyErr)
+}
+
+// captureNode records path, and for directories everything under it.
+func captureNode(sys System, path string) (savedNode, error) {
+	info, err := sys.Lstat(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return savedNode{path: path}, nil
+	}
+	if err != nil {
+		return savedNode{}, i18n.Errorf(messages.SyncStatFailedFmt, path, err)
+	}
+	node := savedNode{path: path, exists: true, mode: info.Mode()}
+	switch {
+	case info.Mode()&os.ModeSymlink != 0:
+		target, err := sys.Readlink(path)
+		if err != nil {
+			return savedNode{}, i18n.Errorf(messages.SyncReadFailedFmt, path, err)
+		}
+		node.data = []byte(target)
+	case info.IsDir():
+		entries, err := sys.ReadDir(path)
+		if err != nil {
+			return savedNode{}, i18n.Errorf(messages.SyncReadFailedFmt, path, err)
+		}
+		for _, entry := range entries {
+			child, err := captureNode(sys, filepath.Join(path, entry.Name()))
+			if err != nil {
+				return savedNode{}, err
+			}
+			node.children = append(node.children, child)
+		}
+	default:
+		data, err := sys.ReadFile(path)
+		if err != nil {
+			return savedNode{}, i18n.Errorf(messages.SyncReadFailedFmt, path, err)
+		}
+		node.data = data
+	}
+	return node, nil
+}
+
+// restoreNode puts path back the way captureNode found it.
+func restoreNode(sys System, node savedNode) error {
+	if node.modeOnly {
+		if err := sys.Chmod(node.path, node.mode.Perm()); err != nil {
+			return i18n.Errorf(messages.SyncChmodFailedFmt, node.path, err)
+		}
+		return nil
+	}
+	if node.exists && node.mode&os.ModeSymlink != 0 {
+		if target, err := sys.Readlink(node.path); err == nil && target == string(node.data) {
+			return nil
+		}
+		if err := sys.RemoveAll(node.path); err != nil {
+			return i18n.Errorf(messages.SyncRemoveFailedFmt, node.path, err)
+		}
+		if err := sys.Symlink(string(node.data), node.path); err != nil {
+			return i18n.Errorf(messages.SyncSymlinkFailedFmt, node.path, err)
+		}
+		return nil
+	}
+	if node.exists && node.mode.IsRegular() {
+		if info, err := sys.Lstat(node.path); err == nil && info.Mode().IsRegular() {
+			if err := sys.WriteFileAtomic(node.path, node.data, node.mode.Perm()); err != nil {
+				return i18n.Errorf(messages.SyncWriteFileFailedFmt, node.path, err)
+			}
+			return nil
+		}
+	}
+	if err := sys.RemoveAll(node.path); err != nil {
+		return i18n.Errorf(messages.SyncRemoveFailedFmt, node.path, err)
+	}
+	if !node.exists {
+		return nil
+	}
+	if node.mode.IsDir() {
+		if err := sys.MkdirAll(node.path, node.mode.Perm()); err != nil {
+			return i18n.Errorf(messages.SyncCreateDirFailedFmt, node.path, err)
+		}
+		if err := sys.Chmod(node.path, node.mode.Perm()); err != nil {
+			return i18n.Errorf(messages.SyncChmodFailedFmt, node.path, err)
+		}
+		var errs []error
+		for _, child := range node.children {
+			if err := restoreNode(sys, child); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		return errors.Join(errs...)
+	}
+	if err := sys.WriteFileAtomic(node.path, node.data, node.mode.Perm()); err != nil {
+		return i18n.Errorf(messages.SyncWriteFileFailedFmt, node.path, err)
+	}
+	return nil
+}
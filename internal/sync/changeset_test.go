@@ -0,0 +1,251 @@
+package sync
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/conn-castle/agent-layer/internal/config"
+)
+
+type failingWriteSystem struct {
+	RealSystem
+	failPath string
+}
+
+func (s failingWriteSystem) WriteFileAtomic(filename string, data []byte, perm os.FileMode) error {
+	if filename == s.failPath {
+		return errors.New("disk full")
+	}
+	return s.RealSystem.WriteFileAtomic(filename, data, perm)
+}
+
+func TestStagingSystem_OverlaysBaseWithoutWriting(t *testing.T) {
+	root := t.TempDir()
+	keep := filepath.Join(root, "keep.txt")
+	gone := filepath.Join(root, "old", "gone.txt")
+	if err := os.MkdirAll(filepath.Dir(gone), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	for _, path := range []string{keep, gone} {
+		if err := os.WriteFile(path, []byte("base\n"), 0o644); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+	}
+
+	sys := newStagingSystem(RealSystem{})
+	if err := sys.WriteFileAtomic(keep, []byte("base\n"), 0o644); err != nil {
+		t.Fatalf("rewrite unchanged: %v", err)
+	}
+	if len(sys.changes) != 0 {
+		t.Fatalf("unchanged write must not be staged: %#v", sys.changes)
+	}
+	newFile := filepath.Join(root, "new", "file.txt")
+	if err := sys.WriteFileAtomic(newFile, []byte("x"), 0o644); err == nil {
+		t.Fatal("expected write into a missing directory to fail")
+	}
+	if err := sys.MkdirAll(filepath.Dir(newFile), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := sys.WriteFileAtomic(newFile, []byte("new\n"), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if err := sys.RemoveAll(filepath.Join(root, "old")); err != nil {
+		t.Fatalf("remove all: %v", err)
+	}
+
+	if data, err := sys.ReadFile(newFile); err != nil || string(data) != "new\n" {
+		t.Fatalf("staged read = %q, %v", data, err)
+	}
+	if _, err := sys.Stat(gone); !os.IsNotExist(err) {
+		t.Fatalf("removed file must be hidden, got %v", err)
+	}
+	entries, err := sys.ReadDir(root)
+	if err != nil {
+		t.Fatalf("read dir: %v", err)
+	}
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	if strings.Join(names, ",") != "keep.txt,new" {
+		t.Fatalf("unexpected listing: %v", names)
+	}
+	if err := sys.Remove(filepath.Join(root, "new")); err == nil {
+		t.Fatal("expected removing a non-empty staged dir to fail")
+	}
+
+	if _, err := os.Stat(newFile); !os.IsNotExist(err) {
+		t.Fatalf("staging must not write, stat err %v", err)
+	}
+	if _, err := os.Stat(gone); err != nil {
+		t.Fatalf("staging must not remove: %v", err)
+	}
+	var kinds []string
+	for _, change := range sys.changes {
+		kinds = append(kinds, change.Kind)
+	}
+	if strings.Join(kinds, ",") != "mkdir,write,remove_all" {
+		t.Fatalf("unexpected changes: %v", kinds)
+	}
+}
+
+func TestApplyChanges_RevertsOnFailure(t *testing.T) {
+	root := t.TempDir()
+	existing := filepath.Join(root, "existing.txt")
+	removed := filepath.Join(root, "skills", "a", "SKILL.md")
+	if err := os.MkdirAll(filepath.Dir(removed), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(existing, []byte("before\n"), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if err := os.WriteFile(removed, []byte("skill\n"), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	failing := filepath.Join(root, "fresh", "fail.txt")
+	changes := []Change{
+		{Kind: ChangeWrite, Path: existing, Data: []byte("after\n"), Perm: 0o644},
+		{Kind: ChangeRemoveAll, Path: filepath.Join(root, "skills")},
+		{Kind: ChangeMkdir, Path: filepath.Dir(failing), Perm: 0o755},
+		{Kind: ChangeWrite, Path: failing, Data: []byte("x"), Perm: 0o644},
+	}
+
+	err := applyChanges(failingWriteSystem{failPath: failing}, changes)
+	if err == nil || !strings.Contains(err.Error(), "disk full") || !strings.Contains(err.Error(), "were reverted") {
+		t.Fatalf("expected reverted apply error, got %v", err)
+	}
+	if data, err := os.ReadFile(existing); err != nil || string(data) != "before\n" {
+		t.Fatalf("existing file not restored: %q, %v", data, err)
+	}
+	if info, err := os.Stat(existing); err != nil || info.Mode().Perm() != 0o600 {
+		t.Fatalf("existing file mode not restored: %v, %v", info, err)
+	}
+	if data, err := os.ReadFile(removed); err != nil || string(data) != "skill\n" {
+		t.Fatalf("removed tree not restored: %q, %v", data, err)
+	}
+	if _, err := os.Stat(filepath.Dir(failing)); !os.IsNotExist(err) {
+		t.Fatalf("created dir must be removed, stat err %v", err)
+	}
+
+	if err := applyChanges(RealSystem{}, changes); err != nil {
+		t.Fatalf("apply: %v", err)
+	}
+	if data, _ := os.ReadFile(failing); string(data) != "x" {
+		t.Fatalf("expected changes applied, got %q", data)
+	}
+}
+
+func TestRunWithProjectOptions_DryRunWritesNothing(t *testing.T) {
+	root := t.TempDir()
+	if err := copyFixtureRepo(filepath.Join("testdata", "fixture-repo"), root); err != nil {
+		t.Fatalf("copy fixture: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(root, ".agent-layer", ".env"), []byte("AL_EXAMPLE_TOKEN=token123\n"), 0o600); err != nil {
+		t.Fatalf("write env: %v", err)
+	}
+	writeTemplateToFixtureSource(t, root, "claude-statusline.sh", filepath.Join(".agent-layer", "claude-statusline.sh"), 0o755)
+	writeTemplateToFixtureSource(t, root, "codex-statusline.toml", filepath.Join(".agent-layer", "codex-statusline.toml"), 0o644)
+	project, err := config.LoadProjectConfig(root)
+	if err != nil {
+		t.Fatalf("load project: %v", err)
+	}
+
+	result, err := RunWithProjectOptions(RealSystem{}, root, project, RunOptions{DryRun: true})
+	if err != nil {
+		t.Fatalf("dry run: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(root, "AGENTS.md")); !os.IsNotExist(err) {
+		t.Fatalf("dry run must not write AGENTS.md, stat err %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(root, ".agent-layer", projectSyncLockFile)); !os.IsNotExist(err) {
+		t.Fatalf("dry run must not create the sync lock, stat err %v", err)
+	}
+	planned := make(map[string]bool)
+	for _, change := range result.Changes {
+		if change.Kind == ChangeWrite {
+			planned[change.Path] = true
+		}
+	}
+	if !planned[filepath.Join(root, "AGENTS.md")] {
+		t.Fatalf("expected AGENTS.md write in changes: %#v", result.Changes)
+	}
+
+	applied, err := RunWithProjectOptions(RealSystem{}, root, project, RunOptions{})
+	if err != nil {
+		t.Fatalf("sync: %v", err)
+	}
+	if len(applied.Changes) != len(result.Changes) {
+		t.Fatalf("dry run planned %d changes, sync made %d", len(result.Changes), len(applied.Changes))
+	}
+	// The Codex config merge normalizes its own output on the next run.
+	if _, err := RunWithProjectOptions(RealSystem{}, root, project, RunOptions{}); err != nil {
+		t.Fatalf("second sync: %v", err)
+	}
+	again, err := RunWithProjectOptions(RealSystem{}, root, project, RunOptions{DryRun: true})
+	if err != nil {
+		t.Fatalf("second dry run: %v", err)
+	}
+	if len(again.Changes) != 0 {
+		t.Fatalf("expected no changes after sync, got %#v", again.Changes)
+	}
+}
+
+func TestApplyChanges_SymlinkAndChmod(t *testing.T) {
+	root := t.TempDir()
+	script := filepath.Join(root, "script.sh")
+	link := filepath.Join(root, "current")
+	if err := os.WriteFile(script, []byte("#!/bin/sh\n"), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if err := os.Symlink("old", link); err != nil {
+		t.Fatalf("symlink: %v", err)
+	}
+
+	sys := newStagingSystem(RealSystem{})
+	if err := sys.Chmod(script, 0o755); err != nil {
+		t.Fatalf("chmod: %v", err)
+	}
+	if info, err := sys.Stat(script); err != nil || info.Mode().Perm() != 0o755 {
+		t.Fatalf("staged mode = %v, %v", info, err)
+	}
+	if err := sys.Symlink("script.sh", link); err == nil {
+		t.Fatal("expected symlink over an existing path to fail")
+	}
+	if err := sys.RemoveAll(link); err != nil {
+		t.Fatalf("remove link: %v", err)
+	}
+	if err := sys.Symlink("script.sh", link); err != nil {
+		t.Fatalf("symlink: %v", err)
+	}
+	if data, err := sys.ReadFile(link); err != nil || string(data) != "#!/bin/sh\n" {
+		t.Fatalf("read through staged link = %q, %v", data, err)
+	}
+	if target, _ := os.Readlink(link); target != "old" {
+		t.Fatalf("staging must not replace the link, got %q", target)
+	}
+
+	failing := filepath.Join(root, "fail.txt")
+	changes := append(sys.changes, Change{Kind: ChangeWrite, Path: failing, Data: []byte("x"), Perm: 0o644})
+	if err := applyChanges(failingWriteSystem{failPath: failing}, changes); err == nil {
+		t.Fatal("expected apply to fail")
+	}
+	if target, err := os.Readlink(link); err != nil || target != "old" {
+		t.Fatalf("replaced symlink not restored: %q, %v", target, err)
+	}
+	if info, err := os.Stat(script); err != nil || info.Mode().Perm() != 0o644 {
+		t.Fatalf("mode not restored: %v, %v", info, err)
+	}
+
+	if err := applyChanges(RealSystem{}, sys.changes); err != nil {
+		t.Fatalf("apply: %v", err)
+	}
+	if target, err := os.Readlink(link); err != nil || target != "script.sh" {
+		t.Fatalf("symlink not applied: %q, %v", target, err)
+	}
+	if info, err := os.Stat(script); err != nil || info.Mode().Perm() != 0o755 {
+		t.Fatalf("mode not applied: %v, %v", info, err)
+	}
+}
@@ -1,11 +1,11 @@
 package sync
 
 import (
-	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
 
+	"github.com/conn-castle/agent-layer/internal/i18n"
 	"github.com/conn-castle/agent-layer/internal/messages"
 )
 
@@ -60,7 +60,7 @@ func ensureNoLegacyAgentSpecificChime(path string, hooks any, command string) er
 		return nil
 	}
 	if containsExactChimeCommand(hooks, managedChimeCommandVariants(command)) {
-		return fmt.Errorf(messages.SyncLegacyAgentSpecificChimeFmt, path)
+		return i18n.Errorf(messages.SyncLegacyAgentSpecificChimeFmt, path)
 	}
 	return nil
 }
@@ -153,20 +153,20 @@ func existingChimeCleanupTarget(sys System, root string, dirName string, fileNam
 		if os.IsNotExist(err) {
 			return path, 0, false, nil
 		}
-		return path, 0, false, fmt.Errorf(messages.InstallFailedStatFmt, dir, err)
+		return path, 0, false, i18n.Errorf(messages.InstallFailedStatFmt, dir, err)
 	}
 	if dirInfo.Mode()&os.ModeSymlink != 0 || !dirInfo.IsDir() {
-		return path, 0, false, fmt.Errorf(messages.SyncChimePathConflictFmt, dir)
+		return path, 0, false, i18n.Errorf(messages.SyncChimePathConflictFmt, dir)
 	}
 	fileInfo, err := sys.Lstat(path)
 	if err != nil {
 		if os.IsNotExist(err) {
 			return path, 0, false, nil
 		}
-		return path, 0, false, fmt.Errorf(messages.InstallFailedStatFmt, path, err)
+		return path, 0, false, i18n.Errorf(messages.InstallFailedStatFmt, path, err)
 	}
 	if fileInfo.Mode()&os.ModeSymlink != 0 || !fileInfo.Mode().IsRegular() {
-		return path, 0, false, fmt.Errorf(messages.SyncChimePathConflictFmt, path)
+		return path, 0, false, i18n.Errorf(messages.SyncChimePathConflictFmt, path)
 	}
 	return path, fileInfo.Mode().Perm(), true, nil
 }
@@ -1,16 +1,18 @@
 package sync
 
 import (
-	"fmt"
 	"path/filepath"
 	"strings"
 
 	"github.com/conn-castle/agent-layer/internal/config"
+	"github.com/conn-castle/agent-layer/internal/i18n"
 	"github.com/conn-castle/agent-layer/internal/messages"
 	"github.com/conn-castle/agent-layer/internal/projection"
 )
 
-// writeClaudeSettings generates .claude/settings.json.
+// writeClaudeSettings merges the managed settings into .claude/settings.json.
+// Keys the user added to the file are preserved; the keys written by the
+// previous sync, recorded under .agent-layer/state/, are replaced or dropped.
 func writeClaudeSettings(sys System, root string, project *config.ProjectConfig) error {
 	settings, err := buildClaudeSettings(root, project)
 	if err != nil {
@@ -19,21 +21,35 @@ func writeClaudeSettings(sys System, root string, project *config.ProjectConfig)
 
 	claudeDir := filepath.Join(root, ".claude")
 	if err := sys.MkdirAll(claudeDir, 0o755); err != nil {
-		return fmt.Errorf(messages.SyncCreateDirFailedFmt, claudeDir, err)
+		return i18n.Errorf(messages.SyncCreateDirFailedFmt, claudeDir, err)
 	}
 
-	data, err := sys.MarshalIndent(settings, "", "  ")
+	path := filepath.Join(claudeDir, "settings.json")
+	existing, err := readNativeSettings(sys, path, "Claude")
+	if err != nil {
+		return err
+	}
+	keysPath := claudeManagedKeysPath(root)
+	previous, err := readClaudeManagedKeys(sys, keysPath)
 	if err != nil {
-		return fmt.Errorf(messages.SyncMarshalClaudeSettingsFailedFmt, err)
+		return err
+	}
+	merged, err := mergeClaudeSettings(existing, settings, previous)
+	if err != nil {
+		return i18n.Errorf(messages.SyncMergeClaudeSettingsFailedFmt, path, err)
+	}
+
+	data, err := sys.MarshalIndent(merged, "", "  ")
+	if err != nil {
+		return i18n.Errorf(messages.SyncMarshalClaudeSettingsFailedFmt, err)
 	}
 	data = append(data, '\n')
 
-	path := filepath.Join(claudeDir, "settings.json")
-	if err := sys.WriteFileAtomic(path, data, 0o644); err != nil {
-		return fmt.Errorf(messages.SyncWriteFileFailedFmt, path, err)
+	if err := sys.WriteFileAtomic(path, data, nativeSettingsFileMode(sys, path, 0o644)); err != nil {
+		return i18n.Errorf(messages.SyncWriteFileFailedFmt, path, err)
 	}
 
-	return nil
+	return writeClaudeManagedKeys(sys, keysPath, managedSettingsLeaves(settings))
 }
 
 func buildClaudeSettings(root string, project *config.ProjectConfig) (map[string]any, error) {
@@ -41,7 +57,7 @@ func buildClaudeSettings(root string, project *config.ProjectConfig) (map[string
 	permissions := buildPermissionsBlock(
 		project.Config,
 		project.CommandsAllow,
-		projection.EnabledServerIDs(project.Config.MCP.Servers, "claude"),
+		projection.EnabledServerIDs(clientMCPServers(project, "claude"), "claude"),
 		claudeRenderer{},
 	)
 	if permissions != nil {
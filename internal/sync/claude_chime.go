@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 
+	"github.com/conn-castle/agent-layer/internal/i18n"
 	"github.com/conn-castle/agent-layer/internal/messages"
 )
 
@@ -17,13 +18,13 @@ func injectClaudeChimeHook(settings map[string]any) error {
 		var ok bool
 		hooks, ok = existingHooks.(map[string]any)
 		if !ok {
-			return fmt.Errorf(messages.SyncChimeKeyTableConflictFmt, "agents.claude.agent_specific.hooks")
+			return i18n.Errorf(messages.SyncChimeKeyTableConflictFmt, "agents.claude.agent_specific.hooks")
 		}
 	}
 	switch hooks[stopHookKey].(type) {
 	case nil, []any:
 	default:
-		return fmt.Errorf(messages.SyncChimeListConflictFmt, "agents.claude.agent_specific.hooks.Stop")
+		return i18n.Errorf(messages.SyncChimeListConflictFmt, "agents.claude.agent_specific.hooks.Stop")
 	}
 	hooks[stopHookKey] = appendClaudeChimeStopHook(hooks[stopHookKey])
 	return nil
@@ -75,7 +76,7 @@ func cleanClaudeChimeHook(sys System, root string) error {
 	}
 	data, err := sys.ReadFile(path)
 	if err != nil {
-		return fmt.Errorf(messages.SyncReadFailedFmt, path, err)
+		return i18n.Errorf(messages.SyncReadFailedFmt, path, err)
 	}
 	if !containsChimeCommandText(string(data), agentLayerClaudeChimeCommand) {
 		return nil
@@ -93,11 +94,11 @@ func cleanClaudeChimeHook(sys System, root string) error {
 	}
 	out, err := sys.MarshalIndent(settings, "", "  ")
 	if err != nil {
-		return fmt.Errorf(messages.SyncMarshalClaudeSettingsFailedFmt, err)
+		return i18n.Errorf(messages.SyncMarshalClaudeSettingsFailedFmt, err)
 	}
 	out = append(out, '\n')
 	if err := sys.WriteFileAtomic(path, out, mode); err != nil {
-		return fmt.Errorf(messages.SyncWriteFileFailedFmt, path, err)
+		return i18n.Errorf(messages.SyncWriteFileFailedFmt, path, err)
 	}
 	return nil
 }
@@ -109,7 +110,7 @@ func removeClaudeChimeHook(settings map[string]any) (bool, error) {
 	}
 	hooks, ok := hooksValue.(map[string]any)
 	if !ok {
-		return false, fmt.Errorf(messages.SyncChimeKeyTableConflictFmt, ".claude/settings.json hooks")
+		return false, i18n.Errorf(messages.SyncChimeKeyTableConflictFmt, ".claude/settings.json hooks")
 	}
 	stopValue, ok := hooks[stopHookKey]
 	if !ok {
@@ -117,7 +118,7 @@ func removeClaudeChimeHook(settings map[string]any) (bool, error) {
 	}
 	stopEntries, ok := stopValue.([]any)
 	if !ok {
-		return false, fmt.Errorf(messages.SyncChimeListConflictFmt, ".claude/settings.json hooks.Stop")
+		return false, i18n.Errorf(messages.SyncChimeListConflictFmt, ".claude/settings.json hooks.Stop")
 	}
 
 	commands := managedChimeCommandVariants(agentLayerClaudeChimeCommand)
@@ -136,7 +137,7 @@ func removeClaudeChimeHook(settings map[string]any) (bool, error) {
 		}
 		handlers, ok := handlersValue.([]any)
 		if !ok {
-			return false, fmt.Errorf(messages.SyncChimeListConflictFmt, ".claude/settings.json hooks.Stop.hooks")
+			return false, i18n.Errorf(messages.SyncChimeListConflictFmt, ".claude/settings.json hooks.Stop.hooks")
 		}
 		filteredHandlers := make([]any, 0, len(handlers))
 		for _, handler := range handlers {
@@ -1,9 +1,8 @@
 package sync
 
 import (
-	"fmt"
-
 	"github.com/conn-castle/agent-layer/internal/config"
+	"github.com/conn-castle/agent-layer/internal/i18n"
 	"github.com/conn-castle/agent-layer/internal/messages"
 )
 
@@ -66,7 +65,7 @@ func questionToolTable(settings map[string]any, key string) (map[string]any, err
 	}
 	table, ok := existing.(map[string]any)
 	if !ok {
-		return nil, fmt.Errorf(messages.SyncClaudeQuestionToolKeyTableConflictFmt, key)
+		return nil, i18n.Errorf(messages.SyncClaudeQuestionToolKeyTableConflictFmt, key)
 	}
 	return table, nil
 }
@@ -82,7 +81,7 @@ func requireQuestionToolList(existing any, name string) error {
 	case nil, []any, []string:
 		return nil
 	default:
-		return fmt.Errorf(messages.SyncClaudeQuestionToolListConflictFmt, name)
+		return i18n.Errorf(messages.SyncClaudeQuestionToolListConflictFmt, name)
 	}
 }
 
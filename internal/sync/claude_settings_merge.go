@@ -0,0 +1,132 @@
+package sync
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/conn-castle/agent-layer/internal/config"
+	"github.com/conn-castle/agent-layer/internal/i18n"
+	"github.com/conn-castle/agent-layer/internal/messages"
+)
+
+// claudeManagedKeysFile records which .claude/settings.json paths the last
+// sync wrote. It lives under .agent-layer/state/ rather than in settings.json
+// itself so Claude Code never sees keys it does not understand.
+const claudeManagedKeysFile = "claude-settings-managed.json"
+
+// claudeManagedKeys is the on-disk shape of claudeManagedKeysFile. Each path
+// is a list of object keys from the settings root to a managed leaf.
+type claudeManagedKeys struct {
+	Paths [][]string `json:"paths"`
+}
+
+func claudeManagedKeysPath(root string) string {
+	return filepath.Join(config.StateDir(root), claudeManagedKeysFile)
+}
+
+// readClaudeManagedKeys returns the leaf paths recorded by the previous sync.
+// A missing file yields none, which is the state before the first merge.
+func readClaudeManagedKeys(sys System, path string) ([][]string, error) {
+	data, err := sys.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, i18n.Errorf(messages.SyncReadClaudeManagedKeysFailedFmt, path, err)
+	}
+	if len(bytes.TrimSpace(data)) == 0 {
+		return nil, nil
+	}
+	var keys claudeManagedKeys
+	if err := json.Unmarshal(data, &keys); err != nil {
+		return nil, i18n.Errorf(messages.SyncReadClaudeManagedKeysFailedFmt, path, err)
+	}
+	return keys.Paths, nil
+}
+
+// writeClaudeManagedKeys records the leaf paths this sync wrote.
+func writeClaudeManagedKeys(sys System, path string, paths [][]string) error {
+	data, err := sys.MarshalIndent(claudeManagedKeys{Paths: paths}, "", "  ")
+	if err != nil {
+		return i18n.Errorf(messages.SyncMarshalClaudeManagedKeysFailedFmt, err)
+	}
+	data = append(data, '\n')
+	if err := sys.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return i18n.Errorf(messages.SyncCreateDirFailedFmt, filepath.Dir(path), err)
+	}
+	if err := sys.WriteFileAtomic(path, data, 0o644); err != nil {
+		return i18n.Errorf(messages.SyncWriteFileFailedFmt, path, err)
+	}
+	return nil
+}
+
+// mergeClaudeSettings returns existing with the previously managed paths
+// removed and desired overlaid. Keys the user added (hooks, statusLine, env,
+// and so on) survive; managed keys that are no longer desired are dropped.
+func mergeClaudeSettings(existing, desired map[string]any, previous [][]string) (map[string]any, error) {
+	merged, ok := cloneAgentSpecificValue(existing).(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("existing Claude settings must be a JSON object")
+	}
+	for _, path := range previous {
+		removeManagedSettingsPath(merged, path)
+	}
+	if err := overlayManagedSettings(merged, desired, nil); err != nil {
+		return nil, err
+	}
+	return merged, nil
+}
+
+// removeManagedSettingsPath deletes the value at path and prunes the parent
+// objects that the deletion left empty. Paths that no longer exist, or that
+// now run through a non-object value, are ignored.
+func removeManagedSettingsPath(target map[string]any, path []string) {
+	if len(path) == 0 {
+		return
+	}
+	key := path[0]
+	if len(path) == 1 {
+		delete(target, key)
+		return
+	}
+	child, ok := target[key].(map[string]any)
+	if !ok {
+		return
+	}
+	removeManagedSettingsPath(child, path[1:])
+	if len(child) == 0 {
+		delete(target, key)
+	}
+}
+
+// managedSettingsLeaves lists the paths to every non-object value and every
+// empty object in desired, sorted so the recorded state is stable.
+func managedSettingsLeaves(desired map[string]any) [][]string {
+	var leaves [][]string
+	var walk func(value map[string]any, prefix []string)
+	walk = func(value map[string]any, prefix []string) {
+		for key, child := range value {
+			path := append(append([]string{}, prefix...), key)
+			if childMap, ok := child.(map[string]any); ok && len(childMap) > 0 {
+				walk(childMap, path)
+				continue
+			}
+			leaves = append(leaves, path)
+		}
+	}
+	walk(desired, nil)
+	sort.Slice(leaves, func(i, j int) bool {
+		a, b := leaves[i], leaves[j]
+		for k := 0; k < len(a) && k < len(b); k++ {
+			if a[k] != b[k] {
+				return a[k] < b[k]
+			}
+		}
+		return len(a) < len(b)
+	})
+	return leaves
+}
@@ -0,0 +1,202 @@
+package sync
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/conn-castle/agent-layer/internal/config"
+)
+
+func claudeMergeProject(allow ...string) *config.ProjectConfig {
+	return &config.ProjectConfig{
+		Config: config.Config{
+			Approvals: config.ApprovalsConfig{Mode: config.ApprovalModeCommands},
+		},
+		CommandsAllow: allow,
+	}
+}
+
+func readClaudeSettingsFile(t *testing.T, root string) map[string]any {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(root, ".claude", "settings.json")) // #nosec G304 -- test-controlled path.
+	if err != nil {
+		t.Fatalf("read settings.json: %v", err)
+	}
+	var settings map[string]any
+	if err := json.Unmarshal(data, &settings); err != nil {
+		t.Fatalf("decode settings.json: %v", err)
+	}
+	return settings
+}
+
+func TestWriteClaudeSettings_PreservesUserKeys(t *testing.T) {
+	t.Parallel()
+	root := t.TempDir()
+	claudeDir := filepath.Join(root, ".claude")
+	if err := os.MkdirAll(claudeDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	existing := `{
+  "env": {"FOO": "bar"},
+  "hooks": {"PreToolUse": [{"matcher": "Bash"}]},
+  "statusLine": {"type": "command", "command": "echo hi"},
+  "permissions": {"additionalDirectories": ["../shared"], "allow": ["Bash(rm:*)"]}
+}`
+	if err := os.WriteFile(filepath.Join(claudeDir, "settings.json"), []byte(existing), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := writeClaudeSettings(RealSystem{}, root, claudeMergeProject("git status")); err != nil {
+		t.Fatalf("writeClaudeSettings: %v", err)
+	}
+
+	settings := readClaudeSettingsFile(t, root)
+	if settings["env"].(map[string]any)["FOO"] != "bar" {
+		t.Fatalf("expected env preserved, got %#v", settings["env"])
+	}
+	if _, ok := settings["hooks"].(map[string]any)["PreToolUse"]; !ok {
+		t.Fatalf("expected hooks preserved, got %#v", settings["hooks"])
+	}
+	if _, ok := settings["statusLine"]; !ok {
+		t.Fatalf("expected statusLine preserved, got %#v", settings)
+	}
+	permissions := settings["permissions"].(map[string]any)
+	if !reflect.DeepEqual(permissions["additionalDirectories"], []any{"../shared"}) {
+		t.Fatalf("expected user permissions key preserved, got %#v", permissions)
+	}
+	if !reflect.DeepEqual(permissions["allow"], []any{"Bash(git status:*)"}) {
+		t.Fatalf("expected managed allow list to replace the existing one, got %#v", permissions["allow"])
+	}
+	info, err := os.Stat(filepath.Join(claudeDir, "settings.json"))
+	if err != nil || info.Mode().Perm() != 0o600 {
+		t.Fatalf("expected existing mode preserved, got %v %v", info, err)
+	}
+}
+
+func TestWriteClaudeSettings_DropsStaleManagedKeys(t *testing.T) {
+	t.Parallel()
+	root := t.TempDir()
+	if err := writeClaudeSettings(RealSystem{}, root, claudeMergeProject("git status")); err != nil {
+		t.Fatalf("first sync: %v", err)
+	}
+	path := filepath.Join(root, ".claude", "settings.json")
+	settings := readClaudeSettingsFile(t, root)
+	settings["env"] = map[string]any{"FOO": "bar"}
+	data, err := json.Marshal(settings)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	none := claudeMergeProject()
+	none.Config.Approvals.Mode = config.ApprovalModeNone
+	if err := writeClaudeSettings(RealSystem{}, root, none); err != nil {
+		t.Fatalf("second sync: %v", err)
+	}
+	settings = readClaudeSettingsFile(t, root)
+	if _, ok := settings["permissions"]; ok {
+		t.Fatalf("expected stale managed permissions removed, got %#v", settings)
+	}
+	if settings["env"].(map[string]any)["FOO"] != "bar" {
+		t.Fatalf("expected user env preserved, got %#v", settings)
+	}
+}
+
+func TestWriteClaudeSettings_Idempotent(t *testing.T) {
+	t.Parallel()
+	root := t.TempDir()
+	project := claudeMergeProject("git status", "go test")
+	if err := writeClaudeSettings(RealSystem{}, root, project); err != nil {
+		t.Fatalf("first sync: %v", err)
+	}
+	first, err := os.ReadFile(filepath.Join(root, ".claude", "settings.json")) // #nosec G304 -- test-controlled path.
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := writeClaudeSettings(RealSystem{}, root, project); err != nil {
+		t.Fatalf("second sync: %v", err)
+	}
+	second, err := os.ReadFile(filepath.Join(root, ".claude", "settings.json")) // #nosec G304 -- test-controlled path.
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(first) != string(second) {
+		t.Fatalf("expected identical output, got:\n%s\n---\n%s", first, second)
+	}
+	keys, err := readClaudeManagedKeys(RealSystem{}, claudeManagedKeysPath(root))
+	if err != nil {
+		t.Fatalf("read managed keys: %v", err)
+	}
+	if !reflect.DeepEqual(keys, [][]string{{"permissions", "allow"}}) {
+		t.Fatalf("unexpected managed keys %v", keys)
+	}
+}
+
+func TestWriteClaudeSettings_InvalidExisting(t *testing.T) {
+	t.Parallel()
+	root := t.TempDir()
+	claudeDir := filepath.Join(root, ".claude")
+	if err := os.MkdirAll(claudeDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(claudeDir, "settings.json"), []byte("{broken"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	err := writeClaudeSettings(RealSystem{}, root, claudeMergeProject("git status"))
+	if err == nil || !strings.Contains(err.Error(), "decode Claude settings") {
+		t.Fatalf("expected decode error, got %v", err)
+	}
+}
+
+func TestWriteClaudeSettings_ShapeConflict(t *testing.T) {
+	t.Parallel()
+	root := t.TempDir()
+	claudeDir := filepath.Join(root, ".claude")
+	if err := os.MkdirAll(claudeDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(claudeDir, "settings.json"), []byte(`{"permissions": "open"}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	err := writeClaudeSettings(RealSystem{}, root, claudeMergeProject("git status"))
+	if err == nil || !strings.Contains(err.Error(), "requires an object") {
+		t.Fatalf("expected shape conflict, got %v", err)
+	}
+}
+
+func TestReadClaudeManagedKeys_Invalid(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), claudeManagedKeysFile)
+	if err := os.WriteFile(path, []byte("[not an object"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := readClaudeManagedKeys(RealSystem{}, path); err == nil {
+		t.Fatal("expected parse error")
+	}
+}
+
+func TestRemoveManagedSettingsPath(t *testing.T) {
+	t.Parallel()
+	settings := map[string]any{
+		"permissions": map[string]any{"allow": []any{"x"}},
+		"hooks":       map[string]any{"Stop": []any{}, "PreToolUse": []any{}},
+		"model":       "opus",
+	}
+	removeManagedSettingsPath(settings, []string{"permissions", "allow"})
+	removeManagedSettingsPath(settings, []string{"hooks", "Stop"})
+	removeManagedSettingsPath(settings, []string{"model", "nested"})
+	removeManagedSettingsPath(settings, []string{"missing", "key"})
+	want := map[string]any{
+		"hooks": map[string]any{"PreToolUse": []any{}},
+		"model": "opus",
+	}
+	if !reflect.DeepEqual(settings, want) {
+		t.Fatalf("unexpected settings %#v", settings)
+	}
+}
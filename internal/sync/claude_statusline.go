@@ -1,11 +1,12 @@
 package sync
 
 import (
-	"fmt"
 	"os"
 	"path/filepath"
 
 	"github.com/conn-castle/agent-layer/internal/config"
+	"github.com/conn-castle/agent-layer/internal/i18n"
+	"github.com/conn-castle/agent-layer/internal/layerdir"
 	"github.com/conn-castle/agent-layer/internal/messages"
 )
 
@@ -30,11 +31,11 @@ func legacyClaudeStatuslinePath(root string) string {
 // claudeStatuslineSourcePath returns the absolute path to the editable
 // source-of-truth status line under .agent-layer.
 func claudeStatuslineSourcePath(root string) string {
-	return filepath.Join(root, ".agent-layer", claudeStatuslineSourceName)
+	return filepath.Join(layerdir.Dir(root), claudeStatuslineSourceName)
 }
 
 func legacyClaudeStatuslineSourcePath(root string) string {
-	return filepath.Join(root, ".agent-layer", legacyClaudeStatuslineSourceName)
+	return filepath.Join(layerdir.Dir(root), legacyClaudeStatuslineSourceName)
 }
 
 // writeClaudeStatusline projects the editable .agent-layer/claude-statusline.sh
@@ -46,7 +47,7 @@ func writeClaudeStatusline(sys System, root string, project *config.ProjectConfi
 	if !config.ClaudeStatuslineEnabled(project.Config.Agents.Claude) {
 		for _, stalePath := range []string{dest, legacyClaudeStatuslinePath(root)} {
 			if err := sys.Remove(stalePath); err != nil && !os.IsNotExist(err) {
-				return fmt.Errorf(messages.SyncRemoveFailedFmt, stalePath, err)
+				return i18n.Errorf(messages.SyncRemoveFailedFmt, stalePath, err)
 			}
 		}
 		return nil
@@ -60,14 +61,14 @@ func writeClaudeStatusline(sys System, root string, project *config.ProjectConfi
 	// version so the rename does not leave two scripts behind when enabled.
 	if legacy := legacyClaudeStatuslinePath(root); legacy != dest {
 		if err := sys.Remove(legacy); err != nil && !os.IsNotExist(err) {
-			return fmt.Errorf(messages.SyncRemoveFailedFmt, legacy, err)
+			return i18n.Errorf(messages.SyncRemoveFailedFmt, legacy, err)
 		}
 	}
 	if err := sys.MkdirAll(filepath.Dir(dest), 0o755); err != nil {
-		return fmt.Errorf(messages.SyncCreateDirFailedFmt, filepath.Dir(dest), err)
+		return i18n.Errorf(messages.SyncCreateDirFailedFmt, filepath.Dir(dest), err)
 	}
 	if err := sys.WriteFileAtomic(dest, data, 0o755); err != nil {
-		return fmt.Errorf(messages.SyncWriteFileFailedFmt, dest, err)
+		return i18n.Errorf(messages.SyncWriteFileFailedFmt, dest, err)
 	}
 	return nil
 }
@@ -84,22 +85,22 @@ func ensureStatuslineSource(sys System, root string) ([]byte, error) {
 		return data, nil
 	}
 	if !os.IsNotExist(err) {
-		return nil, fmt.Errorf(messages.SyncReadFailedFmt, src, err)
+		return nil, i18n.Errorf(messages.SyncReadFailedFmt, src, err)
 	}
 
 	legacySrc := legacyClaudeStatuslineSourcePath(root)
 	legacyData, legacyErr := sys.ReadFile(legacySrc)
 	if legacyErr == nil {
 		if err := sys.MkdirAll(filepath.Dir(src), 0o755); err != nil {
-			return nil, fmt.Errorf(messages.SyncCreateDirFailedFmt, filepath.Dir(src), err)
+			return nil, i18n.Errorf(messages.SyncCreateDirFailedFmt, filepath.Dir(src), err)
 		}
 		if err := sys.WriteFileAtomic(src, legacyData, 0o755); err != nil {
-			return nil, fmt.Errorf(messages.SyncWriteFileFailedFmt, src, err)
+			return nil, i18n.Errorf(messages.SyncWriteFileFailedFmt, src, err)
 		}
 		return legacyData, nil
 	}
 	if !os.IsNotExist(legacyErr) {
-		return nil, fmt.Errorf(messages.SyncReadFailedFmt, legacySrc, legacyErr)
+		return nil, i18n.Errorf(messages.SyncReadFailedFmt, legacySrc, legacyErr)
 	}
-	return nil, fmt.Errorf(messages.SyncClaudeStatuslineSourceMissingFmt, src)
+	return nil, i18n.Errorf(messages.SyncClaudeStatuslineSourceMissingFmt, src)
 }
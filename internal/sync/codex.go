@@ -10,6 +10,7 @@ import (
 	"unicode/utf8"
 
 	"github.com/conn-castle/agent-layer/internal/config"
+	"github.com/conn-castle/agent-layer/internal/i18n"
 	"github.com/conn-castle/agent-layer/internal/messages"
 	"github.com/conn-castle/agent-layer/internal/projection"
 )
@@ -54,7 +55,7 @@ func writeCodexConfigWithCLISettings(sys System, root string, project *config.Pr
 
 	codexDir := filepath.Join(root, ".codex")
 	if err := sys.MkdirAll(codexDir, 0o755); err != nil {
-		return fmt.Errorf(messages.SyncCreateDirFailedFmt, codexDir, err)
+		return i18n.Errorf(messages.SyncCreateDirFailedFmt, codexDir, err)
 	}
 
 	path := filepath.Join(root, ".codex", "config.toml")
@@ -67,7 +68,7 @@ func writeCodexConfigWithCLISettings(sys System, root string, project *config.Pr
 		return err
 	}
 	if err := sys.WriteFileAtomic(path, []byte(content), 0o600); err != nil {
-		return fmt.Errorf(messages.SyncWriteFileFailedFmt, path, err)
+		return i18n.Errorf(messages.SyncWriteFileFailedFmt, path, err)
 	}
 
 	return nil
@@ -78,13 +79,9 @@ func writeCodexRules(sys System, root string, project *config.ProjectConfig) err
 	content := buildCodexRules(project)
 	rulesDir := filepath.Join(root, ".codex", "rules")
 	if err := sys.MkdirAll(rulesDir, 0o755); err != nil {
-		return fmt.Errorf(messages.SyncCreateDirFailedFmt, rulesDir, err)
+		return i18n.Errorf(messages.SyncCreateDirFailedFmt, rulesDir, err)
 	}
-	path := filepath.Join(rulesDir, "default.rules")
-	if err := sys.WriteFileAtomic(path, []byte(content), 0o644); err != nil {
-		return fmt.Errorf(messages.SyncWriteFileFailedFmt, path, err)
-	}
-	return nil
+	return writeGeneratedFile(sys, filepath.Join(rulesDir, "default.rules"), content, 0o644)
 }
 
 //nolint:unparam // Kept aligned with buildCodexManagedConfigWithSystem so tests can exercise source reads through System.
@@ -145,6 +142,12 @@ func buildCodexManagedConfigWithSystem(sys System, root string, project *config.
 		return codexManagedConfig{}, err
 	}
 
+	var profiles map[string]config.CodexProfile
+	if includeCLISettings {
+		profiles = project.Config.Agents.Codex.Profiles
+		appendCodexProfiles(&builder, profiles)
+	}
+
 	if err := appendCodexTrustedProject(&builder, trustedRoot, agentSpecific); err != nil {
 		return codexManagedConfig{}, err
 	}
@@ -157,7 +160,7 @@ func buildCodexManagedConfigWithSystem(sys System, root string, project *config.
 	if !config.HasProviderPassthroughKey(agentSpecific, config.CodexMCPServersKey) {
 		// Use placeholder syntax for initial resolution (needed for bearer_token_env_var extraction).
 		resolved, err := projection.ResolveMCPServers(
-			project.Config.MCP.Servers,
+			clientMCPServers(project, "codex"),
 			project.Env,
 			"codex",
 			projection.ClientPlaceholderResolver("${%s}"),
@@ -184,8 +187,9 @@ func buildCodexManagedConfigWithSystem(sys System, root string, project *config.
 					return codexManagedConfig{}, err
 				}
 			default:
-				return codexManagedConfig{}, fmt.Errorf(messages.MCPServerUnsupportedTransportFmt, server.ID, server.Transport)
+				return codexManagedConfig{}, i18n.Errorf(messages.MCPServerUnsupportedTransportFmt, server.ID, server.Transport)
 			}
+			writeCodexToolFilter(&builder, server)
 		}
 	}
 
@@ -194,16 +198,17 @@ func buildCodexManagedConfigWithSystem(sys System, root string, project *config.
 		TrustedRoot:   trustedRoot,
 		AgentSpecific: agentSpecific,
 		ChimeEnabled:  chimeEnabled,
+		Profiles:      profiles,
 	}, nil
 }
 
 func codexTrustedProjectRoot(root string) (string, error) {
 	if strings.TrimSpace(root) == "" {
-		return "", errors.New(messages.SyncCodexTrustRootRequired)
+		return "", errors.New(i18n.T(messages.SyncCodexTrustRootRequired))
 	}
 	absRoot, err := filepath.Abs(root)
 	if err != nil {
-		return "", fmt.Errorf(messages.SyncCodexTrustRootResolveFailedFmt, root, err)
+		return "", i18n.Errorf(messages.SyncCodexTrustRootResolveFailedFmt, root, err)
 	}
 	// fmt %q (used to encode the `[projects.<root>]` TOML key) emits Go escapes
 	// for control characters (e.g. \x00, \a, \v, \x1b, \x85) and for invalid
@@ -216,11 +221,11 @@ func codexTrustedProjectRoot(root string) (string, error) {
 	// strings.IndexFunc decodes invalid bytes as utf8.RuneError (which is not a
 	// control rune), so the control-char scan alone would miss them.
 	if !utf8.ValidString(absRoot) {
-		return "", fmt.Errorf(messages.SyncCodexTrustRootInvalidUTF8Fmt, absRoot)
+		return "", i18n.Errorf(messages.SyncCodexTrustRootInvalidUTF8Fmt, absRoot)
 	}
 	if idx := strings.IndexFunc(absRoot, unicode.IsControl); idx >= 0 {
 		bad, _ := utf8.DecodeRuneInString(absRoot[idx:])
-		return "", fmt.Errorf(messages.SyncCodexTrustRootControlCharFmt, absRoot, bad)
+		return "", i18n.Errorf(messages.SyncCodexTrustRootControlCharFmt, absRoot, bad)
 	}
 	return absRoot, nil
 }
@@ -239,6 +244,35 @@ func appendCodexTrustedProject(builder *strings.Builder, repoRoot string, agentS
 	return nil
 }
 
+// appendCodexProfiles writes one [profiles.<name>] table per configured
+// profile, in name order, leaving out unset fields.
+func appendCodexProfiles(builder *strings.Builder, profiles map[string]config.CodexProfile) {
+	for _, name := range config.CodexProfileNames(profiles) {
+		appendCodexSectionBreak(builder)
+		fmt.Fprintf(builder, "[profiles.%s]\n", name)
+		for _, field := range codexProfileFields(profiles[name]) {
+			if field.value != "" {
+				fmt.Fprintf(builder, "%s = %q\n", field.key, field.value)
+			}
+		}
+	}
+}
+
+type codexProfileField struct {
+	key   string
+	value string
+}
+
+// codexProfileFields maps a profile onto its Codex keys in output order.
+func codexProfileFields(profile config.CodexProfile) []codexProfileField {
+	return []codexProfileField{
+		{config.CodexModelKey, profile.Model},
+		{config.CodexReasoningEffortKey, profile.ReasoningEffort},
+		{config.CodexApprovalPolicyKey, profile.ApprovalPolicy},
+		{config.CodexSandboxModeKey, profile.SandboxMode},
+	}
+}
+
 func appendCodexSectionBreak(builder *strings.Builder) {
 	content := builder.String()
 	if content == "" || strings.HasSuffix(content, "\n\n") {
@@ -270,22 +304,33 @@ func codexAgentSpecificDefinesProject(agentSpecific map[string]any, repoRoot str
 	}
 	projectsMap, ok := projects.(map[string]any)
 	if !ok {
-		return false, errors.New(messages.SyncCodexAgentSpecificProjectsTableConflict)
+		return false, errors.New(i18n.T(messages.SyncCodexAgentSpecificProjectsTableConflict))
 	}
 	for projectPath, entry := range projectsMap {
 		if _, ok := entry.(map[string]any); !ok {
-			return false, fmt.Errorf(messages.SyncCodexAgentSpecificProjectEntryTableFmt, projectPath)
+			return false, i18n.Errorf(messages.SyncCodexAgentSpecificProjectEntryTableFmt, projectPath)
 		}
 	}
 	_, ok = projectsMap[repoRoot]
 	return ok, nil
 }
 
+// writeCodexToolFilter projects tools_allow and tools_deny onto Codex's
+// enabled_tools and disabled_tools, which Codex applies in the same order.
+func writeCodexToolFilter(builder *strings.Builder, server projection.ResolvedMCPServer) {
+	if len(server.ToolsAllow) > 0 {
+		fmt.Fprintf(builder, "enabled_tools = %s\n", tomlStringArray(server.ToolsAllow))
+	}
+	if len(server.ToolsDeny) > 0 {
+		fmt.Fprintf(builder, "disabled_tools = %s\n", tomlStringArray(server.ToolsDeny))
+	}
+}
+
 func writeCodexHTTPServer(builder *strings.Builder, server projection.ResolvedMCPServer, env map[string]string) error {
 	if len(server.Headers) > 0 {
 		headerSpec, err := splitCodexHeaders(server.Headers)
 		if err != nil {
-			return fmt.Errorf(messages.SyncMCPServerErrorFmt, server.ID, err)
+			return i18n.Errorf(messages.SyncMCPServerErrorFmt, server.ID, err)
 		}
 		if headerSpec.BearerTokenEnvVar != "" {
 			fmt.Fprintf(builder, "bearer_token_env_var = %q\n", headerSpec.BearerTokenEnvVar)
@@ -300,7 +345,7 @@ func writeCodexHTTPServer(builder *strings.Builder, server projection.ResolvedMC
 	// Resolve actual values in the URL (Codex doesn't support ${VAR} placeholders in URLs).
 	resolvedURL, err := config.SubstituteEnvVars(server.URL, env)
 	if err != nil {
-		return fmt.Errorf(messages.MCPServerURLFmt, server.ID, err)
+		return i18n.Errorf(messages.MCPServerURLFmt, server.ID, err)
 	}
 	fmt.Fprintf(builder, "url = %q\n", resolvedURL)
 	return nil
@@ -310,7 +355,7 @@ func writeCodexStdioServer(builder *strings.Builder, server projection.ResolvedM
 	// Resolve actual values in command (Codex doesn't support ${VAR} placeholders).
 	resolvedCommand, err := config.SubstituteEnvVars(server.Command, env)
 	if err != nil {
-		return fmt.Errorf(messages.MCPServerCommandFmt, server.ID, err)
+		return i18n.Errorf(messages.MCPServerCommandFmt, server.ID, err)
 	}
 	fmt.Fprintf(builder, "command = %q\n", resolvedCommand)
 
@@ -319,7 +364,7 @@ func writeCodexStdioServer(builder *strings.Builder, server projection.ResolvedM
 		for _, arg := range server.Args {
 			resolvedArg, err := config.SubstituteEnvVars(arg, env)
 			if err != nil {
-				return fmt.Errorf(messages.SyncMCPServerArgFailedFmt, server.ID, err)
+				return i18n.Errorf(messages.SyncMCPServerArgFailedFmt, server.ID, err)
 			}
 			resolvedArgs = append(resolvedArgs, resolvedArg)
 		}
@@ -332,7 +377,7 @@ func writeCodexStdioServer(builder *strings.Builder, server projection.ResolvedM
 		for key, value := range server.Env {
 			resolvedValue, err := config.SubstituteEnvVars(value, env)
 			if err != nil {
-				return fmt.Errorf(messages.MCPServerEnvFmt, server.ID, key, err)
+				return i18n.Errorf(messages.MCPServerEnvFmt, server.ID, key, err)
 			}
 			resolvedEnv[key] = resolvedValue
 		}
@@ -369,7 +414,7 @@ func splitCodexHeaders(headers map[string]string) (codexHeaderSpec, error) {
 				spec.HTTPHeaders[key] = value
 				continue
 			}
-			return codexHeaderSpec{}, fmt.Errorf(messages.SyncCodexAuthorizationPlaceholderUnsupportedFmt)
+			return codexHeaderSpec{}, i18n.Errorf(messages.SyncCodexAuthorizationPlaceholderUnsupportedFmt)
 		}
 
 		if envVar, ok := extractExactEnvPlaceholder(value); ok {
@@ -380,7 +425,7 @@ func splitCodexHeaders(headers map[string]string) (codexHeaderSpec, error) {
 			spec.HTTPHeaders[key] = value
 			continue
 		}
-		return codexHeaderSpec{}, fmt.Errorf(messages.SyncCodexHeaderPlaceholderUnsupportedFmt, key)
+		return codexHeaderSpec{}, i18n.Errorf(messages.SyncCodexHeaderPlaceholderUnsupportedFmt, key)
 	}
 	if len(spec.EnvHeaders) == 0 {
 		spec.EnvHeaders = nil
@@ -445,14 +490,12 @@ func tomlInlineTable(values map[string]string) string {
 
 func buildCodexRules(project *config.ProjectConfig) string {
 	var builder strings.Builder
-	builder.WriteString("# GENERATED FILE\n")
-	builder.WriteString("# Source: .agent-layer/commands.allow\n")
-	builder.WriteString("# Regenerate: al sync\n")
+	builder.WriteString(hashCommentGeneratedHeader(".agent-layer/commands.allow"))
 	builder.WriteString("\n")
 
 	approvals := projection.BuildApprovals(project.Config, project.CommandsAllow)
 	if !approvals.AllowCommands {
-		return builder.String()
+		return sealGeneratedContent(builder.String())
 	}
 
 	for _, cmd := range approvals.Commands {
@@ -470,5 +513,5 @@ func buildCodexRules(project *config.ProjectConfig) string {
 		)
 	}
 
-	return builder.String()
+	return sealGeneratedContent(builder.String())
 }
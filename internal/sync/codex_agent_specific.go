@@ -2,11 +2,11 @@ package sync
 
 import (
 	"bytes"
-	"fmt"
 	"strings"
 
 	"github.com/pelletier/go-toml/v2"
 
+	"github.com/conn-castle/agent-layer/internal/i18n"
 	"github.com/conn-castle/agent-layer/internal/messages"
 )
 
@@ -32,7 +32,7 @@ func encodeAgentSpecificTOML(agentSpecific map[string]any) (string, error) {
 	var buf bytes.Buffer
 	encoder := toml.NewEncoder(&buf)
 	if err := encoder.Encode(agentSpecific); err != nil {
-		return "", fmt.Errorf(messages.SyncMarshalCodexAgentSpecificFailedFmt, err)
+		return "", i18n.Errorf(messages.SyncMarshalCodexAgentSpecificFailedFmt, err)
 	}
 	return buf.String(), nil
 }
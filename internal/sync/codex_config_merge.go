@@ -11,6 +11,7 @@ import (
 	"github.com/pelletier/go-toml/v2"
 
 	"github.com/conn-castle/agent-layer/internal/config"
+	"github.com/conn-castle/agent-layer/internal/i18n"
 	"github.com/conn-castle/agent-layer/internal/messages"
 	"github.com/conn-castle/agent-layer/internal/tomlpatch"
 )
@@ -35,6 +36,9 @@ type codexManagedConfig struct {
 	TrustedRoot   string
 	AgentSpecific map[string]any
 	ChimeEnabled  bool
+	// Profiles are the agents.codex.profiles entries written to the managed
+	// content; their fields are patched into existing [profiles.<name>] tables.
+	Profiles map[string]config.CodexProfile
 }
 
 type codexTomlEditor struct {
@@ -52,7 +56,7 @@ func readExistingCodexConfig(sys System, path string) (string, error) {
 		if os.IsNotExist(err) {
 			return "", nil
 		}
-		return "", fmt.Errorf(messages.SyncReadFailedFmt, path, err)
+		return "", i18n.Errorf(messages.SyncReadFailedFmt, path, err)
 	}
 	return string(data), nil
 }
@@ -61,7 +65,7 @@ func mergeCodexConfig(path string, existing string, managed codexManagedConfig)
 	var existingMap map[string]any
 	if strings.TrimSpace(existing) != "" {
 		if err := toml.Unmarshal([]byte(existing), &existingMap); err != nil {
-			return "", fmt.Errorf(messages.SyncCodexExistingConfigInvalidFmt, path, err)
+			return "", i18n.Errorf(messages.SyncCodexExistingConfigInvalidFmt, path, err)
 		}
 	}
 	var managedMap map[string]any
@@ -116,6 +120,24 @@ func mergeCodexConfig(path string, existing string, managed codexManagedConfig)
 		editor.removePath(statuslinePath)
 	}
 
+	// Insert fields in reverse so a newly created table lists them in the
+	// same order as a fresh render.
+	for _, name := range config.CodexProfileNames(managed.Profiles) {
+		fields := codexProfileFields(managed.Profiles[name])
+		for i := len(fields) - 1; i >= 0; i-- {
+			pathParts := []string{config.CodexProfilesKey, name, fields[i].key}
+			if fields[i].value == "" {
+				editor.removePath(pathParts)
+				continue
+			}
+			literal, err := tomlLiteral(fields[i].value)
+			if err != nil {
+				return "", err
+			}
+			editor.setPath(pathParts, literal)
+		}
+	}
+
 	for _, item := range agentSpecificLeafValues(managed.AgentSpecific) {
 		if codexPathHandledElsewhere(item.path) {
 			continue
@@ -181,7 +203,7 @@ func cleanCodexChimeHook(sys System, root string) error {
 		return fmt.Errorf("merged Codex config is invalid TOML: %w", err)
 	}
 	if err := sys.WriteFileAtomic(path, []byte(out), mode); err != nil {
-		return fmt.Errorf(messages.SyncWriteFileFailedFmt, path, err)
+		return i18n.Errorf(messages.SyncWriteFileFailedFmt, path, err)
 	}
 	return nil
 }
@@ -190,13 +212,27 @@ func validateCodexExistingShapes(path string, existing map[string]any, managed m
 	for _, managedPath := range [][]string{{codexFeaturesKey}, {codexTUIKey}, {codexProjectsKey}} {
 		if value, ok := valueAtPath(existing, managedPath); ok {
 			if _, table := value.(map[string]any); !table {
-				return fmt.Errorf(messages.SyncCodexExistingConfigShapeConflictFmt, path, strings.Join(managedPath, "."))
+				return i18n.Errorf(messages.SyncCodexExistingConfigShapeConflictFmt, path, strings.Join(managedPath, "."))
+			}
+		}
+	}
+	if profiles, ok := managed[config.CodexProfilesKey].(map[string]any); ok {
+		if value, ok := valueAtPath(existing, []string{config.CodexProfilesKey}); ok {
+			if _, table := value.(map[string]any); !table {
+				return i18n.Errorf(messages.SyncCodexExistingConfigShapeConflictFmt, path, config.CodexProfilesKey)
+			}
+		}
+		for name := range profiles {
+			if value, ok := valueAtPath(existing, []string{config.CodexProfilesKey, name}); ok {
+				if _, table := value.(map[string]any); !table {
+					return i18n.Errorf(messages.SyncCodexExistingConfigShapeConflictFmt, path, config.CodexProfilesKey+"."+name)
+				}
 			}
 		}
 	}
 	if value, ok := valueAtPath(existing, []string{codexProjectsKey, trustedRoot}); ok {
 		if _, table := value.(map[string]any); !table {
-			return fmt.Errorf(messages.SyncCodexExistingConfigShapeConflictFmt, path, "projects."+tomlpatch.FormatKey(trustedRoot))
+			return i18n.Errorf(messages.SyncCodexExistingConfigShapeConflictFmt, path, "projects."+tomlpatch.FormatKey(trustedRoot))
 		}
 	}
 	// A managed root scalar that we will write (e.g. model) cannot coexist with an
@@ -208,7 +244,7 @@ func validateCodexExistingShapes(path string, existing map[string]any, managed m
 		}
 		if value, ok := valueAtPath(existing, []string{key}); ok {
 			if _, table := value.(map[string]any); table {
-				return fmt.Errorf(messages.SyncCodexExistingConfigShapeConflictFmt, path, key)
+				return i18n.Errorf(messages.SyncCodexExistingConfigShapeConflictFmt, path, key)
 			}
 		}
 	}
@@ -349,7 +385,7 @@ func (e *codexTomlEditor) applyCodexChimeHook(path string, enabled bool) (bool,
 		return false, err
 	}
 	if e.rootInlineTableExists(hooksKey) {
-		return false, fmt.Errorf(messages.SyncCodexExistingConfigShapeConflictFmt, path, hooksKey)
+		return false, i18n.Errorf(messages.SyncCodexExistingConfigShapeConflictFmt, path, hooksKey)
 	}
 	var builder strings.Builder
 	appendCodexChimeBlock(&builder)
@@ -375,18 +411,18 @@ func (e *codexTomlEditor) expandCodexStopAssignment(path string) error {
 	fragment := strings.Join(e.lines[assignment.start:assignment.end+1], "\n")
 	var parsed map[string]any
 	if err := toml.Unmarshal([]byte(fragment), &parsed); err != nil {
-		return fmt.Errorf(messages.SyncCodexExistingConfigInvalidFmt, path, err)
+		return i18n.Errorf(messages.SyncCodexExistingConfigInvalidFmt, path, err)
 	}
 	value, _ := valueAtPath(parsed, assignment.keyPath)
 	entries, ok := value.([]any)
 	if !ok {
-		return fmt.Errorf(messages.SyncCodexExistingConfigShapeConflictFmt, path, codexHooksStopPath)
+		return i18n.Errorf(messages.SyncCodexExistingConfigShapeConflictFmt, path, codexHooksStopPath)
 	}
 	var lines []string
 	for _, entry := range entries {
 		entryMap, ok := entry.(map[string]any)
 		if !ok {
-			return fmt.Errorf(messages.SyncCodexExistingConfigShapeConflictFmt, path, codexHooksStopPath)
+			return i18n.Errorf(messages.SyncCodexExistingConfigShapeConflictFmt, path, codexHooksStopPath)
 		}
 		lines = append(lines, "[["+codexHooksStopPath+"]]")
 		entryKeys := make([]string, 0, len(entryMap))
@@ -402,7 +438,7 @@ func (e *codexTomlEditor) expandCodexStopAssignment(path string) error {
 		if hooksValue, ok := entryMap[hooksKey]; ok {
 			hooks, ok := hooksValue.([]any)
 			if !ok {
-				return fmt.Errorf(messages.SyncCodexExistingConfigShapeConflictFmt, path, codexHooksStopHooksPath)
+				return i18n.Errorf(messages.SyncCodexExistingConfigShapeConflictFmt, path, codexHooksStopHooksPath)
 			}
 			if len(hooks) == 0 {
 				lines = append(lines, hooksKey+" = []")
@@ -411,7 +447,7 @@ func (e *codexTomlEditor) expandCodexStopAssignment(path string) error {
 			for _, hook := range hooks {
 				hookMap, ok := hook.(map[string]any)
 				if !ok {
-					return fmt.Errorf(messages.SyncCodexExistingConfigShapeConflictFmt, path, codexHooksStopHooksPath)
+					return i18n.Errorf(messages.SyncCodexExistingConfigShapeConflictFmt, path, codexHooksStopHooksPath)
 				}
 				lines = append(lines, "[["+codexHooksStopHooksPath+"]]")
 				hookKeys := make([]string, 0, len(hookMap))
@@ -460,14 +496,14 @@ func (e *codexTomlEditor) rejectAmbiguousCodexChimeHook(path string) error {
 	content := e.render()
 	var parsed map[string]any
 	if err := toml.Unmarshal([]byte(content), &parsed); err != nil {
-		return fmt.Errorf(messages.SyncCodexExistingConfigInvalidFmt, path, err)
+		return i18n.Errorf(messages.SyncCodexExistingConfigInvalidFmt, path, err)
 	}
 	hooks, ok := parsed[hooksKey]
 	if !ok {
 		return nil
 	}
 	if containsExactChimeCommand(hooks, managedChimeCommandVariants(agentLayerCodexChimeCommand)) {
-		return fmt.Errorf(messages.SyncCodexChimeOwnershipConflictFmt, path)
+		return i18n.Errorf(messages.SyncCodexChimeOwnershipConflictFmt, path)
 	}
 	return nil
 }
@@ -530,7 +566,7 @@ func (e *codexTomlEditor) managedCodexChimeRegions(path string) ([]lineRange, er
 		return tomlpatch.LineWalkResult{}
 	})
 	if invalid || open >= 0 || markerCount > 1 {
-		return nil, fmt.Errorf(messages.SyncCodexChimeMarkerConflictFmt, path)
+		return nil, i18n.Errorf(messages.SyncCodexChimeMarkerConflictFmt, path)
 	}
 	return ranges, nil
 }
@@ -848,13 +884,13 @@ func (e *codexTomlEditor) appendMissingProjects(path string, existing map[string
 		}
 		entry, ok := managedProjects[projectPath].(map[string]any)
 		if !ok {
-			return fmt.Errorf(messages.SyncCodexAgentSpecificProjectEntryTableFmt, projectPath)
+			return i18n.Errorf(messages.SyncCodexAgentSpecificProjectEntryTableFmt, projectPath)
 		}
 		// A new [projects."<path>"] header cannot extend a root inline-table
 		// `projects = { ... }`; fail with the actionable shape message rather than
 		// the opaque go-toml render-check error.
 		if e.rootInlineTableExists(config.CodexProjectsKey) {
-			return fmt.Errorf(messages.SyncCodexExistingConfigShapeConflictFmt, path, config.CodexProjectsKey)
+			return i18n.Errorf(messages.SyncCodexExistingConfigShapeConflictFmt, path, config.CodexProjectsKey)
 		}
 		e.appendBlock(renderProjectBlock(projectPath, entry))
 	}
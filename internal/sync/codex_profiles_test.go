@@ -0,0 +1,117 @@
+package sync
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pelletier/go-toml/v2"
+
+	"github.com/conn-castle/agent-layer/internal/config"
+)
+
+func codexProfilesProject(profiles map[string]config.CodexProfile) *config.ProjectConfig {
+	enabled := true
+	return &config.ProjectConfig{
+		Config: config.Config{
+			Approvals: config.ApprovalsConfig{Mode: config.ApprovalModeAll},
+			Agents: config.AgentsConfig{Codex: config.CodexConfig{
+				Enabled:  &enabled,
+				Model:    "gpt-5.3-codex",
+				Profiles: profiles,
+			}},
+		},
+		Env: map[string]string{},
+	}
+}
+
+func TestBuildCodexConfigProfiles(t *testing.T) {
+	t.Parallel()
+	project := codexProfilesProject(map[string]config.CodexProfile{
+		"fast": {Model: "gpt-5.3-codex-mini", ReasoningEffort: "low"},
+		"deep": {ReasoningEffort: "xhigh", ApprovalPolicy: "on-request", SandboxMode: "workspace-write"},
+	})
+
+	output, err := buildCodexConfigWithSystem(RealSystem{}, t.TempDir(), project)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "[profiles.deep]\n" +
+		"model_reasoning_effort = \"xhigh\"\n" +
+		"approval_policy = \"on-request\"\n" +
+		"sandbox_mode = \"workspace-write\"\n" +
+		"\n" +
+		"[profiles.fast]\n" +
+		"model = \"gpt-5.3-codex-mini\"\n" +
+		"model_reasoning_effort = \"low\"\n"
+	if !strings.Contains(output, want) {
+		t.Fatalf("expected profile tables in output:\n%s", output)
+	}
+	var parsed map[string]any
+	if err := toml.Unmarshal([]byte(output), &parsed); err != nil {
+		t.Fatalf("expected valid TOML: %v\n%s", err, output)
+	}
+
+	managed, err := buildCodexManagedConfigWithSystem(RealSystem{}, t.TempDir(), project, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Contains(managed.Content, "[profiles.") || managed.Profiles != nil {
+		t.Fatalf("expected profiles left out without CLI settings:\n%s", managed.Content)
+	}
+}
+
+func TestWriteCodexConfig_PatchesExistingProfiles(t *testing.T) {
+	t.Parallel()
+	root := t.TempDir()
+	writeExistingCodexConfig(t, root, codexHeader+`
+[profiles.fast]
+model = "stale-model"
+approval_policy = "never" # keep this note
+
+[profiles.mine]
+model = "user-owned"
+`)
+	project := codexProfilesProject(map[string]config.CodexProfile{
+		"fast": {Model: "gpt-5.3-codex-mini", ReasoningEffort: "low"},
+		"deep": {ReasoningEffort: "xhigh"},
+	})
+
+	if err := writeCodexConfig(RealSystem{}, root, project); err != nil {
+		t.Fatalf("writeCodexConfig: %v", err)
+	}
+	first := readCodexConfig(t, root)
+	if err := writeCodexConfig(RealSystem{}, root, project); err != nil {
+		t.Fatalf("second writeCodexConfig: %v", err)
+	}
+	if second := readCodexConfig(t, root); second != first {
+		t.Fatalf("expected idempotent second sync\nfirst:\n%s\nsecond:\n%s", first, second)
+	}
+
+	var parsed struct {
+		Profiles map[string]map[string]string `toml:"profiles"`
+	}
+	if err := toml.Unmarshal([]byte(first), &parsed); err != nil {
+		t.Fatalf("parse merged config: %v\n%s", err, first)
+	}
+	if fast := parsed.Profiles["fast"]; fast["model"] != "gpt-5.3-codex-mini" || fast["model_reasoning_effort"] != "low" || fast["approval_policy"] != "" {
+		t.Fatalf("expected fast profile patched, got %v\n%s", fast, first)
+	}
+	if deep := parsed.Profiles["deep"]; len(deep) != 1 || deep["model_reasoning_effort"] != "xhigh" {
+		t.Fatalf("expected deep profile added, got %v", deep)
+	}
+	if mine := parsed.Profiles["mine"]; mine["model"] != "user-owned" {
+		t.Fatalf("expected unmanaged profile kept, got %v", mine)
+	}
+}
+
+func TestWriteCodexConfig_ProfileShapeConflict(t *testing.T) {
+	t.Parallel()
+	root := t.TempDir()
+	writeExistingCodexConfig(t, root, codexHeader+"profiles = \"oops\"\n")
+	project := codexProfilesProject(map[string]config.CodexProfile{"fast": {Model: "m"}})
+
+	err := writeCodexConfig(RealSystem{}, root, project)
+	if err == nil || !strings.Contains(err.Error(), "profiles") {
+		t.Fatalf("expected shape conflict error, got %v", err)
+	}
+}
@@ -1,13 +1,14 @@
 package sync
 
 import (
-	"fmt"
 	"os"
 	"path/filepath"
 
 	"github.com/pelletier/go-toml/v2"
 
 	"github.com/conn-castle/agent-layer/internal/config"
+	"github.com/conn-castle/agent-layer/internal/i18n"
+	"github.com/conn-castle/agent-layer/internal/layerdir"
 	"github.com/conn-castle/agent-layer/internal/messages"
 )
 
@@ -69,7 +70,7 @@ func injectCodexStatusLine(agentSpecific map[string]any, statusLine []string) er
 	if existing, ok := agentSpecific[codexTUIKey]; ok {
 		existingTUI, ok := existing.(map[string]any)
 		if !ok {
-			return fmt.Errorf(messages.SyncCodexStatuslineTUITableConflict)
+			return i18n.Errorf(messages.SyncCodexStatuslineTUITableConflict)
 		}
 		tui = existingTUI
 	}
@@ -79,13 +80,13 @@ func injectCodexStatusLine(agentSpecific map[string]any, statusLine []string) er
 }
 
 func readCodexStatuslineSource(sys System, root string) ([]string, error) {
-	src := filepath.Join(root, ".agent-layer", codexStatuslineSourceName)
+	src := filepath.Join(layerdir.Dir(root), codexStatuslineSourceName)
 	data, err := sys.ReadFile(src)
 	if err != nil {
 		if !os.IsNotExist(err) {
-			return nil, fmt.Errorf(messages.SyncReadFailedFmt, src, err)
+			return nil, i18n.Errorf(messages.SyncReadFailedFmt, src, err)
 		}
-		return nil, fmt.Errorf(messages.SyncCodexStatuslineSourceMissingFmt, src)
+		return nil, i18n.Errorf(messages.SyncCodexStatuslineSourceMissingFmt, src)
 	}
 	return parseCodexStatuslineSource(data, src)
 }
@@ -93,26 +94,26 @@ func readCodexStatuslineSource(sys System, root string) ([]string, error) {
 func parseCodexStatuslineSource(data []byte, source string) ([]string, error) {
 	var raw map[string]any
 	if err := toml.Unmarshal(data, &raw); err != nil {
-		return nil, fmt.Errorf(messages.SyncCodexStatuslineInvalidTOMLFmt, source, err)
+		return nil, i18n.Errorf(messages.SyncCodexStatuslineInvalidTOMLFmt, source, err)
 	}
 	tuiValue, ok := raw[codexTUIKey]
 	if !ok {
-		return nil, fmt.Errorf(messages.SyncCodexStatuslineStatusLineMissingFmt, source)
+		return nil, i18n.Errorf(messages.SyncCodexStatuslineStatusLineMissingFmt, source)
 	}
 	if len(raw) != 1 {
-		return nil, fmt.Errorf(messages.SyncCodexStatuslineOnlyStatusLineFmt, source)
+		return nil, i18n.Errorf(messages.SyncCodexStatuslineOnlyStatusLineFmt, source)
 	}
 
 	tui, ok := tuiValue.(map[string]any)
 	if !ok {
-		return nil, fmt.Errorf(messages.SyncCodexStatuslineOnlyStatusLineFmt, source)
+		return nil, i18n.Errorf(messages.SyncCodexStatuslineOnlyStatusLineFmt, source)
 	}
 	statusLineValue, ok := tui[codexStatusLineKey]
 	if !ok {
-		return nil, fmt.Errorf(messages.SyncCodexStatuslineStatusLineMissingFmt, source)
+		return nil, i18n.Errorf(messages.SyncCodexStatuslineStatusLineMissingFmt, source)
 	}
 	if len(tui) != 1 {
-		return nil, fmt.Errorf(messages.SyncCodexStatuslineOnlyStatusLineFmt, source)
+		return nil, i18n.Errorf(messages.SyncCodexStatuslineOnlyStatusLineFmt, source)
 	}
 
 	switch statusLine := statusLineValue.(type) {
@@ -121,12 +122,12 @@ func parseCodexStatuslineSource(data []byte, source string) ([]string, error) {
 		for _, item := range statusLine {
 			itemString, ok := item.(string)
 			if !ok {
-				return nil, fmt.Errorf(messages.SyncCodexStatuslineStatusLineTypeFmt, source)
+				return nil, i18n.Errorf(messages.SyncCodexStatuslineStatusLineTypeFmt, source)
 			}
 			out = append(out, itemString)
 		}
 		return out, nil
 	default:
-		return nil, fmt.Errorf(messages.SyncCodexStatuslineStatusLineTypeFmt, source)
+		return nil, i18n.Errorf(messages.SyncCodexStatuslineStatusLineTypeFmt, source)
 	}
 }
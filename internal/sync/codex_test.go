@@ -152,6 +152,37 @@ func TestBuildCodexConfigStdio(t *testing.T) {
 	}
 }
 
+func TestBuildCodexConfigToolFilter(t *testing.T) {
+	enabled := true
+	project := &config.ProjectConfig{
+		Config: config.Config{
+			Approvals: config.ApprovalsConfig{Mode: config.ApprovalModeAll},
+			Agents:    config.AgentsConfig{Codex: config.CodexConfig{Enabled: &enabled}},
+			MCP: config.MCPConfig{
+				Servers: []config.MCPServer{
+					{
+						ID:         "local",
+						Enabled:    &enabled,
+						Transport:  "stdio",
+						Command:    "tool",
+						ToolsAllow: []string{"read", "write"},
+						ToolsDeny:  []string{"write"},
+					},
+				},
+			},
+		},
+		Env: map[string]string{},
+	}
+
+	output, err := buildCodexConfigWithSystem(RealSystem{}, t.TempDir(), project)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(output, "enabled_tools = [\"read\", \"write\"]\ndisabled_tools = [\"write\"]\n") {
+		t.Fatalf("missing tool filter in output:\n%s", output)
+	}
+}
+
 func TestBuildCodexConfigHeaderPrecedesModelSettings(t *testing.T) {
 	enabled := true
 	project := &config.ProjectConfig{
@@ -0,0 +1,120 @@
+package sync
+
+import (
+	"strings"
+
+	"github.com/conn-castle/agent-layer/internal/config"
+)
+
+// instructionComposition is the canonical intermediate form of an instruction
+// set: each file framed in BEGIN/END markers, in order. Every generated
+// instruction document (AGENTS.md, CLAUDE.md, copilot-instructions.md, and
+// the path-scoped and nested files) renders from one, so the instruction text
+// clients share is byte-identical across them.
+type instructionComposition struct {
+	body string
+}
+
+// composeInstructions frames instructions into their canonical form.
+func composeInstructions(instructions []config.InstructionFile) instructionComposition {
+	var builder strings.Builder
+	for _, instruction := range instructions {
+		builder.WriteString("<!-- BEGIN: ")
+		builder.WriteString(instruction.Name)
+		builder.WriteString(" -->\n")
+		content := instruction.Content
+		builder.WriteString(content)
+		if !strings.HasSuffix(content, "\n") {
+			builder.WriteString("\n")
+		}
+		builder.WriteString("<!-- END: ")
+		builder.WriteString(instruction.Name)
+		builder.WriteString(" -->\n\n")
+	}
+	return instructionComposition{body: builder.String()}
+}
+
+// empty reports whether the composition holds no instructions.
+func (c instructionComposition) empty() bool {
+	return c.body == ""
+}
+
+// concat returns c followed by other.
+func (c instructionComposition) concat(other instructionComposition) instructionComposition {
+	return instructionComposition{body: c.body + other.body}
+}
+
+// render places the composition under a provenance header naming source. The
+// content hash is left unsealed so callers can prepend front matter.
+func (c instructionComposition) render(source string) string {
+	return strings.TrimRight(markdownGeneratedHeader(source)+"\n"+c.body, "\n") + "\n"
+}
+
+// instructionSetKey identifies an instruction slice by its backing array.
+// Clients without a [variants.clients] override reuse the shared project's
+// slice, so their sets compare equal without hashing the content.
+type instructionSetKey struct {
+	first *config.InstructionFile
+	n     int
+}
+
+func instructionSetKeyFor(instructions []config.InstructionFile) instructionSetKey {
+	if len(instructions) == 0 {
+		return instructionSetKey{}
+	}
+	return instructionSetKey{first: &instructions[0], n: len(instructions)}
+}
+
+// instructionComposer composes each instruction set once per sync run and
+// renders the root instruction documents from the compositions. Clients that
+// resolve to the same instructions share one composition and one rendered
+// document.
+type instructionComposer struct {
+	fallbacks    instructionComposition
+	compositions map[instructionSetKey]instructionComposition
+	documents    map[instructionDocumentKey]string
+}
+
+type instructionDocumentKey struct {
+	set       instructionSetKey
+	fallbacks bool
+}
+
+func newInstructionComposer(scoped []config.ScopedInstructions) *instructionComposer {
+	return &instructionComposer{
+		fallbacks:    composeInstructions(pathInstructionFallbacks(scoped)),
+		compositions: make(map[instructionSetKey]instructionComposition),
+		documents:    make(map[instructionDocumentKey]string),
+	}
+}
+
+// compose returns the composition of instructions, composing it on first use.
+func (c *instructionComposer) compose(instructions []config.InstructionFile) instructionComposition {
+	key := instructionSetKeyFor(instructions)
+	composition, ok := c.compositions[key]
+	if !ok {
+		composition = composeInstructions(instructions)
+		c.compositions[key] = composition
+	}
+	return composition
+}
+
+// document renders the sealed root instruction document for instructions,
+// appending the file-glob fallback sections when withFallbacks is set. No
+// instructions render as an empty file.
+func (c *instructionComposer) document(instructions []config.InstructionFile, withFallbacks bool) string {
+	key := instructionDocumentKey{set: instructionSetKeyFor(instructions), fallbacks: withFallbacks}
+	if content, ok := c.documents[key]; ok {
+		return content
+	}
+	composition := c.compose(instructions)
+	if withFallbacks {
+		composition = composition.concat(c.fallbacks)
+	}
+	content := ""
+	if !composition.empty() {
+		content = sealGeneratedContent(composition.render(instructionSource))
+	}
+	c.documents[key] = content
+	return content
+}
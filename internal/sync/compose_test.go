@@ -0,0 +1,78 @@
+package sync
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/conn-castle/agent-layer/internal/config"
+)
+
+func TestInstructionComposer_ComposesSharedSetOnce(t *testing.T) {
+	shared := []config.InstructionFile{{Name: "00_base.md", Content: "base"}}
+	pathScoped := []config.ScopedInstructions{{Glob: "**/*.go", Files: []config.InstructionFile{{Name: "go.md", Content: "use gofmt"}}}}
+	composer := newInstructionComposer(pathScoped)
+
+	agents := composer.document(shared, true)
+	claude := composer.document(shared, true)
+	copilot := composer.document(shared, false)
+	if len(composer.compositions) != 1 {
+		t.Fatalf("compositions = %d, want 1", len(composer.compositions))
+	}
+	if agents != claude {
+		t.Fatalf("AGENTS.md and CLAUDE.md differ for the same instructions")
+	}
+	if copilot != buildInstructionShim(shared) {
+		t.Fatalf("copilot document = %q, want %q", copilot, buildInstructionShim(shared))
+	}
+	want := buildInstructionShim(append(append([]config.InstructionFile(nil), shared...), pathInstructionFallbacks(pathScoped)...))
+	if agents != want {
+		t.Fatalf("document = %q, want %q", agents, want)
+	}
+}
+
+func TestInstructionComposer_VariantsComposeSeparately(t *testing.T) {
+	shared := []config.InstructionFile{{Name: "00_base.md", Content: "base"}}
+	claude := []config.InstructionFile{{Name: "00_base.md", Content: "claude base"}}
+	composer := newInstructionComposer(nil)
+
+	if composer.document(shared, true) == composer.document(claude, true) {
+		t.Fatalf("expected variant documents to differ")
+	}
+	if len(composer.compositions) != 2 {
+		t.Fatalf("compositions = %d, want 2", len(composer.compositions))
+	}
+	if got := composer.document(nil, true); got != "" {
+		t.Fatalf("empty document = %q, want empty", got)
+	}
+}
+
+func TestInstructionDocument_FallbacksOnly(t *testing.T) {
+	pathScoped := []config.ScopedInstructions{{Glob: "*.md", Files: []config.InstructionFile{{Name: "docs.md", Content: "wrap prose"}}}}
+	if got := InstructionDocument(nil, pathScoped); got == "" {
+		t.Fatalf("expected fallback sections to render without root instructions")
+	}
+}
+
+func TestWriteClientInstructionShims_SharedContentMatchesAcrossClients(t *testing.T) {
+	root := t.TempDir()
+	instructions := []config.InstructionFile{{Name: "00_base.md", Content: "base\n"}}
+	if err := writeInstructionShims(RealSystem{}, root, instructions, nil); err != nil {
+		t.Fatalf("writeInstructionShims: %v", err)
+	}
+	read := func(rel string) string {
+		t.Helper()
+		data, err := os.ReadFile(filepath.Join(root, rel))
+		if err != nil {
+			t.Fatalf("read %s: %v", rel, err)
+		}
+		return string(data)
+	}
+	agents := read("AGENTS.md")
+	if claude := read("CLAUDE.md"); claude != agents {
+		t.Fatalf("CLAUDE.md differs from AGENTS.md:\n%s\n---\n%s", claude, agents)
+	}
+	if copilot := read(filepath.Join(".github", "copilot-instructions.md")); copilot != agents {
+		t.Fatalf("copilot-instructions.md differs from AGENTS.md:\n%s\n---\n%s", copilot, agents)
+	}
+}
@@ -0,0 +1,245 @@
+package sync
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pelletier/go-toml/v2"
+
+	"github.com/conn-castle/agent-layer/internal/config"
+	"github.com/conn-castle/agent-layer/internal/i18n"
+	"github.com/conn-castle/agent-layer/internal/messages"
+)
+
+// configStateFile records a hash of the effective config as of the last sync,
+// so the next sync can say which keys changed. Values are stored only as
+// hashes; the file never holds config contents.
+const configStateFile = "sync-config.json"
+
+// configState is the on-disk shape of configStateFile. Keys maps each dotted
+// config key to the hash of its value; MCP servers are keyed by id.
+type configState struct {
+	Hash string            `json:"hash"`
+	Keys map[string]string `json:"keys"`
+}
+
+func configStatePath(root string) string {
+	return filepath.Join(config.StateDir(root), configStateFile)
+}
+
+// recordConfigState compares the effective config with the state the previous
+// sync recorded, returns the changed keys, and records the new state. The
+// first sync records state and reports nothing.
+func recordConfigState(sys System, root string, cfg config.Config) ([]string, error) {
+	current, err := effectiveConfigState(cfg)
+	if err != nil {
+		return nil, err
+	}
+	path := configStatePath(root)
+	previous, found, err := readConfigState(sys, path)
+	if err != nil {
+		return nil, err
+	}
+	if found && previous.Hash == current.Hash {
+		return nil, nil
+	}
+	data, err := sys.MarshalIndent(current, "", "  ")
+	if err != nil {
+		return nil, i18n.Errorf(messages.SyncMarshalConfigStateFailedFmt, err)
+	}
+	data = append(data, '\n')
+	if err := sys.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, i18n.Errorf(messages.SyncCreateDirFailedFmt, filepath.Dir(path), err)
+	}
+	if err := sys.WriteFileAtomic(path, data, 0o644); err != nil {
+		return nil, i18n.Errorf(messages.SyncWriteFileFailedFmt, path, err)
+	}
+	if !found {
+		return nil, nil
+	}
+	return changedConfigKeys(previous.Keys, current.Keys), nil
+}
+
+// readConfigState loads the recorded state. A missing or unreadable record is
+// treated as no record: it only feeds an informational summary.
+func readConfigState(sys System, path string) (configState, bool, error) {
+	data, err := sys.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return configState{}, false, nil
+		}
+		return configState{}, false, i18n.Errorf(messages.SyncReadConfigStateFailedFmt, path, err)
+	}
+	var state configState
+	if err := json.Unmarshal(data, &state); err != nil || state.Hash == "" {
+		return configState{}, false, nil
+	}
+	return state, true, nil
+}
+
+// effectiveConfigState hashes every leaf of cfg as config.toml spells it.
+func effectiveConfigState(cfg config.Config) (configState, error) {
+	data, err := toml.Marshal(cfg)
+	if err != nil {
+		return configState{}, i18n.Errorf(messages.SyncMarshalConfigStateFailedFmt, err)
+	}
+	var tree map[string]any
+	if err := toml.Unmarshal(data, &tree); err != nil {
+		return configState{}, i18n.Errorf(messages.SyncMarshalConfigStateFailedFmt, err)
+	}
+	keys := make(map[string]string)
+	if err := flattenConfigValue("", tree, keys); err != nil {
+		return configState{}, err
+	}
+	names := make([]string, 0, len(keys))
+	for name := range keys {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var all bytes.Buffer
+	for _, name := range names {
+		all.WriteString(name + "=" + keys[name] + "\n")
+	}
+	return configState{Hash: hashBytes(all.Bytes()), Keys: keys}, nil
+}
+
+// flattenConfigValue records the hash of each leaf under its dotted key.
+// Arrays of tables with an id, such as mcp.servers, are keyed by id so that
+// reordering them is not a change; other arrays are a single value.
+func flattenConfigValue(prefix string, value any, out map[string]string) error {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, child := range v {
+			if err := flattenConfigValue(joinConfigKey(prefix, key), child, out); err != nil {
+				return err
+			}
+		}
+		return nil
+	case []any:
+		if ids, ok := tableIDs(v); ok {
+			for i, child := range v {
+				if err := flattenConfigValue(joinConfigKey(prefix, ids[i]), child, out); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return i18n.Errorf(messages.SyncMarshalConfigStateFailedFmt, err)
+	}
+	out[prefix] = hashBytes(data)
+	return nil
+}
+
+// tableIDs returns the id of each table in items, when every item is a table
+// with a distinct string id.
+func tableIDs(items []any) ([]string, bool) {
+	ids := make([]string, 0, len(items))
+	seen := make(map[string]struct{}, len(items))
+	for _, item := range items {
+		table, ok := item.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		id, ok := table["id"].(string)
+		if !ok || id == "" {
+			return nil, false
+		}
+		if _, dup := seen[id]; dup {
+			return nil, false
+		}
+		seen[id] = struct{}{}
+		ids = append(ids, id)
+	}
+	return ids, len(ids) > 0
+}
+
+func joinConfigKey(prefix string, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + "." + key
+}
+
+// changedConfigKeys returns the keys added, removed, or changed between
+// previous and current, sorted. A table that appeared or disappeared as a
+// whole is reported once by its own key.
+func changedConfigKeys(previous map[string]string, current map[string]string) []string {
+	changed := make(map[string]struct{})
+	for key, hash := range current {
+		if previous[key] != hash {
+			changed[key] = struct{}{}
+		}
+	}
+	for key := range previous {
+		if _, ok := current[key]; !ok {
+			changed[key] = struct{}{}
+		}
+	}
+	out := make([]string, 0, len(changed))
+	for key := range changed {
+		out = append(out, key)
+	}
+	sort.Strings(out)
+	return collapseConfigKeys(out, previous, current)
+}
+
+// collapseConfigKeys replaces the keys of a table that exists on only one side
+// with the table's key, so adding an MCP server reads as one change.
+func collapseConfigKeys(keys []string, previous map[string]string, current map[string]string) []string {
+	hasPrefix := func(keys map[string]string, prefix string) bool {
+		for key := range keys {
+			if strings.HasPrefix(key, prefix+".") {
+				return true
+			}
+		}
+		return false
+	}
+	out := make([]string, 0, len(keys))
+	for _, key := range keys {
+		collapsed := key
+		for parent := key; strings.Contains(parent, "."); {
+			parent = parent[:strings.LastIndex(parent, ".")]
+			if hasPrefix(previous, parent) != hasPrefix(current, parent) {
+				collapsed = parent
+			}
+		}
+		if len(out) == 0 || out[len(out)-1] != collapsed {
+			out = append(out, collapsed)
+		}
+	}
+	return out
+}
+
+// ChangedOutputPaths returns the repo-relative files sync changed, leaving
+// out created directories and its own state records.
+func ChangedOutputPaths(root string, changes []Change) []string {
+	stateDir := filepath.Dir(configStatePath(root))
+	var out []string
+	for _, change := range changes {
+		if change.Kind == ChangeMkdir || change.Path == stateDir || strings.HasPrefix(change.Path, stateDir+string(os.PathSeparator)) {
+			continue
+		}
+		rel := change.Path
+		if r, err := filepath.Rel(root, change.Path); err == nil {
+			rel = filepath.ToSlash(r)
+		}
+		if len(out) == 0 || out[len(out)-1] != rel {
+			out = append(out, rel)
+		}
+	}
+	return out
+}
+
+func hashBytes(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,98 @@
+package sync
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/conn-castle/agent-layer/internal/config"
+)
+
+func TestRecordConfigState_ReportsChangedKeys(t *testing.T) {
+	t.Parallel()
+	root := t.TempDir()
+	enabled := true
+	cfg := config.Config{
+		Approvals: config.ApprovalsConfig{Mode: config.ApprovalModeAll},
+		MCP: config.MCPConfig{Servers: []config.MCPServer{
+			{ID: "docs", Enabled: &enabled, Transport: "http", URL: "https://docs.example"},
+		}},
+	}
+
+	keys, err := recordConfigState(RealSystem{}, root, cfg)
+	if err != nil || keys != nil {
+		t.Fatalf("first sync = %v, %v; want no report", keys, err)
+	}
+	if _, err := os.Stat(configStatePath(root)); err != nil {
+		t.Fatalf("first sync must record state: %v", err)
+	}
+	if keys, err := recordConfigState(RealSystem{}, root, cfg); err != nil || keys != nil {
+		t.Fatalf("unchanged config = %v, %v", keys, err)
+	}
+
+	cfg.Approvals.Mode = config.ApprovalModeMCP
+	cfg.MCP.Servers = append([]config.MCPServer{
+		{ID: "tracker", Enabled: &enabled, Transport: "stdio", Command: "tracker"},
+	}, cfg.MCP.Servers...)
+	keys, err = recordConfigState(RealSystem{}, root, cfg)
+	if err != nil {
+		t.Fatalf("recordConfigState: %v", err)
+	}
+	want := []string{"approvals.mode", "mcp.servers.tracker"}
+	if !reflect.DeepEqual(keys, want) {
+		t.Fatalf("changed keys = %v, want %v", keys, want)
+	}
+}
+
+func TestRecordConfigState_UnreadableRecordStartsOver(t *testing.T) {
+	t.Parallel()
+	root := t.TempDir()
+	path := configStatePath(root)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(path, []byte("{not json"), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	keys, err := recordConfigState(RealSystem{}, root, config.Config{})
+	if err != nil || keys != nil {
+		t.Fatalf("corrupt record = %v, %v; want a fresh record", keys, err)
+	}
+}
+
+func TestChangedOutputPaths_SkipsStateRecords(t *testing.T) {
+	t.Parallel()
+	root := t.TempDir()
+	changes := []Change{
+		{Kind: ChangeWrite, Path: configStatePath(root)},
+		{Kind: ChangeWrite, Path: filepath.Join(root, ".mcp.json")},
+		{Kind: ChangeMkdir, Path: filepath.Join(root, ".claude")},
+		{Kind: ChangeWrite, Path: filepath.Join(root, ".claude", "settings.json")},
+	}
+
+	got := ChangedOutputPaths(root, changes)
+	want := []string{".mcp.json", ".claude/settings.json"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("affected = %v, want %v", got, want)
+	}
+}
+
+func TestResultStalePaths(t *testing.T) {
+	t.Parallel()
+	root := t.TempDir()
+	result := &Result{
+		Changes: []Change{
+			{Kind: ChangeWrite, Path: filepath.Join(root, "CLAUDE.md")},
+			{Kind: ChangeWrite, Path: configStatePath(root)},
+		},
+		EditedFiles: []EditedFile{{Path: filepath.Join(root, "AGENTS.md")}, {Path: filepath.Join(root, "CLAUDE.md")}},
+	}
+
+	got := result.StalePaths(root)
+	want := []string{"AGENTS.md", "CLAUDE.md"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("stale = %v, want %v", got, want)
+	}
+}
@@ -1,11 +1,11 @@
 package sync
 
 import (
-	"fmt"
 	"os"
 	"path/filepath"
 
 	"github.com/conn-castle/agent-layer/internal/config"
+	"github.com/conn-castle/agent-layer/internal/i18n"
 	"github.com/conn-castle/agent-layer/internal/messages"
 	"github.com/conn-castle/agent-layer/internal/projection"
 )
@@ -22,7 +22,7 @@ type copilotMCPServer struct {
 	Env     OrderedMap[string] `json:"env,omitempty"`
 	URL     string             `json:"url,omitempty"`
 	Headers OrderedMap[string] `json:"headers,omitempty"`
-	Tools   []string           `json:"tools,omitempty"`
+	Tools   []string           `json:"tools"`
 }
 
 // writeCopilotMCPConfig generates .copilot/mcp-config.json for GitHub Copilot CLI.
@@ -35,18 +35,18 @@ func writeCopilotMCPConfig(sys System, root string, project *config.ProjectConfi
 
 	data, err := sys.MarshalIndent(cfg, "", "  ")
 	if err != nil {
-		return fmt.Errorf(messages.SyncMarshalCopilotMCPConfigFailedFmt, err)
+		return i18n.Errorf(messages.SyncMarshalCopilotMCPConfigFailedFmt, err)
 	}
 	data = append(data, '\n')
 
 	copilotDir := filepath.Join(root, ".copilot")
 	if err := sys.MkdirAll(copilotDir, 0o755); err != nil {
-		return fmt.Errorf(messages.SyncCreateDirFailedFmt, copilotDir, err)
+		return i18n.Errorf(messages.SyncCreateDirFailedFmt, copilotDir, err)
 	}
 
 	path := filepath.Join(copilotDir, "mcp-config.json")
 	if err := sys.WriteFileAtomic(path, data, 0o644); err != nil {
-		return fmt.Errorf(messages.SyncWriteFileFailedFmt, path, err)
+		return i18n.Errorf(messages.SyncWriteFileFailedFmt, path, err)
 	}
 
 	return nil
@@ -58,7 +58,7 @@ func buildCopilotMCPConfig(project *config.ProjectConfig) (*copilotMCPConfig, er
 	}
 
 	resolved, err := projection.ResolveMCPServers(
-		project.Config.MCP.Servers,
+		clientMCPServers(project, "copilot"),
 		project.Env,
 		"copilot",
 		projection.ClientPlaceholderResolver("${%s}"),
@@ -75,6 +75,11 @@ func buildCopilotMCPConfig(project *config.ProjectConfig) (*copilotMCPConfig, er
 			URL:     server.URL,
 			Tools:   []string{"*"},
 		}
+		// Copilot CLI only supports an allow list; deny-only filters are
+		// reported by the capability-mismatch policy warning.
+		if allowed := server.AllowedTools(); allowed != nil {
+			entry.Tools = allowed
+		}
 		if len(server.Headers) > 0 {
 			headers := make(OrderedMap[string], len(server.Headers))
 			for key, value := range server.Headers {
@@ -100,7 +105,7 @@ func buildCopilotMCPConfig(project *config.ProjectConfig) (*copilotMCPConfig, er
 func cleanCopilotOutputs(sys System, root string) error {
 	mcpConfigPath := filepath.Join(root, ".copilot", "mcp-config.json")
 	if err := sys.Remove(mcpConfigPath); err != nil && !os.IsNotExist(err) {
-		return fmt.Errorf(messages.SyncRemoveFailedFmt, mcpConfigPath, err)
+		return i18n.Errorf(messages.SyncRemoveFailedFmt, mcpConfigPath, err)
 	}
 	return nil
 }
@@ -55,6 +55,33 @@ func TestBuildCopilotMCPConfigStdio(t *testing.T) {
 	}
 }
 
+func TestBuildCopilotMCPConfigToolsAllow(t *testing.T) {
+	t.Parallel()
+	enabled := true
+	project := &config.ProjectConfig{
+		Config: config.Config{
+			MCP: config.MCPConfig{
+				Servers: []config.MCPServer{
+					{ID: "allow", Enabled: &enabled, Transport: "stdio", Command: "tool", ToolsAllow: []string{"read", "write"}, ToolsDeny: []string{"write"}},
+					{ID: "deny-only", Enabled: &enabled, Transport: "stdio", Command: "tool", ToolsDeny: []string{"write"}},
+				},
+			},
+		},
+		Env: map[string]string{},
+	}
+
+	cfg, err := buildCopilotMCPConfig(project)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if tools := cfg.Servers["allow"].Tools; len(tools) != 1 || tools[0] != "read" {
+		t.Fatalf("expected tools [\"read\"], got %v", tools)
+	}
+	if tools := cfg.Servers["deny-only"].Tools; len(tools) != 1 || tools[0] != "*" {
+		t.Fatalf("expected tools [\"*\"] for deny-only filter, got %v", tools)
+	}
+}
+
 func TestBuildCopilotMCPConfigHTTP(t *testing.T) {
 	t.Parallel()
 	enabled := true
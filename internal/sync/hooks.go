@@ -0,0 +1,110 @@
+package sync
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+
+	"github.com/conn-castle/agent-layer/internal/config"
+	"github.com/conn-castle/agent-layer/internal/errcode"
+	"github.com/conn-castle/agent-layer/internal/execguard"
+	"github.com/conn-castle/agent-layer/internal/i18n"
+	"github.com/conn-castle/agent-layer/internal/messages"
+)
+
+// Sync hook phases, named after their [hooks] keys.
+const (
+	HookPreSync  = "pre_sync"
+	HookPostSync = "post_sync"
+)
+
+// runHookCommand runs one hook from the repo root with its output passed
+// through.
+var runHookCommand = func(root string, argv []string, out io.Writer) error {
+	cmd := exec.Command(argv[0], argv[1:]...) // #nosec G204 -- hooks come from the repo's own config.toml and must match commands.allow.
+	cmd.Dir = root
+	cmd.Env = os.Environ()
+	cmd.Stdout = out
+	cmd.Stderr = out
+	return cmd.Run()
+}
+
+// reloadProject reads the project again after pre_sync hooks ran.
+var reloadProject = config.LoadProjectConfig
+
+// withHooks runs sync wrapped in the configured [hooks]. It checks every
+// hook against commands.allow and commands.deny before any runs, runs
+// pre_sync hooks and reloads the project so files they wrote are synced,
+// then syncs and runs post_sync hooks. A failing hook stops the run.
+func withHooks(root string, project *config.ProjectConfig, out io.Writer, sync func(*config.ProjectConfig) (*Result, error)) (*Result, error) {
+	hooks := project.Config.Hooks
+	if err := checkHooks(root, project); err != nil {
+		return nil, err
+	}
+	if len(hooks.PreSync) > 0 {
+		if err := runHooks(root, HookPreSync, hooks.PreSync, out); err != nil {
+			return nil, err
+		}
+		reloaded, err := reloadProject(root)
+		if err != nil {
+			return nil, errcode.Wrap(errcode.Config, err)
+		}
+		project = reloaded
+	}
+	result, err := sync(project)
+	if err != nil {
+		return nil, err
+	}
+	if err := runHooks(root, HookPostSync, hooks.PostSync, out); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// checkHooks rejects any configured hook that commands.allow does not allow
+// or commands.deny denies.
+func checkHooks(root string, project *config.ProjectConfig) error {
+	hooks := project.Config.Hooks
+	if len(hooks.PreSync) == 0 && len(hooks.PostSync) == 0 {
+		return nil
+	}
+	deny, err := execguard.LoadDeny(root)
+	if err != nil {
+		return errcode.Wrap(errcode.Config, err)
+	}
+	for _, phase := range []struct {
+		name  string
+		hooks []string
+	}{{HookPreSync, hooks.PreSync}, {HookPostSync, hooks.PostSync}} {
+		for i, hook := range phase.hooks {
+			argv, err := config.SplitCommandLine(hook)
+			if err != nil {
+				return errcode.Wrap(errcode.Config, i18n.Errorf(messages.SyncHookInvalidFmt, phase.name, i, hook, err))
+			}
+			decision := execguard.EvaluateHook(project.CommandsAllow, deny, argv)
+			if decision.Outcome != execguard.Allowed {
+				return errcode.Wrap(errcode.Config, i18n.Errorf(messages.SyncHookNotAllowedFmt, phase.name, i, hook, decision.Reason))
+			}
+		}
+	}
+	return nil
+}
+
+// runHooks runs hooks in order, stopping at the first failure.
+func runHooks(root string, phase string, hooks []string, out io.Writer) error {
+	if out == nil {
+		out = io.Discard
+	}
+	for _, hook := range hooks {
+		argv, err := config.SplitCommandLine(hook)
+		if err != nil {
+			return errcode.Wrap(errcode.Config, err)
+		}
+		_, _ = fmt.Fprintf(out, i18n.T(messages.SyncHookRunningFmt), phase, hook)
+		if err := runHookCommand(root, argv, out); err != nil {
+			return errcode.Wrap(errcode.Sync, i18n.Errorf(messages.SyncHookFailedFmt, phase, hook, err))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,162 @@
+package sync
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/conn-castle/agent-layer/internal/config"
+	"github.com/conn-castle/agent-layer/internal/errcode"
+)
+
+func stubHooks(t *testing.T, fail string) *[]string {
+	t.Helper()
+	var calls []string
+	originalRun, originalReload := runHookCommand, reloadProject
+	runHookCommand = func(root string, argv []string, out io.Writer) error {
+		line := strings.Join(argv, " ")
+		calls = append(calls, line)
+		if line == fail {
+			return errors.New("exit status 2")
+		}
+		return nil
+	}
+	t.Cleanup(func() { runHookCommand, reloadProject = originalRun, originalReload })
+	return &calls
+}
+
+func TestRunWithProjectOptions_HooksRunAroundSync(t *testing.T) {
+	root, project := loadSyncFixtureProject(t)
+	project.CommandsAllow = append(project.CommandsAllow, "make context", "notify-wrapper")
+	project.Config.Hooks = config.HooksConfig{PreSync: []string{"make context"}, PostSync: []string{"notify-wrapper --synced"}}
+	calls := stubHooks(t, "")
+	reloaded := false
+	reloadProject = func(string) (*config.ProjectConfig, error) {
+		reloaded = true
+		// Only post_sync may see the generated outputs.
+		if _, err := os.Stat(filepath.Join(root, "AGENTS.md")); err == nil {
+			t.Fatal("pre_sync hooks ran after sync wrote outputs")
+		}
+		return project, nil
+	}
+
+	var out bytes.Buffer
+	if _, err := RunWithProjectOptions(RealSystem{}, root, project, RunOptions{HookOutput: &out}); err != nil {
+		t.Fatalf("RunWithProjectOptions: %v", err)
+	}
+	if want := []string{"make context", "notify-wrapper --synced"}; !reflect.DeepEqual(*calls, want) {
+		t.Fatalf("hook calls = %v, want %v", *calls, want)
+	}
+	if !reloaded {
+		t.Fatal("expected project to be reloaded after pre_sync hooks")
+	}
+	if !strings.Contains(out.String(), "Running post_sync hook: notify-wrapper --synced") {
+		t.Fatalf("unexpected hook output:\n%s", out.String())
+	}
+}
+
+func TestRunWithProjectOptions_HooksRejectDisallowedHookBeforeRunningAny(t *testing.T) {
+	root, project := loadSyncFixtureProject(t)
+	project.CommandsAllow = append(project.CommandsAllow, "make context")
+	project.Config.Hooks = config.HooksConfig{PreSync: []string{"make context"}, PostSync: []string{"curl https://example.com"}}
+	calls := stubHooks(t, "")
+
+	_, err := RunWithProjectOptions(RealSystem{}, root, project, RunOptions{})
+	if err == nil || !strings.Contains(err.Error(), `hooks.post_sync[0] "curl https://example.com" cannot run: not in commands.allow`) {
+		t.Fatalf("expected allowlist error, got %v", err)
+	}
+	if errcode.Of(err) != errcode.Config {
+		t.Fatalf("expected config error code, got %s", errcode.Of(err))
+	}
+	if len(*calls) != 0 {
+		t.Fatalf("expected no hooks to run, got %v", *calls)
+	}
+}
+
+func TestRunWithProjectOptions_FailingPreSyncHookStopsSync(t *testing.T) {
+	root, project := loadSyncFixtureProject(t)
+	project.CommandsAllow = append(project.CommandsAllow, "make")
+	project.Config.Hooks = config.HooksConfig{PreSync: []string{"make context", "make other"}, PostSync: []string{"make notify"}}
+	calls := stubHooks(t, "make context")
+
+	_, err := RunWithProjectOptions(RealSystem{}, root, project, RunOptions{})
+	if err == nil || !strings.Contains(err.Error(), `pre_sync hook "make context" failed: exit status 2`) {
+		t.Fatalf("expected hook failure, got %v", err)
+	}
+	if want := []string{"make context"}; !reflect.DeepEqual(*calls, want) {
+		t.Fatalf("hook calls = %v, want %v", *calls, want)
+	}
+	if _, err := os.Stat(filepath.Join(root, "AGENTS.md")); !os.IsNotExist(err) {
+		t.Fatalf("expected sync not to run, got %v", err)
+	}
+}
+
+func TestRunWithProjectOptions_DryRunSkipsHooks(t *testing.T) {
+	root, project := loadSyncFixtureProject(t)
+	project.Config.Hooks = config.HooksConfig{PreSync: []string{"not-allowed"}}
+	calls := stubHooks(t, "")
+
+	if _, err := RunWithProjectOptions(RealSystem{}, root, project, RunOptions{DryRun: true}); err != nil {
+		t.Fatalf("RunWithProjectOptions: %v", err)
+	}
+	if len(*calls) != 0 {
+		t.Fatalf("expected no hooks on a dry run, got %v", *calls)
+	}
+}
+
+func TestRunWithProjectOptions_ScratchRenderSkipsHooks(t *testing.T) {
+	root, project := loadSyncFixtureProject(t)
+	project.Config.Hooks = config.HooksConfig{PreSync: []string{"not-allowed"}}
+	calls := stubHooks(t, "")
+	scratch, _ := loadSyncFixtureProject(t)
+
+	if _, err := RunWithProjectOptions(RealSystem{}, scratch, project, RunOptions{SourceRoot: root}); err != nil {
+		t.Fatalf("RunWithProjectOptions: %v", err)
+	}
+	if len(*calls) != 0 {
+		t.Fatalf("expected no hooks on a scratch render, got %v", *calls)
+	}
+}
+
+func TestRunWithProjectOptions_HooksSplitQuotedArguments(t *testing.T) {
+	root, project := loadSyncFixtureProject(t)
+	project.CommandsAllow = append(project.CommandsAllow, "notify-wrapper")
+	project.Config.Hooks = config.HooksConfig{PostSync: []string{`notify-wrapper --message "outputs synced" 'it''s done'`}}
+	var got []string
+	original := runHookCommand
+	runHookCommand = func(root string, argv []string, out io.Writer) error {
+		got = argv
+		return nil
+	}
+	t.Cleanup(func() { runHookCommand = original })
+
+	if _, err := RunWithProjectOptions(RealSystem{}, root, project, RunOptions{}); err != nil {
+		t.Fatalf("RunWithProjectOptions: %v", err)
+	}
+	if want := []string{"notify-wrapper", "--message", "outputs synced", "its done"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("argv = %q, want %q", got, want)
+	}
+}
+
+func TestRunWithProjectOptions_HooksRejectUnterminatedQuote(t *testing.T) {
+	root, project := loadSyncFixtureProject(t)
+	project.CommandsAllow = append(project.CommandsAllow, "notify-wrapper")
+	project.Config.Hooks = config.HooksConfig{PostSync: []string{`notify-wrapper "synced`}}
+	calls := stubHooks(t, "")
+
+	_, err := RunWithProjectOptions(RealSystem{}, root, project, RunOptions{})
+	if err == nil || !strings.Contains(err.Error(), "unterminated \" quote") {
+		t.Fatalf("expected quoting error, got %v", err)
+	}
+	if errcode.Of(err) != errcode.Config {
+		t.Fatalf("expected config error code, got %s", errcode.Of(err))
+	}
+	if len(*calls) != 0 {
+		t.Fatalf("expected no hooks to run, got %v", *calls)
+	}
+}
@@ -1,16 +1,15 @@
 package sync
 
 import (
-	"fmt"
 	"os"
 	"path/filepath"
-	"strings"
 
 	"github.com/conn-castle/agent-layer/internal/config"
+	"github.com/conn-castle/agent-layer/internal/i18n"
 	"github.com/conn-castle/agent-layer/internal/messages"
 )
 
-const instructionHeader = "<!--\n  GENERATED FILE\n  Source: .agent-layer/instructions/*.md\n  Regenerate: al sync\n-->\n\n"
+const instructionSource = ".agent-layer/instructions/*.md"
 
 // writeInstructionShims generates instruction shims for supported clients.
 // agy (Antigravity), Claude, Codex, Copilot, and other shared-tier clients
@@ -19,53 +18,69 @@ const instructionHeader = "<!--\n  GENERATED FILE\n  Source: .agent-layer/instru
 // retired in 0.10.2 and agy reads AGENTS.md. The v0.10.2 migration's
 // `f-delete-orphan-gemini-md` op removes any leftover GEMINI.md from
 // pre-0.10.2 repos.
-func writeInstructionShims(sys System, root string, instructions []config.InstructionFile) error {
-	if err := writeInstructionFile(sys, filepath.Join(root, "AGENTS.md"), instructions); err != nil {
+//
+// File-glob scoped instructions are appended to AGENTS.md and CLAUDE.md as
+// "applies to" sections; Copilot scopes them natively through
+// .github/instructions/, so copilot-instructions.md leaves them out.
+func writeInstructionShims(sys System, root string, instructions []config.InstructionFile, scoped []config.ScopedInstructions) error {
+	return writeClientInstructionShims(sys, root, clientInstructions{shared: instructions, claude: instructions, copilot: instructions}, scoped)
+}
+
+// clientInstructions holds the instructions each shim renders. They differ
+// only when [variants.clients] selects other variants for Claude or Copilot.
+type clientInstructions struct {
+	shared  []config.InstructionFile
+	claude  []config.InstructionFile
+	copilot []config.InstructionFile
+}
+
+// writeClientInstructionShims is writeInstructionShims with per-client
+// instruction variants. Each distinct instruction set is composed once and
+// every shim renders from that composition.
+func writeClientInstructionShims(sys System, root string, instructions clientInstructions, scoped []config.ScopedInstructions) error {
+	composer := newInstructionComposer(scoped)
+	if err := writeGeneratedFile(sys, filepath.Join(root, "AGENTS.md"), composer.document(instructions.shared, true), 0o644); err != nil {
 		return err
 	}
-	if err := writeInstructionFile(sys, filepath.Join(root, "CLAUDE.md"), instructions); err != nil {
+	if err := writeGeneratedFile(sys, filepath.Join(root, "CLAUDE.md"), composer.document(instructions.claude, true), 0o644); err != nil {
 		return err
 	}
 
 	githubDir := filepath.Join(root, ".github")
 	if err := sys.MkdirAll(githubDir, 0o755); err != nil {
-		return fmt.Errorf(messages.SyncCreateDirFailedFmt, githubDir, err)
+		return i18n.Errorf(messages.SyncCreateDirFailedFmt, githubDir, err)
 	}
-	if err := writeInstructionFile(sys, filepath.Join(githubDir, "copilot-instructions.md"), instructions); err != nil {
+	if err := writeGeneratedFile(sys, filepath.Join(githubDir, "copilot-instructions.md"), composer.document(instructions.copilot, false), 0o644); err != nil {
 		return err
 	}
 
-	return nil
+	return writeCopilotPathInstructions(sys, root, scoped)
 }
 
-func writeInstructionFile(sys System, path string, instructions []config.InstructionFile) error {
-	content := buildInstructionShim(instructions)
-	if err := sys.WriteFileAtomic(path, []byte(content), 0o644); err != nil {
-		return fmt.Errorf(messages.SyncWriteFileFailedFmt, path, err)
-	}
-	return nil
+// InstructionDocument returns the composed instructions sync writes to
+// AGENTS.md and CLAUDE.md, including fallback sections for file-glob scoped
+// instructions.
+func InstructionDocument(instructions []config.InstructionFile, scoped []config.ScopedInstructions) string {
+	return newInstructionComposer(scoped).document(instructions, true)
 }
 
 func buildInstructionShim(instructions []config.InstructionFile) string {
+	return buildGeneratedInstructions(instructionSource, instructions)
+}
+
+// buildGeneratedInstructions renders instructions under a provenance header
+// naming source. No instructions render as an empty file.
+func buildGeneratedInstructions(source string, instructions []config.InstructionFile) string {
 	if len(instructions) == 0 {
 		return ""
 	}
-	var builder strings.Builder
-	builder.WriteString(instructionHeader)
-	for _, instruction := range instructions {
-		builder.WriteString("<!-- BEGIN: ")
-		builder.WriteString(instruction.Name)
-		builder.WriteString(" -->\n")
-		content := instruction.Content
-		builder.WriteString(content)
-		if !strings.HasSuffix(content, "\n") {
-			builder.WriteString("\n")
-		}
-		builder.WriteString("<!-- END: ")
-		builder.WriteString(instruction.Name)
-		builder.WriteString(" -->\n\n")
-	}
-	return strings.TrimRight(builder.String(), "\n") + "\n"
+	return sealGeneratedContent(composeGeneratedInstructions(source, instructions))
+}
+
+// composeGeneratedInstructions is buildGeneratedInstructions before the
+// content hash is sealed, for callers that prepend front matter.
+func composeGeneratedInstructions(source string, instructions []config.InstructionFile) string {
+	return composeInstructions(instructions).render(source)
 }
 
 // cleanCodexInstructions removes the retired Codex-specific instruction shim.
@@ -82,7 +97,7 @@ func cleanCodexInstructions(sys System, root string) error {
 		return nil
 	}
 	if err := sys.Remove(path); err != nil && !os.IsNotExist(err) {
-		return fmt.Errorf(messages.SyncRemoveFailedFmt, path, err)
+		return i18n.Errorf(messages.SyncRemoveFailedFmt, path, err)
 	}
 	return nil
 }
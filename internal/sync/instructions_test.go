@@ -28,7 +28,7 @@ func TestWriteInstructionShims(t *testing.T) {
 	t.Parallel()
 	root := t.TempDir()
 	instructions := []config.InstructionFile{{Name: "00_base.md", Content: "base\n"}}
-	if err := writeInstructionShims(RealSystem{}, root, instructions); err != nil {
+	if err := writeInstructionShims(RealSystem{}, root, instructions, nil); err != nil {
 		t.Fatalf("writeInstructionShims error: %v", err)
 	}
 
@@ -102,7 +102,7 @@ func TestWriteInstructionShimsError(t *testing.T) {
 		t.Fatalf("write file: %v", err)
 	}
 	instructions := []config.InstructionFile{{Name: "00_base.md", Content: "base\n"}}
-	if err := writeInstructionShims(RealSystem{}, file, instructions); err == nil {
+	if err := writeInstructionShims(RealSystem{}, file, instructions, nil); err == nil {
 		t.Fatalf("expected error")
 	}
 }
@@ -150,7 +150,7 @@ func TestWriteInstructionShimsErrorPaths(t *testing.T) {
 			if err := tc.setup(root); err != nil {
 				t.Fatalf("setup: %v", err)
 			}
-			if err := writeInstructionShims(RealSystem{}, root, instructions); err == nil {
+			if err := writeInstructionShims(RealSystem{}, root, instructions, nil); err == nil {
 				t.Fatalf("expected error")
 			}
 		})
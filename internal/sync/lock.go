@@ -10,7 +10,9 @@ import (
 
 	"golang.org/x/sys/unix"
 
+	"github.com/conn-castle/agent-layer/internal/i18n"
 	"github.com/conn-castle/agent-layer/internal/install"
+	"github.com/conn-castle/agent-layer/internal/layerdir"
 	"github.com/conn-castle/agent-layer/internal/messages"
 )
 
@@ -47,7 +49,7 @@ type projectSyncLock struct {
 }
 
 func withProjectSyncLock(sys System, root string, fn func() (*Result, error)) (result *Result, err error) {
-	lockPath := filepath.Join(root, ".agent-layer", projectSyncLockFile)
+	lockPath := filepath.Join(layerdir.Dir(root), projectSyncLockFile)
 	processLock := processLockForSyncPath(lockPath)
 	deadline := sys.Now().Add(projectSyncLockWaitTimeout)
 	if err := processLock.acquire(sys, deadline); err != nil {
@@ -106,7 +108,7 @@ func (l *projectSyncProcessLock) release() {
 func acquireProjectSyncLock(sys System, path string, processLock *projectSyncProcessLock, deadline time.Time) (*projectSyncLock, error) {
 	file, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0o644) // #nosec G304,G302 -- lock path is rooted under the caller-resolved repo's .agent-layer directory; 0o644 lets a lock file (no sensitive data) be opened by other users/CI runners, matching internal/dispatch/lock.go.
 	if err != nil {
-		return nil, fmt.Errorf(messages.SyncOpenLockFmt, path, err)
+		return nil, i18n.Errorf(messages.SyncOpenLockFmt, path, err)
 	}
 	if err := lockProjectSyncFile(sys, file, deadline); err != nil {
 		closeErr := sys.Close(file)
@@ -118,9 +120,9 @@ func acquireProjectSyncLock(sys System, path string, processLock *projectSyncPro
 			return nil, timeoutErr
 		}
 		if closeErr != nil {
-			return nil, fmt.Errorf(messages.SyncLockFmt, path, fmt.Errorf("%w; acquisition cleanup failed: %v", err, closeErr))
+			return nil, i18n.Errorf(messages.SyncLockFmt, path, fmt.Errorf("%w; acquisition cleanup failed: %v", err, closeErr))
 		}
-		return nil, fmt.Errorf(messages.SyncLockFmt, path, err)
+		return nil, i18n.Errorf(messages.SyncLockFmt, path, err)
 	}
 	return &projectSyncLock{file: file, path: path, sys: sys, processLock: processLock}, nil
 }
@@ -160,19 +162,19 @@ func (l *projectSyncLock) release() error {
 	closeErr := l.sys.Close(l.file)
 	if unlockErr != nil && closeErr != nil {
 		return errors.Join(
-			fmt.Errorf(messages.SyncUnlockFmt, l.path, unlockErr),
-			fmt.Errorf(messages.SyncCloseLockFmt, l.path, closeErr),
+			i18n.Errorf(messages.SyncUnlockFmt, l.path, unlockErr),
+			i18n.Errorf(messages.SyncCloseLockFmt, l.path, closeErr),
 		)
 	}
 	if unlockErr != nil {
-		return fmt.Errorf(messages.SyncUnlockFmt, l.path, unlockErr)
+		return i18n.Errorf(messages.SyncUnlockFmt, l.path, unlockErr)
 	}
 	if closeErr != nil {
-		return fmt.Errorf(messages.SyncCloseLockFmt, l.path, closeErr)
+		return i18n.Errorf(messages.SyncCloseLockFmt, l.path, closeErr)
 	}
 	return nil
 }
 
 func syncLockTimeoutError(path string) error {
-	return fmt.Errorf(messages.SyncLockTimeoutFmt, projectSyncLockWaitTimeout, path)
+	return i18n.Errorf(messages.SyncLockTimeoutFmt, projectSyncLockWaitTimeout, path)
 }
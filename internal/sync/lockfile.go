@@ -0,0 +1,68 @@
+package sync
+
+import (
+	"github.com/conn-castle/agent-layer/internal/config"
+	"github.com/conn-castle/agent-layer/internal/i18n"
+	"github.com/conn-castle/agent-layer/internal/lockfile"
+	"github.com/conn-castle/agent-layer/internal/mcppin"
+	"github.com/conn-castle/agent-layer/internal/messages"
+	"github.com/conn-castle/agent-layer/internal/remotebase"
+)
+
+// recordExtendsLock keeps the [extends] entry in al.lock in step with
+// config.toml. A new or changed extends source records the checksum of the
+// bundle sync just used; removing extends drops the entry. The lock is only
+// written when the entry changes, through sys like every other sync write.
+func recordExtendsLock(sys System, root string, project *config.ProjectConfig) error {
+	lock, err := lockfile.Load(root)
+	if err != nil {
+		return err
+	}
+	source := project.Config.Extends
+	if source == "" || project.ExtendsDir == "" {
+		if lock.Extends == nil {
+			return nil
+		}
+		lock.Extends = nil
+		return saveLock(sys, root, lock)
+	}
+	checksum, err := remotebase.Checksum(project.ExtendsDir)
+	if err != nil {
+		return err
+	}
+	if lock.Extends != nil && lock.Extends.Source == source && lock.Extends.Checksum == checksum {
+		return nil
+	}
+	lock.Extends = &lockfile.Extends{Source: source, Checksum: checksum}
+	return saveLock(sys, root, lock)
+}
+
+// pinMCPServers returns project with every npx or uvx MCP server that has a
+// current al.lock entry rewritten to its pinned, cache-preferring launch, so
+// client configs do not resolve package versions at agent startup. project is
+// returned unchanged when nothing is pinned.
+func pinMCPServers(root string, project *config.ProjectConfig) (*config.ProjectConfig, error) {
+	lock, err := lockfile.Load(root)
+	if err != nil {
+		return nil, err
+	}
+	servers, changed := mcppin.Apply(project.Config.MCP.Servers, lock.MCPServers)
+	if !changed {
+		return project, nil
+	}
+	pinned := *project
+	pinned.Config.MCP.Servers = servers
+	return &pinned, nil
+}
+
+func saveLock(sys System, root string, lock lockfile.File) error {
+	path := lockfile.Path(root)
+	data, err := lockfile.Encode(lock)
+	if err != nil {
+		return i18n.Errorf(messages.LockfileWriteFailedFmt, path, err)
+	}
+	if err := sys.WriteFileAtomic(path, data, 0o644); err != nil {
+		return i18n.Errorf(messages.LockfileWriteFailedFmt, path, err)
+	}
+	return nil
+}
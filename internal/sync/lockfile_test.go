@@ -0,0 +1,103 @@
+package sync
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/conn-castle/agent-layer/internal/config"
+	"github.com/conn-castle/agent-layer/internal/lockfile"
+)
+
+func TestRecordExtendsLock(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, ".agent-layer"), 0o700); err != nil {
+		t.Fatal(err)
+	}
+
+	// No extends and no lock: nothing is written.
+	if err := recordExtendsLock(RealSystem{}, root, &config.ProjectConfig{}); err != nil {
+		t.Fatalf("recordExtendsLock: %v", err)
+	}
+	if _, err := os.Stat(lockfile.Path(root)); !os.IsNotExist(err) {
+		t.Fatalf("expected no al.lock, got %v", err)
+	}
+
+	base := t.TempDir()
+	if err := os.WriteFile(filepath.Join(base, "config.toml"), []byte("[approvals]\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	project := &config.ProjectConfig{ExtendsDir: base}
+	project.Config.Extends = "github.com/org/base@v1"
+	if err := recordExtendsLock(RealSystem{}, root, project); err != nil {
+		t.Fatalf("recordExtendsLock: %v", err)
+	}
+	lock, err := lockfile.Load(root)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if lock.Extends == nil || lock.Extends.Source != "github.com/org/base@v1" || lock.Extends.Checksum == "" {
+		t.Fatalf("extends entry = %+v", lock.Extends)
+	}
+
+	info, err := os.Stat(lockfile.Path(root))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := recordExtendsLock(RealSystem{}, root, project); err != nil {
+		t.Fatalf("recordExtendsLock: %v", err)
+	}
+	again, err := os.Stat(lockfile.Path(root))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !again.ModTime().Equal(info.ModTime()) {
+		t.Fatal("expected unchanged entry not to rewrite al.lock")
+	}
+
+	if err := recordExtendsLock(RealSystem{}, root, &config.ProjectConfig{}); err != nil {
+		t.Fatalf("recordExtendsLock: %v", err)
+	}
+	if lock, _ := lockfile.Load(root); lock.Extends != nil {
+		t.Fatalf("expected extends entry to be dropped, got %+v", lock.Extends)
+	}
+}
+
+func TestPinMCPServers(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, ".agent-layer"), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	enabled := true
+	project := &config.ProjectConfig{}
+	project.Config.MCP.Servers = []config.MCPServer{
+		{ID: "playwright", Enabled: &enabled, Transport: "stdio", Command: "npx", Args: []string{"-y", "@playwright/mcp@latest"}},
+	}
+
+	// No lock: the project is used as configured.
+	unpinned, err := pinMCPServers(root, project)
+	if err != nil {
+		t.Fatalf("pinMCPServers: %v", err)
+	}
+	if unpinned != project {
+		t.Fatal("expected project to be returned unchanged without al.lock")
+	}
+
+	var lock lockfile.File
+	lock.SetMCPServer(lockfile.MCPServer{ID: "playwright", Runner: "npx", Package: "@playwright/mcp", Requested: "latest", Version: "0.0.68"})
+	if err := lockfile.Save(root, lock); err != nil {
+		t.Fatal(err)
+	}
+	pinned, err := pinMCPServers(root, project)
+	if err != nil {
+		t.Fatalf("pinMCPServers: %v", err)
+	}
+	got := strings.Join(pinned.Config.MCP.Servers[0].Args, " ")
+	if got != "--prefer-offline -y @playwright/mcp@0.0.68" {
+		t.Fatalf("pinned args = %q", got)
+	}
+	if project.Config.MCP.Servers[0].Args[1] != "@playwright/mcp@latest" {
+		t.Fatalf("configured project was modified: %v", project.Config.MCP.Servers[0].Args)
+	}
+}
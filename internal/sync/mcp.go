@@ -1,10 +1,10 @@
 package sync
 
 import (
-	"fmt"
 	"path/filepath"
 
 	"github.com/conn-castle/agent-layer/internal/config"
+	"github.com/conn-castle/agent-layer/internal/i18n"
 	"github.com/conn-castle/agent-layer/internal/messages"
 	"github.com/conn-castle/agent-layer/internal/projection"
 )
@@ -32,13 +32,13 @@ func writeMCPConfig(sys System, root string, project *config.ProjectConfig) erro
 
 	data, err := sys.MarshalIndent(cfg, "", "  ")
 	if err != nil {
-		return fmt.Errorf(messages.SyncMarshalMCPConfigFailedFmt, err)
+		return i18n.Errorf(messages.SyncMarshalMCPConfigFailedFmt, err)
 	}
 	data = append(data, '\n')
 
 	path := filepath.Join(root, ".mcp.json")
 	if err := sys.WriteFileAtomic(path, data, 0o644); err != nil {
-		return fmt.Errorf(messages.SyncWriteFileFailedFmt, path, err)
+		return i18n.Errorf(messages.SyncWriteFileFailedFmt, path, err)
 	}
 
 	return nil
@@ -51,7 +51,7 @@ func buildMCPConfig(project *config.ProjectConfig) (*mcpConfig, error) {
 	}
 
 	resolved, err := projection.ResolveMCPServers(
-		project.Config.MCP.Servers,
+		clientMCPServers(project, "claude"),
 		project.Env,
 		"claude",
 		projection.ClientPlaceholderResolver("${%s}"),
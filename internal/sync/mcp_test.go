@@ -4,9 +4,11 @@ import (
 	"errors"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/conn-castle/agent-layer/internal/config"
+	"github.com/conn-castle/agent-layer/internal/projection"
 )
 
 func TestBuildMCPConfig(t *testing.T) {
@@ -51,6 +53,44 @@ func TestBuildMCPConfig(t *testing.T) {
 	}
 }
 
+func TestBuildMCPConfigGateway(t *testing.T) {
+	t.Parallel()
+	enabled := true
+	project := &config.ProjectConfig{
+		Config: config.Config{
+			MCP: config.MCPConfig{
+				Gateway: &enabled,
+				Servers: []config.MCPServer{
+					{
+						ID:        "example",
+						Enabled:   &enabled,
+						Transport: "http",
+						URL:       "https://example.com",
+						Headers:   map[string]string{"Authorization": "Bearer ${TOKEN}"},
+					},
+				},
+			},
+		},
+		Env:  map[string]string{"TOKEN": "abc"},
+		Root: t.TempDir(),
+	}
+
+	cfg, err := buildMCPConfig(project)
+	if err != nil {
+		t.Fatalf("buildMCPConfig error: %v", err)
+	}
+	if len(cfg.Servers) != 1 {
+		t.Fatalf("expected only the gateway server, got %v", cfg.Servers)
+	}
+	gateway, ok := cfg.Servers[projection.GatewayServerID]
+	if !ok {
+		t.Fatalf("expected %s server, got %v", projection.GatewayServerID, cfg.Servers)
+	}
+	if gateway.Type != "stdio" || gateway.Command != "al" || strings.Join(gateway.Args, " ") != "mcp gateway --client claude" {
+		t.Fatalf("unexpected gateway server: %+v", gateway)
+	}
+}
+
 func TestWriteMCPConfig(t *testing.T) {
 	t.Parallel()
 	root := t.TempDir()
@@ -12,7 +12,9 @@ type MockSystem struct {
 	StatFunc            func(name string) (os.FileInfo, error)
 	LstatFunc           func(name string) (os.FileInfo, error)
 	ReadlinkFunc        func(name string) (string, error)
+	SymlinkFunc         func(oldname string, newname string) error
 	MkdirAllFunc        func(path string, perm os.FileMode) error
+	ChmodFunc           func(name string, mode os.FileMode) error
 	WriteFileAtomicFunc func(filename string, data []byte, perm os.FileMode) error
 	MarshalIndentFunc   func(v any, prefix, indent string) ([]byte, error)
 	ReadFileFunc        func(name string) ([]byte, error)
@@ -65,6 +67,26 @@ func (m *MockSystem) Readlink(name string) (string, error) {
 	return "", os.ErrNotExist
 }
 
+func (m *MockSystem) Symlink(oldname string, newname string) error {
+	if m.SymlinkFunc != nil {
+		return m.SymlinkFunc(oldname, newname)
+	}
+	if m.Fallback != nil {
+		return m.Fallback.Symlink(oldname, newname)
+	}
+	return errors.New("mock system Symlink not implemented")
+}
+
+func (m *MockSystem) Chmod(name string, mode os.FileMode) error {
+	if m.ChmodFunc != nil {
+		return m.ChmodFunc(name, mode)
+	}
+	if m.Fallback != nil {
+		return m.Fallback.Chmod(name, mode)
+	}
+	return errors.New("mock system Chmod not implemented")
+}
+
 func (m *MockSystem) MkdirAll(path string, perm os.FileMode) error {
 	if m.MkdirAllFunc != nil {
 		return m.MkdirAllFunc(path, perm)
@@ -0,0 +1,109 @@
+package sync
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/conn-castle/agent-layer/internal/i18n"
+	"github.com/conn-castle/agent-layer/internal/messages"
+)
+
+// readNativeSettings loads and validates a client-owned settings.json for
+// merging; label names the client in errors. A missing, empty, or
+// whitespace-only file yields a fresh empty object because there is no native
+// state to preserve. It rejects a symlink or non-regular target, malformed or non-object JSON, and trailing
+// data so a corrupt native file fails loud before any write, and uses a
+// number-preserving decoder so large or high-precision native numbers survive
+// the round trip.
+func readNativeSettings(sys System, path string, label string) (map[string]any, error) {
+	info, err := sys.Lstat(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return make(map[string]any), nil
+		}
+		return nil, i18n.Errorf(messages.InstallFailedStatFmt, path, err)
+	}
+	if info.Mode()&os.ModeSymlink != 0 || !info.Mode().IsRegular() {
+		return nil, fmt.Errorf("%s settings must be a regular file, not a symlink or special file: %s", label, path)
+	}
+	data, err := sys.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read %s settings %s: %w", label, path, err)
+	}
+	if len(bytes.TrimSpace(data)) == 0 {
+		// An empty or whitespace-only file holds no native state to preserve;
+		// treat it like a missing file so a truncated or editor-created empty
+		// file does not fail the whole sync.
+		return make(map[string]any), nil
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var settings map[string]any
+	if err := decoder.Decode(&settings); err != nil {
+		return nil, fmt.Errorf("decode %s settings %s: %w", label, path, err)
+	}
+	if settings == nil {
+		return nil, fmt.Errorf("decode %s settings %s: top-level JSON value must be an object", label, path)
+	}
+	var trailing any
+	if err := decoder.Decode(&trailing); err != io.EOF {
+		if err == nil {
+			return nil, fmt.Errorf("decode %s settings %s: trailing JSON value", label, path)
+		}
+		return nil, fmt.Errorf("decode %s settings %s: trailing data: %w", label, path, err)
+	}
+	return settings, nil
+}
+
+// overlayManagedSettings recursively overlays the managed desired map onto
+// target, preserving native sibling keys. Nested objects are merged; scalar and
+// array values replace the value at their key. It returns an error when a
+// desired object would overwrite a native scalar or vice versa, so Agent Layer
+// never silently reshapes native state. prefix carries the path walked so far
+// for error messages.
+func overlayManagedSettings(target, desired map[string]any, prefix []string) error {
+	for key, value := range desired {
+		path := append(append([]string{}, prefix...), key)
+		if desiredMap, ok := value.(map[string]any); ok {
+			current, exists := target[key]
+			if !exists {
+				current = make(map[string]any)
+				target[key] = current
+			}
+			targetMap, ok := current.(map[string]any)
+			if !ok {
+				return fmt.Errorf("managed path %s requires an object", strings.Join(path, "."))
+			}
+			if err := overlayManagedSettings(targetMap, desiredMap, path); err != nil {
+				return err
+			}
+			continue
+		}
+		if current, exists := target[key]; exists && managedSettingsShapeConflict(current, value) {
+			return fmt.Errorf("managed path %s has incompatible existing shape", strings.Join(path, "."))
+		}
+		target[key] = cloneAgentSpecificValue(value)
+	}
+	return nil
+}
+
+// managedSettingsShapeConflict reports whether current and desired disagree on
+// being a JSON object, which the overlay treats as an incompatible managed-path shape.
+func managedSettingsShapeConflict(current, desired any) bool {
+	_, currentObject := current.(map[string]any)
+	_, desiredObject := desired.(map[string]any)
+	return currentObject != desiredObject
+}
+
+// nativeSettingsFileMode returns the permission bits of the existing regular
+// file at path, or fallback when there is none.
+func nativeSettingsFileMode(sys System, path string, fallback os.FileMode) os.FileMode {
+	if info, err := sys.Lstat(path); err == nil && info.Mode().IsRegular() {
+		return info.Mode().Perm()
+	}
+	return fallback
+}
@@ -0,0 +1,132 @@
+package sync
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/conn-castle/agent-layer/internal/config"
+	"github.com/conn-castle/agent-layer/internal/i18n"
+	"github.com/conn-castle/agent-layer/internal/messages"
+)
+
+const (
+	scopedInstructionSourceDir = ".agent-layer/scoped/"
+	// copilotPathInstructionPrefix and copilotPathInstructionSuffix frame the
+	// names of generated .github/instructions/ files so stale ones can be
+	// found without touching hand-written instructions.
+	copilotPathInstructionPrefix = "al-"
+	copilotPathInstructionSuffix = ".instructions.md"
+)
+
+var pathSlugPattern = regexp.MustCompile(`[^a-z0-9]+`)
+
+// writeCopilotPathInstructions renders each scoped instruction set into
+// .github/instructions/al-<slug>.instructions.md with an applyTo glob, and
+// removes generated files whose directory or glob no longer exists.
+func writeCopilotPathInstructions(sys System, root string, scoped []config.ScopedInstructions) error {
+	dir := filepath.Join(root, ".github", "instructions")
+	wanted := make(map[string]string, len(scoped))
+	for name, scoped := range copilotPathInstructionNames(scoped) {
+		wanted[name] = buildCopilotPathInstructions(scoped)
+	}
+	if len(wanted) > 0 {
+		if err := sys.MkdirAll(dir, 0o755); err != nil {
+			return i18n.Errorf(messages.SyncCreateDirFailedFmt, dir, err)
+		}
+	}
+	names := make([]string, 0, len(wanted))
+	for name := range wanted {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if err := writeGeneratedFile(sys, filepath.Join(dir, name), wanted[name], 0o644); err != nil {
+			return err
+		}
+	}
+
+	entries, err := sys.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return i18n.Errorf(messages.SyncReadFailedFmt, dir, err)
+	}
+	var stale []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, copilotPathInstructionPrefix) || !strings.HasSuffix(name, copilotPathInstructionSuffix) {
+			continue
+		}
+		if _, ok := wanted[name]; !ok {
+			stale = append(stale, name)
+		}
+	}
+	return removeGeneratedFiles(sys, dir, stale)
+}
+
+// copilotPathInstructionNames assigns each applyTo glob a stable file name.
+// Globs that slug to the same name are numbered in glob order.
+func copilotPathInstructionNames(scoped []config.ScopedInstructions) map[string]config.ScopedInstructions {
+	sorted := append([]config.ScopedInstructions(nil), scoped...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].ApplyTo() < sorted[j].ApplyTo() })
+	names := make(map[string]config.ScopedInstructions, len(sorted))
+	for _, scoped := range sorted {
+		slug := strings.Trim(pathSlugPattern.ReplaceAllString(strings.ToLower(scoped.ApplyTo()), "-"), "-")
+		if slug == "" {
+			slug = "all"
+		}
+		name := copilotPathInstructionPrefix + slug + copilotPathInstructionSuffix
+		for n := 2; ; n++ {
+			if _, taken := names[name]; !taken {
+				break
+			}
+			name = copilotPathInstructionPrefix + slug + "-" + strconv.Itoa(n) + copilotPathInstructionSuffix
+		}
+		names[name] = scoped
+	}
+	return names
+}
+
+func buildCopilotPathInstructions(scoped config.ScopedInstructions) string {
+	var builder strings.Builder
+	builder.WriteString("---\napplyTo: ")
+	builder.WriteString(strconv.Quote(scoped.ApplyTo()))
+	builder.WriteString("\n---\n")
+	builder.WriteString(composeGeneratedInstructions(scopedInstructionSource(scoped), scoped.Files))
+	return sealGeneratedContent(builder.String())
+}
+
+func scopedInstructionSource(scoped config.ScopedInstructions) string {
+	return scopedInstructionSourceDir + scoped.Path() + "/*.md"
+}
+
+// pathInstructionFallbacks returns the file-glob instruction sets as
+// "applies to" sections for the root AGENTS.md and CLAUDE.md, which have no
+// native way to scope guidance to files. Directories are left out: they reach
+// clients as nested AGENTS.md and CLAUDE.md files instead.
+func pathInstructionFallbacks(scoped []config.ScopedInstructions) []config.InstructionFile {
+	var out []config.InstructionFile
+	for _, scoped := range scoped {
+		if scoped.Glob == "" || len(scoped.Files) == 0 {
+			continue
+		}
+		var builder strings.Builder
+		builder.WriteString("## Applies to paths matching `")
+		builder.WriteString(scoped.Glob)
+		builder.WriteString("`\n\nFollow these instructions only when working on files that match `")
+		builder.WriteString(scoped.Glob)
+		builder.WriteString("`.\n")
+		for _, file := range scoped.Files {
+			builder.WriteString("\n")
+			builder.WriteString(strings.TrimRight(file.Content, "\n"))
+			builder.WriteString("\n")
+		}
+		out = append(out, config.InstructionFile{Name: "scoped/" + scoped.Glob, Content: builder.String()})
+	}
+	return out
+}
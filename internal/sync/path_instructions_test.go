@@ -0,0 +1,114 @@
+package sync
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/conn-castle/agent-layer/internal/config"
+)
+
+func pathScopedProject() *config.ProjectConfig {
+	return &config.ProjectConfig{
+		Instructions: []config.InstructionFile{{Name: "00_base.md", Content: "base"}},
+		ScopedInstructions: []config.ScopedInstructions{
+			{Glob: "**/*_test.go", Files: []config.InstructionFile{{Name: "00.md", Content: "use table tests"}}},
+			{Dir: "services", Files: []config.InstructionFile{{Name: "00.md", Content: "services dir"}}},
+			{Dir: "web", Files: []config.InstructionFile{{Name: "00.md", Content: "web dir"}}},
+		},
+	}
+}
+
+func TestWritePathScopedInstructions(t *testing.T) {
+	root := t.TempDir()
+	mkdirs(t, root, "services", "web")
+	stubSparseCheckout(t, nil, false)
+	project := pathScopedProject()
+
+	if err := writeInstructionShims(RealSystem{}, root, project.Instructions, project.ScopedInstructions); err != nil {
+		t.Fatalf("writeInstructionShims: %v", err)
+	}
+	if err := writeScopedInstructions(RealSystem{}, root, project); err != nil {
+		t.Fatalf("writeScopedInstructions: %v", err)
+	}
+
+	agents := readFile(t, filepath.Join(root, "AGENTS.md"))
+	if !strings.Contains(agents, "## Applies to paths matching `**/*_test.go`") || !strings.Contains(agents, "use table tests") {
+		t.Fatalf("AGENTS.md missing fallback section:\n%s", agents)
+	}
+	if strings.Contains(agents, "web dir") {
+		t.Fatalf("directories must not be inlined:\n%s", agents)
+	}
+	if copilot := readFile(t, filepath.Join(root, ".github", "copilot-instructions.md")); strings.Contains(copilot, "use table tests") {
+		t.Fatalf("copilot-instructions.md should leave file globs to applyTo:\n%s", copilot)
+	}
+
+	tests := readFile(t, filepath.Join(root, ".github", "instructions", "al-test-go.instructions.md"))
+	if !strings.HasPrefix(tests, "---\napplyTo: \"**/*_test.go\"\n---\n<!--\n") || !strings.Contains(tests, "use table tests") {
+		t.Fatalf("unexpected copilot path instructions:\n%s", tests)
+	}
+	if generatedContentEdited(tests) {
+		t.Fatal("copilot path instructions hash does not verify")
+	}
+	if web := readFile(t, filepath.Join(root, ".github", "instructions", "al-web.instructions.md")); !strings.HasPrefix(web, "---\napplyTo: \"web/**\"\n---\n") {
+		t.Fatalf("unexpected copilot directory instructions:\n%s", web)
+	}
+
+	services := readFile(t, filepath.Join(root, "services", "CLAUDE.md"))
+	if !strings.Contains(services, "services dir") || !strings.Contains(services, "Source: .agent-layer/scoped/services/*.md") {
+		t.Fatalf("unexpected services/CLAUDE.md:\n%s", services)
+	}
+	if _, err := os.Stat(filepath.Join(root, "**")); !os.IsNotExist(err) {
+		t.Fatalf("globs must not create nested files, got %v", err)
+	}
+
+	handWritten := filepath.Join(root, ".github", "instructions", "al-notes.instructions.md")
+	if err := os.WriteFile(handWritten, []byte("mine"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := writeInstructionShims(RealSystem{}, root, project.Instructions, project.ScopedInstructions[1:]); err != nil {
+		t.Fatalf("writeInstructionShims: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(root, ".github", "instructions", "al-test-go.instructions.md")); !os.IsNotExist(err) {
+		t.Fatalf("expected stale generated file removed, got %v", err)
+	}
+	if readFile(t, handWritten) != "mine" {
+		t.Fatal("hand-written instructions file changed")
+	}
+}
+
+func TestCopilotPathInstructionNames(t *testing.T) {
+	names := copilotPathInstructionNames([]config.ScopedInstructions{{Glob: "src/**.ts"}, {Glob: "src/*.ts"}, {Glob: "**"}})
+	for _, want := range []string{"al-src-ts.instructions.md", "al-src-ts-2.instructions.md", "al-all.instructions.md"} {
+		if _, ok := names[want]; !ok {
+			t.Fatalf("missing %s in %v", want, names)
+		}
+	}
+	if names["al-src-ts.instructions.md"].Glob != "src/**.ts" {
+		t.Fatalf("numbering is not in glob order: %v", names)
+	}
+}
+
+func TestCollectDegradations_FileGlobInstructions(t *testing.T) {
+	project := pathScopedProject()
+	enabled := true
+	project.Config.Agents.Claude.Enabled = &enabled
+	project.Config.Agents.VSCode.Enabled = &enabled
+	var got []string
+	for _, degradation := range collectDegradations(project) {
+		got = append(got, degradation.String())
+	}
+	if len(got) != 1 || !strings.HasPrefix(got[0], "claude: file-glob scoped instructions") {
+		t.Fatalf("degradations = %v", got)
+	}
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read %s: %v", path, err)
+	}
+	return string(data)
+}
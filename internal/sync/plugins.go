@@ -0,0 +1,83 @@
+package sync
+
+import (
+	"os"
+	"strings"
+
+	"github.com/conn-castle/agent-layer/internal/config"
+	"github.com/conn-castle/agent-layer/internal/errcode"
+	"github.com/conn-castle/agent-layer/internal/execguard"
+	"github.com/conn-castle/agent-layer/internal/i18n"
+	"github.com/conn-castle/agent-layer/internal/messages"
+	"github.com/conn-castle/agent-layer/internal/plugin"
+)
+
+// renderPlugin runs a renderer plugin; tests replace it.
+var renderPlugin = plugin.Render
+
+// lookupPlugin resolves a plugin executable; tests replace it.
+var lookupPlugin = plugin.Lookup
+
+// pluginRenderer renders a client through an external plugin listed in
+// plugins.renderers, using the JSON contract in package plugin.
+type pluginRenderer struct {
+	name string
+	path string
+}
+
+func (r pluginRenderer) Client() string { return r.name }
+
+func (r pluginRenderer) Render(in Inputs) ([]Artifact, error) {
+	req, err := plugin.NewRequest(r.name, in.Root, in.Project)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := renderPlugin(r.path, in.Root, req)
+	if err != nil {
+		return nil, err
+	}
+	artifacts := make([]Artifact, 0, len(resp.Artifacts))
+	for _, artifact := range resp.Artifacts {
+		perm := os.FileMode(0o644)
+		if artifact.Executable {
+			perm = 0o755
+		}
+		artifacts = append(artifacts, Artifact{Path: artifact.Path, Content: []byte(artifact.Content), Perm: perm})
+	}
+	return artifacts, nil
+}
+
+// projectRenderers returns the registered renderers followed by the plugins
+// in plugins.renderers. Like [hooks], a plugin runs without a prompt, so its
+// command must match commands.allow and no commands.deny prefix.
+func projectRenderers(root string, project *config.ProjectConfig) ([]Renderer, error) {
+	renderers := registeredRenderers()
+	plugins := project.Config.Plugins
+	if len(plugins.Renderers) == 0 {
+		return renderers, nil
+	}
+	deny, err := execguard.LoadDeny(root)
+	if err != nil {
+		return nil, errcode.Wrap(errcode.Config, err)
+	}
+	registered := make(map[string]bool, len(renderers))
+	for _, r := range renderers {
+		registered[r.Client()] = true
+	}
+	for _, name := range plugins.Renderers {
+		if registered[name] {
+			return nil, errcode.Wrap(errcode.Config, i18n.Errorf(messages.PluginRendererDuplicateFmt, name, name))
+		}
+		command := plugin.Command(name, plugins.Paths)
+		decision := execguard.EvaluateHook(project.CommandsAllow, deny, strings.Fields(command))
+		if decision.Outcome != execguard.Allowed {
+			return nil, errcode.Wrap(errcode.Config, i18n.Errorf(messages.PluginRendererNotAllowedFmt, name, decision.Reason, command))
+		}
+		path, err := lookupPlugin(root, name, plugins.Paths)
+		if err != nil {
+			return nil, err
+		}
+		renderers = append(renderers, pluginRenderer{name: name, path: path})
+	}
+	return renderers, nil
+}
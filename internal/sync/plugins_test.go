@@ -0,0 +1,94 @@
+package sync
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/conn-castle/agent-layer/internal/errcode"
+	"github.com/conn-castle/agent-layer/internal/plugin"
+)
+
+func stubPlugins(t *testing.T, render func(path string, root string, req plugin.Request) (plugin.Response, error)) {
+	t.Helper()
+	originalLookup, originalRender := lookupPlugin, renderPlugin
+	t.Cleanup(func() { lookupPlugin, renderPlugin = originalLookup, originalRender })
+	lookupPlugin = func(_ string, name string, _ map[string]string) (string, error) {
+		return "/bin/al-" + name, nil
+	}
+	renderPlugin = render
+}
+
+func TestRun_PluginRendererWritesArtifacts(t *testing.T) {
+	root, project := syncFixtureProject(t)
+	withRenderers(t)
+	project.Config.Plugins.Renderers = []string{"zed"}
+	project.CommandsAllow = append(project.CommandsAllow, "al-zed")
+	var got plugin.Request
+	stubPlugins(t, func(path string, _ string, req plugin.Request) (plugin.Response, error) {
+		if path != "/bin/al-zed" {
+			t.Fatalf("plugin path = %q", path)
+		}
+		got = req
+		return plugin.Response{SchemaVersion: plugin.SchemaVersion, Artifacts: []plugin.Artifact{
+			{Path: ".zed/rules.md", Content: "rules\n"},
+			{Path: ".zed/run.sh", Content: "#!/bin/sh\n", Executable: true},
+		}}, nil
+	})
+
+	if _, err := RunWithProjectOptions(RealSystem{}, root, project, RunOptions{}); err != nil {
+		t.Fatalf("sync: %v", err)
+	}
+	if got.Plugin != "zed" || got.Root != root || len(got.Sources.Instructions) == 0 {
+		t.Fatalf("request = %+v", got)
+	}
+	data, err := os.ReadFile(filepath.Join(root, ".zed", "rules.md"))
+	if err != nil || string(data) != "rules\n" {
+		t.Fatalf("rules.md = %q, %v", data, err)
+	}
+	info, err := os.Stat(filepath.Join(root, ".zed", "run.sh"))
+	if err != nil || info.Mode().Perm() != 0o755 {
+		t.Fatalf("run.sh mode = %v, %v", info, err)
+	}
+}
+
+func TestRun_PluginRendererErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		allow    bool
+		register bool
+		render   error
+		want     string
+		code     errcode.Code
+	}{
+		{name: "not allowed", want: `add "al-zed" to .agent-layer/commands.allow`, code: errcode.Config},
+		{name: "registered renderer", allow: true, register: true, want: "plugin zed renders client zed", code: errcode.Config},
+		{name: "render failure", allow: true, render: errors.New("plugin zed failed: boom"), want: "renderer zed: plugin zed failed: boom", code: errcode.Sync},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root, project := syncFixtureProject(t)
+			if tt.register {
+				withRenderers(t, &fakeRenderer{client: "zed"})
+			} else {
+				withRenderers(t)
+			}
+			project.Config.Plugins.Renderers = []string{"zed"}
+			if tt.allow {
+				project.CommandsAllow = append(project.CommandsAllow, "al-zed")
+			}
+			stubPlugins(t, func(string, string, plugin.Request) (plugin.Response, error) {
+				return plugin.Response{SchemaVersion: plugin.SchemaVersion}, tt.render
+			})
+			_, err := RunWithProjectOptions(RealSystem{}, root, project, RunOptions{DryRun: true})
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("error = %v, want containing %q", err, tt.want)
+			}
+			if got := errcode.Of(err); got != tt.code {
+				t.Fatalf("code = %s, want %s", got, tt.code)
+			}
+		})
+	}
+}
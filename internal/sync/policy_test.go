@@ -0,0 +1,77 @@
+package sync
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/conn-castle/agent-layer/internal/config"
+	"github.com/conn-castle/agent-layer/internal/errcode"
+	"github.com/conn-castle/agent-layer/internal/warnings"
+)
+
+func policyFixture(t *testing.T, policy string) (string, *config.ProjectConfig) {
+	t.Helper()
+	root := t.TempDir()
+	if err := copyFixtureRepo(filepath.Join("testdata", "fixture-repo"), root); err != nil {
+		t.Fatalf("copy fixture: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(root, ".agent-layer", ".env"), []byte("AL_EXAMPLE_TOKEN=token123\n"), 0o600); err != nil {
+		t.Fatalf("write env: %v", err)
+	}
+	writeTemplateToFixtureSource(t, root, "claude-statusline.sh", filepath.Join(".agent-layer", "claude-statusline.sh"), 0o755)
+	writeTemplateToFixtureSource(t, root, "codex-statusline.toml", filepath.Join(".agent-layer", "codex-statusline.toml"), 0o644)
+	if err := os.WriteFile(filepath.Join(root, ".agent-layer", "policy.toml"), []byte(policy), 0o600); err != nil {
+		t.Fatalf("write policy: %v", err)
+	}
+	project, err := config.LoadProjectConfig(root)
+	if err != nil {
+		t.Fatalf("load project: %v", err)
+	}
+	return root, project
+}
+
+func TestRunWithProject_PolicyWarnings(t *testing.T) {
+	root, project := policyFixture(t, "[instructions]\nrequired_sections = [\"Policy Required Section\"]\n")
+	result, err := RunWithProject(RealSystem{}, root, project)
+	if err != nil {
+		t.Fatalf("sync: %v", err)
+	}
+	var found bool
+	for _, w := range result.AllWarnings {
+		if w.Code == warnings.CodePolicyContentRule && strings.Contains(w.Message, "Policy Required Section") {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatalf("expected %s warning, got %v", warnings.CodePolicyContentRule, result.AllWarnings)
+	}
+}
+
+func TestRunWithProject_PolicyErrorBlocksSync(t *testing.T) {
+	root, project := policyFixture(t, "[instructions]\nrequired_sections = [\"Policy Required Section\"]\n[sync]\nseverity = \"error\"\n")
+	_, err := RunWithProject(RealSystem{}, root, project)
+	if err == nil || !strings.Contains(err.Error(), "sync blocked by 1 policy violation(s)") {
+		t.Fatalf("expected policy error, got %v", err)
+	}
+	if errcode.Of(err) != errcode.Config {
+		t.Fatalf("expected config error code, got %s", errcode.Of(err))
+	}
+	if _, statErr := os.Stat(filepath.Join(root, "AGENTS.md")); !os.IsNotExist(statErr) {
+		t.Fatalf("expected no outputs written, stat err %v", statErr)
+	}
+}
+
+func TestRunWithProject_PolicyOff(t *testing.T) {
+	root, project := policyFixture(t, "[instructions]\nrequired_sections = [\"Policy Required Section\"]\n[sync]\nseverity = \"off\"\n")
+	result, err := RunWithProject(RealSystem{}, root, project)
+	if err != nil {
+		t.Fatalf("sync: %v", err)
+	}
+	for _, w := range result.AllWarnings {
+		if w.Code == warnings.CodePolicyContentRule {
+			t.Fatalf("expected no policy warnings when off, got %v", w)
+		}
+	}
+}
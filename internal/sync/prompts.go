@@ -10,17 +10,22 @@ import (
 	yaml "go.yaml.in/yaml/v3"
 
 	"github.com/conn-castle/agent-layer/internal/config"
+	"github.com/conn-castle/agent-layer/internal/i18n"
 	"github.com/conn-castle/agent-layer/internal/messages"
 )
 
-const promptHeaderTemplate = "<!--\n  GENERATED FILE\n  Source: %s\n  Regenerate: al sync\n-->\n"
-
 const (
 	generatedMarkerHeader     = "GENERATED FILE"
 	generatedMarkerSource     = "Source: .agent-layer/"
 	generatedMarkerRegenerate = "Regenerate: al sync"
+	// generatedMarkerUserScope tags skills projected from the user-global
+	// skills directory, whose source lies outside .agent-layer/.
+	generatedMarkerUserScope = "(user scope, not in this repo)"
 )
 
+// userHomeDir resolves the home directory abbreviated in user-scope skill headers.
+var userHomeDir = os.UserHomeDir
+
 // skillContentBuilder builds skill file content for a skill.
 type skillContentBuilder func(cmd config.Skill) (string, error)
 
@@ -29,7 +34,7 @@ type skillContentBuilder func(cmd config.Skill) (string, error)
 // from the source skill directory to support the Agent Skills spec's on-demand resource loading.
 func writeSkillFiles(sys System, skillsDir string, commands []config.Skill, buildContent skillContentBuilder) error {
 	if err := sys.MkdirAll(skillsDir, 0o755); err != nil {
-		return fmt.Errorf(messages.SyncCreateDirFailedFmt, skillsDir, err)
+		return i18n.Errorf(messages.SyncCreateDirFailedFmt, skillsDir, err)
 	}
 
 	wanted := make(map[string]struct{}, len(commands))
@@ -41,13 +46,13 @@ func writeSkillFiles(sys System, skillsDir string, commands []config.Skill, buil
 		path := filepath.Join(skillDir, "SKILL.md")
 		content, err := buildContent(cmd)
 		if err != nil {
-			return fmt.Errorf(messages.SyncWriteFileFailedFmt, path, err)
+			return i18n.Errorf(messages.SyncWriteFileFailedFmt, path, err)
 		}
 
 		wanted[cmd.Name] = struct{}{}
 		skillInfo, lstatErr := sys.Lstat(skillDir)
 		if lstatErr != nil && !os.IsNotExist(lstatErr) {
-			return fmt.Errorf(messages.SyncReadFailedFmt, skillDir, lstatErr)
+			return i18n.Errorf(messages.SyncReadFailedFmt, skillDir, lstatErr)
 		}
 		if lstatErr == nil && skillInfo.Mode()&os.ModeSymlink != 0 {
 			if err := removeSkillResourceNode(sys, skillDir, skillInfo); err != nil {
@@ -55,10 +60,10 @@ func writeSkillFiles(sys System, skillsDir string, commands []config.Skill, buil
 			}
 		}
 		if err := sys.MkdirAll(skillDir, 0o755); err != nil {
-			return fmt.Errorf(messages.SyncCreateDirFailedFmt, skillDir, err)
+			return i18n.Errorf(messages.SyncCreateDirFailedFmt, skillDir, err)
 		}
-		if err := sys.WriteFileAtomic(path, []byte(content), 0o644); err != nil {
-			return fmt.Errorf(messages.SyncWriteFileFailedFmt, path, err)
+		if err := writeGeneratedFile(sys, path, content, 0o644); err != nil {
+			return err
 		}
 		if err := copySkillSubFiles(sys, cmd, skillDir); err != nil {
 			return err
@@ -109,7 +114,7 @@ func copyDirRecursive(sys System, srcDir string, destDir string, skipFiles map[s
 		destPath := filepath.Join(destDir, relativePath)
 		destInfo, err := sys.Lstat(destPath)
 		if err != nil && !os.IsNotExist(err) {
-			return fmt.Errorf(messages.SyncReadFailedFmt, destPath, err)
+			return i18n.Errorf(messages.SyncReadFailedFmt, destPath, err)
 		}
 		if err == nil && (destInfo.IsDir() != resource.isDir || destInfo.Mode()&os.ModeSymlink != 0) {
 			if err := removeSkillResourceNode(sys, destPath, destInfo); err != nil {
@@ -119,12 +124,12 @@ func copyDirRecursive(sys System, srcDir string, destDir string, skipFiles map[s
 
 		if resource.isDir {
 			if err := sys.MkdirAll(destPath, 0o755); err != nil {
-				return fmt.Errorf(messages.SyncCreateDirFailedFmt, destPath, err)
+				return i18n.Errorf(messages.SyncCreateDirFailedFmt, destPath, err)
 			}
 			continue
 		}
 		if err := sys.WriteFileAtomic(destPath, resource.data, resource.mode); err != nil {
-			return fmt.Errorf(messages.SyncWriteFileFailedFmt, destPath, err)
+			return i18n.Errorf(messages.SyncWriteFileFailedFmt, destPath, err)
 		}
 	}
 
@@ -150,7 +155,7 @@ func collectDesiredSkillResources(
 		if os.IsNotExist(err) {
 			return nil
 		}
-		return fmt.Errorf(messages.SyncReadFailedFmt, srcDir, err)
+		return i18n.Errorf(messages.SyncReadFailedFmt, srcDir, err)
 	}
 
 	for _, entry := range entries {
@@ -166,7 +171,7 @@ func collectDesiredSkillResources(
 		relativePath := filepath.Join(relativeDir, name)
 		srcInfo, err := sys.Lstat(srcPath)
 		if err != nil {
-			return fmt.Errorf(messages.SyncReadFailedFmt, srcPath, err)
+			return i18n.Errorf(messages.SyncReadFailedFmt, srcPath, err)
 		}
 		if srcInfo.Mode()&os.ModeSymlink != 0 {
 			continue
@@ -182,7 +187,7 @@ func collectDesiredSkillResources(
 
 		data, err := sys.ReadFile(srcPath)
 		if err != nil {
-			return fmt.Errorf(messages.SyncReadFailedFmt, srcPath, err)
+			return i18n.Errorf(messages.SyncReadFailedFmt, srcPath, err)
 		}
 		desired[relativePath] = desiredSkillResource{data: data, mode: srcInfo.Mode()}
 	}
@@ -203,7 +208,7 @@ func removeStaleSkillResources(
 	currentDir := filepath.Join(destDir, relativeDir)
 	entries, err := sys.ReadDir(currentDir)
 	if err != nil {
-		return fmt.Errorf(messages.SyncReadFailedFmt, currentDir, err)
+		return i18n.Errorf(messages.SyncReadFailedFmt, currentDir, err)
 	}
 
 	for _, entry := range entries {
@@ -225,7 +230,7 @@ func removeStaleSkillResources(
 		destPath := filepath.Join(destDir, relativePath)
 		info, err := sys.Lstat(destPath)
 		if err != nil {
-			return fmt.Errorf(messages.SyncReadFailedFmt, destPath, err)
+			return i18n.Errorf(messages.SyncReadFailedFmt, destPath, err)
 		}
 		if err := removeSkillResourceNode(sys, destPath, info); err != nil {
 			return err
@@ -245,7 +250,7 @@ func removeSkillResourceNode(sys System, path string, info os.FileInfo) error {
 		err = sys.Remove(path)
 	}
 	if err != nil {
-		return fmt.Errorf(messages.SyncRemoveFailedFmt, path, err)
+		return i18n.Errorf(messages.SyncRemoveFailedFmt, path, err)
 	}
 	return nil
 }
@@ -274,7 +279,7 @@ func buildAgentSkill(cmd config.Skill) (string, error) {
 		return "", err
 	}
 	builder.WriteString(frontMatter)
-	fmt.Fprintf(&builder, promptHeaderTemplate, generatedSkillSourcePath(cmd))
+	builder.WriteString(markdownGeneratedHeader(generatedSkillSourcePath(cmd)))
 	if cmd.Body != "" {
 		builder.WriteString("\n")
 		builder.WriteString(cmd.Body)
@@ -282,7 +287,7 @@ func buildAgentSkill(cmd config.Skill) (string, error) {
 			builder.WriteString("\n")
 		}
 	}
-	return builder.String(), nil
+	return sealGeneratedContent(builder.String()), nil
 }
 
 // buildClaudeSkill returns the Claude Code SKILL.md content.
@@ -377,6 +382,9 @@ func copyMetadata(metadata map[string]string) map[string]string {
 }
 
 func generatedSkillSourcePath(cmd config.Skill) string {
+	if cmd.Scope == config.SkillScopeUser {
+		return userSkillDisplayPath(cmd.SourcePath) + " " + generatedMarkerUserScope
+	}
 	defaultPath := filepath.ToSlash(filepath.Join(".agent-layer", "skills", cmd.Name, "SKILL.md"))
 	source := strings.TrimSpace(cmd.SourcePath)
 	if source == "" {
@@ -393,10 +401,25 @@ func generatedSkillSourcePath(cmd config.Skill) string {
 	return defaultPath
 }
 
+// userSkillDisplayPath abbreviates the home directory in a user-scope skill
+// path so generated headers do not embed the account's absolute home path.
+func userSkillDisplayPath(path string) string {
+	normalized := filepath.ToSlash(path)
+	home, err := userHomeDir()
+	if err != nil || home == "" {
+		return normalized
+	}
+	home = filepath.ToSlash(home)
+	if rest, ok := strings.CutPrefix(normalized, home+"/"); ok {
+		return "~/" + rest
+	}
+	return normalized
+}
+
 func removeStaleSkillDirs(sys System, skillsDir string, wanted map[string]struct{}) error {
 	entries, err := sys.ReadDir(skillsDir)
 	if err != nil {
-		return fmt.Errorf(messages.SyncReadFailedFmt, skillsDir, err)
+		return i18n.Errorf(messages.SyncReadFailedFmt, skillsDir, err)
 	}
 
 	var stale []string
@@ -421,7 +444,7 @@ func removeStaleSkillDirs(sys System, skillsDir string, wanted map[string]struct
 	sort.Strings(stale)
 	for _, dir := range stale {
 		if err := sys.RemoveAll(dir); err != nil {
-			return fmt.Errorf(messages.SyncRemoveFailedFmt, dir, err)
+			return i18n.Errorf(messages.SyncRemoveFailedFmt, dir, err)
 		}
 	}
 
@@ -435,7 +458,7 @@ func cleanSharedAgentSkills(sys System, root string) error {
 		if os.IsNotExist(err) {
 			return nil
 		}
-		return fmt.Errorf(messages.SyncReadFailedFmt, skillsDir, err)
+		return i18n.Errorf(messages.SyncReadFailedFmt, skillsDir, err)
 	}
 	return removeStaleSkillDirs(sys, skillsDir, map[string]struct{}{})
 }
@@ -449,7 +472,7 @@ func cleanLegacySkillOutputs(sys System, root string) error {
 	for _, projection := range config.LegacySkillProjections {
 		path := filepath.Join(append([]string{root}, projection.Dir...)...)
 		if err := sys.RemoveAll(path); err != nil {
-			return fmt.Errorf(messages.SyncRemoveFailedFmt, path, err)
+			return i18n.Errorf(messages.SyncRemoveFailedFmt, path, err)
 		}
 	}
 	return nil
@@ -461,10 +484,10 @@ func hasGeneratedMarker(sys System, path string) (bool, error) {
 		if os.IsNotExist(err) {
 			return false, nil
 		}
-		return false, fmt.Errorf(messages.SyncReadFailedFmt, path, err)
+		return false, i18n.Errorf(messages.SyncReadFailedFmt, path, err)
 	}
 	content := string(data)
 	return strings.Contains(content, generatedMarkerHeader) &&
-		strings.Contains(content, generatedMarkerSource) &&
+		(strings.Contains(content, generatedMarkerSource) || strings.Contains(content, generatedMarkerUserScope)) &&
 		strings.Contains(content, generatedMarkerRegenerate), nil
 }
@@ -9,6 +9,7 @@ import (
 	"testing"
 
 	"github.com/conn-castle/agent-layer/internal/config"
+	"github.com/conn-castle/agent-layer/internal/errcode"
 )
 
 const generatedMarkerFixture = "<!--\n  GENERATED FILE\n  Source: .agent-layer/skills/test.md\n  Regenerate: al sync\n-->\n"
@@ -528,6 +529,59 @@ func TestGeneratedSkillSourcePath(t *testing.T) {
 	}
 }
 
+func TestGeneratedSkillSourcePath_UserScope(t *testing.T) {
+	original := userHomeDir
+	userHomeDir = func() (string, error) { return "/home/dev", nil }
+	t.Cleanup(func() { userHomeDir = original })
+
+	cmd := config.Skill{
+		Name:       "journal",
+		Scope:      config.SkillScopeUser,
+		SourcePath: filepath.Join("/home/dev", ".config", "agent-layer", "skills", "journal", "SKILL.md"),
+	}
+	want := "~/.config/agent-layer/skills/journal/SKILL.md " + generatedMarkerUserScope
+	if got := generatedSkillSourcePath(cmd); got != want {
+		t.Fatalf("unexpected user-scope source path: %q", got)
+	}
+
+	userHomeDir = func() (string, error) { return "", errors.New("no home") }
+	if got := generatedSkillSourcePath(cmd); !strings.HasPrefix(got, "/home/dev/.config/") {
+		t.Fatalf("expected absolute path without a home dir, got %q", got)
+	}
+}
+
+func TestWriteClaudeSkills_UserScopeSkillIsRemovedWhenStale(t *testing.T) {
+	original := userHomeDir
+	userHomeDir = func() (string, error) { return "/home/dev", nil }
+	t.Cleanup(func() { userHomeDir = original })
+
+	root := t.TempDir()
+	skill := config.Skill{
+		Name:        "journal",
+		Description: "personal journal",
+		Body:        "Body",
+		Scope:       config.SkillScopeUser,
+		SourcePath:  "/home/dev/.config/agent-layer/skills/journal/SKILL.md",
+	}
+	if err := WriteClaudeSkills(RealSystem{}, root, []config.Skill{skill}); err != nil {
+		t.Fatalf("WriteClaudeSkills: %v", err)
+	}
+	path := filepath.Join(root, ".claude", "skills", "journal", "SKILL.md")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read skill: %v", err)
+	}
+	if !strings.Contains(string(data), generatedMarkerUserScope) {
+		t.Fatalf("expected user-scope marker in header:\n%s", data)
+	}
+
+	if err := WriteClaudeSkills(RealSystem{}, root, nil); err != nil {
+		t.Fatalf("WriteClaudeSkills: %v", err)
+	}
+	if _, err := os.Stat(filepath.Dir(path)); !os.IsNotExist(err) {
+		t.Fatalf("expected stale user-scope skill removed, got %v", err)
+	}
+}
 func TestCopyDirRecursive_ReadFilePermissionError(t *testing.T) {
 	t.Parallel()
 	srcDir := t.TempDir()
@@ -1318,3 +1372,15 @@ func TestCopySkillSubFiles_SkipOnlyTopLevelSkillMd(t *testing.T) {
 		t.Fatalf("unexpected nested SKILL.md content: %q", string(data))
 	}
 }
+
+func TestRun_SkillRequiresValidated(t *testing.T) {
+	root, project := syncFixtureProject(t)
+	project.Skills = append(project.Skills, config.Skill{Name: "deploy", Description: "Deploy", Requires: []string{"build"}})
+	_, err := RunWithProjectOptions(RealSystem{}, root, project, RunOptions{DryRun: true})
+	if err == nil || !strings.Contains(err.Error(), `skill "deploy" requires "build"`) {
+		t.Fatalf("error = %v, want missing requirement", err)
+	}
+	if got := errcode.Of(err); got != errcode.Config {
+		t.Fatalf("code = %s, want %s", got, errcode.Config)
+	}
+}
@@ -0,0 +1,143 @@
+package sync
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"strings"
+
+	"github.com/conn-castle/agent-layer/internal/i18n"
+	"github.com/conn-castle/agent-layer/internal/messages"
+)
+
+// GeneratorVersion is the Agent Layer version recorded in generated file
+// headers. The CLI sets it to the running binary's version.
+var GeneratorVersion = "dev"
+
+const (
+	generatedMarkerDoNotEdit = "DO NOT EDIT"
+	generatedMarkerGenerator = "Generator: agent-layer "
+	// contentHashPrefix records the SHA-256 of the file as generated, computed
+	// with contentHashPlaceholder in place of the hash itself.
+	contentHashPrefix      = "Content-Hash: sha256:"
+	contentHashPlaceholder = "<content-hash>"
+	contentHashHexLen      = sha256.Size * 2
+)
+
+// generatedHeaderLines returns the standard provenance header for a file
+// generated from source. The header carries no timestamp, so regenerating
+// unchanged sources reproduces the file byte for byte.
+func generatedHeaderLines(source string) []string {
+	return []string{
+		generatedMarkerHeader + " - " + generatedMarkerDoNotEdit,
+		"Source: " + source,
+		generatedMarkerGenerator + GeneratorVersion,
+		contentHashPrefix + contentHashPlaceholder,
+		generatedMarkerRegenerate,
+	}
+}
+
+// markdownGeneratedHeader renders the provenance header as an HTML comment.
+func markdownGeneratedHeader(source string) string {
+	var builder strings.Builder
+	builder.WriteString("<!--\n")
+	for _, line := range generatedHeaderLines(source) {
+		builder.WriteString("  ")
+		builder.WriteString(line)
+		builder.WriteString("\n")
+	}
+	builder.WriteString("-->\n")
+	return builder.String()
+}
+
+// hashCommentGeneratedHeader renders the provenance header as # comments.
+func hashCommentGeneratedHeader(source string) string {
+	var builder strings.Builder
+	for _, line := range generatedHeaderLines(source) {
+		builder.WriteString("# ")
+		builder.WriteString(line)
+		builder.WriteString("\n")
+	}
+	return builder.String()
+}
+
+// sealGeneratedContent replaces the content hash placeholder with the hash of
+// content. Content without a placeholder is returned unchanged.
+func sealGeneratedContent(content string) string {
+	placeholder := contentHashPrefix + contentHashPlaceholder
+	if !strings.Contains(content, placeholder) {
+		return content
+	}
+	sum := sha256.Sum256([]byte(content))
+	return strings.Replace(content, placeholder, contentHashPrefix+hex.EncodeToString(sum[:]), 1)
+}
+
+// generatedContentEdited reports whether content was changed after sync wrote
+// it: its recorded content hash no longer matches. Content without a hash
+// (hand-written files, or files from releases before the hash) reports false.
+func generatedContentEdited(content string) bool {
+	index := strings.Index(content, contentHashPrefix)
+	if index < 0 {
+		return false
+	}
+	start := index + len(contentHashPrefix)
+	end := start + contentHashHexLen
+	if end > len(content) {
+		return true
+	}
+	unsealed := content[:start] + contentHashPlaceholder + content[end:]
+	sum := sha256.Sum256([]byte(unsealed))
+	return content[start:end] != hex.EncodeToString(sum[:])
+}
+
+// HasContentHash reports whether content carries the content hash sync
+// records in generated file headers.
+func HasContentHash(content string) bool {
+	return strings.Contains(content, contentHashPrefix)
+}
+
+// GeneratedContentUnchanged reports whether content carries a content hash
+// that still matches, so it is exactly what sync wrote.
+func GeneratedContentUnchanged(content string) bool {
+	return HasContentHash(content) && !generatedContentEdited(content)
+}
+
+// EditedFile is a generated file that was edited by hand since sync wrote
+// it. Sync keeps such files unless the run forces overwrites.
+type EditedFile struct {
+	// Path is the absolute path of the edited file.
+	Path string
+	// Current is the file as edited.
+	Current string
+	// Generated is what sync would have written.
+	Generated string
+}
+
+// generationGuard wraps a System for one sync run so writeGeneratedFile keeps
+// hand edits to generated files. Writers given a plain System (dispatch
+// projections, for example) always overwrite.
+type generationGuard struct {
+	System
+	force  bool
+	edited []EditedFile
+}
+
+// writeGeneratedFile writes generated content to path. Under a
+// generationGuard without force, a file whose content hash no longer matches
+// is kept and recorded as edited instead of being overwritten.
+func writeGeneratedFile(sys System, path string, content string, perm os.FileMode) error {
+	if guard, ok := sys.(*generationGuard); ok && !guard.force {
+		current, err := sys.ReadFile(path)
+		if err != nil && !os.IsNotExist(err) {
+			return i18n.Errorf(messages.SyncReadFailedFmt, path, err)
+		}
+		if err == nil && string(current) != content && generatedContentEdited(string(current)) {
+			guard.edited = append(guard.edited, EditedFile{Path: path, Current: string(current), Generated: content})
+			return nil
+		}
+	}
+	if err := sys.WriteFileAtomic(path, []byte(content), perm); err != nil {
+		return i18n.Errorf(messages.SyncWriteFileFailedFmt, path, err)
+	}
+	return nil
+}
@@ -0,0 +1,154 @@
+package sync
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/conn-castle/agent-layer/internal/config"
+	"github.com/conn-castle/agent-layer/internal/warnings"
+)
+
+func TestSealGeneratedContent(t *testing.T) {
+	content := markdownGeneratedHeader(".agent-layer/instructions/*.md") + "\nbody\n"
+	sealed := sealGeneratedContent(content)
+	if strings.Contains(sealed, contentHashPlaceholder) {
+		t.Fatalf("expected placeholder replaced:\n%s", sealed)
+	}
+	if sealed != sealGeneratedContent(content) {
+		t.Fatalf("expected sealing to be deterministic")
+	}
+	if !strings.Contains(sealed, "GENERATED FILE - DO NOT EDIT") || !strings.Contains(sealed, "Generator: agent-layer "+GeneratorVersion) {
+		t.Fatalf("expected do-not-edit and generator lines:\n%s", sealed)
+	}
+	if generatedContentEdited(sealed) {
+		t.Fatalf("expected freshly sealed content to verify")
+	}
+	if !generatedContentEdited(sealed + "edit\n") {
+		t.Fatalf("expected an appended edit to be detected")
+	}
+	if !generatedContentEdited(strings.Replace(sealed, "body", "bodY", 1)) {
+		t.Fatalf("expected an in-place edit to be detected")
+	}
+	if got := sealGeneratedContent("plain\n"); got != "plain\n" {
+		t.Fatalf("expected content without placeholder unchanged, got %q", got)
+	}
+}
+
+func TestGeneratedContentEdited_WithoutHash(t *testing.T) {
+	if generatedContentEdited(generatedMarkerFixture + "edited freely\n") {
+		t.Fatalf("expected legacy headers without a hash to count as unedited")
+	}
+	if !generatedContentEdited("# " + contentHashPrefix + "abc") {
+		t.Fatalf("expected a truncated hash to count as edited")
+	}
+}
+
+func TestWriteGeneratedFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "AGENTS.md")
+	generated := buildInstructionShim([]config.InstructionFile{{Name: "00_rules.md", Content: "rules"}})
+	edited := generated + "my note\n"
+	if err := os.WriteFile(path, []byte(edited), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	guard := &generationGuard{System: RealSystem{}}
+	if err := writeGeneratedFile(guard, path, generated, 0o644); err != nil {
+		t.Fatalf("guarded write: %v", err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != edited {
+		t.Fatalf("expected hand edits kept, got %q", data)
+	}
+	if len(guard.edited) != 1 || guard.edited[0].Path != path || guard.edited[0].Generated != generated {
+		t.Fatalf("expected edited file recorded, got %#v", guard.edited)
+	}
+
+	forced := &generationGuard{System: RealSystem{}, force: true}
+	if err := writeGeneratedFile(forced, path, generated, 0o644); err != nil {
+		t.Fatalf("forced write: %v", err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != generated || len(forced.edited) != 0 {
+		t.Fatalf("expected force to overwrite, got %q", data)
+	}
+
+	if err := os.WriteFile(path, []byte(edited), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if err := writeGeneratedFile(RealSystem{}, path, generated, 0o644); err != nil {
+		t.Fatalf("plain write: %v", err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != generated {
+		t.Fatalf("expected an unguarded write to overwrite, got %q", data)
+	}
+}
+
+func TestWriteGeneratedFile_ReadError(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "AGENTS.md")
+	sys := &MockSystem{
+		Fallback:     RealSystem{},
+		ReadFileFunc: func(string) ([]byte, error) { return nil, errors.New("boom") },
+	}
+	err := writeGeneratedFile(&generationGuard{System: sys}, path, "content", 0o644)
+	if err == nil || !strings.Contains(err.Error(), "boom") {
+		t.Fatalf("expected read error, got %v", err)
+	}
+}
+
+func TestRunWithProjectOptions_KeepsEditedGeneratedFiles(t *testing.T) {
+	root := t.TempDir()
+	if err := copyFixtureRepo(filepath.Join("testdata", "fixture-repo"), root); err != nil {
+		t.Fatalf("copy fixture: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(root, ".agent-layer", ".env"), []byte("AL_EXAMPLE_TOKEN=token123\n"), 0o600); err != nil {
+		t.Fatalf("write env: %v", err)
+	}
+	writeTemplateToFixtureSource(t, root, "claude-statusline.sh", filepath.Join(".agent-layer", "claude-statusline.sh"), 0o755)
+	writeTemplateToFixtureSource(t, root, "codex-statusline.toml", filepath.Join(".agent-layer", "codex-statusline.toml"), 0o644)
+	if _, err := Run(root); err != nil {
+		t.Fatalf("first sync: %v", err)
+	}
+	agentsPath := filepath.Join(root, "AGENTS.md")
+	original, err := os.ReadFile(agentsPath)
+	if err != nil {
+		t.Fatalf("read AGENTS.md: %v", err)
+	}
+	edited := string(original) + "local tweak\n"
+	if err := os.WriteFile(agentsPath, []byte(edited), 0o600); err != nil {
+		t.Fatalf("edit AGENTS.md: %v", err)
+	}
+
+	project, err := config.LoadProjectConfig(root)
+	if err != nil {
+		t.Fatalf("load project: %v", err)
+	}
+	result, err := RunWithProjectOptions(RealSystem{}, root, project, RunOptions{})
+	if err != nil {
+		t.Fatalf("sync: %v", err)
+	}
+	if data, _ := os.ReadFile(agentsPath); string(data) != edited {
+		t.Fatalf("expected edited AGENTS.md kept")
+	}
+	if len(result.EditedFiles) != 1 || result.EditedFiles[0].Generated != string(original) {
+		t.Fatalf("expected one edited file, got %#v", result.EditedFiles)
+	}
+	var found bool
+	for _, w := range result.AllWarnings {
+		if w.Code == warnings.CodeGeneratedFileEdited && w.Subject == "AGENTS.md" {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatalf("expected %s warning for AGENTS.md, got %v", warnings.CodeGeneratedFileEdited, result.AllWarnings)
+	}
+
+	result, err = RunWithProjectOptions(RealSystem{}, root, project, RunOptions{Force: true})
+	if err != nil {
+		t.Fatalf("forced sync: %v", err)
+	}
+	if data, _ := os.ReadFile(agentsPath); string(data) != string(original) || len(result.EditedFiles) != 0 {
+		t.Fatalf("expected --force to regenerate AGENTS.md")
+	}
+}
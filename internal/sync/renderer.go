@@ -0,0 +1,311 @@
+package sync
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	stdsync "sync"
+
+	"github.com/conn-castle/agent-layer/internal/config"
+	"github.com/conn-castle/agent-layer/internal/i18n"
+	"github.com/conn-castle/agent-layer/internal/messages"
+)
+
+// rendererStateFile records the repo-relative paths each registered renderer
+// wrote on the last sync, so the next sync can remove outputs a renderer no
+// longer produces, including every output of a renderer that is gone.
+const rendererStateFile = "renderer-outputs.json"
+
+// reservedRendererPaths are repo paths no renderer may write: git metadata
+// and the outputs of the built-in clients. An entry covers everything below
+// it. Nested AGENTS.md and CLAUDE.md files are reserved in every directory,
+// since scoped instructions generate them.
+var reservedRendererPaths = []string{
+	".agents/skills",
+	".agy",
+	".claude",
+	".codex",
+	".copilot",
+	".github/copilot-instructions.md",
+	".github/instructions",
+	".gitignore",
+	".mcp.json",
+	".vscode/" + vscodeWorkspaceFile,
+	".vscode/mcp.json",
+	".vscode/settings.json",
+}
+
+// Inputs is what a Renderer renders from.
+type Inputs struct {
+	// Root is the absolute repo root.
+	Root string
+	// Project is the resolved project: MCP servers pinned and instruction
+	// variables expanded, as the built-in clients see it.
+	Project *config.ProjectConfig
+}
+
+// Artifact is one file a Renderer produces.
+type Artifact struct {
+	// Path is slash-separated and relative to the repo root. It must stay
+	// inside the repo and outside .agent-layer/.
+	Path    string
+	Content []byte
+	// Perm defaults to 0o644 when zero.
+	Perm os.FileMode
+}
+
+// Renderer generates one client's files from the project. Sync writes the
+// artifacts through the same staging, edit protection, and per-client summary
+// as the built-in clients, and removes artifacts a later run no longer
+// returns. A renderer that does not apply to the project returns none.
+type Renderer interface {
+	// Client names the client the artifacts are for. It must be unique and
+	// must not be a built-in client name.
+	Client() string
+	Render(in Inputs) ([]Artifact, error)
+}
+
+var (
+	renderersMu stdsync.Mutex
+	renderers   = make(map[string]Renderer)
+)
+
+// RegisterRenderer adds r to every later sync. Client integrations call it
+// from an init function, the way database/sql drivers register. It panics
+// when r is nil, unnamed, a built-in client, or registered twice.
+func RegisterRenderer(r Renderer) {
+	if r == nil {
+		panic(messages.SyncRendererNil)
+	}
+	client := r.Client()
+	if client == "" {
+		panic(messages.SyncRendererClientRequired)
+	}
+	switch client {
+	case summaryClientAntigravity, summaryClientClaude, summaryClientCodex, summaryClientCopilot, summaryClientVSCode:
+		panic(i18n.Sprintf(messages.SyncRendererBuiltinFmt, client))
+	}
+	renderersMu.Lock()
+	defer renderersMu.Unlock()
+	if _, dup := renderers[client]; dup {
+		panic(i18n.Sprintf(messages.SyncRendererDuplicateFmt, client))
+	}
+	renderers[client] = r
+}
+
+// registeredRenderers returns the registered renderers ordered by client.
+func registeredRenderers() []Renderer {
+	renderersMu.Lock()
+	defer renderersMu.Unlock()
+	out := make([]Renderer, 0, len(renderers))
+	for _, r := range renderers {
+		out = append(out, r)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Client() < out[j].Client() })
+	return out
+}
+
+// rendererState is the on-disk shape of rendererStateFile.
+type rendererState struct {
+	Outputs map[string][]string `json:"outputs"`
+}
+
+func rendererStatePath(root string) string {
+	return filepath.Join(config.StateDir(root), rendererStateFile)
+}
+
+// rendererRun renders the registered renderers during one sync.
+type rendererRun struct {
+	sys      System
+	root     string
+	in       Inputs
+	previous map[string][]string
+	current  map[string][]string
+	owners   map[string]string
+}
+
+// rendererSteps returns the sync steps for renderers: one per renderer, one
+// per renderer that recorded outputs but is no longer registered, then the
+// outputs record. owned tags each step's changes with its client.
+func rendererSteps(sys System, root string, in Inputs, rs []Renderer, owned func(string, func() error) func() error) ([]func() error, error) {
+	previous, err := readRendererState(sys, rendererStatePath(root))
+	if err != nil {
+		return nil, err
+	}
+	run := &rendererRun{
+		sys:      sys,
+		root:     root,
+		in:       in,
+		previous: previous.Outputs,
+		current:  make(map[string][]string),
+		owners:   make(map[string]string),
+	}
+	var steps []func() error
+	registered := make(map[string]bool)
+	for _, r := range rs {
+		registered[r.Client()] = true
+		steps = append(steps, owned(r.Client(), func() error { return run.render(r) }))
+	}
+	gone := make([]string, 0, len(run.previous))
+	for client := range run.previous {
+		if !registered[client] {
+			gone = append(gone, client)
+		}
+	}
+	sort.Strings(gone)
+	for _, client := range gone {
+		steps = append(steps, owned(client, func() error { return run.removeStale(client, nil) }))
+	}
+	if len(steps) > 0 {
+		steps = append(steps, run.record)
+	}
+	return steps, nil
+}
+
+// render writes r's artifacts and removes the ones its previous run wrote
+// that it no longer returns.
+func (run *rendererRun) render(r Renderer) error {
+	client := r.Client()
+	artifacts, err := r.Render(run.in)
+	if err != nil {
+		return i18n.Errorf(messages.SyncRendererFailedFmt, client, err)
+	}
+	written := make(map[string]bool, len(artifacts))
+	for _, artifact := range artifacts {
+		rel, err := rendererArtifactPath(client, artifact.Path)
+		if err != nil {
+			return err
+		}
+		if owner, taken := run.owners[rel]; taken {
+			return i18n.Errorf(messages.SyncRendererArtifactDuplicateFmt, client, rel, owner)
+		}
+		run.owners[rel] = client
+		written[rel] = true
+		path := filepath.Join(run.root, filepath.FromSlash(rel))
+		if err := run.checkOverwrite(client, rel, path, artifact.Content); err != nil {
+			return err
+		}
+		if err := run.sys.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return i18n.Errorf(messages.SyncCreateDirFailedFmt, filepath.Dir(path), err)
+		}
+		perm := artifact.Perm
+		if perm == 0 {
+			perm = 0o644
+		}
+		if err := writeGeneratedFile(run.sys, path, string(artifact.Content), perm); err != nil {
+			return err
+		}
+		run.current[client] = append(run.current[client], rel)
+	}
+	sort.Strings(run.current[client])
+	return run.removeStale(client, written)
+}
+
+// checkOverwrite refuses to let client replace a file it did not write: one
+// that exists, is not in client's outputs from the previous sync, differs
+// from content, and carries no provenance header.
+func (run *rendererRun) checkOverwrite(client string, rel string, path string, content []byte) error {
+	if slices.Contains(run.previous[client], rel) {
+		return nil
+	}
+	current, err := run.sys.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return i18n.Errorf(messages.SyncReadFailedFmt, path, err)
+	}
+	if bytes.Equal(current, content) || HasContentHash(string(current)) {
+		return nil
+	}
+	return i18n.Errorf(messages.SyncRendererArtifactUnownedFmt, client, rel)
+}
+
+// removeStale removes the paths client wrote on the previous sync that are
+// not in keep. Recorded paths are validated like artifact paths first, so an
+// edited record cannot remove files outside a renderer's reach.
+func (run *rendererRun) removeStale(client string, keep map[string]bool) error {
+	for _, recorded := range run.previous[client] {
+		rel, err := rendererArtifactPath(client, recorded)
+		if err != nil {
+			return err
+		}
+		if keep[rel] || run.owners[rel] != "" {
+			continue
+		}
+		path := filepath.Join(run.root, filepath.FromSlash(rel))
+		if err := run.sys.Remove(path); err != nil && !os.IsNotExist(err) {
+			return i18n.Errorf(messages.SyncRemoveFailedFmt, path, err)
+		}
+	}
+	return nil
+}
+
+// record writes the outputs each renderer produced this run. Nothing is
+// written when no renderer has outputs now or before.
+func (run *rendererRun) record() error {
+	path := rendererStatePath(run.root)
+	if len(run.current) == 0 && len(run.previous) == 0 {
+		return nil
+	}
+	data, err := run.sys.MarshalIndent(rendererState{Outputs: run.current}, "", "  ")
+	if err != nil {
+		return i18n.Errorf(messages.SyncMarshalRendererStateFailedFmt, err)
+	}
+	data = append(data, '\n')
+	if err := run.sys.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return i18n.Errorf(messages.SyncCreateDirFailedFmt, filepath.Dir(path), err)
+	}
+	if err := run.sys.WriteFileAtomic(path, data, 0o644); err != nil {
+		return i18n.Errorf(messages.SyncWriteFileFailedFmt, path, err)
+	}
+	return nil
+}
+
+// readRendererState loads the recorded outputs. A missing record means no
+// renderer has written anything yet.
+func readRendererState(sys System, path string) (rendererState, error) {
+	data, err := sys.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return rendererState{}, nil
+		}
+		return rendererState{}, i18n.Errorf(messages.SyncReadRendererStateFailedFmt, path, err)
+	}
+	var state rendererState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return rendererState{}, i18n.Errorf(messages.SyncReadRendererStateFailedFmt, path, err)
+	}
+	return state, nil
+}
+
+// rendererArtifactPath validates an artifact path and returns it cleaned.
+// Paths are compared case-insensitively against .agent-layer/, .git, and
+// reservedRendererPaths, as case-insensitive file systems would resolve them.
+func rendererArtifactPath(client string, path string) (string, error) {
+	rel := filepath.ToSlash(filepath.Clean(filepath.FromSlash(path)))
+	lower := strings.ToLower(rel)
+	if path == "" || !filepath.IsLocal(filepath.FromSlash(rel)) || lower == ".agent-layer" || strings.HasPrefix(lower, ".agent-layer/") {
+		return "", i18n.Errorf(messages.SyncRendererArtifactPathInvalidFmt, client, path)
+	}
+	parts := strings.Split(lower, "/")
+	if slices.Contains(parts, ".git") {
+		return "", i18n.Errorf(messages.SyncRendererArtifactReservedFmt, client, path)
+	}
+	for _, name := range scopedInstructionFiles {
+		if parts[len(parts)-1] == strings.ToLower(name) {
+			return "", i18n.Errorf(messages.SyncRendererArtifactReservedFmt, client, path)
+		}
+	}
+	for _, reserved := range reservedRendererPaths {
+		reserved = strings.ToLower(reserved)
+		if lower == reserved || strings.HasPrefix(lower, reserved+"/") {
+			return "", i18n.Errorf(messages.SyncRendererArtifactReservedFmt, client, path)
+		}
+	}
+	return rel, nil
+}
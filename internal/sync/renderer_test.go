@@ -0,0 +1,238 @@
+package sync
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/conn-castle/agent-layer/internal/config"
+)
+
+type fakeRenderer struct {
+	client    string
+	artifacts []Artifact
+	err       error
+	inputs    Inputs
+}
+
+func (r *fakeRenderer) Client() string { return r.client }
+
+func (r *fakeRenderer) Render(in Inputs) ([]Artifact, error) {
+	r.inputs = in
+	return r.artifacts, r.err
+}
+
+// withRenderers replaces the registry for the duration of the test.
+func withRenderers(t *testing.T, rs ...Renderer) {
+	t.Helper()
+	renderersMu.Lock()
+	original := renderers
+	renderers = make(map[string]Renderer)
+	renderersMu.Unlock()
+	t.Cleanup(func() {
+		renderersMu.Lock()
+		renderers = original
+		renderersMu.Unlock()
+	})
+	for _, r := range rs {
+		RegisterRenderer(r)
+	}
+}
+
+func syncFixtureProject(t *testing.T) (string, *config.ProjectConfig) {
+	t.Helper()
+	root := t.TempDir()
+	if err := copyFixtureRepo(filepath.Join("testdata", "fixture-repo"), root); err != nil {
+		t.Fatalf("copy fixture: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(root, ".agent-layer", ".env"), []byte("AL_EXAMPLE_TOKEN=token123\n"), 0o600); err != nil {
+		t.Fatalf("write env: %v", err)
+	}
+	writeTemplateToFixtureSource(t, root, "claude-statusline.sh", filepath.Join(".agent-layer", "claude-statusline.sh"), 0o755)
+	writeTemplateToFixtureSource(t, root, "codex-statusline.toml", filepath.Join(".agent-layer", "codex-statusline.toml"), 0o644)
+	project, err := config.LoadProjectConfig(root)
+	if err != nil {
+		t.Fatalf("load project: %v", err)
+	}
+	return root, project
+}
+
+func TestRegisterRenderer_Panics(t *testing.T) {
+	withRenderers(t, &fakeRenderer{client: "zed"})
+	tests := []struct {
+		name string
+		r    Renderer
+		want string
+	}{
+		{name: "nil", r: nil, want: "nil renderer"},
+		{name: "unnamed", r: &fakeRenderer{}, want: "no client name"},
+		{name: "builtin", r: &fakeRenderer{client: summaryClientClaude}, want: "built-in client claude"},
+		{name: "duplicate", r: &fakeRenderer{client: "zed"}, want: "twice for client zed"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				got, _ := recover().(string)
+				if !strings.Contains(got, tt.want) {
+					t.Fatalf("panic = %q, want containing %q", got, tt.want)
+				}
+			}()
+			RegisterRenderer(tt.r)
+		})
+	}
+}
+
+func TestRun_RegisteredRendererWritesAndRemovesArtifacts(t *testing.T) {
+	root, project := syncFixtureProject(t)
+	zed := &fakeRenderer{client: "zed", artifacts: []Artifact{
+		{Path: ".zed/settings.json", Content: []byte("{}\n")},
+		{Path: ".zed/rules.md", Content: []byte("rules\n"), Perm: 0o600},
+	}}
+	withRenderers(t, zed)
+
+	result, err := RunWithProjectOptions(RealSystem{}, root, project, RunOptions{})
+	if err != nil {
+		t.Fatalf("sync: %v", err)
+	}
+	if zed.inputs.Root != root || zed.inputs.Project == nil {
+		t.Fatalf("renderer inputs = %+v", zed.inputs)
+	}
+	data, err := os.ReadFile(filepath.Join(root, ".zed", "settings.json"))
+	if err != nil || string(data) != "{}\n" {
+		t.Fatalf("settings.json = %q, %v", data, err)
+	}
+	found := false
+	for _, summary := range result.Summary(root) {
+		if summary.Client == "zed" && len(summary.Written) == 2 {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatalf("expected zed outputs in summary: %#v", result.Summary(root))
+	}
+
+	zed.artifacts = zed.artifacts[:1]
+	if _, err := RunWithProjectOptions(RealSystem{}, root, project, RunOptions{}); err != nil {
+		t.Fatalf("second sync: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(root, ".zed", "rules.md")); !os.IsNotExist(err) {
+		t.Fatalf("expected dropped artifact removed, stat err = %v", err)
+	}
+
+	withRenderers(t)
+	if _, err := RunWithProjectOptions(RealSystem{}, root, project, RunOptions{}); err != nil {
+		t.Fatalf("third sync: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(root, ".zed", "settings.json")); !os.IsNotExist(err) {
+		t.Fatalf("expected unregistered renderer's artifact removed, stat err = %v", err)
+	}
+}
+
+func TestRun_RendererErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		renderers []Renderer
+		want      string
+	}{
+		{
+			name:      "render error",
+			renderers: []Renderer{&fakeRenderer{client: "zed", err: errors.New("boom")}},
+			want:      "renderer zed: boom",
+		},
+		{
+			name:      "escaping path",
+			renderers: []Renderer{&fakeRenderer{client: "zed", artifacts: []Artifact{{Path: "../outside"}}}},
+			want:      "must be a relative path inside the repo",
+		},
+		{
+			name:      "agent-layer path",
+			renderers: []Renderer{&fakeRenderer{client: "zed", artifacts: []Artifact{{Path: ".agent-layer/config.toml"}}}},
+			want:      "outside .agent-layer",
+		},
+		{
+			name:      "git path",
+			renderers: []Renderer{&fakeRenderer{client: "zed", artifacts: []Artifact{{Path: "vendor/lib/.git/config"}}}},
+			want:      "is reserved for git metadata or a built-in client output",
+		},
+		{
+			name:      "built-in output",
+			renderers: []Renderer{&fakeRenderer{client: "zed", artifacts: []Artifact{{Path: ".MCP.json"}}}},
+			want:      "is reserved for git metadata or a built-in client output",
+		},
+		{
+			name:      "built-in output directory",
+			renderers: []Renderer{&fakeRenderer{client: "zed", artifacts: []Artifact{{Path: ".claude/agents/zed.md"}}}},
+			want:      "is reserved for git metadata or a built-in client output",
+		},
+		{
+			name:      "nested instructions",
+			renderers: []Renderer{&fakeRenderer{client: "zed", artifacts: []Artifact{{Path: "services/api/AGENTS.md"}}}},
+			want:      "is reserved for git metadata or a built-in client output",
+		},
+		{
+			name: "shared path",
+			renderers: []Renderer{
+				&fakeRenderer{client: "a", artifacts: []Artifact{{Path: "out.md"}}},
+				&fakeRenderer{client: "b", artifacts: []Artifact{{Path: "out.md"}}},
+			},
+			want: "renderer b: artifact out.md is also written by renderer a",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root, project := syncFixtureProject(t)
+			withRenderers(t, tt.renderers...)
+			_, err := RunWithProjectOptions(RealSystem{}, root, project, RunOptions{DryRun: true})
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("error = %v, want containing %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestRun_RendererRefusesToOverwriteUnownedFile(t *testing.T) {
+	root, project := syncFixtureProject(t)
+	path := filepath.Join(root, ".zed", "settings.json")
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte("{\"theme\": \"mine\"}\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	withRenderers(t, &fakeRenderer{client: "zed", artifacts: []Artifact{{Path: ".zed/settings.json", Content: []byte("{}\n")}}})
+
+	_, err := RunWithProjectOptions(RealSystem{}, root, project, RunOptions{})
+	if err == nil || !strings.Contains(err.Error(), "renderer zed: .zed/settings.json already exists and was not generated by Agent Layer") {
+		t.Fatalf("error = %v, want unowned file refusal", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil || string(data) != "{\"theme\": \"mine\"}\n" {
+		t.Fatalf("settings.json = %q, %v; want it untouched", data, err)
+	}
+}
+
+func TestRun_RendererValidatesRecordedOutputsBeforeRemoving(t *testing.T) {
+	root, project := syncFixtureProject(t)
+	withRenderers(t)
+	victim := filepath.Join(root, "README.md")
+	if err := os.WriteFile(victim, []byte("readme\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	record := rendererStatePath(root)
+	if err := os.MkdirAll(filepath.Dir(record), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(record, []byte(`{"outputs":{"zed":["README.md","../outside","AGENTS.md"]}}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := RunWithProjectOptions(RealSystem{}, root, project, RunOptions{})
+	if err == nil || !strings.Contains(err.Error(), `artifact path "../outside"`) {
+		t.Fatalf("error = %v, want invalid recorded path", err)
+	}
+	if _, err := os.Stat(victim); err != nil {
+		t.Fatalf("a failed sync must remove nothing: %v", err)
+	}
+}
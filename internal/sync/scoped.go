@@ -0,0 +1,195 @@
+package sync
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/conn-castle/agent-layer/internal/config"
+	"github.com/conn-castle/agent-layer/internal/i18n"
+	"github.com/conn-castle/agent-layer/internal/messages"
+)
+
+// scopedInstructionFiles are the per-directory files clients load when they
+// work below a subdirectory: Codex and agy read nested AGENTS.md, Claude reads
+// nested CLAUDE.md.
+var scopedInstructionFiles = []string{"AGENTS.md", "CLAUDE.md"}
+
+// ScopedInstructionFiles returns the names of the files sync writes in each
+// repo directory that has scoped instructions.
+func ScopedInstructionFiles() []string {
+	return slices.Clone(scopedInstructionFiles)
+}
+
+var getenv = os.Getenv
+
+// sparseCheckoutDirsFunc returns the directories of a cone-mode sparse
+// checkout. ok is false when root is not a sparse worktree (or git is
+// unavailable), in which case nothing is narrowed.
+var sparseCheckoutDirsFunc = func(root string) ([]string, bool) {
+	out, err := exec.Command("git", "-C", root, "sparse-checkout", "list").Output() // #nosec G204 -- fixed git subcommand; root is the resolved repo root.
+	if err != nil {
+		return nil, false
+	}
+	var dirs []string
+	for _, line := range strings.Split(string(out), "\n") {
+		line = strings.Trim(strings.TrimSpace(line), "/")
+		if line == "" || strings.ContainsAny(line, "*?[!") {
+			continue
+		}
+		dirs = append(dirs, line)
+	}
+	return dirs, true
+}
+
+// writeScopedInstructions renders .agent-layer/scoped/<dir>/*.md into
+// <dir>/AGENTS.md and <dir>/CLAUDE.md. In sparse mode only directories in the
+// user's working set are generated, and generated files for directories
+// outside it are removed. Directories missing from the worktree (for example
+// excluded by sparse-checkout) are never created.
+func writeScopedInstructions(sys System, root string, project *config.ProjectConfig) error {
+	if len(project.ScopedInstructions) == 0 {
+		return nil
+	}
+	active, err := activeScopedDirs(root, project)
+	if err != nil {
+		return err
+	}
+	for _, scoped := range project.ScopedInstructions {
+		if scoped.Dir == "" {
+			continue
+		}
+		dir := filepath.Join(root, filepath.FromSlash(scoped.Dir))
+		if !inScopedSet(scoped.Dir, active) {
+			if err := removeGeneratedFiles(sys, dir, scopedInstructionFiles); err != nil {
+				return err
+			}
+			continue
+		}
+		info, err := sys.Stat(dir)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return i18n.Errorf(messages.SyncStatFailedFmt, dir, err)
+		}
+		if !info.IsDir() {
+			continue
+		}
+		content := buildScopedInstructionShim(scoped)
+		for _, name := range scopedInstructionFiles {
+			path := filepath.Join(dir, name)
+			if err := ensureGeneratedOrAbsent(sys, path, scoped.Dir); err != nil {
+				return err
+			}
+			if err := writeGeneratedFile(sys, path, content, 0o644); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// activeScopedDirs returns the user's working set in sparse mode, or nil when
+// every scoped directory is in scope. An explicit owner wins over
+// sparse-checkout.
+func activeScopedDirs(root string, project *config.ProjectConfig) ([]string, error) {
+	monorepo := project.Config.Monorepo
+	if !monorepo.IsSparse() {
+		return nil, nil
+	}
+	owner := strings.TrimSpace(getenv(config.EnvMonorepoOwner))
+	if owner == "" {
+		owner = strings.TrimSpace(project.Env[config.EnvMonorepoOwner])
+	}
+	if owner != "" {
+		dirs, ok := monorepo.Owners[owner]
+		if !ok {
+			names := make([]string, 0, len(monorepo.Owners))
+			for name := range monorepo.Owners {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			return nil, i18n.Errorf(messages.SyncMonorepoOwnerUnknownFmt, config.EnvMonorepoOwner, owner, strings.Join(names, ", "))
+		}
+		return cleanScopedDirs(dirs), nil
+	}
+	if dirs, ok := sparseCheckoutDirsFunc(root); ok {
+		return cleanScopedDirs(dirs), nil
+	}
+	return nil, nil
+}
+
+func cleanScopedDirs(dirs []string) []string {
+	cleaned := make([]string, 0, len(dirs))
+	for _, dir := range dirs {
+		if clean, err := config.CleanRepoDir(dir); err == nil {
+			cleaned = append(cleaned, clean)
+		}
+	}
+	return cleaned
+}
+
+// inScopedSet reports whether dir is inside, or an ancestor of, a directory in
+// active. Ancestors stay in scope so shared guidance for a parent (for example
+// services/) still reaches the packages below it. A nil active set includes
+// every directory.
+func inScopedSet(dir string, active []string) bool {
+	if active == nil {
+		return true
+	}
+	for _, owned := range active {
+		if dir == owned || strings.HasPrefix(dir, owned+"/") || strings.HasPrefix(owned, dir+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// ensureGeneratedOrAbsent refuses to overwrite a hand-written file.
+func ensureGeneratedOrAbsent(sys System, path string, dir string) error {
+	if _, err := sys.Stat(path); err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return i18n.Errorf(messages.SyncStatFailedFmt, path, err)
+	}
+	generated, err := hasGeneratedMarker(sys, path)
+	if err != nil {
+		return err
+	}
+	if !generated {
+		return i18n.Errorf(messages.SyncScopedTargetNotGeneratedFmt, path, dir)
+	}
+	return nil
+}
+
+func removeGeneratedFiles(sys System, dir string, names []string) error {
+	for _, name := range names {
+		path := filepath.Join(dir, name)
+		generated, err := hasGeneratedMarker(sys, path)
+		if err != nil {
+			return err
+		}
+		if !generated {
+			continue
+		}
+		if err := sys.Remove(path); err != nil && !os.IsNotExist(err) {
+			return i18n.Errorf(messages.SyncRemoveFailedFmt, path, err)
+		}
+	}
+	return nil
+}
+
+// ScopedInstructionDocument returns the composed instructions sync writes to
+// <dir>/AGENTS.md and <dir>/CLAUDE.md for one scoped directory.
+func ScopedInstructionDocument(scoped config.ScopedInstructions) string {
+	return buildScopedInstructionShim(scoped)
+}
+
+func buildScopedInstructionShim(scoped config.ScopedInstructions) string {
+	return buildGeneratedInstructions(scopedInstructionSource(scoped), scoped.Files)
+}
@@ -0,0 +1,133 @@
+package sync
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/conn-castle/agent-layer/internal/config"
+)
+
+func scopedProject(root string, mode string) *config.ProjectConfig {
+	project := &config.ProjectConfig{
+		Root: root,
+		Env:  map[string]string{},
+		ScopedInstructions: []config.ScopedInstructions{
+			{Dir: "services", Files: []config.InstructionFile{{Name: "00.md", Content: "shared services"}}},
+			{Dir: "services/payments", Files: []config.InstructionFile{{Name: "00.md", Content: "payments"}}},
+			{Dir: "services/search", Files: []config.InstructionFile{{Name: "00.md", Content: "search"}}},
+			{Dir: "apps/web", Files: []config.InstructionFile{{Name: "00.md", Content: "web"}}},
+		},
+	}
+	project.Config.Monorepo = config.MonorepoConfig{
+		Mode:   mode,
+		Owners: map[string][]string{"payments": {"services/payments"}},
+	}
+	return project
+}
+
+func mkdirs(t *testing.T, root string, dirs ...string) {
+	t.Helper()
+	for _, dir := range dirs {
+		if err := os.MkdirAll(filepath.Join(root, filepath.FromSlash(dir)), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func stubSparseCheckout(t *testing.T, dirs []string, ok bool) {
+	t.Helper()
+	original := sparseCheckoutDirsFunc
+	sparseCheckoutDirsFunc = func(string) ([]string, bool) { return dirs, ok }
+	t.Cleanup(func() { sparseCheckoutDirsFunc = original })
+}
+
+func TestWriteScopedInstructions_Full(t *testing.T) {
+	root := t.TempDir()
+	mkdirs(t, root, "services/payments", "services/search")
+	stubSparseCheckout(t, nil, false)
+
+	if err := writeScopedInstructions(RealSystem{}, root, scopedProject(root, "")); err != nil {
+		t.Fatalf("writeScopedInstructions: %v", err)
+	}
+	for _, dir := range []string{"services", "services/payments", "services/search"} {
+		for _, name := range scopedInstructionFiles {
+			data, err := os.ReadFile(filepath.Join(root, dir, name))
+			if err != nil {
+				t.Fatalf("read %s/%s: %v", dir, name, err)
+			}
+			if !strings.Contains(string(data), "Source: .agent-layer/scoped/"+dir+"/*.md") {
+				t.Fatalf("%s/%s header:\n%s", dir, name, data)
+			}
+		}
+	}
+	if _, err := os.Stat(filepath.Join(root, "apps")); !os.IsNotExist(err) {
+		t.Fatalf("expected missing apps/web not to be created, got %v", err)
+	}
+}
+
+func TestWriteScopedInstructions_SparseByOwner(t *testing.T) {
+	root := t.TempDir()
+	mkdirs(t, root, "services/payments", "services/search")
+	stubSparseCheckout(t, []string{"services/search"}, true)
+	stubGetenv(t, map[string]string{config.EnvMonorepoOwner: "payments"})
+
+	// A stale generated file outside the owner's set is removed.
+	if err := writeScopedInstructions(RealSystem{}, root, scopedProject(root, "")); err != nil {
+		t.Fatal(err)
+	}
+	if err := writeScopedInstructions(RealSystem{}, root, scopedProject(root, config.MonorepoModeSparse)); err != nil {
+		t.Fatalf("writeScopedInstructions: %v", err)
+	}
+	for dir, want := range map[string]bool{"services": true, "services/payments": true, "services/search": false} {
+		_, err := os.Stat(filepath.Join(root, dir, "AGENTS.md"))
+		if (err == nil) != want {
+			t.Fatalf("%s/AGENTS.md exists=%v, want %v", dir, err == nil, want)
+		}
+	}
+}
+
+func TestWriteScopedInstructions_SparseCheckout(t *testing.T) {
+	root := t.TempDir()
+	mkdirs(t, root, "services/payments", "services/search")
+	stubSparseCheckout(t, []string{"services/search"}, true)
+	stubGetenv(t, nil)
+
+	if err := writeScopedInstructions(RealSystem{}, root, scopedProject(root, config.MonorepoModeSparse)); err != nil {
+		t.Fatalf("writeScopedInstructions: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(root, "services", "payments", "CLAUDE.md")); !os.IsNotExist(err) {
+		t.Fatalf("expected payments to be skipped, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(root, "services", "search", "CLAUDE.md")); err != nil {
+		t.Fatalf("expected search to be generated: %v", err)
+	}
+}
+
+func TestWriteScopedInstructions_Errors(t *testing.T) {
+	root := t.TempDir()
+	mkdirs(t, root, "services/payments")
+	stubSparseCheckout(t, nil, false)
+	stubGetenv(t, nil)
+
+	project := scopedProject(root, config.MonorepoModeSparse)
+	project.Env[config.EnvMonorepoOwner] = "nobody"
+	if err := writeScopedInstructions(RealSystem{}, root, project); err == nil || !strings.Contains(err.Error(), "known: payments") {
+		t.Fatalf("expected unknown owner error, got %v", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(root, "services", "payments", "AGENTS.md"), []byte("hand written"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := writeScopedInstructions(RealSystem{}, root, scopedProject(root, "")); err == nil || !strings.Contains(err.Error(), "was not generated by al") {
+		t.Fatalf("expected hand-written file error, got %v", err)
+	}
+}
+
+func stubGetenv(t *testing.T, values map[string]string) {
+	t.Helper()
+	original := getenv
+	getenv = func(key string) string { return values[key] }
+	t.Cleanup(func() { getenv = original })
+}
@@ -0,0 +1,355 @@
+package sync
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/conn-castle/agent-layer/internal/config"
+	"github.com/conn-castle/agent-layer/internal/i18n"
+	"github.com/conn-castle/agent-layer/internal/messages"
+)
+
+// EnabledSkills returns the skills whose enabled_when condition holds in
+// root, in their original order. Skills without a condition are always
+// enabled.
+func EnabledSkills(root string, project *config.ProjectConfig) ([]config.Skill, error) {
+	conditions := &skillConditions{sys: RealSystem{}, root: root, resolver: &variableResolver{root: root, cfg: project.Config}}
+	return conditions.filter(project.Skills)
+}
+
+// selectEnabledSkills returns a copy of project without the skills and skill
+// variants whose enabled_when condition is false, so sync projects only the
+// skills that apply to this repo.
+func selectEnabledSkills(sys System, root string, project *config.ProjectConfig) (*config.ProjectConfig, error) {
+	conditions := &skillConditions{sys: sys, root: root, resolver: &variableResolver{root: root, cfg: project.Config}}
+	if !conditions.used(project.Skills) && !conditions.used(project.SkillVariants) {
+		return project, nil
+	}
+	skills, err := conditions.filter(project.Skills)
+	if err != nil {
+		return nil, err
+	}
+	variants, err := conditions.filter(project.SkillVariants)
+	if err != nil {
+		return nil, err
+	}
+	selected := *project
+	selected.Skills = skills
+	selected.SkillVariants = variants
+	return &selected, nil
+}
+
+// skillConditions evaluates enabled_when expressions. An expression is
+// built from true, false, "strings", config.<key>, file_exists("<path>"),
+// ==, !=, !, &&, ||, and parentheses. A config value is true unless it is
+// empty or "false".
+type skillConditions struct {
+	sys      System
+	root     string
+	resolver *variableResolver
+}
+
+func (c *skillConditions) used(skills []config.Skill) bool {
+	for _, skill := range skills {
+		if skill.EnabledWhen != "" {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *skillConditions) filter(skills []config.Skill) ([]config.Skill, error) {
+	enabled := make([]config.Skill, 0, len(skills))
+	for _, skill := range skills {
+		if skill.EnabledWhen == "" {
+			enabled = append(enabled, skill)
+			continue
+		}
+		ok, err := c.evaluate(skill.EnabledWhen)
+		if err != nil {
+			return nil, i18n.Errorf(messages.SyncSkillEnabledWhenInvalidFmt, skill.SourcePath, skill.EnabledWhen, err)
+		}
+		if ok {
+			enabled = append(enabled, skill)
+		}
+	}
+	return enabled, nil
+}
+
+// evaluate parses and evaluates expr. Every operand is evaluated, so a
+// mistake in a branch that does not decide the result still fails.
+func (c *skillConditions) evaluate(expr string) (bool, error) {
+	tokens, err := tokenizeCondition(expr)
+	if err != nil {
+		return false, err
+	}
+	p := &conditionParser{conditions: c, tokens: tokens}
+	value, err := p.or()
+	if err != nil {
+		return false, err
+	}
+	if p.pos < len(p.tokens) {
+		return false, i18n.Errorf(messages.SyncSkillConditionUnexpectedFmt, p.tokens[p.pos])
+	}
+	return conditionTrue(value), nil
+}
+
+func conditionTrue(value string) bool {
+	return value != "" && value != "false"
+}
+
+// tokenizeCondition splits expr into operators, quoted strings (kept with
+// their quotes), and words.
+func tokenizeCondition(expr string) ([]string, error) {
+	var tokens []string
+	for i := 0; i < len(expr); {
+		switch ch := expr[i]; {
+		case ch == ' ' || ch == '\t' || ch == '\n' || ch == '\r':
+			i++
+		case ch == '"':
+			end := i + 1
+			for end < len(expr) && expr[end] != '"' {
+				if expr[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			if end >= len(expr) {
+				return nil, errors.New(i18n.T(messages.SyncSkillConditionUnterminatedQuote))
+			}
+			tokens = append(tokens, expr[i:end+1])
+			i = end + 1
+		case strings.HasPrefix(expr[i:], "&&"), strings.HasPrefix(expr[i:], "||"),
+			strings.HasPrefix(expr[i:], "=="), strings.HasPrefix(expr[i:], "!="):
+			tokens = append(tokens, expr[i:i+2])
+			i += 2
+		case strings.ContainsRune("()!,", rune(ch)):
+			tokens = append(tokens, expr[i:i+1])
+			i++
+		case isConditionWordByte(ch):
+			end := i
+			for end < len(expr) && isConditionWordByte(expr[end]) {
+				end++
+			}
+			tokens = append(tokens, expr[i:end])
+			i = end
+		default:
+			return nil, i18n.Errorf(messages.SyncSkillConditionUnexpectedFmt, expr[i:i+1])
+		}
+	}
+	return tokens, nil
+}
+
+func isConditionWordByte(ch byte) bool {
+	return ch == '_' || ch == '.' || ch == '-' || (ch >= 'a' && ch <= 'z') || (ch >= 'A' && ch <= 'Z') || (ch >= '0' && ch <= '9')
+}
+
+// conditionParser evaluates tokens by recursive descent. Values are strings;
+// booleans are "true" and "false".
+type conditionParser struct {
+	conditions *skillConditions
+	tokens     []string
+	pos        int
+}
+
+func (p *conditionParser) peek() string {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos]
+	}
+	return ""
+}
+
+func (p *conditionParser) next() (string, error) {
+	if p.pos >= len(p.tokens) {
+		return "", errors.New(i18n.T(messages.SyncSkillConditionUnexpectedEnd))
+	}
+	token := p.tokens[p.pos]
+	p.pos++
+	return token, nil
+}
+
+func (p *conditionParser) expect(want string) error {
+	token, err := p.next()
+	if err != nil {
+		return err
+	}
+	if token != want {
+		return i18n.Errorf(messages.SyncSkillConditionUnexpectedFmt, token)
+	}
+	return nil
+}
+
+// or parses and { "||" and }.
+func (p *conditionParser) or() (string, error) {
+	left, err := p.and()
+	if err != nil {
+		return "", err
+	}
+	for p.peek() == "||" {
+		p.pos++
+		right, err := p.and()
+		if err != nil {
+			return "", err
+		}
+		left = strconv.FormatBool(conditionTrue(left) || conditionTrue(right))
+	}
+	return left, nil
+}
+
+// and parses not { "&&" not }.
+func (p *conditionParser) and() (string, error) {
+	left, err := p.not()
+	if err != nil {
+		return "", err
+	}
+	for p.peek() == "&&" {
+		p.pos++
+		right, err := p.not()
+		if err != nil {
+			return "", err
+		}
+		left = strconv.FormatBool(conditionTrue(left) && conditionTrue(right))
+	}
+	return left, nil
+}
+
+// not parses "!" not | compare.
+func (p *conditionParser) not() (string, error) {
+	if p.peek() == "!" {
+		p.pos++
+		value, err := p.not()
+		if err != nil {
+			return "", err
+		}
+		return strconv.FormatBool(!conditionTrue(value)), nil
+	}
+	return p.compare()
+}
+
+// compare parses operand [ ("==" | "!=") operand ].
+func (p *conditionParser) compare() (string, error) {
+	left, err := p.operand()
+	if err != nil {
+		return "", err
+	}
+	op := p.peek()
+	if op != "==" && op != "!=" {
+		return left, nil
+	}
+	p.pos++
+	right, err := p.operand()
+	if err != nil {
+		return "", err
+	}
+	return strconv.FormatBool((left == right) == (op == "==")), nil
+}
+
+// operand parses "(" or ")", a string, a literal, config.<key>, or a call.
+func (p *conditionParser) operand() (string, error) {
+	token, err := p.next()
+	if err != nil {
+		return "", err
+	}
+	switch {
+	case token == "(":
+		value, err := p.or()
+		if err != nil {
+			return "", err
+		}
+		return value, p.expect(")")
+	case strings.HasPrefix(token, `"`):
+		value, err := strconv.Unquote(token)
+		if err != nil {
+			return "", i18n.Errorf(messages.SyncSkillConditionUnexpectedFmt, token)
+		}
+		return value, nil
+	case token == "true" || token == "false":
+		return token, nil
+	case token[0] >= '0' && token[0] <= '9':
+		return token, nil
+	case strings.HasPrefix(token, "config."):
+		value, ok := p.conditions.resolver.configValue(strings.TrimPrefix(token, "config."))
+		if !ok {
+			return "", i18n.Errorf(messages.SyncSkillConditionUnknownConfigFmt, token)
+		}
+		return value, nil
+	case p.peek() == "(":
+		return p.call(token)
+	case !isConditionWordByte(token[0]):
+		return "", i18n.Errorf(messages.SyncSkillConditionUnexpectedFmt, token)
+	}
+	return "", i18n.Errorf(messages.SyncSkillConditionUnknownNameFmt, token)
+}
+
+// call parses the argument list of function name and applies it.
+func (p *conditionParser) call(name string) (string, error) {
+	if name != "file_exists" {
+		return "", i18n.Errorf(messages.SyncSkillConditionUnknownFuncFmt, name)
+	}
+	p.pos++
+	arg, err := p.next()
+	if err != nil {
+		return "", err
+	}
+	if !strings.HasPrefix(arg, `"`) {
+		return "", i18n.Errorf(messages.SyncSkillConditionArgsFmt, name)
+	}
+	path, err := strconv.Unquote(arg)
+	if err != nil {
+		return "", i18n.Errorf(messages.SyncSkillConditionUnexpectedFmt, arg)
+	}
+	if err := p.expect(")"); err != nil {
+		return "", err
+	}
+	return p.conditions.fileExists(path)
+}
+
+// conditionFilePaths returns the paths that file_exists calls in the
+// enabled_when conditions of skills check. Conditions that do not tokenize and
+// paths outside the repo are skipped; sync reports them.
+func conditionFilePaths(skills ...[]config.Skill) []string {
+	seen := make(map[string]bool)
+	var paths []string
+	for _, list := range skills {
+		for _, skill := range list {
+			if skill.EnabledWhen == "" {
+				continue
+			}
+			tokens, err := tokenizeCondition(skill.EnabledWhen)
+			if err != nil {
+				continue
+			}
+			for i := 0; i+2 < len(tokens); i++ {
+				if tokens[i] != "file_exists" || tokens[i+1] != "(" || !strings.HasPrefix(tokens[i+2], `"`) {
+					continue
+				}
+				path, err := strconv.Unquote(tokens[i+2])
+				if err != nil || !filepath.IsLocal(filepath.FromSlash(path)) || seen[path] {
+					continue
+				}
+				seen[path] = true
+				paths = append(paths, path)
+			}
+		}
+	}
+	return paths
+}
+
+// fileExists reports whether the repo-relative path exists, as a file or a
+// directory.
+func (c *skillConditions) fileExists(path string) (string, error) {
+	if !filepath.IsLocal(filepath.FromSlash(path)) {
+		return "", i18n.Errorf(messages.SyncSkillConditionPathInvalidFmt, path)
+	}
+	_, err := c.sys.Stat(filepath.Join(c.root, filepath.FromSlash(path)))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "false", nil
+		}
+		return "", i18n.Errorf(messages.SyncStatFailedFmt, path, err)
+	}
+	return "true", nil
+}
@@ -0,0 +1,92 @@
+package sync
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/conn-castle/agent-layer/internal/config"
+	"github.com/conn-castle/agent-layer/internal/errcode"
+)
+
+func TestSkillConditionsEvaluate(t *testing.T) {
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, "go.mod"), []byte("module x\n"), 0o600); err != nil {
+		t.Fatalf("write go.mod: %v", err)
+	}
+	enabled := true
+	cfg := config.Config{Agents: config.AgentsConfig{Claude: config.ClaudeConfig{Enabled: &enabled}}}
+	conditions := &skillConditions{sys: RealSystem{}, root: root, resolver: &variableResolver{root: root, cfg: cfg}}
+
+	tests := []struct {
+		expr string
+		want bool
+		err  string
+	}{
+		{expr: "true", want: true},
+		{expr: "false"},
+		{expr: `file_exists("go.mod")`, want: true},
+		{expr: `file_exists("package.json")`},
+		{expr: `!file_exists("package.json") && config.agents.claude.enabled`, want: true},
+		{expr: `config.agents.codex.enabled || (file_exists("go.mod") && true)`, want: true},
+		{expr: `config.agents.claude.enabled == "true"`, want: true},
+		{expr: `config.agents.claude.enabled != true`},
+		{expr: "config.agents.codex.model"},
+		{expr: "config.agents.nope", err: "unknown config key config.agents.nope"},
+		{expr: "go_mod", err: "unknown name go_mod"},
+		{expr: `dir_exists("src")`, err: "unknown function dir_exists"},
+		{expr: `file_exists("../outside")`, err: "must be relative and inside the repo"},
+		{expr: `file_exists(go.mod)`, err: "file_exists takes one string argument"},
+		{expr: `file_exists("go.mod"`, err: "unexpected end of expression"},
+		{expr: `"unterminated`, err: "unterminated string"},
+		{expr: "true false", err: `unexpected "false"`},
+		{expr: "true && $x", err: `unexpected "$"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			got, err := conditions.evaluate(tt.expr)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("error = %v, want containing %q", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("evaluate: %v", err)
+			}
+			if got != tt.want {
+				t.Fatalf("evaluate = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRun_SkillEnabledWhen(t *testing.T) {
+	root, project := syncFixtureProject(t)
+	if len(project.Skills) < 2 {
+		t.Fatalf("fixture skills = %d, want at least 2", len(project.Skills))
+	}
+	disabled, kept := project.Skills[0].Name, project.Skills[1].Name
+	project.Skills[0].EnabledWhen = `file_exists("package.json")`
+	project.Skills[1].EnabledWhen = `file_exists(".agent-layer")`
+
+	if _, err := RunWithProjectOptions(RealSystem{}, root, project, RunOptions{}); err != nil {
+		t.Fatalf("sync: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(root, ".agents", "skills", disabled, "SKILL.md")); !os.IsNotExist(err) {
+		t.Fatalf("disabled skill %s was projected: %v", disabled, err)
+	}
+	if _, err := os.Stat(filepath.Join(root, ".agents", "skills", kept, "SKILL.md")); err != nil {
+		t.Fatalf("enabled skill %s missing: %v", kept, err)
+	}
+
+	project.Skills[0].EnabledWhen = "file_exists("
+	_, err := RunWithProjectOptions(RealSystem{}, root, project, RunOptions{DryRun: true})
+	if err == nil || !strings.Contains(err.Error(), "enabled_when") {
+		t.Fatalf("error = %v, want enabled_when error", err)
+	}
+	if got := errcode.Of(err); got != errcode.Config {
+		t.Fatalf("code = %s, want %s", got, errcode.Config)
+	}
+}
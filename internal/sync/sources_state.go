@@ -0,0 +1,270 @@
+package sync
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/conn-castle/agent-layer/internal/config"
+	"github.com/conn-castle/agent-layer/internal/i18n"
+	"github.com/conn-castle/agent-layer/internal/layerdir"
+	"github.com/conn-castle/agent-layer/internal/messages"
+)
+
+// sourcesStateFile records a hash of every source file and the Agent Layer
+// version as of the last sync, so a launch can tell whether the generated
+// outputs are stale without running sync. Sources are the .agent-layer/ files,
+// the user-global skills, the resolved extends base bundle, and whether each
+// path a skill's enabled_when file_exists condition checks exists.
+const sourcesStateFile = "sync-sources.json"
+
+// sourcesState is the on-disk shape of sourcesStateFile. Files maps each
+// slash-separated path to the hash of its content: paths under .agent-layer/
+// are relative to it, and user skills and extends base files are absolute.
+// FileExists maps each repo-relative path a file_exists condition checks to
+// whether it existed.
+type sourcesState struct {
+	Version    string            `json:"version"`
+	Files      map[string]string `json:"files"`
+	FileExists map[string]bool   `json:"file_exists,omitempty"`
+}
+
+// skippedSourceDirs are .agent-layer/ directories that never feed sync.
+var skippedSourceDirs = map[string]bool{"state": true, "tmp": true, "transcripts": true, "templates": true}
+
+// Staleness describes how the sources differ from the last recorded sync.
+type Staleness struct {
+	// Recorded is false when no sync has recorded its sources yet.
+	Recorded bool
+	// PreviousVersion is the Agent Layer version of the last sync when it
+	// differs from the running one.
+	PreviousVersion string
+	// Paths lists the sorted sources added, removed, or edited since the last
+	// sync, in the form sourcesState.Files keys them, and file_exists("<path>")
+	// for each checked path that appeared or disappeared.
+	Paths []string
+}
+
+// Stale reports whether the outputs may not match the sources.
+func (s Staleness) Stale() bool {
+	return !s.Recorded || s.PreviousVersion != "" || len(s.Paths) > 0
+}
+
+func sourcesStatePath(root string) string {
+	return filepath.Join(config.StateDir(root), sourcesStateFile)
+}
+
+// CheckSources compares the sources of root's project with the state the
+// last sync recorded. project supplies the extends base and the skill
+// conditions; nil leaves them out.
+// Other inputs, such as shell environment variables that config placeholders
+// read, are not tracked.
+func CheckSources(sys System, root string, project *config.ProjectConfig) (Staleness, error) {
+	current, err := currentSourcesState(sys, root, project)
+	if err != nil {
+		return Staleness{}, err
+	}
+	previous, found, err := readSourcesState(sys, sourcesStatePath(root))
+	if err != nil || !found {
+		return Staleness{}, err
+	}
+	staleness := Staleness{Recorded: true, Paths: changedSourcesState(previous, current)}
+	if previous.Version != current.Version {
+		staleness.PreviousVersion = previous.Version
+	}
+	return staleness, nil
+}
+
+// recordSourcesState records the sources as sync saw them. It runs as the
+// last sync step so files sync itself writes under .agent-layer/, such as
+// al.lock, are recorded as written. project must still list the skills whose
+// conditions are false, so their file_exists paths are recorded too.
+func recordSourcesState(sys System, root string, project *config.ProjectConfig) error {
+	current, err := currentSourcesState(sys, root, project)
+	if err != nil {
+		return err
+	}
+	path := sourcesStatePath(root)
+	previous, found, err := readSourcesState(sys, path)
+	if err != nil {
+		return err
+	}
+	if found && previous.Version == current.Version && len(changedSourcesState(previous, current)) == 0 {
+		return nil
+	}
+	data, err := sys.MarshalIndent(current, "", "  ")
+	if err != nil {
+		return i18n.Errorf(messages.SyncMarshalSourcesStateFailedFmt, err)
+	}
+	data = append(data, '\n')
+	if err := sys.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return i18n.Errorf(messages.SyncCreateDirFailedFmt, filepath.Dir(path), err)
+	}
+	if err := sys.WriteFileAtomic(path, data, 0o644); err != nil {
+		return i18n.Errorf(messages.SyncWriteFileFailedFmt, path, err)
+	}
+	return nil
+}
+
+// readSourcesState loads the recorded state. A missing or unreadable record
+// is treated as no record, which makes the outputs stale.
+func readSourcesState(sys System, path string) (sourcesState, bool, error) {
+	data, err := sys.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return sourcesState{}, false, nil
+		}
+		return sourcesState{}, false, i18n.Errorf(messages.SyncReadSourcesStateFailedFmt, path, err)
+	}
+	var state sourcesState
+	if err := json.Unmarshal(data, &state); err != nil || state.Files == nil {
+		return sourcesState{}, false, nil
+	}
+	return state, true, nil
+}
+
+// currentSourcesState hashes every source file under .agent-layer/, leaving
+// out runtime directories, backups, the sync lock, and the generated VS Code
+// launchers, then every file of the user-global skills directory and of the
+// project's extends base. A missing directory contributes no files. It then
+// checks every path the project's file_exists conditions name.
+func currentSourcesState(sys System, root string, project *config.ProjectConfig) (sourcesState, error) {
+	state := sourcesState{Version: GeneratorVersion, Files: make(map[string]string)}
+	if err := hashSourceDir(sys, layerdir.Dir(root), "", skipSource, state.Files); err != nil {
+		return sourcesState{}, err
+	}
+	var extra []string
+	if dir, err := config.UserSkillsDir(); err == nil {
+		extra = append(extra, dir)
+	}
+	if project != nil && project.ExtendsDir != "" {
+		extra = append(extra, project.ExtendsDir)
+	}
+	for _, dir := range extra {
+		keep := func(string, string) bool { return false }
+		if err := hashSourceDir(sys, dir, filepath.ToSlash(dir), keep, state.Files); err != nil {
+			return sourcesState{}, err
+		}
+	}
+	if project == nil {
+		return state, nil
+	}
+	conditions := &skillConditions{sys: sys, root: root}
+	for _, path := range conditionFilePaths(project.Skills, project.SkillVariants) {
+		exists, err := conditions.fileExists(path)
+		if err != nil {
+			return sourcesState{}, err
+		}
+		if state.FileExists == nil {
+			state.FileExists = make(map[string]bool)
+		}
+		state.FileExists[path] = conditionTrue(exists)
+	}
+	return state, nil
+}
+
+// hashSourceDir records the hash of every file under dir in files, keyed by
+// its slash-separated path below dir joined to prefix. skip reports whether
+// a name in the directory rel, relative to dir, is left out.
+func hashSourceDir(sys System, dir string, prefix string, skip func(rel string, name string) bool, files map[string]string) error {
+	var walk func(rel string) error
+	walk = func(rel string) error {
+		entries, err := sys.ReadDir(filepath.Join(dir, filepath.FromSlash(rel)))
+		if err != nil {
+			if rel == "" && errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return i18n.Errorf(messages.SyncReadSourcesFailedFmt, filepath.Join(dir, filepath.FromSlash(rel)), err)
+		}
+		sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+		for _, entry := range entries {
+			name := entry.Name()
+			child := joinSourcePath(rel, name)
+			if skip(rel, name) {
+				continue
+			}
+			if entry.IsDir() {
+				if err := walk(child); err != nil {
+					return err
+				}
+				continue
+			}
+			data, err := sys.ReadFile(filepath.Join(dir, filepath.FromSlash(child)))
+			if err != nil {
+				// A symlink to a directory reads as EISDIR.
+				if errors.Is(err, fs.ErrNotExist) || errors.Is(err, syscall.EISDIR) {
+					continue
+				}
+				return i18n.Errorf(messages.SyncReadSourcesFailedFmt, filepath.Join(dir, filepath.FromSlash(child)), err)
+			}
+			files[joinSourcePath(prefix, child)] = hashBytes(data)
+		}
+		return nil
+	}
+	return walk("")
+}
+
+// skipSource reports whether name in the .agent-layer/ directory rel is not
+// a sync source.
+func skipSource(rel string, name string) bool {
+	if strings.HasSuffix(name, ".bak") {
+		return true
+	}
+	if rel != "" {
+		return false
+	}
+	return skippedSourceDirs[name] || name == "sync.lock" || strings.HasPrefix(name, "open-vscode")
+}
+
+func joinSourcePath(rel string, name string) string {
+	if rel == "" {
+		return name
+	}
+	return rel + "/" + name
+}
+
+// changedSourcesState returns the changed files of changedSources followed
+// by a file_exists("<path>") entry for each checked path whose existence
+// differs, or that only one state checks, sorted.
+func changedSourcesState(previous sourcesState, current sourcesState) []string {
+	out := changedSources(previous.Files, current.Files)
+	for path, exists := range current.FileExists {
+		if was, ok := previous.FileExists[path]; !ok || was != exists {
+			out = append(out, fileExistsSource(path))
+		}
+	}
+	for path := range previous.FileExists {
+		if _, ok := current.FileExists[path]; !ok {
+			out = append(out, fileExistsSource(path))
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+func fileExistsSource(path string) string {
+	return "file_exists(" + strconv.Quote(path) + ")"
+}
+
+// changedSources returns the paths added, removed, or edited between
+// previous and current, sorted.
+func changedSources(previous map[string]string, current map[string]string) []string {
+	var out []string
+	for path, hash := range current {
+		if previous[path] != hash {
+			out = append(out, path)
+		}
+	}
+	for path := range previous {
+		if _, ok := current[path]; !ok {
+			out = append(out, path)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
@@ -0,0 +1,156 @@
+package sync
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/conn-castle/agent-layer/internal/config"
+)
+
+func TestCheckSources_ReportsChangesSinceRecordedSync(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	root := t.TempDir()
+	dir := filepath.Join(root, ".agent-layer")
+	for path, content := range map[string]string{
+		"config.toml":             "[approvals]\n",
+		"instructions/00_base.md": "# Base\n",
+		"tmp/runs/run.json":       "{}",
+		"open-vscode.sh":          "#!/bin/sh\n",
+		"config.toml.bak":         "old",
+	} {
+		full := filepath.Join(dir, filepath.FromSlash(path))
+		if err := os.MkdirAll(filepath.Dir(full), 0o700); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(full, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	staleness, err := CheckSources(RealSystem{}, root, nil)
+	if err != nil || staleness.Recorded || !staleness.Stale() {
+		t.Fatalf("before any sync = %+v, %v; want unrecorded and stale", staleness, err)
+	}
+	if err := recordSourcesState(RealSystem{}, root, nil); err != nil {
+		t.Fatalf("recordSourcesState: %v", err)
+	}
+	if staleness, err := CheckSources(RealSystem{}, root, nil); err != nil || staleness.Stale() {
+		t.Fatalf("after sync = %+v, %v; want fresh", staleness, err)
+	}
+
+	// Runtime files, backups, and generated launchers are not sources.
+	for _, path := range []string{"tmp/runs/other.json", "open-vscode.sh", "config.toml.bak"} {
+		if err := os.WriteFile(filepath.Join(dir, filepath.FromSlash(path)), []byte("changed"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if staleness, err := CheckSources(RealSystem{}, root, nil); err != nil || staleness.Stale() {
+		t.Fatalf("after runtime writes = %+v, %v; want fresh", staleness, err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "config.toml"), []byte("[approvals]\nmode = \"all\"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Remove(filepath.Join(dir, "instructions", "00_base.md")); err != nil {
+		t.Fatal(err)
+	}
+	staleness, err = CheckSources(RealSystem{}, root, nil)
+	if err != nil {
+		t.Fatalf("CheckSources: %v", err)
+	}
+	if want := []string{"config.toml", "instructions/00_base.md"}; !reflect.DeepEqual(staleness.Paths, want) {
+		t.Fatalf("paths = %v, want %v", staleness.Paths, want)
+	}
+
+	if err := recordSourcesState(RealSystem{}, root, nil); err != nil {
+		t.Fatal(err)
+	}
+	original := GeneratorVersion
+	GeneratorVersion = "9.9.9"
+	t.Cleanup(func() { GeneratorVersion = original })
+	staleness, err = CheckSources(RealSystem{}, root, nil)
+	if err != nil || staleness.PreviousVersion != original || len(staleness.Paths) != 0 {
+		t.Fatalf("after upgrade = %+v, %v; want previous version %q", staleness, err, original)
+	}
+}
+
+func TestCheckSources_TracksUserSkillsAndExtendsBase(t *testing.T) {
+	configHome := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", configHome)
+	root := t.TempDir()
+	userSkill := filepath.Join(configHome, "agent-layer", "skills", "notes", "SKILL.md")
+	base := t.TempDir()
+	baseInstruction := filepath.Join(base, "instructions", "00_team.md")
+	for path, content := range map[string]string{
+		filepath.Join(root, ".agent-layer", "config.toml"): "[approvals]\n",
+		userSkill:       "---\nname: notes\n---\n",
+		baseInstruction: "# Team\n",
+	} {
+		if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	project := &config.ProjectConfig{ExtendsDir: base}
+	if err := recordSourcesState(RealSystem{}, root, project); err != nil {
+		t.Fatalf("recordSourcesState: %v", err)
+	}
+	if staleness, err := CheckSources(RealSystem{}, root, project); err != nil || staleness.Stale() {
+		t.Fatalf("after sync = %+v, %v; want fresh", staleness, err)
+	}
+
+	for _, path := range []string{userSkill, baseInstruction} {
+		if err := os.WriteFile(path, []byte("changed"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	staleness, err := CheckSources(RealSystem{}, root, project)
+	if err != nil {
+		t.Fatalf("CheckSources: %v", err)
+	}
+	want := []string{filepath.ToSlash(userSkill), filepath.ToSlash(baseInstruction)}
+	sort.Strings(want)
+	if !reflect.DeepEqual(staleness.Paths, want) {
+		t.Fatalf("paths = %v, want %v", staleness.Paths, want)
+	}
+}
+
+func TestCheckSources_TracksFileExistsConditions(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, ".agent-layer"), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "go.mod"), []byte("module x\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	project := &config.ProjectConfig{
+		Skills:        []config.Skill{{Name: "go", EnabledWhen: `file_exists("go.mod")`}},
+		SkillVariants: []config.Skill{{Name: "node", EnabledWhen: `!file_exists("package.json") || file_exists("../outside")`}},
+	}
+	if err := recordSourcesState(RealSystem{}, root, project); err != nil {
+		t.Fatalf("recordSourcesState: %v", err)
+	}
+	if staleness, err := CheckSources(RealSystem{}, root, project); err != nil || staleness.Stale() {
+		t.Fatalf("after sync = %+v, %v; want fresh", staleness, err)
+	}
+
+	if err := os.Remove(filepath.Join(root, "go.mod")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "package.json"), []byte("{}"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	staleness, err := CheckSources(RealSystem{}, root, project)
+	if err != nil {
+		t.Fatalf("CheckSources: %v", err)
+	}
+	if want := []string{`file_exists("go.mod")`, `file_exists("package.json")`}; !reflect.DeepEqual(staleness.Paths, want) {
+		t.Fatalf("paths = %v, want %v", staleness.Paths, want)
+	}
+}
@@ -0,0 +1,174 @@
+package sync
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/pelletier/go-toml/v2"
+
+	"github.com/conn-castle/agent-layer/internal/config"
+	"github.com/conn-castle/agent-layer/internal/i18n"
+	"github.com/conn-castle/agent-layer/internal/messages"
+)
+
+// StripManagedSettings returns the content of rel, one of the settings files
+// sync patches in place (.claude/settings.json, .codex/config.toml, or
+// .vscode/settings.json), with the entries sync manages removed. empty is
+// true when nothing but managed entries was left, so the file can be deleted.
+// Claude keys come from the record under .agent-layer/state/, so call it
+// before state is removed. Other paths return ok false.
+func StripManagedSettings(root string, rel string) (content string, empty bool, ok bool, err error) {
+	sys := RealSystem{}
+	path := filepath.Join(root, filepath.FromSlash(rel))
+	switch rel {
+	case ".claude/settings.json":
+		content, empty, err = stripClaudeSettings(sys, root, path)
+	case ".codex/config.toml":
+		content, empty, err = stripCodexSettings(sys, root, path)
+	case ".vscode/settings.json":
+		content, empty, err = stripVSCodeSettings(sys, path)
+	default:
+		return "", false, false, nil
+	}
+	return content, empty, true, err
+}
+
+// stripClaudeSettings removes the leaf paths the last sync recorded.
+func stripClaudeSettings(sys System, root string, path string) (string, bool, error) {
+	existing, err := readNativeSettings(sys, path, "Claude")
+	if err != nil {
+		return "", false, err
+	}
+	previous, err := readClaudeManagedKeys(sys, claudeManagedKeysPath(root))
+	if err != nil {
+		return "", false, err
+	}
+	for _, managed := range previous {
+		removeManagedSettingsPath(existing, managed)
+	}
+	if len(existing) == 0 {
+		return "", true, nil
+	}
+	data, err := sys.MarshalIndent(existing, "", "  ")
+	if err != nil {
+		return "", false, i18n.Errorf(messages.SyncMarshalClaudeSettingsFailedFmt, err)
+	}
+	return string(data) + "\n", false, nil
+}
+
+// stripCodexSettings removes the entries mergeCodexConfig owns: the header,
+// the managed root keys, feature toggles, and status line, this repo's
+// project trust, mcp_servers, the chime hook, and the profile fields and
+// agent_specific paths config.toml still lists. Tables the removals left
+// empty are dropped.
+func stripCodexSettings(sys System, root string, path string) (string, bool, error) {
+	existing, err := readExistingCodexConfig(sys, path)
+	if err != nil {
+		return "", false, err
+	}
+	trustedRoot, err := codexTrustedProjectRoot(root)
+	if err != nil {
+		return "", false, err
+	}
+	editor := newCodexTomlEditor(existing)
+	editor.removeAgentLayerHeader()
+	for _, key := range codexManagedRootScalarKeys {
+		editor.removePath([]string{key})
+	}
+	for _, key := range config.CodexKnownManagedFeatureKeys() {
+		editor.removePath([]string{codexFeaturesKey, key})
+	}
+	editor.removePath([]string{codexTUIKey, codexStatusLineKey})
+	if cfg, err := config.LoadConfigLenient(config.DefaultPaths(root).ConfigPath); err == nil {
+		for name, profile := range cfg.Agents.Codex.Profiles {
+			for _, field := range codexProfileFields(profile) {
+				editor.removePath([]string{config.CodexProfilesKey, name, field.key})
+			}
+		}
+		for _, item := range agentSpecificLeafValues(cfg.Agents.Codex.AgentSpecific) {
+			editor.removePath(item.path)
+		}
+	}
+	editor.removeNamespace([]string{config.CodexMCPServersKey})
+	editor.removeNamespace([]string{config.CodexProjectsKey, trustedRoot})
+	if _, err := editor.applyCodexChimeHook(path, false); err != nil {
+		return "", false, err
+	}
+	editor.removeEmptyTables([]string{codexFeaturesKey}, []string{codexTUIKey}, []string{config.CodexProfilesKey}, []string{config.CodexProjectsKey})
+	out := editor.render()
+	if strings.TrimSpace(out) == "" {
+		return "", true, nil
+	}
+	var renderCheck map[string]any
+	if err := toml.Unmarshal([]byte(out), &renderCheck); err != nil {
+		return "", false, fmt.Errorf("stripped Codex config is invalid TOML: %w", err)
+	}
+	return out, false, nil
+}
+
+// removeAgentLayerHeader deletes a known generated header from the leading
+// comment block.
+func (e *codexTomlEditor) removeAgentLayerHeader() {
+	preambleEnd := e.leadingPreambleEnd()
+	for _, known := range []string{codexHeader, codexHeaderWithStatusline, codexPartialHeader} {
+		knownLines := headerLines(known)
+		if start, ok := findLineSequence(e.lines[:preambleEnd], knownLines); ok {
+			e.lines = replaceLineRange(e.lines, start, start+len(knownLines), nil)
+			return
+		}
+	}
+}
+
+// removeEmptyTables deletes table headers under any of prefixes that have
+// nothing but blank lines before the next header.
+func (e *codexTomlEditor) removeEmptyTables(prefixes ...[]string) {
+	headers := e.headerLines()
+	var ranges []lineRange
+	for k, header := range headers {
+		if header.isArray || !header.parsed || !slices.ContainsFunc(prefixes, func(prefix []string) bool { return pathHasPrefix(header.path, prefix) }) {
+			continue
+		}
+		end := len(e.lines)
+		if k+1 < len(headers) {
+			end = headers[k+1].index
+		}
+		blank := true
+		for _, line := range e.lines[header.index+1 : end] {
+			if strings.TrimSpace(line) != "" {
+				blank = false
+				break
+			}
+		}
+		if blank {
+			ranges = append(ranges, lineRange{start: header.index, end: header.index})
+		}
+	}
+	e.removeRanges(ranges)
+}
+
+// stripVSCodeSettings removes the managed block between its markers.
+func stripVSCodeSettings(sys System, path string) (string, bool, error) {
+	data, err := sys.ReadFile(path)
+	if err != nil {
+		return "", false, i18n.Errorf(messages.SyncReadFailedFmt, path, err)
+	}
+	existing := string(data)
+	newline := detectNewline(existing)
+	bom, normalized := stripUTF8BOM(normalizeNewlines(existing))
+	lines := strings.Split(normalized, "\n")
+	start, end, _, found, err := findVSCodeManagedBlock(lines, 0, len(lines)-1)
+	if err != nil {
+		return "", false, i18n.Errorf(messages.SyncInvalidVSCodeSettingsFmt, path, invalidVSCodeSettingsError(err.Error()))
+	}
+	if !found {
+		return existing, false, nil
+	}
+	lines = replaceVSCodeManagedBlock(lines, start, end, nil)
+	updated := strings.Join(lines, "\n")
+	if strings.Join(strings.Fields(updated), "") == "{}" {
+		return "", true, nil
+	}
+	return applyNewlineStyle(bom+updated, newline), false, nil
+}
@@ -0,0 +1,83 @@
+package sync
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var strippedSettingsFiles = []string{".claude/settings.json", ".codex/config.toml", ".vscode/settings.json"}
+
+func TestStripManagedSettings_FreshOutputsAreEmpty(t *testing.T) {
+	root, project := loadSyncFixtureProject(t)
+	if _, err := RunWithProject(RealSystem{}, root, project); err != nil {
+		t.Fatalf("RunWithProject: %v", err)
+	}
+	for _, rel := range strippedSettingsFiles {
+		content, empty, ok, err := StripManagedSettings(root, rel)
+		if err != nil || !ok {
+			t.Fatalf("StripManagedSettings(%s) ok=%v err=%v", rel, ok, err)
+		}
+		if !empty {
+			t.Fatalf("StripManagedSettings(%s) left content:\n%s", rel, content)
+		}
+	}
+}
+
+func TestStripManagedSettings_KeepsUserEntries(t *testing.T) {
+	root, project := loadSyncFixtureProject(t)
+	user := map[string]string{
+		".claude/settings.json": "{\"env\": {\"MINE\": \"1\"}}\n",
+		".codex/config.toml":    "# my notes\nmine = true\n\n[mine_table]\nkey = \"value\"\n",
+		".vscode/settings.json": "{\n  \"editor.mine\": true\n}\n",
+	}
+	for rel, content := range user {
+		path := filepath.Join(root, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := RunWithProject(RealSystem{}, root, project); err != nil {
+		t.Fatalf("RunWithProject: %v", err)
+	}
+	managed := map[string][]string{
+		".claude/settings.json": {"permissions"},
+		".codex/config.toml":    {"GENERATED", "trust_level", "mcp_servers"},
+		".vscode/settings.json": {"agent-layer"},
+	}
+	kept := map[string][]string{
+		".claude/settings.json": {"MINE"},
+		".codex/config.toml":    {"# my notes", "mine = true", "[mine_table]"},
+		".vscode/settings.json": {"editor.mine"},
+	}
+	for _, rel := range strippedSettingsFiles {
+		synced := readFileForTest(t, filepath.Join(root, filepath.FromSlash(rel)))
+		content, empty, ok, err := StripManagedSettings(root, rel)
+		if err != nil || !ok || empty {
+			t.Fatalf("StripManagedSettings(%s) ok=%v empty=%v err=%v", rel, ok, empty, err)
+		}
+		for _, want := range managed[rel] {
+			if !strings.Contains(synced, want) {
+				t.Fatalf("%s: fixture sync wrote no %q:\n%s", rel, want, synced)
+			}
+			if strings.Contains(content, want) {
+				t.Fatalf("%s: stripped content still has %q:\n%s", rel, want, content)
+			}
+		}
+		for _, want := range kept[rel] {
+			if !strings.Contains(content, want) {
+				t.Fatalf("%s: stripped content lost %q:\n%s", rel, want, content)
+			}
+		}
+	}
+}
+
+func TestStripManagedSettings_OtherPath(t *testing.T) {
+	if _, _, ok, err := StripManagedSettings(t.TempDir(), ".mcp.json"); ok || err != nil {
+		t.Fatalf("ok=%v err=%v, want not handled", ok, err)
+	}
+}
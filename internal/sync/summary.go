@@ -0,0 +1,95 @@
+package sync
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Client names that tag the changes each client's sync steps stage. They
+// match the client names mcp.servers[].clients accepts.
+const (
+	summaryClientAntigravity = "antigravity"
+	summaryClientClaude      = "claude"
+	summaryClientCodex       = "codex"
+	summaryClientCopilot     = "copilot"
+	summaryClientVSCode      = "vscode"
+)
+
+// ClientSummary lists the generated outputs one client's sync steps wrote,
+// left unchanged, and deleted. Client is empty for outputs every client
+// shares, such as AGENTS.md and .gitignore. Paths are repo-relative and
+// sorted; a path appears once, in the state the run left it.
+type ClientSummary struct {
+	Client    string
+	Written   []string
+	Unchanged []string
+	Deleted   []string
+}
+
+// Summary groups the run's outputs by client: shared outputs first, then
+// clients by name. Directory creation and sync's own state under
+// .agent-layer/state are left out. Under RunOptions.DryRun it describes what
+// the run would have done.
+func (r *Result) Summary(root string) []ClientSummary {
+	type outcome struct {
+		client string
+		kind   int
+	}
+	const (
+		written = iota
+		unchanged
+		deleted
+	)
+	stateDir := filepath.Dir(configStatePath(root))
+	outcomes := make(map[string]outcome)
+	record := func(change Change, kind int) {
+		if change.Kind == ChangeMkdir || change.Path == stateDir || strings.HasPrefix(change.Path, stateDir+string(os.PathSeparator)) {
+			return
+		}
+		rel := change.Path
+		if r, err := filepath.Rel(root, change.Path); err == nil {
+			rel = filepath.ToSlash(r)
+		}
+		outcomes[rel] = outcome{client: change.Client, kind: kind}
+	}
+	// Unchanged writes go first so a later write or removal of the same
+	// path overrides them.
+	for _, change := range r.Unchanged {
+		record(change, unchanged)
+	}
+	for _, change := range r.Changes {
+		if change.Kind == ChangeRemove || change.Kind == ChangeRemoveAll {
+			record(change, deleted)
+		} else {
+			record(change, written)
+		}
+	}
+
+	byClient := make(map[string]*ClientSummary)
+	for rel, o := range outcomes {
+		summary, ok := byClient[o.client]
+		if !ok {
+			summary = &ClientSummary{Client: o.client}
+			byClient[o.client] = summary
+		}
+		switch o.kind {
+		case written:
+			summary.Written = append(summary.Written, rel)
+		case unchanged:
+			summary.Unchanged = append(summary.Unchanged, rel)
+		default:
+			summary.Deleted = append(summary.Deleted, rel)
+		}
+	}
+	out := make([]ClientSummary, 0, len(byClient))
+	for _, summary := range byClient {
+		sort.Strings(summary.Written)
+		sort.Strings(summary.Unchanged)
+		sort.Strings(summary.Deleted)
+		out = append(out, *summary)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Client < out[j].Client })
+	return out
+}
@@ -0,0 +1,85 @@
+package sync
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/conn-castle/agent-layer/internal/config"
+)
+
+func TestResultSummary_GroupsByClient(t *testing.T) {
+	root := "/repo"
+	result := &Result{
+		Unchanged: []Change{
+			{Kind: ChangeWrite, Path: "/repo/AGENTS.md"},
+			{Kind: ChangeWrite, Path: "/repo/.mcp.json", Client: summaryClientClaude},
+			{Kind: ChangeWrite, Path: "/repo/.claude/settings.json", Client: summaryClientClaude},
+		},
+		Changes: []Change{
+			{Kind: ChangeMkdir, Path: "/repo/.claude", Client: summaryClientClaude},
+			{Kind: ChangeWrite, Path: "/repo/.claude/settings.json", Client: summaryClientClaude},
+			{Kind: ChangeRemoveAll, Path: "/repo/.claude/skills/old", Client: summaryClientClaude},
+			{Kind: ChangeWrite, Path: "/repo/.codex/config.toml", Client: summaryClientCodex},
+			{Kind: ChangeWrite, Path: "/repo/.agent-layer/state/config-state.json"},
+		},
+	}
+	got := result.Summary(root)
+	want := []ClientSummary{
+		{Client: "", Unchanged: []string{"AGENTS.md"}},
+		{Client: summaryClientClaude, Written: []string{".claude/settings.json"}, Unchanged: []string{".mcp.json"}, Deleted: []string{".claude/skills/old"}},
+		{Client: summaryClientCodex, Written: []string{".codex/config.toml"}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("Summary = %#v, want %#v", got, want)
+	}
+}
+
+func TestRunWithProjectOptions_SummaryReportsUnchangedAfterSync(t *testing.T) {
+	root := t.TempDir()
+	if err := copyFixtureRepo(filepath.Join("testdata", "fixture-repo"), root); err != nil {
+		t.Fatalf("copy fixture: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(root, ".agent-layer", ".env"), []byte("AL_EXAMPLE_TOKEN=token123\n"), 0o600); err != nil {
+		t.Fatalf("write env: %v", err)
+	}
+	writeTemplateToFixtureSource(t, root, "claude-statusline.sh", filepath.Join(".agent-layer", "claude-statusline.sh"), 0o755)
+	writeTemplateToFixtureSource(t, root, "codex-statusline.toml", filepath.Join(".agent-layer", "codex-statusline.toml"), 0o644)
+	project, err := config.LoadProjectConfig(root)
+	if err != nil {
+		t.Fatalf("load project: %v", err)
+	}
+
+	first, err := RunWithProjectOptions(RealSystem{}, root, project, RunOptions{})
+	if err != nil {
+		t.Fatalf("sync: %v", err)
+	}
+	claudeWritten := false
+	for _, summary := range first.Summary(root) {
+		if summary.Client == summaryClientClaude && slices.Contains(summary.Written, ".mcp.json") {
+			claudeWritten = true
+		}
+	}
+	if !claudeWritten {
+		t.Fatalf("expected .mcp.json written for claude: %#v", first.Summary(root))
+	}
+
+	// The Codex config merge normalizes its own output on the next run.
+	if _, err := RunWithProjectOptions(RealSystem{}, root, project, RunOptions{}); err != nil {
+		t.Fatalf("second sync: %v", err)
+	}
+	again, err := RunWithProjectOptions(RealSystem{}, root, project, RunOptions{DryRun: true})
+	if err != nil {
+		t.Fatalf("dry run: %v", err)
+	}
+	for _, summary := range again.Summary(root) {
+		if len(summary.Written) != 0 || len(summary.Deleted) != 0 {
+			t.Fatalf("expected only unchanged outputs after sync, got %#v", summary)
+		}
+		if summary.Client == "" && !slices.Contains(summary.Unchanged, "AGENTS.md") {
+			t.Fatalf("expected AGENTS.md unchanged in shared summary: %#v", summary)
+		}
+	}
+}
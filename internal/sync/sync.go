@@ -2,14 +2,21 @@ package sync
 
 import (
 	"fmt"
+	"io"
 	"io/fs"
-	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 
 	"github.com/conn-castle/agent-layer/internal/config"
+	"github.com/conn-castle/agent-layer/internal/errcode"
+	"github.com/conn-castle/agent-layer/internal/i18n"
 	"github.com/conn-castle/agent-layer/internal/install"
 	"github.com/conn-castle/agent-layer/internal/launchers"
+	"github.com/conn-castle/agent-layer/internal/layerdir"
 	"github.com/conn-castle/agent-layer/internal/messages"
+	"github.com/conn-castle/agent-layer/internal/output"
+	"github.com/conn-castle/agent-layer/internal/policy"
 	"github.com/conn-castle/agent-layer/internal/warnings"
 )
 
@@ -17,12 +24,47 @@ import (
 type Result struct {
 	Warnings    []warnings.Warning
 	AllWarnings []warnings.Warning
+	// Degradations lists features enabled clients could not receive natively.
+	Degradations []Degradation
+	// EditedFiles lists generated files kept because they were edited by hand.
+	EditedFiles []EditedFile
+	// Changes lists, in order, the filesystem changes the run made, or would
+	// have made under RunOptions.DryRun.
+	Changes []Change
+	// Unchanged lists the writes the run skipped because the file already
+	// held that content.
+	Unchanged []Change
+	// ConfigKeys lists the config keys that changed since the previous sync.
+	// It is empty on the first sync and when nothing changed.
+	ConfigKeys []string
+	// ConfigAffected lists the repo-relative generated paths this run changed
+	// when ConfigKeys is non-empty.
+	ConfigAffected []string
+}
+
+// RunOptions adjusts a sync run.
+type RunOptions struct {
+	// Force overwrites generated files even when they were edited by hand.
+	Force bool
+	// DryRun computes the changes without writing anything, including the
+	// sync lock, so it works on a read-only checkout.
+	DryRun bool
+	// Output receives progress while the steps run. Nil reports nothing.
+	Output *output.Writer
+	// SourceRoot is the repo the project was loaded from when sync renders
+	// into a scratch copy, as `al sync --output-root` and `al diff` do.
+	// {{project.*}} and {{git.*}} variables and file_exists conditions
+	// resolve against it. Empty means the sync root.
+	SourceRoot string
+	// HookOutput receives the progress and output of [hooks] commands. Nil
+	// discards it.
+	HookOutput io.Writer
 }
 
 // Run regenerates all configured outputs for the repo.
 // Returns any sync-time warnings and an error if sync failed.
 func Run(root string) (*Result, error) {
-	project, err := config.LoadProjectConfigFS(os.DirFS(root), root)
+	project, err := config.LoadProjectConfigFS(layerdir.FS(root), root)
 	if err != nil {
 		return nil, err
 	}
@@ -34,10 +76,10 @@ func Run(root string) (*Result, error) {
 // sys provides OS operations for sync writers; fsys must be rooted at repo root.
 func RunWithSystemFS(sys System, fsys fs.FS, root string) (*Result, error) {
 	if sys == nil {
-		return nil, fmt.Errorf(messages.SyncSystemRequired)
+		return nil, i18n.Errorf(messages.SyncSystemRequired)
 	}
 	if fsys == nil {
-		return nil, fmt.Errorf(messages.SyncConfigFSRequired)
+		return nil, i18n.Errorf(messages.SyncConfigFSRequired)
 	}
 	project, err := config.LoadProjectConfigFS(fsys, root)
 	if err != nil {
@@ -47,28 +89,118 @@ func RunWithSystemFS(sys System, fsys fs.FS, root string) (*Result, error) {
 }
 
 // RunWithProject regenerates outputs using an already loaded project config.
-// Returns any sync-time warnings and an error if sync failed.
+// Returns any sync-time warnings and an error if sync failed, tagged
+// errcode.Sync unless a more specific code is already attached.
 func RunWithProject(sys System, root string, project *config.ProjectConfig) (*Result, error) {
+	return RunWithProjectOptions(sys, root, project, RunOptions{})
+}
+
+// RunWithProjectOptions is RunWithProject with explicit run options. A sync
+// that writes into the repo runs the configured [hooks] around it; dry runs
+// and scratch renders (SourceRoot set) skip them.
+func RunWithProjectOptions(sys System, root string, project *config.ProjectConfig, opts RunOptions) (*Result, error) {
 	if sys == nil {
-		return nil, fmt.Errorf(messages.SyncSystemRequired)
+		return nil, i18n.Errorf(messages.SyncSystemRequired)
 	}
 	if project == nil {
-		return nil, fmt.Errorf(messages.SyncProjectRequired)
+		return nil, i18n.Errorf(messages.SyncProjectRequired)
+	}
+	run := func(project *config.ProjectConfig) (*Result, error) {
+		return withProjectSyncLock(sys, root, func() (*Result, error) {
+			return runWithProjectLocked(sys, root, project, opts)
+		})
+	}
+	var result *Result
+	var err error
+	switch {
+	case opts.DryRun:
+		result, err = runWithProjectLocked(sys, root, project, opts)
+	case opts.SourceRoot != "":
+		result, err = run(project)
+	default:
+		result, err = withHooks(root, project, opts.HookOutput, run)
+	}
+	if err != nil {
+		return nil, errcode.Wrap(errcode.Sync, err)
 	}
-	return withProjectSyncLock(sys, root, func() (*Result, error) {
-		return runWithProjectLocked(sys, root, project)
-	})
+	return result, nil
 }
 
-func runWithProjectLocked(sys System, root string, project *config.ProjectConfig) (*Result, error) {
+func runWithProjectLocked(baseSys System, root string, project *config.ProjectConfig, opts RunOptions) (*Result, error) {
+	configured := project.Config
+	project, err := pinMCPServers(root, project)
+	if err != nil {
+		return nil, err
+	}
+	sourceRoot := opts.SourceRoot
+	if sourceRoot == "" {
+		sourceRoot = root
+	}
+	// The sources record lists the file_exists paths of every skill, enabled
+	// or not, so it keeps the project from before the selection.
+	conditioned := project
+	project, err = selectEnabledSkills(baseSys, sourceRoot, project)
+	if err != nil {
+		return nil, errcode.Wrap(errcode.Config, err)
+	}
+	claudeProject, err := clientVariantProject(sourceRoot, project, config.VariantClientClaude)
+	if err != nil {
+		return nil, errcode.Wrap(errcode.Config, err)
+	}
+	copilotProject, err := clientVariantProject(sourceRoot, project, config.VariantClientCopilot)
+	if err != nil {
+		return nil, errcode.Wrap(errcode.Config, err)
+	}
+	project, err = ResolveInstructionVariables(sourceRoot, project)
+	if err != nil {
+		return nil, errcode.Wrap(errcode.Config, err)
+	}
+	if claudeProject == nil {
+		claudeProject = project
+	}
+	if copilotProject == nil {
+		copilotProject = project
+	}
+	if err := config.CheckSkillRequires(project.Skills); err != nil {
+		return nil, errcode.Wrap(errcode.Config, err)
+	}
+	policyWarnings, err := checkContentPolicy(root, project)
+	if err != nil {
+		return nil, err
+	}
+	// Every step stages its writes; nothing touches disk until applyChanges.
+	staging := newStagingSystem(baseSys)
+	guard := &generationGuard{System: staging, force: opts.Force}
+	var sys System = guard
+	// owned tags the changes a step stages with the client it writes for;
+	// untagged steps write outputs every client shares.
+	owned := func(client string, step func() error) func() error {
+		return func() error {
+			staging.client = client
+			defer func() { staging.client = "" }()
+			return step()
+		}
+	}
 	agents := project.Config.Agents
+	var configKeys []string
 	steps := []func() error{
+		func() error {
+			var err error
+			configKeys, err = recordConfigState(sys, root, configured)
+			return err
+		},
 		func() error { return updateGitignore(sys, root) },
 		func() error {
-			return writeInstructionShims(sys, root, project.Instructions)
+			return writeClientInstructionShims(sys, root, clientInstructions{
+				shared:  project.Instructions,
+				claude:  claudeProject.Instructions,
+				copilot: copilotProject.Instructions,
+			}, project.ScopedInstructions)
 		},
-		func() error { return cleanCodexInstructions(sys, root) },
+		func() error { return writeScopedInstructions(sys, root, project) },
+		owned(summaryClientCodex, func() error { return cleanCodexInstructions(sys, root) }),
 		func() error { return cleanLegacySkillOutputs(sys, root) },
+		func() error { return recordExtendsLock(sys, root, project) },
 	}
 
 	if config.SharedAgentSkillsEnabled(agents) {
@@ -85,34 +217,39 @@ func runWithProjectLocked(sys System, root string, project *config.ProjectConfig
 
 	if vscodeEnabled || claudeVSCodeEnabled {
 		steps = append(steps,
-			func() error { return writeVSCodeSettings(sys, root, project) },
+			owned(summaryClientVSCode, func() error { return writeVSCodeSettings(sys, root, project) }),
 		)
 	}
+	if (vscodeEnabled || claudeVSCodeEnabled) && config.VSCodeWorkspaceEnabled(project.Config) {
+		steps = append(steps, owned(summaryClientVSCode, func() error { return WriteVSCodeWorkspace(sys, root, project) }))
+	} else {
+		steps = append(steps, owned(summaryClientVSCode, func() error { return cleanVSCodeWorkspace(sys, root) }))
+	}
 	if vscodeEnabled {
 		steps = append(steps,
-			func() error { return writeVSCodeMCPConfig(sys, root, project) },
-			func() error { return launchers.WriteVSCodeLaunchers(sys, root) },
+			owned(summaryClientVSCode, func() error { return writeVSCodeMCPConfig(sys, root, project) }),
+			owned(summaryClientVSCode, func() error { return launchers.WriteVSCodeLaunchers(sys, root) }),
 		)
 	}
 
 	if config.IsAgentEnabled(agents.CopilotCLI.Enabled) {
 		steps = append(steps,
-			func() error { return writeCopilotMCPConfig(sys, root, project) },
+			owned(summaryClientCopilot, func() error { return writeCopilotMCPConfig(sys, root, project) }),
 		)
 	} else {
-		steps = append(steps, func() error { return cleanCopilotOutputs(sys, root) })
+		steps = append(steps, owned(summaryClientCopilot, func() error { return cleanCopilotOutputs(sys, root) }))
 	}
 
 	if config.IsAgentEnabled(agents.Antigravity.Enabled) {
 		steps = append(steps,
-			func() error { return writeAntigravitySettings(sys, root, project) },
-			func() error { return writeAntigravityMCPConfig(sys, root, project) },
-			func() error { return writeAntigravityChimePlugin(sys, root, project) },
+			owned(summaryClientAntigravity, func() error { return writeAntigravitySettings(sys, root, project) }),
+			owned(summaryClientAntigravity, func() error { return writeAntigravityMCPConfig(sys, root, project) }),
+			owned(summaryClientAntigravity, func() error { return writeAntigravityChimePlugin(sys, root, project) }),
 		)
 	} else {
 		steps = append(steps,
-			func() error { return cleanAntigravityOutputs(sys, root) },
-			func() error { return cleanAntigravityChimePlugin(sys, root) },
+			owned(summaryClientAntigravity, func() error { return cleanAntigravityOutputs(sys, root) }),
+			owned(summaryClientAntigravity, func() error { return cleanAntigravityChimePlugin(sys, root) }),
 		)
 	}
 
@@ -120,43 +257,159 @@ func runWithProjectLocked(sys System, root string, project *config.ProjectConfig
 	claudeEnabled := config.IsAgentEnabled(agents.Claude.Enabled)
 	if claudeEnabled || claudeVSCodeEnabled {
 		steps = append(steps,
-			func() error { return writeClaudeStatusline(sys, root, project) },
-			func() error { return writeClaudeSettings(sys, root, project) },
-			func() error { return writeMCPConfig(sys, root, project) },
-			func() error { return WriteClaudeSkills(sys, root, project.Skills) },
+			owned(summaryClientClaude, func() error { return writeClaudeStatusline(sys, root, project) }),
+			owned(summaryClientClaude, func() error { return writeClaudeSettings(sys, root, project) }),
+			owned(summaryClientClaude, func() error { return writeMCPConfig(sys, root, project) }),
+			owned(summaryClientClaude, func() error { return WriteClaudeSkills(sys, root, claudeProject.Skills) }),
 		)
 	} else {
-		steps = append(steps, func() error { return cleanClaudeChimeHook(sys, root) })
+		steps = append(steps, owned(summaryClientClaude, func() error { return cleanClaudeChimeHook(sys, root) }))
 	}
 
 	codexEnabled := config.IsAgentEnabled(agents.Codex.Enabled)
 	if codexEnabled || vscodeEnabled {
 		steps = append(steps,
-			func() error { return writeCodexConfigWithCLISettings(sys, root, project, codexEnabled) },
+			owned(summaryClientCodex, func() error { return writeCodexConfigWithCLISettings(sys, root, project, codexEnabled) }),
 		)
 	}
 	if codexEnabled {
-		steps = append(steps, func() error { return writeCodexRules(sys, root, project) })
+		steps = append(steps, owned(summaryClientCodex, func() error { return writeCodexRules(sys, root, project) }))
 	} else if !vscodeEnabled {
-		steps = append(steps, func() error { return cleanCodexChimeHook(sys, root) })
+		steps = append(steps, owned(summaryClientCodex, func() error { return cleanCodexChimeHook(sys, root) }))
 	}
 
-	if err := runSteps(steps); err != nil {
+	renderers, err := projectRenderers(root, project)
+	if err != nil {
 		return nil, err
 	}
+	renderSteps, err := rendererSteps(sys, root, Inputs{Root: root, Project: project}, renderers, owned)
+	if err != nil {
+		return nil, err
+	}
+	steps = append(steps, renderSteps...)
+
+	// Recording the sources last captures what the other steps wrote under
+	// .agent-layer/.
+	steps = append(steps, func() error { return recordSourcesState(sys, root, conditioned) })
+
+	progress := opts.Output.Progress(messages.SyncProgressLabel, len(steps))
+	err = runSteps(steps, progress.Step)
+	progress.Done()
+	if err != nil {
+		return nil, err
+	}
+	if !opts.DryRun {
+		if err := applyChanges(baseSys, staging.changes); err != nil {
+			return nil, err
+		}
+	}
 
 	// Collect warnings after successful sync, including post-step warnings
 	// so that all warnings pass through noise control.
-	rawWarnings, err := collectWarnings(project, nil)
+	rawWarnings, err := collectWarnings(project, append(policyWarnings, editedFileWarnings(root, guard.edited)...))
 	if err != nil {
 		return nil, err
 	}
 	filteredWarnings := warnings.ApplyNoiseControl(rawWarnings, project.Config.Warnings.NoiseMode)
 
-	return &Result{
-		Warnings:    filteredWarnings,
-		AllWarnings: rawWarnings,
-	}, nil
+	result := &Result{
+		Warnings:     filteredWarnings,
+		AllWarnings:  rawWarnings,
+		Degradations: collectDegradations(project),
+		EditedFiles:  guard.edited,
+		Changes:      staging.changes,
+		Unchanged:    staging.unchanged,
+		ConfigKeys:   configKeys,
+	}
+	if len(configKeys) > 0 {
+		result.ConfigAffected = ChangedOutputPaths(root, staging.changes)
+	}
+	return result, nil
+}
+
+// StalePaths returns the sorted repo-relative generated files that differ
+// from what sync would write: the files the run changed, or would have
+// changed under RunOptions.DryRun, plus hand-edited files it kept.
+func (r *Result) StalePaths(root string) []string {
+	seen := make(map[string]bool)
+	var out []string
+	add := func(rel string) {
+		if !seen[rel] {
+			seen[rel] = true
+			out = append(out, rel)
+		}
+	}
+	for _, rel := range ChangedOutputPaths(root, r.Changes) {
+		add(rel)
+	}
+	for _, file := range r.EditedFiles {
+		rel := file.Path
+		if p, err := filepath.Rel(root, file.Path); err == nil {
+			rel = filepath.ToSlash(p)
+		}
+		add(rel)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// checkContentPolicy applies .agent-layer/policy.toml at its sync severity.
+// Violations become warnings, or fail sync before anything is written when
+// the policy asks for errors.
+func checkContentPolicy(root string, project *config.ProjectConfig) ([]warnings.Warning, error) {
+	contentPolicy, err := policy.Load(root)
+	if err != nil {
+		return nil, errcode.Wrap(errcode.Config, err)
+	}
+	severity := contentPolicy.SyncSeverity()
+	if severity == policy.SeverityOff {
+		return nil, nil
+	}
+	violations := policy.Check(contentPolicy, project)
+	if len(violations) == 0 {
+		return nil, nil
+	}
+	if severity == policy.SeverityError {
+		lines := make([]string, 0, len(violations))
+		for _, violation := range violations {
+			lines = append(lines, fmt.Sprintf("  %s: %s", violation.Subject, violation.Message))
+		}
+		return nil, errcode.Wrap(errcode.Config, i18n.Errorf(messages.PolicySyncFailedFmt, len(violations), strings.Join(lines, "\n")))
+	}
+	out := make([]warnings.Warning, 0, len(violations))
+	for _, violation := range violations {
+		out = append(out, warnings.Warning{
+			Code:     warnings.CodePolicyContentRule,
+			Subject:  violation.Subject,
+			Message:  violation.Message,
+			Fix:      messages.WarningsPolicyContentRuleFix,
+			Details:  []string{"rule: " + violation.Rule},
+			Source:   warnings.SourceInternal,
+			Severity: warnings.SeverityWarning,
+		})
+	}
+	return out, nil
+}
+
+// editedFileWarnings reports each generated file sync kept because it was
+// edited by hand.
+func editedFileWarnings(root string, edited []EditedFile) []warnings.Warning {
+	out := make([]warnings.Warning, 0, len(edited))
+	for _, file := range edited {
+		subject := file.Path
+		if rel, err := filepath.Rel(root, file.Path); err == nil {
+			subject = filepath.ToSlash(rel)
+		}
+		out = append(out, warnings.Warning{
+			Code:     warnings.CodeGeneratedFileEdited,
+			Subject:  subject,
+			Message:  messages.WarningsGeneratedFileEdited,
+			Fix:      messages.WarningsGeneratedFileEditedFix,
+			Source:   warnings.SourceInternal,
+			Severity: warnings.SeverityWarning,
+		})
+	}
+	return out
 }
 
 // collectWarnings gathers all sync-time warnings based on the project config.
@@ -176,11 +429,12 @@ func collectWarnings(project *config.ProjectConfig, extra []warnings.Warning) ([
 	return collected, nil
 }
 
-func runSteps(steps []func() error) error {
+func runSteps(steps []func() error, onStep func()) error {
 	for _, step := range steps {
 		if err := step(); err != nil {
 			return err
 		}
+		onStep()
 	}
 	return nil
 }
@@ -188,20 +442,20 @@ func runSteps(steps []func() error) error {
 // EnsureEnabled is a helper for command handlers.
 func EnsureEnabled(name string, enabled *bool) error {
 	if enabled == nil {
-		return fmt.Errorf(messages.SyncAgentEnabledFlagMissingFmt, name)
+		return i18n.Errorf(messages.SyncAgentEnabledFlagMissingFmt, name)
 	}
 	if !*enabled {
-		return fmt.Errorf(messages.SyncAgentDisabledFmt, name)
+		return i18n.Errorf(messages.SyncAgentDisabledFmt, name)
 	}
 	return nil
 }
 
 // updateGitignore reads the gitignore block and ensures .gitignore is updated.
 func updateGitignore(sys System, root string) error {
-	blockPath := filepath.Join(root, ".agent-layer", "gitignore.block")
+	blockPath := filepath.Join(layerdir.Dir(root), "gitignore.block")
 	blockBytes, err := sys.ReadFile(blockPath)
 	if err != nil {
-		return fmt.Errorf(messages.SyncFailedReadGitignoreBlockFmt, blockPath, err)
+		return i18n.Errorf(messages.SyncFailedReadGitignoreBlockFmt, blockPath, err)
 	}
 	block, err := install.ValidateGitignoreBlock(string(blockBytes), blockPath)
 	if err != nil {
@@ -8,6 +8,7 @@ import (
 	"testing"
 
 	"github.com/conn-castle/agent-layer/internal/config"
+	"github.com/conn-castle/agent-layer/internal/errcode"
 	"github.com/conn-castle/agent-layer/internal/testutil"
 )
 
@@ -60,12 +61,15 @@ func TestRunWithProjectError(t *testing.T) {
 	if err == nil {
 		t.Fatalf("expected error")
 	}
+	if code := errcode.Of(err); code != errcode.Sync {
+		t.Fatalf("expected code %q, got %q", errcode.Sync, code)
+	}
 }
 
 func TestRunStepsError(t *testing.T) {
 	err := runSteps([]func() error{
 		func() error { return fmt.Errorf("boom") },
-	})
+	}, func() {})
 	if err == nil {
 		t.Fatalf("expected error")
 	}
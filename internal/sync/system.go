@@ -17,7 +17,9 @@ type System interface {
 	Stat(name string) (os.FileInfo, error)
 	Lstat(name string) (os.FileInfo, error)
 	Readlink(name string) (string, error)
+	Symlink(oldname string, newname string) error
 	MkdirAll(path string, perm os.FileMode) error
+	Chmod(name string, mode os.FileMode) error
 	WriteFileAtomic(filename string, data []byte, perm os.FileMode) error
 	MarshalIndent(v any, prefix, indent string) ([]byte, error)
 	ReadFile(name string) ([]byte, error)
@@ -53,11 +55,21 @@ func (RealSystem) Readlink(name string) (string, error) {
 	return os.Readlink(name)
 }
 
+// Symlink creates newname as a symbolic link to oldname.
+func (RealSystem) Symlink(oldname string, newname string) error {
+	return os.Symlink(oldname, newname)
+}
+
 // MkdirAll creates a directory named path, along with any necessary parents.
 func (RealSystem) MkdirAll(path string, perm os.FileMode) error {
 	return os.MkdirAll(path, perm)
 }
 
+// Chmod changes the mode of the named file or directory.
+func (RealSystem) Chmod(name string, mode os.FileMode) error {
+	return os.Chmod(name, mode)
+}
+
 // WriteFileAtomic writes data to a file atomically by writing to a temp file and renaming.
 func (RealSystem) WriteFileAtomic(filename string, data []byte, perm os.FileMode) error {
 	return fsutil.WriteFileAtomic(filename, data, perm)
@@ -0,0 +1,232 @@
+package sync
+
+import (
+	"os/exec"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pelletier/go-toml/v2"
+
+	"github.com/conn-castle/agent-layer/internal/config"
+	"github.com/conn-castle/agent-layer/internal/i18n"
+	"github.com/conn-castle/agent-layer/internal/messages"
+)
+
+// instructionVariablePattern matches {{namespace.key}} placeholders in the
+// project, git, and config namespaces. Other {{...}} text, such as the
+// upper-case placeholders skills fill in at run time, is left alone.
+var instructionVariablePattern = regexp.MustCompile(`\{\{\s*((?:project|git|config)\.[a-z0-9_.]+)\s*\}\}`)
+
+// gitOutputFunc runs a git subcommand in root and returns its trimmed output.
+// ok is false when git fails or is unavailable.
+var gitOutputFunc = func(root string, args ...string) (string, bool) {
+	out, err := exec.Command("git", append([]string{"-C", root}, args...)...).Output() // #nosec G204 -- fixed git subcommands; root is the resolved repo root.
+	if err != nil {
+		return "", false
+	}
+	return strings.TrimSpace(string(out)), true
+}
+
+// ResolveInstructionVariables returns a copy of project whose instructions,
+// scoped instructions, and skill bodies have {{project.*}}, {{git.*}}, and
+// {{config.*}} placeholders replaced. Git is only consulted when a
+// placeholder needs it. Unknown or unresolvable placeholders fail with the
+// full list so they can be fixed in one pass.
+func ResolveInstructionVariables(root string, project *config.ProjectConfig) (*config.ProjectConfig, error) {
+	if !usesInstructionVariables(project) {
+		return project, nil
+	}
+	resolver := &variableResolver{root: root, cfg: project.Config}
+	resolved := *project
+	resolved.Instructions = resolver.instructions(project.Instructions)
+	resolved.ScopedInstructions = make([]config.ScopedInstructions, len(project.ScopedInstructions))
+	for i, scoped := range project.ScopedInstructions {
+		resolved.ScopedInstructions[i] = config.ScopedInstructions{Dir: scoped.Dir, Glob: scoped.Glob, Files: resolver.instructions(scoped.Files)}
+	}
+	resolved.Skills = make([]config.Skill, len(project.Skills))
+	for i, skill := range project.Skills {
+		skill.Body = resolver.substitute(skill.Body)
+		resolved.Skills[i] = skill
+	}
+	if len(resolver.unresolved) > 0 {
+		names := make([]string, 0, len(resolver.unresolved))
+		for name := range resolver.unresolved {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return nil, i18n.Errorf(messages.SyncInstructionVariablesUnresolvedFmt, strings.Join(names, ", "))
+	}
+	return &resolved, nil
+}
+
+func usesInstructionVariables(project *config.ProjectConfig) bool {
+	for _, instruction := range project.Instructions {
+		if instructionVariablePattern.MatchString(instruction.Content) {
+			return true
+		}
+	}
+	for _, scoped := range project.ScopedInstructions {
+		for _, instruction := range scoped.Files {
+			if instructionVariablePattern.MatchString(instruction.Content) {
+				return true
+			}
+		}
+	}
+	for _, skill := range project.Skills {
+		if instructionVariablePattern.MatchString(skill.Body) {
+			return true
+		}
+	}
+	return false
+}
+
+// variableResolver looks up placeholder values, caching config and git
+// lookups for the duration of one sync.
+type variableResolver struct {
+	root       string
+	cfg        config.Config
+	configTree map[string]any
+	values     map[string]string
+	unresolved map[string]struct{}
+}
+
+func (r *variableResolver) instructions(files []config.InstructionFile) []config.InstructionFile {
+	out := make([]config.InstructionFile, len(files))
+	for i, file := range files {
+		out[i] = config.InstructionFile{Name: file.Name, Content: r.substitute(file.Content)}
+	}
+	return out
+}
+
+func (r *variableResolver) substitute(content string) string {
+	return instructionVariablePattern.ReplaceAllStringFunc(content, func(match string) string {
+		name := instructionVariablePattern.FindStringSubmatch(match)[1]
+		value, ok := r.lookup(name)
+		if !ok {
+			if r.unresolved == nil {
+				r.unresolved = make(map[string]struct{})
+			}
+			r.unresolved[name] = struct{}{}
+			return match
+		}
+		return value
+	})
+}
+
+func (r *variableResolver) lookup(name string) (string, bool) {
+	if value, ok := r.values[name]; ok {
+		return value, true
+	}
+	var value string
+	var ok bool
+	namespace, key, _ := strings.Cut(name, ".")
+	switch namespace {
+	case "project":
+		value, ok = r.projectValue(key)
+	case "git":
+		value, ok = r.gitValue(key)
+	case "config":
+		value, ok = r.configValue(key)
+	}
+	if !ok {
+		return "", false
+	}
+	if r.values == nil {
+		r.values = make(map[string]string)
+	}
+	r.values[name] = value
+	return value, true
+}
+
+// projectValue resolves project.name: the origin repository name, or the
+// repo directory name when there is no origin remote.
+func (r *variableResolver) projectValue(key string) (string, bool) {
+	if key != "name" {
+		return "", false
+	}
+	if remote, ok := r.gitValue("remote_url"); ok && remote != "" {
+		name := strings.TrimSuffix(path.Base(strings.ReplaceAll(remote, ":", "/")), ".git")
+		if name != "" && name != "." && name != "/" {
+			return name, true
+		}
+	}
+	return filepath.Base(r.root), true
+}
+
+// gitValue resolves git.default_branch (origin's HEAD, else
+// init.defaultBranch, else the current branch) and git.remote_url.
+func (r *variableResolver) gitValue(key string) (string, bool) {
+	switch key {
+	case "default_branch":
+		if ref, ok := gitOutputFunc(r.root, "symbolic-ref", "--quiet", "--short", "refs/remotes/origin/HEAD"); ok && ref != "" {
+			return strings.TrimPrefix(ref, "origin/"), true
+		}
+		if branch, ok := gitOutputFunc(r.root, "config", "--get", "init.defaultBranch"); ok && branch != "" {
+			return branch, true
+		}
+		if branch, ok := gitOutputFunc(r.root, "symbolic-ref", "--quiet", "--short", "HEAD"); ok && branch != "" {
+			return branch, true
+		}
+	case "remote_url":
+		if url, ok := gitOutputFunc(r.root, "remote", "get-url", "origin"); ok {
+			return url, true
+		}
+	}
+	return "", false
+}
+
+// configValue resolves a dotted path into the loaded config. Only scalar
+// values and lists of scalars resolve; unset optional values are empty.
+func (r *variableResolver) configValue(key string) (string, bool) {
+	if r.configTree == nil {
+		data, err := toml.Marshal(r.cfg)
+		if err != nil {
+			return "", false
+		}
+		if err := toml.Unmarshal(data, &r.configTree); err != nil {
+			return "", false
+		}
+	}
+	var node any = r.configTree
+	for _, part := range strings.Split(key, ".") {
+		table, ok := node.(map[string]any)
+		if !ok {
+			return "", false
+		}
+		node, ok = table[part]
+		if !ok {
+			// Unset optional fields are omitted when marshaled.
+			_, known := config.LookupField(key)
+			return "", known
+		}
+	}
+	return formatVariableValue(node)
+}
+
+func formatVariableValue(value any) (string, bool) {
+	switch v := value.(type) {
+	case string:
+		return v, true
+	case bool:
+		return strconv.FormatBool(v), true
+	case int64:
+		return strconv.FormatInt(v, 10), true
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), true
+	case []any:
+		parts := make([]string, 0, len(v))
+		for _, item := range v {
+			part, ok := formatVariableValue(item)
+			if !ok {
+				return "", false
+			}
+			parts = append(parts, part)
+		}
+		return strings.Join(parts, ", "), true
+	}
+	return "", false
+}
@@ -0,0 +1,87 @@
+package sync
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/conn-castle/agent-layer/internal/config"
+)
+
+func stubGitOutput(t *testing.T, outputs map[string]string) {
+	t.Helper()
+	original := gitOutputFunc
+	gitOutputFunc = func(_ string, args ...string) (string, bool) {
+		out, ok := outputs[strings.Join(args, " ")]
+		return out, ok
+	}
+	t.Cleanup(func() { gitOutputFunc = original })
+}
+
+func TestResolveInstructionVariables(t *testing.T) {
+	stubGitOutput(t, map[string]string{
+		"symbolic-ref --quiet --short refs/remotes/origin/HEAD": "origin/trunk",
+		"remote get-url origin":                                 "git@github.com:acme/widgets.git",
+	})
+	project := &config.ProjectConfig{
+		Instructions: []config.InstructionFile{{Name: "00.md", Content: "Repo {{project.name}} merges into {{ git.default_branch }}."}},
+		ScopedInstructions: []config.ScopedInstructions{
+			{Dir: "api", Files: []config.InstructionFile{{Name: "00.md", Content: "Codex uses {{config.agents.codex.model}}; approvals {{config.approvals.mode}}."}}},
+		},
+		Skills: []config.Skill{{Name: "review", Body: "Plan: {{PLAN_PATH}} on {{git.remote_url}}"}},
+	}
+	project.Config.Approvals.Mode = "all"
+	project.Config.Agents.Codex.Model = "gpt-5.3-codex"
+
+	resolved, err := ResolveInstructionVariables("/repo/checkout", project)
+	if err != nil {
+		t.Fatalf("ResolveInstructionVariables: %v", err)
+	}
+	if got := resolved.Instructions[0].Content; got != "Repo widgets merges into trunk." {
+		t.Fatalf("instructions = %q", got)
+	}
+	if got := resolved.ScopedInstructions[0].Files[0].Content; got != "Codex uses gpt-5.3-codex; approvals all." {
+		t.Fatalf("scoped instructions = %q", got)
+	}
+	if got := resolved.Skills[0].Body; got != "Plan: {{PLAN_PATH}} on git@github.com:acme/widgets.git" {
+		t.Fatalf("skill body = %q", got)
+	}
+	if project.Instructions[0].Content != "Repo {{project.name}} merges into {{ git.default_branch }}." {
+		t.Fatalf("input project was modified: %q", project.Instructions[0].Content)
+	}
+}
+
+func TestResolveInstructionVariables_Fallbacks(t *testing.T) {
+	stubGitOutput(t, map[string]string{"symbolic-ref --quiet --short HEAD": "dev"})
+	project := &config.ProjectConfig{
+		Instructions: []config.InstructionFile{{Name: "00.md", Content: "{{project.name}} {{git.default_branch}}"}},
+	}
+	resolved, err := ResolveInstructionVariables("/work/billing", project)
+	if err != nil {
+		t.Fatalf("ResolveInstructionVariables: %v", err)
+	}
+	if got := resolved.Instructions[0].Content; got != "billing dev" {
+		t.Fatalf("instructions = %q", got)
+	}
+}
+
+func TestResolveInstructionVariables_Unresolved(t *testing.T) {
+	stubGitOutput(t, nil)
+	project := &config.ProjectConfig{
+		Instructions: []config.InstructionFile{{Name: "00.md", Content: "{{git.remote_url}} {{config.agents}} {{config.nope}} {{project.owner}}"}},
+	}
+	_, err := ResolveInstructionVariables("/repo", project)
+	if err == nil {
+		t.Fatal("expected unresolved variables error")
+	}
+	if !strings.Contains(err.Error(), "config.agents, config.nope, git.remote_url, project.owner") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestResolveInstructionVariables_NoPlaceholders(t *testing.T) {
+	project := &config.ProjectConfig{Instructions: []config.InstructionFile{{Name: "00.md", Content: "plain {{TASK_PATH}}"}}}
+	resolved, err := ResolveInstructionVariables("/repo", project)
+	if err != nil || resolved != project {
+		t.Fatalf("expected project returned unchanged, got %p %v", resolved, err)
+	}
+}
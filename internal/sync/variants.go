@@ -0,0 +1,17 @@
+package sync
+
+import "github.com/conn-castle/agent-layer/internal/config"
+
+// clientVariantProject returns the project with client's [variants.clients]
+// selection applied and its instruction variables resolved, or nil when the
+// client follows the default selection.
+func clientVariantProject(root string, project *config.ProjectConfig, client string) (*config.ProjectConfig, error) {
+	selected, err := project.ForVariantClient(client)
+	if err != nil {
+		return nil, err
+	}
+	if selected == project {
+		return nil, nil
+	}
+	return ResolveInstructionVariables(root, selected)
+}
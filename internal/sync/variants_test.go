@@ -0,0 +1,51 @@
+package sync
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/conn-castle/agent-layer/internal/config"
+)
+
+func TestRunWithProject_ClientVariants(t *testing.T) {
+	root, _ := policyFixture(t, "")
+	agentLayer := filepath.Join(root, ".agent-layer")
+	if err := os.WriteFile(filepath.Join(agentLayer, "instructions", "00_base@terse.md"), []byte("Terse base variant.\n"), 0o600); err != nil {
+		t.Fatalf("write variant: %v", err)
+	}
+	configPath := filepath.Join(agentLayer, "config.toml")
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		t.Fatalf("read config: %v", err)
+	}
+	data = append(data, []byte("\n[variants.instructions]\n\"00_base\" = \"terse\"\n\n[variants.clients.claude.instructions]\n\"00_base\" = \"base\"\n")...)
+	if err := os.WriteFile(configPath, data, 0o600); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	project, err := config.LoadProjectConfig(root)
+	if err != nil {
+		t.Fatalf("load project: %v", err)
+	}
+
+	if _, err := RunWithProject(RealSystem{}, root, project); err != nil {
+		t.Fatalf("sync: %v", err)
+	}
+	for path, wantTerse := range map[string]bool{
+		"AGENTS.md":                       true,
+		".github/copilot-instructions.md": true,
+		"CLAUDE.md":                       false,
+	} {
+		content, err := os.ReadFile(filepath.Join(root, filepath.FromSlash(path)))
+		if err != nil {
+			t.Fatalf("read %s: %v", path, err)
+		}
+		if got := strings.Contains(string(content), "Terse base variant."); got != wantTerse {
+			t.Fatalf("%s contains the terse variant = %v, want %v:\n%s", path, got, wantTerse, content)
+		}
+		if !strings.Contains(string(content), "10_extra.md") {
+			t.Fatalf("%s lost the unvaried instruction:\n%s", path, content)
+		}
+	}
+}
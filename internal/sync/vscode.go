@@ -9,6 +9,7 @@ import (
 	"strings"
 
 	"github.com/conn-castle/agent-layer/internal/config"
+	"github.com/conn-castle/agent-layer/internal/i18n"
 	"github.com/conn-castle/agent-layer/internal/messages"
 	"github.com/conn-castle/agent-layer/internal/projection"
 )
@@ -48,25 +49,25 @@ func writeVSCodeSettingsWithBuilder(sys System, root string, project *config.Pro
 
 	vscodeDir := filepath.Join(root, ".vscode")
 	if err := sys.MkdirAll(vscodeDir, 0o755); err != nil {
-		return fmt.Errorf(messages.SyncCreateDirFailedFmt, vscodeDir, err)
+		return i18n.Errorf(messages.SyncCreateDirFailedFmt, vscodeDir, err)
 	}
 
 	path := filepath.Join(vscodeDir, "settings.json")
 	existing, err := sys.ReadFile(path)
 	if err != nil && !errors.Is(err, os.ErrNotExist) {
-		return fmt.Errorf(messages.SyncReadFailedFmt, path, err)
+		return i18n.Errorf(messages.SyncReadFailedFmt, path, err)
 	}
 
 	updated, err := renderVSCodeSettingsContent(sys, string(existing), settings)
 	if err != nil {
 		if errors.Is(err, errInvalidVSCodeSettings) {
-			return fmt.Errorf(messages.SyncInvalidVSCodeSettingsFmt, path, err)
+			return i18n.Errorf(messages.SyncInvalidVSCodeSettingsFmt, path, err)
 		}
 		return err
 	}
 
 	if err := sys.WriteFileAtomic(path, []byte(updated), 0o644); err != nil {
-		return fmt.Errorf(messages.SyncWriteFileFailedFmt, path, err)
+		return i18n.Errorf(messages.SyncWriteFileFailedFmt, path, err)
 	}
 
 	return nil
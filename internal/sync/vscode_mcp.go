@@ -1,10 +1,10 @@
 package sync
 
 import (
-	"fmt"
 	"path/filepath"
 
 	"github.com/conn-castle/agent-layer/internal/config"
+	"github.com/conn-castle/agent-layer/internal/i18n"
 	"github.com/conn-castle/agent-layer/internal/messages"
 	"github.com/conn-castle/agent-layer/internal/projection"
 )
@@ -31,18 +31,18 @@ func writeVSCodeMCPConfig(sys System, root string, project *config.ProjectConfig
 
 	vscodeDir := filepath.Join(root, ".vscode")
 	if err := sys.MkdirAll(vscodeDir, 0o755); err != nil {
-		return fmt.Errorf(messages.SyncCreateDirFailedFmt, vscodeDir, err)
+		return i18n.Errorf(messages.SyncCreateDirFailedFmt, vscodeDir, err)
 	}
 
 	data, err := sys.MarshalIndent(cfg, "", "  ")
 	if err != nil {
-		return fmt.Errorf(messages.SyncMarshalVSCodeMCPConfigFailedFmt, err)
+		return i18n.Errorf(messages.SyncMarshalVSCodeMCPConfigFailedFmt, err)
 	}
 	data = append(data, '\n')
 
 	path := filepath.Join(vscodeDir, "mcp.json")
 	if err := sys.WriteFileAtomic(path, data, 0o644); err != nil {
-		return fmt.Errorf(messages.SyncWriteFileFailedFmt, path, err)
+		return i18n.Errorf(messages.SyncWriteFileFailedFmt, path, err)
 	}
 
 	return nil
@@ -55,7 +55,7 @@ func buildVSCodeMCPConfig(project *config.ProjectConfig) (*vscodeMCPConfig, erro
 
 	// Transform to VS Code env syntax - VS Code resolves ${env:VAR} at runtime.
 	resolved, err := projection.ResolveMCPServers(
-		project.Config.MCP.Servers,
+		clientMCPServers(project, "vscode"),
 		project.Env,
 		"vscode",
 		projection.ClientPlaceholderResolver("${env:%s}"),
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/conn-castle/agent-layer/internal/i18n"
 	"github.com/conn-castle/agent-layer/internal/messages"
 )
 
@@ -479,7 +480,7 @@ func buildVSCodeManagedBlock(sys System, settings *vscodeSettings, indentBase, i
 	}
 	data, err := sys.MarshalIndent(settings, "", indentUnit)
 	if err != nil {
-		return nil, fmt.Errorf(messages.SyncMarshalVSCodeSettingsFailedFmt, err)
+		return nil, i18n.Errorf(messages.SyncMarshalVSCodeSettingsFailedFmt, err)
 	}
 
 	raw := strings.TrimRight(string(data), "\n")
@@ -0,0 +1,121 @@
+package sync
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/conn-castle/agent-layer/internal/config"
+	"github.com/conn-castle/agent-layer/internal/i18n"
+	"github.com/conn-castle/agent-layer/internal/messages"
+)
+
+// vscodeWorkspaceFile is the workspace file sync maintains under .vscode/.
+const vscodeWorkspaceFile = "agent-layer.code-workspace"
+
+// vscodeWorkspaceTitle tags the window title so a workspace opened by
+// `al vscode` is distinguishable from a plain folder window.
+const vscodeWorkspaceTitle = "${dirty}${activeEditorShort}${separator}${folderName}${separator}[agent-layer]"
+
+// Extensions recommended for each enabled VS Code agent.
+const (
+	vscodeExtensionCopilotChat = "github.copilot-chat"
+	vscodeExtensionCodex       = "openai.chatgpt"
+	vscodeExtensionClaudeCode  = "anthropic.claude-code"
+)
+
+type vscodeWorkspace struct {
+	Folders    []vscodeWorkspaceFolder   `json:"folders"`
+	Settings   vscodeWorkspaceSettings   `json:"settings"`
+	Extensions vscodeWorkspaceExtensions `json:"extensions"`
+}
+
+type vscodeWorkspaceFolder struct {
+	Path string `json:"path"`
+}
+
+// vscodeWorkspaceSettings adds the window title to the managed settings
+// block that sync writes to .vscode/settings.json.
+type vscodeWorkspaceSettings struct {
+	WindowTitle string `json:"window.title"`
+	vscodeSettings
+}
+
+type vscodeWorkspaceExtensions struct {
+	Recommendations []string `json:"recommendations"`
+}
+
+// VSCodeWorkspacePath returns the path of the VS Code workspace file sync
+// maintains when clients.vscode.workspace is enabled.
+func VSCodeWorkspacePath(root string) string {
+	return filepath.Join(root, ".vscode", vscodeWorkspaceFile)
+}
+
+// WriteVSCodeWorkspace writes .vscode/agent-layer.code-workspace: the repo as
+// its only folder, a tagged window title, the agent settings from the managed
+// .vscode/settings.json block, and the extensions of the enabled VS Code
+// agents. Sync calls it when clients.vscode.workspace is enabled; `al vscode
+// --workspace` calls it to open the workspace for one launch.
+func WriteVSCodeWorkspace(sys System, root string, project *config.ProjectConfig) error {
+	settings, err := buildVSCodeSettings(project)
+	if err != nil {
+		return err
+	}
+	workspace := vscodeWorkspace{
+		// The file lives in .vscode/, so the repo root is its parent.
+		Folders:    []vscodeWorkspaceFolder{{Path: ".."}},
+		Settings:   vscodeWorkspaceSettings{WindowTitle: vscodeWorkspaceTitle, vscodeSettings: *settings},
+		Extensions: vscodeWorkspaceExtensions{Recommendations: vscodeRecommendedExtensions(project.Config.Agents)},
+	}
+	data, err := sys.MarshalIndent(workspace, "", "  ")
+	if err != nil {
+		return i18n.Errorf(messages.SyncMarshalVSCodeWorkspaceFailedFmt, err)
+	}
+
+	vscodeDir := filepath.Join(root, ".vscode")
+	if err := sys.MkdirAll(vscodeDir, 0o755); err != nil {
+		return i18n.Errorf(messages.SyncCreateDirFailedFmt, vscodeDir, err)
+	}
+	var builder strings.Builder
+	for _, line := range generatedHeaderLines(".agent-layer/config.toml") {
+		builder.WriteString("// " + line + "\n")
+	}
+	builder.Write(data)
+	builder.WriteString("\n")
+	return writeGeneratedFile(sys, VSCodeWorkspacePath(root), sealGeneratedContent(builder.String()), 0o644)
+}
+
+// vscodeRecommendedExtensions lists the extensions the enabled VS Code agents
+// run in: Copilot Chat and Codex for agents.vscode, Claude Code for
+// agents.claude_vscode.
+func vscodeRecommendedExtensions(agents config.AgentsConfig) []string {
+	recommendations := []string{}
+	if config.IsAgentEnabled(agents.VSCode.Enabled) {
+		recommendations = append(recommendations, vscodeExtensionCopilotChat, vscodeExtensionCodex)
+	}
+	if config.IsAgentEnabled(agents.ClaudeVSCode.Enabled) {
+		recommendations = append(recommendations, vscodeExtensionClaudeCode)
+	}
+	return recommendations
+}
+
+// cleanVSCodeWorkspace removes a workspace file sync generated once the
+// workspace is disabled. A file without the generated content hash is the
+// user's own and is kept.
+func cleanVSCodeWorkspace(sys System, root string) error {
+	path := VSCodeWorkspacePath(root)
+	content, err := sys.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return i18n.Errorf(messages.SyncReadFailedFmt, path, err)
+	}
+	if !HasContentHash(string(content)) {
+		return nil
+	}
+	if err := sys.Remove(path); err != nil && !os.IsNotExist(err) {
+		return i18n.Errorf(messages.SyncRemoveFailedFmt, path, err)
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package sync
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/conn-castle/agent-layer/internal/config"
+)
+
+func TestWriteVSCodeWorkspace(t *testing.T) {
+	t.Parallel()
+	root := t.TempDir()
+	enabled := true
+	project := &config.ProjectConfig{
+		Root:          root,
+		CommandsAllow: []string{"git status"},
+	}
+	project.Config.Approvals.Mode = config.ApprovalModeAll
+	project.Config.Agents.VSCode.Enabled = &enabled
+	project.Config.Agents.ClaudeVSCode.Enabled = &enabled
+
+	if err := WriteVSCodeWorkspace(RealSystem{}, root, project); err != nil {
+		t.Fatalf("WriteVSCodeWorkspace: %v", err)
+	}
+	data, err := os.ReadFile(VSCodeWorkspacePath(root))
+	if err != nil {
+		t.Fatal(err)
+	}
+	content := string(data)
+	if !GeneratedContentUnchanged(content) {
+		t.Fatalf("expected a sealed generated header:\n%s", content)
+	}
+	var body strings.Builder
+	for _, line := range strings.Split(content, "\n") {
+		if !strings.HasPrefix(line, "//") {
+			body.WriteString(line + "\n")
+		}
+	}
+	var workspace struct {
+		Folders    []map[string]string `json:"folders"`
+		Settings   map[string]any      `json:"settings"`
+		Extensions struct {
+			Recommendations []string `json:"recommendations"`
+		} `json:"extensions"`
+	}
+	if err := json.Unmarshal([]byte(body.String()), &workspace); err != nil {
+		t.Fatalf("workspace is not JSON with comments: %v\n%s", err, content)
+	}
+	if !reflect.DeepEqual(workspace.Folders, []map[string]string{{"path": ".."}}) {
+		t.Fatalf("folders = %v", workspace.Folders)
+	}
+	if title, _ := workspace.Settings["window.title"].(string); !strings.Contains(title, "[agent-layer]") {
+		t.Fatalf("window.title = %v", workspace.Settings["window.title"])
+	}
+	for _, key := range []string{"chat.agentSkillsLocations", "chat.tools.terminal.autoApprove"} {
+		if _, ok := workspace.Settings[key]; !ok {
+			t.Fatalf("expected %s in settings: %v", key, workspace.Settings)
+		}
+	}
+	if want := []string{"github.copilot-chat", "openai.chatgpt", "anthropic.claude-code"}; !reflect.DeepEqual(workspace.Extensions.Recommendations, want) {
+		t.Fatalf("recommendations = %v, want %v", workspace.Extensions.Recommendations, want)
+	}
+}
+
+func TestCleanVSCodeWorkspace_KeepsUserFile(t *testing.T) {
+	t.Parallel()
+	root := t.TempDir()
+	path := VSCodeWorkspacePath(root)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(`{"folders": [{"path": ".."}]}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := cleanVSCodeWorkspace(RealSystem{}, root); err != nil {
+		t.Fatalf("cleanVSCodeWorkspace: %v", err)
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Fatalf("expected the user's workspace file to be kept: %v", err)
+	}
+
+	if err := WriteVSCodeWorkspace(RealSystem{}, root, &config.ProjectConfig{Root: root}); err != nil {
+		t.Fatal(err)
+	}
+	if err := cleanVSCodeWorkspace(RealSystem{}, root); err != nil {
+		t.Fatalf("cleanVSCodeWorkspace: %v", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatalf("expected the generated workspace file to be removed, stat err %v", err)
+	}
+}
@@ -5,10 +5,16 @@ import (
 	"os"
 
 	"golang.org/x/term"
+
+	"github.com/conn-castle/agent-layer/internal/ci"
 )
 
 // IsInteractive reports whether stdin and stdout are both interactive terminals.
 // This is the canonical implementation for terminal detection across the codebase.
+// CI mode always reports false so no command prompts.
 func IsInteractive() bool {
+	if ci.Enabled(os.Getenv) {
+		return false
+	}
 	return term.IsTerminal(int(os.Stdin.Fd())) && term.IsTerminal(int(os.Stdout.Fd())) //nolint:gosec // Unix file descriptors are small non-negative ints; cast is safe on all supported platforms
 }
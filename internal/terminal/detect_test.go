@@ -1,6 +1,10 @@
 package terminal
 
-import "testing"
+import (
+	"testing"
+
+	"github.com/conn-castle/agent-layer/internal/ci"
+)
 
 func TestIsInteractive(t *testing.T) {
 	// go test always runs with piped stdin/stdout, so IsInteractive
@@ -9,3 +13,10 @@ func TestIsInteractive(t *testing.T) {
 		t.Error("expected false: stdin/stdout are not terminals under go test")
 	}
 }
+
+func TestIsInteractiveFalseInCIMode(t *testing.T) {
+	t.Setenv(ci.EnvVar, "1")
+	if IsInteractive() {
+		t.Error("expected false in CI mode")
+	}
+}
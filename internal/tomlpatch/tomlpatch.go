@@ -11,7 +11,12 @@ import (
 
 // Block is a contiguous TOML table or array-of-table block.
 type Block struct {
-	Name  string
+	Name string
+	// Leading is the comment paragraph directly above the header, set off
+	// from the previous block by a blank line. It documents this block, so it
+	// moves with it when blocks are reordered.
+	Leading []string
+	// Lines starts with the header line.
 	Lines []string
 }
 
@@ -199,10 +204,24 @@ func WalkLinesOutsideMultiline(lines []string, fn func(i int, line string, state
 	}
 }
 
+// ownLines returns the lines of a block up to its first sub-table header, so
+// key lookups in an array-of-tables element skip the element's sub-tables.
+func ownLines(lines []string) []string {
+	end := len(lines)
+	WalkLinesOutsideMultiline(lines, func(i int, line string, _ StringState) LineWalkResult {
+		if _, _, ok := ParseHeader(line); ok && i > 0 {
+			end = i
+			return LineWalkResult{Stop: true}
+		}
+		return LineWalkResult{}
+	})
+	return lines[:end]
+}
+
 // ExtractBlockKeyValue returns the unquoted value for a key in a TOML block.
 func ExtractBlockKeyValue(lines []string, key string) string {
 	value := ""
-	WalkLinesOutsideMultiline(lines, func(_ int, line string, state StringState) LineWalkResult {
+	WalkLinesOutsideMultiline(ownLines(lines), func(_ int, line string, state StringState) LineWalkResult {
 		trimmed := strings.TrimSpace(line)
 		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
 			return LineWalkResult{}
@@ -218,11 +237,29 @@ func ExtractBlockKeyValue(lines []string, key string) string {
 }
 
 // RemoveKeyFromBlock removes all uncommented lines for key from block,
-// including multiline continuation lines and dotted sub-key assignments.
+// including multiline continuation lines, dotted sub-key assignments, and
+// sub-tables of the block named after key (such as [servers.env] for key env
+// in a [[servers]] block) through the next header.
 func RemoveKeyFromBlock(block *Block, key string) {
 	type lineRange struct{ start, end int }
 	var ranges []lineRange
+	subTable := block.Name + "." + FormatKey(key)
+	tableStart := -1
 	WalkLinesOutsideMultiline(block.Lines, func(i int, line string, state StringState) LineWalkResult {
+		if name, _, isHeader := ParseHeader(line); isHeader && i > 0 {
+			if tableStart >= 0 {
+				ranges = append(ranges, lineRange{tableStart, i - 1})
+				tableStart = -1
+			}
+			name = canonicalTableName(name)
+			if name == subTable || strings.HasPrefix(name, subTable+".") {
+				tableStart = i
+			}
+			return LineWalkResult{}
+		}
+		if tableStart >= 0 {
+			return LineWalkResult{}
+		}
 		parsed, ok := ParseKeyLineWithState(line, key, state)
 		if !ok {
 			parsed, ok = ParseDottedPrefixLine(line, key)
@@ -234,6 +271,9 @@ func RemoveKeyFromBlock(block *Block, key string) {
 		}
 		return LineWalkResult{}
 	})
+	if tableStart >= 0 {
+		ranges = append(ranges, lineRange{tableStart, len(block.Lines) - 1})
+	}
 	for i := len(ranges) - 1; i >= 0; i-- {
 		r := ranges[i]
 		block.Lines = append(block.Lines[:r.start], block.Lines[r.end+1:]...)
@@ -478,7 +518,7 @@ func SetCommentedKeyLine(block *Block, templateBlock *Block, key string, afterKe
 func FindKeyLine(lines []string, key string) (KeyLine, bool) {
 	result := KeyLine{}
 	found := false
-	WalkLinesOutsideMultiline(lines, func(_ int, line string, state StringState) LineWalkResult {
+	WalkLinesOutsideMultiline(ownLines(lines), func(_ int, line string, state StringState) LineWalkResult {
 		parsed, ok := ParseKeyLineWithState(line, key, state)
 		if ok {
 			result = parsed
@@ -556,7 +596,7 @@ func EnsureCommented(line string) string {
 func ReplaceOrInsertLine(block *Block, key string, newLine string, afterKey string) {
 	var matches []int
 	uncommentedIndex := -1
-	WalkLinesOutsideMultiline(block.Lines, func(i int, line string, state StringState) LineWalkResult {
+	WalkLinesOutsideMultiline(ownLines(block.Lines), func(i int, line string, state StringState) LineWalkResult {
 		parsed, ok := ParseKeyLineWithState(line, key, state)
 		if !ok {
 			return LineWalkResult{}
@@ -581,7 +621,7 @@ func ReplaceOrInsertLine(block *Block, key string, newLine string, afterKey stri
 		}
 		return
 	}
-	insertAt := FindInsertIndex(block.Lines, afterKey)
+	insertAt := FindInsertIndex(ownLines(block.Lines), afterKey)
 	block.Lines = append(block.Lines[:insertAt], append([]string{newLine}, block.Lines[insertAt:]...)...)
 }
 
@@ -620,7 +660,11 @@ func FormatValue(value any) string {
 	}
 }
 
-// ParseDocument splits TOML content into a line-aware document.
+// ParseDocument splits TOML content into a line-aware document. Block names
+// are canonical dotted paths, so [ a . "b" ] and [a.b] name the same table.
+// Tables and arrays declared under an array-of-tables element (such as
+// [servers.env] after [[servers]]) belong to that element and stay in its
+// block, since moving them away would attach them to a different element.
 func ParseDocument(content string) Document {
 	lines := strings.Split(content, "\n")
 	sections := make(map[string]*Block)
@@ -629,6 +673,11 @@ func ParseDocument(content string) Document {
 	var preamble []string
 	var current *Block
 	var currentIsArray bool
+	// commentRun is the index in the current lines where a trailing run of
+	// comment lines starts, or -1; commentRunSetOff records whether a blank
+	// line precedes that run.
+	commentRun := -1
+	commentRunSetOff := false
 
 	flush := func() {
 		if current == nil {
@@ -643,6 +692,21 @@ func ParseDocument(content string) Document {
 		current = nil
 		currentIsArray = false
 	}
+	appendLine := func(line string, inMultiline bool) {
+		target := &preamble
+		if current != nil {
+			target = &current.Lines
+		}
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case inMultiline || !strings.HasPrefix(trimmed, "#"):
+			commentRun = -1
+		case commentRun < 0:
+			commentRun = len(*target)
+			commentRunSetOff = commentRun > 0 && strings.TrimSpace((*target)[commentRun-1]) == ""
+		}
+		*target = append(*target, line)
+	}
 
 	state := StateNone
 	for _, line := range lines {
@@ -650,27 +714,36 @@ func ParseDocument(content string) Document {
 		// "[mcp_servers.x]" line embedded in a triple-quoted value) must be treated
 		// as string content, not a real table header, so it stays in its block.
 		if StateInMultiline(state) {
-			if current == nil {
-				preamble = append(preamble, line)
-			} else {
-				current.Lines = append(current.Lines, line)
-			}
+			appendLine(line, true)
 			_, state = ScanLineForComment(line, state)
 			continue
 		}
 		name, isArray, ok := ParseHeader(line)
 		if ok {
+			name = canonicalTableName(name)
+			if current != nil && currentIsArray && strings.HasPrefix(name, current.Name+".") {
+				appendLine(line, false)
+				commentRun = -1
+				_, state = ScanLineForComment(line, state)
+				continue
+			}
+			var leading []string
+			target := &preamble
+			if current != nil {
+				target = &current.Lines
+			}
+			if commentRun >= 0 && commentRunSetOff {
+				leading = CloneLines((*target)[commentRun:])
+				*target = (*target)[:commentRun]
+			}
+			commentRun = -1
 			flush()
-			current = &Block{Name: name, Lines: []string{line}}
+			current = &Block{Name: name, Leading: leading, Lines: []string{line}}
 			currentIsArray = isArray
 			_, state = ScanLineForComment(line, state)
 			continue
 		}
-		if current == nil {
-			preamble = append(preamble, line)
-		} else {
-			current.Lines = append(current.Lines, line)
-		}
+		appendLine(line, false)
 		_, state = ScanLineForComment(line, state)
 	}
 	flush()
@@ -683,6 +756,16 @@ func ParseDocument(content string) Document {
 	}
 }
 
+// canonicalTableName normalizes spacing and quoting in a header name. Names
+// that do not parse as key paths are returned unchanged.
+func canonicalTableName(name string) string {
+	parts, ok := ParseKeyPath(name)
+	if !ok {
+		return name
+	}
+	return FormatDottedKeyPath(parts)
+}
+
 // ParseHeader detects a TOML table header and extracts its name.
 func ParseHeader(line string) (string, bool, bool) {
 	trimmed := strings.TrimSpace(line)
@@ -465,3 +465,57 @@ func TestParseDocument_DuplicateSectionsAndArrays(t *testing.T) {
 		t.Fatal("expected invalid basic-string escape in key path to fail")
 	}
 }
+
+func TestParseDocument_LeadingCommentsAndSubTables(t *testing.T) {
+	t.Parallel()
+	content := "[zeta]\nvalue = 1\n# trailing\n\n# About alpha.\n[ alpha . \"beta\" ]\nvalue = 2\n\n[[plugins]]\nid = \"lint\"\n\n[plugins.settings]\nlevel = \"strict\"\n"
+
+	doc := ParseDocument(content)
+
+	zeta := strings.Join(doc.Sections["zeta"].Lines, "\n")
+	if !strings.Contains(zeta, "# trailing") || strings.Contains(zeta, "About alpha") {
+		t.Fatalf("unexpected zeta block:\n%s", zeta)
+	}
+	alpha := doc.Sections["alpha.beta"]
+	if alpha == nil {
+		t.Fatalf("expected canonical alpha.beta section, got %#v", doc.Order)
+	}
+	if len(alpha.Leading) != 1 || alpha.Leading[0] != "# About alpha." {
+		t.Fatalf("unexpected leading comment %#v", alpha.Leading)
+	}
+	if _, ok := doc.Sections["plugins.settings"]; ok {
+		t.Fatalf("array element sub-table must stay in its element, got %#v", doc.Order)
+	}
+	plugin := strings.Join(doc.Arrays["plugins"][0].Lines, "\n")
+	if !strings.Contains(plugin, "[plugins.settings]\nlevel = \"strict\"") {
+		t.Fatalf("unexpected plugins element:\n%s", plugin)
+	}
+}
+
+func TestRemoveKeyFromBlock_RemovesSubTables(t *testing.T) {
+	t.Parallel()
+	block := &Block{Name: "mcp.servers", Lines: []string{
+		"[[mcp.servers]]",
+		`id = "docs"`,
+		`command = "docs-mcp"`,
+		"",
+		"[mcp.servers.env]",
+		`TOKEN = "x"`,
+		"",
+		"[mcp.servers.env.nested]",
+		"deep = true",
+		"",
+		"[mcp.servers.headers]",
+		`Authorization = "y"`,
+	}}
+
+	RemoveKeyFromBlock(block, "env")
+
+	got := strings.Join(block.Lines, "\n")
+	if strings.Contains(got, "env") || strings.Contains(got, "deep") {
+		t.Fatalf("expected env sub-tables removed, got:\n%s", got)
+	}
+	if !strings.Contains(got, "[mcp.servers.headers]\nAuthorization = \"y\"") || !strings.Contains(got, `command = "docs-mcp"`) {
+		t.Fatalf("expected unrelated keys kept, got:\n%s", got)
+	}
+}
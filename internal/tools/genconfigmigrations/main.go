@@ -0,0 +1,191 @@
+//go:build tools
+// +build tools
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/conn-castle/agent-layer/internal/config"
+	"github.com/conn-castle/agent-layer/internal/version"
+)
+
+const schemaVersion = 1
+
+// configOperation mirrors the config_rename_key and config_delete_key shapes
+// of the upgrade migration manifest, in the field order the manifests use.
+type configOperation struct {
+	ID             string `json:"id"`
+	Kind           string `json:"kind"`
+	From           string `json:"from,omitempty"`
+	To             string `json:"to,omitempty"`
+	Key            string `json:"key,omitempty"`
+	Rationale      string `json:"rationale"`
+	SourceAgnostic bool   `json:"source_agnostic"`
+}
+
+// migrationManifest keeps operations raw so hand-written operations of any
+// kind round-trip unchanged.
+type migrationManifest struct {
+	SchemaVersion   int               `json:"schema_version"`
+	TargetVersion   string            `json:"target_version"`
+	MinPriorVersion string            `json:"min_prior_version"`
+	Operations      []json.RawMessage `json:"operations"`
+}
+
+func main() {
+	ver := flag.String("version", "", "release version whose manifest receives the operations (for example v0.15.0)")
+	minPrior := flag.String("min-prior-version", "", "min_prior_version for a new manifest (ignored when the manifest exists)")
+	repoRoot := flag.String("repo-root", ".", "repository root")
+	flag.Parse()
+
+	if strings.TrimSpace(*ver) == "" {
+		fatalf("--version is required")
+	}
+	target, err := version.Normalize(*ver)
+	if err != nil {
+		fatalf("normalize version %q: %v", *ver, err)
+	}
+	path := filepath.Join(*repoRoot, "internal", "templates", "migrations", target+".json")
+
+	ops := operationsFor(config.Deprecations(), target)
+	if len(ops) == 0 {
+		fmt.Printf("no config deprecations target %s; %s left unchanged\n", target, path)
+		return
+	}
+	manifest, err := readManifest(path, target, *minPrior)
+	if err != nil {
+		fatalf("%v", err)
+	}
+	if err := mergeOperations(&manifest, ops); err != nil {
+		fatalf("merge operations into %s: %v", path, err)
+	}
+	data, err := encodeManifest(manifest)
+	if err != nil {
+		fatalf("encode %s: %v", path, err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		fatalf("write %s: %v", path, err)
+	}
+	fmt.Printf("wrote %d config operation(s) to %s\n", len(ops), path)
+}
+
+// operationsFor builds the migration operations for deprecations migrated in
+// target: a rename when the entry has a replacement, otherwise a delete.
+func operationsFor(deprecations []config.Deprecation, target string) []configOperation {
+	var ops []configOperation
+	for _, deprecation := range deprecations {
+		if deprecation.Since != target {
+			continue
+		}
+		op := configOperation{
+			ID:             deprecation.MigrationID,
+			Kind:           "config_delete_key",
+			Key:            deprecation.Key,
+			Rationale:      deprecation.Rationale,
+			SourceAgnostic: true,
+		}
+		if deprecation.Replacement != "" {
+			op.Kind = "config_rename_key"
+			op.Key = ""
+			op.From = deprecation.Key
+			op.To = deprecation.Replacement
+		}
+		ops = append(ops, op)
+	}
+	return ops
+}
+
+// readManifest loads the manifest at path, or starts a new one when it does
+// not exist yet (which requires minPrior).
+func readManifest(path string, target string, minPrior string) (migrationManifest, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		if strings.TrimSpace(minPrior) == "" {
+			return migrationManifest{}, fmt.Errorf("%s does not exist; pass --min-prior-version to create it", path)
+		}
+		normalized, err := version.Normalize(minPrior)
+		if err != nil {
+			return migrationManifest{}, fmt.Errorf("normalize min prior version %q: %w", minPrior, err)
+		}
+		return migrationManifest{SchemaVersion: schemaVersion, TargetVersion: target, MinPriorVersion: normalized}, nil
+	}
+	if err != nil {
+		return migrationManifest{}, fmt.Errorf("read %s: %w", path, err)
+	}
+	var manifest migrationManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return migrationManifest{}, fmt.Errorf("decode %s: %w", path, err)
+	}
+	if manifest.TargetVersion != target {
+		return migrationManifest{}, fmt.Errorf("%s has target_version %q, want %q", path, manifest.TargetVersion, target)
+	}
+	return manifest, nil
+}
+
+// mergeOperations replaces operations whose id matches a generated one and
+// appends the rest, then orders operations by id as the upgrader applies them.
+func mergeOperations(manifest *migrationManifest, ops []configOperation) error {
+	ids := make([]string, len(manifest.Operations))
+	index := make(map[string]int, len(manifest.Operations))
+	for i, raw := range manifest.Operations {
+		var header struct {
+			ID string `json:"id"`
+		}
+		if err := json.Unmarshal(raw, &header); err != nil {
+			return fmt.Errorf("decode operation %d: %w", i, err)
+		}
+		ids[i] = header.ID
+		index[header.ID] = i
+	}
+	for _, op := range ops {
+		raw, err := json.Marshal(op)
+		if err != nil {
+			return err
+		}
+		if i, ok := index[op.ID]; ok {
+			manifest.Operations[i] = raw
+			continue
+		}
+		index[op.ID] = len(manifest.Operations)
+		ids = append(ids, op.ID)
+		manifest.Operations = append(manifest.Operations, raw)
+	}
+	order := make([]int, len(ids))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool { return ids[order[a]] < ids[order[b]] })
+	sorted := make([]json.RawMessage, len(order))
+	for i, j := range order {
+		sorted[i] = manifest.Operations[j]
+	}
+	manifest.Operations = sorted
+	return nil
+}
+
+// encodeManifest renders the manifest with two-space indentation, without
+// HTML escaping, and with a trailing newline, matching the committed files.
+func encodeManifest(manifest migrationManifest) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(manifest); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func fatalf(format string, args ...any) {
+	_, _ = fmt.Fprintf(os.Stderr, format+"\n", args...)
+	os.Exit(1)
+}
@@ -0,0 +1,88 @@
+//go:build tools
+// +build tools
+
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/conn-castle/agent-layer/internal/config"
+)
+
+func TestOperationsForBuildsRenamesAndDeletes(t *testing.T) {
+	deprecations := []config.Deprecation{
+		{Key: "agents.old.enabled", Replacement: "agents.new.enabled", Since: "1.2.0", MigrationID: "a-rename", Rationale: "rename"},
+		{Key: "agents.old", Since: "1.2.0", MigrationID: "b-delete", Rationale: "delete"},
+		{Key: "agents.other", Since: "1.1.0", MigrationID: "a-other", Rationale: "older"},
+	}
+
+	ops := operationsFor(deprecations, "1.2.0")
+
+	assert.Equal(t, []configOperation{
+		{ID: "a-rename", Kind: "config_rename_key", From: "agents.old.enabled", To: "agents.new.enabled", Rationale: "rename", SourceAgnostic: true},
+		{ID: "b-delete", Kind: "config_delete_key", Key: "agents.old", Rationale: "delete", SourceAgnostic: true},
+	}, ops)
+}
+
+func TestMergeOperationsReplacesByIDAndSorts(t *testing.T) {
+	manifest := migrationManifest{Operations: []json.RawMessage{
+		json.RawMessage(`{"id":"c-hand-written","kind":"append_to_file"}`),
+		json.RawMessage(`{"id":"a-rename","kind":"config_rename_key","from":"stale"}`),
+	}}
+
+	require.NoError(t, mergeOperations(&manifest, []configOperation{
+		{ID: "a-rename", Kind: "config_rename_key", From: "x", To: "y", Rationale: "r", SourceAgnostic: true},
+		{ID: "b-delete", Kind: "config_delete_key", Key: "z", Rationale: "r", SourceAgnostic: true},
+	}))
+
+	var ids []string
+	for _, raw := range manifest.Operations {
+		var op configOperation
+		require.NoError(t, json.Unmarshal(raw, &op))
+		ids = append(ids, op.ID)
+	}
+	assert.Equal(t, []string{"a-rename", "b-delete", "c-hand-written"}, ids)
+	assert.JSONEq(t, `{"id":"a-rename","kind":"config_rename_key","from":"x","to":"y","rationale":"r","source_agnostic":true}`, string(manifest.Operations[0]))
+	assert.JSONEq(t, `{"id":"c-hand-written","kind":"append_to_file"}`, string(manifest.Operations[2]))
+}
+
+func TestReadManifestNewRequiresMinPrior(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "1.2.0.json")
+
+	_, err := readManifest(path, "1.2.0", "")
+	require.ErrorContains(t, err, "--min-prior-version")
+
+	manifest, err := readManifest(path, "1.2.0", "v1.1.0")
+	require.NoError(t, err)
+	assert.Equal(t, migrationManifest{SchemaVersion: schemaVersion, TargetVersion: "1.2.0", MinPriorVersion: "1.1.0"}, manifest)
+}
+
+func TestReadManifestRejectsTargetMismatch(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "1.2.0.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"schema_version":1,"target_version":"1.3.0","min_prior_version":"1.0.0","operations":[]}`), 0o600))
+
+	_, err := readManifest(path, "1.2.0", "")
+	require.ErrorContains(t, err, `target_version "1.3.0"`)
+}
+
+func TestShippedManifestsRoundTrip(t *testing.T) {
+	root := filepath.Join("..", "..", "..")
+	for _, target := range []string{"0.8.8", "0.10.2", "0.12.0", "0.14.0"} {
+		path := filepath.Join(root, "internal", "templates", "migrations", target+".json")
+		original, err := os.ReadFile(path)
+		require.NoError(t, err)
+
+		manifest, err := readManifest(path, target, "")
+		require.NoError(t, err)
+		require.NoError(t, mergeOperations(&manifest, operationsFor(config.Deprecations(), target)))
+		data, err := encodeManifest(manifest)
+		require.NoError(t, err)
+		assert.Equal(t, string(original), string(data), "regenerating %s must not change it", path)
+	}
+}
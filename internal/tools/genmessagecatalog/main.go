@@ -0,0 +1,188 @@
+//go:build tools
+// +build tools
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/conn-castle/agent-layer/internal/i18n"
+)
+
+func main() {
+	locale := flag.String("locale", "", "locale whose catalog to write (for example de or pt-BR)")
+	repoRoot := flag.String("repo-root", ".", "repository root")
+	flag.Parse()
+
+	if !i18n.Valid(*locale) || i18n.Normalize(*locale) == i18n.DefaultLocale {
+		fatalf("--locale must name a language other than %s", i18n.DefaultLocale)
+	}
+	sources, err := extractMessages(filepath.Join(*repoRoot, "internal", "messages"))
+	if err != nil {
+		fatalf("extract messages: %v", err)
+	}
+	path := filepath.Join(*repoRoot, "internal", "i18n", "locales", i18n.Normalize(*locale)+".json")
+	existing, err := readCatalog(path)
+	if err != nil {
+		fatalf("%v", err)
+	}
+	catalog, added, dropped := mergeCatalog(existing, sources)
+	data, err := encodeCatalog(catalog)
+	if err != nil {
+		fatalf("encode %s: %v", path, err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		fatalf("write %s: %v", path, err)
+	}
+	fmt.Printf("wrote %s: %d messages, %d new, %d stale removed\n", path, len(catalog), added, dropped)
+}
+
+// extractMessages returns the value of every string constant declared in the
+// Go files of dir, evaluating concatenations of other constants.
+func extractMessages(dir string) ([]string, error) {
+	fset := token.NewFileSet()
+	paths, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return nil, err
+	}
+	var specs []*ast.ValueSpec
+	for _, path := range paths {
+		if strings.HasSuffix(path, "_test.go") {
+			continue
+		}
+		file, err := parser.ParseFile(fset, path, nil, 0)
+		if err != nil {
+			return nil, err
+		}
+		for _, decl := range file.Decls {
+			gen, ok := decl.(*ast.GenDecl)
+			if !ok || gen.Tok != token.CONST {
+				continue
+			}
+			for _, spec := range gen.Specs {
+				specs = append(specs, spec.(*ast.ValueSpec))
+			}
+		}
+	}
+	values := make(map[string]constant.Value)
+	// Constants may refer to constants declared later or in another file, so
+	// evaluate until a pass resolves nothing new.
+	for resolved := true; resolved; {
+		resolved = false
+		for _, spec := range specs {
+			for i, name := range spec.Names {
+				if _, done := values[name.Name]; done || i >= len(spec.Values) {
+					continue
+				}
+				if value := evalString(spec.Values[i], values); value != nil {
+					values[name.Name] = value
+					resolved = true
+				}
+			}
+		}
+	}
+	seen := make(map[string]struct{}, len(values))
+	out := make([]string, 0, len(values))
+	for _, value := range values {
+		text := constant.StringVal(value)
+		if strings.TrimSpace(text) == "" {
+			continue
+		}
+		if _, dup := seen[text]; dup {
+			continue
+		}
+		seen[text] = struct{}{}
+		out = append(out, text)
+	}
+	sort.Strings(out)
+	return out, nil
+}
+
+// evalString evaluates a string constant expression, or returns nil when it
+// refers to a constant that is not known yet.
+func evalString(expr ast.Expr, values map[string]constant.Value) constant.Value {
+	switch e := expr.(type) {
+	case *ast.BasicLit:
+		if e.Kind != token.STRING {
+			return nil
+		}
+		return constant.MakeFromLiteral(e.Value, e.Kind, 0)
+	case *ast.Ident:
+		return values[e.Name]
+	case *ast.ParenExpr:
+		return evalString(e.X, values)
+	case *ast.BinaryExpr:
+		if e.Op != token.ADD {
+			return nil
+		}
+		left, right := evalString(e.X, values), evalString(e.Y, values)
+		if left == nil || right == nil {
+			return nil
+		}
+		return constant.BinaryOp(left, token.ADD, right)
+	}
+	return nil
+}
+
+// readCatalog loads the catalog at path, or an empty catalog when it does not
+// exist yet.
+func readCatalog(path string) (i18n.Catalog, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return i18n.Catalog{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", path, err)
+	}
+	var catalog i18n.Catalog
+	if err := json.Unmarshal(data, &catalog); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	return catalog, nil
+}
+
+// mergeCatalog keeps the translations in existing for every source message,
+// adds untranslated entries for new messages, and drops messages that no
+// longer exist. It reports how many entries it added and dropped.
+func mergeCatalog(existing i18n.Catalog, sources []string) (i18n.Catalog, int, int) {
+	merged := make(i18n.Catalog, len(sources))
+	added := 0
+	for _, source := range sources {
+		translated, ok := existing[source]
+		if !ok {
+			added++
+		}
+		merged[source] = translated
+	}
+	return merged, added, len(existing) + added - len(merged)
+}
+
+// encodeCatalog writes the catalog with sorted keys and without HTML escaping,
+// so translators see messages as they print.
+func encodeCatalog(catalog i18n.Catalog) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(catalog); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func fatalf(format string, args ...any) {
+	_, _ = fmt.Fprintf(os.Stderr, format+"\n", args...)
+	os.Exit(1)
+}
@@ -0,0 +1,45 @@
+//go:build tools
+// +build tools
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/conn-castle/agent-layer/internal/i18n"
+)
+
+func TestExtractMessagesEvaluatesConcatenations(t *testing.T) {
+	dir := t.TempDir()
+	src := "package messages\n\nconst (\n\tJoined = Base + \"\\n\" + `raw`\n\tBase   = \"base %s\"\n\tCount  = 3\n\tEmpty  = \"\"\n\tAgain  = \"base %s\"\n)\n"
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "cli.go"), []byte(src), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "cli_test.go"), []byte("package messages\n\nconst Ignored = \"test only\"\n"), 0o600))
+
+	got, err := extractMessages(dir)
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"base %s", "base %s\nraw"}, got)
+}
+
+func TestMergeCatalogKeepsTranslationsAndDropsStale(t *testing.T) {
+	existing := i18n.Catalog{"kept": "behalten", "stale": "veraltet"}
+
+	merged, added, dropped := mergeCatalog(existing, []string{"kept", "new"})
+
+	assert.Equal(t, i18n.Catalog{"kept": "behalten", "new": ""}, merged)
+	assert.Equal(t, 1, added)
+	assert.Equal(t, 1, dropped)
+}
+
+func TestExtractMessagesCoversRepoMessages(t *testing.T) {
+	got, err := extractMessages(filepath.Join("..", "..", "messages"))
+
+	require.NoError(t, err)
+	assert.Contains(t, got, "sync completed with warnings")
+	assert.Greater(t, len(got), 1000)
+}
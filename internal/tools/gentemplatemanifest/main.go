@@ -4,6 +4,7 @@
 package main
 
 import (
+	"bytes"
 	"crypto/sha256"
 	"encoding/json"
 	"flag"
@@ -14,10 +15,11 @@ import (
 	"regexp"
 	"sort"
 	"strings"
-	"time"
+	"unicode/utf8"
 
 	toml "github.com/pelletier/go-toml/v2"
 
+	"github.com/conn-castle/agent-layer/internal/clock"
 	"github.com/conn-castle/agent-layer/internal/version"
 )
 
@@ -60,6 +62,18 @@ type allowlistPolicyPayload struct {
 	UpstreamSetHash string   `json:"upstream_set_hash"`
 }
 
+// dirPolicy assigns an ownership policy to every destination path under a
+// directory prefix (ending in "/"). Exact-path policies take precedence; among
+// directory policies the longest matching prefix wins.
+type dirPolicy struct {
+	prefix   string
+	policyID string
+}
+
+// staticDirPolicies are directory policies that do not derive from a catalog.
+// Catalog skill directories are appended by directoryPolicies.
+var staticDirPolicies []dirPolicy
+
 type templateSource struct {
 	templatePath string
 	content      []byte
@@ -95,14 +109,18 @@ func main() {
 	if err != nil {
 		fatalf("load CLI skills catalog prefixes: %v", err)
 	}
-	entries, err := buildManifestEntries(sources, catalogPrefixes)
+	dirPolicies, err := directoryPolicies(catalogPrefixes)
+	if err != nil {
+		fatalf("build directory policies: %v", err)
+	}
+	entries, err := buildManifestEntries(sources, dirPolicies)
 	if err != nil {
 		fatalf("build manifest entries: %v", err)
 	}
 	manifest := templateManifest{
 		SchemaVersion: schemaVersion,
 		Version:       normalizedVersion,
-		GeneratedAt:   time.Now().UTC().Format(time.RFC3339),
+		GeneratedAt:   clock.Format(clock.Real{}.Now()),
 		Files:         entries,
 		Metadata: map[string]any{
 			"source_version": normalizedVersion,
@@ -190,18 +208,21 @@ func collectTemplateSources(root string) ([]templateSource, error) {
 	return sources, nil
 }
 
-func buildManifestEntries(sources []templateSource, catalogSkillPrefixes []string) ([]manifestFileEntry, error) {
+func buildManifestEntries(sources []templateSource, dirPolicies []dirPolicy) ([]manifestFileEntry, error) {
 	entries := make([]manifestFileEntry, 0, len(sources)*2)
 	seen := make(map[string]struct{}, len(sources)*2)
 	for _, source := range sources {
-		normalized := normalizeTemplateContent(string(source.content))
-		fullHash := hashString(normalized)
+		binary := isBinaryContent(source.content)
+		fullHash := templateContentHash(source.content)
 		for _, destPath := range source.dests {
 			if _, exists := seen[destPath]; exists {
 				return nil, fmt.Errorf("duplicate destination path %s", destPath)
 			}
 			seen[destPath] = struct{}{}
-			policyID := ownershipPolicyForPath(destPath, catalogSkillPrefixes)
+			policyID := ownershipPolicyForPath(destPath, dirPolicies)
+			if binary && policyID != "" && policyID != policyCatalogSkills {
+				return nil, fmt.Errorf("binary file %s cannot use text policy %s", destPath, policyID)
+			}
 			payload, err := ownershipPolicyPayload(policyID, source.content)
 			if err != nil {
 				return nil, fmt.Errorf("build policy payload for %s: %w", destPath, err)
@@ -288,7 +309,44 @@ func catalogSkillPathPrefixes(root string) ([]string, error) {
 	return out, nil
 }
 
-func ownershipPolicyForPath(relPath string, catalogSkillPrefixes []string) string {
+// directoryPolicies combines staticDirPolicies with one catalog_skills_v1
+// policy per catalog skill prefix, sorted longest prefix first. A prefix
+// mapped to two different policies is an error.
+func directoryPolicies(catalogSkillPrefixes []string) ([]dirPolicy, error) {
+	all := append([]dirPolicy(nil), staticDirPolicies...)
+	for _, prefix := range catalogSkillPrefixes {
+		all = append(all, dirPolicy{prefix: prefix, policyID: policyCatalogSkills})
+	}
+	byPrefix := make(map[string]string, len(all))
+	out := make([]dirPolicy, 0, len(all))
+	for _, policy := range all {
+		if !strings.HasSuffix(policy.prefix, "/") {
+			return nil, fmt.Errorf("directory policy prefix %q must end with /", policy.prefix)
+		}
+		switch policy.policyID {
+		case policyMemoryEntries, policyMemoryRoadmap, policyAllowlist, policyCatalogSkills:
+		default:
+			return nil, fmt.Errorf("directory policy %s has unknown policy %q", policy.prefix, policy.policyID)
+		}
+		if existing, ok := byPrefix[policy.prefix]; ok {
+			if existing != policy.policyID {
+				return nil, fmt.Errorf("directory %s has conflicting policies %s and %s", policy.prefix, existing, policy.policyID)
+			}
+			continue
+		}
+		byPrefix[policy.prefix] = policy.policyID
+		out = append(out, policy)
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		if len(out[i].prefix) != len(out[j].prefix) {
+			return len(out[i].prefix) > len(out[j].prefix)
+		}
+		return out[i].prefix < out[j].prefix
+	})
+	return out, nil
+}
+
+func ownershipPolicyForPath(relPath string, dirPolicies []dirPolicy) string {
 	switch relPath {
 	case ".agent-layer/commands.allow":
 		return policyAllowlist
@@ -297,9 +355,9 @@ func ownershipPolicyForPath(relPath string, catalogSkillPrefixes []string) strin
 	case "docs/agent-layer/ISSUES.md", "docs/agent-layer/BACKLOG.md", "docs/agent-layer/DECISIONS.md", "docs/agent-layer/COMMANDS.md", "docs/agent-layer/CONTEXT.md":
 		return policyMemoryEntries
 	}
-	for _, prefix := range catalogSkillPrefixes {
-		if strings.HasPrefix(relPath, prefix) {
-			return policyCatalogSkills
+	for _, policy := range dirPolicies {
+		if strings.HasPrefix(relPath, policy.prefix) {
+			return policy.policyID
 		}
 	}
 	return ""
@@ -400,6 +458,23 @@ func normalizeTemplateContent(content string) string {
 	return strings.TrimRight(content, "\n") + "\n"
 }
 
+// isBinaryContent reports whether a template is a binary asset: it contains a
+// NUL byte or is not valid UTF-8. Must match the runtime check in
+// internal/install.
+func isBinaryContent(content []byte) bool {
+	return bytes.IndexByte(content, 0) >= 0 || !utf8.Valid(content)
+}
+
+// templateContentHash hashes text templates after line-ending normalization
+// and binary assets over their raw bytes.
+func templateContentHash(content []byte) string {
+	if isBinaryContent(content) {
+		sum := sha256.Sum256(content)
+		return fmt.Sprintf("%x", sum[:])
+	}
+	return hashString(normalizeTemplateContent(string(content)))
+}
+
 func hashString(content string) string {
 	sum := sha256.Sum256([]byte(content))
 	return fmt.Sprintf("%x", sum[:])
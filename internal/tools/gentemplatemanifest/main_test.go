@@ -186,9 +186,65 @@ func TestBuildManifestEntriesClassifiesCatalogSkillsFromDerivedPrefixes(t *testi
 		templatePath: "skills-catalog/custom-cli/SKILL.md",
 		content:      []byte("skill body"),
 		dests:        []string{".agent-layer/skills/custom-cli/SKILL.md"},
-	}}, []string{".agent-layer/skills/custom-cli/"})
+	}}, []dirPolicy{{prefix: ".agent-layer/skills/custom-cli/", policyID: policyCatalogSkills}})
 	require.NoError(t, err)
 	require.Len(t, entries, 1)
 
 	assert.Equal(t, policyCatalogSkills, entries[0].PolicyID)
 }
+
+func TestBuildManifestEntriesHashesBinaryAssetsRaw(t *testing.T) {
+	binary := []byte{0x89, 'P', 'N', 'G', '\r', '\n', 0x1a, '\n', 0x00}
+	entries, err := buildManifestEntries([]templateSource{
+		{
+			templatePath: "skills/demo/assets/logo.png",
+			content:      binary,
+			dests:        []string{".agent-layer/skills/demo/assets/logo.png"},
+		},
+		{
+			templatePath: "skills/demo/scripts/run.sh",
+			content:      []byte("echo hi\r\n\n"),
+			dests:        []string{".agent-layer/skills/demo/scripts/run.sh"},
+		},
+	}, nil)
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+
+	assert.Equal(t, hashString(string(binary)), entries[0].FullHashNormalized)
+	assert.Equal(t, hashString("echo hi\n"), entries[1].FullHashNormalized)
+}
+
+func TestBuildManifestEntriesRejectsBinaryUnderTextPolicy(t *testing.T) {
+	_, err := buildManifestEntries([]templateSource{{
+		templatePath: "commands.allow",
+		content:      []byte{0x00, 0x01},
+		dests:        []string{".agent-layer/commands.allow"},
+	}}, nil)
+	require.ErrorContains(t, err, "cannot use text policy")
+}
+
+func TestDirectoryPoliciesLongestPrefixWins(t *testing.T) {
+	orig := staticDirPolicies
+	t.Cleanup(func() { staticDirPolicies = orig })
+	staticDirPolicies = []dirPolicy{{prefix: ".agent-layer/skills/", policyID: policyCatalogSkills}}
+
+	policies, err := directoryPolicies([]string{".agent-layer/skills/custom-cli/", ".agent-layer/skills/custom-cli/"})
+	require.NoError(t, err)
+	require.Len(t, policies, 2)
+	assert.Equal(t, ".agent-layer/skills/custom-cli/", policies[0].prefix)
+	assert.Equal(t, policyCatalogSkills, ownershipPolicyForPath(".agent-layer/skills/other/SKILL.md", policies))
+	assert.Equal(t, policyAllowlist, ownershipPolicyForPath(".agent-layer/commands.allow", policies))
+	assert.Equal(t, "", ownershipPolicyForPath(".agent-layer/instructions/00.md", policies))
+
+	staticDirPolicies = []dirPolicy{{prefix: ".agent-layer/skills/custom-cli/", policyID: policyAllowlist}}
+	_, err = directoryPolicies([]string{".agent-layer/skills/custom-cli/"})
+	require.ErrorContains(t, err, "conflicting policies")
+
+	staticDirPolicies = []dirPolicy{{prefix: ".agent-layer/skills", policyID: policyCatalogSkills}}
+	_, err = directoryPolicies(nil)
+	require.ErrorContains(t, err, "must end with /")
+
+	staticDirPolicies = []dirPolicy{{prefix: ".agent-layer/skills/", policyID: "bogus"}}
+	_, err = directoryPolicies(nil)
+	require.ErrorContains(t, err, "unknown policy")
+}
@@ -0,0 +1,30 @@
+//go:build tools
+// +build tools
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/conn-castle/agent-layer/internal/install"
+)
+
+// verifyMigrationChain is a test seam for install.VerifyMigrationChain.
+var verifyMigrationChain = install.VerifyMigrationChain
+
+func main() {
+	os.Exit(run(os.Stdout, os.Stderr))
+}
+
+// run checks the embedded migration manifests and returns a non-zero exit
+// code when a release broke the upgrade chain.
+func run(out io.Writer, errOut io.Writer) int {
+	if err := verifyMigrationChain(); err != nil {
+		_, _ = fmt.Fprintln(errOut, err)
+		return 1
+	}
+	_, _ = fmt.Fprintln(out, "migration chain is continuous")
+	return 0
+}
@@ -0,0 +1,32 @@
+//go:build tools
+// +build tools
+
+package main
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunPassesOnEmbeddedManifests(t *testing.T) {
+	var out, errOut bytes.Buffer
+
+	assert.Equal(t, 0, run(&out, &errOut))
+	assert.Equal(t, "migration chain is continuous\n", out.String())
+	assert.Empty(t, errOut.String())
+}
+
+func TestRunFailsOnBrokenChain(t *testing.T) {
+	original := verifyMigrationChain
+	t.Cleanup(func() { verifyMigrationChain = original })
+	verifyMigrationChain = func() error { return errors.New("migration chain is broken:\n1.2.0: gap") }
+
+	var out, errOut bytes.Buffer
+
+	assert.Equal(t, 1, run(&out, &errOut))
+	assert.Empty(t, out.String())
+	assert.Contains(t, errOut.String(), "1.2.0: gap")
+}
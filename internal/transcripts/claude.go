@@ -0,0 +1,146 @@
+package transcripts
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/conn-castle/agent-layer/internal/i18n"
+	"github.com/conn-castle/agent-layer/internal/messages"
+)
+
+// claudeProjectChars matches the path characters Claude Code replaces with
+// "-" when naming a project's session directory.
+var claudeProjectChars = regexp.MustCompile(`[^A-Za-z0-9]`)
+
+type claudeLine struct {
+	Type      string `json:"type"`
+	SessionID string `json:"sessionId"`
+	Timestamp string `json:"timestamp"`
+	Message   struct {
+		Role    string          `json:"role"`
+		Content json.RawMessage `json:"content"`
+	} `json:"message"`
+}
+
+type claudeBlock struct {
+	Type      string          `json:"type"`
+	Text      string          `json:"text"`
+	ID        string          `json:"id"`
+	Name      string          `json:"name"`
+	Input     json.RawMessage `json:"input"`
+	ToolUseID string          `json:"tool_use_id"`
+	Content   json.RawMessage `json:"content"`
+}
+
+// loadClaude reads Claude Code sessions from <home>/projects/<encoded root>/.
+func loadClaude(root string) ([]session, error) {
+	project := claudeProjectChars.ReplaceAllString(root, "-")
+	seen := make(map[string]bool)
+	var sessions []session
+	for _, home := range candidateHomes("CLAUDE_CONFIG_DIR", filepath.Join(root, ".claude-config"), ".claude") {
+		paths, err := filepath.Glob(filepath.Join(home, "projects", project, "*.jsonl"))
+		if err != nil {
+			return nil, i18n.Errorf(messages.TranscriptsReadFmt, home, err)
+		}
+		sort.Strings(paths)
+		for _, path := range paths {
+			s, err := parseClaudeSession(path)
+			if err != nil {
+				return nil, err
+			}
+			if seen[s.ID] {
+				continue
+			}
+			seen[s.ID] = true
+			sessions = append(sessions, s)
+		}
+	}
+	return sessions, nil
+}
+
+func parseClaudeSession(path string) (session, error) {
+	file, err := os.Open(path) // #nosec G304 -- path comes from a glob under the client's session directory.
+	if err != nil {
+		return session{}, i18n.Errorf(messages.TranscriptsReadFmt, path, err)
+	}
+	defer func() { _ = file.Close() }()
+
+	s := session{ID: strings.TrimSuffix(filepath.Base(path), ".jsonl")}
+	reader := bufio.NewReader(file)
+	for {
+		raw, readErr := reader.ReadBytes('\n')
+		if len(strings.TrimSpace(string(raw))) > 0 {
+			var line claudeLine
+			if err := json.Unmarshal(raw, &line); err != nil {
+				return session{}, i18n.Errorf(messages.TranscriptsParseFmt, path, err)
+			}
+			if line.SessionID != "" {
+				s.ID = line.SessionID
+			}
+			if line.Type == RoleUser || line.Type == RoleAssistant {
+				s.Records = append(s.Records, claudeRecords(line)...)
+			}
+		}
+		if errors.Is(readErr, io.EOF) {
+			break
+		}
+		if readErr != nil {
+			return session{}, i18n.Errorf(messages.TranscriptsReadFmt, path, readErr)
+		}
+	}
+	for i := range s.Records {
+		s.Records[i].Session = s.ID
+	}
+	return s, nil
+}
+
+// claudeRecords converts one user or assistant line. Thinking blocks are
+// dropped; tool_use and tool_result blocks become tool records.
+func claudeRecords(line claudeLine) []Record {
+	role := line.Message.Role
+	if role == "" {
+		role = line.Type
+	}
+	at := normalizeTime(line.Timestamp)
+	base := Record{Client: ClientClaude, Time: at}
+
+	var text string
+	if err := json.Unmarshal(line.Message.Content, &text); err == nil {
+		if text == "" {
+			return nil
+		}
+		record := base
+		record.Role, record.Kind, record.Text = role, KindMessage, text
+		return []Record{record}
+	}
+	var blocks []claudeBlock
+	if err := json.Unmarshal(line.Message.Content, &blocks); err != nil {
+		return nil
+	}
+	var records []Record
+	for _, block := range blocks {
+		record := base
+		switch block.Type {
+		case "text":
+			if block.Text == "" {
+				continue
+			}
+			record.Role, record.Kind, record.Text = role, KindMessage, block.Text
+		case "tool_use":
+			record.Role, record.Kind, record.Tool, record.Input, record.CallID = RoleAssistant, KindToolCall, block.Name, rawInput(block.Input), block.ID
+		case "tool_result":
+			record.Role, record.Kind, record.Text, record.CallID = RoleTool, KindToolResult, contentText(block.Content), block.ToolUseID
+		default:
+			continue
+		}
+		records = append(records, record)
+	}
+	return records
+}
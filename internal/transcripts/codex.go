@@ -0,0 +1,177 @@
+package transcripts
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/conn-castle/agent-layer/internal/i18n"
+	"github.com/conn-castle/agent-layer/internal/messages"
+)
+
+// codexLine is one rollout line: a typed envelope around a payload. Rollouts
+// written before the envelope existed hold the payload fields at the top
+// level, so both shapes decode into codexItem.
+type codexLine struct {
+	Timestamp string          `json:"timestamp"`
+	Type      string          `json:"type"`
+	Payload   json.RawMessage `json:"payload"`
+}
+
+type codexItem struct {
+	Type      string          `json:"type"`
+	ID        string          `json:"id"`
+	Cwd       string          `json:"cwd"`
+	Role      string          `json:"role"`
+	Content   json.RawMessage `json:"content"`
+	Name      string          `json:"name"`
+	Arguments json.RawMessage `json:"arguments"`
+	Input     json.RawMessage `json:"input"`
+	Action    json.RawMessage `json:"action"`
+	CallID    string          `json:"call_id"`
+	Output    json.RawMessage `json:"output"`
+}
+
+// loadCodex reads Codex rollouts under <home>/sessions/ and keeps those
+// started inside root.
+func loadCodex(root string) ([]session, error) {
+	seen := make(map[string]bool)
+	var sessions []session
+	for _, home := range candidateHomes("CODEX_HOME", filepath.Join(root, ".codex"), ".codex") {
+		dir := filepath.Join(home, "sessions")
+		err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				if errors.Is(err, os.ErrNotExist) {
+					return nil
+				}
+				return err
+			}
+			if d.IsDir() || !strings.HasPrefix(d.Name(), "rollout-") || filepath.Ext(path) != ".jsonl" {
+				return nil
+			}
+			s, cwd, err := parseCodexSession(path)
+			if err != nil {
+				return err
+			}
+			if !within(root, cwd) || seen[s.ID] {
+				return nil
+			}
+			seen[s.ID] = true
+			sessions = append(sessions, s)
+			return nil
+		})
+		if err != nil {
+			return nil, i18n.Errorf(messages.TranscriptsReadFmt, dir, err)
+		}
+	}
+	return sessions, nil
+}
+
+// parseCodexSession returns the session and the working directory recorded
+// in its session metadata.
+func parseCodexSession(path string) (session, string, error) {
+	file, err := os.Open(path) // #nosec G304 -- path comes from a walk of the client's session directory.
+	if err != nil {
+		return session{}, "", err
+	}
+	defer func() { _ = file.Close() }()
+
+	s := session{ID: strings.TrimSuffix(filepath.Base(path), ".jsonl")}
+	var cwd string
+	reader := bufio.NewReader(file)
+	for {
+		raw, readErr := reader.ReadBytes('\n')
+		if len(strings.TrimSpace(string(raw))) > 0 {
+			var line codexLine
+			if err := json.Unmarshal(raw, &line); err != nil {
+				return session{}, "", i18n.Errorf(messages.TranscriptsParseFmt, path, err)
+			}
+			payload := line.Payload
+			if len(payload) == 0 {
+				payload = raw
+			}
+			var item codexItem
+			if err := json.Unmarshal(payload, &item); err != nil {
+				return session{}, "", i18n.Errorf(messages.TranscriptsParseFmt, path, err)
+			}
+			switch line.Type {
+			case "session_meta":
+				if item.ID != "" {
+					s.ID = item.ID
+				}
+				cwd = item.Cwd
+			case "turn_context":
+				if cwd == "" {
+					cwd = item.Cwd
+				}
+			default:
+				if record, ok := codexRecord(item, line.Timestamp); ok {
+					s.Records = append(s.Records, record)
+				}
+			}
+		}
+		if errors.Is(readErr, io.EOF) {
+			break
+		}
+		if readErr != nil {
+			return session{}, "", readErr
+		}
+	}
+	for i := range s.Records {
+		s.Records[i].Session = s.ID
+	}
+	return s, cwd, nil
+}
+
+// codexRecord converts one response item. Developer and system messages and
+// reasoning items are dropped.
+func codexRecord(item codexItem, timestamp string) (Record, bool) {
+	record := Record{Client: ClientCodex, Time: normalizeTime(timestamp), CallID: item.CallID}
+	switch item.Type {
+	case "message":
+		if item.Role != RoleUser && item.Role != RoleAssistant {
+			return Record{}, false
+		}
+		text := contentText(item.Content)
+		if text == "" {
+			return Record{}, false
+		}
+		record.Role, record.Kind, record.Text = item.Role, KindMessage, text
+	case "function_call":
+		record.Role, record.Kind, record.Tool, record.Input = RoleAssistant, KindToolCall, item.Name, rawInput(item.Arguments)
+	case "custom_tool_call":
+		record.Role, record.Kind, record.Tool, record.Input = RoleAssistant, KindToolCall, item.Name, rawInput(item.Input)
+	case "local_shell_call":
+		record.Role, record.Kind, record.Tool, record.Input = RoleAssistant, KindToolCall, "shell", rawInput(item.Action)
+	case "function_call_output", "custom_tool_call_output":
+		record.Role, record.Kind, record.Text = RoleTool, KindToolResult, codexOutput(item.Output)
+	default:
+		return Record{}, false
+	}
+	return record, true
+}
+
+// codexOutput returns a tool output that is either a plain string or an
+// object with an "output" (or "content") field.
+func codexOutput(value json.RawMessage) string {
+	var text string
+	if err := json.Unmarshal(value, &text); err == nil {
+		return text
+	}
+	var wrapped struct {
+		Output  string          `json:"output"`
+		Content json.RawMessage `json:"content"`
+	}
+	if err := json.Unmarshal(value, &wrapped); err != nil {
+		return string(value)
+	}
+	if wrapped.Output != "" {
+		return wrapped.Output
+	}
+	return contentText(wrapped.Content)
+}
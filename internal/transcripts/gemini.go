@@ -0,0 +1,102 @@
+package transcripts
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/conn-castle/agent-layer/internal/i18n"
+	"github.com/conn-castle/agent-layer/internal/messages"
+)
+
+type geminiChat struct {
+	SessionID string          `json:"sessionId"`
+	Messages  []geminiMessage `json:"messages"`
+}
+
+type geminiMessage struct {
+	Timestamp string           `json:"timestamp"`
+	Type      string           `json:"type"`
+	Content   json.RawMessage  `json:"content"`
+	ToolCalls []geminiToolCall `json:"toolCalls"`
+}
+
+type geminiToolCall struct {
+	ID            string          `json:"id"`
+	Name          string          `json:"name"`
+	Args          json.RawMessage `json:"args"`
+	Timestamp     string          `json:"timestamp"`
+	ResultDisplay json.RawMessage `json:"resultDisplay"`
+}
+
+// loadGemini reads Gemini CLI chats from <home>/tmp/<sha256 of root>/chats/.
+func loadGemini(root string) ([]session, error) {
+	sum := sha256.Sum256([]byte(root))
+	seen := make(map[string]bool)
+	var sessions []session
+	for _, home := range candidateHomes("GEMINI_CLI_HOME", filepath.Join(root, ".gemini"), ".gemini") {
+		paths, err := filepath.Glob(filepath.Join(home, "tmp", hex.EncodeToString(sum[:]), "chats", "session-*.json"))
+		if err != nil {
+			return nil, i18n.Errorf(messages.TranscriptsReadFmt, home, err)
+		}
+		sort.Strings(paths)
+		for _, path := range paths {
+			s, err := parseGeminiSession(path)
+			if err != nil {
+				return nil, err
+			}
+			if seen[s.ID] {
+				continue
+			}
+			seen[s.ID] = true
+			sessions = append(sessions, s)
+		}
+	}
+	return sessions, nil
+}
+
+func parseGeminiSession(path string) (session, error) {
+	data, err := os.ReadFile(path) // #nosec G304 -- path comes from a glob under the client's chat directory.
+	if err != nil {
+		return session{}, i18n.Errorf(messages.TranscriptsReadFmt, path, err)
+	}
+	var chat geminiChat
+	if err := json.Unmarshal(data, &chat); err != nil {
+		return session{}, i18n.Errorf(messages.TranscriptsParseFmt, path, err)
+	}
+	s := session{ID: chat.SessionID}
+	if s.ID == "" {
+		s.ID = strings.TrimSuffix(filepath.Base(path), ".json")
+	}
+	for _, message := range chat.Messages {
+		var role string
+		switch message.Type {
+		case "user":
+			role = RoleUser
+		case "gemini":
+			role = RoleAssistant
+		default:
+			// info, warning, and error entries are CLI notices, not turns.
+			continue
+		}
+		at := normalizeTime(message.Timestamp)
+		if text := contentText(message.Content); text != "" {
+			s.Records = append(s.Records, Record{Client: ClientGemini, Session: s.ID, Time: at, Role: role, Kind: KindMessage, Text: text})
+		}
+		for _, call := range message.ToolCalls {
+			callAt := normalizeTime(call.Timestamp)
+			if callAt == "" {
+				callAt = at
+			}
+			s.Records = append(s.Records,
+				Record{Client: ClientGemini, Session: s.ID, Time: callAt, Role: RoleAssistant, Kind: KindToolCall, Tool: call.Name, Input: rawInput(call.Args), CallID: call.ID},
+				Record{Client: ClientGemini, Session: s.ID, Time: callAt, Role: RoleTool, Kind: KindToolResult, Text: contentText(call.ResultDisplay), CallID: call.ID},
+			)
+		}
+	}
+	return s, nil
+}
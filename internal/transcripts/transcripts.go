@@ -0,0 +1,270 @@
+// Package transcripts imports client session logs into
+// .agent-layer/transcripts/ as JSONL in one format shared by every client, so
+// teams can review what agents did (for example, which commands they ran)
+// against the repo's configured allowlists.
+package transcripts
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/conn-castle/agent-layer/internal/clock"
+	"github.com/conn-castle/agent-layer/internal/fsutil"
+	"github.com/conn-castle/agent-layer/internal/i18n"
+	"github.com/conn-castle/agent-layer/internal/layerdir"
+	"github.com/conn-castle/agent-layer/internal/messages"
+)
+
+// Supported clients.
+const (
+	ClientClaude = "claude"
+	ClientCodex  = "codex"
+	ClientGemini = "gemini"
+)
+
+// Record roles.
+const (
+	RoleUser      = "user"
+	RoleAssistant = "assistant"
+	RoleTool      = "tool"
+)
+
+// Record kinds.
+const (
+	KindMessage    = "message"
+	KindToolCall   = "tool_call"
+	KindToolResult = "tool_result"
+)
+
+// Import statuses reported in SessionResult.Status.
+const (
+	StatusWritten   = "written"
+	StatusUnchanged = "unchanged"
+)
+
+// DirName is the transcript directory under .agent-layer/.
+const DirName = "transcripts"
+
+// Clients lists the supported client names in display order.
+var Clients = []string{ClientClaude, ClientCodex, ClientGemini}
+
+// Record is one normalized transcript entry. Tool calls carry the tool name
+// and its raw input; tool results are linked back through CallID.
+type Record struct {
+	Client  string          `json:"client"`
+	Session string          `json:"session"`
+	Time    string          `json:"time,omitempty"`
+	Role    string          `json:"role"`
+	Kind    string          `json:"kind"`
+	Text    string          `json:"text,omitempty"`
+	Tool    string          `json:"tool,omitempty"`
+	Input   json.RawMessage `json:"input,omitempty"`
+	CallID  string          `json:"call_id,omitempty"`
+}
+
+// session is one parsed client session before it is written.
+type session struct {
+	ID      string
+	Records []Record
+}
+
+// Options configures Import.
+type Options struct {
+	// Root is the repo root holding .agent-layer/.
+	Root string
+	// Client is one of Clients.
+	Client string
+}
+
+// SessionResult describes one imported session.
+type SessionResult struct {
+	ID      string
+	Path    string
+	Records int
+	Status  string
+}
+
+// Result is the outcome of Import.
+type Result struct {
+	Client string
+	// Dir is the client's transcript directory.
+	Dir string
+	// Sessions lists imported sessions sorted by ID.
+	Sessions []SessionResult
+}
+
+// homeDir and getenv are replaced in tests so imports never read the
+// developer's real session logs.
+var (
+	homeDir = os.UserHomeDir
+	getenv  = os.Getenv
+)
+
+var unsafeFileChars = regexp.MustCompile(`[^A-Za-z0-9._-]`)
+
+// Dir returns the transcript directory for client under root.
+func Dir(root string, client string) string {
+	return filepath.Join(layerdir.Dir(root), DirName, client)
+}
+
+// Import reads the client's session logs for the repo at opts.Root and writes
+// one JSONL file per session to .agent-layer/transcripts/<client>/. Sessions
+// whose normalized content is already on disk are reported unchanged.
+func Import(opts Options) (*Result, error) {
+	client := strings.ToLower(strings.TrimSpace(opts.Client))
+	var sessions []session
+	var err error
+	switch client {
+	case ClientClaude:
+		sessions, err = loadClaude(opts.Root)
+	case ClientCodex:
+		sessions, err = loadCodex(opts.Root)
+	case ClientGemini:
+		sessions, err = loadGemini(opts.Root)
+	default:
+		return nil, i18n.Errorf(messages.TranscriptsUnknownClientFmt, opts.Client, strings.Join(Clients, ", "))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Result{Client: client, Dir: Dir(opts.Root, client)}
+	sort.Slice(sessions, func(i, j int) bool { return sessions[i].ID < sessions[j].ID })
+	for _, s := range sessions {
+		if len(s.Records) == 0 {
+			continue
+		}
+		path := filepath.Join(result.Dir, unsafeFileChars.ReplaceAllString(s.ID, "_")+".jsonl")
+		status, err := writeSession(path, s.Records)
+		if err != nil {
+			return nil, err
+		}
+		result.Sessions = append(result.Sessions, SessionResult{ID: s.ID, Path: path, Records: len(s.Records), Status: status})
+	}
+	return result, nil
+}
+
+// writeSession writes records as JSONL to path unless the file already holds
+// the same bytes.
+func writeSession(path string, records []Record) (string, error) {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	for _, record := range records {
+		if err := encoder.Encode(record); err != nil {
+			return "", i18n.Errorf(messages.TranscriptsWriteFmt, path, err)
+		}
+	}
+	existing, err := os.ReadFile(path)
+	if err == nil && bytes.Equal(existing, buf.Bytes()) {
+		return StatusUnchanged, nil
+	}
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return "", i18n.Errorf(messages.TranscriptsWriteFmt, path, err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return "", i18n.Errorf(messages.TranscriptsWriteFmt, path, err)
+	}
+	// Transcripts can quote secrets from tool output, so keep them private.
+	if err := fsutil.WriteFileAtomic(path, buf.Bytes(), 0o600); err != nil {
+		return "", i18n.Errorf(messages.TranscriptsWriteFmt, path, err)
+	}
+	return StatusWritten, nil
+}
+
+// candidateHomes returns the existing client home directories to search, in
+// priority order and without duplicates: the env override, the repo-local
+// directory Agent Layer points the client at, and the user default.
+func candidateHomes(envVar string, repoLocal string, userDir string) []string {
+	var candidates []string
+	if value := strings.TrimSpace(getenv(envVar)); value != "" {
+		candidates = append(candidates, value)
+	}
+	candidates = append(candidates, repoLocal)
+	if home, err := homeDir(); err == nil {
+		candidates = append(candidates, filepath.Join(home, userDir))
+	}
+	var homes []string
+	seen := make(map[string]bool)
+	for _, candidate := range candidates {
+		cleaned := filepath.Clean(candidate)
+		if seen[cleaned] {
+			continue
+		}
+		seen[cleaned] = true
+		if info, err := os.Stat(cleaned); err == nil && info.IsDir() {
+			homes = append(homes, cleaned)
+		}
+	}
+	return homes
+}
+
+// within reports whether dir is root or a directory below it.
+func within(root string, dir string) bool {
+	if dir == "" {
+		return false
+	}
+	rel, err := filepath.Rel(filepath.Clean(root), filepath.Clean(dir))
+	if err != nil {
+		return false
+	}
+	return rel == "." || (rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)))
+}
+
+// normalizeTime renders a client timestamp in the canonical UTC layout, or
+// returns "" when it cannot be parsed.
+func normalizeTime(raw string) string {
+	if strings.TrimSpace(raw) == "" {
+		return ""
+	}
+	parsed, err := clock.Parse(raw)
+	if err != nil {
+		return ""
+	}
+	return clock.Format(parsed)
+}
+
+// rawInput returns value as JSON for Record.Input. String values that hold
+// JSON (as Codex encodes function arguments) are unwrapped.
+func rawInput(value json.RawMessage) json.RawMessage {
+	trimmed := bytes.TrimSpace(value)
+	if len(trimmed) == 0 || bytes.Equal(trimmed, []byte("null")) {
+		return nil
+	}
+	var text string
+	if err := json.Unmarshal(trimmed, &text); err == nil {
+		if json.Valid([]byte(text)) {
+			return json.RawMessage(text)
+		}
+	}
+	return json.RawMessage(trimmed)
+}
+
+// contentText flattens message content that is either a string or a list of
+// blocks with a "text" field. Blocks of other types are skipped.
+func contentText(value json.RawMessage) string {
+	var text string
+	if err := json.Unmarshal(value, &text); err == nil {
+		return text
+	}
+	var blocks []struct {
+		Type string `json:"type"`
+		Text string `json:"text"`
+	}
+	if err := json.Unmarshal(value, &blocks); err != nil {
+		return ""
+	}
+	var parts []string
+	for _, block := range blocks {
+		if block.Text != "" {
+			parts = append(parts, block.Text)
+		}
+	}
+	return strings.Join(parts, "\n")
+}
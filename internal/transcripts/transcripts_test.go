@@ -0,0 +1,213 @@
+package transcripts
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// isolateHomes points every client home lookup at empty temp directories.
+func isolateHomes(t *testing.T) string {
+	t.Helper()
+	home := t.TempDir()
+	origHome, origGetenv := homeDir, getenv
+	homeDir = func() (string, error) { return home, nil }
+	getenv = func(string) string { return "" }
+	t.Cleanup(func() { homeDir, getenv = origHome, origGetenv })
+	return home
+}
+
+func writeFile(t *testing.T, path string, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+}
+
+func readRecords(t *testing.T, path string) []Record {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read transcript: %v", err)
+	}
+	var records []Record
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		var record Record
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			t.Fatalf("decode %q: %v", line, err)
+		}
+		records = append(records, record)
+	}
+	return records
+}
+
+func TestImport_Claude(t *testing.T) {
+	home := isolateHomes(t)
+	root := filepath.Join(t.TempDir(), "my.repo")
+	project := claudeProjectChars.ReplaceAllString(root, "-")
+	writeFile(t, filepath.Join(home, ".claude", "projects", project, "abc.jsonl"), strings.Join([]string{
+		`{"type":"summary","summary":"ignored"}`,
+		`{"type":"user","sessionId":"s-1","timestamp":"2026-01-02T03:04:05.678Z","message":{"role":"user","content":"run the tests"}}`,
+		`{"type":"assistant","sessionId":"s-1","timestamp":"2026-01-02T03:04:06Z","message":{"role":"assistant","content":[{"type":"thinking","thinking":"hmm"},{"type":"text","text":"Running."},{"type":"tool_use","id":"t1","name":"Bash","input":{"command":"go test ./..."}}]}}`,
+		`{"type":"user","sessionId":"s-1","timestamp":"2026-01-02T03:04:07Z","message":{"role":"user","content":[{"type":"tool_result","tool_use_id":"t1","content":[{"type":"text","text":"ok"}]}]}}`,
+	}, "\n")+"\n")
+
+	result, err := Import(Options{Root: root, Client: "Claude"})
+	if err != nil {
+		t.Fatalf("import: %v", err)
+	}
+	if len(result.Sessions) != 1 || result.Sessions[0].ID != "s-1" || result.Sessions[0].Status != StatusWritten {
+		t.Fatalf("unexpected sessions %#v", result.Sessions)
+	}
+	records := readRecords(t, filepath.Join(root, ".agent-layer", "transcripts", "claude", "s-1.jsonl"))
+	if len(records) != 4 {
+		t.Fatalf("expected 4 records, got %#v", records)
+	}
+	if records[0].Role != RoleUser || records[0].Text != "run the tests" || records[0].Time != "2026-01-02T03:04:05Z" {
+		t.Fatalf("unexpected user record %#v", records[0])
+	}
+	call := records[2]
+	if call.Kind != KindToolCall || call.Tool != "Bash" || call.CallID != "t1" || string(call.Input) != `{"command":"go test ./..."}` {
+		t.Fatalf("unexpected tool call %#v", call)
+	}
+	if records[3].Kind != KindToolResult || records[3].Role != RoleTool || records[3].Text != "ok" || records[3].CallID != "t1" {
+		t.Fatalf("unexpected tool result %#v", records[3])
+	}
+
+	again, err := Import(Options{Root: root, Client: ClientClaude})
+	if err != nil {
+		t.Fatalf("reimport: %v", err)
+	}
+	if again.Sessions[0].Status != StatusUnchanged {
+		t.Fatalf("expected unchanged session on reimport, got %s", again.Sessions[0].Status)
+	}
+}
+
+func TestImport_CodexFiltersByCwd(t *testing.T) {
+	home := isolateHomes(t)
+	root := t.TempDir()
+	sessions := filepath.Join(home, ".codex", "sessions", "2026", "01", "02")
+	writeFile(t, filepath.Join(sessions, "rollout-mine.jsonl"), strings.Join([]string{
+		`{"timestamp":"2026-01-02T03:04:05Z","type":"session_meta","payload":{"id":"codex-1","cwd":"` + filepath.ToSlash(filepath.Join(root, "sub")) + `"}}`,
+		`{"timestamp":"2026-01-02T03:04:05Z","type":"response_item","payload":{"type":"message","role":"developer","content":[{"type":"input_text","text":"system prompt"}]}}`,
+		`{"timestamp":"2026-01-02T03:04:06Z","type":"response_item","payload":{"type":"message","role":"user","content":[{"type":"input_text","text":"list files"}]}}`,
+		`{"timestamp":"2026-01-02T03:04:07Z","type":"response_item","payload":{"type":"reasoning","summary":[]}}`,
+		`{"timestamp":"2026-01-02T03:04:08Z","type":"response_item","payload":{"type":"function_call","name":"shell","arguments":"{\"command\":[\"ls\"]}","call_id":"c1"}}`,
+		`{"timestamp":"2026-01-02T03:04:09Z","type":"response_item","payload":{"type":"function_call_output","call_id":"c1","output":"{\"output\":\"README.md\"}"}}`,
+		`{"timestamp":"2026-01-02T03:04:10Z","type":"event_msg","payload":{"type":"token_count"}}`,
+	}, "\n")+"\n")
+	writeFile(t, filepath.Join(sessions, "rollout-other.jsonl"),
+		`{"timestamp":"2026-01-02T03:04:05Z","type":"session_meta","payload":{"id":"codex-2","cwd":"/elsewhere"}}`+"\n"+
+			`{"timestamp":"2026-01-02T03:04:06Z","type":"response_item","payload":{"type":"message","role":"user","content":[{"type":"input_text","text":"hi"}]}}`+"\n")
+
+	result, err := Import(Options{Root: root, Client: ClientCodex})
+	if err != nil {
+		t.Fatalf("import: %v", err)
+	}
+	if len(result.Sessions) != 1 || result.Sessions[0].ID != "codex-1" {
+		t.Fatalf("expected only the session started in the repo, got %#v", result.Sessions)
+	}
+	records := readRecords(t, result.Sessions[0].Path)
+	if len(records) != 3 {
+		t.Fatalf("expected 3 records, got %#v", records)
+	}
+	if records[1].Tool != "shell" || string(records[1].Input) != `{"command":["ls"]}` {
+		t.Fatalf("expected decoded function arguments, got %#v", records[1])
+	}
+	if records[2].Text != `{"output":"README.md"}` {
+		t.Fatalf("unexpected tool output %#v", records[2])
+	}
+}
+
+func TestImport_Gemini(t *testing.T) {
+	home := isolateHomes(t)
+	root := t.TempDir()
+	sum := sha256.Sum256([]byte(root))
+	writeFile(t, filepath.Join(home, ".gemini", "tmp", hex.EncodeToString(sum[:]), "chats", "session-2026.json"), `{
+  "sessionId": "g-1",
+  "messages": [
+    {"type": "user", "timestamp": "2026-01-02T03:04:05Z", "content": "read the file"},
+    {"type": "info", "timestamp": "2026-01-02T03:04:05Z", "content": "notice"},
+    {"type": "gemini", "timestamp": "2026-01-02T03:04:06Z", "content": "Reading.",
+     "toolCalls": [{"id": "r1", "name": "read_file", "args": {"path": "a.txt"}, "resultDisplay": "hello"}]}
+  ]
+}`)
+
+	result, err := Import(Options{Root: root, Client: ClientGemini})
+	if err != nil {
+		t.Fatalf("import: %v", err)
+	}
+	if len(result.Sessions) != 1 || result.Sessions[0].Records != 4 {
+		t.Fatalf("unexpected sessions %#v", result.Sessions)
+	}
+	records := readRecords(t, result.Sessions[0].Path)
+	if records[1].Role != RoleAssistant || records[2].Tool != "read_file" || records[2].Time != "2026-01-02T03:04:06Z" || records[3].Text != "hello" {
+		t.Fatalf("unexpected records %#v", records)
+	}
+}
+
+func TestImport_EnvOverrideAndNoSessions(t *testing.T) {
+	isolateHomes(t)
+	root := t.TempDir()
+	custom := t.TempDir()
+	getenv = func(key string) string {
+		if key == "CODEX_HOME" {
+			return custom
+		}
+		return ""
+	}
+	result, err := Import(Options{Root: root, Client: ClientCodex})
+	if err != nil {
+		t.Fatalf("import: %v", err)
+	}
+	if len(result.Sessions) != 0 {
+		t.Fatalf("expected no sessions, got %#v", result.Sessions)
+	}
+
+	writeFile(t, filepath.Join(custom, "sessions", "rollout-x.jsonl"),
+		`{"type":"session_meta","payload":{"id":"x","cwd":"`+filepath.ToSlash(root)+`"}}`+"\n"+
+			`{"type":"response_item","payload":{"type":"message","role":"assistant","content":[{"type":"output_text","text":"done"}]}}`)
+	result, err = Import(Options{Root: root, Client: ClientCodex})
+	if err != nil {
+		t.Fatalf("import: %v", err)
+	}
+	if len(result.Sessions) != 1 || result.Sessions[0].ID != "x" {
+		t.Fatalf("expected session from CODEX_HOME, got %#v", result.Sessions)
+	}
+}
+
+func TestImport_Errors(t *testing.T) {
+	home := isolateHomes(t)
+	root := t.TempDir()
+	if _, err := Import(Options{Root: root, Client: "cursor"}); err == nil || !strings.Contains(err.Error(), "supported: claude, codex, gemini") {
+		t.Fatalf("expected unknown client error, got %v", err)
+	}
+	project := claudeProjectChars.ReplaceAllString(root, "-")
+	writeFile(t, filepath.Join(home, ".claude", "projects", project, "bad.jsonl"), "{not json\n")
+	if _, err := Import(Options{Root: root, Client: ClientClaude}); err == nil || !strings.Contains(err.Error(), "failed to parse session log") {
+		t.Fatalf("expected parse error, got %v", err)
+	}
+}
+
+func TestWithin(t *testing.T) {
+	root := filepath.Join(string(filepath.Separator), "repo")
+	cases := map[string]bool{
+		root:                           true,
+		filepath.Join(root, "sub"):     true,
+		filepath.Join(root, "..", "x"): false,
+		filepath.Join(root+"2", "sub"): false,
+		"":                             false,
+	}
+	for dir, want := range cases {
+		if got := within(root, dir); got != want {
+			t.Fatalf("within(%q, %q) = %v, want %v", root, dir, got, want)
+		}
+	}
+}
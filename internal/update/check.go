@@ -7,11 +7,14 @@ import (
 	"fmt"
 	"net"
 	"net/http"
+	"os"
 	"strconv"
 	"strings"
 	"time"
 
+	"github.com/conn-castle/agent-layer/internal/i18n"
 	"github.com/conn-castle/agent-layer/internal/messages"
+	"github.com/conn-castle/agent-layer/internal/offline"
 	"github.com/conn-castle/agent-layer/internal/version"
 )
 
@@ -71,6 +74,9 @@ func Check(ctx context.Context, currentVersion string) (CheckResult, error) {
 		return CheckResult{}, err
 	}
 
+	if err := offline.Check(os.Getenv, messages.OfflineOpUpdateCheck); err != nil {
+		return CheckResult{}, err
+	}
 	latest, err := fetchLatestReleaseVersion(ctx)
 	if err != nil {
 		return CheckResult{}, err
@@ -100,7 +106,7 @@ func fetchLatestReleaseVersion(ctx context.Context) (string, error) {
 	for attempt := 0; attempt <= fetchLatestRetryCount; attempt++ {
 		req, err := http.NewRequestWithContext(ctx, http.MethodGet, latestReleaseURL, nil)
 		if err != nil {
-			return "", fmt.Errorf(messages.UpdateCreateRequestErrFmt, err)
+			return "", i18n.Errorf(messages.UpdateCreateRequestErrFmt, err)
 		}
 		req.Header.Set("Accept", "application/vnd.github+json")
 		req.Header.Set("User-Agent", "agent-layer")
@@ -111,7 +117,7 @@ func fetchLatestReleaseVersion(ctx context.Context) (string, error) {
 				updateSleep(retryDelay)
 				continue
 			}
-			return "", fmt.Errorf(messages.UpdateFetchLatestReleaseErrFmt, err)
+			return "", i18n.Errorf(messages.UpdateFetchLatestReleaseErrFmt, err)
 		}
 
 		if resp.StatusCode != http.StatusOK {
@@ -126,26 +132,26 @@ func fetchLatestReleaseVersion(ctx context.Context) (string, error) {
 				updateSleep(retryDelay)
 				continue
 			}
-			return "", fmt.Errorf(messages.UpdateFetchLatestReleaseStatusFmt, statusText)
+			return "", i18n.Errorf(messages.UpdateFetchLatestReleaseStatusFmt, statusText)
 		}
 
 		var payload latestReleaseResponse
 		if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
 			_ = resp.Body.Close()
-			return "", fmt.Errorf(messages.UpdateDecodeLatestReleaseErrFmt, err)
+			return "", i18n.Errorf(messages.UpdateDecodeLatestReleaseErrFmt, err)
 		}
 		_ = resp.Body.Close()
 		if strings.TrimSpace(payload.TagName) == "" {
-			return "", fmt.Errorf(messages.UpdateLatestReleaseMissingTag)
+			return "", i18n.Errorf(messages.UpdateLatestReleaseMissingTag)
 		}
 		normalized, err := version.Normalize(payload.TagName)
 		if err != nil {
-			return "", fmt.Errorf(messages.UpdateInvalidLatestReleaseTagFmt, payload.TagName, err)
+			return "", i18n.Errorf(messages.UpdateInvalidLatestReleaseTagFmt, payload.TagName, err)
 		}
 		return normalized, nil
 	}
 
-	return "", fmt.Errorf(messages.UpdateFetchLatestReleaseErrFmt, errors.New("retry budget exhausted"))
+	return "", i18n.Errorf(messages.UpdateFetchLatestReleaseErrFmt, errors.New("retry budget exhausted"))
 }
 
 func rateLimitErrorFromResponse(resp *http.Response) *RateLimitError {
@@ -193,7 +199,7 @@ func normalizeCurrentVersion(raw string) (string, bool, error) {
 	}
 	normalized, err := version.Normalize(raw)
 	if err != nil {
-		return "", false, fmt.Errorf(messages.UpdateInvalidCurrentVersionFmt, raw, err)
+		return "", false, i18n.Errorf(messages.UpdateInvalidCurrentVersionFmt, raw, err)
 	}
 	return normalized, false, nil
 }
@@ -10,6 +10,8 @@ import (
 	"strings"
 	"testing"
 	"time"
+
+	"github.com/conn-castle/agent-layer/internal/offline"
 )
 
 type roundTripperFunc func(*http.Request) (*http.Response, error)
@@ -132,6 +134,18 @@ func TestCheckNewerThanLatest(t *testing.T) {
 	}
 }
 
+func TestCheckOffline(t *testing.T) {
+	t.Setenv(offline.EnvVar, "1")
+	withLatestReleaseClient(t, "http://example.invalid", &http.Client{Transport: roundTripperFunc(func(*http.Request) (*http.Response, error) {
+		t.Fatal("offline check must not send a request")
+		return nil, nil
+	})})
+
+	if _, err := Check(context.Background(), "1.0.0"); !errors.Is(err, offline.ErrOffline) {
+		t.Fatalf("expected offline error, got %v", err)
+	}
+}
+
 func TestCheckNilContext(t *testing.T) {
 	withLatestReleaseServer(t, func(w http.ResponseWriter, _ *http.Request) {
 		w.Header().Set("Content-Type", "application/json")
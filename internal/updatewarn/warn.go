@@ -9,6 +9,7 @@ import (
 	"github.com/fatih/color"
 
 	"github.com/conn-castle/agent-layer/internal/messages"
+	"github.com/conn-castle/agent-layer/internal/offline"
 	"github.com/conn-castle/agent-layer/internal/update"
 	"github.com/conn-castle/agent-layer/internal/versiondispatch"
 )
@@ -22,7 +23,7 @@ func WarnIfOutdated(ctx context.Context, currentVersion string, stderr io.Writer
 	if strings.TrimSpace(os.Getenv(versiondispatch.EnvVersionOverride)) != "" {
 		return
 	}
-	if strings.TrimSpace(os.Getenv(versiondispatch.EnvNoNetwork)) != "" {
+	if strings.TrimSpace(os.Getenv(versiondispatch.EnvNoNetwork)) != "" || offline.Enabled(os.Getenv) {
 		return
 	}
 	if stderr == nil {
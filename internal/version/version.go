@@ -6,6 +6,7 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/conn-castle/agent-layer/internal/i18n"
 	"github.com/conn-castle/agent-layer/internal/messages"
 )
 
@@ -16,11 +17,11 @@ var semverPattern = regexp.MustCompile(`^v?(\d+)\.(\d+)\.(\d+)$`)
 func Normalize(raw string) (string, error) {
 	trimmed := strings.TrimSpace(raw)
 	if trimmed == "" {
-		return "", fmt.Errorf(messages.VersionRequired)
+		return "", i18n.Errorf(messages.VersionRequired)
 	}
 	match := semverPattern.FindStringSubmatch(trimmed)
 	if match == nil {
-		return "", fmt.Errorf(messages.VersionInvalidFmt, raw)
+		return "", i18n.Errorf(messages.VersionInvalidFmt, raw)
 	}
 	return fmt.Sprintf("%s.%s.%s", match[1], match[2], match[3]), nil
 }
@@ -65,13 +66,13 @@ func Parse(raw string) ([3]int, error) {
 	}
 	parts := strings.Split(normalized, ".")
 	if len(parts) != 3 {
-		return [3]int{}, fmt.Errorf(messages.UpdateInvalidVersionFmt, raw)
+		return [3]int{}, i18n.Errorf(messages.UpdateInvalidVersionFmt, raw)
 	}
 	var out [3]int
 	for idx, part := range parts {
 		value, atoiErr := strconv.Atoi(part)
 		if atoiErr != nil {
-			return [3]int{}, fmt.Errorf(messages.UpdateInvalidVersionSegmentFmt, part, atoiErr)
+			return [3]int{}, i18n.Errorf(messages.UpdateInvalidVersionSegmentFmt, part, atoiErr)
 		}
 		out[idx] = value
 	}
@@ -16,7 +16,9 @@ import (
 	"strings"
 	"time"
 
+	"github.com/conn-castle/agent-layer/internal/i18n"
 	"github.com/conn-castle/agent-layer/internal/messages"
+	"github.com/conn-castle/agent-layer/internal/offline"
 	"github.com/conn-castle/agent-layer/internal/update"
 )
 
@@ -39,39 +41,41 @@ func ensureCachedBinary(cacheRoot string, version string, progressOut io.Writer)
 
 func ensureCachedBinaryWithSystem(sys System, cacheRoot string, version string, progressOut io.Writer) (string, error) {
 	if sys == nil {
-		return "", fmt.Errorf(messages.DispatchSystemRequired)
+		return "", i18n.Errorf(messages.DispatchSystemRequired)
 	}
-	osName, arch, err := sys.PlatformStrings()
+	binPath, err := cachedBinaryPath(sys, cacheRoot, version)
 	if err != nil {
 		return "", err
 	}
-	asset := assetName(osName, arch)
-	binPath := filepath.Join(cacheRoot, "versions", version, osName+"-"+arch, asset)
+	asset := filepath.Base(binPath)
 	if _, err := sys.Stat(binPath); err == nil {
 		return binPath, nil
 	} else if err != nil && !os.IsNotExist(err) {
-		return "", fmt.Errorf(messages.DispatchCheckCachedBinaryFmt, binPath, err)
+		return "", i18n.Errorf(messages.DispatchCheckCachedBinaryFmt, binPath, err)
 	}
 
+	if err := offline.Check(sys.Getenv, i18n.Sprintf(messages.OfflineOpDownloadFmt, version, binPath)); err != nil {
+		return "", err
+	}
 	if noNetworkWithSystem(sys) {
-		return "", fmt.Errorf(messages.DispatchVersionNotCachedFmt, version, binPath, EnvNoNetwork)
+		return "", i18n.Errorf(messages.DispatchVersionNotCachedFmt, version, binPath, EnvNoNetwork)
 	}
 
 	lockPath := binPath + ".lock"
 	if err := os.MkdirAll(filepath.Dir(binPath), 0o755); err != nil { // #nosec G301 -- user-level cache dir (~/.cache/agent-layer/...) must be traversable by the user's shell to invoke the cached 0o755 binary; the lock file is created separately.
-		return "", fmt.Errorf(messages.DispatchCreateCacheDirFmt, err)
+		return "", i18n.Errorf(messages.DispatchCreateCacheDirFmt, err)
 	}
 
 	if err := withFileLock(sys, lockPath, cacheLockWaitTimeoutWithSystem(sys), func() error {
 		if _, err := sys.Stat(binPath); err == nil {
 			return nil
 		} else if err != nil && !os.IsNotExist(err) {
-			return fmt.Errorf(messages.DispatchCheckCachedBinaryFmt, binPath, err)
+			return i18n.Errorf(messages.DispatchCheckCachedBinaryFmt, binPath, err)
 		}
 
 		tmp, err := sys.CreateTemp(filepath.Dir(binPath), asset+".tmp-*")
 		if err != nil {
-			return fmt.Errorf(messages.DispatchCreateTempFileFmt, err)
+			return i18n.Errorf(messages.DispatchCreateTempFileFmt, err)
 		}
 		tmpName := tmp.Name()
 		committed := false
@@ -81,7 +85,7 @@ func ensureCachedBinaryWithSystem(sys System, cacheRoot string, version string,
 			}
 		}()
 
-		_, _ = fmt.Fprintf(progressOut, messages.DispatchDownloadingFmt, version)
+		_, _ = fmt.Fprintf(progressOut, i18n.T(messages.DispatchDownloadingFmt), version)
 		url := fmt.Sprintf("%s/download/v%s/%s", releaseBaseURL, version, asset)
 		if err := downloadToFileWithSystem(sys, url, tmp); err != nil {
 			_ = tmp.Close()
@@ -89,10 +93,10 @@ func ensureCachedBinaryWithSystem(sys System, cacheRoot string, version string,
 		}
 		if err := sys.FileSync(tmp); err != nil {
 			_ = tmp.Close()
-			return fmt.Errorf(messages.DispatchSyncTempFileFmt, err)
+			return i18n.Errorf(messages.DispatchSyncTempFileFmt, err)
 		}
 		if err := tmp.Close(); err != nil {
-			return fmt.Errorf(messages.DispatchCloseTempFileFmt, err)
+			return i18n.Errorf(messages.DispatchCloseTempFileFmt, err)
 		}
 
 		expected, err := fetchChecksumWithSystem(sys, version, asset)
@@ -103,14 +107,14 @@ func ensureCachedBinaryWithSystem(sys System, cacheRoot string, version string,
 			return err
 		}
 		if err := sys.Chmod(tmpName, 0o755); err != nil {
-			return fmt.Errorf(messages.DispatchChmodCachedBinaryFmt, err)
+			return i18n.Errorf(messages.DispatchChmodCachedBinaryFmt, err)
 		}
 
 		if err := sys.Rename(tmpName, binPath); err != nil {
-			return fmt.Errorf(messages.DispatchMoveCachedBinaryFmt, err)
+			return i18n.Errorf(messages.DispatchMoveCachedBinaryFmt, err)
 		}
 		committed = true
-		_, _ = fmt.Fprintf(progressOut, messages.DispatchDownloadedFmt, version)
+		_, _ = fmt.Fprintf(progressOut, i18n.T(messages.DispatchDownloadedFmt), version)
 		return nil
 	}); err != nil {
 		return "", err
@@ -120,6 +124,15 @@ func ensureCachedBinaryWithSystem(sys System, cacheRoot string, version string,
 }
 
 // platformStrings returns the supported OS and architecture strings for release assets.
+// cachedBinaryPath returns where the binary for version lives under cacheRoot.
+func cachedBinaryPath(sys System, cacheRoot string, version string) (string, error) {
+	osName, arch, err := sys.PlatformStrings()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(cacheRoot, "versions", version, osName+"-"+arch, assetName(osName, arch)), nil
+}
+
 func platformStrings() (string, string, error) {
 	return checkPlatform(runtime.GOOS, runtime.GOARCH)
 }
@@ -128,13 +141,13 @@ func checkPlatform(osName, arch string) (string, string, error) {
 	switch osName {
 	case osDarwin, osLinux:
 	default:
-		return "", "", fmt.Errorf(messages.DispatchUnsupportedOSFmt, osName)
+		return "", "", i18n.Errorf(messages.DispatchUnsupportedOSFmt, osName)
 	}
 
 	switch arch {
 	case archAMD64, archARM64:
 	default:
-		return "", "", fmt.Errorf(messages.DispatchUnsupportedArchFmt, arch)
+		return "", "", i18n.Errorf(messages.DispatchUnsupportedArchFmt, arch)
 	}
 
 	return osName, arch, nil
@@ -157,14 +170,14 @@ func downloadToFile(url string, dest *os.File) error {
 
 func downloadToFileWithSystem(sys System, url string, dest *os.File) error {
 	if sys == nil {
-		return fmt.Errorf(messages.DispatchSystemRequired)
+		return i18n.Errorf(messages.DispatchSystemRequired)
 	}
 	client := downloadHTTPClientWithSystem(sys)
 	maxBytes := maxDownloadBytesWithSystem(sys)
 	for attempt := 0; attempt <= downloadRetryCount; attempt++ {
 		req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, url, nil)
 		if err != nil {
-			return fmt.Errorf(messages.DispatchDownloadFailedFmt, url, err)
+			return i18n.Errorf(messages.DispatchDownloadFailedFmt, url, err)
 		}
 		resp, err := client.Do(req) // #nosec G704 -- callers construct URLs from the fixed release base URL and validated release asset names.
 		if err != nil {
@@ -173,14 +186,14 @@ func downloadToFileWithSystem(sys System, url string, dest *os.File) error {
 				continue
 			}
 			if isTimeoutError(err) {
-				return fmt.Errorf(messages.DispatchDownloadTimeoutFmt, url)
+				return i18n.Errorf(messages.DispatchDownloadTimeoutFmt, url)
 			}
-			return fmt.Errorf(messages.DispatchDownloadFailedFmt, url, err)
+			return i18n.Errorf(messages.DispatchDownloadFailedFmt, url, err)
 		}
 
 		if resp.StatusCode == http.StatusNotFound {
 			_ = resp.Body.Close()
-			return fmt.Errorf(messages.DispatchDownload404Fmt, url, releaseBaseURL)
+			return i18n.Errorf(messages.DispatchDownload404Fmt, url, releaseBaseURL)
 		}
 		if resp.StatusCode != http.StatusOK {
 			status := resp.StatusCode
@@ -190,16 +203,16 @@ func downloadToFileWithSystem(sys System, url string, dest *os.File) error {
 				sys.Sleep(downloadRetryBackoff)
 				continue
 			}
-			return fmt.Errorf(messages.DispatchDownloadUnexpectedStatusFmt, url, statusText)
+			return i18n.Errorf(messages.DispatchDownloadUnexpectedStatusFmt, url, statusText)
 		}
 
 		if err := dest.Truncate(0); err != nil {
 			_ = resp.Body.Close()
-			return fmt.Errorf(messages.DispatchTruncateTempFileFmt, err)
+			return i18n.Errorf(messages.DispatchTruncateTempFileFmt, err)
 		}
 		if _, err := dest.Seek(0, io.SeekStart); err != nil {
 			_ = resp.Body.Close()
-			return fmt.Errorf(messages.DispatchResetTempFileOffsetFmt, err)
+			return i18n.Errorf(messages.DispatchResetTempFileOffsetFmt, err)
 		}
 
 		n, copyErr := io.Copy(dest, io.LimitReader(resp.Body, maxBytes+1))
@@ -209,14 +222,14 @@ func downloadToFileWithSystem(sys System, url string, dest *os.File) error {
 				sys.Sleep(downloadRetryBackoff)
 				continue
 			}
-			return fmt.Errorf(messages.DispatchDownloadFailedFmt, url, copyErr)
+			return i18n.Errorf(messages.DispatchDownloadFailedFmt, url, copyErr)
 		}
 		if n > maxBytes {
-			return fmt.Errorf(messages.DispatchDownloadTooLargeFmt, url, n, maxBytes)
+			return i18n.Errorf(messages.DispatchDownloadTooLargeFmt, url, n, maxBytes)
 		}
 		return nil
 	}
-	return fmt.Errorf(messages.DispatchDownloadFailedFmt, url, errors.New("retry budget exhausted"))
+	return i18n.Errorf(messages.DispatchDownloadFailedFmt, url, errors.New("retry budget exhausted"))
 }
 
 // fetchChecksum retrieves the expected checksum for the asset from checksums.txt.
@@ -227,14 +240,14 @@ func fetchChecksum(version string, asset string) (string, error) {
 // fetchChecksumWithSystem retrieves the expected checksum using the provided system for timeout/env resolution.
 func fetchChecksumWithSystem(sys System, version string, asset string) (string, error) {
 	if sys == nil {
-		return "", fmt.Errorf(messages.DispatchSystemRequired)
+		return "", i18n.Errorf(messages.DispatchSystemRequired)
 	}
 	url := fmt.Sprintf("%s/download/v%s/checksums.txt", releaseBaseURL, version)
 	client := downloadHTTPClientWithSystem(sys)
 	for attempt := 0; attempt <= downloadRetryCount; attempt++ {
 		req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, url, nil)
 		if err != nil {
-			return "", fmt.Errorf(messages.DispatchDownloadFailedFmt, url, err)
+			return "", i18n.Errorf(messages.DispatchDownloadFailedFmt, url, err)
 		}
 		resp, err := client.Do(req) // #nosec G704 -- URL uses the fixed release base URL and a validated semantic version.
 		if err != nil {
@@ -243,13 +256,13 @@ func fetchChecksumWithSystem(sys System, version string, asset string) (string,
 				continue
 			}
 			if isTimeoutError(err) {
-				return "", fmt.Errorf(messages.DispatchDownloadTimeoutFmt, url)
+				return "", i18n.Errorf(messages.DispatchDownloadTimeoutFmt, url)
 			}
-			return "", fmt.Errorf(messages.DispatchDownloadFailedFmt, url, err)
+			return "", i18n.Errorf(messages.DispatchDownloadFailedFmt, url, err)
 		}
 		if resp.StatusCode == http.StatusNotFound {
 			_ = resp.Body.Close()
-			return "", fmt.Errorf(messages.DispatchDownload404Fmt, url, releaseBaseURL)
+			return "", i18n.Errorf(messages.DispatchDownload404Fmt, url, releaseBaseURL)
 		}
 		if resp.StatusCode != http.StatusOK {
 			status := resp.StatusCode
@@ -259,7 +272,7 @@ func fetchChecksumWithSystem(sys System, version string, asset string) (string,
 				sys.Sleep(downloadRetryBackoff)
 				continue
 			}
-			return "", fmt.Errorf(messages.DispatchDownloadUnexpectedStatusFmt, url, statusText)
+			return "", i18n.Errorf(messages.DispatchDownloadUnexpectedStatusFmt, url, statusText)
 		}
 
 		scanner := bufio.NewScanner(io.LimitReader(resp.Body, maxChecksumResponseBytes))
@@ -285,12 +298,12 @@ func fetchChecksumWithSystem(sys System, version string, asset string) (string,
 				sys.Sleep(downloadRetryBackoff)
 				continue
 			}
-			return "", fmt.Errorf(messages.DispatchReadFailedFmt, url, err)
+			return "", i18n.Errorf(messages.DispatchReadFailedFmt, url, err)
 		}
 		_ = resp.Body.Close()
-		return "", fmt.Errorf(messages.DispatchChecksumNotFoundFmt, asset, url)
+		return "", i18n.Errorf(messages.DispatchChecksumNotFoundFmt, asset, url)
 	}
-	return "", fmt.Errorf(messages.DispatchDownloadFailedFmt, url, errors.New("retry budget exhausted"))
+	return "", i18n.Errorf(messages.DispatchDownloadFailedFmt, url, errors.New("retry budget exhausted"))
 }
 
 // isTimeoutError reports whether err is a network timeout.
@@ -368,17 +381,17 @@ func downloadHTTPClientWithSystem(sys System) *http.Client {
 func verifyChecksum(path string, expected string) error {
 	file, err := os.Open(path) //nolint:gosec // path is an internally-resolved cache file
 	if err != nil {
-		return fmt.Errorf(messages.DispatchOpenFileFmt, path, err)
+		return i18n.Errorf(messages.DispatchOpenFileFmt, path, err)
 	}
 	defer func() { _ = file.Close() }()
 
 	hasher := sha256.New()
 	if _, err := io.Copy(hasher, file); err != nil {
-		return fmt.Errorf(messages.DispatchHashFileFmt, path, err)
+		return i18n.Errorf(messages.DispatchHashFileFmt, path, err)
 	}
 	actual := fmt.Sprintf("%x", hasher.Sum(nil))
 	if actual != expected {
-		return fmt.Errorf(messages.DispatchChecksumMismatchFmt, path, expected, actual)
+		return i18n.Errorf(messages.DispatchChecksumMismatchFmt, path, expected, actual)
 	}
 	return nil
 }
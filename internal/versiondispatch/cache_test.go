@@ -3,6 +3,7 @@ package versiondispatch
 import (
 	"bytes"
 	"crypto/sha256"
+	"errors"
 	"fmt"
 	"io"
 	"net"
@@ -14,6 +15,8 @@ import (
 	"strings"
 	"testing"
 	"time"
+
+	"github.com/conn-castle/agent-layer/internal/offline"
 )
 
 type failingRoundTripper struct {
@@ -174,6 +177,15 @@ func TestEnsureCachedBinary_NoNetwork(t *testing.T) {
 	}
 }
 
+func TestEnsureCachedBinary_Offline(t *testing.T) {
+	t.Setenv(offline.EnvVar, "1")
+
+	_, err := ensureCachedBinary(t.TempDir(), "1.0.0", io.Discard)
+	if !errors.Is(err, offline.ErrOffline) {
+		t.Fatalf("expected offline error, got %v", err)
+	}
+}
+
 func TestEnsureCachedBinary_PlatformError(t *testing.T) {
 	sys := &testSystem{
 		PlatformStringsFunc: func() (string, string, error) {
@@ -1,12 +1,12 @@
 package versiondispatch
 
 import (
-	"errors"
 	"fmt"
 	"io"
 	"path/filepath"
 	"strings"
 
+	"github.com/conn-castle/agent-layer/internal/i18n"
 	"github.com/conn-castle/agent-layer/internal/messages"
 	"github.com/conn-castle/agent-layer/internal/version"
 )
@@ -28,7 +28,7 @@ const (
 )
 
 // ErrDispatched signals that execution has been handed off to another binary.
-var ErrDispatched = errors.New(messages.DispatchErrDispatched)
+var ErrDispatched = i18n.NewError(messages.DispatchErrDispatched)
 
 // MaybeExec checks for a pinned version and dispatches to it when needed.
 // It returns ErrDispatched if execution was handed off.
@@ -40,16 +40,16 @@ func MaybeExec(args []string, currentVersion string, cwd string, stderr io.Write
 // It returns ErrDispatched if execution was handed off.
 func MaybeExecWithSystem(sys System, args []string, currentVersion string, cwd string, exit func(int)) error {
 	if sys == nil {
-		return fmt.Errorf(messages.DispatchSystemRequired)
+		return i18n.Errorf(messages.DispatchSystemRequired)
 	}
 	if len(args) == 0 {
-		return fmt.Errorf(messages.DispatchMissingArgv0)
+		return i18n.Errorf(messages.DispatchMissingArgv0)
 	}
 	if cwd == "" {
-		return fmt.Errorf(messages.DispatchWorkingDirRequired)
+		return i18n.Errorf(messages.DispatchWorkingDirRequired)
 	}
 	if exit == nil {
-		return fmt.Errorf(messages.DispatchExitHandlerRequired)
+		return i18n.Errorf(messages.DispatchExitHandlerRequired)
 	}
 
 	current, err := normalizeCurrentVersion(currentVersion)
@@ -81,18 +81,18 @@ func MaybeExecWithSystem(sys System, args []string, currentVersion string, cwd s
 			_, _ = fmt.Fprint(sys.Stderr(), warning)
 		}
 		if source != sourceCurrent {
-			_, _ = fmt.Fprintf(sys.Stderr(), messages.DispatchVersionSourceFmt, requested, source)
+			_, _ = fmt.Fprintf(sys.Stderr(), i18n.T(messages.DispatchVersionSourceFmt), requested, source)
 		}
 		if source == EnvVersionOverride && found && hasOverridePinned {
-			_, _ = fmt.Fprintf(sys.Stderr(), messages.DispatchVersionOverrideWarningFmt, EnvVersionOverride, overridePinned)
+			_, _ = fmt.Fprintf(sys.Stderr(), i18n.T(messages.DispatchVersionOverrideWarningFmt), EnvVersionOverride, overridePinned)
 		}
 		return nil
 	}
 	if sys.Getenv(EnvShimActive) != "" {
-		return fmt.Errorf(messages.DispatchAlreadyActiveFmt, current, requested)
+		return i18n.Errorf(messages.DispatchAlreadyActiveFmt, current, requested)
 	}
 	if version.IsDev(requested) {
-		return fmt.Errorf(messages.DispatchDevVersionNotAllowedFmt, EnvVersionOverride)
+		return i18n.Errorf(messages.DispatchDevVersionNotAllowedFmt, EnvVersionOverride)
 	}
 
 	cacheRoot, err := cacheRootDir(sys)
@@ -162,7 +162,7 @@ func normalizeCurrentVersion(raw string) (string, error) {
 	}
 	normalized, err := version.Normalize(raw)
 	if err != nil {
-		return "", fmt.Errorf(messages.DispatchInvalidBuildVersionFmt, raw, err)
+		return "", i18n.Errorf(messages.DispatchInvalidBuildVersionFmt, raw, err)
 	}
 	return normalized, nil
 }
@@ -174,7 +174,7 @@ func resolveRequestedVersion(sys System, rootDir string, hasRoot bool, current s
 	if override != "" {
 		normalized, err := version.Normalize(override)
 		if err != nil {
-			return "", "", "", "", false, fmt.Errorf(messages.DispatchInvalidEnvVersionFmt, EnvVersionOverride, err)
+			return "", "", "", "", false, i18n.Errorf(messages.DispatchInvalidEnvVersionFmt, EnvVersionOverride, err)
 		}
 		if !hasRoot {
 			return normalized, EnvVersionOverride, "", "", false, nil
@@ -209,7 +209,7 @@ func cacheRootDir(sys System) (string, error) {
 	}
 	base, err := sys.UserCacheDir()
 	if err != nil {
-		return "", fmt.Errorf(messages.DispatchResolveUserCacheDirFmt, err)
+		return "", i18n.Errorf(messages.DispatchResolveUserCacheDirFmt, err)
 	}
 	return filepath.Join(base, "agent-layer"), nil
 }
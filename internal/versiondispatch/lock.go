@@ -2,12 +2,12 @@ package versiondispatch
 
 import (
 	"errors"
-	"fmt"
 	"os"
 	"time"
 
 	"golang.org/x/sys/unix"
 
+	"github.com/conn-castle/agent-layer/internal/i18n"
 	"github.com/conn-castle/agent-layer/internal/messages"
 )
 
@@ -34,11 +34,11 @@ func withFileLock(sys System, path string, waitTimeout time.Duration, fn func()
 func acquireFileLock(sys System, path string, waitTimeout time.Duration) (*fileLock, error) {
 	file, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0o644) // #nosec G304,G302 -- lock file path is built from the dispatch cache layout (binPath+".lock"), not user input; 0o644 matches the cached binary perms so the file is visible to debug tooling but only writable by the owner.
 	if err != nil {
-		return nil, fmt.Errorf(messages.DispatchOpenLockFmt, path, err)
+		return nil, i18n.Errorf(messages.DispatchOpenLockFmt, path, err)
 	}
 	if err := lockFile(sys, file, waitTimeout); err != nil {
 		_ = file.Close()
-		return nil, fmt.Errorf(messages.DispatchLockFmt, path, err)
+		return nil, i18n.Errorf(messages.DispatchLockFmt, path, err)
 	}
 	return &fileLock{file: file, sys: sys}, nil
 }
@@ -67,7 +67,7 @@ func lockFile(sys System, file *os.File, waitTimeout time.Duration) error {
 			return err
 		}
 		if time.Now().After(deadline) {
-			return fmt.Errorf(messages.DispatchLockTimeoutFmt, waitTimeout)
+			return i18n.Errorf(messages.DispatchLockTimeoutFmt, waitTimeout)
 		}
 		sys.Sleep(lockPollEvery)
 	}
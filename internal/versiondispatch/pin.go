@@ -7,6 +7,8 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/conn-castle/agent-layer/internal/i18n"
+	"github.com/conn-castle/agent-layer/internal/layerdir"
 	"github.com/conn-castle/agent-layer/internal/messages"
 	"github.com/conn-castle/agent-layer/internal/version"
 )
@@ -15,13 +17,13 @@ import (
 // Empty or invalid pin files return a warning instead of an error so that dispatch
 // can fall through to the current binary version while surfacing the problem to the user.
 func readPinnedVersion(sys System, rootDir string) (string, bool, string, error) {
-	path := filepath.Join(rootDir, ".agent-layer", "al.version")
+	path := filepath.Join(layerdir.Dir(rootDir), "al.version")
 	data, err := sys.ReadFile(path)
 	if err != nil {
 		if errors.Is(err, os.ErrNotExist) {
 			return "", false, "", nil
 		}
-		return "", false, "", fmt.Errorf(messages.DispatchReadPinFailedFmt, path, err)
+		return "", false, "", i18n.Errorf(messages.DispatchReadPinFailedFmt, path, err)
 	}
 
 	var (
@@ -45,13 +47,13 @@ func readPinnedVersion(sys System, rootDir string) (string, bool, string, error)
 	}
 
 	if versionLineNumber == 0 {
-		return "", false, fmt.Sprintf(messages.DispatchPinFileEmptyWarningFmt, path), nil
+		return "", false, i18n.Sprintf(messages.DispatchPinFileEmptyWarningFmt, path), nil
 	}
 
 	normalized, err := version.Normalize(versionLine)
 	if err != nil {
 		warningDetail := fmt.Sprintf("line %d: %v", versionLineNumber, err)
-		return "", false, fmt.Sprintf(messages.DispatchInvalidPinnedVersionWarningFmt, path, warningDetail), nil
+		return "", false, i18n.Sprintf(messages.DispatchInvalidPinnedVersionWarningFmt, path, warningDetail), nil
 	}
 	return normalized, true, "", nil
 }
@@ -1,10 +1,10 @@
 package versiondispatch
 
 import (
-	"fmt"
 	"io"
 	"strings"
 
+	"github.com/conn-castle/agent-layer/internal/i18n"
 	"github.com/conn-castle/agent-layer/internal/messages"
 	"github.com/conn-castle/agent-layer/internal/version"
 )
@@ -13,15 +13,21 @@ import (
 // It validates the version, resolves the cache root, downloads the binary when missing,
 // and writes download progress to progressOut.
 func PrefetchVersion(versionInput string, progressOut io.Writer) error {
+	_, err := CachedBinaryPath(versionInput, progressOut)
+	return err
+}
+
+// CachedBinaryPath returns the path of the cached release binary for the
+// requested version, downloading it first when missing.
+func CachedBinaryPath(versionInput string, progressOut io.Writer) (string, error) {
 	normalized, err := version.Normalize(strings.TrimSpace(versionInput))
 	if err != nil {
-		return fmt.Errorf(messages.DispatchInvalidEnvVersionFmt, "version", err)
+		return "", i18n.Errorf(messages.DispatchInvalidEnvVersionFmt, "version", err)
 	}
 	sys := RealSystem{}
 	cacheRoot, err := cacheRootDir(sys)
 	if err != nil {
-		return err
+		return "", err
 	}
-	_, err = ensureCachedBinaryWithSystem(sys, cacheRoot, normalized, progressOut)
-	return err
+	return ensureCachedBinaryWithSystem(sys, cacheRoot, normalized, progressOut)
 }
@@ -0,0 +1,124 @@
+package versiondispatch
+
+import (
+	"os"
+	"strings"
+
+	"github.com/conn-castle/agent-layer/internal/i18n"
+	"github.com/conn-castle/agent-layer/internal/messages"
+	"github.com/conn-castle/agent-layer/internal/version"
+)
+
+// Decision names what MaybeExec would do for a directory.
+type Decision string
+
+const (
+	// DecisionCurrent runs the command in the current binary.
+	DecisionCurrent Decision = "current"
+	// DecisionDispatch re-executes the cached binary for the requested version.
+	DecisionDispatch Decision = "dispatch"
+	// DecisionBypass skips pin resolution because AL_DEV_BYPASS_VERSION_DISPATCH is set.
+	DecisionBypass Decision = "bypass"
+	// DecisionBlocked fails because dispatch cannot reach the requested version.
+	DecisionBlocked Decision = "blocked"
+)
+
+// Resolution describes the version dispatch decision without executing it.
+type Resolution struct {
+	// Root is the repo root holding .agent-layer/, or "" outside a repo.
+	Root string
+	// CurrentVersion is the running binary's version in X.Y.Z form, or "dev".
+	CurrentVersion string
+	// PinVersion is the version in .agent-layer/al.version, or "" when unpinned.
+	PinVersion string
+	// PinWarning is set when the pin file exists but is empty or invalid.
+	PinWarning string
+	// RequestedVersion is the version the command should run under.
+	RequestedVersion string
+	// Source is where RequestedVersion came from: "current", "pin", or AL_VERSION.
+	Source   string
+	Decision Decision
+	// Reason explains DecisionBlocked.
+	Reason string
+	// CacheDir is the pinned-version cache root.
+	CacheDir string
+	// CachedBinary is the binary DecisionDispatch runs; Cached reports whether
+	// it is already downloaded.
+	CachedBinary string
+	Cached       bool
+}
+
+// Resolve reports which binary MaybeExec would run for cwd. It reads the pin
+// and cache but never downloads or executes anything.
+func Resolve(sys System, currentVersion string, cwd string) (Resolution, error) {
+	if sys == nil {
+		return Resolution{}, i18n.Errorf(messages.DispatchSystemRequired)
+	}
+	if cwd == "" {
+		return Resolution{}, i18n.Errorf(messages.DispatchWorkingDirRequired)
+	}
+	current, err := normalizeCurrentVersion(currentVersion)
+	if err != nil {
+		return Resolution{}, err
+	}
+	cacheRoot, err := cacheRootDir(sys)
+	if err != nil {
+		return Resolution{}, err
+	}
+	resolution := Resolution{CurrentVersion: current, CacheDir: cacheRoot}
+
+	rootDir, found, err := sys.FindAgentLayerRoot(cwd)
+	if err != nil {
+		return Resolution{}, err
+	}
+	if found {
+		resolution.Root = rootDir
+		pinned, ok, warning, err := readPinnedVersion(sys, rootDir)
+		if err != nil {
+			return Resolution{}, err
+		}
+		if ok {
+			resolution.PinVersion = pinned
+		}
+		resolution.PinWarning = strings.TrimSpace(warning)
+	}
+
+	if strings.TrimSpace(sys.Getenv(EnvDevelopmentBypassVersionDispatch)) != "" {
+		resolution.RequestedVersion = current
+		resolution.Source = sourceCurrent
+		resolution.Decision = DecisionBypass
+		return resolution, nil
+	}
+	requested, source, _, _, _, err := resolveRequestedVersion(sys, rootDir, found, current)
+	if err != nil {
+		return Resolution{}, err
+	}
+	resolution.RequestedVersion = requested
+	resolution.Source = source
+	switch {
+	case requested == current:
+		resolution.Decision = DecisionCurrent
+		return resolution, nil
+	case sys.Getenv(EnvShimActive) != "":
+		resolution.Decision = DecisionBlocked
+		resolution.Reason = i18n.Sprintf(messages.DispatchAlreadyActiveFmt, current, requested)
+		return resolution, nil
+	case version.IsDev(requested):
+		resolution.Decision = DecisionBlocked
+		resolution.Reason = i18n.Sprintf(messages.DispatchDevVersionNotAllowedFmt, EnvVersionOverride)
+		return resolution, nil
+	}
+
+	resolution.Decision = DecisionDispatch
+	path, err := cachedBinaryPath(sys, cacheRoot, requested)
+	if err != nil {
+		return Resolution{}, err
+	}
+	resolution.CachedBinary = path
+	if _, err := sys.Stat(path); err == nil {
+		resolution.Cached = true
+	} else if !os.IsNotExist(err) {
+		return Resolution{}, i18n.Errorf(messages.DispatchCheckCachedBinaryFmt, path, err)
+	}
+	return resolution, nil
+}
@@ -0,0 +1,123 @@
+package versiondispatch
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writePin(t *testing.T, root string, content string) {
+	t.Helper()
+	dir := filepath.Join(root, ".agent-layer")
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "al.version"), []byte(content), 0o600); err != nil {
+		t.Fatalf("write pin: %v", err)
+	}
+}
+
+func TestResolve_PinMatchesCurrent(t *testing.T) {
+	root := t.TempDir()
+	writePin(t, root, "v1.0.0\n")
+	t.Setenv(EnvCacheDir, t.TempDir())
+	t.Setenv(EnvVersionOverride, "")
+
+	got, err := Resolve(RealSystem{}, "v1.0.0", root)
+	if err != nil {
+		t.Fatalf("Resolve: %v", err)
+	}
+	if got.Decision != DecisionCurrent || got.Source != sourcePin || got.PinVersion != "1.0.0" || got.RequestedVersion != "1.0.0" {
+		t.Fatalf("unexpected resolution: %+v", got)
+	}
+	if got.CachedBinary != "" {
+		t.Fatalf("current decision should not name a cached binary: %+v", got)
+	}
+}
+
+func TestResolve_DispatchReportsCachePathWithoutDownloading(t *testing.T) {
+	root := t.TempDir()
+	writePin(t, root, "0.9.0\n")
+	cacheDir := t.TempDir()
+	t.Setenv(EnvCacheDir, cacheDir)
+	t.Setenv(EnvVersionOverride, "")
+	t.Setenv(EnvShimActive, "")
+	sys := &testSystem{PlatformStringsFunc: func() (string, string, error) { return osLinux, archAMD64, nil }}
+
+	got, err := Resolve(sys, "1.0.0", root)
+	if err != nil {
+		t.Fatalf("Resolve: %v", err)
+	}
+	want := filepath.Join(cacheDir, "versions", "0.9.0", "linux-amd64", assetName(osLinux, archAMD64))
+	if got.Decision != DecisionDispatch || got.CachedBinary != want || got.Cached {
+		t.Fatalf("unexpected resolution: %+v", got)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(want), 0o700); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(want, []byte("bin"), 0o700); err != nil {
+		t.Fatalf("write binary: %v", err)
+	}
+	got, err = Resolve(sys, "1.0.0", root)
+	if err != nil {
+		t.Fatalf("Resolve: %v", err)
+	}
+	if !got.Cached {
+		t.Fatalf("expected cached binary: %+v", got)
+	}
+}
+
+func TestResolve_BlockedWhenShimActive(t *testing.T) {
+	t.Setenv(EnvCacheDir, t.TempDir())
+	t.Setenv(EnvVersionOverride, "1.1.0")
+	t.Setenv(EnvShimActive, "1")
+
+	got, err := Resolve(RealSystem{}, "1.0.0", t.TempDir())
+	if err != nil {
+		t.Fatalf("Resolve: %v", err)
+	}
+	if got.Decision != DecisionBlocked || got.Source != EnvVersionOverride || !strings.Contains(got.Reason, "already active") {
+		t.Fatalf("unexpected resolution: %+v", got)
+	}
+}
+
+func TestResolve_DevelopmentBypass(t *testing.T) {
+	root := t.TempDir()
+	writePin(t, root, "0.9.0\n")
+	t.Setenv(EnvCacheDir, t.TempDir())
+	t.Setenv(EnvDevelopmentBypassVersionDispatch, "1")
+
+	got, err := Resolve(RealSystem{}, "dev", root)
+	if err != nil {
+		t.Fatalf("Resolve: %v", err)
+	}
+	if got.Decision != DecisionBypass || got.RequestedVersion != "dev" || got.PinVersion != "0.9.0" {
+		t.Fatalf("unexpected resolution: %+v", got)
+	}
+}
+
+func TestResolve_InvalidPinWarns(t *testing.T) {
+	root := t.TempDir()
+	writePin(t, root, "not-a-version\n")
+	t.Setenv(EnvCacheDir, t.TempDir())
+	t.Setenv(EnvVersionOverride, "")
+
+	got, err := Resolve(RealSystem{}, "1.0.0", root)
+	if err != nil {
+		t.Fatalf("Resolve: %v", err)
+	}
+	if got.Decision != DecisionCurrent || got.PinVersion != "" || !strings.HasPrefix(got.PinWarning, "warning: invalid pinned version") {
+		t.Fatalf("unexpected resolution: %+v", got)
+	}
+}
+
+func TestResolve_RequiresArguments(t *testing.T) {
+	if _, err := Resolve(nil, "1.0.0", "."); err == nil {
+		t.Fatal("expected error for nil system")
+	}
+	if _, err := Resolve(RealSystem{}, "1.0.0", ""); err == nil {
+		t.Fatal("expected error for empty cwd")
+	}
+}
@@ -1,12 +1,13 @@
 package warnings
 
 import (
-	"fmt"
 	"os"
 	"path/filepath"
 	"sort"
 	"strings"
 
+	"github.com/conn-castle/agent-layer/internal/i18n"
+	"github.com/conn-castle/agent-layer/internal/layerdir"
 	"github.com/conn-castle/agent-layer/internal/messages"
 )
 
@@ -38,7 +39,7 @@ func CheckInstructions(rootDir string, threshold *int) ([]Warning, error) {
 		return []Warning{{
 			Code:              CodeInstructionsTooLarge,
 			Subject:           subject,
-			Message:           fmt.Sprintf(messages.WarningsInstructionsTooLargeFmt, *threshold, tokens, *threshold),
+			Message:           i18n.Sprintf(messages.WarningsInstructionsTooLargeFmt, *threshold, tokens, *threshold),
 			Fix:               messages.WarningsInstructionsTooLargeFix,
 			Source:            SourceInternal,
 			Severity:          SeverityWarning,
@@ -62,7 +63,7 @@ func getInstructionPayload(rootDir string) (string, string, error) {
 	}
 
 	// 2. Fallback to .agent-layer/instructions/*.md
-	instructionsDir := filepath.Join(rootDir, ".agent-layer", "instructions")
+	instructionsDir := filepath.Join(layerdir.Dir(rootDir), "instructions")
 	files, err := os.ReadDir(instructionsDir)
 	if err != nil {
 		if os.IsNotExist(err) {
@@ -9,6 +9,7 @@ import (
 	"sync"
 
 	"github.com/conn-castle/agent-layer/internal/config"
+	"github.com/conn-castle/agent-layer/internal/i18n"
 	"github.com/conn-castle/agent-layer/internal/messages"
 	"github.com/conn-castle/agent-layer/internal/projection"
 )
@@ -51,7 +52,7 @@ func CheckMCPServers(ctx context.Context, cfg *config.ProjectConfig, connector C
 		return []Warning{{
 			Code:     CodeMCPServerUnreachable,
 			Subject:  subject,
-			Message:  fmt.Sprintf(messages.WarningsResolveConfigFailedFmt, err),
+			Message:  i18n.Sprintf(messages.WarningsResolveConfigFailedFmt, err),
 			Fix:      messages.WarningsResolveConfigFix,
 			Source:   SourceInternal,
 			Severity: SeverityCritical,
@@ -71,7 +72,7 @@ func CheckMCPServers(ctx context.Context, cfg *config.ProjectConfig, connector C
 		warnings = append(warnings, Warning{
 			Code:              CodeMCPTooManyServers,
 			Subject:           mcpServersKey,
-			Message:           fmt.Sprintf(messages.WarningsTooManyServersFmt, *thresholds.MCPServerThreshold, len(enabledServers), *thresholds.MCPServerThreshold),
+			Message:           i18n.Sprintf(messages.WarningsTooManyServersFmt, *thresholds.MCPServerThreshold, len(enabledServers), *thresholds.MCPServerThreshold),
 			Fix:               messages.WarningsTooManyServersFix,
 			Source:            SourceInternal,
 			Severity:          SeverityWarning,
@@ -97,7 +98,7 @@ func CheckMCPServers(ctx context.Context, cfg *config.ProjectConfig, connector C
 			warnings = append(warnings, Warning{
 				Code:     CodeMCPServerUnreachable,
 				Subject:  res.ServerID,
-				Message:  fmt.Sprintf(messages.WarningsMCPConnectFailedFmt, res.Error),
+				Message:  i18n.Sprintf(messages.WarningsMCPConnectFailedFmt, res.Error),
 				Fix:      messages.WarningsMCPConnectFix,
 				Source:   source,
 				Severity: SeverityCritical,
@@ -111,7 +112,7 @@ func CheckMCPServers(ctx context.Context, cfg *config.ProjectConfig, connector C
 			warnings = append(warnings, Warning{
 				Code:              CodeMCPServerTooManyTools,
 				Subject:           res.ServerID,
-				Message:           fmt.Sprintf(messages.WarningsMCPServerTooManyToolsFmt, *thresholds.MCPServerToolsThreshold, len(res.Tools), *thresholds.MCPServerToolsThreshold),
+				Message:           i18n.Sprintf(messages.WarningsMCPServerTooManyToolsFmt, *thresholds.MCPServerToolsThreshold, len(res.Tools), *thresholds.MCPServerToolsThreshold),
 				Fix:               messages.WarningsMCPServerTooManyToolsFix,
 				Source:            SourceInternal,
 				Severity:          SeverityWarning,
@@ -142,7 +143,7 @@ func CheckMCPServers(ctx context.Context, cfg *config.ProjectConfig, connector C
 			warnings = append(warnings, Warning{
 				Code:              CodeMCPToolSchemaBloatServer,
 				Subject:           res.ServerID,
-				Message:           fmt.Sprintf(messages.WarningsMCPSchemaBloatServerFmt, *thresholds.MCPSchemaTokensServerThreshold, res.SchemaTokens, *thresholds.MCPSchemaTokensServerThreshold),
+				Message:           i18n.Sprintf(messages.WarningsMCPSchemaBloatServerFmt, *thresholds.MCPSchemaTokensServerThreshold, res.SchemaTokens, *thresholds.MCPSchemaTokensServerThreshold),
 				Fix:               messages.WarningsMCPSchemaBloatFix,
 				Details:           details,
 				Source:            SourceInternal,
@@ -164,7 +165,7 @@ func CheckMCPServers(ctx context.Context, cfg *config.ProjectConfig, connector C
 		warnings = append(warnings, Warning{
 			Code:              CodeMCPTooManyToolsTotal,
 			Subject:           "mcp.tools.total",
-			Message:           fmt.Sprintf(messages.WarningsMCPTooManyToolsTotalFmt, *thresholds.MCPToolsTotalThreshold, totalTools, *thresholds.MCPToolsTotalThreshold),
+			Message:           i18n.Sprintf(messages.WarningsMCPTooManyToolsTotalFmt, *thresholds.MCPToolsTotalThreshold, totalTools, *thresholds.MCPToolsTotalThreshold),
 			Fix:               messages.WarningsMCPTooManyToolsTotalFix,
 			Source:            SourceInternal,
 			Severity:          SeverityWarning,
@@ -177,7 +178,7 @@ func CheckMCPServers(ctx context.Context, cfg *config.ProjectConfig, connector C
 		warnings = append(warnings, Warning{
 			Code:              CodeMCPToolSchemaBloatTotal,
 			Subject:           "mcp.tools.schema.total",
-			Message:           fmt.Sprintf(messages.WarningsMCPSchemaBloatTotalFmt, *thresholds.MCPSchemaTokensTotalThreshold, totalSchemaTokens, *thresholds.MCPSchemaTokensTotalThreshold),
+			Message:           i18n.Sprintf(messages.WarningsMCPSchemaBloatTotalFmt, *thresholds.MCPSchemaTokensTotalThreshold, totalSchemaTokens, *thresholds.MCPSchemaTokensTotalThreshold),
 			Fix:               messages.WarningsMCPSchemaBloatFix,
 			Source:            SourceInternal,
 			Severity:          SeverityWarning,
@@ -199,7 +200,7 @@ func CheckMCPServers(ctx context.Context, cfg *config.ProjectConfig, connector C
 		warnings = append(warnings, Warning{
 			Code:     CodeMCPToolNameCollision,
 			Subject:  name,
-			Message:  fmt.Sprintf(messages.WarningsMCPToolNameCollisionFmt, servers),
+			Message:  i18n.Sprintf(messages.WarningsMCPToolNameCollisionFmt, servers),
 			Fix:      messages.WarningsMCPToolNameCollisionFix,
 			Source:   SourceInternal,
 			Severity: SeverityWarning,
@@ -229,6 +230,10 @@ type DiscoveryResult struct {
 	Tools        []ToolDef
 	SchemaTokens int
 	Error        error
+	// ServerName and ServerVersion come from the server's initialize response
+	// and are empty when the server did not report them.
+	ServerName    string
+	ServerVersion string
 }
 
 // MCPDiscoveryStatus is the status of a discovery event for an MCP server.
@@ -14,6 +14,7 @@ import (
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 
 	"github.com/conn-castle/agent-layer/internal/config"
+	"github.com/conn-castle/agent-layer/internal/i18n"
 	"github.com/conn-castle/agent-layer/internal/messages"
 	"github.com/conn-castle/agent-layer/internal/projection"
 )
@@ -55,6 +56,19 @@ func (r *realMCPSession) Close() error {
 	return r.session.Close()
 }
 
+func (r *realMCPSession) ServerInfo() *mcp.Implementation {
+	if result := r.session.InitializeResult(); result != nil {
+		return result.ServerInfo
+	}
+	return nil
+}
+
+// serverInfoSession is implemented by sessions that expose the server
+// implementation reported during initialization.
+type serverInfoSession interface {
+	ServerInfo() *mcp.Implementation
+}
+
 // NewMCPClientFunc is a mockable function for creating MCP clients.
 var NewMCPClientFunc = func(impl *mcp.Implementation, opts *mcp.ClientOptions) mcpClientInterface {
 	return &realMCPClient{client: mcp.NewClient(impl, opts)}
@@ -100,64 +114,26 @@ func (r *RealConnector) ConnectAndDiscover(ctx context.Context, server projectio
 		Version: "1.0.0",
 	}, nil)
 
-	var transport mcp.Transport
-
-	switch server.Transport {
-	case config.TransportStdio:
-		// Bind the spawned MCP server process to the discovery context (which
-		// carries mcpDiscoveryTimeout) so a hung or misbehaving server is killed
-		// directly on cancel/timeout rather than only torn down indirectly via
-		// session Close().
-		cmd := exec.CommandContext(ctx, server.Command, server.Args...)
-		cmd.Env = buildMCPCommandEnv(os.Environ(), server.Env)
-
-		transport = &mcp.CommandTransport{
-			Command: cmd,
-		}
-	case config.TransportHTTP:
-		switch server.HTTPTransport {
-		case "", "sse":
-			t := &mcp.SSEClientTransport{
-				Endpoint: server.URL,
-			}
-			if len(server.Headers) > 0 {
-				t.HTTPClient = &http.Client{
-					Transport: &headerTransport{
-						base:    http.DefaultTransport,
-						headers: server.Headers,
-					},
-				}
-			}
-			transport = t
-		case "streamable":
-			t := &mcp.StreamableClientTransport{
-				Endpoint: server.URL,
-			}
-			if len(server.Headers) > 0 {
-				t.HTTPClient = &http.Client{
-					Transport: &headerTransport{
-						base:    http.DefaultTransport,
-						headers: server.Headers,
-					},
-				}
-			}
-			transport = t
-		default:
-			res.Error = fmt.Errorf(messages.WarningsUnsupportedHTTPTransportFmt, server.HTTPTransport)
-			return res
-		}
-	default:
-		res.Error = fmt.Errorf(messages.WarningsUnsupportedTransportFmt, server.Transport)
+	transport, err := NewMCPTransport(ctx, server)
+	if err != nil {
+		res.Error = err
 		return res
 	}
 
 	session, err := mcpClient.Connect(ctx, transport, nil)
 	if err != nil {
-		res.Error = fmt.Errorf(messages.WarningsConnectionFailedFmt, err)
+		res.Error = i18n.Errorf(messages.WarningsConnectionFailedFmt, err)
 		return res
 	}
 	defer func() { _ = session.Close() }()
 
+	if infoSession, ok := session.(serverInfoSession); ok {
+		if info := infoSession.ServerInfo(); info != nil {
+			res.ServerName = info.Name
+			res.ServerVersion = info.Version
+		}
+	}
+
 	// List tools (paginated)
 	var allTools []*mcp.Tool
 	var cursor string
@@ -168,7 +144,7 @@ func (r *RealConnector) ConnectAndDiscover(ctx context.Context, server projectio
 		})
 
 		if err != nil {
-			res.Error = fmt.Errorf(messages.WarningsListToolsFailedFmt, err)
+			res.Error = i18n.Errorf(messages.WarningsListToolsFailedFmt, err)
 			return res
 		}
 
@@ -181,14 +157,18 @@ func (r *RealConnector) ConnectAndDiscover(ctx context.Context, server projectio
 
 		// Guard against infinite loops
 		if len(allTools) > maxToolsToDiscover {
-			res.Error = fmt.Errorf(messages.WarningsTooManyTools)
+			res.Error = i18n.Errorf(messages.WarningsTooManyTools)
 			return res
 		}
 	}
 
-	// Process tools
+	// Process tools. Filtered-out tools never reach clients, so they do not
+	// count toward tool or schema thresholds.
 	var toolsJSON []any
 	for _, t := range allTools {
+		if !server.AllowsTool(t.Name) {
+			continue
+		}
 		toolDef := ToolDef{Name: t.Name}
 
 		// Estimate tokens per tool
@@ -211,6 +191,55 @@ func (r *RealConnector) ConnectAndDiscover(ctx context.Context, server projectio
 	return res
 }
 
+// NewMCPTransport builds the client transport for a resolved MCP server. Stdio
+// servers are started with an allow-listed environment and are killed when ctx
+// is done.
+func NewMCPTransport(ctx context.Context, server projection.ResolvedMCPServer) (mcp.Transport, error) {
+	switch server.Transport {
+	case config.TransportStdio:
+		// Bind the spawned MCP server process to ctx so a hung or misbehaving
+		// server is killed directly on cancel/timeout rather than only torn down
+		// indirectly via session Close().
+		cmd := exec.CommandContext(ctx, server.Command, server.Args...)
+		cmd.Env = buildMCPCommandEnv(os.Environ(), server.Env)
+
+		return &mcp.CommandTransport{Command: cmd}, nil
+	case config.TransportHTTP:
+		switch server.HTTPTransport {
+		case "", "sse":
+			t := &mcp.SSEClientTransport{
+				Endpoint: server.URL,
+			}
+			if len(server.Headers) > 0 {
+				t.HTTPClient = &http.Client{
+					Transport: &headerTransport{
+						base:    http.DefaultTransport,
+						headers: server.Headers,
+					},
+				}
+			}
+			return t, nil
+		case "streamable":
+			t := &mcp.StreamableClientTransport{
+				Endpoint: server.URL,
+			}
+			if len(server.Headers) > 0 {
+				t.HTTPClient = &http.Client{
+					Transport: &headerTransport{
+						base:    http.DefaultTransport,
+						headers: server.Headers,
+					},
+				}
+			}
+			return t, nil
+		default:
+			return nil, i18n.Errorf(messages.WarningsUnsupportedHTTPTransportFmt, server.HTTPTransport)
+		}
+	default:
+		return nil, i18n.Errorf(messages.WarningsUnsupportedTransportFmt, server.Transport)
+	}
+}
+
 // headerTransport adds headers to HTTP requests.
 type headerTransport struct {
 	base    http.RoundTripper
@@ -0,0 +1,48 @@
+package warnings
+
+import (
+	"context"
+
+	"github.com/conn-castle/agent-layer/internal/config"
+	"github.com/conn-castle/agent-layer/internal/projection"
+)
+
+// MCPServerStatus is the health of one enabled MCP server.
+type MCPServerStatus struct {
+	ID            string
+	Transport     string
+	ServerName    string
+	ServerVersion string
+	Tools         int
+	SchemaTokens  int
+	// Err is non-nil when the server failed to start, connect, or list tools.
+	Err error
+}
+
+// MCPStatus connects to every enabled MCP server and reports its health in
+// config order. connector may be nil to use the real SDK connector. It returns
+// an error only when the server configuration cannot be resolved.
+func MCPStatus(ctx context.Context, cfg *config.ProjectConfig, connector Connector, statusFn MCPDiscoveryStatusFunc) ([]MCPServerStatus, error) {
+	if connector == nil {
+		connector = &RealConnector{}
+	}
+	servers, err := projection.ResolveEnabledMCPServers(cfg.Config.MCP.Servers, cfg.Env)
+	if err != nil {
+		return nil, err
+	}
+	results := discoverTools(ctx, servers, connector, statusFn)
+	statuses := make([]MCPServerStatus, len(servers))
+	for i, server := range servers {
+		res := results[i]
+		statuses[i] = MCPServerStatus{
+			ID:            server.ID,
+			Transport:     server.Transport,
+			ServerName:    res.ServerName,
+			ServerVersion: res.ServerVersion,
+			Tools:         len(res.Tools),
+			SchemaTokens:  res.SchemaTokens,
+			Err:           res.Error,
+		}
+	}
+	return statuses, nil
+}
@@ -0,0 +1,106 @@
+package warnings
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/conn-castle/agent-layer/internal/config"
+	"github.com/conn-castle/agent-layer/internal/projection"
+)
+
+func TestMCPStatus(t *testing.T) {
+	enabled := true
+	disabled := false
+	cfg := &config.ProjectConfig{
+		Config: config.Config{
+			MCP: config.MCPConfig{
+				Servers: []config.MCPServer{
+					{ID: "s1", Enabled: &enabled, Transport: "stdio", Command: "echo"},
+					{ID: "off", Enabled: &disabled, Transport: "stdio", Command: "echo"},
+					{ID: "s2", Enabled: &enabled, Transport: "http", URL: "http://localhost"},
+				},
+			},
+		},
+		Env: map[string]string{},
+	}
+	connector := &MockConnector{Results: map[string]DiscoveryResult{
+		"s1": {
+			ServerID:      "s1",
+			Tools:         []ToolDef{{Name: "a"}, {Name: "b"}},
+			SchemaTokens:  42,
+			ServerName:    "demo",
+			ServerVersion: "1.2.3",
+		},
+		"s2": {ServerID: "s2", Error: fmt.Errorf("connection refused")},
+	}}
+
+	statuses, err := MCPStatus(context.Background(), cfg, connector, nil)
+	require.NoError(t, err)
+	require.Len(t, statuses, 2)
+
+	assert.Equal(t, "s1", statuses[0].ID)
+	assert.Equal(t, "stdio", statuses[0].Transport)
+	assert.Equal(t, "demo", statuses[0].ServerName)
+	assert.Equal(t, "1.2.3", statuses[0].ServerVersion)
+	assert.Equal(t, 2, statuses[0].Tools)
+	assert.Equal(t, 42, statuses[0].SchemaTokens)
+	assert.NoError(t, statuses[0].Err)
+
+	assert.Equal(t, "s2", statuses[1].ID)
+	assert.Equal(t, "http", statuses[1].Transport)
+	assert.EqualError(t, statuses[1].Err, "connection refused")
+}
+
+func TestMCPStatus_ResolveError(t *testing.T) {
+	enabled := true
+	cfg := &config.ProjectConfig{
+		Config: config.Config{
+			MCP: config.MCPConfig{
+				Servers: []config.MCPServer{
+					{ID: "s1", Enabled: &enabled, Transport: "http", URL: "${MISSING_URL}"},
+				},
+			},
+		},
+		Env: map[string]string{},
+	}
+
+	_, err := MCPStatus(context.Background(), cfg, &MockConnector{}, nil)
+	require.Error(t, err)
+}
+
+// infoMCPSession is a mockMCPSession that reports server info.
+type infoMCPSession struct {
+	mockMCPSession
+	info *mcp.Implementation
+}
+
+func (m *infoMCPSession) ServerInfo() *mcp.Implementation {
+	return m.info
+}
+
+func TestRealConnector_ServerInfo(t *testing.T) {
+	session := &infoMCPSession{
+		mockMCPSession: mockMCPSession{tools: []*mcp.Tool{{Name: "tool1"}}},
+		info:           &mcp.Implementation{Name: "demo", Version: "0.4.0"},
+	}
+	original := NewMCPClientFunc
+	NewMCPClientFunc = func(impl *mcp.Implementation, opts *mcp.ClientOptions) mcpClientInterface {
+		return &mockMCPClient{session: session}
+	}
+	t.Cleanup(func() { NewMCPClientFunc = original })
+
+	res := (&RealConnector{}).ConnectAndDiscover(context.Background(), projection.ResolvedMCPServer{
+		ID:        "demo",
+		Transport: "stdio",
+		Command:   "echo",
+	})
+	require.NoError(t, res.Error)
+	assert.Equal(t, "demo", res.ServerName)
+	assert.Equal(t, "0.4.0", res.ServerVersion)
+	assert.Len(t, res.Tools, 1)
+}
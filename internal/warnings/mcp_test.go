@@ -1230,3 +1230,23 @@ func TestRealConnector_TooManyToolsGuard(t *testing.T) {
 	assert.Contains(t, result.Error.Error(), "too many tools or infinite loop")
 	assert.True(t, mockSession.closeCalled, "session.Close should be called")
 }
+
+func TestRealConnector_ToolFilter(t *testing.T) {
+	session := &mockMCPSession{tools: []*mcp.Tool{{Name: "read"}, {Name: "write"}, {Name: "delete"}}}
+	original := NewMCPClientFunc
+	NewMCPClientFunc = func(impl *mcp.Implementation, opts *mcp.ClientOptions) mcpClientInterface {
+		return &mockMCPClient{session: session}
+	}
+	t.Cleanup(func() { NewMCPClientFunc = original })
+
+	res := (&RealConnector{}).ConnectAndDiscover(context.Background(), projection.ResolvedMCPServer{
+		ID:         "filtered",
+		Transport:  "stdio",
+		Command:    "echo",
+		ToolsAllow: []string{"read", "write"},
+		ToolsDeny:  []string{"write"},
+	})
+	require.NoError(t, res.Error)
+	require.Len(t, res.Tools, 1)
+	assert.Equal(t, "read", res.Tools[0].Name)
+}
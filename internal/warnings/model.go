@@ -18,6 +18,8 @@ const (
 	CodePolicyCapabilityMismatch     = "POLICY_CLIENT_CAPABILITY_MISMATCH"
 	CodePolicyAgentSpecificOverrides = "POLICY_AGENT_SPECIFIC_OVERRIDES"
 	CodePolicyClaudeReasoningUnknown = "POLICY_CLAUDE_REASONING_EFFORT_UNKNOWN"
+	CodeGeneratedFileEdited          = "GENERATED_FILE_EDITED"
+	CodePolicyContentRule            = "POLICY_CONTENT_RULE"
 )
 
 // Source labels where a warning originates.
@@ -1,9 +1,9 @@
 package warnings
 
 import (
-	"fmt"
 	"strings"
 
+	"github.com/conn-castle/agent-layer/internal/i18n"
 	"github.com/conn-castle/agent-layer/internal/messages"
 )
 
@@ -48,7 +48,7 @@ func ApplyNoiseControl(items []Warning, mode string) []Warning {
 	out = append(out, Warning{
 		Code:     CodeWarningNoiseModeInvalid,
 		Subject:  "warnings.noise_mode",
-		Message:  fmt.Sprintf(messages.WarningsNoiseModeInvalidFmt, mode, NoiseModeDefault, NoiseModeReduce, NoiseModeQuiet),
+		Message:  i18n.Sprintf(messages.WarningsNoiseModeInvalidFmt, mode, NoiseModeDefault, NoiseModeReduce, NoiseModeQuiet),
 		Fix:      messages.WarningsNoiseModeInvalidFix,
 		Source:   SourceInternal,
 		Severity: SeverityCritical,
@@ -9,6 +9,7 @@ import (
 	"unicode"
 
 	"github.com/conn-castle/agent-layer/internal/config"
+	"github.com/conn-castle/agent-layer/internal/i18n"
 	"github.com/conn-castle/agent-layer/internal/messages"
 )
 
@@ -64,6 +65,10 @@ func CheckPolicy(project *config.ProjectConfig) []Warning {
 			})
 		}
 
+		if w := toolFilterCapabilityWarning(project.Config, server); w != nil {
+			results = append(results, *w)
+		}
+
 		if isClientTargeted(server.Clients, "codex") && isEnabled(project.Config.Agents.Codex.Enabled) {
 			if detail, ok := findUnsupportedCodexHeaderForm(server.Headers); ok {
 				results = append(results, Warning{
@@ -83,6 +88,42 @@ func CheckPolicy(project *config.ProjectConfig) []Warning {
 	return dedupePolicyWarnings(results)
 }
 
+// toolFilterCapabilityWarning reports enabled clients that receive server but
+// cannot enforce its tools_allow/tools_deny natively. Codex supports both
+// lists and Copilot CLI supports an allow list; the gateway enforces both for
+// every client.
+func toolFilterCapabilityWarning(cfg config.Config, server config.MCPServer) *Warning {
+	if !server.HasToolFilter() || cfg.MCP.GatewayEnabled() {
+		return nil
+	}
+	agents := cfg.Agents
+	var clients []string
+	if isClientTargeted(server.Clients, "antigravity") && isEnabled(agents.Antigravity.Enabled) {
+		clients = append(clients, "antigravity")
+	}
+	if isClientTargeted(server.Clients, "claude") && (isEnabled(agents.Claude.Enabled) || isEnabled(agents.ClaudeVSCode.Enabled)) {
+		clients = append(clients, "claude")
+	}
+	if isClientTargeted(server.Clients, "copilot") && isEnabled(agents.CopilotCLI.Enabled) && len(server.ToolsAllow) == 0 {
+		clients = append(clients, "copilot")
+	}
+	if isClientTargeted(server.Clients, "vscode") && isEnabled(agents.VSCode.Enabled) {
+		clients = append(clients, "vscode")
+	}
+	if len(clients) == 0 {
+		return nil
+	}
+	return &Warning{
+		Code:     CodePolicyCapabilityMismatch,
+		Subject:  server.ID,
+		Message:  messages.WarningsPolicyToolFilterUnsupported,
+		Fix:      messages.WarningsPolicyToolFilterUnsupportedFix,
+		Details:  []string{fmt.Sprintf("clients without native tool filtering: %s", strings.Join(clients, ", "))},
+		Source:   SourceInternal,
+		Severity: SeverityWarning,
+	}
+}
+
 func claudeReasoningEffortUnknownWarning(effort string) *Warning {
 	trimmed := strings.TrimSpace(effort)
 	known := config.FieldOptionValues(config.ClaudeReasoningEffortFieldKey)
@@ -92,7 +133,7 @@ func claudeReasoningEffortUnknownWarning(effort string) *Warning {
 	return &Warning{
 		Code:     CodePolicyClaudeReasoningUnknown,
 		Subject:  "agents.claude.reasoning_effort",
-		Message:  fmt.Sprintf(messages.WarningsPolicyClaudeReasoningUnknownFmt, trimmed, strings.Join(known, ", ")),
+		Message:  i18n.Sprintf(messages.WarningsPolicyClaudeReasoningUnknownFmt, trimmed, strings.Join(known, ", ")),
 		Fix:      messages.WarningsPolicyClaudeReasoningUnknownFix,
 		Source:   SourceInternal,
 		Severity: SeverityWarning,
@@ -124,7 +165,7 @@ func codexAgentSpecificOverrideWarning(repoRoot string, agentSpecific map[string
 	return &Warning{
 		Code:     CodePolicyAgentSpecificOverrides,
 		Subject:  "agents.codex.agent_specific",
-		Message:  fmt.Sprintf(messages.WarningsPolicyAgentSpecificOverridesFmt, "codex"),
+		Message:  i18n.Sprintf(messages.WarningsPolicyAgentSpecificOverridesFmt, "codex"),
 		Fix:      messages.WarningsPolicyAgentSpecificOverridesFix,
 		Details:  []string{fmt.Sprintf("overridden keys: %s", strings.Join(keys, ", "))},
 		Source:   SourceInternal,
@@ -174,7 +215,7 @@ func claudeAgentSpecificOverrideWarning(agentSpecific map[string]any) *Warning {
 	return &Warning{
 		Code:     CodePolicyAgentSpecificOverrides,
 		Subject:  "agents.claude.agent_specific",
-		Message:  fmt.Sprintf(messages.WarningsPolicyAgentSpecificOverridesFmt, "claude"),
+		Message:  i18n.Sprintf(messages.WarningsPolicyAgentSpecificOverridesFmt, "claude"),
 		Fix:      messages.WarningsPolicyAgentSpecificOverridesFix,
 		Details:  []string{fmt.Sprintf("overridden keys: %s", strings.Join(keys, ", "))},
 		Source:   SourceInternal,
@@ -205,7 +246,7 @@ func antigravityAgentSpecificOverrideWarning(agentSpecific map[string]any) *Warn
 	return &Warning{
 		Code:     CodePolicyAgentSpecificOverrides,
 		Subject:  "agents.antigravity.agent_specific",
-		Message:  fmt.Sprintf(messages.WarningsPolicyAgentSpecificOverridesFmt, "antigravity"),
+		Message:  i18n.Sprintf(messages.WarningsPolicyAgentSpecificOverridesFmt, "antigravity"),
 		Fix:      messages.WarningsPolicyAgentSpecificOverridesFix,
 		Details:  []string{fmt.Sprintf("overridden keys: %s", overriddenKey)},
 		Source:   SourceInternal,
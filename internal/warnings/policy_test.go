@@ -94,6 +94,37 @@ func TestCheckPolicy_CodexHeaderPolicy(t *testing.T) {
 	require.Equal(t, "srv", results[0].Subject)
 }
 
+func TestCheckPolicy_ToolFilterCapabilityMismatch(t *testing.T) {
+	enabled := true
+	project := &config.ProjectConfig{
+		Config: config.Config{
+			Agents: config.AgentsConfig{
+				Claude:     config.ClaudeConfig{Enabled: &enabled},
+				Codex:      config.CodexConfig{Enabled: &enabled},
+				CopilotCLI: config.AgentConfig{Enabled: &enabled},
+			},
+			MCP: config.MCPConfig{
+				Servers: []config.MCPServer{
+					{ID: "allow", Enabled: &enabled, Transport: config.TransportStdio, Command: "tool", ToolsAllow: []string{"read"}},
+					{ID: "deny", Enabled: &enabled, Transport: config.TransportStdio, Command: "tool", ToolsDeny: []string{"write"}},
+					{ID: "codex-only", Enabled: &enabled, Transport: config.TransportStdio, Command: "tool", Clients: []string{"codex"}, ToolsDeny: []string{"write"}},
+				},
+			},
+		},
+	}
+
+	results := CheckPolicy(project)
+	require.Len(t, results, 2)
+	require.Equal(t, CodePolicyCapabilityMismatch, results[0].Code)
+	require.Equal(t, "allow", results[0].Subject)
+	require.Equal(t, []string{"clients without native tool filtering: claude"}, results[0].Details)
+	require.Equal(t, "deny", results[1].Subject)
+	require.Equal(t, []string{"clients without native tool filtering: claude, copilot"}, results[1].Details)
+
+	project.Config.MCP.Gateway = &enabled
+	require.Empty(t, CheckPolicy(project))
+}
+
 func TestCheckPolicy_YOLOModeNoWarning(t *testing.T) {
 	project := &config.ProjectConfig{
 		Config: config.Config{
@@ -9,6 +9,7 @@ import (
 
 	"github.com/conn-castle/agent-layer/internal/envfile"
 	"github.com/conn-castle/agent-layer/internal/fsutil"
+	"github.com/conn-castle/agent-layer/internal/i18n"
 	"github.com/conn-castle/agent-layer/internal/messages"
 	"github.com/conn-castle/agent-layer/internal/sync"
 )
@@ -37,12 +38,12 @@ func applyChanges(root, configPath, envPath string, c *Choices, runSync syncer,
 	configBackupPath := configPath + ".bak"
 	configBackupCreated, err := writeBackup(configBackupPath, rawConfig, configPerm)
 	if err != nil {
-		return fmt.Errorf(messages.WizardBackupConfigFailedFmt, err)
+		return i18n.Errorf(messages.WizardBackupConfigFailedFmt, err)
 	}
 	// Patch
-	newConfig, err := PatchConfig(string(rawConfig), c)
+	newConfig, err := RoundTrip(string(rawConfig), c)
 	if err != nil {
-		return fmt.Errorf(messages.WizardPatchConfigFailedFmt, err)
+		return i18n.Errorf(messages.WizardPatchConfigFailedFmt, err)
 	}
 
 	// Env
@@ -61,7 +62,7 @@ func applyChanges(root, configPath, envPath string, c *Choices, runSync syncer,
 			if configBackupCreated {
 				_ = os.Remove(configBackupPath)
 			}
-			return fmt.Errorf(messages.WizardBackupEnvFailedFmt, err)
+			return i18n.Errorf(messages.WizardBackupEnvFailedFmt, err)
 		}
 	} else if !os.IsNotExist(err) {
 		if configBackupCreated {
@@ -71,24 +72,24 @@ func applyChanges(root, configPath, envPath string, c *Choices, runSync syncer,
 	}
 	// Patch
 	if err := writeFileAtomic(configPath, []byte(newConfig), configPerm); err != nil {
-		return fmt.Errorf(messages.WizardWriteConfigFailedFmt, err)
+		return i18n.Errorf(messages.WizardWriteConfigFailedFmt, err)
 	}
 	newEnv := envfile.Patch(string(rawEnv), c.Secrets)
 	if err := writeFileAtomic(envPath, []byte(newEnv), envPerm); err != nil {
 		if rollbackErr := writeFileAtomic(configPath, rawConfig, configPerm); rollbackErr != nil {
-			return fmt.Errorf(messages.WizardWriteEnvRollbackConfigFailedFmt, err, rollbackErr)
+			return i18n.Errorf(messages.WizardWriteEnvRollbackConfigFailedFmt, err, rollbackErr)
 		}
-		return fmt.Errorf(messages.WizardWriteEnvFailedFmt, err)
+		return i18n.Errorf(messages.WizardWriteEnvFailedFmt, err)
 	}
 
 	// Skills (catalog adds/removes, workflow bundle missing-file install).
 	// Run before sync so sync sees the final on-disk layout.
 	skillsChangeSet, err := computeSkillsChangeSet(root, c)
 	if err != nil {
-		return fmt.Errorf(messages.WizardApplySkillsFailedFmt, err)
+		return i18n.Errorf(messages.WizardApplySkillsFailedFmt, err)
 	}
 	if err := applySkillsChanges(root, skillsChangeSet); err != nil {
-		return fmt.Errorf(messages.WizardApplySkillsFailedFmt, err)
+		return i18n.Errorf(messages.WizardApplySkillsFailedFmt, err)
 	}
 
 	gitignoreChangeSet, err := computeGitignoreBlockChangeSet(root, c)
@@ -108,7 +109,7 @@ func applyChanges(root, configPath, envPath string, c *Choices, runSync syncer,
 	}
 
 	// Sync
-	_, _ = fmt.Fprintln(out, messages.WizardRunningSync)
+	_, _ = fmt.Fprintln(out, i18n.T(messages.WizardRunningSync))
 	result, err := runSync(root)
 	if err != nil {
 		return err
@@ -10,6 +10,7 @@ import (
 	"strings"
 
 	"github.com/conn-castle/agent-layer/internal/fsutil"
+	"github.com/conn-castle/agent-layer/internal/layerdir"
 	"github.com/conn-castle/agent-layer/internal/templates"
 )
 
@@ -82,7 +83,7 @@ func computeSkillsChangeSet(root string, choices *Choices) (skillsChangeSet, err
 			var err error
 			missingFiles, err = templateDirHasMissingFiles(
 				cliSkillsCatalogTemplateRoot+"/"+entry.ID,
-				filepath.Join(root, ".agent-layer", "skills", entry.ID),
+				filepath.Join(layerdir.Dir(root), "skills", entry.ID),
 			)
 			if err != nil {
 				return skillsChangeSet{}, err
@@ -104,7 +105,7 @@ func computeSkillsChangeSet(root string, choices *Choices) (skillsChangeSet, err
 			return skillsChangeSet{}, err
 		}
 		for id := range workflowIDs {
-			missing, err := templateDirHasMissingFiles("skills/"+id, filepath.Join(root, ".agent-layer", "skills", id))
+			missing, err := templateDirHasMissingFiles("skills/"+id, filepath.Join(layerdir.Dir(root), "skills", id))
 			if err != nil {
 				return skillsChangeSet{}, err
 			}
@@ -116,7 +117,7 @@ func computeSkillsChangeSet(root string, choices *Choices) (skillsChangeSet, err
 		if err != nil {
 			return skillsChangeSet{}, err
 		}
-		templateMemoryAdds, err := listMissingMemoryFiles(root, filepath.Join(root, ".agent-layer", "templates", "docs"))
+		templateMemoryAdds, err := listMissingMemoryFiles(root, filepath.Join(layerdir.Dir(root), "templates", "docs"))
 		if err != nil {
 			return skillsChangeSet{}, err
 		}
@@ -165,13 +166,13 @@ func applySkillsChanges(root string, changes skillsChangeSet) error {
 		}
 	}
 	for _, id := range changes.catalogSkillsToRemove {
-		dir := filepath.Join(root, ".agent-layer", "skills", id)
+		dir := filepath.Join(layerdir.Dir(root), "skills", id)
 		if err := os.RemoveAll(dir); err != nil {
 			return fmt.Errorf("remove catalog skill %s: %w", id, err)
 		}
 	}
 	for _, id := range changes.workflowSkillsToInstall {
-		if err := copyTemplateDirMissingWithMode("skills/"+id, filepath.Join(root, ".agent-layer", "skills", id), 0o600); err != nil {
+		if err := copyTemplateDirMissingWithMode("skills/"+id, filepath.Join(layerdir.Dir(root), "skills", id), 0o600); err != nil {
 			return fmt.Errorf("install workflow skill %s: %w", id, err)
 		}
 	}
@@ -181,7 +182,7 @@ func applySkillsChanges(root string, changes skillsChangeSet) error {
 		}
 	}
 	if len(changes.templateMemoryFilesToCreate) > 0 {
-		if err := copyTemplateDirMissing("docs/agent-layer", filepath.Join(root, ".agent-layer", "templates", "docs")); err != nil {
+		if err := copyTemplateDirMissing("docs/agent-layer", filepath.Join(layerdir.Dir(root), "templates", "docs")); err != nil {
 			return fmt.Errorf("create memory templates: %w", err)
 		}
 	}
@@ -207,7 +208,7 @@ func copyCatalogSkillToDisk(root string, id string) error {
 		return fmt.Errorf("invalid catalog skill id %q", id)
 	}
 	templateRoot := cliSkillsCatalogTemplateRoot + "/" + id
-	destRoot := filepath.Join(root, ".agent-layer", "skills", id)
+	destRoot := filepath.Join(layerdir.Dir(root), "skills", id)
 	wrote := false
 	err := templates.Walk(templateRoot, func(path string, entry fs.DirEntry, walkErr error) error {
 		if walkErr != nil {
@@ -248,7 +249,7 @@ func copyCatalogSkillMissingFiles(root string, id string) error {
 	}
 	return copyTemplateDirMissingWithMode(
 		cliSkillsCatalogTemplateRoot+"/"+id,
-		filepath.Join(root, ".agent-layer", "skills", id),
+		filepath.Join(layerdir.Dir(root), "skills", id),
 		0o600,
 	)
 }
@@ -439,7 +440,7 @@ func listMissingUserOwnedInstructionFiles(root string) ([]string, error) {
 		if !isUserOwnedStandardInstructionFile(name) {
 			continue
 		}
-		path := filepath.Join(root, ".agent-layer", "instructions", name)
+		path := filepath.Join(layerdir.Dir(root), "instructions", name)
 		exists, err := regularFileExists(path)
 		if err != nil {
 			return nil, err
@@ -10,6 +10,7 @@ import (
 
 	"github.com/conn-castle/agent-layer/internal/fsutil"
 	"github.com/conn-castle/agent-layer/internal/install"
+	"github.com/conn-castle/agent-layer/internal/layerdir"
 	"github.com/conn-castle/agent-layer/internal/templates"
 )
 
@@ -67,7 +68,7 @@ func applyGitignoreBlockChanges(root string, changes gitignoreBlockChangeSet) er
 	if changes.nextContent == "" {
 		return nil
 	}
-	path := filepath.Join(root, filepath.FromSlash(gitignoreBlockRelPath))
+	path := layerdir.Path(root, gitignoreBlockRelPath)
 	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
 		return err
 	}
@@ -92,7 +93,7 @@ func buildGitignoreBlockPreview(changes gitignoreBlockChangeSet) string {
 // gitignoreBlockSourceContent reads the managed gitignore block source. When it
 // is missing, it returns the embedded template content and exists=false.
 func gitignoreBlockSourceContent(root string) (string, bool, error) {
-	path := filepath.Join(root, filepath.FromSlash(gitignoreBlockRelPath))
+	path := layerdir.Path(root, gitignoreBlockRelPath)
 	exists := true
 	data, err := os.ReadFile(path) // #nosec G304 -- path is the caller-resolved managed gitignore source used by the wizard.
 	if err != nil {
@@ -1,12 +1,12 @@
 package wizard
 
 import (
-	"fmt"
 	"regexp"
 	"strings"
 
 	toml "github.com/pelletier/go-toml/v2"
 
+	"github.com/conn-castle/agent-layer/internal/i18n"
 	"github.com/conn-castle/agent-layer/internal/messages"
 	"github.com/conn-castle/agent-layer/internal/templates"
 )
@@ -36,36 +36,36 @@ func isSafeCLISkillCatalogID(id string) bool {
 func loadCLISkillCatalog() ([]CLISkillCatalogEntry, error) {
 	data, err := templates.Read(cliSkillsCatalogTemplatePath)
 	if err != nil {
-		return nil, fmt.Errorf(messages.WizardLoadCLISkillsCatalogFailedFmt, err)
+		return nil, i18n.Errorf(messages.WizardLoadCLISkillsCatalogFailedFmt, err)
 	}
 	var doc struct {
 		CLISkills []CLISkillCatalogEntry `toml:"cli_skills"`
 	}
 	if err := toml.Unmarshal(data, &doc); err != nil {
-		return nil, fmt.Errorf(messages.WizardLoadCLISkillsCatalogFailedFmt, err)
+		return nil, i18n.Errorf(messages.WizardLoadCLISkillsCatalogFailedFmt, err)
 	}
 	if len(doc.CLISkills) == 0 {
-		return nil, fmt.Errorf(messages.WizardCatalogNoCLISkills)
+		return nil, i18n.Errorf(messages.WizardCatalogNoCLISkills)
 	}
 	seen := make(map[string]struct{}, len(doc.CLISkills))
 	seenNames := make(map[string]struct{}, len(doc.CLISkills))
 	for idx, entry := range doc.CLISkills {
 		if entry.ID == "" {
-			return nil, fmt.Errorf(messages.WizardCLISkillCatalogEntryMissingIDFmt, idx)
+			return nil, i18n.Errorf(messages.WizardCLISkillCatalogEntryMissingIDFmt, idx)
 		}
 		if !isSafeCLISkillCatalogID(entry.ID) {
-			return nil, fmt.Errorf(messages.WizardCLISkillCatalogEntryInvalidIDFmt, idx, entry.ID)
+			return nil, i18n.Errorf(messages.WizardCLISkillCatalogEntryInvalidIDFmt, idx, entry.ID)
 		}
 		if _, ok := seen[entry.ID]; ok {
-			return nil, fmt.Errorf(messages.WizardCLISkillCatalogEntryDuplicateIDFmt, idx, entry.ID)
+			return nil, i18n.Errorf(messages.WizardCLISkillCatalogEntryDuplicateIDFmt, idx, entry.ID)
 		}
 		seen[entry.ID] = struct{}{}
 		name := strings.TrimSpace(entry.Name)
 		if name == "" {
-			return nil, fmt.Errorf(messages.WizardCLISkillCatalogEntryMissingNameFmt, entry.ID)
+			return nil, i18n.Errorf(messages.WizardCLISkillCatalogEntryMissingNameFmt, entry.ID)
 		}
 		if _, ok := seenNames[name]; ok {
-			return nil, fmt.Errorf(messages.WizardCLISkillCatalogEntryDuplicateNameFmt, idx, name)
+			return nil, i18n.Errorf(messages.WizardCLISkillCatalogEntryDuplicateNameFmt, idx, name)
 		}
 		seenNames[name] = struct{}{}
 	}
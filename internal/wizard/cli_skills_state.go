@@ -6,6 +6,7 @@ import (
 	"path/filepath"
 
 	"github.com/conn-castle/agent-layer/internal/install"
+	"github.com/conn-castle/agent-layer/internal/layerdir"
 )
 
 // catalogSkillExistsOnDisk reports whether a CLI catalog skill directory is
@@ -15,7 +16,7 @@ func catalogSkillExistsOnDisk(root string, id string) bool {
 	if root == "" || !isSafeCLISkillCatalogID(id) {
 		return false
 	}
-	dir := filepath.Join(root, ".agent-layer", "skills", id)
+	dir := filepath.Join(layerdir.Dir(root), "skills", id)
 	info, err := os.Stat(dir)
 	if err != nil {
 		return false
@@ -52,7 +53,7 @@ func detectAgentLayerEnabledFromDisk(root string) bool {
 }
 
 func hasNonCatalogWorkflowSkill(root string) bool {
-	skillsDir := filepath.Join(root, ".agent-layer", "skills")
+	skillsDir := filepath.Join(layerdir.Dir(root), "skills")
 	entries, err := os.ReadDir(skillsDir)
 	if err != nil {
 		if errors.Is(err, os.ErrNotExist) {
@@ -89,7 +90,7 @@ func hasAnyMemoryFile(root string) bool {
 
 func hasAnyTemplateMemoryFile(root string) bool {
 	for _, name := range memoryFileBasenames {
-		path := filepath.Join(root, ".agent-layer", "templates", "docs", name)
+		path := filepath.Join(layerdir.Dir(root), "templates", "docs", name)
 		if _, err := os.Stat(path); !errors.Is(err, os.ErrNotExist) {
 			return true
 		}
@@ -99,7 +100,7 @@ func hasAnyTemplateMemoryFile(root string) bool {
 
 func hasAnyStandardInstructionFile(root string) bool {
 	for _, name := range standardInstructionBasenames {
-		path := filepath.Join(root, ".agent-layer", "instructions", name)
+		path := filepath.Join(layerdir.Dir(root), "instructions", name)
 		if _, err := os.Stat(path); !errors.Is(err, os.ErrNotExist) {
 			return true
 		}
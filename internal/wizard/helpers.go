@@ -6,6 +6,7 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/conn-castle/agent-layer/internal/i18n"
 	"github.com/conn-castle/agent-layer/internal/messages"
 )
 
@@ -14,7 +15,7 @@ import (
 // Assumes c.DefaultMCPServers has been populated (see wizard.Run).
 func buildSummary(c *Choices) string {
 	var sb strings.Builder
-	fmt.Fprintf(&sb, messages.WizardSummaryApprovalsFmt, c.ApprovalMode)
+	fmt.Fprintf(&sb, i18n.T(messages.WizardSummaryApprovalsFmt), c.ApprovalMode)
 
 	agents := agentSummaryLines(c)
 	sort.Strings(agents)
@@ -90,7 +91,7 @@ func buildSummary(c *Choices) string {
 		sb.WriteString(messages.WizardSummaryNoneLoaded)
 	case len(mcp) > 0:
 		for _, m := range mcp {
-			fmt.Fprintf(&sb, messages.WizardSummaryListItemFmt, m)
+			fmt.Fprintf(&sb, i18n.T(messages.WizardSummaryListItemFmt), m)
 		}
 	default:
 		sb.WriteString(messages.WizardSummaryNone)
@@ -103,7 +104,7 @@ func buildSummary(c *Choices) string {
 		sb.WriteString(messages.WizardSummaryNoneLoaded)
 	case len(disabledMCP) > 0:
 		for _, m := range disabledMCP {
-			fmt.Fprintf(&sb, messages.WizardSummaryListItemFmt, m)
+			fmt.Fprintf(&sb, i18n.T(messages.WizardSummaryListItemFmt), m)
 		}
 	default:
 		sb.WriteString(messages.WizardSummaryNone)
@@ -113,7 +114,7 @@ func buildSummary(c *Choices) string {
 	if len(disabledCustomMCP) > 0 {
 		sb.WriteString(messages.WizardSummaryDisabledCustomMCPServersHeader)
 		for _, m := range disabledCustomMCP {
-			fmt.Fprintf(&sb, messages.WizardSummaryListItemFmt, m)
+			fmt.Fprintf(&sb, i18n.T(messages.WizardSummaryListItemFmt), m)
 		}
 	}
 
@@ -125,7 +126,7 @@ func buildSummary(c *Choices) string {
 		}
 		sort.Strings(secretKeys)
 		for _, k := range secretKeys {
-			fmt.Fprintf(&sb, messages.WizardSummaryListItemFmt, k)
+			fmt.Fprintf(&sb, i18n.T(messages.WizardSummaryListItemFmt), k)
 		}
 	} else {
 		sb.WriteString(messages.WizardSummaryNone)
@@ -136,22 +137,22 @@ func buildSummary(c *Choices) string {
 		sb.WriteString(messages.WizardSummaryWarningsDisabled)
 		return sb.String()
 	}
-	fmt.Fprintf(&sb, messages.WizardSummaryWarningInstructionTokenFmt, c.InstructionTokenThreshold)
-	fmt.Fprintf(&sb, messages.WizardSummaryWarningMCPServerFmt, c.MCPServerThreshold)
-	fmt.Fprintf(&sb, messages.WizardSummaryWarningMCPToolsTotalFmt, c.MCPToolsTotalThreshold)
-	fmt.Fprintf(&sb, messages.WizardSummaryWarningMCPServerToolsFmt, c.MCPServerToolsThreshold)
-	fmt.Fprintf(&sb, messages.WizardSummaryWarningMCPSchemaTokensTotalFmt, c.MCPSchemaTokensTotalThreshold)
-	fmt.Fprintf(&sb, messages.WizardSummaryWarningMCPSchemaTokensServerFmt, c.MCPSchemaTokensServerThreshold)
+	fmt.Fprintf(&sb, i18n.T(messages.WizardSummaryWarningInstructionTokenFmt), c.InstructionTokenThreshold)
+	fmt.Fprintf(&sb, i18n.T(messages.WizardSummaryWarningMCPServerFmt), c.MCPServerThreshold)
+	fmt.Fprintf(&sb, i18n.T(messages.WizardSummaryWarningMCPToolsTotalFmt), c.MCPToolsTotalThreshold)
+	fmt.Fprintf(&sb, i18n.T(messages.WizardSummaryWarningMCPServerToolsFmt), c.MCPServerToolsThreshold)
+	fmt.Fprintf(&sb, i18n.T(messages.WizardSummaryWarningMCPSchemaTokensTotalFmt), c.MCPSchemaTokensTotalThreshold)
+	fmt.Fprintf(&sb, i18n.T(messages.WizardSummaryWarningMCPSchemaTokensServerFmt), c.MCPSchemaTokensServerThreshold)
 
 	return sb.String()
 }
 
 func writeGitTrackingSummaryLine(sb *strings.Builder, label string, tracked bool) {
 	if tracked {
-		fmt.Fprintf(sb, messages.WizardSummaryGitTrackedFmt, label)
+		fmt.Fprintf(sb, i18n.T(messages.WizardSummaryGitTrackedFmt), label)
 		return
 	}
-	fmt.Fprintf(sb, messages.WizardSummaryGitIgnoredFmt, label)
+	fmt.Fprintf(sb, i18n.T(messages.WizardSummaryGitIgnoredFmt), label)
 }
 
 // codexRuntimeToggleVisible reports whether a Codex runtime feature toggle
@@ -244,12 +245,12 @@ func selectOptionalValue(ui UI, title string, options []string, value *string) e
 	}
 	if selection == messages.WizardCustomOption {
 		customValue := *value
-		if err := ui.Input(fmt.Sprintf(messages.WizardCustomPromptFmt, title), &customValue); err != nil {
+		if err := ui.Input(i18n.Sprintf(messages.WizardCustomPromptFmt, title), &customValue); err != nil {
 			return err
 		}
 		customValue = strings.TrimSpace(customValue)
 		if customValue == "" {
-			return fmt.Errorf(messages.WizardCustomValueRequiredFmt, title)
+			return i18n.Errorf(messages.WizardCustomValueRequiredFmt, title)
 		}
 		*value = customValue
 		return nil
@@ -322,7 +323,7 @@ func promptPositiveInt(ui UI, title string, value *int) error {
 	}
 	parsed, err := strconv.Atoi(input)
 	if err != nil || parsed <= 0 {
-		return fmt.Errorf(messages.WizardPositiveIntRequiredFmt, title)
+		return i18n.Errorf(messages.WizardPositiveIntRequiredFmt, title)
 	}
 	*value = parsed
 	return nil
@@ -338,10 +339,10 @@ func agentSummaryLines(c *Choices) []string {
 		}
 		modelSummary := agentModelSummary(agent, c)
 		if modelSummary == "" {
-			agents = append(agents, fmt.Sprintf(messages.WizardSummaryAgentFmt, agent))
+			agents = append(agents, i18n.Sprintf(messages.WizardSummaryAgentFmt, agent))
 			continue
 		}
-		agents = append(agents, fmt.Sprintf(messages.WizardSummaryAgentModelFmt, agent, modelSummary))
+		agents = append(agents, i18n.Sprintf(messages.WizardSummaryAgentModelFmt, agent, modelSummary))
 	}
 	return agents
 }
@@ -365,13 +366,13 @@ func agentModelSummary(agent string, c *Choices) string {
 
 func claudeModelSummary(c *Choices) string {
 	if c.ClaudeModel != "" && c.ClaudeReasoning != "" {
-		return fmt.Sprintf(messages.WizardSummaryModelReasoningFmt, c.ClaudeModel, c.ClaudeReasoning)
+		return i18n.Sprintf(messages.WizardSummaryModelReasoningFmt, c.ClaudeModel, c.ClaudeReasoning)
 	}
 	if c.ClaudeModel != "" {
 		return c.ClaudeModel
 	}
 	if c.ClaudeReasoning != "" {
-		return fmt.Sprintf(messages.WizardSummaryReasoningFmt, c.ClaudeReasoning)
+		return i18n.Sprintf(messages.WizardSummaryReasoningFmt, c.ClaudeReasoning)
 	}
 	return ""
 }
@@ -380,13 +381,13 @@ func claudeModelSummary(c *Choices) string {
 // c holds wizard choices; returns the summary text.
 func codexModelSummary(c *Choices) string {
 	if c.CodexModel != "" && c.CodexReasoning != "" {
-		return fmt.Sprintf(messages.WizardSummaryModelReasoningFmt, c.CodexModel, c.CodexReasoning)
+		return i18n.Sprintf(messages.WizardSummaryModelReasoningFmt, c.CodexModel, c.CodexReasoning)
 	}
 	if c.CodexModel != "" {
 		return c.CodexModel
 	}
 	if c.CodexReasoning != "" {
-		return fmt.Sprintf(messages.WizardSummaryReasoningFmt, c.CodexReasoning)
+		return i18n.Sprintf(messages.WizardSummaryReasoningFmt, c.CodexReasoning)
 	}
 	return ""
 }
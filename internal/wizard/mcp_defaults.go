@@ -1,11 +1,10 @@
 package wizard
 
 import (
-	"fmt"
-
 	toml "github.com/pelletier/go-toml/v2"
 
 	"github.com/conn-castle/agent-layer/internal/config"
+	"github.com/conn-castle/agent-layer/internal/i18n"
 	"github.com/conn-castle/agent-layer/internal/messages"
 	"github.com/conn-castle/agent-layer/internal/templates"
 )
@@ -37,7 +36,7 @@ func loadDefaultMCPServers() ([]DefaultMCPServer, error) {
 		})
 	}
 	if len(defaults) == 0 {
-		return nil, fmt.Errorf(messages.WizardCatalogNoMCPServers)
+		return nil, i18n.Errorf(messages.WizardCatalogNoMCPServers)
 	}
 	return defaults, nil
 }
@@ -47,7 +46,7 @@ func loadDefaultMCPServers() ([]DefaultMCPServer, error) {
 func loadCatalogMCPServers() ([]config.MCPServer, error) {
 	data, err := templates.Read(catalogTemplatePath)
 	if err != nil {
-		return nil, fmt.Errorf(messages.WizardLoadMCPCatalogFailedFmt, err)
+		return nil, i18n.Errorf(messages.WizardLoadMCPCatalogFailedFmt, err)
 	}
 	var doc struct {
 		MCP struct {
@@ -55,7 +54,7 @@ func loadCatalogMCPServers() ([]config.MCPServer, error) {
 		} `toml:"mcp"`
 	}
 	if err := toml.Unmarshal(data, &doc); err != nil {
-		return nil, fmt.Errorf(messages.WizardLoadMCPCatalogFailedFmt, err)
+		return nil, i18n.Errorf(messages.WizardLoadMCPCatalogFailedFmt, err)
 	}
 	return doc.MCP.Servers, nil
 }
@@ -66,7 +65,7 @@ func loadCatalogMCPServers() ([]config.MCPServer, error) {
 func loadCatalogDocument() (tomlDocument, error) {
 	data, err := templates.Read(catalogTemplatePath)
 	if err != nil {
-		return tomlDocument{}, fmt.Errorf(messages.WizardLoadMCPCatalogFailedFmt, err)
+		return tomlDocument{}, i18n.Errorf(messages.WizardLoadMCPCatalogFailedFmt, err)
 	}
 	return parseTomlDocument(string(data)), nil
 }
@@ -0,0 +1,194 @@
+package wizard
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+
+	toml "github.com/pelletier/go-toml/v2"
+
+	"github.com/conn-castle/agent-layer/internal/config"
+	"github.com/conn-castle/agent-layer/internal/i18n"
+	"github.com/conn-castle/agent-layer/internal/messages"
+	"github.com/conn-castle/agent-layer/internal/templates"
+)
+
+// MCPRegistryVersion is the [registry] schema version this build reads.
+const MCPRegistryVersion = 1
+
+// MCPRegistryEntry describes one MCP server in the registry section of the
+// embedded catalog. The matching [[mcp.servers]] block is the recommended
+// config; the entry records what that block pins and needs.
+type MCPRegistryEntry struct {
+	ID          string
+	Description string
+	// Package and Version name the pinned npm or PyPI package a stdio server
+	// runs; both are empty for remote servers.
+	Package     string
+	Version     string
+	RequiredEnv []string
+	// Platforms overrides the block's command and args per GOOS.
+	Platforms map[string]MCPPlatformVariant
+}
+
+// MCPPlatformVariant replaces a server's command and args on one platform.
+// Empty fields keep the block's value.
+type MCPPlatformVariant struct {
+	Command string   `toml:"command"`
+	Args    []string `toml:"args"`
+}
+
+type registryServer struct {
+	Description string                        `toml:"description"`
+	Package     string                        `toml:"package"`
+	Version     string                        `toml:"version"`
+	RequiredEnv []string                      `toml:"required_env"`
+	Platforms   map[string]MCPPlatformVariant `toml:"platforms"`
+}
+
+// LoadMCPRegistry returns the registry entries in catalog order. Errors when
+// the registry version is not MCPRegistryVersion or when registry entries and
+// catalog server blocks do not match one to one.
+func LoadMCPRegistry() ([]MCPRegistryEntry, error) {
+	data, err := templates.Read(catalogTemplatePath)
+	if err != nil {
+		return nil, i18n.Errorf(messages.WizardLoadMCPCatalogFailedFmt, err)
+	}
+	var doc struct {
+		Registry struct {
+			Version int                       `toml:"version"`
+			Servers map[string]registryServer `toml:"servers"`
+		} `toml:"registry"`
+		MCP struct {
+			Servers []config.MCPServer `toml:"servers"`
+		} `toml:"mcp"`
+	}
+	if err := toml.Unmarshal(data, &doc); err != nil {
+		return nil, i18n.Errorf(messages.WizardLoadMCPCatalogFailedFmt, err)
+	}
+	if doc.Registry.Version != MCPRegistryVersion {
+		return nil, i18n.Errorf(messages.WizardMCPRegistryVersionFmt, doc.Registry.Version, MCPRegistryVersion)
+	}
+	entries := make([]MCPRegistryEntry, 0, len(doc.MCP.Servers))
+	for _, server := range doc.MCP.Servers {
+		meta, ok := doc.Registry.Servers[server.ID]
+		if !ok {
+			return nil, i18n.Errorf(messages.WizardMCPRegistryEntryMissingFmt, server.ID)
+		}
+		entries = append(entries, MCPRegistryEntry{
+			ID:          server.ID,
+			Description: meta.Description,
+			Package:     meta.Package,
+			Version:     meta.Version,
+			RequiredEnv: meta.RequiredEnv,
+			Platforms:   meta.Platforms,
+		})
+	}
+	if len(doc.Registry.Servers) != len(entries) {
+		for id := range doc.Registry.Servers {
+			if !slices.ContainsFunc(entries, func(entry MCPRegistryEntry) bool { return entry.ID == id }) {
+				return nil, i18n.Errorf(messages.WizardMCPRegistryBlockMissingFmt, id)
+			}
+		}
+	}
+	return entries, nil
+}
+
+// AddMCPServer inserts the registry's recommended [[mcp.servers]] block for id
+// into config content, enabled, after the config's last mcp table. goos
+// selects a platform variant. Nothing else in content changes. Errors when id
+// is not in the registry or the config already defines a server with that id.
+func AddMCPServer(content string, id string, goos string) (string, MCPRegistryEntry, error) {
+	var current struct {
+		MCP struct {
+			Servers []config.MCPServer `toml:"servers"`
+		} `toml:"mcp"`
+	}
+	if err := toml.Unmarshal([]byte(content), &current); err != nil {
+		return "", MCPRegistryEntry{}, i18n.Errorf(messages.WizardParseConfigFailedFmt, err)
+	}
+	for _, server := range current.MCP.Servers {
+		if server.ID == id {
+			return "", MCPRegistryEntry{}, i18n.Errorf(messages.WizardMCPServerExistsFmt, id)
+		}
+	}
+
+	entries, err := LoadMCPRegistry()
+	if err != nil {
+		return "", MCPRegistryEntry{}, err
+	}
+	idx := slices.IndexFunc(entries, func(entry MCPRegistryEntry) bool { return entry.ID == id })
+	if idx < 0 {
+		ids := make([]string, 0, len(entries))
+		for _, entry := range entries {
+			ids = append(ids, entry.ID)
+		}
+		return "", MCPRegistryEntry{}, i18n.Errorf(messages.WizardMCPRegistryUnknownIDFmt, id, strings.Join(ids, ", "))
+	}
+	entry := entries[idx]
+
+	catalogDoc, err := loadCatalogDocument()
+	if err != nil {
+		return "", MCPRegistryEntry{}, err
+	}
+	var block *tomlBlock
+	for _, candidate := range parseMCPBlocks(catalogDoc.arrays[mcpServersSection]) {
+		if candidate.id == id {
+			block = &tomlBlock{name: mcpServersSection, leading: cloneLines(candidate.leading), lines: cloneLines(candidate.lines)}
+			break
+		}
+	}
+	if block == nil {
+		return "", MCPRegistryEntry{}, i18n.Errorf(messages.WizardMissingDefaultMCPServerTemplateFmt, id)
+	}
+	setKeyValue(block, nil, "enabled", formatTomlValue(true), "id")
+	if variant, ok := entry.Platforms[goos]; ok {
+		if variant.Command != "" {
+			setKeyValue(block, nil, "command", formatTomlValue(variant.Command), "transport")
+		}
+		if variant.Args != nil {
+			setKeyValue(block, nil, "args", formatStringArray(variant.Args), "command")
+		}
+	}
+	lines := trimTrailingEmptyLines(block.rendered())
+
+	segments := splitConfigSegments(content)
+	last := -1
+	for i, segment := range segments {
+		if i > 0 && (segment.name == "mcp" || strings.HasPrefix(segment.name, "mcp.")) {
+			last = i
+		}
+	}
+	if last < 0 {
+		last = len(segments) - 1
+	}
+	end := len(trimTrailingEmptyLines(segments[last].lines))
+	insert := lines
+	if end > 0 {
+		insert = append([]string{""}, insert...)
+	}
+	rest := segments[last].lines[end:]
+	if len(rest) == 0 || strings.TrimSpace(rest[0]) != "" {
+		insert = append(insert, "")
+	}
+	segments[last].lines = append(append(cloneLines(segments[last].lines[:end]), insert...), rest...)
+
+	result := joinConfigSegments(segments)
+	if !strings.HasSuffix(result, "\n") {
+		result += "\n"
+	}
+	var check map[string]any
+	if err := toml.Unmarshal([]byte(result), &check); err != nil {
+		return "", MCPRegistryEntry{}, i18n.Errorf(messages.WizardRenderConfigFailedFmt, err)
+	}
+	return result, entry, nil
+}
+
+// formatStringArray renders values as a single-line TOML string array.
+func formatStringArray(values []string) string {
+	quoted := make([]string, 0, len(values))
+	for _, value := range values {
+		quoted = append(quoted, strconv.Quote(value))
+	}
+	return "[" + strings.Join(quoted, ", ") + "]"
+}
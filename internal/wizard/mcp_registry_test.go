@@ -0,0 +1,134 @@
+package wizard
+
+import (
+	"strings"
+	"testing"
+
+	toml "github.com/pelletier/go-toml/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/conn-castle/agent-layer/internal/config"
+	"github.com/conn-castle/agent-layer/internal/templates"
+)
+
+func TestLoadMCPRegistry_MatchesCatalogBlocks(t *testing.T) {
+	entries, err := LoadMCPRegistry()
+	require.NoError(t, err)
+	servers, err := loadCatalogMCPServers()
+	require.NoError(t, err)
+	require.Len(t, entries, len(servers))
+
+	for i, server := range servers {
+		entry := entries[i]
+		assert.Equal(t, server.ID, entry.ID)
+		assert.NotEmpty(t, entry.Description, entry.ID)
+		assert.ElementsMatch(t, config.RequiredEnvVarsForMCPServer(server), entry.RequiredEnv, "required_env for %s", entry.ID)
+		if server.Transport != "stdio" {
+			assert.Empty(t, entry.Package, entry.ID)
+			continue
+		}
+		require.NotEmpty(t, entry.Version, entry.ID)
+		args := strings.Join(server.Args, " ")
+		assert.True(t,
+			strings.Contains(args, entry.Package+"@"+entry.Version) || strings.Contains(args, entry.Package+"=="+entry.Version),
+			"%s args %q do not pin %s %s", entry.ID, args, entry.Package, entry.Version)
+	}
+}
+
+func TestLoadMCPRegistry_Mismatch(t *testing.T) {
+	cases := map[string]string{
+		"version":       "[registry]\nversion = 2\n",
+		"missing entry": "[registry]\nversion = 1\n\n[[mcp.servers]]\nid = \"a\"\n",
+		"missing block": "[registry]\nversion = 1\n\n[registry.servers.a]\ndescription = \"A\"\n",
+	}
+	for name, catalog := range cases {
+		t.Run(name, func(t *testing.T) {
+			stubCatalog(t, catalog)
+			_, err := LoadMCPRegistry()
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestAddMCPServer(t *testing.T) {
+	content := `[approvals]
+mode = "all"
+
+[mcp]
+
+[[mcp.servers]]
+id = "custom"
+enabled = true
+transport = "http"
+url = "https://example.com/mcp"
+
+[warnings]
+noise_mode = "default"
+`
+	result, entry, err := AddMCPServer(content, "fetch", "linux")
+	require.NoError(t, err)
+	assert.Equal(t, "fetch", entry.ID)
+
+	head, tail, ok := strings.Cut(content, "[warnings]")
+	require.True(t, ok)
+	assert.True(t, strings.HasPrefix(result, head[:len(head)-1]), result)
+	assert.True(t, strings.HasSuffix(result, "\n\n[warnings]"+tail), result)
+	assert.Contains(t, result, "url = \"https://example.com/mcp\"\n\n[[mcp.servers]]\n# Fetch URL contents (requires `uvx`).\nid = \"fetch\"\nenabled = true\n")
+
+	var parsed struct {
+		MCP struct {
+			Servers []config.MCPServer `toml:"servers"`
+		} `toml:"mcp"`
+	}
+	require.NoError(t, toml.Unmarshal([]byte(result), &parsed))
+	require.Len(t, parsed.MCP.Servers, 2)
+	assert.Equal(t, []string{"mcp-server-fetch==2025.4.7"}, parsed.MCP.Servers[1].Args)
+
+	_, _, err = AddMCPServer(result, "fetch", "linux")
+	assert.ErrorContains(t, err, "already defines")
+	_, _, err = AddMCPServer(content, "nope", "linux")
+	assert.ErrorContains(t, err, "available: context7, tavily, fetch, playwright")
+}
+
+func TestAddMCPServer_WithoutMCPSectionAndPlatformVariant(t *testing.T) {
+	stubCatalog(t, `[registry]
+version = 1
+
+[registry.servers.tool]
+description = "Tool"
+package = "tool"
+version = "1.0.0"
+required_env = []
+
+[registry.servers.tool.platforms.darwin]
+command = "tool-mac"
+args = ["--pinned", "tool@1.0.0"]
+
+[[mcp.servers]]
+id = "tool"
+enabled = false
+transport = "stdio"
+command = "npx"
+args = ["-y", "tool@1.0.0"]
+`)
+	result, _, err := AddMCPServer("[approvals]\nmode = \"all\"", "tool", "darwin")
+	require.NoError(t, err)
+	assert.Equal(t, "[approvals]\nmode = \"all\"\n\n[[mcp.servers]]\nid = \"tool\"\nenabled = true\ntransport = \"stdio\"\ncommand = \"tool-mac\"\nargs = [\"--pinned\", \"tool@1.0.0\"]\n", result)
+
+	result, _, err = AddMCPServer("", "tool", "linux")
+	require.NoError(t, err)
+	assert.Contains(t, result, "command = \"npx\"\nargs = [\"-y\", \"tool@1.0.0\"]\n")
+}
+
+func stubCatalog(t *testing.T, catalog string) {
+	t.Helper()
+	original := templates.ReadFunc
+	templates.ReadFunc = func(path string) ([]byte, error) {
+		if path == catalogTemplatePath {
+			return []byte(catalog), nil
+		}
+		return original(path)
+	}
+	t.Cleanup(func() { templates.ReadFunc = original })
+}
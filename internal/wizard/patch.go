@@ -18,7 +18,6 @@
 package wizard
 
 import (
-	"fmt"
 	"slices"
 	"sort"
 	"strings"
@@ -26,14 +25,25 @@ import (
 	"github.com/pelletier/go-toml/v2"
 
 	"github.com/conn-castle/agent-layer/internal/config"
+	"github.com/conn-castle/agent-layer/internal/i18n"
 	"github.com/conn-castle/agent-layer/internal/messages"
 	"github.com/conn-castle/agent-layer/internal/templates"
 	"github.com/conn-castle/agent-layer/internal/tomlpatch"
 )
 
 type tomlBlock struct {
-	name  string
-	lines []string
+	name string
+	// leading is the comment paragraph above the header; see tomlpatch.Block.
+	leading []string
+	lines   []string
+}
+
+// rendered returns the block's leading comments followed by its lines.
+func (b *tomlBlock) rendered() []string {
+	if len(b.leading) == 0 {
+		return b.lines
+	}
+	return append(cloneLines(b.leading), b.lines...)
 }
 
 type tomlDocument struct {
@@ -70,13 +80,13 @@ func fromSharedKeyLine(line tomlpatch.KeyLine) keyLine {
 func fromSharedDocument(doc tomlpatch.Document) tomlDocument {
 	sections := make(map[string]*tomlBlock, len(doc.Sections))
 	for name, block := range doc.Sections {
-		sections[name] = &tomlBlock{name: block.Name, lines: cloneLines(block.Lines)}
+		sections[name] = &tomlBlock{name: block.Name, leading: cloneLines(block.Leading), lines: cloneLines(block.Lines)}
 	}
 	arrays := make(map[string][]*tomlBlock, len(doc.Arrays))
 	for name, blocks := range doc.Arrays {
 		arrays[name] = make([]*tomlBlock, 0, len(blocks))
 		for _, block := range blocks {
-			arrays[name] = append(arrays[name], &tomlBlock{name: block.Name, lines: cloneLines(block.Lines)})
+			arrays[name] = append(arrays[name], &tomlBlock{name: block.Name, leading: cloneLines(block.Leading), lines: cloneLines(block.Lines)})
 		}
 	}
 	return tomlDocument{
@@ -89,6 +99,8 @@ func fromSharedDocument(doc tomlpatch.Document) tomlDocument {
 
 var preferredWizardSectionOrder = []string{
 	approvalsSection,
+	"dispatch",
+	"notifications",
 	antigravitySection,
 	claudeSection,
 	claudeVSCodeSection,
@@ -108,12 +120,12 @@ var legacySectionAliases = map[string]string{
 func PatchConfig(content string, choices *Choices) (string, error) {
 	var parseCheck map[string]any
 	if err := toml.Unmarshal([]byte(content), &parseCheck); err != nil {
-		return "", fmt.Errorf(messages.WizardParseConfigFailedFmt, err)
+		return "", i18n.Errorf(messages.WizardParseConfigFailedFmt, err)
 	}
 
 	templateBytes, err := templates.Read("config.toml")
 	if err != nil {
-		return "", fmt.Errorf(messages.WizardReadConfigTemplateFailedFmt, err)
+		return "", i18n.Errorf(messages.WizardReadConfigTemplateFailedFmt, err)
 	}
 	templateContent := string(templateBytes)
 
@@ -137,7 +149,7 @@ func PatchConfig(content string, choices *Choices) (string, error) {
 	applyClaudeAgentSpecificUpdate(&currentDoc, choices)
 
 	if choices.EnabledMCPServersTouched && len(choices.DefaultMCPServers) == 0 {
-		return "", fmt.Errorf(messages.WizardDefaultMCPServersRequired)
+		return "", i18n.Errorf(messages.WizardDefaultMCPServersRequired)
 	}
 
 	output, err := assembleCanonicalConfig(currentDoc, templateDoc, catalogDoc, choices)
@@ -148,7 +160,7 @@ func PatchConfig(content string, choices *Choices) (string, error) {
 	rendered := strings.Join(output, "\n")
 	var renderCheck map[string]any
 	if err := toml.Unmarshal([]byte(rendered), &renderCheck); err != nil {
-		return "", fmt.Errorf(messages.WizardRenderConfigFailedFmt, err)
+		return "", i18n.Errorf(messages.WizardRenderConfigFailedFmt, err)
 	}
 
 	return rendered, nil
@@ -175,11 +187,11 @@ func assembleCanonicalConfig(currentDoc tomlDocument, templateDoc tomlDocument,
 		}
 		updated := cloneBlock(block)
 		applySectionUpdates(name, updated, templateDoc.sections[name], choices)
-		appendBlock(&output, updated.lines)
+		appendBlock(&output, updated.rendered())
 
 		if name == codexSection {
 			for _, block := range codexAgentSpecificSectionBlocks(currentDoc.sections, templateDoc.sections) {
-				appendBlock(&output, block.lines)
+				appendBlock(&output, block.rendered())
 			}
 		}
 
@@ -189,20 +201,20 @@ func assembleCanonicalConfig(currentDoc tomlDocument, templateDoc tomlDocument,
 				return nil, err
 			}
 			for _, serverBlock := range serverBlocks {
-				appendBlock(&output, serverBlock.lines)
+				appendBlock(&output, serverBlock.rendered())
 			}
 		}
 	}
 
 	extraSections := extraSectionBlocks(currentDoc.sections, templateDoc.sections)
 	for _, block := range extraSections {
-		appendBlock(&output, block.lines)
+		appendBlock(&output, block.rendered())
 	}
 
 	// Preserve non-mcp.servers array-of-table blocks.
 	extraArrays := extraArrayBlocks(currentDoc.arrays)
 	for _, block := range extraArrays {
-		appendBlock(&output, block.lines)
+		appendBlock(&output, block.rendered())
 	}
 
 	return trimTrailingEmptyLines(output), nil
@@ -345,8 +357,9 @@ func applySectionUpdates(name string, block *tomlBlock, templateBlock *tomlBlock
 }
 
 type mcpBlock struct {
-	id    string
-	lines []string
+	id      string
+	leading []string
+	lines   []string
 }
 
 // stdioIncompatibleKeys are TOML keys that are not valid for stdio transport MCP servers.
@@ -401,7 +414,7 @@ func buildMCPServerBlocks(currentDoc tomlDocument, catalogDoc tomlDocument, choi
 				}
 				tpl, exists := catalogByID[id]
 				if !exists {
-					return nil, fmt.Errorf(messages.WizardMissingDefaultMCPServerTemplateFmt, id)
+					return nil, i18n.Errorf(messages.WizardMissingDefaultMCPServerTemplateFmt, id)
 				}
 				block = tpl
 			}
@@ -426,7 +439,7 @@ func buildMCPServerBlocks(currentDoc tomlDocument, catalogDoc tomlDocument, choi
 				continue
 			}
 		}
-		tb := tomlBlock{name: mcpServersSection, lines: cloneLines(block.lines)}
+		tb := tomlBlock{name: mcpServersSection, leading: cloneLines(block.leading), lines: cloneLines(block.lines)}
 		// Honor the custom-server keep/disable decision. Unlike catalog defaults,
 		// a custom server has no template to restore from, so disabling sets
 		// enabled = false rather than pruning the block. Untouched configs pass
@@ -495,7 +508,7 @@ func removeKeyFromBlock(block *tomlBlock, key string) {
 // updateMCPEnabled applies the enabled toggle to a server block when requested.
 // block holds the current server text; templateBlock provides canonical formatting; id identifies the server.
 func updateMCPEnabled(block mcpBlock, templateBlock mcpBlock, choices *Choices, id string) tomlBlock {
-	updated := tomlBlock{name: mcpServersSection, lines: cloneLines(block.lines)}
+	updated := tomlBlock{name: mcpServersSection, leading: cloneLines(block.leading), lines: cloneLines(block.lines)}
 	if choices.EnabledMCPServersTouched {
 		tpl := (*tomlBlock)(nil)
 		if len(templateBlock.lines) > 0 {
@@ -533,7 +546,7 @@ func parseMCPBlocks(blocks []*tomlBlock) []mcpBlock {
 	result := make([]mcpBlock, 0, len(blocks))
 	for _, block := range blocks {
 		id := extractMCPServerID(block.lines)
-		result = append(result, mcpBlock{id: id, lines: cloneLines(block.lines)})
+		result = append(result, mcpBlock{id: id, leading: cloneLines(block.leading), lines: cloneLines(block.lines)})
 	}
 	return result
 }
@@ -699,7 +712,7 @@ func applyCodexBooleanFeatureUpdate(doc *tomlDocument, choices *Choices, key str
 		if hasUncommentedKeyLine(parentBlock.lines, "features") {
 			if current, exists := codexFeatureValueFromAgentSpecificBlock(parentBlock, key); exists {
 				if current != enabled {
-					return fmt.Errorf(messages.WizardCodexInlineFeaturesUnsupported)
+					return i18n.Errorf(messages.WizardCodexInlineFeaturesUnsupported)
 				}
 				return nil
 			}
@@ -710,7 +723,7 @@ func applyCodexBooleanFeatureUpdate(doc *tomlDocument, choices *Choices, key str
 			if enabled == defaultEnabled {
 				return nil
 			}
-			return fmt.Errorf(messages.WizardCodexInlineFeaturesUnsupported)
+			return i18n.Errorf(messages.WizardCodexInlineFeaturesUnsupported)
 		}
 		if hasUncommentedKeyWithPrefix(parentBlock.lines, "features.") {
 			setKeyValue(parentBlock, nil, dottedKey, formatTomlValue(enabled), "")
@@ -754,7 +767,7 @@ func applyCodexBrowserUpdate(doc *tomlDocument, choices *Choices) error {
 			// (we would need to set the keys) or when the inline table already pins
 			// a browser key the toggle would otherwise clear. Matches the apps path.
 			if choices.CodexDisableBrowser || inlineFeaturesHasAnyCodexBrowserKey(parentBlock) {
-				return fmt.Errorf(messages.WizardCodexInlineFeaturesUnsupported)
+				return i18n.Errorf(messages.WizardCodexInlineFeaturesUnsupported)
 			}
 			return nil
 		}
@@ -1065,7 +1078,7 @@ func cloneBlock(block *tomlBlock) *tomlBlock {
 	if block == nil {
 		return nil
 	}
-	return &tomlBlock{name: block.name, lines: cloneLines(block.lines)}
+	return &tomlBlock{name: block.name, leading: cloneLines(block.leading), lines: cloneLines(block.lines)}
 }
 
 // cloneLines returns a copy of the provided line slice.
@@ -1676,18 +1676,10 @@ func TestRemoveKeyFromBlock_EscapedTripleQuoteMultiline(t *testing.T) {
 	assert.Contains(t, joined, `enabled = true`)
 }
 
-func TestSanitizeMCPServerBlock_SectionStyleSubTableNotInBlock(t *testing.T) {
-	// Section-style sub-tables like [mcp.servers.env] are parsed as separate
-	// sections by the line-based parser — they are NOT part of the [[mcp.servers]]
-	// block. This means sanitizeMCPServerBlock cannot reach them.
-	//
-	// This is a known limitation of the line-based parser. In practice, agent-layer
-	// templates use inline tables (env = { KEY = "val" }), and go-toml validation
-	// will still reject the config if section-style sub-tables contain transport-
-	// incompatible fields. The wizard just can't auto-remove them.
-	//
-	// This test verifies that sanitization works correctly on the server block
-	// itself when a section-style sub-table exists — no crash, no corruption.
+func TestSanitizeMCPServerBlock_SectionStyleSubTableInBlock(t *testing.T) {
+	// Section-style sub-tables like [mcp.servers.env] belong to the preceding
+	// [[mcp.servers]] element, so the parser keeps them in its block. Moving
+	// them elsewhere would attach them to a different server.
 	content := `
 [mcp]
 
@@ -1703,23 +1695,19 @@ KEY = "val"
 `
 	doc := parseTomlDocument(content)
 
-	// The section-style sub-table should be parsed as a separate section.
-	require.Contains(t, doc.sections, "mcp.servers.env",
-		"section-style sub-table should be a separate section in the line-based parser")
-
-	// The [[mcp.servers]] block should NOT contain the env key-value.
+	require.NotContains(t, doc.sections, "mcp.servers.env")
 	require.Contains(t, doc.arrays, "mcp.servers")
 	require.Len(t, doc.arrays["mcp.servers"], 1)
 	serverBlock := doc.arrays["mcp.servers"][0]
-	joined := strings.Join(serverBlock.lines, "\n")
-	assert.NotContains(t, joined, "KEY =",
-		"section-style env sub-table should not be inside the server block")
+	assert.Contains(t, strings.Join(serverBlock.lines, "\n"), `KEY = "val"`)
 
-	// Sanitize the server block — it should remove "command" (http-incompatible).
+	// Sanitizing an http server removes the leftover command and the env sub-table.
 	tb := tomlBlock{name: serverBlock.name, lines: cloneLines(serverBlock.lines)}
 	sanitizeMCPServerBlock(&tb)
 	sanitized := strings.Join(tb.lines, "\n")
 	assert.NotContains(t, sanitized, "command")
+	assert.NotContains(t, sanitized, "[mcp.servers.env]")
+	assert.NotContains(t, sanitized, "KEY =")
 	assert.Contains(t, sanitized, `url = "https://api.example.com"`)
 }
 
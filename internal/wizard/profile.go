@@ -12,7 +12,9 @@ import (
 	"github.com/fatih/color"
 
 	"github.com/conn-castle/agent-layer/internal/config"
+	"github.com/conn-castle/agent-layer/internal/i18n"
 	"github.com/conn-castle/agent-layer/internal/install"
+	"github.com/conn-castle/agent-layer/internal/layerdir"
 	"github.com/conn-castle/agent-layer/internal/messages"
 )
 
@@ -24,13 +26,13 @@ func RunProfile(root string, runSync syncer, pinVersion string, profilePath stri
 		out = os.Stdout
 	}
 	if strings.TrimSpace(profilePath) == "" {
-		return fmt.Errorf(messages.WizardProfilePathRequired)
+		return i18n.Errorf(messages.WizardProfilePathRequired)
 	}
 
-	configPath := filepath.Join(root, ".agent-layer", "config.toml")
+	configPath := filepath.Join(layerdir.Dir(root), "config.toml")
 	if _, err := os.Stat(configPath); os.IsNotExist(err) {
 		if err := install.Run(root, install.Options{Overwrite: false, PinVersion: pinVersion, System: install.RealSystem{}}); err != nil {
-			return fmt.Errorf(messages.WizardInstallFailedFmt, err)
+			return i18n.Errorf(messages.WizardInstallFailedFmt, err)
 		}
 	} else if err != nil {
 		return err
@@ -38,10 +40,10 @@ func RunProfile(root string, runSync syncer, pinVersion string, profilePath stri
 
 	profileBytes, err := os.ReadFile(profilePath) // #nosec G304 -- profilePath is an explicit wizard profile input intentionally read by this command.
 	if err != nil {
-		return fmt.Errorf(messages.WizardProfileReadFailedFmt, profilePath, err)
+		return i18n.Errorf(messages.WizardProfileReadFailedFmt, profilePath, err)
 	}
 	if _, err := config.ParseConfig(profileBytes, profilePath); err != nil {
-		return fmt.Errorf(messages.WizardProfileInvalidFmt, err)
+		return i18n.Errorf(messages.WizardProfileInvalidFmt, err)
 	}
 
 	currentConfig, err := os.ReadFile(configPath) // #nosec G304 -- configPath is the caller-resolved .agent-layer/config.toml path used as the profile merge target.
@@ -49,7 +51,7 @@ func RunProfile(root string, runSync syncer, pinVersion string, profilePath stri
 		return err
 	}
 	if _, err := config.ParseConfigLenient(currentConfig, configPath); err != nil {
-		_, _ = fmt.Fprintf(out, messages.WizardProfileExistingConfigInvalidWarnFmt+"\n", err)
+		_, _ = fmt.Fprintf(out, i18n.T(messages.WizardProfileExistingConfigInvalidWarnFmt)+"\n", err)
 	}
 
 	preview := strings.TrimSpace(udiff.Unified(
@@ -59,14 +61,14 @@ func RunProfile(root string, runSync syncer, pinVersion string, profilePath stri
 		string(profileBytes),
 	))
 	if preview == "" {
-		_, _ = fmt.Fprintln(out, messages.WizardProfileNoConfigChanges)
+		_, _ = fmt.Fprintln(out, i18n.T(messages.WizardProfileNoConfigChanges))
 		if !apply {
 			return nil
 		}
 	} else {
 		_, _ = fmt.Fprintf(out, "%s\n\n%s\n", messages.WizardProfilePreviewHeader, preview)
 		if !apply {
-			_, _ = fmt.Fprintln(out, messages.WizardProfilePreviewOnly)
+			_, _ = fmt.Fprintln(out, i18n.T(messages.WizardProfilePreviewOnly))
 			return nil
 		}
 	}
@@ -77,13 +79,13 @@ func RunProfile(root string, runSync syncer, pinVersion string, profilePath stri
 	}
 	configBackupPath := configPath + ".bak"
 	if _, err := writeBackup(configBackupPath, currentConfig, configPerm); err != nil {
-		return fmt.Errorf(messages.WizardBackupConfigFailedFmt, err)
+		return i18n.Errorf(messages.WizardBackupConfigFailedFmt, err)
 	}
 	if err := writeFileAtomic(configPath, profileBytes, configPerm); err != nil {
-		return fmt.Errorf(messages.WizardWriteConfigFailedFmt, err)
+		return i18n.Errorf(messages.WizardWriteConfigFailedFmt, err)
 	}
 
-	_, _ = fmt.Fprintln(out, messages.WizardRunningSync)
+	_, _ = fmt.Fprintln(out, i18n.T(messages.WizardRunningSync))
 	result, err := runSync(root)
 	if err != nil {
 		return err
@@ -97,15 +99,15 @@ func RunProfile(root string, runSync syncer, pinVersion string, profilePath stri
 		_, _ = fmt.Fprintln(out)
 	}
 
-	_, _ = fmt.Fprintln(out, messages.WizardCompleted)
+	_, _ = fmt.Fprintln(out, i18n.T(messages.WizardCompleted))
 	return nil
 }
 
 // CleanupBackups removes wizard backup files and returns removed paths relative to repo root.
 func CleanupBackups(root string) ([]string, error) {
 	candidates := []string{
-		filepath.Join(root, ".agent-layer", "config.toml.bak"),
-		filepath.Join(root, ".agent-layer", ".env.bak"),
+		filepath.Join(layerdir.Dir(root), "config.toml.bak"),
+		filepath.Join(layerdir.Dir(root), ".env.bak"),
 	}
 
 	removed := make([]string, 0, len(candidates))
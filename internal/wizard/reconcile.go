@@ -0,0 +1,468 @@
+package wizard
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/pelletier/go-toml/v2"
+
+	"github.com/conn-castle/agent-layer/internal/i18n"
+	"github.com/conn-castle/agent-layer/internal/messages"
+	"github.com/conn-castle/agent-layer/internal/templates"
+	"github.com/conn-castle/agent-layer/internal/tomlpatch"
+)
+
+// Reconcile steps. Each can be accepted or declined on its own.
+const (
+	// ReconcileAddKeys adds template keys and sections missing from the
+	// config, with the template's default value and documentation.
+	ReconcileAddKeys = "add_keys"
+	// ReconcileRestoreComments restores template documentation comments that
+	// were removed from the config.
+	ReconcileRestoreComments = "restore_comments"
+	// ReconcileReorder rewrites sections in the wizard's canonical order.
+	ReconcileReorder = "reorder"
+)
+
+// ReconcileSteps lists the steps in the order they are applied.
+var ReconcileSteps = []string{ReconcileAddKeys, ReconcileRestoreComments, ReconcileReorder}
+
+// ReconcileChange is one change reconcile would make.
+type ReconcileChange struct {
+	Step string
+	// Section is the table the change applies to; empty for the preamble and
+	// for ReconcileReorder.
+	Section string
+	// Key is the added or documented key, or the header of a commented-out
+	// table example; empty for whole sections.
+	Key string
+	// Lines are the lines the change inserts.
+	Lines []string
+}
+
+// ReconcileOptions selects the steps Reconcile applies.
+type ReconcileOptions struct {
+	AddKeys         bool
+	RestoreComments bool
+	Reorder         bool
+}
+
+// templateKey is one key line in a template section with the comment lines
+// directly above it. A commented-out subtable example has no key; its line is
+// the commented header and tail holds the commented keys under it.
+type templateKey struct {
+	key  string
+	line string
+	docs []string
+	tail []string
+}
+
+func (tk templateKey) lines() []string {
+	return append(append(cloneLines(tk.docs), tk.line), tk.tail...)
+}
+
+// configSegment is a header line and every line up to the next header. The
+// first segment holds the lines before any header and has no name.
+type configSegment struct {
+	name  string
+	array bool
+	lines []string
+}
+
+// PlanReconcile returns every change Reconcile would make to content with all
+// steps enabled.
+func PlanReconcile(content string) ([]ReconcileChange, error) {
+	_, changes, err := Reconcile(content, ReconcileOptions{AddKeys: true, RestoreComments: true, Reorder: true})
+	return changes, err
+}
+
+// Reconcile brings a drifted config.toml back in line with the current
+// template without changing any value the config already sets: it adds
+// missing keys and sections with their template defaults, restores missing
+// documentation comments, and reorders sections canonically, as selected by
+// opts. It returns the updated content and the changes it made. A result that
+// would change an existing value is an error.
+func Reconcile(content string, opts ReconcileOptions) (string, []ReconcileChange, error) {
+	var before map[string]any
+	if err := toml.Unmarshal([]byte(content), &before); err != nil {
+		return "", nil, i18n.Errorf(messages.WizardParseConfigFailedFmt, err)
+	}
+	templateBytes, err := templates.Read("config.toml")
+	if err != nil {
+		return "", nil, i18n.Errorf(messages.WizardReadConfigTemplateFailedFmt, err)
+	}
+	templateDoc := parseTomlDocument(string(templateBytes))
+	currentDoc := parseTomlDocument(content)
+	segments := splitConfigSegments(content)
+
+	var changes []ReconcileChange
+	if !hasContent(currentDoc.preamble) && hasContent(templateDoc.preamble) {
+		lines := tomlpatch.TrimEmptyLines(templateDoc.preamble)
+		changes = append(changes, ReconcileChange{Step: ReconcileRestoreComments, Lines: lines})
+		if opts.RestoreComments {
+			segments[0].lines = append(append(cloneLines(lines), ""), tomlpatch.TrimEmptyLines(segments[0].lines)...)
+		}
+	}
+
+	previous := -1
+	for _, name := range templateDoc.order {
+		templateBlock := templateDoc.sections[name]
+		// Reparse after every edit so block boundaries match the segments.
+		doc := parseTomlDocument(joinConfigSegments(segments))
+		idx := findConfigSegment(segments, name)
+		if idx < 0 {
+			lines := tomlpatch.TrimEmptyLines(templateBlock.rendered())
+			changes = append(changes, ReconcileChange{Step: ReconcileAddKeys, Section: name, Lines: lines})
+			if opts.AddKeys {
+				insertConfigSection(segments, previous, doc, lines)
+				segments = splitConfigSegments(joinConfigSegments(segments))
+				previous = findConfigSegment(segments, name)
+			}
+			continue
+		}
+		previous = idx
+		end := len(tomlpatch.TrimTrailingEmptyLines(doc.sections[name].lines))
+		body := cloneLines(segments[idx].lines[:end])
+		rest := segments[idx].lines[end:]
+		body, sectionChanges := reconcileSectionBody(name, body, templateSectionKeys(templateBlock.lines, doc), opts)
+		changes = append(changes, sectionChanges...)
+		segments[idx].lines = append(body, rest...)
+	}
+
+	result := joinConfigSegments(segments)
+	reordered, err := canonicalSectionOrder(result, templateDoc)
+	if err != nil {
+		return "", nil, err
+	}
+	if strings.TrimRight(reordered, "\n") != strings.TrimRight(result, "\n") {
+		changes = append(changes, ReconcileChange{Step: ReconcileReorder, Lines: presentTemplateSections(parseTomlDocument(result), templateDoc)})
+		if opts.Reorder {
+			result = reordered
+		}
+	}
+	if strings.HasSuffix(content, "\n") && !strings.HasSuffix(result, "\n") {
+		result += "\n"
+	}
+
+	var after map[string]any
+	if err := toml.Unmarshal([]byte(result), &after); err != nil {
+		return "", nil, i18n.Errorf(messages.WizardRenderConfigFailedFmt, err)
+	}
+	if path := changedValue("", before, after); path != "" {
+		return "", nil, i18n.Errorf(messages.WizardReconcileValueChangedFmt, path)
+	}
+	return result, changes, nil
+}
+
+// reconcileSectionBody adds missing template keys to body and restores the
+// documentation of keys it already has. Insertions go after the last template
+// key seen so far, which keeps them in template order.
+func reconcileSectionBody(name string, body []string, keys []templateKey, opts ReconcileOptions) ([]string, []ReconcileChange) {
+	var changes []ReconcileChange
+	next := 1
+	for _, tk := range keys {
+		idx := templateKeyIndex(body, tk)
+		if idx < 0 {
+			lines := tk.lines()
+			// A commented-out key or subtable is documentation, not a value.
+			step, apply := ReconcileAddKeys, opts.AddKeys
+			if strings.HasPrefix(strings.TrimSpace(tk.line), "#") {
+				step, apply = ReconcileRestoreComments, opts.RestoreComments
+			}
+			key := tk.key
+			if key == "" {
+				key = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(tk.line), "#"))
+			}
+			changes = append(changes, ReconcileChange{Step: step, Section: name, Key: key, Lines: lines})
+			if apply {
+				body = insertLines(body, next, lines)
+				next += len(lines)
+			}
+			continue
+		}
+		if tk.key != "" && len(tk.docs) > 0 && !hasAnyLine(body, tk.docs) {
+			changes = append(changes, ReconcileChange{Step: ReconcileRestoreComments, Section: name, Key: tk.key, Lines: cloneLines(tk.docs)})
+			if opts.RestoreComments {
+				body = insertLines(body, idx, tk.docs)
+				idx += len(tk.docs)
+			}
+		}
+		next = templateKeyEnd(body, tk, idx)
+	}
+	return body, changes
+}
+
+// templateKeyIndex returns the index of tk's line in body, or -1.
+func templateKeyIndex(body []string, tk templateKey) int {
+	if tk.key != "" {
+		if _, ok := tomlpatch.FindKeyLine(body, tk.key); !ok {
+			return -1
+		}
+		return tomlpatch.FindInsertIndex(body, tk.key) - 1
+	}
+	for i, line := range body {
+		if strings.TrimSpace(line) == strings.TrimSpace(tk.line) {
+			return i
+		}
+	}
+	return -1
+}
+
+// templateKeyEnd returns the index just past tk in body, including the rest
+// of a multiline value or the commented keys of a subtable example.
+func templateKeyEnd(body []string, tk templateKey, idx int) int {
+	if tk.key != "" {
+		if end := tomlpatch.MultilineValueEndIndex(body, idx); end > idx {
+			return end + 1
+		}
+		return idx + 1
+	}
+	end := idx + 1
+	for end < len(body) && hasAnyLine(body[end:end+1], tk.tail) {
+		end++
+	}
+	return end
+}
+
+// templateSectionKeys lists the keys a template section documents, active or
+// commented out, with the comment lines directly above each. A commented-out
+// subtable example is listed as one entry that owns the commented keys under
+// it, unless current already defines that table.
+func templateSectionKeys(lines []string, current tomlDocument) []templateKey {
+	var (
+		keys []templateKey
+		docs []string
+		sub  *templateKey
+	)
+	seen := make(map[string]bool)
+	closeSub := func() {
+		if sub != nil && !definesTable(current, sub.line) {
+			keys = append(keys, *sub)
+		}
+		sub = nil
+	}
+	for _, line := range lines[1:] {
+		trimmed := strings.TrimSpace(line)
+		commented := strings.HasPrefix(trimmed, "#")
+		if trimmed == "" {
+			closeSub()
+			docs = nil
+			continue
+		}
+		uncommented := strings.TrimSpace(strings.TrimPrefix(trimmed, "#"))
+		if _, _, isHeader := tomlpatch.ParseHeader(uncommented); isHeader && commented {
+			closeSub()
+			sub = &templateKey{line: line, docs: docs}
+			docs = nil
+			continue
+		}
+		key, ok := templateKeyName(uncommented)
+		if !ok {
+			closeSub()
+			if commented {
+				docs = append(docs, line)
+			} else {
+				docs = nil
+			}
+			continue
+		}
+		if sub != nil && commented {
+			sub.tail = append(sub.tail, line)
+			continue
+		}
+		closeSub()
+		if !seen[key] {
+			seen[key] = true
+			keys = append(keys, templateKey{key: key, line: line, docs: docs})
+		}
+		docs = nil
+	}
+	closeSub()
+	return keys
+}
+
+// definesTable reports whether doc has the table a commented-out header line
+// names, or any table nested under it.
+func definesTable(doc tomlDocument, line string) bool {
+	name, _, _ := tomlpatch.ParseHeader(strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(line), "#")))
+	if parts, ok := tomlpatch.ParseKeyPath(name); ok {
+		name = tomlpatch.FormatDottedKeyPath(parts)
+	}
+	for section := range doc.sections {
+		if section == name || strings.HasPrefix(section, name+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// templateKeyName returns the key a "key = value" line assigns when the value
+// is valid TOML, so prose comments that happen to contain "=" are not keys.
+func templateKeyName(line string) (string, bool) {
+	commentPos, _ := tomlpatch.ScanLineForComment(line, tomlpatch.StateNone)
+	if commentPos >= 0 {
+		line = strings.TrimSpace(line[:commentPos])
+	}
+	left, value, ok := strings.Cut(line, "=")
+	if !ok {
+		return "", false
+	}
+	path, ok := tomlpatch.ParseKeyPath(strings.TrimSpace(left))
+	if !ok {
+		return "", false
+	}
+	var parsed map[string]any
+	if err := toml.Unmarshal([]byte("v = "+strings.TrimSpace(value)), &parsed); err != nil {
+		return "", false
+	}
+	return tomlpatch.FormatDottedKeyPath(path), true
+}
+
+// canonicalSectionOrder renders content through the wizard's canonical
+// assembler, limited to the template sections content already has so the
+// reorder step never adds a section.
+func canonicalSectionOrder(content string, templateDoc tomlDocument) (string, error) {
+	catalogDoc, err := loadCatalogDocument()
+	if err != nil {
+		return "", err
+	}
+	currentDoc := parseTomlDocument(content)
+	present := tomlDocument{sections: make(map[string]*tomlBlock), arrays: templateDoc.arrays}
+	for _, name := range templateDoc.order {
+		if _, ok := currentDoc.sections[name]; ok {
+			present.order = append(present.order, name)
+			present.sections[name] = templateDoc.sections[name]
+		}
+	}
+	output, err := assembleCanonicalConfig(currentDoc, present, catalogDoc, &Choices{})
+	if err != nil {
+		return "", err
+	}
+	return strings.Join(output, "\n"), nil
+}
+
+// presentTemplateSections lists the template sections doc has, in canonical
+// order, to describe a reorder.
+func presentTemplateSections(doc tomlDocument, templateDoc tomlDocument) []string {
+	var names []string
+	for _, name := range orderedWizardSections(templateDoc.order) {
+		if _, ok := doc.sections[name]; ok {
+			names = append(names, "["+name+"]")
+		}
+	}
+	return names
+}
+
+// changedValue returns the dotted path of the first value in before that
+// after changes or drops, or "" when after only adds to before.
+func changedValue(prefix string, before map[string]any, after map[string]any) string {
+	for key, value := range before {
+		path := joinPath(prefix, key)
+		next, ok := after[key]
+		if !ok {
+			return path
+		}
+		if nested, isMap := value.(map[string]any); isMap {
+			nextMap, ok := next.(map[string]any)
+			if !ok {
+				return path
+			}
+			if changed := changedValue(path, nested, nextMap); changed != "" {
+				return changed
+			}
+			continue
+		}
+		if !reflect.DeepEqual(value, next) {
+			return path
+		}
+	}
+	return ""
+}
+
+// splitConfigSegments splits content at table headers outside multiline
+// strings.
+func splitConfigSegments(content string) []configSegment {
+	segments := []configSegment{{}}
+	state := tomlpatch.StateNone
+	for _, line := range strings.Split(content, "\n") {
+		if !tomlpatch.StateInMultiline(state) {
+			if name, isArray, ok := tomlpatch.ParseHeader(line); ok {
+				if parts, ok := tomlpatch.ParseKeyPath(name); ok {
+					name = tomlpatch.FormatDottedKeyPath(parts)
+				}
+				segments = append(segments, configSegment{name: name, array: isArray})
+			}
+		}
+		last := &segments[len(segments)-1]
+		last.lines = append(last.lines, line)
+		_, state = tomlpatch.ScanLineForComment(line, state)
+	}
+	return segments
+}
+
+func joinConfigSegments(segments []configSegment) string {
+	var lines []string
+	for _, segment := range segments {
+		lines = append(lines, segment.lines...)
+	}
+	return strings.Join(lines, "\n")
+}
+
+func findConfigSegment(segments []configSegment, name string) int {
+	for i, segment := range segments {
+		if !segment.array && segment.name == name && i > 0 {
+			return i
+		}
+	}
+	return -1
+}
+
+// insertConfigSection inserts a template section after the body of the
+// segment at previous, before any comment paragraph that documents the next
+// table, or after the preamble when previous is -1.
+func insertConfigSection(segments []configSegment, previous int, currentDoc tomlDocument, lines []string) {
+	idx := previous
+	end := len(tomlpatch.TrimTrailingEmptyLines(segments[0].lines))
+	if idx < 0 {
+		idx = 0
+		if len(segments) > 1 && hasContent(segments[0].lines) {
+			// Keep a comment paragraph above the first header with that header.
+			end = len(tomlpatch.TrimTrailingEmptyLines(currentDoc.preamble))
+		}
+	} else {
+		end = len(tomlpatch.TrimTrailingEmptyLines(currentDoc.sections[segments[idx].name].lines))
+	}
+	insert := cloneLines(lines)
+	if end > 0 {
+		insert = append([]string{""}, insert...)
+	}
+	rest := segments[idx].lines[end:]
+	if len(rest) == 0 || strings.TrimSpace(rest[0]) != "" {
+		insert = append(insert, "")
+	}
+	segments[idx].lines = append(append(cloneLines(segments[idx].lines[:end]), insert...), rest...)
+}
+
+func insertLines(lines []string, at int, insert []string) []string {
+	out := make([]string, 0, len(lines)+len(insert))
+	out = append(out, lines[:at]...)
+	out = append(out, insert...)
+	return append(out, lines[at:]...)
+}
+
+func hasAnyLine(lines []string, want []string) bool {
+	present := make(map[string]bool, len(lines))
+	for _, line := range lines {
+		present[strings.TrimSpace(line)] = true
+	}
+	for _, line := range want {
+		if present[strings.TrimSpace(line)] {
+			return true
+		}
+	}
+	return false
+}
+
+func hasContent(lines []string) bool {
+	return len(tomlpatch.TrimEmptyLines(lines)) > 0
+}
@@ -0,0 +1,86 @@
+package wizard
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/conn-castle/agent-layer/internal/templates"
+)
+
+const driftedConfig = `[agents.codex]
+enabled = false
+model = "gpt-custom"
+
+[approvals]
+mode = "none"
+
+[warnings]
+version_update_on_sync = false
+
+[agents.claude]
+enabled = true
+`
+
+func TestReconcile_TemplateHasNoChanges(t *testing.T) {
+	data, err := templates.Read("config.toml")
+	require.NoError(t, err)
+
+	changes, err := PlanReconcile(string(data))
+	require.NoError(t, err)
+	assert.Empty(t, changes)
+}
+
+func TestReconcile_DriftedConfig(t *testing.T) {
+	result, changes, err := Reconcile(driftedConfig, ReconcileOptions{AddKeys: true, RestoreComments: true, Reorder: true})
+	require.NoError(t, err)
+
+	steps := make(map[string]bool)
+	for _, change := range changes {
+		steps[change.Step] = true
+	}
+	assert.True(t, steps[ReconcileAddKeys])
+	assert.True(t, steps[ReconcileRestoreComments])
+	assert.True(t, steps[ReconcileReorder])
+
+	assert.Contains(t, result, "mode = \"none\"")
+	assert.Contains(t, result, "model = \"gpt-custom\"")
+	assert.Contains(t, result, "version_update_on_sync = false\n")
+	assert.Contains(t, result, "noise_mode = \"default\"")
+	assert.Contains(t, result, "[dispatch]\n# Maximum dispatch depth")
+	assert.Contains(t, result, "# one of: \"all\", \"mcp\", \"commands\", \"none\", \"yolo\"\n")
+	assert.Less(t, strings.Index(result, "[approvals]"), strings.Index(result, "[agents.claude]"))
+	assert.Less(t, strings.Index(result, "[agents.claude]"), strings.Index(result, "[agents.codex]"))
+
+	again, err := PlanReconcile(result)
+	require.NoError(t, err)
+	assert.Empty(t, again, "reconcile must be idempotent")
+}
+
+func TestReconcile_DeclinedStepsAreNotApplied(t *testing.T) {
+	result, changes, err := Reconcile(driftedConfig, ReconcileOptions{RestoreComments: true})
+	require.NoError(t, err)
+	require.NotEmpty(t, changes)
+
+	assert.NotContains(t, result, "[dispatch]")
+	assert.NotContains(t, result, "noise_mode")
+	assert.Contains(t, result, "# one of: \"all\", \"mcp\", \"commands\", \"none\", \"yolo\"\n")
+	assert.Less(t, strings.Index(result, "[agents.codex]"), strings.Index(result, "[approvals]"))
+}
+
+func TestReconcile_InvalidConfig(t *testing.T) {
+	_, err := PlanReconcile("[approvals\n")
+	require.Error(t, err)
+}
+
+func TestReconcile_SkipsExamplesForDefinedTables(t *testing.T) {
+	content := "[agents.claude]\nenabled = true\n\n[agents.claude.agent_specific]\nautoMemoryEnabled = false\n"
+	result, _, err := Reconcile(content, ReconcileOptions{RestoreComments: true})
+	require.NoError(t, err)
+
+	assert.Contains(t, result, "# disable_question_tool = true\n")
+	assert.NotContains(t, result, "# [agents.claude.agent_specific]")
+	assert.Contains(t, result, "[agents.claude.agent_specific]\nautoMemoryEnabled = false\n")
+}
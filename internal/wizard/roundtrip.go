@@ -0,0 +1,203 @@
+package wizard
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/pelletier/go-toml/v2"
+
+	"github.com/conn-castle/agent-layer/internal/i18n"
+	"github.com/conn-castle/agent-layer/internal/messages"
+	"github.com/conn-castle/agent-layer/internal/templates"
+	"github.com/conn-castle/agent-layer/internal/tomlpatch"
+)
+
+// RoundTrip patches content with choices like PatchConfig and then checks
+// the guarantees the wizard makes about a user's config: patching the result
+// again changes nothing, tables the wizard does not manage keep their values
+// and their comment lines, and multiline strings keep their values. It
+// returns the patched content, or an error naming the first guarantee that
+// does not hold. Apply writes through RoundTrip so a patching bug fails the
+// wizard instead of corrupting config.toml.
+func RoundTrip(content string, choices *Choices) (string, error) {
+	patched, err := PatchConfig(content, choices)
+	if err != nil {
+		return "", err
+	}
+	again, err := PatchConfig(patched, choices)
+	if err != nil {
+		return "", i18n.Errorf(messages.WizardRoundTripRepatchFmt, err)
+	}
+	if again != patched {
+		return "", errors.New(i18n.T(messages.WizardRoundTripNotIdempotent))
+	}
+
+	var before, after map[string]any
+	if err := toml.Unmarshal([]byte(content), &before); err != nil {
+		return "", i18n.Errorf(messages.WizardParseConfigFailedFmt, err)
+	}
+	if err := toml.Unmarshal([]byte(patched), &after); err != nil {
+		return "", i18n.Errorf(messages.WizardRenderConfigFailedFmt, err)
+	}
+	if err := checkUnmanagedTables(content, patched, before, after); err != nil {
+		return "", err
+	}
+	if err := checkMultilineStrings("", before, after); err != nil {
+		return "", err
+	}
+	return patched, nil
+}
+
+// checkUnmanagedTables verifies that every table the wizard does not manage
+// keeps its parsed value and its comment lines.
+func checkUnmanagedTables(content string, patched string, before map[string]any, after map[string]any) error {
+	templateBytes, err := templates.Read("config.toml")
+	if err != nil {
+		return i18n.Errorf(messages.WizardReadConfigTemplateFailedFmt, err)
+	}
+	templateDoc := parseTomlDocument(string(templateBytes))
+	currentDoc := parseTomlDocument(content)
+
+	var blocks []*tomlBlock
+	for _, name := range currentDoc.order {
+		if !isManagedTable(name, templateDoc) {
+			blocks = append(blocks, currentDoc.sections[name])
+		}
+	}
+	for name, elements := range currentDoc.arrays {
+		if name != mcpServersSection {
+			blocks = append(blocks, elements...)
+		}
+	}
+
+	available := make(map[string]int)
+	for _, line := range strings.Split(patched, "\n") {
+		available[strings.TrimSpace(line)]++
+	}
+	if lost := consumeComments(currentDoc.preamble, available); lost != "" {
+		return i18n.Errorf(messages.WizardRoundTripCommentLostFmt, lost, messages.WizardRoundTripPreamble)
+	}
+	for _, block := range blocks {
+		path, ok := tomlpatch.ParseKeyPath(block.name)
+		if !ok {
+			continue
+		}
+		if !reflect.DeepEqual(lookupPath(before, path), lookupPath(after, path)) {
+			return i18n.Errorf(messages.WizardRoundTripTableChangedFmt, block.name)
+		}
+		if lost := consumeComments(block.rendered(), available); lost != "" {
+			return i18n.Errorf(messages.WizardRoundTripCommentLostFmt, lost, block.name)
+		}
+	}
+	return nil
+}
+
+// consumeComments takes each comment line of lines out of available and
+// returns the first one that is not there.
+func consumeComments(lines []string, available map[string]int) string {
+	var lost string
+	walkTomlLinesOutsideMultiline(lines, func(_ int, line string, _ tomlStringState) tomlLineWalkResult {
+		trimmed := strings.TrimSpace(line)
+		if !strings.HasPrefix(trimmed, "#") {
+			return tomlLineWalkResult{}
+		}
+		if available[trimmed] == 0 {
+			lost = trimmed
+			return tomlLineWalkResult{stop: true}
+		}
+		available[trimmed]--
+		return tomlLineWalkResult{}
+	})
+	return lost
+}
+
+// isManagedTable reports whether the wizard may rewrite the table name: the
+// template's own tables, MCP servers, the agent_specific tables behind wizard
+// toggles, and legacy section aliases.
+func isManagedTable(name string, templateDoc tomlDocument) bool {
+	if _, ok := templateDoc.sections[name]; ok {
+		return true
+	}
+	if _, ok := legacySectionAliases[name]; ok {
+		return true
+	}
+	return name == mcpServersSection ||
+		isCodexAgentSpecificSection(name) ||
+		name == claudeAgentSpecificSection || strings.HasPrefix(name, claudeAgentSpecificSection+".")
+}
+
+// lookupPath returns the value at path in a decoded TOML document, or nil.
+func lookupPath(doc map[string]any, path []string) any {
+	var current any = doc
+	for _, part := range path {
+		table, ok := current.(map[string]any)
+		if !ok {
+			return nil
+		}
+		current = table[part]
+	}
+	return current
+}
+
+// checkMultilineStrings verifies that every string containing a newline in
+// before has the same value in after wherever after still has its key. Array
+// elements are matched by their id when they have one, since the wizard
+// reorders MCP servers.
+func checkMultilineStrings(prefix string, before any, after any) error {
+	switch value := before.(type) {
+	case string:
+		if got, ok := after.(string); ok && strings.Contains(value, "\n") && got != value {
+			return i18n.Errorf(messages.WizardRoundTripMultilineFmt, prefix)
+		}
+	case map[string]any:
+		next, _ := after.(map[string]any)
+		for key, child := range value {
+			if _, ok := next[key]; !ok {
+				continue
+			}
+			if err := checkMultilineStrings(joinPath(prefix, tomlpatch.FormatKey(key)), child, next[key]); err != nil {
+				return err
+			}
+		}
+	case []any:
+		next, _ := after.([]any)
+		for i, child := range value {
+			var match any
+			if id := elementID(child); id != "" {
+				for _, candidate := range next {
+					if elementID(candidate) == id {
+						match = candidate
+						break
+					}
+				}
+			} else if i < len(next) {
+				match = next[i]
+			}
+			if match == nil {
+				continue
+			}
+			if err := checkMultilineStrings(prefix+"["+strconv.Itoa(i)+"]", child, match); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func elementID(value any) string {
+	table, ok := value.(map[string]any)
+	if !ok {
+		return ""
+	}
+	id, _ := table["id"].(string)
+	return id
+}
+
+func joinPath(prefix string, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + "." + key
+}
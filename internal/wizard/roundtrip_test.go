@@ -0,0 +1,203 @@
+package wizard
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pelletier/go-toml/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/conn-castle/agent-layer/internal/templates"
+)
+
+func readConfigTemplate(t testing.TB) string {
+	t.Helper()
+	data, err := templates.Read("config.toml")
+	require.NoError(t, err)
+	return string(data)
+}
+
+// roundTripChoices returns wizard choices that touch a different mix of
+// managed sections for each selector value.
+func roundTripChoices(t testing.TB, selector uint8) *Choices {
+	t.Helper()
+	c := NewChoices()
+	if selector&1 != 0 {
+		c.ApprovalModeTouched = true
+		c.ApprovalMode = "none"
+	}
+	if selector&2 != 0 {
+		c.EnabledAgentsTouched = true
+		c.EnabledAgents = map[string]bool{AgentClaude: true, AgentCodex: true}
+	}
+	if selector&4 != 0 {
+		c.CodexModelTouched = true
+		c.CodexModel = "gpt-5"
+		c.CodexDisableBrowserTouched = true
+		c.CodexDisableBrowser = true
+	}
+	if selector&8 != 0 {
+		c.ClaudeDisableMemoryTouched = true
+		c.ClaudeDisableMemory = true
+	}
+	if selector&16 != 0 {
+		defaults, err := loadDefaultMCPServers()
+		require.NoError(t, err)
+		c.DefaultMCPServers = defaults
+		c.EnabledMCPServersTouched = true
+		for i, server := range defaults {
+			c.EnabledMCPServers[server.ID] = i%2 == 0
+		}
+	}
+	if selector&32 != 0 {
+		c.WarningsEnabledTouched = true
+		c.WarningsEnabled = selector&64 != 0
+		c.InstructionTokenThreshold = 10000
+	}
+	return c
+}
+
+func TestRoundTrip_TemplateWithUnknownSections(t *testing.T) {
+	content := readConfigTemplate(t) + `
+# Team plugins, kept by hand.
+[[plugins]]
+id = "lint"
+
+[plugins.settings]
+level = "strict"
+
+[[plugins]]
+id = "fmt"
+
+[ custom . notes ]
+body = """
+first line
+[not.a.table]
+last line
+"""
+`
+	for selector := range uint8(128) {
+		out, err := RoundTrip(content, roundTripChoices(t, selector))
+		require.NoError(t, err, "selector %d", selector)
+		assert.Contains(t, out, "# Team plugins, kept by hand.\n[[plugins]]")
+		assert.Contains(t, out, "[plugins.settings]\nlevel = \"strict\"")
+		assert.Contains(t, out, "first line\n[not.a.table]\nlast line\n")
+	}
+}
+
+func TestRoundTrip_MCPServerSubTableStaysWithServer(t *testing.T) {
+	content := `[[mcp.servers]]
+id = "docs"
+enabled = true
+transport = "http"
+url = "https://example.com/mcp"
+
+[mcp.servers.headers]
+Authorization = "Bearer ${AL_DOCS_TOKEN}"
+
+[[mcp.servers]]
+id = "local"
+enabled = true
+transport = "stdio"
+command = "local-mcp"
+`
+	c := NewChoices()
+	c.CustomMCPServers = []string{"docs", "local"}
+	c.CustomMCPServersTouched = true
+	c.CustomMCPServersEnabled = map[string]bool{"docs": true, "local": false}
+
+	out, err := RoundTrip(content, c)
+	require.NoError(t, err)
+
+	var cfg struct {
+		MCP struct {
+			Servers []map[string]any `toml:"servers"`
+		} `toml:"mcp"`
+	}
+	require.NoError(t, toml.Unmarshal([]byte(out), &cfg))
+	require.Len(t, cfg.MCP.Servers, 2)
+	for _, server := range cfg.MCP.Servers {
+		switch server["id"] {
+		case "docs":
+			assert.Equal(t, map[string]any{"Authorization": "Bearer ${AL_DOCS_TOKEN}"}, server["headers"])
+		case "local":
+			assert.Equal(t, false, server["enabled"])
+			assert.NotContains(t, server, "headers")
+		}
+	}
+}
+
+func TestRoundTrip_CommentAboveUnknownSectionMovesWithIt(t *testing.T) {
+	content := `[zeta]
+value = 1
+
+# About alpha.
+[alpha]
+value = 2
+`
+	out, err := RoundTrip(content, NewChoices())
+	require.NoError(t, err)
+	assert.Contains(t, out, "# About alpha.\n[alpha]\nvalue = 2")
+	assert.Less(t, strings.Index(out, "[alpha]"), strings.Index(out, "[zeta]"))
+}
+
+func TestRoundTrip_Errors(t *testing.T) {
+	_, err := RoundTrip("[approvals\n", NewChoices())
+	require.Error(t, err)
+
+	before := map[string]any{"notes": map[string]any{"body": "a\nb"}}
+	after := map[string]any{"notes": map[string]any{"body": "a\nc"}}
+	err = checkMultilineStrings("", before, after)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "notes.body")
+
+	servers := map[string]any{"servers": []any{
+		map[string]any{"id": "a", "note": "x\ny"},
+		map[string]any{"id": "b", "note": "p\nq"},
+	}}
+	reordered := map[string]any{"servers": []any{
+		map[string]any{"id": "b", "note": "p\nq"},
+		map[string]any{"id": "a", "note": "x\ny"},
+	}}
+	assert.NoError(t, checkMultilineStrings("", servers, reordered))
+
+	err = checkUnmanagedTables("# keep me\n[custom]\nkey = 1\n", "[custom]\nkey = 1\n",
+		map[string]any{"custom": map[string]any{"key": int64(1)}},
+		map[string]any{"custom": map[string]any{"key": int64(1)}})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "# keep me")
+
+	err = checkUnmanagedTables("[custom]\nkey = 1\n", "[custom]\nkey = 2\n",
+		map[string]any{"custom": map[string]any{"key": int64(1)}},
+		map[string]any{"custom": map[string]any{"key": int64(2)}})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "table custom")
+}
+
+func FuzzRoundTrip(f *testing.F) {
+	f.Add("notes", "body", "line one\nline two\n", "a comment", uint8(0))
+	f.Add("plugins", "settings", "[fake.header]\n# not a comment\n", "", uint8(31))
+	f.Add("mcp", "extra", "x = 1\n", "x", uint8(16))
+	f.Add("agents.codex", "extra", "", "c", uint8(6))
+	f.Add("a . b", "c", "''' '''\n", "#", uint8(127))
+
+	template := readConfigTemplate(f)
+	f.Fuzz(func(t *testing.T, table string, key string, body string, comment string, selector uint8) {
+		if strings.ContainsAny(comment, "\r\n") || strings.ContainsAny(key, "\r\n") || strings.ContainsAny(table, "\r\n") {
+			t.Skip()
+		}
+		content := template + "\n# " + comment + "\n[" + table + "]\n" + key + " = '''\n" + body + "'''\n" +
+			"\n[[" + table + "_items]]\nid = \"one\"\n\n[" + table + "_items." + key + "]\nnote = \"\"\"\n" + body + "\"\"\"\n"
+		var parsed map[string]any
+		if toml.Unmarshal([]byte(content), &parsed) != nil {
+			t.Skip()
+		}
+		if _, err := PatchConfig(content, NewChoices()); err != nil {
+			// Inputs the wizard rejects outright never reach disk.
+			t.Skip()
+		}
+		_, err := RoundTrip(content, roundTripChoices(t, selector))
+		require.NoError(t, err)
+	})
+}
@@ -2,13 +2,13 @@ package wizard
 
 import (
 	"errors"
-	"fmt"
 	"os"
 
 	"charm.land/bubbles/v2/key"
 	tea "charm.land/bubbletea/v2"
 	"charm.land/huh/v2"
 
+	"github.com/conn-castle/agent-layer/internal/i18n"
 	"github.com/conn-castle/agent-layer/internal/messages"
 	"github.com/conn-castle/agent-layer/internal/terminal"
 )
@@ -23,6 +23,10 @@ type UI interface {
 	Note(title string, body string) error
 }
 
+// Prompter is the interface embedders implement to drive the wizard with
+// their own prompts instead of the terminal forms of HuhUI.
+type Prompter = UI
+
 // HuhUI implements UI using charmbracelet/huh.
 type HuhUI struct {
 	isTerminal func() bool
@@ -46,7 +50,7 @@ func (ui *HuhUI) ensureInteractive() error {
 	if checker() {
 		return nil
 	}
-	return fmt.Errorf(messages.WizardRequiresTerminal)
+	return i18n.Errorf(messages.WizardRequiresTerminal)
 }
 
 // wizardKeyMap returns a custom keymap for wizard forms.
@@ -1,9 +1,8 @@
 package wizard
 
 import (
-	"fmt"
-
 	"github.com/conn-castle/agent-layer/internal/config"
+	"github.com/conn-castle/agent-layer/internal/i18n"
 	"github.com/conn-castle/agent-layer/internal/messages"
 )
 
@@ -31,7 +30,7 @@ func loadWarningDefaults() (WarningDefaults, error) {
 		w.MCPServerToolsThreshold == nil ||
 		w.MCPSchemaTokensTotalThreshold == nil ||
 		w.MCPSchemaTokensServerThreshold == nil {
-		return WarningDefaults{}, fmt.Errorf(messages.WizardTemplateWarningsDefaultsIncomplete)
+		return WarningDefaults{}, i18n.Errorf(messages.WizardTemplateWarningsDefaultsIncomplete)
 	}
 
 	return WarningDefaults{
@@ -15,7 +15,9 @@ import (
 	"github.com/conn-castle/agent-layer/internal/agentoptions"
 	"github.com/conn-castle/agent-layer/internal/config"
 	"github.com/conn-castle/agent-layer/internal/envfile"
+	"github.com/conn-castle/agent-layer/internal/i18n"
 	"github.com/conn-castle/agent-layer/internal/install"
+	"github.com/conn-castle/agent-layer/internal/layerdir"
 	"github.com/conn-castle/agent-layer/internal/messages"
 )
 
@@ -54,13 +56,13 @@ func runWithWriter(root string, ui UI, runSync syncer, pinVersion string, out io
 	if out == nil {
 		out = os.Stdout
 	}
-	configPath := filepath.Join(root, ".agent-layer", "config.toml")
-	envPath := filepath.Join(root, ".agent-layer", ".env")
+	configPath := filepath.Join(layerdir.Dir(root), "config.toml")
+	envPath := filepath.Join(layerdir.Dir(root), ".env")
 
 	proceed, freshInstall, err := ensureWizardConfig(root, configPath, ui, pinVersion, out)
 	if err != nil {
 		if errors.Is(err, errWizardBack) || errors.Is(err, errWizardCancelled) {
-			_, _ = fmt.Fprintln(out, messages.WizardExitWithoutChanges)
+			_, _ = fmt.Fprintln(out, i18n.T(messages.WizardExitWithoutChanges))
 			return nil
 		}
 		return err
@@ -74,14 +76,14 @@ func runWithWriter(root string, ui UI, runSync syncer, pinVersion string, out io
 		if !errors.Is(err, config.ErrConfigValidation) {
 			// Non-validation failure (env, instructions, skills, etc.) —
 			// lenient config fallback would not help; propagate the real error.
-			return fmt.Errorf(messages.WizardLoadConfigFailedFmt, err)
+			return i18n.Errorf(messages.WizardLoadConfigFailedFmt, err)
 		}
 		if errors.Is(err, config.ErrConfigNeedsUpgrade) {
 			// The config contains a legacy key that only `al upgrade` can
 			// migrate. The wizard's config patch preserves unknown sections
 			// verbatim, so proceeding would dead-end at sync. Redirect instead
 			// of promising a fix the wizard cannot deliver.
-			_, _ = fmt.Fprintf(out, messages.WizardConfigNeedsUpgradeFmt+"\n", err)
+			_, _ = fmt.Fprintf(out, i18n.T(messages.WizardConfigNeedsUpgradeFmt)+"\n", err)
 			return nil
 		}
 		// Config has validation errors (e.g., missing required fields from a
@@ -90,9 +92,12 @@ func runWithWriter(root string, ui UI, runSync syncer, pinVersion string, out io
 		lenientCfg, lenientErr := loadConfigLenientFunc(configPath)
 		if lenientErr != nil {
 			// TOML syntax error or file unreadable — can't recover.
-			return fmt.Errorf(messages.WizardLoadConfigFailedFmt, lenientErr)
+			return i18n.Errorf(messages.WizardLoadConfigFailedFmt, lenientErr)
+		}
+		_, _ = fmt.Fprintf(out, i18n.T(messages.ConfigLenientLoadInfoFmt)+"\n", "the wizard", err)
+		for _, deprecation := range lenientCfg.Deprecated {
+			_, _ = fmt.Fprintf(out, i18n.T(messages.WizardDeprecatedKeyWarningFmt)+"\n", deprecation.Message(configPath))
 		}
-		_, _ = fmt.Fprintf(out, messages.ConfigLenientLoadInfoFmt+"\n", "the wizard", err)
 		cfg = &config.ProjectConfig{Config: *lenientCfg, Root: root}
 	}
 
@@ -108,7 +113,7 @@ func runWithWriter(root string, ui UI, runSync syncer, pinVersion string, out io
 	if err := promptWizardFlow(root, ui, choices); err != nil {
 		if errors.Is(err, errWizardCancelled) || errors.Is(err, errWizardBack) {
 			if !freshInstall {
-				_, _ = fmt.Fprintln(out, messages.WizardExitWithoutChanges)
+				_, _ = fmt.Fprintln(out, i18n.T(messages.WizardExitWithoutChanges))
 			}
 			return nil
 		}
@@ -118,7 +123,7 @@ func runWithWriter(root string, ui UI, runSync syncer, pinVersion string, out io
 	if err := confirmAndApply(root, configPath, envPath, ui, choices, runSync, out, !freshInstall); err != nil {
 		if errors.Is(err, errWizardCancelled) || errors.Is(err, errWizardBack) {
 			if !freshInstall {
-				_, _ = fmt.Fprintln(out, messages.WizardExitWithoutChanges)
+				_, _ = fmt.Fprintln(out, i18n.T(messages.WizardExitWithoutChanges))
 			}
 			return nil
 		}
@@ -138,12 +143,12 @@ func applyFreshSetupDefaults(choices *Choices) {
 
 func ensureWizardConfig(root, configPath string, ui UI, pinVersion string, out io.Writer) (bool, bool, error) {
 	if _, err := os.Stat(configPath); os.IsNotExist(err) {
-		agentLayerPath := filepath.Join(root, ".agent-layer")
+		agentLayerPath, isDir := layerdir.InstallMarker(root)
 		if info, agentLayerErr := os.Stat(agentLayerPath); agentLayerErr == nil {
-			if !info.IsDir() {
-				return false, false, fmt.Errorf(messages.RootPathNotDirFmt, agentLayerPath)
+			if isDir && !info.IsDir() {
+				return false, false, i18n.Errorf(messages.RootPathNotDirFmt, agentLayerPath)
 			}
-			return false, false, fmt.Errorf(messages.WizardPartialInstallUpgradeRequired)
+			return false, false, i18n.Errorf(messages.WizardPartialInstallUpgradeRequired)
 		} else if !os.IsNotExist(agentLayerErr) {
 			return false, false, agentLayerErr
 		}
@@ -153,7 +158,7 @@ func ensureWizardConfig(root, configPath string, ui UI, pinVersion string, out i
 			return false, false, err
 		}
 		if !confirm {
-			_, _ = fmt.Fprintln(out, messages.WizardExitWithoutChanges)
+			_, _ = fmt.Fprintln(out, i18n.T(messages.WizardExitWithoutChanges))
 			return false, false, nil
 		}
 
@@ -162,9 +167,9 @@ func ensureWizardConfig(root, configPath string, ui UI, pinVersion string, out i
 			PinVersion: pinVersion,
 			System:     install.RealSystem{},
 		}); err != nil {
-			return false, false, fmt.Errorf(messages.WizardInstallFailedFmt, err)
+			return false, false, i18n.Errorf(messages.WizardInstallFailedFmt, err)
 		}
-		_, _ = fmt.Fprintln(out, messages.WizardInstallComplete)
+		_, _ = fmt.Fprintln(out, i18n.T(messages.WizardInstallComplete))
 		return true, true, nil
 	} else if err != nil {
 		return false, false, err
@@ -178,7 +183,7 @@ func initializeChoices(cfg *config.ProjectConfig) (*Choices, error) {
 
 	defaultServers, err := loadDefaultMCPServersFunc()
 	if err != nil {
-		return nil, fmt.Errorf(messages.WizardLoadDefaultMCPServersFailedFmt, err)
+		return nil, i18n.Errorf(messages.WizardLoadDefaultMCPServersFailedFmt, err)
 	}
 	choices.DefaultMCPServers = defaultServers
 
@@ -197,7 +202,7 @@ func initializeChoices(cfg *config.ProjectConfig) (*Choices, error) {
 
 	warningDefaults, err := loadWarningDefaultsFunc()
 	if err != nil {
-		return nil, fmt.Errorf(messages.WizardLoadWarningDefaultsFailedFmt, err)
+		return nil, i18n.Errorf(messages.WizardLoadWarningDefaultsFailedFmt, err)
 	}
 	choices.InstructionTokenThreshold = warningDefaults.InstructionTokenThreshold
 	choices.MCPServerThreshold = warningDefaults.MCPServerThreshold
@@ -601,14 +606,14 @@ func promptCLISkills(ui UI, choices *Choices) error {
 func promptApprovalMode(ui UI, choices *Choices) error {
 	approvalModeLabel, ok := approvalModeLabelForValue(choices.ApprovalMode)
 	if !ok {
-		return fmt.Errorf(messages.WizardUnknownApprovalModeFmt, choices.ApprovalMode)
+		return i18n.Errorf(messages.WizardUnknownApprovalModeFmt, choices.ApprovalMode)
 	}
 	if err := ui.Select(messages.WizardApprovalModeTitle, approvalModeLabels(), &approvalModeLabel); err != nil {
 		return err
 	}
 	approvalModeValue, ok := approvalModeValueForLabel(approvalModeLabel)
 	if !ok {
-		return fmt.Errorf(messages.WizardUnknownApprovalModeSelectionFmt, approvalModeLabel)
+		return i18n.Errorf(messages.WizardUnknownApprovalModeSelectionFmt, approvalModeLabel)
 	}
 	choices.ApprovalMode = approvalModeValue
 	choices.ApprovalModeTouched = true
@@ -886,7 +891,7 @@ func confirmAndApply(root, configPath, envPath string, ui UI, choices *Choices,
 	}
 	if !confirmApply {
 		if printExitWithoutChanges {
-			_, _ = fmt.Fprintln(out, messages.WizardExitWithoutChanges)
+			_, _ = fmt.Fprintln(out, i18n.T(messages.WizardExitWithoutChanges))
 		}
 		return nil
 	}
@@ -895,17 +900,17 @@ func confirmAndApply(root, configPath, envPath string, ui UI, choices *Choices,
 		return err
 	}
 
-	_, _ = color.New(color.FgGreen).Fprintln(out, messages.WizardCompleted)
+	_, _ = color.New(color.FgGreen).Fprintln(out, i18n.T(messages.WizardCompleted))
 	return nil
 }
 
 func promptSecrets(root string, ui UI, choices *Choices) error {
-	envPath := filepath.Join(root, ".agent-layer", ".env")
+	envPath := filepath.Join(layerdir.Dir(root), ".env")
 	envValues := make(map[string]string)
 	if b, err := os.ReadFile(envPath); err == nil { // #nosec G304 -- envPath is the caller-resolved .agent-layer/.env path used by wizard prompts.
 		parsed, parseErr := envfile.Parse(string(b))
 		if parseErr != nil {
-			return fmt.Errorf(messages.WizardInvalidEnvFileFmt, envPath, parseErr)
+			return i18n.Errorf(messages.WizardInvalidEnvFileFmt, envPath, parseErr)
 		}
 		envValues = parsed
 	} else if !os.IsNotExist(err) {
@@ -926,7 +931,7 @@ func promptSecrets(root string, ui UI, choices *Choices) error {
 			}
 			if value, ok := envValues[key]; ok && value != "" {
 				override := false
-				if err := ui.Confirm(fmt.Sprintf(messages.WizardSecretAlreadySetPromptFmt, key), &override); err != nil {
+				if err := ui.Confirm(i18n.Sprintf(messages.WizardSecretAlreadySetPromptFmt, key), &override); err != nil {
 					return err
 				}
 				if !override {
@@ -935,7 +940,7 @@ func promptSecrets(root string, ui UI, choices *Choices) error {
 				}
 			} else if value := os.Getenv(key); value != "" {
 				useEnv := false
-				if err := ui.Confirm(fmt.Sprintf(messages.WizardEnvSecretFoundPromptFmt, key), &useEnv); err != nil {
+				if err := ui.Confirm(i18n.Sprintf(messages.WizardEnvSecretFoundPromptFmt, key), &useEnv); err != nil {
 					return err
 				}
 				if useEnv {
@@ -946,7 +951,7 @@ func promptSecrets(root string, ui UI, choices *Choices) error {
 
 			for {
 				var value string
-				if err := ui.SecretInput(fmt.Sprintf(messages.WizardSecretInputPromptFmt, key), &value); err != nil {
+				if err := ui.SecretInput(i18n.Sprintf(messages.WizardSecretInputPromptFmt, key), &value); err != nil {
 					return err
 				}
 				normalized := strings.TrimSpace(value)
@@ -967,7 +972,7 @@ func promptSecrets(root string, ui UI, choices *Choices) error {
 				}
 
 				disable := true
-				if err := ui.Confirm(fmt.Sprintf(messages.WizardSecretMissingDisablePromptFmt, key, server.ID), &disable); err != nil {
+				if err := ui.Confirm(i18n.Sprintf(messages.WizardSecretMissingDisablePromptFmt, key, server.ID), &disable); err != nil {
 					return err
 				}
 				if disable {
@@ -993,7 +998,7 @@ func buildRewritePreview(configPath, envPath string, choices *Choices) (string,
 	}
 	nextConfig, err := PatchConfig(string(currentConfigBytes), choices)
 	if err != nil {
-		return "", fmt.Errorf(messages.WizardPatchConfigFailedFmt, err)
+		return "", i18n.Errorf(messages.WizardPatchConfigFailedFmt, err)
 	}
 
 	currentEnvBytes, err := os.ReadFile(envPath) // #nosec G304 -- envPath is the caller-resolved .agent-layer/.env path used for the rewrite preview.
@@ -1036,11 +1041,11 @@ func buildRewritePreview(configPath, envPath string, choices *Choices) (string,
 func redactEnvPreviewContent(currentContent string, nextContent string) (string, string, error) {
 	currentValues, err := envfile.Parse(currentContent)
 	if err != nil {
-		return "", "", fmt.Errorf(messages.WizardInvalidEnvFileFmt, ".agent-layer/.env", err)
+		return "", "", i18n.Errorf(messages.WizardInvalidEnvFileFmt, ".agent-layer/.env", err)
 	}
 	nextValues, err := envfile.Parse(nextContent)
 	if err != nil {
-		return "", "", fmt.Errorf(messages.WizardInvalidEnvFileFmt, ".agent-layer/.env", err)
+		return "", "", i18n.Errorf(messages.WizardInvalidEnvFileFmt, ".agent-layer/.env", err)
 	}
 	return redactEnvPreviewSide(currentContent, currentValues, nextValues, true),
 		redactEnvPreviewSide(nextContent, nextValues, currentValues, false),
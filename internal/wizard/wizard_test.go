@@ -258,6 +258,7 @@ func TestRunWithWriter_LenientFallbackOnUnknownKeys(t *testing.T) {
 				Codex:       config.CodexConfig{Enabled: &trueVal},
 				VSCode:      config.EnableOnlyConfig{Enabled: &trueVal},
 			},
+			Deprecated: []config.Deprecation{{Key: "agents.claude-vscode.enabled", Replacement: "agents.claude_vscode.enabled", RemovedIn: "0.8.8"}},
 		}, nil
 	}
 	t.Cleanup(func() { loadConfigLenientFunc = origLenient })
@@ -298,6 +299,7 @@ func TestRunWithWriter_LenientFallbackOnUnknownKeys(t *testing.T) {
 
 	// Verify that the lenient-loading info message was printed.
 	assert.Contains(t, out.String(), "validation errors")
+	assert.Contains(t, out.String(), "Warning: "+configDir+"/config.toml: agents.claude-vscode.enabled was removed in 0.8.8; use agents.claude_vscode.enabled instead")
 	assert.Contains(t, out.String(), "the wizard")
 }
 
@@ -0,0 +1,171 @@
+// Package worktree detects git worktrees. A repo checked out with
+// `git worktree add` has one main worktree and any number of linked ones; a
+// linked worktree's .git is a file pointing at its admin directory under the
+// main repository's .git/worktrees/.
+package worktree
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/conn-castle/agent-layer/internal/i18n"
+	"github.com/conn-castle/agent-layer/internal/messages"
+)
+
+const gitDirPrefix = "gitdir:"
+
+// Info describes the git worktree a repo root belongs to.
+type Info struct {
+	// Root is the worktree root that was inspected.
+	Root string
+	// Linked is true for a worktree created by `git worktree add`.
+	Linked bool
+	// Name is the linked worktree's admin directory name; empty for the main
+	// worktree.
+	Name string
+	// CommonDir is the .git directory every worktree of the repo shares. It is
+	// empty when root is not a git checkout.
+	CommonDir string
+}
+
+// Detect inspects root/.git. A root that is not a git checkout returns an
+// Info with only Root set.
+func Detect(root string) (Info, error) {
+	info := Info{Root: root}
+	gitPath := filepath.Join(root, ".git")
+	stat, err := os.Stat(gitPath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return info, nil
+		}
+		return Info{}, i18n.Errorf(messages.WorktreeReadFmt, gitPath, err)
+	}
+	if stat.IsDir() {
+		info.CommonDir = gitPath
+		return info, nil
+	}
+	adminDir, err := readGitDirFile(gitPath, root)
+	if err != nil {
+		return Info{}, err
+	}
+	info.CommonDir = adminDir
+	data, err := os.ReadFile(filepath.Join(adminDir, "commondir")) // #nosec G304 -- adminDir comes from the worktree's own .git file.
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			// A .git file without commondir is a submodule or a separate git
+			// dir, not a linked worktree.
+			return info, nil
+		}
+		return Info{}, i18n.Errorf(messages.WorktreeReadFmt, adminDir, err)
+	}
+	common := strings.TrimSpace(string(data))
+	if !filepath.IsAbs(common) {
+		common = filepath.Join(adminDir, common)
+	}
+	info.Linked = true
+	info.Name = filepath.Base(adminDir)
+	info.CommonDir = filepath.Clean(common)
+	return info, nil
+}
+
+// Roots returns every worktree root of the repo root belongs to, main first
+// and then linked worktrees in path order. Worktrees whose directories no
+// longer exist are skipped. A root that is not a git checkout returns only
+// itself.
+func Roots(root string) ([]string, error) {
+	info, err := Detect(root)
+	if err != nil {
+		return nil, err
+	}
+	if info.CommonDir == "" {
+		return []string{root}, nil
+	}
+	var roots []string
+	if filepath.Base(info.CommonDir) == ".git" {
+		roots = append(roots, filepath.Dir(info.CommonDir))
+	}
+	adminRoot := filepath.Join(info.CommonDir, "worktrees")
+	entries, err := os.ReadDir(adminRoot)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, i18n.Errorf(messages.WorktreeReadFmt, adminRoot, err)
+	}
+	var linked []string
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		gitFile, err := readGitDirFile(filepath.Join(adminRoot, entry.Name(), "gitdir"), "")
+		if err != nil {
+			continue
+		}
+		worktreeRoot := filepath.Dir(gitFile)
+		if _, err := os.Stat(worktreeRoot); err != nil {
+			continue
+		}
+		linked = append(linked, worktreeRoot)
+	}
+	sort.Strings(linked)
+	return append(roots, linked...), nil
+}
+
+// SiblingPath reports whether path is rel inside a worktree of root's repo
+// other than root itself, such as a CODEX_HOME inherited from a shell opened
+// in another worktree.
+func SiblingPath(root string, path string, rel string) bool {
+	roots, err := Roots(root)
+	if err != nil || len(roots) < 2 {
+		return false
+	}
+	target := resolve(path)
+	self := resolve(root)
+	for _, other := range roots {
+		if resolve(other) == self {
+			continue
+		}
+		if resolve(filepath.Join(other, rel)) == target {
+			return true
+		}
+	}
+	return false
+}
+
+// readGitDirFile reads a pointer file. A .git file holds "gitdir: <path>";
+// an admin directory's gitdir file holds the bare path. Relative paths are
+// resolved against base, or the file's directory when base is empty.
+func readGitDirFile(path string, base string) (string, error) {
+	data, err := os.ReadFile(path) // #nosec G304 -- path is a git pointer file inside the repo or its admin directory.
+	if err != nil {
+		return "", i18n.Errorf(messages.WorktreeReadFmt, path, err)
+	}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	if !scanner.Scan() {
+		return "", i18n.Errorf(messages.WorktreeGitFileInvalidFmt, path)
+	}
+	target := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(scanner.Text()), gitDirPrefix))
+	if target == "" {
+		return "", i18n.Errorf(messages.WorktreeGitFileInvalidFmt, path)
+	}
+	if !filepath.IsAbs(target) {
+		if base == "" {
+			base = filepath.Dir(path)
+		}
+		target = filepath.Join(base, target)
+	}
+	return filepath.Clean(target), nil
+}
+
+func resolve(path string) string {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		abs = path
+	}
+	if eval, err := filepath.EvalSymlinks(abs); err == nil {
+		return eval
+	}
+	return filepath.Clean(abs)
+}
@@ -0,0 +1,137 @@
+package worktree
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// makeRepo lays out a main worktree and one linked worktree named "feature"
+// the way `git worktree add` does, and returns both roots.
+func makeRepo(t *testing.T) (string, string) {
+	t.Helper()
+	base := t.TempDir()
+	main := filepath.Join(base, "main")
+	linked := filepath.Join(base, "feature")
+	admin := filepath.Join(main, ".git", "worktrees", "feature")
+	if err := os.MkdirAll(admin, 0o755); err != nil {
+		t.Fatalf("mkdir admin: %v", err)
+	}
+	if err := os.MkdirAll(linked, 0o755); err != nil {
+		t.Fatalf("mkdir linked: %v", err)
+	}
+	writeFile(t, filepath.Join(admin, "commondir"), "../..\n")
+	writeFile(t, filepath.Join(admin, "gitdir"), filepath.Join(linked, ".git")+"\n")
+	writeFile(t, filepath.Join(linked, ".git"), "gitdir: "+admin+"\n")
+	return main, linked
+}
+
+func writeFile(t *testing.T, path string, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("write %s: %v", path, err)
+	}
+}
+
+func TestDetect(t *testing.T) {
+	main, linked := makeRepo(t)
+	commonDir := filepath.Join(main, ".git")
+
+	info, err := Detect(main)
+	if err != nil {
+		t.Fatalf("Detect main: %v", err)
+	}
+	if info.Linked || info.Name != "" || info.CommonDir != commonDir {
+		t.Fatalf("unexpected main info: %+v", info)
+	}
+
+	info, err = Detect(linked)
+	if err != nil {
+		t.Fatalf("Detect linked: %v", err)
+	}
+	if !info.Linked || info.Name != "feature" || info.CommonDir != commonDir {
+		t.Fatalf("unexpected linked info: %+v", info)
+	}
+}
+
+func TestDetectNotGit(t *testing.T) {
+	root := t.TempDir()
+	info, err := Detect(root)
+	if err != nil {
+		t.Fatalf("Detect: %v", err)
+	}
+	if info.Linked || info.CommonDir != "" {
+		t.Fatalf("expected no git info, got %+v", info)
+	}
+}
+
+func TestDetectGitFileWithoutCommonDir(t *testing.T) {
+	root := t.TempDir()
+	gitDir := filepath.Join(t.TempDir(), "modules", "sub")
+	if err := os.MkdirAll(gitDir, 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	writeFile(t, filepath.Join(root, ".git"), "gitdir: "+gitDir+"\n")
+
+	info, err := Detect(root)
+	if err != nil {
+		t.Fatalf("Detect: %v", err)
+	}
+	if info.Linked {
+		t.Fatalf("expected submodule-style .git file not to be linked, got %+v", info)
+	}
+}
+
+func TestDetectInvalidGitFile(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, ".git"), "\n")
+	if _, err := Detect(root); err == nil {
+		t.Fatal("expected error for empty .git file")
+	}
+}
+
+func TestRoots(t *testing.T) {
+	main, linked := makeRepo(t)
+	for _, root := range []string{main, linked} {
+		roots, err := Roots(root)
+		if err != nil {
+			t.Fatalf("Roots(%s): %v", root, err)
+		}
+		if len(roots) != 2 || roots[0] != main || roots[1] != linked {
+			t.Fatalf("Roots(%s) = %v, want [%s %s]", root, roots, main, linked)
+		}
+	}
+}
+
+func TestRootsSkipsRemovedWorktree(t *testing.T) {
+	main, linked := makeRepo(t)
+	if err := os.RemoveAll(linked); err != nil {
+		t.Fatalf("remove linked: %v", err)
+	}
+	roots, err := Roots(main)
+	if err != nil {
+		t.Fatalf("Roots: %v", err)
+	}
+	if len(roots) != 1 || roots[0] != main {
+		t.Fatalf("expected only main root, got %v", roots)
+	}
+}
+
+func TestSiblingPath(t *testing.T) {
+	main, linked := makeRepo(t)
+	if !SiblingPath(linked, filepath.Join(main, ".codex"), ".codex") {
+		t.Fatal("expected main worktree .codex to be a sibling of the linked worktree")
+	}
+	if !SiblingPath(main, filepath.Join(linked, ".codex"), ".codex") {
+		t.Fatal("expected linked worktree .codex to be a sibling of the main worktree")
+	}
+	if SiblingPath(linked, filepath.Join(linked, ".codex"), ".codex") {
+		t.Fatal("expected the worktree's own .codex not to be a sibling")
+	}
+	if SiblingPath(linked, filepath.Join(t.TempDir(), ".codex"), ".codex") {
+		t.Fatal("expected an unrelated path not to be a sibling")
+	}
+	if SiblingPath(t.TempDir(), filepath.Join(main, ".codex"), ".codex") {
+		t.Fatal("expected no siblings outside a git checkout")
+	}
+}
@@ -0,0 +1,122 @@
+// Package agentlayer is the supported Go API for Agent Layer. Other tools,
+// such as GUI wrappers and CI integrations, use it to load a repo's
+// configuration, list skills, sync client outputs, plan and apply upgrades,
+// and run the configuration wizard in-process instead of running the al CLI.
+// Prompts go to the caller's own Prompter and WizardPrompter implementations
+// instead of a terminal.
+//
+// The data types returned here, such as Project, SyncResult, and UpgradePlan,
+// belong to this package and change only as APIVersion allows. The prompter
+// interfaces and their argument types are aliases of the types the installer
+// and wizard use.
+//
+// Templates and migrations come from the agent-layer module version the
+// caller builds against, the same way they come from the running binary for
+// the CLI.
+package agentlayer
+
+import (
+	"io"
+
+	"github.com/conn-castle/agent-layer/internal/config"
+	"github.com/conn-castle/agent-layer/internal/install"
+	"github.com/conn-castle/agent-layer/internal/output"
+	"github.com/conn-castle/agent-layer/internal/statelock"
+	alsync "github.com/conn-castle/agent-layer/internal/sync"
+	"github.com/conn-castle/agent-layer/internal/wizard"
+)
+
+// APIVersion is the semantic version of this package's API. New functions,
+// types, and fields bump the minor version; removing or changing any of them
+// bumps the major version.
+const APIVersion = "1.3.0"
+
+// Install and upgrade prompts. Prompter is required for ApplyUpgrade; a
+// Prompter may also implement any of the optional prompter interfaces to
+// answer more prompt categories. PromptFuncs adapts plain callbacks and
+// implements all of them.
+type (
+	Prompter                 = install.Prompter
+	PromptFuncs              = install.PromptFuncs
+	ConfigSetDefaultPrompter = install.ConfigSetDefaultPrompter
+	SkillsMigrationPrompter  = install.SkillsMigrationPrompter
+	StatuslineSourcePrompter = install.StatuslineSourcePrompter
+	SourceVersionPrompter    = install.SourceVersionPrompter
+	TmpUnknownsPrompter      = install.TmpUnknownsPrompter
+	UnifiedOverwritePrompter = install.UnifiedOverwritePrompter
+
+	DiffPreview             = install.DiffPreview
+	OwnershipLabel          = install.OwnershipLabel
+	SkillsMigrationConflict = install.SkillsMigrationConflict
+	BaselineCandidate       = install.BaselineCandidate
+	FieldDef                = config.FieldDef
+)
+
+// ErrLocked matches the error Init, Sync, and ApplyUpgrade return when another
+// al process, or another call in this one, holds the repo's state lock.
+var ErrLocked = statelock.ErrHeld
+
+// WizardPrompter answers the configuration wizard's selects, confirmations,
+// and text inputs.
+type WizardPrompter = wizard.Prompter
+
+// InitOptions configures Init.
+type InitOptions struct {
+	// PinVersion is written to .agent-layer/al.version. Empty leaves the repo
+	// unpinned.
+	PinVersion string
+	// Wait blocks while another al process holds the repo's state lock
+	// instead of failing with ErrLocked.
+	Wait bool
+	// Stdout and Stderr receive notes and warnings. Nil discards them.
+	Stdout io.Writer
+	Stderr io.Writer
+}
+
+// Init installs the .agent-layer/ templates into root, like `al init`. Files
+// that already exist, including a different version pin, are kept.
+func Init(root string, opts InitOptions) error {
+	return withStateLock(root, opts.Wait, func() error {
+		return install.Run(root, install.Options{
+			PinVersion: opts.PinVersion,
+			System:     install.RealSystem{},
+			Output:     newOutput(opts.Stdout, opts.Stderr),
+		})
+	})
+}
+
+// RunWizard walks through the configuration wizard in root, like `al wizard`,
+// with prompter answering each question. It installs the templates first
+// when .agent-layer/ is missing, pinning pinVersion, and syncs when it saves
+// changes. out receives the summary; nil discards it.
+func RunWizard(root string, prompter WizardPrompter, pinVersion string, out io.Writer) error {
+	if out == nil {
+		out = io.Discard
+	}
+	return wizard.RunWithWriter(root, prompter, alsync.Run, pinVersion, out)
+}
+
+// withStateLock runs fn while holding the repo's state lock, the same lock the
+// al CLI takes for init, sync, and upgrade.
+func withStateLock(root string, wait bool, fn func() error) (err error) {
+	lock, err := statelock.Acquire(root, wait)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if releaseErr := lock.Release(); err == nil {
+			err = releaseErr
+		}
+	}()
+	return fn()
+}
+
+func newOutput(stdout io.Writer, stderr io.Writer) *output.Writer {
+	if stdout == nil {
+		stdout = io.Discard
+	}
+	if stderr == nil {
+		stderr = io.Discard
+	}
+	return output.New(stdout, stderr, output.Normal)
+}
@@ -0,0 +1,158 @@
+package agentlayer_test
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/conn-castle/agent-layer/internal/statelock"
+	"github.com/conn-castle/agent-layer/pkg/agentlayer"
+)
+
+// recordingPrompter is a Prompter implemented outside the module, the way a
+// GUI wrapper would.
+type recordingPrompter struct {
+	overwrites []string
+}
+
+func (p *recordingPrompter) OverwriteAll([]agentlayer.DiffPreview) (bool, error) { return false, nil }
+func (p *recordingPrompter) OverwriteAllMemory([]agentlayer.DiffPreview) (bool, error) {
+	return false, nil
+}
+func (p *recordingPrompter) Overwrite(preview agentlayer.DiffPreview) (bool, error) {
+	p.overwrites = append(p.overwrites, preview.Path)
+	return true, nil
+}
+func (p *recordingPrompter) DeleteUnknownAll([]string) (bool, error) { return false, nil }
+func (p *recordingPrompter) DeleteUnknown(string) (bool, error)      { return false, nil }
+func (p *recordingPrompter) ChooseSourceVersion([]agentlayer.BaselineCandidate) (string, error) {
+	return "", nil
+}
+
+var _ agentlayer.SourceVersionPrompter = (*recordingPrompter)(nil)
+
+func TestInitUpgradeSync(t *testing.T) {
+	root := t.TempDir()
+	if err := os.Mkdir(filepath.Join(root, ".git"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := agentlayer.Init(root, agentlayer.InitOptions{PinVersion: "0.9.0"}); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+	if pin, err := os.ReadFile(filepath.Join(root, ".agent-layer", "al.version")); err != nil || string(bytes.TrimSpace(pin)) != "0.9.0" {
+		t.Fatalf("pin = %q (%v)", pin, err)
+	}
+
+	instructions := filepath.Join(root, ".agent-layer", "instructions", "01_base.md")
+	if err := os.WriteFile(instructions, []byte("edited\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	prompter := &recordingPrompter{}
+	if err := agentlayer.ApplyUpgrade(root, agentlayer.UpgradeOptions{PinVersion: "0.9.1", Prompter: prompter}); err != nil {
+		t.Fatalf("ApplyUpgrade: %v", err)
+	}
+	if len(prompter.overwrites) != 1 || prompter.overwrites[0] != ".agent-layer/instructions/01_base.md" {
+		t.Fatalf("overwrite prompts = %v", prompter.overwrites)
+	}
+	if data, _ := os.ReadFile(instructions); string(data) == "edited\n" {
+		t.Fatal("approved overwrite must restore the template")
+	}
+
+	if err := agentlayer.ApplyUpgrade(root, agentlayer.UpgradeOptions{}); err == nil {
+		t.Fatal("ApplyUpgrade without a Prompter must fail")
+	}
+
+	result, err := agentlayer.Sync(root, agentlayer.SyncOptions{})
+	if err != nil {
+		t.Fatalf("Sync: %v", err)
+	}
+	if len(result.Changes) == 0 {
+		t.Fatal("Sync must report the generated files it wrote")
+	}
+	if _, err := os.Stat(filepath.Join(root, "AGENTS.md")); err != nil {
+		t.Fatalf("AGENTS.md: %v", err)
+	}
+}
+
+func TestLoadConfigAndPlanUpgrade(t *testing.T) {
+	root := t.TempDir()
+	if err := agentlayer.Init(root, agentlayer.InitOptions{PinVersion: "0.9.0"}); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+
+	for rel, content := range map[string]string{
+		"instructions/01_base.md":   "edited\n",
+		"skills/deploy/SKILL.md":    "---\nname: deploy\ndescription: Ship it\n---\n\nSteps.\n",
+		"skills/deploy@v2/SKILL.md": "---\nname: deploy\ndescription: Ship it faster\n---\n\nSteps.\n",
+	} {
+		path := filepath.Join(root, ".agent-layer", filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	project, err := agentlayer.LoadConfig(root)
+	if err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+	if len(project.Clients) != 6 || project.Clients[1].Name != "claude" || project.ApprovalMode == "" {
+		t.Fatalf("project = %+v", project)
+	}
+	if len(project.Instructions) != 1 || project.Instructions[0].Content != "edited\n" {
+		t.Fatalf("instructions = %+v", project.Instructions)
+	}
+	skills, err := agentlayer.ListSkills(root)
+	if err != nil || len(skills) != 1 || skills[0].Description != "Ship it" || skills[0].Variant != "" {
+		t.Fatalf("ListSkills = %+v (%v)", skills, err)
+	}
+	plan, err := agentlayer.PlanUpgrade(root, "0.9.1")
+	if err != nil {
+		t.Fatalf("PlanUpgrade: %v", err)
+	}
+	if plan.CurrentVersion != "0.9.0" || plan.TargetVersion != "0.9.1" {
+		t.Fatalf("plan versions = %q -> %q", plan.CurrentVersion, plan.TargetVersion)
+	}
+	var planned bool
+	for _, group := range plan.RiskGroups {
+		for _, item := range group.Items {
+			planned = planned || item.Path == ".agent-layer/instructions/01_base.md"
+		}
+	}
+	if !planned {
+		t.Fatalf("plan must include the edited instruction, got %+v", plan.RiskGroups)
+	}
+	if data, _ := os.ReadFile(filepath.Join(root, ".agent-layer", "instructions", "01_base.md")); string(data) != "edited\n" {
+		t.Fatal("PlanUpgrade must not write")
+	}
+}
+
+func TestStateLockHeld(t *testing.T) {
+	root := t.TempDir()
+	if err := agentlayer.Init(root, agentlayer.InitOptions{}); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+	held, err := statelock.Acquire(root, false)
+	if err != nil {
+		t.Fatalf("Acquire: %v", err)
+	}
+	if _, err := agentlayer.Sync(root, agentlayer.SyncOptions{}); !errors.Is(err, agentlayer.ErrLocked) {
+		t.Fatalf("Sync with held lock = %v", err)
+	}
+	if err := agentlayer.ApplyUpgrade(root, agentlayer.UpgradeOptions{Prompter: &recordingPrompter{}}); !errors.Is(err, agentlayer.ErrLocked) {
+		t.Fatalf("ApplyUpgrade with held lock = %v", err)
+	}
+	if result, err := agentlayer.Sync(root, agentlayer.SyncOptions{DryRun: true}); err != nil || len(result.Changes) == 0 {
+		t.Fatalf("dry run = %+v (%v)", result, err)
+	}
+	if err := held.Release(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := agentlayer.Sync(root, agentlayer.SyncOptions{}); err != nil {
+		t.Fatalf("Sync after release: %v", err)
+	}
+}
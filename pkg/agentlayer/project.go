@@ -0,0 +1,111 @@
+package agentlayer
+
+import (
+	"github.com/conn-castle/agent-layer/internal/config"
+)
+
+// Project is the loaded configuration of a repo: the effective config.toml
+// (after extends), plus the instructions and skills sync projects.
+type Project struct {
+	// Root is the repo root the project was loaded from.
+	Root string
+	// ApprovalMode is approvals.mode: all, mcp, commands, none, or yolo.
+	ApprovalMode string
+	Clients      []Client
+	MCPServers   []MCPServer
+	// Instructions and Skills hold the selected variants, in sync order.
+	Instructions []Instruction
+	Skills       []Skill
+}
+
+// Client is one [agents.*] entry.
+type Client struct {
+	// Name is the config key, such as "claude" or "copilot_cli".
+	Name    string
+	Enabled bool
+	// Model is empty when the client's default model is used or the client
+	// has no model setting.
+	Model string
+}
+
+// MCPServer is one [[mcp.servers]] entry.
+type MCPServer struct {
+	ID        string
+	Enabled   bool
+	Transport string
+	// Clients lists the clients the server is limited to; empty means all.
+	Clients []string
+}
+
+// Instruction is one file under .agent-layer/instructions/.
+type Instruction struct {
+	Name    string
+	Content string
+}
+
+// Skill is one skill under .agent-layer/skills/ or a user skill directory.
+type Skill struct {
+	Name        string
+	Description string
+	// Variant is empty for the base skill and "v2" for skills/<name>@v2.
+	Variant string
+	// Path is the absolute path of the skill's SKILL.md.
+	Path string
+}
+
+// LoadConfig loads and validates the project configuration in root.
+func LoadConfig(root string) (*Project, error) {
+	project, err := config.LoadProjectConfig(root)
+	if err != nil {
+		return nil, err
+	}
+	cfg := project.Config
+	agents := cfg.Agents
+	out := &Project{
+		Root:         root,
+		ApprovalMode: cfg.Approvals.Mode,
+		Clients: []Client{
+			{Name: "antigravity", Enabled: config.IsAgentEnabled(agents.Antigravity.Enabled), Model: agents.Antigravity.Model},
+			{Name: "claude", Enabled: config.IsAgentEnabled(agents.Claude.Enabled), Model: agents.Claude.Model},
+			{Name: "claude_vscode", Enabled: config.IsAgentEnabled(agents.ClaudeVSCode.Enabled)},
+			{Name: "codex", Enabled: config.IsAgentEnabled(agents.Codex.Enabled), Model: agents.Codex.Model},
+			{Name: "copilot_cli", Enabled: config.IsAgentEnabled(agents.CopilotCLI.Enabled), Model: agents.CopilotCLI.Model},
+			{Name: "vscode", Enabled: config.IsAgentEnabled(agents.VSCode.Enabled)},
+		},
+		Skills: newSkills(project.Skills),
+	}
+	for _, server := range cfg.MCP.Servers {
+		out.MCPServers = append(out.MCPServers, MCPServer{
+			ID:        server.ID,
+			Enabled:   config.IsAgentEnabled(server.Enabled),
+			Transport: server.Transport,
+			Clients:   append([]string(nil), server.Clients...),
+		})
+	}
+	for _, instruction := range project.Instructions {
+		out.Instructions = append(out.Instructions, Instruction{Name: instruction.Name, Content: instruction.Content})
+	}
+	return out, nil
+}
+
+// ListSkills returns the skills sync projects for root, in sync order.
+func ListSkills(root string) ([]Skill, error) {
+	project, err := LoadConfig(root)
+	if err != nil {
+		return nil, err
+	}
+	return project.Skills, nil
+}
+
+func newSkills(skills []config.Skill) []Skill {
+	out := make([]Skill, 0, len(skills))
+	for _, skill := range skills {
+		out = append(out, Skill{
+			Name:        skill.Name,
+			Description: skill.Description,
+			Variant:     skill.Variant,
+			Path:        skill.SourcePath,
+		})
+	}
+	return out
+}
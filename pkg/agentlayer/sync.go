@@ -0,0 +1,97 @@
+package agentlayer
+
+import (
+	"io"
+	"path/filepath"
+
+	"github.com/conn-castle/agent-layer/internal/config"
+	alsync "github.com/conn-castle/agent-layer/internal/sync"
+)
+
+// SyncOptions adjusts Sync.
+type SyncOptions struct {
+	// Force overwrites generated files even when they were edited by hand.
+	Force bool
+	// DryRun reports the changes without writing anything. It takes no
+	// state lock.
+	DryRun bool
+	// Wait blocks while another al process holds the repo's state lock
+	// instead of failing with ErrLocked.
+	Wait bool
+	// HookOutput receives the output of the configured [hooks] commands,
+	// which run around every sync that is not a DryRun. Nil discards it.
+	HookOutput io.Writer
+}
+
+// SyncResult is the outcome of Sync.
+type SyncResult struct {
+	// Warnings are the sync warnings left after warnings.noise_mode.
+	Warnings []Warning
+	// Changes lists, in order, the filesystem changes sync made, or would
+	// have made under DryRun.
+	Changes []Change
+	// EditedFiles lists the repo-relative generated files sync kept because
+	// they were edited by hand.
+	EditedFiles []string
+	// Degradations describes features enabled clients could not receive
+	// natively.
+	Degradations []string
+}
+
+// Warning is one sync warning.
+type Warning struct {
+	// Code is a stable identifier such as GENERATED_FILE_EDITED.
+	Code    string
+	Message string
+}
+
+// Change is one filesystem change.
+type Change struct {
+	// Kind is write, mkdir, remove, or remove_all.
+	Kind string
+	// Path is relative to the repo root, with forward slashes.
+	Path string
+}
+
+// Sync regenerates client outputs in root from .agent-layer/, like `al sync`.
+func Sync(root string, opts SyncOptions) (*SyncResult, error) {
+	project, err := config.LoadProjectConfig(root)
+	if err != nil {
+		return nil, err
+	}
+	var result *alsync.Result
+	run := func() error {
+		var err error
+		result, err = alsync.RunWithProjectOptions(alsync.RealSystem{}, root, project, alsync.RunOptions{Force: opts.Force, DryRun: opts.DryRun, HookOutput: opts.HookOutput})
+		return err
+	}
+	if opts.DryRun {
+		err = run()
+	} else {
+		err = withStateLock(root, opts.Wait, run)
+	}
+	if err != nil {
+		return nil, err
+	}
+	out := &SyncResult{}
+	for _, w := range result.Warnings {
+		out.Warnings = append(out.Warnings, Warning{Code: w.Code, Message: w.Message})
+	}
+	for _, change := range result.Changes {
+		out.Changes = append(out.Changes, Change{Kind: change.Kind, Path: relPath(root, change.Path)})
+	}
+	for _, file := range result.EditedFiles {
+		out.EditedFiles = append(out.EditedFiles, relPath(root, file.Path))
+	}
+	for _, d := range result.Degradations {
+		out.Degradations = append(out.Degradations, d.String())
+	}
+	return out, nil
+}
+
+func relPath(root string, path string) string {
+	if rel, err := filepath.Rel(root, path); err == nil {
+		return filepath.ToSlash(rel)
+	}
+	return path
+}
@@ -0,0 +1,141 @@
+package agentlayer
+
+import (
+	"io"
+
+	"github.com/conn-castle/agent-layer/internal/install"
+)
+
+// UpgradeOptions configures ApplyUpgrade.
+type UpgradeOptions struct {
+	// PinVersion is the version written to .agent-layer/al.version. Empty
+	// leaves the pin unchanged.
+	PinVersion string
+	// Prompter decides every overwrite and deletion. It is required.
+	Prompter Prompter
+	// DiffMaxLines caps the lines in each DiffPreview. Zero uses the CLI
+	// default.
+	DiffMaxLines int
+	// Wait blocks while another al process holds the repo's state lock
+	// instead of failing with ErrLocked.
+	Wait bool
+	// Resume runs over an init or upgrade that stopped partway. Without it
+	// ApplyUpgrade fails with ErrInterrupted; see Interrupted.
+	Resume bool
+	// Only and Skip narrow the upgrade to chosen migration operation IDs and
+	// template paths, like `al upgrade --only` and `--skip`.
+	Only []string
+	Skip []string
+	// Stdout and Stderr receive notes, warnings, and the upgrade changelog.
+	// Nil discards them.
+	Stdout io.Writer
+	Stderr io.Writer
+}
+
+// UpgradePlan previews what ApplyUpgrade would change, like `al upgrade plan`.
+type UpgradePlan struct {
+	// CurrentVersion and TargetVersion are the pinned versions before and
+	// after the upgrade; CurrentVersion is empty for an unpinned repo.
+	CurrentVersion string
+	TargetVersion  string
+	// RiskGroups lists the planned changes by ascending risk.
+	RiskGroups []UpgradeRiskGroup
+	// ConfigKeyMigrations lists config keys the upgrade renames or rewrites.
+	ConfigKeyMigrations []ConfigKeyMigration
+	// ReadinessChecks lists problems to resolve before upgrading.
+	ReadinessChecks []ReadinessCheck
+}
+
+// UpgradeRiskGroup collects planned changes that share a risk level.
+type UpgradeRiskGroup struct {
+	// Risk is safe, config, overwrite, or destructive.
+	Risk  string
+	Items []UpgradeItem
+	// Skippable is false when declining the group cancels the upgrade.
+	Skippable bool
+}
+
+// UpgradeItem is one planned change.
+type UpgradeItem struct {
+	Path   string
+	Detail string
+	// Migration marks migration operations, which apply as a unit.
+	Migration bool
+}
+
+// ConfigKeyMigration is one config key change.
+type ConfigKeyMigration struct {
+	Key  string
+	From string
+	To   string
+}
+
+// ReadinessCheck is one pre-upgrade problem.
+type ReadinessCheck struct {
+	ID      string
+	Summary string
+	Details []string
+}
+
+// PlanUpgrade reports what upgrading root to targetVersion would change,
+// without writing anything. An empty targetVersion keeps the current pin.
+func PlanUpgrade(root string, targetVersion string) (*UpgradePlan, error) {
+	plan, err := install.BuildUpgradePlan(root, install.UpgradePlanOptions{
+		TargetPinVersion: targetVersion,
+		System:           install.RealSystem{},
+	})
+	if err != nil {
+		return nil, err
+	}
+	out := &UpgradePlan{
+		CurrentVersion: plan.PinVersionChange.Current,
+		TargetVersion:  plan.PinVersionChange.Target,
+	}
+	for _, group := range plan.RiskGroups {
+		outGroup := UpgradeRiskGroup{Risk: string(group.Risk), Skippable: group.Skippable}
+		for _, item := range group.Items {
+			outGroup.Items = append(outGroup.Items, UpgradeItem{Path: item.Path, Detail: item.Detail, Migration: item.Migration})
+		}
+		out.RiskGroups = append(out.RiskGroups, outGroup)
+	}
+	for _, migration := range plan.ConfigKeyMigrations {
+		out.ConfigKeyMigrations = append(out.ConfigKeyMigrations, ConfigKeyMigration(migration))
+	}
+	for _, check := range plan.ReadinessChecks {
+		out.ReadinessChecks = append(out.ReadinessChecks, ReadinessCheck{ID: check.ID, Summary: check.Summary, Details: append([]string(nil), check.Details...)})
+	}
+	return out, nil
+}
+
+// ApplyUpgrade applies template updates and migrations to root, like
+// `al upgrade`, asking opts.Prompter before each overwrite or deletion. It
+// snapshots the files it changes and rolls them back if a step fails. Run
+// Sync afterwards to regenerate client outputs, as `al upgrade` does.
+func ApplyUpgrade(root string, opts UpgradeOptions) error {
+	return withStateLock(root, opts.Wait, func() error {
+		return install.Run(root, install.Options{
+			Overwrite:         true,
+			Prompter:          opts.Prompter,
+			PinVersion:        opts.PinVersion,
+			DiffMaxLines:      opts.DiffMaxLines,
+			System:            install.RealSystem{},
+			Output:            newOutput(opts.Stdout, opts.Stderr),
+			ResumeInterrupted: opts.Resume,
+			OperationFilter:   install.UpgradeOperationFilter{Only: opts.Only, Skip: opts.Skip},
+		})
+	})
+}
+
+// InterruptedOperation describes an init or upgrade that stopped partway.
+type InterruptedOperation = install.InterruptedOperation
+
+// ErrInterrupted matches the error ApplyUpgrade returns when an earlier init
+// or upgrade stopped partway and UpgradeOptions.Resume is unset.
+var ErrInterrupted = install.ErrInterruptedOperation
+
+// Interrupted returns the init or upgrade that stopped partway in root, or
+// nil when none did. Recover by calling ApplyUpgrade with Resume, or by
+// restoring op.SnapshotID with `al upgrade rollback`.
+func Interrupted(root string) (*InterruptedOperation, error) {
+	return install.ReadInterruptedOperation(root, install.RealSystem{})
+}
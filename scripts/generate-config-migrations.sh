@@ -0,0 +1,54 @@
+#!/usr/bin/env bash
+set -euo pipefail
+
+ROOT_DIR="$(cd "$(dirname "$0")/.." && pwd)"
+
+usage() {
+  cat <<USAGE
+Usage:
+  scripts/generate-config-migrations.sh --tag vX.Y.Z [--min-prior-version vA.B.C]
+
+Description:
+  Writes config_rename_key and config_delete_key operations for every entry in
+  the config deprecation table (internal/config/deprecations.go) whose Since
+  version matches the tag into internal/templates/migrations/X.Y.Z.json.
+  Operations with the same id are replaced; hand-written operations are kept.
+  --min-prior-version is required only when the manifest does not exist yet.
+USAGE
+}
+
+tag=""
+min_prior=""
+
+while [[ $# -gt 0 ]]; do
+  case "$1" in
+    --tag)
+      [[ $# -ge 2 ]] || { echo "--tag requires a value" >&2; exit 1; }
+      tag="$2"
+      shift 2
+      ;;
+    --min-prior-version)
+      [[ $# -ge 2 ]] || { echo "--min-prior-version requires a value" >&2; exit 1; }
+      min_prior="$2"
+      shift 2
+      ;;
+    -h|--help)
+      usage
+      exit 0
+      ;;
+    *)
+      echo "unknown argument: $1" >&2
+      usage
+      exit 1
+      ;;
+  esac
+done
+
+if [[ -z "$tag" ]]; then
+  echo "--tag is required" >&2
+  usage
+  exit 1
+fi
+
+cd "$ROOT_DIR"
+go run -tags tools ./internal/tools/genconfigmigrations --version "$tag" --min-prior-version "$min_prior" --repo-root "$ROOT_DIR"
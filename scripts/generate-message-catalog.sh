@@ -0,0 +1,47 @@
+#!/usr/bin/env bash
+set -euo pipefail
+
+ROOT_DIR="$(cd "$(dirname "$0")/.." && pwd)"
+
+usage() {
+  cat <<USAGE
+Usage:
+  scripts/generate-message-catalog.sh --locale <locale>
+
+Description:
+  Extracts every message in internal/messages into
+  internal/i18n/locales/<locale>.json for translation. Existing translations
+  are kept, new messages are added untranslated, and messages that no longer
+  exist are removed. Untranslated messages print in English.
+USAGE
+}
+
+locale=""
+
+while [[ $# -gt 0 ]]; do
+  case "$1" in
+    --locale)
+      [[ $# -ge 2 ]] || { echo "--locale requires a value" >&2; exit 1; }
+      locale="$2"
+      shift 2
+      ;;
+    -h|--help)
+      usage
+      exit 0
+      ;;
+    *)
+      echo "unknown argument: $1" >&2
+      usage
+      exit 1
+      ;;
+  esac
+done
+
+if [[ -z "$locale" ]]; then
+  echo "--locale is required" >&2
+  usage
+  exit 1
+fi
+
+cd "$ROOT_DIR"
+go run -tags tools ./internal/tools/genmessagecatalog --locale "$locale" --repo-root "$ROOT_DIR"
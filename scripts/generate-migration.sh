@@ -0,0 +1,74 @@
+#!/usr/bin/env bash
+set -euo pipefail
+
+ROOT_DIR="$(cd "$(dirname "$0")/.." && pwd)"
+
+usage() {
+  cat <<USAGE
+Usage:
+  scripts/generate-migration.sh --from vA.B.C --tag vX.Y.Z [--min-prior-version vA.B.C] [--force]
+
+Description:
+  Scaffolds internal/templates/migrations/X.Y.Z.json by diffing the template
+  ownership manifests of --from and --tag (generate the --tag manifest first
+  with scripts/generate-template-manifest.sh). Moved and removed templates
+  become rename_file / delete_file operations; when the --from tag exists
+  locally, keys its config.toml template lacks become config_set_default
+  operations. Every rationale is a TODO placeholder to rewrite.
+USAGE
+}
+
+from=""
+tag=""
+extra=()
+
+while [[ $# -gt 0 ]]; do
+  case "$1" in
+    --from)
+      [[ $# -ge 2 ]] || { echo "--from requires a value" >&2; exit 1; }
+      from="$2"
+      shift 2
+      ;;
+    --tag)
+      [[ $# -ge 2 ]] || { echo "--tag requires a value" >&2; exit 1; }
+      tag="$2"
+      shift 2
+      ;;
+    --min-prior-version)
+      [[ $# -ge 2 ]] || { echo "--min-prior-version requires a value" >&2; exit 1; }
+      extra+=(--min-prior-version "$2")
+      shift 2
+      ;;
+    --force)
+      extra+=(--force)
+      shift
+      ;;
+    -h|--help)
+      usage
+      exit 0
+      ;;
+    *)
+      echo "unknown argument: $1" >&2
+      usage
+      exit 1
+      ;;
+  esac
+done
+
+if [[ -z "$from" || -z "$tag" ]]; then
+  echo "--from and --tag are required" >&2
+  usage
+  exit 1
+fi
+
+cd "$ROOT_DIR"
+from_tag="v${from#v}"
+if git rev-parse -q --verify "refs/tags/$from_tag" >/dev/null; then
+  from_config="$(mktemp)"
+  trap 'rm -f "$from_config"' EXIT
+  git show "$from_tag:internal/templates/config.toml" >"$from_config"
+  extra+=(--from-config "$from_config")
+else
+  echo "tag $from_tag not found locally; skipping config_set_default scaffolding" >&2
+fi
+go run -tags tools ./cmd/al dev gen-migration --from "$from" --to "$tag" "${extra[@]}"
@@ -243,3 +243,29 @@ run_go_tool_tests_gentemplatemanifest() {
     fail "gentemplatemanifest tests failed"
   fi
 }
+
+run_go_tool_tests_verifymigrationchain() {
+  section "Go Tool Tests: verifymigrationchain"
+
+  # The verifymigrationchain package (and its tests) are guarded by the `tools`
+  # build tag, so `go test ./...` (used by make coverage) skips them. Run them
+  # explicitly here so a release that forgets a migration manifest fails.
+  if (cd "$ROOT_DIR" && go test -tags tools ./internal/tools/verifymigrationchain/); then
+    pass "verifymigrationchain tests passed"
+  else
+    fail "verifymigrationchain tests failed"
+  fi
+}
+
+run_go_tool_tests_genmessagecatalog() {
+  section "Go Tool Tests: genmessagecatalog"
+
+  # The genmessagecatalog package (and its tests) are guarded by the `tools`
+  # build tag, so `go test ./...` (used by make coverage) skips them. Run them
+  # explicitly here so message extraction keeps up with internal/messages.
+  if (cd "$ROOT_DIR" && go test -tags tools ./internal/tools/genmessagecatalog/); then
+    pass "genmessagecatalog tests passed"
+  else
+    fail "genmessagecatalog tests failed"
+  fi
+}